@@ -14,6 +14,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"audit-service/internal/cache"
+	"audit-service/internal/clients"
 	"audit-service/internal/config"
 	"audit-service/internal/consumer"
 	"audit-service/internal/database"
@@ -26,6 +27,7 @@ import (
 	"audit-service/internal/tenant"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/Tesseract-Nexus/go-shared/rbac"
 	"github.com/Tesseract-Nexus/go-shared/tracing"
 )
 
@@ -173,9 +175,50 @@ func main() {
 
 	// Initialize service with NATS publisher for event streaming
 	auditService := services.NewAuditService(auditRepo, logger, natsPublisher)
+	auditService.SetRetentionPolicy(services.NewRetentionPolicy(cfg.Retention))
+
+	// Purged customers are pseudonymized with a dedicated secret, falling back to the JWT secret
+	pseudonymSecret := cfg.App.PseudonymSecret
+	if pseudonymSecret == "" {
+		pseudonymSecret = cfg.App.JWTSecret
+	}
+	auditService.SetPseudonymizer(services.NewPseudonymizer(pseudonymSecret))
+
+	// Events are stamped with coarse IP geolocation from location-service at ingest
+	if cfg.Geo.Enabled {
+		locationClient := clients.NewLocationClient(cfg.Geo.LocationServiceURL, time.Duration(cfg.Geo.LookupTimeoutMs)*time.Millisecond)
+		auditService.SetGeoEnricher(services.NewGeoEnricher(locationClient, redisClient, cfg.Geo, logger))
+		log.Printf("IP geolocation enrichment enabled via %s", cfg.Geo.LocationServiceURL)
+	}
+
+	// Tenant sampling policies store only a share of high-volume, low-value events
+	if cfg.Sampling.Enabled {
+		auditService.SetSampler(services.NewSampler(auditRepo, cfg.Sampling, logger))
+		log.Println("Audit event sampling enabled")
+	}
+
+	// Tenant webhook subscriptions receive new events in real time; failed deliveries are retried
+	var webhookScheduler *scheduler.WebhookRetryScheduler
+	if cfg.Webhooks.Enabled {
+		webhookDispatcher := services.NewWebhookDispatcher(auditRepo, cfg.Webhooks, logger)
+		auditService.SetWebhookDispatcher(webhookDispatcher)
+		webhookScheduler = scheduler.NewWebhookRetryScheduler(webhookDispatcher, tenantRegistry, logger)
+		if err := webhookScheduler.Start(); err != nil {
+			logger.WithError(err).Warn("Failed to start webhook retry scheduler (failed deliveries will not be retried)")
+		}
+		defer webhookScheduler.Stop()
+		log.Println("Audit webhooks enabled")
+	}
+
+	// Field visibility is resolved from staff-service RBAC permissions; development skips the check
+	var permissionChecker handlers.PermissionChecker
+	if !cfg.IsDevelopment() {
+		permissionChecker = rbac.NewMiddlewareWithURL(cfg.App.StaffServiceURL, nil)
+	}
+	fieldVisibility := handlers.NewFieldVisibility(permissionChecker, logger)
 
 	// Initialize handlers with NATS subscriber for real-time streaming
-	auditHandlers := handlers.NewAuditHandlers(auditService, logger, natsSubscriber)
+	auditHandlers := handlers.NewAuditHandlers(auditService, logger, natsSubscriber, fieldVisibility)
 
 	// Initialize cleanup scheduler for retention management
 	cleanupScheduler := scheduler.NewCleanupScheduler(auditRepo, tenantRegistry, cfg.Retention, logger)
@@ -218,6 +261,7 @@ func main() {
 		cache:            auditCache,
 		natsSubscriber:   natsSubscriber,
 		cleanupScheduler: cleanupScheduler,
+		webhookScheduler: webhookScheduler,
 	}
 
 	// Setup router
@@ -306,6 +350,7 @@ type StatsHandler struct {
 	cache            *cache.AuditCache
 	natsSubscriber   *auditNats.Subscriber
 	cleanupScheduler *scheduler.CleanupScheduler
+	webhookScheduler *scheduler.WebhookRetryScheduler
 }
 
 // setupRouter configures the Gin router with middleware and routes
@@ -350,6 +395,9 @@ func setupRouter(cfg *config.Config, auditHandlers *handlers.AuditHandlers, stat
 		if statsHandler.cleanupScheduler != nil {
 			stats["cleanup_scheduler"] = statsHandler.cleanupScheduler.GetStats()
 		}
+		if statsHandler.webhookScheduler != nil {
+			stats["webhooks"] = statsHandler.webhookScheduler.GetStats()
+		}
 		c.JSON(200, stats)
 	})
 
@@ -372,6 +420,7 @@ func setupRouter(cfg *config.Config, auditHandlers *handlers.AuditHandlers, stat
 
 			// Analytics and reporting
 			auditLogs.GET("/summary", auditHandlers.GetSummary)
+			auditLogs.GET("/timeseries", auditHandlers.GetTimeseries)
 			auditLogs.GET("/critical", auditHandlers.GetCriticalEvents)
 			auditLogs.GET("/failed-auth", auditHandlers.GetFailedAuthAttempts)
 			auditLogs.GET("/suspicious-activity", auditHandlers.GetSuspiciousActivity)
@@ -392,6 +441,20 @@ func setupRouter(cfg *config.Config, auditHandlers *handlers.AuditHandlers, stat
 			auditLogs.GET("/retention", auditHandlers.GetRetentionSettings)
 			auditLogs.PUT("/retention", auditHandlers.SetRetentionSettings)
 			auditLogs.POST("/cleanup", auditHandlers.TriggerCleanup)
+
+			// Ingest sampling policies
+			auditLogs.GET("/sampling-policies", auditHandlers.ListSamplingPolicies)
+			auditLogs.PUT("/sampling-policies", auditHandlers.SetSamplingPolicy)
+			auditLogs.DELETE("/sampling-policies/:id", auditHandlers.DeleteSamplingPolicy)
+
+			// Real-time webhook forwarding
+			auditLogs.GET("/webhooks", auditHandlers.ListWebhookSubscriptions)
+			auditLogs.POST("/webhooks", auditHandlers.CreateWebhookSubscription)
+			auditLogs.GET("/webhooks/deliveries", auditHandlers.ListWebhookDeliveries)
+			auditLogs.POST("/webhooks/deliveries/:id/redeliver", auditHandlers.RedeliverWebhook)
+			auditLogs.PUT("/webhooks/:id", auditHandlers.UpdateWebhookSubscription)
+			auditLogs.DELETE("/webhooks/:id", auditHandlers.DeleteWebhookSubscription)
+			auditLogs.POST("/webhooks/:id/rotate-secret", auditHandlers.RotateWebhookSecret)
 		}
 
 		// Cache management (internal use)
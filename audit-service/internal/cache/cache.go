@@ -29,6 +29,7 @@ type AuditCache struct {
 	defaultTTL      time.Duration
 	summaryTTL      time.Duration
 	criticalTTL     time.Duration
+	timeseriesTTL   time.Duration
 	localCacheSize  int
 
 	// Metrics
@@ -45,6 +46,7 @@ type CacheConfig struct {
 	DefaultTTL     time.Duration // Default cache TTL (default: 5 minutes)
 	SummaryTTL     time.Duration // Summary cache TTL (default: 1 minute)
 	CriticalTTL    time.Duration // Critical events TTL (default: 30 seconds)
+	TimeseriesTTL  time.Duration // Timeseries TTL (default: 1 minute)
 	LocalCacheSize int           // Max items in local cache (default: 1000)
 }
 
@@ -72,6 +74,9 @@ func NewAuditCache(config CacheConfig) *AuditCache {
 	if config.CriticalTTL == 0 {
 		config.CriticalTTL = 30 * time.Second
 	}
+	if config.TimeseriesTTL == 0 {
+		config.TimeseriesTTL = 1 * time.Minute
+	}
 	if config.LocalCacheSize == 0 {
 		config.LocalCacheSize = 1000
 	}
@@ -82,6 +87,7 @@ func NewAuditCache(config CacheConfig) *AuditCache {
 		defaultTTL:     config.DefaultTTL,
 		summaryTTL:     config.SummaryTTL,
 		criticalTTL:    config.CriticalTTL,
+		timeseriesTTL:  config.TimeseriesTTL,
 		localCacheSize: config.LocalCacheSize,
 		local: &LocalCache{
 			items:   make(map[string]*localCacheItem),
@@ -109,6 +115,10 @@ func (c *AuditCache) criticalKey(tenantID string, hours int) string {
 	return fmt.Sprintf("audit:critical:%s:h%d", tenantID, hours)
 }
 
+func (c *AuditCache) timeseriesKey(tenantID, params string) string {
+	return fmt.Sprintf("audit:timeseries:%s:%s", tenantID, params)
+}
+
 func (c *AuditCache) userActivityKey(tenantID, userID string) string {
 	return fmt.Sprintf("audit:user:%s:%s", tenantID, userID)
 }
@@ -317,6 +327,54 @@ func (c *AuditCache) SetCriticalEvents(ctx context.Context, tenantID string, hou
 	return nil
 }
 
+// GetTimeseries retrieves a cached timeseries
+func (c *AuditCache) GetTimeseries(ctx context.Context, tenantID, params string) (*models.AuditTimeseries, error) {
+	key := c.timeseriesKey(tenantID, params)
+
+	if data := c.getLocal(key); data != nil {
+		var ts models.AuditTimeseries
+		if err := json.Unmarshal(data, &ts); err == nil {
+			c.recordHit()
+			return &ts, nil
+		}
+	}
+
+	if c.redis != nil {
+		data, err := c.redis.Get(ctx, key).Bytes()
+		if err == nil {
+			var ts models.AuditTimeseries
+			if err := json.Unmarshal(data, &ts); err == nil {
+				c.setLocal(key, data, c.timeseriesTTL)
+				c.recordHit()
+				return &ts, nil
+			}
+		}
+	}
+
+	c.recordMiss()
+	return nil, ErrCacheMiss
+}
+
+// SetTimeseries caches a timeseries
+// Timeseries are not invalidated on writes; they expire after the timeseries TTL.
+func (c *AuditCache) SetTimeseries(ctx context.Context, tenantID, params string, ts *models.AuditTimeseries) error {
+	key := c.timeseriesKey(tenantID, params)
+
+	data, err := json.Marshal(ts)
+	if err != nil {
+		return err
+	}
+
+	c.setLocal(key, data, c.timeseriesTTL)
+	if c.redis != nil {
+		if err := c.redis.Set(ctx, key, data, c.timeseriesTTL).Err(); err != nil {
+			c.recordError()
+		}
+	}
+
+	return nil
+}
+
 // InvalidateTenant invalidates all cache entries for a tenant
 func (c *AuditCache) InvalidateTenant(ctx context.Context, tenantID string) error {
 	pattern := fmt.Sprintf("audit:*:%s:*", tenantID)
@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Placeholder sources reported by location-service instead of a real lookup
+var placeholderLocationSources = map[string]bool{
+	"default": true, // Private IP
+	"mock":    true, // Mock provider, or fallback after a provider failure
+}
+
+// IPLocation is the coarse location of an IP address
+type IPLocation struct {
+	Country string `json:"country"` // ISO 3166-1 alpha-2
+	City    string `json:"city,omitempty"`
+}
+
+// LocationClient resolves IP addresses through location-service
+type LocationClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewLocationClient creates a new location-service client
+func NewLocationClient(baseURL string, timeout time.Duration) *LocationClient {
+	return &LocationClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+type detectLocationResponse struct {
+	Success bool `json:"success"`
+	Data    struct {
+		Country string  `json:"country"`
+		City    *string `json:"city"`
+		Source  string  `json:"source"`
+	} `json:"data"`
+}
+
+// LocateIP returns the location of an IP address
+// Returns nil without an error when location-service only has placeholder data for the IP
+func (c *LocationClient) LocateIP(ctx context.Context, ip string) (*IPLocation, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/location/detect?ip=%s", c.baseURL, url.QueryEscape(ip))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create location request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call location-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("location-service returned status %d", resp.StatusCode)
+	}
+
+	var body detectLocationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode location response: %w", err)
+	}
+	if !body.Success || body.Data.Country == "" || placeholderLocationSources[body.Data.Source] {
+		return nil, nil
+	}
+
+	location := &IPLocation{Country: strings.ToUpper(body.Data.Country)}
+	if body.Data.City != nil {
+		location.City = *body.Data.City
+	}
+	return location, nil
+}
@@ -19,6 +19,9 @@ type Config struct {
 	Tenant     TenantConfig
 	Pool       PoolConfig
 	Retention  RetentionConfig
+	Geo        GeoConfig
+	Sampling   SamplingConfig
+	Webhooks   WebhookConfig
 }
 
 // FallbackDBConfig holds fallback database configuration (used when tenant config unavailable)
@@ -42,6 +45,35 @@ type RetentionConfig struct {
 	CleanupEnabled  bool   // Whether auto-cleanup is enabled
 	CleanupSchedule string // Cron schedule for cleanup job
 	BatchSize       int    // Batch size for cleanup operations
+
+	// PlanTiers caps retention days by tenant subscription plan (e.g. free:30, starter:90)
+	PlanTiers map[string]int
+}
+
+// GeoConfig holds IP geolocation enrichment configuration
+type GeoConfig struct {
+	Enabled            bool
+	LocationServiceURL string // location-service base URL used to resolve IPs
+	LookupTimeoutMs    int    // Per-lookup timeout; ingest never waits longer than this
+	CacheTTL           int    // How long a resolved IP is cached, in seconds
+	FailureCacheTTL    int    // How long an unresolvable IP is cached, in seconds
+}
+
+// SamplingConfig holds ingest sampling configuration
+type SamplingConfig struct {
+	Enabled        bool
+	PolicyCacheTTL int // How long a tenant's sampling policies are cached per instance, in seconds
+}
+
+// WebhookConfig holds real-time webhook forwarding configuration
+type WebhookConfig struct {
+	Enabled              bool
+	TimeoutSeconds       int  // Per-attempt HTTP timeout
+	MaxAttempts          int  // Attempts before a delivery is dead-lettered
+	RetryBaseSeconds     int  // First retry delay; doubles on every further attempt
+	RetryInterval        int  // How often due retries are picked up, in seconds
+	SubscriptionCacheTTL int  // How long a tenant's subscriptions are cached per instance, in seconds
+	AllowPrivateTargets  bool // Allow endpoints on private, loopback and link-local addresses (development only)
 }
 
 // NATSConfig holds NATS configuration for real-time event streaming
@@ -93,9 +125,11 @@ type PoolConfig struct {
 
 // AppConfig holds application-specific configuration
 type AppConfig struct {
-	Environment string
-	LogLevel    string
-	JWTSecret   string
+	Environment     string
+	LogLevel        string
+	JWTSecret       string
+	StaffServiceURL string // RBAC permission lookups for field visibility
+	PseudonymSecret string // Keys pseudonyms for purged customers; must stay stable across deploys
 }
 
 // Load loads configuration from environment variables
@@ -154,11 +188,34 @@ func Load() (*Config, error) {
 			CleanupEnabled:  getEnvAsBool("AUDIT_CLEANUP_ENABLED", true),
 			CleanupSchedule: getEnv("AUDIT_CLEANUP_SCHEDULE", "0 2 * * *"), // 2 AM daily
 			BatchSize:       getEnvAsInt("AUDIT_BATCH_SIZE", 100),
+			PlanTiers:       getEnvAsIntMap("AUDIT_RETENTION_PLAN_TIERS", "free:30,starter:90,professional:365,enterprise:365"),
+		},
+		Geo: GeoConfig{
+			Enabled:            getEnvAsBool("AUDIT_GEO_ENRICHMENT_ENABLED", true),
+			LocationServiceURL: getEnv("LOCATION_SERVICE_URL", "http://location-service:8080"),
+			LookupTimeoutMs:    getEnvAsInt("AUDIT_GEO_LOOKUP_TIMEOUT_MS", 1500),
+			CacheTTL:           getEnvAsInt("AUDIT_GEO_CACHE_TTL", 86400),       // 24 hours
+			FailureCacheTTL:    getEnvAsInt("AUDIT_GEO_FAILURE_CACHE_TTL", 600), // 10 minutes
+		},
+		Sampling: SamplingConfig{
+			Enabled:        getEnvAsBool("AUDIT_SAMPLING_ENABLED", true),
+			PolicyCacheTTL: getEnvAsInt("AUDIT_SAMPLING_POLICY_CACHE_TTL", 60),
+		},
+		Webhooks: WebhookConfig{
+			Enabled:              getEnvAsBool("AUDIT_WEBHOOKS_ENABLED", true),
+			TimeoutSeconds:       getEnvAsInt("AUDIT_WEBHOOK_TIMEOUT_SECONDS", 10),
+			MaxAttempts:          getEnvAsInt("AUDIT_WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryBaseSeconds:     getEnvAsInt("AUDIT_WEBHOOK_RETRY_BASE_SECONDS", 60),
+			RetryInterval:        getEnvAsInt("AUDIT_WEBHOOK_RETRY_INTERVAL", 30),
+			SubscriptionCacheTTL: getEnvAsInt("AUDIT_WEBHOOK_SUBSCRIPTION_CACHE_TTL", 60),
+			AllowPrivateTargets:  getEnvAsBool("AUDIT_WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
 		},
 		App: AppConfig{
 			Environment: getEnv("APP_ENV", "development"),
 			LogLevel:    getEnv("LOG_LEVEL", "info"),
 			JWTSecret: secrets.GetJWTSecret(),
+			StaffServiceURL: getEnv("STAFF_SERVICE_URL", "http://staff-service:8080"),
+			PseudonymSecret: getEnv("AUDIT_PSEUDONYM_SECRET", ""),
 		},
 	}
 
@@ -240,3 +297,20 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvAsIntMap parses "key:value,key:value" into a map with lower-cased keys; malformed entries are skipped
+func getEnvAsIntMap(key, defaultValue string) map[string]int {
+	result := make(map[string]int)
+	for _, pair := range strings.Split(getEnv(key, defaultValue), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			continue
+		}
+		intValue, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || intValue <= 0 {
+			continue
+		}
+		result[strings.ToLower(strings.TrimSpace(name))] = intValue
+	}
+	return result
+}
@@ -103,6 +103,7 @@ func (c *DomainEventConsumer) Start(ctx context.Context) error {
 		{name: "PAYMENT_EVENTS", subjects: []string{"payment.>"}},
 		{name: "CUSTOMER_EVENTS", subjects: []string{"customer.>"}},
 		{name: "AUTH_EVENTS", subjects: []string{"auth.>"}},
+		{name: "SECURITY_EVENTS", subjects: []string{"security.>"}},
 		{name: "INVENTORY_EVENTS", subjects: []string{"inventory.>"}},
 		{name: "PRODUCT_EVENTS", subjects: []string{"product.>"}},
 		{name: "RETURN_EVENTS", subjects: []string{"return.>"}},
@@ -126,6 +127,10 @@ func (c *DomainEventConsumer) Start(ctx context.Context) error {
 		}
 	}
 
+	if err := c.subscribeTenantDeletion(ctx); err != nil {
+		c.logger.WithError(err).Warn("Failed to subscribe to tenant deletion requests (deleted tenants won't be purged)")
+	}
+
 	c.logger.Info("Domain event consumer started")
 	return nil
 }
@@ -225,6 +230,14 @@ func (c *DomainEventConsumer) processMessage(ctx context.Context, msg jetstream.
 	// Convert to audit log
 	auditLog := c.convertToAuditLog(msg.Subject(), &baseEvent, msg.Data())
 
+	// Purged customers are pseudonymized in history before the purge itself is logged,
+	// so a failure is redelivered without leaving an identifying purge record behind
+	if baseEvent.EventType == "customer.purged" {
+		if err := c.pseudonymizeCustomer(ctx, &baseEvent, msg.Data(), auditLog); err != nil {
+			return err
+		}
+	}
+
 	// Create audit log
 	if err := c.auditService.LogAction(ctx, baseEvent.TenantID, auditLog); err != nil {
 		return fmt.Errorf("failed to create audit log: %w", err)
@@ -237,6 +250,57 @@ func (c *DomainEventConsumer) processMessage(ctx context.Context, msg jetstream.
 		"resource":   auditLog.Resource,
 	}).Debug("Created audit log from domain event")
 
+	// Keep plan-linked retention tiers in sync with the tenant's subscription
+	if baseEvent.EventType == "tenant.subscription_changed" {
+		var planEvent struct {
+			SubscriptionPlan string `json:"subscriptionPlan"`
+		}
+		if err := json.Unmarshal(msg.Data(), &planEvent); err == nil && planEvent.SubscriptionPlan != "" {
+			// The audit log is already written, so a failure here is logged rather than redelivered
+			if err := c.auditService.ApplyTenantPlan(ctx, baseEvent.TenantID, planEvent.SubscriptionPlan); err != nil {
+				c.logger.WithError(err).WithField("tenant_id", baseEvent.TenantID).Error("Failed to apply tenant plan to audit retention")
+			}
+		}
+	}
+
+	return nil
+}
+
+// pseudonymizeCustomer replaces a purged customer's identifiers in historical audit
+// logs and in the audit log recording the purge
+func (c *DomainEventConsumer) pseudonymizeCustomer(ctx context.Context, event *BaseEvent, rawData []byte, auditLog *models.AuditLog) error {
+	var purged struct {
+		CustomerID    string `json:"customerId"`
+		UserID        string `json:"userId"`
+		CustomerEmail string `json:"customerEmail"`
+		Email         string `json:"email"`
+	}
+	if err := json.Unmarshal(rawData, &purged); err != nil {
+		return fmt.Errorf("failed to unmarshal customer.purged event: %w", err)
+	}
+
+	subject := models.ErasureSubject{
+		CustomerID: purged.CustomerID,
+		Email:      purged.CustomerEmail,
+	}
+	if subject.Email == "" {
+		subject.Email = purged.Email
+	}
+	for _, id := range []string{purged.UserID, purged.CustomerID} {
+		if uid, err := uuid.Parse(id); err == nil {
+			subject.UserID = uid
+			break
+		}
+	}
+	if subject.IsEmpty() {
+		c.logger.WithField("tenant_id", event.TenantID).Warn("Skipping pseudonymization for customer.purged event without identifiers")
+		return nil
+	}
+
+	if _, err := c.auditService.PseudonymizeSubject(ctx, event.TenantID, subject); err != nil {
+		return fmt.Errorf("failed to pseudonymize purged customer: %w", err)
+	}
+	c.auditService.PseudonymizeLog(event.TenantID, subject, auditLog)
 	return nil
 }
 
@@ -376,6 +440,10 @@ func (c *DomainEventConsumer) convertToAuditLog(subject string, event *BaseEvent
 		auditLog.Status = models.StatusFailure
 	}
 
+	if isSecurityEvent(event.EventType) {
+		applySecurityEvent(auditLog, eventData)
+	}
+
 	return auditLog
 }
 
@@ -415,6 +483,8 @@ func (c *DomainEventConsumer) mapEventToAudit(eventType string) (models.AuditAct
 		return models.ActionUpdate, models.ResourceCustomer, models.SeverityLow
 	case "customer.deleted":
 		return models.ActionDelete, models.ResourceCustomer, models.SeverityMedium
+	case "customer.purged":
+		return models.ActionDelete, models.ResourceCustomer, models.SeverityHigh
 
 	// Auth events
 	case "auth.login_success":
@@ -432,6 +502,18 @@ func (c *DomainEventConsumer) mapEventToAudit(eventType string) (models.AuditAct
 	case "auth.email_verified", "auth.phone_verified":
 		return models.ActionUpdate, models.ResourceAuth, models.SeverityLow
 
+	// Security events (severity is overridden by the event's own severity)
+	case "security.login_failed":
+		return models.ActionLoginFailed, models.ResourceAuth, models.SeverityMedium
+	case "security.account_locked":
+		return models.ActionUpdate, models.ResourceAuth, models.SeverityHigh
+	case "security.password_reset":
+		return models.ActionPasswordReset, models.ResourceAuth, models.SeverityMedium
+	case "security.role_escalation":
+		return models.ActionRoleAssign, models.ResourceRole, models.SeverityHigh
+	case "security.impersonation":
+		return models.ActionLogin, models.ResourceUser, models.SeverityCritical
+
 	// Product events
 	case "product.created":
 		return models.ActionCreate, models.ResourceProduct, models.SeverityLow
@@ -523,6 +605,8 @@ func (c *DomainEventConsumer) mapEventToAudit(eventType string) (models.AuditAct
 		return models.ActionUpdate, models.ResourceTenant, models.SeverityCritical
 	case "tenant.settings_updated":
 		return models.ActionUpdate, models.ResourceSettings, models.SeverityMedium
+	case "tenant.subscription_changed":
+		return models.ActionUpdate, models.ResourceTenant, models.SeverityMedium
 
 	// Settings events (from settings-service via NATS)
 	case "settings.updated", "settings.created", "settings.bulk_updated":
@@ -769,6 +853,29 @@ func (c *DomainEventConsumer) generateDescription(eventType string, data map[str
 		return "Settings were created"
 	case "settings.bulk_updated":
 		return "Settings were bulk updated"
+	// Security events
+	case "security.login_failed":
+		if email, ok := data["actorEmail"].(string); ok && email != "" {
+			return fmt.Sprintf("Failed login attempt for %s", email)
+		}
+		return "Failed login attempt"
+	case "security.account_locked":
+		if email, ok := data["actorEmail"].(string); ok && email != "" {
+			return fmt.Sprintf("Login locked for %s after repeated failures", email)
+		}
+		return "Login locked after repeated failures"
+	case "security.password_reset":
+		if email, ok := data["targetEmail"].(string); ok && email != "" {
+			return fmt.Sprintf("Password reset for %s", email)
+		}
+		return "Password was reset"
+	case "security.role_escalation":
+		return "Role change granted additional permissions"
+	case "security.impersonation":
+		if email, ok := data["targetEmail"].(string); ok && email != "" {
+			return fmt.Sprintf("User %s was impersonated", email)
+		}
+		return "A user was impersonated"
 	default:
 		// Generate generic description from event type
 		return fmt.Sprintf("Event: %s", eventType)
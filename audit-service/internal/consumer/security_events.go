@@ -0,0 +1,61 @@
+package consumer
+
+import (
+	"encoding/json"
+	"strings"
+
+	"audit-service/internal/models"
+)
+
+// securityEventPrefix is the subject prefix of the standardized security events (SECURITY_EVENTS stream)
+const securityEventPrefix = "security."
+
+// isSecurityEvent reports whether an event type is a standardized security event
+func isSecurityEvent(eventType string) bool {
+	return strings.HasPrefix(eventType, securityEventPrefix)
+}
+
+// applySecurityEvent fills in the fields specific to security events (schema version 1):
+// the outcome decides the status, the event's own severity overrides the default mapping,
+// the target user becomes the resource and synthetic events are tagged so alerts on them
+// can be told apart from real incidents
+func applySecurityEvent(auditLog *models.AuditLog, data map[string]interface{}) {
+	if outcome, _ := data["outcome"].(string); outcome == "failure" {
+		auditLog.Status = models.StatusFailure
+	}
+
+	switch severity := models.AuditSeverity(strings.ToUpper(stringField(data, "severity"))); severity {
+	case models.SeverityLow, models.SeverityMedium, models.SeverityHigh, models.SeverityCritical:
+		auditLog.Severity = severity
+	}
+
+	if targetID := stringField(data, "targetUserId"); targetID != "" {
+		auditLog.ResourceID = targetID
+	}
+	if targetEmail := stringField(data, "targetEmail"); targetEmail != "" {
+		auditLog.ResourceName = targetEmail
+	}
+	if reason := stringField(data, "reason"); reason != "" && auditLog.ErrorMessage == "" && auditLog.Status == models.StatusFailure {
+		auditLog.ErrorMessage = reason
+	}
+
+	tags := []string{"security"}
+	if synthetic, _ := data["synthetic"].(bool); synthetic {
+		tags = append(tags, "synthetic")
+		auditLog.Description = "[Synthetic] " + auditLog.Description
+	}
+	if encoded, err := json.Marshal(tags); err == nil {
+		auditLog.Tags = encoded
+	}
+	if metadata, ok := data["metadata"].(map[string]interface{}); ok && len(metadata) > 0 {
+		if encoded, err := json.Marshal(metadata); err == nil {
+			auditLog.Metadata = encoded
+		}
+	}
+}
+
+// stringField returns a string field of an event, or "" when it is missing or not a string
+func stringField(data map[string]interface{}, field string) string {
+	value, _ := data[field].(string)
+	return value
+}
@@ -0,0 +1,154 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// tenantDeletionService is how tenant-service's deletion saga addresses this service
+	tenantDeletionService = "audit-service"
+
+	tenantDeletionRequested    = "tenant.deletion.requested"
+	tenantDeletionAcknowledged = "tenant.deletion.acknowledged"
+
+	// tenantDeletionConsumer is separate from the TENANT_EVENTS audit consumer, so redelivering a
+	// failed acknowledgment does not redeliver the events being audited
+	tenantDeletionConsumer = "audit-service-tenant-deletion"
+)
+
+// tenantDeletionRequest is the part of tenant-service's tenant.deletion.requested event needed
+// to purge a tenant
+type tenantDeletionRequest struct {
+	TenantID string   `json:"tenant_id"`
+	Services []string `json:"services"` // Services asked to purge; others ignore the request
+}
+
+// tenantDeletionAck reports the outcome of a purge back to tenant-service
+type tenantDeletionAck struct {
+	EventType   string    `json:"event_type"`
+	TenantID    string    `json:"tenant_id"`
+	Service     string    `json:"service"`
+	Status      string    `json:"status"` // "purged" or "failed"
+	ItemsPurged int64     `json:"items_purged,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// subscribeTenantDeletion purges deleted tenants' audit data when tenant-service's deletion saga
+// asks, acknowledging each request on tenant.deletion.acknowledged
+func (c *DomainEventConsumer) subscribeTenantDeletion(ctx context.Context) error {
+	stream, err := c.js.Stream(ctx, "TENANT_EVENTS")
+	if err != nil {
+		return fmt.Errorf("stream TENANT_EVENTS not found: %w", err)
+	}
+
+	consumer, err := stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Name:           tenantDeletionConsumer,
+		Durable:        tenantDeletionConsumer,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		AckWait:        2 * time.Minute,
+		MaxDeliver:     5,
+		DeliverPolicy:  jetstream.DeliverNewPolicy,
+		FilterSubjects: []string{tenantDeletionRequested},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tenant deletion consumer: %w", err)
+	}
+
+	c.mu.Lock()
+	c.consumers = append(c.consumers, consumer)
+	c.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-c.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			msgs, err := consumer.Fetch(10, jetstream.FetchMaxWait(5*time.Second))
+			if err != nil {
+				if err != context.DeadlineExceeded && err != nats.ErrTimeout {
+					c.logger.WithError(err).Warn("Error fetching tenant deletion requests")
+				}
+				continue
+			}
+
+			for msg := range msgs.Messages() {
+				if err := c.handleTenantDeletion(ctx, msg.Data()); err != nil {
+					c.logger.WithError(err).Error("Failed to acknowledge tenant deletion")
+					msg.Nak()
+				} else {
+					msg.Ack()
+				}
+			}
+		}
+	}()
+
+	c.logger.WithField("consumer", tenantDeletionConsumer).Info("Subscribed to tenant deletion requests")
+	return nil
+}
+
+// handleTenantDeletion purges the requested tenant and publishes the outcome. A failed purge is
+// reported rather than redelivered; tenant-service re-requests it later. Only a failure to
+// publish the acknowledgment is returned, so the request is redelivered and purged again.
+func (c *DomainEventConsumer) handleTenantDeletion(ctx context.Context, data []byte) error {
+	var request tenantDeletionRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		c.logger.WithError(err).Warn("Dropping malformed tenant deletion request")
+		return nil
+	}
+	if request.TenantID == "" || !containsService(request.Services, tenantDeletionService) {
+		return nil
+	}
+
+	ack := tenantDeletionAck{
+		EventType: tenantDeletionAcknowledged,
+		TenantID:  request.TenantID,
+		Service:   tenantDeletionService,
+		Status:    "purged",
+	}
+	purged, err := c.auditService.PurgeTenant(ctx, request.TenantID)
+	if err != nil {
+		ack.Status = "failed"
+		ack.Error = err.Error()
+	} else {
+		ack.ItemsPurged = purged
+	}
+	ack.Timestamp = time.Now().UTC()
+
+	payload, err := json.Marshal(ack)
+	if err != nil {
+		return err
+	}
+	if _, err := c.js.Publish(ctx, tenantDeletionAcknowledged, payload); err != nil {
+		return err
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"tenant_id": request.TenantID,
+		"status":    ack.Status,
+		"purged":    purged,
+	}).Info("Acknowledged tenant deletion")
+	return nil
+}
+
+// containsService reports whether service is one of the services a deletion request names
+func containsService(services []string, service string) bool {
+	for _, s := range services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
@@ -421,9 +421,9 @@ func (m *Manager) connectWithTimeout(ctx context.Context, dsn string, config ten
 	}
 }
 
-// runMigrations creates the audit_logs table if it doesn't exist
+// runMigrations creates the audit_logs, sampling policy and webhook tables if they don't exist
 func (m *Manager) runMigrations(db *gorm.DB) error {
-	return db.AutoMigrate(&models.AuditLog{})
+	return db.AutoMigrate(&models.AuditLog{}, &models.SamplingPolicy{}, &models.WebhookSubscription{}, &models.WebhookDelivery{})
 }
 
 // getCircuitBreaker gets or creates a circuit breaker for a tenant
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,6 +15,7 @@ import (
 
 	"audit-service/internal/models"
 	auditNats "audit-service/internal/nats"
+	"audit-service/internal/repository"
 	"audit-service/internal/services"
 )
 
@@ -21,14 +24,19 @@ type AuditHandlers struct {
 	service    *services.AuditService
 	logger     *logrus.Logger
 	subscriber *auditNats.Subscriber
+	visibility *FieldVisibility
 }
 
 // NewAuditHandlers creates a new audit handlers instance
-func NewAuditHandlers(service *services.AuditService, logger *logrus.Logger, subscriber *auditNats.Subscriber) *AuditHandlers {
+func NewAuditHandlers(service *services.AuditService, logger *logrus.Logger, subscriber *auditNats.Subscriber, visibility *FieldVisibility) *AuditHandlers {
+	if visibility == nil {
+		visibility = NewFieldVisibility(nil, logger)
+	}
 	return &AuditHandlers{
 		service:    service,
 		logger:     logger,
 		subscriber: subscriber,
+		visibility: visibility,
 	}
 }
 
@@ -49,11 +57,17 @@ func (h *AuditHandlers) CreateAuditLog(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.LogAction(c.Request.Context(), tenantID, &log); err != nil {
+	stored, err := h.service.Ingest(c.Request.Context(), tenantID, &log)
+	if err != nil {
 		h.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to create audit log")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create audit log"})
 		return
 	}
+	if !stored {
+		// Counted towards the sampleWeight of the next stored event of the same policy
+		c.JSON(http.StatusAccepted, gin.H{"stored": false, "reason": "sampled"})
+		return
+	}
 
 	c.JSON(http.StatusCreated, log)
 }
@@ -84,6 +98,7 @@ func (h *AuditHandlers) GetAuditLog(c *gin.Context) {
 		return
 	}
 
+	log.ApplyView(h.visibility.Resolve(c))
 	c.JSON(http.StatusOK, log)
 }
 
@@ -145,6 +160,7 @@ func (h *AuditHandlers) ListAuditLogs(c *gin.Context) {
 		return
 	}
 
+	models.ApplyViewToLogs(logs, h.visibility.Resolve(c))
 	c.JSON(http.StatusOK, gin.H{
 		"data":   logs,
 		"total":  total,
@@ -176,6 +192,7 @@ func (h *AuditHandlers) GetResourceHistory(c *gin.Context) {
 		return
 	}
 
+	models.ApplyViewToLogs(logs, h.visibility.Resolve(c))
 	c.JSON(http.StatusOK, gin.H{
 		"resource_type": resourceType,
 		"resource_id":   resourceID,
@@ -206,6 +223,7 @@ func (h *AuditHandlers) GetUserActivity(c *gin.Context) {
 		return
 	}
 
+	models.ApplyViewToLogs(logs, h.visibility.Resolve(c))
 	c.JSON(http.StatusOK, gin.H{
 		"user_id":  userID,
 		"activity": logs,
@@ -231,6 +249,7 @@ func (h *AuditHandlers) GetCriticalEvents(c *gin.Context) {
 		return
 	}
 
+	models.ApplyViewToLogs(logs, h.visibility.Resolve(c))
 	c.JSON(http.StatusOK, gin.H{
 		"events": logs,
 		"count":  len(logs),
@@ -256,6 +275,7 @@ func (h *AuditHandlers) GetFailedAuthAttempts(c *gin.Context) {
 		return
 	}
 
+	models.ApplyViewToLogs(logs, h.visibility.Resolve(c))
 	c.JSON(http.StatusOK, gin.H{
 		"attempts": logs,
 		"count":    len(logs),
@@ -294,9 +314,46 @@ func (h *AuditHandlers) GetSummary(c *gin.Context) {
 		return
 	}
 
+	summary.ApplyView(h.visibility.Resolve(c))
 	c.JSON(http.StatusOK, summary)
 }
 
+// GetTimeseries retrieves bucketed audit log counts for charts
+// GET /api/v1/audit-logs/timeseries?interval=1h&group_by=action&from=...&to=...
+func (h *AuditHandlers) GetTimeseries(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	var from, to *time.Time
+	for param, target := range map[string]**time.Time{"from": &from, "to": &to} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid %s: expected RFC3339 timestamp", param)})
+			return
+		}
+		*target = &parsed
+	}
+
+	ts, err := h.service.GetTimeseries(c.Request.Context(), tenantID, c.Query("interval"), c.Query("group_by"), from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTimeseriesQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get audit timeseries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ts)
+}
+
 // ExportAuditLogs exports audit logs in JSON or CSV format
 // GET /api/v1/audit-logs/export
 func (h *AuditHandlers) ExportAuditLogs(c *gin.Context) {
@@ -335,13 +392,15 @@ func (h *AuditHandlers) ExportAuditLogs(c *gin.Context) {
 	var filename string
 	var err error
 
+	view := h.visibility.Resolve(c)
+
 	switch format {
 	case "csv":
-		data, err = h.service.ExportToCSV(c.Request.Context(), tenantID, filter)
+		data, err = h.service.ExportToCSV(c.Request.Context(), tenantID, filter, view)
 		contentType = "text/csv"
 		filename = fmt.Sprintf("audit-logs-%s.csv", time.Now().Format("2006-01-02"))
 	case "json":
-		data, err = h.service.ExportToJSON(c.Request.Context(), tenantID, filter)
+		data, err = h.service.ExportToJSON(c.Request.Context(), tenantID, filter, view)
 		contentType = "application/json"
 		filename = fmt.Sprintf("audit-logs-%s.json", time.Now().Format("2006-01-02"))
 	default:
@@ -361,7 +420,7 @@ func (h *AuditHandlers) ExportAuditLogs(c *gin.Context) {
 }
 
 // GetSuspiciousActivity detects suspicious activity patterns
-// GET /api/v1/audit-logs/suspicious-activity
+// GET /api/v1/audit-logs/suspicious-activity?outside_countries=IN,US
 func (h *AuditHandlers) GetSuspiciousActivity(c *gin.Context) {
 	tenantID := c.GetString("tenant_id")
 	if tenantID == "" {
@@ -369,17 +428,47 @@ func (h *AuditHandlers) GetSuspiciousActivity(c *gin.Context) {
 		return
 	}
 
-	logs, err := h.service.GetSuspiciousActivity(c.Request.Context(), tenantID)
+	var params repository.SuspiciousActivityParams
+	if raw := c.Query("outside_countries"); raw != "" {
+		for _, code := range strings.Split(raw, ",") {
+			code = strings.ToUpper(strings.TrimSpace(code))
+			if !isCountryCode(code) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "outside_countries must be comma-separated ISO 3166-1 alpha-2 codes"})
+				return
+			}
+			params.OutsideCountries = append(params.OutsideCountries, code)
+		}
+	}
+
+	logs, err := h.service.GetSuspiciousActivity(c.Request.Context(), tenantID, params)
 	if err != nil {
 		h.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to detect suspicious activity")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to detect suspicious activity"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	models.ApplyViewToLogs(logs, h.visibility.Resolve(c))
+	response := gin.H{
 		"suspicious_events": logs,
 		"count":             len(logs),
-	})
+	}
+	if len(params.OutsideCountries) > 0 {
+		response["outside_countries"] = params.OutsideCountries
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// isCountryCode reports whether code looks like an ISO 3166-1 alpha-2 code
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
 }
 
 // GetUserIPHistory retrieves all IP addresses used by a user
@@ -403,6 +492,7 @@ func (h *AuditHandlers) GetUserIPHistory(c *gin.Context) {
 		return
 	}
 
+	models.ApplyViewToIPHistory(entries, h.visibility.Resolve(c))
 	c.JSON(http.StatusOK, gin.H{
 		"user_id":    userID,
 		"ip_history": entries,
@@ -428,6 +518,7 @@ func (h *AuditHandlers) GetRecentLogs(c *gin.Context) {
 		return
 	}
 
+	models.ApplyViewToLogs(logs, h.visibility.Resolve(c))
 	c.JSON(http.StatusOK, gin.H{
 		"logs":  logs,
 		"count": len(logs),
@@ -453,9 +544,13 @@ func (h *AuditHandlers) StreamAuditLogs(c *gin.Context) {
 	// Create a channel to signal client disconnect
 	clientGone := c.Request.Context().Done()
 
+	// Resolve field visibility once for the lifetime of the stream
+	view := h.visibility.Resolve(c)
+
 	// Initial fetch - send recent logs on connect
 	logs, err := h.service.GetRecentLogs(c.Request.Context(), tenantID, 20)
 	if err == nil && len(logs) > 0 {
+		models.ApplyViewToLogs(logs, view)
 		data, _ := json.Marshal(gin.H{"type": "initial", "logs": logs})
 		fmt.Fprintf(c.Writer, "data: %s\n\n", data)
 		c.Writer.Flush()
@@ -466,7 +561,7 @@ func (h *AuditHandlers) StreamAuditLogs(c *gin.Context) {
 		eventChan, cleanup, err := h.subscriber.SubscribeToTenant(c.Request.Context(), tenantID)
 		if err != nil {
 			h.logger.WithError(err).WithField("tenant_id", tenantID).Warn("Failed to subscribe to NATS, falling back to polling")
-			h.streamWithPolling(c, tenantID, clientGone)
+			h.streamWithPolling(c, tenantID, view, clientGone)
 			return
 		}
 		defer cleanup()
@@ -494,7 +589,9 @@ func (h *AuditHandlers) StreamAuditLogs(c *gin.Context) {
 				}
 				// Forward NATS event to SSE client
 				if event.Log != nil {
-					data, _ := json.Marshal(gin.H{"type": "update", "logs": []*models.AuditLog{event.Log}})
+					shaped := *event.Log
+					shaped.ApplyView(view)
+					data, _ := json.Marshal(gin.H{"type": "update", "logs": []*models.AuditLog{&shaped}})
 					fmt.Fprintf(c.Writer, "data: %s\n\n", data)
 					c.Writer.Flush()
 				}
@@ -506,7 +603,7 @@ func (h *AuditHandlers) StreamAuditLogs(c *gin.Context) {
 		}
 	} else {
 		// Fallback to polling if NATS is not available
-		h.streamWithPolling(c, tenantID, clientGone)
+		h.streamWithPolling(c, tenantID, view, clientGone)
 	}
 }
 
@@ -527,8 +624,9 @@ func (h *AuditHandlers) GetRetentionSettings(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"settings": settings,
-		"options":  h.service.GetRetentionOptions(),
+		"settings":               settings,
+		"effectiveRetentionDays": h.service.EffectiveRetentionDays(settings),
+		"options":                h.service.GetRetentionOptions(settings),
 	})
 }
 
@@ -542,22 +640,38 @@ func (h *AuditHandlers) SetRetentionSettings(c *gin.Context) {
 	}
 
 	var request struct {
-		RetentionDays int `json:"retentionDays" binding:"required,min=90,max=365"`
+		RetentionDays int `json:"retentionDays" binding:"required,min=1"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request body",
 			"details": err.Error(),
-			"hint":    "Retention days must be between 90 (3 months) and 365 (1 year)",
+			"hint":    "retentionDays must be a positive number of days",
 		})
 		return
 	}
 
 	settings, err := h.service.SetRetentionSettings(c.Request.Context(), tenantID, request.RetentionDays)
 	if err != nil {
-		h.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to set retention settings")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set retention settings"})
+		var planErr *services.RetentionPlanError
+		switch {
+		case errors.As(err, &planErr):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   planErr.Error(),
+				"code":    "RETENTION_EXCEEDS_PLAN",
+				"details": planErr,
+				"hint":    planErr.Hint(),
+			})
+		case errors.Is(err, services.ErrRetentionOutOfRange):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "RETENTION_OUT_OF_RANGE",
+			})
+		default:
+			h.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to set retention settings")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set retention settings"})
+		}
 		return
 	}
 
@@ -589,8 +703,107 @@ func (h *AuditHandlers) TriggerCleanup(c *gin.Context) {
 	})
 }
 
+// ListSamplingPolicies lists the tenant's ingest sampling policies
+// GET /api/v1/audit-logs/sampling-policies
+func (h *AuditHandlers) ListSamplingPolicies(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	policies, err := h.service.ListSamplingPolicies(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sampling policies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"policies": policies,
+		"count":    len(policies),
+	})
+}
+
+// SetSamplingPolicy creates or replaces the sampling policy of an action and resource
+// PUT /api/v1/audit-logs/sampling-policies
+func (h *AuditHandlers) SetSamplingPolicy(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	var request struct {
+		Action      models.AuditAction   `json:"action"`
+		Resource    models.AuditResource `json:"resource"`
+		SampleRate  float64              `json:"sampleRate" binding:"required"`
+		MaxSeverity models.AuditSeverity `json:"maxSeverity"`
+		Description string               `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+			"hint":    "sampleRate is the share of matching events stored, e.g. 0.01 for 1%",
+		})
+		return
+	}
+
+	policy := &models.SamplingPolicy{
+		Action:      models.AuditAction(strings.ToUpper(string(request.Action))),
+		Resource:    models.AuditResource(strings.ToUpper(string(request.Resource))),
+		SampleRate:  request.SampleRate,
+		MaxSeverity: models.AuditSeverity(strings.ToUpper(string(request.MaxSeverity))),
+		Description: request.Description,
+	}
+	if err := h.service.SetSamplingPolicy(c.Request.Context(), tenantID, policy); err != nil {
+		if errors.Is(err, services.ErrInvalidSamplingPolicy) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "INVALID_SAMPLING_POLICY",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save sampling policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Sampling policy saved successfully",
+		"policy":  policy,
+	})
+}
+
+// DeleteSamplingPolicy removes a sampling policy
+// DELETE /api/v1/audit-logs/sampling-policies/:id
+func (h *AuditHandlers) DeleteSamplingPolicy(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sampling policy ID"})
+		return
+	}
+
+	if err := h.service.DeleteSamplingPolicy(c.Request.Context(), tenantID, id); err != nil {
+		if errors.Is(err, repository.ErrSamplingPolicyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sampling policy not found"})
+			return
+		}
+		h.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to delete sampling policy")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete sampling policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sampling policy deleted successfully"})
+}
+
 // streamWithPolling is the fallback polling-based implementation
-func (h *AuditHandlers) streamWithPolling(c *gin.Context, tenantID string, clientGone <-chan struct{}) {
+func (h *AuditHandlers) streamWithPolling(c *gin.Context, tenantID string, view models.FieldView, clientGone <-chan struct{}) {
 	h.logger.WithField("tenant_id", tenantID).Info("SSE client connected with polling fallback")
 
 	// Send connected event (polling mode)
@@ -630,6 +843,7 @@ func (h *AuditHandlers) streamWithPolling(c *gin.Context, tenantID string, clien
 
 			if len(newLogs) > 0 {
 				lastTimestamp = newLogs[0].Timestamp
+				models.ApplyViewToLogs(newLogs, view)
 				data, _ := json.Marshal(gin.H{"type": "update", "logs": newLogs})
 				fmt.Fprintf(c.Writer, "data: %s\n\n", data)
 				c.Writer.Flush()
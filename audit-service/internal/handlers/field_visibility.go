@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"audit-service/internal/models"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+)
+
+// PermissionChecker checks a caller's RBAC permissions (satisfied by rbac.Middleware)
+type PermissionChecker interface {
+	HasPermission(c *gin.Context, permission string) bool
+}
+
+// fieldViewContextKey caches the resolved view for the lifetime of a request
+const fieldViewContextKey = "audit_field_view"
+
+// FieldVisibility resolves which audit fields a caller may see
+type FieldVisibility struct {
+	checker PermissionChecker
+	logger  *logrus.Logger
+}
+
+// NewFieldVisibility creates a new field visibility resolver
+// A nil checker grants the full view to every caller (development mode)
+func NewFieldVisibility(checker PermissionChecker, logger *logrus.Logger) *FieldVisibility {
+	return &FieldVisibility{
+		checker: checker,
+		logger:  logger,
+	}
+}
+
+// Resolve returns the caller's field view and logs the redaction decision
+func (v *FieldVisibility) Resolve(c *gin.Context) models.FieldView {
+	if cached, ok := c.Get(fieldViewContextKey); ok {
+		if view, ok := cached.(models.FieldView); ok {
+			return view
+		}
+	}
+
+	view, reason := v.resolve(c)
+	c.Set(fieldViewContextKey, view)
+
+	entry := v.logger.WithFields(logrus.Fields{
+		"tenant_id":  c.GetString("tenant_id"),
+		"user_id":    c.GetString("user_id"),
+		"staff_id":   c.GetString("staff_id"),
+		"path":       c.FullPath(),
+		"field_view": view,
+		"reason":     reason,
+	})
+	if view == models.ViewFull {
+		entry.Debug("Audit field visibility resolved")
+	} else {
+		entry.Info("Audit response redacted")
+	}
+
+	return view
+}
+
+// resolve picks the most permissive view the caller is entitled to
+func (v *FieldVisibility) resolve(c *gin.Context) (models.FieldView, string) {
+	if v.checker == nil {
+		return models.ViewFull, "rbac_disabled"
+	}
+	if gosharedmw.IsPlatformOwner(c) {
+		return models.ViewFull, "platform_owner"
+	}
+	if v.checker.HasPermission(c, models.PermissionAuditViewFull) {
+		return models.ViewFull, models.PermissionAuditViewFull
+	}
+	if v.checker.HasPermission(c, models.PermissionAuditViewRedacted) {
+		return models.ViewRedacted, models.PermissionAuditViewRedacted
+	}
+	return models.ViewSummary, "no_audit_permission"
+}
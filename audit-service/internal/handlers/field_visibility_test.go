@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Tesseract-Nexus/go-shared/rbac"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"audit-service/internal/models"
+)
+
+// fakeStaffService serves effective permissions keyed by staff ID
+func fakeStaffService(t *testing.T, staff map[uuid.UUID]rbac.EffectivePermissions) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimSuffix(r.URL.Path, "/effective-permissions"), "/")
+		perms, ok := staff[uuid.MustParse(parts[len(parts)-1])]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": perms})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func permissions(names ...string) []rbac.Permission {
+	perms := make([]rbac.Permission, len(names))
+	for i, name := range names {
+		perms[i] = rbac.Permission{ID: uuid.New(), Name: name}
+	}
+	return perms
+}
+
+func TestFieldVisibilityResolvesFromCatalogPermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	owner, admin, manager, viewer := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	server := fakeStaffService(t, map[uuid.UUID]rbac.EffectivePermissions{
+		owner:   {Role: "store_owner", Priority: rbac.PriorityStoreOwner},
+		admin:   {Role: "store_admin", Priority: rbac.PriorityStoreAdmin, Permissions: permissions(rbac.PermissionAuditRead, rbac.PermissionAuditExport)},
+		manager: {Role: "store_manager", Priority: rbac.PriorityStoreManager, Permissions: permissions(rbac.PermissionAuditRead)},
+		viewer:  {Role: "viewer", Priority: rbac.PriorityViewer, Permissions: permissions(rbac.PermissionOrdersRead)},
+	})
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	visibility := NewFieldVisibility(rbac.NewMiddlewareWithURL(server.URL, nil), logger)
+
+	tests := []struct {
+		name    string
+		staffID uuid.UUID
+		want    models.FieldView
+	}{
+		{"owner", owner, models.ViewFull},
+		{"admin with audit export", admin, models.ViewFull},
+		{"manager with audit read", manager, models.ViewRedacted},
+		{"no audit permission", viewer, models.ViewSummary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/audit-logs", nil)
+			c.Set("tenant_id", "tenant-1")
+			c.Set("staff_id", tt.staffID.String())
+
+			if got := visibility.Resolve(c); got != tt.want {
+				t.Fatalf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"audit-service/internal/models"
+	"audit-service/internal/repository"
+	"audit-service/internal/services"
+)
+
+// ListWebhookSubscriptions lists the tenant's webhook subscriptions
+// GET /api/v1/audit-logs/webhooks
+func (h *AuditHandlers) ListWebhookSubscriptions(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	subscriptions, err := h.service.ListWebhookSubscriptions(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list webhook subscriptions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"subscriptions": subscriptions,
+		"count":         len(subscriptions),
+	})
+}
+
+// CreateWebhookSubscription subscribes an HTTPS endpoint to the tenant's audit events.
+// The signing secret is only returned in this response.
+// POST /api/v1/audit-logs/webhooks
+func (h *AuditHandlers) CreateWebhookSubscription(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	var request struct {
+		Name        string               `json:"name" binding:"required"`
+		URL         string               `json:"url" binding:"required"`
+		EventTypes  []string             `json:"eventTypes"`
+		MinSeverity models.AuditSeverity `json:"minSeverity"`
+		View        models.FieldView     `json:"view"`
+		Enabled     *bool                `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+			"hint":    "eventTypes filter on RESOURCE.ACTION, e.g. [\"PRODUCT.DELETE\", \"USER.*\"]; omit them to receive every event",
+		})
+		return
+	}
+
+	subscription := &models.WebhookSubscription{
+		Name:        request.Name,
+		URL:         request.URL,
+		EventTypes:  datatypes.NewJSONType(request.EventTypes),
+		MinSeverity: request.MinSeverity,
+		View:        models.FieldView(strings.ToLower(string(request.View))),
+		Enabled:     request.Enabled == nil || *request.Enabled,
+	}
+	secret, err := h.service.CreateWebhookSubscription(c.Request.Context(), tenantID, subscription)
+	if err != nil {
+		h.respondWebhookError(c, err, "Failed to create webhook subscription")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Webhook subscription created successfully",
+		"subscription": subscription,
+		"secret":       secret,
+	})
+}
+
+// UpdateWebhookSubscription changes the settings of a webhook subscription
+// PUT /api/v1/audit-logs/webhooks/:id
+func (h *AuditHandlers) UpdateWebhookSubscription(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook subscription ID"})
+		return
+	}
+
+	var request struct {
+		Name        *string               `json:"name"`
+		URL         *string               `json:"url"`
+		EventTypes  *[]string             `json:"eventTypes"`
+		MinSeverity *models.AuditSeverity `json:"minSeverity"`
+		View        *models.FieldView     `json:"view"`
+		Enabled     *bool                 `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.service.UpdateWebhookSubscription(c.Request.Context(), tenantID, id, func(s *models.WebhookSubscription) {
+		if request.Name != nil {
+			s.Name = *request.Name
+		}
+		if request.URL != nil {
+			s.URL = *request.URL
+		}
+		if request.EventTypes != nil {
+			s.EventTypes = datatypes.NewJSONType(*request.EventTypes)
+		}
+		if request.MinSeverity != nil {
+			s.MinSeverity = *request.MinSeverity
+		}
+		if request.View != nil {
+			s.View = models.FieldView(strings.ToLower(string(*request.View)))
+		}
+		if request.Enabled != nil {
+			s.Enabled = *request.Enabled
+		}
+	})
+	if err != nil {
+		h.respondWebhookError(c, err, "Failed to update webhook subscription")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Webhook subscription updated successfully",
+		"subscription": subscription,
+	})
+}
+
+// DeleteWebhookSubscription removes a webhook subscription; its delivery log is kept
+// DELETE /api/v1/audit-logs/webhooks/:id
+func (h *AuditHandlers) DeleteWebhookSubscription(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook subscription ID"})
+		return
+	}
+
+	if err := h.service.DeleteWebhookSubscription(c.Request.Context(), tenantID, id); err != nil {
+		h.respondWebhookError(c, err, "Failed to delete webhook subscription")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook subscription deleted successfully"})
+}
+
+// RotateWebhookSecret replaces the signing secret of a webhook subscription
+// POST /api/v1/audit-logs/webhooks/:id/rotate-secret
+func (h *AuditHandlers) RotateWebhookSecret(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook subscription ID"})
+		return
+	}
+
+	secret, err := h.service.RotateWebhookSecret(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondWebhookError(c, err, "Failed to rotate webhook secret")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook secret rotated successfully",
+		"secret":  secret,
+	})
+}
+
+// ListWebhookDeliveries returns the tenant's webhook delivery log, newest first
+// GET /api/v1/audit-logs/webhooks/deliveries?subscription_id=&status=&limit=&offset=
+func (h *AuditHandlers) ListWebhookDeliveries(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := models.WebhookDeliveryFilter{
+		Status: models.WebhookDeliveryStatus(strings.ToUpper(c.Query("status"))),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if raw := c.Query("subscription_id"); raw != "" {
+		subscriptionID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook subscription ID"})
+			return
+		}
+		filter.SubscriptionID = &subscriptionID
+	}
+
+	deliveries, total, err := h.service.ListWebhookDeliveries(c.Request.Context(), tenantID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list webhook deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+// RedeliverWebhook sends a delivered or dead-lettered delivery again and returns the outcome
+// POST /api/v1/audit-logs/webhooks/deliveries/:id/redeliver
+func (h *AuditHandlers) RedeliverWebhook(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID is required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook delivery ID"})
+		return
+	}
+
+	delivery, err := h.service.RedeliverWebhook(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondWebhookError(c, err, "Failed to redeliver webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"delivery": delivery})
+}
+
+// respondWebhookError maps webhook service errors to HTTP responses
+func (h *AuditHandlers) respondWebhookError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhookSubscription):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_WEBHOOK_SUBSCRIPTION",
+		})
+	case errors.Is(err, repository.ErrWebhookSubscriptionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook subscription not found"})
+	case errors.Is(err, repository.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook delivery not found"})
+	case errors.Is(err, services.ErrWebhookDeliveryPending):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Webhook delivery is still being retried",
+			"code":  "DELIVERY_PENDING",
+		})
+	case errors.Is(err, services.ErrWebhooksDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Webhooks are disabled",
+			"code":  "WEBHOOKS_DISABLED",
+		})
+	default:
+		h.logger.WithError(err).WithField("tenant_id", c.GetString("tenant_id")).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -124,6 +124,8 @@ type AuditLog struct {
 	Path         string `json:"path" gorm:"type:varchar(500)"` // Request path
 	Query        string `json:"query" gorm:"type:text"` // Query parameters
 	IPAddress    string `json:"ipAddress" gorm:"type:varchar(45);index"` // IPv4 or IPv6
+	GeoCountry   string `json:"geoCountry,omitempty" gorm:"type:varchar(2);index"` // ISO country resolved from IPAddress at ingest
+	GeoCity      string `json:"geoCity,omitempty" gorm:"type:varchar(100)"`
 	UserAgent    string `json:"userAgent" gorm:"type:text"`
 	RequestID    string `json:"requestId" gorm:"type:varchar(100);index"` // Correlation ID
 
@@ -145,14 +147,20 @@ type AuditLog struct {
 	ServiceName string `json:"serviceName" gorm:"type:varchar(100);index"` // Which service created this log
 	ServiceVersion string `json:"serviceVersion" gorm:"type:varchar(50)"`
 
+	// Sampling: number of events this entry stands for (1 unless a sampling policy skipped some).
+	// Summing it instead of counting rows reconstructs the ingested volume.
+	SampleWeight int64 `json:"sampleWeight" gorm:"not null;default:1"`
+
 	// Timestamps
 	Timestamp time.Time `json:"timestamp" gorm:"index;not null"` // When the action occurred
 	CreatedAt time.Time `json:"createdAt"`
 }
 
 // AuditLogSummary represents aggregated statistics
+// Counts include events dropped by sampling policies; StoredLogs is the number of entries actually stored.
 type AuditLogSummary struct {
 	TotalLogs      int64                  `json:"totalLogs"`
+	StoredLogs     int64                  `json:"storedLogs"`
 	ByAction       map[string]int64       `json:"byAction"`
 	ByResource     map[string]int64       `json:"byResource"`
 	ByStatus       map[string]int64       `json:"byStatus"`
@@ -183,6 +191,8 @@ type AuditSummary = AuditLogSummary
 // IPHistoryEntry represents an IP address history entry for a user
 type IPHistoryEntry struct {
 	IPAddress string    `json:"ipAddress"`
+	Country   string    `json:"country,omitempty"`
+	City      string    `json:"city,omitempty"`
 	Count     int64     `json:"count"`
 	FirstSeen time.Time `json:"firstSeen"`
 	LastSeen  time.Time `json:"lastSeen"`
@@ -301,6 +311,8 @@ type RetentionSettings struct {
 	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	TenantID       string    `json:"tenantId" gorm:"type:varchar(255);uniqueIndex;not null"`
 	RetentionDays  int       `json:"retentionDays" gorm:"not null;default:180"`  // 3-12 months (90-365 days)
+	Plan           string    `json:"plan" gorm:"type:varchar(50)"`      // Tenant subscription plan, synced from tenant events
+	PlanMaxDays    int       `json:"planMaxDays" gorm:"default:0"`     // Retention cap for the plan; 0 = no plan cap
 	LastCleanupAt  *time.Time `json:"lastCleanupAt"`
 	LogsDeleted    int64     `json:"logsDeleted"`  // Total logs deleted in last cleanup
 	CreatedAt      time.Time `json:"createdAt"`
@@ -312,16 +324,32 @@ func (RetentionSettings) TableName() string {
 	return "audit_retention_settings"
 }
 
+// EffectiveDays returns the retention enforced by cleanup: the tenant's setting
+// (or defaultDays when unset), capped by the plan's maximum
+func (s *RetentionSettings) EffectiveDays(defaultDays int) int {
+	days := s.RetentionDays
+	if days <= 0 {
+		days = defaultDays
+	}
+	if s.PlanMaxDays > 0 && days > s.PlanMaxDays {
+		days = s.PlanMaxDays
+	}
+	return days
+}
+
 // RetentionOption represents a selectable retention period option
 type RetentionOption struct {
-	Months int    `json:"months"`
-	Days   int    `json:"days"`
-	Label  string `json:"label"`
+	Months       int    `json:"months"`
+	Days         int    `json:"days"`
+	Label        string `json:"label"`
+	Available    bool   `json:"available"`              // Allowed on the tenant's current plan
+	RequiredPlan string `json:"requiredPlan,omitempty"` // Lowest plan offering this option when unavailable
 }
 
-// GetRetentionOptions returns available retention period options (3-12 months)
+// GetRetentionOptions returns available retention period options (1-12 months)
 func GetRetentionOptions() []RetentionOption {
 	return []RetentionOption{
+		{Months: 1, Days: 30, Label: "1 month"},
 		{Months: 3, Days: 90, Label: "3 months"},
 		{Months: 4, Days: 120, Label: "4 months"},
 		{Months: 5, Days: 150, Label: "5 months"},
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// ErasureSubject identifies a data subject whose identifiers must be pseudonymized in audit logs
+type ErasureSubject struct {
+	UserID     uuid.UUID // Matches AuditLog.UserID
+	Email      string    // Matches AuditLog.UserEmail (case-insensitive)
+	CustomerID string    // Matches AuditLog.ResourceID on CUSTOMER resources
+}
+
+// IsEmpty reports whether the subject has no identifiers to match on
+func (s ErasureSubject) IsEmpty() bool {
+	return s.UserID == uuid.Nil && s.Email == "" && s.CustomerID == ""
+}
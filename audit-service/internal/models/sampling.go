@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SamplingPolicy stores only a share of a tenant's high-volume, low-value events,
+// e.g. 1% of READ events on PRODUCT. Empty Action or Resource match any value.
+type SamplingPolicy struct {
+	ID       uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID string        `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_sampling_policy_scope"`
+	Action   AuditAction   `json:"action" gorm:"type:varchar(50);not null;default:'';uniqueIndex:idx_sampling_policy_scope"`
+	Resource AuditResource `json:"resource" gorm:"type:varchar(50);not null;default:'';uniqueIndex:idx_sampling_policy_scope"`
+
+	// SampleRate is the share of matching events stored, in (0, 1]
+	SampleRate float64 `json:"sampleRate" gorm:"not null"`
+	// MaxSeverity is the highest severity sampled; more severe events are always stored
+	MaxSeverity AuditSeverity `json:"maxSeverity" gorm:"type:varchar(20);not null;default:'MEDIUM'"`
+	Description string        `json:"description" gorm:"type:text"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName specifies the table name for sampling policies
+func (SamplingPolicy) TableName() string {
+	return "audit_sampling_policies"
+}
+
+// Matches reports whether the policy applies to the log.
+// Failed events are never sampled, nor are events above MaxSeverity.
+func (p *SamplingPolicy) Matches(log *AuditLog) bool {
+	if p.Action != "" && p.Action != log.Action {
+		return false
+	}
+	if p.Resource != "" && p.Resource != log.Resource {
+		return false
+	}
+	if log.IsFailure() {
+		return false
+	}
+	return SeverityRank(log.Severity) <= SeverityRank(p.MaxSeverity)
+}
+
+// Specificity ranks overlapping policies: action and resource beat either alone
+func (p *SamplingPolicy) Specificity() int {
+	score := 0
+	if p.Action != "" {
+		score += 2
+	}
+	if p.Resource != "" {
+		score++
+	}
+	return score
+}
+
+// Interval returns N for storing one in every N matching events
+func (p *SamplingPolicy) Interval() int64 {
+	if p.SampleRate <= 0 || p.SampleRate >= 1 {
+		return 1
+	}
+	return int64(1/p.SampleRate + 0.5)
+}
+
+// SeverityRank orders severities from LOW (1) to CRITICAL (4); unknown severities rank as MEDIUM
+func SeverityRank(severity AuditSeverity) int {
+	switch severity {
+	case SeverityLow:
+		return 1
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// IsValidSeverity reports whether severity is one of the known severities
+func IsValidSeverity(severity AuditSeverity) bool {
+	switch severity {
+	case SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// AuditTimeseries holds bucketed audit log counts for charts
+// Buckets are aligned to the interval (UTC) and every series has one count per bucket.
+type AuditTimeseries struct {
+	Interval  string                  `json:"interval"`
+	GroupBy   string                  `json:"groupBy,omitempty"`
+	TimeRange TimeRange               `json:"timeRange"`
+	Buckets   []time.Time             `json:"buckets"`
+	Totals    []int64                 `json:"totals"` // All groups per bucket
+	Total     int64                   `json:"total"`
+	Series    []AuditTimeseriesSeries `json:"series"`
+}
+
+// AuditTimeseriesSeries is the count of one group per bucket
+type AuditTimeseriesSeries struct {
+	Key        string  `json:"key"`
+	Counts     []int64 `json:"counts"`
+	Cumulative []int64 `json:"cumulative"` // Running total up to and including each bucket
+	Total      int64   `json:"total"`
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// FieldView controls which audit log fields are returned to a caller
+type FieldView string
+
+const (
+	// ViewFull returns every field, including IPs and payload bodies
+	ViewFull FieldView = "full"
+	// ViewRedacted masks IPs and contact details and drops payload bodies
+	ViewRedacted FieldView = "redacted"
+	// ViewSummary returns only who/what/when fields
+	ViewSummary FieldView = "summary"
+)
+
+// Audit field visibility permissions (checked against staff-service RBAC)
+// Both are go-shared rbac catalog permissions; store owners hold every permission
+const (
+	// PermissionAuditViewFull grants the full view of audit logs; callers allowed to
+	// export raw logs already see every field
+	PermissionAuditViewFull = "audit:export"
+	// PermissionAuditViewRedacted grants the redacted view of audit logs
+	PermissionAuditViewRedacted = "audit:read"
+)
+
+// RedactedPlaceholder replaces string values that are hidden from the caller
+const RedactedPlaceholder = "[REDACTED]"
+
+// ApplyView strips fields from the log that the given view is not allowed to see
+func (a *AuditLog) ApplyView(view FieldView) {
+	switch view {
+	case ViewFull:
+		return
+	case ViewRedacted:
+		a.IPAddress = MaskIP(a.IPAddress)
+		a.GeoCity = "" // Country alone is coarse enough to show next to a masked IP
+		a.UserEmail = MaskEmail(a.UserEmail)
+		a.Query = redactIfSet(a.Query)
+		a.OldValue = nil
+		a.NewValue = nil
+		a.Changes = nil
+		a.Metadata = nil
+	default:
+		// Summary (and any unknown view) keeps only identifying fields
+		a.IPAddress = ""
+		a.GeoCountry = ""
+		a.GeoCity = ""
+		a.UserEmail = ""
+		a.UserAgent = ""
+		a.Query = ""
+		a.Path = ""
+		a.OldValue = nil
+		a.NewValue = nil
+		a.Changes = nil
+		a.Metadata = nil
+		a.ErrorMessage = ""
+		a.Description = ""
+	}
+}
+
+// ApplyViewToLogs applies the view to every log in the slice
+func ApplyViewToLogs(logs []AuditLog, view FieldView) {
+	if view == ViewFull {
+		return
+	}
+	for i := range logs {
+		logs[i].ApplyView(view)
+	}
+}
+
+// ApplyView strips fields from the summary that the given view is not allowed to see
+func (s *AuditLogSummary) ApplyView(view FieldView) {
+	if view == ViewFull {
+		return
+	}
+	for i := range s.TopUsers {
+		if view == ViewRedacted {
+			s.TopUsers[i].UserEmail = MaskEmail(s.TopUsers[i].UserEmail)
+		} else {
+			s.TopUsers[i].UserEmail = ""
+		}
+	}
+	ApplyViewToLogs(s.RecentFailures, view)
+}
+
+// ApplyViewToIPHistory masks IP history entries for non-full views
+func ApplyViewToIPHistory(entries []IPHistoryEntry, view FieldView) {
+	if view == ViewFull {
+		return
+	}
+	for i := range entries {
+		entries[i].City = ""
+		if view == ViewRedacted {
+			entries[i].IPAddress = MaskIP(entries[i].IPAddress)
+		} else {
+			entries[i].IPAddress = RedactedPlaceholder
+			entries[i].Country = ""
+		}
+	}
+}
+
+// MaskIP hides the host portion of an IP address
+// IPv4 keeps the first three octets (203.0.113.x), IPv6 keeps the first three groups
+func MaskIP(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return RedactedPlaceholder
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.x", v4[0], v4[1], v4[2])
+	}
+	groups := strings.Split(parsed.String(), ":")
+	if len(groups) > 3 {
+		groups = groups[:3]
+	}
+	return strings.Join(groups, ":") + ":x"
+}
+
+// MaskEmail keeps the first character of the local part and the domain
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return redactIfSet(email)
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+func redactIfSet(value string) string {
+	if value == "" {
+		return ""
+	}
+	return RedactedPlaceholder
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// WebhookDeliveryStatus is the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending is waiting for its first attempt or a retry
+	WebhookDeliveryPending WebhookDeliveryStatus = "PENDING"
+	// WebhookDeliveryDelivered was acknowledged by the endpoint with a 2xx response
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	// WebhookDeliveryDeadLetter failed every attempt and is kept for manual redelivery
+	WebhookDeliveryDeadLetter WebhookDeliveryStatus = "DEAD_LETTER"
+)
+
+// WebhookSubscription forwards a tenant's audit events to an HTTPS endpoint in real time.
+// EventTypes filter on "RESOURCE.ACTION", e.g. "PRODUCT.DELETE"; either side may be "*",
+// and an empty list matches every event.
+type WebhookSubscription struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID string    `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	Name     string    `json:"name" gorm:"type:varchar(255);not null"`
+	URL      string    `json:"url" gorm:"type:varchar(2048);not null"`
+
+	// Secret keys the HMAC signature of every delivery; only returned when created or rotated
+	Secret string `json:"-" gorm:"type:varchar(128);not null"`
+
+	EventTypes datatypes.JSONType[[]string] `json:"eventTypes" gorm:"type:jsonb"`
+	// MinSeverity is the lowest severity forwarded
+	MinSeverity AuditSeverity `json:"minSeverity" gorm:"type:varchar(20);not null;default:'LOW'"`
+	// View controls which fields of the log are sent, as for API callers
+	View    FieldView `json:"view" gorm:"type:varchar(20);not null;default:'redacted'"`
+	Enabled bool      `json:"enabled" gorm:"not null;default:true"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName specifies the table name for webhook subscriptions
+func (WebhookSubscription) TableName() string {
+	return "audit_webhook_subscriptions"
+}
+
+// Matches reports whether the subscription forwards the log
+func (s *WebhookSubscription) Matches(log *AuditLog) bool {
+	if !s.Enabled {
+		return false
+	}
+	if SeverityRank(log.Severity) < SeverityRank(s.MinSeverity) {
+		return false
+	}
+	eventTypes := s.EventTypes.Data()
+	if len(eventTypes) == 0 {
+		return true
+	}
+	for _, eventType := range eventTypes {
+		if MatchesEventType(eventType, log) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEventType returns the event type of a log, e.g. "PRODUCT.DELETE"
+func WebhookEventType(log *AuditLog) string {
+	return string(log.Resource) + "." + string(log.Action)
+}
+
+// MatchesEventType reports whether a "RESOURCE.ACTION" filter matches the log.
+// A filter without a dot matches the resource alone.
+func MatchesEventType(filter string, log *AuditLog) bool {
+	resource, action, ok := strings.Cut(filter, ".")
+	if !ok {
+		action = "*"
+	}
+	if resource != "*" && !strings.EqualFold(resource, string(log.Resource)) {
+		return false
+	}
+	return action == "*" || strings.EqualFold(action, string(log.Action))
+}
+
+// WebhookDelivery logs the delivery of one audit event to one subscription.
+// The payload is stored so failed deliveries can be retried and redelivered as sent.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string                `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_webhook_delivery_due,priority:2"`
+	SubscriptionID uuid.UUID             `json:"subscriptionId" gorm:"type:uuid;not null;index"`
+	AuditLogID     uuid.UUID             `json:"auditLogId" gorm:"type:uuid;index"`
+	EventType      string                `json:"eventType" gorm:"type:varchar(101);not null"`
+	Status         WebhookDeliveryStatus `json:"status" gorm:"type:varchar(20);not null;index:idx_webhook_delivery_due,priority:1"`
+	Attempts       int                   `json:"attempts" gorm:"not null;default:0"`
+
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty" gorm:"index:idx_webhook_delivery_due,priority:3"`
+	LastAttemptAt  *time.Time `json:"lastAttemptAt,omitempty"`
+	ResponseStatus int        `json:"responseStatus,omitempty"`
+	LastError      string     `json:"lastError,omitempty" gorm:"type:text"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+
+	Payload datatypes.JSON `json:"payload" gorm:"type:jsonb"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName specifies the table name for webhook deliveries
+func (WebhookDelivery) TableName() string {
+	return "audit_webhook_deliveries"
+}
+
+// WebhookDeliveryFilter narrows the delivery log
+type WebhookDeliveryFilter struct {
+	SubscriptionID *uuid.UUID
+	Status         WebhookDeliveryStatus
+	Limit          int
+	Offset         int
+}
@@ -24,6 +24,9 @@ type AuditRepositoryInterface interface {
 	// GetSummary retrieves summary statistics
 	GetSummary(ctx context.Context, tenantID string, fromDate, toDate time.Time) (*models.AuditSummary, error)
 
+	// GetTimeseries retrieves bucketed counts for charts
+	GetTimeseries(ctx context.Context, tenantID string, params TimeseriesParams) (*models.AuditTimeseries, error)
+
 	// GetCriticalEvents retrieves critical events from the last N hours
 	GetCriticalEvents(ctx context.Context, tenantID string, hours int) ([]models.AuditLog, error)
 
@@ -37,7 +40,7 @@ type AuditRepositoryInterface interface {
 	GetFailedAuthAttempts(ctx context.Context, tenantID string, hours int) ([]models.AuditLog, error)
 
 	// GetSuspiciousActivity retrieves potentially suspicious activities
-	GetSuspiciousActivity(ctx context.Context, tenantID string) ([]models.AuditLog, error)
+	GetSuspiciousActivity(ctx context.Context, tenantID string, params SuspiciousActivityParams) ([]models.AuditLog, error)
 
 	// GetUserIPHistory retrieves IP addresses used by a user
 	GetUserIPHistory(ctx context.Context, tenantID, userID string) ([]models.IPHistoryEntry, error)
@@ -56,6 +59,46 @@ type AuditRepositoryInterface interface {
 
 	// SetRetentionSettings saves retention settings for a tenant
 	SetRetentionSettings(ctx context.Context, tenantID string, settings *models.RetentionSettings) error
+
+	// PurgeTenant permanently deletes every audit log, webhook, sampling policy and retention
+	// setting of a deleted tenant
+	PurgeTenant(ctx context.Context, tenantID string) (int64, error)
+
+	// PseudonymizeSubject rewrites every log referencing the subject with transform
+	PseudonymizeSubject(ctx context.Context, tenantID string, subject models.ErasureSubject, transform func(*models.AuditLog)) (int64, error)
+
+	// ListSamplingPolicies retrieves the tenant's ingest sampling policies
+	ListSamplingPolicies(ctx context.Context, tenantID string) ([]models.SamplingPolicy, error)
+
+	// UpsertSamplingPolicy saves a sampling policy, replacing the one with the same action and resource
+	UpsertSamplingPolicy(ctx context.Context, tenantID string, policy *models.SamplingPolicy) error
+
+	// DeleteSamplingPolicy removes a sampling policy
+	DeleteSamplingPolicy(ctx context.Context, tenantID string, id uuid.UUID) error
+
+	// ListWebhookSubscriptions retrieves the tenant's webhook subscriptions
+	ListWebhookSubscriptions(ctx context.Context, tenantID string) ([]models.WebhookSubscription, error)
+
+	// GetWebhookSubscription retrieves a webhook subscription by ID
+	GetWebhookSubscription(ctx context.Context, tenantID string, id uuid.UUID) (*models.WebhookSubscription, error)
+
+	// SaveWebhookSubscription creates or updates a webhook subscription
+	SaveWebhookSubscription(ctx context.Context, tenantID string, subscription *models.WebhookSubscription) error
+
+	// DeleteWebhookSubscription removes a webhook subscription and dead-letters its pending deliveries
+	DeleteWebhookSubscription(ctx context.Context, tenantID string, id uuid.UUID) error
+
+	// SaveWebhookDelivery creates or updates a webhook delivery log entry
+	SaveWebhookDelivery(ctx context.Context, tenantID string, delivery *models.WebhookDelivery) error
+
+	// GetWebhookDelivery retrieves a webhook delivery by ID
+	GetWebhookDelivery(ctx context.Context, tenantID string, id uuid.UUID) (*models.WebhookDelivery, error)
+
+	// ListWebhookDeliveries retrieves the tenant's webhook delivery log
+	ListWebhookDeliveries(ctx context.Context, tenantID string, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, int64, error)
+
+	// ClaimDueWebhookDeliveries leases pending deliveries whose retry is due
+	ClaimDueWebhookDeliveries(ctx context.Context, tenantID string, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
 }
 
 // Ensure MultiTenantRepository implements the interface
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -240,8 +241,12 @@ func (r *MultiTenantRepository) GetSummary(ctx context.Context, tenantID string,
 	baseQuery := db.WithContext(ctx).Model(&models.AuditLog{}).
 		Where("tenant_id = ? AND timestamp >= ? AND timestamp <= ?", tenantID, fromDate, toDate)
 
-	// Total count
-	baseQuery.Count(&summary.TotalLogs)
+	// Counts add up sample weights, so events dropped by sampling policies are included
+	baseQuery.Count(&summary.StoredLogs)
+	db.WithContext(ctx).Model(&models.AuditLog{}).
+		Select("COALESCE(SUM(sample_weight), 0)").
+		Where("tenant_id = ? AND timestamp >= ? AND timestamp <= ?", tenantID, fromDate, toDate).
+		Scan(&summary.TotalLogs)
 
 	// Count by action
 	var actionCounts []struct {
@@ -249,7 +254,7 @@ func (r *MultiTenantRepository) GetSummary(ctx context.Context, tenantID string,
 		Count  int64
 	}
 	db.WithContext(ctx).Model(&models.AuditLog{}).
-		Select("action, COUNT(*) as count").
+		Select("action, SUM(sample_weight) as count").
 		Where("tenant_id = ? AND timestamp >= ? AND timestamp <= ?", tenantID, fromDate, toDate).
 		Group("action").
 		Find(&actionCounts)
@@ -263,7 +268,7 @@ func (r *MultiTenantRepository) GetSummary(ctx context.Context, tenantID string,
 		Count    int64
 	}
 	db.WithContext(ctx).Model(&models.AuditLog{}).
-		Select("resource, COUNT(*) as count").
+		Select("resource, SUM(sample_weight) as count").
 		Where("tenant_id = ? AND timestamp >= ? AND timestamp <= ?", tenantID, fromDate, toDate).
 		Group("resource").
 		Find(&resourceCounts)
@@ -277,7 +282,7 @@ func (r *MultiTenantRepository) GetSummary(ctx context.Context, tenantID string,
 		Count  int64
 	}
 	db.WithContext(ctx).Model(&models.AuditLog{}).
-		Select("status, COUNT(*) as count").
+		Select("status, SUM(sample_weight) as count").
 		Where("tenant_id = ? AND timestamp >= ? AND timestamp <= ?", tenantID, fromDate, toDate).
 		Group("status").
 		Find(&statusCounts)
@@ -291,7 +296,7 @@ func (r *MultiTenantRepository) GetSummary(ctx context.Context, tenantID string,
 		Count    int64
 	}
 	db.WithContext(ctx).Model(&models.AuditLog{}).
-		Select("severity, COUNT(*) as count").
+		Select("severity, SUM(sample_weight) as count").
 		Where("tenant_id = ? AND timestamp >= ? AND timestamp <= ?", tenantID, fromDate, toDate).
 		Group("severity").
 		Find(&severityCounts)
@@ -302,7 +307,7 @@ func (r *MultiTenantRepository) GetSummary(ctx context.Context, tenantID string,
 	// Top users
 	var topUsers []models.UserActivity
 	db.WithContext(ctx).Model(&models.AuditLog{}).
-		Select("user_id, username, COUNT(*) as activity_count, MAX(timestamp) as last_activity").
+		Select("user_id, username, SUM(sample_weight) as activity_count, MAX(timestamp) as last_activity").
 		Where("tenant_id = ? AND timestamp >= ? AND timestamp <= ?", tenantID, fromDate, toDate).
 		Group("user_id, username").
 		Order("activity_count DESC").
@@ -424,8 +429,15 @@ func (r *MultiTenantRepository) GetFailedAuthAttempts(ctx context.Context, tenan
 	return logs, nil
 }
 
+// SuspiciousActivityParams defines parameters for suspicious activity detection
+type SuspiciousActivityParams struct {
+	// OutsideCountries restricts results to events geolocated outside these
+	// ISO 3166-1 alpha-2 countries; logins then count as suspicious too
+	OutsideCountries []string
+}
+
 // GetSuspiciousActivity retrieves potentially suspicious activities
-func (r *MultiTenantRepository) GetSuspiciousActivity(ctx context.Context, tenantID string) ([]models.AuditLog, error) {
+func (r *MultiTenantRepository) GetSuspiciousActivity(ctx context.Context, tenantID string, params SuspiciousActivityParams) ([]models.AuditLog, error) {
 	db, err := r.dbManager.GetDB(ctx, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
@@ -433,13 +445,22 @@ func (r *MultiTenantRepository) GetSuspiciousActivity(ctx context.Context, tenan
 
 	since := time.Now().Add(-24 * time.Hour)
 
-	var logs []models.AuditLog
-	if err := db.WithContext(ctx).
-		Where(`tenant_id = ? AND timestamp >= ? AND (
-			(action IN ('DELETE', 'BULK_DELETE', 'MASS_UPDATE') AND severity = 'HIGH') OR
+	rules := `(action IN ('DELETE', 'BULK_DELETE', 'MASS_UPDATE') AND severity = 'HIGH') OR
 			(status = 'FAILURE' AND severity IN ('HIGH', 'CRITICAL')) OR
-			(action = 'EXPORT' AND resource IN ('CUSTOMERS', 'ORDERS', 'PAYMENTS'))
-		)`, tenantID, since).
+			(action = 'EXPORT' AND resource IN ('CUSTOMERS', 'ORDERS', 'PAYMENTS'))`
+
+	query := db.WithContext(ctx).Where("tenant_id = ? AND timestamp >= ?", tenantID, since)
+	if len(params.OutsideCountries) > 0 {
+		// Events without a resolved country are excluded rather than assumed foreign
+		query = query.
+			Where("("+rules+" OR action IN ?)", []models.AuditAction{models.ActionLogin, models.ActionLoginFailed}).
+			Where("geo_country <> '' AND geo_country NOT IN ?", params.OutsideCountries)
+	} else {
+		query = query.Where("(" + rules + ")")
+	}
+
+	var logs []models.AuditLog
+	if err := query.
 		Order("timestamp DESC").
 		Limit(100).
 		Find(&logs).Error; err != nil {
@@ -458,7 +479,7 @@ func (r *MultiTenantRepository) GetUserIPHistory(ctx context.Context, tenantID,
 
 	var entries []models.IPHistoryEntry
 	if err := db.WithContext(ctx).Model(&models.AuditLog{}).
-		Select("ip_address, COUNT(*) as count, MIN(timestamp) as first_seen, MAX(timestamp) as last_seen").
+		Select("ip_address, COUNT(*) as count, MIN(timestamp) as first_seen, MAX(timestamp) as last_seen, MAX(geo_country) as country, MAX(geo_city) as city").
 		Where("tenant_id = ? AND user_id = ?", tenantID, userID).
 		Group("ip_address").
 		Order("last_seen DESC").
@@ -559,6 +580,9 @@ func mapToSummary(m map[string]interface{}) *models.AuditSummary {
 	if v, ok := m["total_logs"].(float64); ok {
 		summary.TotalLogs = int64(v)
 	}
+	if v, ok := m["stored_logs"].(float64); ok {
+		summary.StoredLogs = int64(v)
+	}
 
 	// Convert maps
 	if v, ok := m["by_action"].(map[string]interface{}); ok {
@@ -575,6 +599,7 @@ func mapToSummary(m map[string]interface{}) *models.AuditSummary {
 func summaryToMap(s *models.AuditSummary) map[string]interface{} {
 	return map[string]interface{}{
 		"total_logs":      s.TotalLogs,
+		"stored_logs":     s.StoredLogs,
 		"by_action":       s.ByAction,
 		"by_resource":     s.ByResource,
 		"by_status":       s.ByStatus,
@@ -678,13 +703,7 @@ func (r *MultiTenantRepository) SetRetentionSettings(ctx context.Context, tenant
 		r.logger.WithError(err).Warn("Failed to auto-migrate retention settings table")
 	}
 
-	// Validate retention days (90-365)
-	if settings.RetentionDays < 90 {
-		settings.RetentionDays = 90
-	}
-	if settings.RetentionDays > 365 {
-		settings.RetentionDays = 365
-	}
+	// Retention days are validated against the tenant's plan by the service layer
 
 	settings.TenantID = tenantID
 	settings.UpdatedAt = time.Now()
@@ -692,9 +711,11 @@ func (r *MultiTenantRepository) SetRetentionSettings(ctx context.Context, tenant
 	// Upsert the settings
 	result := db.WithContext(ctx).
 		Where("tenant_id = ?", tenantID).
-		Assign(models.RetentionSettings{
-			RetentionDays: settings.RetentionDays,
-			UpdatedAt:     settings.UpdatedAt,
+		Assign(map[string]interface{}{
+			"retention_days": settings.RetentionDays,
+			"plan":           settings.Plan,
+			"plan_max_days":  settings.PlanMaxDays,
+			"updated_at":     settings.UpdatedAt,
 		}).
 		FirstOrCreate(settings)
 
@@ -705,7 +726,137 @@ func (r *MultiTenantRepository) SetRetentionSettings(ctx context.Context, tenant
 	r.logger.WithFields(logrus.Fields{
 		"tenant_id":      tenantID,
 		"retention_days": settings.RetentionDays,
+		"plan":           settings.Plan,
 	}).Info("Retention settings updated")
 
 	return nil
 }
+
+// PurgeTenant deletes a deleted tenant's audit logs in batches, then its webhook deliveries and
+// subscriptions, sampling policies and retention settings. Nothing is left behind on success, so
+// purging again removes nothing.
+func (r *MultiTenantRepository) PurgeTenant(ctx context.Context, tenantID string) (int64, error) {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	var totalDeleted int64
+	batchSize := 1000
+
+	for {
+		batch := db.Model(&models.AuditLog{}).Select("id").Where("tenant_id = ?", tenantID).Limit(batchSize)
+		result := db.WithContext(ctx).Where("id IN (?)", batch).Delete(&models.AuditLog{})
+		if result.Error != nil {
+			return totalDeleted, fmt.Errorf("failed to purge audit logs: %w", result.Error)
+		}
+		totalDeleted += result.RowsAffected
+		if result.RowsAffected < int64(batchSize) {
+			break
+		}
+	}
+
+	tenantScoped := []interface{}{&models.WebhookDelivery{}, &models.WebhookSubscription{}, &models.SamplingPolicy{}}
+	if db.Migrator().HasTable(&models.RetentionSettings{}) {
+		tenantScoped = append(tenantScoped, &models.RetentionSettings{})
+	}
+	for _, model := range tenantScoped {
+		result := db.WithContext(ctx).Where("tenant_id = ?", tenantID).Delete(model)
+		if result.Error != nil {
+			return totalDeleted, fmt.Errorf("failed to purge %T: %w", model, result.Error)
+		}
+		totalDeleted += result.RowsAffected
+	}
+
+	if r.cache != nil {
+		r.cache.InvalidateTenant(ctx, tenantID)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"tenant_id":     tenantID,
+		"items_deleted": totalDeleted,
+	}).Info("Purged deleted tenant's audit data")
+
+	return totalDeleted, nil
+}
+
+// PseudonymizeSubject rewrites logs referencing the subject in batches, paging by ID
+func (r *MultiTenantRepository) PseudonymizeSubject(ctx context.Context, tenantID string, subject models.ErasureSubject, transform func(*models.AuditLog)) (int64, error) {
+	if subject.IsEmpty() {
+		return 0, nil
+	}
+
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	var conditions []string
+	var args []interface{}
+	if subject.UserID != uuid.Nil {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, subject.UserID)
+	}
+	if subject.Email != "" {
+		conditions = append(conditions, "LOWER(user_email) = LOWER(?)")
+		args = append(args, subject.Email)
+	}
+	if subject.CustomerID != "" {
+		conditions = append(conditions, "(resource = ? AND resource_id = ?)")
+		args = append(args, models.ResourceCustomer, subject.CustomerID)
+	}
+	match := "(" + strings.Join(conditions, " OR ") + ")"
+
+	var total int64
+	var lastID uuid.UUID
+	batchSize := 500
+
+	for {
+		var logs []models.AuditLog
+		if err := db.WithContext(ctx).
+			Where("tenant_id = ? AND id > ?", tenantID, lastID).
+			Where(match, args...).
+			Order("id").
+			Limit(batchSize).
+			Find(&logs).Error; err != nil {
+			return total, fmt.Errorf("failed to load logs for pseudonymization: %w", err)
+		}
+		if len(logs) == 0 {
+			break
+		}
+
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for i := range logs {
+				transform(&logs[i])
+				if err := tx.Model(&logs[i]).
+					Select("user_id", "username", "user_email", "ip_address", "user_agent",
+						"resource_id", "resource_name", "path", "query", "description",
+						"old_value", "new_value", "changes", "metadata").
+					Updates(&logs[i]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return total, fmt.Errorf("failed to pseudonymize logs: %w", err)
+		}
+
+		total += int64(len(logs))
+		lastID = logs[len(logs)-1].ID
+		if len(logs) < batchSize {
+			break
+		}
+	}
+
+	if total > 0 && r.cache != nil {
+		r.cache.InvalidateTenant(ctx, tenantID)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"tenant_id":      tenantID,
+		"logs_rewritten": total,
+	}).Info("Pseudonymized audit logs for erased subject")
+
+	return total, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+
+	"audit-service/internal/models"
+)
+
+// ErrSamplingPolicyNotFound is returned when a sampling policy does not exist
+var ErrSamplingPolicyNotFound = errors.New("sampling policy not found")
+
+// ListSamplingPolicies returns the tenant's sampling policies
+func (r *MultiTenantRepository) ListSamplingPolicies(ctx context.Context, tenantID string) ([]models.SamplingPolicy, error) {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	var policies []models.SamplingPolicy
+	if err := db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("action, resource").
+		Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to list sampling policies: %w", err)
+	}
+	return policies, nil
+}
+
+// UpsertSamplingPolicy creates the policy or replaces the one with the same action and resource
+func (r *MultiTenantRepository) UpsertSamplingPolicy(ctx context.Context, tenantID string, policy *models.SamplingPolicy) error {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	policy.TenantID = tenantID
+	policy.UpdatedAt = time.Now()
+
+	err = db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "action"}, {Name: "resource"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sample_rate", "max_severity", "description", "updated_at"}),
+	}).Create(policy).Error
+	if err != nil {
+		return fmt.Errorf("failed to save sampling policy: %w", err)
+	}
+
+	// On conflict the generated ID is not the stored one; reload it
+	return db.WithContext(ctx).
+		Where("tenant_id = ? AND action = ? AND resource = ?", tenantID, policy.Action, policy.Resource).
+		First(policy).Error
+}
+
+// DeleteSamplingPolicy removes a sampling policy
+func (r *MultiTenantRepository) DeleteSamplingPolicy(ctx context.Context, tenantID string, id uuid.UUID) error {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	result := db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&models.SamplingPolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete sampling policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrSamplingPolicyNotFound
+	}
+	return nil
+}
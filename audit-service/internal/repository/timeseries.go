@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"audit-service/internal/models"
+)
+
+// timeseriesGroupColumns maps group_by values to audit_logs columns
+var timeseriesGroupColumns = map[string]string{
+	"action":   "action",
+	"resource": "resource",
+	"status":   "status",
+	"severity": "severity",
+	"service":  "service_name",
+}
+
+// TimeseriesGroupColumn reports whether a group_by value is supported
+func TimeseriesGroupColumn(groupBy string) (string, bool) {
+	column, ok := timeseriesGroupColumns[groupBy]
+	return column, ok
+}
+
+// TimeseriesParams defines a bucketed count query
+// From and To must be aligned to Interval; To is exclusive.
+type TimeseriesParams struct {
+	From          time.Time
+	To            time.Time
+	Interval      time.Duration
+	IntervalLabel string
+	GroupBy       string // Empty for a single "total" series
+}
+
+// timeseriesRow is one (bucket, group) count with its window aggregates
+type timeseriesRow struct {
+	BucketStart time.Time
+	GroupKey    string
+	Count       int64
+	Cumulative  int64
+}
+
+// GetTimeseries counts audit logs per interval bucket, optionally per group
+// Counts add up sample weights, so events dropped by sampling policies are included.
+func (r *MultiTenantRepository) GetTimeseries(ctx context.Context, tenantID string, params TimeseriesParams) (*models.AuditTimeseries, error) {
+	if r.cache != nil {
+		if cached, err := r.cache.GetTimeseries(ctx, tenantID, params.cacheKey()); err == nil {
+			return cached, nil
+		}
+	}
+
+	groupExpr := "'total'"
+	if params.GroupBy != "" {
+		column, ok := TimeseriesGroupColumn(params.GroupBy)
+		if !ok {
+			return nil, fmt.Errorf("unsupported group_by %q", params.GroupBy)
+		}
+		groupExpr = fmt.Sprintf("COALESCE(NULLIF(%s, ''), 'unknown')", column)
+	}
+
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	// Bucket in SQL and let window functions compute the running total per group,
+	// so only one row per (bucket, group) leaves the database
+	seconds := int64(params.Interval / time.Second)
+	query := fmt.Sprintf(`
+		WITH counts AS (
+			SELECT to_timestamp(floor(extract(epoch FROM audit_logs.timestamp) / ?) * ?) AS bucket_start,
+			       %s AS group_key,
+			       SUM(sample_weight) AS count
+			FROM audit_logs
+			WHERE tenant_id = ? AND timestamp >= ? AND timestamp < ?
+			GROUP BY 1, 2
+		)
+		SELECT bucket_start, group_key, count,
+		       SUM(count) OVER (PARTITION BY group_key ORDER BY bucket_start) AS cumulative
+		FROM counts
+		ORDER BY bucket_start, group_key`, groupExpr)
+
+	var rows []timeseriesRow
+	if err := db.WithContext(ctx).Raw(query, seconds, seconds, tenantID, params.From, params.To).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query audit timeseries: %w", err)
+	}
+
+	result := buildTimeseries(params, rows)
+
+	if r.cache != nil {
+		r.cache.SetTimeseries(ctx, tenantID, params.cacheKey(), result)
+	}
+	return result, nil
+}
+
+// buildTimeseries fills empty buckets with zeros so every series lines up with Buckets
+func buildTimeseries(params TimeseriesParams, rows []timeseriesRow) *models.AuditTimeseries {
+	result := &models.AuditTimeseries{
+		Interval:  params.IntervalLabel,
+		GroupBy:   params.GroupBy,
+		TimeRange: models.TimeRange{From: params.From, To: params.To},
+		Buckets:   []time.Time{},
+		Totals:    []int64{},
+		Series:    []models.AuditTimeseriesSeries{},
+	}
+
+	index := map[int64]int{}
+	for t := params.From; t.Before(params.To); t = t.Add(params.Interval) {
+		index[t.Unix()] = len(result.Buckets)
+		result.Buckets = append(result.Buckets, t.UTC())
+		result.Totals = append(result.Totals, 0)
+	}
+
+	series := map[string]*models.AuditTimeseriesSeries{}
+	for _, row := range rows {
+		i, ok := index[row.BucketStart.Unix()]
+		if !ok {
+			continue
+		}
+		s, ok := series[row.GroupKey]
+		if !ok {
+			s = &models.AuditTimeseriesSeries{
+				Key:        row.GroupKey,
+				Counts:     make([]int64, len(result.Buckets)),
+				Cumulative: make([]int64, len(result.Buckets)),
+			}
+			series[row.GroupKey] = s
+		}
+		s.Counts[i] = row.Count
+		s.Cumulative[i] = row.Cumulative
+		s.Total += row.Count
+		result.Totals[i] += row.Count
+		result.Total += row.Count
+	}
+
+	for _, s := range series {
+		// Carry the running total through buckets without events
+		for i := 1; i < len(s.Cumulative); i++ {
+			if s.Cumulative[i] == 0 {
+				s.Cumulative[i] = s.Cumulative[i-1]
+			}
+		}
+		result.Series = append(result.Series, *s)
+	}
+	sort.Slice(result.Series, func(i, j int) bool {
+		if result.Series[i].Total != result.Series[j].Total {
+			return result.Series[i].Total > result.Series[j].Total
+		}
+		return result.Series[i].Key < result.Series[j].Key
+	})
+
+	return result
+}
+
+func (p TimeseriesParams) cacheKey() string {
+	return fmt.Sprintf("%d:%d:%s:%s", p.From.Unix(), p.To.Unix(), p.IntervalLabel, p.GroupBy)
+}
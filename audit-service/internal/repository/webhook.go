@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"audit-service/internal/models"
+)
+
+var (
+	// ErrWebhookSubscriptionNotFound is returned when a webhook subscription does not exist
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	// ErrWebhookDeliveryNotFound is returned when a webhook delivery does not exist
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// ListWebhookSubscriptions returns the tenant's webhook subscriptions
+func (r *MultiTenantRepository) ListWebhookSubscriptions(ctx context.Context, tenantID string) ([]models.WebhookSubscription, error) {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	var subscriptions []models.WebhookSubscription
+	if err := db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at").
+		Find(&subscriptions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// GetWebhookSubscription returns a webhook subscription
+func (r *MultiTenantRepository) GetWebhookSubscription(ctx context.Context, tenantID string, id uuid.UUID) (*models.WebhookSubscription, error) {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	var subscription models.WebhookSubscription
+	err = db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).First(&subscription).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+// SaveWebhookSubscription creates or updates a webhook subscription
+func (r *MultiTenantRepository) SaveWebhookSubscription(ctx context.Context, tenantID string, subscription *models.WebhookSubscription) error {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	subscription.TenantID = tenantID
+	if subscription.ID == uuid.Nil {
+		err = db.WithContext(ctx).Create(subscription).Error
+	} else {
+		err = db.WithContext(ctx).Save(subscription).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteWebhookSubscription removes a webhook subscription; its delivery log is kept
+func (r *MultiTenantRepository) DeleteWebhookSubscription(ctx context.Context, tenantID string, id uuid.UUID) error {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	result := db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&models.WebhookSubscription{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookSubscriptionNotFound
+	}
+
+	// Deliveries still waiting for a retry have nowhere to go
+	if err := db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("tenant_id = ? AND subscription_id = ? AND status = ?", tenantID, id, models.WebhookDeliveryPending).
+		Updates(map[string]interface{}{
+			"status":          models.WebhookDeliveryDeadLetter,
+			"next_attempt_at": nil,
+			"last_error":      "subscription deleted",
+		}).Error; err != nil {
+		return fmt.Errorf("failed to dead-letter pending deliveries: %w", err)
+	}
+	return nil
+}
+
+// SaveWebhookDelivery creates or updates a webhook delivery
+func (r *MultiTenantRepository) SaveWebhookDelivery(ctx context.Context, tenantID string, delivery *models.WebhookDelivery) error {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	delivery.TenantID = tenantID
+	if delivery.ID == uuid.Nil {
+		err = db.WithContext(ctx).Create(delivery).Error
+	} else {
+		err = db.WithContext(ctx).Save(delivery).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDelivery returns a webhook delivery
+func (r *MultiTenantRepository) GetWebhookDelivery(ctx context.Context, tenantID string, id uuid.UUID) (*models.WebhookDelivery, error) {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	var delivery models.WebhookDelivery
+	err = db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).First(&delivery).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// ListWebhookDeliveries returns the tenant's delivery log, newest first, and the total matching the filter
+func (r *MultiTenantRepository) ListWebhookDeliveries(ctx context.Context, tenantID string, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, int64, error) {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	query := db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("tenant_id = ?", tenantID)
+	if filter.SubscriptionID != nil {
+		query = query.Where("subscription_id = ?", *filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("created_at DESC").Limit(limit).Offset(filter.Offset).Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// ClaimDueWebhookDeliveries returns up to limit pending deliveries whose retry is due and
+// pushes their next attempt back by lease, so other instances don't retry them meanwhile
+func (r *MultiTenantRepository) ClaimDueWebhookDeliveries(ctx context.Context, tenantID string, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	db, err := r.dbManager.GetDB(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database for tenant %s: %w", tenantID, err)
+	}
+
+	var deliveries []models.WebhookDelivery
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("tenant_id = ? AND status = ? AND next_attempt_at <= ?", tenantID, models.WebhookDeliveryPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
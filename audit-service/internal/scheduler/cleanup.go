@@ -130,10 +130,8 @@ func (s *CleanupScheduler) runCleanup() {
 			continue
 		}
 
-		retentionDays := settings.RetentionDays
-		if retentionDays <= 0 {
-			retentionDays = s.config.DefaultDays
-		}
+		// Plan tiers cap the tenant's chosen retention
+		retentionDays := settings.EffectiveDays(s.config.DefaultDays)
 
 		// Cleanup old logs
 		deleted, err := s.repo.CleanupOldLogs(ctx, tenantID, retentionDays)
@@ -146,6 +144,7 @@ func (s *CleanupScheduler) runCleanup() {
 		if deleted > 0 {
 			s.logger.WithFields(logrus.Fields{
 				"tenant_id":      tenantID,
+				"plan":           settings.Plan,
 				"retention_days": retentionDays,
 				"logs_deleted":   deleted,
 			}).Info("Cleaned up old audit logs")
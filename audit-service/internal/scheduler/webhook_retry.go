@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+
+	"audit-service/internal/services"
+	"audit-service/internal/tenant"
+)
+
+// WebhookRetryScheduler periodically retries failed webhook deliveries of every tenant
+type WebhookRetryScheduler struct {
+	dispatcher     *services.WebhookDispatcher
+	tenantRegistry *tenant.Registry
+	logger         *logrus.Logger
+	cron           *cron.Cron
+	mu             sync.Mutex
+	running        bool
+	lastRun        time.Time
+	lastRetried    int
+}
+
+// NewWebhookRetryScheduler creates a new webhook retry scheduler
+func NewWebhookRetryScheduler(
+	dispatcher *services.WebhookDispatcher,
+	tenantRegistry *tenant.Registry,
+	logger *logrus.Logger,
+) *WebhookRetryScheduler {
+	return &WebhookRetryScheduler{
+		dispatcher:     dispatcher,
+		tenantRegistry: tenantRegistry,
+		logger:         logger,
+	}
+}
+
+// Start starts the webhook retry scheduler
+func (s *WebhookRetryScheduler) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return nil
+	}
+
+	// SkipIfStillRunning keeps a slow run from overlapping the next one
+	s.cron = cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+
+	schedule := fmt.Sprintf("@every %s", s.dispatcher.RetryInterval())
+	if _, err := s.cron.AddFunc(schedule, s.runRetries); err != nil {
+		s.logger.WithError(err).Error("Failed to schedule webhook retry job")
+		return err
+	}
+
+	s.cron.Start()
+	s.running = true
+
+	s.logger.WithField("schedule", schedule).Info("Webhook retry scheduler started")
+	return nil
+}
+
+// Stop stops the webhook retry scheduler
+func (s *WebhookRetryScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running || s.cron == nil {
+		return
+	}
+
+	ctx := s.cron.Stop()
+	<-ctx.Done()
+	s.running = false
+	s.logger.Info("Webhook retry scheduler stopped")
+}
+
+// runRetries retries the due webhook deliveries of all tenants
+func (s *WebhookRetryScheduler) runRetries() {
+	ctx := context.Background()
+
+	tenants, err := s.tenantRegistry.GetAllTenants(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get tenant list for webhook retries")
+		return
+	}
+
+	var retried int
+	for _, tenantID := range tenants {
+		n, err := s.dispatcher.RetryDue(ctx, tenantID)
+		if err != nil {
+			s.logger.WithError(err).WithField("tenant_id", tenantID).Warn("Failed to retry webhook deliveries")
+		}
+		retried += n
+	}
+
+	s.mu.Lock()
+	s.lastRun = time.Now()
+	s.lastRetried = retried
+	s.mu.Unlock()
+
+	if retried > 0 {
+		s.logger.WithField("deliveries_retried", retried).Info("Retried due webhook deliveries")
+	}
+}
+
+// GetStats returns scheduler and delivery statistics
+func (s *WebhookRetryScheduler) GetStats() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.dispatcher.GetStats()
+	stats["running"] = s.running
+	stats["retry_interval"] = s.dispatcher.RetryInterval().String()
+	if !s.lastRun.IsZero() {
+		stats["last_run"] = s.lastRun.Format(time.RFC3339)
+		stats["last_retried"] = s.lastRetried
+	}
+	return stats
+}
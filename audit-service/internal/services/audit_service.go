@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
+	"audit-service/internal/config"
 	"audit-service/internal/models"
 	auditNats "audit-service/internal/nats"
 	"audit-service/internal/repository"
@@ -20,6 +21,12 @@ type AuditService struct {
 	repo      repository.AuditRepositoryInterface
 	logger    *logrus.Logger
 	publisher *auditNats.Publisher
+	retention *RetentionPolicy
+
+	pseudonymizer *Pseudonymizer
+	geo           *GeoEnricher
+	sampler       *Sampler
+	webhooks      *WebhookDispatcher
 }
 
 // NewAuditService creates a new audit service
@@ -28,11 +35,20 @@ func NewAuditService(repo repository.AuditRepositoryInterface, logger *logrus.Lo
 		repo:      repo,
 		logger:    logger,
 		publisher: publisher,
+		retention: NewRetentionPolicy(config.RetentionConfig{}),
 	}
 }
 
 // LogAction logs an action to the audit trail
+// Events dropped by a sampling policy are not an error.
 func (s *AuditService) LogAction(ctx context.Context, tenantID string, log *models.AuditLog) error {
+	_, err := s.Ingest(ctx, tenantID, log)
+	return err
+}
+
+// Ingest logs an action to the audit trail and reports whether it was stored
+// or dropped by the tenant's sampling policy
+func (s *AuditService) Ingest(ctx context.Context, tenantID string, log *models.AuditLog) (bool, error) {
 	// Set timestamp if not already set
 	if log.Timestamp.IsZero() {
 		log.Timestamp = time.Now()
@@ -41,10 +57,21 @@ func (s *AuditService) LogAction(ctx context.Context, tenantID string, log *mode
 	// Ensure tenant ID is set
 	log.TenantID = tenantID
 
+	// Store only a share of high-volume, low-value events; the weight is set by ingest, never by callers
+	log.SampleWeight = 1
+	if s.sampler != nil && !s.sampler.Sample(ctx, tenantID, log) {
+		return false, nil
+	}
+
+	// Stamp coarse location from the client IP
+	if s.geo != nil {
+		s.geo.Enrich(ctx, log)
+	}
+
 	// Create audit log
 	if err := s.repo.Create(ctx, tenantID, log); err != nil {
 		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to create audit log")
-		return fmt.Errorf("failed to create audit log: %w", err)
+		return false, fmt.Errorf("failed to create audit log: %w", err)
 	}
 
 	// Publish event to NATS for real-time streaming
@@ -56,6 +83,11 @@ func (s *AuditService) LogAction(ctx context.Context, tenantID string, log *mode
 		}()
 	}
 
+	// Forward to the tenant's webhook subscriptions
+	if s.webhooks != nil {
+		go s.webhooks.Dispatch(context.Background(), tenantID, log)
+	}
+
 	// Log critical events to application logger
 	if log.IsCritical() || log.ShouldAlert() {
 		s.logger.WithFields(logrus.Fields{
@@ -70,7 +102,7 @@ func (s *AuditService) LogAction(ctx context.Context, tenantID string, log *mode
 		}).Warn("Critical audit event")
 	}
 
-	return nil
+	return true, nil
 }
 
 // GetAuditLog retrieves a single audit log by ID
@@ -185,8 +217,8 @@ func (s *AuditService) GetSummary(ctx context.Context, tenantID string, fromDate
 }
 
 // GetSuspiciousActivity detects potentially suspicious activity patterns
-func (s *AuditService) GetSuspiciousActivity(ctx context.Context, tenantID string) ([]models.AuditLog, error) {
-	logs, err := s.repo.GetSuspiciousActivity(ctx, tenantID)
+func (s *AuditService) GetSuspiciousActivity(ctx context.Context, tenantID string, params repository.SuspiciousActivityParams) ([]models.AuditLog, error) {
+	logs, err := s.repo.GetSuspiciousActivity(ctx, tenantID, params)
 	if err != nil {
 		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to get suspicious activity")
 		return nil, fmt.Errorf("failed to get suspicious activity: %w", err)
@@ -247,12 +279,13 @@ func (s *AuditService) ExportAuditLogs(ctx context.Context, tenantID string, fil
 	return logs, nil
 }
 
-// ExportToJSON exports audit logs to JSON format
-func (s *AuditService) ExportToJSON(ctx context.Context, tenantID string, filter *models.AuditLogFilter) ([]byte, error) {
+// ExportToJSON exports audit logs to JSON format, shaped for the caller's field view
+func (s *AuditService) ExportToJSON(ctx context.Context, tenantID string, filter *models.AuditLogFilter, view models.FieldView) ([]byte, error) {
 	logs, err := s.ExportAuditLogs(ctx, tenantID, filter)
 	if err != nil {
 		return nil, err
 	}
+	models.ApplyViewToLogs(logs, view)
 
 	data, err := json.MarshalIndent(logs, "", "  ")
 	if err != nil {
@@ -263,17 +296,19 @@ func (s *AuditService) ExportToJSON(ctx context.Context, tenantID string, filter
 		"tenant_id": tenantID,
 		"count":     len(logs),
 		"format":    "JSON",
+		"view":      view,
 	}).Info("Exported audit logs")
 
 	return data, nil
 }
 
-// ExportToCSV exports audit logs to CSV format
-func (s *AuditService) ExportToCSV(ctx context.Context, tenantID string, filter *models.AuditLogFilter) ([]byte, error) {
+// ExportToCSV exports audit logs to CSV format, shaped for the caller's field view
+func (s *AuditService) ExportToCSV(ctx context.Context, tenantID string, filter *models.AuditLogFilter, view models.FieldView) ([]byte, error) {
 	logs, err := s.ExportAuditLogs(ctx, tenantID, filter)
 	if err != nil {
 		return nil, err
 	}
+	models.ApplyViewToLogs(logs, view)
 
 	// Create CSV data
 	var csvData [][]string
@@ -322,6 +357,7 @@ func (s *AuditService) ExportToCSV(ctx context.Context, tenantID string, filter
 		"tenant_id": tenantID,
 		"count":     len(logs),
 		"format":    "CSV",
+		"view":      view,
 	}).Info("Exported audit logs")
 
 	return buf, nil
@@ -348,19 +384,18 @@ func (s *AuditService) GetRetentionSettings(ctx context.Context, tenantID string
 }
 
 // SetRetentionSettings saves retention settings for a tenant
+// Returns *RetentionPlanError when the requested retention exceeds the tenant's plan
 func (s *AuditService) SetRetentionSettings(ctx context.Context, tenantID string, retentionDays int) (*models.RetentionSettings, error) {
-	// Validate retention days (3-12 months)
-	if retentionDays < 90 {
-		retentionDays = 90
-	}
-	if retentionDays > 365 {
-		retentionDays = 365
+	settings, err := s.repo.GetRetentionSettings(ctx, tenantID)
+	if err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to get retention settings")
+		return nil, fmt.Errorf("failed to get retention settings: %w", err)
 	}
 
-	settings := &models.RetentionSettings{
-		TenantID:      tenantID,
-		RetentionDays: retentionDays,
+	if err := s.retention.Validate(settings, retentionDays); err != nil {
+		return nil, err
 	}
+	settings.RetentionDays = retentionDays
 
 	if err := s.repo.SetRetentionSettings(ctx, tenantID, settings); err != nil {
 		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to set retention settings")
@@ -384,10 +419,7 @@ func (s *AuditService) CleanupOldLogs(ctx context.Context, tenantID string) (int
 		return 0, err
 	}
 
-	retentionDays := settings.RetentionDays
-	if retentionDays <= 0 {
-		retentionDays = 180 // Default 6 months
-	}
+	retentionDays := settings.EffectiveDays(s.retention.DefaultDays())
 
 	// Perform cleanup
 	deleted, err := s.repo.CleanupOldLogs(ctx, tenantID, retentionDays)
@@ -405,7 +437,21 @@ func (s *AuditService) CleanupOldLogs(ctx context.Context, tenantID string) (int
 	return deleted, nil
 }
 
-// GetRetentionOptions returns available retention period options
-func (s *AuditService) GetRetentionOptions() []models.RetentionOption {
-	return models.GetRetentionOptions()
+// PurgeTenant permanently deletes a deleted tenant's audit data for tenant-service's deletion saga
+func (s *AuditService) PurgeTenant(ctx context.Context, tenantID string) (int64, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("tenant ID is required")
+	}
+
+	purged, err := s.repo.PurgeTenant(ctx, tenantID)
+	if err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to purge tenant audit data")
+		return purged, err
+	}
+	return purged, nil
+}
+
+// GetRetentionOptions returns retention period options with availability on the tenant's plan
+func (s *AuditService) GetRetentionOptions(settings *models.RetentionSettings) []models.RetentionOption {
+	return s.retention.Options(settings)
 }
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"audit-service/internal/clients"
+	"audit-service/internal/config"
+	"audit-service/internal/models"
+)
+
+// geoLocalCacheSize caps the in-memory IP cache; it is cleared when full
+const geoLocalCacheSize = 10000
+
+// IPLocator resolves an IP address to a coarse location (nil when unknown)
+type IPLocator interface {
+	LocateIP(ctx context.Context, ip string) (*clients.IPLocation, error)
+}
+
+// GeoEnricher stamps audit logs with the country and city of their IP address.
+// Lookups are cached in memory and Redis so each IP reaches location-service at most once per TTL.
+type GeoEnricher struct {
+	locator    IPLocator
+	redis      *redis.Client
+	logger     *logrus.Logger
+	timeout    time.Duration
+	ttl        time.Duration
+	failureTTL time.Duration
+
+	mu    sync.RWMutex
+	local map[string]geoCacheEntry
+}
+
+type geoCacheEntry struct {
+	location  *clients.IPLocation // nil caches "unknown"
+	expiresAt time.Time
+}
+
+// NewGeoEnricher creates a new geo enricher; redisClient may be nil
+func NewGeoEnricher(locator IPLocator, redisClient *redis.Client, cfg config.GeoConfig, logger *logrus.Logger) *GeoEnricher {
+	return &GeoEnricher{
+		locator:    locator,
+		redis:      redisClient,
+		logger:     logger,
+		timeout:    time.Duration(cfg.LookupTimeoutMs) * time.Millisecond,
+		ttl:        time.Duration(cfg.CacheTTL) * time.Second,
+		failureTTL: time.Duration(cfg.FailureCacheTTL) * time.Second,
+		local:      make(map[string]geoCacheEntry),
+	}
+}
+
+// SetGeoEnricher sets the enricher that stamps new audit logs with their IP location
+func (s *AuditService) SetGeoEnricher(e *GeoEnricher) {
+	s.geo = e
+}
+
+// Enrich sets GeoCountry and GeoCity from the log's IP address.
+// Logs that already carry a country, or whose IP is private or unresolvable, are left unchanged.
+func (e *GeoEnricher) Enrich(ctx context.Context, log *models.AuditLog) {
+	if log.GeoCountry != "" {
+		return
+	}
+	ip := publicIP(log.IPAddress)
+	if ip == "" {
+		return
+	}
+
+	if location := e.resolve(ctx, ip); location != nil {
+		log.GeoCountry = location.Country
+		log.GeoCity = location.City
+	}
+}
+
+// resolve returns the cached location of an IP, looking it up on a miss
+func (e *GeoEnricher) resolve(ctx context.Context, ip string) *clients.IPLocation {
+	if location, ok := e.getLocal(ip); ok {
+		return location
+	}
+
+	// Resolution outlives a cancelled request context (middleware logs asynchronously) but not the timeout
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.timeout)
+	defer cancel()
+
+	if location, ok := e.getRedis(ctx, ip); ok {
+		e.setLocal(ip, location, e.ttlFor(location))
+		return location
+	}
+
+	location, err := e.locator.LocateIP(ctx, ip)
+	if err != nil {
+		e.logger.WithError(err).Debug("IP geolocation lookup failed")
+	}
+
+	ttl := e.ttlFor(location)
+	e.setLocal(ip, location, ttl)
+	e.setRedis(ctx, ip, location, ttl)
+	return location
+}
+
+// ttlFor caches unknown IPs briefly so they are retried once location-service recovers
+func (e *GeoEnricher) ttlFor(location *clients.IPLocation) time.Duration {
+	if location == nil {
+		return e.failureTTL
+	}
+	return e.ttl
+}
+
+func (e *GeoEnricher) getLocal(ip string) (*clients.IPLocation, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	entry, ok := e.local[ip]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.location, true
+}
+
+func (e *GeoEnricher) setLocal(ip string, location *clients.IPLocation, ttl time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.local) >= geoLocalCacheSize {
+		e.local = make(map[string]geoCacheEntry)
+	}
+	e.local[ip] = geoCacheEntry{location: location, expiresAt: time.Now().Add(ttl)}
+}
+
+func geoCacheKey(ip string) string {
+	return "audit:geo:" + ip
+}
+
+// getRedis returns the cached location; an empty country is a cached "unknown"
+func (e *GeoEnricher) getRedis(ctx context.Context, ip string) (*clients.IPLocation, bool) {
+	if e.redis == nil {
+		return nil, false
+	}
+	data, err := e.redis.Get(ctx, geoCacheKey(ip)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			e.logger.WithError(err).Debug("Failed to read geo cache")
+		}
+		return nil, false
+	}
+
+	var location clients.IPLocation
+	if err := json.Unmarshal(data, &location); err != nil {
+		return nil, false
+	}
+	if location.Country == "" {
+		return nil, true
+	}
+	return &location, true
+}
+
+func (e *GeoEnricher) setRedis(ctx context.Context, ip string, location *clients.IPLocation, ttl time.Duration) {
+	if e.redis == nil {
+		return
+	}
+	if location == nil {
+		location = &clients.IPLocation{}
+	}
+	data, err := json.Marshal(location)
+	if err != nil {
+		return
+	}
+	if err := e.redis.Set(ctx, geoCacheKey(ip), data, ttl).Err(); err != nil {
+		e.logger.WithError(err).Debug("Failed to write geo cache")
+	}
+}
+
+// publicIP returns the normalized IP, or "" for addresses that have no public location
+func publicIP(address string) string {
+	ip := net.ParseIP(address)
+	if ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return ""
+	}
+	return ip.String()
+}
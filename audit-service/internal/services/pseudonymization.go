@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/datatypes"
+
+	"audit-service/internal/models"
+)
+
+// pseudonymNamespace scopes pseudonymous user IDs so they cannot collide with real ones
+var pseudonymNamespace = uuid.MustParse("6f1c9a52-3b7e-4d0a-9c61-2f8e5d4b7a10")
+
+// Pseudonymizer derives stable per-tenant pseudonyms for erased data subjects.
+// The same subject always maps to the same pseudonym within a tenant, so an erased
+// user's actions remain linkable to each other without identifying them.
+type Pseudonymizer struct {
+	secret []byte
+}
+
+// NewPseudonymizer creates a pseudonymizer keyed by secret
+func NewPseudonymizer(secret string) *Pseudonymizer {
+	return &Pseudonymizer{secret: []byte(secret)}
+}
+
+// token returns the pseudonym for a subject key within a tenant
+func (p *Pseudonymizer) token(tenantID, key string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(tenantID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(key))
+	return "anon-" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// subjectPseudonyms holds the replacement values for one erased subject
+type subjectPseudonyms struct {
+	subject     models.ErasureSubject
+	token       string    // Replaces names and customer IDs
+	userID      uuid.UUID // Replaces the user ID
+	maskedEmail string
+	emailRe     *regexp.Regexp
+}
+
+func (p *Pseudonymizer) forSubject(tenantID string, subject models.ErasureSubject) *subjectPseudonyms {
+	// Key on the most stable identifier available
+	key := strings.ToLower(subject.Email)
+	if subject.CustomerID != "" {
+		key = "customer:" + subject.CustomerID
+	}
+	if subject.UserID != uuid.Nil {
+		key = "user:" + subject.UserID.String()
+	}
+
+	token := p.token(tenantID, key)
+	ps := &subjectPseudonyms{
+		subject: subject,
+		token:   token,
+		userID:  uuid.NewSHA1(pseudonymNamespace, []byte(tenantID+":"+token)),
+	}
+	if subject.Email != "" {
+		ps.maskedEmail = models.MaskEmail(subject.Email)
+		ps.emailRe = regexp.MustCompile("(?i)" + regexp.QuoteMeta(subject.Email))
+	}
+	return ps
+}
+
+// apply pseudonymizes the subject's identifiers in a log entry
+func (ps *subjectPseudonyms) apply(log *models.AuditLog) {
+	userMatched := (ps.subject.UserID != uuid.Nil && log.UserID == ps.subject.UserID) ||
+		(ps.subject.Email != "" && strings.EqualFold(log.UserEmail, ps.subject.Email))
+
+	if userMatched {
+		log.UserID = ps.userID
+		log.Username = ps.token
+		log.UserEmail = models.MaskEmail(log.UserEmail)
+		log.IPAddress = models.MaskIP(log.IPAddress)
+		log.GeoCity = ""
+	}
+	if log.Resource == models.ResourceCustomer && ps.subject.CustomerID != "" && log.ResourceID == ps.subject.CustomerID {
+		log.ResourceID = ps.token
+		log.ResourceName = ps.token
+	}
+
+	// Identifiers can also appear in free text and change payloads
+	log.Path = ps.replace(log.Path)
+	log.Query = ps.replace(log.Query)
+	log.Description = ps.replace(log.Description)
+	log.OldValue = ps.replaceJSON(log.OldValue)
+	log.NewValue = ps.replaceJSON(log.NewValue)
+	log.Changes = ps.replaceJSON(log.Changes)
+	log.Metadata = ps.replaceJSON(log.Metadata)
+}
+
+func (ps *subjectPseudonyms) replace(text string) string {
+	if text == "" {
+		return text
+	}
+	if ps.emailRe != nil {
+		text = ps.emailRe.ReplaceAllLiteralString(text, ps.maskedEmail)
+	}
+	if ps.subject.UserID != uuid.Nil {
+		text = strings.ReplaceAll(text, ps.subject.UserID.String(), ps.userID.String())
+	}
+	if ps.subject.CustomerID != "" {
+		text = strings.ReplaceAll(text, ps.subject.CustomerID, ps.token)
+	}
+	return text
+}
+
+// replaceJSON substitutes identifiers inside a JSON document; replacements contain
+// no characters needing escapes, so the document stays valid
+func (ps *subjectPseudonyms) replaceJSON(doc datatypes.JSON) datatypes.JSON {
+	if len(doc) == 0 {
+		return doc
+	}
+	return datatypes.JSON(ps.replace(string(doc)))
+}
+
+// SetPseudonymizer sets the pseudonymizer used for erasure requests
+func (s *AuditService) SetPseudonymizer(p *Pseudonymizer) {
+	s.pseudonymizer = p
+}
+
+// PseudonymizeSubject replaces a purged customer's identifiers in the tenant's
+// historical audit logs with a stable pseudonym, keeping the records themselves
+func (s *AuditService) PseudonymizeSubject(ctx context.Context, tenantID string, subject models.ErasureSubject) (int64, error) {
+	if s.pseudonymizer == nil {
+		return 0, fmt.Errorf("pseudonymization is not configured")
+	}
+	ps := s.pseudonymizer.forSubject(tenantID, subject)
+
+	rewritten, err := s.repo.PseudonymizeSubject(ctx, tenantID, subject, ps.apply)
+	if err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to pseudonymize audit logs")
+		return rewritten, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"tenant_id":      tenantID,
+		"pseudonym":      ps.token,
+		"logs_rewritten": rewritten,
+	}).Info("Pseudonymized audit logs for erased customer")
+	return rewritten, nil
+}
+
+// PseudonymizeLog applies a subject's pseudonyms to a single log before it is written
+func (s *AuditService) PseudonymizeLog(tenantID string, subject models.ErasureSubject, log *models.AuditLog) {
+	if s.pseudonymizer == nil {
+		return
+	}
+	s.pseudonymizer.forSubject(tenantID, subject).apply(log)
+}
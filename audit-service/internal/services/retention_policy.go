@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"audit-service/internal/config"
+	"audit-service/internal/models"
+)
+
+// ErrRetentionOutOfRange is returned when requested retention is outside the platform limits
+var ErrRetentionOutOfRange = errors.New("retention days out of range")
+
+// RetentionPlanError is returned when a tenant requests retention beyond their plan's tier
+type RetentionPlanError struct {
+	RequestedDays int    `json:"requestedDays"`
+	Plan          string `json:"plan"`
+	MaxDays       int    `json:"maxDays"`
+	UpgradePlan   string `json:"upgradePlan,omitempty"` // Lowest plan allowing the requested retention
+	UpgradeDays   int    `json:"upgradeDays,omitempty"` // Retention allowed by UpgradePlan
+}
+
+func (e *RetentionPlanError) Error() string {
+	return fmt.Sprintf("retention of %d days exceeds the %d days allowed on the %s plan", e.RequestedDays, e.MaxDays, e.Plan)
+}
+
+// Hint returns a user-facing suggestion for resolving the error
+func (e *RetentionPlanError) Hint() string {
+	if e.UpgradePlan == "" {
+		return fmt.Sprintf("Choose a retention period of %d days or less", e.MaxDays)
+	}
+	return fmt.Sprintf("Upgrade to the %s plan to keep audit logs for up to %d days, or choose %d days or less", e.UpgradePlan, e.UpgradeDays, e.MaxDays)
+}
+
+// planTier is a plan and the retention it allows
+type planTier struct {
+	plan string
+	days int
+}
+
+// RetentionPolicy maps tenant subscription plans to retention tiers
+type RetentionPolicy struct {
+	tiers       map[string]int
+	ordered     []planTier // Ascending by days
+	defaultDays int
+	minDays     int
+	maxDays     int
+}
+
+// NewRetentionPolicy creates a retention policy from configuration
+func NewRetentionPolicy(cfg config.RetentionConfig) *RetentionPolicy {
+	p := &RetentionPolicy{
+		tiers:       make(map[string]int, len(cfg.PlanTiers)),
+		defaultDays: cfg.DefaultDays,
+		minDays:     cfg.MinDays,
+		maxDays:     cfg.MaxDays,
+	}
+	if p.defaultDays <= 0 {
+		p.defaultDays = 180
+	}
+	if p.minDays <= 0 {
+		p.minDays = 90
+	}
+	if p.maxDays <= 0 {
+		p.maxDays = 365
+	}
+
+	for plan, days := range cfg.PlanTiers {
+		if days > p.maxDays {
+			days = p.maxDays
+		}
+		plan = strings.ToLower(plan)
+		p.tiers[plan] = days
+		p.ordered = append(p.ordered, planTier{plan: plan, days: days})
+	}
+	sort.Slice(p.ordered, func(i, j int) bool {
+		if p.ordered[i].days == p.ordered[j].days {
+			return p.ordered[i].plan < p.ordered[j].plan
+		}
+		return p.ordered[i].days < p.ordered[j].days
+	})
+	return p
+}
+
+// PlanMaxDays returns the retention cap for a plan; 0 when the plan has no tier
+func (p *RetentionPolicy) PlanMaxDays(plan string) int {
+	return p.tiers[strings.ToLower(plan)]
+}
+
+// DefaultDays returns the retention used when a tenant has not chosen one
+func (p *RetentionPolicy) DefaultDays() int {
+	return p.defaultDays
+}
+
+// limits returns the selectable retention range for a tenant's settings
+func (p *RetentionPolicy) limits(settings *models.RetentionSettings) (minDays, maxDays int) {
+	minDays, maxDays = p.minDays, p.maxDays
+	if settings.PlanMaxDays > 0 && settings.PlanMaxDays < maxDays {
+		maxDays = settings.PlanMaxDays
+	}
+	if minDays > maxDays {
+		minDays = maxDays
+	}
+	return minDays, maxDays
+}
+
+// upgradeFor returns the lowest plan whose tier allows days
+func (p *RetentionPolicy) upgradeFor(days int) (string, int) {
+	for _, tier := range p.ordered {
+		if tier.days >= days {
+			return tier.plan, tier.days
+		}
+	}
+	return "", 0
+}
+
+// Validate checks requested retention against the platform limits and the tenant's plan
+func (p *RetentionPolicy) Validate(settings *models.RetentionSettings, days int) error {
+	minDays, maxDays := p.limits(settings)
+	if days < minDays || days > p.maxDays {
+		return fmt.Errorf("%w: retention must be between %d and %d days", ErrRetentionOutOfRange, minDays, maxDays)
+	}
+	if days > maxDays {
+		planErr := &RetentionPlanError{
+			RequestedDays: days,
+			Plan:          settings.Plan,
+			MaxDays:       maxDays,
+		}
+		planErr.UpgradePlan, planErr.UpgradeDays = p.upgradeFor(days)
+		return planErr
+	}
+	return nil
+}
+
+// Options returns the retention options annotated with availability on the tenant's plan
+func (p *RetentionPolicy) Options(settings *models.RetentionSettings) []models.RetentionOption {
+	minDays, maxDays := p.limits(settings)
+	options := models.GetRetentionOptions()
+	for i := range options {
+		days := options[i].Days
+		options[i].Available = days >= minDays && days <= maxDays
+		if !options[i].Available && days > maxDays && days <= p.maxDays {
+			options[i].RequiredPlan, _ = p.upgradeFor(days)
+		}
+	}
+	return options
+}
+
+// SetRetentionPolicy sets the plan-linked retention policy
+func (s *AuditService) SetRetentionPolicy(policy *RetentionPolicy) {
+	s.retention = policy
+}
+
+// EffectiveRetentionDays returns the retention cleanup enforces for the tenant
+func (s *AuditService) EffectiveRetentionDays(settings *models.RetentionSettings) int {
+	return settings.EffectiveDays(s.retention.DefaultDays())
+}
+
+// ApplyTenantPlan records a tenant's subscription plan and lowers retention that
+// exceeds the new plan's tier; logs beyond the cap are removed by the next cleanup
+func (s *AuditService) ApplyTenantPlan(ctx context.Context, tenantID, plan string) error {
+	settings, err := s.repo.GetRetentionSettings(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get retention settings: %w", err)
+	}
+
+	previousDays := settings.RetentionDays
+	settings.Plan = strings.ToLower(plan)
+	settings.PlanMaxDays = s.retention.PlanMaxDays(plan)
+	if settings.PlanMaxDays > 0 && settings.RetentionDays > settings.PlanMaxDays {
+		settings.RetentionDays = settings.PlanMaxDays
+	}
+
+	if err := s.repo.SetRetentionSettings(ctx, tenantID, settings); err != nil {
+		return fmt.Errorf("failed to save retention settings: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"tenant_id":               tenantID,
+		"plan":                    settings.Plan,
+		"plan_max_days":           settings.PlanMaxDays,
+		"previous_retention_days": previousDays,
+		"retention_days":          settings.RetentionDays,
+	}).Info("Applied tenant plan to audit retention")
+	return nil
+}
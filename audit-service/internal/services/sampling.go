@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"audit-service/internal/config"
+	"audit-service/internal/models"
+)
+
+// ErrInvalidSamplingPolicy is returned when a sampling policy fails validation
+var ErrInvalidSamplingPolicy = errors.New("invalid sampling policy")
+
+// SamplingPolicyStore loads and saves tenant sampling policies
+type SamplingPolicyStore interface {
+	ListSamplingPolicies(ctx context.Context, tenantID string) ([]models.SamplingPolicy, error)
+	UpsertSamplingPolicy(ctx context.Context, tenantID string, policy *models.SamplingPolicy) error
+	DeleteSamplingPolicy(ctx context.Context, tenantID string, id uuid.UUID) error
+}
+
+// Sampler applies tenant sampling policies at ingest.
+// Matching events are stored one in every N (systematic sampling); the stored entry's
+// SampleWeight counts the events it stands for, so SUM(sample_weight) reconstructs the
+// ingested volume. Events skipped since a policy last stored one are only held in memory
+// and are not represented if the instance stops before the next one is stored.
+type Sampler struct {
+	store  SamplingPolicyStore
+	logger *logrus.Logger
+	ttl    time.Duration
+
+	mu       sync.Mutex
+	policies map[string]samplingCacheEntry // By tenant
+	pending  map[string]int64              // Events seen since the last stored one, by tenant and policy
+}
+
+type samplingCacheEntry struct {
+	policies  []models.SamplingPolicy
+	expiresAt time.Time
+}
+
+// NewSampler creates a new sampler
+func NewSampler(store SamplingPolicyStore, cfg config.SamplingConfig, logger *logrus.Logger) *Sampler {
+	ttl := time.Duration(cfg.PolicyCacheTTL) * time.Second
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return &Sampler{
+		store:    store,
+		logger:   logger,
+		ttl:      ttl,
+		policies: make(map[string]samplingCacheEntry),
+		pending:  make(map[string]int64),
+	}
+}
+
+// SetSampler sets the sampler applied to new audit logs
+func (s *AuditService) SetSampler(sampler *Sampler) {
+	s.sampler = sampler
+}
+
+// Sample reports whether the log should be stored. When a policy applies, the stored
+// log's SampleWeight is set to the number of events it stands for, itself included.
+// Logs no policy applies to are always stored.
+func (sm *Sampler) Sample(ctx context.Context, tenantID string, log *models.AuditLog) bool {
+	policy := sm.match(ctx, tenantID, log)
+	if policy == nil {
+		return true
+	}
+	interval := policy.Interval()
+	if interval <= 1 {
+		return true
+	}
+
+	key := tenantID + "/" + policy.ID.String()
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.pending[key]++
+	if sm.pending[key] < interval {
+		return false
+	}
+	log.SampleWeight = sm.pending[key]
+	delete(sm.pending, key)
+	return true
+}
+
+// Invalidate drops the cached policies of a tenant
+func (sm *Sampler) Invalidate(tenantID string) {
+	sm.mu.Lock()
+	delete(sm.policies, tenantID)
+	sm.mu.Unlock()
+}
+
+// match returns the most specific policy applying to the log, if any
+func (sm *Sampler) match(ctx context.Context, tenantID string, log *models.AuditLog) *models.SamplingPolicy {
+	var best *models.SamplingPolicy
+	policies := sm.tenantPolicies(ctx, tenantID)
+	for i := range policies {
+		if !policies[i].Matches(log) {
+			continue
+		}
+		if best == nil || policies[i].Specificity() > best.Specificity() {
+			best = &policies[i]
+		}
+	}
+	return best
+}
+
+// tenantPolicies returns the cached policies of a tenant, loading them on a miss.
+// A failed load stores everything rather than dropping events.
+func (sm *Sampler) tenantPolicies(ctx context.Context, tenantID string) []models.SamplingPolicy {
+	sm.mu.Lock()
+	entry, ok := sm.policies[tenantID]
+	sm.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.policies
+	}
+
+	policies, err := sm.store.ListSamplingPolicies(ctx, tenantID)
+	if err != nil {
+		sm.logger.WithError(err).WithField("tenant_id", tenantID).Warn("Failed to load sampling policies; storing event unsampled")
+		return nil
+	}
+
+	sm.mu.Lock()
+	sm.policies[tenantID] = samplingCacheEntry{policies: policies, expiresAt: time.Now().Add(sm.ttl)}
+	sm.mu.Unlock()
+	return policies
+}
+
+// ListSamplingPolicies returns the tenant's sampling policies
+func (s *AuditService) ListSamplingPolicies(ctx context.Context, tenantID string) ([]models.SamplingPolicy, error) {
+	policies, err := s.repo.ListSamplingPolicies(ctx, tenantID)
+	if err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to list sampling policies")
+		return nil, err
+	}
+	return policies, nil
+}
+
+// SetSamplingPolicy validates and saves a sampling policy; it applies to new events
+// once the sampler's policy cache of the tenant expires on other instances
+func (s *AuditService) SetSamplingPolicy(ctx context.Context, tenantID string, policy *models.SamplingPolicy) error {
+	if policy.SampleRate <= 0 || policy.SampleRate > 1 {
+		return fmt.Errorf("%w: sampleRate must be greater than 0 and at most 1", ErrInvalidSamplingPolicy)
+	}
+	if policy.MaxSeverity == "" {
+		policy.MaxSeverity = models.SeverityMedium
+	}
+	if !models.IsValidSeverity(policy.MaxSeverity) {
+		return fmt.Errorf("%w: maxSeverity must be LOW, MEDIUM, HIGH or CRITICAL", ErrInvalidSamplingPolicy)
+	}
+	if policy.Action == "" && policy.Resource == "" {
+		return fmt.Errorf("%w: action or resource is required", ErrInvalidSamplingPolicy)
+	}
+
+	if err := s.repo.UpsertSamplingPolicy(ctx, tenantID, policy); err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to save sampling policy")
+		return err
+	}
+	if s.sampler != nil {
+		s.sampler.Invalidate(tenantID)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"tenant_id":    tenantID,
+		"action":       policy.Action,
+		"resource":     policy.Resource,
+		"sample_rate":  policy.SampleRate,
+		"max_severity": policy.MaxSeverity,
+	}).Info("Sampling policy saved")
+	return nil
+}
+
+// DeleteSamplingPolicy removes a sampling policy
+func (s *AuditService) DeleteSamplingPolicy(ctx context.Context, tenantID string, id uuid.UUID) error {
+	if err := s.repo.DeleteSamplingPolicy(ctx, tenantID, id); err != nil {
+		return err
+	}
+	if s.sampler != nil {
+		s.sampler.Invalidate(tenantID)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"audit-service/internal/models"
+	"audit-service/internal/repository"
+)
+
+// ErrInvalidTimeseriesQuery is returned for unsupported intervals, groupings or ranges
+var ErrInvalidTimeseriesQuery = errors.New("invalid timeseries query")
+
+// timeseriesIntervals are the bucket sizes accepted by GetTimeseries
+var timeseriesIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"6h":  6 * time.Hour,
+	"12h": 12 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// maxTimeseriesBuckets caps the number of buckets a single query may return
+const maxTimeseriesBuckets = 1000
+
+// GetTimeseries returns audit log counts bucketed by interval and optionally grouped
+// The range is widened to whole buckets so repeated dashboard requests share a cache entry.
+func (s *AuditService) GetTimeseries(ctx context.Context, tenantID, interval, groupBy string, from, to *time.Time) (*models.AuditTimeseries, error) {
+	if interval == "" {
+		interval = "1h"
+	}
+	step, ok := timeseriesIntervals[interval]
+	if !ok {
+		return nil, fmt.Errorf("%w: interval must be one of 1m, 5m, 15m, 30m, 1h, 6h, 12h, 1d", ErrInvalidTimeseriesQuery)
+	}
+	if groupBy != "" {
+		if _, ok := repository.TimeseriesGroupColumn(groupBy); !ok {
+			return nil, fmt.Errorf("%w: group_by must be one of action, resource, status, severity, service", ErrInvalidTimeseriesQuery)
+		}
+	}
+
+	end := time.Now().UTC()
+	if to != nil {
+		end = to.UTC()
+	}
+	end = alignUp(end, step)
+
+	start := end.Add(-24 * time.Hour)
+	if step >= 6*time.Hour {
+		start = end.AddDate(0, 0, -30)
+	}
+	if from != nil {
+		start = from.UTC().Truncate(step)
+	}
+
+	if !start.Before(end) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidTimeseriesQuery)
+	}
+	if buckets := end.Sub(start) / step; buckets > maxTimeseriesBuckets {
+		return nil, fmt.Errorf("%w: range spans %d buckets, the maximum is %d; use a larger interval", ErrInvalidTimeseriesQuery, buckets, maxTimeseriesBuckets)
+	}
+
+	ts, err := s.repo.GetTimeseries(ctx, tenantID, repository.TimeseriesParams{
+		From:          start,
+		To:            end,
+		Interval:      step,
+		IntervalLabel: interval,
+		GroupBy:       groupBy,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to get audit timeseries")
+		return nil, fmt.Errorf("failed to get audit timeseries: %w", err)
+	}
+
+	return ts, nil
+}
+
+// alignUp rounds t up to the next multiple of step (UTC)
+func alignUp(t time.Time, step time.Duration) time.Time {
+	aligned := t.Truncate(step)
+	if aligned.Before(t) {
+		aligned = aligned.Add(step)
+	}
+	return aligned
+}
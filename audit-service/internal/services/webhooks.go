@@ -0,0 +1,610 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/datatypes"
+
+	"audit-service/internal/config"
+	"audit-service/internal/models"
+	"audit-service/internal/repository"
+)
+
+// Webhook request headers; receivers verify X-Audit-Signature with SignWebhookPayload
+const (
+	WebhookHeaderSignature = "X-Audit-Signature"
+	WebhookHeaderTimestamp = "X-Audit-Timestamp"
+	WebhookHeaderEvent     = "X-Audit-Event"
+	WebhookHeaderDelivery  = "X-Audit-Delivery"
+)
+
+var (
+	// ErrInvalidWebhookSubscription is returned when a webhook subscription fails validation
+	ErrInvalidWebhookSubscription = errors.New("invalid webhook subscription")
+	// ErrWebhooksDisabled is returned when webhook forwarding is turned off on this deployment
+	ErrWebhooksDisabled = errors.New("webhooks are disabled")
+	// ErrWebhookDeliveryPending is returned when redelivering a delivery that is still being retried
+	ErrWebhookDeliveryPending = errors.New("webhook delivery is still pending")
+
+	errWebhookTargetBlocked = errors.New("webhook target address is not allowed")
+)
+
+// maxWebhookRetryDelay caps the exponential backoff between attempts
+const maxWebhookRetryDelay = 6 * time.Hour
+
+// webhookClaimBatch is the number of due retries picked up per tenant and run
+const webhookClaimBatch = 100
+
+// WebhookStore loads webhook subscriptions and records their deliveries
+type WebhookStore interface {
+	ListWebhookSubscriptions(ctx context.Context, tenantID string) ([]models.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, tenantID string, id uuid.UUID) (*models.WebhookSubscription, error)
+	SaveWebhookDelivery(ctx context.Context, tenantID string, delivery *models.WebhookDelivery) error
+	GetWebhookDelivery(ctx context.Context, tenantID string, id uuid.UUID) (*models.WebhookDelivery, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, tenantID string, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+}
+
+// WebhookPayload is the JSON body posted to webhook endpoints
+type WebhookPayload struct {
+	Event    string           `json:"event"` // RESOURCE.ACTION, e.g. PRODUCT.DELETE
+	TenantID string           `json:"tenantId"`
+	Log      *models.AuditLog `json:"log"`
+}
+
+// WebhookDispatcher forwards new audit logs to the tenant's webhook subscriptions.
+// Every delivery is logged; failed ones are retried with exponential backoff by
+// RetryDue and dead-lettered after the configured number of attempts.
+type WebhookDispatcher struct {
+	store  WebhookStore
+	cfg    config.WebhookConfig
+	logger *logrus.Logger
+	client *http.Client
+	ttl    time.Duration
+
+	mu            sync.Mutex
+	subscriptions map[string]webhookCacheEntry // By tenant
+
+	delivered    atomic.Int64
+	failed       atomic.Int64
+	deadLettered atomic.Int64
+}
+
+type webhookCacheEntry struct {
+	subscriptions []models.WebhookSubscription
+	expiresAt     time.Time
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher
+func NewWebhookDispatcher(store WebhookStore, cfg config.WebhookConfig, logger *logrus.Logger) *WebhookDispatcher {
+	if cfg.TimeoutSeconds <= 0 {
+		cfg.TimeoutSeconds = 10
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.RetryBaseSeconds <= 0 {
+		cfg.RetryBaseSeconds = 60
+	}
+	ttl := time.Duration(cfg.SubscriptionCacheTTL) * time.Second
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !cfg.AllowPrivateTargets {
+		// Checked on the resolved address so DNS can't point an endpoint at the cluster
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateWebhookIP(ip) {
+				return errWebhookTargetBlocked
+			}
+			return nil
+		}
+	}
+
+	return &WebhookDispatcher{
+		store:  store,
+		cfg:    cfg,
+		logger: logger,
+		ttl:    ttl,
+		client: &http.Client{
+			Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// A redirect is not an acknowledgement; it could also lead past the address check
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		subscriptions: make(map[string]webhookCacheEntry),
+	}
+}
+
+// SetWebhookDispatcher sets the dispatcher forwarding new audit logs to webhooks
+func (s *AuditService) SetWebhookDispatcher(dispatcher *WebhookDispatcher) {
+	s.webhooks = dispatcher
+}
+
+// SignWebhookPayload returns the X-Audit-Signature of a request body:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the subscription secret
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatch delivers a stored audit log to every matching subscription of the tenant.
+// Failed deliveries are left pending for RetryDue.
+func (d *WebhookDispatcher) Dispatch(ctx context.Context, tenantID string, log *models.AuditLog) {
+	subscriptions := d.tenantSubscriptions(ctx, tenantID)
+	for i := range subscriptions {
+		if !subscriptions[i].Matches(log) {
+			continue
+		}
+		delivery, err := d.newDelivery(tenantID, &subscriptions[i], log)
+		if err != nil {
+			d.logger.WithError(err).WithField("tenant_id", tenantID).Warn("Failed to build webhook payload")
+			continue
+		}
+		// Leased for the first attempt so the retry worker leaves it alone meanwhile
+		lease := time.Now().Add(d.lease())
+		delivery.NextAttemptAt = &lease
+		if err := d.store.SaveWebhookDelivery(ctx, tenantID, delivery); err != nil {
+			d.logger.WithError(err).WithFields(logrus.Fields{
+				"tenant_id":       tenantID,
+				"subscription_id": subscriptions[i].ID,
+			}).Error("Failed to record webhook delivery")
+			continue
+		}
+		d.attempt(ctx, tenantID, &subscriptions[i], delivery)
+	}
+}
+
+// RetryDue retries the tenant's pending deliveries whose backoff has elapsed and
+// returns how many were attempted
+func (d *WebhookDispatcher) RetryDue(ctx context.Context, tenantID string) (int, error) {
+	deliveries, err := d.store.ClaimDueWebhookDeliveries(ctx, tenantID, webhookClaimBatch, d.lease())
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := make(map[uuid.UUID]*models.WebhookSubscription)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = d.store.GetWebhookSubscription(ctx, tenantID, delivery.SubscriptionID)
+			if err != nil && !errors.Is(err, repository.ErrWebhookSubscriptionNotFound) {
+				return i, err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		switch {
+		case subscription == nil:
+			d.deadLetter(ctx, tenantID, delivery, "subscription deleted")
+		case !subscription.Enabled:
+			d.deadLetter(ctx, tenantID, delivery, "subscription disabled")
+		default:
+			d.attempt(ctx, tenantID, subscription, delivery)
+		}
+	}
+	return len(deliveries), nil
+}
+
+// Redeliver sends a delivered or dead-lettered delivery again, as originally sent, and
+// starts a fresh retry schedule if it fails
+func (d *WebhookDispatcher) Redeliver(ctx context.Context, tenantID string, id uuid.UUID) (*models.WebhookDelivery, error) {
+	delivery, err := d.store.GetWebhookDelivery(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		return nil, ErrWebhookDeliveryPending
+	}
+	subscription, err := d.store.GetWebhookSubscription(ctx, tenantID, delivery.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.DeliveredAt = nil
+	d.attempt(ctx, tenantID, subscription, delivery)
+	return delivery, nil
+}
+
+// Invalidate drops the cached subscriptions of a tenant
+func (d *WebhookDispatcher) Invalidate(tenantID string) {
+	d.mu.Lock()
+	delete(d.subscriptions, tenantID)
+	d.mu.Unlock()
+}
+
+// GetStats returns delivery counters since the instance started
+func (d *WebhookDispatcher) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"delivered":          d.delivered.Load(),
+		"failed_attempts":    d.failed.Load(),
+		"dead_lettered":      d.deadLettered.Load(),
+		"max_attempts":       d.cfg.MaxAttempts,
+		"retry_base_seconds": d.cfg.RetryBaseSeconds,
+	}
+}
+
+// RetryInterval returns how often due retries should be picked up
+func (d *WebhookDispatcher) RetryInterval() time.Duration {
+	if d.cfg.RetryInterval <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(d.cfg.RetryInterval) * time.Second
+}
+
+// newDelivery builds the pending delivery of a log to a subscription, with the
+// log reduced to the subscription's field view
+func (d *WebhookDispatcher) newDelivery(tenantID string, subscription *models.WebhookSubscription, log *models.AuditLog) (*models.WebhookDelivery, error) {
+	entry := *log
+	entry.ApplyView(subscription.View)
+
+	eventType := models.WebhookEventType(log)
+	payload, err := json.Marshal(WebhookPayload{Event: eventType, TenantID: tenantID, Log: &entry})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	return &models.WebhookDelivery{
+		TenantID:       tenantID,
+		SubscriptionID: subscription.ID,
+		AuditLogID:     log.ID,
+		EventType:      eventType,
+		Status:         models.WebhookDeliveryPending,
+		Payload:        datatypes.JSON(payload),
+	}, nil
+}
+
+// attempt sends a delivery once and records the outcome: delivered, pending with the
+// next retry scheduled, or dead-lettered once it ran out of attempts
+func (d *WebhookDispatcher) attempt(ctx context.Context, tenantID string, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	status, err := d.send(ctx, subscription, delivery)
+	delivery.ResponseStatus = status
+
+	fields := logrus.Fields{
+		"tenant_id":       tenantID,
+		"subscription_id": subscription.ID,
+		"delivery_id":     delivery.ID,
+		"event":           delivery.EventType,
+		"attempt":         delivery.Attempts,
+	}
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		d.delivered.Add(1)
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		delivery.Status = models.WebhookDeliveryDeadLetter
+		delivery.NextAttemptAt = nil
+		delivery.LastError = err.Error()
+		d.failed.Add(1)
+		d.deadLettered.Add(1)
+		d.logger.WithError(err).WithFields(fields).Warn("Webhook delivery dead-lettered")
+	default:
+		next := now.Add(d.backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = err.Error()
+		d.failed.Add(1)
+		d.logger.WithError(err).WithFields(fields).Debug("Webhook delivery failed; retry scheduled")
+	}
+
+	if err := d.store.SaveWebhookDelivery(ctx, tenantID, delivery); err != nil {
+		d.logger.WithError(err).WithFields(fields).Error("Failed to record webhook delivery attempt")
+	}
+}
+
+// deadLetter gives up on a delivery without sending it
+func (d *WebhookDispatcher) deadLetter(ctx context.Context, tenantID string, delivery *models.WebhookDelivery, reason string) {
+	delivery.Status = models.WebhookDeliveryDeadLetter
+	delivery.NextAttemptAt = nil
+	delivery.LastError = reason
+	d.deadLettered.Add(1)
+	if err := d.store.SaveWebhookDelivery(ctx, tenantID, delivery); err != nil {
+		d.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to dead-letter webhook delivery")
+	}
+}
+
+// send posts the delivery's payload, signed with the subscription secret, and returns the
+// response status. Any status outside 2xx is a failure.
+func (d *WebhookDispatcher) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Tesseract-Audit-Webhooks/1.0")
+	req.Header.Set(WebhookHeaderEvent, delivery.EventType)
+	req.Header.Set(WebhookHeaderDelivery, delivery.ID.String())
+	req.Header.Set(WebhookHeaderTimestamp, timestamp)
+	req.Header.Set(WebhookHeaderSignature, SignWebhookPayload(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the next attempt: the base delay doubled for every attempt made
+func (d *WebhookDispatcher) backoff(attempts int) time.Duration {
+	delay := time.Duration(d.cfg.RetryBaseSeconds) * time.Second
+	for i := 1; i < attempts && delay < maxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxWebhookRetryDelay {
+		delay = maxWebhookRetryDelay
+	}
+	return delay
+}
+
+// lease is how long an attempt in flight keeps other instances from retrying the delivery
+func (d *WebhookDispatcher) lease() time.Duration {
+	return 2 * time.Duration(d.cfg.TimeoutSeconds) * time.Second
+}
+
+// tenantSubscriptions returns the cached subscriptions of a tenant, loading them on a miss
+func (d *WebhookDispatcher) tenantSubscriptions(ctx context.Context, tenantID string) []models.WebhookSubscription {
+	d.mu.Lock()
+	entry, ok := d.subscriptions[tenantID]
+	d.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.subscriptions
+	}
+
+	subscriptions, err := d.store.ListWebhookSubscriptions(ctx, tenantID)
+	if err != nil {
+		d.logger.WithError(err).WithField("tenant_id", tenantID).Warn("Failed to load webhook subscriptions; event not forwarded")
+		return nil
+	}
+
+	d.mu.Lock()
+	d.subscriptions[tenantID] = webhookCacheEntry{subscriptions: subscriptions, expiresAt: time.Now().Add(d.ttl)}
+	d.mu.Unlock()
+	return subscriptions
+}
+
+// validateURL requires an absolute HTTPS URL; plain HTTP and private addresses are only
+// accepted when private targets are allowed
+func (d *WebhookDispatcher) validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute URL", ErrInvalidWebhookSubscription)
+	}
+	if d.cfg.AllowPrivateTargets {
+		if u.Scheme != "https" && u.Scheme != "http" {
+			return fmt.Errorf("%w: url must use http or https", ErrInvalidWebhookSubscription)
+		}
+		return nil
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%w: url must use https", ErrInvalidWebhookSubscription)
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); (ip != nil && isPrivateWebhookIP(ip)) || strings.EqualFold(host, "localhost") {
+		return fmt.Errorf("%w: url must not point at a private address", ErrInvalidWebhookSubscription)
+	}
+	return nil
+}
+
+// isPrivateWebhookIP reports whether ip is internal to the platform
+func isPrivateWebhookIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast()
+}
+
+// newWebhookSecret generates a random signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// validateWebhookSubscription normalizes and checks a subscription's settings
+func (d *WebhookDispatcher) validateWebhookSubscription(subscription *models.WebhookSubscription) error {
+	subscription.Name = strings.TrimSpace(subscription.Name)
+	if subscription.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWebhookSubscription)
+	}
+	subscription.URL = strings.TrimSpace(subscription.URL)
+	if err := d.validateURL(subscription.URL); err != nil {
+		return err
+	}
+
+	if subscription.MinSeverity == "" {
+		subscription.MinSeverity = models.SeverityLow
+	}
+	subscription.MinSeverity = models.AuditSeverity(strings.ToUpper(string(subscription.MinSeverity)))
+	if !models.IsValidSeverity(subscription.MinSeverity) {
+		return fmt.Errorf("%w: minSeverity must be LOW, MEDIUM, HIGH or CRITICAL", ErrInvalidWebhookSubscription)
+	}
+
+	if subscription.View == "" {
+		subscription.View = models.ViewRedacted
+	}
+	switch subscription.View {
+	case models.ViewFull, models.ViewRedacted, models.ViewSummary:
+	default:
+		return fmt.Errorf("%w: view must be full, redacted or summary", ErrInvalidWebhookSubscription)
+	}
+
+	eventTypes := subscription.EventTypes.Data()
+	normalized := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.ToUpper(strings.TrimSpace(eventType))
+		resource, action, _ := strings.Cut(eventType, ".")
+		if resource == "" || strings.Contains(action, ".") {
+			return fmt.Errorf("%w: event type %q must be RESOURCE.ACTION, e.g. PRODUCT.DELETE or USER.*", ErrInvalidWebhookSubscription, eventType)
+		}
+		normalized = append(normalized, eventType)
+	}
+	subscription.EventTypes = datatypes.NewJSONType(normalized)
+	return nil
+}
+
+// ListWebhookSubscriptions returns the tenant's webhook subscriptions
+func (s *AuditService) ListWebhookSubscriptions(ctx context.Context, tenantID string) ([]models.WebhookSubscription, error) {
+	subscriptions, err := s.repo.ListWebhookSubscriptions(ctx, tenantID)
+	if err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to list webhook subscriptions")
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// CreateWebhookSubscription validates and saves a new subscription with a generated
+// signing secret, which is returned once
+func (s *AuditService) CreateWebhookSubscription(ctx context.Context, tenantID string, subscription *models.WebhookSubscription) (string, error) {
+	if s.webhooks == nil {
+		return "", ErrWebhooksDisabled
+	}
+	if err := s.webhooks.validateWebhookSubscription(subscription); err != nil {
+		return "", err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+
+	subscription.ID = uuid.Nil
+	subscription.Secret = secret
+	if err := s.repo.SaveWebhookSubscription(ctx, tenantID, subscription); err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to create webhook subscription")
+		return "", err
+	}
+	s.webhooks.Invalidate(tenantID)
+
+	s.logger.WithFields(logrus.Fields{
+		"tenant_id":       tenantID,
+		"subscription_id": subscription.ID,
+		"event_types":     subscription.EventTypes.Data(),
+	}).Info("Webhook subscription created")
+	return secret, nil
+}
+
+// UpdateWebhookSubscription applies changed settings to a subscription; its secret is kept
+func (s *AuditService) UpdateWebhookSubscription(ctx context.Context, tenantID string, id uuid.UUID, update func(*models.WebhookSubscription)) (*models.WebhookSubscription, error) {
+	if s.webhooks == nil {
+		return nil, ErrWebhooksDisabled
+	}
+	subscription, err := s.repo.GetWebhookSubscription(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	update(subscription)
+	if err := s.webhooks.validateWebhookSubscription(subscription); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveWebhookSubscription(ctx, tenantID, subscription); err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to update webhook subscription")
+		return nil, err
+	}
+	s.webhooks.Invalidate(tenantID)
+	return subscription, nil
+}
+
+// RotateWebhookSecret replaces a subscription's signing secret and returns the new one
+func (s *AuditService) RotateWebhookSecret(ctx context.Context, tenantID string, id uuid.UUID) (string, error) {
+	if s.webhooks == nil {
+		return "", ErrWebhooksDisabled
+	}
+	subscription, err := s.repo.GetWebhookSubscription(ctx, tenantID, id)
+	if err != nil {
+		return "", err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	subscription.Secret = secret
+	if err := s.repo.SaveWebhookSubscription(ctx, tenantID, subscription); err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to rotate webhook secret")
+		return "", err
+	}
+	s.webhooks.Invalidate(tenantID)
+
+	s.logger.WithFields(logrus.Fields{
+		"tenant_id":       tenantID,
+		"subscription_id": id,
+	}).Info("Webhook secret rotated")
+	return secret, nil
+}
+
+// DeleteWebhookSubscription removes a subscription; its delivery log is kept
+func (s *AuditService) DeleteWebhookSubscription(ctx context.Context, tenantID string, id uuid.UUID) error {
+	if err := s.repo.DeleteWebhookSubscription(ctx, tenantID, id); err != nil {
+		return err
+	}
+	if s.webhooks != nil {
+		s.webhooks.Invalidate(tenantID)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the tenant's webhook delivery log
+func (s *AuditService) ListWebhookDeliveries(ctx context.Context, tenantID string, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, int64, error) {
+	deliveries, total, err := s.repo.ListWebhookDeliveries(ctx, tenantID, filter)
+	if err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to list webhook deliveries")
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// RedeliverWebhook sends a delivered or dead-lettered delivery again
+func (s *AuditService) RedeliverWebhook(ctx context.Context, tenantID string, id uuid.UUID) (*models.WebhookDelivery, error) {
+	if s.webhooks == nil {
+		return nil, ErrWebhooksDisabled
+	}
+	return s.webhooks.Redeliver(ctx, tenantID, id)
+}
@@ -135,6 +135,17 @@ func main() {
 	// Initialize security handlers for admin unlock endpoints
 	securityHandlers = handlers.NewSecurityHandlers(securityMiddleware, authRepo, eventsPublisher)
 
+	// Initialize compat mode middleware (read-only mode and usage telemetry for decommissioning)
+	compatMiddleware := middleware.NewCompatMiddleware(middleware.CompatConfig{
+		Enabled:                 cfg.Compat.Enabled,
+		MigrationGuideURL:       cfg.Compat.MigrationGuideURL,
+		MaxConsumersPerEndpoint: cfg.Compat.MaxConsumersPerEndpoint,
+	}, logger)
+	compatHandlers := handlers.NewCompatHandlers(compatMiddleware)
+	if cfg.Compat.Enabled {
+		log.Println("⚠️  Compat mode enabled: registration and password changes are disabled")
+	}
+
 	// Setup Gin router
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -148,6 +159,7 @@ func main() {
 	router.Use(middleware.SecurityHeaders())
 	router.Use(middleware.TenantMiddlewareWithResolver(tenantClient)) // Supports both UUID and slug in X-Tenant-ID
 	router.Use(gin.Recovery())
+	router.Use(compatMiddleware.TrackUsage()) // Per-endpoint usage to find remaining consumers
 
 	// Health check endpoints
 	router.GET("/health", authHandlers.Health)
@@ -182,6 +194,7 @@ func main() {
 
 			// Registration with auth rate limiting
 			auth.POST("/register",
+				compatMiddleware.Disable(middleware.CompatOperationRegister),
 				sharedmw.AuthRateLimit(),
 				passwordHandlers.Register,
 			)
@@ -191,16 +204,19 @@ func main() {
 
 			// Password reset with strict rate limiting (prevents abuse)
 			auth.POST("/password/forgot",
+				compatMiddleware.Disable(middleware.CompatOperationPasswordReset),
 				sharedmw.PasswordResetRateLimit(), // Very strict: ~3 requests per hour
 				passwordHandlers.ForgotPassword,
 			)
 			auth.POST("/password/reset",
+				compatMiddleware.Disable(middleware.CompatOperationPasswordReset),
 				sharedmw.PasswordResetRateLimit(),
 				passwordHandlers.ResetPassword,
 			)
 
 			// Resend verification email (rate limited to prevent spam)
 			auth.POST("/resend-verification",
+				compatMiddleware.Disable(middleware.CompatOperationRegister),
 				sharedmw.PasswordResetRateLimit(),
 				passwordHandlers.ResendVerification,
 			)
@@ -215,6 +231,7 @@ func main() {
 
 			// Password change (requires authentication + rate limiting)
 			protected.POST("/password/change",
+				compatMiddleware.Disable(middleware.CompatOperationPasswordChange),
 				sharedmw.AuthRateLimit(), // Rate limited to prevent brute force
 				passwordHandlers.ChangePassword,
 			)
@@ -272,6 +289,9 @@ func main() {
 				security.POST("/unlock-by-email", securityHandlers.UnlockAccountByEmail)
 				security.GET("/config", securityHandlers.GetSecurityConfig)
 			}
+
+			// Compat mode telemetry (remaining consumers before decommissioning)
+			admin.GET("/compat/usage", compatHandlers.GetUsage)
 		}
 	}
 
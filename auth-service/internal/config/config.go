@@ -16,6 +16,7 @@ type Config struct {
 	JWT                    JWTConfig      `mapstructure:"jwt"`
 	Azure                  AzureConfig    `mapstructure:"azure"`
 	Security               SecurityConfig `mapstructure:"security"`
+	Compat                 CompatConfig   `mapstructure:"compat"`
 	NotificationServiceURL string         `mapstructure:"notification_service_url"`
 	TenantServiceURL       string         `mapstructure:"tenant_service_url"`
 }
@@ -56,6 +57,16 @@ func (c *SecurityConfig) GetLockoutResetDuration() time.Duration {
 	return time.Duration(c.LockoutResetHours) * time.Hour
 }
 
+// CompatConfig holds settings for the read-only compat mode used while decommissioning
+type CompatConfig struct {
+	// Enabled disables registration and password changes with migration guidance errors
+	Enabled bool `mapstructure:"enabled"`
+	// MigrationGuideURL is returned to callers of disabled endpoints
+	MigrationGuideURL string `mapstructure:"migration_guide_url"`
+	// MaxConsumersPerEndpoint bounds the distinct consumers tracked per endpoint
+	MaxConsumersPerEndpoint int `mapstructure:"max_consumers_per_endpoint"`
+}
+
 type ServerConfig struct {
 	Host string `mapstructure:"host"`
 	Port string `mapstructure:"port"`
@@ -125,6 +136,11 @@ func LoadConfig() *Config {
 	viper.SetDefault("security.permanent_lockout_threshold", 20) // 4 tiers x 5 attempts
 	viper.SetDefault("security.lockout_reset_hours", 24)     // 24 hours
 
+	// Compat mode defaults (off until consumers have migrated to Keycloak)
+	viper.SetDefault("compat.enabled", false)
+	viper.SetDefault("compat.migration_guide_url", "")
+	viper.SetDefault("compat.max_consumers_per_endpoint", 50)
+
 	// Read from environment variables
 	viper.AutomaticEnv()
 
@@ -224,6 +240,17 @@ func LoadConfig() *Config {
 		viper.Set("security.lockout_reset_hours", resetHours)
 	}
 
+	// Compat mode environment variables
+	if compatMode := os.Getenv("AUTH_COMPAT_MODE"); compatMode != "" {
+		viper.Set("compat.enabled", compatMode)
+	}
+	if guideURL := os.Getenv("AUTH_MIGRATION_GUIDE_URL"); guideURL != "" {
+		viper.Set("compat.migration_guide_url", guideURL)
+	}
+	if maxConsumers := os.Getenv("AUTH_COMPAT_MAX_CONSUMERS_PER_ENDPOINT"); maxConsumers != "" {
+		viper.Set("compat.max_consumers_per_endpoint", maxConsumers)
+	}
+
 	// Unmarshal into config struct
 	if err := viper.Unmarshal(config); err != nil {
 		log.Fatalf("Unable to decode config: %v", err)
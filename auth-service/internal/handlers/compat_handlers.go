@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"auth-service/internal/middleware"
+)
+
+// CompatHandlers handles admin endpoints for the deprecation compat mode
+type CompatHandlers struct {
+	compatMw *middleware.CompatMiddleware
+}
+
+// NewCompatHandlers creates a new CompatHandlers instance
+func NewCompatHandlers(compatMw *middleware.CompatMiddleware) *CompatHandlers {
+	return &CompatHandlers{
+		compatMw: compatMw,
+	}
+}
+
+// GetUsage handles GET /api/v1/admin/compat/usage
+// Returns per-endpoint request counts and consumers of this instance since startup
+func (h *CompatHandlers) GetUsage(c *gin.Context) {
+	endpoints := h.compatMw.Usage()
+
+	var total, blocked int64
+	for _, endpoint := range endpoints {
+		total += endpoint.Requests
+		blocked += endpoint.Blocked
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"compat_mode":      h.compatMw.Enabled(),
+			"tracking_since":   h.compatMw.StartedAt(),
+			"total_requests":   total,
+			"blocked_requests": blocked,
+			"endpoints":        endpoints,
+		},
+	})
+}
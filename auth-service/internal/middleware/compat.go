@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Operations disabled in compat mode
+const (
+	CompatOperationRegister       = "register"
+	CompatOperationPasswordChange = "password_change"
+	CompatOperationPasswordReset  = "password_reset"
+)
+
+// compatGuidance tells callers where each disabled operation moved to
+var compatGuidance = map[string]string{
+	CompatOperationRegister:       "Register users in Keycloak through tenant-service onboarding or auth-bff",
+	CompatOperationPasswordChange: "Change passwords through the Keycloak account console via auth-bff",
+	CompatOperationPasswordReset:  "Reset passwords with the Keycloak forgot-password flow via auth-bff",
+}
+
+// overflowConsumer collects requests from consumers beyond MaxConsumersPerEndpoint
+const overflowConsumer = "other"
+
+// CompatConfig holds configuration for the read-only compat mode of the deprecated service
+type CompatConfig struct {
+	// Enabled disables registration and password changes; token validation and login keep working
+	Enabled bool
+	// MigrationGuideURL is returned to callers of disabled endpoints
+	MigrationGuideURL string
+	// MaxConsumersPerEndpoint bounds the distinct consumers tracked per endpoint
+	MaxConsumersPerEndpoint int
+}
+
+// CompatMiddleware enforces compat mode and records per-endpoint usage so the remaining
+// consumers of auth-service can be tracked down before it is decommissioned
+type CompatMiddleware struct {
+	config    CompatConfig
+	logger    *logrus.Logger
+	startedAt time.Time
+	usage     map[string]*endpointUsage
+	mu        sync.Mutex
+}
+
+// endpointUsage accumulates requests to one route
+type endpointUsage struct {
+	method      string
+	path        string
+	requests    int64
+	blocked     int64
+	firstSeenAt time.Time
+	lastSeenAt  time.Time
+	consumers   map[string]int64
+}
+
+// EndpointUsage is a snapshot of the requests made to one route
+type EndpointUsage struct {
+	Method      string          `json:"method"`
+	Path        string          `json:"path"`
+	Requests    int64           `json:"requests"`
+	Blocked     int64           `json:"blocked"`
+	FirstSeenAt time.Time       `json:"first_seen_at"`
+	LastSeenAt  time.Time       `json:"last_seen_at"`
+	Consumers   []ConsumerUsage `json:"consumers"`
+}
+
+// ConsumerUsage counts the requests one consumer made to a route
+type ConsumerUsage struct {
+	Consumer string `json:"consumer"`
+	Requests int64  `json:"requests"`
+}
+
+// NewCompatMiddleware creates a new compat mode middleware instance
+func NewCompatMiddleware(config CompatConfig, logger *logrus.Logger) *CompatMiddleware {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	}
+	if config.MaxConsumersPerEndpoint <= 0 {
+		config.MaxConsumersPerEndpoint = 50
+	}
+
+	return &CompatMiddleware{
+		config:    config,
+		logger:    logger,
+		startedAt: time.Now(),
+		usage:     make(map[string]*endpointUsage),
+	}
+}
+
+// Enabled reports whether compat mode is enforced
+func (cm *CompatMiddleware) Enabled() bool {
+	return cm.config.Enabled
+}
+
+// TrackUsage records every request per route and consumer
+// Health probes are skipped so the numbers only show real consumers
+func (cm *CompatMiddleware) TrackUsage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cm.config.Enabled {
+			c.Header("Deprecation", "true")
+			if cm.config.MigrationGuideURL != "" {
+				c.Header("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", cm.config.MigrationGuideURL))
+			}
+		}
+
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" || path == "/health" || path == "/ready" {
+			return
+		}
+		cm.record(c.Request.Method, path, consumerID(c), c.GetBool("compat_blocked"))
+	}
+}
+
+// Disable rejects the operation with migration guidance while compat mode is enabled
+func (cm *CompatMiddleware) Disable(operation string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cm.config.Enabled {
+			c.Next()
+			return
+		}
+
+		c.Set("compat_blocked", true)
+		cm.logger.WithFields(logrus.Fields{
+			"operation": operation,
+			"path":      c.FullPath(),
+			"consumer":  consumerID(c),
+		}).Warn("Rejected disabled operation in auth-service compat mode")
+
+		migration := gin.H{
+			"replacement": compatGuidance[operation],
+		}
+		if cm.config.MigrationGuideURL != "" {
+			migration["guide_url"] = cm.config.MigrationGuideURL
+		}
+		c.JSON(http.StatusGone, gin.H{
+			"error":     "This operation is no longer available: auth-service is deprecated and running in read-only compat mode",
+			"code":      "AUTH_SERVICE_READ_ONLY",
+			"operation": operation,
+			"migration": migration,
+		})
+		c.Abort()
+	}
+}
+
+// Usage returns a snapshot of the recorded usage, busiest routes first
+func (cm *CompatMiddleware) Usage() []EndpointUsage {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	result := make([]EndpointUsage, 0, len(cm.usage))
+	for _, usage := range cm.usage {
+		consumers := make([]ConsumerUsage, 0, len(usage.consumers))
+		for consumer, requests := range usage.consumers {
+			consumers = append(consumers, ConsumerUsage{Consumer: consumer, Requests: requests})
+		}
+		sort.Slice(consumers, func(i, j int) bool {
+			return consumers[i].Requests > consumers[j].Requests
+		})
+
+		result = append(result, EndpointUsage{
+			Method:      usage.method,
+			Path:        usage.path,
+			Requests:    usage.requests,
+			Blocked:     usage.blocked,
+			FirstSeenAt: usage.firstSeenAt,
+			LastSeenAt:  usage.lastSeenAt,
+			Consumers:   consumers,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Requests > result[j].Requests
+	})
+	return result
+}
+
+// StartedAt returns when usage tracking started (counters are per instance and reset on restart)
+func (cm *CompatMiddleware) StartedAt() time.Time {
+	return cm.startedAt
+}
+
+// record adds one request to the usage counters
+func (cm *CompatMiddleware) record(method, path, consumer string, blocked bool) {
+	now := time.Now()
+	key := method + " " + path
+
+	cm.mu.Lock()
+	usage, exists := cm.usage[key]
+	if !exists {
+		usage = &endpointUsage{
+			method:      method,
+			path:        path,
+			firstSeenAt: now,
+			consumers:   make(map[string]int64),
+		}
+		cm.usage[key] = usage
+	}
+	usage.requests++
+	if blocked {
+		usage.blocked++
+	}
+	usage.lastSeenAt = now
+
+	_, known := usage.consumers[consumer]
+	if !known && len(usage.consumers) >= cm.config.MaxConsumersPerEndpoint {
+		consumer = overflowConsumer
+		_, known = usage.consumers[consumer]
+	}
+	usage.consumers[consumer]++
+	cm.mu.Unlock()
+
+	// Log each new consumer once so remaining callers show up in aggregated logs across replicas
+	if !known {
+		cm.logger.WithFields(logrus.Fields{
+			"method":   method,
+			"path":     path,
+			"consumer": consumer,
+		}).Info("Deprecated auth-service endpoint used by new consumer")
+	}
+}
+
+// consumerID identifies the caller of a request
+// Internal services are expected to send X-Service-Name; otherwise the User-Agent product is used
+func consumerID(c *gin.Context) string {
+	if service := strings.TrimSpace(c.GetHeader("X-Service-Name")); service != "" {
+		return "service:" + service
+	}
+	userAgent := strings.TrimSpace(c.GetHeader("User-Agent"))
+	if userAgent == "" {
+		return "unknown"
+	}
+	if i := strings.IndexByte(userAgent, ' '); i > 0 {
+		userAgent = userAgent[:i]
+	}
+	return "agent:" + userAgent
+}
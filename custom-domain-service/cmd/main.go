@@ -118,6 +118,15 @@ func main() {
 		log.Warn().Msg("NATS URL not configured, event publishing disabled")
 	}
 
+	// Initialize DNS provider credential cipher (customer API tokens are encrypted at rest)
+	credentialCipher, err := services.NewCredentialCipher(cfg.DNSProviders.CredentialsEncryptionKey)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize DNS provider credential cipher")
+	}
+	if !credentialCipher.Enabled() {
+		log.Warn().Msg("DNS_PROVIDER_CREDENTIALS_ENCRYPTION_KEY not set, DNS provider credentials cannot be saved")
+	}
+
 	// Initialize domain service
 	domainService := services.NewDomainService(
 		cfg,
@@ -129,6 +138,7 @@ func main() {
 		cloudflareClient,
 		redisClient,
 		eventPublisher,
+		credentialCipher,
 	)
 
 	// Initialize handlers
@@ -249,6 +259,8 @@ func runMigrations(db *gorm.DB) error {
 		&models.CustomDomain{},
 		&models.DomainActivity{},
 		&models.DomainHealth{},
+		&models.DomainVerificationMethodChange{},
+		&models.ProviderCredential{},
 	)
 }
 
@@ -336,10 +348,26 @@ func setupRouter(cfg *config.Config, domainHandlers *handlers.DomainHandlers, in
 			domains.GET("/:id/health", domainHandlers.HealthCheck)
 			domains.GET("/:id/activities", domainHandlers.GetActivities)
 
+			// Ownership verification method (DNS record, well-known file or meta tag)
+			domains.PUT("/:id/verification-method", domainHandlers.ChangeVerificationMethod)
+			domains.GET("/:id/verification-method/history", domainHandlers.GetVerificationMethodHistory)
+
 			// CNAME Delegation routes for automatic SSL certificate management
 			domains.GET("/:id/cname-delegation", domainHandlers.GetCNAMEDelegationStatus)
 			domains.POST("/:id/cname-delegation/verify", domainHandlers.VerifyCNAMEDelegation)
 			domains.POST("/:id/cname-delegation/enable", domainHandlers.EnableCNAMEDelegation)
+			domains.POST("/:id/dns-mode/detect", domainHandlers.DetectDNSMode)
+
+			// Create the required records through the customer's DNS provider API
+			domains.POST("/:id/auto-configure", domainHandlers.AutoConfigureDNS)
+		}
+
+		// DNS provider credentials used by auto-configure (authenticated)
+		dnsCredentials := v1.Group("/dns-credentials")
+		{
+			dnsCredentials.POST("", domainHandlers.CreateProviderCredential)
+			dnsCredentials.GET("", domainHandlers.ListProviderCredentials)
+			dnsCredentials.DELETE("/:id", domainHandlers.DeleteProviderCredential)
 		}
 
 		// Internal routes (service-to-service)
@@ -347,6 +375,7 @@ func setupRouter(cfg *config.Config, domainHandlers *handlers.DomainHandlers, in
 		{
 			internal.GET("/resolve", internalHandlers.ResolveDomain)
 			internal.GET("/check", internalHandlers.CheckDomain)
+			internal.POST("/verify-txt", internalHandlers.VerifyTXT)
 		}
 	}
 
@@ -401,6 +430,10 @@ func startWorkers(
 	cleanupWorker := workers.NewCleanupWorker(cfg, repo)
 	go cleanupWorker.Start(ctx)
 
+	// Certificate Expiry Notification Worker
+	certExpiryWorker := workers.NewCertExpiryNotificationWorker(cfg, repo, domainSvc)
+	go certExpiryWorker.Start(ctx)
+
 	log.Info().Msg("Background workers started")
 }
 
@@ -0,0 +1,93 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"custom-domain-service/internal/models"
+)
+
+// ErrDNSZoneNotFound is returned when none of a domain's parent zones is hosted in the provider account
+var ErrDNSZoneNotFound = errors.New("no DNS zone found for domain")
+
+// dnsProviderTimeout bounds each request made to a DNS provider API
+const dnsProviderTimeout = 30 * time.Second
+
+// DNSProviderClient manages records in a customer's DNS zone through their provider's API
+// Record names are fully qualified without a trailing dot; TXT values are unquoted
+type DNSProviderClient interface {
+	// FindZone returns the ID of the most specific public zone in the account that contains domain
+	FindZone(ctx context.Context, domain string) (string, error)
+	// GetRecord returns the values of the record set of the given type at name, or nil if there is none
+	GetRecord(ctx context.Context, zoneID, recordType, name string) ([]string, error)
+	// UpsertRecord makes value the only value of the record set of the given type at name
+	UpsertRecord(ctx context.Context, zoneID, recordType, name, value string, ttl int) error
+}
+
+// DNSProviderCredentials are the decrypted secrets of a provider credential
+type DNSProviderCredentials struct {
+	APIToken           string // cloudflare
+	AccessKeyID        string // route53
+	SecretAccessKey    string // route53
+	ServiceAccountJSON string // google_cloud_dns
+	ProjectID          string // google_cloud_dns; defaults to the service account's project
+}
+
+// NewDNSProviderClient creates the API client for a DNS provider
+func NewDNSProviderClient(provider models.DNSProvider, creds DNSProviderCredentials) (DNSProviderClient, error) {
+	switch provider {
+	case models.DNSProviderCloudflare:
+		return NewCloudflareDNSClient(creds.APIToken), nil
+	case models.DNSProviderRoute53:
+		return NewRoute53Client(creds.AccessKeyID, creds.SecretAccessKey), nil
+	case models.DNSProviderGoogleCloudDNS:
+		return NewGoogleCloudDNSClient(creds.ServiceAccountJSON, creds.ProjectID)
+	default:
+		return nil, fmt.Errorf("unsupported DNS provider %q", provider)
+	}
+}
+
+// candidateZones returns the names a domain's zone may have, most specific first
+// The last label alone is never a customer zone, so a.b.example.com yields a.b.example.com, b.example.com, example.com
+func candidateZones(domain string) []string {
+	parts := splitDomain(strings.TrimSuffix(domain, "."))
+	zones := make([]string, 0, len(parts))
+	for i := 0; i < len(parts)-1; i++ {
+		zones = append(zones, joinDomain(parts[i:]))
+	}
+	return zones
+}
+
+// fqdn returns name with the trailing dot zone files and some provider APIs expect
+func fqdn(name string) string {
+	return strings.TrimSuffix(name, ".") + "."
+}
+
+// quoteTXT quotes a TXT value as providers that take zone file syntax expect
+func quoteTXT(value string) string {
+	return strconv.Quote(value)
+}
+
+// unquoteTXT reverses quoteTXT, joining the strings of a value split into 255 character chunks
+func unquoteTXT(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, `"`) {
+		return value
+	}
+
+	var b strings.Builder
+	for value != "" {
+		chunk, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return value
+		}
+		unquoted, _ := strconv.Unquote(chunk)
+		b.WriteString(unquoted)
+		value = strings.TrimSpace(value[len(chunk):])
+	}
+	return b.String()
+}
@@ -0,0 +1,145 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// CloudflareDNSClient manages records in a customer's Cloudflare zone with their own API token
+// Unlike CloudflareClient it never touches the platform account
+type CloudflareDNSClient struct {
+	apiToken   string
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewCloudflareDNSClient creates a client for a customer API token with Zone:Read and DNS:Edit permissions
+func NewCloudflareDNSClient(apiToken string) *CloudflareDNSClient {
+	return &CloudflareDNSClient{
+		apiToken:   apiToken,
+		httpClient: &http.Client{Timeout: dnsProviderTimeout},
+		baseURL:    "https://api.cloudflare.com/client/v4",
+	}
+}
+
+// FindZone walks up the domain hierarchy to the first zone the token can see
+func (c *CloudflareDNSClient) FindZone(ctx context.Context, domain string) (string, error) {
+	for _, zoneName := range candidateZones(domain) {
+		var result ZoneResponse
+		if err := c.do(ctx, http.MethodGet, "/zones?name="+url.QueryEscape(zoneName), nil, &result); err != nil {
+			return "", err
+		}
+		if len(result.Result) > 0 {
+			return result.Result[0].ID, nil
+		}
+	}
+	return "", ErrDNSZoneNotFound
+}
+
+// GetRecord returns the contents of the records of the given type at name
+func (c *CloudflareDNSClient) GetRecord(ctx context.Context, zoneID, recordType, name string) ([]string, error) {
+	records, err := c.listRecords(ctx, zoneID, recordType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, record := range records {
+		values = append(values, record.Content)
+	}
+	return values, nil
+}
+
+// UpsertRecord updates the first record of the given type at name, or creates one, and deletes the rest
+// Records are created unproxied (grey cloud) so verification and certificate issuance see our targets
+func (c *CloudflareDNSClient) UpsertRecord(ctx context.Context, zoneID, recordType, name, value string, ttl int) error {
+	existing, err := c.listRecords(ctx, zoneID, recordType, name)
+	if err != nil {
+		return err
+	}
+
+	record := DNSRecord{
+		Type:    recordType,
+		Name:    name,
+		Content: value,
+		TTL:     ttl,
+		Proxied: false,
+	}
+
+	if len(existing) == 0 {
+		return c.do(ctx, http.MethodPost, fmt.Sprintf("/zones/%s/dns_records", zoneID), record, &DNSRecordResponse{})
+	}
+
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/zones/%s/dns_records/%s", zoneID, existing[0].ID), record, &DNSRecordResponse{}); err != nil {
+		return err
+	}
+	for _, extra := range existing[1:] {
+		if err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/zones/%s/dns_records/%s", zoneID, extra.ID), nil, &DNSRecordResponse{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CloudflareDNSClient) listRecords(ctx context.Context, zoneID, recordType, name string) ([]DNSRecord, error) {
+	query := url.Values{"type": {recordType}, "name": {name}}
+	var result DNSRecordsListResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/zones/%s/dns_records?%s", zoneID, query.Encode()), nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Result, nil
+}
+
+// do sends a request and decodes the response envelope into result, turning API errors into Go errors
+func (c *CloudflareDNSClient) do(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var envelope struct {
+		Success bool              `json:"success"`
+		Errors  []CloudflareError `json:"errors"`
+	}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return fmt.Errorf("failed to parse response (status %d): %w", resp.StatusCode, err)
+	}
+	if !envelope.Success {
+		if len(envelope.Errors) > 0 {
+			return fmt.Errorf("cloudflare API error: %s", envelope.Errors[0].Message)
+		}
+		return fmt.Errorf("cloudflare API request failed with status %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	// googleCloudDNSScope grants read/write access to Cloud DNS
+	googleCloudDNSScope = "https://www.googleapis.com/auth/ndev.clouddns.readwrite"
+	// googleTokenURL is always used to mint tokens; the key's own token_uri is customer input and is ignored
+	googleTokenURL = "https://oauth2.googleapis.com/token"
+)
+
+// GoogleServiceAccountKey is the subset of a service account JSON key needed to authenticate
+type GoogleServiceAccountKey struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+}
+
+// ParseGoogleServiceAccountKey parses and checks a service account JSON key
+func ParseGoogleServiceAccountKey(serviceAccountJSON string) (*GoogleServiceAccountKey, error) {
+	var key GoogleServiceAccountKey
+	if err := json.Unmarshal([]byte(serviceAccountJSON), &key); err != nil {
+		return nil, fmt.Errorf("invalid service account JSON: %w", err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("service account JSON must contain client_email and private_key")
+	}
+	return &key, nil
+}
+
+// GoogleCloudDNSClient manages records in a customer's Cloud DNS managed zone with a service account key
+// The service account needs the DNS Administrator role (roles/dns.admin) on the project
+type GoogleCloudDNSClient struct {
+	projectID  string
+	httpClient *http.Client
+	baseURL    string
+}
+
+type googleManagedZonesResponse struct {
+	ManagedZones []struct {
+		Name       string `json:"name"`
+		DNSName    string `json:"dnsName"`
+		Visibility string `json:"visibility"`
+	} `json:"managedZones"`
+}
+
+type googleResourceRecordSet struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     int      `json:"ttl"`
+	RRDatas []string `json:"rrdatas"`
+}
+
+type googleRecordSetsResponse struct {
+	RRSets []googleResourceRecordSet `json:"rrsets"`
+}
+
+type googleChange struct {
+	Additions []googleResourceRecordSet `json:"additions,omitempty"`
+	Deletions []googleResourceRecordSet `json:"deletions,omitempty"`
+}
+
+type googleErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewGoogleCloudDNSClient creates a client for a service account key
+// projectID defaults to the project the service account belongs to
+func NewGoogleCloudDNSClient(serviceAccountJSON, projectID string) (*GoogleCloudDNSClient, error) {
+	key, err := ParseGoogleServiceAccountKey(serviceAccountJSON)
+	if err != nil {
+		return nil, err
+	}
+	if projectID == "" {
+		projectID = key.ProjectID
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("project ID is required for Google Cloud DNS")
+	}
+
+	jwtConfig := &jwt.Config{
+		Email:      key.ClientEmail,
+		PrivateKey: []byte(key.PrivateKey),
+		TokenURL:   googleTokenURL,
+		Scopes:     []string{googleCloudDNSScope},
+	}
+
+	// Token requests use a client with the same timeout as API requests
+	tokenCtx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: dnsProviderTimeout})
+	httpClient := jwtConfig.Client(tokenCtx)
+	httpClient.Timeout = dnsProviderTimeout
+
+	return &GoogleCloudDNSClient{
+		projectID:  projectID,
+		httpClient: httpClient,
+		baseURL:    "https://dns.googleapis.com/dns/v1",
+	}, nil
+}
+
+// FindZone walks up the domain hierarchy to the first public managed zone in the project
+// Cloud DNS addresses zones by name, so the zone name is returned as its ID
+func (c *GoogleCloudDNSClient) FindZone(ctx context.Context, domain string) (string, error) {
+	for _, zoneName := range candidateZones(domain) {
+		query := url.Values{"dnsName": {fqdn(zoneName)}}
+		var result googleManagedZonesResponse
+		if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%s/managedZones?%s", c.projectID, query.Encode()), nil, &result); err != nil {
+			return "", err
+		}
+		for _, zone := range result.ManagedZones {
+			if zone.Visibility != "private" {
+				return zone.Name, nil
+			}
+		}
+	}
+	return "", ErrDNSZoneNotFound
+}
+
+// GetRecord returns the values of the record set of the given type at name
+func (c *GoogleCloudDNSClient) GetRecord(ctx context.Context, zoneID, recordType, name string) ([]string, error) {
+	set, err := c.getRecordSet(ctx, zoneID, recordType, name)
+	if err != nil || set == nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(set.RRDatas))
+	for _, value := range set.RRDatas {
+		if recordType == "TXT" {
+			value = unquoteTXT(value)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// UpsertRecord replaces the record set of the given type at name in a single change
+// Cloud DNS has no upsert, so an existing set is deleted (it must match exactly) in the same change
+func (c *GoogleCloudDNSClient) UpsertRecord(ctx context.Context, zoneID, recordType, name, value string, ttl int) error {
+	existing, err := c.getRecordSet(ctx, zoneID, recordType, name)
+	if err != nil {
+		return err
+	}
+
+	switch recordType {
+	case "TXT":
+		value = quoteTXT(value)
+	case "CNAME":
+		value = fqdn(value)
+	}
+
+	change := googleChange{
+		Additions: []googleResourceRecordSet{{
+			Name:    fqdn(name),
+			Type:    recordType,
+			TTL:     ttl,
+			RRDatas: []string{value},
+		}},
+	}
+	if existing != nil {
+		change.Deletions = []googleResourceRecordSet{*existing}
+	}
+
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/projects/%s/managedZones/%s/changes", c.projectID, zoneID), change, nil)
+}
+
+func (c *GoogleCloudDNSClient) getRecordSet(ctx context.Context, zoneID, recordType, name string) (*googleResourceRecordSet, error) {
+	query := url.Values{"name": {fqdn(name)}, "type": {recordType}}
+	var result googleRecordSetsResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%s/managedZones/%s/rrsets?%s", c.projectID, zoneID, query.Encode()), nil, &result); err != nil {
+		return nil, err
+	}
+
+	for i := range result.RRSets {
+		if strings.EqualFold(result.RRSets[i].Name, fqdn(name)) && result.RRSets[i].Type == recordType {
+			return &result.RRSets[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// do sends a request and decodes the JSON response into result
+func (c *GoogleCloudDNSClient) do(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var apiErr googleErrorResponse
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("google cloud DNS API error: %s", apiErr.Error.Message)
+		}
+		return fmt.Errorf("google cloud DNS API request failed with status %d", resp.StatusCode)
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	// route53XMLNamespace is the namespace of Route 53 API request and response documents
+	route53XMLNamespace = "https://route53.amazonaws.com/doc/2013-04-01/"
+	// route53SigningRegion is the region Route 53, a global service, signs requests for
+	route53SigningRegion = "us-east-1"
+)
+
+// Route53Client manages records in a customer's Route 53 hosted zone with an IAM access key
+// The key needs route53:ListHostedZonesByName, route53:ListResourceRecordSets and route53:ChangeResourceRecordSets
+type Route53Client struct {
+	credentials aws.Credentials
+	signer      *v4.Signer
+	httpClient  *http.Client
+	baseURL     string
+}
+
+// NewRoute53Client creates a client for an IAM access key
+func NewRoute53Client(accessKeyID, secretAccessKey string) *Route53Client {
+	return &Route53Client{
+		credentials: aws.Credentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey},
+		signer:      v4.NewSigner(),
+		httpClient:  &http.Client{Timeout: dnsProviderTimeout},
+		baseURL:     "https://route53.amazonaws.com/2013-04-01",
+	}
+}
+
+type route53HostedZonesResponse struct {
+	HostedZones []struct {
+		ID     string `xml:"Id"`
+		Name   string `xml:"Name"`
+		Config struct {
+			PrivateZone bool `xml:"PrivateZone"`
+		} `xml:"Config"`
+	} `xml:"HostedZones>HostedZone"`
+}
+
+type route53ResourceRecordSet struct {
+	Name            string   `xml:"Name"`
+	Type            string   `xml:"Type"`
+	TTL             int      `xml:"TTL,omitempty"`
+	ResourceRecords []string `xml:"ResourceRecords>ResourceRecord>Value"`
+}
+
+type route53RecordSetsResponse struct {
+	ResourceRecordSets []route53ResourceRecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+}
+
+type route53Change struct {
+	Action            string                   `xml:"Action"`
+	ResourceRecordSet route53ResourceRecordSet `xml:"ResourceRecordSet"`
+}
+
+type route53ChangeRequest struct {
+	XMLName xml.Name        `xml:"ChangeResourceRecordSetsRequest"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Changes []route53Change `xml:"ChangeBatch>Changes>Change"`
+}
+
+type route53ErrorResponse struct {
+	Errors []struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+// FindZone looks up each candidate zone name and returns the first public hosted zone with that exact name
+func (c *Route53Client) FindZone(ctx context.Context, domain string) (string, error) {
+	for _, zoneName := range candidateZones(domain) {
+		query := url.Values{"dnsname": {zoneName}, "maxitems": {"1"}}
+		var result route53HostedZonesResponse
+		if err := c.do(ctx, http.MethodGet, "/hostedzonesbyname?"+query.Encode(), nil, &result); err != nil {
+			return "", err
+		}
+		// Zones are listed in order starting at dnsname, so the first one is only a match if the names are equal
+		if len(result.HostedZones) > 0 {
+			zone := result.HostedZones[0]
+			if strings.EqualFold(zone.Name, fqdn(zoneName)) && !zone.Config.PrivateZone {
+				return strings.TrimPrefix(zone.ID, "/hostedzone/"), nil
+			}
+		}
+	}
+	return "", ErrDNSZoneNotFound
+}
+
+// GetRecord returns the values of the record set of the given type at name
+func (c *Route53Client) GetRecord(ctx context.Context, zoneID, recordType, name string) ([]string, error) {
+	query := url.Values{"name": {fqdn(name)}, "type": {recordType}, "maxitems": {"1"}}
+	var result route53RecordSetsResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/hostedzone/%s/rrset?%s", zoneID, query.Encode()), nil, &result); err != nil {
+		return nil, err
+	}
+
+	// The listing starts at name and type but continues past them, so check the first set is the one asked for
+	if len(result.ResourceRecordSets) == 0 {
+		return nil, nil
+	}
+	set := result.ResourceRecordSets[0]
+	// Route 53 returns the wildcard label escaped as \052
+	setName := strings.ReplaceAll(set.Name, `\052`, "*")
+	if !strings.EqualFold(setName, fqdn(name)) || set.Type != recordType {
+		return nil, nil
+	}
+
+	values := make([]string, 0, len(set.ResourceRecords))
+	for _, value := range set.ResourceRecords {
+		if recordType == "TXT" {
+			value = unquoteTXT(value)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// UpsertRecord replaces the record set of the given type at name with a single value
+func (c *Route53Client) UpsertRecord(ctx context.Context, zoneID, recordType, name, value string, ttl int) error {
+	if recordType == "TXT" {
+		value = quoteTXT(value)
+	}
+
+	request := route53ChangeRequest{
+		Xmlns: route53XMLNamespace,
+		Changes: []route53Change{{
+			Action: "UPSERT",
+			ResourceRecordSet: route53ResourceRecordSet{
+				Name:            fqdn(name),
+				Type:            recordType,
+				TTL:             ttl,
+				ResourceRecords: []string{value},
+			},
+		}},
+	}
+
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/hostedzone/%s/rrset/", zoneID), append([]byte(xml.Header), body...), nil)
+}
+
+// do signs and sends a request and decodes the XML response into result
+func (c *Route53Client) do(ctx context.Context, method, path string, body []byte, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	payloadHash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, c.credentials, req, hex.EncodeToString(payloadHash[:]), "route53", route53SigningRegion, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var apiErr route53ErrorResponse
+		if xml.Unmarshal(respBody, &apiErr) == nil && len(apiErr.Errors) > 0 {
+			return fmt.Errorf("route53 API error: %s: %s", apiErr.Errors[0].Code, apiErr.Errors[0].Message)
+		}
+		return fmt.Errorf("route53 API request failed with status %d", resp.StatusCode)
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := xml.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -72,6 +72,9 @@ type CertificateResult struct {
 	ExpiresAt   *time.Time
 	Error       string
 	IsReady     bool
+
+	// ChallengeType is the ACME challenge the certificate is issued with (http-01 or dns-01)
+	ChallengeType models.CertValidationMethod
 }
 
 // CreateCertificate creates a cert-manager Certificate resource for the domain
@@ -105,8 +108,9 @@ func (k *KubernetesClient) CreateCertificate(ctx context.Context, domain *models
 				"tesserix.app/domain-id":       domain.ID.String(),
 			},
 			Annotations: map[string]string{
-				"tesserix.app/domain":     domain.Domain,
-				"tesserix.app/created-at": time.Now().UTC().Format(time.RFC3339),
+				"tesserix.app/domain":         domain.Domain,
+				"tesserix.app/created-at":     time.Now().UTC().Format(time.RFC3339),
+				"tesserix.app/challenge-type": string(models.CertValidationHTTP01),
 			},
 		},
 		Spec: certmanagerv1.CertificateSpec{
@@ -128,6 +132,8 @@ func (k *KubernetesClient) CreateCertificate(ctx context.Context, domain *models
 	if err == nil {
 		// Certificate exists, update it
 		existing.Spec = cert.Spec
+		existing.Labels = cert.Labels
+		existing.Annotations = cert.Annotations
 		_, err = k.certmanagerClient.CertmanagerV1().Certificates(k.cfg.SSL.CertificateNamespace).Update(ctx, existing, metav1.UpdateOptions{})
 		if err != nil {
 			result.Error = fmt.Sprintf("failed to update certificate: %v", err)
@@ -147,6 +153,7 @@ func (k *KubernetesClient) CreateCertificate(ctx context.Context, domain *models
 	}
 
 	result.Status = models.SSLStatusProvisioning
+	result.ChallengeType = models.CertValidationHTTP01
 	return result, nil
 }
 
@@ -232,6 +239,7 @@ func (k *KubernetesClient) CreateCertificateWithCNAMEDelegation(ctx context.Cont
 	}
 
 	result.Status = models.SSLStatusProvisioning
+	result.ChallengeType = models.CertValidationDNS01
 	return result, nil
 }
 
@@ -248,6 +256,11 @@ func (k *KubernetesClient) GetCertificateStatus(ctx context.Context, domain *mod
 		return result, nil
 	}
 
+	result.ChallengeType = models.CertValidationMethod(cert.Annotations["tesserix.app/challenge-type"])
+	if result.ChallengeType == "" {
+		result.ChallengeType = models.CertValidationHTTP01
+	}
+
 	// Check certificate conditions
 	for _, condition := range cert.Status.Conditions {
 		if condition.Type == certmanagerv1.CertificateConditionReady {
@@ -266,6 +279,18 @@ func (k *KubernetesClient) GetCertificateStatus(ctx context.Context, domain *mod
 		}
 	}
 
+	// cert-manager keeps a certificate whose issuance failed not-ready and retries it with
+	// an exponential backoff of an hour or more, so report the failure instead of waiting
+	if !result.IsReady && cert.Status.LastFailureTime != nil {
+		result.Status = models.SSLStatusFailed
+		for _, condition := range cert.Status.Conditions {
+			if condition.Type == certmanagerv1.CertificateConditionIssuing && condition.Message != "" {
+				result.Error = condition.Message
+				break
+			}
+		}
+	}
+
 	return result, nil
 }
 
@@ -724,11 +749,14 @@ func generateResourceName(domain, suffix string) string {
 }
 
 // sanitizeDomain converts domain to a valid K8s name
+// The wildcard label becomes "wildcard" so *.shop.example.com does not collide with shop.example.com
 func sanitizeDomain(domain string) string {
 	// Replace dots with dashes
 	name := ""
 	for _, r := range domain {
-		if r == '.' {
+		if r == '*' {
+			name += "wildcard"
+		} else if r == '.' {
 			name += "-"
 		} else if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
 			name += string(r)
@@ -36,6 +36,9 @@ type TenantInfo struct {
 	ID               uuid.UUID `json:"id"`
 	Slug             string    `json:"slug"`
 	Name             string    `json:"name"`
+	DisplayName      string    `json:"displayName"`
+	BillingEmail     string    `json:"billingEmail"`
+	AdminURL         string    `json:"admin_url"`
 	Status           string    `json:"status"`
 	Subdomain        string    `json:"subdomain"`
 	CustomDomainSlot int       `json:"custom_domain_slot"`
@@ -19,6 +19,7 @@ type Config struct {
 	SSL             SSLConfig             `json:"ssl"`
 	CNAMEDelegation CNAMEDelegationConfig `json:"cname_delegation"`
 	Cloudflare      CloudflareConfig      `json:"cloudflare"`
+	DNSProviders    DNSProvidersConfig    `json:"dns_providers"`
 	Limits       LimitsConfig       `json:"limits"`
 	Tenant       TenantConfig       `json:"tenant"`
 	Workers      WorkersConfig      `json:"workers"`
@@ -77,6 +78,13 @@ type DNSConfig struct {
 	ProxyDomain        string `json:"proxy_domain"`
 	ProxyIP            string `json:"proxy_ip"`
 	PlatformDomain     string `json:"platform_domain"` // Base domain for tenant subdomains (e.g., tesserix.app)
+
+	// CloudflareIPRanges are Cloudflare's edge ranges (https://www.cloudflare.com/ips/)
+	// A domain resolving into them is behind the Cloudflare proxy (orange cloud)
+	CloudflareIPRanges []string `json:"cloudflare_ip_ranges"`
+
+	// HTTPVerificationTimeout bounds each fetch made for http_file and meta_tag verification
+	HTTPVerificationTimeout time.Duration `json:"http_verification_timeout"`
 }
 
 type SSLConfig struct {
@@ -86,6 +94,9 @@ type SSLConfig struct {
 	CNAMEDelegationIssuerName  string `json:"cname_delegation_issuer_name"`   // Issuer for DNS-01 challenges (CNAME-delegated domains)
 	RenewalDaysBefore          int    `json:"renewal_days_before"`
 	CertificateNamespace       string `json:"certificate_namespace"`
+
+	// ExpiryNotifyDays are the days before certificate expiry at which tenant owners are notified
+	ExpiryNotifyDays []int `json:"expiry_notify_days"`
 }
 
 // CNAMEDelegationConfig holds CNAME delegation configuration for automatic certificate management
@@ -98,6 +109,13 @@ type CNAMEDelegationConfig struct {
 	MaxAttempts          int           `json:"max_attempts"`          // Max verification attempts before marking as failed
 }
 
+// DNSProvidersConfig holds configuration for creating records through customers' DNS provider APIs
+type DNSProvidersConfig struct {
+	// CredentialsEncryptionKey is a base64 encoded 32-byte AES key for stored provider credentials
+	// Credentials cannot be saved while it is unset
+	CredentialsEncryptionKey string `json:"-"`
+}
+
 type LimitsConfig struct {
 	MaxDomainsPerTenant          int `json:"max_domains_per_tenant"`
 	MaxVerificationAttemptsHour  int `json:"max_verification_attempts_hour"`
@@ -109,10 +127,11 @@ type TenantConfig struct {
 }
 
 type WorkersConfig struct {
-	DNSVerificationInterval time.Duration `json:"dns_verification_interval"`
-	CertMonitorInterval     time.Duration `json:"cert_monitor_interval"`
-	HealthCheckInterval     time.Duration `json:"health_check_interval"`
-	CleanupInterval         time.Duration `json:"cleanup_interval"`
+	DNSVerificationInterval  time.Duration `json:"dns_verification_interval"`
+	CertMonitorInterval      time.Duration `json:"cert_monitor_interval"`
+	HealthCheckInterval      time.Duration `json:"health_check_interval"`
+	CleanupInterval          time.Duration `json:"cleanup_interval"`
+	CertExpiryNotifyInterval time.Duration `json:"cert_expiry_notify_interval"`
 }
 
 type CloudflareConfig struct {
@@ -173,10 +192,12 @@ func NewConfig() *Config {
 			SharedAuthPolicySelector:  getEnv("ISTIO_SHARED_AUTH_POLICY_SELECTOR", "custom-ingressgateway"),
 		},
 		DNS: DNSConfig{
-			VerificationDomain: getEnv("DNS_VERIFICATION_DOMAIN", "tesserix.app"),
-			ProxyDomain:        getEnv("DNS_PROXY_DOMAIN", "proxy.tesserix.app"),
-			ProxyIP:            getEnv("DNS_PROXY_IP", ""),
-			PlatformDomain:     getEnv("DNS_PLATFORM_DOMAIN", "tesserix.app"),
+			VerificationDomain:      getEnv("DNS_VERIFICATION_DOMAIN", "tesserix.app"),
+			ProxyDomain:             getEnv("DNS_PROXY_DOMAIN", "proxy.tesserix.app"),
+			ProxyIP:                 getEnv("DNS_PROXY_IP", ""),
+			PlatformDomain:          getEnv("DNS_PLATFORM_DOMAIN", "tesserix.app"),
+			CloudflareIPRanges:      getStringSliceEnv("DNS_CLOUDFLARE_IP_RANGES", defaultCloudflareIPRanges),
+			HTTPVerificationTimeout: getDurationEnv("DNS_HTTP_VERIFICATION_TIMEOUT", 10*time.Second),
 		},
 		SSL: SSLConfig{
 			IssuerName:                getEnv("SSL_ISSUER_NAME", "letsencrypt-prod"),
@@ -185,6 +206,7 @@ func NewConfig() *Config {
 			CNAMEDelegationIssuerName: getEnv("SSL_CNAME_DELEGATION_ISSUER_NAME", "letsencrypt-prod-cname-delegation"),
 			RenewalDaysBefore:         getIntEnv("SSL_RENEWAL_DAYS_BEFORE", 30),
 			CertificateNamespace:      getEnv("SSL_CERTIFICATE_NAMESPACE", "istio-system"),
+			ExpiryNotifyDays:          getIntSliceEnv("SSL_EXPIRY_NOTIFY_DAYS", []int{30, 14, 7, 1}),
 		},
 		CNAMEDelegation: CNAMEDelegationConfig{
 			Enabled:              getBoolEnv("CNAME_DELEGATION_ENABLED", false),
@@ -205,6 +227,9 @@ func NewConfig() *Config {
 			SaaSZoneID:     getEnv("CLOUDFLARE_SAAS_ZONE_ID", ""),     // Zone ID of tesserix.app
 			FallbackOrigin: getEnv("CLOUDFLARE_FALLBACK_ORIGIN", ""), // e.g., customers.tesserix.app
 		},
+		DNSProviders: DNSProvidersConfig{
+			CredentialsEncryptionKey: getEnv("DNS_PROVIDER_CREDENTIALS_ENCRYPTION_KEY", ""),
+		},
 		Limits: LimitsConfig{
 			MaxDomainsPerTenant:            getIntEnv("MAX_DOMAINS_PER_TENANT", 5),
 			MaxVerificationAttemptsHour:    getIntEnv("MAX_VERIFICATION_ATTEMPTS_HOUR", 10),
@@ -214,10 +239,11 @@ func NewConfig() *Config {
 			ServiceURL: getEnv("TENANT_SERVICE_URL", "http://tenant-service:8080"),
 		},
 		Workers: WorkersConfig{
-			DNSVerificationInterval: getDurationEnv("DNS_VERIFICATION_INTERVAL", 5*time.Minute),
-			CertMonitorInterval:     getDurationEnv("CERT_MONITOR_INTERVAL", 24*time.Hour),
-			HealthCheckInterval:     getDurationEnv("HEALTH_CHECK_INTERVAL", 15*time.Minute),
-			CleanupInterval:         getDurationEnv("CLEANUP_INTERVAL", 24*time.Hour),
+			DNSVerificationInterval:  getDurationEnv("DNS_VERIFICATION_INTERVAL", 5*time.Minute),
+			CertMonitorInterval:      getDurationEnv("CERT_MONITOR_INTERVAL", 24*time.Hour),
+			HealthCheckInterval:      getDurationEnv("HEALTH_CHECK_INTERVAL", 15*time.Minute),
+			CleanupInterval:          getDurationEnv("CLEANUP_INTERVAL", 24*time.Hour),
+			CertExpiryNotifyInterval: getDurationEnv("CERT_EXPIRY_NOTIFY_INTERVAL", 6*time.Hour),
 		},
 	}
 }
@@ -257,6 +283,16 @@ func buildRedisConfig() RedisConfig {
 	}
 }
 
+// defaultCloudflareIPRanges is Cloudflare's published list of edge IP ranges
+var defaultCloudflareIPRanges = []string{
+	"173.245.48.0/20", "103.21.244.0/22", "103.22.200.0/22", "103.31.4.0/22",
+	"141.101.64.0/18", "108.162.192.0/18", "190.93.240.0/20", "188.114.96.0/20",
+	"197.234.240.0/22", "198.41.128.0/17", "162.158.0.0/15", "104.16.0.0/13",
+	"104.24.0.0/14", "172.64.0.0/13", "131.0.72.0/22",
+	"2400:cb00::/32", "2606:4700::/32", "2803:f800::/32", "2405:b500::/32",
+	"2405:8100::/32", "2a06:98c0::/29", "2c0f:f248::/32",
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -305,6 +341,21 @@ func getStringSliceEnv(key string, fallback []string) []string {
 	return fallback
 }
 
+func getIntSliceEnv(key string, fallback []int) []int {
+	if value := os.Getenv(key); value != "" {
+		values := make([]int, 0)
+		for _, part := range splitAndTrim(value, ",") {
+			if parsed, err := strconv.Atoi(part); err == nil && parsed > 0 {
+				values = append(values, parsed)
+			}
+		}
+		if len(values) > 0 {
+			return values
+		}
+	}
+	return fallback
+}
+
 func splitAndTrim(s, sep string) []string {
 	parts := make([]string, 0)
 	for _, part := range splitString(s, sep) {
@@ -73,6 +73,30 @@ func (h *DomainHandlers) CreateDomain(c *gin.Context) {
 				Code:    "LIMIT_EXCEEDED",
 				Message: "You have reached the maximum number of domains allowed for your account",
 			})
+		case repository.ErrWildcardHTTPVerification:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "unsupported verification method",
+				Code:    "UNSUPPORTED_VERIFICATION_METHOD",
+				Message: "Wildcard domains must be verified with a TXT or CNAME record",
+			})
+		case repository.ErrWildcardNotSupported:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "wildcard domains not supported",
+				Code:    "WILDCARD_NOT_SUPPORTED",
+				Message: "Wildcard domains are not available on this platform yet",
+			})
+		case repository.ErrWildcardHTTPChallenge:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "unsupported challenge type",
+				Code:    "UNSUPPORTED_CHALLENGE_TYPE",
+				Message: "Wildcard certificates can only be issued with the dns-01 challenge",
+			})
+		case repository.ErrDNS01NotSupported:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "unsupported challenge type",
+				Code:    "UNSUPPORTED_CHALLENGE_TYPE",
+				Message: "DNS-01 certificates are not available on this platform yet",
+			})
 		default:
 			log.Error().Err(err).Msg("Failed to create domain")
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -218,18 +242,31 @@ func (h *DomainHandlers) UpdateDomain(c *gin.Context) {
 
 	domain, err := h.domainService.UpdateDomain(c.Request.Context(), tenantID, domainID, &req)
 	if err != nil {
-		if err == repository.ErrDomainNotFound {
+		switch err {
+		case repository.ErrDomainNotFound:
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Error: "domain not found",
 				Code:  "NOT_FOUND",
 			})
-			return
+		case repository.ErrWildcardHTTPChallenge:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "unsupported challenge type",
+				Code:    "UNSUPPORTED_CHALLENGE_TYPE",
+				Message: "Wildcard certificates can only be issued with the dns-01 challenge",
+			})
+		case repository.ErrDNS01NotSupported:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "unsupported challenge type",
+				Code:    "UNSUPPORTED_CHALLENGE_TYPE",
+				Message: "DNS-01 certificates are not available on this platform yet",
+			})
+		default:
+			log.Error().Err(err).Msg("Failed to update domain")
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "failed to update domain",
+				Code:  "INTERNAL_ERROR",
+			})
 		}
-		log.Error().Err(err).Msg("Failed to update domain")
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error: "failed to update domain",
-			Code:  "INTERNAL_ERROR",
-		})
 		return
 	}
 
@@ -760,6 +797,54 @@ func (h *DomainHandlers) VerifyCNAMEDelegation(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
+// DetectDNSMode handles POST /api/v1/domains/:id/dns-mode/detect
+// @Summary Detect DNS mode
+// @Description Detect whether the domain is proxied through Cloudflare and return mode-specific setup instructions
+// @Tags domains
+// @Produce json
+// @Param id path string true "Domain ID"
+// @Success 200 {object} models.DNSModeResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/domains/{id}/dns-mode/detect [post]
+func (h *DomainHandlers) DetectDNSMode(c *gin.Context) {
+	tenantID, _, err := getTenantAndUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "unauthorized",
+			Code:  "UNAUTHORIZED",
+		})
+		return
+	}
+
+	domainID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "invalid domain ID",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	mode, err := h.domainService.DetectDNSMode(c.Request.Context(), tenantID, domainID)
+	if err != nil {
+		if err == repository.ErrDomainNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "domain not found",
+				Code:  "NOT_FOUND",
+			})
+			return
+		}
+		log.Error().Err(err).Msg("Failed to detect DNS mode")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "DNS mode detection failed",
+			Code:  "DETECTION_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, mode)
+}
+
 // EnableCNAMEDelegation handles POST /api/v1/domains/:id/cname-delegation/enable
 // @Summary Enable CNAME delegation
 // @Description Enable CNAME delegation for a domain (switches from HTTP-01 to DNS-01 for certificates)
@@ -816,6 +901,129 @@ func (h *DomainHandlers) EnableCNAMEDelegation(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
+// ChangeVerificationMethod handles PUT /api/v1/domains/:id/verification-method
+// @Summary Change verification method
+// @Description Switch how ownership of an unverified domain is proven (txt, cname, http_file, meta_tag)
+// @Tags domains
+// @Accept json
+// @Produce json
+// @Param id path string true "Domain ID"
+// @Param request body models.ChangeVerificationMethodRequest true "Verification method"
+// @Success 200 {object} models.DNSStatusResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /api/v1/domains/{id}/verification-method [put]
+func (h *DomainHandlers) ChangeVerificationMethod(c *gin.Context) {
+	tenantID, userID, err := getTenantAndUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "unauthorized",
+			Code:  "UNAUTHORIZED",
+		})
+		return
+	}
+
+	domainID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "invalid domain ID",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	var req models.ChangeVerificationMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid request",
+			Code:    "INVALID_REQUEST",
+			Message: "method must be one of txt, cname, http_file, meta_tag",
+		})
+		return
+	}
+
+	status, err := h.domainService.ChangeVerificationMethod(c.Request.Context(), tenantID, domainID, req.Method, userID)
+	if err != nil {
+		switch err {
+		case repository.ErrDomainNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "domain not found",
+				Code:  "NOT_FOUND",
+			})
+		case repository.ErrDomainAlreadyVerified:
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "domain already verified",
+				Code:    "ALREADY_VERIFIED",
+				Message: "The verification method can only be changed before the domain is verified",
+			})
+		case repository.ErrWildcardHTTPVerification:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "unsupported verification method",
+				Code:    "UNSUPPORTED_VERIFICATION_METHOD",
+				Message: "Wildcard domains must be verified with a TXT or CNAME record",
+			})
+		default:
+			log.Error().Err(err).Msg("Failed to change verification method")
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "failed to change verification method",
+				Code:  "INTERNAL_ERROR",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// GetVerificationMethodHistory handles GET /api/v1/domains/:id/verification-method/history
+// @Summary Get verification method history
+// @Description Get the verification methods a domain has used, newest first
+// @Tags domains
+// @Produce json
+// @Param id path string true "Domain ID"
+// @Success 200 {array} models.DomainVerificationMethodChange
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/domains/{id}/verification-method/history [get]
+func (h *DomainHandlers) GetVerificationMethodHistory(c *gin.Context) {
+	tenantID, _, err := getTenantAndUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "unauthorized",
+			Code:  "UNAUTHORIZED",
+		})
+		return
+	}
+
+	domainID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "invalid domain ID",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	history, err := h.domainService.GetVerificationMethodHistory(c.Request.Context(), tenantID, domainID)
+	if err != nil {
+		if err == repository.ErrDomainNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "domain not found",
+				Code:  "NOT_FOUND",
+			})
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get verification method history")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "failed to get verification method history",
+			Code:  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 // Helper function to extract tenant ID and user ID from context
 func getTenantAndUserFromContext(c *gin.Context) (uuid.UUID, uuid.UUID, error) {
 	// Get tenant ID from header (set by Istio/JWT middleware)
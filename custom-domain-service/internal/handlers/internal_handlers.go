@@ -108,6 +108,30 @@ func (h *InternalHandlers) CheckDomain(c *gin.Context) {
 	})
 }
 
+// VerifyTXT handles POST /api/v1/internal/verify-txt
+// @Summary Verify TXT record
+// @Description Check a host for a TXT record with the expected value (internal use only)
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param request body models.VerifyTXTRequest true "Host and expected value"
+// @Success 200 {object} models.VerifyTXTResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/internal/verify-txt [post]
+func (h *InternalHandlers) VerifyTXT(c *gin.Context) {
+	var req models.VerifyTXTRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid request",
+			Code:    "INVALID_REQUEST",
+			Message: "host and expected_value are required",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.domainService.CheckTXTRecord(c.Request.Context(), req.Host, req.ExpectedValue)})
+}
+
 // Health handles GET /health
 // @Summary Health check
 // @Description Service health check endpoint
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"custom-domain-service/internal/clients"
+	"custom-domain-service/internal/models"
+	"custom-domain-service/internal/repository"
+	"custom-domain-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// CreateProviderCredential handles POST /api/v1/dns-credentials
+// @Summary Add a DNS provider credential
+// @Description Store an encrypted API credential for the DNS provider hosting the tenant's domains (cloudflare, route53, google_cloud_dns)
+// @Tags dns-credentials
+// @Accept json
+// @Produce json
+// @Param request body models.CreateProviderCredentialRequest true "Provider credential"
+// @Success 201 {object} models.ProviderCredential
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/dns-credentials [post]
+func (h *DomainHandlers) CreateProviderCredential(c *gin.Context) {
+	tenantID, userID, err := getTenantAndUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "unauthorized",
+			Code:  "UNAUTHORIZED",
+		})
+		return
+	}
+
+	var req models.CreateProviderCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid request",
+			Code:    "INVALID_REQUEST",
+			Message: "provider must be one of cloudflare, route53, google_cloud_dns and name is required",
+		})
+		return
+	}
+
+	credential, err := h.domainService.CreateProviderCredential(c.Request.Context(), tenantID, &req, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidProviderCredential):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "invalid credential",
+				Code:    "INVALID_CREDENTIAL",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrCredentialEncryptionDisabled):
+			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Error:   "credential storage unavailable",
+				Code:    "ENCRYPTION_DISABLED",
+				Message: "DNS provider credentials cannot be stored on this platform yet",
+			})
+		default:
+			log.Error().Err(err).Msg("Failed to create DNS provider credential")
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "failed to create DNS provider credential",
+				Code:  "INTERNAL_ERROR",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, credential)
+}
+
+// ListProviderCredentials handles GET /api/v1/dns-credentials
+// @Summary List DNS provider credentials
+// @Description List the tenant's DNS provider credentials; secrets are never returned
+// @Tags dns-credentials
+// @Produce json
+// @Success 200 {array} models.ProviderCredential
+// @Failure 401 {object} models.ErrorResponse
+// @Router /api/v1/dns-credentials [get]
+func (h *DomainHandlers) ListProviderCredentials(c *gin.Context) {
+	tenantID, _, err := getTenantAndUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "unauthorized",
+			Code:  "UNAUTHORIZED",
+		})
+		return
+	}
+
+	credentials, err := h.domainService.ListProviderCredentials(c.Request.Context(), tenantID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list DNS provider credentials")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "failed to list DNS provider credentials",
+			Code:  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, credentials)
+}
+
+// DeleteProviderCredential handles DELETE /api/v1/dns-credentials/:id
+// @Summary Delete a DNS provider credential
+// @Tags dns-credentials
+// @Produce json
+// @Param id path string true "Credential ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/dns-credentials/{id} [delete]
+func (h *DomainHandlers) DeleteProviderCredential(c *gin.Context) {
+	tenantID, _, err := getTenantAndUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "unauthorized",
+			Code:  "UNAUTHORIZED",
+		})
+		return
+	}
+
+	credentialID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "invalid credential ID",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	if err := h.domainService.DeleteProviderCredential(c.Request.Context(), tenantID, credentialID); err != nil {
+		if err == repository.ErrCredentialNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "credential not found",
+				Code:  "NOT_FOUND",
+			})
+			return
+		}
+		log.Error().Err(err).Msg("Failed to delete DNS provider credential")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "failed to delete DNS provider credential",
+			Code:  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "DNS provider credential deleted",
+	})
+}
+
+// AutoConfigureDNS handles POST /api/v1/domains/:id/auto-configure
+// @Summary Create required DNS records automatically
+// @Description Create the domain's verification and routing records in the customer's zone at Cloudflare, Route53 or Google Cloud DNS using a stored credential
+// @Tags domains
+// @Accept json
+// @Produce json
+// @Param id path string true "Domain ID"
+// @Param request body models.AutoConfigureDNSRequest true "Credential and options"
+// @Success 200 {object} models.AutoConfigureDNSResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /api/v1/domains/{id}/auto-configure [post]
+func (h *DomainHandlers) AutoConfigureDNS(c *gin.Context) {
+	tenantID, _, err := getTenantAndUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "unauthorized",
+			Code:  "UNAUTHORIZED",
+		})
+		return
+	}
+
+	domainID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "invalid domain ID",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	var req models.AutoConfigureDNSRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid request",
+			Code:    "INVALID_REQUEST",
+			Message: "credential_id is required",
+		})
+		return
+	}
+
+	result, err := h.domainService.AutoConfigureDNS(c.Request.Context(), tenantID, domainID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDomainNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "domain not found",
+				Code:  "NOT_FOUND",
+			})
+		case errors.Is(err, repository.ErrCredentialNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "credential not found",
+				Code:  "CREDENTIAL_NOT_FOUND",
+			})
+		case errors.Is(err, repository.ErrInvalidProviderCredential):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "invalid credential",
+				Code:    "INVALID_CREDENTIAL",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrCredentialEncryptionDisabled):
+			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Error: "credential storage unavailable",
+				Code:  "ENCRYPTION_DISABLED",
+			})
+		case errors.Is(err, clients.ErrDNSZoneNotFound):
+			c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{
+				Error:   "DNS zone not found",
+				Code:    "ZONE_NOT_FOUND",
+				Message: "The credential cannot see a zone for this domain; check the account or set zone_id on the credential",
+			})
+		default:
+			log.Error().Err(err).Msg("Failed to auto-configure DNS")
+			c.JSON(http.StatusBadGateway, models.ErrorResponse{
+				Error:   "DNS auto-configuration failed",
+				Code:    "PROVIDER_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
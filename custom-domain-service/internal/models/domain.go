@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,8 +27,12 @@ type DomainType string
 const (
 	DomainTypeApex      DomainType = "apex"
 	DomainTypeSubdomain DomainType = "subdomain"
+	DomainTypeWildcard  DomainType = "wildcard" // *.shop.example.com: every first-level subdomain of the base domain
 )
 
+// WildcardPrefix is the leading label of wildcard domains
+const WildcardPrefix = "*."
+
 // TargetType represents what the domain points to
 type TargetType string
 
@@ -37,12 +42,46 @@ const (
 	TargetTypeAPI        TargetType = "api"
 )
 
-// VerificationMethod represents DNS verification method
+// VerificationMethod represents the domain ownership verification method
 type VerificationMethod string
 
 const (
-	VerificationMethodCNAME VerificationMethod = "cname"
-	VerificationMethodTXT   VerificationMethod = "txt"
+	VerificationMethodCNAME    VerificationMethod = "cname"
+	VerificationMethodTXT      VerificationMethod = "txt"
+	VerificationMethodHTTPFile VerificationMethod = "http_file" // Token file served at /.well-known/ (no DNS access needed)
+	VerificationMethodMetaTag  VerificationMethod = "meta_tag"  // <meta> tag on the domain's home page (no DNS access needed)
+)
+
+// IsHTTP returns true if the method is checked by fetching the domain over HTTP instead of DNS
+func (m VerificationMethod) IsHTTP() bool {
+	return m == VerificationMethodHTTPFile || m == VerificationMethodMetaTag
+}
+
+// DNSMode describes how a domain's DNS records reach the platform
+type DNSMode string
+
+const (
+	DNSModeUnknown           DNSMode = "unknown"
+	DNSModeDirect            DNSMode = "direct"              // Records resolve straight to the platform (not Cloudflare)
+	DNSModeCloudflareDNSOnly DNSMode = "cloudflare_dns_only" // Cloudflare nameservers with the proxy off (grey cloud)
+	DNSModeCloudflareProxied DNSMode = "cloudflare_proxied"  // Cloudflare proxy on (orange cloud): records resolve to Cloudflare edge IPs
+)
+
+// CertValidationMethod represents the ACME challenge used to issue the domain's certificate
+type CertValidationMethod string
+
+const (
+	CertValidationHTTP01 CertValidationMethod = "http-01" // HTTP token served by the gateway
+	CertValidationDNS01  CertValidationMethod = "dns-01"  // TXT record via CNAME delegation
+)
+
+// ACMEChallengePreference is the ACME challenge a tenant prefers for a domain's certificate
+type ACMEChallengePreference string
+
+const (
+	ACMEChallengeAuto   ACMEChallengePreference = "auto"    // HTTP-01, falling back to DNS-01 when HTTP-01 validation fails
+	ACMEChallengeHTTP01 ACMEChallengePreference = "http-01" // HTTP-01 only, even when CNAME delegation is in place
+	ACMEChallengeDNS01  ACMEChallengePreference = "dns-01"  // DNS-01 only; waits for the CNAME delegation record
 )
 
 // SSLStatus represents SSL certificate status
@@ -87,6 +126,18 @@ type CustomDomain struct {
 	DNSLastCheckedAt   *time.Time         `json:"dns_last_checked_at"`
 	DNSCheckAttempts   int                `json:"dns_check_attempts" gorm:"default:0"`
 
+	// DNS mode detected from the domain's nameservers and resolved IPs
+	// Proxied domains hide the origin, so certificates must use DNS-01 instead of HTTP-01
+	DNSMode              DNSMode              `json:"dns_mode" gorm:"size:30;default:'unknown'"`
+	DNSModeDetectedAt    *time.Time           `json:"dns_mode_detected_at"`
+	CertValidationMethod CertValidationMethod `json:"cert_validation_method" gorm:"size:20;default:'http-01'"`
+
+	// ACME challenge preference, and when and why issuance fell back from HTTP-01 to DNS-01
+	// (e.g. a WAF in front of the domain blocking /.well-known/acme-challenge)
+	ACMEChallengePreference ACMEChallengePreference `json:"acme_challenge_preference" gorm:"size:20;default:'auto'"`
+	CertFallbackAt          *time.Time              `json:"cert_fallback_at"`
+	CertFallbackReason      string                  `json:"cert_fallback_reason" gorm:"size:500"`
+
 	// Session tracking for security - each onboarding session gets a unique verification token
 	// This prevents cross-tenant token reuse and verification hijacking
 	SessionID string `json:"session_id" gorm:"size:100;index"`
@@ -98,6 +149,11 @@ type CustomDomain struct {
 	SSLCertSecretName string     `json:"ssl_cert_secret_name" gorm:"size:100"`
 	SSLLastError      string     `json:"ssl_last_error" gorm:"size:500"`
 
+	// Lowest expiry threshold (in days) the tenant owner was notified about for the
+	// certificate expiring at SSLExpiryNotifiedFor; a renewed certificate starts over
+	SSLExpiryNotifiedDays int        `json:"ssl_expiry_notified_days" gorm:"default:0"`
+	SSLExpiryNotifiedFor  *time.Time `json:"ssl_expiry_notified_for"`
+
 	// Routing
 	RoutingStatus      RoutingStatus `json:"routing_status" gorm:"size:20;default:'pending'"`
 	VirtualServiceName string        `json:"virtual_service_name" gorm:"size:100"`
@@ -186,9 +242,56 @@ func (d *CustomDomain) IsCNAMEDelegationReady() bool {
 	return d.CNAMEDelegationEnabled && d.CNAMEDelegationVerified && d.CNAMEDelegationVerifiedAt != nil
 }
 
+// CanFallBackToDNS01 returns true if a failed HTTP-01 issuance may be retried via DNS-01
+func (d *CustomDomain) CanFallBackToDNS01() bool {
+	return d.ACMEChallengePreference == "" || d.ACMEChallengePreference == ACMEChallengeAuto
+}
+
+// RequiresDNS01 returns true if the certificate can only be issued via DNS-01: by preference, because
+// HTTP-01 cannot work for the domain (proxied or wildcard), or because HTTP-01 already failed
+func (d *CustomDomain) RequiresDNS01() bool {
+	switch d.ACMEChallengePreference {
+	case ACMEChallengeDNS01:
+		return true
+	case ACMEChallengeHTTP01:
+		return d.IsWildcard()
+	}
+	return d.IsCloudflareProxied() || d.IsWildcard() || d.CertFallbackAt != nil
+}
+
+// ACMEChallengeType returns the challenge the domain's certificate is issued with.
+// DNS-01 is used whenever it is required, and otherwise as soon as CNAME delegation is
+// ready unless the tenant prefers HTTP-01.
+func (d *CustomDomain) ACMEChallengeType() CertValidationMethod {
+	if d.RequiresDNS01() {
+		return CertValidationDNS01
+	}
+	if d.IsCNAMEDelegationReady() && d.ACMEChallengePreference != ACMEChallengeHTTP01 {
+		return CertValidationDNS01
+	}
+	return CertValidationHTTP01
+}
+
+// IsCloudflareProxied returns true if the domain was detected behind Cloudflare's proxy
+func (d *CustomDomain) IsCloudflareProxied() bool {
+	return d.DNSMode == DNSModeCloudflareProxied
+}
+
+// IsWildcard returns true if the domain covers every first-level subdomain of its base domain
+func (d *CustomDomain) IsWildcard() bool {
+	return d.DomainType == DomainTypeWildcard || strings.HasPrefix(d.Domain, WildcardPrefix)
+}
+
+// RecordDomain returns the domain verification and ACME challenge records are placed under
+// Records cannot live under a wildcard label, so *.shop.example.com uses shop.example.com.
+func (d *CustomDomain) RecordDomain() string {
+	return strings.TrimPrefix(d.Domain, WildcardPrefix)
+}
+
 // GetACMEChallengeHost returns the ACME challenge subdomain for CNAME delegation
+// Let's Encrypt validates *.shop.example.com at _acme-challenge.shop.example.com
 func (d *CustomDomain) GetACMEChallengeHost() string {
-	return "_acme-challenge." + d.Domain
+	return "_acme-challenge." + d.RecordDomain()
 }
 
 // GetAllHosts returns all hosts for this domain (including www if enabled)
@@ -200,6 +303,20 @@ func (d *CustomDomain) GetAllHosts() []string {
 	return hosts
 }
 
+// WildcardPatternFor returns the wildcard domain that covers host, e.g. *.shop.example.com for
+// east.shop.example.com. Wildcards match exactly one label, so there is at most one candidate.
+// Returns an empty string when no wildcard can cover the host.
+func WildcardPatternFor(host string) string {
+	if strings.HasPrefix(host, "*") {
+		return ""
+	}
+	parts := strings.SplitN(host, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || strings.Count(parts[1], ".") < 1 {
+		return ""
+	}
+	return WildcardPrefix + parts[1]
+}
+
 // generateVerificationToken generates a secure verification token
 func generateVerificationToken() string {
 	return uuid.New().String()[:32]
@@ -223,9 +340,29 @@ func (DomainActivity) TableName() string {
 	return "domain_activities"
 }
 
+// DomainVerificationMethodChange records a domain's ownership verification method over time.
+// A row is added when the method is chosen or changed; VerifiedAt is set on the row whose
+// method verified the domain.
+type DomainVerificationMethodChange struct {
+	ID             uuid.UUID          `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	DomainID       uuid.UUID          `json:"domain_id" gorm:"type:uuid;not null;index"`
+	TenantID       uuid.UUID          `json:"tenant_id" gorm:"type:uuid;not null"`
+	Method         VerificationMethod `json:"method" gorm:"size:20;not null"`
+	PreviousMethod VerificationMethod `json:"previous_method,omitempty" gorm:"size:20"`
+	ChangedBy      uuid.UUID          `json:"changed_by" gorm:"type:uuid"`
+	VerifiedAt     *time.Time         `json:"verified_at,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+// TableName returns the table name for GORM
+func (DomainVerificationMethodChange) TableName() string {
+	return "domain_verification_method_history"
+}
+
 // DNSRecord represents a DNS record that needs to be configured
+// HTTP verification methods use it too, with the URL as Host and the content to serve as Value
 type DNSRecord struct {
-	RecordType string `json:"record_type"` // TXT, CNAME, A
+	RecordType string `json:"record_type"` // TXT, CNAME, A, HTTP_FILE, META_TAG
 	Host       string `json:"host"`
 	Value      string `json:"value"`
 	TTL        int    `json:"ttl"`
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DNSProvider identifies a DNS hosting provider whose API can create records on the customer's behalf
+type DNSProvider string
+
+const (
+	DNSProviderCloudflare     DNSProvider = "cloudflare"
+	DNSProviderRoute53        DNSProvider = "route53"
+	DNSProviderGoogleCloudDNS DNSProvider = "google_cloud_dns"
+)
+
+// IsValid reports whether the provider is supported
+func (p DNSProvider) IsValid() bool {
+	switch p {
+	case DNSProviderCloudflare, DNSProviderRoute53, DNSProviderGoogleCloudDNS:
+		return true
+	}
+	return false
+}
+
+// ProviderCredential is a tenant's API credential for the DNS provider hosting their domain
+// The secret (API token, access key or service account key) is stored encrypted and never returned
+type ProviderCredential struct {
+	ID               uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	TenantID         uuid.UUID      `json:"tenant_id" gorm:"type:uuid;not null;index"`
+	Provider         DNSProvider    `json:"provider" gorm:"size:30;not null"`
+	Name             string         `json:"name" gorm:"size:100;not null"`
+	ZoneID           string         `json:"zone_id,omitempty" gorm:"size:255"`    // Optional; looked up from the domain when empty
+	ProjectID        string         `json:"project_id,omitempty" gorm:"size:255"` // Google Cloud project (google_cloud_dns only)
+	SecretCiphertext string         `json:"-" gorm:"type:text;not null"`
+	SecretHint       string         `json:"secret_hint" gorm:"size:50"`
+	LastUsedAt       *time.Time     `json:"last_used_at,omitempty"`
+	CreatedBy        uuid.UUID      `json:"created_by" gorm:"type:uuid"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for GORM
+func (ProviderCredential) TableName() string {
+	return "dns_provider_credentials"
+}
@@ -3,11 +3,18 @@ package models
 import "github.com/google/uuid"
 
 // CreateDomainRequest represents a request to create a new custom domain
+// Domain may be a wildcard such as *.shop.example.com, so its format is checked by the DNS verifier
 type CreateDomainRequest struct {
-	Domain     string     `json:"domain" binding:"required,fqdn"`
+	Domain     string     `json:"domain" binding:"required,max=253"`
 	TargetType TargetType `json:"target_type" binding:"omitempty,oneof=storefront admin api"`
 	IncludeWWW bool       `json:"include_www"`
 	SetPrimary bool       `json:"set_primary"`
+
+	// Ownership verification method, defaults to txt
+	VerificationMethod VerificationMethod `json:"verification_method" binding:"omitempty,oneof=txt cname http_file meta_tag"`
+
+	// ACME challenge used for the certificate, defaults to auto (HTTP-01 with DNS-01 fallback)
+	ACMEChallengePreference ACMEChallengePreference `json:"acme_challenge_preference" binding:"omitempty,oneof=auto http-01 dns-01"`
 }
 
 // UpdateDomainRequest represents a request to update domain settings
@@ -15,6 +22,14 @@ type UpdateDomainRequest struct {
 	RedirectWWW   *bool `json:"redirect_www"`
 	ForceHTTPS    *bool `json:"force_https"`
 	PrimaryDomain *bool `json:"primary_domain"`
+
+	// Changing the ACME challenge preference applies to the next issuance or renewal
+	ACMEChallengePreference *ACMEChallengePreference `json:"acme_challenge_preference" binding:"omitempty,oneof=auto http-01 dns-01"`
+}
+
+// ChangeVerificationMethodRequest selects how a domain's ownership is verified
+type ChangeVerificationMethodRequest struct {
+	Method VerificationMethod `json:"method" binding:"required,oneof=txt cname http_file meta_tag"`
 }
 
 // VerifyDomainRequest represents a request to verify DNS
@@ -46,6 +61,14 @@ type DomainResponse struct {
 	DNSRecords         []DNSRecord        `json:"dns_records,omitempty"`
 	VerificationMethod VerificationMethod `json:"verification_method"`
 
+	// DNS mode and the setup steps that apply to it
+	DNSMode              DNSMode              `json:"dns_mode"`
+	CertValidationMethod CertValidationMethod `json:"cert_validation_method"`
+	SetupInstructions    []string             `json:"setup_instructions,omitempty"`
+
+	// ACME challenge preference for the domain's certificate
+	ACMEChallengePreference ACMEChallengePreference `json:"acme_challenge_preference"`
+
 	// Cloudflare Tunnel fields
 	CloudflareTunnelConfigured bool   `json:"cloudflare_tunnel_configured,omitempty"`
 	CloudflareDNSConfigured    bool   `json:"cloudflare_dns_configured,omitempty"`
@@ -71,6 +94,22 @@ type DNSStatusResponse struct {
 	CheckAttempts  int         `json:"check_attempts"`
 	Records        []DNSRecord `json:"records"`
 	Message        string      `json:"message,omitempty"`
+
+	DNSMode           DNSMode  `json:"dns_mode"`
+	SetupInstructions []string `json:"setup_instructions,omitempty"`
+}
+
+// DNSModeResponse represents the detected DNS mode of a domain
+type DNSModeResponse struct {
+	DomainID             uuid.UUID            `json:"domain_id"`
+	Domain               string               `json:"domain"`
+	Mode                 DNSMode              `json:"mode"`
+	DetectedAt           *string              `json:"detected_at,omitempty"`
+	Nameservers          []string             `json:"nameservers,omitempty"`
+	ResolvedIPs          []string             `json:"resolved_ips,omitempty"`
+	CertValidationMethod CertValidationMethod `json:"cert_validation_method"`
+	SetupInstructions    []string             `json:"setup_instructions"`
+	Message              string               `json:"message,omitempty"`
 }
 
 // SSLStatusResponse represents SSL certificate status
@@ -83,6 +122,17 @@ type SSLStatusResponse struct {
 	DaysRemaining *int      `json:"days_remaining,omitempty"`
 	AutoRenew     bool      `json:"auto_renew"`
 	LastError     string    `json:"last_error,omitempty"`
+
+	// ACME challenge in use, the tenant's preference and, after a failed HTTP-01 issuance,
+	// when and why the certificate fell back to DNS-01
+	ChallengeType       CertValidationMethod    `json:"challenge_type"`
+	ChallengePreference ACMEChallengePreference `json:"challenge_preference"`
+	FallbackAt          *string                 `json:"fallback_at,omitempty"`
+	FallbackReason      string                  `json:"fallback_reason,omitempty"`
+
+	// Set while the certificate waits for the _acme-challenge CNAME that DNS-01 needs
+	RequiredCNAMERecord *CNAMEDelegationRecord `json:"required_cname_record,omitempty"`
+	Message             string                 `json:"message,omitempty"`
 }
 
 // DomainStatsResponse represents domain statistics
@@ -117,6 +167,24 @@ type InternalResolveResponse struct {
 	TargetType   TargetType `json:"target_type"`
 	IsActive     bool      `json:"is_active"`
 	IsPrimary    bool      `json:"is_primary"`
+
+	// Set when the host was matched by a wildcard domain; Domain is then the wildcard pattern
+	IsWildcard bool `json:"is_wildcard,omitempty"`
+}
+
+// VerifyTXTRequest asks for a TXT record check on behalf of another service
+type VerifyTXTRequest struct {
+	Host          string `json:"host" binding:"required"`           // e.g., _tesserix-join.example.com
+	ExpectedValue string `json:"expected_value" binding:"required"` // e.g., tesserix-join=<token>
+}
+
+// VerifyTXTResponse represents the result of a TXT record check
+type VerifyTXTResponse struct {
+	Host          string   `json:"host"`
+	ExpectedValue string   `json:"expected_value"`
+	Verified      bool     `json:"verified"`
+	RecordsFound  []string `json:"records_found,omitempty"`
+	Message       string   `json:"message"`
 }
 
 // ErrorResponse represents an API error
@@ -160,3 +228,57 @@ type CNAMEDelegationRecord struct {
 	Target string `json:"target"` // The CNAME target (e.g., "example-com.acme.tesserix.app")
 	TTL    int    `json:"ttl"`    // Recommended TTL
 }
+
+// CreateProviderCredentialRequest stores a DNS provider credential used to auto-configure domains
+// Only the secret of the chosen provider is read: api_token for cloudflare, access_key_id and
+// secret_access_key for route53, service_account_json for google_cloud_dns
+type CreateProviderCredentialRequest struct {
+	Provider           DNSProvider `json:"provider" binding:"required,oneof=cloudflare route53 google_cloud_dns"`
+	Name               string      `json:"name" binding:"required,max=100"`
+	ZoneID             string      `json:"zone_id" binding:"max=255"`    // Optional; looked up from the domain when empty
+	ProjectID          string      `json:"project_id" binding:"max=255"` // google_cloud_dns; defaults to the service account's project
+	APIToken           string      `json:"api_token"`
+	AccessKeyID        string      `json:"access_key_id"`
+	SecretAccessKey    string      `json:"secret_access_key"`
+	ServiceAccountJSON string      `json:"service_account_json"`
+}
+
+// AutoConfigureDNSRequest asks for a domain's required DNS records to be created through a provider API
+type AutoConfigureDNSRequest struct {
+	CredentialID uuid.UUID `json:"credential_id" binding:"required"`
+	DryRun       bool      `json:"dry_run"`   // Report the changes without making them
+	Overwrite    bool      `json:"overwrite"` // Replace records that already exist with a different value
+}
+
+// Auto-configure record actions
+const (
+	AutoConfigureActionCreated   = "created"   // Record did not exist and was created (or would be, in a dry run)
+	AutoConfigureActionUpdated   = "updated"   // Record had a different value and was replaced (or would be)
+	AutoConfigureActionUnchanged = "unchanged" // Record already had the required value
+	AutoConfigureActionConflict  = "conflict"  // Record has a different value and overwrite was not requested
+	AutoConfigureActionFailed    = "failed"    // Provider API call failed
+)
+
+// AutoConfigureRecordResult is the outcome for one required DNS record
+type AutoConfigureRecordResult struct {
+	RecordType    string   `json:"record_type"`
+	Host          string   `json:"host"`
+	Value         string   `json:"value"`
+	TTL           int      `json:"ttl"`
+	Purpose       string   `json:"purpose"`
+	Action        string   `json:"action"`
+	CurrentValues []string `json:"current_values,omitempty"` // Values found before the change
+	Error         string   `json:"error,omitempty"`
+}
+
+// AutoConfigureDNSResponse reports the records created through a DNS provider API
+type AutoConfigureDNSResponse struct {
+	DomainID uuid.UUID                   `json:"domain_id"`
+	Domain   string                      `json:"domain"`
+	Provider DNSProvider                 `json:"provider"`
+	ZoneID   string                      `json:"zone_id"`
+	DryRun   bool                        `json:"dry_run"`
+	Records  []AutoConfigureRecordResult `json:"records"`
+	Skipped  []DNSRecord                 `json:"skipped,omitempty"` // Required records a DNS provider cannot create (HTTP file, meta tag)
+	Message  string                      `json:"message"`
+}
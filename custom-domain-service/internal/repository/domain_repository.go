@@ -12,9 +12,18 @@ import (
 )
 
 var (
-	ErrDomainNotFound      = errors.New("domain not found")
-	ErrDomainAlreadyExists = errors.New("domain already exists")
-	ErrDomainLimitExceeded = errors.New("domain limit exceeded for tenant")
+	ErrDomainNotFound        = errors.New("domain not found")
+	ErrDomainAlreadyExists   = errors.New("domain already exists")
+	ErrDomainLimitExceeded   = errors.New("domain limit exceeded for tenant")
+	ErrDomainAlreadyVerified = errors.New("domain ownership is already verified")
+
+	ErrWildcardHTTPVerification = errors.New("wildcard domains must be verified with a txt or cname record")
+	ErrWildcardNotSupported     = errors.New("wildcard domains need DNS-01 certificates, which are not enabled")
+	ErrWildcardHTTPChallenge    = errors.New("wildcard certificates cannot be issued with the http-01 challenge")
+	ErrDNS01NotSupported        = errors.New("DNS-01 certificates are not enabled")
+
+	ErrCredentialNotFound        = errors.New("DNS provider credential not found")
+	ErrInvalidProviderCredential = errors.New("invalid DNS provider credential")
 )
 
 // DomainRepository handles database operations for custom domains
@@ -92,6 +101,19 @@ func (r *DomainRepository) GetPendingVerification(ctx context.Context, limit int
 	return domains, err
 }
 
+// GetAwaitingCNAMEDelegation retrieves verified domains whose certificate waits for the
+// _acme-challenge CNAME delegation record before it can be issued via DNS-01
+func (r *DomainRepository) GetAwaitingCNAMEDelegation(ctx context.Context, limit int) ([]models.CustomDomain, error) {
+	var domains []models.CustomDomain
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND dns_verified = ? AND ns_delegation_enabled = ?",
+			models.DomainStatusVerifying, true, true).
+		Order("ns_delegation_last_checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&domains).Error
+	return domains, err
+}
+
 // GetExpiringCertificates retrieves domains with certificates expiring soon
 func (r *DomainRepository) GetExpiringCertificates(ctx context.Context, daysBeforeExpiry int) ([]models.CustomDomain, error) {
 	var domains []models.CustomDomain
@@ -103,6 +125,17 @@ func (r *DomainRepository) GetExpiringCertificates(ctx context.Context, daysBefo
 	return domains, err
 }
 
+// RecordCertificateExpiryNotification records the expiry threshold the tenant owner was notified about
+func (r *DomainRepository) RecordCertificateExpiryNotification(ctx context.Context, id uuid.UUID, thresholdDays int, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.CustomDomain{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"ssl_expiry_notified_days": thresholdDays,
+			"ssl_expiry_notified_for":  expiresAt,
+		}).Error
+}
+
 // GetAllActive retrieves all active domains
 func (r *DomainRepository) GetAllActive(ctx context.Context) ([]models.CustomDomain, error) {
 	var domains []models.CustomDomain
@@ -170,6 +203,27 @@ func (r *DomainRepository) EnableCNAMEDelegation(ctx context.Context, id uuid.UU
 	}).Error
 }
 
+// UpdateDNSMode stores the detected DNS mode and the certificate validation method it implies
+func (r *DomainRepository) UpdateDNSMode(ctx context.Context, id uuid.UUID, mode models.DNSMode, method models.CertValidationMethod) error {
+	return r.db.WithContext(ctx).Model(&models.CustomDomain{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"dns_mode":               mode,
+		"dns_mode_detected_at":   time.Now(),
+		"cert_validation_method": method,
+		"updated_at":             time.Now(),
+	}).Error
+}
+
+// RecordDNS01Fallback switches a domain's certificate to DNS-01 after HTTP-01 validation failed
+func (r *DomainRepository) RecordDNS01Fallback(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.CustomDomain{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"cert_fallback_at":       time.Now(),
+		"cert_fallback_reason":   reason,
+		"cert_validation_method": models.CertValidationDNS01,
+		"ns_delegation_enabled":  true,
+		"updated_at":             time.Now(),
+	}).Error
+}
+
 // UpdateSSLStatus updates SSL certificate status
 func (r *DomainRepository) UpdateSSLStatus(ctx context.Context, id uuid.UUID, status models.SSLStatus, secretName string, expiresAt *time.Time, lastError string) error {
 	updates := map[string]interface{}{
@@ -284,6 +338,62 @@ func (r *DomainRepository) GetActivities(ctx context.Context, domainID uuid.UUID
 	return activities, err
 }
 
+// ChangeVerificationMethod switches a domain's verification method, restarts its verification
+// attempts and records the change in the method history
+func (r *DomainRepository) ChangeVerificationMethod(ctx context.Context, domain *models.CustomDomain, method models.VerificationMethod, changedBy uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"verification_method": method,
+			"dns_check_attempts":  0,
+			"updated_at":          time.Now(),
+		}
+		// Domains that ran out of attempts get another chance with the new method
+		if domain.Status == models.DomainStatusFailed {
+			updates["status"] = models.DomainStatusPending
+			updates["status_message"] = "Waiting for ownership verification"
+		}
+		if err := tx.Model(&models.CustomDomain{}).Where("id = ?", domain.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.DomainVerificationMethodChange{
+			DomainID:       domain.ID,
+			TenantID:       domain.TenantID,
+			Method:         method,
+			PreviousMethod: domain.VerificationMethod,
+			ChangedBy:      changedBy,
+			CreatedAt:      time.Now(),
+		}).Error
+	})
+}
+
+// RecordVerificationMethod adds a method history entry, e.g. for the method chosen at creation
+func (r *DomainRepository) RecordVerificationMethod(ctx context.Context, change *models.DomainVerificationMethodChange) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+// MarkVerificationMethodVerified stamps the domain's latest method history entry as the one that verified it
+func (r *DomainRepository) MarkVerificationMethodVerified(ctx context.Context, domainID uuid.UUID) error {
+	latest := r.db.Model(&models.DomainVerificationMethodChange{}).
+		Select("id").
+		Where("domain_id = ?", domainID).
+		Order("created_at DESC").
+		Limit(1)
+	return r.db.WithContext(ctx).
+		Model(&models.DomainVerificationMethodChange{}).
+		Where("id = (?) AND verified_at IS NULL", latest).
+		Update("verified_at", time.Now()).Error
+}
+
+// GetVerificationMethodHistory retrieves a domain's verification method history, newest first
+func (r *DomainRepository) GetVerificationMethodHistory(ctx context.Context, domainID uuid.UUID) ([]models.DomainVerificationMethodChange, error) {
+	var history []models.DomainVerificationMethodChange
+	err := r.db.WithContext(ctx).
+		Where("domain_id = ?", domainID).
+		Order("created_at DESC").
+		Find(&history).Error
+	return history, err
+}
+
 // SaveHealthCheck saves a health check result
 func (r *DomainRepository) SaveHealthCheck(ctx context.Context, health *models.DomainHealth) error {
 	return r.db.WithContext(ctx).Create(health).Error
@@ -390,3 +500,45 @@ func (r *DomainRepository) GetStats(ctx context.Context, tenantID uuid.UUID) (*m
 
 	return &stats, nil
 }
+
+// CreateProviderCredential stores a DNS provider credential
+func (r *DomainRepository) CreateProviderCredential(ctx context.Context, credential *models.ProviderCredential) error {
+	return r.db.WithContext(ctx).Create(credential).Error
+}
+
+// GetProviderCredential retrieves a tenant's DNS provider credential
+func (r *DomainRepository) GetProviderCredential(ctx context.Context, tenantID, id uuid.UUID) (*models.ProviderCredential, error) {
+	var credential models.ProviderCredential
+	err := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", id, tenantID).First(&credential).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCredentialNotFound
+	}
+	return &credential, err
+}
+
+// ListProviderCredentials retrieves a tenant's DNS provider credentials, newest first
+func (r *DomainRepository) ListProviderCredentials(ctx context.Context, tenantID uuid.UUID) ([]models.ProviderCredential, error) {
+	var credentials []models.ProviderCredential
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at DESC").
+		Find(&credentials).Error
+	return credentials, err
+}
+
+// DeleteProviderCredential soft deletes a tenant's DNS provider credential
+func (r *DomainRepository) DeleteProviderCredential(ctx context.Context, tenantID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&models.ProviderCredential{}, "id = ? AND tenant_id = ?", id, tenantID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCredentialNotFound
+	}
+	return nil
+}
+
+// TouchProviderCredential records that a DNS provider credential was used
+func (r *DomainRepository) TouchProviderCredential(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.ProviderCredential{}).Where("id = ?", id).Update("last_used_at", time.Now()).Error
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"custom-domain-service/internal/models"
+	"custom-domain-service/internal/repository"
+
+	"github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/rs/zerolog/log"
+)
+
+// maxFallbackReasonLength matches the size of the cert_fallback_reason column
+const maxFallbackReasonLength = 500
+
+// validateChallengePreference checks that the domain's certificate can be issued with the preferred ACME challenge
+func (s *DomainService) validateChallengePreference(domain *models.CustomDomain, preference models.ACMEChallengePreference) error {
+	switch preference {
+	case models.ACMEChallengeHTTP01:
+		if domain.IsWildcard() {
+			return repository.ErrWildcardHTTPChallenge
+		}
+	case models.ACMEChallengeDNS01:
+		if !s.cfg.CNAMEDelegation.Enabled {
+			return repository.ErrDNS01NotSupported
+		}
+	}
+	return nil
+}
+
+// applyChallengePreference stores the preferred ACME challenge on the domain and enables the
+// CNAME delegation DNS-01 needs. It does not persist the domain.
+func (s *DomainService) applyChallengePreference(domain *models.CustomDomain, preference models.ACMEChallengePreference) {
+	if preference == "" {
+		preference = models.ACMEChallengeAuto
+	}
+	domain.ACMEChallengePreference = preference
+	if domain.RequiresDNS01() && s.cfg.CNAMEDelegation.Enabled {
+		domain.CNAMEDelegationEnabled = true
+	}
+	domain.CertValidationMethod = domain.ACMEChallengeType()
+}
+
+// dns01WaitMessage tells the tenant which record is missing before a DNS-01 certificate can be issued
+func dns01WaitMessage(domain *models.CustomDomain) string {
+	host, target := domain.GetACMEChallengeHost(), domain.CNAMEDelegationTarget
+	switch {
+	case domain.IsWildcard():
+		return fmt.Sprintf("Wildcard certificates are issued via DNS-01. Add %s CNAME %s so the certificate can be issued.", host, target)
+	case domain.IsCloudflareProxied() && domain.ACMEChallengePreference != models.ACMEChallengeDNS01:
+		return fmt.Sprintf("Domain is proxied through Cloudflare. Add %s CNAME %s as DNS only so the certificate can be issued via DNS-01.", host, target)
+	case domain.CertFallbackAt != nil:
+		return fmt.Sprintf("HTTP-01 validation failed, likely blocked by a firewall in front of the domain. Add %s CNAME %s so the certificate can be issued via DNS-01.", host, target)
+	default:
+		return fmt.Sprintf("Certificates for this domain are issued via DNS-01. Add %s CNAME %s so the certificate can be issued.", host, target)
+	}
+}
+
+// fallBackToDNS01 retries a certificate whose HTTP-01 validation failed via DNS-01.
+// WAFs and bot protection in front of a domain commonly block /.well-known/acme-challenge,
+// while the DNS-01 TXT record lives in our ACME zone behind the tenant's _acme-challenge CNAME.
+func (s *DomainService) fallBackToDNS01(ctx context.Context, domain *models.CustomDomain, reason string) {
+	if len(reason) > maxFallbackReasonLength {
+		reason = reason[:maxFallbackReasonLength]
+	}
+	log.Warn().Str("domain", domain.Domain).Str("reason", reason).Msg("HTTP-01 validation failed, falling back to DNS-01")
+
+	if err := s.repo.RecordDNS01Fallback(ctx, domain.ID, reason); err != nil {
+		log.Error().Err(err).Str("domain", domain.Domain).Msg("Failed to record DNS-01 fallback")
+		s.repo.UpdateStatus(ctx, domain.ID, models.DomainStatusFailed, "SSL certificate provisioning failed. Please verify your DNS configuration.")
+		domain.Status = models.DomainStatusFailed
+		domain.StatusMessage = "SSL certificate provisioning failed"
+		s.publishDomainEvent(ctx, events.DomainFailed, domain, string(models.DomainStatusPending))
+		return
+	}
+	now := time.Now()
+	domain.CertFallbackAt = &now
+	domain.CertFallbackReason = reason
+	domain.CertValidationMethod = models.CertValidationDNS01
+	domain.CNAMEDelegationEnabled = true
+	s.logActivity(ctx, domain, "ssl_fallback", "in_progress", "HTTP-01 validation failed, retrying the certificate via DNS-01")
+
+	// cert-manager only retries a failed issuance after a backoff of an hour or more, so start over
+	// with a new Certificate rather than updating the failed one
+	if err := s.k8sClient.DeleteCertificate(ctx, domain); err != nil {
+		log.Warn().Err(err).Str("domain", domain.Domain).Msg("Failed to delete HTTP-01 certificate before DNS-01 fallback")
+	}
+
+	s.checkCNAMEDelegation(ctx, domain)
+	s.provisionDomainWithCertManager(ctx, domain)
+}
+
+// checkCNAMEDelegation verifies the domain's _acme-challenge CNAME against its stored target
+// and records the outcome on the domain
+func (s *DomainService) checkCNAMEDelegation(ctx context.Context, domain *models.CustomDomain) {
+	if domain.CNAMEDelegationVerified || !s.cfg.CNAMEDelegation.Enabled {
+		return
+	}
+
+	expectedTarget := domain.CNAMEDelegationTarget
+	if expectedTarget == "" {
+		expectedTarget = s.dnsVerifier.GetCNAMEDelegationTargetForTenant(domain.Domain, domain.TenantID.String())
+	}
+
+	cnameResult, err := s.dnsVerifier.VerifyCNAMEDelegationWithTarget(ctx, domain.Domain, expectedTarget)
+	if err != nil {
+		log.Warn().Err(err).Str("domain", domain.Domain).Msg("CNAME delegation verification error")
+		return
+	}
+
+	attempts := domain.CNAMEDelegationCheckAttempts + 1
+	if err := s.repo.UpdateCNAMEDelegationVerification(ctx, domain.ID, cnameResult.IsVerified, attempts); err != nil {
+		log.Warn().Err(err).Str("domain", domain.Domain).Msg("Failed to update CNAME delegation status")
+		return
+	}
+	domain.CNAMEDelegationVerified = cnameResult.IsVerified
+	domain.CNAMEDelegationCheckAttempts = attempts
+
+	if cnameResult.IsVerified {
+		now := time.Now()
+		domain.CNAMEDelegationVerifiedAt = &now
+		s.logActivity(ctx, domain, "cname_delegation_verified", "success", "CNAME delegation verified - DNS-01 challenges now possible")
+	}
+}
+
+// ResumeDNS01Provisioning re-checks the CNAME delegation of a domain whose certificate waits for it
+// and resumes provisioning once the record is in place. It returns true if provisioning resumed.
+func (s *DomainService) ResumeDNS01Provisioning(ctx context.Context, domain *models.CustomDomain) bool {
+	maxAttempts := s.cfg.CNAMEDelegation.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = 100
+	}
+	if !domain.CNAMEDelegationVerified && domain.CNAMEDelegationCheckAttempts >= maxAttempts {
+		return false
+	}
+
+	s.checkCNAMEDelegation(ctx, domain)
+	if !domain.IsCNAMEDelegationReady() {
+		return false
+	}
+
+	// Leave the verifying state first so the next worker run doesn't start a second issuance
+	if err := s.repo.UpdateStatus(ctx, domain.ID, models.DomainStatusProvisioning, "Issuing SSL certificate via DNS-01"); err != nil {
+		log.Error().Err(err).Str("domain", domain.Domain).Msg("Failed to resume DNS-01 provisioning")
+		return false
+	}
+	domain.Status = models.DomainStatusProvisioning
+	domain.StatusMessage = "Issuing SSL certificate via DNS-01"
+
+	log.Info().Str("domain", domain.Domain).Msg("CNAME delegation in place, resuming DNS-01 certificate provisioning")
+	go s.provisionDomain(context.Background(), domain)
+	return true
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"custom-domain-service/internal/config"
+	"custom-domain-service/internal/models"
+	"custom-domain-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACMEChallengeType(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		domain   models.CustomDomain
+		requires bool
+		want     models.CertValidationMethod
+	}{
+		{
+			name:   "auto uses HTTP-01 by default",
+			domain: models.CustomDomain{Domain: "shop.example.com"},
+			want:   models.CertValidationHTTP01,
+		},
+		{
+			name:   "auto uses DNS-01 once delegation is ready",
+			domain: models.CustomDomain{Domain: "shop.example.com", CNAMEDelegationEnabled: true, CNAMEDelegationVerified: true, CNAMEDelegationVerifiedAt: &now},
+			want:   models.CertValidationDNS01,
+		},
+		{
+			name:     "auto requires DNS-01 after HTTP-01 failed",
+			domain:   models.CustomDomain{Domain: "shop.example.com", CertFallbackAt: &now},
+			requires: true,
+			want:     models.CertValidationDNS01,
+		},
+		{
+			name:     "auto requires DNS-01 behind the Cloudflare proxy",
+			domain:   models.CustomDomain{Domain: "shop.example.com", DNSMode: models.DNSModeCloudflareProxied},
+			requires: true,
+			want:     models.CertValidationDNS01,
+		},
+		{
+			name:     "dns-01 preference",
+			domain:   models.CustomDomain{Domain: "shop.example.com", ACMEChallengePreference: models.ACMEChallengeDNS01},
+			requires: true,
+			want:     models.CertValidationDNS01,
+		},
+		{
+			name: "http-01 preference ignores delegation and fallback",
+			domain: models.CustomDomain{
+				Domain: "shop.example.com", ACMEChallengePreference: models.ACMEChallengeHTTP01, CertFallbackAt: &now,
+				CNAMEDelegationEnabled: true, CNAMEDelegationVerified: true, CNAMEDelegationVerifiedAt: &now,
+			},
+			want: models.CertValidationHTTP01,
+		},
+		{
+			name:     "wildcards always require DNS-01",
+			domain:   models.CustomDomain{Domain: "*.example.com", DomainType: models.DomainTypeWildcard, ACMEChallengePreference: models.ACMEChallengeHTTP01},
+			requires: true,
+			want:     models.CertValidationDNS01,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.requires, tt.domain.RequiresDNS01())
+			assert.Equal(t, tt.want, tt.domain.ACMEChallengeType())
+		})
+	}
+}
+
+func TestCanFallBackToDNS01(t *testing.T) {
+	assert.True(t, (&models.CustomDomain{}).CanFallBackToDNS01())
+	assert.True(t, (&models.CustomDomain{ACMEChallengePreference: models.ACMEChallengeAuto}).CanFallBackToDNS01())
+	assert.False(t, (&models.CustomDomain{ACMEChallengePreference: models.ACMEChallengeHTTP01}).CanFallBackToDNS01())
+}
+
+func TestValidateChallengePreference(t *testing.T) {
+	enabled := &DomainService{cfg: &config.Config{CNAMEDelegation: config.CNAMEDelegationConfig{Enabled: true}}}
+	disabled := &DomainService{cfg: &config.Config{}}
+	wildcard := &models.CustomDomain{Domain: "*.example.com", DomainType: models.DomainTypeWildcard}
+	apex := &models.CustomDomain{Domain: "example.com"}
+
+	assert.ErrorIs(t, enabled.validateChallengePreference(wildcard, models.ACMEChallengeHTTP01), repository.ErrWildcardHTTPChallenge)
+	assert.ErrorIs(t, disabled.validateChallengePreference(apex, models.ACMEChallengeDNS01), repository.ErrDNS01NotSupported)
+	assert.NoError(t, enabled.validateChallengePreference(apex, models.ACMEChallengeDNS01))
+	assert.NoError(t, disabled.validateChallengePreference(apex, models.ACMEChallengeAuto))
+	assert.NoError(t, disabled.validateChallengePreference(apex, ""))
+}
+
+func TestApplyChallengePreference(t *testing.T) {
+	svc := &DomainService{cfg: &config.Config{CNAMEDelegation: config.CNAMEDelegationConfig{Enabled: true}}}
+
+	domain := &models.CustomDomain{Domain: "shop.example.com"}
+	svc.applyChallengePreference(domain, "")
+	assert.Equal(t, models.ACMEChallengeAuto, domain.ACMEChallengePreference)
+	assert.Equal(t, models.CertValidationHTTP01, domain.CertValidationMethod)
+	assert.False(t, domain.CNAMEDelegationEnabled)
+
+	svc.applyChallengePreference(domain, models.ACMEChallengeDNS01)
+	assert.Equal(t, models.CertValidationDNS01, domain.CertValidationMethod)
+	assert.True(t, domain.CNAMEDelegationEnabled)
+}
+
+func TestDNS01WaitMessage(t *testing.T) {
+	now := time.Now()
+	domain := &models.CustomDomain{Domain: "shop.example.com", CNAMEDelegationTarget: "shop-example-com.acme.tesserix.app", CertFallbackAt: &now}
+
+	message := dns01WaitMessage(domain)
+	assert.Contains(t, message, "HTTP-01 validation failed")
+	assert.Contains(t, message, "_acme-challenge.shop.example.com CNAME shop-example-com.acme.tesserix.app")
+}
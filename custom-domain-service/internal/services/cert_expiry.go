@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"custom-domain-service/internal/models"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DomainCertificateExpiring is published when a custom domain certificate crosses one of the
+// SSL_EXPIRY_NOTIFY_DAYS thresholds; notification-service emails the tenant owner
+const DomainCertificateExpiring = "domain.certificate.expiring"
+
+// ErrEventPublisherUnavailable is returned when an event must be published but NATS is not configured
+var ErrEventPublisherUnavailable = errors.New("event publisher not configured")
+
+// healthCheckExpiryTolerance absorbs the whole-day rounding of health check expiry dates
+// when deciding whether a notification was already sent for the same certificate
+const healthCheckExpiryTolerance = 48 * time.Hour
+
+// NotifyCertificateExpiry publishes a domain.certificate.expiring event when the domain's certificate
+// has crossed a notification threshold it was not yet notified about, returning that threshold (0 if none)
+func (s *DomainService) NotifyCertificateExpiry(ctx context.Context, domain *models.CustomDomain, now time.Time) (int, error) {
+	expiresAt, err := s.certificateExpiry(ctx, domain)
+	if err != nil || expiresAt == nil {
+		return 0, err
+	}
+
+	daysRemaining := int(expiresAt.Sub(now).Hours() / 24)
+	threshold := certificateExpiryThreshold(s.cfg.SSL.ExpiryNotifyDays, daysRemaining, notifiedExpiryThreshold(domain, *expiresAt))
+	if threshold == 0 {
+		return 0, nil
+	}
+
+	if s.eventPublisher == nil {
+		return 0, ErrEventPublisherUnavailable
+	}
+
+	tenant, err := s.tenantClient.GetTenant(ctx, domain.TenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up tenant owner: %w", err)
+	}
+
+	event := newDomainEvent(DomainCertificateExpiring, domain, "")
+	event.SSLExpiresAt = expiresAt.Format(time.RFC3339)
+	event.OwnerEmail = tenant.BillingEmail
+	event.OwnerName = tenant.DisplayName
+	if event.OwnerName == "" {
+		event.OwnerName = tenant.Name
+	}
+	if tenant.AdminURL != "" {
+		event.AdminURL = tenant.AdminURL
+	}
+	event.Metadata["days_remaining"] = daysRemaining
+	event.Metadata["threshold_days"] = threshold
+
+	if event.OwnerEmail == "" {
+		log.Warn().Str("domain", domain.Domain).Str("tenant_id", domain.TenantID.String()).
+			Msg("Tenant has no billing email, certificate expiry notification will not be emailed")
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := s.eventPublisher.PublishDomain(publishCtx, event); err != nil {
+		return 0, fmt.Errorf("failed to publish certificate expiry event: %w", err)
+	}
+
+	if err := s.repo.RecordCertificateExpiryNotification(ctx, domain.ID, threshold, *expiresAt); err != nil {
+		return 0, fmt.Errorf("failed to record certificate expiry notification: %w", err)
+	}
+
+	s.logActivity(ctx, domain, "ssl_expiry_notified", "success",
+		fmt.Sprintf("Certificate expires in %d days (%s), tenant owner notified", daysRemaining, expiresAt.Format("2006-01-02")))
+
+	return threshold, nil
+}
+
+// certificateExpiry returns when the domain's certificate expires: the expiry reported by cert-manager,
+// or else the expiry the latest health check saw on the served certificate (e.g. Cloudflare-issued ones)
+func (s *DomainService) certificateExpiry(ctx context.Context, domain *models.CustomDomain) (*time.Time, error) {
+	if domain.SSLExpiresAt != nil {
+		return domain.SSLExpiresAt, nil
+	}
+
+	health, err := s.repo.GetLatestHealthCheck(ctx, domain.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest health check: %w", err)
+	}
+	if health == nil || !health.SSLValid {
+		return nil, nil
+	}
+	expiresAt := health.CheckedAt.Add(time.Duration(health.SSLExpiresIn) * 24 * time.Hour)
+	return &expiresAt, nil
+}
+
+// notifiedExpiryThreshold returns the lowest threshold already notified for the certificate expiring
+// at expiresAt, or 0 if the notifications were for an earlier certificate that has since been renewed
+func notifiedExpiryThreshold(domain *models.CustomDomain, expiresAt time.Time) int {
+	if domain.SSLExpiryNotifiedFor == nil {
+		return 0
+	}
+	diff := expiresAt.Sub(*domain.SSLExpiryNotifiedFor)
+	if diff > healthCheckExpiryTolerance || diff < -healthCheckExpiryTolerance {
+		return 0
+	}
+	return domain.SSLExpiryNotifiedDays
+}
+
+// certificateExpiryThreshold returns the lowest threshold (in days) that daysRemaining has crossed,
+// or 0 when none has or it is not lower than notifiedDays. Only the lowest crossed threshold is
+// returned so a worker outage does not cause a burst of catch-up notifications.
+func certificateExpiryThreshold(thresholds []int, daysRemaining, notifiedDays int) int {
+	due := 0
+	for _, threshold := range thresholds {
+		if daysRemaining <= threshold && (due == 0 || threshold < due) {
+			due = threshold
+		}
+	}
+	if due == 0 || (notifiedDays > 0 && due >= notifiedDays) {
+		return 0
+	}
+	return due
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"custom-domain-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificateExpiryThreshold(t *testing.T) {
+	thresholds := []int{30, 14, 7, 1}
+	tests := []struct {
+		name          string
+		daysRemaining int
+		notifiedDays  int
+		want          int
+	}{
+		{name: "not yet due", daysRemaining: 45, want: 0},
+		{name: "first threshold", daysRemaining: 30, want: 30},
+		{name: "already notified for threshold", daysRemaining: 20, notifiedDays: 30, want: 0},
+		{name: "next threshold", daysRemaining: 14, notifiedDays: 30, want: 14},
+		{name: "outage skips to lowest crossed threshold", daysRemaining: 5, notifiedDays: 30, want: 7},
+		{name: "last day", daysRemaining: 0, notifiedDays: 7, want: 1},
+		{name: "expired after final notification", daysRemaining: -3, notifiedDays: 1, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, certificateExpiryThreshold(thresholds, tt.daysRemaining, tt.notifiedDays))
+		})
+	}
+}
+
+func TestCertificateExpiryThreshold_UnsortedThresholds(t *testing.T) {
+	assert.Equal(t, 7, certificateExpiryThreshold([]int{7, 30, 1, 14}, 6, 0))
+}
+
+func TestNotifiedExpiryThreshold(t *testing.T) {
+	expiresAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0, notifiedExpiryThreshold(&models.CustomDomain{}, expiresAt), "never notified")
+
+	sameCert := expiresAt.Add(-20 * time.Hour)
+	domain := &models.CustomDomain{SSLExpiryNotifiedDays: 14, SSLExpiryNotifiedFor: &sameCert}
+	assert.Equal(t, 14, notifiedExpiryThreshold(domain, expiresAt), "same certificate within health check rounding")
+
+	renewed := expiresAt.AddDate(0, 0, 60)
+	assert.Equal(t, 0, notifiedExpiryThreshold(domain, renewed), "renewed certificate starts over")
+}
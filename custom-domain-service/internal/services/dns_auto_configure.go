@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"custom-domain-service/internal/clients"
+	"custom-domain-service/internal/models"
+	"custom-domain-service/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// autoConfigurableRecordTypes are the required record types a DNS provider API can create
+// HTTP file and meta tag verification have to be served by the customer's site instead
+var autoConfigurableRecordTypes = map[string]bool{"A": true, "AAAA": true, "CNAME": true, "TXT": true}
+
+// defaultAutoConfigureTTL is used for required records that carry no TTL
+const defaultAutoConfigureTTL = 300
+
+// CreateProviderCredential encrypts and stores a tenant's DNS provider credential
+func (s *DomainService) CreateProviderCredential(ctx context.Context, tenantID uuid.UUID, req *models.CreateProviderCredentialRequest, createdBy uuid.UUID) (*models.ProviderCredential, error) {
+	secrets, hint, err := providerCredentialSecrets(req)
+	if err != nil {
+		return nil, err
+	}
+
+	credential := &models.ProviderCredential{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		Provider:   req.Provider,
+		Name:       strings.TrimSpace(req.Name),
+		ZoneID:     strings.TrimSpace(req.ZoneID),
+		ProjectID:  strings.TrimSpace(req.ProjectID),
+		SecretHint: hint,
+		CreatedBy:  createdBy,
+	}
+
+	credential.SecretCiphertext, err = s.credentialCipher.EncryptMap(secrets, credentialAdditionalData(credential))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateProviderCredential(ctx, credential); err != nil {
+		return nil, fmt.Errorf("failed to save DNS provider credential: %w", err)
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID.String()).
+		Str("provider", string(credential.Provider)).
+		Str("credential_id", credential.ID.String()).
+		Msg("DNS provider credential created")
+
+	return credential, nil
+}
+
+// ListProviderCredentials returns a tenant's DNS provider credentials, without their secrets
+func (s *DomainService) ListProviderCredentials(ctx context.Context, tenantID uuid.UUID) ([]models.ProviderCredential, error) {
+	return s.repo.ListProviderCredentials(ctx, tenantID)
+}
+
+// DeleteProviderCredential deletes a tenant's DNS provider credential
+func (s *DomainService) DeleteProviderCredential(ctx context.Context, tenantID, credentialID uuid.UUID) error {
+	return s.repo.DeleteProviderCredential(ctx, tenantID, credentialID)
+}
+
+// AutoConfigureDNS creates the domain's required DNS records in the customer's zone through their provider's API
+// Records that already exist with a different value are reported as conflicts unless overwrite is set.
+// Verification still happens through DNS: the verification worker picks the records up once they propagate.
+func (s *DomainService) AutoConfigureDNS(ctx context.Context, tenantID, domainID uuid.UUID, req *models.AutoConfigureDNSRequest) (*models.AutoConfigureDNSResponse, error) {
+	domain, err := s.repo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if domain.TenantID != tenantID {
+		return nil, repository.ErrDomainNotFound
+	}
+
+	credential, err := s.repo.GetProviderCredential(ctx, tenantID, req.CredentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := s.providerCredentials(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.newDNSProviderClient(credential.Provider, creds)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", repository.ErrInvalidProviderCredential, err)
+	}
+
+	zoneID := credential.ZoneID
+	if zoneID == "" {
+		zoneID, err = client.FindZone(ctx, domain.RecordDomain())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	records, skipped := autoConfigurableRecords(s.dnsVerifier.GetRequiredDNSRecords(domain))
+	response := &models.AutoConfigureDNSResponse{
+		DomainID: domain.ID,
+		Domain:   domain.Domain,
+		Provider: credential.Provider,
+		ZoneID:   zoneID,
+		DryRun:   req.DryRun,
+		Records:  make([]models.AutoConfigureRecordResult, 0, len(records)),
+		Skipped:  skipped,
+	}
+
+	counts := map[string]int{}
+	for _, record := range records {
+		result := applyDNSRecord(ctx, client, zoneID, record, req.DryRun, req.Overwrite)
+		counts[result.Action]++
+		response.Records = append(response.Records, result)
+	}
+	response.Message = autoConfigureMessage(counts, req.DryRun)
+
+	if !req.DryRun {
+		status := "success"
+		if counts[models.AutoConfigureActionFailed] > 0 || counts[models.AutoConfigureActionConflict] > 0 {
+			status = "failed"
+		}
+		s.logActivity(ctx, domain, "dns_auto_configured", status, fmt.Sprintf("%s via %s", response.Message, credential.Provider))
+
+		if err := s.repo.TouchProviderCredential(ctx, credential.ID); err != nil {
+			log.Warn().Err(err).Str("credential_id", credential.ID.String()).Msg("Failed to record DNS provider credential use")
+		}
+	}
+
+	log.Info().
+		Str("domain", domain.Domain).
+		Str("provider", string(credential.Provider)).
+		Bool("dry_run", req.DryRun).
+		Int("created", counts[models.AutoConfigureActionCreated]).
+		Int("updated", counts[models.AutoConfigureActionUpdated]).
+		Int("conflicts", counts[models.AutoConfigureActionConflict]).
+		Int("failed", counts[models.AutoConfigureActionFailed]).
+		Msg("DNS records auto-configured")
+
+	return response, nil
+}
+
+// providerCredentialSecrets checks a credential request and returns its secrets and a hint to show for them
+func providerCredentialSecrets(req *models.CreateProviderCredentialRequest) (map[string]string, string, error) {
+	switch req.Provider {
+	case models.DNSProviderCloudflare:
+		if req.APIToken == "" {
+			return nil, "", fmt.Errorf("%w: api_token is required for cloudflare", repository.ErrInvalidProviderCredential)
+		}
+		return map[string]string{"api_token": req.APIToken}, MaskSecret(req.APIToken), nil
+	case models.DNSProviderRoute53:
+		if req.AccessKeyID == "" || req.SecretAccessKey == "" {
+			return nil, "", fmt.Errorf("%w: access_key_id and secret_access_key are required for route53", repository.ErrInvalidProviderCredential)
+		}
+		return map[string]string{
+			"access_key_id":     req.AccessKeyID,
+			"secret_access_key": req.SecretAccessKey,
+		}, MaskSecret(req.AccessKeyID), nil
+	case models.DNSProviderGoogleCloudDNS:
+		key, err := clients.ParseGoogleServiceAccountKey(req.ServiceAccountJSON)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", repository.ErrInvalidProviderCredential, err)
+		}
+		if req.ProjectID == "" && key.ProjectID == "" {
+			return nil, "", fmt.Errorf("%w: project_id is required when the service account JSON has none", repository.ErrInvalidProviderCredential)
+		}
+		return map[string]string{"service_account_json": req.ServiceAccountJSON}, key.ClientEmail, nil
+	default:
+		return nil, "", fmt.Errorf("%w: unsupported provider %q", repository.ErrInvalidProviderCredential, req.Provider)
+	}
+}
+
+// providerCredentials decrypts a stored credential's secrets
+func (s *DomainService) providerCredentials(credential *models.ProviderCredential) (clients.DNSProviderCredentials, error) {
+	values, err := s.credentialCipher.DecryptMap(credential.SecretCiphertext, credentialAdditionalData(credential))
+	if err != nil {
+		return clients.DNSProviderCredentials{}, err
+	}
+
+	return clients.DNSProviderCredentials{
+		APIToken:           values["api_token"],
+		AccessKeyID:        values["access_key_id"],
+		SecretAccessKey:    values["secret_access_key"],
+		ServiceAccountJSON: values["service_account_json"],
+		ProjectID:          credential.ProjectID,
+	}, nil
+}
+
+// credentialAdditionalData binds a credential's ciphertext to its tenant, ID and provider
+func credentialAdditionalData(credential *models.ProviderCredential) string {
+	return credential.TenantID.String() + ":" + credential.ID.String() + ":" + string(credential.Provider)
+}
+
+// autoConfigurableRecords splits required records into those a DNS provider API can create and the rest
+func autoConfigurableRecords(required []models.DNSRecord) ([]models.DNSRecord, []models.DNSRecord) {
+	var records, skipped []models.DNSRecord
+	for _, record := range required {
+		if autoConfigurableRecordTypes[record.RecordType] {
+			records = append(records, record)
+		} else {
+			skipped = append(skipped, record)
+		}
+	}
+	return records, skipped
+}
+
+// applyDNSRecord compares a required record with the zone and creates or replaces it as needed
+func applyDNSRecord(ctx context.Context, client clients.DNSProviderClient, zoneID string, record models.DNSRecord, dryRun, overwrite bool) models.AutoConfigureRecordResult {
+	result := models.AutoConfigureRecordResult{
+		RecordType: record.RecordType,
+		Host:       record.Host,
+		Value:      record.Value,
+		TTL:        record.TTL,
+		Purpose:    record.Purpose,
+	}
+	if result.TTL <= 0 {
+		result.TTL = defaultAutoConfigureTTL
+	}
+
+	current, err := client.GetRecord(ctx, zoneID, record.RecordType, record.Host)
+	if err != nil {
+		result.Action = models.AutoConfigureActionFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.CurrentValues = current
+
+	switch {
+	case len(current) == 0:
+		result.Action = models.AutoConfigureActionCreated
+	case dnsRecordSatisfied(record.RecordType, current, record.Value):
+		result.Action = models.AutoConfigureActionUnchanged
+		return result
+	case !overwrite:
+		result.Action = models.AutoConfigureActionConflict
+		result.Error = "record exists with a different value; set overwrite to replace it"
+		return result
+	default:
+		result.Action = models.AutoConfigureActionUpdated
+	}
+
+	if dryRun {
+		return result
+	}
+
+	if err := client.UpsertRecord(ctx, zoneID, record.RecordType, record.Host, record.Value, result.TTL); err != nil {
+		result.Action = models.AutoConfigureActionFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// dnsRecordSatisfied reports whether a record set already resolves to the required value
+// A TXT value may sit next to other TXT values at the same name; other types must have exactly that value
+func dnsRecordSatisfied(recordType string, current []string, value string) bool {
+	if recordType == "TXT" {
+		for _, v := range current {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+	return len(current) == 1 && strings.EqualFold(strings.TrimSuffix(current[0], "."), strings.TrimSuffix(value, "."))
+}
+
+// autoConfigureMessage summarizes the record actions of an auto-configuration
+func autoConfigureMessage(counts map[string]int, dryRun bool) string {
+	changed := counts[models.AutoConfigureActionCreated] + counts[models.AutoConfigureActionUpdated]
+	prefix := fmt.Sprintf("%d records created or updated", changed)
+	if dryRun {
+		prefix = fmt.Sprintf("%d records would be created or updated", changed)
+	}
+
+	message := fmt.Sprintf("%s, %d already correct", prefix, counts[models.AutoConfigureActionUnchanged])
+	if n := counts[models.AutoConfigureActionConflict]; n > 0 {
+		message += fmt.Sprintf(", %d conflicting", n)
+	}
+	if n := counts[models.AutoConfigureActionFailed]; n > 0 {
+		message += fmt.Sprintf(", %d failed", n)
+	}
+	return message
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"custom-domain-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDNSProvider is an in-memory zone keyed by record type and name
+type fakeDNSProvider struct {
+	records  map[string][]string
+	upserted []string
+	failGet  bool
+}
+
+func (f *fakeDNSProvider) FindZone(ctx context.Context, domain string) (string, error) {
+	return "zone-1", nil
+}
+
+func (f *fakeDNSProvider) GetRecord(ctx context.Context, zoneID, recordType, name string) ([]string, error) {
+	if f.failGet {
+		return nil, errors.New("provider unavailable")
+	}
+	return f.records[recordType+" "+name], nil
+}
+
+func (f *fakeDNSProvider) UpsertRecord(ctx context.Context, zoneID, recordType, name, value string, ttl int) error {
+	f.records[recordType+" "+name] = []string{value}
+	f.upserted = append(f.upserted, recordType+" "+name)
+	return nil
+}
+
+func TestApplyDNSRecord(t *testing.T) {
+	ctx := context.Background()
+	aRecord := models.DNSRecord{RecordType: "A", Host: "shop.example.com", Value: "203.0.113.10", TTL: 300}
+	txtRecord := models.DNSRecord{RecordType: "TXT", Host: "_tesserix.shop.example.com", Value: "tesserix-verify=abc"}
+
+	t.Run("creates missing record", func(t *testing.T) {
+		provider := &fakeDNSProvider{records: map[string][]string{}}
+		result := applyDNSRecord(ctx, provider, "zone-1", aRecord, false, false)
+		assert.Equal(t, models.AutoConfigureActionCreated, result.Action)
+		assert.Equal(t, []string{"A shop.example.com"}, provider.upserted)
+	})
+
+	t.Run("dry run makes no changes", func(t *testing.T) {
+		provider := &fakeDNSProvider{records: map[string][]string{}}
+		result := applyDNSRecord(ctx, provider, "zone-1", aRecord, true, false)
+		assert.Equal(t, models.AutoConfigureActionCreated, result.Action)
+		assert.Empty(t, provider.upserted)
+	})
+
+	t.Run("leaves matching record alone", func(t *testing.T) {
+		provider := &fakeDNSProvider{records: map[string][]string{"A shop.example.com": {"203.0.113.10"}}}
+		result := applyDNSRecord(ctx, provider, "zone-1", aRecord, false, true)
+		assert.Equal(t, models.AutoConfigureActionUnchanged, result.Action)
+		assert.Empty(t, provider.upserted)
+	})
+
+	t.Run("reports conflict without overwrite", func(t *testing.T) {
+		provider := &fakeDNSProvider{records: map[string][]string{"A shop.example.com": {"198.51.100.1"}}}
+		result := applyDNSRecord(ctx, provider, "zone-1", aRecord, false, false)
+		assert.Equal(t, models.AutoConfigureActionConflict, result.Action)
+		assert.Equal(t, []string{"198.51.100.1"}, result.CurrentValues)
+		assert.Empty(t, provider.upserted)
+	})
+
+	t.Run("replaces conflicting record with overwrite", func(t *testing.T) {
+		provider := &fakeDNSProvider{records: map[string][]string{"A shop.example.com": {"198.51.100.1"}}}
+		result := applyDNSRecord(ctx, provider, "zone-1", aRecord, false, true)
+		assert.Equal(t, models.AutoConfigureActionUpdated, result.Action)
+		assert.Equal(t, []string{"203.0.113.10"}, provider.records["A shop.example.com"])
+	})
+
+	t.Run("TXT value next to other values is satisfied", func(t *testing.T) {
+		provider := &fakeDNSProvider{records: map[string][]string{
+			"TXT _tesserix.shop.example.com": {"v=spf1 -all", "tesserix-verify=abc"},
+		}}
+		result := applyDNSRecord(ctx, provider, "zone-1", txtRecord, false, false)
+		assert.Equal(t, models.AutoConfigureActionUnchanged, result.Action)
+		assert.Equal(t, defaultAutoConfigureTTL, result.TTL)
+	})
+
+	t.Run("provider error fails the record", func(t *testing.T) {
+		provider := &fakeDNSProvider{records: map[string][]string{}, failGet: true}
+		result := applyDNSRecord(ctx, provider, "zone-1", aRecord, false, false)
+		assert.Equal(t, models.AutoConfigureActionFailed, result.Action)
+		assert.Equal(t, "provider unavailable", result.Error)
+	})
+}
+
+func TestAutoConfigurableRecords(t *testing.T) {
+	records, skipped := autoConfigurableRecords([]models.DNSRecord{
+		{RecordType: "CNAME", Host: "shop.example.com"},
+		{RecordType: "HTTP_FILE", Host: "https://shop.example.com/.well-known/tesserix-verification.txt"},
+		{RecordType: "TXT", Host: "_tesserix.shop.example.com"},
+	})
+
+	assert.Len(t, records, 2)
+	assert.Len(t, skipped, 1)
+	assert.Equal(t, "HTTP_FILE", skipped[0].RecordType)
+}
+
+func TestProviderCredentialSecrets(t *testing.T) {
+	_, _, err := providerCredentialSecrets(&models.CreateProviderCredentialRequest{Provider: models.DNSProviderRoute53, AccessKeyID: "AKIAEXAMPLE"})
+	assert.Error(t, err)
+
+	_, _, err = providerCredentialSecrets(&models.CreateProviderCredentialRequest{Provider: models.DNSProviderGoogleCloudDNS, ServiceAccountJSON: "{}"})
+	assert.Error(t, err)
+
+	secrets, hint, err := providerCredentialSecrets(&models.CreateProviderCredentialRequest{
+		Provider:           models.DNSProviderGoogleCloudDNS,
+		ServiceAccountJSON: `{"project_id":"acme","client_email":"dns@acme.iam.gserviceaccount.com","private_key":"key"}`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "dns@acme.iam.gserviceaccount.com", hint)
+	assert.Contains(t, secrets, "service_account_json")
+}
+
+func TestCredentialCipherRoundTrip(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	cipher, err := NewCredentialCipher(key)
+	require.NoError(t, err)
+
+	ciphertext, err := cipher.EncryptMap(map[string]string{"api_token": "cf-token-1234"}, "tenant:credential")
+	require.NoError(t, err)
+	assert.NotContains(t, ciphertext, "cf-token-1234")
+
+	values, err := cipher.DecryptMap(ciphertext, "tenant:credential")
+	require.NoError(t, err)
+	assert.Equal(t, "cf-token-1234", values["api_token"])
+
+	// The ciphertext is bound to its owner
+	_, err = cipher.DecryptMap(ciphertext, "other-tenant:credential")
+	assert.Error(t, err)
+
+	disabled, err := NewCredentialCipher("")
+	require.NoError(t, err)
+	_, err = disabled.EncryptMap(map[string]string{"api_token": "x"}, "")
+	assert.ErrorIs(t, err, ErrCredentialEncryptionDisabled)
+}
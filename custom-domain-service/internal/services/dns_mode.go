@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"custom-domain-service/internal/models"
+	"custom-domain-service/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// DetectDNSMode re-detects the DNS mode of a domain and returns it with mode-specific setup instructions
+func (s *DomainService) DetectDNSMode(ctx context.Context, tenantID, domainID uuid.UUID) (*models.DNSModeResponse, error) {
+	domain, err := s.repo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if domain.TenantID != tenantID {
+		return nil, repository.ErrDomainNotFound
+	}
+
+	result := s.RefreshDNSMode(ctx, domain)
+
+	response := &models.DNSModeResponse{
+		DomainID:             domain.ID,
+		Domain:               domain.Domain,
+		Mode:                 domain.DNSMode,
+		Nameservers:          result.Nameservers,
+		ResolvedIPs:          result.ResolvedIPs,
+		CertValidationMethod: domain.CertValidationMethod,
+		SetupInstructions:    s.setupInstructions(domain),
+		Message:              result.Message,
+	}
+	if domain.DNSModeDetectedAt != nil {
+		v := domain.DNSModeDetectedAt.Format(time.RFC3339)
+		response.DetectedAt = &v
+	}
+
+	return response, nil
+}
+
+// RefreshDNSMode detects the domain's DNS mode and stores it along with the certificate validation method
+// Proxied domains are switched to DNS-01: Cloudflare terminates TLS and may redirect or cache
+// /.well-known/acme-challenge, so HTTP-01 tokens never reach our gateway reliably.
+// A tenant preferring HTTP-01 keeps it for non-wildcard domains, proxied or not.
+// An unknown result (records not in place yet) keeps the previously detected mode.
+func (s *DomainService) RefreshDNSMode(ctx context.Context, domain *models.CustomDomain) *DNSModeResult {
+	result := s.dnsVerifier.DetectDNSMode(ctx, domain.Domain)
+	if result.Mode == models.DNSModeUnknown {
+		return result
+	}
+
+	previousMode := domain.DNSMode
+	detected := *domain
+	detected.DNSMode = result.Mode
+	if result.Mode == models.DNSModeCloudflareProxied && detected.RequiresDNS01() && !domain.CNAMEDelegationEnabled && s.cfg.CNAMEDelegation.Enabled {
+		if err := s.repo.EnableCNAMEDelegation(ctx, domain.ID, true); err != nil {
+			log.Warn().Err(err).Str("domain", domain.Domain).Msg("Failed to enable CNAME delegation for proxied domain")
+		} else {
+			domain.CNAMEDelegationEnabled = true
+			detected.CNAMEDelegationEnabled = true
+			s.logActivity(ctx, domain, "cname_delegation_enabled", "success", "CNAME delegation enabled automatically - domain is proxied through Cloudflare")
+		}
+	}
+
+	method := detected.ACMEChallengeType()
+
+	if err := s.repo.UpdateDNSMode(ctx, domain.ID, result.Mode, method); err != nil {
+		log.Warn().Err(err).Str("domain", domain.Domain).Msg("Failed to store DNS mode")
+		return result
+	}
+	domain.DNSMode = result.Mode
+	domain.DNSModeDetectedAt = &result.CheckedAt
+	domain.CertValidationMethod = method
+
+	if previousMode != result.Mode {
+		log.Info().
+			Str("domain", domain.Domain).
+			Str("previous_mode", string(previousMode)).
+			Str("mode", string(result.Mode)).
+			Str("cert_validation", string(method)).
+			Msg("DNS mode detected")
+		s.logActivity(ctx, domain, "dns_mode_detected", "success", result.Message)
+	}
+
+	return result
+}
+
+// setupInstructions returns the setup steps that apply to the domain's DNS mode
+func (s *DomainService) setupInstructions(domain *models.CustomDomain) []string {
+	challengeHost := domain.GetACMEChallengeHost()
+	challengeTarget := domain.CNAMEDelegationTarget
+	if challengeTarget == "" {
+		challengeTarget = s.dnsVerifier.GetCNAMEDelegationTargetForTenant(domain.Domain, domain.TenantID.String())
+	}
+	routingTarget := s.cfg.DNS.ProxyIP
+	if routingTarget == "" {
+		routingTarget = s.cfg.DNS.ProxyDomain
+	}
+	hosts := strings.Join(domain.GetAllHosts(), ", ")
+
+	switch domain.DNSMode {
+	case models.DNSModeCloudflareProxied:
+		instructions := []string{
+			"Cloudflare proxy (orange cloud) detected. You can keep the proxy on.",
+			fmt.Sprintf("In Cloudflare, point %s to %s.", hosts, routingTarget),
+			"Set Cloudflare SSL/TLS encryption mode to Full (strict) to avoid redirect loops.",
+			"Keep the _tesserix verification record as DNS only (grey cloud).",
+		}
+		if s.cfg.CNAMEDelegation.Enabled {
+			instructions = append(instructions, fmt.Sprintf(
+				"Add %s CNAME %s as DNS only (grey cloud). Certificates are issued via DNS-01 because Cloudflare intercepts HTTP-01 challenges.",
+				challengeHost, challengeTarget))
+		} else {
+			instructions = append(instructions, "Certificates cannot be issued while the proxy is on. Turn the proxy off (grey cloud) until the certificate is active.")
+		}
+		return instructions
+	case models.DNSModeCloudflareDNSOnly:
+		instructions := []string{
+			"Cloudflare DNS detected with the proxy off (grey cloud). Keep the records DNS only until the certificate is active.",
+			fmt.Sprintf("Point %s to %s.", hosts, routingTarget),
+		}
+		if s.cfg.CNAMEDelegation.Enabled {
+			instructions = append(instructions, fmt.Sprintf(
+				"To turn the proxy on later, first add %s CNAME %s (DNS only) so certificate renewals keep working.",
+				challengeHost, challengeTarget))
+		}
+		return instructions
+	case models.DNSModeDirect:
+		return []string{
+			fmt.Sprintf("Point %s to %s.", hosts, routingTarget),
+			"The certificate is issued automatically via HTTP-01 once the records resolve to our gateway.",
+		}
+	default:
+		return []string{
+			fmt.Sprintf("Point %s to %s and add the verification record.", hosts, routingTarget),
+			"If your DNS is hosted on Cloudflare, we detect whether the proxy is on once the records resolve.",
+		}
+	}
+}
@@ -4,23 +4,37 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"strings"
 	"time"
 
 	"custom-domain-service/internal/config"
 	"custom-domain-service/internal/models"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 )
 
 // DNSVerifier handles DNS record verification
 type DNSVerifier struct {
-	cfg      *config.Config
-	resolver *net.Resolver
+	cfg                *config.Config
+	resolver           *net.Resolver
+	cloudflareIPRanges []*net.IPNet
+	httpClient         *http.Client // For http_file and meta_tag verification
 }
 
 // NewDNSVerifier creates a new DNS verifier
 func NewDNSVerifier(cfg *config.Config) *DNSVerifier {
+	var cloudflareIPRanges []*net.IPNet
+	for _, cidr := range cfg.DNS.CloudflareIPRanges {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Warn().Err(err).Str("cidr", cidr).Msg("Ignoring invalid Cloudflare IP range")
+			continue
+		}
+		cloudflareIPRanges = append(cloudflareIPRanges, ipNet)
+	}
+
 	// Use the system's default DNS resolver (CoreDNS in Kubernetes)
 	// This works within cluster network policies unlike hardcoded external DNS
 	return &DNSVerifier{
@@ -29,6 +43,8 @@ func NewDNSVerifier(cfg *config.Config) *DNSVerifier {
 			PreferGo: true,
 			// Use default dialer which respects /etc/resolv.conf (CoreDNS in K8s)
 		},
+		cloudflareIPRanges: cloudflareIPRanges,
+		httpClient:         newVerificationHTTPClient(cfg.DNS.HTTPVerificationTimeout),
 	}
 }
 
@@ -50,20 +66,40 @@ type CNAMEDelegationResult struct {
 	CheckedAt      time.Time
 }
 
+// DNSModeResult contains the result of DNS mode detection
+type DNSModeResult struct {
+	Mode        models.DNSMode
+	Nameservers []string
+	ResolvedIPs []string
+	Message     string
+	CheckedAt   time.Time
+}
+
 // VerifyDomain verifies DNS configuration for a domain
 func (v *DNSVerifier) VerifyDomain(ctx context.Context, domain *models.CustomDomain) (*VerificationResult, error) {
 	result := &VerificationResult{
 		CheckedAt: time.Now(),
 	}
 
+	var err error
 	switch domain.VerificationMethod {
 	case models.VerificationMethodTXT:
-		return v.verifyTXTRecord(ctx, domain, result)
+		result, err = v.verifyTXTRecord(ctx, domain, result)
 	case models.VerificationMethodCNAME:
-		return v.verifyCNAMERecord(ctx, domain, result)
+		result, err = v.verifyCNAMERecord(ctx, domain, result)
+	case models.VerificationMethodHTTPFile:
+		result, err = v.verifyHTTPFile(ctx, domain, result)
+	case models.VerificationMethodMetaTag:
+		result, err = v.verifyMetaTag(ctx, domain, result)
 	default:
-		return v.verifyTXTRecord(ctx, domain, result)
+		result, err = v.verifyTXTRecord(ctx, domain, result)
+	}
+	if err != nil || !result.IsVerified || !domain.IsWildcard() {
+		return result, err
 	}
+
+	// Ownership of the base domain is not enough for a wildcard: the *.<base> record must reach us too
+	return v.verifyWildcardRouting(ctx, domain, result), nil
 }
 
 // VerifyCNAMEDelegation checks if _acme-challenge.{domain} CNAME points to our ACME zone
@@ -89,7 +125,7 @@ func (v *DNSVerifier) VerifyCNAMEDelegationWithTarget(ctx context.Context, domai
 	}
 
 	// Check CNAME record for _acme-challenge.{domain}
-	challengeHost := "_acme-challenge." + domain
+	challengeHost := "_acme-challenge." + recordDomain(domain)
 
 	cname, err := v.resolver.LookupCNAME(ctx, challengeHost)
 	if err != nil {
@@ -138,7 +174,7 @@ func (v *DNSVerifier) VerifyCNAMEDelegationWithTarget(ctx context.Context, domai
 // For tenant-specific targets, use GetCNAMEDelegationTargetForTenant
 func (v *DNSVerifier) GetCNAMEDelegationTarget(domain string) string {
 	// Sanitize domain name: replace dots with dashes
-	sanitized := strings.ReplaceAll(domain, ".", "-")
+	sanitized := strings.ReplaceAll(recordDomain(domain), ".", "-")
 	return sanitized + "." + v.cfg.CNAMEDelegation.ACMEZone
 }
 
@@ -146,7 +182,7 @@ func (v *DNSVerifier) GetCNAMEDelegationTarget(domain string) string {
 // Deprecated: Use GetCNAMEDelegationTargetForSlug instead
 func (v *DNSVerifier) GetCNAMEDelegationTargetForTenant(domain, tenantID string) string {
 	// Sanitize domain name: replace dots with dashes
-	sanitized := strings.ReplaceAll(domain, ".", "-")
+	sanitized := strings.ReplaceAll(recordDomain(domain), ".", "-")
 
 	tenantShort := ""
 	if len(tenantID) >= 8 {
@@ -169,7 +205,7 @@ func (v *DNSVerifier) GetCNAMEDelegationTargetForTenant(domain, tenantID string)
 // The slug is the unique business/store identifier available during onboarding
 func (v *DNSVerifier) GetCNAMEDelegationTargetForSlug(domain, slug string) string {
 	// Sanitize domain name: replace dots with dashes
-	sanitizedDomain := strings.ReplaceAll(domain, ".", "-")
+	sanitizedDomain := strings.ReplaceAll(recordDomain(domain), ".", "-")
 
 	// Sanitize slug: lowercase, replace spaces/special chars with dashes
 	sanitizedSlug := strings.ToLower(slug)
@@ -194,7 +230,7 @@ func (v *DNSVerifier) GetCNAMEDelegationRecord(domain string) *models.DNSRecord
 		return nil
 	}
 
-	challengeHost := "_acme-challenge." + domain
+	challengeHost := "_acme-challenge." + recordDomain(domain)
 	target := v.GetCNAMEDelegationTarget(domain)
 
 	return &models.DNSRecord{
@@ -214,7 +250,7 @@ func (v *DNSVerifier) GetCNAMEDelegationRecordForTenant(domain, tenantID string)
 		return nil
 	}
 
-	challengeHost := "_acme-challenge." + domain
+	challengeHost := "_acme-challenge." + recordDomain(domain)
 	target := v.GetCNAMEDelegationTargetForTenant(domain, tenantID)
 
 	return &models.DNSRecord{
@@ -230,7 +266,7 @@ func (v *DNSVerifier) GetCNAMEDelegationRecordForTenant(domain, tenantID string)
 // verifyTXTRecord verifies TXT record for domain ownership
 func (v *DNSVerifier) verifyTXTRecord(ctx context.Context, domain *models.CustomDomain, result *VerificationResult) (*VerificationResult, error) {
 	// Expected TXT record format: _tesserix-verification.example.com TXT "tesserix-verify=<token>"
-	verificationHost := "_tesserix-verification." + domain.Domain
+	verificationHost := "_tesserix-verification." + domain.RecordDomain()
 	expectedValue := "tesserix-verify=" + domain.VerificationToken
 
 	result.ExpectedRecord = expectedValue
@@ -267,6 +303,50 @@ func (v *DNSVerifier) verifyTXTRecord(ctx context.Context, domain *models.Custom
 	return result, nil
 }
 
+// CheckTXTRecord checks that host has a TXT record with the expected value
+// Used by other services (e.g. tenant-service email domain verification) that hold their own tokens
+func (v *DNSVerifier) CheckTXTRecord(ctx context.Context, host, expectedValue string) *models.VerifyTXTResponse {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	result := &models.VerifyTXTResponse{
+		Host:          host,
+		ExpectedValue: expectedValue,
+	}
+
+	txtRecords, err := v.resolver.LookupTXT(ctx, host)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			result.Message = fmt.Sprintf("TXT record not found at %s", host)
+			return result
+		}
+		log.Warn().Err(err).Str("host", host).Msg("DNS lookup failed")
+		result.Message = "DNS lookup failed. Please try again later."
+		return result
+	}
+
+	result.RecordsFound = txtRecords
+	if txtRecordMatches(txtRecords, expectedValue) {
+		result.Verified = true
+		result.Message = "TXT record verified"
+	} else if len(txtRecords) > 0 {
+		result.Message = fmt.Sprintf("TXT record found but value doesn't match. Expected: %s", expectedValue)
+	} else {
+		result.Message = fmt.Sprintf("No TXT records found at %s", host)
+	}
+	return result
+}
+
+// txtRecordMatches reports whether any record equals the expected value, ignoring surrounding
+// whitespace and quotes that some DNS panels keep in the value
+func txtRecordMatches(records []string, expectedValue string) bool {
+	expectedValue = strings.TrimSpace(expectedValue)
+	for _, txt := range records {
+		if strings.Trim(strings.TrimSpace(txt), `"`) == expectedValue {
+			return true
+		}
+	}
+	return false
+}
+
 // verifyCNAMERecord verifies CNAME record with unique verification token in subdomain
 // Format: _tesserix-<token>.<domain> → verify.tesserix.app
 func (v *DNSVerifier) verifyCNAMERecord(ctx context.Context, domain *models.CustomDomain, result *VerificationResult) (*VerificationResult, error) {
@@ -277,7 +357,7 @@ func (v *DNSVerifier) verifyCNAMERecord(ctx context.Context, domain *models.Cust
 	}
 
 	// Verification CNAME subdomain with unique token
-	verificationHost := "_tesserix-" + shortToken + "." + domain.Domain
+	verificationHost := "_tesserix-" + shortToken + "." + domain.RecordDomain()
 	expectedTarget := "verify.tesserix.app"
 
 	result.ExpectedRecord = fmt.Sprintf("%s CNAME %s", verificationHost, expectedTarget)
@@ -393,7 +473,7 @@ func (v *DNSVerifier) GetRequiredDNSRecords(domain *models.CustomDomain) []model
 	// Customer adds: _acme-challenge.theirdomain.com CNAME theirdomain-com-{tenant-short-id}.acme.tesserix.app
 	// This ensures each tenant gets a unique target, preventing cross-tenant certificate hijacking
 	if v.cfg.CNAMEDelegation.Enabled && domain.CNAMEDelegationEnabled {
-		challengeHost := "_acme-challenge." + domain.Domain
+		challengeHost := domain.GetACMEChallengeHost()
 		// Use stored CNAME target from database (tenant-specific)
 		// Fall back to generating if not stored (backward compatibility)
 		target := domain.CNAMEDelegationTarget
@@ -410,19 +490,7 @@ func (v *DNSVerifier) GetRequiredDNSRecords(domain *models.CustomDomain) []model
 		})
 	}
 
-	// Determine CNAME target based on configuration
-	// Priority: Cloudflare Tunnel > Tenant subdomain > Proxy domain
-	cnameTarget := v.cfg.DNS.ProxyDomain
-	cnameDescription := "proxy domain"
-
-	if v.cfg.Cloudflare.Enabled && v.cfg.Cloudflare.TunnelID != "" {
-		// Use Cloudflare Tunnel CNAME (preferred - no verification record needed)
-		cnameTarget = fmt.Sprintf("%s.cfargotunnel.com", v.cfg.Cloudflare.TunnelID)
-		cnameDescription = "Cloudflare Tunnel"
-	} else if domain.TenantSlug != "" && v.cfg.DNS.PlatformDomain != "" {
-		cnameTarget = domain.TenantSlug + "." + v.cfg.DNS.PlatformDomain
-		cnameDescription = "tenant subdomain"
-	}
+	cnameTarget, cnameDescription := v.routingCNAMETarget(domain)
 
 	// Short token for CNAME verification (first 8 chars)
 	shortToken := ""
@@ -433,20 +501,39 @@ func (v *DNSVerifier) GetRequiredDNSRecords(domain *models.CustomDomain) []model
 	// Verification record needed for ownership proof
 	// CNAME method: _tesserix-<token>.<domain> → verify.tesserix.app
 	// TXT method: _tesserix.<domain> TXT "tesserix-verify=<full-token>"
-	if domain.VerificationMethod == models.VerificationMethodCNAME {
+	// HTTP file method: https://<domain>/.well-known/tesserix-verification.txt containing "tesserix-verify=<full-token>"
+	// Meta tag method: <meta name="tesserix-verification" content="<full-token>"> on https://<domain>/
+	switch domain.VerificationMethod {
+	case models.VerificationMethodHTTPFile:
+		records = append(records, models.DNSRecord{
+			RecordType: "HTTP_FILE",
+			Host:       "https://" + domain.Domain + VerificationFilePath,
+			Value:      "tesserix-verify=" + domain.VerificationToken,
+			Purpose:    "verification",
+			IsVerified: domain.DNSVerified,
+		})
+	case models.VerificationMethodMetaTag:
+		records = append(records, models.DNSRecord{
+			RecordType: "META_TAG",
+			Host:       "https://" + domain.Domain + "/",
+			Value:      VerificationMetaTag(domain.VerificationToken),
+			Purpose:    "verification",
+			IsVerified: domain.DNSVerified,
+		})
+	case models.VerificationMethodCNAME:
 		records = append(records, models.DNSRecord{
 			RecordType: "CNAME",
-			Host:       "_tesserix-" + shortToken + "." + domain.Domain,
+			Host:       "_tesserix-" + shortToken + "." + domain.RecordDomain(),
 			Value:      "verify.tesserix.app",
 			TTL:        300,
 			Purpose:    "verification",
 			IsVerified: domain.DNSVerified,
 		})
-	} else {
+	default:
 		// TXT verification
 		records = append(records, models.DNSRecord{
 			RecordType: "TXT",
-			Host:       "_tesserix." + domain.Domain,
+			Host:       "_tesserix." + domain.RecordDomain(),
 			Value:      "tesserix-verify=" + domain.VerificationToken,
 			TTL:        300,
 			Purpose:    "verification",
@@ -489,25 +576,26 @@ func (v *DNSVerifier) GetRequiredDNSRecords(domain *models.CustomDomain) []model
 			})
 		}
 
-		// Admin subdomain
-		records = append(records, models.DNSRecord{
-			RecordType: "A",
-			Host:       "admin." + domain.Domain,
-			Value:      v.cfg.DNS.ProxyIP,
-			TTL:        300,
-			Purpose:    "routing (LoadBalancer IP)",
-			IsVerified: domain.DNSVerified,
-		})
+		// Admin and API subdomains (a wildcard record already covers every label under its base)
+		if !domain.IsWildcard() {
+			records = append(records, models.DNSRecord{
+				RecordType: "A",
+				Host:       "admin." + domain.Domain,
+				Value:      v.cfg.DNS.ProxyIP,
+				TTL:        300,
+				Purpose:    "routing (LoadBalancer IP)",
+				IsVerified: domain.DNSVerified,
+			})
 
-		// API subdomain
-		records = append(records, models.DNSRecord{
-			RecordType: "A",
-			Host:       "api." + domain.Domain,
-			Value:      v.cfg.DNS.ProxyIP,
-			TTL:        300,
-			Purpose:    "routing (LoadBalancer IP)",
-			IsVerified: domain.DNSVerified,
-		})
+			records = append(records, models.DNSRecord{
+				RecordType: "A",
+				Host:       "api." + domain.Domain,
+				Value:      v.cfg.DNS.ProxyIP,
+				TTL:        300,
+				Purpose:    "routing (LoadBalancer IP)",
+				IsVerified: domain.DNSVerified,
+			})
+		}
 	} else {
 		// Fallback: CNAME records (only works if not on Cloudflare or using same account)
 		if domain.DomainType == models.DomainTypeApex {
@@ -546,6 +634,76 @@ func (v *DNSVerifier) GetRequiredDNSRecords(domain *models.CustomDomain) []model
 	return records
 }
 
+// routingCNAMETarget returns the CNAME target a domain's routing record points to and a description of it
+// Priority: Cloudflare Tunnel > Tenant subdomain > Proxy domain
+func (v *DNSVerifier) routingCNAMETarget(domain *models.CustomDomain) (string, string) {
+	if v.cfg.Cloudflare.Enabled && v.cfg.Cloudflare.TunnelID != "" {
+		// Use Cloudflare Tunnel CNAME (preferred - no verification record needed)
+		return fmt.Sprintf("%s.cfargotunnel.com", v.cfg.Cloudflare.TunnelID), "Cloudflare Tunnel"
+	}
+	if domain.TenantSlug != "" && v.cfg.DNS.PlatformDomain != "" {
+		return domain.TenantSlug + "." + v.cfg.DNS.PlatformDomain, "tenant subdomain"
+	}
+	return v.cfg.DNS.ProxyDomain, "proxy domain"
+}
+
+// verifyWildcardRouting checks that an arbitrary host under a wildcard domain reaches the platform
+// The base domain usually resolves on its own, so a missing *.<base> record would otherwise go
+// unnoticed until the first microsite fails to load.
+func (v *DNSVerifier) verifyWildcardRouting(ctx context.Context, domain *models.CustomDomain, result *VerificationResult) *VerificationResult {
+	if domain.IsCloudflareProxied() {
+		// Cloudflare answers with its own edge IPs, so the origin behind the proxy cannot be checked via DNS
+		return result
+	}
+
+	probe := wildcardProbeHost(domain.Domain)
+	if v.cfg.DNS.ProxyIP != "" {
+		valid, ips, err := v.CheckARecord(ctx, probe)
+		if err != nil {
+			log.Warn().Err(err).Str("host", probe).Msg("Wildcard DNS lookup failed")
+			result.IsVerified = false
+			result.Message = "Ownership verified, but the wildcard DNS lookup failed. Please try again later."
+			return result
+		}
+		if !valid {
+			result.IsVerified = false
+			result.Message = fmt.Sprintf("Ownership verified, but hosts under %s do not resolve to %s yet (found %v). Please add: %s A %s",
+				domain.RecordDomain(), v.cfg.DNS.ProxyIP, ips, domain.Domain, v.cfg.DNS.ProxyIP)
+		}
+		return result
+	}
+
+	target, _ := v.routingCNAMETarget(domain)
+	cname, err := v.resolver.LookupCNAME(ctx, probe)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); !ok || !dnsErr.IsNotFound {
+			log.Warn().Err(err).Str("host", probe).Msg("Wildcard CNAME lookup failed")
+		}
+		result.IsVerified = false
+		result.Message = fmt.Sprintf("Ownership verified, but hosts under %s do not resolve yet. Please add: %s CNAME %s",
+			domain.RecordDomain(), domain.Domain, target)
+		return result
+	}
+
+	cname = strings.TrimSuffix(cname, ".")
+	if !strings.EqualFold(cname, target) {
+		result.IsVerified = false
+		result.Message = fmt.Sprintf("Ownership verified, but %s points to %s instead of %s", domain.Domain, cname, target)
+	}
+	return result
+}
+
+// wildcardProbeHost returns a random host under a wildcard domain's base
+// Nobody creates a record for it explicitly, so it can only resolve through the wildcard record.
+func wildcardProbeHost(domain string) string {
+	return "tesserix-probe-" + uuid.New().String()[:8] + "." + recordDomain(domain)
+}
+
+// recordDomain strips the wildcard label, which cannot hold verification or challenge records itself
+func recordDomain(domain string) string {
+	return strings.TrimPrefix(domain, models.WildcardPrefix)
+}
+
 // GetTunnelCNAMETarget returns the Cloudflare tunnel CNAME target
 func (v *DNSVerifier) GetTunnelCNAMETarget() string {
 	if v.cfg.Cloudflare.Enabled && v.cfg.Cloudflare.TunnelID != "" {
@@ -554,8 +712,12 @@ func (v *DNSVerifier) GetTunnelCNAMETarget() string {
 	return ""
 }
 
-// DetectDomainType determines if domain is apex or subdomain
+// DetectDomainType determines if domain is apex, subdomain or wildcard
 func (v *DNSVerifier) DetectDomainType(domain string) models.DomainType {
+	if strings.HasPrefix(domain, models.WildcardPrefix) {
+		return models.DomainTypeWildcard
+	}
+
 	parts := strings.Split(domain, ".")
 	// If domain has more than 2 parts (e.g., shop.example.com), it's a subdomain
 	// Exception for common TLDs like co.uk, com.au, etc.
@@ -584,8 +746,23 @@ func (v *DNSVerifier) ValidateDomainFormat(domain string) error {
 		return fmt.Errorf("domain exceeds maximum length of 253 characters")
 	}
 
-	// Check for valid characters
+	// Wildcard domains cover one label under a base that must itself be a valid custom domain
 	domain = strings.ToLower(domain)
+	if base, ok := strings.CutPrefix(domain, models.WildcardPrefix); ok {
+		if strings.Contains(base, "*") {
+			return fmt.Errorf("only a single leading wildcard label is supported")
+		}
+		if err := v.ValidateDomainFormat(base); err != nil {
+			return err
+		}
+		// A host directly under a public suffix (e.g. x.co.uk) is its own registrable domain
+		if v.getBaseDomain("x."+base) == "x."+base {
+			return fmt.Errorf("wildcard domains must be under a registered domain")
+		}
+		return nil
+	}
+
+	// Check for valid characters
 	for i, r := range domain {
 		if !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '.') {
 			return fmt.Errorf("invalid character '%c' at position %d", r, i)
@@ -706,8 +883,88 @@ func (v *DNSVerifier) getBaseDomain(domain string) string {
 	return parts[len(parts)-2] + "." + parts[len(parts)-1]
 }
 
+// DetectDNSMode determines whether a domain is served through Cloudflare's proxy
+// Proxied records resolve to Cloudflare edge IPs instead of our gateway, which hides the origin
+// from routing checks and breaks HTTP-01 challenges when Cloudflare redirects or caches them
+func (v *DNSVerifier) DetectDNSMode(ctx context.Context, domainName string) *DNSModeResult {
+	result := &DNSModeResult{
+		Mode:      models.DNSModeUnknown,
+		CheckedAt: time.Now(),
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Wildcard names cannot be looked up directly, so resolve a host the wildcard record covers
+	lookupHost := domainName
+	if strings.HasPrefix(domainName, models.WildcardPrefix) {
+		lookupHost = wildcardProbeHost(domainName)
+	}
+
+	if ns, err := v.resolver.LookupNS(checkCtx, v.getBaseDomain(domainName)); err == nil {
+		for _, record := range ns {
+			result.Nameservers = append(result.Nameservers, strings.TrimSuffix(strings.ToLower(record.Host), "."))
+		}
+	} else {
+		log.Debug().Err(err).Str("domain", domainName).Msg("NS lookup failed during DNS mode detection")
+	}
+
+	ips, err := v.resolver.LookupIP(checkCtx, "ip", lookupHost)
+	if err != nil {
+		log.Debug().Err(err).Str("domain", domainName).Msg("IP lookup failed during DNS mode detection")
+	}
+	for _, ip := range ips {
+		result.ResolvedIPs = append(result.ResolvedIPs, ip.String())
+	}
+
+	result.Mode = v.classifyDNSMode(ips, result.Nameservers)
+	switch result.Mode {
+	case models.DNSModeCloudflareProxied:
+		result.Message = "Domain is proxied through Cloudflare (orange cloud); certificates will be issued via DNS-01"
+	case models.DNSModeCloudflareDNSOnly:
+		result.Message = "Domain uses Cloudflare DNS with the proxy disabled (grey cloud)"
+	case models.DNSModeDirect:
+		result.Message = "Domain resolves directly to its origin"
+	default:
+		result.Message = "Domain does not resolve yet; DNS mode will be detected once records are in place"
+	}
+	return result
+}
+
+// classifyDNSMode derives the DNS mode from resolved IPs and nameservers
+func (v *DNSVerifier) classifyDNSMode(ips []net.IP, nameservers []string) models.DNSMode {
+	for _, ip := range ips {
+		if v.isCloudflareIP(ip) {
+			return models.DNSModeCloudflareProxied
+		}
+	}
+	if len(ips) == 0 {
+		return models.DNSModeUnknown
+	}
+	for _, ns := range nameservers {
+		if strings.HasSuffix(ns, ".ns.cloudflare.com") {
+			return models.DNSModeCloudflareDNSOnly
+		}
+	}
+	return models.DNSModeDirect
+}
+
+func (v *DNSVerifier) isCloudflareIP(ip net.IP) bool {
+	for _, ipNet := range v.cloudflareIPRanges {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsRoutingConfigured checks if DNS is properly configured for routing
 func (v *DNSVerifier) IsRoutingConfigured(ctx context.Context, domain *models.CustomDomain) (bool, string, error) {
+	if domain.IsCloudflareProxied() {
+		// Cloudflare answers with its own edge IPs, so the origin behind the proxy cannot be checked via DNS
+		return true, "Domain is proxied through Cloudflare; the origin must be set to our gateway in Cloudflare", nil
+	}
+
 	if domain.DomainType == models.DomainTypeApex {
 		// Check A record
 		valid, ips, err := v.CheckARecord(ctx, domain.Domain)
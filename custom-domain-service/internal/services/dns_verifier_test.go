@@ -1,6 +1,7 @@
 package services
 
 import (
+	"net"
 	"testing"
 
 	"custom-domain-service/internal/config"
@@ -78,6 +79,41 @@ func TestDNSVerifier_ValidateDomainFormat(t *testing.T) {
 			domain:  "exam_ple.com",
 			wantErr: true,
 		},
+		{
+			name:    "wildcard subdomain",
+			domain:  "*.shop.example.com",
+			wantErr: false,
+		},
+		{
+			name:    "wildcard under registered domain",
+			domain:  "*.example.co.uk",
+			wantErr: false,
+		},
+		{
+			name:    "wildcard under tld",
+			domain:  "*.com",
+			wantErr: true,
+		},
+		{
+			name:    "wildcard under two-part tld",
+			domain:  "*.co.uk",
+			wantErr: true,
+		},
+		{
+			name:    "nested wildcard",
+			domain:  "*.*.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "wildcard not leading",
+			domain:  "shop.*.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "wildcard platform domain",
+			domain:  "*.tesserix.app",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,6 +162,11 @@ func TestDNSVerifier_DetectDomainType(t *testing.T) {
 			domain:   "shop.example.co.uk",
 			expected: models.DomainTypeSubdomain,
 		},
+		{
+			name:     "wildcard",
+			domain:   "*.shop.example.com",
+			expected: models.DomainTypeWildcard,
+		},
 	}
 
 	for _, tt := range tests {
@@ -218,3 +259,253 @@ func TestDNSVerifier_GetRequiredDNSRecords(t *testing.T) {
 		assert.True(t, cnameFound, "CNAME record should be present for subdomain")
 	})
 }
+
+func TestDNSVerifier_ClassifyDNSMode(t *testing.T) {
+	cfg := &config.Config{
+		DNS: config.DNSConfig{
+			VerificationDomain: "tesserix.app",
+			ProxyDomain:        "proxy.tesserix.app",
+			CloudflareIPRanges: []string{"104.16.0.0/13", "2606:4700::/32"},
+		},
+	}
+	verifier := NewDNSVerifier(cfg)
+
+	cloudflareNS := []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"}
+	otherNS := []string{"ns1.registrar.com"}
+
+	tests := []struct {
+		name        string
+		ips         []string
+		nameservers []string
+		want        models.DNSMode
+	}{
+		{
+			name:        "proxied IPv4",
+			ips:         []string{"104.21.32.1"},
+			nameservers: cloudflareNS,
+			want:        models.DNSModeCloudflareProxied,
+		},
+		{
+			name:        "proxied IPv6",
+			ips:         []string{"2606:4700:3030::6815:2001"},
+			nameservers: cloudflareNS,
+			want:        models.DNSModeCloudflareProxied,
+		},
+		{
+			name:        "cloudflare dns only",
+			ips:         []string{"34.120.10.20"},
+			nameservers: cloudflareNS,
+			want:        models.DNSModeCloudflareDNSOnly,
+		},
+		{
+			name:        "direct",
+			ips:         []string{"34.120.10.20"},
+			nameservers: otherNS,
+			want:        models.DNSModeDirect,
+		},
+		{
+			name:        "no records",
+			ips:         nil,
+			nameservers: cloudflareNS,
+			want:        models.DNSModeUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ips []net.IP
+			for _, ip := range tt.ips {
+				ips = append(ips, net.ParseIP(ip))
+			}
+			assert.Equal(t, tt.want, verifier.classifyDNSMode(ips, tt.nameservers))
+		})
+	}
+}
+
+func TestDNSVerifier_GetRequiredDNSRecords_HTTPMethods(t *testing.T) {
+	cfg := &config.Config{
+		DNS: config.DNSConfig{
+			VerificationDomain: "tesserix.app",
+			ProxyDomain:        "proxy.tesserix.app",
+		},
+	}
+	verifier := NewDNSVerifier(cfg)
+
+	t.Run("http file", func(t *testing.T) {
+		domain := &models.CustomDomain{
+			Domain:             "shop.example.com",
+			DomainType:         models.DomainTypeSubdomain,
+			VerificationMethod: models.VerificationMethodHTTPFile,
+			VerificationToken:  "test-token-789",
+		}
+
+		records := verifier.GetRequiredDNSRecords(domain)
+
+		assert.Len(t, records, 2) // HTTP_FILE + CNAME, no TXT record
+		assert.Equal(t, "HTTP_FILE", records[0].RecordType)
+		assert.Equal(t, "https://shop.example.com/.well-known/tesserix-verification.txt", records[0].Host)
+		assert.Equal(t, "tesserix-verify=test-token-789", records[0].Value)
+		for _, r := range records {
+			assert.NotEqual(t, "TXT", r.RecordType)
+		}
+	})
+
+	t.Run("meta tag", func(t *testing.T) {
+		domain := &models.CustomDomain{
+			Domain:             "shop.example.com",
+			DomainType:         models.DomainTypeSubdomain,
+			VerificationMethod: models.VerificationMethodMetaTag,
+			VerificationToken:  "test-token-789",
+		}
+
+		records := verifier.GetRequiredDNSRecords(domain)
+
+		assert.Len(t, records, 2) // META_TAG + CNAME
+		assert.Equal(t, "META_TAG", records[0].RecordType)
+		assert.Equal(t, `<meta name="tesserix-verification" content="test-token-789">`, records[0].Value)
+	})
+}
+
+func TestFindVerificationMetaContents(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want []string
+	}{
+		{
+			name: "double quoted",
+			page: `<html><head><meta name="tesserix-verification" content="abc123"></head></html>`,
+			want: []string{"abc123"},
+		},
+		{
+			name: "content before name, single quotes, self closing",
+			page: `<head><META content='abc123' NAME='Tesserix-Verification' /></head>`,
+			want: []string{"abc123"},
+		},
+		{
+			name: "multiple tags",
+			page: `<meta name="tesserix-verification" content="old"><meta name="tesserix-verification" content="new">`,
+			want: []string{"old", "new"},
+		},
+		{
+			name: "other meta tags ignored",
+			page: `<meta name="description" content="abc123"><meta property="og:title" content="Shop">`,
+			want: nil,
+		},
+		{
+			name: "no meta tags",
+			page: `<p>tesserix-verification abc123</p>`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FindVerificationMetaContents(tt.page))
+		})
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.0.0.5", false},
+		{"172.16.3.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.want, isPublicIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestTXTRecordMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []string
+		want    bool
+	}{
+		{"exact", []string{"v=spf1 -all", "tesserix-join=abc123"}, true},
+		{"quoted by dns panel", []string{`"tesserix-join=abc123"`}, true},
+		{"surrounding whitespace", []string{" tesserix-join=abc123 "}, true},
+		{"different token", []string{"tesserix-join=zzz"}, false},
+		{"prefix only", []string{"tesserix-join=abc1234"}, false},
+		{"no records", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, txtRecordMatches(tt.records, "tesserix-join=abc123"))
+		})
+	}
+}
+
+func TestDNSVerifier_WildcardDomainRecords(t *testing.T) {
+	cfg := &config.Config{
+		DNS: config.DNSConfig{
+			VerificationDomain: "tesserix.app",
+			ProxyDomain:        "proxy.tesserix.app",
+			ProxyIP:            "1.2.3.4",
+		},
+		CNAMEDelegation: config.CNAMEDelegationConfig{
+			Enabled:  true,
+			ACMEZone: "acme.tesserix.app",
+		},
+	}
+	verifier := NewDNSVerifier(cfg)
+
+	domain := &models.CustomDomain{
+		Domain:                 "*.shop.example.com",
+		DomainType:             models.DomainTypeWildcard,
+		VerificationMethod:     models.VerificationMethodTXT,
+		VerificationToken:      "test-token-789",
+		CNAMEDelegationEnabled: true,
+	}
+	assert.Equal(t, "shop.example.com", domain.RecordDomain())
+	assert.Equal(t, "_acme-challenge.shop.example.com", domain.GetACMEChallengeHost())
+	assert.Equal(t, []string{"*.shop.example.com"}, domain.GetAllHosts())
+
+	// The wildcard label never appears in the delegation target
+	target := verifier.GetCNAMEDelegationTargetForTenant(domain.Domain, "12345678-aaaa")
+	assert.Equal(t, "shop-example-com-12345678.acme.tesserix.app", target)
+
+	hosts := make(map[string]string)
+	for _, r := range verifier.GetRequiredDNSRecords(domain) {
+		hosts[r.Host] = r.RecordType
+	}
+	assert.Equal(t, map[string]string{
+		"_acme-challenge.shop.example.com": "CNAME",
+		"_tesserix.shop.example.com":       "TXT",
+		"*.shop.example.com":               "A",
+	}, hosts)
+}
+
+func TestWildcardPatternFor(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"east.shop.example.com", "*.shop.example.com"},
+		{"shop.example.com", "*.example.com"},
+		{"example.com", ""},
+		{"*.shop.example.com", ""},
+		{"localhost", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.WildcardPatternFor(tt.host))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -28,6 +29,10 @@ type DomainService struct {
 	cloudflare     *clients.CloudflareClient
 	redisClient    *redis.Client
 	eventPublisher *events.Publisher
+
+	// DNS provider credentials for auto-configuring customer zones
+	credentialCipher     *CredentialCipher
+	newDNSProviderClient func(models.DNSProvider, clients.DNSProviderCredentials) (clients.DNSProviderClient, error)
 }
 
 // NewDomainService creates a new domain service
@@ -41,6 +46,7 @@ func NewDomainService(
 	cloudflare *clients.CloudflareClient,
 	redisClient *redis.Client,
 	eventPublisher *events.Publisher,
+	credentialCipher *CredentialCipher,
 ) *DomainService {
 	return &DomainService{
 		cfg:            cfg,
@@ -52,6 +58,9 @@ func NewDomainService(
 		cloudflare:     cloudflare,
 		redisClient:    redisClient,
 		eventPublisher: eventPublisher,
+
+		credentialCipher:     credentialCipher,
+		newDNSProviderClient: clients.NewDNSProviderClient,
 	}
 }
 
@@ -113,6 +122,26 @@ func (s *DomainService) CreateDomain(ctx context.Context, tenantID uuid.UUID, re
 	// This is stored in DB for consistent verification and audit trail
 	cnameDelegationTarget := s.dnsVerifier.GetCNAMEDelegationTargetForTenant(domainName, tenantID.String())
 
+	verificationMethod := req.VerificationMethod
+	if verificationMethod == "" {
+		verificationMethod = models.VerificationMethodTXT
+	}
+
+	// Wildcard certificates can only be issued via DNS-01, and there is no single host to fetch
+	// an HTTP verification file from
+	includeWWW := req.IncludeWWW
+	certValidation := models.CertValidationHTTP01
+	if domainType == models.DomainTypeWildcard {
+		if verificationMethod.IsHTTP() {
+			return nil, repository.ErrWildcardHTTPVerification
+		}
+		if !s.cfg.CNAMEDelegation.Enabled && !s.cfg.Cloudflare.Enabled {
+			return nil, repository.ErrWildcardNotSupported
+		}
+		includeWWW = false
+		certValidation = models.CertValidationDNS01
+	}
+
 	// Create domain record
 	domain := &models.CustomDomain{
 		TenantID:              tenantID,
@@ -120,18 +149,32 @@ func (s *DomainService) CreateDomain(ctx context.Context, tenantID uuid.UUID, re
 		Domain:                domainName,
 		DomainType:            domainType,
 		TargetType:            targetType,
-		VerificationMethod:    models.VerificationMethodTXT,
-		IncludeWWW:            req.IncludeWWW,
+		VerificationMethod:    verificationMethod,
+		IncludeWWW:            includeWWW,
 		Status:                models.DomainStatusPending,
 		StatusMessage:         "Waiting for DNS verification",
 		CreatedBy:             createdBy,
 		CNAMEDelegationTarget: cnameDelegationTarget, // Store tenant-specific target
+		CertValidationMethod:  certValidation,
 	}
+	if err := s.validateChallengePreference(domain, req.ACMEChallengePreference); err != nil {
+		return nil, err
+	}
+	s.applyChallengePreference(domain, req.ACMEChallengePreference)
 
 	if err := s.repo.Create(ctx, domain); err != nil {
 		return nil, fmt.Errorf("failed to create domain: %w", err)
 	}
 
+	if err := s.repo.RecordVerificationMethod(ctx, &models.DomainVerificationMethodChange{
+		DomainID:  domain.ID,
+		TenantID:  tenantID,
+		Method:    verificationMethod,
+		ChangedBy: createdBy,
+	}); err != nil {
+		log.Warn().Err(err).Str("domain", domain.Domain).Msg("Failed to record verification method")
+	}
+
 	// Log activity
 	s.logActivity(ctx, domain, "created", "success", "Domain created, awaiting DNS verification")
 
@@ -177,6 +220,10 @@ type ValidateDomainResponse struct {
 	CNAMEDelegationRecord  *models.DNSRecord  `json:"cname_delegation_record,omitempty"`  // CNAME for automatic SSL (_acme-challenge)
 	CNAMEDelegationEnabled bool               `json:"cname_delegation_enabled"`           // Whether CNAME delegation is available
 	ProxyTarget            string             `json:"proxy_target,omitempty"`             // Target for routing (e.g., proxy.tesserix.app)
+
+	// Detected DNS mode (only when check_dns is set)
+	DNSMode           models.DNSMode `json:"dns_mode,omitempty"`
+	SetupInstructions []string       `json:"setup_instructions,omitempty"`
 }
 
 // ValidateDomain validates a domain and creates/retrieves a pending verification record
@@ -198,6 +245,12 @@ func (s *DomainService) ValidateDomain(ctx context.Context, req *ValidateDomainR
 	// Note: Apex domains are now supported - we use A records pointing to gateway IP
 	// instead of CNAME records which don't work for apex domains
 
+	// Wildcards need DNS-01 certificates, so they are added from the domain settings once the store exists
+	if strings.HasPrefix(domainName, models.WildcardPrefix) {
+		response.Message = "Wildcard domains can be added from your domain settings once your store is live"
+		return response, nil
+	}
+
 	response.Valid = true
 
 	// Check if domain actually exists (is registered) by looking up NS records
@@ -465,6 +518,16 @@ func (s *DomainService) ValidateDomain(ctx context.Context, req *ValidateDomainR
 			response.DNSConfigured = true
 			response.Message = "Domain is valid and DNS is configured"
 		}
+
+		// Tell customers on Cloudflare whether they can keep the proxy on
+		tempDomain.DNSMode = s.dnsVerifier.DetectDNSMode(ctx, domainName).Mode
+		tempDomain.DomainType = domainType
+		tempDomain.IncludeWWW = true
+		if response.CNAMEDelegationRecord != nil {
+			tempDomain.CNAMEDelegationTarget = response.CNAMEDelegationRecord.Value
+		}
+		response.DNSMode = tempDomain.DNSMode
+		response.SetupInstructions = s.setupInstructions(tempDomain)
 	}
 
 	return response, nil
@@ -491,6 +554,11 @@ type VerifyDomainByNameResponse struct {
 	DomainID        string `json:"domain_id,omitempty"` // ID of the pending domain record if exists
 }
 
+// CheckTXTRecord checks a TXT record for another service; nothing is stored
+func (s *DomainService) CheckTXTRecord(ctx context.Context, host, expectedValue string) *models.VerifyTXTResponse {
+	return s.dnsVerifier.CheckTXTRecord(ctx, host, expectedValue)
+}
+
 // VerifyDomainByName verifies DNS for a domain by name (used during onboarding)
 // This checks if the DNS record is configured and updates the pending domain record if it exists
 func (s *DomainService) VerifyDomainByName(ctx context.Context, req *VerifyDomainByNameRequest) (*VerifyDomainByNameResponse, error) {
@@ -612,6 +680,7 @@ func (s *DomainService) UpdateDomain(ctx context.Context, tenantID, domainID uui
 	}
 
 	updated := false
+	resumeProvisioning := false
 
 	if req.RedirectWWW != nil {
 		domain.RedirectWWW = *req.RedirectWWW
@@ -623,6 +692,21 @@ func (s *DomainService) UpdateDomain(ctx context.Context, tenantID, domainID uui
 		updated = true
 	}
 
+	if req.ACMEChallengePreference != nil && *req.ACMEChallengePreference != domain.ACMEChallengePreference {
+		if err := s.validateChallengePreference(domain, *req.ACMEChallengePreference); err != nil {
+			return nil, err
+		}
+		s.applyChallengePreference(domain, *req.ACMEChallengePreference)
+		updated = true
+
+		// A certificate waiting for CNAME delegation can be issued right away once DNS-01 is no longer required
+		if domain.Status == models.DomainStatusVerifying && domain.DNSVerified && !domain.RequiresDNS01() {
+			domain.Status = models.DomainStatusProvisioning
+			domain.StatusMessage = "Issuing SSL certificate"
+			resumeProvisioning = true
+		}
+	}
+
 	if req.PrimaryDomain != nil && *req.PrimaryDomain && !domain.PrimaryDomain {
 		if err := s.repo.SetPrimaryDomain(ctx, tenantID, domainID); err != nil {
 			return nil, fmt.Errorf("failed to set primary domain: %w", err)
@@ -647,6 +731,10 @@ func (s *DomainService) UpdateDomain(ctx context.Context, tenantID, domainID uui
 		}
 
 		s.logActivity(ctx, domain, "updated", "success", "Domain settings updated")
+
+		if resumeProvisioning {
+			go s.provisionDomain(context.Background(), domain)
+		}
 	}
 
 	return s.toDomainResponse(domain), nil
@@ -752,6 +840,9 @@ func (s *DomainService) VerifyDomain(ctx context.Context, tenantID, domainID uui
 		return s.toDNSStatusResponse(domain, "Domain already verified"), nil
 	}
 
+	// Detect Cloudflare proxying before verification so the certificate flow matches the DNS setup
+	s.RefreshDNSMode(ctx, domain)
+
 	// Verify DNS
 	result, err := s.dnsVerifier.VerifyDomain(ctx, domain)
 	if err != nil {
@@ -768,6 +859,9 @@ func (s *DomainService) VerifyDomain(ctx context.Context, tenantID, domainID uui
 
 	// If verified, start provisioning
 	if result.IsVerified {
+		if err := s.repo.MarkVerificationMethodVerified(ctx, domainID); err != nil {
+			log.Warn().Err(err).Str("domain", domain.Domain).Msg("Failed to mark verification method as verified")
+		}
 		go s.provisionDomain(context.Background(), domain)
 		s.logActivity(ctx, domain, "verified", "success", "DNS verification successful, starting provisioning")
 		// Publish DNS verified event
@@ -865,7 +959,10 @@ func (s *DomainService) provisionDomainWithCloudflare(ctx context.Context, domai
 	}
 
 	// Step 3: Update Keycloak redirect URIs
-	if err := s.keycloak.AddDomainRedirectURIs(ctx, domain); err != nil {
+	if domain.IsWildcard() {
+		// Keycloak only accepts wildcards at the end of a redirect URI, not in the host
+		s.logActivity(ctx, domain, "keycloak", "skipped", "Wildcard hosts cannot be registered as redirect URIs")
+	} else if err := s.keycloak.AddDomainRedirectURIs(ctx, domain); err != nil {
 		log.Error().Err(err).Str("domain", domain.Domain).Msg("Failed to update Keycloak")
 		s.logActivity(ctx, domain, "keycloak", "failed", "Authentication configuration update failed")
 	} else {
@@ -903,8 +1000,27 @@ func (s *DomainService) provisionDomainWithCloudflare(ctx context.Context, domai
 
 // provisionDomainWithCertManager provisions a domain using cert-manager (legacy)
 func (s *DomainService) provisionDomainWithCertManager(ctx context.Context, domain *models.CustomDomain) {
+	// HTTP-01 challenges do not reach us through the Cloudflare proxy, cannot validate wildcard names at all,
+	// and are not used once the tenant prefers DNS-01 or HTTP-01 validation has already failed
+	if domain.RequiresDNS01() && !domain.IsCNAMEDelegationReady() {
+		if !domain.CNAMEDelegationEnabled && s.cfg.CNAMEDelegation.Enabled {
+			if err := s.repo.EnableCNAMEDelegation(ctx, domain.ID, true); err != nil {
+				log.Warn().Err(err).Str("domain", domain.Domain).Msg("Failed to enable CNAME delegation")
+			} else {
+				domain.CNAMEDelegationEnabled = true
+			}
+		}
+		message := dns01WaitMessage(domain)
+		log.Info().Str("domain", domain.Domain).Msg("Waiting for CNAME delegation before issuing DNS-01 certificate")
+		s.repo.UpdateStatus(ctx, domain.ID, models.DomainStatusVerifying, message)
+		s.logActivity(ctx, domain, "ssl_provisioning", "pending", message)
+		domain.Status = models.DomainStatusVerifying
+		domain.StatusMessage = message
+		return
+	}
+
 	// Determine which ACME challenge type to use
-	useCNAMEDelegation := domain.IsCNAMEDelegationReady()
+	useCNAMEDelegation := domain.ACMEChallengeType() == models.CertValidationDNS01
 	solverType := "HTTP-01"
 	if useCNAMEDelegation {
 		solverType = "DNS-01 (CNAME Delegation)"
@@ -961,6 +1077,10 @@ func (s *DomainService) provisionDomainWithCertManager(ctx context.Context, doma
 		select {
 		case <-certCtx.Done():
 			log.Warn().Str("domain", domain.Domain).Msg("Certificate provisioning timed out")
+			if !useCNAMEDelegation && domain.CanFallBackToDNS01() && s.cfg.CNAMEDelegation.Enabled {
+				s.fallBackToDNS01(ctx, domain, "HTTP-01 validation timed out")
+				return
+			}
 			s.repo.UpdateSSLStatus(ctx, domain.ID, models.SSLStatusFailed, certResult.SecretName, nil, "Certificate provisioning timed out")
 			s.repo.UpdateStatus(ctx, domain.ID, models.DomainStatusFailed, "SSL certificate provisioning timed out")
 			domain.Status = models.DomainStatusFailed
@@ -985,6 +1105,10 @@ func (s *DomainService) provisionDomainWithCertManager(ctx context.Context, doma
 
 			if certStatus.Status == models.SSLStatusFailed {
 				log.Error().Str("domain", domain.Domain).Str("cert_error", certStatus.Error).Msg("Certificate provisioning failed")
+				if !useCNAMEDelegation && domain.CanFallBackToDNS01() && s.cfg.CNAMEDelegation.Enabled {
+					s.fallBackToDNS01(ctx, domain, certStatus.Error)
+					return
+				}
 				s.repo.UpdateSSLStatus(ctx, domain.ID, models.SSLStatusFailed, certStatus.SecretName, nil, "Certificate validation failed")
 				s.repo.UpdateStatus(ctx, domain.ID, models.DomainStatusFailed, "SSL certificate provisioning failed. Please verify your DNS configuration.")
 				s.logActivity(ctx, domain, "ssl_provisioning", "failed", "Certificate provisioning failed")
@@ -1031,7 +1155,10 @@ configureRouting:
 	}
 
 	// Step 4: Update Keycloak redirect URIs
-	if err := s.keycloak.AddDomainRedirectURIs(ctx, domain); err != nil {
+	if domain.IsWildcard() {
+		// Keycloak only accepts wildcards at the end of a redirect URI, not in the host
+		s.logActivity(ctx, domain, "keycloak", "skipped", "Wildcard hosts cannot be registered as redirect URIs")
+	} else if err := s.keycloak.AddDomainRedirectURIs(ctx, domain); err != nil {
 		log.Error().Err(err).Str("domain", domain.Domain).Msg("Failed to update Keycloak")
 		s.logActivity(ctx, domain, "keycloak", "failed", "Authentication configuration update failed")
 	} else {
@@ -1094,6 +1221,13 @@ func (s *DomainService) GetSSLStatus(ctx context.Context, tenantID, domainID uui
 		Provider:  domain.SSLProvider,
 		AutoRenew: true,
 		LastError: domain.SSLLastError,
+
+		ChallengeType:       domain.ACMEChallengeType(),
+		ChallengePreference: domain.ACMEChallengePreference,
+		FallbackReason:      domain.CertFallbackReason,
+	}
+	if response.ChallengePreference == "" {
+		response.ChallengePreference = models.ACMEChallengeAuto
 	}
 
 	if domain.SSLExpiresAt != nil {
@@ -1103,6 +1237,27 @@ func (s *DomainService) GetSSLStatus(ctx context.Context, tenantID, domainID uui
 		response.DaysRemaining = &daysRemaining
 	}
 
+	if domain.CertFallbackAt != nil {
+		v := domain.CertFallbackAt.Format(time.RFC3339)
+		response.FallbackAt = &v
+	}
+
+	// A DNS-01 certificate cannot be issued until the tenant's _acme-challenge CNAME is in place
+	if response.ChallengeType == models.CertValidationDNS01 && !domain.CNAMEDelegationVerified && domain.SSLStatus != models.SSLStatusActive {
+		target := domain.CNAMEDelegationTarget
+		if target == "" {
+			target = s.dnsVerifier.GetCNAMEDelegationTargetForTenant(domain.Domain, domain.TenantID.String())
+		}
+		response.RequiredCNAMERecord = &models.CNAMEDelegationRecord{
+			Host:   domain.GetACMEChallengeHost(),
+			Target: target,
+			TTL:    3600,
+		}
+		response.Message = dns01WaitMessage(domain)
+	} else if domain.CertFallbackAt != nil {
+		response.Message = "HTTP-01 validation failed, so the certificate is issued via DNS-01"
+	}
+
 	return response, nil
 }
 
@@ -1171,6 +1326,12 @@ func (s *DomainService) ResolveDomain(ctx context.Context, domainName string) (*
 
 	// Query database
 	domain, err := s.repo.GetByDomain(ctx, domainName)
+	if errors.Is(err, repository.ErrDomainNotFound) {
+		// Hosts without a domain of their own may be covered by a wildcard, e.g. east.shop.example.com by *.shop.example.com
+		if pattern := models.WildcardPatternFor(domainName); pattern != "" {
+			return s.ResolveDomain(ctx, pattern)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1182,6 +1343,7 @@ func (s *DomainService) ResolveDomain(ctx context.Context, domainName string) (*
 		TargetType: domain.TargetType,
 		IsActive:   domain.Status == models.DomainStatusActive,
 		IsPrimary:  domain.PrimaryDomain,
+		IsWildcard: domain.IsWildcard(),
 	}
 
 	// Cache the response
@@ -1229,6 +1391,10 @@ func (s *DomainService) toDomainResponse(domain *models.CustomDomain) *models.Do
 		CreatedAt:          domain.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:          domain.UpdatedAt.Format(time.RFC3339),
 		VerificationMethod: domain.VerificationMethod,
+		DNSMode:            domain.DNSMode,
+		CertValidationMethod: domain.CertValidationMethod,
+		SetupInstructions:  s.setupInstructions(domain),
+		ACMEChallengePreference: domain.ACMEChallengePreference,
 	}
 
 	if domain.DNSVerifiedAt != nil {
@@ -1264,6 +1430,8 @@ func (s *DomainService) toDNSStatusResponse(domain *models.CustomDomain, message
 		CheckAttempts: domain.DNSCheckAttempts,
 		Records:       s.dnsVerifier.GetRequiredDNSRecords(domain),
 		Message:       message,
+		DNSMode:       domain.DNSMode,
+		SetupInstructions: s.setupInstructions(domain),
 	}
 
 	if domain.DNSVerifiedAt != nil {
@@ -1342,6 +1510,7 @@ func (s *DomainService) getDomainFromCache(ctx context.Context, domainName strin
 		TargetType: models.TargetType(parts[2]),
 		IsActive:   parts[3] == "true",
 		IsPrimary:  parts[4] == "true",
+		IsWildcard: strings.HasPrefix(domainName, models.WildcardPrefix),
 	}, nil
 }
 
@@ -1361,6 +1530,30 @@ func (s *DomainService) publishDomainEvent(ctx context.Context, eventType string
 		return
 	}
 
+	event := newDomainEvent(eventType, domain, previousStatus)
+
+	// Publish asynchronously to avoid blocking the main flow
+	go func() {
+		publishCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := s.eventPublisher.PublishDomain(publishCtx, event); err != nil {
+			log.Error().Err(err).
+				Str("event_type", eventType).
+				Str("domain", domain.Domain).
+				Msg("Failed to publish domain event")
+		} else {
+			log.Info().
+				Str("event_type", eventType).
+				Str("domain", domain.Domain).
+				Str("tenant_id", domain.TenantID.String()).
+				Msg("Domain event published")
+		}
+	}()
+}
+
+// newDomainEvent builds a domain event from the current state of the domain
+func newDomainEvent(eventType string, domain *models.CustomDomain, previousStatus string) *events.DomainEvent {
 	event := events.NewDomainEvent(eventType, domain.TenantID.String())
 	event.DomainID = domain.ID.String()
 	event.Domain = domain.Domain
@@ -1384,24 +1577,7 @@ func (s *DomainService) publishDomainEvent(ctx context.Context, eventType string
 		event.SSLExpiresAt = domain.SSLExpiresAt.Format(time.RFC3339)
 	}
 
-	// Publish asynchronously to avoid blocking the main flow
-	go func() {
-		publishCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		if err := s.eventPublisher.PublishDomain(publishCtx, event); err != nil {
-			log.Error().Err(err).
-				Str("event_type", eventType).
-				Str("domain", domain.Domain).
-				Msg("Failed to publish domain event")
-		} else {
-			log.Info().
-				Str("event_type", eventType).
-				Str("domain", domain.Domain).
-				Str("tenant_id", domain.TenantID.String()).
-				Msg("Domain event published")
-		}
-	}()
+	return event
 }
 
 // GetCNAMEDelegationStatus returns CNAME delegation status and required CNAME record
@@ -1467,6 +1643,11 @@ func (s *DomainService) VerifyCNAMEDelegation(ctx context.Context, tenantID, dom
 			Str("domain", domain.Domain).
 			Str("cname_target", cnameResult.FoundCNAME).
 			Msg("CNAME delegation verified successfully")
+
+		// Resume a certificate that was waiting for the delegation
+		if domain.Status == models.DomainStatusVerifying && domain.DNSVerified {
+			s.ResumeDNS01Provisioning(ctx, domain)
+		}
 	} else {
 		s.logActivity(ctx, domain, "cname_delegation_check", "pending", cnameResult.Message)
 	}
@@ -1515,7 +1696,7 @@ func (s *DomainService) toCNAMEDelegationStatusResponse(domain *models.CustomDom
 
 	cnameRecord := &models.DNSRecord{
 		RecordType: "CNAME",
-		Host:       "_acme-challenge." + domain.Domain,
+		Host:       domain.GetACMEChallengeHost(),
 		Value:      target,
 		TTL:        3600,
 		Purpose:    "cname_delegation (automatic SSL - tenant specific)",
@@ -1570,3 +1751,56 @@ func (s *DomainService) toCNAMEDelegationStatusResponse(domain *models.CustomDom
 
 	return response
 }
+
+// ChangeVerificationMethod switches how ownership of an unverified domain is proven and restarts verification
+func (s *DomainService) ChangeVerificationMethod(ctx context.Context, tenantID, domainID uuid.UUID, method models.VerificationMethod, changedBy uuid.UUID) (*models.DNSStatusResponse, error) {
+	domain, err := s.repo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if domain.TenantID != tenantID {
+		return nil, repository.ErrDomainNotFound
+	}
+
+	if domain.DNSVerified {
+		return nil, repository.ErrDomainAlreadyVerified
+	}
+
+	if domain.VerificationMethod == method {
+		return s.toDNSStatusResponse(domain, fmt.Sprintf("Verification method is already %s", method)), nil
+	}
+
+	if domain.IsWildcard() && method.IsHTTP() {
+		return nil, repository.ErrWildcardHTTPVerification
+	}
+
+	previous := domain.VerificationMethod
+	if err := s.repo.ChangeVerificationMethod(ctx, domain, method, changedBy); err != nil {
+		return nil, fmt.Errorf("failed to change verification method: %w", err)
+	}
+
+	domain, err = s.repo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Verification method changed from %s to %s", previous, method)
+	s.logActivity(ctx, domain, "verification_method_changed", "success", message)
+
+	return s.toDNSStatusResponse(domain, message), nil
+}
+
+// GetVerificationMethodHistory returns the verification methods a domain has used, newest first
+func (s *DomainService) GetVerificationMethodHistory(ctx context.Context, tenantID, domainID uuid.UUID) ([]models.DomainVerificationMethodChange, error) {
+	domain, err := s.repo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if domain.TenantID != tenantID {
+		return nil, repository.ErrDomainNotFound
+	}
+
+	return s.repo.GetVerificationMethodHistory(ctx, domainID)
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"custom-domain-service/internal/models"
+
+	"github.com/rs/zerolog/log"
+)
+
+// HTTP verification for merchants who cannot add DNS records
+const (
+	// VerificationFilePath is where the http_file method expects the token file
+	VerificationFilePath = "/.well-known/tesserix-verification.txt"
+	// VerificationMetaName is the name of the meta tag the meta_tag method looks for
+	VerificationMetaName = "tesserix-verification"
+
+	verificationFileMaxBytes = 1 << 10   // The file only holds the token
+	verificationPageMaxBytes = 512 << 10 // Meta tags live in <head>, near the top of the page
+	verificationMaxRedirects = 3
+)
+
+var (
+	errBlockedVerificationTarget = errors.New("domain resolves to a non-public address")
+
+	metaTagPattern       = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	metaAttributePattern = regexp.MustCompile(`(?is)([a-z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// newVerificationHTTPClient returns the client used to fetch verification files and pages.
+// Verification hosts are customer controlled, so the dialer refuses private, loopback and
+// link-local addresses to keep the fetch from reaching cluster-internal services.
+func newVerificationHTTPClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errBlockedVerificationTarget
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			DisableKeepAlives:   true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= verificationMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", verificationMaxRedirects)
+			}
+			// Only follow redirects within the domain (e.g. http -> https, apex -> www)
+			origin := strings.TrimPrefix(via[0].URL.Hostname(), "www.")
+			if strings.TrimPrefix(req.URL.Hostname(), "www.") != origin {
+				return fmt.Errorf("redirect to %s leaves the domain", req.URL.Hostname())
+			}
+			return nil
+		},
+	}
+}
+
+// isPublicIP reports whether ip is a globally routable unicast address
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// verifyHTTPFile checks https://<domain>/.well-known/tesserix-verification.txt (falling back to http)
+// contains the domain's verification value
+func (v *DNSVerifier) verifyHTTPFile(ctx context.Context, domain *models.CustomDomain, result *VerificationResult) (*VerificationResult, error) {
+	expectedValue := "tesserix-verify=" + domain.VerificationToken
+	result.ExpectedRecord = fmt.Sprintf("%s containing %s", VerificationFilePath, expectedValue)
+
+	body, url, err := v.fetchVerificationURL(ctx, domain.Domain, VerificationFilePath, verificationFileMaxBytes)
+	if err != nil {
+		result.Message = fmt.Sprintf("Could not fetch %s: %v", VerificationFilePath, err)
+		return result, nil
+	}
+
+	content := strings.TrimSpace(body)
+	result.RecordFound = content
+	// Accept the bare token too, since some hosting panels only take a single value
+	if content == expectedValue || content == domain.VerificationToken {
+		result.IsVerified = true
+		result.Message = fmt.Sprintf("Domain ownership verified successfully via %s", url)
+		return result, nil
+	}
+
+	result.Message = fmt.Sprintf("%s found but its content doesn't match. Expected: %s", url, expectedValue)
+	return result, nil
+}
+
+// verifyMetaTag checks the domain's home page for <meta name="tesserix-verification" content="<token>">
+func (v *DNSVerifier) verifyMetaTag(ctx context.Context, domain *models.CustomDomain, result *VerificationResult) (*VerificationResult, error) {
+	result.ExpectedRecord = VerificationMetaTag(domain.VerificationToken)
+
+	body, url, err := v.fetchVerificationURL(ctx, domain.Domain, "/", verificationPageMaxBytes)
+	if err != nil {
+		result.Message = fmt.Sprintf("Could not fetch the home page: %v", err)
+		return result, nil
+	}
+
+	contents := FindVerificationMetaContents(body)
+	for _, content := range contents {
+		if content == domain.VerificationToken {
+			result.IsVerified = true
+			result.RecordFound = content
+			result.Message = fmt.Sprintf("Domain ownership verified successfully via meta tag on %s", url)
+			return result, nil
+		}
+	}
+
+	if len(contents) > 0 {
+		result.RecordFound = contents[0]
+		result.Message = fmt.Sprintf("Meta tag %q found on %s but its content doesn't match", VerificationMetaName, url)
+	} else {
+		result.Message = fmt.Sprintf("Meta tag %q not found on %s. Add it inside <head>.", VerificationMetaName, url)
+	}
+	return result, nil
+}
+
+// fetchVerificationURL GETs path on the domain over HTTPS, falling back to HTTP, and returns
+// at most maxBytes of a 200 response body along with the URL that served it
+func (v *DNSVerifier) fetchVerificationURL(ctx context.Context, domainName, path string, maxBytes int64) (string, string, error) {
+	var lastErr error
+	for _, scheme := range []string{"https", "http"} {
+		url := scheme + "://" + domainName + path
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return "", url, err
+		}
+		req.Header.Set("User-Agent", "Tesserix-Domain-Verification/1.0")
+
+		resp, err := v.httpClient.Do(req)
+		if err != nil {
+			log.Debug().Err(err).Str("url", url).Msg("Verification fetch failed")
+			lastErr = err
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes))
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			lastErr = fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+			continue
+		}
+		return string(body), url, nil
+	}
+	if errors.Is(lastErr, errBlockedVerificationTarget) {
+		lastErr = errBlockedVerificationTarget
+	}
+	return "", "", lastErr
+}
+
+// VerificationMetaTag returns the meta tag a merchant adds for the meta_tag method
+func VerificationMetaTag(token string) string {
+	return fmt.Sprintf(`<meta name="%s" content="%s">`, VerificationMetaName, token)
+}
+
+// FindVerificationMetaContents returns the content of every tesserix-verification meta tag in an HTML page
+func FindVerificationMetaContents(page string) []string {
+	var contents []string
+	for _, tag := range metaTagPattern.FindAllString(page, -1) {
+		attrs := make(map[string]string)
+		for _, match := range metaAttributePattern.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(match[1])] = match[2] + match[3] + match[4]
+		}
+		if strings.EqualFold(strings.TrimSpace(attrs["name"]), VerificationMetaName) {
+			contents = append(contents, strings.TrimSpace(attrs["content"]))
+		}
+	}
+	return contents
+}
@@ -0,0 +1,106 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"custom-domain-service/internal/config"
+	"custom-domain-service/internal/repository"
+	"custom-domain-service/internal/services"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CertExpiryNotificationWorker notifies tenant owners ahead of certificate expiry
+// so renewal failures are noticed before the domain stops serving HTTPS
+type CertExpiryNotificationWorker struct {
+	cfg       *config.Config
+	repo      *repository.DomainRepository
+	domainSvc *services.DomainService
+	stopCh    chan struct{}
+}
+
+// NewCertExpiryNotificationWorker creates a new certificate expiry notification worker
+func NewCertExpiryNotificationWorker(
+	cfg *config.Config,
+	repo *repository.DomainRepository,
+	domainSvc *services.DomainService,
+) *CertExpiryNotificationWorker {
+	return &CertExpiryNotificationWorker{
+		cfg:       cfg,
+		repo:      repo,
+		domainSvc: domainSvc,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start starts the certificate expiry notification worker
+func (w *CertExpiryNotificationWorker) Start(ctx context.Context) {
+	log.Info().
+		Dur("interval", w.cfg.Workers.CertExpiryNotifyInterval).
+		Ints("thresholds_days", w.cfg.SSL.ExpiryNotifyDays).
+		Msg("Starting certificate expiry notification worker")
+
+	ticker := time.NewTicker(w.cfg.Workers.CertExpiryNotifyInterval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	w.run(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Certificate expiry notification worker stopping (context cancelled)")
+			return
+		case <-w.stopCh:
+			log.Info().Msg("Certificate expiry notification worker stopped")
+			return
+		case <-ticker.C:
+			w.run(ctx)
+		}
+	}
+}
+
+// Stop stops the worker
+func (w *CertExpiryNotificationWorker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *CertExpiryNotificationWorker) run(ctx context.Context) {
+	log.Debug().Msg("Running certificate expiry notification check")
+
+	domains, err := w.repo.GetAllActive(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get active domains")
+		return
+	}
+
+	now := time.Now()
+	notified := 0
+	for _, domain := range domains {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		threshold, err := w.domainSvc.NotifyCertificateExpiry(ctx, &domain, now)
+		if errors.Is(err, services.ErrEventPublisherUnavailable) {
+			log.Warn().Msg("Event publisher not configured, skipping certificate expiry notifications")
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("domain", domain.Domain).Msg("Failed to send certificate expiry notification")
+			continue
+		}
+		if threshold > 0 {
+			notified++
+			log.Info().Str("domain", domain.Domain).Int("threshold_days", threshold).Msg("Certificate expiry notification sent")
+		}
+	}
+
+	if notified > 0 {
+		log.Info().Int("count", notified).Msg("Sent certificate expiry notifications")
+	}
+}
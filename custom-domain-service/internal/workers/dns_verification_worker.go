@@ -69,6 +69,11 @@ func (w *DNSVerificationWorker) Stop() {
 func (w *DNSVerificationWorker) run(ctx context.Context) {
 	log.Debug().Msg("Running DNS verification check")
 
+	// Resume certificates that wait for CNAME delegation before they can be issued via DNS-01
+	if w.cfg.CNAMEDelegation.Enabled {
+		w.resumeDNS01Provisioning(ctx)
+	}
+
 	// Get domains pending verification
 	domains, err := w.repo.GetPendingVerification(ctx, 50)
 	if err != nil {
@@ -108,12 +113,15 @@ func (w *DNSVerificationWorker) verifyDomain(ctx context.Context, domain *models
 		return
 	}
 
+	// Detect Cloudflare proxying - proxied domains get CNAME delegation enabled for DNS-01
+	w.domainSvc.RefreshDNSMode(ctx, domain)
+
 	// Check CNAME delegation if enabled for this domain
 	if domain.CNAMEDelegationEnabled && !domain.CNAMEDelegationVerified && w.cfg.CNAMEDelegation.Enabled {
 		w.verifyCNAMEDelegation(ctx, domain)
 	}
 
-	// Verify DNS ownership (TXT or CNAME record)
+	// Verify ownership (TXT/CNAME record, or verification file/meta tag over HTTP)
 	result, err := w.dnsVerifier.VerifyDomain(ctx, domain)
 	if err != nil {
 		log.Error().Err(err).Str("domain", domain.Domain).Msg("DNS verification error")
@@ -127,6 +135,9 @@ func (w *DNSVerificationWorker) verifyDomain(ctx context.Context, domain *models
 	}
 
 	if result.IsVerified {
+		if err := w.repo.MarkVerificationMethodVerified(ctx, domain.ID); err != nil {
+			log.Warn().Err(err).Str("domain", domain.Domain).Msg("Failed to mark verification method as verified")
+		}
 		log.Info().Str("domain", domain.Domain).Msg("Domain DNS verified successfully")
 		// Note: The domain service will handle provisioning when status changes to provisioning
 	} else {
@@ -134,6 +145,26 @@ func (w *DNSVerificationWorker) verifyDomain(ctx context.Context, domain *models
 	}
 }
 
+// resumeDNS01Provisioning re-checks the CNAME delegation of verified domains whose certificate
+// waits for it, and resumes provisioning once the record is in place
+func (w *DNSVerificationWorker) resumeDNS01Provisioning(ctx context.Context) {
+	domains, err := w.repo.GetAwaitingCNAMEDelegation(ctx, 50)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get domains awaiting CNAME delegation")
+		return
+	}
+
+	for _, domain := range domains {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		w.domainSvc.ResumeDNS01Provisioning(ctx, &domain)
+	}
+}
+
 // verifyCNAMEDelegation checks if CNAME delegation is properly configured
 // Uses the stored CNAMEDelegationTarget from the database for security
 func (w *DNSVerificationWorker) verifyCNAMEDelegation(ctx context.Context, domain *models.CustomDomain) {
@@ -109,7 +109,13 @@ func (w *HealthCheckWorker) checkDomain(ctx context.Context, domain *models.Cust
 		CheckedAt: time.Now(),
 	}
 
-	url := fmt.Sprintf("https://%s", domain.Domain)
+	// A wildcard serves every host under its base, so check a representative one
+	host := domain.Domain
+	if domain.IsWildcard() {
+		host = "www." + domain.RecordDomain()
+	}
+
+	url := fmt.Sprintf("https://%s", host)
 	start := time.Now()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
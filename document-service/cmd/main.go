@@ -17,6 +17,7 @@ import (
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
 
+	"document-service/internal/clients"
 	"document-service/internal/config"
 	"document-service/internal/events"
 	"document-service/internal/handlers"
@@ -80,6 +81,17 @@ func main() {
 		redisCache = cache.NewNoOpCache()
 	}
 
+	// Branded asset URLs: public and download URLs under the tenant's custom domain or the platform asset domain
+	var assetURLs models.AssetURLSigner
+	if assetCfg := cfg.GetAssetURLConfig(); assetCfg.Enabled {
+		var domains models.TenantDomainResolver
+		if assetCfg.UseCustomDomains && assetCfg.CustomDomainServiceURL != "" {
+			domains = clients.NewCustomDomainClient(assetCfg.CustomDomainServiceURL)
+		}
+		assetURLs = service.NewAssetURLSigner(assetCfg, domains, redisCache, logger)
+		logger.WithField("platform_base_url", assetCfg.PlatformBaseURL).Info("Branded asset URLs enabled")
+	}
+
 	// Initialize repository and service
 	repo := repository.NewDocumentRepository(db)
 	cacheConfig := cfg.GetCacheConfig()
@@ -87,6 +99,7 @@ func main() {
 		Cache:           redisCache,
 		PresignedURLTTL: time.Duration(cacheConfig.PresignedURLTTL) * time.Second,
 		MetadataTTL:     time.Duration(cacheConfig.MetadataTTL) * time.Second,
+		AssetURLs:       assetURLs,
 	})
 
 	// Initialize NATS events publisher (non-blocking)
@@ -98,8 +111,36 @@ func main() {
 		}
 	}()
 
+	// Promote anonymous onboarding uploads once tenant-service creates the tenant (non-blocking)
+	go func() {
+		promote := func(ctx context.Context, onboardingSessionID, tenantID string) error {
+			_, err := documentService.PromoteOnboardingUploads(ctx, onboardingSessionID, tenantID)
+			return err
+		}
+		if _, err := events.SubscribeTenantCreated(context.Background(), logger, promote); err != nil {
+			logger.WithError(err).Warn("Failed to subscribe to tenant events (onboarding uploads won't be promoted)")
+		}
+	}()
+
+	// Purge a deleted tenant's documents when tenant-service's deletion saga asks (non-blocking)
+	go func() {
+		if _, err := events.SubscribeTenantDeletionRequested(context.Background(), logger, documentService.PurgeTenant); err != nil {
+			logger.WithError(err).Warn("Failed to subscribe to tenant deletion requests (deleted tenants won't be purged)")
+		}
+	}()
+
+	// Purge expired resumable uploads, abandoned onboarding uploads and their staged files
+	cleanupCtx, stopCleanup := context.WithCancel(context.Background())
+	defer stopCleanup()
+	go runUploadCleanup(cleanupCtx, documentService, logger)
+
+	// Report (and optionally clean up) objects and document records that no longer match
+	if reconcileCfg := cfg.GetStorageReconcileConfig(); reconcileCfg.Enabled {
+		go runStorageReconcile(cleanupCtx, documentService, reconcileCfg, logger)
+	}
+
 	// Setup HTTP server
-	router := setupRouter(cfg, documentService, logger)
+	router := setupRouter(cfg, documentService, assetURLs, logger)
 	server := &http.Server{
 		Addr:         cfg.GetAddr(),
 		Handler:      router,
@@ -134,6 +175,81 @@ func main() {
 	logger.Info("Server exited")
 }
 
+// runUploadCleanup periodically deletes expired resumable and onboarding upload sessions until ctx is cancelled
+func runUploadCleanup(ctx context.Context, documentService models.DocumentService, logger *logrus.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := documentService.CleanupExpiredUploads(ctx)
+			if err != nil {
+				logger.WithError(err).Warn("Failed to clean up expired uploads")
+				continue
+			}
+			if purged > 0 {
+				logger.WithField("count", purged).Info("Purged expired uploads")
+			}
+
+			expired, err := documentService.CleanupExpiredOnboardingUploads(ctx)
+			if err != nil {
+				logger.WithError(err).Warn("Failed to clean up abandoned onboarding uploads")
+				continue
+			}
+			if expired > 0 {
+				logger.WithField("count", expired).Info("Expired abandoned onboarding uploads")
+			}
+		}
+	}
+}
+
+// runStorageReconcile periodically reconciles the configured buckets against the document records until ctx is cancelled
+func runStorageReconcile(ctx context.Context, documentService models.DocumentService, cfg *models.StorageReconcileConfig, logger *logrus.Logger) {
+	interval := time.Duration(cfg.IntervalMins) * time.Minute
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, bucket := range cfg.Buckets {
+				report, err := documentService.ReconcileStorage(ctx, models.StorageReconcileRequest{
+					Bucket:               bucket,
+					Prefix:               cfg.Prefix,
+					MinAgeMins:           cfg.MinAgeMins,
+					DryRun:               cfg.DryRun,
+					DeleteOrphanObjects:  cfg.DeleteOrphanObjects,
+					DeleteMissingRecords: cfg.DeleteMissingRecords,
+				})
+				if err != nil {
+					logger.WithError(err).WithField("bucket", bucket).Warn("Failed to reconcile storage")
+					continue
+				}
+				if report.OrphanCount > 0 || report.MissingCount > 0 || len(report.Errors) > 0 {
+					logger.WithFields(logrus.Fields{
+						"bucket":          bucket,
+						"dry_run":         report.DryRun,
+						"orphans":         report.OrphanCount,
+						"orphan_bytes":    report.OrphanBytes,
+						"missing":         report.MissingCount,
+						"deleted_objects": report.DeletedObjects,
+						"deleted_records": report.DeletedRecords,
+						"errors":          len(report.Errors),
+					}).Warn("Storage is out of sync with document records")
+				}
+			}
+		}
+	}
+}
+
 // setupLogger configures the application logger
 func setupLogger(cfg *config.Config) *logrus.Logger {
 	logger := logrus.New()
@@ -235,6 +351,15 @@ func runMigrations(db *gorm.DB, logger *logrus.Logger) error {
 	if err := db.AutoMigrate(&models.Document{}); err != nil {
 		return fmt.Errorf("failed to migrate Document model: %w", err)
 	}
+	if err := db.AutoMigrate(&models.EncryptionPolicy{}); err != nil {
+		return fmt.Errorf("failed to migrate EncryptionPolicy model: %w", err)
+	}
+	if err := db.AutoMigrate(&models.UploadSession{}); err != nil {
+		return fmt.Errorf("failed to migrate UploadSession model: %w", err)
+	}
+	if err := db.AutoMigrate(&models.OnboardingUploadSession{}); err != nil {
+		return fmt.Errorf("failed to migrate OnboardingUploadSession model: %w", err)
+	}
 
 	// Create unique index on path with IF NOT EXISTS to avoid errors on restart
 	// GORM's AutoMigrate doesn't support IF NOT EXISTS for unique constraints
@@ -268,7 +393,7 @@ func createStorageProvider(cfg *config.Config, logger *logrus.Logger) (models.Cl
 }
 
 // setupRouter configures the HTTP router
-func setupRouter(cfg *config.Config, documentService models.DocumentService, logger *logrus.Logger) *gin.Engine { //nolint:funlen
+func setupRouter(cfg *config.Config, documentService models.DocumentService, assetURLs models.AssetURLSigner, logger *logrus.Logger) *gin.Engine { //nolint:funlen
 	// Set Gin mode based on environment
 	if cfg.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
@@ -305,6 +430,31 @@ func setupRouter(cfg *config.Config, documentService models.DocumentService, log
 		health.GET("/live", healthHandler.Live)
 	}
 
+	// Internal service-to-service endpoints (requires X-Internal-Service header)
+	internal := router.Group("/internal")
+	{
+		internal.POST("/storage/reconcile", documentHandler.ReconcileStorage)
+	}
+
+	// Asset proxy for branded asset URLs (no auth: the URL signature authorizes the request)
+	if assetURLs != nil {
+		assets := router.Group(models.AssetURLPathPrefix)
+		assets.Use(middleware.AssetSignatureMiddleware(assetURLs, logger))
+		{
+			assets.GET("/:slug/:bucket/*path", documentHandler.ServeAsset)
+			assets.HEAD("/:slug/:bucket/*path", documentHandler.ServeAsset)
+		}
+	}
+
+	// Anonymous onboarding uploads (no auth: prospects have no account yet; each session has its own token)
+	onboarding := router.Group("/api/v1/onboarding/uploads")
+	onboarding.Use(middleware.ProductMiddleware(logger))
+	{
+		onboarding.POST("", documentHandler.CreateOnboardingUploadSession)
+		onboarding.GET("/:sessionId", documentHandler.GetOnboardingUploadSession)
+		onboarding.POST("/:sessionId/files", documentHandler.UploadOnboardingFile)
+	}
+
 	// Protected API routes
 	api := router.Group("/api/v1")
 
@@ -338,6 +488,16 @@ func setupRouter(cfg *config.Config, documentService models.DocumentService, log
 			documents.POST("/copy", documentHandler.CopyDocument)
 			documents.POST("/move", documentHandler.MoveDocument)
 
+			// Resumable (chunked) uploads
+			uploads := documents.Group("/uploads")
+			{
+				uploads.POST("", documentHandler.CreateUpload)
+				uploads.GET("/:uploadId", documentHandler.GetUploadProgress)
+				uploads.PUT("/:uploadId", documentHandler.UploadChunk)
+				uploads.POST("/:uploadId/complete", documentHandler.CompleteUpload)
+				uploads.DELETE("/:uploadId", documentHandler.AbortUpload)
+			}
+
 			// Batch operations
 			batch := documents.Group("/batch")
 			{
@@ -360,6 +520,13 @@ func setupRouter(cfg *config.Config, documentService models.DocumentService, log
 			storage.GET("/config", documentHandler.GetBucketConfig)
 		}
 
+		// Client-side encryption policy (per tenant)
+		encryption := api.Group("/encryption")
+		{
+			encryption.GET("/policy", documentHandler.GetEncryptionPolicy)
+			encryption.PUT("/policy", documentHandler.UpdateEncryptionPolicy)
+		}
+
 		// Public URL endpoint (for marketplace assets)
 		documents.GET("/public/*path", documentHandler.GetPublicURL)
 	}
@@ -394,7 +561,7 @@ func corsMiddleware(cfg *config.Config) gin.HandlerFunc {
 		if len(cfg.Security.AllowedHeaders) > 0 {
 			c.Header("Access-Control-Allow-Headers", strings.Join(cfg.Security.AllowedHeaders, ", "))
 		} else {
-			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Tenant-ID, X-User-ID, X-Product-ID")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Tenant-ID, X-User-ID, X-Product-ID, X-Upload-Token")
 		}
 
 		if len(cfg.Security.AllowedMethods) > 0 {
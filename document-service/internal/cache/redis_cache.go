@@ -173,3 +173,15 @@ type CachedPresignedURL struct {
 func (c *CachedPresignedURL) IsExpired() bool {
 	return time.Now().Add(5 * time.Minute).After(c.ExpiresAt)
 }
+
+// AssetDomainCacheKey generates a cache key for a tenant's branded asset domain
+// Format: assets:domain:{tenantID}
+func AssetDomainCacheKey(tenantID string) string {
+	return fmt.Sprintf("assets:domain:%s", tenantID)
+}
+
+// CachedAssetDomain represents a cached storefront domain lookup; Domain is empty for tenants without one
+type CachedAssetDomain struct {
+	Domain   string `json:"domain"`
+	Resolved bool   `json:"resolved"`
+}
@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CustomDomainClient looks up tenant domains in custom-domain-service
+type CustomDomainClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewCustomDomainClient creates a new custom-domain-service client
+func NewCustomDomainClient(baseURL string) *CustomDomainClient {
+	return &CustomDomainClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// domainListResponse is the part of custom-domain-service's domain list used here
+type domainListResponse struct {
+	Domains []struct {
+		Domain        string `json:"domain"`
+		DomainType    string `json:"domain_type"`
+		TargetType    string `json:"target_type"`
+		Status        string `json:"status"`
+		PrimaryDomain bool   `json:"primary_domain"`
+	} `json:"domains"`
+}
+
+// GetStorefrontDomain returns the tenant's active storefront domain, preferring the primary one,
+// or "" if it has none. Wildcard domains are skipped as they do not name a single host.
+func (c *CustomDomainClient) GetStorefrontDomain(ctx context.Context, tenantID string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/v1/domains?limit=100", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Tenant-ID", tenantID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call custom-domain-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("custom-domain-service returned status %d", resp.StatusCode)
+	}
+
+	var list domainListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return "", fmt.Errorf("failed to decode domain list: %w", err)
+	}
+
+	domain := ""
+	for _, d := range list.Domains {
+		if d.Status != "active" || (d.TargetType != "" && d.TargetType != "storefront") || d.DomainType == "wildcard" || strings.HasPrefix(d.Domain, "*.") {
+			continue
+		}
+		if d.PrimaryDomain {
+			return d.Domain, nil
+		}
+		if domain == "" {
+			domain = d.Domain
+		}
+	}
+	return domain, nil
+}
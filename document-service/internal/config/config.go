@@ -77,6 +77,15 @@ type StorageConfig struct {
 	Azure models.AzureConfig `mapstructure:"azure"`
 	GCP   models.GCPConfig   `mapstructure:"gcp"`
 	Local models.LocalConfig `mapstructure:"local"`
+
+	// Anonymous uploads during tenant onboarding
+	Onboarding models.OnboardingUploadConfig `mapstructure:"onboarding"`
+
+	// Reconciliation of stored objects against document records
+	Reconcile models.StorageReconcileConfig `mapstructure:"reconcile"`
+
+	// Branded asset URLs served by the asset proxy
+	AssetURLs models.AssetURLConfig `mapstructure:"asset_urls"`
 }
 
 // LoggingConfig holds logging configuration
@@ -174,6 +183,22 @@ func setDefaults() {
 	viper.SetDefault("storage.enable_virus_scanning", false)
 	viper.SetDefault("storage.enable_thumbnails", false)
 	viper.SetDefault("storage.default_cache_control", "public, max-age=3600")
+	viper.SetDefault("storage.onboarding.session_ttl_mins", 120)
+	viper.SetDefault("storage.onboarding.max_file_size", 5242880) // 5MB
+	viper.SetDefault("storage.onboarding.max_files", 5)
+	viper.SetDefault("storage.onboarding.allowed_mime_types", []string{"image/png", "image/jpeg", "image/webp", "image/gif"})
+	viper.SetDefault("storage.reconcile.enabled", true)
+	viper.SetDefault("storage.reconcile.interval_mins", 1440)
+	viper.SetDefault("storage.reconcile.min_age_mins", 1440)
+	viper.SetDefault("storage.reconcile.dry_run", true)
+	viper.SetDefault("storage.reconcile.delete_orphan_objects", false)
+	viper.SetDefault("storage.reconcile.delete_missing_records", false)
+	viper.SetDefault("storage.asset_urls.enabled", false)
+	viper.SetDefault("storage.asset_urls.platform_base_url", "https://assets.tesserix.app")
+	viper.SetDefault("storage.asset_urls.use_custom_domains", true)
+	viper.SetDefault("storage.asset_urls.custom_domain_service_url", "http://custom-domain-service:8080")
+	viper.SetDefault("storage.asset_urls.cache_control", "public, max-age=86400")
+	viper.SetDefault("storage.asset_urls.domain_cache_ttl_mins", 10)
 
 	// Cache defaults
 	viper.SetDefault("cache.enabled", true)
@@ -215,6 +240,25 @@ func bindEnvVars() {
 	viper.BindEnv("storage.public_bucket", "STORAGE_PUBLIC_BUCKET")
 	viper.BindEnv("storage.public_bucket_url", "STORAGE_PUBLIC_BUCKET_URL")
 	viper.BindEnv("storage.max_file_size", "STORAGE_MAX_FILE_SIZE")
+	viper.BindEnv("storage.onboarding.bucket", "ONBOARDING_UPLOAD_BUCKET")
+	viper.BindEnv("storage.onboarding.session_ttl_mins", "ONBOARDING_UPLOAD_TTL_MINS")
+	viper.BindEnv("storage.onboarding.max_file_size", "ONBOARDING_UPLOAD_MAX_FILE_SIZE")
+	viper.BindEnv("storage.onboarding.max_files", "ONBOARDING_UPLOAD_MAX_FILES")
+	viper.BindEnv("storage.reconcile.enabled", "STORAGE_RECONCILE_ENABLED")
+	viper.BindEnv("storage.reconcile.interval_mins", "STORAGE_RECONCILE_INTERVAL_MINS")
+	viper.BindEnv("storage.reconcile.min_age_mins", "STORAGE_RECONCILE_MIN_AGE_MINS")
+	viper.BindEnv("storage.reconcile.dry_run", "STORAGE_RECONCILE_DRY_RUN")
+	viper.BindEnv("storage.reconcile.delete_orphan_objects", "STORAGE_RECONCILE_DELETE_ORPHAN_OBJECTS")
+	viper.BindEnv("storage.reconcile.delete_missing_records", "STORAGE_RECONCILE_DELETE_MISSING_RECORDS")
+	viper.BindEnv("storage.asset_urls.enabled", "ASSET_URLS_ENABLED")
+	viper.BindEnv("storage.asset_urls.platform_base_url", "ASSET_PLATFORM_BASE_URL")
+	viper.BindEnv("storage.asset_urls.use_custom_domains", "ASSET_URLS_USE_CUSTOM_DOMAINS")
+	viper.BindEnv("storage.asset_urls.custom_domain_service_url", "CUSTOM_DOMAIN_SERVICE_URL")
+	viper.BindEnv("storage.asset_urls.cache_control", "ASSET_CACHE_CONTROL")
+	// Asset URL signing key is loaded from GCP Secret Manager or ASSET_URL_SIGNING_KEY env var
+	if signingKey := secrets.GetSecretOrEnv("ASSET_URL_SIGNING_KEY_SECRET_NAME", "ASSET_URL_SIGNING_KEY", ""); signingKey != "" {
+		viper.Set("storage.asset_urls.signing_key", signingKey)
+	}
 
 	// AWS
 	viper.BindEnv("storage.aws.region", "AWS_REGION")
@@ -296,6 +340,16 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("max file size must be greater than 0")
 	}
 
+	// Validate branded asset URLs
+	if config.Storage.AssetURLs.Enabled {
+		if config.Storage.AssetURLs.SigningKey == "" {
+			return fmt.Errorf("asset URL signing key is required when branded asset URLs are enabled")
+		}
+		if config.Storage.AssetURLs.PlatformBaseURL == "" {
+			return fmt.Errorf("asset platform base URL is required when branded asset URLs are enabled")
+		}
+	}
+
 	// Validate JWT secret if auth is enabled
 	if config.Security.EnableAuth && config.Security.JWTSecret == "" {
 		return fmt.Errorf("JWT secret is required when authentication is enabled")
@@ -429,6 +483,29 @@ func (c *Config) GetLocalConfig() *models.LocalConfig {
 	return &c.Storage.Local
 }
 
+// GetOnboardingUploadConfig returns the onboarding upload limits; the bucket defaults to the default bucket
+func (c *Config) GetOnboardingUploadConfig() *models.OnboardingUploadConfig {
+	onboarding := c.Storage.Onboarding
+	if onboarding.Bucket == "" {
+		onboarding.Bucket = c.Storage.DefaultBucket
+	}
+	return &onboarding
+}
+
+// GetStorageReconcileConfig returns the storage reconciliation settings; buckets default to the default bucket
+func (c *Config) GetStorageReconcileConfig() *models.StorageReconcileConfig {
+	reconcile := c.Storage.Reconcile
+	if len(reconcile.Buckets) == 0 && c.Storage.DefaultBucket != "" {
+		reconcile.Buckets = []string{c.Storage.DefaultBucket}
+	}
+	return &reconcile
+}
+
+// GetAssetURLConfig returns the branded asset URL settings
+func (c *Config) GetAssetURLConfig() *models.AssetURLConfig {
+	return &c.Storage.AssetURLs
+}
+
 func (c *Config) GetCacheConfig() *CacheConfig {
 	return &c.Cache
 }
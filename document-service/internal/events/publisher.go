@@ -97,13 +97,15 @@ func (p *Publisher) PublishDocumentProcessed(ctx context.Context, tenantID, prod
 }
 
 // PublishDocumentDeleted publishes a document deleted event
-func (p *Publisher) PublishDocumentDeleted(ctx context.Context, tenantID, productID, documentID, bucketName, objectPath, deletedBy string) error {
+// fileSize is the size of the removed object, letting consumers release the storage it used
+func (p *Publisher) PublishDocumentDeleted(ctx context.Context, tenantID, productID, documentID, bucketName, objectPath string, fileSize int64, deletedBy string) error {
 	event := events.NewDocumentEvent(events.DocumentDeleted, tenantID)
 	event.DocumentID = documentID
 	event.ProductID = productID
 	event.SourceService = "document-service"
 	event.BucketName = bucketName
 	event.ObjectPath = objectPath
+	event.FileSize = fileSize
 	event.UploadedBy = deletedBy // Reusing field for actor
 	event.Status = "DELETED"
 
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/sirupsen/logrus"
+)
+
+// onboardingPromotionConsumer is the durable consumer shared by all replicas, so each tenant's
+// onboarding uploads are promoted once
+const onboardingPromotionConsumer = "document-service-onboarding-uploads"
+
+// tenantCreatedEvent is the part of tenant-service's tenant.created event needed to promote
+// onboarding uploads
+type tenantCreatedEvent struct {
+	TenantID  string `json:"tenant_id"`
+	SessionID string `json:"session_id"` // Onboarding session the tenant was created from
+}
+
+// TenantCreatedHandler handles a tenant created from an onboarding session; returning an error
+// redelivers the event
+type TenantCreatedHandler func(ctx context.Context, onboardingSessionID, tenantID string) error
+
+// SubscribeTenantCreated consumes tenant.created events for tenants created through onboarding and
+// passes them to handler. It returns nil without subscribing when NATS is not configured.
+func SubscribeTenantCreated(ctx context.Context, logger *logrus.Logger, handler TenantCreatedHandler) (*events.Subscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		logger.Warn("NATS_URL not set, onboarding upload promotion disabled")
+		return nil, nil
+	}
+
+	config := events.DefaultSubscriberConfig(natsURL, onboardingPromotionConsumer)
+	config.Name = "document-service-onboarding"
+
+	sub, err := events.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sub.Subscribe(ctx, events.StreamTenants, []string{events.TenantCreated}, func(ctx context.Context, msg *events.Message) error {
+		var event tenantCreatedEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			// Acknowledged and dropped; redelivery would not make it parse
+			logger.WithError(err).WithField("subject", msg.Subject).Warn("Dropping malformed tenant created event")
+			return nil
+		}
+		if event.SessionID == "" || event.TenantID == "" {
+			return nil // Not created through onboarding
+		}
+		return handler(ctx, event.SessionID, event.TenantID)
+	})
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	return sub, nil
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServeAsset handles requests for branded asset URLs
+// @Summary Serve an asset through a branded URL
+// @Description Stream an object under the tenant's custom domain or the platform asset domain. The URL is signed
+// @Description (AssetSignatureMiddleware checks sig and exp first); public URLs never expire and are cacheable by the CDN,
+// @Description signed download URLs are cached privately until they expire.
+// @Tags assets
+// @Produce octet-stream
+// @Param slug path string true "Tenant slug"
+// @Param bucket path string true "Bucket name"
+// @Param path path string true "Object path"
+// @Param exp query int false "Expiry (Unix seconds) of signed download URLs"
+// @Param sig query string true "URL signature"
+// @Success 200 {file} file
+// @Success 304 "Not modified"
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /t/{slug}/{bucket}/{path} [get]
+func (h *DocumentHandler) ServeAsset(c *gin.Context) {
+	bucket := c.Param("bucket")
+	path := normalizePath(c.Param("path"))
+	ctx := c.Request.Context()
+
+	metadata, err := h.service.GetDocumentMetadata(ctx, path, bucket)
+	if err != nil {
+		c.Header("Cache-Control", "no-store")
+		if strings.Contains(err.Error(), "not found") {
+			h.respondError(c, http.StatusNotFound, "Asset not found", err)
+		} else {
+			h.respondError(c, http.StatusInternalServerError, "Failed to get asset", err)
+		}
+		return
+	}
+
+	// Public URLs never expire and may be cached by the CDN; signed download URLs are private and
+	// must not outlive their expiry in any cache
+	cacheControl := h.config.GetAssetURLConfig().CacheControl
+	if expiresAt, _ := c.Get("asset_expires_at"); expiresAt != nil && !expiresAt.(time.Time).IsZero() {
+		maxAge := int(time.Until(expiresAt.(time.Time)).Seconds())
+		if maxAge < 0 {
+			maxAge = 0
+		}
+		cacheControl = fmt.Sprintf("private, max-age=%d", maxAge)
+	}
+	if cacheControl != "" {
+		c.Header("Cache-Control", cacheControl)
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Last-Modified", metadata.UpdatedAt.UTC().Format(http.TimeFormat))
+	etag := ""
+	if checksum := metadata.ChecksumSHA256; checksum != "" {
+		etag = `"` + checksum + `"`
+	} else if metadata.Checksum != "" {
+		etag = `"` + metadata.Checksum + `"`
+	}
+	if etag != "" {
+		c.Header("ETag", etag)
+		if match := c.GetHeader("If-None-Match"); match != "" && (match == etag || match == "*") {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	if metadata.Encryption != nil {
+		setEncryptionHeaders(c, metadata.Encryption)
+	}
+
+	contentType := metadata.MimeType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if c.Request.Method == http.MethodHead {
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Length", fmt.Sprintf("%d", metadata.Size))
+		c.Status(http.StatusOK)
+		return
+	}
+
+	stream, _, err := h.service.DownloadDocumentStream(ctx, path, bucket)
+	if err != nil {
+		c.Header("Cache-Control", "no-store")
+		h.respondError(c, http.StatusBadGateway, "Failed to read asset from storage", err)
+		return
+	}
+	defer stream.Close()
+
+	c.DataFromReader(http.StatusOK, metadata.Size, contentType, stream, nil)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -71,8 +72,12 @@ func (h *DocumentHandler) validateBucketAccess(c *gin.Context, bucket string) bo
 // @Param path formData string false "Custom storage path"
 // @Param tags formData string false "JSON string of tags"
 // @Param isPublic formData boolean false "Whether the document should be publicly accessible"
+// @Param encryption formData string false "JSON encryption envelope for client-encrypted content"
+// @Param checksumMd5 formData string false "Expected MD5 of the file (hex or base64)"
+// @Param checksumSha256 formData string false "Expected SHA-256 of the file (hex or base64)"
 // @Success 201 {object} models.Document
 // @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /documents/upload [post]
 func (h *DocumentHandler) UploadDocument(c *gin.Context) {
@@ -116,6 +121,11 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 		TenantID:  tenantID,
 		UserID:    userID,
 		ProductID: middleware.GetProductID(c),
+		// For the branded URL of public files
+		TenantSlug: middleware.GetTenantSlug(c),
+		// Verified against the received content before the document is stored
+		ChecksumMD5:    c.PostForm("checksumMd5"),
+		ChecksumSHA256: c.PostForm("checksumSha256"),
 	}
 
 	// Parse tags if provided - supports both JSON and comma-separated key:value format
@@ -146,10 +156,32 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 		request.Tags = tags
 	}
 
+	// Client-encrypted uploads carry the wrapped DEK alongside the ciphertext
+	if envelopeStr := c.PostForm("encryption"); envelopeStr != "" {
+		var envelope models.EncryptionEnvelope
+		if err := json.Unmarshal([]byte(envelopeStr), &envelope); err != nil {
+			h.respondError(c, http.StatusBadRequest, "Invalid encryption envelope", err)
+			return
+		}
+		request.Encryption = &envelope
+	}
+
 	// Upload document
 	ctx := c.Request.Context()
 	document, err := h.service.UploadDocument(ctx, request, file)
 	if err != nil {
+		if errors.Is(err, models.ErrEncryptionRequired) || errors.Is(err, models.ErrInvalidEnvelope) {
+			h.respondError(c, http.StatusBadRequest, "Upload rejected by encryption policy", err)
+			return
+		}
+		if errors.Is(err, models.ErrInvalidChecksum) {
+			h.respondError(c, http.StatusBadRequest, "Invalid checksum", err)
+			return
+		}
+		if errors.Is(err, models.ErrChecksumMismatch) {
+			h.respondError(c, http.StatusUnprocessableEntity, "Checksum mismatch", err)
+			return
+		}
 		h.logger.WithError(err).Error("Failed to upload document")
 		h.respondError(c, http.StatusInternalServerError, "Failed to upload document", err)
 		return
@@ -166,7 +198,9 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 
 // DownloadDocument handles document download
 // @Summary Download a document
-// @Description Download a document from cloud storage (redirects to presigned URL)
+// @Description Download a document from cloud storage (redirects to presigned URL).
+// @Description Client-encrypted documents return their envelope in X-Encryption-* headers.
+// @Description Stored checksums are returned in X-Checksum-MD5 and X-Checksum-SHA256.
 // @Tags documents
 // @Produce application/octet-stream
 // @Param bucket path string true "Bucket name"
@@ -186,12 +220,23 @@ func (h *DocumentHandler) DownloadDocument(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
+	// Return the key metadata of client-encrypted documents so the client can decrypt the blob,
+	// and the stored checksums so it can verify what it downloads
+	if metadata, err := h.service.GetDocumentMetadata(ctx, path, bucket); err == nil {
+		if metadata.Encryption != nil {
+			setEncryptionHeaders(c, metadata.Encryption)
+		}
+		setChecksumHeaders(c, metadata)
+	}
+
 	// Generate presigned URL instead of streaming
 	response, err := h.service.GeneratePresignedURL(ctx, models.PresignedURLRequest{
-		Bucket:    bucket,
-		Path:      path,
-		Method:    "GET",
-		ExpiresIn: 3600, // 1 hour expiration
+		Bucket:     bucket,
+		Path:       path,
+		Method:     "GET",
+		ExpiresIn:  3600, // 1 hour expiration
+		TenantID:   middleware.GetTenantID(c),
+		TenantSlug: middleware.GetTenantSlug(c),
 	})
 
 	if err != nil {
@@ -273,7 +318,9 @@ func (h *DocumentHandler) UpdateDocumentMetadata(c *gin.Context) {
 	ctx := c.Request.Context()
 	metadata, err := h.service.UpdateDocumentMetadata(ctx, path, bucket, updates)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, models.ErrInvalidEnvelope) {
+			h.respondError(c, http.StatusBadRequest, "Invalid metadata update", err)
+		} else if strings.Contains(err.Error(), "not found") {
 			h.respondError(c, http.StatusNotFound, "Document not found", err)
 		} else {
 			h.respondError(c, http.StatusInternalServerError, "Failed to update document metadata", err)
@@ -384,6 +431,9 @@ func (h *DocumentHandler) GeneratePresignedURL(c *gin.Context) {
 
 	// Set product ID for cache key prefixing
 	request.ProductID = middleware.GetProductID(c)
+	// Tenant context for branded download URLs
+	request.TenantID = middleware.GetTenantID(c)
+	request.TenantSlug = middleware.GetTenantSlug(c)
 
 	ctx := c.Request.Context()
 	response, err := h.service.GeneratePresignedURL(ctx, request)
@@ -551,7 +601,8 @@ func (h *DocumentHandler) GetStorageUsage(c *gin.Context) {
 
 // GetPublicURL handles generating a direct public URL for public bucket assets
 // @Summary Get direct public URL
-// @Description Get a direct public URL for assets in the public bucket (no presigning needed)
+// @Description Get a direct public URL for assets in the public bucket (no presigning needed). When branded asset URLs
+// @Description are enabled, the URL is under the tenant's custom domain or the platform asset domain.
 // @Tags documents
 // @Produce json
 // @Param path path string true "Asset path"
@@ -575,8 +626,16 @@ func (h *DocumentHandler) GetPublicURL(c *gin.Context) {
 		return
 	}
 
-	// Generate direct public URL
-	var url string
+	// Generate direct public URL (branded through the asset proxy when enabled)
+	url, branded := h.service.PublicAssetURL(c.Request.Context(), middleware.GetTenantID(c), middleware.GetTenantSlug(c), publicBucket, path)
+	if branded {
+		c.JSON(http.StatusOK, PublicURLResponse{
+			URL:    url,
+			Bucket: publicBucket,
+			Path:   path,
+		})
+		return
+	}
 	if publicBucketURL != "" {
 		// Use CDN URL if configured
 		url = fmt.Sprintf("%s/%s", strings.TrimSuffix(publicBucketURL, "/"), path)
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"document-service/internal/middleware"
+	"document-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Response headers carrying the envelope of a client-encrypted document on download
+const (
+	headerEncryptionMode       = "X-Encryption-Mode"
+	headerEncryptionAlgorithm  = "X-Encryption-Algorithm"
+	headerEncryptionKeyID      = "X-Encryption-Key-Id"
+	headerEncryptionWrappedKey = "X-Encryption-Wrapped-Key"
+	headerEncryptionIV         = "X-Encryption-IV"
+)
+
+// setEncryptionHeaders adds the envelope needed to decrypt a client-encrypted document
+// The escrow copy is not returned on download; escrow holders read it from the metadata endpoint.
+func setEncryptionHeaders(c *gin.Context, envelope *models.EncryptionEnvelope) {
+	c.Header(headerEncryptionMode, string(envelope.Mode))
+	c.Header(headerEncryptionAlgorithm, envelope.Algorithm)
+	c.Header(headerEncryptionKeyID, envelope.KeyID)
+	c.Header(headerEncryptionWrappedKey, envelope.WrappedKey)
+	if envelope.IV != "" {
+		c.Header(headerEncryptionIV, envelope.IV)
+	}
+	c.Header("Access-Control-Expose-Headers", "X-Encryption-Mode, X-Encryption-Algorithm, X-Encryption-Key-Id, X-Encryption-Wrapped-Key, X-Encryption-IV")
+}
+
+// GetEncryptionPolicy handles getting the tenant's client-side encryption policy
+// @Summary Get encryption policy
+// @Description Get the tenant's client-side encryption policy (defaults apply when none is configured)
+// @Tags encryption
+// @Produce json
+// @Success 200 {object} models.EncryptionPolicy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /encryption/policy [get]
+func (h *DocumentHandler) GetEncryptionPolicy(c *gin.Context) {
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == "" {
+		h.respondError(c, http.StatusBadRequest, "Tenant ID is required", nil)
+		return
+	}
+
+	policy, err := h.service.GetEncryptionPolicy(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, "Failed to get encryption policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdateEncryptionPolicy handles replacing the tenant's client-side encryption policy
+// @Summary Update encryption policy
+// @Description Require client-side envelope encryption for uploads and configure key escrow
+// @Tags encryption
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateEncryptionPolicyRequest true "Encryption policy"
+// @Success 200 {object} models.EncryptionPolicy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /encryption/policy [put]
+func (h *DocumentHandler) UpdateEncryptionPolicy(c *gin.Context) {
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == "" {
+		h.respondError(c, http.StatusBadRequest, "Tenant ID is required", nil)
+		return
+	}
+
+	var request models.UpdateEncryptionPolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	policy, err := h.service.UpdateEncryptionPolicy(c.Request.Context(), tenantID, middleware.GetUserID(c), request)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidPolicy) {
+			h.respondError(c, http.StatusBadRequest, "Invalid encryption policy", err)
+		} else {
+			h.respondError(c, http.StatusInternalServerError, "Failed to update encryption policy", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
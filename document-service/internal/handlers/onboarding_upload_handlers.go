@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"document-service/internal/middleware"
+	"document-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// headerUploadToken carries the token returned when an onboarding upload session is created
+const headerUploadToken = "X-Upload-Token"
+
+// CreateOnboardingUploadRequest starts an anonymous onboarding upload session
+type CreateOnboardingUploadRequest struct {
+	OnboardingSessionID string `json:"onboardingSessionId" binding:"required"`
+}
+
+// CreateOnboardingUploadSession handles starting an anonymous onboarding upload session
+// @Summary Start an onboarding upload session
+// @Description Lets a prospect upload files (e.g. a logo) before a tenant or account exists. The returned token
+// @Description must be sent as X-Upload-Token; files are quarantined until the tenant is created for the onboarding session.
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param request body CreateOnboardingUploadRequest true "Onboarding session"
+// @Success 201 {object} models.OnboardingUploadSession
+// @Failure 400 {object} ErrorResponse
+// @Router /onboarding/uploads [post]
+func (h *DocumentHandler) CreateOnboardingUploadSession(c *gin.Context) {
+	var request CreateOnboardingUploadRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	session, err := h.service.CreateOnboardingUploadSession(c.Request.Context(), request.OnboardingSessionID, middleware.GetProductID(c))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "Failed to start onboarding upload session", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// UploadOnboardingFile handles uploading a file to an onboarding upload session
+// @Summary Upload an onboarding file
+// @Description Store a file in quarantine for the onboarding session; only small images are accepted
+// @Tags onboarding
+// @Accept multipart/form-data
+// @Produce json
+// @Param sessionId path string true "Onboarding upload session ID"
+// @Param X-Upload-Token header string true "Session token"
+// @Param file formData file true "File"
+// @Param mediaType formData string false "What the file is for, e.g. logo"
+// @Success 201 {object} models.OnboardingUploadFile
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /onboarding/uploads/{sessionId}/files [post]
+func (h *DocumentHandler) UploadOnboardingFile(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "Failed to get file from request", err)
+		return
+	}
+	defer file.Close()
+
+	uploaded, err := h.service.UploadOnboardingFile(c.Request.Context(), c.Param("sessionId"), c.GetHeader(headerUploadToken),
+		header.Filename, header.Header.Get("Content-Type"), c.PostForm("mediaType"), file)
+	if err != nil {
+		h.respondOnboardingUploadError(c, "Failed to upload file", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, uploaded)
+}
+
+// GetOnboardingUploadSession handles reading an onboarding upload session
+// @Summary Get an onboarding upload session
+// @Description The session's files with short-lived preview URLs while it is active
+// @Tags onboarding
+// @Produce json
+// @Param sessionId path string true "Onboarding upload session ID"
+// @Param X-Upload-Token header string true "Session token"
+// @Success 200 {object} models.OnboardingUploadSession
+// @Failure 404 {object} ErrorResponse
+// @Router /onboarding/uploads/{sessionId} [get]
+func (h *DocumentHandler) GetOnboardingUploadSession(c *gin.Context) {
+	session, err := h.service.GetOnboardingUploadSession(c.Request.Context(), c.Param("sessionId"), c.GetHeader(headerUploadToken))
+	if err != nil {
+		h.respondOnboardingUploadError(c, "Failed to get onboarding upload session", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// respondOnboardingUploadError maps onboarding upload errors to status codes
+func (h *DocumentHandler) respondOnboardingUploadError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, models.ErrOnboardingUploadNotFound):
+		h.respondError(c, http.StatusNotFound, "Onboarding upload session not found", err)
+	case errors.Is(err, models.ErrOnboardingUploadClosed), errors.Is(err, models.ErrOnboardingUploadLimit):
+		h.respondError(c, http.StatusConflict, message, err)
+	case errors.Is(err, models.ErrOnboardingFileRejected):
+		h.respondError(c, http.StatusBadRequest, message, err)
+	default:
+		h.respondError(c, http.StatusInternalServerError, message, err)
+	}
+}
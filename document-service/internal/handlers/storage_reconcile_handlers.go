@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"document-service/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// headerInternalService names the calling service on service-to-service requests
+const headerInternalService = "X-Internal-Service"
+
+// ReconcileStorage handles an on-demand storage reconciliation
+// @Summary Reconcile storage against document records
+// @Description Lists a bucket (optionally under a prefix) and reports objects with no document record and
+// @Description records whose object is missing. Unless dryRun is set, the enabled cleanups delete them.
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param X-Internal-Service header string true "Internal service name"
+// @Param request body models.StorageReconcileRequest true "Bucket, prefix and cleanups to apply"
+// @Success 200 {object} models.StorageReconcileReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /internal/storage/reconcile [post]
+func (h *DocumentHandler) ReconcileStorage(c *gin.Context) {
+	if c.GetHeader(headerInternalService) == "" {
+		h.respondError(c, http.StatusUnauthorized, "Internal service header required", nil)
+		return
+	}
+
+	var request models.StorageReconcileRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"caller":  c.GetHeader(headerInternalService),
+		"bucket":  request.Bucket,
+		"dry_run": request.DryRun,
+	}).Info("Storage reconciliation requested")
+
+	report, err := h.service.ReconcileStorage(c.Request.Context(), request)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, "Failed to reconcile storage", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
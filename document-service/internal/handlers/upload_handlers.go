@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"document-service/internal/middleware"
+	"document-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Headers used by the resumable upload flow
+const (
+	headerUploadOffset = "Upload-Offset" // Byte offset of the chunk in the request body
+	headerContentMD5   = "Content-MD5"   // Optional MD5 of the chunk (base64 or hex)
+
+	headerChecksumMD5    = "X-Checksum-MD5"
+	headerChecksumSHA256 = "X-Checksum-SHA256"
+)
+
+// setChecksumHeaders returns a document's stored checksums (hex) on download
+func setChecksumHeaders(c *gin.Context, metadata *models.DocumentMetadata) {
+	if metadata.Checksum != "" {
+		c.Header(headerChecksumMD5, metadata.Checksum)
+	}
+	if metadata.ChecksumSHA256 != "" {
+		c.Header(headerChecksumSHA256, metadata.ChecksumSHA256)
+	}
+	c.Writer.Header().Add("Access-Control-Expose-Headers", "X-Checksum-MD5, X-Checksum-SHA256")
+}
+
+// CreateUpload handles starting a resumable upload
+// @Summary Start a resumable upload
+// @Description Start a chunked upload for large files. Send chunks in order with PUT /documents/uploads/{uploadId};
+// @Description optional checksumMd5/checksumSha256 are verified against the assembled file on completion.
+// @Tags uploads
+// @Accept json
+// @Produce json
+// @Param request body models.UploadRequest true "Upload details (size is the total file size)"
+// @Success 201 {object} models.UploadProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /documents/uploads [post]
+func (h *DocumentHandler) CreateUpload(c *gin.Context) {
+	var request models.UploadRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// Validate bucket access for this product
+	if !h.validateBucketAccess(c, request.Bucket) {
+		return
+	}
+
+	request.TenantID = middleware.GetTenantID(c)
+	request.UserID = middleware.GetUserID(c)
+	request.ProductID = middleware.GetProductID(c)
+
+	progress, err := h.service.CreateUpload(c.Request.Context(), request)
+	if err != nil {
+		h.respondUploadError(c, "Failed to start upload", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, progress)
+}
+
+// UploadChunk handles receiving one chunk of a resumable upload
+// @Summary Upload a chunk
+// @Description Append the request body at the Upload-Offset header. A Content-MD5 header (base64 or hex) is verified
+// @Description before the chunk is accepted. On 409 the response carries the offset to resume from.
+// @Tags uploads
+// @Accept application/octet-stream
+// @Produce json
+// @Param uploadId path string true "Upload ID"
+// @Param Upload-Offset header int true "Byte offset of this chunk"
+// @Param Content-MD5 header string false "MD5 of this chunk"
+// @Success 200 {object} models.UploadProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} models.UploadProgress
+// @Failure 422 {object} ErrorResponse
+// @Router /documents/uploads/{uploadId} [put]
+func (h *DocumentHandler) UploadChunk(c *gin.Context) {
+	uploadID := c.Param("uploadId")
+	if _, ok := h.uploadProgressForBucket(c, uploadID); !ok {
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader(headerUploadOffset), 10, 64)
+	if err != nil || offset < 0 {
+		h.respondError(c, http.StatusBadRequest, "Upload-Offset header must be a non-negative integer", nil)
+		return
+	}
+
+	progress, err := h.service.UploadChunk(c.Request.Context(), uploadID, middleware.GetTenantID(c), offset, c.GetHeader(headerContentMD5), c.Request.Body)
+	if err != nil {
+		if errors.Is(err, models.ErrUploadOffsetMismatch) {
+			// Tell the client where to resume from
+			if current, progressErr := h.service.GetUploadProgress(c.Request.Context(), uploadID, middleware.GetTenantID(c)); progressErr == nil {
+				c.Header(headerUploadOffset, strconv.FormatInt(current.NextOffset, 10))
+				c.JSON(http.StatusConflict, current)
+				return
+			}
+		}
+		h.respondUploadError(c, "Failed to upload chunk", err)
+		return
+	}
+
+	c.Header(headerUploadOffset, strconv.FormatInt(progress.NextOffset, 10))
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetUploadProgress handles reporting resumable upload progress
+// @Summary Get upload progress
+// @Description Bytes received, percent complete and the offset to resume from; includes the document once completed
+// @Tags uploads
+// @Produce json
+// @Param uploadId path string true "Upload ID"
+// @Success 200 {object} models.UploadProgress
+// @Failure 404 {object} ErrorResponse
+// @Router /documents/uploads/{uploadId} [get]
+func (h *DocumentHandler) GetUploadProgress(c *gin.Context) {
+	progress, ok := h.uploadProgressForBucket(c, c.Param("uploadId"))
+	if !ok {
+		return
+	}
+
+	c.Header(headerUploadOffset, strconv.FormatInt(progress.NextOffset, 10))
+	c.JSON(http.StatusOK, progress)
+}
+
+// CompleteUpload handles assembling a resumable upload into a document
+// @Summary Complete a resumable upload
+// @Description Assemble the received chunks, verify checksums and create the document
+// @Tags uploads
+// @Produce json
+// @Param uploadId path string true "Upload ID"
+// @Success 201 {object} models.Document
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /documents/uploads/{uploadId}/complete [post]
+func (h *DocumentHandler) CompleteUpload(c *gin.Context) {
+	uploadID := c.Param("uploadId")
+	if _, ok := h.uploadProgressForBucket(c, uploadID); !ok {
+		return
+	}
+
+	document, err := h.service.CompleteUpload(c.Request.Context(), uploadID, middleware.GetTenantID(c))
+	if err != nil {
+		h.respondUploadError(c, "Failed to complete upload", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// AbortUpload handles cancelling a resumable upload
+// @Summary Abort a resumable upload
+// @Description Cancel the upload and delete the chunks received so far
+// @Tags uploads
+// @Param uploadId path string true "Upload ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /documents/uploads/{uploadId} [delete]
+func (h *DocumentHandler) AbortUpload(c *gin.Context) {
+	uploadID := c.Param("uploadId")
+	if _, ok := h.uploadProgressForBucket(c, uploadID); !ok {
+		return
+	}
+
+	if err := h.service.AbortUpload(c.Request.Context(), uploadID, middleware.GetTenantID(c)); err != nil {
+		h.respondUploadError(c, "Failed to abort upload", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// uploadProgressForBucket loads the upload and checks the caller's product can use its bucket
+func (h *DocumentHandler) uploadProgressForBucket(c *gin.Context, uploadID string) (*models.UploadProgress, bool) {
+	progress, err := h.service.GetUploadProgress(c.Request.Context(), uploadID, middleware.GetTenantID(c))
+	if err != nil {
+		h.respondUploadError(c, "Failed to get upload", err)
+		return nil, false
+	}
+	if !h.validateBucketAccess(c, progress.Bucket) {
+		return nil, false
+	}
+	return progress, true
+}
+
+// respondUploadError maps upload and checksum errors to status codes
+func (h *DocumentHandler) respondUploadError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, models.ErrUploadNotFound):
+		h.respondError(c, http.StatusNotFound, "Upload not found", err)
+	case errors.Is(err, models.ErrChecksumMismatch):
+		h.respondError(c, http.StatusUnprocessableEntity, "Checksum mismatch", err)
+	case errors.Is(err, models.ErrUploadOffsetMismatch), errors.Is(err, models.ErrUploadNotInProgress),
+		errors.Is(err, models.ErrUploadIncomplete):
+		h.respondError(c, http.StatusConflict, message, err)
+	case errors.Is(err, models.ErrUploadChunkTooLarge):
+		h.respondError(c, http.StatusRequestEntityTooLarge, message, err)
+	case errors.Is(err, models.ErrInvalidChecksum):
+		h.respondError(c, http.StatusBadRequest, "Invalid checksum", err)
+	case errors.Is(err, models.ErrEncryptionRequired), errors.Is(err, models.ErrInvalidEnvelope):
+		h.respondError(c, http.StatusBadRequest, "Upload rejected by encryption policy", err)
+	default:
+		h.respondError(c, http.StatusInternalServerError, message, err)
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"document-service/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AssetSignatureMiddleware verifies the signature and expiry of branded asset URLs
+// (/t/:slug/:bucket/*path?exp=...&sig=...) before the asset proxy serves the object.
+// The verified expiry is stored as "asset_expires_at" (zero for URLs that never expire).
+func AssetSignatureMiddleware(signer models.AssetURLSigner, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := strings.TrimPrefix(c.Param("path"), "/")
+		expiresAt, err := signer.Verify(c.Param("slug"), c.Param("bucket"), path, c.Query("exp"), c.Query("sig"))
+		if err != nil {
+			code, message := "INVALID_SIGNATURE", "Asset URL signature is invalid"
+			if errors.Is(err, models.ErrAssetURLExpired) {
+				code, message = "URL_EXPIRED", "Asset URL has expired"
+			}
+			logger.WithFields(logrus.Fields{
+				"slug":   c.Param("slug"),
+				"bucket": c.Param("bucket"),
+				"path":   path,
+			}).WithError(err).Debug("Rejected asset request")
+
+			// Rejections must not be cached, or a CDN would keep serving them after the URL is fixed
+			c.Header("Cache-Control", "no-store")
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    code,
+					"message": message,
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("asset_expires_at", expiresAt)
+		c.Next()
+	}
+}
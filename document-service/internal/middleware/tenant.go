@@ -125,3 +125,14 @@ func HasAnyRole(c *gin.Context, requiredRoles []string) bool {
 	}
 	return false
 }
+
+// GetTenantSlug extracts tenant slug from gin context (set by Istio auth middleware), falling back to
+// the X-Tenant-Slug header in development
+func GetTenantSlug(c *gin.Context) string {
+	if tenantSlug, exists := c.Get("tenant_slug"); exists {
+		if slug, ok := tenantSlug.(string); ok && slug != "" {
+			return slug
+		}
+	}
+	return c.GetHeader("X-Tenant-Slug")
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrAssetURLInvalid = errors.New("asset URL signature is invalid")
+	ErrAssetURLExpired = errors.New("asset URL has expired")
+)
+
+const (
+	// AssetURLPathPrefix is where the asset proxy serves branded URLs: /t/{slug}/{bucket}/{path}
+	AssetURLPathPrefix = "/t"
+	// AssetURLExpiryStep rounds signed URL expiry up so repeated requests get the same URL,
+	// which lets browsers and the CDN cache it
+	AssetURLExpiryStep = 5 * time.Minute
+	// AssetDomainCacheTTL is how long a tenant's storefront domain lookup is cached by default
+	AssetDomainCacheTTL = 10 * time.Minute
+)
+
+// AssetURLConfig controls branded asset URLs. When enabled, public and presigned GET URLs point at the
+// asset proxy under the tenant's custom storefront domain, or the platform CDN domain, instead of the
+// storage provider's hostname.
+type AssetURLConfig struct {
+	Enabled                bool   `json:"enabled" mapstructure:"enabled"`
+	PlatformBaseURL        string `json:"platformBaseUrl" mapstructure:"platform_base_url"` // e.g. https://assets.tesserix.app
+	UseCustomDomains       bool   `json:"useCustomDomains" mapstructure:"use_custom_domains"`
+	CustomDomainServiceURL string `json:"customDomainServiceUrl" mapstructure:"custom_domain_service_url"`
+	SigningKey             string `json:"-" mapstructure:"signing_key"`
+	CacheControl           string `json:"cacheControl" mapstructure:"cache_control"` // For public (non-expiring) URLs
+	DomainCacheTTLMins     int    `json:"domainCacheTtlMins" mapstructure:"domain_cache_ttl_mins"`
+}
+
+// DomainCacheTTL returns how long a tenant's storefront domain lookup is cached
+func (c AssetURLConfig) DomainCacheTTL() time.Duration {
+	if c.DomainCacheTTLMins <= 0 {
+		return AssetDomainCacheTTL
+	}
+	return time.Duration(c.DomainCacheTTLMins) * time.Minute
+}
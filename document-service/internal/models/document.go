@@ -19,28 +19,32 @@ const (
 
 // Document represents a document stored in cloud storage
 type Document struct {
-	ID           uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Filename     string            `json:"filename" gorm:"not null"`
-	OriginalName string            `json:"originalName" gorm:"not null"`
-	MimeType     string            `json:"mimeType" gorm:"not null"`
-	Size         int64             `json:"size" gorm:"not null"`
-	Path         string            `json:"path" gorm:"not null"` // unique index created manually in migrations
-	Bucket       string            `json:"bucket" gorm:"not null"`
-	Provider     CloudProvider     `json:"provider" gorm:"not null"`
-	Checksum     string            `json:"checksum,omitempty"`
-	Tags         map[string]string `json:"tags,omitempty" gorm:"type:jsonb"`
-	IsPublic     bool              `json:"isPublic" gorm:"default:false"`
-	URL          string            `json:"url,omitempty"`
+	ID             uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Filename       string            `json:"filename" gorm:"not null"`
+	OriginalName   string            `json:"originalName" gorm:"not null"`
+	MimeType       string            `json:"mimeType" gorm:"not null"`
+	Size           int64             `json:"size" gorm:"not null"`
+	Path           string            `json:"path" gorm:"not null"` // unique index created manually in migrations
+	Bucket         string            `json:"bucket" gorm:"not null"`
+	Provider       CloudProvider     `json:"provider" gorm:"not null"`
+	Checksum       string            `json:"checksum,omitempty"`       // Hex MD5 of the stored content
+	ChecksumSHA256 string            `json:"checksumSha256,omitempty"` // Hex SHA-256 of the stored content
+	Tags           map[string]string `json:"tags,omitempty" gorm:"type:jsonb"`
+	IsPublic       bool              `json:"isPublic" gorm:"default:false"`
+	URL            string            `json:"url,omitempty"`
 
 	// Metadata
 	ContentEncoding string `json:"contentEncoding,omitempty"`
 	CacheControl    string `json:"cacheControl,omitempty"`
 
+	// Client-side encryption key metadata (empty for plaintext uploads)
+	Encryption EncryptionEnvelope `json:"-" gorm:"embedded;embeddedPrefix:encryption_"`
+
 	// Entity association fields (for better querying)
 	EntityType string `json:"entityType,omitempty" gorm:"index:idx_documents_entity"` // product, category, vendor, user, etc.
 	EntityID   string `json:"entityId,omitempty" gorm:"index:idx_documents_entity"`   // ID of the associated entity
-	MediaType  string `json:"mediaType,omitempty"`                                     // primary, gallery, icon, banner, thumbnail, etc.
-	Position   int    `json:"position" gorm:"default:0"`                               // Display order position for galleries
+	MediaType  string `json:"mediaType,omitempty"`                                    // primary, gallery, icon, banner, thumbnail, etc.
+	Position   int    `json:"position" gorm:"default:0"`                              // Display order position for galleries
 
 	// Audit fields
 	TenantID  string         `json:"tenantId,omitempty" gorm:"index"`
@@ -53,24 +57,26 @@ type Document struct {
 
 // DocumentMetadata represents document metadata without the full document record
 type DocumentMetadata struct {
-	ID           uuid.UUID         `json:"id"`
-	Filename     string            `json:"filename"`
-	OriginalName string            `json:"originalName"`
-	MimeType     string            `json:"mimeType"`
-	Size         int64             `json:"size"`
-	Path         string            `json:"path"`
-	Bucket       string            `json:"bucket"`
-	Provider     CloudProvider     `json:"provider"`
-	Checksum     string            `json:"checksum,omitempty"`
-	Tags         map[string]string `json:"tags,omitempty"`
-	IsPublic     bool              `json:"isPublic"`
-	URL          string            `json:"url,omitempty"`
-	EntityType   string            `json:"entityType,omitempty"`
-	EntityID     string            `json:"entityId,omitempty"`
-	MediaType    string            `json:"mediaType,omitempty"`
-	Position     int               `json:"position"`
-	CreatedAt    time.Time         `json:"createdAt"`
-	UpdatedAt    time.Time         `json:"updatedAt"`
+	ID             uuid.UUID           `json:"id"`
+	Filename       string              `json:"filename"`
+	OriginalName   string              `json:"originalName"`
+	MimeType       string              `json:"mimeType"`
+	Size           int64               `json:"size"`
+	Path           string              `json:"path"`
+	Bucket         string              `json:"bucket"`
+	Provider       CloudProvider       `json:"provider"`
+	Checksum       string              `json:"checksum,omitempty"`
+	ChecksumSHA256 string              `json:"checksumSha256,omitempty"`
+	Tags           map[string]string   `json:"tags,omitempty"`
+	IsPublic       bool                `json:"isPublic"`
+	URL            string              `json:"url,omitempty"`
+	EntityType     string              `json:"entityType,omitempty"`
+	EntityID       string              `json:"entityId,omitempty"`
+	MediaType      string              `json:"mediaType,omitempty"`
+	Position       int                 `json:"position"`
+	Encryption     *EncryptionEnvelope `json:"encryption,omitempty"`
+	CreatedAt      time.Time           `json:"createdAt"`
+	UpdatedAt      time.Time           `json:"updatedAt"`
 }
 
 // UploadRequest represents a document upload request
@@ -85,6 +91,7 @@ type UploadRequest struct {
 	ContentEncoding string            `json:"contentEncoding,omitempty"`
 	CacheControl    string            `json:"cacheControl,omitempty"`
 	TenantID        string            `json:"tenantId,omitempty"`
+	TenantSlug      string            `json:"-"` // For the branded URL of public files
 	UserID          string            `json:"userId,omitempty"`
 	ProductID       string            `json:"productId,omitempty"` // Product ID (extracted from X-Product-ID header)
 	// Entity association (optional - can also be extracted from tags)
@@ -92,6 +99,11 @@ type UploadRequest struct {
 	EntityID   string `json:"entityId,omitempty"`   // ID of the associated entity
 	MediaType  string `json:"mediaType,omitempty"`  // primary, gallery, icon, banner, etc.
 	Position   int    `json:"position,omitempty"`   // Display order for galleries
+	// Client-side envelope encryption (content is already encrypted by the client)
+	Encryption *EncryptionEnvelope `json:"encryption,omitempty"`
+	// Checksums computed by the client (hex or base64); the upload is rejected if the content does not match
+	ChecksumMD5    string `json:"checksumMd5,omitempty"`
+	ChecksumSHA256 string `json:"checksumSha256,omitempty"`
 }
 
 // DownloadResponse represents a document download response
@@ -107,6 +119,9 @@ type PresignedURLRequest struct {
 	Method    string `json:"method,omitempty"`          // GET, PUT, DELETE
 	ExpiresIn int    `json:"expiresIn,omitempty"`       // seconds
 	ProductID string `json:"productId,omitempty"`       // Product ID (from X-Product-ID header)
+	// Tenant context for branded asset URLs (set from the request context, not the body)
+	TenantID   string `json:"-"`
+	TenantSlug string `json:"-"`
 }
 
 // PresignedURLResponse represents a presigned URL response
@@ -172,25 +187,41 @@ type OperationResult struct {
 // ToMetadata converts a Document to DocumentMetadata
 func (d *Document) ToMetadata() DocumentMetadata {
 	return DocumentMetadata{
-		ID:           d.ID,
-		Filename:     d.Filename,
-		OriginalName: d.OriginalName,
-		MimeType:     d.MimeType,
-		Size:         d.Size,
-		Path:         d.Path,
-		Bucket:       d.Bucket,
-		Provider:     d.Provider,
-		Checksum:     d.Checksum,
-		Tags:         d.Tags,
-		IsPublic:     d.IsPublic,
-		URL:          d.URL,
-		EntityType:   d.EntityType,
-		EntityID:     d.EntityID,
-		MediaType:    d.MediaType,
-		Position:     d.Position,
-		CreatedAt:    d.CreatedAt,
-		UpdatedAt:    d.UpdatedAt,
+		ID:             d.ID,
+		Filename:       d.Filename,
+		OriginalName:   d.OriginalName,
+		MimeType:       d.MimeType,
+		Size:           d.Size,
+		Path:           d.Path,
+		Bucket:         d.Bucket,
+		Provider:       d.Provider,
+		Checksum:       d.Checksum,
+		ChecksumSHA256: d.ChecksumSHA256,
+		Tags:           d.Tags,
+		IsPublic:       d.IsPublic,
+		URL:            d.URL,
+		EntityType:     d.EntityType,
+		EntityID:       d.EntityID,
+		MediaType:      d.MediaType,
+		Position:       d.Position,
+		Encryption:     d.Envelope(),
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+// IsClientEncrypted returns true if the content was encrypted by the client before upload
+func (d *Document) IsClientEncrypted() bool {
+	return d.Encryption.Mode == EncryptionModeClientEnvelope
+}
+
+// Envelope returns the document's encryption envelope, or nil for plaintext documents
+func (d *Document) Envelope() *EncryptionEnvelope {
+	if !d.IsClientEncrypted() {
+		return nil
 	}
+	envelope := d.Encryption
+	return &envelope
 }
 
 // TableName returns the table name for the Document model
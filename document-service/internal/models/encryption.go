@@ -0,0 +1,137 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors returned for client-side encrypted uploads
+var (
+	ErrEncryptionRequired = errors.New("tenant policy requires client-side encrypted uploads")
+	ErrInvalidEnvelope    = errors.New("invalid encryption envelope")
+	ErrInvalidPolicy      = errors.New("invalid encryption policy")
+)
+
+// EncryptionMode describes how a document's content is encrypted
+type EncryptionMode string
+
+const (
+	EncryptionModeNone           EncryptionMode = "none"            // Plaintext upload (provider encryption at rest only)
+	EncryptionModeClientEnvelope EncryptionMode = "client_envelope" // Client-encrypted blob, DEK wrapped by a client-held key
+)
+
+// KeyEscrowMode controls whether an escrow copy of the wrapped DEK must accompany uploads
+type KeyEscrowMode string
+
+const (
+	KeyEscrowNone     KeyEscrowMode = "none"     // No escrow copy is stored
+	KeyEscrowOptional KeyEscrowMode = "optional" // An escrow copy is stored when the client sends one
+	KeyEscrowRequired KeyEscrowMode = "required" // Uploads are rejected without an escrow copy
+)
+
+// SupportedEnvelopeAlgorithms lists the content encryption algorithms accepted for envelope uploads
+var SupportedEnvelopeAlgorithms = []string{"AES-256-GCM", "XChaCha20-Poly1305"}
+
+// EncryptionEnvelope holds the key material needed by the client to decrypt a document
+// The service never sees the DEK in plaintext: it is wrapped by a key that only the client (or escrow holder) can unwrap.
+type EncryptionEnvelope struct {
+	Mode             EncryptionMode `json:"mode" gorm:"size:30"`
+	Algorithm        string         `json:"algorithm" gorm:"size:50"`                    // Content encryption algorithm, e.g. AES-256-GCM
+	KeyID            string         `json:"keyId" gorm:"size:255"`                       // Identifier of the client KEK that wrapped the DEK
+	WrappedKey       string         `json:"wrappedKey" gorm:"type:text"`                 // Base64 DEK wrapped by KeyID
+	IV               string         `json:"iv,omitempty" gorm:"size:100"`                // Base64 nonce used for content encryption
+	EscrowKeyID      string         `json:"escrowKeyId,omitempty" gorm:"size:255"`       // Identifier of the escrow key
+	EscrowWrappedKey string         `json:"escrowWrappedKey,omitempty" gorm:"type:text"` // Base64 DEK wrapped by the escrow key
+}
+
+// Validate checks the envelope fields and that wrapped keys are valid base64
+func (e *EncryptionEnvelope) Validate() error {
+	supported := false
+	for _, algorithm := range SupportedEnvelopeAlgorithms {
+		if e.Algorithm == algorithm {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidEnvelope, e.Algorithm)
+	}
+	if e.KeyID == "" {
+		return fmt.Errorf("%w: keyId is required", ErrInvalidEnvelope)
+	}
+	if _, err := base64.StdEncoding.DecodeString(e.WrappedKey); err != nil || e.WrappedKey == "" {
+		return fmt.Errorf("%w: wrappedKey must be non-empty base64", ErrInvalidEnvelope)
+	}
+	if e.IV != "" {
+		if _, err := base64.StdEncoding.DecodeString(e.IV); err != nil {
+			return fmt.Errorf("%w: iv must be base64", ErrInvalidEnvelope)
+		}
+	}
+	if e.EscrowWrappedKey != "" {
+		if _, err := base64.StdEncoding.DecodeString(e.EscrowWrappedKey); err != nil {
+			return fmt.Errorf("%w: escrowWrappedKey must be base64", ErrInvalidEnvelope)
+		}
+		if e.EscrowKeyID == "" {
+			return fmt.Errorf("%w: escrowKeyId is required with escrowWrappedKey", ErrInvalidEnvelope)
+		}
+	}
+	return nil
+}
+
+// EncryptionPolicy holds a tenant's client-side encryption requirements
+type EncryptionPolicy struct {
+	TenantID                string        `json:"tenantId" gorm:"primaryKey;size:255"`
+	RequireClientEncryption bool          `json:"requireClientEncryption" gorm:"default:false"` // Reject plaintext uploads
+	KeyEscrow               KeyEscrowMode `json:"keyEscrow" gorm:"size:20;default:'none'"`
+	EscrowKeyID             string        `json:"escrowKeyId,omitempty" gorm:"size:255"` // Escrow key the escrow copy must be wrapped with
+	UpdatedBy               string        `json:"updatedBy,omitempty"`
+	CreatedAt               time.Time     `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt               time.Time     `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the EncryptionPolicy model
+func (EncryptionPolicy) TableName() string {
+	return "tenant_encryption_policies"
+}
+
+// DefaultEncryptionPolicy returns the policy used for tenants that have not configured one
+func DefaultEncryptionPolicy(tenantID string) *EncryptionPolicy {
+	return &EncryptionPolicy{
+		TenantID:  tenantID,
+		KeyEscrow: KeyEscrowNone,
+	}
+}
+
+// Check validates an upload's envelope against the policy
+func (p *EncryptionPolicy) Check(envelope *EncryptionEnvelope) error {
+	if envelope == nil {
+		if p.RequireClientEncryption {
+			return ErrEncryptionRequired
+		}
+		return nil
+	}
+
+	switch p.KeyEscrow {
+	case KeyEscrowRequired:
+		if envelope.EscrowWrappedKey == "" {
+			return fmt.Errorf("%w: tenant policy requires an escrow copy of the wrapped key", ErrInvalidEnvelope)
+		}
+	case KeyEscrowNone:
+		if envelope.EscrowWrappedKey != "" {
+			return fmt.Errorf("%w: key escrow is disabled for this tenant", ErrInvalidEnvelope)
+		}
+	}
+	if envelope.EscrowWrappedKey != "" && p.EscrowKeyID != "" && envelope.EscrowKeyID != p.EscrowKeyID {
+		return fmt.Errorf("%w: escrow copy must be wrapped with key %s", ErrInvalidEnvelope, p.EscrowKeyID)
+	}
+	return nil
+}
+
+// UpdateEncryptionPolicyRequest represents a request to change a tenant's encryption policy
+type UpdateEncryptionPolicyRequest struct {
+	RequireClientEncryption bool          `json:"requireClientEncryption"`
+	KeyEscrow               KeyEscrowMode `json:"keyEscrow" binding:"omitempty,oneof=none optional required"`
+	EscrowKeyID             string        `json:"escrowKeyId,omitempty"`
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"io"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // DocumentService defines the interface for document storage operations
@@ -12,6 +14,14 @@ type DocumentService interface {
 	UploadDocument(ctx context.Context, request UploadRequest, content io.Reader) (*Document, error)
 	UploadFromURL(ctx context.Context, request UploadRequest, sourceURL string) (*Document, error)
 
+	// Resumable (chunked) upload operations
+	CreateUpload(ctx context.Context, request UploadRequest) (*UploadProgress, error)
+	UploadChunk(ctx context.Context, uploadID, tenantID string, offset int64, chunkMD5 string, content io.Reader) (*UploadProgress, error)
+	GetUploadProgress(ctx context.Context, uploadID, tenantID string) (*UploadProgress, error)
+	CompleteUpload(ctx context.Context, uploadID, tenantID string) (*Document, error)
+	AbortUpload(ctx context.Context, uploadID, tenantID string) error
+	CleanupExpiredUploads(ctx context.Context) (int, error)
+
 	// Download operations
 	DownloadDocument(ctx context.Context, path, bucket string) (*DownloadResponse, error)
 	DownloadDocumentStream(ctx context.Context, path, bucket string) (io.ReadCloser, *DocumentMetadata, error)
@@ -34,6 +44,9 @@ type DocumentService interface {
 	// Presigned URL operations
 	GeneratePresignedURL(ctx context.Context, request PresignedURLRequest) (*PresignedURLResponse, error)
 
+	// Branded asset URLs (false when branding is disabled or the tenant slug is unknown)
+	PublicAssetURL(ctx context.Context, tenantID, tenantSlug, bucket, path string) (string, bool)
+
 	// Batch operations
 	BatchDeleteDocuments(ctx context.Context, request BatchRequest) (*BatchResponse, error)
 
@@ -45,6 +58,23 @@ type DocumentService interface {
 	// Storage usage
 	GetStorageUsage(ctx context.Context, bucket string) (*StorageUsage, error)
 
+	// Anonymous onboarding uploads (before the tenant exists)
+	CreateOnboardingUploadSession(ctx context.Context, onboardingSessionID, productID string) (*OnboardingUploadSession, error)
+	UploadOnboardingFile(ctx context.Context, sessionID, token, filename, mimeType, mediaType string, content io.Reader) (*OnboardingUploadFile, error)
+	GetOnboardingUploadSession(ctx context.Context, sessionID, token string) (*OnboardingUploadSession, error)
+	PromoteOnboardingUploads(ctx context.Context, onboardingSessionID, tenantID string) ([]*Document, error)
+	CleanupExpiredOnboardingUploads(ctx context.Context) (int, error)
+
+	// Storage reconciliation (orphaned objects and records whose object is missing)
+	ReconcileStorage(ctx context.Context, request StorageReconcileRequest) (*StorageReconcileReport, error)
+
+	// Client-side encryption policy
+	GetEncryptionPolicy(ctx context.Context, tenantID string) (*EncryptionPolicy, error)
+	UpdateEncryptionPolicy(ctx context.Context, tenantID, userID string, request UpdateEncryptionPolicyRequest) (*EncryptionPolicy, error)
+
+	// Tenant deletion (removes every object and record the tenant owns)
+	PurgeTenant(ctx context.Context, tenantID string) (int64, error)
+
 	// Health check
 	TestConnection(ctx context.Context) error
 }
@@ -75,8 +105,38 @@ type DocumentRepository interface {
 	UpdateMetadata(ctx context.Context, id string, updates map[string]interface{}) error
 	GetDocumentsByPathPrefix(ctx context.Context, bucket, pathPrefix, tenantID string, limit, offset int) ([]*Document, int64, error)
 
+	// Storage reconciliation operations
+	GetExistingPaths(ctx context.Context, bucket string, paths []string) (map[string]bool, error)
+	ListDocumentsAfterPath(ctx context.Context, bucket, pathPrefix, afterPath string, limit int) ([]*Document, error)
+
 	// Search operations
 	Search(ctx context.Context, query string, filters map[string]interface{}, limit, offset int) ([]*Document, int64, error)
+
+	// Encryption policy operations
+	GetEncryptionPolicy(ctx context.Context, tenantID string) (*EncryptionPolicy, error) // nil if the tenant has no policy
+	SaveEncryptionPolicy(ctx context.Context, policy *EncryptionPolicy) error
+
+	// Resumable upload session operations
+	CreateUploadSession(ctx context.Context, session *UploadSession) error
+	GetUploadSession(ctx context.Context, id, tenantID string) (*UploadSession, error)
+	AppendUploadPart(ctx context.Context, session *UploadSession, part UploadPart) (bool, error) // false if another chunk got there first
+	TransitionUploadSession(ctx context.Context, id string, from, to UploadStatus, documentID *uuid.UUID) (bool, error)
+	ListExpiredUploadSessions(ctx context.Context, now time.Time, limit int) ([]*UploadSession, error)
+
+	// Onboarding upload session operations
+	CreateOnboardingUploadSession(ctx context.Context, session *OnboardingUploadSession) error
+	GetOnboardingUploadSession(ctx context.Context, id string) (*OnboardingUploadSession, error)
+	AppendOnboardingUploadFile(ctx context.Context, session *OnboardingUploadSession, file OnboardingUploadFile, maxFiles int) (bool, error) // false if closed, full or changed
+	ListOnboardingUploadSessions(ctx context.Context, onboardingSessionID string) ([]*OnboardingUploadSession, error)
+	TransitionOnboardingUploadSession(ctx context.Context, id uuid.UUID, from, to OnboardingUploadStatus, tenantID string) (bool, error)
+	SaveOnboardingUploadFiles(ctx context.Context, session *OnboardingUploadSession) error
+	ListExpiredOnboardingUploadSessions(ctx context.Context, now time.Time, limit int) ([]*OnboardingUploadSession, error)
+
+	// Tenant deletion operations (hard deletes, including soft-deleted documents)
+	ListTenantDocumentsForPurge(ctx context.Context, tenantID string, limit int) ([]*Document, error)
+	PurgeDocuments(ctx context.Context, ids []uuid.UUID) (int64, error)
+	ListTenantUploadSessions(ctx context.Context, tenantID string) ([]*UploadSession, error)
+	PurgeTenantRecords(ctx context.Context, tenantID string) (int64, error) // upload sessions, onboarding uploads and encryption policy
 }
 
 // CloudStorageProvider defines the interface that all cloud providers must implement
@@ -134,6 +194,20 @@ type CloudStorageObject struct {
 	Metadata     map[string]string `json:"metadata,omitempty"`
 }
 
+// AssetURLSigner builds branded asset URLs served by the asset proxy and verifies their signatures
+type AssetURLSigner interface {
+	Enabled() bool
+	// SignURL returns the branded URL of an object; a ttl of zero or less makes a URL that never expires
+	SignURL(ctx context.Context, tenantID, tenantSlug, bucket, path string, ttl time.Duration) (string, time.Time)
+	// Verify checks the signature and expiry of a branded URL, returning its expiry (zero if it never expires)
+	Verify(tenantSlug, bucket, path, expires, signature string) (time.Time, error)
+}
+
+// TenantDomainResolver looks up the custom storefront domain of a tenant ("" if it has none)
+type TenantDomainResolver interface {
+	GetStorefrontDomain(ctx context.Context, tenantID string) (string, error)
+}
+
 // ConfigProvider defines the interface for configuration management
 type ConfigProvider interface {
 	GetCloudProvider() CloudProvider
@@ -147,6 +221,9 @@ type ConfigProvider interface {
 	GetAzureConfig() *AzureConfig
 	GetGCPConfig() *GCPConfig
 	GetLocalConfig() *LocalConfig
+	GetOnboardingUploadConfig() *OnboardingUploadConfig
+	GetStorageReconcileConfig() *StorageReconcileConfig
+	GetAssetURLConfig() *AssetURLConfig
 }
 
 // AWSConfig represents AWS S3 configuration
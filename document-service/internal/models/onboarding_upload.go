@@ -0,0 +1,103 @@
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors returned for anonymous onboarding uploads
+var (
+	ErrOnboardingUploadNotFound = errors.New("onboarding upload session not found")
+	ErrOnboardingUploadClosed   = errors.New("onboarding upload session is no longer accepting files")
+	ErrOnboardingUploadLimit    = errors.New("onboarding upload session has reached its file limit")
+	ErrOnboardingFileRejected   = errors.New("file is not accepted for onboarding uploads")
+)
+
+const (
+	// OnboardingQuarantinePrefix is where anonymous onboarding files are kept until the tenant exists.
+	// Objects under it have no document record, so the document endpoints never serve them.
+	OnboardingQuarantinePrefix = "quarantine/onboarding"
+	// OnboardingPreviewURLTTL is how long the preview URLs returned with a session stay valid
+	OnboardingPreviewURLTTL = 15 * time.Minute
+)
+
+// OnboardingUploadStatus is the state of an anonymous onboarding upload session
+type OnboardingUploadStatus string
+
+const (
+	OnboardingUploadActive   OnboardingUploadStatus = "active"   // Accepting files
+	OnboardingUploadPromoted OnboardingUploadStatus = "promoted" // Files moved into the tenant's documents
+	OnboardingUploadExpired  OnboardingUploadStatus = "expired"  // Abandoned; quarantined files deleted
+)
+
+// OnboardingUploadConfig limits anonymous uploads made before the prospect has a tenant or account
+type OnboardingUploadConfig struct {
+	Bucket           string   `json:"bucket" mapstructure:"bucket"` // Defaults to the storage default bucket
+	SessionTTLMins   int      `json:"sessionTtlMins" mapstructure:"session_ttl_mins"`
+	MaxFileSize      int64    `json:"maxFileSize" mapstructure:"max_file_size"`
+	MaxFiles         int      `json:"maxFiles" mapstructure:"max_files"`
+	AllowedMimeTypes []string `json:"allowedMimeTypes" mapstructure:"allowed_mime_types"`
+}
+
+// OnboardingUploadFile is a file received in an onboarding upload session
+type OnboardingUploadFile struct {
+	ID             uuid.UUID  `json:"id"`
+	Filename       string     `json:"filename"`
+	MimeType       string     `json:"mimeType"`
+	Size           int64      `json:"size"`
+	MediaType      string     `json:"mediaType,omitempty"` // e.g. logo, favicon, banner
+	ChecksumSHA256 string     `json:"checksumSha256"`
+	Path           string     `json:"-"`                    // Quarantine path
+	DocumentID     *uuid.UUID `json:"documentId,omitempty"` // Set once promoted
+	PreviewURL     string     `json:"previewUrl,omitempty"` // Short-lived download URL, not stored
+	UploadedAt     time.Time  `json:"uploadedAt"`
+}
+
+// OnboardingUploadSession lets a prospect upload files (such as a logo) during onboarding, before
+// a tenant or account exists. It is bound to the tenant-service onboarding session and authorised
+// by a token returned once at creation. When the tenant is created for that onboarding session
+// the files are promoted into the tenant's documents; abandoned sessions expire.
+type OnboardingUploadSession struct {
+	ID                  uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OnboardingSessionID string                 `json:"onboardingSessionId" gorm:"not null;index"`
+	ProductID           string                 `json:"productId,omitempty"`
+	Bucket              string                 `json:"-" gorm:"not null"`
+	TokenHash           string                 `json:"-" gorm:"size:64;not null"`
+	Token               string                 `json:"token,omitempty" gorm:"-"` // Only returned when the session is created
+	Files               []OnboardingUploadFile `json:"files" gorm:"type:jsonb;serializer:json"`
+	Status              OnboardingUploadStatus `json:"status" gorm:"size:20;not null;index"`
+	TenantID            string                 `json:"tenantId,omitempty"`
+	ExpiresAt           time.Time              `json:"expiresAt" gorm:"index"`
+	PromotedAt          *time.Time             `json:"promotedAt,omitempty"`
+	CreatedAt           time.Time              `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time              `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the OnboardingUploadSession model
+func (OnboardingUploadSession) TableName() string {
+	return "document_onboarding_upload_sessions"
+}
+
+// HashOnboardingUploadToken returns the stored form of a session token
+func HashOnboardingUploadToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenMatches reports whether token authorises the session
+func (s *OnboardingUploadSession) TokenMatches(token string) bool {
+	if token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(HashOnboardingUploadToken(token)), []byte(s.TokenHash)) == 1
+}
+
+// AcceptingFiles reports whether the session can still receive files at now
+func (s *OnboardingUploadSession) AcceptingFiles(now time.Time) bool {
+	return s.Status == OnboardingUploadActive && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// StorageReconcileDefaultMinAge keeps objects and records younger than this out of a reconciliation,
+	// so uploads and deletions that are still in flight are not reported
+	StorageReconcileDefaultMinAge = 24 * time.Hour
+	// StorageReconcileMaxReported caps the orphans and missing objects listed in a report; counts are exact
+	StorageReconcileMaxReported = 1000
+)
+
+// StorageReconcileConfig controls the periodic storage reconciliation worker
+type StorageReconcileConfig struct {
+	Enabled              bool     `json:"enabled" mapstructure:"enabled"`
+	IntervalMins         int      `json:"intervalMins" mapstructure:"interval_mins"`
+	Buckets              []string `json:"buckets" mapstructure:"buckets"` // Defaults to the default bucket
+	Prefix               string   `json:"prefix" mapstructure:"prefix"`
+	MinAgeMins           int      `json:"minAgeMins" mapstructure:"min_age_mins"`
+	DryRun               bool     `json:"dryRun" mapstructure:"dry_run"` // Report only, even when deletion is enabled
+	DeleteOrphanObjects  bool     `json:"deleteOrphanObjects" mapstructure:"delete_orphan_objects"`
+	DeleteMissingRecords bool     `json:"deleteMissingRecords" mapstructure:"delete_missing_records"`
+}
+
+// StorageReconcileRequest is one reconciliation of a bucket (or a prefix of it) against the document records
+type StorageReconcileRequest struct {
+	Bucket               string `json:"bucket" binding:"required"`
+	Prefix               string `json:"prefix,omitempty"`
+	MinAgeMins           int    `json:"minAgeMins,omitempty"` // Defaults to 24 hours
+	DryRun               bool   `json:"dryRun"`
+	DeleteOrphanObjects  bool   `json:"deleteOrphanObjects"`  // Delete objects that have no document record
+	DeleteMissingRecords bool   `json:"deleteMissingRecords"` // Delete document records whose object is gone
+}
+
+// MinAge returns how old an object or record must be to be reconciled
+func (r StorageReconcileRequest) MinAge() time.Duration {
+	if r.MinAgeMins <= 0 {
+		return StorageReconcileDefaultMinAge
+	}
+	return time.Duration(r.MinAgeMins) * time.Minute
+}
+
+// OrphanObject is a stored object that no document record points at
+type OrphanObject struct {
+	Path         string    `json:"path"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// MissingObject is a document record whose object is no longer in storage
+type MissingObject struct {
+	DocumentID string    `json:"documentId"`
+	Path       string    `json:"path"`
+	TenantID   string    `json:"tenantId,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// StorageReconcileReport is the outcome of a reconciliation. In a dry run nothing is deleted.
+type StorageReconcileReport struct {
+	Bucket         string          `json:"bucket"`
+	Prefix         string          `json:"prefix,omitempty"`
+	DryRun         bool            `json:"dryRun"`
+	StartedAt      time.Time       `json:"startedAt"`
+	CompletedAt    time.Time       `json:"completedAt"`
+	ScannedObjects int             `json:"scannedObjects"`
+	ScannedRecords int             `json:"scannedRecords"`
+	SkippedObjects int             `json:"skippedObjects"` // Too recent, or owned by upload sessions
+	SkippedRecords int             `json:"skippedRecords"` // Too recent
+	OrphanCount    int             `json:"orphanCount"`
+	OrphanBytes    int64           `json:"orphanBytes"`
+	MissingCount   int             `json:"missingCount"`
+	OrphanObjects  []OrphanObject  `json:"orphanObjects"`
+	MissingObjects []MissingObject `json:"missingObjects"`
+	DeletedObjects int             `json:"deletedObjects"`
+	DeletedRecords int             `json:"deletedRecords"`
+	Errors         []string        `json:"errors,omitempty"`
+}
+
+// IsStorageReconcileExcluded reports whether an object belongs to an upload that has no document
+// record yet: staged resumable upload chunks and quarantined onboarding files. Their own cleanup
+// jobs expire them, so reconciliation leaves them alone.
+func IsStorageReconcileExcluded(path string) bool {
+	return strings.HasPrefix(path, UploadPartsPrefix+"/") || strings.HasPrefix(path, OnboardingQuarantinePrefix+"/")
+}
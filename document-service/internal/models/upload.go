@@ -0,0 +1,136 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors returned for checksum verification and resumable uploads
+var (
+	ErrInvalidChecksum      = errors.New("invalid checksum")
+	ErrChecksumMismatch     = errors.New("checksum does not match the uploaded content")
+	ErrUploadNotFound       = errors.New("upload session not found")
+	ErrUploadNotInProgress  = errors.New("upload session is not in progress")
+	ErrUploadOffsetMismatch = errors.New("chunk offset does not match the bytes received")
+	ErrUploadIncomplete     = errors.New("upload has not received all bytes")
+	ErrUploadChunkTooLarge  = errors.New("chunk exceeds the maximum chunk size")
+)
+
+const (
+	// MaxUploadChunkSize is the largest chunk accepted by a single resumable upload request
+	MaxUploadChunkSize = 32 << 20
+	// UploadSessionTTL is how long an unfinished resumable upload is kept before it is purged
+	UploadSessionTTL = 24 * time.Hour
+	// UploadPartsPrefix is where chunks are staged in the destination bucket until the upload completes
+	UploadPartsPrefix = ".uploads"
+)
+
+// UploadStatus is the state of a resumable upload session
+type UploadStatus string
+
+const (
+	UploadStatusInProgress UploadStatus = "in_progress" // Accepting chunks
+	UploadStatusCompleting UploadStatus = "completing"  // Chunks are being assembled into the document
+	UploadStatusCompleted  UploadStatus = "completed"   // Document created
+	UploadStatusFailed     UploadStatus = "failed"      // Assembled content did not match the expected checksum
+	UploadStatusAborted    UploadStatus = "aborted"     // Cancelled by the client or expired
+)
+
+// UploadPart is one chunk staged in storage
+type UploadPart struct {
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	Path   string `json:"path"`
+	MD5    string `json:"md5"`
+}
+
+// UploadSession tracks a resumable (chunked) upload. Chunks must arrive in order; a client that
+// lost its connection reads the session to find the offset to resume from.
+type UploadSession struct {
+	ID            uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID      string        `json:"tenantId,omitempty" gorm:"index"`
+	UserID        string        `json:"userId,omitempty"`
+	Bucket        string        `json:"bucket" gorm:"not null"`
+	Path          string        `json:"path" gorm:"not null"`
+	Filename      string        `json:"filename" gorm:"not null"`
+	TotalSize     int64         `json:"totalSize" gorm:"not null"`
+	BytesReceived int64         `json:"bytesReceived" gorm:"not null;default:0"`
+	Parts         []UploadPart  `json:"-" gorm:"type:jsonb;serializer:json"`
+	Status        UploadStatus  `json:"status" gorm:"size:20;not null;index"`
+	Request       UploadRequest `json:"-" gorm:"type:jsonb;serializer:json"` // Upload options applied when the document is created
+	DocumentID    *uuid.UUID    `json:"documentId,omitempty" gorm:"type:uuid"`
+	ExpiresAt     time.Time     `json:"expiresAt" gorm:"index"`
+	CreatedAt     time.Time     `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time     `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the UploadSession model
+func (UploadSession) TableName() string {
+	return "document_upload_sessions"
+}
+
+// UploadProgress reports how far a resumable upload has got
+type UploadProgress struct {
+	UploadID       uuid.UUID         `json:"uploadId"`
+	Status         UploadStatus      `json:"status"`
+	Bucket         string            `json:"bucket"`
+	Path           string            `json:"path"`
+	BytesReceived  int64             `json:"bytesReceived"`
+	TotalSize      int64             `json:"totalSize"`
+	Percent        float64           `json:"percent"`
+	PartsReceived  int               `json:"partsReceived"`
+	NextOffset     int64             `json:"nextOffset"` // Offset of the next chunk to send
+	MaxChunkSize   int64             `json:"maxChunkSize"`
+	ExpiresAt      time.Time         `json:"expiresAt"`
+	ChecksumMD5    string            `json:"checksumMd5,omitempty"`    // Expected MD5 supplied when the upload started
+	ChecksumSHA256 string            `json:"checksumSha256,omitempty"` // Expected SHA-256 supplied when the upload started
+	Document       *DocumentMetadata `json:"document,omitempty"`       // Set once the upload is completed
+}
+
+// Progress returns the session's progress report
+func (u *UploadSession) Progress() *UploadProgress {
+	percent := 0.0
+	if u.TotalSize > 0 {
+		percent = math.Round(float64(u.BytesReceived)*10000/float64(u.TotalSize)) / 100
+	}
+	return &UploadProgress{
+		UploadID:       u.ID,
+		Status:         u.Status,
+		Bucket:         u.Bucket,
+		Path:           u.Path,
+		BytesReceived:  u.BytesReceived,
+		TotalSize:      u.TotalSize,
+		Percent:        percent,
+		PartsReceived:  len(u.Parts),
+		NextOffset:     u.BytesReceived,
+		MaxChunkSize:   MaxUploadChunkSize,
+		ExpiresAt:      u.ExpiresAt,
+		ChecksumMD5:    u.Request.ChecksumMD5,
+		ChecksumSHA256: u.Request.ChecksumSHA256,
+	}
+}
+
+// NormalizeChecksum accepts a hex or base64 digest of the given length in bytes and returns it as lowercase hex.
+// An empty value is returned unchanged.
+func NormalizeChecksum(value string, size int) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	if len(value) == 2*size {
+		if decoded, err := hex.DecodeString(value); err == nil {
+			return hex.EncodeToString(decoded), nil
+		}
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(value); err == nil && len(decoded) == size {
+		return hex.EncodeToString(decoded), nil
+	}
+	return "", fmt.Errorf("%w: expected a %d-byte digest in hex or base64", ErrInvalidChecksum, size)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -395,3 +396,356 @@ func (r *documentRepository) GetDocumentsByPathPrefix(ctx context.Context, bucke
 
 	return documents, total, nil
 }
+
+// GetExistingPaths returns which of the given paths in a bucket have a document record
+func (r *documentRepository) GetExistingPaths(ctx context.Context, bucket string, paths []string) (map[string]bool, error) {
+	existing := make(map[string]bool, len(paths))
+	if len(paths) == 0 {
+		return existing, nil
+	}
+
+	var found []string
+	if err := r.db.WithContext(ctx).
+		Model(&models.Document{}).
+		Where("bucket = ? AND path IN ?", bucket, paths).
+		Pluck("path", &found).Error; err != nil {
+		return nil, fmt.Errorf("failed to look up document paths: %w", err)
+	}
+
+	for _, path := range found {
+		existing[path] = true
+	}
+	return existing, nil
+}
+
+// ListDocumentsAfterPath returns up to limit documents of a bucket under a path prefix, ordered by
+// path and starting after afterPath, so large buckets can be walked page by page
+func (r *documentRepository) ListDocumentsAfterPath(ctx context.Context, bucket, pathPrefix, afterPath string, limit int) ([]*models.Document, error) {
+	var documents []*models.Document
+
+	query := r.db.WithContext(ctx).Where("bucket = ?", bucket)
+	if pathPrefix != "" {
+		query = query.Where("path LIKE ?", pathPrefix+"%")
+	}
+	if afterPath != "" {
+		query = query.Where("path > ?", afterPath)
+	}
+
+	if err := query.Order("path").Limit(limit).Find(&documents).Error; err != nil {
+		return nil, fmt.Errorf("failed to list documents by path: %w", err)
+	}
+
+	return documents, nil
+}
+
+// GetEncryptionPolicy retrieves a tenant's encryption policy
+// Returns nil without an error when the tenant has not configured a policy
+func (r *documentRepository) GetEncryptionPolicy(ctx context.Context, tenantID string) (*models.EncryptionPolicy, error) {
+	var policy models.EncryptionPolicy
+
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).First(&policy).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get encryption policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// SaveEncryptionPolicy creates or replaces a tenant's encryption policy
+func (r *documentRepository) SaveEncryptionPolicy(ctx context.Context, policy *models.EncryptionPolicy) error {
+	if err := r.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return fmt.Errorf("failed to save encryption policy: %w", err)
+	}
+	return nil
+}
+
+// CreateUploadSession creates a resumable upload session
+func (r *documentRepository) CreateUploadSession(ctx context.Context, session *models.UploadSession) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create upload session: %w", err)
+	}
+	return nil
+}
+
+// GetUploadSession retrieves an upload session
+// TenantID is required for multi-tenant isolation - prevents cross-tenant access
+func (r *documentRepository) GetUploadSession(ctx context.Context, id, tenantID string) (*models.UploadSession, error) {
+	var session models.UploadSession
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, models.ErrUploadNotFound
+	}
+
+	query := r.db.WithContext(ctx).Where("id = ?", parsedID)
+	// Enforce tenant isolation - tenantID can be empty for internal/system operations
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
+	if err := query.First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, models.ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to get upload session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// AppendUploadPart records a stored chunk if the session is still at the chunk's offset.
+// The offset check makes concurrent chunks for the same offset safe: only one is recorded.
+func (r *documentRepository) AppendUploadPart(ctx context.Context, session *models.UploadSession, part models.UploadPart) (bool, error) {
+	parts := append(append([]models.UploadPart{}, session.Parts...), part)
+	encoded, err := json.Marshal(parts)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode upload parts: %w", err)
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&models.UploadSession{}).
+		Where("id = ? AND status = ? AND bytes_received = ?", session.ID, models.UploadStatusInProgress, part.Offset).
+		Updates(map[string]interface{}{
+			"bytes_received": part.Offset + part.Size,
+			"parts":          gorm.Expr("?::jsonb", string(encoded)),
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record upload part: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	session.Parts = parts
+	session.BytesReceived = part.Offset + part.Size
+	return true, nil
+}
+
+// TransitionUploadSession moves a session between states if it is still in the expected state
+func (r *documentRepository) TransitionUploadSession(ctx context.Context, id string, from, to models.UploadStatus, documentID *uuid.UUID) (bool, error) {
+	updates := map[string]interface{}{
+		"status":     to,
+		"updated_at": time.Now(),
+	}
+	if documentID != nil {
+		updates["document_id"] = *documentID
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&models.UploadSession{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update upload session: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListExpiredUploadSessions returns unfinished upload sessions past their expiry, including
+// completions interrupted by a restart
+func (r *documentRepository) ListExpiredUploadSessions(ctx context.Context, now time.Time, limit int) ([]*models.UploadSession, error) {
+	var sessions []*models.UploadSession
+
+	if err := r.db.WithContext(ctx).
+		Where("status IN ? AND expires_at < ?", []models.UploadStatus{models.UploadStatusInProgress, models.UploadStatusCompleting, models.UploadStatusFailed}, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list expired upload sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// CreateOnboardingUploadSession creates an anonymous onboarding upload session
+func (r *documentRepository) CreateOnboardingUploadSession(ctx context.Context, session *models.OnboardingUploadSession) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create onboarding upload session: %w", err)
+	}
+	return nil
+}
+
+// GetOnboardingUploadSession retrieves an onboarding upload session; callers check its token
+func (r *documentRepository) GetOnboardingUploadSession(ctx context.Context, id string) (*models.OnboardingUploadSession, error) {
+	var session models.OnboardingUploadSession
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, models.ErrOnboardingUploadNotFound
+	}
+
+	if err := r.db.WithContext(ctx).Where("id = ?", parsedID).First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, models.ErrOnboardingUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to get onboarding upload session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// AppendOnboardingUploadFile records a quarantined file if the session is still active, unchanged
+// since it was read and below maxFiles. Returns false when another request changed it first.
+func (r *documentRepository) AppendOnboardingUploadFile(ctx context.Context, session *models.OnboardingUploadSession, file models.OnboardingUploadFile, maxFiles int) (bool, error) {
+	if len(session.Files) >= maxFiles {
+		return false, nil
+	}
+	files := append(append([]models.OnboardingUploadFile{}, session.Files...), file)
+	encoded, err := json.Marshal(files)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode onboarding upload files: %w", err)
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&models.OnboardingUploadSession{}).
+		Where("id = ? AND status = ? AND COALESCE(jsonb_array_length(files), 0) = ?", session.ID, models.OnboardingUploadActive, len(session.Files)).
+		Updates(map[string]interface{}{
+			"files":      gorm.Expr("?::jsonb", string(encoded)),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record onboarding upload file: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	session.Files = files
+	return true, nil
+}
+
+// ListOnboardingUploadSessions returns the active and promoted upload sessions of an onboarding session
+func (r *documentRepository) ListOnboardingUploadSessions(ctx context.Context, onboardingSessionID string) ([]*models.OnboardingUploadSession, error) {
+	var sessions []*models.OnboardingUploadSession
+
+	if err := r.db.WithContext(ctx).
+		Where("onboarding_session_id = ? AND status IN ?", onboardingSessionID, []models.OnboardingUploadStatus{models.OnboardingUploadActive, models.OnboardingUploadPromoted}).
+		Order("created_at").
+		Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list onboarding upload sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// TransitionOnboardingUploadSession moves a session between states if it is still in the expected
+// state; promoting records the tenant the files are promoted into
+func (r *documentRepository) TransitionOnboardingUploadSession(ctx context.Context, id uuid.UUID, from, to models.OnboardingUploadStatus, tenantID string) (bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":     to,
+		"updated_at": now,
+	}
+	if to == models.OnboardingUploadPromoted {
+		updates["tenant_id"] = tenantID
+		updates["promoted_at"] = now
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&models.OnboardingUploadSession{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update onboarding upload session: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SaveOnboardingUploadFiles stores the session's file list (e.g. after files were promoted)
+func (r *documentRepository) SaveOnboardingUploadFiles(ctx context.Context, session *models.OnboardingUploadSession) error {
+	encoded, err := json.Marshal(session.Files)
+	if err != nil {
+		return fmt.Errorf("failed to encode onboarding upload files: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&models.OnboardingUploadSession{}).
+		Where("id = ?", session.ID).
+		Updates(map[string]interface{}{
+			"files":      gorm.Expr("?::jsonb", string(encoded)),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update onboarding upload files: %w", err)
+	}
+	return nil
+}
+
+// ListExpiredOnboardingUploadSessions returns active onboarding upload sessions past their expiry
+func (r *documentRepository) ListExpiredOnboardingUploadSessions(ctx context.Context, now time.Time, limit int) ([]*models.OnboardingUploadSession, error) {
+	var sessions []*models.OnboardingUploadSession
+
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", models.OnboardingUploadActive, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list expired onboarding upload sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// ListTenantDocumentsForPurge returns a page of the tenant's documents, soft-deleted ones included,
+// for tenant deletion. Callers purge each page before reading the next.
+func (r *documentRepository) ListTenantDocumentsForPurge(ctx context.Context, tenantID string, limit int) ([]*models.Document, error) {
+	var documents []*models.Document
+
+	if err := r.db.WithContext(ctx).Unscoped().
+		Where("tenant_id = ?", tenantID).
+		Order("id").
+		Limit(limit).
+		Find(&documents).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tenant documents: %w", err)
+	}
+
+	return documents, nil
+}
+
+// PurgeDocuments permanently deletes document records
+func (r *documentRepository) PurgeDocuments(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Delete(&models.Document{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge documents: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// ListTenantUploadSessions returns every resumable upload session of a tenant, whatever its status
+func (r *documentRepository) ListTenantUploadSessions(ctx context.Context, tenantID string) ([]*models.UploadSession, error) {
+	var sessions []*models.UploadSession
+
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tenant upload sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// PurgeTenantRecords deletes the tenant's upload sessions, promoted onboarding uploads and
+// encryption policy in one transaction
+func (r *documentRepository) PurgeTenantRecords(ctx context.Context, tenantID string) (int64, error) {
+	var purged int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&models.UploadSession{}, &models.OnboardingUploadSession{}, &models.EncryptionPolicy{}} {
+			result := tx.Where("tenant_id = ?", tenantID).Delete(model)
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge tenant records: %w", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"document-service/internal/cache"
+	"document-service/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// assetURLSigner signs branded asset URLs with HMAC-SHA256. The signature covers the tenant slug,
+// bucket, path and expiry but not the host, so a URL verifies whether the request reached the asset
+// proxy through the platform CDN domain or the tenant's custom domain.
+type assetURLSigner struct {
+	config  models.AssetURLConfig
+	domains models.TenantDomainResolver
+	cache   cache.Cache
+	logger  *logrus.Logger
+}
+
+// NewAssetURLSigner creates a signer for branded asset URLs; domains may be nil to always use the
+// platform domain
+func NewAssetURLSigner(config *models.AssetURLConfig, domains models.TenantDomainResolver, c cache.Cache, logger *logrus.Logger) models.AssetURLSigner {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	if c == nil {
+		c = cache.NewNoOpCache()
+	}
+	return &assetURLSigner{
+		config:  *config,
+		domains: domains,
+		cache:   c,
+		logger:  logger,
+	}
+}
+
+// Enabled reports whether branded asset URLs are configured
+func (s *assetURLSigner) Enabled() bool {
+	return s.config.Enabled && s.config.SigningKey != "" && s.config.PlatformBaseURL != ""
+}
+
+// SignURL returns https://{domain}/t/{slug}/{bucket}/{path}?exp=...&sig=...; the expiry is rounded up
+// to models.AssetURLExpiryStep so the URL stays the same (and cacheable) for a while
+func (s *assetURLSigner) SignURL(ctx context.Context, tenantID, tenantSlug, bucket, path string, ttl time.Duration) (string, time.Time) {
+	var expiresAt time.Time
+	expires := ""
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl).Truncate(models.AssetURLExpiryStep).Add(models.AssetURLExpiryStep)
+		expires = strconv.FormatInt(expiresAt.Unix(), 10)
+	}
+
+	query := url.Values{}
+	if expires != "" {
+		query.Set("exp", expires)
+	}
+	query.Set("sig", s.sign(tenantSlug, bucket, path, expires))
+
+	return s.baseURL(ctx, tenantID) + assetURLPath(tenantSlug, bucket, path) + "?" + query.Encode(), expiresAt
+}
+
+// Verify checks the signature of a branded URL, then its expiry
+func (s *assetURLSigner) Verify(tenantSlug, bucket, path, expires, signature string) (time.Time, error) {
+	if !s.Enabled() || signature == "" {
+		return time.Time{}, models.ErrAssetURLInvalid
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(tenantSlug, bucket, path, expires))) {
+		return time.Time{}, models.ErrAssetURLInvalid
+	}
+	if expires == "" {
+		return time.Time{}, nil
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return time.Time{}, models.ErrAssetURLInvalid
+	}
+	expiresAt := time.Unix(unix, 0)
+	if time.Now().After(expiresAt) {
+		return expiresAt, models.ErrAssetURLExpired
+	}
+	return expiresAt, nil
+}
+
+func (s *assetURLSigner) sign(tenantSlug, bucket, path, expires string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.SigningKey))
+	mac.Write([]byte(strings.Join([]string{tenantSlug, bucket, path, expires}, "\n")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// baseURL returns the tenant's custom storefront domain, or the platform domain when the tenant has
+// none or the lookup fails
+func (s *assetURLSigner) baseURL(ctx context.Context, tenantID string) string {
+	platform := strings.TrimSuffix(s.config.PlatformBaseURL, "/")
+	if !s.config.UseCustomDomains || s.domains == nil || tenantID == "" {
+		return platform
+	}
+
+	cacheKey := cache.AssetDomainCacheKey(tenantID)
+	var cached cache.CachedAssetDomain
+	if err := s.cache.GetJSON(ctx, cacheKey, &cached); err != nil || !cached.Resolved {
+		domain, err := s.domains.GetStorefrontDomain(ctx, tenantID)
+		if err != nil {
+			s.logger.WithError(err).WithField("tenant_id", tenantID).Warn("Failed to look up tenant domain, using platform asset domain")
+			return platform
+		}
+		cached = cache.CachedAssetDomain{Domain: domain, Resolved: true}
+		if err := s.cache.SetJSON(ctx, cacheKey, cached, s.config.DomainCacheTTL()); err != nil {
+			s.logger.WithError(err).Warn("Failed to cache tenant asset domain")
+		}
+	}
+
+	if cached.Domain == "" {
+		return platform
+	}
+	return "https://" + cached.Domain
+}
+
+// assetURLPath returns the escaped asset proxy path of an object
+func assetURLPath(tenantSlug, bucket, path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", models.AssetURLPathPrefix, url.PathEscape(tenantSlug), url.PathEscape(bucket), strings.Join(segments, "/"))
+}
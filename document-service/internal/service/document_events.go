@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+
+	"document-service/internal/events"
+	"document-service/internal/models"
+)
+
+// publishDocumentUploaded announces a stored document so other services (e.g. tenant-service
+// storage metering) can track it. Publishing is best-effort and never fails the upload.
+func (s *documentService) publishDocumentUploaded(ctx context.Context, document *models.Document) {
+	publisher := events.GetPublisher()
+	if publisher == nil || document.TenantID == "" {
+		return
+	}
+
+	// The upload already succeeded; don't lose the event when the request ends
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := publisher.PublishDocumentUploaded(ctx, document.TenantID, document.ProductID, document.ID.String(),
+			document.MediaType, document.OriginalName, document.MimeType, document.Bucket, document.Path,
+			document.Size, document.EntityID, document.EntityType, document.UserID); err != nil {
+			s.logger.WithError(err).WithField("document_id", document.ID).Warn("Failed to publish document uploaded event")
+		}
+	}()
+}
+
+// publishDocumentsDeleted announces removed documents with their sizes
+func (s *documentService) publishDocumentsDeleted(ctx context.Context, documents []*models.Document) {
+	publisher := events.GetPublisher()
+	if publisher == nil || len(documents) == 0 {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		for _, document := range documents {
+			if document.TenantID == "" {
+				continue
+			}
+			if err := publisher.PublishDocumentDeleted(ctx, document.TenantID, document.ProductID, document.ID.String(),
+				document.Bucket, document.Path, document.Size, ""); err != nil {
+				s.logger.WithError(err).WithField("document_id", document.ID).Warn("Failed to publish document deleted event")
+			}
+		}
+	}()
+}
+
+// lookupDocuments loads the records of documents about to be deleted so their deletion can be
+// published; paths without a record are skipped
+func (s *documentService) lookupDocuments(ctx context.Context, bucket string, paths []string) []*models.Document {
+	if events.GetPublisher() == nil {
+		return nil
+	}
+
+	documents := make([]*models.Document, 0, len(paths))
+	for _, path := range paths {
+		document, err := s.repository.GetByPath(ctx, path, bucket, "")
+		if err != nil {
+			continue
+		}
+		documents = append(documents, document)
+	}
+	return documents
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -23,6 +24,7 @@ type documentService struct {
 	repository       models.DocumentRepository
 	config           models.ConfigProvider
 	cache            cache.Cache
+	assetURLs        models.AssetURLSigner
 	presignedURLTTL  time.Duration
 	metadataTTL      time.Duration
 	logger           *logrus.Logger
@@ -33,6 +35,8 @@ type ServiceOptions struct {
 	Cache           cache.Cache
 	PresignedURLTTL time.Duration // TTL for caching presigned URLs
 	MetadataTTL     time.Duration // TTL for caching metadata
+	// Branded asset URLs; nil keeps storage provider URLs
+	AssetURLs models.AssetURLSigner
 }
 
 // NewDocumentService creates a new document service
@@ -77,6 +81,7 @@ func NewDocumentServiceWithOptions(
 		if opts.MetadataTTL > 0 {
 			svc.metadataTTL = opts.MetadataTTL
 		}
+		svc.assetURLs = opts.AssetURLs
 	}
 
 	// Use no-op cache if none provided
@@ -116,6 +121,16 @@ func (s *documentService) UploadDocument(ctx context.Context, request models.Upl
 		return nil, err
 	}
 
+	// Enforce the tenant's client-side encryption policy
+	if err := s.checkEncryption(ctx, &request); err != nil {
+		return nil, err
+	}
+
+	// Normalize client-provided checksums so they compare against the server-computed hex digests
+	if err := normalizeRequestChecksums(&request); err != nil {
+		return nil, err
+	}
+
 	// Read content to calculate checksum and validate size
 	contentBytes, err := io.ReadAll(content)
 	if err != nil {
@@ -127,14 +142,22 @@ func (s *documentService) UploadDocument(ctx context.Context, request models.Upl
 		return nil, err
 	}
 
-	// Calculate checksum
+	// Calculate checksums and reject content that was corrupted in transit
 	checksum := fmt.Sprintf("%x", md5.Sum(contentBytes))
+	checksumSHA256 := fmt.Sprintf("%x", sha256.Sum256(contentBytes))
+	if request.ChecksumMD5 != "" && request.ChecksumMD5 != checksum {
+		return nil, fmt.Errorf("%w: MD5 expected %s, computed %s", models.ErrChecksumMismatch, request.ChecksumMD5, checksum)
+	}
+	if request.ChecksumSHA256 != "" && request.ChecksumSHA256 != checksumSHA256 {
+		return nil, fmt.Errorf("%w: SHA-256 expected %s, computed %s", models.ErrChecksumMismatch, request.ChecksumSHA256, checksumSHA256)
+	}
 
 	// Prepare metadata for cloud storage
 	metadata := map[string]string{
-		"original-name": request.Filename,
-		"mime-type":     mimeType,
-		"checksum":      checksum,
+		"original-name":   request.Filename,
+		"mime-type":       mimeType,
+		"checksum":        checksum,
+		"checksum-sha256": checksumSHA256,
 	}
 
 	// Add custom tags to metadata
@@ -142,6 +165,12 @@ func (s *documentService) UploadDocument(ctx context.Context, request models.Upl
 		metadata["tag-"+key] = value
 	}
 
+	// Mark client-encrypted objects so they are recognisable in the bucket (key material stays in the database)
+	if request.Encryption != nil {
+		metadata["encryption-mode"] = string(request.Encryption.Mode)
+		metadata["encryption-key-id"] = request.Encryption.KeyID
+	}
+
 	// Upload to cloud storage
 	contentReader := strings.NewReader(string(contentBytes))
 	if err := s.provider.Upload(ctx, bucket, path, contentReader, metadata); err != nil {
@@ -203,6 +232,7 @@ func (s *documentService) UploadDocument(ctx context.Context, request models.Upl
 		Bucket:          bucket,
 		Provider:        s.provider.GetProviderName(),
 		Checksum:        checksum,
+		ChecksumSHA256:  checksumSHA256,
 		Tags:            request.Tags,
 		IsPublic:        request.IsPublic,
 		ContentEncoding: request.ContentEncoding,
@@ -215,11 +245,15 @@ func (s *documentService) UploadDocument(ctx context.Context, request models.Upl
 		UserID:          request.UserID,
 		ProductID:       request.ProductID,
 	}
+	if request.Encryption != nil {
+		document.Encryption = *request.Encryption
+	}
 
-	// Generate URL if public
+	// Generate URL if public (branded through the asset proxy when enabled)
 	if request.IsPublic {
-		url, err := s.provider.GeneratePresignedURL(ctx, bucket, path, "GET", 365*24*3600) // 1 year for public files
-		if err != nil {
+		if url, ok := s.PublicAssetURL(ctx, request.TenantID, request.TenantSlug, bucket, path); ok {
+			document.URL = url
+		} else if url, err := s.provider.GeneratePresignedURL(ctx, bucket, path, "GET", 365*24*3600); err != nil { // 1 year for public files
 			s.logger.WithError(err).Warn("Failed to generate public URL")
 		} else {
 			document.URL = url
@@ -241,8 +275,11 @@ func (s *documentService) UploadDocument(ctx context.Context, request models.Upl
 		"path":        path,
 		"filename":    request.Filename,
 		"size":        document.Size,
+		"encrypted":   document.IsClientEncrypted(),
 	}).Info("Document uploaded successfully")
 
+	s.publishDocumentUploaded(ctx, document)
+
 	return document, nil
 }
 
@@ -326,6 +363,16 @@ func (s *documentService) UpdateDocumentMetadata(ctx context.Context, path, buck
 		return nil, fmt.Errorf("document not found: %w", err)
 	}
 
+	// Key material is immutable and client-encrypted documents can never be made public
+	for key, value := range updates {
+		if strings.HasPrefix(strings.ToLower(key), "encryption") {
+			return nil, fmt.Errorf("%w: encryption metadata cannot be changed", models.ErrInvalidEnvelope)
+		}
+		if document.IsClientEncrypted() && (key == "is_public" || key == "isPublic") && value == true {
+			return nil, fmt.Errorf("%w: client-encrypted documents cannot be public", models.ErrInvalidEnvelope)
+		}
+	}
+
 	// Update cloud storage metadata if tags are being updated
 	if tags, ok := updates["tags"]; ok {
 		if tagsMap, ok := tags.(map[string]string); ok {
@@ -370,6 +417,8 @@ func (s *documentService) DeleteDocument(ctx context.Context, path, bucket strin
 		return fmt.Errorf("bucket is required")
 	}
 
+	deleted := s.lookupDocuments(ctx, bucket, []string{path})
+
 	// Delete from cloud storage first
 	if err := s.provider.Delete(ctx, bucket, path); err != nil {
 		return fmt.Errorf("failed to delete from cloud storage: %w", err)
@@ -387,6 +436,8 @@ func (s *documentService) DeleteDocument(ctx context.Context, path, bucket strin
 		"path":   path,
 	}).Info("Document deleted successfully")
 
+	s.publishDocumentsDeleted(ctx, deleted)
+
 	return nil
 }
 
@@ -426,6 +477,8 @@ func (s *documentService) CopyDocument(ctx context.Context, sourcePath, destPath
 		return fmt.Errorf("failed to create copy metadata: %w", err)
 	}
 
+	s.publishDocumentUploaded(ctx, &newDoc)
+
 	return nil
 }
 
@@ -535,6 +588,18 @@ func (s *documentService) GeneratePresignedURL(ctx context.Context, request mode
 		expiresIn = 3600 // 1 hour default
 	}
 
+	// Downloads go through the asset proxy when branded URLs are enabled; uploads and deletes still
+	// need the storage provider's URL
+	if method == "GET" {
+		if url, expiresAt, ok := s.brandedAssetURL(ctx, request.TenantID, request.TenantSlug, bucket, request.Path, time.Duration(expiresIn)*time.Second); ok {
+			return &models.PresignedURLResponse{
+				URL:       url,
+				Method:    method,
+				ExpiresAt: expiresAt,
+			}, nil
+		}
+	}
+
 	// Get product ID for cache key prefixing (defaults to "marketplace" if empty)
 	productID := request.ProductID
 
@@ -584,6 +649,21 @@ func (s *documentService) GeneratePresignedURL(ctx context.Context, request mode
 	}, nil
 }
 
+// PublicAssetURL returns the non-expiring branded URL of a public object
+func (s *documentService) PublicAssetURL(ctx context.Context, tenantID, tenantSlug, bucket, path string) (string, bool) {
+	url, _, ok := s.brandedAssetURL(ctx, tenantID, tenantSlug, bucket, path, 0)
+	return url, ok
+}
+
+// brandedAssetURL signs an asset proxy URL; false when branding is disabled or the tenant slug is unknown
+func (s *documentService) brandedAssetURL(ctx context.Context, tenantID, tenantSlug, bucket, path string, ttl time.Duration) (string, time.Time, bool) {
+	if s.assetURLs == nil || !s.assetURLs.Enabled() || tenantSlug == "" {
+		return "", time.Time{}, false
+	}
+	url, expiresAt := s.assetURLs.SignURL(ctx, tenantID, tenantSlug, bucket, path, ttl)
+	return url, expiresAt, true
+}
+
 // BatchDeleteDocuments deletes multiple documents
 func (s *documentService) BatchDeleteDocuments(ctx context.Context, request models.BatchRequest) (*models.BatchResponse, error) {
 	// Bucket is now required - no default fallback
@@ -592,6 +672,8 @@ func (s *documentService) BatchDeleteDocuments(ctx context.Context, request mode
 		return nil, fmt.Errorf("bucket is required")
 	}
 
+	documents := s.lookupDocuments(ctx, bucket, request.Paths)
+
 	// Delete from cloud storage
 	successful, failed, err := s.provider.BatchDelete(ctx, bucket, request.Paths)
 	if err != nil {
@@ -606,6 +688,19 @@ func (s *documentService) BatchDeleteDocuments(ctx context.Context, request mode
 		}
 	}
 
+	// Only documents removed from storage are reported as deleted
+	removed := make(map[string]bool, len(successful))
+	for _, path := range successful {
+		removed[path] = true
+	}
+	deleted := documents[:0]
+	for _, document := range documents {
+		if removed[document.Path] {
+			deleted = append(deleted, document)
+		}
+	}
+	s.publishDocumentsDeleted(ctx, deleted)
+
 	return &models.BatchResponse{
 		Successful:     successful,
 		Failed:         failed,
@@ -636,6 +731,61 @@ func (s *documentService) GetStorageUsage(ctx context.Context, bucket string) (*
 	return s.repository.GetStorageStats(ctx, bucket)
 }
 
+// GetEncryptionPolicy returns a tenant's client-side encryption policy
+// Tenants without a stored policy get the default (plaintext allowed, no escrow)
+func (s *documentService) GetEncryptionPolicy(ctx context.Context, tenantID string) (*models.EncryptionPolicy, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant ID is required")
+	}
+
+	policy, err := s.repository.GetEncryptionPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return models.DefaultEncryptionPolicy(tenantID), nil
+	}
+	return policy, nil
+}
+
+// UpdateEncryptionPolicy replaces a tenant's client-side encryption policy
+// Existing documents keep their envelopes; the policy only applies to new uploads.
+func (s *documentService) UpdateEncryptionPolicy(ctx context.Context, tenantID, userID string, request models.UpdateEncryptionPolicyRequest) (*models.EncryptionPolicy, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant ID is required")
+	}
+
+	keyEscrow := request.KeyEscrow
+	if keyEscrow == "" {
+		keyEscrow = models.KeyEscrowNone
+	}
+	if keyEscrow == models.KeyEscrowRequired && request.EscrowKeyID == "" {
+		return nil, fmt.Errorf("%w: escrowKeyId is required when key escrow is required", models.ErrInvalidPolicy)
+	}
+
+	policy, err := s.GetEncryptionPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	policy.RequireClientEncryption = request.RequireClientEncryption
+	policy.KeyEscrow = keyEscrow
+	policy.EscrowKeyID = request.EscrowKeyID
+	policy.UpdatedBy = userID
+
+	if err := s.repository.SaveEncryptionPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"tenant_id":                 tenantID,
+		"require_client_encryption": policy.RequireClientEncryption,
+		"key_escrow":                policy.KeyEscrow,
+		"updated_by":                userID,
+	}).Info("Encryption policy updated")
+
+	return policy, nil
+}
+
 // TestConnection tests the connection to the cloud provider
 func (s *documentService) TestConnection(ctx context.Context) error {
 	return s.provider.TestConnection(ctx)
@@ -655,6 +805,45 @@ func (s *documentService) validateUploadRequest(request models.UploadRequest) er
 	return nil
 }
 
+// checkEncryption validates the upload's envelope against the tenant's encryption policy
+func (s *documentService) checkEncryption(ctx context.Context, request *models.UploadRequest) error {
+	if request.Encryption != nil {
+		request.Encryption.Mode = models.EncryptionModeClientEnvelope
+		if err := request.Encryption.Validate(); err != nil {
+			return err
+		}
+		if request.IsPublic {
+			return fmt.Errorf("%w: client-encrypted documents cannot be public", models.ErrInvalidEnvelope)
+		}
+	}
+
+	// Internal uploads without a tenant are not subject to tenant policies
+	if request.TenantID == "" {
+		return nil
+	}
+
+	policy, err := s.GetEncryptionPolicy(ctx, request.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to load encryption policy: %w", err)
+	}
+	return policy.Check(request.Encryption)
+}
+
+// normalizeRequestChecksums converts the client's expected checksums to lowercase hex
+func normalizeRequestChecksums(request *models.UploadRequest) error {
+	md5Hex, err := models.NormalizeChecksum(request.ChecksumMD5, md5.Size)
+	if err != nil {
+		return fmt.Errorf("checksumMd5: %w", err)
+	}
+	sha256Hex, err := models.NormalizeChecksum(request.ChecksumSHA256, sha256.Size)
+	if err != nil {
+		return fmt.Errorf("checksumSha256: %w", err)
+	}
+	request.ChecksumMD5 = md5Hex
+	request.ChecksumSHA256 = sha256Hex
+	return nil
+}
+
 func (s *documentService) validateFileSize(size int64) error {
 	maxSize := s.config.GetMaxFileSize()
 	if maxSize > 0 && size > maxSize {
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"document-service/internal/models"
+	"document-service/internal/utils"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateOnboardingUploadSession starts an anonymous upload session for a tenant-service onboarding
+// session. The returned token authorises uploads to it and is not stored in plaintext.
+func (s *documentService) CreateOnboardingUploadSession(ctx context.Context, onboardingSessionID, productID string) (*models.OnboardingUploadSession, error) {
+	if _, err := uuid.Parse(onboardingSessionID); err != nil {
+		return nil, fmt.Errorf("onboardingSessionId must be a UUID")
+	}
+
+	cfg := s.config.GetOnboardingUploadConfig()
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("no bucket is configured for onboarding uploads")
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate upload token: %w", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	session := &models.OnboardingUploadSession{
+		ID:                  uuid.New(),
+		OnboardingSessionID: onboardingSessionID,
+		ProductID:           productID,
+		Bucket:              cfg.Bucket,
+		TokenHash:           models.HashOnboardingUploadToken(token),
+		Files:               []models.OnboardingUploadFile{},
+		Status:              models.OnboardingUploadActive,
+		ExpiresAt:           time.Now().Add(time.Duration(cfg.SessionTTLMins) * time.Minute),
+	}
+	if err := s.repository.CreateOnboardingUploadSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"upload_session_id":     session.ID,
+		"onboarding_session_id": onboardingSessionID,
+	}).Info("Onboarding upload session started")
+
+	session.Token = token
+	return session, nil
+}
+
+// UploadOnboardingFile stores a file in quarantine for the session. Only the configured image
+// types and sizes are accepted, and a session holds a limited number of files.
+func (s *documentService) UploadOnboardingFile(ctx context.Context, sessionID, token, filename, mimeType, mediaType string, content io.Reader) (*models.OnboardingUploadFile, error) {
+	session, err := s.authorizedOnboardingSession(ctx, sessionID, token)
+	if err != nil {
+		return nil, err
+	}
+	if !session.AcceptingFiles(time.Now()) {
+		return nil, models.ErrOnboardingUploadClosed
+	}
+
+	cfg := s.config.GetOnboardingUploadConfig()
+	if len(session.Files) >= cfg.MaxFiles {
+		return nil, fmt.Errorf("%w: at most %d files", models.ErrOnboardingUploadLimit, cfg.MaxFiles)
+	}
+
+	if err := utils.ValidateFilename(filename); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrOnboardingFileRejected, err)
+	}
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = utils.DetectMimeType(filename)
+	}
+	if !onboardingMimeTypeAllowed(cfg.AllowedMimeTypes, mimeType) {
+		return nil, fmt.Errorf("%w: MIME type %s is not allowed", models.ErrOnboardingFileRejected, mimeType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, cfg.MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", models.ErrOnboardingFileRejected)
+	}
+	if int64(len(data)) > cfg.MaxFileSize {
+		return nil, fmt.Errorf("%w: file exceeds %d bytes", models.ErrOnboardingFileRejected, cfg.MaxFileSize)
+	}
+
+	file := models.OnboardingUploadFile{
+		ID:             uuid.New(),
+		Filename:       s.sanitizeFilename(filename),
+		MimeType:       mimeType,
+		Size:           int64(len(data)),
+		MediaType:      strings.TrimSpace(mediaType),
+		ChecksumSHA256: fmt.Sprintf("%x", sha256.Sum256(data)),
+		UploadedAt:     time.Now(),
+	}
+	file.Path = fmt.Sprintf("%s/%s/%s/%s%s", models.OnboardingQuarantinePrefix, session.OnboardingSessionID, session.ID, file.ID, strings.ToLower(filepath.Ext(file.Filename)))
+
+	metadata := map[string]string{
+		"original-name":         filename,
+		"mime-type":             mimeType,
+		"quarantine":            "true",
+		"onboarding-session-id": session.OnboardingSessionID,
+	}
+	if err := s.provider.Upload(ctx, session.Bucket, file.Path, bytes.NewReader(data), metadata); err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+
+	recorded, err := s.repository.AppendOnboardingUploadFile(ctx, session, file, cfg.MaxFiles)
+	if err != nil || !recorded {
+		if deleteErr := s.provider.Delete(ctx, session.Bucket, file.Path); deleteErr != nil {
+			s.logger.WithError(deleteErr).WithField("path", file.Path).Warn("Failed to delete unrecorded onboarding file")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: the session changed while the file was uploading", models.ErrOnboardingUploadClosed)
+	}
+
+	file.PreviewURL = s.onboardingPreviewURL(ctx, session.Bucket, file.Path)
+	return &file, nil
+}
+
+// GetOnboardingUploadSession returns the session and its files with short-lived preview URLs
+func (s *documentService) GetOnboardingUploadSession(ctx context.Context, sessionID, token string) (*models.OnboardingUploadSession, error) {
+	session, err := s.authorizedOnboardingSession(ctx, sessionID, token)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == models.OnboardingUploadActive {
+		for i := range session.Files {
+			session.Files[i].PreviewURL = s.onboardingPreviewURL(ctx, session.Bucket, session.Files[i].Path)
+		}
+	}
+	return session, nil
+}
+
+// PromoteOnboardingUploads moves the quarantined files of an onboarding session into the tenant's
+// documents once the tenant exists. Files already promoted are skipped, so a failed promotion can
+// be retried; the error reports the files that could not be promoted.
+func (s *documentService) PromoteOnboardingUploads(ctx context.Context, onboardingSessionID, tenantID string) ([]*models.Document, error) {
+	sessions, err := s.repository.ListOnboardingUploadSessions(ctx, onboardingSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []*models.Document
+	var failures []error
+	for _, session := range sessions {
+		if session.Status == models.OnboardingUploadActive {
+			// Claiming the session stops further uploads to it before its files are listed
+			claimed, err := s.repository.TransitionOnboardingUploadSession(ctx, session.ID, models.OnboardingUploadActive, models.OnboardingUploadPromoted, tenantID)
+			if err != nil {
+				failures = append(failures, err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+			if session, err = s.repository.GetOnboardingUploadSession(ctx, session.ID.String()); err != nil {
+				failures = append(failures, err)
+				continue
+			}
+		} else if session.TenantID != tenantID {
+			continue
+		}
+
+		promoted, err := s.promoteOnboardingSession(ctx, session, tenantID)
+		documents = append(documents, promoted...)
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+
+	if len(documents) > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"onboarding_session_id": onboardingSessionID,
+			"tenant_id":             tenantID,
+			"documents":             len(documents),
+		}).Info("Promoted onboarding uploads")
+	}
+	return documents, errors.Join(failures...)
+}
+
+// CleanupExpiredOnboardingUploads expires abandoned onboarding upload sessions and deletes their files
+func (s *documentService) CleanupExpiredOnboardingUploads(ctx context.Context) (int, error) {
+	sessions, err := s.repository.ListExpiredOnboardingUploadSessions(ctx, time.Now(), expiredUploadBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, session := range sessions {
+		expired, err := s.repository.TransitionOnboardingUploadSession(ctx, session.ID, models.OnboardingUploadActive, models.OnboardingUploadExpired, "")
+		if err != nil {
+			s.logger.WithError(err).WithField("upload_session_id", session.ID).Warn("Failed to expire onboarding upload session")
+			continue
+		}
+		if !expired {
+			continue
+		}
+		if len(session.Files) > 0 {
+			paths := make([]string, len(session.Files))
+			for i, file := range session.Files {
+				paths[i] = file.Path
+			}
+			if _, failed, err := s.provider.BatchDelete(ctx, session.Bucket, paths); err != nil || len(failed) > 0 {
+				s.logger.WithError(err).WithFields(logrus.Fields{
+					"upload_session_id": session.ID,
+					"failed":            len(failed),
+				}).Warn("Failed to delete some quarantined onboarding files")
+			}
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// promoteOnboardingSession copies each unpromoted file into the tenant's space as a document and
+// deletes the quarantined copy. The file list is saved even when some files fail.
+func (s *documentService) promoteOnboardingSession(ctx context.Context, session *models.OnboardingUploadSession, tenantID string) ([]*models.Document, error) {
+	var documents []*models.Document
+	var failures []error
+	for i := range session.Files {
+		file := &session.Files[i]
+		if file.DocumentID != nil {
+			continue
+		}
+
+		document, err := s.promoteOnboardingFile(ctx, session, file, tenantID)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("file %s: %w", file.ID, err))
+			continue
+		}
+		file.DocumentID = &document.ID
+		documents = append(documents, document)
+
+		if err := s.provider.Delete(ctx, session.Bucket, file.Path); err != nil {
+			s.logger.WithError(err).WithField("path", file.Path).Warn("Failed to delete promoted onboarding file from quarantine")
+		}
+	}
+
+	if len(documents) > 0 {
+		if err := s.repository.SaveOnboardingUploadFiles(ctx, session); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return documents, errors.Join(failures...)
+}
+
+func (s *documentService) promoteOnboardingFile(ctx context.Context, session *models.OnboardingUploadSession, file *models.OnboardingUploadFile, tenantID string) (*models.Document, error) {
+	content, err := s.provider.DownloadStream(ctx, session.Bucket, file.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quarantined file: %w", err)
+	}
+	defer content.Close()
+
+	// The recorded SHA-256 makes UploadDocument reject a quarantined copy that changed
+	return s.UploadDocument(ctx, models.UploadRequest{
+		Filename:       file.Filename,
+		MimeType:       file.MimeType,
+		Size:           file.Size,
+		Bucket:         session.Bucket,
+		Path:           fmt.Sprintf("tenants/%s/onboarding/%s%s", tenantID, file.ID, strings.ToLower(filepath.Ext(file.Filename))),
+		TenantID:       tenantID,
+		ProductID:      session.ProductID,
+		EntityType:     "tenant",
+		EntityID:       tenantID,
+		MediaType:      file.MediaType,
+		ChecksumSHA256: file.ChecksumSHA256,
+	}, content)
+}
+
+// authorizedOnboardingSession loads a session and checks the token; a wrong token looks like a missing session
+func (s *documentService) authorizedOnboardingSession(ctx context.Context, sessionID, token string) (*models.OnboardingUploadSession, error) {
+	session, err := s.repository.GetOnboardingUploadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !session.TokenMatches(token) {
+		return nil, models.ErrOnboardingUploadNotFound
+	}
+	return session, nil
+}
+
+func (s *documentService) onboardingPreviewURL(ctx context.Context, bucket, path string) string {
+	url, err := s.provider.GeneratePresignedURL(ctx, bucket, path, "GET", int(models.OnboardingPreviewURLTTL.Seconds()))
+	if err != nil {
+		s.logger.WithError(err).WithField("path", path).Warn("Failed to generate onboarding preview URL")
+		return ""
+	}
+	return url
+}
+
+// onboardingMimeTypeAllowed matches exact types and "type/*" patterns; an empty list allows only images
+func onboardingMimeTypeAllowed(allowed []string, mimeType string) bool {
+	if len(allowed) == 0 {
+		return strings.HasPrefix(mimeType, "image/") && mimeType != "image/svg+xml"
+	}
+	for _, pattern := range allowed {
+		if pattern == mimeType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(mimeType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"document-service/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// storageReconcilePageSize is how many objects or records are read per storage or database page
+const storageReconcilePageSize = 500
+
+// ReconcileStorage cross-checks a bucket against the document records in both directions: objects
+// no record points at (orphans, e.g. left by failed uploads) and records whose object is gone
+// (e.g. deleted out of band). Objects and records younger than the minimum age are skipped so
+// in-flight uploads and deletions are not reported. Unless the request is a dry run, the enabled
+// cleanups delete the orphans and the dangling records.
+func (s *documentService) ReconcileStorage(ctx context.Context, request models.StorageReconcileRequest) (*models.StorageReconcileReport, error) {
+	if request.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+
+	report := &models.StorageReconcileReport{
+		Bucket:         request.Bucket,
+		Prefix:         request.Prefix,
+		DryRun:         request.DryRun,
+		StartedAt:      time.Now(),
+		OrphanObjects:  []models.OrphanObject{},
+		MissingObjects: []models.MissingObject{},
+	}
+	cutoff := report.StartedAt.Add(-request.MinAge())
+
+	stored, orphans, err := s.findOrphanObjects(ctx, request, cutoff, report)
+	if err != nil {
+		return nil, err
+	}
+	missing, err := s.findMissingObjects(ctx, request, cutoff, stored, report)
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.DryRun {
+		if request.DeleteOrphanObjects {
+			s.deleteOrphanObjects(ctx, request.Bucket, orphans, report)
+		}
+		if request.DeleteMissingRecords {
+			s.deleteMissingRecords(ctx, missing, report)
+		}
+	}
+
+	report.CompletedAt = time.Now()
+	s.logger.WithFields(logrus.Fields{
+		"bucket":          report.Bucket,
+		"prefix":          report.Prefix,
+		"dry_run":         report.DryRun,
+		"scanned_objects": report.ScannedObjects,
+		"scanned_records": report.ScannedRecords,
+		"orphans":         report.OrphanCount,
+		"missing":         report.MissingCount,
+		"deleted_objects": report.DeletedObjects,
+		"deleted_records": report.DeletedRecords,
+	}).Info("Storage reconciliation completed")
+
+	return report, nil
+}
+
+// findOrphanObjects lists the bucket page by page and returns every stored path along with the
+// paths of objects old enough to reconcile that have no document record
+func (s *documentService) findOrphanObjects(ctx context.Context, request models.StorageReconcileRequest, cutoff time.Time, report *models.StorageReconcileReport) (map[string]struct{}, []string, error) {
+	stored := make(map[string]struct{})
+	var orphans []string
+
+	token := ""
+	for {
+		objects, next, err := s.provider.List(ctx, request.Bucket, request.Prefix, storageReconcilePageSize, token)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list storage objects: %w", err)
+		}
+
+		candidates := make([]models.CloudStorageObject, 0, len(objects))
+		paths := make([]string, 0, len(objects))
+		for _, object := range objects {
+			// Some providers repeat the last object of a page at the start of the next one
+			if _, seen := stored[object.Key]; seen {
+				continue
+			}
+			stored[object.Key] = struct{}{}
+			report.ScannedObjects++
+
+			if models.IsStorageReconcileExcluded(object.Key) || object.LastModified.After(cutoff) {
+				report.SkippedObjects++
+				continue
+			}
+			candidates = append(candidates, object)
+			paths = append(paths, object.Key)
+		}
+
+		existing, err := s.repository.GetExistingPaths(ctx, request.Bucket, paths)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, object := range candidates {
+			if existing[object.Key] {
+				continue
+			}
+			orphans = append(orphans, object.Key)
+			report.OrphanCount++
+			report.OrphanBytes += object.Size
+			if len(report.OrphanObjects) < models.StorageReconcileMaxReported {
+				report.OrphanObjects = append(report.OrphanObjects, models.OrphanObject{
+					Path:         object.Key,
+					Size:         object.Size,
+					LastModified: object.LastModified,
+				})
+			}
+		}
+
+		if next == "" || len(objects) == 0 {
+			break
+		}
+		token = next
+	}
+
+	return stored, orphans, nil
+}
+
+// findMissingObjects walks the document records under the prefix and returns those old enough to
+// reconcile whose object was not listed in storage
+func (s *documentService) findMissingObjects(ctx context.Context, request models.StorageReconcileRequest, cutoff time.Time, stored map[string]struct{}, report *models.StorageReconcileReport) ([]*models.Document, error) {
+	var missing []*models.Document
+
+	afterPath := ""
+	for {
+		documents, err := s.repository.ListDocumentsAfterPath(ctx, request.Bucket, request.Prefix, afterPath, storageReconcilePageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, document := range documents {
+			report.ScannedRecords++
+			if document.CreatedAt.After(cutoff) {
+				report.SkippedRecords++
+				continue
+			}
+			if _, ok := stored[document.Path]; ok {
+				continue
+			}
+			missing = append(missing, document)
+			report.MissingCount++
+			if len(report.MissingObjects) < models.StorageReconcileMaxReported {
+				report.MissingObjects = append(report.MissingObjects, models.MissingObject{
+					DocumentID: document.ID.String(),
+					Path:       document.Path,
+					TenantID:   document.TenantID,
+					CreatedAt:  document.CreatedAt,
+				})
+			}
+		}
+
+		if len(documents) < storageReconcilePageSize {
+			break
+		}
+		afterPath = documents[len(documents)-1].Path
+	}
+
+	return missing, nil
+}
+
+func (s *documentService) deleteOrphanObjects(ctx context.Context, bucket string, orphans []string, report *models.StorageReconcileReport) {
+	for start := 0; start < len(orphans); start += storageReconcilePageSize {
+		end := min(start+storageReconcilePageSize, len(orphans))
+		deleted, failed, err := s.provider.BatchDelete(ctx, bucket, orphans[start:end])
+		report.DeletedObjects += len(deleted)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to delete orphaned objects: %v", err))
+			continue
+		}
+		for _, batchErr := range failed {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to delete orphaned object %s: %s", batchErr.Path, batchErr.Error))
+		}
+	}
+}
+
+func (s *documentService) deleteMissingRecords(ctx context.Context, missing []*models.Document, report *models.StorageReconcileReport) {
+	deleted := make([]*models.Document, 0, len(missing))
+	for _, document := range missing {
+		if err := s.repository.Delete(ctx, document.ID.String()); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to delete record of missing object %s: %v", document.Path, err))
+			continue
+		}
+		deleted = append(deleted, document)
+	}
+	report.DeletedRecords = len(deleted)
+	s.publishDocumentsDeleted(ctx, deleted)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// tenantPurgePageSize is how many documents are deleted per page when purging a tenant
+const tenantPurgePageSize = 200
+
+// PurgeTenant permanently deletes a tenant's documents (objects and records, soft-deleted ones
+// included), staged upload chunks, upload sessions and encryption policy, and returns how many
+// items were removed. It is safe to call again for a tenant that is already purged: deleting a
+// missing object succeeds and nothing is left to list.
+func (s *documentService) PurgeTenant(ctx context.Context, tenantID string) (int64, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("tenant ID is required")
+	}
+
+	var purged int64
+	for {
+		documents, err := s.repository.ListTenantDocumentsForPurge(ctx, tenantID, tenantPurgePageSize)
+		if err != nil {
+			return purged, err
+		}
+		if len(documents) == 0 {
+			break
+		}
+
+		ids := make([]uuid.UUID, len(documents))
+		for i, document := range documents {
+			// Objects go first so a failure leaves the record to retry from
+			if err := s.provider.Delete(ctx, document.Bucket, document.Path); err != nil {
+				return purged, fmt.Errorf("failed to delete %s/%s: %w", document.Bucket, document.Path, err)
+			}
+			ids[i] = document.ID
+		}
+
+		deleted, err := s.repository.PurgeDocuments(ctx, ids)
+		if err != nil {
+			return purged, err
+		}
+		purged += deleted
+	}
+
+	sessions, err := s.repository.ListTenantUploadSessions(ctx, tenantID)
+	if err != nil {
+		return purged, err
+	}
+	for _, session := range sessions {
+		s.deleteUploadParts(ctx, session)
+	}
+
+	records, err := s.repository.PurgeTenantRecords(ctx, tenantID)
+	if err != nil {
+		return purged, err
+	}
+	purged += records
+
+	s.logger.WithFields(logrus.Fields{
+		"tenant_id": tenantID,
+		"purged":    purged,
+	}).Info("Tenant documents purged")
+
+	return purged, nil
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"document-service/internal/models"
+	"document-service/internal/utils"
+)
+
+// expiredUploadBatchSize caps how many expired upload sessions one cleanup pass purges
+const expiredUploadBatchSize = 100
+
+// CreateUpload starts a resumable upload. The request is validated up front so that a client
+// does not send every chunk only to be rejected at completion.
+func (s *documentService) CreateUpload(ctx context.Context, request models.UploadRequest) (*models.UploadProgress, error) {
+	if err := s.validateUploadRequest(request); err != nil {
+		return nil, fmt.Errorf("invalid upload request: %w", err)
+	}
+	if request.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	if err := s.validateFileSize(request.Size); err != nil {
+		return nil, err
+	}
+
+	if request.MimeType == "" {
+		request.MimeType = utils.DetectMimeType(request.Filename)
+	}
+	if err := s.validateMimeType(request.MimeType); err != nil {
+		return nil, err
+	}
+	if err := s.checkEncryption(ctx, &request); err != nil {
+		return nil, err
+	}
+	if err := normalizeRequestChecksums(&request); err != nil {
+		return nil, err
+	}
+	if request.Path == "" {
+		request.Path = s.generateFilePath(request.Filename)
+	}
+
+	session := &models.UploadSession{
+		ID:        uuid.New(),
+		TenantID:  request.TenantID,
+		UserID:    request.UserID,
+		Bucket:    request.Bucket,
+		Path:      request.Path,
+		Filename:  request.Filename,
+		TotalSize: request.Size,
+		Status:    models.UploadStatusInProgress,
+		Request:   request,
+		ExpiresAt: time.Now().Add(models.UploadSessionTTL),
+	}
+	if err := s.repository.CreateUploadSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"upload_id": session.ID,
+		"bucket":    session.Bucket,
+		"path":      session.Path,
+		"size":      session.TotalSize,
+	}).Info("Resumable upload started")
+
+	return session.Progress(), nil
+}
+
+// UploadChunk stores the chunk starting at offset. Chunks must be sent in order; chunkMD5
+// (hex or base64, optional) lets the client detect a chunk corrupted in transit and resend it.
+func (s *documentService) UploadChunk(ctx context.Context, uploadID, tenantID string, offset int64, chunkMD5 string, content io.Reader) (*models.UploadProgress, error) {
+	session, err := s.activeUploadSession(ctx, uploadID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if offset != session.BytesReceived {
+		return nil, fmt.Errorf("%w: expected offset %d, got %d", models.ErrUploadOffsetMismatch, session.BytesReceived, offset)
+	}
+
+	expectedMD5, err := models.NormalizeChecksum(chunkMD5, md5.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	chunk, err := io.ReadAll(io.LimitReader(content, models.MaxUploadChunkSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chunk: %w", err)
+	}
+	if len(chunk) == 0 {
+		return session.Progress(), nil
+	}
+	if len(chunk) > models.MaxUploadChunkSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", models.ErrUploadChunkTooLarge, models.MaxUploadChunkSize)
+	}
+	if offset+int64(len(chunk)) > session.TotalSize {
+		return nil, fmt.Errorf("%w: chunk ends at byte %d but the upload is %d bytes", models.ErrUploadChunkTooLarge, offset+int64(len(chunk)), session.TotalSize)
+	}
+
+	computedMD5 := fmt.Sprintf("%x", md5.Sum(chunk))
+	if expectedMD5 != "" && expectedMD5 != computedMD5 {
+		return nil, fmt.Errorf("%w: chunk MD5 expected %s, computed %s", models.ErrChecksumMismatch, expectedMD5, computedMD5)
+	}
+
+	// A random suffix keeps two concurrent requests for the same offset from overwriting each other's part
+	part := models.UploadPart{
+		Offset: offset,
+		Size:   int64(len(chunk)),
+		Path:   fmt.Sprintf("%s/%s/%015d-%s", models.UploadPartsPrefix, session.ID, offset, uuid.New().String()[:8]),
+		MD5:    computedMD5,
+	}
+	if err := s.provider.Upload(ctx, session.Bucket, part.Path, bytes.NewReader(chunk), map[string]string{"upload-id": session.ID.String()}); err != nil {
+		return nil, fmt.Errorf("failed to store chunk: %w", err)
+	}
+
+	recorded, err := s.repository.AppendUploadPart(ctx, session, part)
+	if err != nil || !recorded {
+		if deleteErr := s.provider.Delete(ctx, session.Bucket, part.Path); deleteErr != nil {
+			s.logger.WithError(deleteErr).WithField("path", part.Path).Warn("Failed to delete unrecorded upload part")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: another chunk for offset %d was received first", models.ErrUploadOffsetMismatch, offset)
+	}
+
+	return session.Progress(), nil
+}
+
+// GetUploadProgress reports how many bytes of a resumable upload have been received
+func (s *documentService) GetUploadProgress(ctx context.Context, uploadID, tenantID string) (*models.UploadProgress, error) {
+	session, err := s.repository.GetUploadSession(ctx, uploadID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := session.Progress()
+	if session.Status == models.UploadStatusCompleted && session.DocumentID != nil {
+		if document, err := s.repository.GetByID(ctx, session.DocumentID.String()); err == nil {
+			metadata := document.ToMetadata()
+			progress.Document = &metadata
+		}
+	}
+	return progress, nil
+}
+
+// CompleteUpload assembles the received chunks into the document. The assembled content is
+// checked against each chunk's MD5 and against the checksums supplied when the upload started.
+// Completing an already completed upload returns its document.
+func (s *documentService) CompleteUpload(ctx context.Context, uploadID, tenantID string) (*models.Document, error) {
+	session, err := s.repository.GetUploadSession(ctx, uploadID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == models.UploadStatusCompleted && session.DocumentID != nil {
+		return s.repository.GetByID(ctx, session.DocumentID.String())
+	}
+	if session.Status != models.UploadStatusInProgress {
+		return nil, fmt.Errorf("%w: status is %s", models.ErrUploadNotInProgress, session.Status)
+	}
+	if session.BytesReceived != session.TotalSize {
+		return nil, fmt.Errorf("%w: received %d of %d bytes", models.ErrUploadIncomplete, session.BytesReceived, session.TotalSize)
+	}
+
+	claimed, err := s.repository.TransitionUploadSession(ctx, uploadID, models.UploadStatusInProgress, models.UploadStatusCompleting, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("%w: upload is already being completed", models.ErrUploadNotInProgress)
+	}
+
+	reader := &uploadPartsReader{ctx: ctx, provider: s.provider, bucket: session.Bucket, parts: session.Parts}
+	defer reader.Close()
+
+	document, err := s.UploadDocument(ctx, session.Request, reader)
+	if err != nil {
+		if errors.Is(err, models.ErrChecksumMismatch) {
+			// The received bytes are wrong; resending chunks cannot fix this upload
+			s.finishUpload(ctx, session, models.UploadStatusFailed, nil)
+			return nil, err
+		}
+		// Let the client retry completion after transient storage or database errors
+		if _, revertErr := s.repository.TransitionUploadSession(ctx, uploadID, models.UploadStatusCompleting, models.UploadStatusInProgress, nil); revertErr != nil {
+			s.logger.WithError(revertErr).WithField("upload_id", uploadID).Error("Failed to reopen upload after completion error")
+		}
+		return nil, err
+	}
+
+	s.finishUpload(ctx, session, models.UploadStatusCompleted, &document.ID)
+	return document, nil
+}
+
+// AbortUpload cancels a resumable upload and deletes the chunks received so far
+func (s *documentService) AbortUpload(ctx context.Context, uploadID, tenantID string) error {
+	session, err := s.repository.GetUploadSession(ctx, uploadID, tenantID)
+	if err != nil {
+		return err
+	}
+	if session.Status != models.UploadStatusInProgress && session.Status != models.UploadStatusFailed {
+		return fmt.Errorf("%w: status is %s", models.ErrUploadNotInProgress, session.Status)
+	}
+
+	aborted, err := s.repository.TransitionUploadSession(ctx, uploadID, session.Status, models.UploadStatusAborted, nil)
+	if err != nil {
+		return err
+	}
+	if !aborted {
+		return fmt.Errorf("%w: upload changed state", models.ErrUploadNotInProgress)
+	}
+	s.deleteUploadParts(ctx, session)
+	return nil
+}
+
+// CleanupExpiredUploads aborts unfinished uploads past their expiry and deletes their chunks
+func (s *documentService) CleanupExpiredUploads(ctx context.Context) (int, error) {
+	sessions, err := s.repository.ListExpiredUploadSessions(ctx, time.Now(), expiredUploadBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, session := range sessions {
+		aborted, err := s.repository.TransitionUploadSession(ctx, session.ID.String(), session.Status, models.UploadStatusAborted, nil)
+		if err != nil {
+			s.logger.WithError(err).WithField("upload_id", session.ID).Warn("Failed to expire upload session")
+			continue
+		}
+		if aborted {
+			s.deleteUploadParts(ctx, session)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// activeUploadSession loads a session that can still accept chunks
+func (s *documentService) activeUploadSession(ctx context.Context, uploadID, tenantID string) (*models.UploadSession, error) {
+	session, err := s.repository.GetUploadSession(ctx, uploadID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.UploadStatusInProgress {
+		return nil, fmt.Errorf("%w: status is %s", models.ErrUploadNotInProgress, session.Status)
+	}
+	if session.ExpiresAt.Before(time.Now()) {
+		return nil, fmt.Errorf("%w: upload expired at %s", models.ErrUploadNotInProgress, session.ExpiresAt.Format(time.RFC3339))
+	}
+	return session, nil
+}
+
+func (s *documentService) finishUpload(ctx context.Context, session *models.UploadSession, status models.UploadStatus, documentID *uuid.UUID) {
+	if _, err := s.repository.TransitionUploadSession(ctx, session.ID.String(), models.UploadStatusCompleting, status, documentID); err != nil {
+		s.logger.WithError(err).WithField("upload_id", session.ID).Error("Failed to update upload session status")
+	}
+	s.deleteUploadParts(ctx, session)
+}
+
+func (s *documentService) deleteUploadParts(ctx context.Context, session *models.UploadSession) {
+	if len(session.Parts) == 0 {
+		return
+	}
+	paths := make([]string, len(session.Parts))
+	for i, part := range session.Parts {
+		paths[i] = part.Path
+	}
+	if _, failed, err := s.provider.BatchDelete(ctx, session.Bucket, paths); err != nil || len(failed) > 0 {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"upload_id": session.ID,
+			"failed":    len(failed),
+		}).Warn("Failed to delete some upload parts")
+	}
+}
+
+// uploadPartsReader streams the staged chunks in order, opening one part at a time and
+// verifying each part against the MD5 recorded when it was received
+type uploadPartsReader struct {
+	ctx      context.Context
+	provider models.CloudStorageProvider
+	bucket   string
+	parts    []models.UploadPart
+
+	current io.ReadCloser
+	part    models.UploadPart
+	hash    hash.Hash
+	read    int64
+}
+
+func (r *uploadPartsReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.parts) == 0 {
+				return 0, io.EOF
+			}
+			r.part, r.parts = r.parts[0], r.parts[1:]
+			stream, err := r.provider.DownloadStream(r.ctx, r.bucket, r.part.Path)
+			if err != nil {
+				return 0, fmt.Errorf("failed to read upload part at offset %d: %w", r.part.Offset, err)
+			}
+			r.current, r.hash, r.read = stream, md5.New(), 0
+		}
+
+		n, err := r.current.Read(p)
+		r.hash.Write(p[:n])
+		r.read += int64(n)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if r.read != r.part.Size || fmt.Sprintf("%x", r.hash.Sum(nil)) != r.part.MD5 {
+				return n, fmt.Errorf("%w: stored part at offset %d is corrupted", models.ErrChecksumMismatch, r.part.Offset)
+			}
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *uploadPartsReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
@@ -0,0 +1,32 @@
+-- Migration: Add client-side envelope encryption support
+-- Documents uploaded in envelope mode are encrypted by the client; we only store the wrapped DEK and key metadata
+
+-- Envelope key metadata on documents (all NULL for plaintext uploads)
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS encryption_mode VARCHAR(30);
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS encryption_algorithm VARCHAR(50);
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS encryption_key_id VARCHAR(255);
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS encryption_wrapped_key TEXT;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS encryption_iv VARCHAR(100);
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS encryption_escrow_key_id VARCHAR(255);
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS encryption_escrow_wrapped_key TEXT;
+
+-- Find documents wrapped by a given client key (key rotation)
+CREATE INDEX IF NOT EXISTS idx_documents_tenant_encryption_key
+ON documents(tenant_id, encryption_key_id)
+WHERE deleted_at IS NULL AND encryption_key_id IS NOT NULL;
+
+-- Per-tenant encryption policy
+CREATE TABLE IF NOT EXISTS tenant_encryption_policies (
+    tenant_id VARCHAR(255) PRIMARY KEY,
+    require_client_encryption BOOLEAN DEFAULT FALSE,
+    key_escrow VARCHAR(20) DEFAULT 'none' CHECK (key_escrow IN ('none', 'optional', 'required')),
+    escrow_key_id VARCHAR(255),
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON COLUMN documents.encryption_mode IS 'client_envelope when the content was encrypted by the client before upload';
+COMMENT ON COLUMN documents.encryption_wrapped_key IS 'Base64 data encryption key wrapped by the client key (never stored in plaintext)';
+COMMENT ON COLUMN documents.encryption_escrow_wrapped_key IS 'Base64 data encryption key wrapped by the tenant escrow key';
+COMMENT ON COLUMN tenant_encryption_policies.require_client_encryption IS 'Reject plaintext uploads for this tenant';
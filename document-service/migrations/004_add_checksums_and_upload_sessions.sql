@@ -0,0 +1,32 @@
+-- Migration: Add server-side checksums and resumable upload sessions
+
+-- SHA-256 alongside the existing MD5 checksum
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS checksum_sha256 VARCHAR(64);
+
+-- Resumable (chunked) uploads; chunks are staged under .uploads/<id>/ in the destination bucket
+CREATE TABLE IF NOT EXISTS document_upload_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255),
+    user_id VARCHAR(255),
+    bucket VARCHAR(255) NOT NULL,
+    path TEXT NOT NULL,
+    filename VARCHAR(500) NOT NULL,
+    total_size BIGINT NOT NULL,
+    bytes_received BIGINT NOT NULL DEFAULT 0,
+    parts JSONB,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('in_progress', 'completing', 'completed', 'failed', 'aborted')),
+    request JSONB,
+    document_id UUID,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_upload_sessions_tenant_id ON document_upload_sessions(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_document_upload_sessions_status ON document_upload_sessions(status);
+CREATE INDEX IF NOT EXISTS idx_document_upload_sessions_expires_at ON document_upload_sessions(expires_at);
+
+COMMENT ON COLUMN documents.checksum IS 'Hex MD5 of the stored content';
+COMMENT ON COLUMN documents.checksum_sha256 IS 'Hex SHA-256 of the stored content';
+COMMENT ON COLUMN document_upload_sessions.bytes_received IS 'Offset the next chunk must start at';
+COMMENT ON COLUMN document_upload_sessions.parts IS 'Staged chunks with their offsets, sizes and MD5s';
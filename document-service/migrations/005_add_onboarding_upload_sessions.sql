@@ -0,0 +1,25 @@
+-- Migration: Anonymous upload sessions for onboarding prospects (before a tenant exists)
+
+-- Files are kept under quarantine/onboarding/<onboarding session>/<upload session>/ until
+-- tenant-service creates the tenant, then promoted into tenants/<tenant id>/onboarding/
+CREATE TABLE IF NOT EXISTS document_onboarding_upload_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    onboarding_session_id VARCHAR(255) NOT NULL,
+    product_id VARCHAR(255),
+    bucket VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    files JSONB,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'promoted', 'expired')),
+    tenant_id VARCHAR(255),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    promoted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_onboarding_upload_sessions_onboarding_session_id ON document_onboarding_upload_sessions(onboarding_session_id);
+CREATE INDEX IF NOT EXISTS idx_document_onboarding_upload_sessions_status ON document_onboarding_upload_sessions(status);
+CREATE INDEX IF NOT EXISTS idx_document_onboarding_upload_sessions_expires_at ON document_onboarding_upload_sessions(expires_at);
+
+COMMENT ON COLUMN document_onboarding_upload_sessions.token_hash IS 'SHA-256 of the upload token returned when the session was created';
+COMMENT ON COLUMN document_onboarding_upload_sessions.files IS 'Quarantined files with their checksums and, once promoted, document IDs';
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -95,6 +96,8 @@ func main() {
 	var cacheRepo repository.LocationCacheRepository
 	var addressCacheRepo repository.AddressCacheRepository
 	var placesRepo repository.PlacesRepository
+	var postalCodeRepo repository.PostalCodeRepository
+	var taxJurisdictionRepo repository.TaxJurisdictionRepository
 
 	if db != nil {
 		countryRepo = repository.NewCountryRepository(db, redisClient)
@@ -104,11 +107,79 @@ func main() {
 		cacheRepo = repository.NewLocationCacheRepository(db)
 		addressCacheRepo = repository.NewAddressCacheRepository(db)
 		placesRepo = repository.NewPlacesRepository(db)
+		postalCodeRepo = repository.NewPostalCodeRepository(db)
+		taxJurisdictionRepo = repository.NewTaxJurisdictionRepository(db)
 	}
 
 	// Initialize services
 	locationSvc := services.NewLocationService(countryRepo, stateRepo, currencyRepo, timezoneRepo, cacheRepo)
-	geoSvc := services.NewGeoLocationServiceWithProvider(cfg.Services.GeoLocationProvider)
+	if db != nil {
+		locationSvc.SetLocalizedNameRepository(repository.NewLocalizedNameRepository(db))
+
+		refreshInterval := services.DefaultReferenceExportRefresh
+		if minutes := os.Getenv("REFERENCE_EXPORT_REFRESH_MINUTES"); minutes != "" {
+			if m, err := strconv.Atoi(minutes); err == nil && m > 0 {
+				refreshInterval = time.Duration(m) * time.Minute
+			}
+		}
+		locationSvc.SetReferenceDataRepository(repository.NewReferenceDataRepository(db), refreshInterval)
+	}
+
+	// IP geolocation: GEO_PROVIDER=failover tries MaxMind (local MMDB) → ipapi → ipinfo,
+	// skipping providers that keep failing
+	geoSvc := services.NewGeoLocationServiceWithConfig(services.GeoLocationConfig{
+		Provider:          cfg.Services.GeoLocationProvider,
+		Chain:             strings.Split(cfg.Services.GeoProviderChain, ","),
+		MaxMindDBPath:     cfg.Services.MaxMindDBPath,
+		MaxMindAccountID:  cfg.Services.MaxMindAccountID,
+		MaxMindLicenseKey: cfg.Services.MaxMindLicenseKey,
+		IPAPIKey:          cfg.Services.IPAPIKey,
+		IPInfoToken:       cfg.Services.IPInfoToken,
+	})
+	if maxmind := geoSvc.MaxMind(); maxmind != nil {
+		// Pick up databases installed by other replicas on a shared volume
+		maxmind.Start(time.Minute)
+		if !maxmind.Info().Loaded && cfg.Services.MaxMindAccountID != "" && cfg.Services.MaxMindLicenseKey != "" {
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+				defer cancel()
+				if _, err := maxmind.Refresh(ctx); err != nil {
+					log.Printf("WARNING: Failed to download MaxMind database: %v", err)
+				}
+			}()
+		}
+	}
+	log.Printf("✓ Geolocation service initialized (provider: %s)", cfg.Services.GeoLocationProvider)
+
+	// Postal codes are resolved from GeoNames datasets imported per country; countries without
+	// one (or every country without a database) are validated by format only
+	postalCodeSvc := services.NewPostalCodeService(postalCodeRepo, stateRepo, os.Getenv("GEONAMES_POSTAL_URL"))
+
+	// Per-tenant geocoding quotas: provider calls are counted in Redis, so quotas need Redis
+	providerCosts, err := services.ParseGeoProviderCosts(cfg.GeoQuota.ProviderCosts)
+	if err != nil {
+		log.Fatalf("Invalid GEO_PROVIDER_COSTS: %v", err)
+	}
+	tenantLimits, err := services.ParseGeoTenantLimits(cfg.GeoQuota.TenantLimits)
+	if err != nil {
+		log.Fatalf("Invalid GEO_TENANT_QUOTAS: %v", err)
+	}
+	geoQuota := services.NewGeoQuotaService(redisClient, services.GeoQuotaConfig{
+		Enabled: cfg.GeoQuota.Enabled,
+		Defaults: services.GeoTenantLimits{
+			DailyLimit:    cfg.GeoQuota.DailyLimit,
+			MonthlyLimit:  cfg.GeoQuota.MonthlyLimit,
+			MonthlyBudget: cfg.GeoQuota.MonthlyBudget,
+		},
+		Tenants:       tenantLimits,
+		ProviderCosts: providerCosts,
+	})
+	if geoQuota.Enabled() {
+		log.Printf("✓ Geocoding quotas enabled (daily: %d, monthly: %d, budget: %.2f, %d tenant overrides)",
+			cfg.GeoQuota.DailyLimit, cfg.GeoQuota.MonthlyLimit, cfg.GeoQuota.MonthlyBudget, len(tenantLimits))
+	} else if cfg.GeoQuota.Enabled {
+		log.Println("WARNING: GEO_QUOTA_ENABLED is set but Redis is unavailable, geocoding quotas disabled")
+	}
 
 	// Create address service with failover chain: Mapbox → Photon → LocationIQ → OpenStreetMap → Google
 	// Google is last (pay-per-use), free providers are prioritized
@@ -119,9 +190,17 @@ func main() {
 		LocationIQAPIKey: cfg.Services.LocationIQAPIKey,
 		PhotonURL:        cfg.Services.PhotonURL,
 		EnableFailover:   true,
+		Quota:            geoQuota,
 	})
 	log.Printf("✓ Address service initialized with failover (Mapbox → Photon → LocationIQ → OpenStreetMap → Google)")
 
+	// Distance matrices are straight-line by default; road distances need the Mapbox Matrix API
+	var routingProvider services.RoutingProvider
+	if cfg.Services.MapboxAccessToken != "" {
+		routingProvider = services.NewMapboxRoutingProvider(cfg.Services.MapboxAccessToken, &http.Client{Timeout: 10 * time.Second})
+	}
+	distanceSvc := services.NewDistanceMatrixService(routingProvider)
+
 	// Initialize GeoTag caching and service
 	var geotagSvc *services.GeoTagService
 	var cachedProvider *services.CachedAddressProvider
@@ -144,6 +223,11 @@ func main() {
 		if enabled := os.Getenv("ADDRESS_CACHE_ENABLED"); enabled == "false" {
 			cacheConfig.Enabled = false
 		}
+		if staleHours := os.Getenv("ADDRESS_CACHE_STALE_WHILE_REVALIDATE_HOURS"); staleHours != "" {
+			if hours, err := strconv.Atoi(staleHours); err == nil && hours >= 0 {
+				cacheConfig.StaleWhileRevalidate = time.Duration(hours) * time.Hour
+			}
+		}
 		if storePlaces := os.Getenv("GEOTAG_STORE_PLACES"); storePlaces == "false" {
 			cacheConfig.StorePlaces = false
 		}
@@ -171,22 +255,33 @@ func main() {
 				cleanupConfig.Interval = time.Duration(hours) * time.Hour
 			}
 		}
+		// Keep expired entries for as long as they may still be served stale
+		cleanupConfig.StaleRetention = cacheConfig.StaleWhileRevalidate
 		cleanupWorker = worker.NewCacheCleanupWorker(addressCacheRepo, cleanupConfig)
 		cleanupWorker.Start()
 
-		log.Printf("✓ GeoTag service initialized with caching (TTL: %v geocode, %v autocomplete)",
-			cacheConfig.GeocodeTTL, cacheConfig.AutocompleteTTL)
+		log.Printf("✓ GeoTag service initialized with caching (TTL: %v geocode, %v autocomplete, stale-while-revalidate: %v)",
+			cacheConfig.GeocodeTTL, cacheConfig.AutocompleteTTL, cacheConfig.StaleWhileRevalidate)
 	} else {
 		// Create GeoTag service without caching
 		geotagSvc = services.NewGeoTagService(nil, nil, addressSvc, nil)
 		log.Println("GeoTag service initialized without caching (database not available)")
 	}
 
+	// Tax regions are resolved from jurisdiction boundaries imported per country; addresses are
+	// geocoded through the GeoTag cache
+	taxRegionSvc := services.NewTaxRegionService(taxJurisdictionRepo, stateRepo, geotagSvc)
+
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(db)
 	locationHandler := handlers.NewLocationHandler(locationSvc, geoSvc)
 	addressHandler := handlers.NewAddressHandler(addressSvc)
+	postalCodeHandler := handlers.NewPostalCodeHandler(postalCodeSvc)
+	taxRegionHandler := handlers.NewTaxRegionHandler(taxRegionSvc)
 	geotagHandler := handler.NewGeoTagHandler(geotagSvc)
+	geoHandler := handlers.NewGeoHandler(distanceSvc, geotagSvc)
+	geoIPHandler := handlers.NewGeoIPHandler(geoSvc)
+	geoUsageHandler := handlers.NewGeoUsageHandler(geoQuota)
 
 	// Initialize NATS events publisher (non-blocking)
 	eventLogger := logrus.New()
@@ -212,7 +307,7 @@ func main() {
 	log.Println("✓ RBAC middleware initialized")
 
 	// Setup router
-	router := setupRouter(healthHandler, locationHandler, addressHandler, geotagHandler, metricsCollector, rbacMiddleware, redisClient)
+	router := setupRouter(healthHandler, locationHandler, addressHandler, postalCodeHandler, taxRegionHandler, geoHandler, geoIPHandler, geoUsageHandler, geotagHandler, metricsCollector, rbacMiddleware, redisClient, geoQuota)
 
 	// Setup server
 	server := &http.Server{
@@ -243,6 +338,11 @@ func main() {
 		cleanupWorker.Stop()
 	}
 
+	// Stop GeoIP database watcher
+	if maxmind := geoSvc.MaxMind(); maxmind != nil {
+		maxmind.Stop()
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
@@ -254,10 +354,16 @@ func setupRouter(
 	healthHandler *handlers.HealthHandler,
 	locationHandler *handlers.LocationHandler,
 	addressHandler *handlers.AddressHandler,
+	postalCodeHandler *handlers.PostalCodeHandler,
+	taxRegionHandler *handlers.TaxRegionHandler,
+	geoHandler *handlers.GeoHandler,
+	geoIPHandler *handlers.GeoIPHandler,
+	geoUsageHandler *handlers.GeoUsageHandler,
 	geotagHandler *handler.GeoTagHandler,
 	metricsCollector *metrics.Metrics,
 	rbacMiddleware *rbac.Middleware,
 	redisClient *redis.Client,
+	geoQuota *services.GeoQuotaService,
 ) *gin.Engine {
 	// Set Gin mode
 	if os.Getenv("GIN_MODE") == "release" {
@@ -290,6 +396,9 @@ func setupRouter(
 	router.GET("/ready", healthHandler.Ready)
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// Endpoints that may call external geocoding providers are metered per tenant (X-Tenant-ID)
+	geoMetered := middleware.GeoQuota(geoQuota)
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
@@ -328,27 +437,30 @@ func setupRouter(
 			timezones.GET("/:timezone", locationHandler.GetTimezone)
 		}
 
+		// Reference data export - public access for edge/storefront caches
+		v1.GET("/reference/export", locationHandler.ExportReferenceData)
+
 		// Address lookup endpoints - public access for address autocomplete
 		address := v1.Group("/address")
 		{
 			// Autocomplete - for address search suggestions
-			address.GET("/autocomplete", addressHandler.Autocomplete)
-			address.POST("/autocomplete", addressHandler.AutocompletePost)
+			address.GET("/autocomplete", geoMetered, addressHandler.Autocomplete)
+			address.POST("/autocomplete", geoMetered, addressHandler.AutocompletePost)
 
 			// Geocoding - convert address to coordinates
-			address.GET("/geocode", addressHandler.Geocode)
-			address.POST("/geocode", addressHandler.GeocodePost)
+			address.GET("/geocode", geoMetered, addressHandler.Geocode)
+			address.POST("/geocode", geoMetered, addressHandler.GeocodePost)
 
 			// Reverse geocoding - convert coordinates to address
-			address.GET("/reverse-geocode", addressHandler.ReverseGeocode)
-			address.POST("/reverse-geocode", addressHandler.ReverseGeocodePost)
+			address.GET("/reverse-geocode", geoMetered, addressHandler.ReverseGeocode)
+			address.POST("/reverse-geocode", geoMetered, addressHandler.ReverseGeocodePost)
 
 			// Place details - get full address details from place ID
-			address.GET("/place-details", addressHandler.GetPlaceDetails)
+			address.GET("/place-details", geoMetered, addressHandler.GetPlaceDetails)
 
 			// Validation - validate and standardize address
-			address.GET("/validate", addressHandler.ValidateAddress)
-			address.POST("/validate", addressHandler.ValidateAddressPost)
+			address.GET("/validate", geoMetered, addressHandler.ValidateAddress)
+			address.POST("/validate", geoMetered, addressHandler.ValidateAddressPost)
 
 			// Manual address entry - fallback when autocomplete doesn't work
 			address.POST("/format-manual", addressHandler.FormatManualAddress)
@@ -357,9 +469,31 @@ func setupRouter(
 			address.POST("/parse", addressHandler.ParseAddress)
 		}
 
+		// Postal codes - public access for checkout validation and city/state autofill
+		postalCodes := v1.Group("/postal-codes")
+		{
+			postalCodes.GET("/validate", postalCodeHandler.ValidatePostalCode)
+			postalCodes.GET("/coverage", postalCodeHandler.GetCoverage)
+			postalCodes.POST("/bulk-lookup", postalCodeHandler.BulkLookup)
+			postalCodes.GET("/:countryCode/:postalCode", postalCodeHandler.GetPostalCode)
+		}
+
+		// Tax regions - public access for pricing and checkout tax calculation; resolving an
+		// address geocodes it, so resolution is metered
+		taxRegions := v1.Group("/tax-regions")
+		{
+			taxRegions.GET("/resolve", geoMetered, taxRegionHandler.ResolveTaxRegion)
+			taxRegions.POST("/resolve", geoMetered, taxRegionHandler.ResolveTaxRegionPost)
+			taxRegions.GET("/coverage", taxRegionHandler.GetCoverage)
+		}
+
+		// Distance matrices and nearest places - public access for storefront pickup point selection
+		v1.POST("/geo/distance-matrix", geoHandler.DistanceMatrix)
+		v1.GET("/places/nearby", geoHandler.NearbyPlaces)
+
 		// GeoTag API - cached geocoding and places database
 		// Public endpoints for geocoding with caching
-		geotagHandler.RegisterRoutes(v1)
+		geotagHandler.RegisterRoutes(v1, geoMetered)
 
 		// Admin endpoints for CRUD operations with RBAC
 		admin := v1.Group("/admin")
@@ -370,6 +504,11 @@ func setupRouter(
 				adminCountries.POST("", rbacMiddleware.RequirePermission(rbac.PermissionLocationsCreate), locationHandler.CreateCountry)
 				adminCountries.PUT("/:countryId", rbacMiddleware.RequirePermission(rbac.PermissionLocationsUpdate), locationHandler.UpdateCountry)
 				adminCountries.DELETE("/:countryId", rbacMiddleware.RequirePermission(rbac.PermissionLocationsDelete), locationHandler.DeleteCountry)
+
+				// Localized names (CLDR-seeded, admin overrides per language)
+				adminCountries.GET("/:countryId/names", rbacMiddleware.RequirePermission(rbac.PermissionLocationsRead), locationHandler.GetCountryNames)
+				adminCountries.PUT("/:countryId/names/:language", rbacMiddleware.RequirePermission(rbac.PermissionLocationsUpdate), locationHandler.SetCountryName)
+				adminCountries.DELETE("/:countryId/names/:language", rbacMiddleware.RequirePermission(rbac.PermissionLocationsUpdate), locationHandler.DeleteCountryName)
 			}
 
 			// Admin - States with RBAC
@@ -378,6 +517,11 @@ func setupRouter(
 				adminStates.POST("", rbacMiddleware.RequirePermission(rbac.PermissionLocationsCreate), locationHandler.CreateState)
 				adminStates.PUT("/:stateId", rbacMiddleware.RequirePermission(rbac.PermissionLocationsUpdate), locationHandler.UpdateState)
 				adminStates.DELETE("/:stateId", rbacMiddleware.RequirePermission(rbac.PermissionLocationsDelete), locationHandler.DeleteState)
+
+				// Localized names
+				adminStates.GET("/:stateId/names", rbacMiddleware.RequirePermission(rbac.PermissionLocationsRead), locationHandler.GetStateNames)
+				adminStates.PUT("/:stateId/names/:language", rbacMiddleware.RequirePermission(rbac.PermissionLocationsUpdate), locationHandler.SetStateName)
+				adminStates.DELETE("/:stateId/names/:language", rbacMiddleware.RequirePermission(rbac.PermissionLocationsUpdate), locationHandler.DeleteStateName)
 			}
 
 			// Admin - Currencies with RBAC
@@ -396,12 +540,35 @@ func setupRouter(
 				adminTimezones.DELETE("/:timezoneId", rbacMiddleware.RequirePermission(rbac.PermissionLocationsDelete), locationHandler.DeleteTimezone)
 			}
 
+			// Admin - Bulk reference data import/export with RBAC
+			adminReference := admin.Group("/reference")
+			{
+				adminReference.GET("/:type/export", rbacMiddleware.RequirePermission(rbac.PermissionLocationsRead), locationHandler.ExportReferenceTable)
+				adminReference.POST("/:type/import", rbacMiddleware.RequirePermission(rbac.PermissionLocationsUpdate), locationHandler.ImportReferenceData)
+			}
+
+			// Admin - Postal code dataset import with RBAC
+			admin.POST("/postal-codes/import", rbacMiddleware.RequirePermission(rbac.PermissionLocationsCreate), postalCodeHandler.ImportPostalCodes)
+
+			// Admin - Tax jurisdiction boundary import with RBAC
+			admin.POST("/tax-regions/import", rbacMiddleware.RequirePermission(rbac.PermissionLocationsCreate), taxRegionHandler.ImportBoundaries)
+
 			// Admin - Cache management with RBAC
 			adminCache := admin.Group("/cache")
 			{
 				adminCache.GET("/stats", rbacMiddleware.RequirePermission(rbac.PermissionLocationsRead), locationHandler.GetCacheStats)
 				adminCache.POST("/cleanup", rbacMiddleware.RequirePermission(rbac.PermissionLocationsUpdate), locationHandler.CleanupCache)
 			}
+
+			// Admin - IP geolocation providers and MaxMind database with RBAC
+			adminGeoIP := admin.Group("/geoip")
+			{
+				adminGeoIP.GET("/providers", rbacMiddleware.RequirePermission(rbac.PermissionLocationsRead), geoIPHandler.GetProviderStatus)
+				adminGeoIP.POST("/database", rbacMiddleware.RequirePermission(rbac.PermissionLocationsUpdate), geoIPHandler.UpdateDatabase)
+			}
+
+			// Admin - Per-tenant geocoding usage against quotas and budgets with RBAC
+			admin.GET("/geo-usage", rbacMiddleware.RequirePermission(rbac.PermissionLocationsRead), geoUsageHandler.GetUsage)
 		}
 	}
 
@@ -478,6 +645,9 @@ func autoMigrate(db *gorm.DB, cfg *config.Config) error {
 		&models.Currency{},
 		&models.Timezone{},
 		&models.LocationCache{},
+		&models.LocalizedName{},
+		&models.PostalCode{},
+		&models.TaxJurisdiction{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
@@ -678,6 +848,7 @@ func initMetrics(db *gorm.DB) *metrics.Metrics {
 	if db != nil {
 		log.Printf("Database metrics: db_connections_open, db_connections_in_use, db_connections_idle, cached_locations")
 		log.Printf("GeoTag metrics: geotag_cache_hits_total, geotag_cache_misses_total, geotag_cache_entries, geotag_places_total, geotag_api_calls_saved_total")
+		log.Printf("GeoTag coalescing metrics: geotag_cache_coalesced_requests_total, geotag_cache_stale_served_total, geotag_cache_revalidations_total")
 	} else {
 		log.Println("Database metrics skipped (running in mock mode)")
 	}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets")
 
@@ -11,6 +12,7 @@ type Config struct {
 	Port     string
 	Database DatabaseConfig
 	Services ServicesConfig
+	GeoQuota GeoQuotaConfig
 	RedisURL string
 }
 
@@ -27,9 +29,15 @@ type DatabaseConfig struct {
 
 // ServicesConfig holds external service configurations
 type ServicesConfig struct {
-	GeoLocationProvider string // "mock", "maxmind", "ipapi"
+	// IP geolocation with failover support
+	// Failover chain: MaxMind (local MMDB) → ipapi → ipinfo
+	GeoLocationProvider string // "mock", "maxmind", "ipapi", "ipinfo", "ip-api", "failover"
+	GeoProviderChain    string // Comma-separated failover order (default: maxmind,ipapi,ipinfo)
+	MaxMindDBPath       string // Local GeoLite2/GeoIP2 City database
+	MaxMindAccountID    string // Needed with the license key to download the database
 	MaxMindLicenseKey   string
 	IPAPIKey            string
+	IPInfoToken         string
 	// Address lookup services with failover support
 	// Failover chain: Mapbox → Photon → LocationIQ → OpenStreetMap → Google
 	AddressProvider   string // "mock", "google", "mapbox", "here", "locationiq", "photon", "openstreetmap", "failover"
@@ -40,6 +48,16 @@ type ServicesConfig struct {
 	PhotonURL         string // Custom Photon URL (default: https://photon.komoot.io)
 }
 
+// GeoQuotaConfig holds per-tenant geocoding quotas; limits of 0 are unlimited
+type GeoQuotaConfig struct {
+	Enabled       bool
+	DailyLimit    int64   // Provider calls per tenant per UTC day
+	MonthlyLimit  int64   // Provider calls per tenant per UTC month
+	MonthlyBudget float64 // Provider spend per tenant per UTC month (USD)
+	ProviderCosts string  // Cost per call, e.g. "google=0.005,mapbox=0.00075"
+	TenantLimits  string  // JSON overrides: {"<tenantId>": {"dailyLimit": 0, "monthlyLimit": 0, "monthlyBudget": 0}}
+}
+
 // Load loads configuration from environment variables
 func Load() *Config {
 	return &Config{
@@ -55,8 +73,12 @@ func Load() *Config {
 		},
 		Services: ServicesConfig{
 			GeoLocationProvider: getEnv("GEO_PROVIDER", "mock"),
-			MaxMindLicenseKey:   getEnv("MAXMIND_LICENSE_KEY", ""),
-			IPAPIKey:            getEnv("IPAPI_KEY", ""),
+			GeoProviderChain:    getEnv("GEO_PROVIDER_CHAIN", "maxmind,ipapi,ipinfo"),
+			MaxMindDBPath:       getEnv("GEOIP_MMDB_PATH", "/data/geoip/GeoLite2-City.mmdb"),
+			MaxMindAccountID:    getEnv("MAXMIND_ACCOUNT_ID", ""),
+			MaxMindLicenseKey:   secrets.GetSecretOrEnv("MAXMIND_LICENSE_KEY_SECRET_NAME", "MAXMIND_LICENSE_KEY", ""),
+			IPAPIKey:            secrets.GetSecretOrEnv("IPAPI_KEY_SECRET_NAME", "IPAPI_KEY", ""),
+			IPInfoToken:         secrets.GetSecretOrEnv("IPINFO_TOKEN_SECRET_NAME", "IPINFO_TOKEN", ""),
 			AddressProvider:     getEnv("ADDRESS_PROVIDER", "failover"),
 			GoogleMapsAPIKey:    secrets.GetSecretOrEnv("GOOGLE_MAPS_API_KEY_SECRET_NAME", "GOOGLE_MAPS_API_KEY", ""),
 			MapboxAccessToken:   secrets.GetSecretOrEnv("MAPBOX_ACCESS_TOKEN_SECRET_NAME", "MAPBOX_ACCESS_TOKEN", ""),
@@ -64,6 +86,14 @@ func Load() *Config {
 			LocationIQAPIKey:    secrets.GetSecretOrEnv("LOCATIONIQ_API_KEY_SECRET_NAME", "LOCATIONIQ_API_KEY", ""),
 			PhotonURL:           getEnv("PHOTON_URL", "https://photon.komoot.io"), // Default to Komoot's public instance
 		},
+		GeoQuota: GeoQuotaConfig{
+			Enabled:       getEnv("GEO_QUOTA_ENABLED", "false") == "true",
+			DailyLimit:    getEnvInt64("GEO_QUOTA_DAILY_LIMIT", 0),
+			MonthlyLimit:  getEnvInt64("GEO_QUOTA_MONTHLY_LIMIT", 0),
+			MonthlyBudget: getEnvFloat("GEO_BUDGET_MONTHLY", 0),
+			ProviderCosts: getEnv("GEO_PROVIDER_COSTS", "google=0.005,mapbox=0.00075"),
+			TenantLimits:  os.Getenv("GEO_TENANT_QUOTAS"),
+		},
 		RedisURL: getEnv("REDIS_URL", "redis://redis.redis-marketplace.svc.cluster.local:6379/0"),
 	}
 }
@@ -75,3 +105,23 @@ func getEnv(key, defaultValue string) string {
 	log.Printf("Using default value for %s: %s", key, defaultValue)
 	return defaultValue
 }
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+		log.Printf("Invalid value for %s: %s", key, value)
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+		log.Printf("Invalid value for %s: %s", key, value)
+	}
+	return defaultValue
+}
@@ -22,14 +22,19 @@ func NewGeoTagHandler(geotagSvc *services.GeoTagService) *GeoTagHandler {
 	}
 }
 
-// RegisterRoutes registers all GeoTag API routes
-func (h *GeoTagHandler) RegisterRoutes(router *gin.RouterGroup) {
+// RegisterRoutes registers all GeoTag API routes; metered handlers (the geocoding quota) run before
+// the endpoints that may call external geocoding providers
+func (h *GeoTagHandler) RegisterRoutes(router *gin.RouterGroup, metered ...gin.HandlerFunc) {
+	withMetering := func(handler gin.HandlerFunc) []gin.HandlerFunc {
+		return append(append([]gin.HandlerFunc{}, metered...), handler)
+	}
+
 	geotag := router.Group("/geotag")
 	{
 		// Core geocoding endpoints
-		geotag.GET("/geocode", h.Geocode)
-		geotag.GET("/reverse", h.ReverseGeocode)
-		geotag.GET("/autocomplete", h.Autocomplete)
+		geotag.GET("/geocode", withMetering(h.Geocode)...)
+		geotag.GET("/reverse", withMetering(h.ReverseGeocode)...)
+		geotag.GET("/autocomplete", withMetering(h.Autocomplete)...)
 
 		// Places endpoints
 		places := geotag.Group("/places")
@@ -37,7 +42,7 @@ func (h *GeoTagHandler) RegisterRoutes(router *gin.RouterGroup) {
 			places.GET("/search", h.SearchPlaces)
 			places.GET("/nearby", h.FindNearby)
 			places.GET("/:id", h.GetPlace)
-			places.POST("/validate", h.ValidateAndStore)
+			places.POST("/validate", withMetering(h.ValidateAndStore)...)
 			places.PUT("/:id/verify", h.SetPlaceVerified)
 			places.DELETE("/:id", h.DeletePlace)
 		}
@@ -45,7 +50,7 @@ func (h *GeoTagHandler) RegisterRoutes(router *gin.RouterGroup) {
 		// Bulk operations
 		bulk := geotag.Group("/bulk")
 		{
-			bulk.POST("/geocode", h.BulkGeocode)
+			bulk.POST("/geocode", withMetering(h.BulkGeocode)...)
 		}
 
 		// Cache management (admin)
@@ -356,6 +361,8 @@ func (h *GeoTagHandler) FindNearby(c *gin.Context) {
 	results := make([]*models.GeoTagResult, len(places))
 	for i, place := range places {
 		results[i] = place.ToGeoTagResult()
+		distance := place.Distance
+		results[i].DistanceKm = &distance
 	}
 
 	c.JSON(http.StatusOK, models.GeoTagAPIResponse{
@@ -545,18 +552,22 @@ func (h *GeoTagHandler) GetCacheStats(c *gin.Context) {
 		return
 	}
 
-	// Add hit rate from metrics
+	// Add hit rate and request coalescing from metrics
 	metrics := h.geotagSvc.GetCacheMetrics()
 	hitRate := 0.0
+	var coalescing *services.CoalescingStats
 	if metrics != nil {
 		hitRate = metrics.GetHitRate()
+		coalescingStats := metrics.GetCoalescingStats()
+		coalescing = &coalescingStats
 	}
 
 	c.JSON(http.StatusOK, models.GeoTagAPIResponse{
 		Success: true,
 		Data: map[string]interface{}{
-			"cache":   stats,
-			"hitRate": hitRate,
+			"cache":      stats,
+			"hitRate":    hitRate,
+			"coalescing": coalescing,
 		},
 	})
 }
@@ -560,7 +560,8 @@ func (h *AddressHandler) GetPlaceDetails(c *gin.Context) {
 
 // ValidateAddress godoc
 // @Summary Validate an address
-// @Description Validate and standardize an address
+// @Description Validate and standardize an address, with a confidence score, component issues,
+// @Description ranked corrections and an accept/suggest/review decision
 // @Tags Address
 // @Accept json
 // @Produce json
@@ -608,7 +609,7 @@ func (h *AddressHandler) ValidateAddress(c *gin.Context) {
 
 // ValidateAddressPost godoc
 // @Summary Validate an address (POST)
-// @Description Validate and standardize an address (POST method)
+// @Description Validate and standardize an address (POST method); see GET for the report fields
 // @Tags Address
 // @Accept json
 // @Produce json
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"location-service/internal/models"
+	"location-service/internal/services"
+)
+
+const (
+	defaultNearbyRadiusKm = 5
+	maxNearbyRadiusKm     = 100
+	defaultNearbyLimit    = 20
+	maxNearbyLimit        = 100
+)
+
+// GeoHandler handles distance and proximity queries
+type GeoHandler struct {
+	distanceSvc *services.DistanceMatrixService
+	geotagSvc   *services.GeoTagService
+}
+
+// NewGeoHandler creates a new geo handler
+func NewGeoHandler(distanceSvc *services.DistanceMatrixService, geotagSvc *services.GeoTagService) *GeoHandler {
+	return &GeoHandler{
+		distanceSvc: distanceSvc,
+		geotagSvc:   geotagSvc,
+	}
+}
+
+// DistanceMatrix godoc
+// @Summary Compute a distance matrix
+// @Description Distance from every origin to every destination (at most 25 each), with each row's nearest destination. Straight-line (haversine) distances are always returned; driving, walking and cycling modes add road distance and duration from the routing provider, falling back to straight-line distances (fallback=true) when routing is unavailable.
+// @Tags Geo
+// @Accept json
+// @Produce json
+// @Param body body models.DistanceMatrixRequest true "Origins, destinations and travel mode"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/geo/distance-matrix [post]
+func (h *GeoHandler) DistanceMatrix(c *gin.Context) {
+	var req models.DistanceMatrixRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST", err)
+		return
+	}
+
+	result, err := h.distanceSvc.Compute(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTravelMode):
+			h.errorResponse(c, http.StatusBadRequest, "Invalid travel mode", "INVALID_MODE", err)
+		case errors.Is(err, services.ErrTooManyMatrixPoints):
+			h.errorResponse(c, http.StatusBadRequest, "Too many points", "TOO_MANY_POINTS", err)
+		default:
+			h.errorResponse(c, http.StatusInternalServerError, "Failed to compute distance matrix", "DISTANCE_MATRIX_FAILED", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Distance matrix computed successfully",
+		"timestamp": time.Now(),
+		"data":      result,
+	})
+}
+
+// NearbyPlaces godoc
+// @Summary Find nearby places
+// @Description Places from the places database within a radius of a point, nearest first, with their distance in kilometers
+// @Tags Geo
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Param radius query number false "Radius in kilometers (default 5, max 100)"
+// @Param limit query int false "Maximum number of places (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/places/nearby [get]
+func (h *GeoHandler) NearbyPlaces(c *gin.Context) {
+	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		h.errorResponse(c, http.StatusBadRequest, "lat must be a latitude between -90 and 90", "INVALID_PARAMETER", nil)
+		return
+	}
+	lng, err := strconv.ParseFloat(c.Query("lng"), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		h.errorResponse(c, http.StatusBadRequest, "lng must be a longitude between -180 and 180", "INVALID_PARAMETER", nil)
+		return
+	}
+	radius := float64(defaultNearbyRadiusKm)
+	if value := c.Query("radius"); value != "" {
+		radius, err = strconv.ParseFloat(value, 64)
+		if err != nil || radius <= 0 || radius > maxNearbyRadiusKm {
+			h.errorResponse(c, http.StatusBadRequest, "radius must be between 0 and 100 km", "INVALID_PARAMETER", nil)
+			return
+		}
+	}
+	limit := defaultNearbyLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxNearbyLimit {
+			h.errorResponse(c, http.StatusBadRequest, "limit must be between 1 and 100", "INVALID_PARAMETER", nil)
+			return
+		}
+	}
+
+	places, err := h.geotagSvc.FindNearby(c.Request.Context(), models.NearbyQuery{
+		Latitude:  lat,
+		Longitude: lng,
+		RadiusKm:  radius,
+		Limit:     limit,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrNoDatabase) {
+			status = http.StatusServiceUnavailable
+		}
+		h.errorResponse(c, status, "Failed to find nearby places", "NEARBY_ERROR", err)
+		return
+	}
+
+	results := make([]*models.GeoTagResult, len(places))
+	for i, place := range places {
+		results[i] = place.ToGeoTagResult()
+		distance := place.Distance
+		results[i].DistanceKm = &distance
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Nearby places retrieved successfully",
+		"timestamp": time.Now(),
+		"data":      results,
+	})
+}
+
+func (h *GeoHandler) errorResponse(c *gin.Context, status int, message, code string, err error) {
+	details := message
+	if err != nil {
+		details = err.Error()
+	}
+	c.JSON(status, gin.H{
+		"success":   false,
+		"message":   message,
+		"timestamp": time.Now(),
+		"error": gin.H{
+			"code":    code,
+			"details": details,
+		},
+	})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"location-service/internal/services"
+)
+
+// GeoUsageHandler reports per-tenant geocoding usage against quotas and budgets
+type GeoUsageHandler struct {
+	quota *services.GeoQuotaService
+}
+
+// NewGeoUsageHandler creates a new geocoding usage handler
+func NewGeoUsageHandler(quota *services.GeoQuotaService) *GeoUsageHandler {
+	return &GeoUsageHandler{
+		quota: quota,
+	}
+}
+
+// GetUsage godoc
+// @Summary Get geocoding usage
+// @Description Get external geocoding provider calls and spend per tenant for a month, against each tenant's quotas and budget. With tenantId, returns that tenant's usage including today; otherwise every tenant with usage in the month, highest spend first.
+// @Tags Admin - Geocoding Usage
+// @Produce json
+// @Param tenantId query string false "Tenant ID"
+// @Param month query string false "Month (YYYY-MM, default current UTC month)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/admin/geo-usage [get]
+func (h *GeoUsageHandler) GetUsage(c *gin.Context) {
+	var data interface{}
+	var err error
+	if tenantID := c.Query("tenantId"); tenantID != "" {
+		data, err = h.quota.Usage(c.Request.Context(), tenantID, c.Query("month"))
+	} else {
+		data, err = h.quota.ListUsage(c.Request.Context(), c.Query("month"))
+	}
+	if err != nil {
+		status, message, code := http.StatusInternalServerError, "Failed to get geocoding usage", "USAGE_FAILED"
+		switch {
+		case errors.Is(err, services.ErrInvalidUsageMonth):
+			status, message, code = http.StatusBadRequest, "Invalid month", "INVALID_MONTH"
+		case errors.Is(err, services.ErrGeoQuotaUnavailable):
+			status, message, code = http.StatusServiceUnavailable, "Geocoding quotas are not enabled", "QUOTAS_NOT_ENABLED"
+		}
+		h.errorResponse(c, status, message, code, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"timestamp": time.Now(),
+		"data":      data,
+	})
+}
+
+func (h *GeoUsageHandler) errorResponse(c *gin.Context, status int, message, code string, err error) {
+	details := message
+	if err != nil {
+		details = err.Error()
+	}
+	c.JSON(status, gin.H{
+		"success":   false,
+		"message":   message,
+		"timestamp": time.Now(),
+		"error": gin.H{
+			"code":    code,
+			"details": details,
+		},
+	})
+}
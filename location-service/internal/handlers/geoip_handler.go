@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"location-service/internal/services"
+)
+
+// GeoIPHandler handles administration of the IP geolocation providers
+type GeoIPHandler struct {
+	geoService *services.GeoLocationService
+}
+
+// NewGeoIPHandler creates a new GeoIP handler
+func NewGeoIPHandler(geoService *services.GeoLocationService) *GeoIPHandler {
+	return &GeoIPHandler{
+		geoService: geoService,
+	}
+}
+
+// GetProviderStatus godoc
+// @Summary Get IP geolocation provider status
+// @Description Get the health of each IP geolocation provider in failover order, and the loaded MaxMind database
+// @Tags Admin - GeoIP
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/geoip/providers [get]
+func (h *GeoIPHandler) GetProviderStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"timestamp": time.Now(),
+		"data": gin.H{
+			"mode":      h.geoService.Provider(),
+			"providers": h.geoService.ProviderStatus(),
+		},
+	})
+}
+
+// UpdateDatabase godoc
+// @Summary Update the MaxMind GeoIP database
+// @Description Install a MaxMind City or Country database (.mmdb or the .tar.gz MaxMind distributes) sent as the request body, without a redeploy. Without a body, the latest GeoLite2-City database is downloaded from MaxMind.
+// @Tags Admin - GeoIP
+// @Accept octet-stream
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/geoip/database [post]
+func (h *GeoIPHandler) UpdateDatabase(c *gin.Context) {
+	maxmind := h.geoService.MaxMind()
+	if maxmind == nil {
+		h.errorResponse(c, http.StatusConflict, "MaxMind provider is not enabled", "PROVIDER_NOT_ENABLED", nil)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxGeoIPDatabaseSize))
+	if err != nil {
+		h.errorResponse(c, http.StatusRequestEntityTooLarge, "GeoIP database is too large", "DATABASE_TOO_LARGE", err)
+		return
+	}
+
+	var info *services.GeoIPDatabaseInfo
+	if len(data) == 0 {
+		info, err = maxmind.Refresh(c.Request.Context())
+	} else {
+		info, err = maxmind.Install(data)
+	}
+	if err != nil {
+		status, message, code := http.StatusInternalServerError, "Failed to update GeoIP database", "DATABASE_UPDATE_FAILED"
+		switch {
+		case errors.Is(err, services.ErrInvalidGeoIPDatabase):
+			status, message, code = http.StatusBadRequest, "Invalid GeoIP database", "INVALID_DATABASE"
+		case errors.Is(err, services.ErrGeoIPDownloadNotConfigured):
+			status, message, code = http.StatusBadRequest, "MaxMind download is not configured", "DOWNLOAD_NOT_CONFIGURED"
+		case len(data) == 0:
+			status, code = http.StatusBadGateway, "DOWNLOAD_FAILED"
+		}
+		h.errorResponse(c, status, message, code, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "GeoIP database updated successfully",
+		"timestamp": time.Now(),
+		"data":      info,
+	})
+}
+
+func (h *GeoIPHandler) errorResponse(c *gin.Context, status int, message, code string, err error) {
+	details := message
+	if err != nil {
+		details = err.Error()
+	}
+	c.JSON(status, gin.H{
+		"success":   false,
+		"message":   message,
+		"timestamp": time.Now(),
+		"error": gin.H{
+			"code":    code,
+			"details": details,
+		},
+	})
+}
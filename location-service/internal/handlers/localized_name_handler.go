@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"location-service/internal/models"
+	"location-service/internal/services"
+)
+
+// SetNameOverrideRequest is the body for overriding a localized name
+type SetNameOverrideRequest struct {
+	Name string `json:"name" binding:"required,max=200"`
+}
+
+// nameLanguages resolves the requested languages from ?lang or Accept-Language
+func (h *LocationHandler) nameLanguages(c *gin.Context) []string {
+	c.Header("Vary", "Accept-Language")
+	languages := services.NameLanguages(c.Query("lang"), c.GetHeader("Accept-Language"))
+	if len(languages) > 0 {
+		c.Header("Content-Language", languages[0])
+	}
+	return languages
+}
+
+// localizeCountries sets localized names; on failure the English names are still returned
+func (h *LocationHandler) localizeCountries(c *gin.Context, countries []models.Country) {
+	if err := h.locationService.LocalizeCountries(c.Request.Context(), countries, h.nameLanguages(c)); err != nil {
+		log.Printf("Warning: Failed to localize country names: %v", err)
+	}
+}
+
+func (h *LocationHandler) localizeCountry(c *gin.Context, country *models.Country) {
+	countries := []models.Country{*country}
+	h.localizeCountries(c, countries)
+	country.LocalizedName = countries[0].LocalizedName
+}
+
+// localizeStates sets localized names; on failure the English names are still returned
+func (h *LocationHandler) localizeStates(c *gin.Context, states []models.State) {
+	if err := h.locationService.LocalizeStates(c.Request.Context(), states, h.nameLanguages(c)); err != nil {
+		log.Printf("Warning: Failed to localize state names: %v", err)
+	}
+}
+
+func (h *LocationHandler) localizeState(c *gin.Context, state *models.State) {
+	states := []models.State{*state}
+	h.localizeStates(c, states)
+	state.LocalizedName = states[0].LocalizedName
+}
+
+// GetCountryNames godoc
+// @Summary Get localized names for a country
+// @Description List CLDR and override names for a country in every stored language
+// @Tags Admin - Localized Names
+// @Produce json
+// @Param countryId path string true "Country ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/countries/{countryId}/names [get]
+func (h *LocationHandler) GetCountryNames(c *gin.Context) {
+	h.getNames(c, models.EntityTypeCountry, c.Param("countryId"))
+}
+
+// SetCountryName godoc
+// @Summary Override a country's localized name
+// @Description Store a translation that takes precedence over the CLDR name
+// @Tags Admin - Localized Names
+// @Accept json
+// @Produce json
+// @Param countryId path string true "Country ID"
+// @Param language path string true "BCP 47 language tag, e.g. de or pt-BR"
+// @Param body body SetNameOverrideRequest true "Localized name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/countries/{countryId}/names/{language} [put]
+func (h *LocationHandler) SetCountryName(c *gin.Context) {
+	h.setName(c, models.EntityTypeCountry, c.Param("countryId"))
+}
+
+// DeleteCountryName godoc
+// @Summary Remove a country name override
+// @Description Delete an override, reverting to the CLDR name
+// @Tags Admin - Localized Names
+// @Produce json
+// @Param countryId path string true "Country ID"
+// @Param language path string true "BCP 47 language tag"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/countries/{countryId}/names/{language} [delete]
+func (h *LocationHandler) DeleteCountryName(c *gin.Context) {
+	h.deleteName(c, models.EntityTypeCountry, c.Param("countryId"))
+}
+
+// GetStateNames godoc
+// @Summary Get localized names for a state
+// @Description List CLDR and override names for a state in every stored language
+// @Tags Admin - Localized Names
+// @Produce json
+// @Param stateId path string true "State ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/states/{stateId}/names [get]
+func (h *LocationHandler) GetStateNames(c *gin.Context) {
+	h.getNames(c, models.EntityTypeState, c.Param("stateId"))
+}
+
+// SetStateName godoc
+// @Summary Override a state's localized name
+// @Description Store a translation that takes precedence over the seeded name
+// @Tags Admin - Localized Names
+// @Accept json
+// @Produce json
+// @Param stateId path string true "State ID"
+// @Param language path string true "BCP 47 language tag, e.g. ja or zh-Hant"
+// @Param body body SetNameOverrideRequest true "Localized name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/states/{stateId}/names/{language} [put]
+func (h *LocationHandler) SetStateName(c *gin.Context) {
+	h.setName(c, models.EntityTypeState, c.Param("stateId"))
+}
+
+// DeleteStateName godoc
+// @Summary Remove a state name override
+// @Description Delete an override, reverting to the seeded name
+// @Tags Admin - Localized Names
+// @Produce json
+// @Param stateId path string true "State ID"
+// @Param language path string true "BCP 47 language tag"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/states/{stateId}/names/{language} [delete]
+func (h *LocationHandler) DeleteStateName(c *gin.Context) {
+	h.deleteName(c, models.EntityTypeState, c.Param("stateId"))
+}
+
+func (h *LocationHandler) getNames(c *gin.Context, entityType, id string) {
+	names, err := h.locationService.GetLocalizedNames(c.Request.Context(), entityType, id)
+	if err != nil {
+		h.localizedNameError(c, err, "Failed to retrieve localized names", "LOCALIZED_NAMES_RETRIEVAL_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Localized names retrieved successfully",
+		"timestamp": time.Now(),
+		"data":      names,
+	})
+}
+
+func (h *LocationHandler) setName(c *gin.Context, entityType, id string) {
+	var req SetNameOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		details := "name is required"
+		if err != nil {
+			details = err.Error()
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":   false,
+			"message":   "Invalid request body",
+			"timestamp": time.Now(),
+			"error": gin.H{
+				"code":    "INVALID_REQUEST",
+				"details": details,
+			},
+		})
+		return
+	}
+
+	name, err := h.locationService.SetNameOverride(c.Request.Context(), entityType, id, c.Param("language"), req.Name)
+	if err != nil {
+		h.localizedNameError(c, err, "Failed to set localized name", "LOCALIZED_NAME_UPDATE_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Localized name updated successfully",
+		"timestamp": time.Now(),
+		"data":      name,
+	})
+}
+
+func (h *LocationHandler) deleteName(c *gin.Context, entityType, id string) {
+	if err := h.locationService.DeleteNameOverride(c.Request.Context(), entityType, id, c.Param("language")); err != nil {
+		h.localizedNameError(c, err, "Failed to delete localized name", "LOCALIZED_NAME_DELETE_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Localized name override deleted successfully",
+		"timestamp": time.Now(),
+	})
+}
+
+// localizedNameError maps localized name errors to responses
+func (h *LocationHandler) localizedNameError(c *gin.Context, err error, message, code string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidLanguage):
+		status, message, code = http.StatusBadRequest, "Invalid language tag", "INVALID_LANGUAGE"
+	case errors.Is(err, services.ErrLocationNotFound):
+		status, message, code = http.StatusNotFound, "Location not found", "LOCATION_NOT_FOUND"
+	case errors.Is(err, services.ErrNameOverrideNotFound):
+		status, message, code = http.StatusNotFound, "Localized name override not found", "LOCALIZED_NAME_NOT_FOUND"
+	}
+
+	c.JSON(status, gin.H{
+		"success":   false,
+		"message":   message,
+		"timestamp": time.Now(),
+		"error": gin.H{
+			"code":    code,
+			"details": err.Error(),
+		},
+	})
+}
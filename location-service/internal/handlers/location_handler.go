@@ -77,6 +77,7 @@ func (h *LocationHandler) DetectLocation(c *gin.Context) {
 // @Param region query string false "Filter by region"
 // @Param limit query int false "Limit number of results" default(50)
 // @Param offset query int false "Offset for pagination" default(0)
+// @Param lang query string false "Language for localized_name (overrides Accept-Language)"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/countries [get]
 func (h *LocationHandler) GetCountries(c *gin.Context) {
@@ -110,6 +111,7 @@ func (h *LocationHandler) GetCountries(c *gin.Context) {
 		})
 		return
 	}
+	h.localizeCountries(c, countries)
 
 	hasNext := int64(offset+limit) < total
 	hasPrevious := offset > 0
@@ -136,6 +138,7 @@ func (h *LocationHandler) GetCountries(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param countryId path string true "Country ID"
+// @Param lang query string false "Language for localized_name (overrides Accept-Language)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /api/v1/countries/{countryId} [get]
@@ -155,6 +158,7 @@ func (h *LocationHandler) GetCountry(c *gin.Context) {
 		})
 		return
 	}
+	h.localizeCountry(c, country)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":   true,
@@ -172,6 +176,7 @@ func (h *LocationHandler) GetCountry(c *gin.Context) {
 // @Produce json
 // @Param countryId path string true "Country ID"
 // @Param search query string false "Search states by name or code"
+// @Param lang query string false "Language for localized_name (overrides Accept-Language)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /api/v1/countries/{countryId}/states [get]
@@ -192,6 +197,7 @@ func (h *LocationHandler) GetStates(c *gin.Context) {
 		})
 		return
 	}
+	h.localizeStates(c, states)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":   true,
@@ -211,6 +217,7 @@ func (h *LocationHandler) GetStates(c *gin.Context) {
 // @Param country_id query string false "Filter by country ID"
 // @Param limit query int false "Limit number of results" default(50)
 // @Param offset query int false "Offset for pagination" default(0)
+// @Param lang query string false "Language for localized_name (overrides Accept-Language)"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/states [get]
 func (h *LocationHandler) GetAllStates(c *gin.Context) {
@@ -244,6 +251,7 @@ func (h *LocationHandler) GetAllStates(c *gin.Context) {
 		})
 		return
 	}
+	h.localizeStates(c, states)
 
 	hasNext := int64(offset+limit) < total
 	hasPrevious := offset > 0
@@ -270,6 +278,7 @@ func (h *LocationHandler) GetAllStates(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param stateId path string true "State ID"
+// @Param lang query string false "Language for localized_name (overrides Accept-Language)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /api/v1/states/{stateId} [get]
@@ -289,6 +298,7 @@ func (h *LocationHandler) GetState(c *gin.Context) {
 		})
 		return
 	}
+	h.localizeState(c, state)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":   true,
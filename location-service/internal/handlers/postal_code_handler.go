@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"location-service/internal/services"
+)
+
+// PostalCodeHandler handles postal code validation and lookup requests
+type PostalCodeHandler struct {
+	postalCodeService *services.PostalCodeService
+}
+
+// NewPostalCodeHandler creates a new postal code handler
+func NewPostalCodeHandler(postalCodeService *services.PostalCodeService) *PostalCodeHandler {
+	return &PostalCodeHandler{
+		postalCodeService: postalCodeService,
+	}
+}
+
+// BulkPostalCodeLookupRequest is the body of a bulk postal code lookup
+type BulkPostalCodeLookupRequest struct {
+	PostalCodes []services.PostalCodeQuery `json:"postal_codes" binding:"required,min=1,dive"`
+}
+
+// ValidatePostalCode godoc
+// @Summary Validate a postal code
+// @Description Check a postal code against the country's format and the imported GeoNames dataset, resolving its city and state. Countries without a dataset are checked by format only (verified=false).
+// @Tags Postal Codes
+// @Produce json
+// @Param country query string true "ISO 3166-1 alpha-2 country code"
+// @Param postal_code query string true "Postal code"
+// @Param state query string false "State ID (US-CA), code or name the postal code should be in"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/postal-codes/validate [get]
+func (h *PostalCodeHandler) ValidatePostalCode(c *gin.Context) {
+	query := services.PostalCodeQuery{
+		Country:    c.Query("country"),
+		PostalCode: c.Query("postal_code"),
+		State:      c.Query("state"),
+	}
+	if query.Country == "" || query.PostalCode == "" {
+		h.errorResponse(c, http.StatusBadRequest, "country and postal_code are required", "INVALID_REQUEST", nil)
+		return
+	}
+
+	result, err := h.postalCodeService.Lookup(c.Request.Context(), query)
+	if err != nil {
+		h.serviceError(c, err, "Failed to validate postal code", "POSTAL_CODE_VALIDATION_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Postal code validated successfully",
+		"timestamp": time.Now(),
+		"data":      result,
+	})
+}
+
+// GetPostalCode godoc
+// @Summary Look up a postal code
+// @Description Resolve a postal code to the city and state it belongs to, with every place it serves
+// @Tags Postal Codes
+// @Produce json
+// @Param countryCode path string true "ISO 3166-1 alpha-2 country code"
+// @Param postalCode path string true "Postal code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/postal-codes/{countryCode}/{postalCode} [get]
+func (h *PostalCodeHandler) GetPostalCode(c *gin.Context) {
+	result, err := h.postalCodeService.Lookup(c.Request.Context(), services.PostalCodeQuery{
+		Country:    c.Param("countryCode"),
+		PostalCode: c.Param("postalCode"),
+	})
+	if err != nil {
+		h.serviceError(c, err, "Failed to look up postal code", "POSTAL_CODE_LOOKUP_FAILED")
+		return
+	}
+	if !result.Found {
+		err := fmt.Errorf("%w: %s %s", services.ErrPostalCodeNotFound, result.CountryID, result.Standardized)
+		if !result.FormatValid {
+			h.errorResponse(c, http.StatusBadRequest, "Invalid postal code format", "INVALID_POSTAL_CODE", err)
+			return
+		}
+		h.errorResponse(c, http.StatusNotFound, "Postal code not found", "POSTAL_CODE_NOT_FOUND", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Postal code retrieved successfully",
+		"timestamp": time.Now(),
+		"data":      result,
+	})
+}
+
+// BulkLookup godoc
+// @Summary Look up postal codes in bulk
+// @Description Validate and resolve up to 100 postal codes, possibly of different countries; results are returned in request order
+// @Tags Postal Codes
+// @Accept json
+// @Produce json
+// @Param body body BulkPostalCodeLookupRequest true "Postal codes"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/postal-codes/bulk-lookup [post]
+func (h *PostalCodeHandler) BulkLookup(c *gin.Context) {
+	var req BulkPostalCodeLookupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST", err)
+		return
+	}
+	if len(req.PostalCodes) > services.MaxPostalCodeBulkLookup {
+		h.errorResponse(c, http.StatusBadRequest, fmt.Sprintf("At most %d postal codes per request", services.MaxPostalCodeBulkLookup), "TOO_MANY_POSTAL_CODES", nil)
+		return
+	}
+
+	results, err := h.postalCodeService.BulkLookup(c.Request.Context(), req.PostalCodes)
+	if err != nil {
+		h.serviceError(c, err, "Failed to look up postal codes", "POSTAL_CODE_LOOKUP_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Postal codes retrieved successfully",
+		"timestamp": time.Now(),
+		"data":      results,
+	})
+}
+
+// GetCoverage godoc
+// @Summary Get postal code dataset coverage
+// @Description List the countries with an imported postal code dataset; other countries are validated by format only
+// @Tags Postal Codes
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/postal-codes/coverage [get]
+func (h *PostalCodeHandler) GetCoverage(c *gin.Context) {
+	coverage, err := h.postalCodeService.Coverage(c.Request.Context())
+	if err != nil {
+		h.serviceError(c, err, "Failed to retrieve postal code coverage", "POSTAL_CODE_COVERAGE_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Postal code coverage retrieved successfully",
+		"timestamp": time.Now(),
+		"data":      coverage,
+	})
+}
+
+// ImportPostalCodes godoc
+// @Summary Import postal codes
+// @Description Import a GeoNames postal code file (tab-separated text or zip) sent as the request body, replacing the postal codes of each country in it. Without a body, the country's dataset is downloaded from GeoNames.
+// @Tags Admin - Postal Codes
+// @Accept plain
+// @Produce json
+// @Param country query string false "ISO 3166-1 alpha-2 country code; required without a body, otherwise limits the import to that country"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/postal-codes/import [post]
+func (h *PostalCodeHandler) ImportPostalCodes(c *gin.Context) {
+	country := c.Query("country")
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxPostalCodeDatasetSize))
+	if err != nil {
+		h.errorResponse(c, http.StatusRequestEntityTooLarge, "Postal code dataset is too large", "DATASET_TOO_LARGE", err)
+		return
+	}
+
+	var result *services.PostalCodeImportResult
+	if len(data) == 0 {
+		if country == "" {
+			h.errorResponse(c, http.StatusBadRequest, "country is required when no dataset is uploaded", "INVALID_REQUEST", nil)
+			return
+		}
+		result, err = h.postalCodeService.ImportFromGeoNames(c.Request.Context(), country)
+	} else {
+		result, err = h.postalCodeService.Import(c.Request.Context(), country, data)
+	}
+	if err != nil {
+		h.serviceError(c, err, "Failed to import postal codes", "POSTAL_CODE_IMPORT_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Postal codes imported successfully",
+		"timestamp": time.Now(),
+		"data":      result,
+	})
+}
+
+// serviceError maps postal code service errors to responses
+func (h *PostalCodeHandler) serviceError(c *gin.Context, err error, message, code string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidCountryCode):
+		status, message, code = http.StatusBadRequest, "Invalid country code", "INVALID_COUNTRY_CODE"
+	case errors.Is(err, services.ErrInvalidPostalCodeDataset):
+		status, message, code = http.StatusBadRequest, "Invalid postal code dataset", "INVALID_DATASET"
+	case errors.Is(err, services.ErrPostalCodeDatasetNotFound):
+		status, message, code = http.StatusNotFound, "Postal code dataset not found", "DATASET_NOT_FOUND"
+	case errors.Is(err, services.ErrNoDatabase):
+		status = http.StatusServiceUnavailable
+	}
+	h.errorResponse(c, status, message, code, err)
+}
+
+func (h *PostalCodeHandler) errorResponse(c *gin.Context, status int, message, code string, err error) {
+	details := message
+	if err != nil {
+		details = err.Error()
+	}
+	c.JSON(status, gin.H{
+		"success":   false,
+		"message":   message,
+		"timestamp": time.Now(),
+		"error": gin.H{
+			"code":    code,
+			"details": details,
+		},
+	})
+}
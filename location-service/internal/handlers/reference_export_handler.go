@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"location-service/internal/services"
+)
+
+// ExportReferenceData godoc
+// @Summary Export reference data
+// @Description Versioned bundle of all countries, states, currencies and timezones for edge and storefront caches.
+// @Description The ETag is the reference data version; send it as If-None-Match to get 304 until the data changes.
+// @Description The bundle is gzip-encoded when the client accepts gzip.
+// @Tags Reference Data
+// @Produce json
+// @Param If-None-Match header string false "ETag of the cached bundle"
+// @Success 200 {object} models.ReferenceData
+// @Success 304 "Bundle unchanged"
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/reference/export [get]
+func (h *LocationHandler) ExportReferenceData(c *gin.Context) {
+	export, err := h.locationService.ExportReferenceData(c.Request.Context())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrNoDatabase) {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{
+			"success":   false,
+			"message":   "Failed to export reference data",
+			"timestamp": time.Now(),
+			"error": gin.H{
+				"code":    "REFERENCE_EXPORT_FAILED",
+				"details": err.Error(),
+			},
+		})
+		return
+	}
+
+	etag := `"` + export.Version + `"`
+	c.Header("ETag", etag)
+	c.Header("X-Reference-Data-Version", export.Version)
+	c.Header("Last-Modified", export.UpdatedAt.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, no-cache")
+	c.Header("Vary", "Accept-Encoding")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	if acceptsGzip(c.GetHeader("Accept-Encoding")) {
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json; charset=utf-8", export.Gzip)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", export.JSON)
+}
+
+// etagMatches reports whether an If-None-Match header matches the ETag (weak comparison)
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.TrimSpace(coding)
+		if !strings.EqualFold(coding, "gzip") && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
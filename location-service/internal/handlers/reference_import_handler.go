@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"location-service/internal/services"
+)
+
+// ExportReferenceTable godoc
+// @Summary Export reference data for editing
+// @Description Export every country, state, currency or timezone, including inactive ones, as CSV (one column per importable field) or JSON. The file can be edited and sent back to the import endpoint.
+// @Tags Admin - Reference Data
+// @Produce text/csv
+// @Produce json
+// @Param type path string true "countries, states, currencies or timezones"
+// @Param format query string false "csv (default) or json"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/admin/reference/{type}/export [get]
+func (h *LocationHandler) ExportReferenceTable(c *gin.Context) {
+	dataType, err := services.ParseReferenceDataType(c.Param("type"))
+	if err != nil {
+		h.referenceImportError(c, err, "Failed to export reference data", "REFERENCE_EXPORT_FAILED", nil)
+		return
+	}
+	format := strings.ToLower(c.DefaultQuery("format", services.ReferenceFormatCSV))
+
+	data, err := h.locationService.ExportReferenceTable(c.Request.Context(), dataType, format)
+	if err != nil {
+		h.referenceImportError(c, err, "Failed to export reference data", "REFERENCE_EXPORT_FAILED", nil)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == services.ReferenceFormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, dataType, format))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ImportReferenceData godoc
+// @Summary Import reference data
+// @Description Create or update countries, states, currencies or timezones from a CSV file with a header row of field names, or a JSON array. Records are matched by ID (currencies by code) and only the fields present change.
+// @Description With dry_run=true the diff is returned without changing anything. Otherwise all changes are applied in one transaction, and nothing is applied when any record is invalid.
+// @Tags Admin - Reference Data
+// @Accept text/csv
+// @Accept json
+// @Produce json
+// @Param type path string true "countries, states, currencies or timezones"
+// @Param format query string false "csv or json (default: from Content-Type)"
+// @Param dry_run query bool false "Preview the diff without applying it"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Router /api/v1/admin/reference/{type}/import [post]
+func (h *LocationHandler) ImportReferenceData(c *gin.Context) {
+	dataType, err := services.ParseReferenceDataType(c.Param("type"))
+	if err != nil {
+		h.referenceImportError(c, err, "Failed to import reference data", "REFERENCE_IMPORT_FAILED", nil)
+		return
+	}
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = services.ReferenceFormatJSON
+		if strings.Contains(c.ContentType(), "csv") {
+			format = services.ReferenceFormatCSV
+		}
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxReferenceImportSize))
+	if err != nil {
+		h.referenceImportError(c, err, "Reference data file is too large", "FILE_TOO_LARGE", nil)
+		return
+	}
+	if len(data) == 0 {
+		h.referenceImportError(c, services.ErrInvalidReferenceFile, "Failed to import reference data", "REFERENCE_IMPORT_FAILED", nil)
+		return
+	}
+
+	result, err := h.locationService.ImportReferenceData(c.Request.Context(), dataType, format, data, dryRun)
+	if err != nil {
+		h.referenceImportError(c, err, "Failed to import reference data", "REFERENCE_IMPORT_FAILED", result)
+		return
+	}
+
+	message := "Reference data imported successfully"
+	if dryRun {
+		message = "Reference data import previewed"
+	} else if !result.Applied {
+		message = "Reference data is already up to date"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   message,
+		"timestamp": time.Now(),
+		"data":      result,
+	})
+}
+
+// referenceImportError maps reference data import and export errors to responses; the import
+// result is included so validation errors can be fixed
+func (h *LocationHandler) referenceImportError(c *gin.Context, err error, message, code string, result *services.ReferenceImportResult) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidReferenceDataType):
+		status, message, code = http.StatusBadRequest, "Invalid reference data type", "INVALID_REFERENCE_TYPE"
+	case errors.Is(err, services.ErrInvalidReferenceFormat):
+		status, message, code = http.StatusBadRequest, "Invalid reference data format", "INVALID_FORMAT"
+	case errors.Is(err, services.ErrInvalidReferenceFile):
+		status, message, code = http.StatusBadRequest, "Invalid reference data file", "INVALID_FILE"
+	case errors.Is(err, services.ErrReferenceImportInvalid):
+		status, message, code = http.StatusUnprocessableEntity, "Reference data import has validation errors", "VALIDATION_FAILED"
+	case errors.Is(err, services.ErrNoDatabase):
+		status = http.StatusServiceUnavailable
+	case code == "FILE_TOO_LARGE":
+		status = http.StatusRequestEntityTooLarge
+	}
+
+	response := gin.H{
+		"success":   false,
+		"message":   message,
+		"timestamp": time.Now(),
+		"error": gin.H{
+			"code":    code,
+			"details": err.Error(),
+		},
+	}
+	if result != nil {
+		response["data"] = result
+	}
+	c.JSON(status, response)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"location-service/internal/services"
+)
+
+// TaxRegionHandler handles tax region resolution and boundary data imports
+type TaxRegionHandler struct {
+	taxRegionService *services.TaxRegionService
+}
+
+// NewTaxRegionHandler creates a new tax region handler
+func NewTaxRegionHandler(taxRegionService *services.TaxRegionService) *TaxRegionHandler {
+	return &TaxRegionHandler{
+		taxRegionService: taxRegionService,
+	}
+}
+
+// ResolveTaxRegion godoc
+// @Summary Resolve a location to its tax jurisdictions
+// @Description Map coordinates, or an address (geocoded first), to the country, state, county, city and special districts it is in, from imported boundary data. region_key identifies the combination of jurisdictions. Countries without boundary data resolve to the country and state from geocoding (verified=false).
+// @Tags Tax Regions
+// @Produce json
+// @Param lat query number false "Latitude (with lng)"
+// @Param lng query number false "Longitude (with lat)"
+// @Param address query string false "Address to geocode when no coordinates are given"
+// @Param country query string false "ISO 3166-1 alpha-2 country code to match jurisdictions of"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Router /api/v1/tax-regions/resolve [get]
+func (h *TaxRegionHandler) ResolveTaxRegion(c *gin.Context) {
+	query := services.TaxRegionQuery{
+		Address: c.Query("address"),
+		Country: c.Query("country"),
+	}
+	if c.Query("lat") != "" || c.Query("lng") != "" {
+		lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+		lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+		if latErr != nil || lngErr != nil {
+			h.errorResponse(c, http.StatusBadRequest, "lat and lng must both be numbers", "INVALID_PARAMETER", nil)
+			return
+		}
+		query.Latitude, query.Longitude = &lat, &lng
+	}
+	h.resolve(c, query)
+}
+
+// ResolveTaxRegionPost godoc
+// @Summary Resolve a location to its tax jurisdictions
+// @Description Same as GET /api/v1/tax-regions/resolve with a JSON body, e.g. the location of a validated address
+// @Tags Tax Regions
+// @Accept json
+// @Produce json
+// @Param body body services.TaxRegionQuery true "Coordinates or address"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Router /api/v1/tax-regions/resolve [post]
+func (h *TaxRegionHandler) ResolveTaxRegionPost(c *gin.Context) {
+	var query services.TaxRegionQuery
+	if err := c.ShouldBindJSON(&query); err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST", err)
+		return
+	}
+	if (query.Latitude == nil) != (query.Longitude == nil) {
+		h.errorResponse(c, http.StatusBadRequest, "latitude and longitude must be given together", "INVALID_REQUEST", nil)
+		return
+	}
+	h.resolve(c, query)
+}
+
+func (h *TaxRegionHandler) resolve(c *gin.Context, query services.TaxRegionQuery) {
+	region, err := h.taxRegionService.Resolve(c.Request.Context(), query)
+	if err != nil {
+		h.serviceError(c, err, "Failed to resolve tax region", "TAX_REGION_RESOLUTION_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Tax region resolved successfully",
+		"timestamp": time.Now(),
+		"data":      region,
+	})
+}
+
+// GetCoverage godoc
+// @Summary Get tax boundary coverage
+// @Description List the countries with imported tax jurisdiction boundaries, with the number of jurisdictions of each type
+// @Tags Tax Regions
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/tax-regions/coverage [get]
+func (h *TaxRegionHandler) GetCoverage(c *gin.Context) {
+	coverage, err := h.taxRegionService.Coverage(c.Request.Context())
+	if err != nil {
+		h.serviceError(c, err, "Failed to retrieve tax boundary coverage", "TAX_BOUNDARY_COVERAGE_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Tax boundary coverage retrieved successfully",
+		"timestamp": time.Now(),
+		"data":      coverage,
+	})
+}
+
+// ImportBoundaries godoc
+// @Summary Import tax jurisdiction boundaries
+// @Description Import a GeoJSON FeatureCollection sent as the request body, replacing the jurisdictions of each country in it. Features need a Polygon or MultiPolygon geometry and type (state, county, city or special), code and name properties, plus country (unless given as a parameter) and optionally state.
+// @Tags Admin - Tax Regions
+// @Accept json
+// @Produce json
+// @Param country query string false "ISO 3166-1 alpha-2 country code; the default country of features, and limits the import to that country"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Router /api/v1/admin/tax-regions/import [post]
+func (h *TaxRegionHandler) ImportBoundaries(c *gin.Context) {
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxTaxBoundaryDatasetSize))
+	if err != nil {
+		h.errorResponse(c, http.StatusRequestEntityTooLarge, "Tax boundary dataset is too large", "DATASET_TOO_LARGE", err)
+		return
+	}
+	if len(data) == 0 {
+		h.errorResponse(c, http.StatusBadRequest, "A GeoJSON FeatureCollection body is required", "INVALID_REQUEST", nil)
+		return
+	}
+
+	result, err := h.taxRegionService.Import(c.Request.Context(), c.Query("country"), data)
+	if err != nil {
+		h.serviceError(c, err, "Failed to import tax boundaries", "TAX_BOUNDARY_IMPORT_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Tax boundaries imported successfully",
+		"timestamp": time.Now(),
+		"data":      result,
+	})
+}
+
+// serviceError maps tax region service errors to responses
+func (h *TaxRegionHandler) serviceError(c *gin.Context, err error, message, code string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidCountryCode):
+		status, message, code = http.StatusBadRequest, "Invalid country code", "INVALID_COUNTRY_CODE"
+	case errors.Is(err, services.ErrTaxRegionLocationRequired):
+		status, message, code = http.StatusBadRequest, "Coordinates or an address are required", "INVALID_REQUEST"
+	case errors.Is(err, services.ErrInvalidCoordinates):
+		status, message, code = http.StatusBadRequest, "Invalid coordinates", "INVALID_COORDINATES"
+	case errors.Is(err, services.ErrAddressNotGeocoded):
+		status, message, code = http.StatusUnprocessableEntity, "Address could not be geocoded", "ADDRESS_NOT_FOUND"
+	case errors.Is(err, services.ErrInvalidTaxBoundaryDataset):
+		status, message, code = http.StatusBadRequest, "Invalid tax boundary dataset", "INVALID_DATASET"
+	case errors.Is(err, services.ErrNoDatabase):
+		status = http.StatusServiceUnavailable
+	}
+	h.errorResponse(c, status, message, code, err)
+}
+
+func (h *TaxRegionHandler) errorResponse(c *gin.Context, status int, message, code string, err error) {
+	details := message
+	if err != nil {
+		details = err.Error()
+	}
+	c.JSON(status, gin.H{
+		"success":   false,
+		"message":   message,
+		"timestamp": time.Now(),
+		"error": gin.H{
+			"code":    code,
+			"details": details,
+		},
+	})
+}
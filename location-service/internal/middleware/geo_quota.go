@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"location-service/internal/services"
+)
+
+// GeoQuota meters geocoding endpoints per tenant. The tenant comes from the X-Tenant-ID header (or a
+// "tenant_id" set by earlier middleware); requests without one are not metered. Tenants over their
+// daily/monthly call quota or monthly provider budget get 429 with Retry-After until the period resets.
+func GeoQuota(quota *services.GeoQuotaService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.GetString("tenant_id")
+		if tenantID == "" {
+			tenantID = c.GetHeader("X-Tenant-ID")
+		}
+		if !quota.Enabled() || tenantID == "" {
+			c.Next()
+			return
+		}
+
+		status, err := quota.Check(c.Request.Context(), tenantID)
+		if err != nil {
+			code, message := "GEO_QUOTA_EXCEEDED", "Geocoding quota exceeded"
+			if errors.Is(err, services.ErrGeoBudgetExceeded) {
+				code, message = "GEO_BUDGET_EXCEEDED", "Geocoding provider budget exceeded"
+			}
+
+			retryAfter := int(time.Until(status.ResetAt).Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"success":   false,
+				"message":   message,
+				"timestamp": time.Now(),
+				"error": gin.H{
+					"code":    code,
+					"details": status,
+				},
+			})
+			return
+		}
+
+		c.Request = c.Request.WithContext(services.WithGeoTenant(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
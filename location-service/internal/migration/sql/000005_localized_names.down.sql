@@ -0,0 +1,7 @@
+-- Localized Country/State Names: Rollback
+-- Migration: 000005_localized_names.down.sql
+
+SET search_path TO location, public;
+
+DROP INDEX IF EXISTS idx_localized_names_language;
+DROP TABLE IF EXISTS localized_names;
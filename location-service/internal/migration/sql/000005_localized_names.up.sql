@@ -0,0 +1,26 @@
+-- Localized Country/State Names
+-- Migration: 000005_localized_names.up.sql
+
+SET search_path TO location, public;
+
+-- ============================================================================
+-- Table: localized_names
+-- Country and state names per language. CLDR names are seeded at startup;
+-- admin overrides are stored alongside them and take precedence.
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS localized_names (
+    entity_type VARCHAR(10) NOT NULL,     -- 'country', 'state'
+    entity_id VARCHAR(10) NOT NULL,       -- Country ID (US) or state ID (US-CA)
+    language VARCHAR(20) NOT NULL,        -- BCP 47 tag: de, pt-BR, zh-Hant
+    source VARCHAR(10) NOT NULL,          -- 'cldr', 'override'
+    name VARCHAR(200) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (entity_type, entity_id, language, source)
+);
+
+-- Lookups fetch one language for many entities of a type
+CREATE INDEX IF NOT EXISTS idx_localized_names_language ON localized_names(entity_type, language);
+
+COMMENT ON TABLE localized_names IS 'Country and state names per language (CLDR-seeded, admin-overridable)';
+COMMENT ON COLUMN localized_names.source IS 'cldr: seeded from CLDR; override: set by an admin, preferred over cldr';
@@ -0,0 +1,7 @@
+-- Postal Codes: Rollback
+-- Migration: 000006_postal_codes.down.sql
+
+SET search_path TO location, public;
+
+DROP INDEX IF EXISTS idx_postal_codes_lookup;
+DROP TABLE IF EXISTS postal_codes;
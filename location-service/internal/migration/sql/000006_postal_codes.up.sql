@@ -0,0 +1,31 @@
+-- Postal Codes
+-- Migration: 000006_postal_codes.up.sql
+
+SET search_path TO location, public;
+
+-- ============================================================================
+-- Table: postal_codes
+-- Places served by each postal code, imported per country from the GeoNames
+-- postal code dataset. An import replaces every row of the country.
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS postal_codes (
+    id BIGSERIAL PRIMARY KEY,
+    country_id VARCHAR(2) NOT NULL,       -- ISO 3166-1 alpha-2
+    postal_code VARCHAR(20) NOT NULL,     -- Standardized: uppercase, canonical spacing
+    place_name VARCHAR(180) NOT NULL,
+    state_name VARCHAR(100),
+    state_code VARCHAR(20),               -- Admin code from the dataset
+    state_id VARCHAR(10),                 -- Matched state (US-CA), if any
+    county VARCHAR(100),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    accuracy INT DEFAULT 0,               -- GeoNames: 1 estimated, 4 geonameid, 6 centroid of addresses
+    source VARCHAR(20) NOT NULL DEFAULT 'geonames',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Lookups and imports are always by country, then postal code
+CREATE INDEX IF NOT EXISTS idx_postal_codes_lookup ON postal_codes(country_id, postal_code);
+
+COMMENT ON TABLE postal_codes IS 'Places per postal code (GeoNames), used for postal code validation and city/state resolution';
+COMMENT ON COLUMN postal_codes.postal_code IS 'For some countries (CA, GB) the dataset holds only the outward part of the code';
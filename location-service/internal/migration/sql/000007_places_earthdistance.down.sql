@@ -0,0 +1,7 @@
+-- Places: Nearby Search Index Rollback
+-- Migration: 000007_places_earthdistance.down.sql
+
+SET search_path TO location, public;
+
+-- The extensions are left installed: other schemas in the database may use them
+DROP INDEX IF EXISTS idx_places_earth;
@@ -0,0 +1,16 @@
+-- Places: Nearby Search Index
+-- Migration: 000007_places_earthdistance.up.sql
+
+SET search_path TO location, public;
+
+-- ============================================================================
+-- earthdistance (on top of cube) provides ll_to_earth/earth_box, which let a
+-- GiST index answer radius queries over places without scanning the table.
+-- The extensions live in public so every schema on the search path sees them.
+-- ============================================================================
+CREATE EXTENSION IF NOT EXISTS cube WITH SCHEMA public;
+CREATE EXTENSION IF NOT EXISTS earthdistance WITH SCHEMA public;
+
+CREATE INDEX IF NOT EXISTS idx_places_earth
+    ON places USING gist (ll_to_earth(latitude::float8, longitude::float8))
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,8 @@
+-- Tax Jurisdictions: Rollback
+-- Migration: 000008_tax_jurisdictions.down.sql
+
+SET search_path TO location, public;
+
+DROP INDEX IF EXISTS idx_tax_jurisdictions_bbox;
+DROP INDEX IF EXISTS idx_tax_jurisdictions_country;
+DROP TABLE IF EXISTS tax_jurisdictions;
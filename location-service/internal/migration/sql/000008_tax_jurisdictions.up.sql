@@ -0,0 +1,37 @@
+-- Tax Jurisdictions
+-- Migration: 000008_tax_jurisdictions.up.sql
+
+SET search_path TO location, public;
+
+-- ============================================================================
+-- Table: tax_jurisdictions
+-- Tax jurisdiction boundaries (state, county, city, special districts),
+-- imported per country from GeoJSON. An import replaces every row of the
+-- country. Points are matched against the bounding box in SQL, then against
+-- the polygons in the service.
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS tax_jurisdictions (
+    id BIGSERIAL PRIMARY KEY,
+    country_id VARCHAR(2) NOT NULL,       -- ISO 3166-1 alpha-2
+    state_id VARCHAR(10),                 -- Matched state (US-CA), if any
+    type VARCHAR(20) NOT NULL,            -- country, state, county, city, special
+    code VARCHAR(50) NOT NULL,            -- Identifier from the dataset (e.g. FIPS code)
+    name VARCHAR(180) NOT NULL,
+    min_latitude DOUBLE PRECISION NOT NULL,
+    min_longitude DOUBLE PRECISION NOT NULL,
+    max_latitude DOUBLE PRECISION NOT NULL,
+    max_longitude DOUBLE PRECISION NOT NULL,
+    geometry TEXT NOT NULL,               -- GeoJSON MultiPolygon coordinates
+    source VARCHAR(50) NOT NULL DEFAULT 'geojson',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Imports and coverage are by country
+CREATE INDEX IF NOT EXISTS idx_tax_jurisdictions_country ON tax_jurisdictions(country_id, type);
+
+-- Bounding boxes as built-in geometric boxes, so a point lookup is an index scan
+CREATE INDEX IF NOT EXISTS idx_tax_jurisdictions_bbox
+    ON tax_jurisdictions USING gist (box(point(min_longitude, min_latitude), point(max_longitude, max_latitude)));
+
+COMMENT ON TABLE tax_jurisdictions IS 'Tax jurisdiction boundaries, used to resolve addresses and coordinates to tax regions';
+COMMENT ON COLUMN tax_jurisdictions.geometry IS 'Polygons as GeoJSON MultiPolygon coordinates ([lng, lat] pairs)';
@@ -0,0 +1,59 @@
+package models
+
+// Distance matrix travel modes. Straight-line distances need no provider; the others are
+// routed by the configured routing provider.
+const (
+	TravelModeStraightLine = "straight_line"
+	TravelModeDriving      = "driving"
+	TravelModeWalking      = "walking"
+	TravelModeCycling      = "cycling"
+)
+
+// Distance matrix calculation methods reported in responses
+const (
+	DistanceMethodHaversine = "haversine"
+	DistanceMethodRouted    = "routed"
+)
+
+// GeoPoint is a coordinate pair, optionally tagged with the caller's ID for the point
+type GeoPoint struct {
+	ID        string  `json:"id,omitempty"`
+	Latitude  float64 `json:"latitude" binding:"min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"min=-180,max=180"`
+}
+
+// DistanceMatrixRequest asks for the distance from every origin to every destination
+type DistanceMatrixRequest struct {
+	Origins      []GeoPoint `json:"origins" binding:"required,min=1,dive"`
+	Destinations []GeoPoint `json:"destinations" binding:"required,min=1,dive"`
+	Mode         string     `json:"mode,omitempty"` // straight_line (default), driving, walking or cycling
+}
+
+// DistanceMatrixElement is the distance from one origin to one destination. The straight-line
+// distance is always set; route fields are set when the pair was routed and is reachable.
+type DistanceMatrixElement struct {
+	DestinationIndex int      `json:"destination_index"`
+	DestinationID    string   `json:"destination_id,omitempty"`
+	StraightLineKm   float64  `json:"straight_line_km"`
+	RouteDistanceKm  *float64 `json:"route_distance_km,omitempty"`
+	DurationSeconds  *float64 `json:"duration_seconds,omitempty"`
+	Reachable        bool     `json:"reachable"`
+}
+
+// DistanceMatrixRow holds the distances from one origin, in destination order
+type DistanceMatrixRow struct {
+	OriginIndex int                     `json:"origin_index"`
+	OriginID    string                  `json:"origin_id,omitempty"`
+	Elements    []DistanceMatrixElement `json:"elements"`
+	// Nearest is the index of the closest reachable destination (by route when routed)
+	Nearest *int `json:"nearest,omitempty"`
+}
+
+// DistanceMatrixResult is the response of a distance matrix request
+type DistanceMatrixResult struct {
+	Mode     string              `json:"mode"`
+	Method   string              `json:"method"` // haversine or routed
+	Provider string              `json:"provider,omitempty"`
+	Fallback bool                `json:"fallback,omitempty"` // Routing was requested but failed; distances are straight-line
+	Rows     []DistanceMatrixRow `json:"rows"`
+}
@@ -94,6 +94,7 @@ type GeoTagResult struct {
 	Location         GeoTagLocation    `json:"location"`
 	Components       AddressComponents `json:"components"`
 	Metadata         GeoTagMetadata    `json:"metadata"`
+	DistanceKm       *float64          `json:"distance_km,omitempty"` // Set by nearby searches
 }
 
 // GeoTagLocation contains coordinates
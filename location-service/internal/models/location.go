@@ -25,6 +25,9 @@ type Country struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Name in the requested language; set only when the request asks for localized names
+	LocalizedName string `gorm:"-" json:"localized_name,omitempty"`
+
 	// Relationships
 	States []State `gorm:"foreignKey:CountryID" json:"states,omitempty"`
 }
@@ -44,10 +47,41 @@ type State struct {
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Name in the requested language; set only when the request asks for localized names
+	LocalizedName string `gorm:"-" json:"localized_name,omitempty"`
+
 	// Relationships
 	Country Country `gorm:"foreignKey:CountryID" json:"country,omitempty"`
 }
 
+// Localized name entity types
+const (
+	EntityTypeCountry = "country"
+	EntityTypeState   = "state"
+)
+
+// Localized name sources; admin overrides take precedence over CLDR names
+const (
+	NameSourceCLDR     = "cldr"
+	NameSourceOverride = "override"
+)
+
+// LocalizedName is the name of a country or state in one language
+type LocalizedName struct {
+	EntityType string    `gorm:"primaryKey;size:10" json:"entity_type"` // country, state
+	EntityID   string    `gorm:"primaryKey;size:10" json:"entity_id"`   // Country or state ID
+	Language   string    `gorm:"primaryKey;size:20" json:"language"`    // BCP 47 tag, e.g. de, pt-BR, zh-Hant
+	Source     string    `gorm:"primaryKey;size:10" json:"source"`      // cldr, override
+	Name       string    `gorm:"size:200;not null" json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for LocalizedName
+func (LocalizedName) TableName() string {
+	return "localized_names"
+}
+
 // Currency represents a currency in the database
 type Currency struct {
 	Code          string         `gorm:"primaryKey;size:3" json:"code"` // ISO 4217
@@ -73,6 +107,25 @@ type Timezone struct {
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// ReferenceState is a state in the reference data export; the nil Country field hides the
+// nested State.Country so each state carries only its country_id
+type ReferenceState struct {
+	State
+	Country *Country `json:"country,omitempty"`
+}
+
+// ReferenceData is the offline bundle of countries, states, currencies and timezones served
+// to edge and storefront caches. Version is a hash of the data, so it only changes with it.
+type ReferenceData struct {
+	FormatVersion int              `json:"format_version"`
+	Version       string           `json:"version"`
+	UpdatedAt     time.Time        `json:"updated_at"` // Latest update of any exported record
+	Countries     []Country        `json:"countries"`
+	States        []ReferenceState `json:"states"`
+	Currencies    []Currency       `json:"currencies"`
+	Timezones     []Timezone       `json:"timezones"`
+}
+
 // LocationCache represents cached location data for IP addresses
 type LocationCache struct {
 	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -189,4 +242,55 @@ type AddressValidationResult struct {
 	Deliverable      bool               `json:"deliverable"`
 	Issues           []string           `json:"issues,omitempty"`
 	Suggestions      []string           `json:"suggestions,omitempty"`
+
+	// Standardization report
+	Confidence      float64              `json:"confidence"`                 // 0-1, how likely the resolved address is what was meant
+	Decision        AddressDecision      `json:"decision"`                   // accept, suggest, review
+	ComponentIssues []AddressIssue       `json:"component_issues,omitempty"` // Per-component problems found
+	Corrections     []AddressCorrection  `json:"corrections,omitempty"`      // Alternatives, most likely first
+	Standardized    *StandardizedAddress `json:"standardized,omitempty"`     // Canonical form of the resolved address
+}
+
+// AddressDecision is the recommended checkout action for a validated address
+type AddressDecision string
+
+const (
+	AddressDecisionAccept  AddressDecision = "accept"  // Use the standardized address as-is
+	AddressDecisionSuggest AddressDecision = "suggest" // Ask the customer to confirm or pick a correction
+	AddressDecisionReview  AddressDecision = "review"  // Require manual entry or review
+)
+
+// AddressIssueSeverity indicates whether an issue blocks auto-acceptance
+type AddressIssueSeverity string
+
+const (
+	AddressIssueError   AddressIssueSeverity = "error"
+	AddressIssueWarning AddressIssueSeverity = "warning"
+)
+
+// AddressIssue is a problem with one component of an address
+type AddressIssue struct {
+	Component string               `json:"component"` // street_number, route, locality, postal_code, country
+	Code      string               `json:"code"`      // e.g. MISSING_HOUSE_NUMBER, AMBIGUOUS_CITY
+	Severity  AddressIssueSeverity `json:"severity"`
+	Message   string               `json:"message"`
+}
+
+// AddressCorrection is a suggested alternative for the entered address
+type AddressCorrection struct {
+	Address    string  `json:"address"`
+	PlaceID    string  `json:"place_id,omitempty"`
+	Likelihood float64 `json:"likelihood"` // 0-1, relative to the entered address
+}
+
+// StandardizedAddress is the canonical form of a resolved address
+type StandardizedAddress struct {
+	StreetNumber string   `json:"street_number,omitempty"`
+	Route        string   `json:"route,omitempty"`
+	Locality     string   `json:"locality,omitempty"`
+	AdminArea    string   `json:"admin_area,omitempty"` // State/province code where available
+	PostalCode   string   `json:"postal_code,omitempty"`
+	CountryCode  string   `json:"country_code,omitempty"` // ISO 3166-1 alpha-2
+	Lines        []string `json:"lines"`                  // Postal lines in the country's order
+	SingleLine   string   `json:"single_line"`
 }
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// PostalCodeSourceGeoNames marks postal codes imported from the GeoNames postal code dataset
+const PostalCodeSourceGeoNames = "geonames"
+
+// PostalCode is a place served by a postal code. A code can cover several places, and for some
+// countries (e.g. CA, GB) the dataset only holds the outward part of the code.
+type PostalCode struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"-"`
+	CountryID  string    `gorm:"size:2;not null;index:idx_postal_codes_lookup,priority:1" json:"country_id"`
+	PostalCode string    `gorm:"size:20;not null;index:idx_postal_codes_lookup,priority:2" json:"postal_code"`
+	PlaceName  string    `gorm:"size:180;not null" json:"place_name"`
+	StateName  string    `gorm:"size:100" json:"state_name,omitempty"`
+	StateCode  string    `gorm:"size:20" json:"state_code,omitempty"` // Admin code from the dataset
+	StateID    string    `gorm:"size:10" json:"state_id,omitempty"`   // Country-State format: US-CA, when the state is known
+	County     string    `gorm:"size:100" json:"county,omitempty"`
+	Latitude   *float64  `json:"latitude,omitempty"`
+	Longitude  *float64  `json:"longitude,omitempty"`
+	Accuracy   int       `json:"accuracy,omitempty"` // GeoNames: 1 estimated, 4 geonameid, 6 centroid of addresses
+	Source     string    `gorm:"size:20;not null;default:'geonames'" json:"source"`
+	CreatedAt  time.Time `json:"-"`
+}
+
+// TableName returns the table name for PostalCode
+func (PostalCode) TableName() string {
+	return "postal_codes"
+}
+
+// PostalCodeCoverage is the number of postal codes imported for a country
+type PostalCodeCoverage struct {
+	CountryID   string    `json:"country_id"`
+	PostalCodes int64     `json:"postal_codes"`
+	Places      int64     `json:"places"`
+	ImportedAt  time.Time `json:"imported_at"`
+}
+
+// PostalCodeLookup is the resolution of one postal code: whether it is well formed for the
+// country, whether the dataset knows it and the city and state it belongs to
+type PostalCodeLookup struct {
+	CountryID    string `json:"country_id"`
+	PostalCode   string `json:"postal_code"`  // As requested
+	Standardized string `json:"standardized"` // Uppercased with the country's canonical spacing
+	Valid        bool   `json:"valid"`
+	FormatValid  bool   `json:"format_valid"`
+	// Found reports the dataset matched the code; Precision is "exact", or "prefix" when only
+	// the outward part or 5-digit ZIP of the code is in the dataset
+	Found      bool         `json:"found"`
+	Precision  string       `json:"precision,omitempty"`
+	Verified   bool         `json:"verified"` // False when no dataset is imported for the country (format check only)
+	Reason     string       `json:"reason,omitempty"`
+	City       string       `json:"city,omitempty"`
+	StateID    string       `json:"state_id,omitempty"`
+	StateCode  string       `json:"state_code,omitempty"`
+	StateName  string       `json:"state_name,omitempty"`
+	StateMatch *bool        `json:"state_match,omitempty"` // Set when the request names a state
+	Places     []PostalCode `json:"places"`
+}
+
+// Postal code validation failure reasons
+const (
+	PostalCodeReasonInvalidFormat = "INVALID_FORMAT"
+	PostalCodeReasonNotFound      = "NOT_FOUND"
+	PostalCodeReasonStateMismatch = "STATE_MISMATCH"
+)
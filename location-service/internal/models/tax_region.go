@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// TaxJurisdictionSourceGeoJSON marks jurisdictions imported from a GeoJSON boundary file
+const TaxJurisdictionSourceGeoJSON = "geojson"
+
+// Tax jurisdiction types, from the broadest to the most specific
+const (
+	TaxJurisdictionCountry = "country"
+	TaxJurisdictionState   = "state"
+	TaxJurisdictionCounty  = "county"
+	TaxJurisdictionCity    = "city"
+	TaxJurisdictionSpecial = "special" // Special taxing districts (transit, stadium, ...)
+)
+
+// TaxJurisdiction is the boundary of a tax jurisdiction. Codes are the dataset's identifiers
+// (e.g. FIPS codes in the US) and are what pricing and checkout services key tax rates by.
+type TaxJurisdiction struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement" json:"-"`
+	CountryID    string    `gorm:"size:2;not null;index:idx_tax_jurisdictions_country,priority:1" json:"country_id"`
+	StateID      string    `gorm:"size:10" json:"state_id,omitempty"` // Country-State format: US-CA, when the state is known
+	Type         string    `gorm:"size:20;not null;index:idx_tax_jurisdictions_country,priority:2" json:"type"`
+	Code         string    `gorm:"size:50;not null" json:"code"`
+	Name         string    `gorm:"size:180;not null" json:"name"`
+	MinLatitude  float64   `gorm:"not null" json:"-"`
+	MinLongitude float64   `gorm:"not null" json:"-"`
+	MaxLatitude  float64   `gorm:"not null" json:"-"`
+	MaxLongitude float64   `gorm:"not null" json:"-"`
+	Geometry     string    `gorm:"type:text;not null" json:"-"` // GeoJSON MultiPolygon coordinates
+	Source       string    `gorm:"size:50;not null;default:'geojson'" json:"source"`
+	CreatedAt    time.Time `json:"-"`
+}
+
+// TableName returns the table name for TaxJurisdiction
+func (TaxJurisdiction) TableName() string {
+	return "tax_jurisdictions"
+}
+
+// TaxJurisdictionCoverage is the number of jurisdictions imported for a country
+type TaxJurisdictionCoverage struct {
+	CountryID        string    `json:"country_id"`
+	Jurisdictions    int64     `json:"jurisdictions"`
+	States           int64     `json:"states"`
+	Counties         int64     `json:"counties"`
+	Cities           int64     `json:"cities"`
+	SpecialDistricts int64     `json:"special_districts"`
+	ImportedAt       time.Time `json:"imported_at"`
+}
+
+// TaxRegion is the set of tax jurisdictions a location is in
+type TaxRegion struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	FormattedAddress string  `json:"formatted_address,omitempty"` // Set when resolved from an address
+	CountryID        string  `json:"country_id,omitempty"`
+	StateID          string  `json:"state_id,omitempty"`
+	// RegionKey identifies the combination of jurisdictions ("US|state:06|county:06037|city:0644000"),
+	// so services can key tax rates and cache results consistently
+	RegionKey        string            `json:"region_key,omitempty"`
+	State            *TaxJurisdiction  `json:"state,omitempty"`
+	County           *TaxJurisdiction  `json:"county,omitempty"`
+	City             *TaxJurisdiction  `json:"city,omitempty"` // Nil in unincorporated areas
+	SpecialDistricts []TaxJurisdiction `json:"special_districts"`
+	// Verified is false when no boundary data is imported for the country; the country and state
+	// then come from geocoding only
+	Verified bool `json:"verified"`
+}
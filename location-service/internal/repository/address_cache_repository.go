@@ -15,6 +15,9 @@ type AddressCacheRepository interface {
 	// GetByHash retrieves a cached entry by type and key hash
 	GetByHash(ctx context.Context, cacheType, keyHash string) (*models.AddressCacheEntry, error)
 
+	// GetByHashAllowStale retrieves a cached entry that is unexpired or expired within maxStale
+	GetByHashAllowStale(ctx context.Context, cacheType, keyHash string, maxStale time.Duration) (*models.AddressCacheEntry, error)
+
 	// Set creates or updates a cache entry (upsert)
 	Set(ctx context.Context, entry *models.AddressCacheEntry) error
 
@@ -27,6 +30,9 @@ type AddressCacheRepository interface {
 	// DeleteExpired removes expired entries in batches
 	DeleteExpired(ctx context.Context, batchSize int) (int64, error)
 
+	// DeleteExpiredBefore removes entries that expired before cutoff in batches
+	DeleteExpiredBefore(ctx context.Context, cutoff time.Time, batchSize int) (int64, error)
+
 	// GetStats returns cache statistics
 	GetStats(ctx context.Context) (*models.CacheStats, error)
 
@@ -60,6 +66,20 @@ func (r *addressCacheRepository) GetByHash(ctx context.Context, cacheType, keyHa
 	return &entry, nil
 }
 
+// GetByHashAllowStale retrieves a cached entry by type and key hash, including entries
+// expired less than maxStale ago (served while being revalidated)
+func (r *addressCacheRepository) GetByHashAllowStale(ctx context.Context, cacheType, keyHash string, maxStale time.Duration) (*models.AddressCacheEntry, error) {
+	var entry models.AddressCacheEntry
+	err := r.db.WithContext(ctx).
+		Where("cache_type = ? AND cache_key_hash = ? AND expires_at > ?",
+			cacheType, keyHash, time.Now().Add(-maxStale)).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
 // Set creates or updates a cache entry using upsert
 func (r *addressCacheRepository) Set(ctx context.Context, entry *models.AddressCacheEntry) error {
 	return r.db.WithContext(ctx).
@@ -110,8 +130,13 @@ func (r *addressCacheRepository) Delete(ctx context.Context, id uint64) error {
 
 // DeleteExpired removes expired entries in batches and returns count
 func (r *addressCacheRepository) DeleteExpired(ctx context.Context, batchSize int) (int64, error) {
+	return r.DeleteExpiredBefore(ctx, time.Now(), batchSize)
+}
+
+// DeleteExpiredBefore removes entries that expired before cutoff in batches and returns count
+func (r *addressCacheRepository) DeleteExpiredBefore(ctx context.Context, cutoff time.Time, batchSize int) (int64, error) {
 	result := r.db.WithContext(ctx).
-		Where("expires_at < ?", time.Now()).
+		Where("expires_at < ?", cutoff).
 		Limit(batchSize).
 		Delete(&models.AddressCacheEntry{})
 	return result.RowsAffected, result.Error
@@ -28,6 +28,8 @@ type CountryRepository interface {
 	// Health check methods
 	RedisHealth(ctx context.Context) error
 	CacheStats() *cache.CacheStats
+	// InvalidateCache drops every cached country and country list, e.g. after a bulk import
+	InvalidateCache(ctx context.Context)
 }
 
 // countryRepository implements CountryRepository
@@ -75,6 +77,14 @@ func (r *countryRepository) invalidateCountryCaches(ctx context.Context, id stri
 	_ = r.cache.DeletePattern(ctx, "list:*")
 }
 
+// InvalidateCache drops every cached country and country list
+func (r *countryRepository) InvalidateCache(ctx context.Context) {
+	if r.cache == nil {
+		return
+	}
+	_ = r.cache.DeletePattern(ctx, "*")
+}
+
 // RedisHealth returns the health status of Redis connection
 func (r *countryRepository) RedisHealth(ctx context.Context) error {
 	if r.redis == nil {
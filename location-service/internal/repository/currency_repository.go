@@ -29,6 +29,8 @@ type CurrencyRepository interface {
 	// Health check methods
 	RedisHealth(ctx context.Context) error
 	CacheStats() *cache.CacheStats
+	// InvalidateCache drops every cached currency and currency list, e.g. after a bulk import
+	InvalidateCache(ctx context.Context)
 }
 
 // currencyRepository implements CurrencyRepository
@@ -76,6 +78,14 @@ func (r *currencyRepository) invalidateCurrencyCaches(ctx context.Context, code
 	_ = r.cache.DeletePattern(ctx, "list:*")
 }
 
+// InvalidateCache drops every cached currency and currency list
+func (r *currencyRepository) InvalidateCache(ctx context.Context) {
+	if r.cache == nil {
+		return
+	}
+	_ = r.cache.DeletePattern(ctx, "*")
+}
+
 // RedisHealth returns the health status of Redis connection
 func (r *currencyRepository) RedisHealth(ctx context.Context) error {
 	if r.redis == nil {
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"location-service/internal/models"
+)
+
+// LocalizedNameRepository interface for localized country/state name operations
+type LocalizedNameRepository interface {
+	// Find returns names for the given entities in any of the given languages
+	Find(ctx context.Context, entityType string, entityIDs []string, languages []string) ([]models.LocalizedName, error)
+	ListForEntity(ctx context.Context, entityType, entityID string) ([]models.LocalizedName, error)
+	Upsert(ctx context.Context, name *models.LocalizedName) error
+	Delete(ctx context.Context, entityType, entityID, language, source string) (bool, error)
+}
+
+// localizedNameRepository implements LocalizedNameRepository
+type localizedNameRepository struct {
+	db *gorm.DB
+}
+
+// NewLocalizedNameRepository creates a new localized name repository
+func NewLocalizedNameRepository(db *gorm.DB) LocalizedNameRepository {
+	return &localizedNameRepository{db: db}
+}
+
+// Find returns names for the given entities in any of the given languages
+// A nil entityIDs slice matches every entity of the type
+func (r *localizedNameRepository) Find(ctx context.Context, entityType string, entityIDs []string, languages []string) ([]models.LocalizedName, error) {
+	var names []models.LocalizedName
+	query := r.db.WithContext(ctx).
+		Where("entity_type = ? AND language IN ?", entityType, languages)
+	if entityIDs != nil {
+		query = query.Where("entity_id IN ?", entityIDs)
+	}
+	if err := query.Find(&names).Error; err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// ListForEntity returns every localized name stored for a country or state
+func (r *localizedNameRepository) ListForEntity(ctx context.Context, entityType, entityID string) ([]models.LocalizedName, error) {
+	var names []models.LocalizedName
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("language ASC, source ASC").
+		Find(&names).Error
+	return names, err
+}
+
+// Upsert creates or replaces a localized name
+func (r *localizedNameRepository) Upsert(ctx context.Context, name *models.LocalizedName) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}, {Name: "language"}, {Name: "source"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+	}).Create(name).Error
+}
+
+// Delete removes a localized name, reporting whether it existed
+func (r *localizedNameRepository) Delete(ctx context.Context, entityType, entityID, language, source string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ? AND language = ? AND source = ?", entityType, entityID, language, source).
+		Delete(&models.LocalizedName{})
+	return result.RowsAffected > 0, result.Error
+}
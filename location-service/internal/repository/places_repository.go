@@ -25,8 +25,8 @@ type PlacesRepository interface {
 	// Search performs full-text search on places
 	Search(ctx context.Context, query string, filters models.SearchFilters) ([]models.Place, int64, error)
 
-	// FindNearby finds places within a radius of coordinates
-	FindNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]models.Place, error)
+	// FindNearby finds places within a radius of coordinates, nearest first
+	FindNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]PlaceWithDistance, error)
 
 	// GetByCity retrieves places in a specific city
 	GetByCity(ctx context.Context, city, countryCode string, limit, offset int) ([]models.Place, int64, error)
@@ -156,45 +156,35 @@ func (r *placesRepository) Search(ctx context.Context, query string, filters mod
 	return places, total, nil
 }
 
-// FindNearby finds places within a radius of coordinates using geohash
-func (r *placesRepository) FindNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]models.Place, error) {
-	var places []models.Place
+// FindNearby finds places within a radius of coordinates, nearest first. The earth_box
+// condition uses the idx_places_earth GiST index; earth_distance then trims the box's
+// corners to the exact radius.
+func (r *placesRepository) FindNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]PlaceWithDistance, error) {
+	var places []PlaceWithDistance
 
 	if limit <= 0 || limit > 100 {
 		limit = 20
 	}
+	radiusMeters := radiusKm * 1000
 
-	// Use Haversine formula for accurate distance calculation
-	// 6371 is Earth's radius in kilometers
 	query := `
-		SELECT *,
-			(6371 * acos(
-				cos(radians(?)) * cos(radians(latitude)) *
-				cos(radians(longitude) - radians(?)) +
-				sin(radians(?)) * sin(radians(latitude))
-			)) AS distance
-		FROM places
-		WHERE deleted_at IS NULL
-			AND latitude BETWEEN ? AND ?
-			AND longitude BETWEEN ? AND ?
-		HAVING distance <= ?
-		ORDER BY distance ASC
+		SELECT *, distance_m / 1000 AS distance
+		FROM (
+			SELECT *,
+				earth_distance(ll_to_earth(?, ?), ll_to_earth(latitude::float8, longitude::float8)) AS distance_m
+			FROM places
+			WHERE deleted_at IS NULL
+				AND earth_box(ll_to_earth(?, ?), ?) @> ll_to_earth(latitude::float8, longitude::float8)
+		) nearby
+		WHERE distance_m <= ?
+		ORDER BY distance_m ASC
 		LIMIT ?
 	`
 
-	// Calculate bounding box for initial filtering (optimization)
-	latDelta := radiusKm / 111.0 // 1 degree latitude = ~111 km
-	lngDelta := radiusKm / (111.0 * cos(lat*3.14159/180.0))
-
-	minLat := lat - latDelta
-	maxLat := lat + latDelta
-	minLng := lng - lngDelta
-	maxLng := lng + lngDelta
-
 	err := r.db.WithContext(ctx).Raw(query,
-		lat, lng, lat,
-		minLat, maxLat, minLng, maxLng,
-		radiusKm, limit,
+		lat, lng,
+		lat, lng, radiusMeters,
+		radiusMeters, limit,
 	).Scan(&places).Error
 
 	if err != nil {
@@ -406,15 +396,8 @@ func sanitizeSearchQuery(query string) string {
 	return replacer.Replace(query)
 }
 
-// cos returns the cosine of an angle in radians
-func cos(rad float64) float64 {
-	// Simple cosine approximation using Taylor series
-	// For more accuracy, use math.Cos
-	return 1 - (rad*rad)/2 + (rad*rad*rad*rad)/24
-}
-
 // PlaceWithDistance is a place with calculated distance
 type PlaceWithDistance struct {
 	models.Place
-	Distance float64 `json:"distance"`
+	Distance float64 `json:"distance"` // Kilometers
 }
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"location-service/internal/models"
+)
+
+// postalCodeImportBatchSize is the number of rows inserted per statement on import
+const postalCodeImportBatchSize = 1000
+
+// PostalCodeRepository interface for postal code operations
+type PostalCodeRepository interface {
+	// Find returns the places of any of the given codes in a country
+	Find(ctx context.Context, countryID string, postalCodes []string) ([]models.PostalCode, error)
+	// HasCountry reports whether postal codes are imported for a country
+	HasCountry(ctx context.Context, countryID string) (bool, error)
+	Coverage(ctx context.Context) ([]models.PostalCodeCoverage, error)
+	// ReplaceCountry replaces every postal code of a country in one transaction
+	ReplaceCountry(ctx context.Context, countryID string, postalCodes []models.PostalCode) error
+}
+
+// postalCodeRepository implements PostalCodeRepository
+type postalCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewPostalCodeRepository creates a new postal code repository
+func NewPostalCodeRepository(db *gorm.DB) PostalCodeRepository {
+	return &postalCodeRepository{db: db}
+}
+
+// Find returns the places of any of the given codes in a country
+func (r *postalCodeRepository) Find(ctx context.Context, countryID string, postalCodes []string) ([]models.PostalCode, error) {
+	var places []models.PostalCode
+	err := r.db.WithContext(ctx).
+		Where("country_id = ? AND postal_code IN ?", countryID, postalCodes).
+		Order("postal_code ASC, accuracy DESC, place_name ASC").
+		Find(&places).Error
+	return places, err
+}
+
+// HasCountry reports whether postal codes are imported for a country
+func (r *postalCodeRepository) HasCountry(ctx context.Context, countryID string) (bool, error) {
+	var exists bool
+	err := r.db.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM postal_codes WHERE country_id = ?)", countryID).
+		Scan(&exists).Error
+	return exists, err
+}
+
+// Coverage returns the number of postal codes and places imported per country
+func (r *postalCodeRepository) Coverage(ctx context.Context) ([]models.PostalCodeCoverage, error) {
+	var coverage []models.PostalCodeCoverage
+	err := r.db.WithContext(ctx).Model(&models.PostalCode{}).
+		Select("country_id, COUNT(DISTINCT postal_code) AS postal_codes, COUNT(*) AS places, MAX(created_at) AS imported_at").
+		Group("country_id").
+		Order("country_id ASC").
+		Scan(&coverage).Error
+	return coverage, err
+}
+
+// ReplaceCountry replaces every postal code of a country in one transaction, so lookups see
+// either the previous or the new dataset
+func (r *postalCodeRepository) ReplaceCountry(ctx context.Context, countryID string, postalCodes []models.PostalCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("country_id = ?", countryID).Delete(&models.PostalCode{}).Error; err != nil {
+			return err
+		}
+		if len(postalCodes) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(postalCodes, postalCodeImportBatchSize).Error
+	})
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"location-service/internal/models"
+)
+
+// ReferenceDataRepository reads the complete reference data set for offline export
+type ReferenceDataRepository interface {
+	// LoadAll returns every active country, state and currency and all timezones, ordered by ID
+	LoadAll(ctx context.Context) (*models.ReferenceData, error)
+	// LoadForAdmin returns every country, state, currency and timezone, active or not, ordered
+	// by ID; soft-deleted records are included only when includeDeleted is set
+	LoadForAdmin(ctx context.Context, includeDeleted bool) (*models.ReferenceData, error)
+	// Apply creates or updates the given records in a single transaction
+	Apply(ctx context.Context, records *models.ReferenceData) error
+}
+
+// referenceDataRepository implements ReferenceDataRepository
+type referenceDataRepository struct {
+	db *gorm.DB
+}
+
+// NewReferenceDataRepository creates a new reference data repository
+func NewReferenceDataRepository(db *gorm.DB) ReferenceDataRepository {
+	return &referenceDataRepository{db: db}
+}
+
+// LoadAll returns every active country, state and currency and all timezones, ordered by ID.
+// The data is read straight from the database so the export never mixes cached and fresh rows.
+func (r *referenceDataRepository) LoadAll(ctx context.Context) (*models.ReferenceData, error) {
+	data := &models.ReferenceData{
+		Countries:  []models.Country{},
+		States:     []models.ReferenceState{},
+		Currencies: []models.Currency{},
+		Timezones:  []models.Timezone{},
+	}
+	db := r.db.WithContext(ctx)
+
+	if err := db.Where("active = ?", true).Order("id").Find(&data.Countries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load countries: %w", err)
+	}
+
+	var states []models.State
+	if err := db.Where("active = ?", true).Order("id").Find(&states).Error; err != nil {
+		return nil, fmt.Errorf("failed to load states: %w", err)
+	}
+	for _, state := range states {
+		data.States = append(data.States, models.ReferenceState{State: state})
+	}
+
+	if err := db.Where("active = ?", true).Order("code").Find(&data.Currencies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load currencies: %w", err)
+	}
+	if err := db.Order("id").Find(&data.Timezones).Error; err != nil {
+		return nil, fmt.Errorf("failed to load timezones: %w", err)
+	}
+	return data, nil
+}
+
+// LoadForAdmin returns every country, state, currency and timezone, active or not, ordered by ID.
+// Soft-deleted records are included only when includeDeleted is set.
+func (r *referenceDataRepository) LoadForAdmin(ctx context.Context, includeDeleted bool) (*models.ReferenceData, error) {
+	data := &models.ReferenceData{
+		Countries:  []models.Country{},
+		States:     []models.ReferenceState{},
+		Currencies: []models.Currency{},
+		Timezones:  []models.Timezone{},
+	}
+	db := r.db.WithContext(ctx)
+	if includeDeleted {
+		db = db.Unscoped()
+	}
+
+	if err := db.Order("id").Find(&data.Countries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load countries: %w", err)
+	}
+
+	var states []models.State
+	if err := db.Order("id").Find(&states).Error; err != nil {
+		return nil, fmt.Errorf("failed to load states: %w", err)
+	}
+	for _, state := range states {
+		data.States = append(data.States, models.ReferenceState{State: state})
+	}
+
+	if err := db.Order("code").Find(&data.Currencies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load currencies: %w", err)
+	}
+	if err := db.Order("id").Find(&data.Timezones).Error; err != nil {
+		return nil, fmt.Errorf("failed to load timezones: %w", err)
+	}
+	return data, nil
+}
+
+// Apply creates or updates the given records in a single transaction. Soft-deleted records
+// are restored. Currencies and countries are written before the states that reference them.
+func (r *referenceDataRepository) Apply(ctx context.Context, records *models.ReferenceData) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range records.Currencies {
+			currency := &records.Currencies[i]
+			if err := upsertReferenceRecord(tx, currency, currency.Active); err != nil {
+				return fmt.Errorf("failed to import currency %s: %w", currency.Code, err)
+			}
+		}
+		for i := range records.Countries {
+			country := &records.Countries[i]
+			if err := upsertReferenceRecord(tx, country, country.Active); err != nil {
+				return fmt.Errorf("failed to import country %s: %w", country.ID, err)
+			}
+		}
+		for i := range records.States {
+			state := &records.States[i].State
+			if err := upsertReferenceRecord(tx, state, state.Active); err != nil {
+				return fmt.Errorf("failed to import state %s: %w", state.ID, err)
+			}
+		}
+		for i := range records.Timezones {
+			timezone := &records.Timezones[i]
+			if err := upsertReferenceRecord(tx, timezone, true); err != nil {
+				return fmt.Errorf("failed to import timezone %s: %w", timezone.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// upsertReferenceRecord writes every column of an existing (possibly soft-deleted) record, or
+// creates it. Create leaves out false values of columns with a default, so a new inactive
+// record is deactivated afterwards.
+func upsertReferenceRecord(tx *gorm.DB, record interface{}, active bool) error {
+	result := tx.Unscoped().Select("*").Omit(clause.Associations).Updates(record)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	if err := tx.Omit(clause.Associations).Create(record).Error; err != nil {
+		return err
+	}
+	if !active {
+		return tx.Model(record).Update("active", false).Error
+	}
+	return nil
+}
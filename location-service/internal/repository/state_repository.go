@@ -29,6 +29,8 @@ type StateRepository interface {
 	// Health check methods
 	RedisHealth(ctx context.Context) error
 	CacheStats() *cache.CacheStats
+	// InvalidateCache drops every cached state and state list, e.g. after a bulk import
+	InvalidateCache(ctx context.Context)
 }
 
 // stateRepository implements StateRepository
@@ -76,6 +78,14 @@ func (r *stateRepository) invalidateStateCaches(ctx context.Context, id string)
 	_ = r.cache.DeletePattern(ctx, "list:*")
 }
 
+// InvalidateCache drops every cached state and state list
+func (r *stateRepository) InvalidateCache(ctx context.Context) {
+	if r.cache == nil {
+		return
+	}
+	_ = r.cache.DeletePattern(ctx, "*")
+}
+
 // RedisHealth returns the health status of Redis connection
 func (r *stateRepository) RedisHealth(ctx context.Context) error {
 	if r.redis == nil {
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"location-service/internal/models"
+)
+
+// taxJurisdictionImportBatchSize is the number of rows inserted per statement on import; rows
+// carry their polygons, so batches are smaller than for postal codes
+const taxJurisdictionImportBatchSize = 100
+
+// TaxJurisdictionRepository interface for tax jurisdiction operations
+type TaxJurisdictionRepository interface {
+	// FindContaining returns the jurisdictions whose bounding box contains a point, in one
+	// country when countryID is set
+	FindContaining(ctx context.Context, lat, lng float64, countryID string) ([]models.TaxJurisdiction, error)
+	// HasCountry reports whether jurisdictions are imported for a country
+	HasCountry(ctx context.Context, countryID string) (bool, error)
+	Coverage(ctx context.Context) ([]models.TaxJurisdictionCoverage, error)
+	// ReplaceCountry replaces every jurisdiction of a country in one transaction
+	ReplaceCountry(ctx context.Context, countryID string, jurisdictions []models.TaxJurisdiction) error
+}
+
+// taxJurisdictionRepository implements TaxJurisdictionRepository
+type taxJurisdictionRepository struct {
+	db *gorm.DB
+}
+
+// NewTaxJurisdictionRepository creates a new tax jurisdiction repository
+func NewTaxJurisdictionRepository(db *gorm.DB) TaxJurisdictionRepository {
+	return &taxJurisdictionRepository{db: db}
+}
+
+// FindContaining returns the jurisdictions whose bounding box contains a point. The expression
+// matches idx_tax_jurisdictions_bbox; callers check the polygons.
+func (r *taxJurisdictionRepository) FindContaining(ctx context.Context, lat, lng float64, countryID string) ([]models.TaxJurisdiction, error) {
+	query := r.db.WithContext(ctx).
+		Where("box(point(min_longitude, min_latitude), point(max_longitude, max_latitude)) @> point(?, ?)", lng, lat)
+	if countryID != "" {
+		query = query.Where("country_id = ?", countryID)
+	}
+
+	var jurisdictions []models.TaxJurisdiction
+	err := query.Order("country_id ASC, type ASC, code ASC").Find(&jurisdictions).Error
+	return jurisdictions, err
+}
+
+// HasCountry reports whether jurisdictions are imported for a country
+func (r *taxJurisdictionRepository) HasCountry(ctx context.Context, countryID string) (bool, error) {
+	var exists bool
+	err := r.db.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM tax_jurisdictions WHERE country_id = ?)", countryID).
+		Scan(&exists).Error
+	return exists, err
+}
+
+// Coverage returns the number of jurisdictions of each type imported per country
+func (r *taxJurisdictionRepository) Coverage(ctx context.Context) ([]models.TaxJurisdictionCoverage, error) {
+	var coverage []models.TaxJurisdictionCoverage
+	err := r.db.WithContext(ctx).Model(&models.TaxJurisdiction{}).
+		Select(`country_id, COUNT(*) AS jurisdictions,
+			COUNT(*) FILTER (WHERE type = ?) AS states,
+			COUNT(*) FILTER (WHERE type = ?) AS counties,
+			COUNT(*) FILTER (WHERE type = ?) AS cities,
+			COUNT(*) FILTER (WHERE type = ?) AS special_districts,
+			MAX(created_at) AS imported_at`,
+			models.TaxJurisdictionState, models.TaxJurisdictionCounty, models.TaxJurisdictionCity, models.TaxJurisdictionSpecial).
+		Group("country_id").
+		Order("country_id ASC").
+		Scan(&coverage).Error
+	return coverage, err
+}
+
+// ReplaceCountry replaces every jurisdiction of a country in one transaction, so lookups see
+// either the previous or the new boundaries
+func (r *taxJurisdictionRepository) ReplaceCountry(ctx context.Context, countryID string, jurisdictions []models.TaxJurisdiction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("country_id = ?", countryID).Delete(&models.TaxJurisdiction{}).Error; err != nil {
+			return err
+		}
+		if len(jurisdictions) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(jurisdictions, taxJurisdictionImportBatchSize).Error
+	})
+}
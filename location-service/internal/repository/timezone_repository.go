@@ -29,6 +29,8 @@ type TimezoneRepository interface {
 	// Health check methods
 	RedisHealth(ctx context.Context) error
 	CacheStats() *cache.CacheStats
+	// InvalidateCache drops every cached timezone and timezone list, e.g. after a bulk import
+	InvalidateCache(ctx context.Context)
 }
 
 // timezoneRepository implements TimezoneRepository
@@ -76,6 +78,14 @@ func (r *timezoneRepository) invalidateTimezoneCaches(ctx context.Context, id st
 	_ = r.cache.DeletePattern(ctx, "list:*")
 }
 
+// InvalidateCache drops every cached timezone and timezone list
+func (r *timezoneRepository) InvalidateCache(ctx context.Context) {
+	if r.cache == nil {
+		return
+	}
+	_ = r.cache.DeletePattern(ctx, "*")
+}
+
 // RedisHealth returns the health status of Redis connection
 func (r *timezoneRepository) RedisHealth(ctx context.Context) error {
 	if r.redis == nil {
@@ -0,0 +1,138 @@
+package seeder
+
+import (
+	"log"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"location-service/internal/models"
+)
+
+// cldrNameLanguages are the languages country names are seeded in from CLDR
+// English is the base name on the country itself and is not stored
+var cldrNameLanguages = []string{
+	"ar", "bn", "de", "es", "fr", "hi", "id", "it", "ja", "ko", "ms",
+	"ne", "nl", "pl", "pt", "ru", "sv", "th", "tr", "vi", "zh", "zh-Hant",
+}
+
+// seedLocalizedNames upserts CLDR country names for every country in the database,
+// plus native-script state names. Admin overrides are stored separately and never touched.
+func seedLocalizedNames(db *gorm.DB) error {
+	var countryIDs []string
+	if err := db.Model(&models.Country{}).Pluck("id", &countryIDs).Error; err != nil {
+		return err
+	}
+
+	names := make([]models.LocalizedName, 0, len(countryIDs)*len(cldrNameLanguages))
+	for _, lang := range cldrNameLanguages {
+		namer := display.Regions(language.MustParse(lang))
+		if namer == nil {
+			continue
+		}
+		for _, id := range countryIDs {
+			region, err := language.ParseRegion(id)
+			if err != nil {
+				continue
+			}
+			if name := namer.Name(region); name != "" {
+				names = append(names, models.LocalizedName{
+					EntityType: models.EntityTypeCountry,
+					EntityID:   id,
+					Language:   lang,
+					Source:     models.NameSourceCLDR,
+					Name:       name,
+				})
+			}
+		}
+	}
+
+	for lang, states := range getStateNativeNames() {
+		for id, name := range states {
+			names = append(names, models.LocalizedName{
+				EntityType: models.EntityTypeState,
+				EntityID:   id,
+				Language:   lang,
+				Source:     models.NameSourceCLDR,
+				Name:       name,
+			})
+		}
+	}
+
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}, {Name: "language"}, {Name: "source"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+	}).CreateInBatches(names, 500)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	log.Printf("Localized names: %d upserted across %d languages", len(names), len(cldrNameLanguages))
+	return nil
+}
+
+// getStateNativeNames returns official local-language state names, keyed by state ID,
+// for countries whose subdivisions are written in a non-Latin script
+func getStateNativeNames() map[string]map[string]string {
+	return map[string]map[string]string{
+		"ja": {
+			"JP-01": "北海道",
+			"JP-13": "東京都",
+			"JP-14": "神奈川県",
+			"JP-23": "愛知県",
+			"JP-26": "京都府",
+			"JP-27": "大阪府",
+			"JP-28": "兵庫県",
+			"JP-40": "福岡県",
+		},
+		"ko": {
+			"KR-11": "서울특별시",
+			"KR-26": "부산광역시",
+			"KR-27": "대구광역시",
+			"KR-28": "인천광역시",
+			"KR-41": "경기도",
+			"KR-42": "강원특별자치도",
+			"KR-47": "경상북도",
+			"KR-50": "제주특별자치도",
+		},
+		"th": {
+			"TH-10": "กรุงเทพมหานคร",
+			"TH-20": "ชลบุรี",
+			"TH-30": "นครราชสีมา",
+			"TH-40": "ขอนแก่น",
+			"TH-50": "เชียงใหม่",
+			"TH-71": "กาญจนบุรี",
+			"TH-83": "ภูเก็ต",
+			"TH-90": "สงขลา",
+		},
+		"ar": {
+			"AE-AJ": "عجمان",
+			"AE-AZ": "أبوظبي",
+			"AE-DU": "دبي",
+			"AE-FU": "الفجيرة",
+			"AE-RK": "رأس الخيمة",
+			"AE-SH": "الشارقة",
+			"AE-UQ": "أم القيوين",
+		},
+		"bn": {
+			"BD-A": "বরিশাল",
+			"BD-B": "চট্টগ্রাম",
+			"BD-C": "ঢাকা",
+			"BD-D": "খুলনা",
+			"BD-E": "রাজশাহী",
+			"BD-F": "রংপুর",
+			"BD-G": "সিলেট",
+			"BD-H": "ময়মনসিংহ",
+		},
+		"ne": {
+			"NP-P1": "कोशी प्रदेश",
+			"NP-P2": "मधेश प्रदेश",
+			"NP-P3": "बागमती प्रदेश",
+			"NP-P4": "गण्डकी प्रदेश",
+			"NP-P5": "लुम्बिनी प्रदेश",
+			"NP-P6": "कर्णाली प्रदेश",
+			"NP-P7": "सुदूरपश्चिम प्रदेश",
+		},
+	}
+}
@@ -38,6 +38,12 @@ func SeedDatabase(db *gorm.DB) error {
 		log.Printf("Warning: Failed to seed timezones: %v", err)
 	}
 
+	// Seed localized country/state names (after countries and states exist)
+	log.Println("Seeding/updating localized names...")
+	if err := seedLocalizedNames(db); err != nil {
+		log.Printf("Warning: Failed to seed localized names: %v", err)
+	}
+
 	// Log final counts
 	var countryCount, stateCount, currencyCount, timezoneCount int64
 	db.Model(&models.Country{}).Count(&countryCount)
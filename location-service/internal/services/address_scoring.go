@@ -0,0 +1,422 @@
+package services
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"location-service/internal/models"
+)
+
+// Decision thresholds for address confidence
+const (
+	AddressAcceptThreshold  = 0.85 // At or above, with no error issues: auto-accept
+	AddressSuggestThreshold = 0.5  // Below: force manual review
+	maxAddressCorrections   = 3
+)
+
+// issue penalties subtracted from confidence
+var addressIssuePenalties = map[string]float64{
+	"MISSING_HOUSE_NUMBER":  0.30,
+	"HOUSE_NUMBER_MISMATCH": 0.25,
+	"MISSING_STREET":        0.35,
+	"MISSING_CITY":          0.25,
+	"AMBIGUOUS_CITY":        0.15,
+	"MISSING_POSTAL_CODE":   0.10,
+	"INVALID_POSTAL_CODE":   0.15,
+	"POSTAL_CODE_MISMATCH":  0.25,
+	"MISSING_COUNTRY":       0.20,
+}
+
+// postalCodePatterns validates standardized postal codes for common countries
+var postalCodePatterns = map[string]*regexp.Regexp{
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] \d[A-Z]\d$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? \d[A-Z]{2}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"NZ": regexp.MustCompile(`^\d{4}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^\d{4} [A-Z]{2}$`),
+	"IN": regexp.MustCompile(`^\d{6}$`),
+	"JP": regexp.MustCompile(`^\d{3}-\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-\d{3}$`),
+	"SG": regexp.MustCompile(`^\d{6}$`),
+}
+
+// streetNumberAfterRoute lists countries that write the house number after the street name
+var streetNumberAfterRoute = map[string]bool{
+	"AT": true, "BE": true, "BR": true, "CH": true, "CZ": true, "DE": true, "DK": true,
+	"ES": true, "FI": true, "IT": true, "MX": true, "NL": true, "NO": true, "PL": true,
+	"PT": true, "SE": true, "AR": true, "CL": true,
+}
+
+// postalBeforeLocality lists countries whose city line is "postal-code locality"
+// rather than "locality, STATE postal-code"
+var postalBeforeLocality = map[string]bool{
+	"AT": true, "BE": true, "CH": true, "CZ": true, "DE": true, "DK": true, "ES": true,
+	"FI": true, "FR": true, "IT": true, "NL": true, "NO": true, "PL": true, "PT": true,
+	"SE": true, "BR": true, "MX": true,
+}
+
+var addressNumberPattern = regexp.MustCompile(`\b\d+[A-Za-z]?\b`)
+
+// addressParts holds the components of a resolved address by type
+type addressParts struct {
+	streetNumber string
+	route        string
+	locality     string
+	adminArea    string
+	postalCode   string
+	country      string
+	countryCode  string
+}
+
+func partsFromComponents(components []models.AddressComponent) addressParts {
+	var p addressParts
+	for _, comp := range components {
+		switch comp.Type {
+		case "street_number":
+			p.streetNumber = strings.TrimSpace(comp.LongName)
+		case "route":
+			p.route = strings.TrimSpace(comp.LongName)
+		case "locality":
+			p.locality = strings.TrimSpace(comp.LongName)
+		case "sublocality":
+			if p.locality == "" {
+				p.locality = strings.TrimSpace(comp.LongName)
+			}
+		case "administrative_area_level_1":
+			p.adminArea = strings.TrimSpace(comp.ShortName)
+			if p.adminArea == "" {
+				p.adminArea = strings.TrimSpace(comp.LongName)
+			}
+		case "postal_code":
+			p.postalCode = strings.TrimSpace(comp.LongName)
+		case "country":
+			p.country = strings.TrimSpace(comp.LongName)
+			if code := strings.ToUpper(strings.TrimSpace(comp.ShortName)); len(code) == 2 {
+				p.countryCode = code
+			}
+		}
+	}
+	return p
+}
+
+// scoreAddress adds the confidence score, component issues, corrections and
+// standardized form to a provider's validation result
+func (s *AddressService) scoreAddress(ctx context.Context, input string, result *models.AddressValidationResult) {
+	if !result.Valid && result.FormattedAddress == "" {
+		result.Confidence = 0
+		result.Decision = models.AddressDecisionReview
+		result.ComponentIssues = append(result.ComponentIssues, models.AddressIssue{
+			Component: "address",
+			Code:      "NOT_FOUND",
+			Severity:  models.AddressIssueError,
+			Message:   "Address could not be found",
+		})
+		result.Corrections = s.addressCorrections(ctx, input, "")
+		return
+	}
+
+	parts := partsFromComponents(result.Components)
+	parts.postalCode = standardizePostalCode(parts.postalCode, parts.countryCode)
+	result.Standardized = standardizeAddress(parts)
+	result.ComponentIssues = append(result.ComponentIssues, componentIssues(input, parts)...)
+
+	confidence := 1.0
+	hasError := false
+	for _, issue := range result.ComponentIssues {
+		confidence -= addressIssuePenalties[issue.Code]
+		if issue.Severity == models.AddressIssueError {
+			hasError = true
+		}
+	}
+	// Scale by how much of what was typed is reflected in the resolved address
+	confidence *= 0.6 + 0.4*tokenCoverage(input, result.FormattedAddress)
+	result.Confidence = math.Round(math.Max(0, math.Min(1, confidence))*100) / 100
+
+	switch {
+	case !result.Valid || result.Confidence < AddressSuggestThreshold:
+		result.Decision = models.AddressDecisionReview
+	case result.Confidence >= AddressAcceptThreshold && !hasError:
+		result.Decision = models.AddressDecisionAccept
+	default:
+		result.Decision = models.AddressDecisionSuggest
+	}
+
+	// Corrections cost a provider call, so only fetch them when the customer will see them
+	if result.Decision != models.AddressDecisionAccept {
+		result.Corrections = s.addressCorrections(ctx, input, result.FormattedAddress)
+	}
+	if len(result.Suggestions) == 0 {
+		for _, correction := range result.Corrections {
+			result.Suggestions = append(result.Suggestions, correction.Address)
+		}
+	}
+}
+
+// componentIssues checks a resolved address for missing or doubtful components
+func componentIssues(input string, p addressParts) []models.AddressIssue {
+	var issues []models.AddressIssue
+	add := func(component, code string, severity models.AddressIssueSeverity, message string) {
+		issues = append(issues, models.AddressIssue{Component: component, Code: code, Severity: severity, Message: message})
+	}
+	normalizedInput := normalizeAddressText(input)
+
+	inputPostal := ""
+	if p.postalCode != "" {
+		inputPostal = findPostalCode(input, p.countryCode)
+	}
+
+	switch {
+	case p.route == "":
+		add("route", "MISSING_STREET", models.AddressIssueError, "Street name is missing")
+	case p.streetNumber == "":
+		add("street_number", "MISSING_HOUSE_NUMBER", models.AddressIssueError, "House number is missing")
+	default:
+		// A number typed by the customer that differs from the resolved one suggests the wrong building
+		var numbers []string
+		for _, number := range addressNumberPattern.FindAllString(input, -1) {
+			if !strings.Contains(inputPostal, number) {
+				numbers = append(numbers, number)
+			}
+		}
+		switch {
+		case len(numbers) == 0:
+			add("street_number", "MISSING_HOUSE_NUMBER", models.AddressIssueError, "House number was not entered")
+		case !containsFold(numbers, p.streetNumber):
+			add("street_number", "HOUSE_NUMBER_MISMATCH", models.AddressIssueError, "House number "+p.streetNumber+" differs from the one entered")
+		}
+	}
+
+	switch {
+	case p.locality == "":
+		add("locality", "MISSING_CITY", models.AddressIssueError, "City is missing")
+	case !strings.Contains(normalizedInput, normalizeAddressText(p.locality)) && inputPostal == "":
+		// The provider picked a city the customer did not type and no postal code pins it down
+		add("locality", "AMBIGUOUS_CITY", models.AddressIssueWarning, "City "+p.locality+" was inferred; confirm it is correct")
+	}
+
+	switch {
+	case p.postalCode == "":
+		add("postal_code", "MISSING_POSTAL_CODE", models.AddressIssueWarning, "Postal code is missing")
+	case inputPostal != "" && standardizePostalCode(inputPostal, p.countryCode) != p.postalCode:
+		add("postal_code", "POSTAL_CODE_MISMATCH", models.AddressIssueError, "Postal code "+p.postalCode+" differs from the one entered")
+	default:
+		if pattern, ok := postalCodePatterns[p.countryCode]; ok && !pattern.MatchString(p.postalCode) {
+			add("postal_code", "INVALID_POSTAL_CODE", models.AddressIssueWarning, "Postal code format is not valid for "+p.countryCode)
+		}
+	}
+
+	if p.country == "" && p.countryCode == "" {
+		add("country", "MISSING_COUNTRY", models.AddressIssueError, "Country is missing")
+	}
+	return issues
+}
+
+// findPostalCode returns the postal code typed in the input, if recognizable for the country
+func findPostalCode(input, countryCode string) string {
+	pattern, ok := postalCodePatterns[countryCode]
+	if !ok {
+		return ""
+	}
+	// Match against standardized candidates built from each token and adjacent token pairs
+	tokens := strings.FieldsFunc(strings.ToUpper(input), func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	for i := range tokens {
+		candidates := []string{tokens[i]}
+		if i+1 < len(tokens) {
+			candidates = append(candidates, tokens[i]+" "+tokens[i+1])
+		}
+		for _, candidate := range candidates {
+			if pattern.MatchString(standardizePostalCode(candidate, countryCode)) {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// standardizePostalCode uppercases a postal code and applies the country's canonical spacing
+func standardizePostalCode(code, countryCode string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	compact := strings.NewReplacer(" ", "", "-", "").Replace(code)
+	switch countryCode {
+	case "CA", "GB":
+		if len(compact) >= 5 {
+			return compact[:len(compact)-3] + " " + compact[len(compact)-3:]
+		}
+	case "NL":
+		if len(compact) == 6 {
+			return compact[:4] + " " + compact[4:]
+		}
+	case "JP":
+		if len(compact) == 7 {
+			return compact[:3] + "-" + compact[3:]
+		}
+	case "BR":
+		if len(compact) == 8 {
+			return compact[:5] + "-" + compact[5:]
+		}
+	case "US":
+		if len(compact) == 9 {
+			return compact[:5] + "-" + compact[5:]
+		}
+		return code
+	default:
+		return code
+	}
+	return code
+}
+
+// standardizeAddress builds the canonical postal lines for the resolved address
+func standardizeAddress(p addressParts) *models.StandardizedAddress {
+	std := &models.StandardizedAddress{
+		StreetNumber: p.streetNumber,
+		Route:        p.route,
+		Locality:     p.locality,
+		AdminArea:    p.adminArea,
+		PostalCode:   p.postalCode,
+		CountryCode:  p.countryCode,
+	}
+
+	var street string
+	if streetNumberAfterRoute[p.countryCode] {
+		street = joinNonEmpty(" ", p.route, p.streetNumber)
+	} else {
+		street = joinNonEmpty(" ", p.streetNumber, p.route)
+	}
+
+	var cityLine string
+	if postalBeforeLocality[p.countryCode] {
+		cityLine = joinNonEmpty(" ", p.postalCode, p.locality)
+	} else {
+		cityLine = joinNonEmpty(" ", joinNonEmpty(", ", p.locality, p.adminArea), p.postalCode)
+	}
+
+	country := p.countryCode
+	if country == "" {
+		country = p.country
+	}
+
+	for _, line := range []string{street, cityLine, country} {
+		if line != "" {
+			std.Lines = append(std.Lines, line)
+		}
+	}
+	std.SingleLine = strings.Join(std.Lines, ", ")
+	return std
+}
+
+// addressCorrections ranks autocomplete candidates by similarity to what was typed
+func (s *AddressService) addressCorrections(ctx context.Context, input, resolved string) []models.AddressCorrection {
+	suggestions, err := s.provider.Autocomplete(ctx, input, AutocompleteOptions{Types: []string{"address"}})
+	if err != nil || len(suggestions) == 0 {
+		return nil
+	}
+
+	resolvedKey := normalizeAddressText(resolved)
+	var corrections []models.AddressCorrection
+	for i, suggestion := range suggestions {
+		if suggestion.Description == "" || normalizeAddressText(suggestion.Description) == resolvedKey {
+			continue
+		}
+		// Blend textual similarity with the provider's own ranking
+		rankPrior := 1 - 0.05*float64(i)
+		likelihood := tokenSimilarity(input, suggestion.Description) * rankPrior
+		if likelihood <= 0 {
+			continue
+		}
+		corrections = append(corrections, models.AddressCorrection{
+			Address:    suggestion.Description,
+			PlaceID:    suggestion.PlaceID,
+			Likelihood: math.Round(likelihood*100) / 100,
+		})
+	}
+
+	sort.SliceStable(corrections, func(i, j int) bool {
+		return corrections[i].Likelihood > corrections[j].Likelihood
+	})
+	if len(corrections) > maxAddressCorrections {
+		corrections = corrections[:maxAddressCorrections]
+	}
+	return corrections
+}
+
+// normalizeAddressText lowercases and strips punctuation for comparison
+func normalizeAddressText(text string) string {
+	return strings.Join(addressTokens(text), " ")
+}
+
+func addressTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// tokenCoverage returns the fraction of input tokens present in the resolved text
+func tokenCoverage(input, resolved string) float64 {
+	inputTokens := addressTokens(input)
+	if len(inputTokens) == 0 {
+		return 0
+	}
+	resolvedSet := make(map[string]bool)
+	for _, token := range addressTokens(resolved) {
+		resolvedSet[token] = true
+	}
+	found := 0
+	for _, token := range inputTokens {
+		if resolvedSet[token] {
+			found++
+		}
+	}
+	return float64(found) / float64(len(inputTokens))
+}
+
+// tokenSimilarity is the Dice coefficient over the two texts' token sets
+func tokenSimilarity(a, b string) float64 {
+	setA := make(map[string]bool)
+	for _, token := range addressTokens(a) {
+		setA[token] = true
+	}
+	setB := make(map[string]bool)
+	for _, token := range addressTokens(b) {
+		setB[token] = true
+	}
+	if len(setA)+len(setB) == 0 {
+		return 0
+	}
+	shared := 0
+	for token := range setA {
+		if setB[token] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(setA)+len(setB))
+}
+
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var nonEmpty []string
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
+}
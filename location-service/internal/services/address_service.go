@@ -46,6 +46,7 @@ type AddressServiceConfig struct {
 	LocationIQAPIKey string
 	PhotonURL        string // Custom Photon URL (default: https://photon.komoot.io)
 	EnableFailover   bool   // If true, creates failover chain
+	Quota            *GeoQuotaService
 }
 
 // NewAddressService creates a new address service
@@ -121,6 +122,13 @@ func NewAddressServiceWithFailover(config AddressServiceConfig) *AddressService
 		names = append(names, "mock")
 	}
 
+	// Meter each provider call against the requesting tenant's geocoding quota
+	if config.Quota.Enabled() {
+		for i := range providers {
+			providers[i] = NewMeteredAddressProvider(providers[i], names[i], config.Quota)
+		}
+	}
+
 	var provider AddressProvider
 	if len(providers) == 1 {
 		provider = providers[0]
@@ -177,7 +185,13 @@ func (s *AddressService) ValidateAddress(ctx context.Context, address string) (*
 			Issues: []string{"Address cannot be empty"},
 		}, nil
 	}
-	return s.provider.ValidateAddress(ctx, address)
+
+	result, err := s.provider.ValidateAddress(ctx, address)
+	if err != nil || result == nil {
+		return result, err
+	}
+	s.scoreAddress(ctx, address, result)
+	return result, nil
 }
 
 // ==================== GOOGLE PLACES PROVIDER ====================
@@ -8,8 +8,12 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/singleflight"
 	"location-service/internal/models"
 	"location-service/internal/repository"
 )
@@ -30,6 +34,11 @@ type CacheConfig struct {
 
 	// Minimum confidence to store a place
 	MinConfidence float64
+
+	// How long past expiry an entry is still served while it is refreshed in the background
+	StaleWhileRevalidate time.Duration // Default: 24 hours
+	// Timeout of a provider call shared by coalesced requests
+	FetchTimeout time.Duration // Default: 10 seconds
 }
 
 // DefaultCacheConfig returns sensible defaults
@@ -42,53 +51,137 @@ func DefaultCacheConfig() CacheConfig {
 		PlaceDetailsTTL:   30 * 24 * time.Hour,
 		StorePlaces:       true,
 		MinConfidence:     0.7,
+
+		StaleWhileRevalidate: 24 * time.Hour,
+		FetchTimeout:         10 * time.Second,
 	}
 }
 
+var (
+	coalescedRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tesseract",
+			Subsystem: "geotag",
+			Name:      "cache_coalesced_requests_total",
+			Help:      "Total number of cache misses served by another request's in-flight provider call",
+		},
+		[]string{"type"},
+	)
+
+	staleServed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tesseract",
+			Subsystem: "geotag",
+			Name:      "cache_stale_served_total",
+			Help:      "Total number of expired cache entries served while being revalidated",
+		},
+		[]string{"type"},
+	)
+
+	cacheRevalidations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tesseract",
+			Subsystem: "geotag",
+			Name:      "cache_revalidations_total",
+			Help:      "Total number of background refreshes of expired cache entries",
+		},
+		[]string{"type"},
+	)
+)
+
 // CacheMetrics tracks cache statistics
 type CacheMetrics struct {
-	Hits       map[string]int64
-	Misses     map[string]int64
-	Sets       map[string]int64
-	Errors     map[string]int64
-	TotalHits  int64
+	mu          sync.Mutex
+	Hits        map[string]int64
+	Misses      map[string]int64
+	Sets        map[string]int64
+	Errors      map[string]int64
+	Coalesced   map[string]int64 // Misses that joined an in-flight provider call
+	StaleServed map[string]int64 // Expired entries served while revalidating
+	Refreshes   map[string]int64 // Background revalidations started
+	TotalHits   int64
 	TotalMisses int64
 }
 
+// CoalescingStats summarizes request coalescing and stale-while-revalidate activity by cache type
+type CoalescingStats struct {
+	Coalesced      map[string]int64 `json:"coalesced"`
+	StaleServed    map[string]int64 `json:"staleServed"`
+	Refreshes      map[string]int64 `json:"refreshes"`
+	TotalCoalesced int64            `json:"totalCoalesced"`
+}
+
 // NewCacheMetrics creates a new metrics tracker
 func NewCacheMetrics() *CacheMetrics {
 	return &CacheMetrics{
-		Hits:   make(map[string]int64),
-		Misses: make(map[string]int64),
-		Sets:   make(map[string]int64),
-		Errors: make(map[string]int64),
+		Hits:        make(map[string]int64),
+		Misses:      make(map[string]int64),
+		Sets:        make(map[string]int64),
+		Errors:      make(map[string]int64),
+		Coalesced:   make(map[string]int64),
+		StaleServed: make(map[string]int64),
+		Refreshes:   make(map[string]int64),
 	}
 }
 
 // RecordHit records a cache hit
 func (m *CacheMetrics) RecordHit(cacheType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Hits[cacheType]++
 	m.TotalHits++
 }
 
 // RecordMiss records a cache miss
 func (m *CacheMetrics) RecordMiss(cacheType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Misses[cacheType]++
 	m.TotalMisses++
 }
 
 // RecordSet records a cache set operation
 func (m *CacheMetrics) RecordSet(cacheType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Sets[cacheType]++
 }
 
 // RecordError records a cache error
 func (m *CacheMetrics) RecordError(cacheType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Errors[cacheType]++
 }
 
+// RecordCoalesced records a miss answered by another request's provider call
+func (m *CacheMetrics) RecordCoalesced(cacheType string) {
+	coalescedRequests.WithLabelValues(cacheType).Inc()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Coalesced[cacheType]++
+}
+
+// RecordStale records an expired entry served while it is revalidated
+func (m *CacheMetrics) RecordStale(cacheType string) {
+	staleServed.WithLabelValues(cacheType).Inc()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.StaleServed[cacheType]++
+}
+
+// RecordRefresh records a background revalidation
+func (m *CacheMetrics) RecordRefresh(cacheType string) {
+	cacheRevalidations.WithLabelValues(cacheType).Inc()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Refreshes[cacheType]++
+}
+
 // GetHitRate returns the overall cache hit rate
 func (m *CacheMetrics) GetHitRate() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	total := m.TotalHits + m.TotalMisses
 	if total == 0 {
 		return 0
@@ -96,13 +189,39 @@ func (m *CacheMetrics) GetHitRate() float64 {
 	return float64(m.TotalHits) / float64(total)
 }
 
-// CachedAddressProvider wraps an AddressProvider with database caching
+// GetCoalescingStats returns a copy of the coalescing and revalidation counters
+func (m *CacheMetrics) GetCoalescingStats() CoalescingStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := CoalescingStats{
+		Coalesced:   make(map[string]int64, len(m.Coalesced)),
+		StaleServed: make(map[string]int64, len(m.StaleServed)),
+		Refreshes:   make(map[string]int64, len(m.Refreshes)),
+	}
+	for cacheType, count := range m.Coalesced {
+		stats.Coalesced[cacheType] = count
+		stats.TotalCoalesced += count
+	}
+	for cacheType, count := range m.StaleServed {
+		stats.StaleServed[cacheType] = count
+	}
+	for cacheType, count := range m.Refreshes {
+		stats.Refreshes[cacheType] = count
+	}
+	return stats
+}
+
+// CachedAddressProvider wraps an AddressProvider with database caching.
+// Concurrent misses for the same key share one provider call, and entries expired within
+// the stale window are served while a single background refresh updates them.
 type CachedAddressProvider struct {
 	inner      AddressProvider
 	cacheRepo  repository.AddressCacheRepository
 	placesRepo repository.PlacesRepository
 	config     CacheConfig
 	metrics    *CacheMetrics
+	flights    singleflight.Group
+	refreshing sync.Map // cache type + hash of entries being revalidated
 }
 
 // NewCachedAddressProvider creates a cached wrapper around an address provider
@@ -126,6 +245,83 @@ func (c *CachedAddressProvider) GetMetrics() *CacheMetrics {
 	return c.metrics
 }
 
+// lookup returns the cached entry for a key, including one expired within the stale window
+func (c *CachedAddressProvider) lookup(ctx context.Context, cacheType, hash string) *models.AddressCacheEntry {
+	cached, err := c.cacheRepo.GetByHashAllowStale(ctx, cacheType, hash, c.config.StaleWhileRevalidate)
+	if err != nil || cached == nil {
+		return nil
+	}
+
+	// Increment hit count asynchronously
+	go func() {
+		if err := c.cacheRepo.IncrementHits(context.Background(), cached.ID); err != nil {
+			log.Printf("Failed to increment cache hits: %v", err)
+		}
+	}()
+
+	return cached
+}
+
+// coalesce runs fetch once for all concurrent callers of the same cache key. The shared call
+// is detached from the caller's context so one cancelled request does not fail the others.
+func coalesce[T any](ctx context.Context, c *CachedAddressProvider, cacheType, hash string, fetch func(context.Context) (T, error)) (T, error) {
+	leader := false
+	ch := c.flights.DoChan(cacheType+":"+hash, func() (interface{}, error) {
+		leader = true
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.fetchTimeout())
+		defer cancel()
+		return fetch(fetchCtx)
+	})
+
+	var zero T
+	select {
+	case res := <-ch:
+		if !leader {
+			c.metrics.RecordCoalesced(cacheType)
+		}
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		result, _ := res.Val.(T)
+		return result, nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// revalidate refreshes an expired entry in the background; concurrent stale hits start one refresh
+func revalidate[T any](c *CachedAddressProvider, cacheType, hash string, fetch func(context.Context) (T, error)) {
+	refreshKey := cacheType + ":" + hash
+	if _, inFlight := c.refreshing.LoadOrStore(refreshKey, struct{}{}); inFlight {
+		return
+	}
+	c.metrics.RecordRefresh(cacheType)
+
+	go func() {
+		defer c.refreshing.Delete(refreshKey)
+		if _, err := coalesce(context.Background(), c, cacheType, hash, fetch); err != nil {
+			log.Printf("Failed to revalidate %s cache entry: %v", cacheType, err)
+		}
+	}()
+}
+
+// serveCached records a hit on a cached entry and revalidates it when stale
+func serveCached[T any](c *CachedAddressProvider, cacheType, hash string, cached *models.AddressCacheEntry, fetch func(context.Context) (T, error)) {
+	c.metrics.RecordHit(cacheType)
+	if cached.IsExpired() {
+		c.metrics.RecordStale(cacheType)
+		revalidate(c, cacheType, hash, fetch)
+	}
+}
+
+// fetchTimeout returns the timeout of a shared provider call
+func (c *CachedAddressProvider) fetchTimeout() time.Duration {
+	if c.config.FetchTimeout <= 0 {
+		return 10 * time.Second
+	}
+	return c.config.FetchTimeout
+}
+
 // generateHash generates a SHA256 hash of the input
 func generateHash(input string) string {
 	hasher := sha256.New()
@@ -190,47 +386,50 @@ func (c *CachedAddressProvider) Autocomplete(ctx context.Context, input string,
 
 	cacheType := string(models.CacheTypeAutocomplete)
 	key, hash := generateAutocompleteKey(input, opts)
+	fetch := func(ctx context.Context) ([]models.AddressSuggestion, error) {
+		suggestions, err := c.inner.Autocomplete(ctx, input, opts)
+		if err != nil {
+			return nil, err
+		}
+		// Stored before the shared call completes so the next request hits the cache
+		if len(suggestions) > 0 {
+			c.storeAutocompleteCache(key, hash, input, suggestions)
+		}
+		return suggestions, nil
+	}
 
 	// Try cache first
-	cached, err := c.cacheRepo.GetByHash(ctx, cacheType, hash)
-	if err == nil && cached != nil {
-		c.metrics.RecordHit(cacheType)
-
-		// Increment hit count asynchronously
-		go func() {
-			if err := c.cacheRepo.IncrementHits(context.Background(), cached.ID); err != nil {
-				log.Printf("Failed to increment cache hits: %v", err)
-			}
-		}()
-
-		// Deserialize suggestions from JSON
-		var suggestions []models.AddressSuggestion
-		if cached.ResponseJSON != nil {
-			data, _ := json.Marshal(cached.ResponseJSON)
-			if err := json.Unmarshal(data, &suggestions); err == nil {
-				return suggestions, nil
-			}
+	if cached := c.lookup(ctx, cacheType, hash); cached != nil {
+		if suggestions, ok := cacheEntryToSuggestions(cached); ok {
+			serveCached(c, cacheType, hash, cached, fetch)
+			return suggestions, nil
 		}
 	}
 
 	c.metrics.RecordMiss(cacheType)
 
-	// Cache miss - call underlying provider
-	suggestions, err := c.inner.Autocomplete(ctx, input, opts)
+	// Cache miss - call underlying provider once for all concurrent requests
+	return coalesce(ctx, c, cacheType, hash, fetch)
+}
+
+// cacheEntryToSuggestions deserializes cached autocomplete suggestions
+func cacheEntryToSuggestions(entry *models.AddressCacheEntry) ([]models.AddressSuggestion, bool) {
+	if entry.ResponseJSON == nil {
+		return nil, false
+	}
+	data, err := json.Marshal(entry.ResponseJSON["suggestions"])
 	if err != nil {
-		return nil, err
+		return nil, false
 	}
-
-	// Store in cache asynchronously
-	if len(suggestions) > 0 {
-		go c.storeAutocompleteCacheAsync(key, hash, input, suggestions)
+	var suggestions []models.AddressSuggestion
+	if err := json.Unmarshal(data, &suggestions); err != nil || len(suggestions) == 0 {
+		return nil, false
 	}
-
-	return suggestions, nil
+	return suggestions, true
 }
 
-// storeAutocompleteCacheAsync stores autocomplete results in cache
-func (c *CachedAddressProvider) storeAutocompleteCacheAsync(key, hash, input string, suggestions []models.AddressSuggestion) {
+// storeAutocompleteCache stores autocomplete results in cache
+func (c *CachedAddressProvider) storeAutocompleteCache(key, hash, input string, suggestions []models.AddressSuggestion) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -263,44 +462,36 @@ func (c *CachedAddressProvider) Geocode(ctx context.Context, address string) (*m
 
 	cacheType := string(models.CacheTypeGeocode)
 	key, hash := generateGeocodeKey(address)
+	fetch := func(ctx context.Context) (*models.GeocodingResult, error) {
+		result, err := c.inner.Geocode(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			c.storeGeocodeCache(key, hash, address, result)
 
-	// Try cache first
-	cached, err := c.cacheRepo.GetByHash(ctx, cacheType, hash)
-	if err == nil && cached != nil {
-		c.metrics.RecordHit(cacheType)
-
-		go func() {
-			if err := c.cacheRepo.IncrementHits(context.Background(), cached.ID); err != nil {
-				log.Printf("Failed to increment cache hits: %v", err)
+			// Store in places table if enabled
+			if c.config.StorePlaces {
+				go c.storePlaceAsync(result)
 			}
-		}()
+		}
+		return result, nil
+	}
 
+	// Try cache first
+	if cached := c.lookup(ctx, cacheType, hash); cached != nil {
+		serveCached(c, cacheType, hash, cached, fetch)
 		return cacheEntryToGeocodingResult(cached), nil
 	}
 
 	c.metrics.RecordMiss(cacheType)
 
-	// Cache miss - call underlying provider
-	result, err := c.inner.Geocode(ctx, address)
-	if err != nil {
-		return nil, err
-	}
-
-	if result != nil {
-		// Store in cache asynchronously
-		go c.storeGeocodeCacheAsync(key, hash, address, result)
-
-		// Store in places table if enabled
-		if c.config.StorePlaces {
-			go c.storePlaceAsync(result)
-		}
-	}
-
-	return result, nil
+	// Cache miss - call underlying provider once for all concurrent requests
+	return coalesce(ctx, c, cacheType, hash, fetch)
 }
 
-// storeGeocodeCacheAsync stores geocode results in cache
-func (c *CachedAddressProvider) storeGeocodeCacheAsync(key, hash, address string, result *models.GeocodingResult) {
+// storeGeocodeCache stores geocode results in cache
+func (c *CachedAddressProvider) storeGeocodeCache(key, hash, address string, result *models.GeocodingResult) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -349,38 +540,31 @@ func (c *CachedAddressProvider) ReverseGeocode(ctx context.Context, lat, lng flo
 
 	cacheType := string(models.CacheTypeReverse)
 	key, hash := generateReverseGeocodeKey(lat, lng)
+	fetch := func(ctx context.Context) (*models.ReverseGeocodingResult, error) {
+		result, err := c.inner.ReverseGeocode(ctx, lat, lng)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			c.storeReverseGeocodeCache(key, hash, lat, lng, result)
+		}
+		return result, nil
+	}
 
 	// Try cache first
-	cached, err := c.cacheRepo.GetByHash(ctx, cacheType, hash)
-	if err == nil && cached != nil {
-		c.metrics.RecordHit(cacheType)
-
-		go func() {
-			if err := c.cacheRepo.IncrementHits(context.Background(), cached.ID); err != nil {
-				log.Printf("Failed to increment cache hits: %v", err)
-			}
-		}()
-
+	if cached := c.lookup(ctx, cacheType, hash); cached != nil {
+		serveCached(c, cacheType, hash, cached, fetch)
 		return cacheEntryToReverseGeocodingResult(cached), nil
 	}
 
 	c.metrics.RecordMiss(cacheType)
 
-	// Cache miss - call underlying provider
-	result, err := c.inner.ReverseGeocode(ctx, lat, lng)
-	if err != nil {
-		return nil, err
-	}
-
-	if result != nil {
-		go c.storeReverseGeocodeCacheAsync(key, hash, lat, lng, result)
-	}
-
-	return result, nil
+	// Cache miss - call underlying provider once for all concurrent requests
+	return coalesce(ctx, c, cacheType, hash, fetch)
 }
 
-// storeReverseGeocodeCacheAsync stores reverse geocode results in cache
-func (c *CachedAddressProvider) storeReverseGeocodeCacheAsync(key, hash string, lat, lng float64, result *models.ReverseGeocodingResult) {
+// storeReverseGeocodeCache stores reverse geocode results in cache
+func (c *CachedAddressProvider) storeReverseGeocodeCache(key, hash string, lat, lng float64, result *models.ReverseGeocodingResult) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -434,42 +618,35 @@ func (c *CachedAddressProvider) GetPlaceDetails(ctx context.Context, placeID str
 
 	cacheType := string(models.CacheTypePlaceDetails)
 	key, hash := generatePlaceDetailsKey(placeID)
+	fetch := func(ctx context.Context) (*models.GeocodingResult, error) {
+		result, err := c.inner.GetPlaceDetails(ctx, placeID)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			c.storePlaceDetailsCache(key, hash, placeID, result)
 
-	// Try cache first
-	cached, err := c.cacheRepo.GetByHash(ctx, cacheType, hash)
-	if err == nil && cached != nil {
-		c.metrics.RecordHit(cacheType)
-
-		go func() {
-			if err := c.cacheRepo.IncrementHits(context.Background(), cached.ID); err != nil {
-				log.Printf("Failed to increment cache hits: %v", err)
+			if c.config.StorePlaces {
+				go c.storePlaceAsync(result)
 			}
-		}()
+		}
+		return result, nil
+	}
 
+	// Try cache first
+	if cached := c.lookup(ctx, cacheType, hash); cached != nil {
+		serveCached(c, cacheType, hash, cached, fetch)
 		return cacheEntryToGeocodingResult(cached), nil
 	}
 
 	c.metrics.RecordMiss(cacheType)
 
-	// Cache miss - call underlying provider
-	result, err := c.inner.GetPlaceDetails(ctx, placeID)
-	if err != nil {
-		return nil, err
-	}
-
-	if result != nil {
-		go c.storePlaceDetailsCacheAsync(key, hash, placeID, result)
-
-		if c.config.StorePlaces {
-			go c.storePlaceAsync(result)
-		}
-	}
-
-	return result, nil
+	// Cache miss - call underlying provider once for all concurrent requests
+	return coalesce(ctx, c, cacheType, hash, fetch)
 }
 
-// storePlaceDetailsCacheAsync stores place details in cache
-func (c *CachedAddressProvider) storePlaceDetailsCacheAsync(key, hash, placeID string, result *models.GeocodingResult) {
+// storePlaceDetailsCache stores place details in cache
+func (c *CachedAddressProvider) storePlaceDetailsCache(key, hash, placeID string, result *models.GeocodingResult) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"location-service/internal/models"
+)
+
+// MaxDistanceMatrixPoints is the most origins, and the most destinations, per request
+const MaxDistanceMatrixPoints = 25
+
+// earthRadiusKm is the mean Earth radius used by haversine distances
+const earthRadiusKm = 6371.0088
+
+var (
+	// ErrInvalidTravelMode is returned for an unknown distance matrix mode
+	ErrInvalidTravelMode = errors.New("invalid travel mode: must be straight_line, driving, walking or cycling")
+	// ErrTooManyMatrixPoints is returned when a request exceeds the matrix size limits
+	ErrTooManyMatrixPoints = errors.New("too many points for a distance matrix")
+)
+
+// RoutingProvider computes travel distances and durations over a road network
+type RoutingProvider interface {
+	Name() string
+	// MaxCoordinates is the most origins plus destinations one Matrix call accepts
+	MaxCoordinates() int
+	// Matrix returns the distance in meters and duration in seconds from every origin to every
+	// destination; nil entries are unreachable
+	Matrix(ctx context.Context, origins, destinations []models.GeoPoint, mode string) (distances, durations [][]*float64, err error)
+}
+
+// DistanceMatrixService computes origin/destination distance matrices: straight-line
+// distances always, and road distances when a routing provider is configured
+type DistanceMatrixService struct {
+	router RoutingProvider // optional
+}
+
+// NewDistanceMatrixService creates a distance matrix service; router may be nil
+func NewDistanceMatrixService(router RoutingProvider) *DistanceMatrixService {
+	return &DistanceMatrixService{router: router}
+}
+
+// Compute returns the distance from every origin to every destination. Routed modes fall back
+// to straight-line distances (with Fallback set) when no routing provider is configured or the
+// provider fails, so callers always get an answer.
+func (s *DistanceMatrixService) Compute(ctx context.Context, req models.DistanceMatrixRequest) (*models.DistanceMatrixResult, error) {
+	mode := strings.ToLower(strings.TrimSpace(req.Mode))
+	if mode == "" {
+		mode = models.TravelModeStraightLine
+	}
+	switch mode {
+	case models.TravelModeStraightLine, models.TravelModeDriving, models.TravelModeWalking, models.TravelModeCycling:
+	default:
+		return nil, ErrInvalidTravelMode
+	}
+	if len(req.Origins) > MaxDistanceMatrixPoints || len(req.Destinations) > MaxDistanceMatrixPoints {
+		return nil, fmt.Errorf("%w: at most %d origins and %d destinations", ErrTooManyMatrixPoints, MaxDistanceMatrixPoints, MaxDistanceMatrixPoints)
+	}
+
+	result := &models.DistanceMatrixResult{
+		Mode:   mode,
+		Method: models.DistanceMethodHaversine,
+		Rows:   straightLineRows(req.Origins, req.Destinations),
+	}
+	if mode == models.TravelModeStraightLine {
+		setNearest(result.Rows, false)
+		return result, nil
+	}
+
+	if s.router == nil {
+		result.Fallback = true
+		setNearest(result.Rows, false)
+		return result, nil
+	}
+	if limit := s.router.MaxCoordinates(); len(req.Origins)+len(req.Destinations) > limit {
+		return nil, fmt.Errorf("%w: routed matrices allow at most %d origins and destinations combined", ErrTooManyMatrixPoints, limit)
+	}
+
+	distances, durations, err := s.router.Matrix(ctx, req.Origins, req.Destinations, mode)
+	if err != nil {
+		log.Printf("[DistanceMatrix] Routing via %s failed, using straight-line distances: %v", s.router.Name(), err)
+		result.Fallback = true
+		setNearest(result.Rows, false)
+		return result, nil
+	}
+
+	result.Method = models.DistanceMethodRouted
+	result.Provider = s.router.Name()
+	for i := range result.Rows {
+		for j := range result.Rows[i].Elements {
+			element := &result.Rows[i].Elements[j]
+			distance := matrixValue(distances, i, j)
+			element.Reachable = distance != nil
+			if distance != nil {
+				km := *distance / 1000
+				element.RouteDistanceKm = &km
+			}
+			element.DurationSeconds = matrixValue(durations, i, j)
+		}
+	}
+	setNearest(result.Rows, true)
+	return result, nil
+}
+
+// HaversineKm returns the great-circle distance between two points in kilometers
+func HaversineKm(a, b models.GeoPoint) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// straightLineRows builds the matrix with haversine distances; every pair is reachable
+func straightLineRows(origins, destinations []models.GeoPoint) []models.DistanceMatrixRow {
+	rows := make([]models.DistanceMatrixRow, len(origins))
+	for i, origin := range origins {
+		elements := make([]models.DistanceMatrixElement, len(destinations))
+		for j, destination := range destinations {
+			elements[j] = models.DistanceMatrixElement{
+				DestinationIndex: j,
+				DestinationID:    destination.ID,
+				StraightLineKm:   math.Round(HaversineKm(origin, destination)*1000) / 1000,
+				Reachable:        true,
+			}
+		}
+		rows[i] = models.DistanceMatrixRow{OriginIndex: i, OriginID: origin.ID, Elements: elements}
+	}
+	return rows
+}
+
+// setNearest marks each row's closest reachable destination, by route distance when routed
+func setNearest(rows []models.DistanceMatrixRow, routed bool) {
+	for i := range rows {
+		best := -1
+		bestKm := math.Inf(1)
+		for j, element := range rows[i].Elements {
+			km := element.StraightLineKm
+			if routed {
+				if element.RouteDistanceKm == nil {
+					continue
+				}
+				km = *element.RouteDistanceKm
+			}
+			if km < bestKm {
+				best, bestKm = j, km
+			}
+		}
+		if best >= 0 {
+			nearest := best
+			rows[i].Nearest = &nearest
+		}
+	}
+}
+
+func matrixValue(values [][]*float64, i, j int) *float64 {
+	if i >= len(values) || j >= len(values[i]) {
+		return nil
+	}
+	return values[i][j]
+}
+
+// ==================== MAPBOX ROUTING ====================
+
+// mapboxMatrixMaxCoordinates is the Matrix API's coordinate limit for non-traffic profiles
+const mapboxMatrixMaxCoordinates = 25
+
+// mapboxProfiles maps travel modes to Mapbox routing profiles
+var mapboxProfiles = map[string]string{
+	models.TravelModeDriving: "driving",
+	models.TravelModeWalking: "walking",
+	models.TravelModeCycling: "cycling",
+}
+
+// MapboxRoutingProvider implements RoutingProvider using the Mapbox Matrix API
+type MapboxRoutingProvider struct {
+	accessToken string
+	httpClient  *http.Client
+	baseURL     string
+}
+
+// NewMapboxRoutingProvider creates a new Mapbox routing provider
+func NewMapboxRoutingProvider(accessToken string, httpClient *http.Client) *MapboxRoutingProvider {
+	return &MapboxRoutingProvider{
+		accessToken: accessToken,
+		httpClient:  httpClient,
+		baseURL:     "https://api.mapbox.com/directions-matrix/v1/mapbox",
+	}
+}
+
+// Name implements RoutingProvider
+func (m *MapboxRoutingProvider) Name() string {
+	return "mapbox"
+}
+
+// MaxCoordinates implements RoutingProvider
+func (m *MapboxRoutingProvider) MaxCoordinates() int {
+	return mapboxMatrixMaxCoordinates
+}
+
+// Matrix implements RoutingProvider
+func (m *MapboxRoutingProvider) Matrix(ctx context.Context, origins, destinations []models.GeoPoint, mode string) ([][]*float64, [][]*float64, error) {
+	profile, ok := mapboxProfiles[mode]
+	if !ok {
+		return nil, nil, ErrInvalidTravelMode
+	}
+
+	coordinates := make([]string, 0, len(origins)+len(destinations))
+	sources := make([]string, 0, len(origins))
+	targets := make([]string, 0, len(destinations))
+	for i, point := range origins {
+		coordinates = append(coordinates, fmt.Sprintf("%f,%f", point.Longitude, point.Latitude))
+		sources = append(sources, fmt.Sprint(i))
+	}
+	for i, point := range destinations {
+		coordinates = append(coordinates, fmt.Sprintf("%f,%f", point.Longitude, point.Latitude))
+		targets = append(targets, fmt.Sprint(len(origins)+i))
+	}
+
+	params := url.Values{}
+	params.Set("access_token", m.accessToken)
+	params.Set("annotations", "distance,duration")
+	params.Set("sources", strings.Join(sources, ";"))
+	params.Set("destinations", strings.Join(targets, ";"))
+	reqURL := fmt.Sprintf("%s/%s/%s?%s", m.baseURL, profile, strings.Join(coordinates, ";"), params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to call Mapbox API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result struct {
+		Code      string       `json:"code"`
+		Message   string       `json:"message"`
+		Distances [][]*float64 `json:"distances"`
+		Durations [][]*float64 `json:"durations"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.Code != "Ok" {
+		return nil, nil, fmt.Errorf("Mapbox Matrix API error (status %d, code %s): %s", resp.StatusCode, result.Code, result.Message)
+	}
+
+	return result.Distances, result.Durations, nil
+}
@@ -0,0 +1,455 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"location-service/internal/models"
+)
+
+var (
+	// ErrGeoQuotaExceeded is returned when a tenant has used its daily or monthly geocoding calls
+	ErrGeoQuotaExceeded = errors.New("geocoding quota exceeded")
+	// ErrGeoBudgetExceeded is returned when a tenant has spent its monthly geocoding provider budget
+	ErrGeoBudgetExceeded = errors.New("geocoding provider budget exceeded")
+	// ErrGeoQuotaUnavailable is returned for usage reports when quotas are disabled or Redis is unavailable
+	ErrGeoQuotaUnavailable = errors.New("geocoding usage tracking is not available")
+	// ErrInvalidUsageMonth is returned for a usage month that is not YYYY-MM
+	ErrInvalidUsageMonth = errors.New("month must be in YYYY-MM format")
+)
+
+const (
+	geoUsageKeyPrefix = "tesseract:location:geo-usage:"
+	// geoUsageDailyTTL keeps daily counters a little past the day they count
+	geoUsageDailyTTL = 72 * time.Hour
+	// geoUsageMonthlyTTL keeps monthly counters for a year of usage reports
+	geoUsageMonthlyTTL = 400 * 24 * time.Hour
+
+	geoUsageFieldCalls = "calls"
+	geoUsageFieldCost  = "cost"
+	geoUsageCallPrefix = "calls:" // per-provider call counts
+)
+
+// DefaultGeoProviderCosts is the cost per call of each geocoding provider, in USD; providers not
+// listed are free
+var DefaultGeoProviderCosts = map[string]float64{
+	"google": 0.005,
+	"mapbox": 0.00075,
+}
+
+var geoQuotaRejections = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "tesseract",
+		Subsystem: "location",
+		Name:      "geo_quota_rejections_total",
+		Help:      "Total number of geocoding requests rejected by per-tenant quotas",
+	},
+	[]string{"reason"}, // reason: daily_quota, monthly_quota, monthly_budget
+)
+
+// GeoTenantLimits are the geocoding limits of one tenant; zero means unlimited
+type GeoTenantLimits struct {
+	DailyLimit    int64   `json:"dailyLimit"`
+	MonthlyLimit  int64   `json:"monthlyLimit"`
+	MonthlyBudget float64 `json:"monthlyBudget"`
+}
+
+// GeoQuotaConfig configures per-tenant geocoding quotas
+type GeoQuotaConfig struct {
+	Enabled       bool
+	Defaults      GeoTenantLimits
+	Tenants       map[string]GeoTenantLimits // Overrides keyed by tenant ID
+	ProviderCosts map[string]float64         // Cost per call by provider name
+}
+
+// GeoProviderUsage is the calls and spend of one provider
+type GeoProviderUsage struct {
+	Provider string  `json:"provider"`
+	Calls    int64   `json:"calls"`
+	Cost     float64 `json:"cost"`
+}
+
+// GeoPeriodUsage is a tenant's usage over one day or month
+type GeoPeriodUsage struct {
+	Period    string             `json:"period"` // YYYY-MM-DD or YYYY-MM
+	Calls     int64              `json:"calls"`
+	Cost      float64            `json:"cost"`
+	Providers []GeoProviderUsage `json:"providers"`
+}
+
+// GeoTenantUsage is a tenant's geocoding usage against its limits
+type GeoTenantUsage struct {
+	TenantID string          `json:"tenantId"`
+	Limits   GeoTenantLimits `json:"limits"`
+	Today    *GeoPeriodUsage `json:"today,omitempty"` // Only for the current month
+	Month    GeoPeriodUsage  `json:"month"`
+	Exceeded []string        `json:"exceeded,omitempty"` // daily_quota, monthly_quota, monthly_budget
+}
+
+// GeoQuotaStatus explains a rejected request
+type GeoQuotaStatus struct {
+	Reason  string    `json:"reason"`
+	Limit   float64   `json:"limit"`
+	Used    float64   `json:"used"`
+	ResetAt time.Time `json:"resetAt"`
+}
+
+// GeoQuotaService meters external geocoding provider calls per tenant in Redis and enforces daily
+// and monthly call quotas and a monthly provider budget. Cache hits never reach a provider, so only
+// real provider calls count.
+type GeoQuotaService struct {
+	redis  *redis.Client
+	config GeoQuotaConfig
+	now    func() time.Time
+}
+
+// NewGeoQuotaService creates a quota service; without Redis every request is allowed and nothing is metered
+func NewGeoQuotaService(redisClient *redis.Client, config GeoQuotaConfig) *GeoQuotaService {
+	if config.ProviderCosts == nil {
+		config.ProviderCosts = DefaultGeoProviderCosts
+	}
+	return &GeoQuotaService{
+		redis:  redisClient,
+		config: config,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Enabled reports whether usage is metered
+func (s *GeoQuotaService) Enabled() bool {
+	return s != nil && s.config.Enabled && s.redis != nil
+}
+
+// Limits returns the limits of a tenant
+func (s *GeoQuotaService) Limits(tenantID string) GeoTenantLimits {
+	if limits, ok := s.config.Tenants[tenantID]; ok {
+		return limits
+	}
+	return s.config.Defaults
+}
+
+// Check returns ErrGeoQuotaExceeded or ErrGeoBudgetExceeded with the limit that was reached. Redis
+// errors allow the request: quotas must not take geocoding down.
+func (s *GeoQuotaService) Check(ctx context.Context, tenantID string) (*GeoQuotaStatus, error) {
+	if !s.Enabled() || tenantID == "" {
+		return nil, nil
+	}
+	limits := s.Limits(tenantID)
+	if limits.DailyLimit <= 0 && limits.MonthlyLimit <= 0 && limits.MonthlyBudget <= 0 {
+		return nil, nil
+	}
+
+	now := s.now()
+	pipe := s.redis.Pipeline()
+	daily := pipe.HGet(ctx, s.dailyKey(tenantID, now), geoUsageFieldCalls)
+	monthly := pipe.HMGet(ctx, s.monthlyKey(tenantID, now.Format("2006-01")), geoUsageFieldCalls, geoUsageFieldCost)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("WARNING: Failed to check geocoding quota for tenant %s: %v", tenantID, err)
+		return nil, nil
+	}
+
+	dailyCalls, _ := daily.Int64()
+	var monthlyCalls int64
+	var monthlyCost float64
+	if values, err := monthly.Result(); err == nil {
+		monthlyCalls = parseUsageInt(values[0])
+		monthlyCost = parseUsageFloat(values[1])
+	}
+
+	nextDay := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	nextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	switch {
+	case limits.DailyLimit > 0 && dailyCalls >= limits.DailyLimit:
+		return s.reject("daily_quota", float64(limits.DailyLimit), float64(dailyCalls), nextDay, ErrGeoQuotaExceeded)
+	case limits.MonthlyLimit > 0 && monthlyCalls >= limits.MonthlyLimit:
+		return s.reject("monthly_quota", float64(limits.MonthlyLimit), float64(monthlyCalls), nextMonth, ErrGeoQuotaExceeded)
+	case limits.MonthlyBudget > 0 && monthlyCost >= limits.MonthlyBudget:
+		return s.reject("monthly_budget", limits.MonthlyBudget, monthlyCost, nextMonth, ErrGeoBudgetExceeded)
+	}
+	return nil, nil
+}
+
+func (s *GeoQuotaService) reject(reason string, limit, used float64, resetAt time.Time, err error) (*GeoQuotaStatus, error) {
+	geoQuotaRejections.WithLabelValues(reason).Inc()
+	return &GeoQuotaStatus{Reason: reason, Limit: limit, Used: used, ResetAt: resetAt}, err
+}
+
+// Record counts one call to a provider against the tenant's daily and monthly usage
+func (s *GeoQuotaService) Record(ctx context.Context, tenantID, provider string) {
+	if !s.Enabled() || tenantID == "" {
+		return
+	}
+	cost := s.config.ProviderCosts[provider]
+	now := s.now()
+	month := now.Format("2006-01")
+
+	// Count even if the request was cancelled: the provider call was made
+	ctx = context.WithoutCancel(ctx)
+	pipe := s.redis.Pipeline()
+	for _, key := range []string{s.dailyKey(tenantID, now), s.monthlyKey(tenantID, month)} {
+		pipe.HIncrBy(ctx, key, geoUsageFieldCalls, 1)
+		pipe.HIncrBy(ctx, key, geoUsageCallPrefix+provider, 1)
+		if cost > 0 {
+			pipe.HIncrByFloat(ctx, key, geoUsageFieldCost, cost)
+		}
+	}
+	pipe.Expire(ctx, s.dailyKey(tenantID, now), geoUsageDailyTTL)
+	pipe.Expire(ctx, s.monthlyKey(tenantID, month), geoUsageMonthlyTTL)
+	pipe.SAdd(ctx, s.tenantsKey(month), tenantID)
+	pipe.Expire(ctx, s.tenantsKey(month), geoUsageMonthlyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("WARNING: Failed to record geocoding usage for tenant %s: %v", tenantID, err)
+	}
+}
+
+// Usage returns a tenant's usage for a month (YYYY-MM, default the current month)
+func (s *GeoQuotaService) Usage(ctx context.Context, tenantID, month string) (*GeoTenantUsage, error) {
+	if !s.Enabled() {
+		return nil, ErrGeoQuotaUnavailable
+	}
+	month, err := s.usageMonth(month)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &GeoTenantUsage{TenantID: tenantID, Limits: s.Limits(tenantID)}
+	monthly, err := s.periodUsage(ctx, s.monthlyKey(tenantID, month), month)
+	if err != nil {
+		return nil, err
+	}
+	usage.Month = *monthly
+
+	if now := s.now(); month == now.Format("2006-01") {
+		today, err := s.periodUsage(ctx, s.dailyKey(tenantID, now), now.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		usage.Today = today
+		if usage.Limits.DailyLimit > 0 && today.Calls >= usage.Limits.DailyLimit {
+			usage.Exceeded = append(usage.Exceeded, "daily_quota")
+		}
+	}
+	if usage.Limits.MonthlyLimit > 0 && usage.Month.Calls >= usage.Limits.MonthlyLimit {
+		usage.Exceeded = append(usage.Exceeded, "monthly_quota")
+	}
+	if usage.Limits.MonthlyBudget > 0 && usage.Month.Cost >= usage.Limits.MonthlyBudget {
+		usage.Exceeded = append(usage.Exceeded, "monthly_budget")
+	}
+	return usage, nil
+}
+
+// ListUsage returns the usage of every tenant that made provider calls in a month, highest spend first
+func (s *GeoQuotaService) ListUsage(ctx context.Context, month string) ([]*GeoTenantUsage, error) {
+	if !s.Enabled() {
+		return nil, ErrGeoQuotaUnavailable
+	}
+	month, err := s.usageMonth(month)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantIDs, err := s.redis.SMembers(ctx, s.tenantsKey(month)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants with geocoding usage: %w", err)
+	}
+
+	usages := make([]*GeoTenantUsage, 0, len(tenantIDs))
+	for _, tenantID := range tenantIDs {
+		usage, err := s.Usage(ctx, tenantID, month)
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, usage)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Month.Cost != usages[j].Month.Cost {
+			return usages[i].Month.Cost > usages[j].Month.Cost
+		}
+		if usages[i].Month.Calls != usages[j].Month.Calls {
+			return usages[i].Month.Calls > usages[j].Month.Calls
+		}
+		return usages[i].TenantID < usages[j].TenantID
+	})
+	return usages, nil
+}
+
+// periodUsage reads one usage hash
+func (s *GeoQuotaService) periodUsage(ctx context.Context, key, period string) (*GeoPeriodUsage, error) {
+	fields, err := s.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read geocoding usage: %w", err)
+	}
+
+	usage := &GeoPeriodUsage{
+		Period:    period,
+		Calls:     parseUsageInt(fields[geoUsageFieldCalls]),
+		Cost:      roundCost(parseUsageFloat(fields[geoUsageFieldCost])),
+		Providers: []GeoProviderUsage{},
+	}
+	for field, value := range fields {
+		provider, ok := strings.CutPrefix(field, geoUsageCallPrefix)
+		if !ok {
+			continue
+		}
+		calls := parseUsageInt(value)
+		usage.Providers = append(usage.Providers, GeoProviderUsage{
+			Provider: provider,
+			Calls:    calls,
+			Cost:     roundCost(float64(calls) * s.config.ProviderCosts[provider]),
+		})
+	}
+	sort.Slice(usage.Providers, func(i, j int) bool {
+		return usage.Providers[i].Provider < usage.Providers[j].Provider
+	})
+	return usage, nil
+}
+
+func (s *GeoQuotaService) usageMonth(month string) (string, error) {
+	if month == "" {
+		return s.now().Format("2006-01"), nil
+	}
+	if _, err := time.Parse("2006-01", month); err != nil {
+		return "", ErrInvalidUsageMonth
+	}
+	return month, nil
+}
+
+func (s *GeoQuotaService) dailyKey(tenantID string, day time.Time) string {
+	return geoUsageKeyPrefix + tenantID + ":" + day.Format("2006-01-02")
+}
+
+func (s *GeoQuotaService) monthlyKey(tenantID, month string) string {
+	return geoUsageKeyPrefix + tenantID + ":" + month
+}
+
+func (s *GeoQuotaService) tenantsKey(month string) string {
+	return geoUsageKeyPrefix + "tenants:" + month
+}
+
+// ParseGeoProviderCosts parses "google=0.005,mapbox=0.00075" into costs per call
+func ParseGeoProviderCosts(value string) (map[string]float64, error) {
+	costs := make(map[string]float64)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		provider, costStr, ok := strings.Cut(entry, "=")
+		cost, err := strconv.ParseFloat(strings.TrimSpace(costStr), 64)
+		if !ok || err != nil || cost < 0 {
+			return nil, fmt.Errorf("invalid provider cost %q", entry)
+		}
+		costs[strings.ToLower(strings.TrimSpace(provider))] = cost
+	}
+	return costs, nil
+}
+
+// ParseGeoTenantLimits parses per-tenant limit overrides: {"<tenantId>": {"dailyLimit": 1000, ...}}
+func ParseGeoTenantLimits(value string) (map[string]GeoTenantLimits, error) {
+	tenants := make(map[string]GeoTenantLimits)
+	if strings.TrimSpace(value) == "" {
+		return tenants, nil
+	}
+	if err := json.Unmarshal([]byte(value), &tenants); err != nil {
+		return nil, fmt.Errorf("invalid tenant geocoding limits: %w", err)
+	}
+	return tenants, nil
+}
+
+// roundCost drops float noise from accumulated per-call costs
+func roundCost(cost float64) float64 {
+	return math.Round(cost*1e6) / 1e6
+}
+
+func parseUsageInt(value interface{}) int64 {
+	s, _ := value.(string)
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+func parseUsageFloat(value interface{}) float64 {
+	s, _ := value.(string)
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// ==================== METERED PROVIDER ====================
+
+// MeteredAddressProvider records each call to an external provider against the tenant in the
+// request context. It wraps the individual providers of a failover chain, so a request that fails
+// over is charged for every provider it reached.
+type MeteredAddressProvider struct {
+	provider AddressProvider
+	name     string
+	quota    *GeoQuotaService
+}
+
+// NewMeteredAddressProvider wraps a provider so its calls are metered
+func NewMeteredAddressProvider(provider AddressProvider, name string, quota *GeoQuotaService) *MeteredAddressProvider {
+	return &MeteredAddressProvider{
+		provider: provider,
+		name:     name,
+		quota:    quota,
+	}
+}
+
+// Autocomplete calls the provider and records the call
+func (m *MeteredAddressProvider) Autocomplete(ctx context.Context, input string, opts AutocompleteOptions) ([]models.AddressSuggestion, error) {
+	defer m.record(ctx)
+	return m.provider.Autocomplete(ctx, input, opts)
+}
+
+// Geocode calls the provider and records the call
+func (m *MeteredAddressProvider) Geocode(ctx context.Context, address string) (*models.GeocodingResult, error) {
+	defer m.record(ctx)
+	return m.provider.Geocode(ctx, address)
+}
+
+// ReverseGeocode calls the provider and records the call
+func (m *MeteredAddressProvider) ReverseGeocode(ctx context.Context, lat, lng float64) (*models.ReverseGeocodingResult, error) {
+	defer m.record(ctx)
+	return m.provider.ReverseGeocode(ctx, lat, lng)
+}
+
+// GetPlaceDetails calls the provider and records the call
+func (m *MeteredAddressProvider) GetPlaceDetails(ctx context.Context, placeID string) (*models.GeocodingResult, error) {
+	defer m.record(ctx)
+	return m.provider.GetPlaceDetails(ctx, placeID)
+}
+
+// ValidateAddress calls the provider and records the call
+func (m *MeteredAddressProvider) ValidateAddress(ctx context.Context, address string) (*models.AddressValidationResult, error) {
+	defer m.record(ctx)
+	return m.provider.ValidateAddress(ctx, address)
+}
+
+// record counts the call whether or not it succeeded: providers bill failed requests too
+func (m *MeteredAddressProvider) record(ctx context.Context) {
+	m.quota.Record(ctx, GeoTenantFromContext(ctx), m.name)
+}
+
+// ==================== TENANT CONTEXT ====================
+
+type geoTenantContextKey struct{}
+
+// WithGeoTenant returns a context whose provider calls are metered against a tenant
+func WithGeoTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, geoTenantContextKey{}, tenantID)
+}
+
+// GeoTenantFromContext returns the tenant provider calls are metered against ("" if none)
+func GeoTenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(geoTenantContextKey{}).(string)
+	return tenantID
+}
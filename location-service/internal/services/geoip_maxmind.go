@@ -0,0 +1,350 @@
+package services
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// MaxGeoIPDatabaseSize caps uploaded and downloaded GeoIP databases (GeoLite2-City is ~70 MB)
+const MaxGeoIPDatabaseSize = 256 << 20
+
+// maxMindEditionID is the database downloaded by Refresh
+const maxMindEditionID = "GeoLite2-City"
+
+var (
+	// ErrGeoIPDatabaseNotLoaded is returned by lookups before a database is installed
+	ErrGeoIPDatabaseNotLoaded = errors.New("no GeoIP database loaded")
+	// ErrInvalidGeoIPDatabase is returned for uploads that are not a City or Country MMDB
+	ErrInvalidGeoIPDatabase = errors.New("invalid GeoIP database: expected a MaxMind City or Country .mmdb or .tar.gz")
+	// ErrGeoIPDownloadNotConfigured is returned by Refresh without MaxMind credentials
+	ErrGeoIPDownloadNotConfigured = errors.New("MAXMIND_ACCOUNT_ID and MAXMIND_LICENSE_KEY are required to download the GeoIP database")
+)
+
+// GeoIPDatabaseInfo describes the loaded MaxMind database
+type GeoIPDatabaseInfo struct {
+	Loaded       bool       `json:"loaded"`
+	Path         string     `json:"path"`
+	DatabaseType string     `json:"database_type,omitempty"`
+	BuildDate    *time.Time `json:"build_date,omitempty"`
+	LoadedAt     *time.Time `json:"loaded_at,omitempty"`
+	SizeBytes    int64      `json:"size_bytes,omitempty"`
+}
+
+// mmdbRecord is the subset of a GeoIP2/GeoLite2 City or Country record we use
+type mmdbRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+		TimeZone  string   `maxminddb:"time_zone"`
+	} `maxminddb:"location"`
+	Postal struct {
+		Code string `maxminddb:"code"`
+	} `maxminddb:"postal"`
+	Subdivisions []struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+}
+
+// MaxMindProvider implements GeoIPProvider with a local MaxMind GeoLite2/GeoIP2 database.
+// The database can be replaced while serving: Install and Refresh write it atomically to the
+// configured path, and replicas sharing that path reload it when its modification time changes.
+type MaxMindProvider struct {
+	path       string
+	accountID  string
+	licenseKey string
+	httpClient *http.Client
+
+	mu       sync.RWMutex
+	reader   *maxminddb.Reader
+	modTime  time.Time
+	loadedAt time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMaxMindProvider creates a MaxMind provider for the database at path. The account ID and
+// license key are only needed to download the database.
+func NewMaxMindProvider(path, accountID, licenseKey string, httpClient *http.Client) *MaxMindProvider {
+	return &MaxMindProvider{
+		path:       path,
+		accountID:  accountID,
+		licenseKey: licenseKey,
+		httpClient: httpClient,
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Name implements GeoIPProvider
+func (m *MaxMindProvider) Name() string {
+	return "maxmind"
+}
+
+// Lookup implements GeoIPProvider
+func (m *MaxMindProvider) Lookup(ctx context.Context, ip string) (*LocationData, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("invalid IP address %q", ip)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.reader == nil {
+		return nil, ErrGeoIPDatabaseNotLoaded
+	}
+
+	var record mmdbRecord
+	_, found, err := m.reader.LookupNetwork(parsed, &record)
+	if err != nil {
+		return nil, fmt.Errorf("MaxMind lookup failed: %w", err)
+	}
+	if !found || record.Country.ISOCode == "" {
+		return nil, ErrIPNotFound
+	}
+
+	regionCode, regionName := "", ""
+	if len(record.Subdivisions) > 0 {
+		regionCode = record.Subdivisions[0].ISOCode
+		regionName = record.Subdivisions[0].Names["en"]
+	}
+	return newLocationData(ip, record.Country.ISOCode, record.Country.Names["en"], regionCode, regionName,
+		record.City.Names["en"], record.Postal.Code, record.Location.TimeZone,
+		record.Location.Latitude, record.Location.Longitude, m.Name()), nil
+}
+
+// Load opens the database at the configured path. A missing file is not an error: the
+// provider stays unavailable until a database is installed.
+func (m *MaxMindProvider) Load() error {
+	info, err := os.Stat(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return m.open(info.ModTime())
+}
+
+// Install validates and installs a database: a raw .mmdb, or the .tar.gz MaxMind distributes
+func (m *MaxMindProvider) Install(data []byte) (*GeoIPDatabaseInfo, error) {
+	mmdb, err := extractMMDB(data)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := maxminddb.FromBytes(mmdb)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeoIPDatabase, err)
+	}
+	dbType := reader.Metadata.DatabaseType
+	if !strings.Contains(dbType, "City") && !strings.Contains(dbType, "Country") {
+		return nil, fmt.Errorf("%w: database type %q", ErrInvalidGeoIPDatabase, dbType)
+	}
+
+	// Write next to the target and rename so readers never see a partial file
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create GeoIP database directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), ".geoip-*.mmdb")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write GeoIP database: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(mmdb); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write GeoIP database: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write GeoIP database: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.path); err != nil {
+		return nil, fmt.Errorf("failed to install GeoIP database: %w", err)
+	}
+
+	info, err := os.Stat(m.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.open(info.ModTime()); err != nil {
+		return nil, err
+	}
+	log.Printf("[GeoIP] Installed %s database (%d bytes)", dbType, len(mmdb))
+	status := m.Info()
+	return &status, nil
+}
+
+// Refresh downloads the latest GeoLite2-City database from MaxMind and installs it
+func (m *MaxMindProvider) Refresh(ctx context.Context) (*GeoIPDatabaseInfo, error) {
+	if m.accountID == "" || m.licenseKey == "" {
+		return nil, ErrGeoIPDownloadNotConfigured
+	}
+
+	url := fmt.Sprintf("https://download.maxmind.com/geoip/databases/%s/download?suffix=tar.gz", maxMindEditionID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(m.accountID, m.licenseKey)
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download GeoIP database: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("MaxMind download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxGeoIPDatabaseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download GeoIP database: %w", err)
+	}
+	if len(data) > MaxGeoIPDatabaseSize {
+		return nil, fmt.Errorf("%w: download exceeds %d bytes", ErrInvalidGeoIPDatabase, MaxGeoIPDatabaseSize)
+	}
+	return m.Install(data)
+}
+
+// Info describes the loaded database
+func (m *MaxMindProvider) Info() GeoIPDatabaseInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	info := GeoIPDatabaseInfo{Path: m.path, Loaded: m.reader != nil}
+	if m.reader == nil {
+		return info
+	}
+	buildDate := time.Unix(int64(m.reader.Metadata.BuildEpoch), 0).UTC()
+	loadedAt := m.loadedAt
+	info.DatabaseType = m.reader.Metadata.DatabaseType
+	info.BuildDate = &buildDate
+	info.LoadedAt = &loadedAt
+	if stat, err := os.Stat(m.path); err == nil {
+		info.SizeBytes = stat.Size()
+	}
+	return info
+}
+
+// Start reloads the database whenever the file at the configured path changes, so an
+// install on one replica reaches the others through a shared volume
+func (m *MaxMindProvider) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.reloadIfChanged()
+			case <-m.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops watching the database file and closes the database
+func (m *MaxMindProvider) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.reader != nil {
+			m.reader.Close()
+			m.reader = nil
+		}
+	})
+}
+
+func (m *MaxMindProvider) reloadIfChanged() {
+	info, err := os.Stat(m.path)
+	if err != nil {
+		return
+	}
+	m.mu.RLock()
+	changed := !info.ModTime().Equal(m.modTime)
+	m.mu.RUnlock()
+	if !changed {
+		return
+	}
+	if err := m.open(info.ModTime()); err != nil {
+		log.Printf("[GeoIP] Failed to reload database %s: %v", m.path, err)
+		return
+	}
+	log.Printf("[GeoIP] Reloaded changed database %s", m.path)
+}
+
+// open memory-maps the database file and swaps it in, closing the previous one
+func (m *MaxMindProvider) open(modTime time.Time) error {
+	reader, err := maxminddb.Open(m.path)
+	if err != nil {
+		return fmt.Errorf("failed to open GeoIP database %s: %w", m.path, err)
+	}
+
+	m.mu.Lock()
+	previous := m.reader
+	m.reader = reader
+	m.modTime = modTime
+	m.loadedAt = time.Now().UTC()
+	m.mu.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+	return nil
+}
+
+// extractMMDB returns the .mmdb file from a MaxMind .tar.gz, or data itself when it is not gzipped
+func extractMMDB(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeoIPDatabase, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: archive contains no .mmdb file", ErrInvalidGeoIPDatabase)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeoIPDatabase, err)
+		}
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(header.Name, ".mmdb") {
+			continue
+		}
+		mmdb, err := io.ReadAll(io.LimitReader(tr, MaxGeoIPDatabaseSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeoIPDatabase, err)
+		}
+		if len(mmdb) > MaxGeoIPDatabaseSize {
+			return nil, fmt.Errorf("%w: database exceeds %d bytes", ErrInvalidGeoIPDatabase, MaxGeoIPDatabaseSize)
+		}
+		return mmdb, nil
+	}
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ErrIPNotFound is returned by a provider that has no location for an IP. It is a valid
+// answer rather than a provider failure, so it does not affect provider health.
+var ErrIPNotFound = errors.New("no location found for IP")
+
+// GeoIPProvider resolves an IP address to a location
+type GeoIPProvider interface {
+	// Name identifies the provider in LocationData.Source, metrics and status
+	Name() string
+	// Lookup returns the location of a public IP, ErrIPNotFound when it has none
+	Lookup(ctx context.Context, ip string) (*LocationData, error)
+}
+
+var (
+	geoIPLookups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tesseract",
+			Subsystem: "location",
+			Name:      "geoip_lookups_total",
+			Help:      "Total number of IP geolocation lookups by provider and result",
+		},
+		[]string{"provider", "result"}, // result: success, not_found, unavailable, error, skipped, fallback
+	)
+
+	geoIPLookupDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tesseract",
+			Subsystem: "location",
+			Name:      "geoip_lookup_duration_seconds",
+			Help:      "Duration of IP geolocation lookups by provider",
+			Buckets:   []float64{.0005, .001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		},
+		[]string{"provider"},
+	)
+
+	geoIPProviderHealthy = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tesseract",
+			Subsystem: "location",
+			Name:      "geoip_provider_healthy",
+			Help:      "Whether an IP geolocation provider is in service (1) or cooling down after failures (0)",
+		},
+		[]string{"provider"},
+	)
+)
+
+// IPAPIProvider uses ip-api.com (free, 45 req/min, HTTP only)
+type IPAPIProvider struct {
+	httpClient *http.Client
+}
+
+// NewIPAPIProvider creates an ip-api.com provider
+func NewIPAPIProvider(httpClient *http.Client) *IPAPIProvider {
+	return &IPAPIProvider{httpClient: httpClient}
+}
+
+// Name implements GeoIPProvider
+func (p *IPAPIProvider) Name() string {
+	return "ip-api"
+}
+
+// Lookup implements GeoIPProvider
+func (p *IPAPIProvider) Lookup(ctx context.Context, ip string) (*LocationData, error) {
+	endpoint := fmt.Sprintf("http://ip-api.com/json/%s?fields=status,message,country,countryCode,region,regionName,city,zip,lat,lon,timezone,isp,query", url.PathEscape(ip))
+
+	var apiResp ipAPIResponse
+	if err := getGeoIPJSON(ctx, p.httpClient, endpoint, p.Name(), &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Status != "success" {
+		// ip-api reports reserved and unknown ranges as a failed lookup
+		if apiResp.Message == "reserved range" || apiResp.Message == "private range" || apiResp.Message == "invalid query" {
+			return nil, ErrIPNotFound
+		}
+		return nil, fmt.Errorf("ip-api lookup failed: %s", apiResp.Message)
+	}
+
+	return newLocationData(apiResp.Query, apiResp.CountryCode, apiResp.Country, apiResp.Region, apiResp.RegionName,
+		apiResp.City, apiResp.Zip, apiResp.Timezone, &apiResp.Lat, &apiResp.Lon, p.Name()), nil
+}
+
+// ipapiCoResponse represents the response from ipapi.co
+type ipapiCoResponse struct {
+	IP          string   `json:"ip"`
+	Error       bool     `json:"error"`
+	Reason      string   `json:"reason"`
+	Reserved    bool     `json:"reserved"`
+	City        string   `json:"city"`
+	Region      string   `json:"region"`
+	RegionCode  string   `json:"region_code"`
+	CountryCode string   `json:"country_code"`
+	CountryName string   `json:"country_name"`
+	Postal      string   `json:"postal"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	Timezone    string   `json:"timezone"`
+}
+
+// IPAPICoProvider uses ipapi.co; the API key is optional and lifts the free rate limit
+type IPAPICoProvider struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewIPAPICoProvider creates an ipapi.co provider
+func NewIPAPICoProvider(apiKey string, httpClient *http.Client) *IPAPICoProvider {
+	return &IPAPICoProvider{apiKey: apiKey, httpClient: httpClient}
+}
+
+// Name implements GeoIPProvider
+func (p *IPAPICoProvider) Name() string {
+	return "ipapi"
+}
+
+// Lookup implements GeoIPProvider
+func (p *IPAPICoProvider) Lookup(ctx context.Context, ip string) (*LocationData, error) {
+	endpoint := fmt.Sprintf("https://ipapi.co/%s/json/", url.PathEscape(ip))
+	if p.apiKey != "" {
+		endpoint += "?key=" + url.QueryEscape(p.apiKey)
+	}
+
+	var apiResp ipapiCoResponse
+	if err := getGeoIPJSON(ctx, p.httpClient, endpoint, p.Name(), &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Reserved {
+		return nil, ErrIPNotFound
+	}
+	if apiResp.Error {
+		return nil, fmt.Errorf("ipapi lookup failed: %s", apiResp.Reason)
+	}
+	if apiResp.CountryCode == "" {
+		return nil, ErrIPNotFound
+	}
+
+	return newLocationData(ip, apiResp.CountryCode, apiResp.CountryName, apiResp.RegionCode, apiResp.Region,
+		apiResp.City, apiResp.Postal, apiResp.Timezone, apiResp.Latitude, apiResp.Longitude, p.Name()), nil
+}
+
+// ipinfoResponse represents the response from ipinfo.io
+type ipinfoResponse struct {
+	IP       string `json:"ip"`
+	Bogon    bool   `json:"bogon"`
+	City     string `json:"city"`
+	Region   string `json:"region"`
+	Country  string `json:"country"`
+	Loc      string `json:"loc"` // "lat,lng"
+	Postal   string `json:"postal"`
+	Timezone string `json:"timezone"`
+}
+
+// IPInfoProvider uses ipinfo.io; the token is optional and lifts the free rate limit
+type IPInfoProvider struct {
+	token      string
+	httpClient *http.Client
+}
+
+// NewIPInfoProvider creates an ipinfo.io provider
+func NewIPInfoProvider(token string, httpClient *http.Client) *IPInfoProvider {
+	return &IPInfoProvider{token: token, httpClient: httpClient}
+}
+
+// Name implements GeoIPProvider
+func (p *IPInfoProvider) Name() string {
+	return "ipinfo"
+}
+
+// Lookup implements GeoIPProvider
+func (p *IPInfoProvider) Lookup(ctx context.Context, ip string) (*LocationData, error) {
+	endpoint := fmt.Sprintf("https://ipinfo.io/%s/json", url.PathEscape(ip))
+	if p.token != "" {
+		endpoint += "?token=" + url.QueryEscape(p.token)
+	}
+
+	var apiResp ipinfoResponse
+	if err := getGeoIPJSON(ctx, p.httpClient, endpoint, p.Name(), &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Bogon || apiResp.Country == "" {
+		return nil, ErrIPNotFound
+	}
+
+	var lat, lng *float64
+	if latStr, lngStr, ok := strings.Cut(apiResp.Loc, ","); ok {
+		if v, err := strconv.ParseFloat(latStr, 64); err == nil {
+			lat = &v
+		}
+		if v, err := strconv.ParseFloat(lngStr, 64); err == nil {
+			lng = &v
+		}
+	}
+
+	// ipinfo returns neither a country name nor a region code
+	return newLocationData(ip, apiResp.Country, apiResp.Country, "", apiResp.Region,
+		apiResp.City, apiResp.Postal, apiResp.Timezone, lat, lng, p.Name()), nil
+}
+
+// getGeoIPJSON performs a GET request and decodes a JSON response
+func getGeoIPJSON(ctx context.Context, client *http.Client, endpoint, provider string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrIPNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", provider, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", provider, err)
+	}
+	return nil
+}
+
+// newLocationData builds LocationData from provider fields, leaving empty optional fields unset
+func newLocationData(ip, countryCode, countryName, regionCode, regionName, city, postalCode, timezone string, lat, lng *float64, source string) *LocationData {
+	countryCode = strings.ToUpper(countryCode)
+	countryData := getCountryMetadata(countryCode)
+	locale := fmt.Sprintf("en_%s", countryCode)
+
+	data := &LocationData{
+		Ip:          ip,
+		Country:     countryCode,
+		CountryName: countryName,
+		CallingCode: countryData.callingCode,
+		FlagEmoji:   countryData.flagEmoji,
+		Latitude:    lat,
+		Longitude:   lng,
+		Timezone:    timezone,
+		Currency:    countryData.currency,
+		Locale:      &locale,
+		Source:      source,
+	}
+	if regionCode != "" {
+		state := fmt.Sprintf("%s-%s", countryCode, regionCode)
+		data.State = &state
+	}
+	if regionName != "" {
+		data.StateName = &regionName
+	}
+	if city != "" {
+		data.City = &city
+	}
+	if postalCode != "" {
+		data.PostalCode = &postalCode
+	}
+	return data
+}
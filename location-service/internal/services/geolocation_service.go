@@ -2,12 +2,12 @@ package services
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"errors"
 	"log"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -27,8 +27,15 @@ type LocationData struct {
 	Timezone    string   `json:"timezone"`
 	Currency    string   `json:"currency"`
 	Locale      *string  `json:"locale,omitempty"`
+	Source      string   `json:"source"` // Geolocation provider, or "default"/"mock" for placeholder data
 }
 
+// Location sources that are placeholders rather than a real lookup
+const (
+	LocationSourceDefault = "default" // Private IP: fixed default location
+	LocationSourceMock    = "mock"    // Mock provider, or fallback after a provider failure
+)
+
 // ipAPIResponse represents the response from ip-api.com
 type ipAPIResponse struct {
 	Status      string  `json:"status"`
@@ -46,30 +53,140 @@ type ipAPIResponse struct {
 	Query       string  `json:"query"`
 }
 
-// GeoLocationService handles IP-based location detection
+// DefaultGeoProviderChain is the failover order: the local database first, then the HTTP APIs
+var DefaultGeoProviderChain = []string{"maxmind", "ipapi", "ipinfo"}
+
+const (
+	// geoProviderFailureThreshold consecutive failures take a provider out of the chain
+	geoProviderFailureThreshold = 3
+	// geoProviderCooldown is how long a failing provider is skipped before it is retried
+	geoProviderCooldown = time.Minute
+)
+
+// GeoLocationConfig holds configuration for IP geolocation
+type GeoLocationConfig struct {
+	// Provider is "mock", "failover", or a single provider: "maxmind", "ipapi", "ipinfo", "ip-api"
+	Provider string
+	// Chain orders the providers tried in "failover" mode (default: DefaultGeoProviderChain)
+	Chain []string
+
+	MaxMindDBPath     string
+	MaxMindAccountID  string
+	MaxMindLicenseKey string
+	IPAPIKey          string
+	IPInfoToken       string
+}
+
+// GeoProviderStatus reports the health of an IP geolocation provider
+type GeoProviderStatus struct {
+	Name                string             `json:"name"`
+	Healthy             bool               `json:"healthy"`
+	ConsecutiveFailures int                `json:"consecutive_failures"`
+	Lookups             int64              `json:"lookups"`
+	Failures            int64              `json:"failures"`
+	LastError           string             `json:"last_error,omitempty"`
+	LastFailureAt       *time.Time         `json:"last_failure_at,omitempty"`
+	CooldownUntil       *time.Time         `json:"cooldown_until,omitempty"`
+	Database            *GeoIPDatabaseInfo `json:"database,omitempty"` // MaxMind only
+}
+
+// geoProviderHealth tracks failures of a provider in the chain
+type geoProviderHealth struct {
+	consecutiveFailures int
+	lookups             int64
+	failures            int64
+	lastError           string
+	lastFailureAt       time.Time
+	cooldownUntil       time.Time
+}
+
+// GeoLocationService handles IP-based location detection. Providers are tried in order;
+// one that fails repeatedly is skipped for a cooldown, and mock data is returned only
+// when every provider fails.
 type GeoLocationService struct {
-	provider   string // "mock", "maxmind", "ipapi", "ip-api"
+	provider   string // "mock", "failover", "maxmind", "ipapi", "ipinfo", "ip-api"
+	providers  []GeoIPProvider
+	maxmind    *MaxMindProvider
 	httpClient *http.Client
+
+	mu     sync.Mutex
+	health map[string]*geoProviderHealth
 }
 
 // NewGeoLocationService creates a new geolocation service
 func NewGeoLocationService() *GeoLocationService {
-	return &GeoLocationService{
-		provider: "mock", // Default to mock for now
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-	}
+	return NewGeoLocationServiceWithConfig(GeoLocationConfig{Provider: "mock"})
 }
 
 // NewGeoLocationServiceWithProvider creates a new geolocation service with specified provider
 func NewGeoLocationServiceWithProvider(provider string) *GeoLocationService {
-	return &GeoLocationService{
-		provider: provider,
+	return NewGeoLocationServiceWithConfig(GeoLocationConfig{Provider: provider})
+}
+
+// NewGeoLocationServiceWithConfig creates a geolocation service with a provider or failover chain
+func NewGeoLocationServiceWithConfig(config GeoLocationConfig) *GeoLocationService {
+	s := &GeoLocationService{
+		provider: config.Provider,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		health: make(map[string]*geoProviderHealth),
+	}
+
+	var names []string
+	switch config.Provider {
+	case "failover":
+		names = config.Chain
+		if len(names) == 0 {
+			names = DefaultGeoProviderChain
+		}
+	case "maxmind", "ipapi", "ipinfo", "ip-api":
+		names = []string{config.Provider}
+	default:
+		// Mock provider for development
+	}
+
+	for _, name := range names {
+		var provider GeoIPProvider
+		switch strings.TrimSpace(name) {
+		case "maxmind":
+			if s.maxmind != nil {
+				continue
+			}
+			s.maxmind = NewMaxMindProvider(config.MaxMindDBPath, config.MaxMindAccountID, config.MaxMindLicenseKey,
+				&http.Client{Timeout: 5 * time.Minute})
+			if err := s.maxmind.Load(); err != nil {
+				log.Printf("[GeoIP] %v", err)
+			} else if !s.maxmind.Info().Loaded {
+				log.Printf("[GeoIP] No MaxMind database at %s; upload one to enable local lookups", config.MaxMindDBPath)
+			}
+			provider = s.maxmind
+		case "ipapi":
+			provider = NewIPAPICoProvider(config.IPAPIKey, s.httpClient)
+		case "ipinfo":
+			provider = NewIPInfoProvider(config.IPInfoToken, s.httpClient)
+		case "ip-api":
+			provider = NewIPAPIProvider(s.httpClient)
+		default:
+			log.Printf("[GeoIP] Unknown geolocation provider %q, skipping", name)
+			continue
+		}
+		s.providers = append(s.providers, provider)
+		s.health[provider.Name()] = &geoProviderHealth{}
+		geoIPProviderHealthy.WithLabelValues(provider.Name()).Set(1)
 	}
+
+	return s
+}
+
+// Provider returns the configured provider mode
+func (s *GeoLocationService) Provider() string {
+	return s.provider
+}
+
+// MaxMind returns the local database provider, or nil when it is not in the chain
+func (s *GeoLocationService) MaxMind() *MaxMindProvider {
+	return s.maxmind
 }
 
 // DetectLocationFromIP detects location based on IP address
@@ -79,85 +196,134 @@ func (s *GeoLocationService) DetectLocationFromIP(ctx context.Context, ip string
 		return s.getDefaultLocation(ip), nil
 	}
 
-	// Use the configured provider
-	switch s.provider {
-	case "ip-api":
-		return s.detectFromIPAPI(ctx, ip)
-	case "ipapi":
-		// ipapi.co provider - can add support later
-		log.Printf("ipapi provider not implemented, falling back to ip-api")
-		return s.detectFromIPAPI(ctx, ip)
-	case "maxmind":
-		// MaxMind provider - requires license
-		log.Printf("maxmind provider not implemented, falling back to ip-api")
-		return s.detectFromIPAPI(ctx, ip)
-	default:
-		// Mock provider for development
+	if len(s.providers) == 0 {
 		return s.detectFromMock(ip)
 	}
-}
-
-// detectFromIPAPI uses ip-api.com for geolocation (free, accurate, 45 req/min limit)
-func (s *GeoLocationService) detectFromIPAPI(ctx context.Context, ip string) (*LocationData, error) {
-	url := fmt.Sprintf("http://ip-api.com/json/%s?fields=status,message,country,countryCode,region,regionName,city,zip,lat,lon,timezone,isp,query", ip)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		log.Printf("Error creating request for ip-api.com: %v", err)
-		return s.detectFromMock(ip)
+	if location := s.lookup(ctx, ip); location != nil {
+		return location, nil
 	}
+	geoIPLookups.WithLabelValues(LocationSourceMock, "fallback").Inc()
+	return s.detectFromMock(ip)
+}
 
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		log.Printf("Error calling ip-api.com: %v, falling back to mock", err)
-		return s.detectFromMock(ip)
+// lookup tries each available provider in order, returning nil when none has a location
+func (s *GeoLocationService) lookup(ctx context.Context, ip string) *LocationData {
+	for _, provider := range s.availableProviders() {
+		name := provider.Name()
+		start := time.Now()
+		location, err := provider.Lookup(ctx, ip)
+		geoIPLookupDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+
+		switch {
+		case err == nil:
+			geoIPLookups.WithLabelValues(name, "success").Inc()
+			s.recordResult(name, nil)
+			return location
+		case errors.Is(err, ErrIPNotFound):
+			// A valid answer; the next provider may still know the IP
+			geoIPLookups.WithLabelValues(name, "not_found").Inc()
+			s.recordResult(name, nil)
+		case errors.Is(err, ErrGeoIPDatabaseNotLoaded):
+			geoIPLookups.WithLabelValues(name, "unavailable").Inc()
+		case ctx.Err() != nil:
+			// The caller gave up; not the provider's fault
+			geoIPLookups.WithLabelValues(name, "error").Inc()
+			return nil
+		default:
+			geoIPLookups.WithLabelValues(name, "error").Inc()
+			log.Printf("[GeoIP] %s lookup for %s failed: %v", name, ip, err)
+			s.recordResult(name, err)
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("ip-api.com returned status %d, falling back to mock", resp.StatusCode)
-		return s.detectFromMock(ip)
-	}
+	log.Printf("[GeoIP] No provider located %s, falling back to mock", ip)
+	return nil
+}
 
-	var apiResp ipAPIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		log.Printf("Error decoding ip-api.com response: %v, falling back to mock", err)
-		return s.detectFromMock(ip)
+// availableProviders returns the providers not cooling down, in chain order. When every
+// provider is cooling down they are all returned, so a recovered provider is found sooner.
+func (s *GeoLocationService) availableProviders() []GeoIPProvider {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	available := make([]GeoIPProvider, 0, len(s.providers))
+	for _, provider := range s.providers {
+		if now.Before(s.health[provider.Name()].cooldownUntil) {
+			geoIPLookups.WithLabelValues(provider.Name(), "skipped").Inc()
+			continue
+		}
+		available = append(available, provider)
 	}
-
-	if apiResp.Status != "success" {
-		log.Printf("ip-api.com lookup failed: %s, falling back to mock", apiResp.Message)
-		return s.detectFromMock(ip)
+	if len(available) == 0 {
+		return s.providers
 	}
-
-	// Map the response to LocationData
-	return s.mapIPAPIResponse(&apiResp), nil
+	return available
 }
 
-// mapIPAPIResponse converts ip-api.com response to LocationData
-func (s *GeoLocationService) mapIPAPIResponse(resp *ipAPIResponse) *LocationData {
-	// Get country metadata
-	countryData := getCountryMetadata(resp.CountryCode)
+// recordResult updates a provider's health after a lookup; err is nil on success
+func (s *GeoLocationService) recordResult(name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	state := fmt.Sprintf("%s-%s", resp.CountryCode, resp.Region)
-	locale := fmt.Sprintf("en_%s", resp.CountryCode)
+	health := s.health[name]
+	health.lookups++
+	if err == nil {
+		if health.consecutiveFailures >= geoProviderFailureThreshold {
+			log.Printf("[GeoIP] Provider %s recovered", name)
+		}
+		health.consecutiveFailures = 0
+		health.cooldownUntil = time.Time{}
+		geoIPProviderHealthy.WithLabelValues(name).Set(1)
+		return
+	}
 
-	return &LocationData{
-		Ip:          resp.Query,
-		Country:     resp.CountryCode,
-		CountryName: resp.Country,
-		CallingCode: countryData.callingCode,
-		FlagEmoji:   countryData.flagEmoji,
-		State:       &state,
-		StateName:   &resp.RegionName,
-		City:        &resp.City,
-		PostalCode:  &resp.Zip,
-		Latitude:    &resp.Lat,
-		Longitude:   &resp.Lon,
-		Timezone:    resp.Timezone,
-		Currency:    countryData.currency,
-		Locale:      &locale,
+	health.failures++
+	health.consecutiveFailures++
+	health.lastError = err.Error()
+	health.lastFailureAt = time.Now().UTC()
+	if health.consecutiveFailures >= geoProviderFailureThreshold {
+		// Also re-entered when the trial request after a cooldown fails
+		health.cooldownUntil = health.lastFailureAt.Add(geoProviderCooldown)
+		geoIPProviderHealthy.WithLabelValues(name).Set(0)
+		log.Printf("[GeoIP] Provider %s failed %d times in a row, skipping it for %s", name, health.consecutiveFailures, geoProviderCooldown)
+	}
+}
+
+// ProviderStatus returns the health of each provider in chain order
+func (s *GeoLocationService) ProviderStatus() []GeoProviderStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]GeoProviderStatus, 0, len(s.providers))
+	for _, provider := range s.providers {
+		health := s.health[provider.Name()]
+		status := GeoProviderStatus{
+			Name:                provider.Name(),
+			Healthy:             !now.Before(health.cooldownUntil),
+			ConsecutiveFailures: health.consecutiveFailures,
+			Lookups:             health.lookups,
+			Failures:            health.failures,
+			LastError:           health.lastError,
+		}
+		if !health.lastFailureAt.IsZero() {
+			lastFailureAt := health.lastFailureAt
+			status.LastFailureAt = &lastFailureAt
+		}
+		if !status.Healthy {
+			cooldownUntil := health.cooldownUntil
+			status.CooldownUntil = &cooldownUntil
+		}
+		if s.maxmind != nil && provider.Name() == s.maxmind.Name() {
+			info := s.maxmind.Info()
+			status.Database = &info
+			status.Healthy = status.Healthy && info.Loaded
+		}
+		statuses = append(statuses, status)
 	}
+	return statuses
 }
 
 // countryMetadata holds static country data
@@ -237,12 +403,14 @@ func (s *GeoLocationService) getDefaultLocation(ip string) *LocationData {
 		Timezone:    "America/Los_Angeles",
 		Currency:    "USD",
 		Locale:      &locale,
+		Source:      LocationSourceDefault,
 	}
 }
 
 // detectFromMock returns mock location data based on IP patterns (for development)
 func (s *GeoLocationService) detectFromMock(ip string) (*LocationData, error) {
 	locationData := s.getDefaultLocation(ip)
+	locationData.Source = LocationSourceMock
 
 	// Mock geographic detection based on IP ranges
 	switch {
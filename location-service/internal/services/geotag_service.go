@@ -161,8 +161,11 @@ func (s *GeoTagService) SearchPlaces(ctx context.Context, filters models.SearchF
 	return s.placesRepo.Search(ctx, filters.Query, filters)
 }
 
-// FindNearby finds places near a location
-func (s *GeoTagService) FindNearby(ctx context.Context, query models.NearbyQuery) ([]models.Place, error) {
+// FindNearby finds places near a location, nearest first
+func (s *GeoTagService) FindNearby(ctx context.Context, query models.NearbyQuery) ([]repository.PlaceWithDistance, error) {
+	if s.placesRepo == nil {
+		return nil, ErrNoDatabase
+	}
 	limit := query.Limit
 	if limit <= 0 || limit > 100 {
 		limit = 20
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+
+	"location-service/internal/models"
+	"location-service/internal/repository"
+)
+
+var (
+	// ErrInvalidLanguage is returned for language tags that are not valid BCP 47
+	ErrInvalidLanguage = errors.New("invalid language tag")
+	// ErrLocationNotFound is returned when the country or state does not exist
+	ErrLocationNotFound = errors.New("location not found")
+	// ErrNameOverrideNotFound is returned when deleting an override that does not exist
+	ErrNameOverrideNotFound = errors.New("localized name override not found")
+)
+
+// maxNameLanguages bounds the fallback chain built from Accept-Language
+const maxNameLanguages = 8
+
+// SetLocalizedNameRepository enables localized country/state names
+func (s *LocationService) SetLocalizedNameRepository(repo repository.LocalizedNameRepository) {
+	s.nameRepo = repo
+}
+
+// NormalizeLanguage validates a language tag and returns its canonical form (e.g. pt-br -> pt-BR)
+func NormalizeLanguage(tag string) (string, error) {
+	parsed, err := language.Parse(strings.TrimSpace(tag))
+	if err != nil || parsed == language.Und {
+		return "", ErrInvalidLanguage
+	}
+	return parsed.String(), nil
+}
+
+// NameLanguages returns the languages to look names up in, most preferred first.
+// An explicit lang wins over the Accept-Language header; each tag is followed by its
+// CLDR parents (pt-BR -> pt). The chain stops at English, since English names are the
+// base names. Returns nil when no localization is needed.
+func NameLanguages(lang, acceptLanguage string) []string {
+	var tags []language.Tag
+	if lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			tags = []language.Tag{tag}
+		}
+	} else if acceptLanguage != "" {
+		parsed, q, err := language.ParseAcceptLanguage(acceptLanguage)
+		if err == nil {
+			for i, tag := range parsed {
+				if q[i] > 0 {
+					tags = append(tags, tag)
+				}
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var languages []string
+	for _, tag := range tags {
+		for ; tag != language.Und; tag = tag.Parent() {
+			if base, _ := tag.Base(); base.String() == "en" {
+				return languages
+			}
+			name := tag.String()
+			if !seen[name] {
+				seen[name] = true
+				languages = append(languages, name)
+			}
+			if len(languages) == maxNameLanguages {
+				return languages
+			}
+		}
+	}
+	return languages
+}
+
+// resolveNames picks the best available name per entity: earlier languages win,
+// and within a language an admin override beats the CLDR name
+func (s *LocationService) resolveNames(ctx context.Context, entityType string, ids []string, languages []string) (map[string]string, error) {
+	names, err := s.nameRepo.Find(ctx, entityType, ids, languages)
+	if err != nil {
+		return nil, err
+	}
+
+	rank := make(map[string]int, len(languages))
+	for i, lang := range languages {
+		rank[lang] = i * 2
+	}
+
+	best := make(map[string]string, len(ids))
+	bestRank := make(map[string]int, len(ids))
+	for _, n := range names {
+		r := rank[n.Language]
+		if n.Source != models.NameSourceOverride {
+			r++
+		}
+		if current, ok := bestRank[n.EntityID]; !ok || r < current {
+			best[n.EntityID] = n.Name
+			bestRank[n.EntityID] = r
+		}
+	}
+	return best, nil
+}
+
+// LocalizeCountries sets LocalizedName on each country, falling back to the English name
+func (s *LocationService) LocalizeCountries(ctx context.Context, countries []models.Country, languages []string) error {
+	if s.nameRepo == nil || len(languages) == 0 || len(countries) == 0 {
+		return nil
+	}
+	ids := make([]string, len(countries))
+	for i := range countries {
+		ids[i] = countries[i].ID
+	}
+	names, err := s.resolveNames(ctx, models.EntityTypeCountry, ids, languages)
+	if err != nil {
+		return err
+	}
+	for i := range countries {
+		countries[i].LocalizedName = countries[i].Name
+		if name, ok := names[countries[i].ID]; ok {
+			countries[i].LocalizedName = name
+		}
+	}
+	return nil
+}
+
+// LocalizeStates sets LocalizedName on each state, falling back to the English name
+func (s *LocationService) LocalizeStates(ctx context.Context, states []models.State, languages []string) error {
+	if s.nameRepo == nil || len(languages) == 0 || len(states) == 0 {
+		return nil
+	}
+	ids := make([]string, len(states))
+	for i := range states {
+		ids[i] = states[i].ID
+	}
+	names, err := s.resolveNames(ctx, models.EntityTypeState, ids, languages)
+	if err != nil {
+		return err
+	}
+	for i := range states {
+		states[i].LocalizedName = states[i].Name
+		if name, ok := names[states[i].ID]; ok {
+			states[i].LocalizedName = name
+		}
+	}
+	return nil
+}
+
+// ensureLocation checks that the country or state exists
+func (s *LocationService) ensureLocation(ctx context.Context, entityType, id string) error {
+	var err error
+	switch entityType {
+	case models.EntityTypeCountry:
+		_, err = s.GetCountryByID(ctx, id)
+	case models.EntityTypeState:
+		_, err = s.GetStateByID(ctx, id)
+	default:
+		return ErrLocationNotFound
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrLocationNotFound
+	}
+	return err
+}
+
+// GetLocalizedNames returns every stored name (CLDR and override) for a country or state
+func (s *LocationService) GetLocalizedNames(ctx context.Context, entityType, id string) ([]models.LocalizedName, error) {
+	if s.nameRepo == nil {
+		return nil, ErrNoDatabase
+	}
+	if err := s.ensureLocation(ctx, entityType, id); err != nil {
+		return nil, err
+	}
+	return s.nameRepo.ListForEntity(ctx, entityType, id)
+}
+
+// SetNameOverride stores an admin translation that takes precedence over the CLDR name
+func (s *LocationService) SetNameOverride(ctx context.Context, entityType, id, lang, name string) (*models.LocalizedName, error) {
+	if s.nameRepo == nil {
+		return nil, ErrNoDatabase
+	}
+	lang, err := NormalizeLanguage(lang)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureLocation(ctx, entityType, id); err != nil {
+		return nil, err
+	}
+
+	override := &models.LocalizedName{
+		EntityType: entityType,
+		EntityID:   id,
+		Language:   lang,
+		Source:     models.NameSourceOverride,
+		Name:       strings.TrimSpace(name),
+	}
+	if err := s.nameRepo.Upsert(ctx, override); err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+// DeleteNameOverride removes an admin translation, reverting to the CLDR name if one exists
+func (s *LocationService) DeleteNameOverride(ctx context.Context, entityType, id, lang string) error {
+	if s.nameRepo == nil {
+		return ErrNoDatabase
+	}
+	lang, err := NormalizeLanguage(lang)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.nameRepo.Delete(ctx, entityType, id, lang, models.NameSourceOverride)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNameOverrideNotFound
+	}
+	return nil
+}
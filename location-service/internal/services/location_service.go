@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"location-service/internal/models"
 	"location-service/internal/repository"
@@ -19,6 +21,13 @@ type LocationService struct {
 	currencyRepo repository.CurrencyRepository
 	timezoneRepo repository.TimezoneRepository
 	cacheRepo    repository.LocationCacheRepository
+	nameRepo     repository.LocalizedNameRepository
+
+	// Offline reference data export, rebuilt at most every refExportRefresh
+	refRepo          repository.ReferenceDataRepository
+	refExportRefresh time.Duration
+	refExportMu      sync.Mutex
+	refExport        *ReferenceExport
 }
 
 // NewLocationService creates a new location service
@@ -0,0 +1,493 @@
+package services
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"location-service/internal/models"
+	"location-service/internal/repository"
+)
+
+const (
+	// DefaultGeoNamesPostalURL serves the GeoNames postal code dataset as one zip per country ({CC}.zip)
+	DefaultGeoNamesPostalURL = "https://download.geonames.org/export/zip"
+	// MaxPostalCodeBulkLookup is the maximum number of postal codes per bulk lookup
+	MaxPostalCodeBulkLookup = 100
+	// MaxPostalCodeDatasetSize bounds uploaded and downloaded datasets
+	MaxPostalCodeDatasetSize = 256 << 20
+)
+
+var (
+	// ErrInvalidCountryCode is returned for country codes that are not ISO 3166-1 alpha-2
+	ErrInvalidCountryCode = errors.New("invalid country code")
+	// ErrPostalCodeNotFound is returned when the dataset has no place for a postal code
+	ErrPostalCodeNotFound = errors.New("postal code not found")
+	// ErrPostalCodeDatasetNotFound is returned when GeoNames has no dataset for a country
+	ErrPostalCodeDatasetNotFound = errors.New("postal code dataset not found")
+	// ErrInvalidPostalCodeDataset is returned for files that are not in the GeoNames postal code format
+	ErrInvalidPostalCodeDataset = errors.New("invalid postal code dataset")
+)
+
+var (
+	countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	// genericPostalCodePattern accepts plausible codes of countries without a known format
+	genericPostalCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9 -]{1,9}$`)
+)
+
+// PostalCodeQuery names a postal code to look up, optionally with the state it should be in
+type PostalCodeQuery struct {
+	Country    string `json:"country" binding:"required"`
+	PostalCode string `json:"postal_code" binding:"required"`
+	State      string `json:"state,omitempty"` // State ID (US-CA), code (CA) or name
+}
+
+// PostalCodeImportResult reports the postal codes imported per country
+type PostalCodeImportResult struct {
+	Countries []PostalCodeCountryImport `json:"countries"`
+	Places    int                       `json:"places"`
+	Duration  string                    `json:"duration"`
+}
+
+// PostalCodeCountryImport reports the import of one country
+type PostalCodeCountryImport struct {
+	CountryID string `json:"country_id"`
+	Places    int    `json:"places"`
+	// Places whose state could not be matched to a state of the country keep the dataset's
+	// state name and code, without a state_id
+	UnmatchedStates int `json:"unmatched_states"`
+}
+
+// PostalCodeService validates postal codes per country and resolves them to a city and state
+// from an imported GeoNames postal code dataset. Countries without an imported dataset are
+// validated by format only.
+type PostalCodeService struct {
+	postalRepo repository.PostalCodeRepository
+	stateRepo  repository.StateRepository
+	datasetURL string
+	httpClient *http.Client
+}
+
+// NewPostalCodeService creates a new postal code service. datasetURL is the base URL of
+// the per-country GeoNames zips (DefaultGeoNamesPostalURL if empty).
+func NewPostalCodeService(postalRepo repository.PostalCodeRepository, stateRepo repository.StateRepository, datasetURL string) *PostalCodeService {
+	if datasetURL == "" {
+		datasetURL = DefaultGeoNamesPostalURL
+	}
+	return &PostalCodeService{
+		postalRepo: postalRepo,
+		stateRepo:  stateRepo,
+		datasetURL: strings.TrimRight(datasetURL, "/"),
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// Lookup validates a postal code and resolves its city and state
+func (s *PostalCodeService) Lookup(ctx context.Context, query PostalCodeQuery) (*models.PostalCodeLookup, error) {
+	results, err := s.BulkLookup(ctx, []PostalCodeQuery{query})
+	if err != nil {
+		return nil, err
+	}
+	return &results[0], nil
+}
+
+// BulkLookup validates and resolves postal codes, returning results in request order.
+// The dataset is queried once per country.
+func (s *PostalCodeService) BulkLookup(ctx context.Context, queries []PostalCodeQuery) ([]models.PostalCodeLookup, error) {
+	results := make([]models.PostalCodeLookup, len(queries))
+	candidatesByCountry := make(map[string][]string)
+	for i, query := range queries {
+		countryID := strings.ToUpper(strings.TrimSpace(query.Country))
+		if !countryCodePattern.MatchString(countryID) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCountryCode, query.Country)
+		}
+		results[i] = newPostalCodeLookup(countryID, query.PostalCode)
+		if results[i].FormatValid {
+			candidatesByCountry[countryID] = append(candidatesByCountry[countryID], postalCodeCandidates(results[i].Standardized, countryID)...)
+		}
+	}
+
+	// Without a database every country is validated by format only
+	placesByCountry := make(map[string]map[string][]models.PostalCode)
+	if s.postalRepo != nil {
+		for countryID, candidates := range candidatesByCountry {
+			available, err := s.postalRepo.HasCountry(ctx, countryID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check postal code coverage: %w", err)
+			}
+			if !available {
+				continue
+			}
+			places, err := s.postalRepo.Find(ctx, countryID, candidates)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find postal codes: %w", err)
+			}
+			byCode := make(map[string][]models.PostalCode)
+			for _, place := range places {
+				byCode[place.PostalCode] = append(byCode[place.PostalCode], place)
+			}
+			placesByCountry[countryID] = byCode
+		}
+	}
+
+	for i := range results {
+		byCode, verified := placesByCountry[results[i].CountryID]
+		resolvePostalCode(&results[i], byCode, verified, strings.TrimSpace(queries[i].State))
+	}
+	return results, nil
+}
+
+// Coverage returns the countries with an imported dataset
+func (s *PostalCodeService) Coverage(ctx context.Context) ([]models.PostalCodeCoverage, error) {
+	if s.postalRepo == nil {
+		return nil, ErrNoDatabase
+	}
+	coverage, err := s.postalRepo.Coverage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get postal code coverage: %w", err)
+	}
+	if coverage == nil {
+		coverage = []models.PostalCodeCoverage{}
+	}
+	return coverage, nil
+}
+
+// ImportFromGeoNames downloads and imports the GeoNames dataset of a country
+func (s *PostalCodeService) ImportFromGeoNames(ctx context.Context, countryID string) (*PostalCodeImportResult, error) {
+	countryID = strings.ToUpper(strings.TrimSpace(countryID))
+	if !countryCodePattern.MatchString(countryID) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCountryCode, countryID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.datasetURL+"/"+countryID+".zip", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download postal code dataset: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: GeoNames has no dataset for %s", ErrPostalCodeDatasetNotFound, countryID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download postal code dataset: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxPostalCodeDatasetSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download postal code dataset: %w", err)
+	}
+	if len(data) > MaxPostalCodeDatasetSize {
+		return nil, fmt.Errorf("%w: dataset exceeds %d bytes", ErrInvalidPostalCodeDataset, MaxPostalCodeDatasetSize)
+	}
+	return s.Import(ctx, countryID, data)
+}
+
+// Import imports a GeoNames postal code file (tab-separated text, or a zip of it). Each country
+// in the file replaces that country's postal codes; with countryID set, other countries are skipped.
+func (s *PostalCodeService) Import(ctx context.Context, countryID string, data []byte) (*PostalCodeImportResult, error) {
+	if s.postalRepo == nil {
+		return nil, ErrNoDatabase
+	}
+	countryID = strings.ToUpper(strings.TrimSpace(countryID))
+	if countryID != "" && !countryCodePattern.MatchString(countryID) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCountryCode, countryID)
+	}
+
+	readers, err := postalCodeDatasetReaders(data)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, r := range readers {
+			r.Close()
+		}
+	}()
+
+	start := time.Now()
+	result := &PostalCodeImportResult{Countries: []PostalCodeCountryImport{}}
+	imported := make(map[string]bool)
+	flush := func(country string, places []models.PostalCode) error {
+		if imported[country] {
+			return fmt.Errorf("%w: rows of %s are not grouped together", ErrInvalidPostalCodeDataset, country)
+		}
+		imported[country] = true
+		countryImport, err := s.replaceCountry(ctx, country, places)
+		if err != nil {
+			return err
+		}
+		result.Countries = append(result.Countries, *countryImport)
+		result.Places += countryImport.Places
+		return nil
+	}
+
+	for _, r := range readers {
+		if err := parseGeoNamesPostalCodes(r, countryID, flush); err != nil {
+			return nil, err
+		}
+	}
+	if len(result.Countries) == 0 {
+		if countryID != "" {
+			return nil, fmt.Errorf("%w: no postal codes for %s", ErrInvalidPostalCodeDataset, countryID)
+		}
+		return nil, fmt.Errorf("%w: no postal codes", ErrInvalidPostalCodeDataset)
+	}
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+	return result, nil
+}
+
+// replaceCountry matches the places' states to the country's states and replaces its postal codes
+func (s *PostalCodeService) replaceCountry(ctx context.Context, countryID string, places []models.PostalCode) (*PostalCodeCountryImport, error) {
+	states, err := countryStateIDs(ctx, s.stateRepo, countryID)
+	if err != nil {
+		return nil, err
+	}
+
+	countryImport := &PostalCodeCountryImport{CountryID: countryID, Places: len(places)}
+	for i := range places {
+		place := &places[i]
+		for _, key := range []string{countryID + "-" + place.StateCode, place.StateCode, place.StateName} {
+			if id, ok := states[strings.ToUpper(key)]; ok && key != "" {
+				place.StateID = id
+				break
+			}
+		}
+		if place.StateID == "" && (place.StateCode != "" || place.StateName != "") {
+			countryImport.UnmatchedStates++
+		}
+	}
+
+	if err := s.postalRepo.ReplaceCountry(ctx, countryID, places); err != nil {
+		return nil, fmt.Errorf("failed to import postal codes of %s: %w", countryID, err)
+	}
+	return countryImport, nil
+}
+
+// countryStateIDs indexes the IDs of a country's states by uppercased ID, code and name, for
+// matching the states named in imported datasets
+func countryStateIDs(ctx context.Context, stateRepo repository.StateRepository, countryID string) (map[string]string, error) {
+	states := make(map[string]string)
+	if stateRepo == nil {
+		return states, nil
+	}
+	countryStates, err := stateRepo.GetByCountryID(ctx, countryID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get states of %s: %w", countryID, err)
+	}
+	for _, state := range countryStates {
+		states[strings.ToUpper(state.ID)] = state.ID
+		states[strings.ToUpper(state.Code)] = state.ID
+		states[strings.ToUpper(state.Name)] = state.ID
+	}
+	return states, nil
+}
+
+// postalCodeDatasetReaders returns the text files of a zipped dataset, or the data itself
+func postalCodeDatasetReaders(data []byte) ([]io.ReadCloser, error) {
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return []io.ReadCloser{io.NopCloser(bytes.NewReader(data))}, nil
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPostalCodeDataset, err)
+	}
+	var readers []io.ReadCloser
+	for _, file := range archive.File {
+		name := strings.ToLower(path.Base(file.Name))
+		// GeoNames zips ship a readme.txt next to the data
+		if !strings.HasSuffix(name, ".txt") || name == "readme.txt" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			for _, r := range readers {
+				r.Close()
+			}
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPostalCodeDataset, err)
+		}
+		readers = append(readers, rc)
+	}
+	if len(readers) == 0 {
+		return nil, fmt.Errorf("%w: zip contains no .txt dataset", ErrInvalidPostalCodeDataset)
+	}
+	return readers, nil
+}
+
+// parseGeoNamesPostalCodes reads the tab-separated GeoNames postal code format (country code,
+// postal code, place name, admin name1, admin code1, admin name2, admin code2, admin name3,
+// admin code3, latitude, longitude, accuracy) and calls flush with the places of each country.
+// Rows of countries other than onlyCountry are skipped when it is set.
+func parseGeoNamesPostalCodes(r io.Reader, onlyCountry string, flush func(countryID string, places []models.PostalCode) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var country string
+	var places []models.PostalCode
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 3 {
+			return fmt.Errorf("%w: line %d has %d columns, expected 12", ErrInvalidPostalCodeDataset, line, len(fields))
+		}
+		for len(fields) < 12 {
+			fields = append(fields, "")
+		}
+
+		countryID := strings.ToUpper(strings.TrimSpace(fields[0]))
+		if !countryCodePattern.MatchString(countryID) {
+			return fmt.Errorf("%w: line %d has country code %q", ErrInvalidPostalCodeDataset, line, fields[0])
+		}
+		if onlyCountry != "" && countryID != onlyCountry {
+			continue
+		}
+		if countryID != country {
+			if len(places) > 0 {
+				if err := flush(country, places); err != nil {
+					return err
+				}
+			}
+			country, places = countryID, nil
+		}
+
+		place := models.PostalCode{
+			CountryID:  countryID,
+			PostalCode: standardizePostalCode(fields[1], countryID),
+			PlaceName:  strings.TrimSpace(fields[2]),
+			StateName:  strings.TrimSpace(fields[3]),
+			StateCode:  strings.TrimSpace(fields[4]),
+			County:     strings.TrimSpace(fields[5]),
+			Source:     models.PostalCodeSourceGeoNames,
+		}
+		if place.PostalCode == "" || place.PlaceName == "" {
+			continue
+		}
+		if lat, err := strconv.ParseFloat(strings.TrimSpace(fields[9]), 64); err == nil {
+			place.Latitude = &lat
+		}
+		if lng, err := strconv.ParseFloat(strings.TrimSpace(fields[10]), 64); err == nil {
+			place.Longitude = &lng
+		}
+		place.Accuracy, _ = strconv.Atoi(strings.TrimSpace(fields[11]))
+		places = append(places, place)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPostalCodeDataset, err)
+	}
+	if len(places) > 0 {
+		return flush(country, places)
+	}
+	return nil
+}
+
+// newPostalCodeLookup standardizes a postal code and checks it against the country's format
+func newPostalCodeLookup(countryID, postalCode string) models.PostalCodeLookup {
+	standardized := standardizePostalCode(postalCode, countryID)
+	pattern, ok := postalCodePatterns[countryID]
+	if !ok {
+		pattern = genericPostalCodePattern
+	}
+	return models.PostalCodeLookup{
+		CountryID:    countryID,
+		PostalCode:   postalCode,
+		Standardized: standardized,
+		FormatValid:  pattern.MatchString(standardized),
+		Places:       []models.PostalCode{},
+	}
+}
+
+// postalCodeCandidates returns the codes to look a standardized code up by, most precise
+// first: datasets hold 5-digit US ZIPs, and only the outward part of CA, GB and NL codes
+func postalCodeCandidates(standardized, countryID string) []string {
+	candidates := []string{standardized}
+	switch countryID {
+	case "US":
+		if len(standardized) > 5 {
+			candidates = append(candidates, standardized[:5])
+		}
+	case "CA", "GB":
+		if outward, _, ok := strings.Cut(standardized, " "); ok {
+			candidates = append(candidates, outward)
+		}
+	case "NL":
+		if len(standardized) > 4 {
+			candidates = append(candidates, standardized[:4])
+		}
+	}
+	return candidates
+}
+
+// resolvePostalCode fills a lookup from the dataset's places by postal code. verified reports
+// that the country has a dataset; without one a well-formed code is valid by format only.
+func resolvePostalCode(result *models.PostalCodeLookup, byCode map[string][]models.PostalCode, verified bool, state string) {
+	if !result.FormatValid {
+		result.Reason = models.PostalCodeReasonInvalidFormat
+		return
+	}
+	if !verified {
+		result.Valid = true
+		return
+	}
+	result.Verified = true
+
+	for i, candidate := range postalCodeCandidates(result.Standardized, result.CountryID) {
+		places := byCode[candidate]
+		if len(places) == 0 {
+			continue
+		}
+		result.Found = true
+		result.Precision = "exact"
+		if i > 0 {
+			result.Precision = "prefix"
+		}
+		result.Places = places
+		result.City = places[0].PlaceName
+		result.StateID = places[0].StateID
+		result.StateCode = places[0].StateCode
+		result.StateName = places[0].StateName
+		break
+	}
+	if !result.Found {
+		result.Reason = models.PostalCodeReasonNotFound
+		return
+	}
+
+	result.Valid = true
+	if state != "" {
+		match := postalCodeInState(result.Places, result.CountryID, state)
+		result.StateMatch = &match
+		if !match {
+			result.Valid = false
+			result.Reason = models.PostalCodeReasonStateMismatch
+		}
+	}
+}
+
+// postalCodeInState reports whether any place is in the state given by ID, code or name
+func postalCodeInState(places []models.PostalCode, countryID, state string) bool {
+	for _, place := range places {
+		for _, candidate := range []string{place.StateID, countryID + "-" + place.StateCode, place.StateCode, place.StateName} {
+			if candidate != "" && candidate != countryID+"-" && strings.EqualFold(candidate, state) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"location-service/internal/models"
+	"location-service/internal/repository"
+)
+
+// ReferenceDataFormatVersion is the version of the reference data bundle layout; it is part of
+// the data version so caches refresh when the layout changes
+const ReferenceDataFormatVersion = 1
+
+// DefaultReferenceExportRefresh is how long a built bundle is served before the data is reloaded
+const DefaultReferenceExportRefresh = 5 * time.Minute
+
+// ReferenceExport is a built reference data bundle. The same data always produces the same
+// bytes and version, so every replica serves identical bundles under the same ETag.
+type ReferenceExport struct {
+	Version   string
+	UpdatedAt time.Time
+	JSON      []byte
+	Gzip      []byte
+
+	loadedAt time.Time
+}
+
+// SetReferenceDataRepository enables the offline reference data export
+func (s *LocationService) SetReferenceDataRepository(repo repository.ReferenceDataRepository, refresh time.Duration) {
+	if refresh <= 0 {
+		refresh = DefaultReferenceExportRefresh
+	}
+	s.refRepo = repo
+	s.refExportRefresh = refresh
+}
+
+// ExportReferenceData returns the current reference data bundle. The bundle is rebuilt from the
+// database when it is older than the refresh interval; the version only changes with the data.
+func (s *LocationService) ExportReferenceData(ctx context.Context) (*ReferenceExport, error) {
+	if s.refRepo == nil {
+		return nil, ErrNoDatabase
+	}
+
+	s.refExportMu.Lock()
+	defer s.refExportMu.Unlock()
+
+	if s.refExport != nil && time.Since(s.refExport.loadedAt) < s.refExportRefresh {
+		return s.refExport, nil
+	}
+
+	data, err := s.refRepo.LoadAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	export, err := buildReferenceExport(data)
+	if err != nil {
+		return nil, err
+	}
+	if s.refExport != nil && s.refExport.Version == export.Version {
+		// Unchanged data: keep the existing bundle
+		s.refExport.loadedAt = time.Now()
+		return s.refExport, nil
+	}
+
+	export.loadedAt = time.Now()
+	s.refExport = export
+	return export, nil
+}
+
+// buildReferenceExport versions and encodes a reference data bundle. The version is a hash of
+// the bundle content, so it is stable across restarts and replicas.
+func buildReferenceExport(data *models.ReferenceData) (*ReferenceExport, error) {
+	data.FormatVersion = ReferenceDataFormatVersion
+	data.Version = ""
+	data.UpdatedAt = latestReferenceUpdate(data)
+
+	content, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode reference data: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	data.Version = fmt.Sprintf("%d-%s", ReferenceDataFormatVersion, hex.EncodeToString(sum[:8]))
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode reference data: %w", err)
+	}
+
+	var compressed bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gz.Write(body); err != nil {
+		return nil, fmt.Errorf("failed to compress reference data: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress reference data: %w", err)
+	}
+
+	return &ReferenceExport{
+		Version:   data.Version,
+		UpdatedAt: data.UpdatedAt,
+		JSON:      body,
+		Gzip:      compressed.Bytes(),
+	}, nil
+}
+
+// latestReferenceUpdate returns the most recent update time of any record in the bundle
+func latestReferenceUpdate(data *models.ReferenceData) time.Time {
+	var latest time.Time
+	track := func(t time.Time) {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	for _, country := range data.Countries {
+		track(country.UpdatedAt)
+	}
+	for _, state := range data.States {
+		track(state.UpdatedAt)
+	}
+	for _, currency := range data.Currencies {
+		track(currency.UpdatedAt)
+	}
+	for _, timezone := range data.Timezones {
+		track(timezone.UpdatedAt)
+	}
+	return latest.UTC()
+}
@@ -0,0 +1,769 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"location-service/internal/models"
+)
+
+// ReferenceDataType is a reference data table that can be imported and exported in bulk
+type ReferenceDataType string
+
+const (
+	ReferenceCountries  ReferenceDataType = "countries"
+	ReferenceStates     ReferenceDataType = "states"
+	ReferenceCurrencies ReferenceDataType = "currencies"
+	ReferenceTimezones  ReferenceDataType = "timezones"
+)
+
+// Reference data file formats
+const (
+	ReferenceFormatCSV  = "csv"
+	ReferenceFormatJSON = "json"
+)
+
+// MaxReferenceImportSize caps reference data import files
+const MaxReferenceImportSize = 10 << 20
+
+// Record changes reported by a reference data import
+const (
+	ReferenceActionCreate  = "create"
+	ReferenceActionUpdate  = "update"
+	ReferenceActionRestore = "restore" // Soft-deleted record imported again
+)
+
+var (
+	// ErrInvalidReferenceDataType is returned for tables that cannot be imported or exported
+	ErrInvalidReferenceDataType = errors.New("invalid reference data type: expected countries, states, currencies or timezones")
+	// ErrInvalidReferenceFormat is returned for file formats other than CSV and JSON
+	ErrInvalidReferenceFormat = errors.New("invalid reference data format: expected csv or json")
+	// ErrInvalidReferenceFile is returned when an import file cannot be parsed
+	ErrInvalidReferenceFile = errors.New("invalid reference data file")
+	// ErrReferenceImportInvalid is returned when applying an import whose records fail validation
+	ErrReferenceImportInvalid = errors.New("reference data import has validation errors")
+)
+
+// ReferenceFieldChange is a field changed by an import
+type ReferenceFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// ReferenceRecordChange is a record created, updated or restored by an import
+type ReferenceRecordChange struct {
+	ID      string                 `json:"id"`
+	Action  string                 `json:"action"` // create, update, restore
+	Changes []ReferenceFieldChange `json:"changes,omitempty"`
+}
+
+// ReferenceImportIssue is a validation error in an import file
+type ReferenceImportIssue struct {
+	Row     int    `json:"row"` // CSV line, or 1-based index in a JSON array
+	ID      string `json:"id,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ReferenceImportResult is the diff of an import against the current data, and whether it was applied
+type ReferenceImportResult struct {
+	Type      ReferenceDataType       `json:"type"`
+	DryRun    bool                    `json:"dry_run"`
+	Applied   bool                    `json:"applied"`
+	Total     int                     `json:"total"`
+	Created   int                     `json:"created"`
+	Updated   int                     `json:"updated"`
+	Restored  int                     `json:"restored"`
+	Unchanged int                     `json:"unchanged"`
+	Changes   []ReferenceRecordChange `json:"changes"`
+	Errors    []ReferenceImportIssue  `json:"errors,omitempty"`
+}
+
+type referenceFieldKind int
+
+const (
+	referenceString referenceFieldKind = iota
+	referenceBool
+	referenceInt
+	referenceFloat // Nullable
+)
+
+type referenceField struct {
+	name string
+	kind referenceFieldKind
+}
+
+// referenceFields are the importable fields of each type in export column order; the first is the key
+var referenceFields = map[ReferenceDataType][]referenceField{
+	ReferenceCountries: {
+		{"id", referenceString}, {"name", referenceString}, {"native_name", referenceString},
+		{"capital", referenceString}, {"region", referenceString}, {"subregion", referenceString},
+		{"currency", referenceString}, {"languages", referenceString}, {"calling_code", referenceString},
+		{"flag_emoji", referenceString}, {"latitude", referenceFloat}, {"longitude", referenceFloat},
+		{"active", referenceBool},
+	},
+	ReferenceStates: {
+		{"id", referenceString}, {"country_id", referenceString}, {"code", referenceString},
+		{"name", referenceString}, {"native_name", referenceString}, {"type", referenceString},
+		{"latitude", referenceFloat}, {"longitude", referenceFloat}, {"active", referenceBool},
+	},
+	ReferenceCurrencies: {
+		{"code", referenceString}, {"name", referenceString}, {"symbol", referenceString},
+		{"decimal_places", referenceInt}, {"active", referenceBool},
+	},
+	ReferenceTimezones: {
+		{"id", referenceString}, {"name", referenceString}, {"abbreviation", referenceString},
+		{"offset", referenceString}, {"dst", referenceBool}, {"countries", referenceString},
+	},
+}
+
+// referenceDefaults are the values of fields a new record does not set, matching the column defaults
+var referenceDefaults = map[ReferenceDataType]map[string]interface{}{
+	ReferenceCountries:  {"active": true},
+	ReferenceStates:     {"type": "state", "active": true},
+	ReferenceCurrencies: {"decimal_places": float64(2), "active": true},
+	ReferenceTimezones:  {"dst": false},
+}
+
+// referenceReadOnlyFields are exported but ignored on import, so exports can be imported again
+var referenceReadOnlyFields = map[string]bool{
+	"created_at": true, "updated_at": true, "localized_name": true, "states": true, "country": true,
+}
+
+// referenceUpperFields hold ISO codes, normalized to upper case
+var referenceUpperFields = map[ReferenceDataType]map[string]bool{
+	ReferenceCountries:  {"id": true, "currency": true},
+	ReferenceStates:     {"id": true, "country_id": true, "code": true},
+	ReferenceCurrencies: {"code": true},
+}
+
+var (
+	countryIDPattern    = regexp.MustCompile(`^[A-Z]{2}$`)
+	stateIDPattern      = regexp.MustCompile(`^[A-Z]{2}-[A-Z0-9]{1,7}$`)
+	currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	utcOffsetPattern    = regexp.MustCompile(`^[+-]\d{2}:\d{2}$`)
+)
+
+// referenceRow is a parsed import record with the fields it sets
+type referenceRow struct {
+	row    int
+	fields map[string]interface{}
+}
+
+// ParseReferenceDataType validates a reference data type
+func ParseReferenceDataType(value string) (ReferenceDataType, error) {
+	dataType := ReferenceDataType(strings.ToLower(strings.TrimSpace(value)))
+	if _, ok := referenceFields[dataType]; !ok {
+		return "", ErrInvalidReferenceDataType
+	}
+	return dataType, nil
+}
+
+// ExportReferenceTable exports every country, state, currency or timezone, active or not, as CSV
+// with one column per importable field, or as JSON. Both can be edited and imported again.
+func (s *LocationService) ExportReferenceTable(ctx context.Context, dataType ReferenceDataType, format string) ([]byte, error) {
+	if s.refRepo == nil {
+		return nil, ErrNoDatabase
+	}
+	if _, ok := referenceFields[dataType]; !ok {
+		return nil, ErrInvalidReferenceDataType
+	}
+	if format != ReferenceFormatCSV && format != ReferenceFormatJSON {
+		return nil, ErrInvalidReferenceFormat
+	}
+
+	data, err := s.refRepo.LoadForAdmin(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == ReferenceFormatJSON {
+		return json.MarshalIndent(referenceTable(data, dataType), "", "  ")
+	}
+
+	records, err := referenceRecordList(data, dataType)
+	if err != nil {
+		return nil, err
+	}
+	fields := referenceFields[dataType]
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = field.name
+	}
+	_ = w.Write(header)
+	for _, record := range records {
+		row := make([]string, len(fields))
+		for i, field := range fields {
+			row[i] = formatReferenceValue(record[field.name])
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to encode reference data: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportReferenceData validates a CSV or JSON file of countries, states, currencies or timezones
+// and diffs it against the current data. Records are matched by ID (currencies by code); a
+// record only changes the fields it includes, so a CSV of id,name renames without touching
+// anything else. Unless dryRun is set, the changes are applied in a single transaction, and
+// nothing is applied when any record is invalid.
+func (s *LocationService) ImportReferenceData(ctx context.Context, dataType ReferenceDataType, format string, data []byte, dryRun bool) (*ReferenceImportResult, error) {
+	if s.refRepo == nil {
+		return nil, ErrNoDatabase
+	}
+	if _, ok := referenceFields[dataType]; !ok {
+		return nil, ErrInvalidReferenceDataType
+	}
+
+	var rows []referenceRow
+	var issues []ReferenceImportIssue
+	var err error
+	switch format {
+	case ReferenceFormatCSV:
+		rows, issues, err = parseReferenceCSV(dataType, data)
+	case ReferenceFormatJSON:
+		rows, issues, err = parseReferenceJSON(dataType, data)
+	default:
+		return nil, ErrInvalidReferenceFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.refRepo.LoadForAdmin(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := referenceRecordList(current, dataType)
+	if err != nil {
+		return nil, err
+	}
+	key := referenceFields[dataType][0].name
+	existingByID := make(map[string]map[string]interface{}, len(existing))
+	for _, record := range existing {
+		if id, ok := record[key].(string); ok {
+			existingByID[id] = record
+		}
+	}
+	deleted := deletedReferenceIDs(current, dataType)
+	validator := newReferenceValidator(current)
+
+	result := &ReferenceImportResult{
+		Type:    dataType,
+		DryRun:  dryRun,
+		Total:   len(rows) + countIssueRows(issues),
+		Changes: []ReferenceRecordChange{},
+	}
+	changes := &models.ReferenceData{}
+	seen := make(map[string]int, len(rows))
+
+	for _, row := range rows {
+		if dataType == ReferenceStates {
+			deriveStateID(row.fields)
+		}
+		id, _ := row.fields[key].(string)
+		if id == "" {
+			issues = append(issues, ReferenceImportIssue{Row: row.row, Field: key, Message: key + " is required"})
+			continue
+		}
+		if first, ok := seen[id]; ok {
+			issues = append(issues, ReferenceImportIssue{Row: row.row, ID: id, Field: key, Message: fmt.Sprintf("duplicate of row %d", first)})
+			continue
+		}
+		seen[id] = row.row
+
+		base, exists := existingByID[id]
+		if !exists {
+			base = referenceDefaults[dataType]
+		}
+		merged := make(map[string]interface{}, len(base)+len(row.fields))
+		for field, value := range base {
+			merged[field] = value
+		}
+		for field, value := range row.fields {
+			merged[field] = value
+		}
+
+		record, problems := validator.validate(dataType, merged)
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				problem.Row, problem.ID = row.row, id
+				issues = append(issues, problem)
+			}
+			continue
+		}
+
+		change := ReferenceRecordChange{ID: id, Action: ReferenceActionCreate}
+		if exists {
+			change.Action = ReferenceActionUpdate
+			if deleted[id] {
+				change.Action = ReferenceActionRestore
+			}
+			for _, field := range referenceFields[dataType][1:] {
+				if !reflect.DeepEqual(base[field.name], merged[field.name]) {
+					change.Changes = append(change.Changes, ReferenceFieldChange{Field: field.name, From: base[field.name], To: merged[field.name]})
+				}
+			}
+			if len(change.Changes) == 0 && change.Action == ReferenceActionUpdate {
+				result.Unchanged++
+				continue
+			}
+		}
+
+		switch change.Action {
+		case ReferenceActionCreate:
+			result.Created++
+		case ReferenceActionUpdate:
+			result.Updated++
+		case ReferenceActionRestore:
+			result.Restored++
+		}
+		result.Changes = append(result.Changes, change)
+		addReferenceRecord(changes, record)
+	}
+
+	result.Errors = issues
+	if len(issues) > 0 {
+		if dryRun {
+			return result, nil
+		}
+		return result, ErrReferenceImportInvalid
+	}
+	if dryRun || len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	if err := s.refRepo.Apply(ctx, changes); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	s.invalidateReferenceData(ctx, dataType)
+	return result, nil
+}
+
+// invalidateReferenceData drops cached records of an imported type and the export bundle
+func (s *LocationService) invalidateReferenceData(ctx context.Context, dataType ReferenceDataType) {
+	switch dataType {
+	case ReferenceCountries:
+		if s.countryRepo != nil {
+			s.countryRepo.InvalidateCache(ctx)
+		}
+	case ReferenceStates:
+		if s.stateRepo != nil {
+			s.stateRepo.InvalidateCache(ctx)
+		}
+	case ReferenceCurrencies:
+		if s.currencyRepo != nil {
+			s.currencyRepo.InvalidateCache(ctx)
+		}
+	case ReferenceTimezones:
+		if s.timezoneRepo != nil {
+			s.timezoneRepo.InvalidateCache(ctx)
+		}
+	}
+
+	s.refExportMu.Lock()
+	s.refExport = nil
+	s.refExportMu.Unlock()
+}
+
+// parseReferenceCSV reads a CSV file with a header row of field names. Empty bool and number
+// cells leave the field unchanged; an empty latitude or longitude clears it.
+func parseReferenceCSV(dataType ReferenceDataType, data []byte) ([]referenceRow, []ReferenceImportIssue, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("%w: file is empty", ErrInvalidReferenceFile)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidReferenceFile, err)
+	}
+
+	kinds := referenceFieldKinds(dataType)
+	columns := make([]string, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := kinds[name]; !ok && !referenceReadOnlyFields[name] {
+			return nil, nil, fmt.Errorf("%w: unknown column %q", ErrInvalidReferenceFile, name)
+		}
+		columns[i] = name
+	}
+
+	var rows []referenceRow
+	var issues []ReferenceImportIssue
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidReferenceFile, err)
+		}
+
+		row := referenceRow{row: line, fields: make(map[string]interface{}, len(columns))}
+		valid := true
+		for i, name := range columns {
+			kind, ok := kinds[name]
+			if !ok {
+				continue
+			}
+			cell := strings.TrimSpace(record[i])
+			value, set, err := parseReferenceCell(kind, cell)
+			if err != nil {
+				issues = append(issues, ReferenceImportIssue{Row: line, Field: name, Message: err.Error()})
+				valid = false
+				continue
+			}
+			if set {
+				row.fields[name] = normalizeReferenceValue(dataType, name, value)
+			}
+		}
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+	return rows, issues, nil
+}
+
+// parseReferenceCell converts a CSV cell to its JSON value; set is false when the cell leaves
+// the field unchanged
+func parseReferenceCell(kind referenceFieldKind, cell string) (value interface{}, set bool, err error) {
+	switch kind {
+	case referenceBool:
+		if cell == "" {
+			return nil, false, nil
+		}
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid boolean %q", cell)
+		}
+		return b, true, nil
+	case referenceInt:
+		if cell == "" {
+			return nil, false, nil
+		}
+		n, err := strconv.Atoi(cell)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid integer %q", cell)
+		}
+		return float64(n), true, nil
+	case referenceFloat:
+		if cell == "" {
+			return nil, true, nil
+		}
+		f, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid number %q", cell)
+		}
+		return f, true, nil
+	default:
+		return cell, true, nil
+	}
+}
+
+// parseReferenceJSON reads an array of records, or an object holding the array under the type
+// name, such as an export or the reference data bundle
+func parseReferenceJSON(dataType ReferenceDataType, data []byte) ([]referenceRow, []ReferenceImportIssue, error) {
+	var records []map[string]interface{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		var bundle map[string]json.RawMessage
+		if json.Unmarshal(data, &bundle) != nil || bundle[string(dataType)] == nil {
+			return nil, nil, fmt.Errorf("%w: expected a JSON array of %s", ErrInvalidReferenceFile, dataType)
+		}
+		if err := json.Unmarshal(bundle[string(dataType)], &records); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidReferenceFile, err)
+		}
+	}
+
+	kinds := referenceFieldKinds(dataType)
+	var rows []referenceRow
+	var issues []ReferenceImportIssue
+	for i, record := range records {
+		row := referenceRow{row: i + 1, fields: make(map[string]interface{}, len(record))}
+		valid := true
+		for name, value := range record {
+			kind, ok := kinds[name]
+			if !ok {
+				if !referenceReadOnlyFields[name] {
+					issues = append(issues, ReferenceImportIssue{Row: row.row, Field: name, Message: "unknown field"})
+					valid = false
+				}
+				continue
+			}
+			if !referenceValueMatches(kind, value) {
+				issues = append(issues, ReferenceImportIssue{Row: row.row, Field: name, Message: "invalid value type"})
+				valid = false
+				continue
+			}
+			row.fields[name] = normalizeReferenceValue(dataType, name, value)
+		}
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+	return rows, issues, nil
+}
+
+// referenceValueMatches reports whether a decoded JSON value has the field's type
+func referenceValueMatches(kind referenceFieldKind, value interface{}) bool {
+	switch kind {
+	case referenceBool:
+		_, ok := value.(bool)
+		return ok
+	case referenceInt:
+		n, ok := value.(float64)
+		return ok && n == float64(int(n))
+	case referenceFloat:
+		_, ok := value.(float64)
+		return ok || value == nil
+	default:
+		_, ok := value.(string)
+		return ok
+	}
+}
+
+// normalizeReferenceValue trims strings and upper-cases ISO codes
+func normalizeReferenceValue(dataType ReferenceDataType, field string, value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	str = strings.TrimSpace(str)
+	if referenceUpperFields[dataType][field] {
+		str = strings.ToUpper(str)
+	}
+	return str
+}
+
+// deriveStateID sets a state's ID from its country and code when the row has none (US + CA -> US-CA)
+func deriveStateID(fields map[string]interface{}) {
+	if id, _ := fields["id"].(string); id != "" {
+		return
+	}
+	countryID, _ := fields["country_id"].(string)
+	code, _ := fields["code"].(string)
+	if countryID != "" && code != "" {
+		fields["id"] = countryID + "-" + code
+	}
+}
+
+// referenceValidator checks records against the current countries and currencies
+type referenceValidator struct {
+	countries  map[string]bool
+	currencies map[string]bool
+}
+
+func newReferenceValidator(current *models.ReferenceData) *referenceValidator {
+	v := &referenceValidator{countries: make(map[string]bool), currencies: make(map[string]bool)}
+	for _, country := range current.Countries {
+		if !country.DeletedAt.Valid {
+			v.countries[country.ID] = true
+		}
+	}
+	for _, currency := range current.Currencies {
+		if !currency.DeletedAt.Valid {
+			v.currencies[currency.Code] = true
+		}
+	}
+	return v
+}
+
+// validate decodes a merged record into its model and checks it
+func (v *referenceValidator) validate(dataType ReferenceDataType, fields map[string]interface{}) (interface{}, []ReferenceImportIssue) {
+	content, err := json.Marshal(fields)
+	if err != nil {
+		return nil, []ReferenceImportIssue{{Message: err.Error()}}
+	}
+
+	var issues []ReferenceImportIssue
+	check := func(ok bool, field, message string) {
+		if !ok {
+			issues = append(issues, ReferenceImportIssue{Field: field, Message: message})
+		}
+	}
+	checkCoordinates := func(lat, lng *float64) {
+		check(lat == nil || (*lat >= -90 && *lat <= 90), "latitude", "must be between -90 and 90")
+		check(lng == nil || (*lng >= -180 && *lng <= 180), "longitude", "must be between -180 and 180")
+	}
+
+	switch dataType {
+	case ReferenceCountries:
+		var country models.Country
+		if err := json.Unmarshal(content, &country); err != nil {
+			return nil, []ReferenceImportIssue{{Message: err.Error()}}
+		}
+		check(countryIDPattern.MatchString(country.ID), "id", "must be an ISO 3166-1 alpha-2 code")
+		check(country.Name != "" && len(country.Name) <= 100, "name", "is required and at most 100 characters")
+		check(country.Currency == "" || v.currencies[country.Currency], "currency", fmt.Sprintf("unknown currency %q", country.Currency))
+		check(country.Languages == "" || isJSONStringArray(country.Languages), "languages", "must be a JSON array of strings")
+		checkCoordinates(country.Latitude, country.Longitude)
+		return country, issues
+	case ReferenceStates:
+		var state models.State
+		if err := json.Unmarshal(content, &state); err != nil {
+			return nil, []ReferenceImportIssue{{Message: err.Error()}}
+		}
+		check(stateIDPattern.MatchString(state.ID), "id", "must be a country code and state code, e.g. US-CA")
+		check(state.ID == state.CountryID+"-"+state.Code, "id", "must be country_id-code")
+		check(v.countries[state.CountryID], "country_id", fmt.Sprintf("unknown country %q", state.CountryID))
+		check(state.Name != "" && len(state.Name) <= 100, "name", "is required and at most 100 characters")
+		check(state.Type != "" && len(state.Type) <= 20, "type", "is required and at most 20 characters")
+		checkCoordinates(state.Latitude, state.Longitude)
+		return models.ReferenceState{State: state}, issues
+	case ReferenceCurrencies:
+		var currency models.Currency
+		if err := json.Unmarshal(content, &currency); err != nil {
+			return nil, []ReferenceImportIssue{{Message: err.Error()}}
+		}
+		check(currencyCodePattern.MatchString(currency.Code), "code", "must be an ISO 4217 code")
+		check(currency.Name != "" && len(currency.Name) <= 100, "name", "is required and at most 100 characters")
+		check(currency.Symbol != "" && len(currency.Symbol) <= 10, "symbol", "is required and at most 10 characters")
+		check(currency.DecimalPlaces >= 0 && currency.DecimalPlaces <= 4, "decimal_places", "must be between 0 and 4")
+		return currency, issues
+	default:
+		var timezone models.Timezone
+		if err := json.Unmarshal(content, &timezone); err != nil {
+			return nil, []ReferenceImportIssue{{Message: err.Error()}}
+		}
+		check(len(timezone.ID) <= 50 && (strings.Contains(timezone.ID, "/") || timezone.ID == "UTC"), "id", "must be an IANA timezone, e.g. America/New_York")
+		check(timezone.Name != "" && len(timezone.Name) <= 100, "name", "is required and at most 100 characters")
+		check(utcOffsetPattern.MatchString(timezone.Offset), "offset", "must be a UTC offset, e.g. -05:00")
+		if timezone.Countries != "" {
+			var countries []string
+			if json.Unmarshal([]byte(timezone.Countries), &countries) != nil {
+				check(false, "countries", "must be a JSON array of country codes")
+			}
+			for _, country := range countries {
+				// Not checked against the countries table: timezones also list territories without one
+				check(countryIDPattern.MatchString(country), "countries", fmt.Sprintf("invalid country code %q", country))
+			}
+		}
+		return timezone, issues
+	}
+}
+
+// addReferenceRecord adds a validated record to the records to apply
+func addReferenceRecord(changes *models.ReferenceData, record interface{}) {
+	switch r := record.(type) {
+	case models.Country:
+		changes.Countries = append(changes.Countries, r)
+	case models.ReferenceState:
+		changes.States = append(changes.States, r)
+	case models.Currency:
+		changes.Currencies = append(changes.Currencies, r)
+	case models.Timezone:
+		changes.Timezones = append(changes.Timezones, r)
+	}
+}
+
+// referenceTable returns the records of one type
+func referenceTable(data *models.ReferenceData, dataType ReferenceDataType) interface{} {
+	switch dataType {
+	case ReferenceCountries:
+		return data.Countries
+	case ReferenceStates:
+		return data.States
+	case ReferenceCurrencies:
+		return data.Currencies
+	default:
+		return data.Timezones
+	}
+}
+
+// referenceRecordList returns the records of one type as JSON field maps
+func referenceRecordList(data *models.ReferenceData, dataType ReferenceDataType) ([]map[string]interface{}, error) {
+	content, err := json.Marshal(referenceTable(data, dataType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode reference data: %w", err)
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal(content, &records); err != nil {
+		return nil, fmt.Errorf("failed to encode reference data: %w", err)
+	}
+	return records, nil
+}
+
+// deletedReferenceIDs returns the IDs of soft-deleted records of one type
+func deletedReferenceIDs(data *models.ReferenceData, dataType ReferenceDataType) map[string]bool {
+	deleted := make(map[string]bool)
+	switch dataType {
+	case ReferenceCountries:
+		for _, country := range data.Countries {
+			if country.DeletedAt.Valid {
+				deleted[country.ID] = true
+			}
+		}
+	case ReferenceStates:
+		for _, state := range data.States {
+			if state.DeletedAt.Valid {
+				deleted[state.ID] = true
+			}
+		}
+	case ReferenceCurrencies:
+		for _, currency := range data.Currencies {
+			if currency.DeletedAt.Valid {
+				deleted[currency.Code] = true
+			}
+		}
+	case ReferenceTimezones:
+		for _, timezone := range data.Timezones {
+			if timezone.DeletedAt.Valid {
+				deleted[timezone.ID] = true
+			}
+		}
+	}
+	return deleted
+}
+
+func referenceFieldKinds(dataType ReferenceDataType) map[string]referenceFieldKind {
+	kinds := make(map[string]referenceFieldKind, len(referenceFields[dataType]))
+	for _, field := range referenceFields[dataType] {
+		kinds[field.name] = field.kind
+	}
+	return kinds
+}
+
+// formatReferenceValue formats a JSON value as a CSV cell
+func formatReferenceValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// countIssueRows counts the distinct rows rejected while parsing
+func countIssueRows(issues []ReferenceImportIssue) int {
+	rows := make(map[int]bool, len(issues))
+	for _, issue := range issues {
+		rows[issue.Row] = true
+	}
+	return len(rows)
+}
+
+func isJSONStringArray(value string) bool {
+	var values []string
+	return json.Unmarshal([]byte(value), &values) == nil
+}
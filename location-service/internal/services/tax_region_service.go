@@ -0,0 +1,500 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"location-service/internal/models"
+	"location-service/internal/repository"
+)
+
+// MaxTaxBoundaryDatasetSize bounds uploaded boundary files; detailed county and city boundaries
+// of a large country run to hundreds of megabytes
+const MaxTaxBoundaryDatasetSize = 512 << 20
+
+var (
+	// ErrInvalidTaxBoundaryDataset is returned for files that are not a GeoJSON FeatureCollection of jurisdictions
+	ErrInvalidTaxBoundaryDataset = errors.New("invalid tax boundary dataset")
+	// ErrTaxRegionLocationRequired is returned when a resolution has neither coordinates nor an address
+	ErrTaxRegionLocationRequired = errors.New("latitude and longitude, or an address, are required")
+	// ErrInvalidCoordinates is returned for coordinates out of range
+	ErrInvalidCoordinates = errors.New("latitude must be between -90 and 90 and longitude between -180 and 180")
+	// ErrAddressNotGeocoded is returned when an address to resolve could not be geocoded
+	ErrAddressNotGeocoded = errors.New("address could not be geocoded")
+)
+
+// taxJurisdictionTypes maps the type property of boundary features to jurisdiction types
+var taxJurisdictionTypes = map[string]string{
+	"country":          models.TaxJurisdictionCountry,
+	"state":            models.TaxJurisdictionState,
+	"province":         models.TaxJurisdictionState,
+	"region":           models.TaxJurisdictionState,
+	"county":           models.TaxJurisdictionCounty,
+	"parish":           models.TaxJurisdictionCounty,
+	"borough":          models.TaxJurisdictionCounty,
+	"city":             models.TaxJurisdictionCity,
+	"municipality":     models.TaxJurisdictionCity,
+	"place":            models.TaxJurisdictionCity,
+	"town":             models.TaxJurisdictionCity,
+	"special":          models.TaxJurisdictionSpecial,
+	"special_district": models.TaxJurisdictionSpecial,
+	"district":         models.TaxJurisdictionSpecial,
+}
+
+// TaxRegionQuery names the location to resolve: coordinates, or an address to geocode
+type TaxRegionQuery struct {
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	Address   string   `json:"address,omitempty"` // Geocoded when no coordinates are given
+	Country   string   `json:"country,omitempty"` // Only match jurisdictions of this country
+}
+
+// TaxBoundaryImportResult reports the jurisdictions imported per country
+type TaxBoundaryImportResult struct {
+	Countries     []TaxBoundaryCountryImport `json:"countries"`
+	Jurisdictions int                        `json:"jurisdictions"`
+	Skipped       int                        `json:"skipped"` // Features without polygons
+	Duration      string                     `json:"duration"`
+}
+
+// TaxBoundaryCountryImport reports the import of one country
+type TaxBoundaryCountryImport struct {
+	CountryID     string `json:"country_id"`
+	Jurisdictions int    `json:"jurisdictions"`
+	// Jurisdictions whose state could not be matched to a state of the country are kept
+	// without a state_id
+	UnmatchedStates int `json:"unmatched_states"`
+}
+
+// TaxRegionService resolves addresses and coordinates to the tax jurisdictions they are in,
+// from boundary data imported per country as GeoJSON. Countries without boundary data resolve
+// to the country and state from geocoding only.
+type TaxRegionService struct {
+	taxRepo   repository.TaxJurisdictionRepository
+	stateRepo repository.StateRepository
+	geotagSvc *GeoTagService
+}
+
+// NewTaxRegionService creates a new tax region service; geotagSvc geocodes addresses
+func NewTaxRegionService(taxRepo repository.TaxJurisdictionRepository, stateRepo repository.StateRepository, geotagSvc *GeoTagService) *TaxRegionService {
+	return &TaxRegionService{
+		taxRepo:   taxRepo,
+		stateRepo: stateRepo,
+		geotagSvc: geotagSvc,
+	}
+}
+
+// Resolve returns the tax jurisdictions of a location
+func (s *TaxRegionService) Resolve(ctx context.Context, query TaxRegionQuery) (*models.TaxRegion, error) {
+	countryID := strings.ToUpper(strings.TrimSpace(query.Country))
+	if countryID != "" && !countryCodePattern.MatchString(countryID) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCountryCode, query.Country)
+	}
+
+	region := &models.TaxRegion{SpecialDistricts: []models.TaxJurisdiction{}}
+	var geocoded *models.AddressComponents
+	switch address := strings.TrimSpace(query.Address); {
+	case query.Latitude != nil && query.Longitude != nil:
+		region.Latitude, region.Longitude = *query.Latitude, *query.Longitude
+	case address != "" && s.geotagSvc != nil:
+		result, _, err := s.geotagSvc.Geocode(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrAddressNotGeocoded, err)
+		}
+		region.Latitude, region.Longitude = result.Location.Latitude, result.Location.Longitude
+		region.FormattedAddress = result.FormattedAddress
+		geocoded = &result.Components
+	default:
+		return nil, ErrTaxRegionLocationRequired
+	}
+	if region.Latitude < -90 || region.Latitude > 90 || region.Longitude < -180 || region.Longitude > 180 {
+		return nil, ErrInvalidCoordinates
+	}
+
+	matched, err := s.findContaining(ctx, region.Latitude, region.Longitude, countryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The country comes from the boundaries when they match, otherwise from geocoding
+	switch {
+	case countryID != "":
+	case len(matched) > 0:
+		countryID = matched[0].CountryID
+	case geocoded != nil:
+		countryID = strings.ToUpper(geocoded.CountryCode)
+	case s.geotagSvc != nil:
+		if result, _, err := s.geotagSvc.ReverseGeocode(ctx, region.Latitude, region.Longitude); err == nil {
+			geocoded = &result.Components
+			countryID = strings.ToUpper(geocoded.CountryCode)
+		}
+	}
+	region.CountryID = countryID
+
+	if s.taxRepo != nil && countryID != "" {
+		region.Verified, err = s.taxRepo.HasCountry(ctx, countryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check tax boundary coverage: %w", err)
+		}
+	}
+
+	for _, jurisdictionType := range []string{models.TaxJurisdictionState, models.TaxJurisdictionCounty, models.TaxJurisdictionCity} {
+		jurisdiction := mostSpecificJurisdiction(matched, countryID, jurisdictionType)
+		switch jurisdictionType {
+		case models.TaxJurisdictionState:
+			region.State = jurisdiction
+		case models.TaxJurisdictionCounty:
+			region.County = jurisdiction
+		case models.TaxJurisdictionCity:
+			region.City = jurisdiction
+		}
+	}
+	for _, jurisdiction := range matched {
+		if jurisdiction.CountryID == countryID && jurisdiction.Type == models.TaxJurisdictionSpecial {
+			region.SpecialDistricts = append(region.SpecialDistricts, jurisdiction)
+		}
+	}
+
+	switch {
+	case region.State != nil:
+		region.StateID = region.State.StateID
+	case !region.Verified && geocoded != nil && geocoded.StateCode != "" && strings.EqualFold(geocoded.CountryCode, countryID):
+		region.StateID = countryID + "-" + strings.ToUpper(geocoded.StateCode)
+	}
+	region.RegionKey = taxRegionKey(region)
+	return region, nil
+}
+
+// findContaining returns the jurisdictions whose polygons contain a point
+func (s *TaxRegionService) findContaining(ctx context.Context, lat, lng float64, countryID string) ([]models.TaxJurisdiction, error) {
+	if s.taxRepo == nil {
+		return nil, nil
+	}
+	candidates, err := s.taxRepo.FindContaining(ctx, lat, lng, countryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tax jurisdictions: %w", err)
+	}
+
+	var matched []models.TaxJurisdiction
+	for _, candidate := range candidates {
+		var polygons [][][][2]float64
+		if err := json.Unmarshal([]byte(candidate.Geometry), &polygons); err != nil {
+			log.Printf("WARNING: Invalid geometry of tax jurisdiction %s %s %s: %v", candidate.CountryID, candidate.Type, candidate.Code, err)
+			continue
+		}
+		if multiPolygonContains(polygons, lat, lng) {
+			matched = append(matched, candidate)
+		}
+	}
+	return matched, nil
+}
+
+// Coverage returns the countries with imported boundary data
+func (s *TaxRegionService) Coverage(ctx context.Context) ([]models.TaxJurisdictionCoverage, error) {
+	if s.taxRepo == nil {
+		return nil, ErrNoDatabase
+	}
+	coverage, err := s.taxRepo.Coverage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax boundary coverage: %w", err)
+	}
+	if coverage == nil {
+		coverage = []models.TaxJurisdictionCoverage{}
+	}
+	return coverage, nil
+}
+
+// Import imports a GeoJSON FeatureCollection of jurisdiction boundaries. Each feature needs a
+// Polygon or MultiPolygon geometry and type, code and name properties, plus country (unless
+// countryID is set) and optionally state. Each country in the file replaces that country's
+// jurisdictions; with countryID set, other countries are skipped.
+func (s *TaxRegionService) Import(ctx context.Context, countryID string, data []byte) (*TaxBoundaryImportResult, error) {
+	if s.taxRepo == nil {
+		return nil, ErrNoDatabase
+	}
+	countryID = strings.ToUpper(strings.TrimSpace(countryID))
+	if countryID != "" && !countryCodePattern.MatchString(countryID) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCountryCode, countryID)
+	}
+
+	var collection geoJSONFeatureCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTaxBoundaryDataset, err)
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("%w: expected a GeoJSON FeatureCollection", ErrInvalidTaxBoundaryDataset)
+	}
+
+	start := time.Now()
+	result := &TaxBoundaryImportResult{Countries: []TaxBoundaryCountryImport{}}
+	byCountry := make(map[string][]models.TaxJurisdiction)
+	var countries []string
+	for i, feature := range collection.Features {
+		jurisdiction, err := taxJurisdictionFromFeature(feature, countryID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: feature %d: %v", ErrInvalidTaxBoundaryDataset, i, err)
+		}
+		if jurisdiction == nil {
+			result.Skipped++
+			continue
+		}
+		if countryID != "" && jurisdiction.CountryID != countryID {
+			continue
+		}
+		if _, ok := byCountry[jurisdiction.CountryID]; !ok {
+			countries = append(countries, jurisdiction.CountryID)
+		}
+		byCountry[jurisdiction.CountryID] = append(byCountry[jurisdiction.CountryID], *jurisdiction)
+	}
+	if len(countries) == 0 {
+		if countryID != "" {
+			return nil, fmt.Errorf("%w: no jurisdictions for %s", ErrInvalidTaxBoundaryDataset, countryID)
+		}
+		return nil, fmt.Errorf("%w: no jurisdictions", ErrInvalidTaxBoundaryDataset)
+	}
+
+	for _, country := range countries {
+		countryImport, err := s.replaceCountry(ctx, country, byCountry[country])
+		if err != nil {
+			return nil, err
+		}
+		result.Countries = append(result.Countries, *countryImport)
+		result.Jurisdictions += countryImport.Jurisdictions
+	}
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+	return result, nil
+}
+
+// replaceCountry matches the jurisdictions' states to the country's states and replaces its jurisdictions
+func (s *TaxRegionService) replaceCountry(ctx context.Context, countryID string, jurisdictions []models.TaxJurisdiction) (*TaxBoundaryCountryImport, error) {
+	states, err := countryStateIDs(ctx, s.stateRepo, countryID)
+	if err != nil {
+		return nil, err
+	}
+
+	countryImport := &TaxBoundaryCountryImport{CountryID: countryID, Jurisdictions: len(jurisdictions)}
+	for i := range jurisdictions {
+		jurisdiction := &jurisdictions[i]
+		// StateID holds the dataset's state until it is matched; a state's own state is itself
+		keys := []string{countryID + "-" + jurisdiction.StateID, jurisdiction.StateID}
+		if jurisdiction.Type == models.TaxJurisdictionState {
+			keys = []string{countryID + "-" + jurisdiction.Code, jurisdiction.Code, jurisdiction.Name}
+		}
+		hadState := jurisdiction.StateID != "" || jurisdiction.Type == models.TaxJurisdictionState
+		jurisdiction.StateID = ""
+		for _, key := range keys {
+			if id, ok := states[strings.ToUpper(key)]; ok && key != "" {
+				jurisdiction.StateID = id
+				break
+			}
+		}
+		if jurisdiction.StateID == "" && hadState {
+			countryImport.UnmatchedStates++
+		}
+	}
+
+	if err := s.taxRepo.ReplaceCountry(ctx, countryID, jurisdictions); err != nil {
+		return nil, fmt.Errorf("failed to import tax jurisdictions of %s: %w", countryID, err)
+	}
+	return countryImport, nil
+}
+
+// mostSpecificJurisdiction returns the jurisdiction of a type with the smallest bounding box,
+// for datasets where jurisdictions of one type nest or overlap
+func mostSpecificJurisdiction(jurisdictions []models.TaxJurisdiction, countryID, jurisdictionType string) *models.TaxJurisdiction {
+	var best *models.TaxJurisdiction
+	bestArea := 0.0
+	for i := range jurisdictions {
+		j := &jurisdictions[i]
+		if j.CountryID != countryID || j.Type != jurisdictionType {
+			continue
+		}
+		area := (j.MaxLatitude - j.MinLatitude) * (j.MaxLongitude - j.MinLongitude)
+		if best == nil || area < bestArea {
+			best, bestArea = j, area
+		}
+	}
+	return best
+}
+
+// taxRegionKey joins the country and jurisdiction codes into a stable identifier of the region
+func taxRegionKey(region *models.TaxRegion) string {
+	if region.CountryID == "" {
+		return ""
+	}
+	parts := []string{region.CountryID}
+	for _, jurisdiction := range []*models.TaxJurisdiction{region.State, region.County, region.City} {
+		if jurisdiction != nil {
+			parts = append(parts, jurisdiction.Type+":"+jurisdiction.Code)
+		}
+	}
+	var special []string
+	for _, jurisdiction := range region.SpecialDistricts {
+		special = append(special, jurisdiction.Type+":"+jurisdiction.Code)
+	}
+	sort.Strings(special)
+	return strings.Join(append(parts, special...), "|")
+}
+
+// ==================== GEOJSON ====================
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   *geoJSONGeometry       `json:"geometry"`
+}
+
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// taxJurisdictionFromFeature converts a boundary feature; features without polygons return nil.
+// The feature's state is returned in StateID for replaceCountry to match.
+func taxJurisdictionFromFeature(feature geoJSONFeature, defaultCountry string) (*models.TaxJurisdiction, error) {
+	if feature.Geometry == nil || (feature.Geometry.Type != "Polygon" && feature.Geometry.Type != "MultiPolygon") {
+		return nil, nil
+	}
+
+	countryID := strings.ToUpper(geoJSONProperty(feature.Properties, "country", "country_id", "country_code"))
+	if countryID == "" {
+		countryID = defaultCountry
+	}
+	if !countryCodePattern.MatchString(countryID) {
+		return nil, fmt.Errorf("country %q is not an ISO 3166-1 alpha-2 code", countryID)
+	}
+	rawType := geoJSONProperty(feature.Properties, "type", "jurisdiction_type")
+	jurisdictionType, ok := taxJurisdictionTypes[strings.ToLower(rawType)]
+	if !ok {
+		return nil, fmt.Errorf("unknown jurisdiction type %q", rawType)
+	}
+	code := geoJSONProperty(feature.Properties, "code", "fips", "geoid", "id")
+	if code == "" {
+		return nil, errors.New("code is required")
+	}
+	name := geoJSONProperty(feature.Properties, "name")
+	if name == "" {
+		name = code
+	}
+
+	var polygons [][][][]float64
+	if feature.Geometry.Type == "Polygon" {
+		var polygon [][][]float64
+		if err := json.Unmarshal(feature.Geometry.Coordinates, &polygon); err != nil {
+			return nil, fmt.Errorf("invalid polygon: %v", err)
+		}
+		polygons = [][][][]float64{polygon}
+	} else if err := json.Unmarshal(feature.Geometry.Coordinates, &polygons); err != nil {
+		return nil, fmt.Errorf("invalid multipolygon: %v", err)
+	}
+
+	jurisdiction := &models.TaxJurisdiction{
+		CountryID:    countryID,
+		StateID:      geoJSONProperty(feature.Properties, "state", "state_code", "state_id"),
+		Type:         jurisdictionType,
+		Code:         code,
+		Name:         name,
+		MinLatitude:  90,
+		MinLongitude: 180,
+		MaxLatitude:  -90,
+		MaxLongitude: -180,
+		Source:       models.TaxJurisdictionSourceGeoJSON,
+	}
+
+	// Keep [lng, lat] only, and bound the outer rings
+	geometry := make([][][][2]float64, 0, len(polygons))
+	for _, polygon := range polygons {
+		if len(polygon) == 0 {
+			continue
+		}
+		rings := make([][][2]float64, len(polygon))
+		for r, ring := range polygon {
+			if len(ring) < 4 {
+				return nil, fmt.Errorf("ring has %d positions, expected at least 4", len(ring))
+			}
+			rings[r] = make([][2]float64, len(ring))
+			for p, position := range ring {
+				if len(position) < 2 || position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+					return nil, fmt.Errorf("invalid position %v", position)
+				}
+				rings[r][p] = [2]float64{position[0], position[1]}
+				if r == 0 {
+					jurisdiction.MinLongitude = min(jurisdiction.MinLongitude, position[0])
+					jurisdiction.MaxLongitude = max(jurisdiction.MaxLongitude, position[0])
+					jurisdiction.MinLatitude = min(jurisdiction.MinLatitude, position[1])
+					jurisdiction.MaxLatitude = max(jurisdiction.MaxLatitude, position[1])
+				}
+			}
+		}
+		geometry = append(geometry, rings)
+	}
+	if len(geometry) == 0 {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(geometry)
+	if err != nil {
+		return nil, err
+	}
+	jurisdiction.Geometry = string(encoded)
+	return jurisdiction, nil
+}
+
+// geoJSONProperty returns the first of the named properties that is set, as a string (codes
+// such as FIPS are often numbers)
+func geoJSONProperty(properties map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		switch value := properties[name].(type) {
+		case string:
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		case float64:
+			return fmt.Sprintf("%.0f", value)
+		}
+	}
+	return ""
+}
+
+// multiPolygonContains reports whether a point is inside any polygon and outside its holes
+func multiPolygonContains(polygons [][][][2]float64, lat, lng float64) bool {
+	for _, polygon := range polygons {
+		if len(polygon) == 0 || !ringContains(polygon[0], lat, lng) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if ringContains(hole, lat, lng) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains is the even-odd ray casting test on [lng, lat] positions
+func ringContains(ring [][2]float64, lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		lngI, latI := ring[i][0], ring[i][1]
+		lngJ, latJ := ring[j][0], ring[j][1]
+		if (latI > lat) != (latJ > lat) && lng < (lngJ-lngI)*(lat-latI)/(latJ-latI)+lngI {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -10,17 +10,19 @@ import (
 
 // CacheCleanupConfig holds configuration for the cleanup worker
 type CacheCleanupConfig struct {
-	Interval  time.Duration // How often to run cleanup
-	BatchSize int           // How many entries to delete per batch
-	Enabled   bool          // Whether cleanup is enabled
+	Interval       time.Duration // How often to run cleanup
+	BatchSize      int           // How many entries to delete per batch
+	Enabled        bool          // Whether cleanup is enabled
+	StaleRetention time.Duration // How long expired entries are kept for stale-while-revalidate
 }
 
 // DefaultCacheCleanupConfig returns sensible defaults
 func DefaultCacheCleanupConfig() CacheCleanupConfig {
 	return CacheCleanupConfig{
-		Interval:  1 * time.Hour,
-		BatchSize: 1000,
-		Enabled:   true,
+		Interval:       1 * time.Hour,
+		BatchSize:      1000,
+		Enabled:        true,
+		StaleRetention: 24 * time.Hour,
 	}
 }
 
@@ -93,8 +95,9 @@ func (w *CacheCleanupWorker) cleanup() {
 	maxIterations := 100 // Safety limit
 
 	// Delete in batches until no more expired entries
+	cutoff := time.Now().Add(-w.config.StaleRetention)
 	for iterations < maxIterations {
-		deleted, err := w.cacheRepo.DeleteExpired(ctx, w.config.BatchSize)
+		deleted, err := w.cacheRepo.DeleteExpiredBefore(ctx, cutoff, w.config.BatchSize)
 		if err != nil {
 			log.Printf("Error during cache cleanup: %v", err)
 			break
@@ -123,9 +126,10 @@ func (w *CacheCleanupWorker) cleanup() {
 // RunOnce performs a single cleanup pass (for testing or manual triggers)
 func (w *CacheCleanupWorker) RunOnce(ctx context.Context) (int64, error) {
 	totalDeleted := int64(0)
+	cutoff := time.Now().Add(-w.config.StaleRetention)
 
 	for {
-		deleted, err := w.cacheRepo.DeleteExpired(ctx, w.config.BatchSize)
+		deleted, err := w.cacheRepo.DeleteExpiredBefore(ctx, cutoff, w.config.BatchSize)
 		if err != nil {
 			return totalDeleted, err
 		}
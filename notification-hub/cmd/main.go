@@ -12,12 +12,16 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"notification-hub/internal/cache"
 	"notification-hub/internal/config"
+	"notification-hub/internal/connlimit"
 	"notification-hub/internal/handlers"
+	"notification-hub/internal/metrics"
 	"notification-hub/internal/middleware"
 	"notification-hub/internal/models"
 	natsc "notification-hub/internal/nats"
 	"notification-hub/internal/repository"
+	"notification-hub/internal/services"
 	"notification-hub/internal/websocket"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -78,11 +82,38 @@ func main() {
 		}
 		log.Println("Successfully recreated notification_preferences table")
 	}
+
+	if err := db.AutoMigrate(&models.NotificationExport{}); err != nil {
+		log.Fatalf("Failed to auto-migrate NotificationExport: %v", err)
+	}
 	log.Println("Database migration completed")
 
 	// Initialize repositories
 	notifRepo := repository.NewNotificationRepository(db)
 	prefRepo := repository.NewPreferenceRepository(db)
+	exportRepo := repository.NewExportRepository(db)
+
+	// Initialize export service (async notification history exports)
+	exportSvc := services.NewExportService(notifRepo, exportRepo, cfg.Export)
+	exportSvc.Start(context.Background())
+
+	// Collapse historical duplicates, then enforce event idempotency with a unique index
+	go runDedupeBackfill(notifRepo)
+
+	// Initialize recent event ID cache (optional fast path for redelivery dedupe)
+	var recentEvents cache.RecentEventCache
+	var redisCache *cache.RedisRecentEventCache
+	if cfg.Redis.Enabled() {
+		redisCache, err = cache.NewRedisRecentEventCache(cfg.Redis)
+		if err != nil {
+			log.Printf("Warning: Recent event cache disabled: %v", err)
+			redisCache = nil
+		} else {
+			recentEvents = redisCache
+			defer redisCache.Close()
+			log.Println("✓ Recent event cache connected to Redis")
+		}
+	}
 
 	// Initialize WebSocket hub
 	wsHub := websocket.NewHub()
@@ -91,12 +122,35 @@ func main() {
 	// Initialize SSE hub
 	sseHub := handlers.NewSSEHub()
 
+	// Connection quotas shared by WebSocket and SSE, plus idle connection reaping
+	connLimiter := connlimit.NewLimiter(cfg.Limits)
+	reaperDone := make(chan struct{})
+	if cfg.Limits.IdleTimeout > 0 && cfg.Limits.ReapInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.Limits.ReapInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if n := wsHub.ReapIdle(cfg.Limits.IdleTimeout) + sseHub.ReapIdle(cfg.Limits.IdleTimeout); n > 0 {
+						log.Printf("Closed %d idle real-time connections", n)
+					}
+				case <-reaperDone:
+					return
+				}
+			}
+		}()
+	}
+
 	// Connect to NATS with retry
 	var natsClient *natsc.Client
 	var natsSubscriber *natsc.Subscriber
 
 	// Initialize health handler early (NATS client may be nil initially)
 	healthHandler := handlers.NewHealthHandler(db, nil)
+	if redisCache != nil {
+		healthHandler.SetRedis(redisCache)
+	}
 
 	natsClient, err = natsc.NewClient(&cfg.NATS)
 	if err != nil {
@@ -127,6 +181,7 @@ func main() {
 					wsHub:  wsHub,
 				}
 				natsSubscriber = natsc.NewSubscriber(natsClient, wsHub, notifRepo, userResolver)
+				natsSubscriber.SetRecentEventCache(recentEvents)
 				if err := natsSubscriber.Start(context.Background()); err != nil {
 					log.Printf("Warning: Failed to start NATS subscriber: %v", err)
 				} else {
@@ -148,6 +203,7 @@ func main() {
 			wsHub:  wsHub,
 		}
 		natsSubscriber = natsc.NewSubscriber(natsClient, wsHub, notifRepo, userResolver)
+		natsSubscriber.SetRecentEventCache(recentEvents)
 		if err := natsSubscriber.Start(context.Background()); err != nil {
 			log.Printf("Warning: Failed to start NATS subscriber: %v", err)
 		}
@@ -156,9 +212,10 @@ func main() {
 	// Initialize other handlers
 	notifHandler := handlers.NewNotificationHandler(notifRepo, wsHub, sseHub)
 	prefHandler := handlers.NewPreferenceHandler(prefRepo)
-	wsHandler := handlers.NewWebSocketHandler(wsHub, notifRepo, &cfg.WebSocket)
-	sseHandler := handlers.NewSSEHandler(sseHub, notifRepo)
+	wsHandler := handlers.NewWebSocketHandler(wsHub, notifRepo, &cfg.WebSocket, connLimiter)
+	sseHandler := handlers.NewSSEHandler(sseHub, notifRepo, connLimiter)
 	debugHandler := handlers.NewDebugHandler(notifRepo, cfg.App.Environment)
+	exportHandler := handlers.NewExportHandler(exportSvc, exportRepo)
 
 	// Initialize OpenTelemetry tracing
 	var tracerProvider *tracing.TracerProvider
@@ -212,6 +269,11 @@ func main() {
 			// Unread count
 			notifications.GET("/unread-count", notifHandler.GetUnreadCount)
 
+			// History export (CSV/JSON; large exports run async with a download link)
+			notifications.GET("/export", exportHandler.Export)
+			notifications.GET("/exports/:id", exportHandler.GetExport)
+			notifications.GET("/exports/:id/download", exportHandler.DownloadExport)
+
 			// Delete
 			notifications.DELETE("/:id", notifHandler.Delete)
 			notifications.DELETE("", notifHandler.DeleteAll)
@@ -270,6 +332,12 @@ func main() {
 		natsClient.Close()
 	}
 
+	// Stop export workers
+	exportSvc.Stop()
+
+	// Stop idle connection reaper
+	close(reaperDone)
+
 	// Shutdown WebSocket hub
 	wsHub.Shutdown()
 
@@ -326,6 +394,36 @@ func initDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 	return db, nil
 }
 
+// runDedupeBackfill removes duplicate notifications left by past NATS redeliveries
+// and then creates the unique index that prevents new ones. Skipped once the index exists.
+func runDedupeBackfill(notifRepo repository.NotificationRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	hasIndex, err := notifRepo.HasSourceEventUniqueIndex(ctx)
+	if err != nil {
+		log.Printf("Warning: Dedupe backfill skipped: %v", err)
+		return
+	}
+	if hasIndex {
+		return
+	}
+
+	removed, err := notifRepo.CollapseDuplicates(ctx, 1000)
+	metrics.RecordDuplicateSuppressed("all", metrics.DedupeLayerBackfill, int(removed))
+	if err != nil {
+		log.Printf("Warning: Dedupe backfill failed after removing %d duplicates: %v", removed, err)
+		return
+	}
+	log.Printf("Dedupe backfill removed %d duplicate notifications", removed)
+
+	if err := notifRepo.EnsureSourceEventUniqueIndex(ctx); err != nil {
+		log.Printf("Warning: %v (will retry on next startup)", err)
+		return
+	}
+	log.Println("✓ Notification source event unique index created")
+}
+
 // CombinedUserResolver resolves users by checking connected WebSocket and SSE clients
 type CombinedUserResolver struct {
 	sseHub *handlers.SSEHub
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"notification-hub/internal/config"
+	"notification-hub/internal/redisconn"
+)
+
+// recentEventKeyPrefix namespaces processed event IDs in Redis
+const recentEventKeyPrefix = "notification-hub:event:"
+
+// RecentEventCache remembers recently processed event IDs so JetStream
+// redeliveries can be dropped without a database round trip
+type RecentEventCache interface {
+	// Seen reports whether the event ID was processed recently
+	Seen(ctx context.Context, eventID string) (bool, error)
+	// Remember records the event ID as processed
+	Remember(ctx context.Context, eventID string) error
+}
+
+// RedisRecentEventCache is a RecentEventCache backed by Redis keys with a TTL
+type RedisRecentEventCache struct {
+	client *redisconn.Client
+	ttl    time.Duration
+}
+
+// NewRedisRecentEventCache connects to Redis and returns a recent event cache
+func NewRedisRecentEventCache(cfg config.RedisConfig) (*RedisRecentEventCache, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := redisconn.Connect(ctx, cfg.Config, "notification-hub")
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisRecentEventCache{client: client, ttl: cfg.EventTTL}, nil
+}
+
+// Seen reports whether the event ID was processed within the TTL
+func (c *RedisRecentEventCache) Seen(ctx context.Context, eventID string) (bool, error) {
+	n, err := c.client.Exists(ctx, recentEventKeyPrefix+eventID).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Remember records the event ID as processed for the TTL
+func (c *RedisRecentEventCache) Remember(ctx context.Context, eventID string) error {
+	return c.client.Set(ctx, recentEventKeyPrefix+eventID, 1, c.ttl).Err()
+}
+
+// Check pings Redis for the readiness probe
+func (c *RedisRecentEventCache) Check(ctx context.Context) error {
+	return c.client.Check(ctx)
+}
+
+// Close closes the Redis connection
+func (c *RedisRecentEventCache) Close() error {
+	return c.client.Close()
+}
@@ -6,7 +6,8 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/Tesseract-Nexus/go-shared/secrets")
+	"github.com/Tesseract-Nexus/go-shared/secrets"
+	"notification-hub/internal/redisconn")
 
 // Config holds all configuration for the notification-hub service
 type Config struct {
@@ -14,8 +15,26 @@ type Config struct {
 	Database  DatabaseConfig
 	NATS      NATSConfig
 	WebSocket WebSocketConfig
+	Limits    ConnectionLimitConfig
 	App       AppConfig
 	Auth      AuthConfig
+	Export    ExportConfig
+	Redis     RedisConfig
+}
+
+// RedisConfig holds Redis configuration for the recent event ID cache
+// (disabled unless REDIS_HOST, REDIS_ADDRS or REDIS_URL is set)
+type RedisConfig struct {
+	redisconn.Config
+	EventTTL time.Duration // How long processed event IDs are remembered
+}
+
+// ExportConfig holds notification history export configuration
+type ExportConfig struct {
+	SyncMaxRows int           // Exports above this size run async with a download link
+	MaxRows     int           // Hard cap on rows in a single export
+	LinkTTL     time.Duration // How long a generated export can be downloaded
+	Workers     int           // Concurrent async export jobs
 }
 
 // AuthConfig holds auth-bff configuration for ticket validation
@@ -51,10 +70,27 @@ type NATSConfig struct {
 type WebSocketConfig struct {
 	ReadBufferSize  int
 	WriteBufferSize int
-	PingInterval    time.Duration
-	PongWait        time.Duration
+	PingInterval    time.Duration // Also the heartbeat interval hinted to clients in the handshake
+	PongWait        time.Duration // Connections without a pong or message within this are dropped
 	WriteWait       time.Duration
 	MaxMessageSize  int64
+
+	// Reconnect backoff hinted to clients: the delay starts at ReconnectInitialDelay and is
+	// multiplied by ReconnectMultiplier per failed attempt up to ReconnectMaxDelay, with up to
+	// ReconnectJitter (a fraction of the delay) added at random
+	ReconnectInitialDelay time.Duration
+	ReconnectMaxDelay     time.Duration
+	ReconnectMultiplier   float64
+	ReconnectJitter       float64
+}
+
+// ConnectionLimitConfig holds per-tenant and per-user quotas for real-time (WebSocket + SSE) connections
+type ConnectionLimitConfig struct {
+	MaxPerTenant int           // Concurrent connections per tenant across both transports (0 = unlimited)
+	MaxPerUser   int           // Concurrent connections per user within a tenant (0 = unlimited)
+	RetryAfter   time.Duration // Retry-After hint returned with 429 responses
+	IdleTimeout  time.Duration // Connections without client messages or deliveries are closed after this (0 = never)
+	ReapInterval time.Duration // How often idle connections are reaped
 }
 
 // AppConfig holds application-specific configuration
@@ -91,6 +127,18 @@ func Load() (*Config, error) {
 			PongWait:        getEnvAsDuration("WS_PONG_WAIT", 60*time.Second),
 			WriteWait:       getEnvAsDuration("WS_WRITE_WAIT", 10*time.Second),
 			MaxMessageSize:  getEnvAsInt64("WS_MAX_MESSAGE_SIZE", 512*1024), // 512KB
+
+			ReconnectInitialDelay: getEnvAsDuration("WS_RECONNECT_INITIAL_DELAY", time.Second),
+			ReconnectMaxDelay:     getEnvAsDuration("WS_RECONNECT_MAX_DELAY", 60*time.Second),
+			ReconnectMultiplier:   getEnvAsFloat("WS_RECONNECT_MULTIPLIER", 2),
+			ReconnectJitter:       getEnvAsFloat("WS_RECONNECT_JITTER", 0.5),
+		},
+		Limits: ConnectionLimitConfig{
+			MaxPerTenant: getEnvAsInt("CONN_MAX_PER_TENANT", 500),
+			MaxPerUser:   getEnvAsInt("CONN_MAX_PER_USER", 10),
+			RetryAfter:   getEnvAsDuration("CONN_LIMIT_RETRY_AFTER", 30*time.Second),
+			IdleTimeout:  getEnvAsDuration("CONN_IDLE_TIMEOUT", 30*time.Minute),
+			ReapInterval: getEnvAsDuration("CONN_REAP_INTERVAL", time.Minute),
 		},
 		App: AppConfig{
 			Environment: getEnv("APP_ENV", "development"),
@@ -99,6 +147,20 @@ func Load() (*Config, error) {
 		Auth: AuthConfig{
 			BffURL: getEnv("AUTH_BFF_URL", "http://auth-bff.identity.svc.cluster.local:8080"),
 		},
+		Redis: RedisConfig{
+			Config: redisconn.ConfigFromEnv(redisconn.Config{
+				DialTimeout:  5 * time.Second,
+				ReadTimeout:  time.Second,
+				WriteTimeout: time.Second,
+			}),
+			EventTTL: getEnvAsDuration("DEDUPE_EVENT_TTL", 24*time.Hour),
+		},
+		Export: ExportConfig{
+			SyncMaxRows: getEnvAsInt("EXPORT_SYNC_MAX_ROWS", 1000),
+			MaxRows:     getEnvAsInt("EXPORT_MAX_ROWS", 100000),
+			LinkTTL:     getEnvAsDuration("EXPORT_LINK_TTL", 24*time.Hour),
+			Workers:     getEnvAsInt("EXPORT_WORKERS", 2),
+		},
 	}, nil
 }
 
@@ -154,3 +216,12 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
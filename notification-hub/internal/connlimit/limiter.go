@@ -0,0 +1,97 @@
+// Package connlimit enforces per-tenant and per-user quotas on real-time
+// (WebSocket and SSE) connections so a single tenant cannot exhaust the hub.
+package connlimit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"notification-hub/internal/config"
+	"notification-hub/internal/metrics"
+)
+
+// LimitError is returned when opening a connection would exceed a quota
+type LimitError struct {
+	Reason     string        // metrics.ConnectionReasonTenantLimit or metrics.ConnectionReasonUserLimit
+	Limit      int           // The quota that was reached
+	RetryAfter time.Duration // Suggested wait before reconnecting
+}
+
+func (e *LimitError) Error() string {
+	if e.Reason == metrics.ConnectionReasonUserLimit {
+		return fmt.Sprintf("too many open connections for this user (limit %d)", e.Limit)
+	}
+	return fmt.Sprintf("too many open connections for this tenant (limit %d)", e.Limit)
+}
+
+// Limiter counts open connections per tenant and per user across both transports
+type Limiter struct {
+	maxPerTenant int
+	maxPerUser   int
+	retryAfter   time.Duration
+
+	mu      sync.Mutex
+	tenants map[string]int
+	users   map[string]int // keyed by tenantID + "/" + userID
+}
+
+// NewLimiter creates a new connection limiter
+func NewLimiter(cfg config.ConnectionLimitConfig) *Limiter {
+	retryAfter := cfg.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = 30 * time.Second
+	}
+	return &Limiter{
+		maxPerTenant: cfg.MaxPerTenant,
+		maxPerUser:   cfg.MaxPerUser,
+		retryAfter:   retryAfter,
+		tenants:      make(map[string]int),
+		users:        make(map[string]int),
+	}
+}
+
+// Acquire reserves a connection slot for the user, returning a release func that must be
+// called exactly once when the connection closes (further calls are no-ops)
+func (l *Limiter) Acquire(tenantID, userID, transport string) (func(), error) {
+	userKey := tenantID + "/" + userID
+
+	l.mu.Lock()
+	if l.maxPerTenant > 0 && l.tenants[tenantID] >= l.maxPerTenant {
+		l.mu.Unlock()
+		metrics.RecordConnectionRejected(tenantID, transport, metrics.ConnectionReasonTenantLimit)
+		return nil, &LimitError{Reason: metrics.ConnectionReasonTenantLimit, Limit: l.maxPerTenant, RetryAfter: l.retryAfter}
+	}
+	if l.maxPerUser > 0 && l.users[userKey] >= l.maxPerUser {
+		l.mu.Unlock()
+		metrics.RecordConnectionRejected(tenantID, transport, metrics.ConnectionReasonUserLimit)
+		return nil, &LimitError{Reason: metrics.ConnectionReasonUserLimit, Limit: l.maxPerUser, RetryAfter: l.retryAfter}
+	}
+	l.tenants[tenantID]++
+	l.users[userKey]++
+	l.mu.Unlock()
+
+	metrics.ConnectionOpened(tenantID, transport)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			if l.tenants[tenantID]--; l.tenants[tenantID] <= 0 {
+				delete(l.tenants, tenantID)
+			}
+			if l.users[userKey]--; l.users[userKey] <= 0 {
+				delete(l.users, userKey)
+			}
+			l.mu.Unlock()
+			metrics.ConnectionClosed(tenantID, transport)
+		})
+	}, nil
+}
+
+// TenantConnections returns the number of open connections for a tenant
+func (l *Limiter) TenantConnections(tenantID string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tenants[tenantID]
+}
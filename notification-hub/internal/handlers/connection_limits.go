@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"notification-hub/internal/connlimit"
+)
+
+// acquireConnection reserves a connection slot for the caller. When a quota is reached it
+// responds with 429 and a Retry-After header and returns ok=false.
+func acquireConnection(c *gin.Context, limiter *connlimit.Limiter, tenantID, userID, transport string) (release func(), ok bool) {
+	if limiter == nil {
+		return func() {}, true
+	}
+
+	release, err := limiter.Acquire(tenantID, userID, transport)
+	if err == nil {
+		return release, true
+	}
+
+	var limitErr *connlimit.LimitError
+	if !errors.As(err, &limitErr) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to open connection"})
+		return nil, false
+	}
+
+	retryAfter := int(math.Ceil(limitErr.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":               limitErr.Error(),
+		"code":                "CONNECTION_LIMIT_EXCEEDED",
+		"reason":              limitErr.Reason,
+		"limit":               limitErr.Limit,
+		"retry_after_seconds": retryAfter,
+	})
+	return nil, false
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"notification-hub/internal/middleware"
+	"notification-hub/internal/models"
+	"notification-hub/internal/repository"
+	"notification-hub/internal/services"
+)
+
+// defaultExportWindow is the date range exported when no "from" is given
+const defaultExportWindow = 365 * 24 * time.Hour
+
+// ExportHandler handles notification history exports
+type ExportHandler struct {
+	exportSvc  *services.ExportService
+	exportRepo repository.ExportRepository
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportSvc *services.ExportService, exportRepo repository.ExportRepository) *ExportHandler {
+	return &ExportHandler{
+		exportSvc:  exportSvc,
+		exportRepo: exportRepo,
+	}
+}
+
+// Export exports a user's notification history as CSV or JSON
+// Users export their own history; tenant admins may pass user_id to export another user's.
+// Small exports are returned directly, large ones (or async=true) return 202 with a download link.
+func (h *ExportHandler) Export(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	userIDStr := c.GetString("user_id")
+	if tenantID == "" || userIDStr == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing tenant_id or user_id"})
+		return
+	}
+
+	callerID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		return
+	}
+
+	// Resolve the export subject (admin-scoped when exporting someone else)
+	subjectID := callerID
+	if target := c.Query("user_id"); target != "" {
+		subjectID, err = uuid.Parse(target)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id query parameter"})
+			return
+		}
+		if subjectID != callerID && !middleware.IsTenantAdmin(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only tenant admins can export another user's notifications"})
+			return
+		}
+	}
+
+	format := models.ExportFormat(strings.ToLower(c.DefaultQuery("format", string(models.ExportFormatCSV))))
+	if format != models.ExportFormatCSV && format != models.ExportFormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+
+	from, to, err := parseExportRange(c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req := services.ExportRequest{
+		TenantID:    tenantID,
+		UserID:      subjectID,
+		RequestedBy: callerID,
+		Format:      format,
+		From:        from,
+		To:          to,
+	}
+
+	count, err := h.exportSvc.Count(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, services.ErrExportTooLarge) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "count": count})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to prepare export"})
+		return
+	}
+
+	if c.Query("async") == "true" || h.exportSvc.ShouldRunAsync(count) {
+		export, err := h.exportSvc.Enqueue(c.Request.Context(), req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start export"})
+			return
+		}
+		export.RowCount = count
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"success":     true,
+			"message":     "Export started; you will be notified when the download is ready",
+			"data":        export,
+			"statusUrl":   fmt.Sprintf("/api/v1/notifications/exports/%s", export.ID),
+			"downloadUrl": services.DownloadPath(export.ID),
+		})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", services.FileName(req)))
+	c.Status(http.StatusOK)
+	if _, err := h.exportSvc.Write(c.Request.Context(), c.Writer, req); err != nil {
+		// Headers are already sent; the truncated file is the only signal left
+		log.Printf("Notification export streaming failed (tenant=%s, user=%s): %v", tenantID, subjectID, err)
+	}
+}
+
+// GetExport returns the status of an async export
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	export, ok := h.loadExport(c)
+	if !ok {
+		return
+	}
+
+	if export.IsDownloadable() {
+		export.DownloadURL = services.DownloadPath(export.ID)
+	}
+
+	c.JSON(http.StatusOK, models.NotificationExportResponse{
+		Success: true,
+		Data:    export,
+	})
+}
+
+// DownloadExport serves the file of a completed async export
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	export, ok := h.loadExport(c)
+	if !ok {
+		return
+	}
+
+	switch {
+	case export.Status == models.ExportStatusFailed:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Export failed: " + export.Error})
+		return
+	case export.Status != models.ExportStatusCompleted:
+		c.JSON(http.StatusConflict, gin.H{"error": "Export is not ready yet", "status": export.Status})
+		return
+	case !export.IsDownloadable():
+		c.JSON(http.StatusGone, gin.H{"error": "Export download link has expired"})
+		return
+	}
+
+	content, err := h.exportRepo.GetContent(c.Request.Context(), export.TenantID, export.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read export"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName))
+	c.Data(http.StatusOK, export.Format.ContentType(), content)
+}
+
+// loadExport fetches an export visible to the caller, writing the error response otherwise
+func (h *ExportHandler) loadExport(c *gin.Context) (*models.NotificationExport, bool) {
+	tenantID := c.GetString("tenant_id")
+	userIDStr := c.GetString("user_id")
+	if tenantID == "" || userIDStr == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing tenant_id or user_id"})
+		return nil, false
+	}
+
+	callerID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		return nil, false
+	}
+
+	exportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export ID"})
+		return nil, false
+	}
+
+	export, err := h.exportRepo.GetByID(c.Request.Context(), tenantID, exportID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get export"})
+		return nil, false
+	}
+	// Hide exports of other users unless the caller is a tenant admin
+	if export == nil || (export.RequestedBy != callerID && export.UserID != callerID && !middleware.IsTenantAdmin(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return nil, false
+	}
+
+	return export, true
+}
+
+// parseExportRange parses the from/to query parameters (RFC3339 or YYYY-MM-DD)
+// A date-only "to" covers the whole day; defaults are the last year up to now.
+func parseExportRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	to := time.Now()
+	if toStr != "" {
+		parsed, dateOnly, err := parseExportTime(toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+		}
+		to = parsed
+		if dateOnly {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+	}
+
+	from := to.Add(-defaultExportWindow)
+	if fromStr != "" {
+		parsed, _, err := parseExportTime(fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
+
+func parseExportTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, false, errors.New("expected RFC3339 timestamp or YYYY-MM-DD date")
+	}
+	return t, true, nil
+}
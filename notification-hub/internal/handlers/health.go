@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -12,6 +13,12 @@ import (
 type HealthHandler struct {
 	db         *gorm.DB
 	natsClient *natsc.Client
+	redis      redisChecker
+}
+
+// redisChecker pings Redis; the recent event cache implements it
+type redisChecker interface {
+	Check(ctx context.Context) error
 }
 
 // NewHealthHandler creates a new health handler
@@ -27,6 +34,11 @@ func (h *HealthHandler) SetNATSClient(client *natsc.Client) {
 	h.natsClient = client
 }
 
+// SetRedis adds the Redis recent event cache to the readiness checks
+func (h *HealthHandler) SetRedis(redis redisChecker) {
+	h.redis = redis
+}
+
 // Health returns basic health status
 func (h *HealthHandler) Health(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
@@ -71,6 +83,15 @@ func (h *HealthHandler) Readyz(c *gin.Context) {
 		// Don't mark service as not ready - REST API still works without NATS
 	}
 
+	// Check Redis (optional - redeliveries are still deduplicated by the database)
+	if h.redis != nil {
+		if err := h.redis.Check(c.Request.Context()); err != nil {
+			checks["redis"] = "disconnected (optional)"
+		} else {
+			checks["redis"] = "connected"
+		}
+	}
+
 	c.JSON(httpStatus, gin.H{
 		"status": status,
 		"checks": checks,
@@ -2,10 +2,10 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"notification-hub/internal/listquery"
 	"notification-hub/internal/models"
 	"notification-hub/internal/repository"
 	"notification-hub/internal/websocket"
@@ -31,7 +31,27 @@ func NewNotificationHandler(
 	}
 }
 
+// notificationListQuery declares the pagination, sorting and filtering accepted by List
+var notificationListQuery = listquery.Config{
+	DefaultLimit: 50,
+	MaxLimit:     100,
+	Sorts: map[string]string{
+		"created_at": "created_at",
+	},
+	DefaultSort: "-created_at",
+	Filters: map[string]listquery.Field{
+		"is_read":     {Column: "is_read", Type: listquery.TypeBool},
+		"type":        {Column: "type", Ops: []string{listquery.OpEq, listquery.OpIn, listquery.OpNotIn}},
+		"priority":    {Column: "priority", Ops: []string{listquery.OpEq, listquery.OpIn}},
+		"group_key":   {Column: "group_key", Ops: []string{listquery.OpEq}},
+		"entity_type": {Column: "entity_type", Ops: []string{listquery.OpEq, listquery.OpIn}},
+		"entity_id":   {Column: "entity_id", Type: listquery.TypeUUID, Ops: []string{listquery.OpEq}},
+		"created_at":  {Column: "created_at", Type: listquery.TypeTime},
+	},
+}
+
 // List returns a paginated list of notifications
+// Supports limit/offset or cursor pagination, sort=-created_at and filters such as type[in]=order,payment
 func (h *NotificationHandler) List(c *gin.Context) {
 	tenantID := c.GetString("tenant_id")
 	userIDStr := c.GetString("user_id")
@@ -46,22 +66,13 @@ func (h *NotificationHandler) List(c *gin.Context) {
 		return
 	}
 
-	// Parse filters
-	filters := repository.NotificationFilters{
-		IsRead:   parseBoolPtr(c.Query("is_read")),
-		Type:     c.Query("type"),
-		Priority: c.Query("priority"),
-		GroupKey: c.Query("group_key"),
-		Limit:    parseIntWithDefault(c.Query("limit"), 50),
-		Offset:   parseIntWithDefault(c.Query("offset"), 0),
-	}
-
-	// Validate limit
-	if filters.Limit > 100 {
-		filters.Limit = 100
+	q, err := listquery.Parse(c.Request.URL.Query(), notificationListQuery)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	notifications, total, err := h.notifRepo.List(c.Request.Context(), tenantID, userID, filters)
+	notifications, total, next, err := h.notifRepo.List(c.Request.Context(), tenantID, userID, q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list notifications"})
 		return
@@ -74,9 +85,11 @@ func (h *NotificationHandler) List(c *gin.Context) {
 		Success: true,
 		Data:    notifications,
 		Pagination: &models.Pagination{
-			Limit:  filters.Limit,
-			Offset: filters.Offset,
-			Total:  total,
+			Limit:      q.Limit,
+			Offset:     q.Offset,
+			Total:      total,
+			HasMore:    q.Meta(total, next).HasMore,
+			NextCursor: next,
 		},
 		UnreadCount: unreadCount,
 	})
@@ -384,23 +397,3 @@ func (h *NotificationHandler) DeleteAll(c *gin.Context) {
 		"unread_count": 0,
 	})
 }
-
-// Helper functions
-func parseBoolPtr(s string) *bool {
-	if s == "" {
-		return nil
-	}
-	b := s == "true" || s == "1"
-	return &b
-}
-
-func parseIntWithDefault(s string, defaultVal int) int {
-	if s == "" {
-		return defaultVal
-	}
-	val, err := strconv.Atoi(s)
-	if err != nil {
-		return defaultVal
-	}
-	return val
-}
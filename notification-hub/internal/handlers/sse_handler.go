@@ -7,10 +7,13 @@ import (
 	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"notification-hub/internal/connlimit"
+	"notification-hub/internal/metrics"
 	"notification-hub/internal/models"
 	"notification-hub/internal/repository"
 )
@@ -22,6 +25,39 @@ type SSEClient struct {
 	UserID   uuid.UUID
 	Events   chan *SSEEvent
 	Done     chan struct{}
+
+	// lastActivity is the unix-nano time of the last event delivered to the client
+	lastActivity atomic.Int64
+	closeOnce    sync.Once
+}
+
+// NewSSEClient creates a new SSE client
+func NewSSEClient(tenantID string, userID uuid.UUID) *SSEClient {
+	client := &SSEClient{
+		ID:       uuid.New().String(),
+		TenantID: tenantID,
+		UserID:   userID,
+		Events:   make(chan *SSEEvent, 256),
+		Done:     make(chan struct{}),
+	}
+	// A new stream counts as active, or the next sweep would reap it before its first event
+	client.touch()
+	return client
+}
+
+// touch records a delivery so the stream is not reaped as idle
+func (c *SSEClient) touch() {
+	c.lastActivity.Store(time.Now().UnixNano())
+}
+
+// IdleSince returns the time of the last event delivered to the client
+func (c *SSEClient) IdleSince() time.Time {
+	return time.Unix(0, c.lastActivity.Load())
+}
+
+// Close asks the stream to end; safe to call more than once
+func (c *SSEClient) Close() {
+	c.closeOnce.Do(func() { close(c.Done) })
 }
 
 // SSEEvent represents an SSE event to send
@@ -105,6 +141,7 @@ func (h *SSEHub) BroadcastToUser(tenantID string, userID uuid.UUID, notification
 		for _, client := range h.clients[tenantID][userIDStr] {
 			select {
 			case client.Events <- event:
+				client.touch()
 			default:
 				// Buffer full, skip
 				log.Printf("SSE client buffer full, skipping: client=%s", client.ID)
@@ -134,6 +171,29 @@ func (h *SSEHub) BroadcastUnreadCount(tenantID string, userID uuid.UUID, count i
 	}
 }
 
+// ReapIdle closes streams that have had no event deliveries within timeout and returns
+// how many were closed. Each stream unregisters itself when its handler returns.
+func (h *SSEHub) ReapIdle(timeout time.Duration) int {
+	cutoff := time.Now().Add(-timeout)
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	reaped := 0
+	for _, users := range h.clients {
+		for _, clients := range users {
+			for _, client := range clients {
+				if client.IdleSince().Before(cutoff) {
+					client.Close()
+					reaped++
+				}
+			}
+		}
+	}
+	metrics.RecordConnectionsReaped(metrics.TransportSSE, metrics.ConnectionReasonIdle, reaped)
+	return reaped
+}
+
 // GetConnectedUserIDs returns all connected user IDs for a tenant
 func (h *SSEHub) GetConnectedUserIDs(tenantID string) []uuid.UUID {
 	h.mu.RLock()
@@ -156,13 +216,15 @@ func (h *SSEHub) GetConnectedUserIDs(tenantID string) []uuid.UUID {
 type SSEHandler struct {
 	hub       *SSEHub
 	notifRepo repository.NotificationRepository
+	limiter   *connlimit.Limiter
 }
 
 // NewSSEHandler creates a new SSE handler
-func NewSSEHandler(hub *SSEHub, notifRepo repository.NotificationRepository) *SSEHandler {
+func NewSSEHandler(hub *SSEHub, notifRepo repository.NotificationRepository, limiter *connlimit.Limiter) *SSEHandler {
 	return &SSEHandler{
 		hub:       hub,
 		notifRepo: notifRepo,
+		limiter:   limiter,
 	}
 }
 
@@ -181,6 +243,13 @@ func (h *SSEHandler) Stream(c *gin.Context) {
 		return
 	}
 
+	// Enforce connection quotas before the stream starts
+	release, ok := acquireConnection(c, h.limiter, tenantID, userIDStr, metrics.TransportSSE)
+	if !ok {
+		return
+	}
+	defer release()
+
 	// Set SSE headers
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
@@ -188,13 +257,7 @@ func (h *SSEHandler) Stream(c *gin.Context) {
 	c.Header("X-Accel-Buffering", "no") // Disable nginx buffering
 
 	// Create client
-	client := &SSEClient{
-		ID:       uuid.New().String(),
-		TenantID: tenantID,
-		UserID:   userID,
-		Events:   make(chan *SSEEvent, 256),
-		Done:     make(chan struct{}),
-	}
+	client := NewSSEClient(tenantID, userID)
 
 	// Register client
 	h.hub.Register(client)
@@ -221,6 +284,12 @@ func (h *SSEHandler) Stream(c *gin.Context) {
 		case <-clientGone:
 			log.Printf("SSE client disconnected: %s", client.ID)
 			return
+		case <-client.Done:
+			log.Printf("SSE client closed as idle: %s", client.ID)
+			h.sendEvent(c, "idle_timeout", map[string]string{
+				"message": "Connection closed after inactivity",
+			})
+			return
 		case event, ok := <-client.Events:
 			if !ok {
 				return
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSSEHubReapIdleKeepsNewClient(t *testing.T) {
+	hub := NewSSEHub()
+	client := NewSSEClient("tenant-1", uuid.New())
+	hub.Register(client)
+
+	if reaped := hub.ReapIdle(time.Minute); reaped != 0 {
+		t.Fatalf("ReapIdle() reaped %d streams, want 0 for a new client", reaped)
+	}
+	select {
+	case <-client.Done:
+		t.Fatal("new client was closed by ReapIdle")
+	default:
+	}
+}
+
+func TestSSEHubReapIdleClosesIdleClient(t *testing.T) {
+	hub := NewSSEHub()
+	client := NewSSEClient("tenant-1", uuid.New())
+	client.lastActivity.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+	hub.Register(client)
+
+	if reaped := hub.ReapIdle(time.Minute); reaped != 1 {
+		t.Fatalf("ReapIdle() reaped %d streams, want 1", reaped)
+	}
+	select {
+	case <-client.Done:
+	default:
+		t.Fatal("idle client was not closed")
+	}
+}
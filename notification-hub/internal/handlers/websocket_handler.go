@@ -4,11 +4,14 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"notification-hub/internal/config"
+	"notification-hub/internal/connlimit"
+	"notification-hub/internal/metrics"
 	"notification-hub/internal/repository"
 	ws "notification-hub/internal/websocket"
 )
@@ -16,6 +19,7 @@ import (
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	Subprotocols:    ws.Subprotocols(),
 	CheckOrigin: func(r *http.Request) bool {
 		// In production, validate origin
 		return true
@@ -27,6 +31,7 @@ type WebSocketHandler struct {
 	hub       *ws.Hub
 	notifRepo repository.NotificationRepository
 	config    *config.WebSocketConfig
+	limiter   *connlimit.Limiter
 }
 
 // NewWebSocketHandler creates a new WebSocket handler
@@ -34,15 +39,18 @@ func NewWebSocketHandler(
 	hub *ws.Hub,
 	notifRepo repository.NotificationRepository,
 	cfg *config.WebSocketConfig,
+	limiter *connlimit.Limiter,
 ) *WebSocketHandler {
 	return &WebSocketHandler{
 		hub:       hub,
 		notifRepo: notifRepo,
 		config:    cfg,
+		limiter:   limiter,
 	}
 }
 
-// Handle upgrades HTTP connection to WebSocket
+// Handle upgrades HTTP connection to WebSocket. The protocol version is negotiated through the
+// "notification-hub.v<N>" subprotocol or the protocol_version query parameter (default 1).
 func (h *WebSocketHandler) Handle(c *gin.Context) {
 	tenantID := c.GetString("tenant_id")
 	userIDStr := c.GetString("user_id")
@@ -57,15 +65,34 @@ func (h *WebSocketHandler) Handle(c *gin.Context) {
 		return
 	}
 
+	// Enforce connection quotas before upgrading so rejected clients get a plain 429
+	release, ok := acquireConnection(c, h.limiter, tenantID, userIDStr, metrics.TransportWebSocket)
+	if !ok {
+		return
+	}
+
 	// Upgrade to WebSocket
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
+		release()
 		log.Printf("Failed to upgrade WebSocket: %v", err)
 		return
 	}
 
+	// Unsupported versions are refused with a close code clients can act on, unlike a failed upgrade
+	protocolVersion, err := ws.NegotiateProtocolVersion(conn.Subprotocol(), c.Query("protocol_version"))
+	if err != nil {
+		closeMsg := websocket.FormatCloseMessage(ws.CloseUnsupportedProtocol, "unsupported protocol version")
+		conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(h.config.WriteWait))
+		conn.Close()
+		release()
+		log.Printf("Rejected WebSocket connection: tenant=%s, user=%s: %v", tenantID, userIDStr, err)
+		return
+	}
+
 	// Create client
-	client := ws.NewClient(h.hub, conn, tenantID, userID, h.config)
+	client := ws.NewClient(h.hub, conn, tenantID, userID, h.config, protocolVersion)
+	client.OnClose = release
 
 	// Set up message handlers
 	client.OnMarkRead = func(notificationIDs []string) {
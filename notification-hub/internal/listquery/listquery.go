@@ -0,0 +1,369 @@
+// Package listquery parses the pagination, sorting and filtering parameters
+// accepted by list endpoints and turns them into GORM scopes.
+//
+// Supported query parameters:
+//
+//	page, limit (aliases: page_size, per_page), offset  - offset pagination
+//	cursor                                              - cursor pagination (empty value starts at the first page)
+//	sort=-created_at,name                               - whitelisted sort keys, "-" for descending
+//	status=active, status[in]=a,b, created_at[gte]=...  - whitelisted filters with operators
+//
+// The package only depends on GORM and the standard library so it can move to
+// go-shared unchanged. Until it is published there, tenant-service and
+// notification-hub carry identical copies; change both together.
+package listquery
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pagination modes
+const (
+	ModeOffset = "offset"
+	ModeCursor = "cursor"
+)
+
+// Filter operators
+const (
+	OpEq     = "eq"
+	OpNe     = "ne"
+	OpIn     = "in"
+	OpNotIn  = "nin"
+	OpGt     = "gt"
+	OpGte    = "gte"
+	OpLt     = "lt"
+	OpLte    = "lte"
+	OpLike   = "like"
+	OpIsNull = "null"
+)
+
+// Field value types
+const (
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeFloat  = "float"
+	TypeBool   = "bool"
+	TypeTime   = "time"
+	TypeUUID   = "uuid"
+)
+
+// defaultOps are the operators allowed for a field type when Field.Ops is empty
+var defaultOps = map[string][]string{
+	TypeString: {OpEq, OpNe, OpIn, OpNotIn, OpLike, OpIsNull},
+	TypeInt:    {OpEq, OpNe, OpIn, OpNotIn, OpGt, OpGte, OpLt, OpLte, OpIsNull},
+	TypeFloat:  {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIsNull},
+	TypeBool:   {OpEq, OpNe, OpIsNull},
+	TypeTime:   {OpEq, OpGt, OpGte, OpLt, OpLte, OpIsNull},
+	TypeUUID:   {OpEq, OpNe, OpIn, OpNotIn, OpIsNull},
+}
+
+// Field describes a filterable query parameter
+type Field struct {
+	Column string   // Database column, e.g. "created_at"
+	Type   string   // One of the Type* constants (default: TypeString)
+	Ops    []string // Allowed operators (default: all operators valid for the type)
+}
+
+// Config declares what a list endpoint accepts
+type Config struct {
+	DefaultLimit int               // Page size when none is requested (default: 20)
+	MaxLimit     int               // Upper bound for the page size (default: 100)
+	Sorts        map[string]string // Sort key -> column
+	DefaultSort  string            // Sort applied when none is requested, e.g. "-created_at"
+	Filters      map[string]Field  // Filter parameter -> field
+	TieBreaker   string            // Unique column appended to the sort for stable pages (default: "id")
+}
+
+// Sort is a parsed sort key
+type Sort struct {
+	Key    string
+	Column string
+	Desc   bool
+}
+
+// Filter is a parsed filter condition
+type Filter struct {
+	Key    string
+	Column string
+	Op     string
+	Value  interface{}
+}
+
+// Query is a parsed list request
+type Query struct {
+	Mode    string
+	Page    int
+	Limit   int
+	Offset  int
+	Sorts   []Sort
+	Filters []Filter
+
+	cursor     *cursor
+	tieBreaker string
+}
+
+// Error describes an invalid list query parameter
+type Error struct {
+	Param   string `json:"param"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid query parameter %q: %s", e.Param, e.Message)
+}
+
+// IsError checks if an error is a list query Error
+func IsError(err error) (*Error, bool) {
+	var queryErr *Error
+	if errors.As(err, &queryErr) {
+		return queryErr, true
+	}
+	return nil, false
+}
+
+// Parse parses list query parameters against the endpoint configuration
+func Parse(values url.Values, cfg Config) (*Query, error) {
+	if cfg.DefaultLimit <= 0 {
+		cfg.DefaultLimit = 20
+	}
+	if cfg.MaxLimit <= 0 {
+		cfg.MaxLimit = 100
+	}
+	if cfg.TieBreaker == "" {
+		cfg.TieBreaker = "id"
+	}
+
+	q := &Query{Mode: ModeOffset, Page: 1, tieBreaker: cfg.TieBreaker}
+
+	limit, err := parseLimit(values, cfg)
+	if err != nil {
+		return nil, err
+	}
+	q.Limit = limit
+
+	sortParam := values.Get("sort")
+	if sortParam == "" {
+		sortParam = cfg.DefaultSort
+	}
+	if q.Sorts, err = parseSorts(sortParam, cfg); err != nil {
+		return nil, err
+	}
+
+	if q.Filters, err = parseFilters(values, cfg); err != nil {
+		return nil, err
+	}
+
+	if _, ok := values["cursor"]; ok {
+		q.Mode = ModeCursor
+		if len(q.Sorts) > 1 {
+			return nil, &Error{Param: "sort", Message: "cursor pagination supports a single sort key"}
+		}
+		if token := values.Get("cursor"); token != "" {
+			c, err := decodeCursor(token)
+			if err != nil {
+				return nil, &Error{Param: "cursor", Message: "malformed cursor"}
+			}
+			if c.Sort != q.sortSignature() {
+				return nil, &Error{Param: "cursor", Message: "cursor was issued for a different sort order"}
+			}
+			q.cursor = c
+		}
+		return q, nil
+	}
+
+	if raw := values.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return nil, &Error{Param: "offset", Message: "must be a non-negative integer"}
+		}
+		q.Offset = offset
+		q.Page = offset/q.Limit + 1
+		return q, nil
+	}
+
+	if raw := values.Get("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return nil, &Error{Param: "page", Message: "must be a positive integer"}
+		}
+		q.Page = page
+	}
+	q.Offset = (q.Page - 1) * q.Limit
+
+	return q, nil
+}
+
+// FilterValue returns the value of the first filter on key with the given operator
+func (q *Query) FilterValue(key, op string) (interface{}, bool) {
+	for _, f := range q.Filters {
+		if f.Key == key && f.Op == op {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+func parseLimit(values url.Values, cfg Config) (int, error) {
+	for _, param := range []string{"limit", "page_size", "per_page"} {
+		raw := values.Get(param)
+		if raw == "" {
+			continue
+		}
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return 0, &Error{Param: param, Message: "must be a positive integer"}
+		}
+		if limit > cfg.MaxLimit {
+			limit = cfg.MaxLimit
+		}
+		return limit, nil
+	}
+	return cfg.DefaultLimit, nil
+}
+
+func parseSorts(raw string, cfg Config) ([]Sort, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var sorts []Sort
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		desc := strings.HasPrefix(part, "-")
+		key := strings.TrimPrefix(strings.TrimPrefix(part, "-"), "+")
+		column, ok := cfg.Sorts[key]
+		if !ok {
+			return nil, &Error{Param: "sort", Message: fmt.Sprintf("cannot sort by %q (allowed: %s)", key, strings.Join(sortedKeys(cfg.Sorts), ", "))}
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		sorts = append(sorts, Sort{Key: key, Column: column, Desc: desc})
+	}
+	return sorts, nil
+}
+
+func parseFilters(values url.Values, cfg Config) ([]Filter, error) {
+	var filters []Filter
+	for param, raws := range values {
+		key, op := param, OpEq
+		if i := strings.Index(param, "["); i > 0 && strings.HasSuffix(param, "]") {
+			key, op = param[:i], strings.ToLower(param[i+1:len(param)-1])
+		}
+
+		field, ok := cfg.Filters[key]
+		if !ok {
+			if key != param {
+				return nil, &Error{Param: param, Message: fmt.Sprintf("cannot filter by %q", key)}
+			}
+			// Plain parameters that are not filters (page, sort, ...) are left to the caller
+			continue
+		}
+		if field.Type == "" {
+			field.Type = TypeString
+		}
+		if field.Column == "" {
+			field.Column = key
+		}
+
+		allowed := field.Ops
+		if len(allowed) == 0 {
+			allowed = defaultOps[field.Type]
+		}
+		if !contains(allowed, op) {
+			return nil, &Error{Param: param, Message: fmt.Sprintf("operator %q is not supported (allowed: %s)", op, strings.Join(allowed, ", "))}
+		}
+
+		for _, raw := range raws {
+			value, err := parseFilterValue(field.Type, op, raw)
+			if err != nil {
+				return nil, &Error{Param: param, Message: err.Error()}
+			}
+			filters = append(filters, Filter{Key: key, Column: field.Column, Op: op, Value: value})
+		}
+	}
+	return filters, nil
+}
+
+func parseFilterValue(fieldType, op, raw string) (interface{}, error) {
+	switch op {
+	case OpIsNull:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("must be true or false")
+		}
+		return b, nil
+	case OpIn, OpNotIn:
+		parts := strings.Split(raw, ",")
+		list := make([]interface{}, 0, len(parts))
+		for _, part := range parts {
+			v, err := parseScalar(fieldType, strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case OpLike:
+		return raw, nil
+	}
+	return parseScalar(fieldType, raw)
+}
+
+func parseScalar(fieldType, raw string) (interface{}, error) {
+	switch fieldType {
+	case TypeInt:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, errors.New("must be an integer")
+		}
+		return n, nil
+	case TypeFloat:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		return f, nil
+	case TypeBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("must be true or false")
+		}
+		return b, nil
+	case TypeTime:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		if t, err := time.Parse("2006-01-02", raw); err == nil {
+			return t, nil
+		}
+		return nil, errors.New("must be an RFC3339 timestamp or YYYY-MM-DD date")
+	case TypeUUID:
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, errors.New("must be a UUID")
+		}
+		return id, nil
+	}
+	return raw, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
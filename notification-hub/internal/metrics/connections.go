@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Real-time transports
+const (
+	TransportWebSocket = "websocket"
+	TransportSSE       = "sse"
+)
+
+// Reasons a real-time connection is refused or closed by the hub
+const (
+	ConnectionReasonTenantLimit = "tenant_limit"
+	ConnectionReasonUserLimit   = "user_limit"
+	ConnectionReasonIdle        = "idle"
+)
+
+var (
+	activeConnections = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tesseract",
+			Subsystem: "notification_hub",
+			Name:      "active_connections",
+			Help:      "Open real-time connections, by tenant and transport",
+		},
+		[]string{"tenant_id", "transport"},
+	)
+
+	connectionsRejected = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tesseract",
+			Subsystem: "notification_hub",
+			Name:      "connections_rejected_total",
+			Help:      "Real-time connections refused with 429, by tenant, transport and limit",
+		},
+		[]string{"tenant_id", "transport", "reason"},
+	)
+
+	connectionsReaped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tesseract",
+			Subsystem: "notification_hub",
+			Name:      "connections_reaped_total",
+			Help:      "Real-time connections closed by the hub, by transport and reason",
+		},
+		[]string{"transport", "reason"},
+	)
+)
+
+// ConnectionOpened counts a new real-time connection for a tenant
+func ConnectionOpened(tenantID, transport string) {
+	activeConnections.WithLabelValues(tenantID, transport).Inc()
+}
+
+// ConnectionClosed removes a closed real-time connection from the tenant's count
+func ConnectionClosed(tenantID, transport string) {
+	activeConnections.WithLabelValues(tenantID, transport).Dec()
+}
+
+// RecordConnectionRejected counts a connection refused because a quota was reached
+func RecordConnectionRejected(tenantID, transport, reason string) {
+	connectionsRejected.WithLabelValues(tenantID, transport, reason).Inc()
+}
+
+// RecordConnectionsReaped counts connections closed by the hub
+func RecordConnectionsReaped(transport, reason string, count int) {
+	if count <= 0 {
+		return
+	}
+	connectionsReaped.WithLabelValues(transport, reason).Add(float64(count))
+}
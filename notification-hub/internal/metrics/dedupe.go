@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Layers at which a duplicate event can be suppressed
+const (
+	DedupeLayerCache    = "cache"        // Redis recent event ID cache
+	DedupeLayerDatabase = "database"     // Existing notification with the same source event ID
+	DedupeLayerIndex    = "unique_index" // Concurrent redelivery rejected by the unique index
+	DedupeLayerBackfill = "backfill"     // Historical duplicate removed by the backfill job
+)
+
+var duplicateEventsSuppressed = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "tesseract",
+		Subsystem: "notification_hub",
+		Name:      "duplicate_events_suppressed_total",
+		Help:      "Duplicate notifications suppressed, by event stream and dedupe layer",
+	},
+	[]string{"stream", "layer"},
+)
+
+// RecordDuplicateSuppressed counts duplicates suppressed for a stream at a dedupe layer
+func RecordDuplicateSuppressed(stream, layer string, count int) {
+	if count <= 0 {
+		return
+	}
+	duplicateEventsSuppressed.WithLabelValues(stream, layer).Add(float64(count))
+}
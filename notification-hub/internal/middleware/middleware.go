@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -96,6 +97,8 @@ func TenantAuth() gin.HandlerFunc {
 			if tenantID != "" && userID != "" {
 				c.Set("tenant_id", tenantID)
 				c.Set("user_id", userID)
+				c.Set("user_roles", parseRolesHeader(c.GetHeader("x-jwt-claim-roles")))
+				c.Set("is_platform_owner", c.GetHeader("x-jwt-claim-platform-owner") == "true")
 				c.Next()
 				return
 			}
@@ -179,6 +182,47 @@ func OptionalTenantAuth() gin.HandlerFunc {
 	}
 }
 
+// adminRoles are the roles allowed to act on other users' notifications within their tenant
+var adminRoles = map[string]bool{
+	"owner":        true,
+	"store_owner":  true,
+	"admin":        true,
+	"tenant_admin": true,
+	"super_admin":  true,
+}
+
+// parseRolesHeader parses the Istio roles claim (JSON array or comma separated)
+func parseRolesHeader(header string) []string {
+	if header == "" {
+		return nil
+	}
+	if strings.HasPrefix(header, "[") {
+		var roles []string
+		if err := json.Unmarshal([]byte(header), &roles); err == nil {
+			return roles
+		}
+	}
+	roles := strings.Split(header, ",")
+	for i := range roles {
+		roles[i] = strings.TrimSpace(roles[i])
+	}
+	return roles
+}
+
+// IsTenantAdmin reports whether the authenticated user is an admin of their tenant
+// Only Istio-verified role claims are considered
+func IsTenantAdmin(c *gin.Context) bool {
+	if c.GetBool("is_platform_owner") {
+		return true
+	}
+	for _, role := range c.GetStringSlice("user_roles") {
+		if adminRoles[strings.ToLower(role)] {
+			return true
+		}
+	}
+	return false
+}
+
 // Logger logs request details
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
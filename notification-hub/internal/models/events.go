@@ -36,6 +36,9 @@ const (
 	// Review events
 	SubjectReviewSubmitted = "review.submitted"
 	SubjectReviewApproved  = "review.approved"
+
+	// Notification engagement events (published by notification-service)
+	SubjectEmailOpened = "notification.email.opened"
 )
 
 // Stream names
@@ -46,6 +49,8 @@ const (
 	StreamCustomerEvents  = "CUSTOMER_EVENTS"
 	StreamReturnEvents    = "RETURN_EVENTS"
 	StreamReviewEvents    = "REVIEW_EVENTS"
+
+	StreamNotificationEvents = "NOTIFICATION_EVENTS"
 )
 
 // BaseEvent is the common structure for all events
@@ -119,6 +124,22 @@ type ReviewEvent struct {
 	Rating      int    `json:"rating"`
 }
 
+// EmailOpenedEvent reports that a user opened the email sent for an event
+type EmailOpenedEvent struct {
+	EventType      string    `json:"eventType"`
+	TenantID       string    `json:"tenantId"`
+	Timestamp      time.Time `json:"timestamp"`
+	NotificationID string    `json:"notificationId"` // notification-service email ID
+	UserID         string    `json:"userId"`
+	SourceEventID  string    `json:"sourceEventId"` // sourceId of the event the email was sent for
+	OpenedAt       time.Time `json:"openedAt"`
+}
+
+// InAppSourceEventIDs returns the SourceEventIDs the in-app copies of the email were stored under
+func (e *EmailOpenedEvent) InAppSourceEventIDs() []string {
+	return []string{e.SourceEventID, e.SourceEventID + "-customer"}
+}
+
 // EventToNotification converts an event to a notification
 func EventToNotification(event interface{}, targetUserID uuid.UUID) *Notification {
 	switch e := event.(type) {
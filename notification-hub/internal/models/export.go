@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExportFormat is the file format of a notification history export
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatJSON ExportFormat = "json"
+)
+
+// ExportStatus is the processing state of an async export
+type ExportStatus string
+
+const (
+	ExportStatusPending    ExportStatus = "pending"
+	ExportStatusProcessing ExportStatus = "processing"
+	ExportStatusCompleted  ExportStatus = "completed"
+	ExportStatusFailed     ExportStatus = "failed"
+)
+
+// NotificationExport is an async export of a user's notification history
+// The generated file is kept until ExpiresAt and served through the download link
+type NotificationExport struct {
+	ID          uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string       `json:"tenantId" gorm:"column:tenant_id;type:varchar(255);not null;index:idx_notification_exports_tenant_user"`
+	UserID      uuid.UUID    `json:"userId" gorm:"column:user_id;type:uuid;not null;index:idx_notification_exports_tenant_user"` // Subject of the export
+	RequestedBy uuid.UUID    `json:"requestedBy" gorm:"column:requested_by;type:uuid;not null"`                                  // User or admin who requested it
+	Format      ExportFormat `json:"format" gorm:"type:varchar(10);not null"`
+	FromDate    time.Time    `json:"from" gorm:"column:from_date;not null"`
+	ToDate      time.Time    `json:"to" gorm:"column:to_date;not null"`
+	Status      ExportStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	RowCount    int64        `json:"rowCount" gorm:"column:row_count;default:0"`
+	FileName    string       `json:"fileName,omitempty" gorm:"column:file_name;type:varchar(255)"`
+	FileSize    int64        `json:"fileSize" gorm:"column:file_size;default:0"`
+	Content     []byte       `json:"-" gorm:"type:bytea"`
+	Error       string       `json:"error,omitempty" gorm:"type:text"`
+	DownloadURL string       `json:"downloadUrl,omitempty" gorm:"-"`
+	CreatedAt   time.Time    `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+	CompletedAt *time.Time   `json:"completedAt,omitempty" gorm:"column:completed_at"`
+	ExpiresAt   *time.Time   `json:"expiresAt,omitempty" gorm:"column:expires_at;index"`
+}
+
+// TableName returns the table name for the NotificationExport model
+func (NotificationExport) TableName() string {
+	return "notification_exports"
+}
+
+// BeforeCreate sets default values before creating an export
+func (e *NotificationExport) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.Status == "" {
+		e.Status = ExportStatusPending
+	}
+	return nil
+}
+
+// IsDownloadable reports whether the export file is ready and not yet expired
+func (e *NotificationExport) IsDownloadable() bool {
+	if e.Status != ExportStatusCompleted {
+		return false
+	}
+	return e.ExpiresAt == nil || e.ExpiresAt.After(time.Now())
+}
+
+// ContentType returns the MIME type of the export file
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatJSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// NotificationExportResponse is the API response for an export job
+type NotificationExportResponse struct {
+	Success bool                `json:"success"`
+	Data    *NotificationExport `json:"data,omitempty"`
+	Message string              `json:"message,omitempty"`
+}
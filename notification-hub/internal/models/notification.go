@@ -123,9 +123,11 @@ type NotificationListResponse struct {
 
 // Pagination holds pagination info
 type Pagination struct {
-	Limit  int   `json:"limit"`
-	Offset int   `json:"offset"`
-	Total  int64 `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Total      int64  `json:"total"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // UnreadCountResponse is the API response for unread count
@@ -163,6 +163,16 @@ func (c *Client) ensureStreams() error {
 			MaxMsgs:     100000,
 			Discard:     nats.DiscardOld,
 		},
+		{
+			Name:        "NOTIFICATION_EVENTS",
+			Description: "Notification engagement events",
+			Subjects:    []string{"notification.>"},
+			Storage:     nats.FileStorage,
+			Retention:   nats.LimitsPolicy,
+			MaxAge:      24 * time.Hour * 7,
+			MaxMsgs:     100000,
+			Discard:     nats.DiscardOld,
+		},
 	}
 
 	for _, streamCfg := range streams {
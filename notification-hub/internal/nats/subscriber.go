@@ -8,6 +8,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nats-io/nats.go"
+	"notification-hub/internal/cache"
+	"notification-hub/internal/metrics"
 	"notification-hub/internal/models"
 	"notification-hub/internal/repository"
 	"notification-hub/internal/websocket"
@@ -32,6 +34,7 @@ type Subscriber struct {
 	hub          *websocket.Hub
 	notifRepo    repository.NotificationRepository
 	userResolver TargetUserResolver
+	recentEvents cache.RecentEventCache // Optional fast path for redelivery dedupe
 	subs         []*nats.Subscription
 }
 
@@ -54,6 +57,11 @@ func NewSubscriber(
 	}
 }
 
+// SetRecentEventCache sets the cache of recently processed event IDs
+func (s *Subscriber) SetRecentEventCache(recentEvents cache.RecentEventCache) {
+	s.recentEvents = recentEvents
+}
+
 // Start begins subscribing to all event streams
 func (s *Subscriber) Start(ctx context.Context) error {
 	js := s.client.JetStream()
@@ -178,6 +186,26 @@ func (s *Subscriber) Start(ctx context.Context) error {
 		log.Println("Subscribed to review.> events")
 	}
 
+	// Subscribe to email opens so in-app notifications are marked read
+	emailOpenedSub, err := js.QueueSubscribe(
+		models.SubjectEmailOpened,
+		"notification-hub-workers",
+		s.handleEmailOpened,
+		nats.BindStream(models.StreamNotificationEvents),
+		nats.Durable("notification-hub-email-opens"),
+		nats.DeliverNew(),
+		nats.ManualAck(),
+		nats.AckWait(30*time.Second),
+		nats.MaxDeliver(3),
+		nats.InactiveThreshold(24*time.Hour),
+	)
+	if err != nil {
+		log.Printf("Warning: failed to subscribe to email opened events: %v", err)
+	} else {
+		s.subs = append(s.subs, emailOpenedSub)
+		log.Printf("Subscribed to %s events", models.SubjectEmailOpened)
+	}
+
 	log.Printf("NATS subscriber started with %d subscriptions", len(s.subs))
 	return nil
 }
@@ -201,13 +229,13 @@ func (s *Subscriber) handleOrderEvent(msg *nats.Msg) {
 	}
 
 	// Check for deduplication (admin notification)
-	exists, err := s.notifRepo.ExistsBySourceEventID(context.Background(), event.SourceID)
+	duplicate, err := s.isDuplicate(context.Background(), "order", event.SourceID)
 	if err != nil {
 		log.Printf("Failed to check for duplicate: %v", err)
 		msg.Nak()
 		return
 	}
-	if exists {
+	if duplicate {
 		log.Printf("Duplicate event ignored: %s", event.SourceID)
 		msg.Ack()
 		return
@@ -221,9 +249,7 @@ func (s *Subscriber) handleOrderEvent(msg *nats.Msg) {
 		log.Printf("No connected admin users for tenant %s, storing broadcast notification", event.TenantID)
 		notification := models.EventToNotification(&event, uuid.Nil)
 		if notification != nil {
-			if err := s.notifRepo.Create(context.Background(), notification); err != nil {
-				log.Printf("Failed to create broadcast notification: %v", err)
-			} else {
+			if s.createNotification(context.Background(), "order", notification) {
 				log.Printf("Created admin broadcast notification for tenant %s", event.TenantID)
 			}
 		}
@@ -233,8 +259,7 @@ func (s *Subscriber) handleOrderEvent(msg *nats.Msg) {
 			if notification == nil {
 				continue
 			}
-			if err := s.notifRepo.Create(context.Background(), notification); err != nil {
-				log.Printf("Failed to create admin notification: %v", err)
+			if !s.createNotification(context.Background(), "order", notification) {
 				continue
 			}
 			s.hub.BroadcastToUser(event.TenantID, userID, notification)
@@ -251,9 +276,7 @@ func (s *Subscriber) handleOrderEvent(msg *nats.Msg) {
 		if err == nil {
 			customerNotif := models.CustomerEventToNotification(&event, customerID)
 			if customerNotif != nil {
-				if err := s.notifRepo.Create(context.Background(), customerNotif); err != nil {
-					log.Printf("Failed to create customer notification: %v", err)
-				} else {
+				if s.createNotification(context.Background(), "order", customerNotif) {
 					log.Printf("Created customer notification for %s: %s", customerID, event.EventType)
 					// Broadcast to customer if connected
 					s.hub.BroadcastToUser(event.TenantID, customerID, customerNotif)
@@ -264,6 +287,7 @@ func (s *Subscriber) handleOrderEvent(msg *nats.Msg) {
 		}
 	}
 
+	s.markProcessed(context.Background(), event.SourceID)
 	msg.Ack()
 	log.Printf("Processed order event: %s", event.EventType)
 }
@@ -276,8 +300,13 @@ func (s *Subscriber) handlePaymentEvent(msg *nats.Msg) {
 		return
 	}
 
-	exists, _ := s.notifRepo.ExistsBySourceEventID(context.Background(), event.SourceID)
-	if exists {
+	duplicate, err := s.isDuplicate(context.Background(), "payment", event.SourceID)
+	if err != nil {
+		log.Printf("Failed to check for duplicate payment event: %v", err)
+		msg.Nak()
+		return
+	}
+	if duplicate {
 		msg.Ack()
 		return
 	}
@@ -287,7 +316,7 @@ func (s *Subscriber) handlePaymentEvent(msg *nats.Msg) {
 		// Store as broadcast notification
 		notification := models.EventToNotification(&event, uuid.Nil)
 		if notification != nil {
-			s.notifRepo.Create(context.Background(), notification)
+			s.createNotification(context.Background(), "payment", notification)
 		}
 	} else {
 		for _, userID := range targetUsers {
@@ -295,7 +324,7 @@ func (s *Subscriber) handlePaymentEvent(msg *nats.Msg) {
 			if notification == nil {
 				continue
 			}
-			if err := s.notifRepo.Create(context.Background(), notification); err != nil {
+			if !s.createNotification(context.Background(), "payment", notification) {
 				continue
 			}
 			s.hub.BroadcastToUser(event.TenantID, userID, notification)
@@ -304,6 +333,7 @@ func (s *Subscriber) handlePaymentEvent(msg *nats.Msg) {
 		}
 	}
 
+	s.markProcessed(context.Background(), event.SourceID)
 	msg.Ack()
 	log.Printf("Processed payment event: %s", event.EventType)
 }
@@ -315,8 +345,13 @@ func (s *Subscriber) handleInventoryEvent(msg *nats.Msg) {
 		return
 	}
 
-	exists, _ := s.notifRepo.ExistsBySourceEventID(context.Background(), event.SourceID)
-	if exists {
+	duplicate, err := s.isDuplicate(context.Background(), "inventory", event.SourceID)
+	if err != nil {
+		log.Printf("Failed to check for duplicate inventory event: %v", err)
+		msg.Nak()
+		return
+	}
+	if duplicate {
 		msg.Ack()
 		return
 	}
@@ -325,7 +360,7 @@ func (s *Subscriber) handleInventoryEvent(msg *nats.Msg) {
 	if len(targetUsers) == 0 {
 		notification := models.EventToNotification(&event, uuid.Nil)
 		if notification != nil {
-			s.notifRepo.Create(context.Background(), notification)
+			s.createNotification(context.Background(), "inventory", notification)
 		}
 	} else {
 		for _, userID := range targetUsers {
@@ -333,13 +368,16 @@ func (s *Subscriber) handleInventoryEvent(msg *nats.Msg) {
 			if notification == nil {
 				continue
 			}
-			s.notifRepo.Create(context.Background(), notification)
+			if !s.createNotification(context.Background(), "inventory", notification) {
+				continue
+			}
 			s.hub.BroadcastToUser(event.TenantID, userID, notification)
 			count, _ := s.notifRepo.GetUnreadCount(context.Background(), event.TenantID, userID)
 			s.hub.BroadcastUnreadCount(event.TenantID, userID, int(count))
 		}
 	}
 
+	s.markProcessed(context.Background(), event.SourceID)
 	msg.Ack()
 	log.Printf("Processed inventory event: %s", event.EventType)
 }
@@ -351,8 +389,13 @@ func (s *Subscriber) handleCustomerEvent(msg *nats.Msg) {
 		return
 	}
 
-	exists, _ := s.notifRepo.ExistsBySourceEventID(context.Background(), event.SourceID)
-	if exists {
+	duplicate, err := s.isDuplicate(context.Background(), "customer", event.SourceID)
+	if err != nil {
+		log.Printf("Failed to check for duplicate customer event: %v", err)
+		msg.Nak()
+		return
+	}
+	if duplicate {
 		msg.Ack()
 		return
 	}
@@ -361,7 +404,7 @@ func (s *Subscriber) handleCustomerEvent(msg *nats.Msg) {
 	if len(targetUsers) == 0 {
 		notification := models.EventToNotification(&event, uuid.Nil)
 		if notification != nil {
-			s.notifRepo.Create(context.Background(), notification)
+			s.createNotification(context.Background(), "customer", notification)
 		}
 	} else {
 		for _, userID := range targetUsers {
@@ -369,13 +412,16 @@ func (s *Subscriber) handleCustomerEvent(msg *nats.Msg) {
 			if notification == nil {
 				continue
 			}
-			s.notifRepo.Create(context.Background(), notification)
+			if !s.createNotification(context.Background(), "customer", notification) {
+				continue
+			}
 			s.hub.BroadcastToUser(event.TenantID, userID, notification)
 			count, _ := s.notifRepo.GetUnreadCount(context.Background(), event.TenantID, userID)
 			s.hub.BroadcastUnreadCount(event.TenantID, userID, int(count))
 		}
 	}
 
+	s.markProcessed(context.Background(), event.SourceID)
 	msg.Ack()
 	log.Printf("Processed customer event: %s", event.EventType)
 }
@@ -387,8 +433,13 @@ func (s *Subscriber) handleReturnEvent(msg *nats.Msg) {
 		return
 	}
 
-	exists, _ := s.notifRepo.ExistsBySourceEventID(context.Background(), event.SourceID)
-	if exists {
+	duplicate, err := s.isDuplicate(context.Background(), "return", event.SourceID)
+	if err != nil {
+		log.Printf("Failed to check for duplicate return event: %v", err)
+		msg.Nak()
+		return
+	}
+	if duplicate {
 		msg.Ack()
 		return
 	}
@@ -398,7 +449,7 @@ func (s *Subscriber) handleReturnEvent(msg *nats.Msg) {
 	if len(targetUsers) == 0 {
 		notification := models.EventToNotification(&event, uuid.Nil)
 		if notification != nil {
-			s.notifRepo.Create(context.Background(), notification)
+			s.createNotification(context.Background(), "return", notification)
 		}
 	} else {
 		for _, userID := range targetUsers {
@@ -406,7 +457,9 @@ func (s *Subscriber) handleReturnEvent(msg *nats.Msg) {
 			if notification == nil {
 				continue
 			}
-			s.notifRepo.Create(context.Background(), notification)
+			if !s.createNotification(context.Background(), "return", notification) {
+				continue
+			}
 			s.hub.BroadcastToUser(event.TenantID, userID, notification)
 			count, _ := s.notifRepo.GetUnreadCount(context.Background(), event.TenantID, userID)
 			s.hub.BroadcastUnreadCount(event.TenantID, userID, int(count))
@@ -419,9 +472,7 @@ func (s *Subscriber) handleReturnEvent(msg *nats.Msg) {
 		if err == nil {
 			customerNotif := models.CustomerEventToNotification(&event, customerID)
 			if customerNotif != nil {
-				if err := s.notifRepo.Create(context.Background(), customerNotif); err != nil {
-					log.Printf("Failed to create customer return notification: %v", err)
-				} else {
+				if s.createNotification(context.Background(), "return", customerNotif) {
 					log.Printf("Created customer return notification for %s: %s", customerID, event.EventType)
 					s.hub.BroadcastToUser(event.TenantID, customerID, customerNotif)
 					count, _ := s.notifRepo.GetUnreadCount(context.Background(), event.TenantID, customerID)
@@ -431,6 +482,7 @@ func (s *Subscriber) handleReturnEvent(msg *nats.Msg) {
 		}
 	}
 
+	s.markProcessed(context.Background(), event.SourceID)
 	msg.Ack()
 	log.Printf("Processed return event: %s", event.EventType)
 }
@@ -442,8 +494,13 @@ func (s *Subscriber) handleReviewEvent(msg *nats.Msg) {
 		return
 	}
 
-	exists, _ := s.notifRepo.ExistsBySourceEventID(context.Background(), event.SourceID)
-	if exists {
+	duplicate, err := s.isDuplicate(context.Background(), "review", event.SourceID)
+	if err != nil {
+		log.Printf("Failed to check for duplicate review event: %v", err)
+		msg.Nak()
+		return
+	}
+	if duplicate {
 		msg.Ack()
 		return
 	}
@@ -452,7 +509,7 @@ func (s *Subscriber) handleReviewEvent(msg *nats.Msg) {
 	if len(targetUsers) == 0 {
 		notification := models.EventToNotification(&event, uuid.Nil)
 		if notification != nil {
-			s.notifRepo.Create(context.Background(), notification)
+			s.createNotification(context.Background(), "review", notification)
 		}
 	} else {
 		for _, userID := range targetUsers {
@@ -460,17 +517,112 @@ func (s *Subscriber) handleReviewEvent(msg *nats.Msg) {
 			if notification == nil {
 				continue
 			}
-			s.notifRepo.Create(context.Background(), notification)
+			if !s.createNotification(context.Background(), "review", notification) {
+				continue
+			}
 			s.hub.BroadcastToUser(event.TenantID, userID, notification)
 			count, _ := s.notifRepo.GetUnreadCount(context.Background(), event.TenantID, userID)
 			s.hub.BroadcastUnreadCount(event.TenantID, userID, int(count))
 		}
 	}
 
+	s.markProcessed(context.Background(), event.SourceID)
 	msg.Ack()
 	log.Printf("Processed review event: %s", event.EventType)
 }
 
+// handleEmailOpened marks the in-app copy of an opened email as read
+func (s *Subscriber) handleEmailOpened(msg *nats.Msg) {
+	var event models.EmailOpenedEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Printf("Failed to unmarshal email opened event: %v", err)
+		msg.Ack()
+		return
+	}
+
+	userID, err := uuid.Parse(event.UserID)
+	if err != nil || userID == uuid.Nil || event.TenantID == "" || event.SourceEventID == "" {
+		log.Printf("Ignoring email opened event without tenant, user or source event: %s", event.NotificationID)
+		msg.Ack()
+		return
+	}
+
+	ctx := context.Background()
+	ids, err := s.notifRepo.MarkReadBySourceEventIDs(ctx, event.TenantID, userID, event.InAppSourceEventIDs())
+	if err != nil {
+		log.Printf("Failed to mark notifications read for opened email %s: %v", event.NotificationID, err)
+		msg.Nak()
+		return
+	}
+
+	if len(ids) > 0 {
+		notificationIDs := make([]string, len(ids))
+		for i, id := range ids {
+			notificationIDs[i] = id.String()
+		}
+		s.hub.BroadcastReadStatus(event.TenantID, userID, notificationIDs, true)
+		count, _ := s.notifRepo.GetUnreadCount(ctx, event.TenantID, userID)
+		s.hub.BroadcastUnreadCount(event.TenantID, userID, int(count))
+		log.Printf("Marked %d notification(s) read from opened email %s", len(ids), event.NotificationID)
+	}
+
+	msg.Ack()
+}
+
+// isDuplicate reports whether an event was already turned into notifications
+// Checks the Redis recent-ID cache first and falls back to the database
+func (s *Subscriber) isDuplicate(ctx context.Context, stream, sourceID string) (bool, error) {
+	if sourceID == "" {
+		return false, nil
+	}
+
+	if s.recentEvents != nil {
+		seen, err := s.recentEvents.Seen(ctx, sourceID)
+		if err != nil {
+			log.Printf("Recent event cache lookup failed, falling back to database: %v", err)
+		} else if seen {
+			metrics.RecordDuplicateSuppressed(stream, metrics.DedupeLayerCache, 1)
+			return true, nil
+		}
+	}
+
+	exists, err := s.notifRepo.ExistsBySourceEventID(ctx, sourceID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		metrics.RecordDuplicateSuppressed(stream, metrics.DedupeLayerDatabase, 1)
+		s.markProcessed(ctx, sourceID)
+		return true, nil
+	}
+	return false, nil
+}
+
+// createNotification stores a notification unless a concurrent redelivery already did
+// Returns true only when a new row was written and should be pushed to clients
+func (s *Subscriber) createNotification(ctx context.Context, stream string, notification *models.Notification) bool {
+	created, err := s.notifRepo.CreateIfAbsent(ctx, notification)
+	if err != nil {
+		log.Printf("Failed to create %s notification: %v", stream, err)
+		return false
+	}
+	if !created {
+		metrics.RecordDuplicateSuppressed(stream, metrics.DedupeLayerIndex, 1)
+		return false
+	}
+	return true
+}
+
+// markProcessed remembers the event ID so redeliveries are dropped from the cache
+func (s *Subscriber) markProcessed(ctx context.Context, sourceID string) {
+	if s.recentEvents == nil || sourceID == "" {
+		return
+	}
+	if err := s.recentEvents.Remember(ctx, sourceID); err != nil {
+		log.Printf("Failed to remember processed event %s: %v", sourceID, err)
+	}
+}
+
 // getTargetUsers returns the list of users who should receive notifications for a tenant
 func (s *Subscriber) getTargetUsers(tenantID string) []uuid.UUID {
 	// Get connected users from WebSocket/SSE hubs
@@ -0,0 +1,249 @@
+package redisconn
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// checkTimeout bounds a health check when the caller's context has no deadline
+const checkTimeout = 2 * time.Second
+
+// Client is a go-redis client built from a Config. It is a redis.UniversalClient, so the
+// same code runs against a standalone server, a Sentinel-managed master or a cluster.
+type Client struct {
+	redis.UniversalClient
+	name string
+	mode Mode
+	pool *poolCollector
+}
+
+// New builds a client for cfg without connecting to Redis. name identifies the client in
+// metrics, usually the service name.
+func New(cfg Config, name string) (*Client, error) {
+	mode := cfg.Mode
+	if mode == "" {
+		mode = ModeStandalone
+	}
+
+	opts, err := universalOptions(cfg, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	var client redis.UniversalClient
+	switch mode {
+	case ModeStandalone:
+		client = redis.NewClient(opts.Simple())
+	case ModeSentinel:
+		if opts.MasterName == "" {
+			return nil, errors.New("redis: sentinel mode requires a master name")
+		}
+		client = redis.NewFailoverClient(opts.Failover())
+	case ModeCluster:
+		client = redis.NewClusterClient(opts.Cluster())
+	}
+
+	c := &Client{UniversalClient: client, name: name, mode: mode}
+	client.AddHook(metricsHook{name: name})
+	c.pool = registerPoolCollector(name, client)
+	return c, nil
+}
+
+// Connect builds a client for cfg and checks that Redis answers, closing the client when
+// it does not
+func Connect(ctx context.Context, cfg Config, name string) (*Client, error) {
+	client, err := New(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Check(ctx); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return client, nil
+}
+
+// Mode reports how the client reaches Redis
+func (c *Client) Mode() Mode {
+	return c.mode
+}
+
+// Check pings Redis (every master in cluster mode) and records the outcome in the
+// tesseract_redis_up gauge. It is meant for readiness and health endpoints.
+func (c *Client) Check(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, checkTimeout)
+		defer cancel()
+	}
+
+	err := c.ForEachShard(ctx, func(ctx context.Context, shard *redis.Client) error {
+		return shard.Ping(ctx).Err()
+	})
+	if err != nil {
+		up.WithLabelValues(c.name).Set(0)
+		return err
+	}
+	up.WithLabelValues(c.name).Set(1)
+	return nil
+}
+
+// ForEachShard calls fn for every master holding keys: each cluster master, or the single
+// server otherwise. Commands that only see one node's keys, such as SCAN, need it to cover
+// a cluster.
+func (c *Client) ForEachShard(ctx context.Context, fn func(ctx context.Context, shard *redis.Client) error) error {
+	switch client := c.UniversalClient.(type) {
+	case *redis.ClusterClient:
+		return client.ForEachMaster(ctx, fn)
+	case *redis.Client:
+		return fn(ctx, client)
+	default:
+		return fmt.Errorf("redis: unsupported client %T", client)
+	}
+}
+
+// Single returns the underlying *redis.Client for APIs that cannot take a cluster client;
+// ok is false in cluster mode
+func (c *Client) Single() (client *redis.Client, ok bool) {
+	client, ok = c.UniversalClient.(*redis.Client)
+	return client, ok
+}
+
+// Close closes the connections and stops reporting pool metrics for the client
+func (c *Client) Close() error {
+	c.pool.unregister()
+	return c.UniversalClient.Close()
+}
+
+// universalOptions merges the URL, when set, with the discrete settings of cfg
+func universalOptions(cfg Config, mode Mode) (*redis.UniversalOptions, error) {
+	opts := &redis.UniversalOptions{}
+
+	if cfg.URL != "" {
+		if err := applyURL(opts, cfg.URL, mode); err != nil {
+			return nil, err
+		}
+	} else {
+		if len(cfg.Addrs) == 0 {
+			return nil, errors.New("redis: no URL or address configured")
+		}
+		opts.Addrs = cfg.Addrs
+		opts.DB = cfg.DB
+	}
+
+	if cfg.MasterName != "" {
+		opts.MasterName = cfg.MasterName
+	}
+	if cfg.Username != "" {
+		opts.Username = cfg.Username
+	}
+	if cfg.Password != "" {
+		opts.Password = cfg.Password
+	}
+	if cfg.SentinelPassword != "" {
+		opts.SentinelPassword = cfg.SentinelPassword
+	}
+	if cfg.PoolSize > 0 {
+		opts.PoolSize = cfg.PoolSize
+	}
+	if cfg.DialTimeout > 0 {
+		opts.DialTimeout = cfg.DialTimeout
+	}
+	if cfg.ReadTimeout > 0 {
+		opts.ReadTimeout = cfg.ReadTimeout
+	}
+	if cfg.WriteTimeout > 0 {
+		opts.WriteTimeout = cfg.WriteTimeout
+	}
+
+	tlsConfig, err := buildTLSConfig(cfg.TLS, opts.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	opts.TLSConfig = tlsConfig
+	return opts, nil
+}
+
+// applyURL parses rawURL with the go-redis parser for mode
+func applyURL(opts *redis.UniversalOptions, rawURL string, mode Mode) error {
+	switch mode {
+	case ModeStandalone:
+		parsed, err := redis.ParseURL(rawURL)
+		if err != nil {
+			return fmt.Errorf("redis: invalid URL: %w", err)
+		}
+		opts.Addrs = []string{parsed.Addr}
+		opts.Username, opts.Password, opts.DB = parsed.Username, parsed.Password, parsed.DB
+		opts.TLSConfig = parsed.TLSConfig
+		opts.PoolSize = parsed.PoolSize
+		opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = parsed.DialTimeout, parsed.ReadTimeout, parsed.WriteTimeout
+	case ModeSentinel:
+		parsed, err := redis.ParseFailoverURL(rawURL)
+		if err != nil {
+			return fmt.Errorf("redis: invalid sentinel URL: %w", err)
+		}
+		opts.Addrs, opts.MasterName = parsed.SentinelAddrs, parsed.MasterName
+		opts.Username, opts.Password, opts.DB = parsed.Username, parsed.Password, parsed.DB
+		opts.SentinelUsername, opts.SentinelPassword = parsed.SentinelUsername, parsed.SentinelPassword
+		opts.TLSConfig = parsed.TLSConfig
+		opts.PoolSize = parsed.PoolSize
+		opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = parsed.DialTimeout, parsed.ReadTimeout, parsed.WriteTimeout
+	case ModeCluster:
+		parsed, err := redis.ParseClusterURL(rawURL)
+		if err != nil {
+			return fmt.Errorf("redis: invalid cluster URL: %w", err)
+		}
+		opts.Addrs = parsed.Addrs
+		opts.Username, opts.Password = parsed.Username, parsed.Password
+		opts.TLSConfig = parsed.TLSConfig
+		opts.PoolSize = parsed.PoolSize
+		opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = parsed.DialTimeout, parsed.ReadTimeout, parsed.WriteTimeout
+	default:
+		return fmt.Errorf("redis: unknown mode %q (use standalone, sentinel or cluster)", mode)
+	}
+	return nil
+}
+
+// buildTLSConfig applies settings on top of the TLS configuration a rediss:// URL produced;
+// it returns nil when TLS is off
+func buildTLSConfig(settings TLSConfig, fromURL *tls.Config) (*tls.Config, error) {
+	if !settings.Enabled && fromURL == nil {
+		return nil, nil
+	}
+
+	tlsConfig := fromURL
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	if tlsConfig.MinVersion < tls.VersionTLS12 {
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+	if settings.ServerName != "" {
+		tlsConfig.ServerName = settings.ServerName
+	}
+	if settings.InsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if settings.CAFile != "" {
+		pem, err := os.ReadFile(settings.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("redis: failed to read TLS CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("redis: TLS CA file contains no certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
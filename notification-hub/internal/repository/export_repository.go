@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"notification-hub/internal/models"
+)
+
+// ExportRepository defines the interface for notification export job data access
+type ExportRepository interface {
+	Create(ctx context.Context, export *models.NotificationExport) error
+	GetByID(ctx context.Context, tenantID string, id uuid.UUID) (*models.NotificationExport, error)
+	GetContent(ctx context.Context, tenantID string, id uuid.UUID) ([]byte, error)
+	ListUnfinished(ctx context.Context) ([]models.NotificationExport, error)
+	MarkProcessing(ctx context.Context, id uuid.UUID) error
+	MarkCompleted(ctx context.Context, id uuid.UUID, fileName string, content []byte, rowCount int64, expiresAt time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, errMsg string, expiresAt time.Time) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type exportRepository struct {
+	db *gorm.DB
+}
+
+// NewExportRepository creates a new export repository
+func NewExportRepository(db *gorm.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+// Create creates a new export job
+func (r *exportRepository) Create(ctx context.Context, export *models.NotificationExport) error {
+	if err := r.db.WithContext(ctx).Create(export).Error; err != nil {
+		return fmt.Errorf("failed to create export: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an export job (without its file content) with tenant isolation
+func (r *exportRepository) GetByID(ctx context.Context, tenantID string, id uuid.UUID) (*models.NotificationExport, error) {
+	var export models.NotificationExport
+	err := r.db.WithContext(ctx).
+		Omit("content").
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&export).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export: %w", err)
+	}
+	return &export, nil
+}
+
+// GetContent retrieves the generated file of an export
+func (r *exportRepository) GetContent(ctx context.Context, tenantID string, id uuid.UUID) ([]byte, error) {
+	var export models.NotificationExport
+	err := r.db.WithContext(ctx).
+		Select("content").
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&export).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export content: %w", err)
+	}
+	return export.Content, nil
+}
+
+// ListUnfinished returns pending and processing exports (used to resume after restarts)
+func (r *exportRepository) ListUnfinished(ctx context.Context) ([]models.NotificationExport, error) {
+	var exports []models.NotificationExport
+	err := r.db.WithContext(ctx).
+		Omit("content").
+		Where("status IN ?", []models.ExportStatus{models.ExportStatusPending, models.ExportStatusProcessing}).
+		Order("created_at ASC").
+		Find(&exports).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unfinished exports: %w", err)
+	}
+	return exports, nil
+}
+
+// MarkProcessing marks an export as being generated
+func (r *exportRepository) MarkProcessing(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).
+		Model(&models.NotificationExport{}).
+		Where("id = ?", id).
+		Update("status", models.ExportStatusProcessing).Error; err != nil {
+		return fmt.Errorf("failed to update export status: %w", err)
+	}
+	return nil
+}
+
+// MarkCompleted stores the generated file and marks the export as downloadable
+func (r *exportRepository) MarkCompleted(ctx context.Context, id uuid.UUID, fileName string, content []byte, rowCount int64, expiresAt time.Time) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).
+		Model(&models.NotificationExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.ExportStatusCompleted,
+			"file_name":    fileName,
+			"content":      content,
+			"file_size":    len(content),
+			"row_count":    rowCount,
+			"error":        "",
+			"completed_at": now,
+			"expires_at":   expiresAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to complete export: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records an export failure; the record is kept until expiresAt so the requester can see why
+func (r *exportRepository) MarkFailed(ctx context.Context, id uuid.UUID, errMsg string, expiresAt time.Time) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).
+		Model(&models.NotificationExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.ExportStatusFailed,
+			"error":        errMsg,
+			"completed_at": now,
+			"expires_at":   expiresAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to mark export failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes exports whose download link has expired
+func (r *exportRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at IS NOT NULL AND expires_at < ?", time.Now()).
+		Delete(&models.NotificationExport{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired exports: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
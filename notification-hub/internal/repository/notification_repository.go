@@ -6,35 +6,31 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"notification-hub/internal/listquery"
 	"notification-hub/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// NotificationFilters holds filtering options for listing notifications
-type NotificationFilters struct {
-	IsRead     *bool
-	Type       string
-	Priority   string
-	GroupKey   string
-	EntityType string
-	From       *time.Time
-	To         *time.Time
-	Limit      int
-	Offset     int
-}
-
 // NotificationRepository defines the interface for notification data access
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *models.Notification) error
 	GetByID(ctx context.Context, tenantID string, userID uuid.UUID, id uuid.UUID) (*models.Notification, error)
-	List(ctx context.Context, tenantID string, userID uuid.UUID, filters NotificationFilters) ([]models.Notification, int64, error)
+	List(ctx context.Context, tenantID string, userID uuid.UUID, q *listquery.Query) ([]models.Notification, int64, string, error)
 	MarkAsRead(ctx context.Context, tenantID string, userID uuid.UUID, ids []uuid.UUID) error
 	MarkAsUnread(ctx context.Context, tenantID string, userID uuid.UUID, id uuid.UUID) error
 	MarkAllAsRead(ctx context.Context, tenantID string, userID uuid.UUID) (int64, error)
+	MarkReadBySourceEventIDs(ctx context.Context, tenantID string, userID uuid.UUID, sourceEventIDs []string) ([]uuid.UUID, error)
 	GetUnreadCount(ctx context.Context, tenantID string, userID uuid.UUID) (int64, error)
 	Delete(ctx context.Context, tenantID string, userID uuid.UUID, id uuid.UUID) error
 	DeleteAll(ctx context.Context, tenantID string, userID uuid.UUID) (int64, error)
 	ExistsBySourceEventID(ctx context.Context, sourceEventID string) (bool, error)
+	CreateIfAbsent(ctx context.Context, notification *models.Notification) (bool, error)
+	CollapseDuplicates(ctx context.Context, batchSize int) (int64, error)
+	EnsureSourceEventUniqueIndex(ctx context.Context) error
+	HasSourceEventUniqueIndex(ctx context.Context) (bool, error)
+	CountForExport(ctx context.Context, tenantID string, userID uuid.UUID, from, to time.Time) (int64, error)
+	StreamForExport(ctx context.Context, tenantID string, userID uuid.UUID, from, to time.Time, batchSize int, fn func([]models.Notification) error) error
 }
 
 type notificationRepository struct {
@@ -70,65 +66,33 @@ func (r *notificationRepository) GetByID(ctx context.Context, tenantID string, u
 	return &notification, nil
 }
 
-// List retrieves notifications with filtering and pagination
-func (r *notificationRepository) List(ctx context.Context, tenantID string, userID uuid.UUID, filters NotificationFilters) ([]models.Notification, int64, error) {
+// List retrieves notifications matching the list query
+func (r *notificationRepository) List(ctx context.Context, tenantID string, userID uuid.UUID, q *listquery.Query) ([]models.Notification, int64, string, error) {
 	// Include both user-specific notifications AND broadcast notifications (uuid.Nil)
 	// Only return in_app notifications (exclude email, push, sms which are handled by notification-service)
 	query := r.db.WithContext(ctx).Model(&models.Notification{}).
 		Where("tenant_id = ? AND (user_id = ? OR user_id = ?)", tenantID, userID, uuid.Nil).
 		Where("channel = ?", "in_app").
-		Where("is_archived = ?", false)
-
-	// Apply filters
-	if filters.IsRead != nil {
-		query = query.Where("is_read = ?", *filters.IsRead)
-	}
-	if filters.Type != "" {
-		query = query.Where("type = ?", filters.Type)
-	}
-	if filters.Priority != "" {
-		query = query.Where("priority = ?", filters.Priority)
-	}
-	if filters.GroupKey != "" {
-		query = query.Where("group_key = ?", filters.GroupKey)
-	}
-	if filters.EntityType != "" {
-		query = query.Where("entity_type = ?", filters.EntityType)
-	}
-	if filters.From != nil {
-		query = query.Where("created_at >= ?", filters.From)
-	}
-	if filters.To != nil {
-		query = query.Where("created_at <= ?", filters.To)
-	}
+		Where("is_archived = ?", false).
+		Scopes(q.FilterScope())
 
 	// Count total
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
-	}
-
-	// Apply pagination
-	if filters.Limit < 1 {
-		filters.Limit = 20
-	}
-	if filters.Limit > 100 {
-		filters.Limit = 100
+		return nil, 0, "", fmt.Errorf("failed to count notifications: %w", err)
 	}
 
 	// Fetch results
 	var notifications []models.Notification
-	err := query.
-		Order("created_at DESC").
-		Offset(filters.Offset).
-		Limit(filters.Limit).
-		Find(&notifications).Error
+	if err := query.Scopes(q.PageScope()).Find(&notifications).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to list notifications: %w", err)
+	}
 
+	notifications, next, err := listquery.NextPage(q, notifications)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+		return nil, 0, "", err
 	}
-
-	return notifications, total, nil
+	return notifications, total, next, nil
 }
 
 // MarkAsRead marks notifications as read (including broadcast notifications)
@@ -183,6 +147,34 @@ func (r *notificationRepository) MarkAllAsRead(ctx context.Context, tenantID str
 	return result.RowsAffected, nil
 }
 
+// MarkReadBySourceEventIDs marks a user's unread in-app notifications for the given source events as read
+// Broadcast notifications are left alone since other users still need to see them. Returns the IDs marked read.
+func (r *notificationRepository) MarkReadBySourceEventIDs(ctx context.Context, tenantID string, userID uuid.UUID, sourceEventIDs []string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("tenant_id = ? AND user_id = ? AND source_event_id IN ? AND channel = ? AND is_read = ?", tenantID, userID, sourceEventIDs, "in_app", false).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notifications for source events: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id IN ? AND is_read = ?", ids, false).
+		Updates(map[string]interface{}{
+			"is_read": true,
+			"read_at": time.Now(),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to mark notifications as read: %w", result.Error)
+	}
+	return ids, nil
+}
+
 // GetUnreadCount returns the count of unread notifications for a user
 func (r *notificationRepository) GetUnreadCount(ctx context.Context, tenantID string, userID uuid.UUID) (int64, error) {
 	var count int64
@@ -248,3 +240,157 @@ func (r *notificationRepository) ExistsBySourceEventID(ctx context.Context, sour
 	}
 	return count > 0, nil
 }
+
+// CreateIfAbsent creates a notification unless one already exists for the same
+// tenant, user and source event. Returns false when the insert was suppressed.
+// Relies on idx_notifications_source_event_unique (see EnsureSourceEventUniqueIndex).
+func (r *notificationRepository) CreateIfAbsent(ctx context.Context, notification *models.Notification) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(notification)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create notification: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CollapseDuplicates removes historical duplicate notifications created by event redelivery
+// The earliest row per (tenant, user, source event) is kept and inherits read status from
+// its duplicates. Deletes in batches and returns the number of rows removed.
+func (r *notificationRepository) CollapseDuplicates(ctx context.Context, batchSize int) (int64, error) {
+	// Carry read status over to the surviving row so users don't see read items reappear
+	if err := r.db.WithContext(ctx).Exec(`
+		UPDATE notifications n
+		SET is_read = TRUE, read_at = d.first_read_at
+		FROM (
+			SELECT tenant_id, user_id, source_event_id, MIN(read_at) AS first_read_at
+			FROM notifications
+			WHERE source_event_id IS NOT NULL AND source_event_id <> ''
+			GROUP BY tenant_id, user_id, source_event_id
+			HAVING COUNT(*) > 1 AND BOOL_OR(is_read)
+		) d
+		WHERE n.tenant_id = d.tenant_id
+			AND n.user_id = d.user_id
+			AND n.source_event_id = d.source_event_id
+			AND n.is_read = FALSE`).Error; err != nil {
+		return 0, fmt.Errorf("failed to merge read status of duplicates: %w", err)
+	}
+
+	var total int64
+	for {
+		result := r.db.WithContext(ctx).Exec(`
+			DELETE FROM notifications
+			WHERE id IN (
+				SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (
+						PARTITION BY tenant_id, user_id, source_event_id
+						ORDER BY created_at ASC, id ASC
+					) AS rn
+					FROM notifications
+					WHERE source_event_id IS NOT NULL AND source_event_id <> ''
+				) ranked
+				WHERE ranked.rn > 1
+				LIMIT ?
+			)`, batchSize)
+		if result.Error != nil {
+			return total, fmt.Errorf("failed to delete duplicate notifications: %w", result.Error)
+		}
+		total += result.RowsAffected
+		if result.RowsAffected < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// EnsureSourceEventUniqueIndex creates the unique index that makes event processing idempotent
+// Must run after CollapseDuplicates, since existing duplicates would make the index build fail.
+func (r *notificationRepository) EnsureSourceEventUniqueIndex(ctx context.Context) error {
+	// A failed concurrent build leaves an invalid index behind that IF NOT EXISTS would keep
+	var invalid bool
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT EXISTS(
+			SELECT 1 FROM pg_index i
+			JOIN pg_class c ON c.oid = i.indexrelid
+			WHERE c.relname = 'idx_notifications_source_event_unique' AND NOT i.indisvalid
+		)`).Scan(&invalid).Error; err != nil {
+		return fmt.Errorf("failed to check source event unique index: %w", err)
+	}
+	if invalid {
+		if err := r.db.WithContext(ctx).Exec("DROP INDEX CONCURRENTLY IF EXISTS idx_notifications_source_event_unique").Error; err != nil {
+			return fmt.Errorf("failed to drop invalid source event unique index: %w", err)
+		}
+	}
+
+	if err := r.db.WithContext(ctx).Exec(`
+		CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_notifications_source_event_unique
+		ON notifications(tenant_id, user_id, source_event_id)
+		WHERE source_event_id IS NOT NULL AND source_event_id <> ''`).Error; err != nil {
+		return fmt.Errorf("failed to create source event unique index: %w", err)
+	}
+	return nil
+}
+
+// HasSourceEventUniqueIndex reports whether a valid source event unique index exists
+// Once it does, no new duplicates can be written and the backfill can be skipped
+func (r *notificationRepository) HasSourceEventUniqueIndex(ctx context.Context) (bool, error) {
+	var exists bool
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT EXISTS(
+			SELECT 1 FROM pg_index i
+			JOIN pg_class c ON c.oid = i.indexrelid
+			WHERE c.relname = 'idx_notifications_source_event_unique' AND i.indisvalid
+		)`).Scan(&exists).Error; err != nil {
+		return false, fmt.Errorf("failed to check source event unique index: %w", err)
+	}
+	return exists, nil
+}
+
+// exportQuery selects a user's full notification history within a date range
+// Unlike List this covers every channel and archived rows, since exports serve support and DSAR requests
+func (r *notificationRepository) exportQuery(ctx context.Context, tenantID string, userID uuid.UUID, from, to time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("tenant_id = ? AND user_id = ?", tenantID, userID).
+		Where("created_at >= ? AND created_at <= ?", from, to)
+}
+
+// CountForExport returns the number of notifications an export would contain
+func (r *notificationRepository) CountForExport(ctx context.Context, tenantID string, userID uuid.UUID, from, to time.Time) (int64, error) {
+	var count int64
+	if err := r.exportQuery(ctx, tenantID, userID, from, to).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count notifications for export: %w", err)
+	}
+	return count, nil
+}
+
+// StreamForExport passes a user's notifications to fn in batches, oldest first
+// Uses keyset pagination on (created_at, id) so batches stay cheap on long histories
+func (r *notificationRepository) StreamForExport(ctx context.Context, tenantID string, userID uuid.UUID, from, to time.Time, batchSize int, fn func([]models.Notification) error) error {
+	var lastCreatedAt time.Time
+	var lastID uuid.UUID
+
+	for {
+		query := r.exportQuery(ctx, tenantID, userID, from, to)
+		if lastID != uuid.Nil {
+			query = query.Where("(created_at, id) > (?, ?)", lastCreatedAt, lastID)
+		}
+
+		var batch []models.Notification
+		if err := query.Order("created_at ASC, id ASC").Limit(batchSize).Find(&batch).Error; err != nil {
+			return fmt.Errorf("failed to read notifications for export: %w", err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
+
+		last := batch[len(batch)-1]
+		lastCreatedAt, lastID = last.CreatedAt, last.ID
+		if len(batch) < batchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"notification-hub/internal/config"
+	"notification-hub/internal/models"
+	"notification-hub/internal/repository"
+)
+
+const (
+	// exportBatchSize is the number of notifications read per query while exporting
+	exportBatchSize = 500
+	// exportCleanupInterval is how often expired export files are removed
+	exportCleanupInterval = time.Hour
+)
+
+// ErrExportTooLarge is returned when an export exceeds the configured row cap
+var ErrExportTooLarge = errors.New("export exceeds the maximum number of notifications; narrow the date range")
+
+// exportCSVHeader is the column layout of CSV exports
+var exportCSVHeader = []string{
+	"id", "created_at", "channel", "type", "priority", "title", "message",
+	"source_service", "entity_type", "entity_id", "action_url",
+	"is_read", "read_at", "is_archived", "archived_at",
+}
+
+// ExportRequest describes a notification history export
+type ExportRequest struct {
+	TenantID    string
+	UserID      uuid.UUID // Subject of the export
+	RequestedBy uuid.UUID
+	Format      models.ExportFormat
+	From        time.Time
+	To          time.Time
+}
+
+// ExportService produces CSV/JSON exports of a user's notification history
+// Small exports are streamed directly; large ones are generated by background workers
+type ExportService struct {
+	notifRepo  repository.NotificationRepository
+	exportRepo repository.ExportRepository
+	cfg        config.ExportConfig
+	jobs       chan models.NotificationExport
+	stopCh     chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewExportService creates a new export service
+func NewExportService(notifRepo repository.NotificationRepository, exportRepo repository.ExportRepository, cfg config.ExportConfig) *ExportService {
+	return &ExportService{
+		notifRepo:  notifRepo,
+		exportRepo: exportRepo,
+		cfg:        cfg,
+		jobs:       make(chan models.NotificationExport, 100),
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start launches the export workers, resumes unfinished exports and schedules cleanup
+func (s *ExportService) Start(ctx context.Context) {
+	workers := s.cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+
+	// Resume exports interrupted by a restart
+	unfinished, err := s.exportRepo.ListUnfinished(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to resume notification exports: %v", err)
+	}
+	for _, export := range unfinished {
+		s.enqueue(export)
+	}
+	if len(unfinished) > 0 {
+		log.Printf("Resumed %d notification exports", len(unfinished))
+	}
+
+	s.wg.Add(1)
+	go s.cleanupLoop()
+}
+
+// Stop stops the workers and waits for in-flight exports to finish
+func (s *ExportService) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// ShouldRunAsync reports whether an export of the given size must run in the background
+func (s *ExportService) ShouldRunAsync(rowCount int64) bool {
+	return rowCount > int64(s.cfg.SyncMaxRows)
+}
+
+// Count returns the number of notifications an export would contain, enforcing the row cap
+func (s *ExportService) Count(ctx context.Context, req ExportRequest) (int64, error) {
+	count, err := s.notifRepo.CountForExport(ctx, req.TenantID, req.UserID, req.From, req.To)
+	if err != nil {
+		return 0, err
+	}
+	if s.cfg.MaxRows > 0 && count > int64(s.cfg.MaxRows) {
+		return count, ErrExportTooLarge
+	}
+	return count, nil
+}
+
+// Write streams the export to w and returns the number of notifications written
+func (s *ExportService) Write(ctx context.Context, w io.Writer, req ExportRequest) (int64, error) {
+	if req.Format == models.ExportFormatJSON {
+		return s.writeJSON(ctx, w, req)
+	}
+	return s.writeCSV(ctx, w, req)
+}
+
+// Enqueue records an async export job and hands it to the workers
+func (s *ExportService) Enqueue(ctx context.Context, req ExportRequest) (*models.NotificationExport, error) {
+	export := &models.NotificationExport{
+		TenantID:    req.TenantID,
+		UserID:      req.UserID,
+		RequestedBy: req.RequestedBy,
+		Format:      req.Format,
+		FromDate:    req.From,
+		ToDate:      req.To,
+		Status:      models.ExportStatusPending,
+	}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	s.enqueue(*export)
+	return export, nil
+}
+
+// FileName returns the download file name of an export
+func FileName(req ExportRequest) string {
+	return fmt.Sprintf("notifications-%s-%s-%s.%s",
+		req.UserID, req.From.Format("20060102"), req.To.Format("20060102"), req.Format)
+}
+
+// DownloadPath returns the API path serving an export file
+func DownloadPath(id uuid.UUID) string {
+	return fmt.Sprintf("/api/v1/notifications/exports/%s/download", id)
+}
+
+func (s *ExportService) enqueue(export models.NotificationExport) {
+	// Never block the request path on a full queue
+	go func() {
+		select {
+		case s.jobs <- export:
+		case <-s.stopCh:
+		}
+	}()
+}
+
+func (s *ExportService) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case export := <-s.jobs:
+			s.process(export)
+		}
+	}
+}
+
+// process generates an export file and stores it for download
+func (s *ExportService) process(export models.NotificationExport) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	req := ExportRequest{
+		TenantID:    export.TenantID,
+		UserID:      export.UserID,
+		RequestedBy: export.RequestedBy,
+		Format:      export.Format,
+		From:        export.FromDate,
+		To:          export.ToDate,
+	}
+	expiresAt := time.Now().Add(s.cfg.LinkTTL)
+
+	if err := s.exportRepo.MarkProcessing(ctx, export.ID); err != nil {
+		log.Printf("Failed to start notification export %s: %v", export.ID, err)
+		return
+	}
+
+	if _, err := s.Count(ctx, req); err != nil {
+		s.fail(ctx, export.ID, err, expiresAt)
+		return
+	}
+
+	var buf bytes.Buffer
+	rows, err := s.Write(ctx, &buf, req)
+	if err != nil {
+		s.fail(ctx, export.ID, err, expiresAt)
+		return
+	}
+
+	if err := s.exportRepo.MarkCompleted(ctx, export.ID, FileName(req), buf.Bytes(), rows, expiresAt); err != nil {
+		log.Printf("Failed to store notification export %s: %v", export.ID, err)
+		return
+	}
+	log.Printf("Notification export %s completed (%d notifications, %d bytes)", export.ID, rows, buf.Len())
+
+	// Let the requester know the download is ready
+	ready := &models.Notification{
+		TenantID:      export.TenantID,
+		UserID:        export.RequestedBy,
+		Channel:       "in_app",
+		Type:          "notification.export.ready",
+		Title:         "Your notification export is ready",
+		Message:       fmt.Sprintf("%d notifications exported as %s. The download link expires %s.", rows, export.Format, expiresAt.Format(time.RFC1123)),
+		ActionURL:     DownloadPath(export.ID),
+		SourceService: "notification-hub",
+		EntityType:    "notification_export",
+		EntityID:      &export.ID,
+		ExpiresAt:     &expiresAt,
+	}
+	if err := s.notifRepo.Create(ctx, ready); err != nil {
+		log.Printf("Failed to notify requester of export %s: %v", export.ID, err)
+	}
+}
+
+func (s *ExportService) fail(ctx context.Context, id uuid.UUID, cause error, expiresAt time.Time) {
+	log.Printf("Notification export %s failed: %v", id, cause)
+	if err := s.exportRepo.MarkFailed(ctx, id, cause.Error(), expiresAt); err != nil {
+		log.Printf("Failed to record export failure %s: %v", id, err)
+	}
+}
+
+func (s *ExportService) cleanupLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(exportCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			deleted, err := s.exportRepo.DeleteExpired(context.Background())
+			if err != nil {
+				log.Printf("Failed to clean up expired notification exports: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Removed %d expired notification exports", deleted)
+			}
+		}
+	}
+}
+
+func (s *ExportService) writeCSV(ctx context.Context, w io.Writer, req ExportRequest) (int64, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	err := s.notifRepo.StreamForExport(ctx, req.TenantID, req.UserID, req.From, req.To, exportBatchSize, func(batch []models.Notification) error {
+		for _, n := range batch {
+			entityID := ""
+			if n.EntityID != nil {
+				entityID = n.EntityID.String()
+			}
+			if err := cw.Write([]string{
+				n.ID.String(),
+				n.CreatedAt.UTC().Format(time.RFC3339),
+				n.Channel,
+				n.Type,
+				string(n.Priority),
+				n.Title,
+				n.Message,
+				n.SourceService,
+				n.EntityType,
+				entityID,
+				n.ActionURL,
+				strconv.FormatBool(n.IsRead),
+				formatExportTime(n.ReadAt),
+				strconv.FormatBool(n.IsArchived),
+				formatExportTime(n.ArchivedAt),
+			}); err != nil {
+				return err
+			}
+			rows++
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return rows, err
+	}
+
+	cw.Flush()
+	return rows, cw.Error()
+}
+
+func (s *ExportService) writeJSON(ctx context.Context, w io.Writer, req ExportRequest) (int64, error) {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	err := s.notifRepo.StreamForExport(ctx, req.TenantID, req.UserID, req.From, req.To, exportBatchSize, func(batch []models.Notification) error {
+		for _, n := range batch {
+			data, err := json.Marshal(n)
+			if err != nil {
+				return err
+			}
+			if rows > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+			rows++
+		}
+		return nil
+	})
+	if err != nil {
+		return rows, err
+	}
+
+	_, err = io.WriteString(w, "]")
+	return rows, err
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
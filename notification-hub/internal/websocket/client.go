@@ -3,6 +3,7 @@ package websocket
 import (
 	"encoding/json"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,22 +21,59 @@ type Client struct {
 	send     chan []byte
 	config   *config.WebSocketConfig
 
+	// ProtocolVersion is the protocol version negotiated on connect
+	ProtocolVersion int
+
+	// closeMessage is the close frame sent once send is closed; set before closing send
+	closeMessage []byte
+
+	// lastActivity is the unix-nano time of the last client message or notification delivery
+	lastActivity atomic.Int64
+
 	// Handler for incoming messages
 	OnMarkRead    func(notificationIDs []string)
 	OnMarkAllRead func()
+
+	// OnClose is called once the connection has been torn down
+	OnClose func()
+}
+
+// NewClient creates a new WebSocket client speaking the given protocol version
+func NewClient(hub *Hub, conn *websocket.Conn, tenantID string, userID uuid.UUID, cfg *config.WebSocketConfig, protocolVersion int) *Client {
+	client := &Client{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		UserID:          userID,
+		Hub:             hub,
+		Conn:            conn,
+		send:            make(chan []byte, 256),
+		config:          cfg,
+		ProtocolVersion: protocolVersion,
+	}
+	client.touch()
+	return client
+}
+
+// touch records activity on the connection so it is not reaped as idle
+func (c *Client) touch() {
+	c.lastActivity.Store(time.Now().UnixNano())
+}
+
+// IdleSince returns the time of the last client message or notification delivery
+func (c *Client) IdleSince() time.Time {
+	return time.Unix(0, c.lastActivity.Load())
 }
 
-// NewClient creates a new WebSocket client
-func NewClient(hub *Hub, conn *websocket.Conn, tenantID string, userID uuid.UUID, cfg *config.WebSocketConfig) *Client {
-	return &Client{
-		ID:       uuid.New().String(),
-		TenantID: tenantID,
-		UserID:   userID,
-		Hub:      hub,
-		Conn:     conn,
-		send:     make(chan []byte, 256),
-		config:   cfg,
+// closeFrame returns the close frame payload for code and reason. Version 1 clients get the
+// frames they always got: legacyCode, or an empty payload when legacyCode is 0.
+func (c *Client) closeFrame(code int, reason string, legacyCode int) []byte {
+	if c.ProtocolVersion < ProtocolVersionCurrent {
+		if legacyCode == 0 {
+			return []byte{}
+		}
+		code = legacyCode
 	}
+	return websocket.FormatCloseMessage(code, reason)
 }
 
 // SendMessage sends a message to the client
@@ -59,6 +97,9 @@ func (c *Client) ReadPump() {
 	defer func() {
 		c.Hub.Unregister(c)
 		c.Conn.Close()
+		if c.OnClose != nil {
+			c.OnClose()
+		}
 	}()
 
 	c.Conn.SetReadLimit(c.config.MaxMessageSize)
@@ -77,6 +118,8 @@ func (c *Client) ReadPump() {
 			break
 		}
 
+		// Application-level heartbeats keep the connection alive like pongs do
+		c.Conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
 		c.handleMessage(message)
 	}
 }
@@ -95,7 +138,11 @@ func (c *Client) WritePump() {
 			c.Conn.SetWriteDeadline(time.Now().Add(c.config.WriteWait))
 			if !ok {
 				// Hub closed the channel
-				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+				closeMessage := c.closeMessage
+				if closeMessage == nil {
+					closeMessage = []byte{}
+				}
+				c.Conn.WriteMessage(websocket.CloseMessage, closeMessage)
 				return
 			}
 
@@ -138,6 +185,11 @@ func (c *Client) handleMessage(message []byte) {
 		return
 	}
 
+	// Keepalive pings do not count as activity
+	if msg.Type != "ping" {
+		c.touch()
+	}
+
 	switch msg.Type {
 	case "ping":
 		c.SendMessage(&OutgoingMessage{
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"notification-hub/internal/metrics"
 	"notification-hub/internal/models"
 )
 
@@ -21,6 +23,7 @@ const (
 	MessageTypePong               MessageType = "pong"
 	MessageTypeError              MessageType = "error"
 	MessageTypeConnected          MessageType = "connected"
+	MessageTypeHandshake          MessageType = "handshake"
 )
 
 // OutgoingMessage represents a message sent to clients
@@ -132,7 +135,14 @@ func (h *Hub) registerClient(client *Client) {
 	h.clients[tenantID][userID][clientID] = client
 	log.Printf("Client registered: tenant=%s, user=%s, client=%s", tenantID, userID, clientID)
 
-	// Send connected message
+	// Version 2 clients get the handshake, older ones the original connected message
+	if client.ProtocolVersion >= ProtocolVersionCurrent {
+		client.SendMessage(&OutgoingMessage{
+			Type: MessageTypeHandshake,
+			Data: newHandshakeData(clientID, client.ProtocolVersion, client.config),
+		})
+		return
+	}
 	client.SendMessage(&OutgoingMessage{
 		Type: MessageTypeConnected,
 		Data: ConnectedData{
@@ -174,6 +184,7 @@ func (h *Hub) closeAllClients() {
 	for _, users := range h.clients {
 		for _, clients := range users {
 			for _, client := range clients {
+				client.closeMessage = client.closeFrame(CloseServerShutdown, "server shutting down", 0)
 				close(client.send)
 			}
 		}
@@ -194,6 +205,7 @@ func (h *Hub) BroadcastToUser(tenantID string, userID uuid.UUID, notification *m
 		}
 
 		for _, client := range h.clients[tenantID][userIDStr] {
+			client.touch()
 			client.SendMessage(message)
 		}
 	}
@@ -299,3 +311,31 @@ func (h *Hub) GetConnectedUserIDs(tenantID string) []uuid.UUID {
 	}
 	return userIDs
 }
+
+// ReapIdle closes connections that have had no client messages or notification deliveries
+// within timeout and returns how many were closed. The read pump unregisters each one.
+func (h *Hub) ReapIdle(timeout time.Duration) int {
+	cutoff := time.Now().Add(-timeout)
+
+	h.mu.RLock()
+	var idle []*Client
+	for _, users := range h.clients {
+		for _, clients := range users {
+			for _, client := range clients {
+				if client.IdleSince().Before(cutoff) {
+					idle = append(idle, client)
+				}
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range idle {
+		closeMsg := client.closeFrame(CloseIdleTimeout, "idle timeout", websocket.CloseNormalClosure)
+		client.Conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+		client.Conn.Close()
+		log.Printf("Idle client closed: tenant=%s, user=%s, client=%s", client.TenantID, client.UserID, client.ID)
+	}
+	metrics.RecordConnectionsReaped(metrics.TransportWebSocket, metrics.ConnectionReasonIdle, len(idle))
+	return len(idle)
+}
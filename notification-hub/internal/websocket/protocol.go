@@ -0,0 +1,115 @@
+package websocket
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"notification-hub/internal/config"
+)
+
+// Protocol versions. Version 1 is the original protocol: a "connected" message on connect and
+// plain close frames. Version 2 opens with a "handshake" message carrying heartbeat and reconnect
+// hints and closes with the codes below. Clients that do not ask for a version get version 1.
+const (
+	ProtocolVersionLegacy  = 1
+	ProtocolVersionCurrent = 2
+)
+
+// SubprotocolPrefix names protocol versions as WebSocket subprotocols, e.g. "notification-hub.v2"
+const SubprotocolPrefix = "notification-hub.v"
+
+// Close codes sent to clients on protocol version 2 and later. Codes in the 4000-4999 range
+// are application defined; clients should follow the reconnect advice of each code.
+const (
+	// CloseServerShutdown: the server is restarting; reconnect with backoff
+	CloseServerShutdown = 1001
+	// CloseUnsupportedProtocol: the requested protocol version is not supported; do not retry it
+	CloseUnsupportedProtocol = 4000
+	// CloseIdleTimeout: no activity for too long; reconnect on the next user activity
+	CloseIdleTimeout = 4001
+)
+
+// HandshakeData is the first message sent on protocol version 2 and later
+type HandshakeData struct {
+	ClientID          string          `json:"client_id"`
+	ProtocolVersion   int             `json:"protocol_version"`
+	SupportedVersions []int           `json:"supported_versions"`
+	Heartbeat         HeartbeatPolicy `json:"heartbeat"`
+	Reconnect         ReconnectPolicy `json:"reconnect"`
+	CloseCodes        map[string]int  `json:"close_codes"`
+}
+
+// HeartbeatPolicy tells clients how often to send {"type":"ping"} and when the server gives up
+// on a silent connection
+type HeartbeatPolicy struct {
+	IntervalMs int64 `json:"interval_ms"`
+	TimeoutMs  int64 `json:"timeout_ms"`
+}
+
+// ReconnectPolicy is the exponential backoff clients should use between reconnect attempts
+type ReconnectPolicy struct {
+	InitialDelayMs int64   `json:"initial_delay_ms"`
+	MaxDelayMs     int64   `json:"max_delay_ms"`
+	Multiplier     float64 `json:"multiplier"`
+	Jitter         float64 `json:"jitter"` // Fraction of the delay to add at random
+}
+
+// SupportedProtocolVersions lists the protocol versions the server speaks, newest first
+func SupportedProtocolVersions() []int {
+	versions := make([]int, 0, ProtocolVersionCurrent-ProtocolVersionLegacy+1)
+	for v := ProtocolVersionCurrent; v >= ProtocolVersionLegacy; v-- {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// Subprotocols returns the WebSocket subprotocols of the supported versions, preferred first
+func Subprotocols() []string {
+	versions := SupportedProtocolVersions()
+	subprotocols := make([]string, 0, len(versions))
+	for _, v := range versions {
+		subprotocols = append(subprotocols, SubprotocolPrefix+strconv.Itoa(v))
+	}
+	return subprotocols
+}
+
+// NegotiateProtocolVersion picks the protocol version of a connection from the negotiated
+// subprotocol, or else the protocol_version query parameter. Neither means version 1.
+func NegotiateProtocolVersion(subprotocol, requested string) (int, error) {
+	if subprotocol != "" {
+		requested = strings.TrimPrefix(subprotocol, SubprotocolPrefix)
+	}
+	if requested == "" {
+		return ProtocolVersionLegacy, nil
+	}
+	version, err := strconv.Atoi(requested)
+	if err != nil || version < ProtocolVersionLegacy || version > ProtocolVersionCurrent {
+		return 0, fmt.Errorf("unsupported protocol version %q, supported %d-%d", requested, ProtocolVersionLegacy, ProtocolVersionCurrent)
+	}
+	return version, nil
+}
+
+// newHandshakeData builds the handshake for a client from the WebSocket configuration
+func newHandshakeData(clientID string, version int, cfg *config.WebSocketConfig) HandshakeData {
+	return HandshakeData{
+		ClientID:          clientID,
+		ProtocolVersion:   version,
+		SupportedVersions: SupportedProtocolVersions(),
+		Heartbeat: HeartbeatPolicy{
+			IntervalMs: cfg.PingInterval.Milliseconds(),
+			TimeoutMs:  cfg.PongWait.Milliseconds(),
+		},
+		Reconnect: ReconnectPolicy{
+			InitialDelayMs: cfg.ReconnectInitialDelay.Milliseconds(),
+			MaxDelayMs:     cfg.ReconnectMaxDelay.Milliseconds(),
+			Multiplier:     cfg.ReconnectMultiplier,
+			Jitter:         cfg.ReconnectJitter,
+		},
+		CloseCodes: map[string]int{
+			"server_shutdown":      CloseServerShutdown,
+			"unsupported_protocol": CloseUnsupportedProtocol,
+			"idle_timeout":         CloseIdleTimeout,
+		},
+	}
+}
@@ -0,0 +1,29 @@
+-- Notification Hub Database Schema
+-- Migration: 002_create_notification_exports
+
+-- Async exports of a user's notification history (support investigations / DSAR requests)
+CREATE TABLE IF NOT EXISTS notification_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL,
+    requested_by UUID NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    from_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    to_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    row_count BIGINT DEFAULT 0,
+    file_name VARCHAR(255),
+    file_size BIGINT DEFAULT 0,
+    content BYTEA,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_exports_tenant_user
+    ON notification_exports(tenant_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_notification_exports_status
+    ON notification_exports(status);
+CREATE INDEX IF NOT EXISTS idx_notification_exports_expires_at
+    ON notification_exports(expires_at) WHERE expires_at IS NOT NULL;
@@ -0,0 +1,37 @@
+-- Notification Hub Database Schema
+-- Migration: 003_dedupe_source_events
+-- The service runs the same backfill on startup until the index exists; this file is for manual runs.
+
+-- Carry read status over to the surviving row of each duplicate group
+UPDATE notifications n
+SET is_read = TRUE, read_at = d.first_read_at
+FROM (
+    SELECT tenant_id, user_id, source_event_id, MIN(read_at) AS first_read_at
+    FROM notifications
+    WHERE source_event_id IS NOT NULL AND source_event_id <> ''
+    GROUP BY tenant_id, user_id, source_event_id
+    HAVING COUNT(*) > 1 AND BOOL_OR(is_read)
+) d
+WHERE n.tenant_id = d.tenant_id
+    AND n.user_id = d.user_id
+    AND n.source_event_id = d.source_event_id
+    AND n.is_read = FALSE;
+
+-- Collapse duplicates created by NATS redelivery, keeping the earliest row
+DELETE FROM notifications
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY tenant_id, user_id, source_event_id
+            ORDER BY created_at ASC, id ASC
+        ) AS rn
+        FROM notifications
+        WHERE source_event_id IS NOT NULL AND source_event_id <> ''
+    ) ranked
+    WHERE ranked.rn > 1
+);
+
+-- One notification per user per source event
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_source_event_unique
+    ON notifications(tenant_id, user_id, source_event_id)
+    WHERE source_event_id IS NOT NULL AND source_event_id <> '';
@@ -48,6 +48,17 @@ func main() {
 
 	// Initialize providers
 	emailProvider := initEmailProvider(cfg)
+
+	// Probe email providers so unhealthy ones drop out of the failover chain
+	healthMonitor := services.NewProviderHealthMonitor(&services.HealthMonitorConfig{
+		Interval:         cfg.Email.HealthCheckInterval,
+		FailureThreshold: cfg.Email.HealthCheckFailureThreshold,
+	})
+	if failover, ok := emailProvider.(*services.FailoverEmailProvider); ok {
+		failover.SetHealthMonitor(healthMonitor)
+	}
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	healthMonitor.Start(monitorCtx)
 	smsProvider := initSMSProvider(cfg)
 	pushProvider := initPushProvider(cfg)
 
@@ -107,8 +118,49 @@ func main() {
 		log.Println("Warning: Twilio Verify not configured - OTP features disabled")
 	}
 
+	// Monitor bounce/complaint rates per sending domain and warm up new tenant domains
+	var reputationMonitor *services.ReputationMonitor
+	if cfg.Email.ReputationEnabled {
+		reputationMonitor = services.NewReputationMonitor(repository.NewSenderReputationRepository(db), &services.ReputationConfig{
+			EvaluationInterval:    cfg.Email.ReputationInterval,
+			WindowDays:            cfg.Email.ReputationWindowDays,
+			MinSampleSize:         int64(cfg.Email.ReputationMinSample),
+			BounceRateDegraded:    cfg.Email.BounceRateDegraded,
+			BounceRatePoor:        cfg.Email.BounceRatePoor,
+			ComplaintRateDegraded: cfg.Email.ComplaintRateDegraded,
+			ComplaintRatePoor:     cfg.Email.ComplaintRatePoor,
+			ThrottleFactor:        cfg.Email.ReputationThrottleFactor,
+			PlatformDomain:        platformSendingDomain(cfg),
+		})
+		reputationMonitor.Start(monitorCtx)
+	}
+
+	// Track provider cost per message; monthly usage is published to billing via NATS
+	rateCard, err := services.ParseRateCard(cfg.Usage.RateCard)
+	if err != nil {
+		log.Fatalf("Failed to load notification rate card: %v", err)
+	}
+	costTracker := services.NewCostTracker(repository.NewUsageRepository(db), &services.CostTrackerConfig{
+		RateCard:            rateCard,
+		Currency:            cfg.Usage.Currency,
+		SMSIncludedSegments: int64(cfg.Usage.SMSIncludedSegments),
+		SMSProvider:         primarySMSProviderName(smsProvider),
+		ReportInterval:      cfg.Usage.ReportInterval,
+	})
+
+	// Country-specific SMS sender IDs and the SMS segment limit
+	smsSenderIDs, err := services.ParseSMSSenderIDs(cfg.SMS.SenderIDs)
+	if err != nil {
+		log.Fatalf("Failed to load SMS sender IDs: %v", err)
+	}
+	smsPolicy := &services.SMSPolicy{
+		SenderIDs:   smsSenderIDs,
+		MaxSegments: cfg.SMS.MaxSegments,
+	}
+
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(db)
+	providerHandler := handlers.NewProviderHandler(emailProvider, smsProvider, healthMonitor)
 	notifHandler := handlers.NewNotificationHandler(
 		notifRepo,
 		templateRepo,
@@ -121,7 +173,58 @@ func main() {
 	if emailRateLimiter != nil {
 		notifHandler.SetRateLimiter(emailRateLimiter)
 	}
+	notifHandler.SetCostTracker(costTracker)
+	notifHandler.SetSMSPolicy(smsPolicy)
+
+	// Marketing sends deferred while Mautic was down go out once it recovers
+	if failover, ok := emailProvider.(*services.FailoverEmailProvider); ok && failover.MarketingProviderName() != "" {
+		replayDeferred := func() {
+			if sent, err := notifHandler.ReplayDeferred(monitorCtx); err != nil {
+				log.Printf("Warning: Failed to replay deferred notifications: %v", err)
+			} else if sent > 0 {
+				log.Printf("✓ Replayed %d notification(s) deferred while %s was down", sent, failover.MarketingProviderName())
+			}
+		}
+		healthMonitor.OnRecovery(func(name string) {
+			if name == failover.MarketingProviderName() {
+				go replayDeferred()
+			}
+		})
+		// Sends deferred before a restart
+		if failover.MarketingAvailable() {
+			go replayDeferred()
+		}
+	}
+	usageHandler := handlers.NewUsageHandler(costTracker)
+	var sendingDomainHandler *handlers.SendingDomainHandler
+	if reputationMonitor != nil {
+		notifHandler.SetReputationMonitor(reputationMonitor)
+		sendingDomainHandler = handlers.NewSendingDomainHandler(reputationMonitor, notifRepo)
+	}
+	// Inbound email: replies to sent emails are routed back through reply+<token> addresses
+	var replyRouter *services.ReplyRouter
+	var inboundEmailService *services.InboundEmailService
+	var inboundEmailHandler *handlers.InboundEmailHandler
+	if cfg.Email.InboundDomain != "" && cfg.Email.InboundWebhookSecret != "" {
+		signingKey := cfg.Email.ReplySigningKey
+		if signingKey == "" {
+			signingKey = cfg.Email.InboundWebhookSecret
+		}
+		replyRouter = services.NewReplyRouter(cfg.Email.InboundDomain, signingKey)
+		notifHandler.SetReplyRouter(replyRouter)
+		inboundEmailService = services.NewInboundEmailService(
+			repository.NewInboundEmailRepository(db),
+			notifRepo,
+			emailProvider,
+			services.NewTenantClient(),
+			replyRouter,
+			cfg.App.SupportEmail,
+		)
+		inboundEmailHandler = handlers.NewInboundEmailHandler(inboundEmailService, cfg.Email.InboundWebhookSecret)
+		log.Printf("Inbound email reply routing enabled for %s", cfg.Email.InboundDomain)
+	}
 	templateHandler := handlers.NewTemplateHandler(templateRepo)
+	templateHandler.SetSMSEstimates(costTracker, smsPolicy)
 	prefHandler := handlers.NewPreferenceHandler(prefRepo)
 	var verifyHandler *handlers.VerifyHandler
 	if verifyService != nil {
@@ -146,13 +249,70 @@ func main() {
 			cfg.App.AdminEmail,
 			cfg.App.SupportEmail,
 		)
+		natsSubscriber.SetCostTracker(costTracker)
+		natsSubscriber.SetReplyRouter(replyRouter)
+		natsSubscriber.SetTenantPurger(repository.NewTenantDataRepository(db).Purge)
 		if err := natsSubscriber.Start(context.Background()); err != nil {
 			log.Printf("Warning: Failed to start NATS subscriber: %v", err)
 		}
+
+		if err := natsClient.EnsureStream("USAGE_EVENTS", "usage.>", "Usage events for billing"); err != nil {
+			log.Printf("Warning: Failed to ensure usage stream: %v - monthly usage events disabled", err)
+		} else {
+			costTracker.SetPublisher(natsClient)
+		}
+
+		if sendingDomainHandler != nil {
+			if err := natsClient.EnsureStream("NOTIFICATION_EVENTS", "notification.>", "Notification engagement events"); err != nil {
+				log.Printf("Warning: Failed to ensure notification stream: %v - email read sync disabled", err)
+			} else {
+				sendingDomainHandler.SetPublisher(natsClient)
+			}
+		}
+
+		if inboundEmailService != nil {
+			if err := natsClient.EnsureStream("SUPPORT_EVENTS", "support.>", "Support events from customer replies"); err != nil {
+				log.Printf("Warning: Failed to ensure support stream: %v - support.email.received events disabled", err)
+			} else {
+				inboundEmailService.SetPublisher(natsClient)
+			}
+		}
+	}
+
+	costTracker.Start(monitorCtx)
+
+	// Marketing campaigns: throttled bulk sends to a Mautic segment or uploaded list
+	campaignService := services.NewCampaignService(
+		repository.NewCampaignRepository(db),
+		templateRepo,
+		notifRepo,
+		prefRepo,
+		notifHandler.Deliver,
+		&services.CampaignConfig{
+			DispatchInterval:         cfg.Campaign.DispatchInterval,
+			DefaultThrottlePerMinute: cfg.Campaign.DefaultThrottlePerMinute,
+			MaxThrottlePerMinute:     cfg.Campaign.MaxThrottlePerMinute,
+			SendConcurrency:          cfg.Campaign.SendConcurrency,
+		},
+	)
+	if cfg.Email.MauticURL != "" {
+		campaignService.SetSegmentSource(services.NewMauticProvider(&services.ProviderConfig{
+			MauticURL:      cfg.Email.MauticURL,
+			MauticUsername: cfg.Email.MauticUsername,
+			MauticPassword: cfg.Email.MauticPassword,
+		}))
+	}
+	if reputationMonitor != nil {
+		campaignService.SetReputationMonitor(reputationMonitor)
 	}
+	if failover, ok := emailProvider.(*services.FailoverEmailProvider); ok {
+		campaignService.SetAvailability(failover.MarketingAvailable)
+	}
+	campaignService.Start(monitorCtx)
+	campaignHandler := handlers.NewCampaignHandler(campaignService)
 
 	// Setup router
-	router := setupRouter(cfg, healthHandler, notifHandler, templateHandler, prefHandler, verifyHandler)
+	router := setupRouter(cfg, healthHandler, providerHandler, notifHandler, templateHandler, prefHandler, verifyHandler, sendingDomainHandler, usageHandler, campaignHandler, inboundEmailHandler)
 
 	// Start server with graceful shutdown
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
@@ -175,6 +335,15 @@ func main() {
 	<-quit
 	log.Println("Shutting down Notification Service...")
 
+	// Stop provider health probes
+	healthMonitor.Stop()
+	if reputationMonitor != nil {
+		reputationMonitor.Stop()
+	}
+	costTracker.Stop()
+	campaignService.Stop()
+	stopMonitor()
+
 	// Stop NATS subscriber
 	if natsSubscriber != nil {
 		natsSubscriber.Stop()
@@ -235,6 +404,13 @@ func migrateDatabase(db *gorm.DB) error {
 		&models.NotificationPreference{},
 		&models.NotificationLog{},
 		&models.NotificationBatch{},
+		&models.SendingDomain{},
+		&models.SendingDomainDailyStats{},
+		&models.NotificationCost{},
+		&models.UsageReport{},
+		&models.Campaign{},
+		&models.CampaignRecipient{},
+		&models.InboundEmail{},
 	}
 
 	for _, model := range modelsToMigrate {
@@ -417,6 +593,22 @@ func initSMSProvider(cfg *config.Config) services.Provider {
 	return failover
 }
 
+// primarySMSProviderName returns the name of the SMS provider tried first, which SMS
+// cost estimates are priced at
+func primarySMSProviderName(smsProvider services.Provider) string {
+	switch p := smsProvider.(type) {
+	case nil:
+		return ""
+	case *services.FailoverSMSProvider:
+		if primary := p.GetPrimaryProvider(); primary != nil {
+			return primary.GetName()
+		}
+		return ""
+	default:
+		return p.GetName()
+	}
+}
+
 // initPushProvider initializes the push notification provider
 func initPushProvider(cfg *config.Config) services.Provider {
 	if cfg.Push.FCMCredentials != "" {
@@ -435,14 +627,29 @@ func initPushProvider(cfg *config.Config) services.Provider {
 	return nil
 }
 
+// platformSendingDomain returns the domain of the platform's default From address
+func platformSendingDomain(cfg *config.Config) string {
+	for _, from := range []string{cfg.Email.SESFrom, cfg.Email.PostalFrom, cfg.Email.SendGridFrom, cfg.Email.SMTPFrom} {
+		if from != "" {
+			return services.DomainOf(from)
+		}
+	}
+	return ""
+}
+
 // setupRouter configures the Gin router with middleware and routes
 func setupRouter(
 	cfg *config.Config,
 	healthHandler *handlers.HealthHandler,
+	providerHandler *handlers.ProviderHandler,
 	notifHandler *handlers.NotificationHandler,
 	templateHandler *handlers.TemplateHandler,
 	prefHandler *handlers.PreferenceHandler,
 	verifyHandler *handlers.VerifyHandler,
+	sendingDomainHandler *handlers.SendingDomainHandler,
+	usageHandler *handlers.UsageHandler,
+	campaignHandler *handlers.CampaignHandler,
+	inboundEmailHandler *handlers.InboundEmailHandler,
 ) *gin.Engine {
 	// Set Gin mode
 	if cfg.App.Environment == "production" {
@@ -463,6 +670,15 @@ func setupRouter(
 	router.GET("/livez", healthHandler.Livez)
 	router.GET("/readyz", healthHandler.Readyz)
 
+	// Internal provider status (cluster-internal, not exposed via the API gateway)
+	router.GET("/internal/providers", providerHandler.List)
+
+	// Internal deliverability: per-domain reputation overview and bounce/complaint ingestion
+	if sendingDomainHandler != nil {
+		router.GET("/internal/deliverability", sendingDomainHandler.Overview)
+		router.POST("/internal/deliverability/events", sendingDomainHandler.IngestEvents)
+	}
+
 	// API routes
 	api := router.Group("/api/v1")
 
@@ -517,6 +733,37 @@ func setupRouter(
 			preferences.POST("/:userId/push-token", prefHandler.RegisterPushToken)
 		}
 
+		// Usage and provider costs
+		api.GET("/usage/costs", usageHandler.GetCosts)
+
+		// Marketing campaigns
+		campaigns := api.Group("/campaigns")
+		{
+			campaigns.POST("", campaignHandler.Create)
+			campaigns.GET("", campaignHandler.List)
+			campaigns.GET("/:id", campaignHandler.Get)
+			campaigns.PUT("/:id", campaignHandler.Update)
+			campaigns.DELETE("/:id", campaignHandler.Delete)
+			campaigns.POST("/:id/recipients", campaignHandler.AddRecipients)
+			campaigns.GET("/:id/recipients", campaignHandler.ListRecipients)
+			campaigns.POST("/:id/schedule", campaignHandler.Schedule)
+			campaigns.POST("/:id/pause", campaignHandler.Pause)
+			campaigns.POST("/:id/resume", campaignHandler.Resume)
+			campaigns.POST("/:id/cancel", campaignHandler.Cancel)
+			campaigns.GET("/:id/stats", campaignHandler.Stats)
+		}
+
+		// Tenant sending domains (warm-up and reputation)
+		if sendingDomainHandler != nil {
+			sendingDomains := api.Group("/sending-domains")
+			{
+				sendingDomains.POST("", sendingDomainHandler.Register)
+				sendingDomains.GET("", sendingDomainHandler.List)
+				sendingDomains.GET("/:id", sendingDomainHandler.Get)
+				sendingDomains.DELETE("/:id", sendingDomainHandler.Delete)
+			}
+		}
+
 		// OTP/Verification endpoints (only if Twilio Verify is configured)
 		if verifyHandler != nil {
 			verify := api.Group("/verify")
@@ -535,6 +782,12 @@ func setupRouter(
 	{
 		webhooks.POST("/sendgrid", handleSendGridWebhook)
 		webhooks.POST("/twilio", handleTwilioWebhook)
+
+		// Inbound email: replies to sent emails (shared-secret token)
+		if inboundEmailHandler != nil {
+			webhooks.POST("/inbound/postal", inboundEmailHandler.Postal)
+			webhooks.POST("/inbound/ses", inboundEmailHandler.SES)
+		}
 	}
 
 	return router
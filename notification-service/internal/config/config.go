@@ -21,6 +21,32 @@ type Config struct {
 	Push           PushConfig
 	Verify         VerifyConfig
 	EmailRateLimit EmailRateLimitConfig
+	Usage          UsageConfig
+	Campaign       CampaignConfig
+}
+
+// UsageConfig holds provider cost tracking and usage reporting settings
+type UsageConfig struct {
+	// RateCard is a JSON object of provider -> cost per unit (SMS segment or message),
+	// merged over the built-in defaults, e.g. {"twilio":0.0083,"ses":0.0001}
+	RateCard string
+	Currency string
+	// SMSIncludedSegments is the SMS segments per tenant per month included before overage
+	SMSIncludedSegments int
+	// ReportInterval is how often unreported monthly usage is published
+	ReportInterval time.Duration
+}
+
+// CampaignConfig holds marketing campaign dispatch settings
+type CampaignConfig struct {
+	// DispatchInterval is how often each sending campaign is given a batch
+	DispatchInterval time.Duration
+	// DefaultThrottlePerMinute applies to campaigns created without a throttle rate
+	DefaultThrottlePerMinute int
+	// MaxThrottlePerMinute caps the throttle rate a merchant may choose
+	MaxThrottlePerMinute int
+	// SendConcurrency is the number of campaign sends in flight per batch
+	SendConcurrency int
 }
 
 // RedisConfig holds Redis settings for rate limiting
@@ -125,6 +151,27 @@ type EmailConfig struct {
 	MauticFrom     string
 	MauticFromName string
 
+	// Provider health probes (providers failing probes are removed from the chain)
+	HealthCheckInterval         time.Duration
+	HealthCheckFailureThreshold int
+
+	// Sender reputation: bounce/complaint monitoring per sending domain and warm-up of new domains
+	ReputationEnabled        bool
+	ReputationInterval       time.Duration
+	ReputationWindowDays     int
+	ReputationMinSample      int
+	BounceRateDegraded       float64
+	BounceRatePoor           float64
+	ComplaintRateDegraded    float64
+	ComplaintRatePoor        float64
+	ReputationThrottleFactor float64
+
+	// Inbound email: replies to sent emails are routed to reply+<token>@InboundDomain and
+	// delivered by the Postal/SES inbound webhooks, which must present InboundWebhookSecret
+	InboundDomain        string
+	InboundWebhookSecret string
+	ReplySigningKey      string // Signs reply-to addresses; defaults to InboundWebhookSecret
+
 	// Provider priority: SES > Postal > SendGrid
 	// EnableFailover enables automatic failover to next provider
 	EnableFailover bool
@@ -142,6 +189,12 @@ type SMSConfig struct {
 
 	// Enable failover from SNS to Twilio
 	EnableFailover bool
+
+	// SenderIDs is a JSON object of country calling code -> sender ID or number,
+	// e.g. {"+44":"Tesseract","+91":"TSRHUB"}; other countries use SNSFrom/TwilioFrom
+	SenderIDs string
+	// MaxSegments is the longest SMS allowed unless a template sets its own limit
+	MaxSegments int
 }
 
 // PushConfig holds push notification settings
@@ -237,6 +290,23 @@ func Load() (*Config, error) {
 			MauticPassword: getEnv("MAUTIC_PASSWORD", ""),
 			MauticFrom:     getEnv("MAUTIC_FROM", ""),
 			MauticFromName: getEnv("MAUTIC_FROM_NAME", "Tesseract Hub"),
+			// Provider health probes
+			HealthCheckInterval:         time.Duration(getEnvInt("EMAIL_HEALTH_CHECK_INTERVAL_SECONDS", 30)) * time.Second,
+			HealthCheckFailureThreshold: getEnvInt("EMAIL_HEALTH_CHECK_FAILURE_THRESHOLD", 2),
+			// Sender reputation
+			ReputationEnabled:        getEnvBool("EMAIL_REPUTATION_ENABLED", true),
+			ReputationInterval:       time.Duration(getEnvInt("EMAIL_REPUTATION_INTERVAL_SECONDS", 300)) * time.Second,
+			ReputationWindowDays:     getEnvInt("EMAIL_REPUTATION_WINDOW_DAYS", 7),
+			ReputationMinSample:      getEnvInt("EMAIL_REPUTATION_MIN_SAMPLE", 200),
+			BounceRateDegraded:       getEnvFloat("EMAIL_BOUNCE_RATE_DEGRADED", 0.05),
+			BounceRatePoor:           getEnvFloat("EMAIL_BOUNCE_RATE_POOR", 0.10),
+			ComplaintRateDegraded:    getEnvFloat("EMAIL_COMPLAINT_RATE_DEGRADED", 0.001),
+			ComplaintRatePoor:        getEnvFloat("EMAIL_COMPLAINT_RATE_POOR", 0.003),
+			ReputationThrottleFactor: getEnvFloat("EMAIL_REPUTATION_THROTTLE_FACTOR", 0.5),
+			// Inbound email (reply routing)
+			InboundDomain:        getEnv("INBOUND_EMAIL_DOMAIN", ""),
+			InboundWebhookSecret: secrets.GetSecretOrEnv("INBOUND_EMAIL_WEBHOOK_SECRET_NAME", "INBOUND_EMAIL_WEBHOOK_SECRET", ""),
+			ReplySigningKey:      secrets.GetSecretOrEnv("REPLY_SIGNING_KEY_SECRET_NAME", "REPLY_SIGNING_KEY", ""),
 			// Failover: SES > Postal > SendGrid
 			EnableFailover: getEnvBool("EMAIL_FAILOVER_ENABLED", true),
 		},
@@ -249,11 +319,25 @@ func Load() (*Config, error) {
 			TwilioFrom:       getEnv("TWILIO_FROM", ""),
 			// Failover: SNS > Twilio
 			EnableFailover: getEnvBool("SMS_FAILOVER_ENABLED", true),
+			SenderIDs:      getEnv("SMS_SENDER_IDS", ""),
+			MaxSegments:    getEnvInt("SMS_MAX_SEGMENTS", 3),
 		},
 		Push: PushConfig{
 			FCMProjectID:   getEnv("FCM_PROJECT_ID", ""),
 			FCMCredentials: getEnv("FCM_CREDENTIALS_JSON", ""),
 		},
+		Usage: UsageConfig{
+			RateCard:            getEnv("NOTIFICATION_RATE_CARD", ""),
+			Currency:            getEnv("NOTIFICATION_COST_CURRENCY", "USD"),
+			SMSIncludedSegments: getEnvInt("SMS_INCLUDED_SEGMENTS_PER_MONTH", 100),
+			ReportInterval:      time.Duration(getEnvInt("USAGE_REPORT_INTERVAL_MINUTES", 60)) * time.Minute,
+		},
+		Campaign: CampaignConfig{
+			DispatchInterval:         time.Duration(getEnvInt("CAMPAIGN_DISPATCH_INTERVAL_SECONDS", 10)) * time.Second,
+			DefaultThrottlePerMinute: getEnvInt("CAMPAIGN_DEFAULT_THROTTLE_PER_MINUTE", 300),
+			MaxThrottlePerMinute:     getEnvInt("CAMPAIGN_MAX_THROTTLE_PER_MINUTE", 3000),
+			SendConcurrency:          getEnvInt("CAMPAIGN_SEND_CONCURRENCY", 5),
+		},
 		Verify: VerifyConfig{
 			TwilioVerifyServiceSID: getEnv("TWILIO_VERIFY_SERVICE_SID", ""),
 			TwilioAccountSID:       getEnv("TWILIO_ACCOUNT_SID", ""),
@@ -319,3 +403,12 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"notification-service/internal/models"
+	"notification-service/internal/repository"
+	"notification-service/internal/services"
+)
+
+// CampaignHandler manages marketing campaigns
+type CampaignHandler struct {
+	campaigns *services.CampaignService
+}
+
+// NewCampaignHandler creates a new campaign handler
+func NewCampaignHandler(campaigns *services.CampaignService) *CampaignHandler {
+	return &CampaignHandler{campaigns: campaigns}
+}
+
+// ScheduleCampaignRequest schedules a campaign; an omitted or past time starts it immediately
+type ScheduleCampaignRequest struct {
+	ScheduledFor *time.Time `json:"scheduledFor"`
+}
+
+// Create creates a draft campaign
+func (h *CampaignHandler) Create(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing tenant_id"})
+		return
+	}
+
+	var req services.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	campaign, err := h.campaigns.Create(c.Request.Context(), tenantID, c.GetString("user_id"), &req)
+	if err != nil {
+		h.respondError(c, err, "Failed to create campaign")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// List returns the tenant's campaigns
+func (h *CampaignHandler) List(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing tenant_id"})
+		return
+	}
+
+	filters := repository.CampaignFilters{
+		Status: c.Query("status"),
+		Limit:  parseIntWithDefault(c.Query("limit"), 50),
+		Offset: parseIntWithDefault(c.Query("offset"), 0),
+	}
+
+	campaigns, total, err := h.campaigns.List(c.Request.Context(), tenantID, filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list campaigns"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaigns,
+		"pagination": gin.H{
+			"limit":  filters.Limit,
+			"offset": filters.Offset,
+			"total":  total,
+		},
+	})
+}
+
+// Get returns a campaign
+func (h *CampaignHandler) Get(c *gin.Context) {
+	id, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	campaign, err := h.campaigns.Get(c.Request.Context(), c.GetString("tenant_id"), id)
+	if err != nil {
+		h.respondError(c, err, "Failed to get campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// Update changes a draft campaign
+func (h *CampaignHandler) Update(c *gin.Context) {
+	id, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	campaign, err := h.campaigns.Update(c.Request.Context(), c.GetString("tenant_id"), id, &req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// Delete removes a campaign that is not scheduled or sending
+func (h *CampaignHandler) Delete(c *gin.Context) {
+	id, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.campaigns.Delete(c.Request.Context(), c.GetString("tenant_id"), id); err != nil {
+		h.respondError(c, err, "Failed to delete campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// AddRecipients uploads recipients to a LIST campaign
+func (h *CampaignHandler) AddRecipients(c *gin.Context) {
+	id, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	var req services.AddRecipientsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	added, campaign, err := h.campaigns.AddRecipients(c.Request.Context(), c.GetString("tenant_id"), id, &req)
+	if err != nil {
+		h.respondError(c, err, "Failed to add recipients")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"added":   added,
+		"total":   campaign.TotalRecipients,
+	})
+}
+
+// ListRecipients returns a page of a campaign's recipients
+func (h *CampaignHandler) ListRecipients(c *gin.Context) {
+	id, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	limit := parseIntWithDefault(c.Query("limit"), 100)
+	offset := parseIntWithDefault(c.Query("offset"), 0)
+	recipients, total, err := h.campaigns.ListRecipients(c.Request.Context(), c.GetString("tenant_id"), id, c.Query("status"), limit, offset)
+	if err != nil {
+		h.respondError(c, err, "Failed to list recipients")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    recipients,
+		"pagination": gin.H{
+			"limit":  limit,
+			"offset": offset,
+			"total":  total,
+		},
+	})
+}
+
+// Schedule queues a draft campaign for sending
+func (h *CampaignHandler) Schedule(c *gin.Context) {
+	id, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	var req ScheduleCampaignRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	campaign, err := h.campaigns.Schedule(c.Request.Context(), c.GetString("tenant_id"), id, req.ScheduledFor)
+	if err != nil {
+		h.respondError(c, err, "Failed to schedule campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// Pause suspends a scheduled or sending campaign
+func (h *CampaignHandler) Pause(c *gin.Context) {
+	h.changeStatus(c, h.campaigns.Pause, "Failed to pause campaign")
+}
+
+// Resume continues a paused campaign
+func (h *CampaignHandler) Resume(c *gin.Context) {
+	h.changeStatus(c, h.campaigns.Resume, "Failed to resume campaign")
+}
+
+// Cancel stops a campaign
+func (h *CampaignHandler) Cancel(c *gin.Context) {
+	h.changeStatus(c, h.campaigns.Cancel, "Failed to cancel campaign")
+}
+
+// Stats returns send progress and delivery/open counts for a campaign
+func (h *CampaignHandler) Stats(c *gin.Context) {
+	id, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.campaigns.Stats(c.Request.Context(), c.GetString("tenant_id"), id)
+	if err != nil {
+		h.respondError(c, err, "Failed to get campaign stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stats,
+	})
+}
+
+// changeStatus applies a lifecycle action to a campaign
+func (h *CampaignHandler) changeStatus(c *gin.Context, action func(context.Context, string, uuid.UUID) (*models.Campaign, error), failure string) {
+	id, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	campaign, err := action(c.Request.Context(), c.GetString("tenant_id"), id)
+	if err != nil {
+		h.respondError(c, err, failure)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// respondError maps campaign service errors to HTTP responses
+func (h *CampaignHandler) respondError(c *gin.Context, err error, failure string) {
+	switch {
+	case errors.Is(err, services.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+	case errors.Is(err, services.ErrInvalidCampaign):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCampaignState):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSegmentsUnavailable):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		log.Printf("[CampaignHandler] %s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure})
+	}
+}
+
+// parseCampaignID parses the campaign ID path parameter, responding 400 if invalid
+func parseCampaignID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
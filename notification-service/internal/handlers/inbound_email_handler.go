@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"notification-service/internal/models"
+	"notification-service/internal/services"
+)
+
+// maxInboundWebhookBytes caps inbound webhook bodies (Postal includes attachments)
+const maxInboundWebhookBytes = 10 << 20
+
+// InboundEmailHandler receives replies to sent emails from the Postal and SES inbound webhooks
+type InboundEmailHandler struct {
+	inbound       *services.InboundEmailService
+	webhookSecret string
+	httpClient    *http.Client // Confirms SNS subscriptions
+}
+
+// NewInboundEmailHandler creates a new inbound email handler. Webhooks must present
+// webhookSecret as the token query parameter or X-Webhook-Token header.
+func NewInboundEmailHandler(inbound *services.InboundEmailService, webhookSecret string) *InboundEmailHandler {
+	return &InboundEmailHandler{
+		inbound:       inbound,
+		webhookSecret: webhookSecret,
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// snsEnvelope is an Amazon SNS HTTP(S) delivery
+type snsEnvelope struct {
+	Type         string `json:"Type"`
+	MessageID    string `json:"MessageId"`
+	TopicArn     string `json:"TopicArn"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+// Postal receives a message from a Postal HTTP endpoint route (JSON "Hash" format)
+func (h *InboundEmailHandler) Postal(c *gin.Context) {
+	if !h.authorized(c) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid webhook token"})
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxInboundWebhookBytes))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+		return
+	}
+
+	email, recipients, err := services.ParsePostalInbound(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.process(c, email, recipients)
+}
+
+// SES receives an SES receipt rule notification delivered by an SNS HTTPS subscription,
+// confirming the subscription when SNS first calls
+func (h *InboundEmailHandler) SES(c *gin.Context) {
+	if !h.authorized(c) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid webhook token"})
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxInboundWebhookBytes))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+		return
+	}
+
+	var envelope snsEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SNS message"})
+		return
+	}
+
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		if err := h.confirmSubscription(c, envelope.SubscribeURL); err != nil {
+			log.Printf("[INBOUND] Failed to confirm SNS subscription to %s: %v", envelope.TopicArn, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to confirm subscription"})
+			return
+		}
+		log.Printf("[INBOUND] Confirmed SNS subscription to %s", envelope.TopicArn)
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	case "Notification":
+		email, recipients, err := services.ParseSESInbound([]byte(envelope.Message))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.process(c, email, recipients)
+	default:
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	}
+}
+
+// process routes a parsed email; storage failures return 500 so the provider retries
+func (h *InboundEmailHandler) process(c *gin.Context, email *models.InboundEmail, recipients []string) {
+	stored, duplicate, err := h.inbound.Process(c.Request.Context(), email, recipients)
+	if err != nil {
+		log.Printf("[INBOUND] Failed to process %s message %s: %v", email.Provider, email.MessageID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process inbound email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"id":        stored.ID,
+		"status":    stored.Status,
+		"duplicate": duplicate,
+	})
+}
+
+// authorized checks the shared webhook secret in constant time
+func (h *InboundEmailHandler) authorized(c *gin.Context) bool {
+	token := c.GetHeader("X-Webhook-Token")
+	if token == "" {
+		token = c.Query("token")
+	}
+	return h.webhookSecret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.webhookSecret)) == 1
+}
+
+// confirmSubscription visits the SubscribeURL of an SNS subscription confirmation. Only
+// HTTPS URLs on an SNS endpoint are followed.
+func (h *InboundEmailHandler) confirmSubscription(c *gin.Context, subscribeURL string) error {
+	parsed, err := url.Parse(subscribeURL)
+	if err != nil {
+		return err
+	}
+	host := parsed.Hostname()
+	if parsed.Scheme != "https" || !strings.HasPrefix(host, "sns.") || !strings.HasSuffix(host, ".amazonaws.com") {
+		return errors.New("subscribe URL is not an SNS endpoint")
+	}
+
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, subscribeURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("SNS returned " + resp.Status)
+	}
+	return nil
+}
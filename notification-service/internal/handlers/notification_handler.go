@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -27,6 +28,10 @@ type NotificationHandler struct {
 	sender       *NotificationSender
 	templateEng  *template.Engine
 	rateLimiter  *middleware.EmailRateLimiter
+	reputation   *services.ReputationMonitor
+	costTracker  *services.CostTracker
+	smsPolicy    *services.SMSPolicy
+	replyRouter  *services.ReplyRouter
 }
 
 // NotificationSender sends notifications via different channels
@@ -87,6 +92,26 @@ func (h *NotificationHandler) SetRateLimiter(rateLimiter *middleware.EmailRateLi
 	h.rateLimiter = rateLimiter
 }
 
+// SetReputationMonitor enables per-domain sender identity, bulk throttling and warm-up limits
+func (h *NotificationHandler) SetReputationMonitor(reputation *services.ReputationMonitor) {
+	h.reputation = reputation
+}
+
+// SetCostTracker enables per-message provider cost tracking
+func (h *NotificationHandler) SetCostTracker(costTracker *services.CostTracker) {
+	h.costTracker = costTracker
+}
+
+// SetReplyRouter routes replies to sent emails back to the service via per-notification Reply-To addresses
+func (h *NotificationHandler) SetReplyRouter(replyRouter *services.ReplyRouter) {
+	h.replyRouter = replyRouter
+}
+
+// SetSMSPolicy enables country-specific SMS sender IDs and the SMS segment limit
+func (h *NotificationHandler) SetSMSPolicy(smsPolicy *services.SMSPolicy) {
+	h.smsPolicy = smsPolicy
+}
+
 // SendRequest represents a send notification request
 type SendRequest struct {
 	Channel        string                 `json:"channel" binding:"required,oneof=EMAIL SMS PUSH"`
@@ -172,6 +197,31 @@ func (h *NotificationHandler) Send(c *gin.Context) {
 		}
 	}
 
+	// Resolve the sending domain; bulk email from a domain with degraded reputation
+	// or past its warm-up volume is throttled to protect platform deliverability
+	var sendingDomain string
+	if models.NotificationChannel(req.Channel) == models.ChannelEmail && h.reputation != nil {
+		sendingDomain, _, _ = h.reputation.Sender(tenantID)
+		if services.IsBulkSend(req.Metadata) {
+			decision, err := h.reputation.CheckBulkSend(c.Request.Context(), sendingDomain)
+			if err != nil {
+				log.Printf("[NotificationHandler] Sender reputation check error: %v", err)
+				// Continue even if the check fails (fail-open for availability)
+			} else if !decision.Allowed {
+				c.JSON(http.StatusTooManyRequests, gin.H{
+					"error":           "Bulk sending throttled to protect sender reputation",
+					"code":            "SENDER_REPUTATION_THROTTLED",
+					"reason":          decision.Reason,
+					"domain":          decision.Domain,
+					"reputation":      decision.Reputation,
+					"daily_limit":     decision.DailyLimit,
+					"retry_after_sec": int(decision.RetryAfter.Seconds()),
+				})
+				return
+			}
+		}
+	}
+
 	// Derive title from subject or use default
 	title := req.Subject
 	if title == "" {
@@ -199,6 +249,7 @@ func (h *NotificationHandler) Send(c *gin.Context) {
 		Body:           req.Body,
 		BodyHTML:       req.BodyHTML,
 		ScheduledFor:   req.ScheduledFor,
+		SendingDomain:  sendingDomain,
 	}
 
 	// Parse recipient ID if provided
@@ -214,6 +265,7 @@ func (h *NotificationHandler) Send(c *gin.Context) {
 	}
 
 	// Handle template if provided
+	smsMaxSegments := 0
 	if req.TemplateName != "" || req.TemplateID != "" {
 		var tmpl *models.NotificationTemplate
 		var err error
@@ -231,20 +283,22 @@ func (h *NotificationHandler) Send(c *gin.Context) {
 		if err == nil && tmpl != nil {
 			notification.TemplateID = &tmpl.ID
 			notification.TemplateName = tmpl.Name
+			smsMaxSegments = tmpl.MaxSegments
 
-			// Render database template
+			// Render database template, with request variables over the template's defaults
+			variables := mergeTemplateData(tmpl.DefaultData, req.Variables)
 			if tmpl.Subject != "" && notification.Subject == "" {
-				if rendered, err := h.templateEng.RenderText(tmpl.Subject, req.Variables); err == nil {
+				if rendered, err := h.templateEng.RenderText(tmpl.Subject, variables); err == nil {
 					notification.Subject = rendered
 				}
 			}
 			if tmpl.BodyTemplate != "" && notification.Body == "" {
-				if rendered, err := h.templateEng.RenderText(tmpl.BodyTemplate, req.Variables); err == nil {
+				if rendered, err := h.templateEng.RenderText(tmpl.BodyTemplate, variables); err == nil {
 					notification.Body = rendered
 				}
 			}
 			if tmpl.HTMLTemplate != "" && notification.BodyHTML == "" {
-				if rendered, err := h.templateEng.RenderHTML(tmpl.HTMLTemplate, req.Variables); err == nil {
+				if rendered, err := h.templateEng.RenderHTML(tmpl.HTMLTemplate, variables); err == nil {
 					notification.BodyHTML = rendered
 				}
 			}
@@ -280,6 +334,20 @@ func (h *NotificationHandler) Send(c *gin.Context) {
 		_ = usedEmbeddedTemplate // silence unused variable warning
 	}
 
+	// Reject SMS longer than the template's segment limit before anything is stored or sent
+	var smsEstimate *services.SMSEstimate
+	if notification.Channel == models.ChannelSMS {
+		smsEstimate = h.costTracker.EstimateSMS(notification.Body, h.smsPolicy.MaxSegmentsFor(smsMaxSegments))
+		smsEstimate.SenderID = h.smsPolicy.SenderID(notification.RecipientPhone)
+		if smsEstimate.ExceedsMaxSegments() {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": fmt.Sprintf("SMS is %d segments, more than the limit of %d", smsEstimate.Segments, smsEstimate.MaxSegments),
+				"sms":   smsEstimate,
+			})
+			return
+		}
+	}
+
 	// Store variables and metadata
 	if req.Variables != nil {
 		if jsonData, err := json.Marshal(req.Variables); err == nil {
@@ -304,12 +372,16 @@ func (h *NotificationHandler) Send(c *gin.Context) {
 		// so the caller knows immediately if sending failed
 		if notification.Priority == models.PriorityHigh || notification.Priority == models.PriorityCritical {
 			sendErr := h.sendNotificationSync(c.Request.Context(), notification)
-			if sendErr != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
+			if sendErr != nil && !errors.Is(sendErr, services.ErrMarketingProviderUnavailable) {
+				response := gin.H{
 					"success": false,
 					"error":   sendErr.Error(),
 					"data":    notification,
-				})
+				}
+				if smsEstimate != nil {
+					response["sms"] = smsEstimate
+				}
+				c.JSON(http.StatusInternalServerError, response)
 				return
 			}
 		} else {
@@ -318,10 +390,65 @@ func (h *NotificationHandler) Send(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"success": true,
 		"data":    notification,
-	})
+	}
+	if smsEstimate != nil {
+		response["sms"] = smsEstimate
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// Deliver sends a stored notification and returns any error; used by the campaign dispatcher.
+// A send deferred while the marketing provider is down is not an error: it goes out on recovery
+func (h *NotificationHandler) Deliver(ctx context.Context, notification *models.Notification) error {
+	err := h.sendNotificationSync(ctx, notification)
+	if errors.Is(err, services.ErrMarketingProviderUnavailable) {
+		return nil
+	}
+	return err
+}
+
+// providerDeferredBatchSize is the number of deferred sends claimed per replay batch
+const providerDeferredBatchSize = 100
+
+// ReplayDeferred resends notifications deferred while their provider was down, stopping
+// early if the provider goes down again. Returns the number of notifications sent
+func (h *NotificationHandler) ReplayDeferred(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		notifications, err := h.notifRepo.ClaimProviderDeferred(ctx, providerDeferredBatchSize)
+		if err != nil {
+			return sent, fmt.Errorf("failed to claim deferred notifications: %w", err)
+		}
+		if len(notifications) == 0 {
+			return sent, nil
+		}
+
+		deferred := false
+		for i := range notifications {
+			err := h.sendNotificationSync(ctx, &notifications[i])
+			switch {
+			case err == nil:
+				sent++
+			case errors.Is(err, services.ErrMarketingProviderUnavailable):
+				deferred = true
+			}
+		}
+		if deferred {
+			log.Printf("[NotificationHandler] Provider unavailable again, %d deferred notification(s) replayed", sent)
+			return sent, nil
+		}
+	}
+}
+
+// deferSend parks a send until its provider recovers instead of failing it
+func (h *NotificationHandler) deferSend(ctx context.Context, notification *models.Notification, err error) {
+	notification.Status = models.StatusQueuedProviderDown
+	if updateErr := h.notifRepo.UpdateStatus(ctx, notification.ID, models.StatusQueuedProviderDown, "", err.Error()); updateErr != nil {
+		log.Printf("[NotificationHandler] Failed to defer notification %s: %v", notification.ID, updateErr)
+	}
 }
 
 // sendNotificationSync sends the notification synchronously and returns any error
@@ -363,16 +490,21 @@ func (h *NotificationHandler) sendNotificationSync(ctx context.Context, notifica
 		BodyHTML: notification.BodyHTML,
 	}
 
-	// Parse metadata for push notifications
-	if notification.Channel == models.ChannelPush && notification.Metadata != nil {
+	// Parse metadata for push notifications and email routing (e.g. marketing category)
+	if (notification.Channel == models.ChannelPush || notification.Channel == models.ChannelEmail) && notification.Metadata != nil {
 		var metadata map[string]interface{}
 		if err := json.Unmarshal(notification.Metadata, &metadata); err == nil {
 			message.Metadata = metadata
 		}
 	}
+	h.applySenderIdentity(notification, message)
 
 	// Send
 	result, err := provider.Send(ctx, message)
+	if errors.Is(err, services.ErrMarketingProviderUnavailable) {
+		h.deferSend(ctx, notification, err)
+		return fmt.Errorf("notification deferred: %w", err)
+	}
 	if err != nil {
 		h.notifRepo.UpdateStatus(ctx, notification.ID, models.StatusFailed, "", err.Error())
 		return fmt.Errorf("failed to send notification: %w", err)
@@ -381,13 +513,18 @@ func (h *NotificationHandler) sendNotificationSync(ctx context.Context, notifica
 	if result.Success {
 		h.notifRepo.UpdateStatus(ctx, notification.ID, models.StatusSent, result.ProviderID, "")
 
-		// Record successful email send for rate limiting
-		if notification.Channel == models.ChannelEmail && h.rateLimiter != nil {
+		// Record successful email send for rate limiting; campaign sends are throttled by
+		// the campaign dispatcher and don't consume the tenant's transactional allowance
+		if notification.Channel == models.ChannelEmail && h.rateLimiter != nil && notification.CampaignID == nil {
 			action := h.getEmailAction(notification.TemplateName, nil)
 			if err := h.rateLimiter.RecordSend(ctx, notification.TenantID, notification.RecipientEmail, action); err != nil {
 				log.Printf("[NotificationHandler] Failed to record email send for rate limiting: %v", err)
 			}
 		}
+		h.recordDomainSend(ctx, notification, message)
+		if err := h.costTracker.RecordSend(ctx, notification, result.ProviderName, message); err != nil {
+			log.Printf("[NotificationHandler] Failed to record notification cost: %v", err)
+		}
 
 		return nil
 	}
@@ -436,16 +573,21 @@ func (h *NotificationHandler) sendNotification(ctx context.Context, notification
 		BodyHTML: notification.BodyHTML,
 	}
 
-	// Parse metadata for push notifications
-	if notification.Channel == models.ChannelPush && notification.Metadata != nil {
+	// Parse metadata for push notifications and email routing (e.g. marketing category)
+	if (notification.Channel == models.ChannelPush || notification.Channel == models.ChannelEmail) && notification.Metadata != nil {
 		var metadata map[string]interface{}
 		if err := json.Unmarshal(notification.Metadata, &metadata); err == nil {
 			message.Metadata = metadata
 		}
 	}
+	h.applySenderIdentity(notification, message)
 
 	// Send
 	result, err := provider.Send(ctx, message)
+	if errors.Is(err, services.ErrMarketingProviderUnavailable) {
+		h.deferSend(ctx, notification, err)
+		return
+	}
 	if err != nil {
 		h.notifRepo.UpdateStatus(ctx, notification.ID, models.StatusFailed, "", err.Error())
 		return
@@ -454,13 +596,18 @@ func (h *NotificationHandler) sendNotification(ctx context.Context, notification
 	if result.Success {
 		h.notifRepo.UpdateStatus(ctx, notification.ID, models.StatusSent, result.ProviderID, "")
 
-		// Record successful email send for rate limiting
-		if notification.Channel == models.ChannelEmail && h.rateLimiter != nil {
+		// Record successful email send for rate limiting; campaign sends are throttled by
+		// the campaign dispatcher and don't consume the tenant's transactional allowance
+		if notification.Channel == models.ChannelEmail && h.rateLimiter != nil && notification.CampaignID == nil {
 			action := h.getEmailAction(notification.TemplateName, nil)
 			if err := h.rateLimiter.RecordSend(ctx, notification.TenantID, notification.RecipientEmail, action); err != nil {
 				log.Printf("[NotificationHandler] Failed to record email send for rate limiting: %v", err)
 			}
 		}
+		h.recordDomainSend(ctx, notification, message)
+		if err := h.costTracker.RecordSend(ctx, notification, result.ProviderName, message); err != nil {
+			log.Printf("[NotificationHandler] Failed to record notification cost: %v", err)
+		}
 	} else {
 		errorMsg := "Send failed"
 		if result.Error != nil {
@@ -470,6 +617,42 @@ func (h *NotificationHandler) sendNotification(ctx context.Context, notification
 	}
 }
 
+// applySenderIdentity sends tenant email from the tenant's registered sending domain with
+// a routed Reply-To address, and SMS from the sender ID configured for the recipient's country
+func (h *NotificationHandler) applySenderIdentity(notification *models.Notification, message *services.Message) {
+	if notification.Channel == models.ChannelSMS {
+		message.From = h.smsPolicy.SenderID(message.To)
+		return
+	}
+	if notification.Channel != models.ChannelEmail {
+		return
+	}
+	if message.ReplyTo == "" {
+		message.ReplyTo = h.replyRouter.Address(notification.ID)
+	}
+	if h.reputation == nil {
+		return
+	}
+	domain, fromAddress, fromName := h.reputation.Sender(notification.TenantID)
+	if notification.SendingDomain == "" {
+		notification.SendingDomain = domain
+	}
+	if fromAddress != "" && notification.SendingDomain == domain {
+		message.From = fromAddress
+		message.FromName = fromName
+	}
+}
+
+// recordDomainSend counts a successful email towards its sending domain's reputation stats
+func (h *NotificationHandler) recordDomainSend(ctx context.Context, notification *models.Notification, message *services.Message) {
+	if notification.Channel != models.ChannelEmail || h.reputation == nil {
+		return
+	}
+	if err := h.reputation.RecordSend(ctx, notification.SendingDomain, services.IsBulkSend(message.Metadata)); err != nil {
+		log.Printf("[NotificationHandler] Failed to record send for sender reputation: %v", err)
+	}
+}
+
 // List returns notifications for a tenant
 func (h *NotificationHandler) List(c *gin.Context) {
 	tenantID := c.GetString("tenant_id")
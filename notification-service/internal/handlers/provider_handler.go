@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notification-service/internal/services"
+)
+
+// providerChain is implemented by failover providers that expose per-provider status
+type providerChain interface {
+	GetProviderStatuses() []services.ProviderStatus
+	IsHealthy() bool
+}
+
+// ProviderHandler exposes delivery provider status for operators
+type ProviderHandler struct {
+	emailProvider services.Provider
+	smsProvider   services.Provider
+	monitor       *services.ProviderHealthMonitor
+}
+
+// NewProviderHandler creates a new provider status handler
+func NewProviderHandler(emailProvider, smsProvider services.Provider, monitor *services.ProviderHealthMonitor) *ProviderHandler {
+	return &ProviderHandler{
+		emailProvider: emailProvider,
+		smsProvider:   smsProvider,
+		monitor:       monitor,
+	}
+}
+
+// List returns the failover chain and health of each configured provider
+func (h *ProviderHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"email": describeChannel(h.emailProvider, h.monitor),
+			"sms":   describeChannel(h.smsProvider, nil),
+		},
+	})
+}
+
+// describeChannel summarizes a channel's provider, expanding failover chains
+func describeChannel(provider services.Provider, monitor *services.ProviderHealthMonitor) gin.H {
+	if provider == nil {
+		return gin.H{"configured": false}
+	}
+
+	if chain, ok := provider.(providerChain); ok {
+		return gin.H{
+			"configured": true,
+			"name":       provider.GetName(),
+			"healthy":    chain.IsHealthy(),
+			"providers":  chain.GetProviderStatuses(),
+		}
+	}
+
+	status := services.ProviderStatus{
+		Name:      provider.GetName(),
+		Position:  1,
+		Available: monitor.IsAvailable(provider.GetName()),
+	}
+	if health, ok := monitor.Health(provider.GetName()); ok {
+		status.Health = &health
+	}
+	return gin.H{
+		"configured": true,
+		"name":       provider.GetName(),
+		"healthy":    status.Available,
+		"providers":  []services.ProviderStatus{status},
+	}
+}
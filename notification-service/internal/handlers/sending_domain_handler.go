@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"notification-service/internal/models"
+	"notification-service/internal/repository"
+	"notification-service/internal/services"
+)
+
+// SendingDomainHandler manages tenant sending domains and ingests deliverability events
+type SendingDomainHandler struct {
+	reputation *services.ReputationMonitor
+	notifRepo  repository.NotificationRepository
+	publisher  services.UsagePublisher // Optional; publishes email opens for in-app read sync
+}
+
+// NewSendingDomainHandler creates a new sending domain handler
+func NewSendingDomainHandler(reputation *services.ReputationMonitor, notifRepo repository.NotificationRepository) *SendingDomainHandler {
+	return &SendingDomainHandler{
+		reputation: reputation,
+		notifRepo:  notifRepo,
+	}
+}
+
+// SetPublisher enables publishing first email opens so notification-hub can mark the
+// in-app copy of the notification read
+func (h *SendingDomainHandler) SetPublisher(publisher services.UsagePublisher) {
+	h.publisher = publisher
+}
+
+// Register adds a sending domain for the tenant and starts its warm-up
+func (h *SendingDomainHandler) Register(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing tenant_id"})
+		return
+	}
+
+	var req services.RegisterSendingDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domain, err := h.reputation.RegisterDomain(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidSendingDomain):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrSendingDomainExists), errors.Is(err, services.ErrSendingDomainTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("[SendingDomainHandler] Failed to register sending domain: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register sending domain"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    domain,
+	})
+}
+
+// List returns the tenant's sending domains with today's bulk usage
+func (h *SendingDomainHandler) List(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing tenant_id"})
+		return
+	}
+	if tenantID == models.PlatformTenantID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid tenant"})
+		return
+	}
+
+	domains, err := h.reputation.ListDomains(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sending domains"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    domains,
+	})
+}
+
+// Get returns a sending domain with its daily stats for the evaluation window
+func (h *SendingDomainHandler) Get(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	domain, err := h.reputation.GetDomain(c.Request.Context(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sending domain not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get sending domain"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    domain,
+	})
+}
+
+// Delete removes a sending domain; the tenant's email reverts to the platform domain
+func (h *SendingDomainHandler) Delete(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.reputation.DeleteDomain(c.Request.Context(), tenantID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sending domain not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete sending domain"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Overview returns reputation and warm-up state for every sending domain on the platform
+func (h *SendingDomainHandler) Overview(c *gin.Context) {
+	domains, err := h.reputation.ListDomains(c.Request.Context(), models.PlatformTenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sending domains"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    domains,
+	})
+}
+
+// DeliverabilityEvent is a delivery outcome reported by a provider webhook or bounce processor
+// Either ProviderID (the provider's message ID) or Domain identifies the sending domain
+type DeliverabilityEvent struct {
+	Type       models.DeliverabilityEventType `json:"type" binding:"required,oneof=delivered bounce complaint open"`
+	ProviderID string                         `json:"providerId"`
+	Domain     string                         `json:"domain"`
+}
+
+// DeliverabilityEventsRequest is a batch of deliverability events
+type DeliverabilityEventsRequest struct {
+	Events []DeliverabilityEvent `json:"events" binding:"required,min=1,max=1000,dive"`
+}
+
+// IngestEvents records deliveries, bounces and complaints against their sending domains.
+// Opens only update the notification they belong to.
+func (h *SendingDomainHandler) IngestEvents(c *gin.Context) {
+	var req DeliverabilityEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	recorded := 0
+	skipped := 0
+	for _, event := range req.Events {
+		domain := services.DomainOf(event.Domain)
+
+		if event.ProviderID != "" {
+			notification, err := h.notifRepo.GetByProviderID(ctx, event.ProviderID)
+			if err == nil {
+				if domain == "" {
+					domain = notification.SendingDomain
+				}
+				h.updateNotificationStatus(c, notification, event.Type)
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("[SendingDomainHandler] Failed to look up notification %s: %v", event.ProviderID, err)
+			}
+
+			if event.Type == models.EventOpen {
+				if err == nil {
+					recorded++
+				} else {
+					skipped++
+				}
+				continue
+			}
+		} else if event.Type == models.EventOpen {
+			skipped++
+			continue
+		}
+
+		if domain == "" {
+			skipped++
+			continue
+		}
+		if err := h.reputation.RecordEvent(ctx, domain, event.Type); err != nil {
+			log.Printf("[SendingDomainHandler] Failed to record %s for %s: %v", event.Type, domain, err)
+			skipped++
+			continue
+		}
+		recorded++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"recorded": recorded,
+		"skipped":  skipped,
+	})
+}
+
+// updateNotificationStatus reflects a delivery outcome on the notification it belongs to
+func (h *SendingDomainHandler) updateNotificationStatus(c *gin.Context, notification *models.Notification, eventType models.DeliverabilityEventType) {
+	var status models.NotificationStatus
+	switch eventType {
+	case models.EventDelivered:
+		status = models.StatusDelivered
+	case models.EventBounce:
+		status = models.StatusBounced
+	case models.EventOpen:
+		openedAt := time.Now()
+		firstOpen, err := h.notifRepo.MarkOpened(c.Request.Context(), notification.ID, openedAt)
+		if err != nil {
+			log.Printf("[SendingDomainHandler] Failed to mark notification %s opened: %v", notification.ID, err)
+			return
+		}
+		if firstOpen {
+			h.publishOpened(notification, openedAt)
+		}
+		return
+	default:
+		return
+	}
+	if err := h.notifRepo.UpdateStatus(c.Request.Context(), notification.ID, status, notification.ProviderID, ""); err != nil {
+		log.Printf("[SendingDomainHandler] Failed to update notification %s status: %v", notification.ID, err)
+	}
+}
+
+// publishOpened publishes the first open of an email sent for a platform event.
+// Failures are logged; the open itself is already recorded.
+func (h *SendingDomainHandler) publishOpened(notification *models.Notification, openedAt time.Time) {
+	if h.publisher == nil {
+		return
+	}
+	event := services.NewEmailOpenedEvent(notification, openedAt)
+	if event == nil {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err == nil {
+		err = h.publisher.Publish(services.EmailOpenedSubject, data)
+	}
+	if err != nil {
+		log.Printf("[SendingDomainHandler] Failed to publish open of notification %s: %v", notification.ID, err)
+	}
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"notification-service/internal/models"
 	"notification-service/internal/repository"
+	"notification-service/internal/services"
 	"notification-service/internal/template"
 )
 
@@ -14,6 +16,8 @@ import (
 type TemplateHandler struct {
 	templateRepo repository.TemplateRepository
 	templateEng  *template.Engine
+	costTracker  *services.CostTracker
+	smsPolicy    *services.SMSPolicy
 }
 
 // NewTemplateHandler creates a new template handler
@@ -24,6 +28,12 @@ func NewTemplateHandler(templateRepo repository.TemplateRepository) *TemplateHan
 	}
 }
 
+// SetSMSEstimates enables segment and cost estimates for SMS templates
+func (h *TemplateHandler) SetSMSEstimates(costTracker *services.CostTracker, smsPolicy *services.SMSPolicy) {
+	h.costTracker = costTracker
+	h.smsPolicy = smsPolicy
+}
+
 // CreateTemplateRequest represents a create template request
 type CreateTemplateRequest struct {
 	Name         string                 `json:"name" binding:"required"`
@@ -36,6 +46,63 @@ type CreateTemplateRequest struct {
 	Variables    map[string]interface{} `json:"variables"`
 	DefaultData  map[string]interface{} `json:"defaultData"`
 	Tags         []string               `json:"tags"`
+	MaxSegments  int                    `json:"maxSegments" binding:"omitempty,min=1,max=10"` // SMS only
+}
+
+// estimateSMS renders an SMS template body over its default data and estimates its segments and cost
+func (h *TemplateHandler) estimateSMS(tmpl *models.NotificationTemplate, variables map[string]interface{}, recipientPhone string) (*services.SMSEstimate, error) {
+	body, err := h.templateEng.RenderText(tmpl.BodyTemplate, mergeTemplateData(tmpl.DefaultData, variables))
+	if err != nil {
+		return nil, err
+	}
+	estimate := h.costTracker.EstimateSMS(body, h.smsPolicy.MaxSegmentsFor(tmpl.MaxSegments))
+	estimate.SenderID = h.smsPolicy.SenderID(recipientPhone)
+	return estimate, nil
+}
+
+// templateResponse returns a template, with a segment and cost estimate of its default
+// rendering for SMS templates
+func (h *TemplateHandler) templateResponse(tmpl *models.NotificationTemplate) gin.H {
+	response := gin.H{
+		"success": true,
+		"data":    tmpl,
+	}
+	if tmpl.Channel == models.ChannelSMS {
+		if estimate, err := h.estimateSMS(tmpl, nil, ""); err == nil {
+			response["sms"] = estimate
+		}
+	}
+	return response
+}
+
+// mergeTemplateData overlays request variables on a template's default data
+func mergeTemplateData(defaults []byte, variables map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{})
+	if len(defaults) > 0 {
+		_ = json.Unmarshal(defaults, &data)
+	}
+	for k, v := range variables {
+		data[k] = v
+	}
+	return data
+}
+
+// applyTemplateRequest copies the request fields onto a template
+func applyTemplateRequest(tmpl *models.NotificationTemplate, req *CreateTemplateRequest) {
+	tmpl.Name = req.Name
+	tmpl.Description = req.Description
+	tmpl.Channel = models.NotificationChannel(req.Channel)
+	tmpl.Category = req.Category
+	tmpl.Subject = req.Subject
+	tmpl.BodyTemplate = req.BodyTemplate
+	tmpl.HTMLTemplate = req.HTMLTemplate
+	tmpl.MaxSegments = req.MaxSegments
+	tmpl.DefaultData = nil
+	if req.DefaultData != nil {
+		if jsonData, err := json.Marshal(req.DefaultData); err == nil {
+			tmpl.DefaultData = jsonData
+		}
+	}
 }
 
 // List returns templates for a tenant
@@ -87,10 +154,7 @@ func (h *TemplateHandler) Get(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    tmpl,
-	})
+	c.JSON(http.StatusOK, h.templateResponse(tmpl))
 }
 
 // Create creates a new template
@@ -115,17 +179,18 @@ func (h *TemplateHandler) Create(c *gin.Context) {
 	}
 
 	tmpl := &models.NotificationTemplate{
-		TenantID:     tenantID,
-		Name:         req.Name,
-		Description:  req.Description,
-		Channel:      models.NotificationChannel(req.Channel),
-		Category:     req.Category,
-		Subject:      req.Subject,
-		BodyTemplate: req.BodyTemplate,
-		HTMLTemplate: req.HTMLTemplate,
-		IsActive:     true,
-		IsSystem:     false,
-		Version:      1,
+		TenantID: tenantID,
+		IsActive: true,
+		IsSystem: false,
+		Version:  1,
+	}
+	applyTemplateRequest(tmpl, &req)
+
+	if tmpl.Channel == models.ChannelSMS {
+		if _, err := h.estimateSMS(tmpl, nil, ""); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SMS body template: " + err.Error()})
+			return
+		}
 	}
 
 	if err := h.templateRepo.Create(c.Request.Context(), tmpl); err != nil {
@@ -133,10 +198,7 @@ func (h *TemplateHandler) Create(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"success": true,
-		"data":    tmpl,
-	})
+	c.JSON(http.StatusCreated, h.templateResponse(tmpl))
 }
 
 // Update updates a template
@@ -172,24 +234,22 @@ func (h *TemplateHandler) Update(c *gin.Context) {
 	}
 
 	// Update fields
-	tmpl.Name = req.Name
-	tmpl.Description = req.Description
-	tmpl.Channel = models.NotificationChannel(req.Channel)
-	tmpl.Category = req.Category
-	tmpl.Subject = req.Subject
-	tmpl.BodyTemplate = req.BodyTemplate
-	tmpl.HTMLTemplate = req.HTMLTemplate
+	applyTemplateRequest(tmpl, &req)
 	tmpl.Version++
 
+	if tmpl.Channel == models.ChannelSMS {
+		if _, err := h.estimateSMS(tmpl, nil, ""); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SMS body template: " + err.Error()})
+			return
+		}
+	}
+
 	if err := h.templateRepo.Update(c.Request.Context(), tmpl); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update template"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    tmpl,
-	})
+	c.JSON(http.StatusOK, h.templateResponse(tmpl))
 }
 
 // Delete deletes a template
@@ -225,7 +285,8 @@ func (h *TemplateHandler) Delete(c *gin.Context) {
 
 // TestRequest represents a template test request
 type TestRequest struct {
-	Variables map[string]interface{} `json:"variables"`
+	Variables      map[string]interface{} `json:"variables"`
+	RecipientPhone string                 `json:"recipientPhone"` // SMS only: resolves the country's sender ID
 }
 
 // Test renders a template with test variables
@@ -263,13 +324,20 @@ func (h *TemplateHandler) Test(c *gin.Context) {
 
 	// Render body
 	if tmpl.BodyTemplate != "" {
-		if rendered, err := h.templateEng.RenderText(tmpl.BodyTemplate, req.Variables); err == nil {
+		if rendered, err := h.templateEng.RenderText(tmpl.BodyTemplate, mergeTemplateData(tmpl.DefaultData, req.Variables)); err == nil {
 			result["body"] = rendered
 		} else {
 			result["bodyError"] = err.Error()
 		}
 	}
 
+	// Estimate segments and cost of the rendered SMS
+	if tmpl.Channel == models.ChannelSMS {
+		if estimate, err := h.estimateSMS(tmpl, req.Variables, req.RecipientPhone); err == nil {
+			result["sms"] = estimate
+		}
+	}
+
 	// Render HTML
 	if tmpl.HTMLTemplate != "" {
 		if rendered, err := h.templateEng.RenderHTML(tmpl.HTMLTemplate, req.Variables); err == nil {
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notification-service/internal/services"
+)
+
+// UsageHandler exposes per-tenant notification usage and provider costs
+type UsageHandler struct {
+	costTracker *services.CostTracker
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(costTracker *services.CostTracker) *UsageHandler {
+	return &UsageHandler{costTracker: costTracker}
+}
+
+// GetCosts returns the tenant's provider costs per channel and provider for a month
+// Query: period=YYYY-MM|current|previous (default current)
+func (h *UsageHandler) GetCosts(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing tenant_id"})
+		return
+	}
+
+	summary, err := h.costTracker.GetCosts(c.Request.Context(), tenantID, c.Query("period"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidUsagePeriod) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("[UsageHandler] Failed to aggregate costs: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get usage costs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summary,
+	})
+}
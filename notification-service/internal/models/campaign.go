@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// CampaignStatus represents the lifecycle state of a campaign
+type CampaignStatus string
+
+const (
+	CampaignDraft     CampaignStatus = "DRAFT"     // Editable; audience can be uploaded
+	CampaignScheduled CampaignStatus = "SCHEDULED" // Waiting for ScheduledFor
+	CampaignSending   CampaignStatus = "SENDING"   // Dispatcher is sending batches
+	CampaignPaused    CampaignStatus = "PAUSED"    // Sending suspended by the merchant
+	CampaignCompleted CampaignStatus = "COMPLETED" // Every recipient was processed
+	CampaignCancelled CampaignStatus = "CANCELLED" // Stopped by the merchant; remaining recipients are not sent
+	CampaignFailed    CampaignStatus = "FAILED"    // Audience could not be resolved
+)
+
+// CampaignAudienceType is where a campaign's recipients come from
+type CampaignAudienceType string
+
+const (
+	AudienceSegment CampaignAudienceType = "SEGMENT" // Contacts of a Mautic segment, resolved when sending starts
+	AudienceList    CampaignAudienceType = "LIST"    // Recipients uploaded to the campaign
+)
+
+// CampaignRecipientStatus represents the send state of a single recipient
+type CampaignRecipientStatus string
+
+const (
+	RecipientPending CampaignRecipientStatus = "PENDING"
+	RecipientSending CampaignRecipientStatus = "SENDING" // Claimed by a dispatcher batch
+	RecipientSent    CampaignRecipientStatus = "SENT"
+	RecipientFailed  CampaignRecipientStatus = "FAILED"
+	RecipientSkipped CampaignRecipientStatus = "SKIPPED" // Opted out of marketing email
+)
+
+// Campaign is a bulk email send of a template to an audience
+type Campaign struct {
+	ID          uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string              `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	Name        string              `json:"name" gorm:"type:varchar(255);not null"`
+	Description string              `json:"description" gorm:"type:text"`
+	TemplateID  uuid.UUID           `json:"templateId" gorm:"type:uuid;not null"`
+	Channel     NotificationChannel `json:"channel" gorm:"type:varchar(20);not null"`
+	Variables   datatypes.JSON      `json:"variables" gorm:"type:jsonb"` // Template variables shared by all recipients
+
+	// Audience
+	AudienceType     CampaignAudienceType `json:"audienceType" gorm:"type:varchar(20);not null"`
+	SegmentID        int                  `json:"segmentId,omitempty"` // Mautic segment for SEGMENT audiences
+	AudienceResolved bool                 `json:"audienceResolved" gorm:"default:false"`
+
+	// Scheduling and throttling
+	Status            CampaignStatus `json:"status" gorm:"type:varchar(20);not null;default:'DRAFT';index"`
+	ScheduledFor      *time.Time     `json:"scheduledFor"`
+	ThrottlePerMinute int            `json:"throttlePerMinute"`
+	NextBatchAt       *time.Time     `json:"-"` // Lease of the replica sending the current batch
+
+	// Progress
+	TotalRecipients int    `json:"totalRecipients" gorm:"default:0"`
+	SentCount       int    `json:"sentCount" gorm:"default:0"`
+	FailedCount     int    `json:"failedCount" gorm:"default:0"`
+	SkippedCount    int    `json:"skippedCount" gorm:"default:0"`
+	LastError       string `json:"lastError,omitempty" gorm:"type:text"`
+
+	StartedAt   *time.Time     `json:"startedAt"`
+	CompletedAt *time.Time     `json:"completedAt"`
+	CreatedBy   string         `json:"createdBy" gorm:"type:varchar(255)"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// CampaignRecipient is a single recipient of a campaign
+type CampaignRecipient struct {
+	ID             uuid.UUID               `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CampaignID     uuid.UUID               `json:"campaignId" gorm:"type:uuid;not null;uniqueIndex:idx_campaign_recipient_email;index:idx_campaign_recipient_status"`
+	TenantID       string                  `json:"tenantId" gorm:"type:varchar(255);not null"`
+	Email          string                  `json:"email" gorm:"type:varchar(255);not null;uniqueIndex:idx_campaign_recipient_email"`
+	FirstName      string                  `json:"firstName" gorm:"type:varchar(255)"`
+	LastName       string                  `json:"lastName" gorm:"type:varchar(255)"`
+	Variables      datatypes.JSON          `json:"variables" gorm:"type:jsonb"` // Per-recipient template variables
+	Status         CampaignRecipientStatus `json:"status" gorm:"type:varchar(20);not null;default:'PENDING';index:idx_campaign_recipient_status"`
+	NotificationID *uuid.UUID              `json:"notificationId" gorm:"type:uuid"`
+	ErrorMessage   string                  `json:"errorMessage,omitempty" gorm:"type:text"`
+	SentAt         *time.Time              `json:"sentAt"`
+	CreatedAt      time.Time               `json:"createdAt"`
+	UpdatedAt      time.Time               `json:"updatedAt"`
+}
+
+func (Campaign) TableName() string {
+	return "notification_campaigns"
+}
+
+func (CampaignRecipient) TableName() string {
+	return "notification_campaign_recipients"
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InboundEmailStatus is the outcome of processing a received email
+type InboundEmailStatus string
+
+const (
+	InboundForwarded InboundEmailStatus = "FORWARDED" // Forwarded to the tenant's support email
+	InboundPublished InboundEmailStatus = "PUBLISHED" // Published as support.email.received only
+	InboundUnmatched InboundEmailStatus = "UNMATCHED" // No originating notification found
+	InboundIgnored   InboundEmailStatus = "IGNORED"   // Auto-replies and loops; never forwarded
+	InboundFailed    InboundEmailStatus = "FAILED"    // Matched but neither forwarded nor published
+)
+
+// InboundEmail is a reply received to an email the service sent
+type InboundEmail struct {
+	ID             uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string             `json:"tenantId" gorm:"type:varchar(255);index"`        // Empty when unmatched
+	NotificationID *uuid.UUID         `json:"notificationId" gorm:"type:uuid;index"`          // Notification the email replies to
+	Provider       string             `json:"provider" gorm:"type:varchar(50);not null"`      // postal, ses
+	MessageID      string             `json:"messageId" gorm:"type:varchar(512);uniqueIndex"` // Message-ID header; providers retry webhooks
+	InReplyTo      string             `json:"inReplyTo" gorm:"type:varchar(512)"`
+	References     string             `json:"references" gorm:"type:text"`
+	FromAddress    string             `json:"fromAddress" gorm:"type:varchar(255);not null"`
+	FromName       string             `json:"fromName" gorm:"type:varchar(255)"`
+	ToAddress      string             `json:"toAddress" gorm:"type:varchar(512)"`
+	Subject        string             `json:"subject" gorm:"type:varchar(998)"`
+	TextBody       string             `json:"textBody" gorm:"type:text"`
+	HTMLBody       string             `json:"htmlBody" gorm:"type:text"`
+	AutoSubmitted  bool               `json:"autoSubmitted" gorm:"default:false"` // Auto-Submitted header or provider auto-reply flag
+	Status         InboundEmailStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	ForwardedTo    string             `json:"forwardedTo" gorm:"type:varchar(255)"`
+	ErrorMessage   string             `json:"errorMessage" gorm:"type:text"`
+	ReceivedAt     time.Time          `json:"receivedAt" gorm:"not null"`
+	CreatedAt      time.Time          `json:"createdAt"`
+	UpdatedAt      time.Time          `json:"updatedAt"`
+}
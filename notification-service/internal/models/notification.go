@@ -30,6 +30,8 @@ const (
 	StatusFailed    NotificationStatus = "FAILED"
 	StatusBounced   NotificationStatus = "BOUNCED"
 	StatusCancelled NotificationStatus = "CANCELLED"
+	// StatusQueuedProviderDown holds a send deferred while its provider is unhealthy; replayed on recovery
+	StatusQueuedProviderDown NotificationStatus = "QUEUED_PROVIDER_DOWN"
 )
 
 // NotificationPriority represents message priority
@@ -96,6 +98,8 @@ type Notification struct {
 	Provider       string               `json:"provider" gorm:"type:varchar(100)"` // sendgrid, twilio, fcm, etc.
 	ProviderID     string               `json:"providerId" gorm:"type:varchar(255)"` // External provider message ID
 	ProviderData   datatypes.JSON       `json:"providerData" gorm:"type:jsonb"`
+	SendingDomain  string               `json:"sendingDomain,omitempty" gorm:"type:varchar(255);index"` // Email sending domain, for reputation tracking
+	CampaignID     *uuid.UUID           `json:"campaignId,omitempty" gorm:"type:uuid;index"` // Campaign the notification was sent for
 
 	// Tracking
 	OpenedAt       *time.Time           `json:"openedAt"`
@@ -124,6 +128,7 @@ type NotificationTemplate struct {
 	// Template configuration
 	Variables      datatypes.JSON       `json:"variables" gorm:"type:jsonb"` // Available variables with descriptions
 	DefaultData    datatypes.JSON       `json:"defaultData" gorm:"type:jsonb"` // Default values for variables
+	MaxSegments    int                  `json:"maxSegments" gorm:"default:0"` // SMS only: longest rendered body in segments, 0 uses the service default
 
 	// Versioning
 	Version        int                  `json:"version" gorm:"default:1"`
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SendingDomainStatus represents the lifecycle state of a sending domain
+type SendingDomainStatus string
+
+const (
+	SendingDomainWarming SendingDomainStatus = "WARMING" // Daily bulk volume follows the warm-up schedule
+	SendingDomainActive  SendingDomainStatus = "ACTIVE"  // Warm-up complete, no volume schedule
+)
+
+// ReputationLevel is the assessed sender reputation of a domain
+type ReputationLevel string
+
+const (
+	ReputationGood     ReputationLevel = "GOOD"     // Bulk sends unrestricted (subject to warm-up)
+	ReputationDegraded ReputationLevel = "DEGRADED" // Bulk sends throttled
+	ReputationPoor     ReputationLevel = "POOR"     // Bulk sends paused
+)
+
+// DeliverabilityEventType is a delivery outcome reported by a provider
+type DeliverabilityEventType string
+
+const (
+	EventDelivered DeliverabilityEventType = "delivered"
+	EventBounce    DeliverabilityEventType = "bounce"
+	EventComplaint DeliverabilityEventType = "complaint"
+	EventOpen      DeliverabilityEventType = "open" // Tracked on the notification only; not a reputation signal
+)
+
+// PlatformTenantID owns the platform's default sending domain
+const PlatformTenantID = "platform"
+
+// SendingDomain is a domain emails are sent from, with its warm-up and reputation state
+type SendingDomain struct {
+	ID          uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string              `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	Domain      string              `json:"domain" gorm:"type:varchar(255);not null;uniqueIndex"`
+	FromAddress string              `json:"fromAddress" gorm:"type:varchar(255)"` // Used as the From address for the tenant's emails
+	FromName    string              `json:"fromName" gorm:"type:varchar(255)"`
+	Status      SendingDomainStatus `json:"status" gorm:"type:varchar(20);not null;default:'WARMING'"`
+
+	// Warm-up progress
+	WarmupDay       int        `json:"warmupDay" gorm:"default:0"` // Index into the warm-up schedule
+	WarmupStartedAt *time.Time `json:"warmupStartedAt"`
+	WarmupAdvanced  *time.Time `json:"warmupAdvancedAt"` // Last time WarmupDay changed
+	DailyLimit      int        `json:"dailyLimit"`       // Current bulk send cap per day; 0 = unlimited
+
+	// Last reputation evaluation
+	Reputation      ReputationLevel `json:"reputation" gorm:"type:varchar(20);not null;default:'GOOD'"`
+	BounceRate      float64         `json:"bounceRate"`
+	ComplaintRate   float64         `json:"complaintRate"`
+	SampleSize      int64           `json:"sampleSize"` // Sends within the evaluation window
+	EvaluatedAt     *time.Time      `json:"evaluatedAt"`
+	ReputationSince *time.Time      `json:"reputationSince"` // When the current reputation level was entered
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SendingDomainDailyStats holds per-day send outcomes for a domain
+type SendingDomainDailyStats struct {
+	Domain     string    `json:"domain" gorm:"type:varchar(255);primaryKey"`
+	Day        time.Time `json:"day" gorm:"type:date;primaryKey"`
+	Sent       int64     `json:"sent" gorm:"default:0"`
+	BulkSent   int64     `json:"bulkSent" gorm:"default:0"`
+	Delivered  int64     `json:"delivered" gorm:"default:0"`
+	Bounced    int64     `json:"bounced" gorm:"default:0"`
+	Complaints int64     `json:"complaints" gorm:"default:0"`
+}
+
+func (SendingDomain) TableName() string {
+	return "sending_domains"
+}
+
+func (SendingDomainDailyStats) TableName() string {
+	return "sending_domain_daily_stats"
+}
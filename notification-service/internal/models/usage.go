@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationCost is the provider cost of one sent notification
+type NotificationCost struct {
+	ID             uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	NotificationID uuid.UUID           `json:"notificationId" gorm:"type:uuid;index"`
+	TenantID       string              `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_notification_costs_tenant_time"`
+	Channel        NotificationChannel `json:"channel" gorm:"type:varchar(20);not null"`
+	Provider       string              `json:"provider" gorm:"type:varchar(100);not null"`
+	Units          int                 `json:"units" gorm:"not null;default:1"` // SMS segments; 1 for email and push
+	UnitCost       float64             `json:"unitCost" gorm:"type:numeric(12,6);not null;default:0"`
+	Cost           float64             `json:"cost" gorm:"type:numeric(12,6);not null;default:0"`
+	Currency       string              `json:"currency" gorm:"type:varchar(3);not null"`
+	CreatedAt      time.Time           `json:"createdAt" gorm:"index:idx_notification_costs_tenant_time"`
+}
+
+// UsageReport records that a tenant's monthly usage event was published
+type UsageReport struct {
+	TenantID    string    `json:"tenantId" gorm:"type:varchar(255);primaryKey"`
+	Period      string    `json:"period" gorm:"type:varchar(7);primaryKey"` // YYYY-MM
+	TotalCost   float64   `json:"totalCost" gorm:"type:numeric(12,6)"`
+	SMSSegments int64     `json:"smsSegments"`
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
+func (NotificationCost) TableName() string {
+	return "notification_costs"
+}
+
+func (UsageReport) TableName() string {
+	return "usage_reports"
+}
@@ -85,3 +85,32 @@ func (c *Client) Close() {
 func (c *Client) IsConnected() bool {
 	return c.conn != nil && c.conn.IsConnected()
 }
+
+// EnsureStream creates a JetStream stream for subject if it does not exist
+func (c *Client) EnsureStream(name, subject, description string) error {
+	if _, err := c.js.StreamInfo(name); err == nil {
+		return nil
+	} else if err != nats.ErrStreamNotFound {
+		return err
+	}
+
+	_, err := c.js.AddStream(&nats.StreamConfig{
+		Name:        name,
+		Description: description,
+		Subjects:    []string{subject},
+		Storage:     nats.FileStorage,
+		Retention:   nats.LimitsPolicy,
+		MaxAge:      30 * 24 * time.Hour,
+		Discard:     nats.DiscardOld,
+	})
+	if err != nil && err != nats.ErrStreamNameAlreadyInUse {
+		return err
+	}
+	return nil
+}
+
+// Publish publishes a message to JetStream
+func (c *Client) Publish(subject string, data []byte) error {
+	_, err := c.js.Publish(subject, data)
+	return err
+}
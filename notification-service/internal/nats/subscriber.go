@@ -18,6 +18,10 @@ import (
 	"notification-service/internal/templates"
 )
 
+// domainCertificateExpiring is published by custom-domain-service when a certificate crosses
+// one of its expiry notification thresholds (30/14/7/1 days by default)
+const domainCertificateExpiring = "domain.certificate.expiring"
+
 // NotificationCategory maps event types to preference categories
 var eventCategoryMap = map[string]string{
 	events.OrderCreated:       "orders",
@@ -73,6 +77,7 @@ var eventCategoryMap = map[string]string{
 	events.DomainRemoved:         "security",
 	events.DomainMigrated:        "security",
 	events.DomainSSLExpiringSoon: "security",
+	domainCertificateExpiring:    "security",
 }
 
 // Subscriber handles NATS event subscriptions for sending external notifications
@@ -91,6 +96,12 @@ type Subscriber struct {
 	supportEmail string
 	// Tenant client for dynamic URL construction
 	tenantClient *services.TenantClient
+	// Optional provider cost tracking
+	costTracker *services.CostTracker
+	// Optional per-notification Reply-To addresses for inbound reply routing
+	replyRouter *services.ReplyRouter
+	// Optional purge of deleted tenants (tenant.deletion.requested)
+	tenantPurger TenantPurger
 }
 
 // NewSubscriber creates a new NATS subscriber
@@ -128,6 +139,16 @@ func NewSubscriber(
 	}
 }
 
+// SetCostTracker enables per-message provider cost tracking
+func (s *Subscriber) SetCostTracker(costTracker *services.CostTracker) {
+	s.costTracker = costTracker
+}
+
+// SetReplyRouter routes replies to event emails back to the service
+func (s *Subscriber) SetReplyRouter(replyRouter *services.ReplyRouter) {
+	s.replyRouter = replyRouter
+}
+
 // ensureStream creates a stream if it doesn't exist
 // This makes notification-service resilient to startup ordering
 func (s *Subscriber) ensureStream(js nats.JetStreamContext, name, subject, description string) error {
@@ -384,6 +405,11 @@ func (s *Subscriber) Start(ctx context.Context) error {
 		log.Println("[NATS] Subscribed to tenant.> events")
 	}
 
+	// Subscribe to tenant deletion requests (purge a deleted tenant's data)
+	if s.tenantPurger != nil {
+		s.subscribeTenantDeletion(js)
+	}
+
 	// Subscribe to approval events
 	approvalSub, err := js.QueueSubscribe(
 		"approval.>",
@@ -451,8 +477,10 @@ func (s *Subscriber) handleOrderEvent(msg *nats.Msg) {
 
 	// Get user preferences if we have a customer ID
 	var prefs *models.NotificationPreference
+	var customerID *uuid.UUID
 	if event.CustomerID != "" {
 		if customerUUID, err := uuid.Parse(event.CustomerID); err == nil {
+			customerID = &customerUUID
 			prefs, _ = s.prefRepo.GetByUserID(ctx, event.TenantID, customerUUID)
 		}
 	}
@@ -525,7 +553,7 @@ func (s *Subscriber) handleOrderEvent(msg *nats.Msg) {
 	// Send email if enabled
 	if s.shouldSendEmail(prefs, category) && event.CustomerEmail != "" {
 		log.Printf("[EMAIL] Sending %s to %s", templateName, event.CustomerEmail)
-		s.sendTemplatedEmail(ctx, event.TenantID, templateName, event.CustomerEmail, variables)
+		s.sendEventEmail(ctx, event.TenantID, templateName, event.CustomerEmail, variables, event.SourceID, customerID)
 	}
 
 	// Send SMS for important events (shipped, delivered) if enabled
@@ -555,8 +583,10 @@ func (s *Subscriber) handlePaymentEvent(msg *nats.Msg) {
 
 	// Get user preferences
 	var prefs *models.NotificationPreference
+	var customerID *uuid.UUID
 	if event.CustomerID != "" {
 		if customerUUID, err := uuid.Parse(event.CustomerID); err == nil {
+			customerID = &customerUUID
 			prefs, _ = s.prefRepo.GetByUserID(ctx, event.TenantID, customerUUID)
 		}
 	}
@@ -598,7 +628,7 @@ func (s *Subscriber) handlePaymentEvent(msg *nats.Msg) {
 	// Send email if enabled
 	if s.shouldSendEmail(prefs, category) && event.CustomerEmail != "" {
 		log.Printf("[EMAIL] Sending %s to %s", templateName, event.CustomerEmail)
-		s.sendTemplatedEmail(ctx, event.TenantID, templateName, event.CustomerEmail, variables)
+		s.sendEventEmail(ctx, event.TenantID, templateName, event.CustomerEmail, variables, event.SourceID, customerID)
 	}
 
 	// For failed payments, also send SMS if enabled
@@ -1369,9 +1399,9 @@ func (s *Subscriber) handleDomainEvent(msg *nats.Msg) {
 		log.Printf("[EMAIL] Sending domain-migrated to %s for %s", event.OwnerEmail, event.Domain)
 		s.sendTemplatedEmail(ctx, event.TenantID, "domain-migrated", event.OwnerEmail, variables)
 
-	case events.DomainSSLExpiringSoon:
+	case events.DomainSSLExpiringSoon, domainCertificateExpiring:
 		// Send warning about SSL certificate expiring soon
-		log.Printf("[EMAIL] Sending domain-ssl-expiring to %s for %s", event.OwnerEmail, event.Domain)
+		log.Printf("[EMAIL] Sending domain-ssl-expiring to %s for %s (expires %s)", event.OwnerEmail, event.Domain, event.SSLExpiresAt)
 		s.sendTemplatedEmail(ctx, event.TenantID, "domain-ssl-expiring", event.OwnerEmail, variables)
 
 	case events.DomainHealthCheckFailed:
@@ -1506,6 +1536,12 @@ func (s *Subscriber) getOrderTemplateName(eventType string) string {
 }
 
 func (s *Subscriber) sendTemplatedEmail(ctx context.Context, tenantID, templateName, recipient string, variables map[string]interface{}) {
+	s.sendEventEmail(ctx, tenantID, templateName, recipient, variables, "", nil)
+}
+
+// sendEventEmail sends a templated email for a platform event. The event's sourceId and
+// recipient user are kept on the notification so an open can mark the in-app copy read.
+func (s *Subscriber) sendEventEmail(ctx context.Context, tenantID, templateName, recipient string, variables map[string]interface{}, sourceEventID string, recipientID *uuid.UUID) {
 	if s.emailProvider == nil {
 		log.Println("[EMAIL] Provider not configured, skipping")
 		return
@@ -1606,6 +1642,8 @@ func (s *Subscriber) sendTemplatedEmail(ctx context.Context, tenantID, templateN
 		TemplateID:     tmplID,
 		TemplateName:   templateName,
 		RecipientEmail: recipient,
+		RecipientID:    recipientID,
+		SourceEventID:  sourceEventID,
 		Subject:        subject,
 		BodyHTML:       body,
 	}
@@ -1627,6 +1665,7 @@ func (s *Subscriber) sendTemplatedEmail(ctx context.Context, tenantID, templateN
 		To:       recipient,
 		Subject:  subject,
 		BodyHTML: body,
+		ReplyTo:  s.replyRouter.Address(notification.ID),
 	}
 
 	result, err := s.emailProvider.Send(ctx, message)
@@ -1639,6 +1678,9 @@ func (s *Subscriber) sendTemplatedEmail(ctx context.Context, tenantID, templateN
 	if result.Success {
 		s.notifRepo.UpdateStatus(ctx, notification.ID, models.StatusSent, result.ProviderID, "")
 		log.Printf("[EMAIL] Successfully sent to %s (provider_id: %s)", recipient, result.ProviderID)
+		if err := s.costTracker.RecordSend(ctx, notification, result.ProviderName, message); err != nil {
+			log.Printf("[EMAIL] Failed to record cost: %v", err)
+		}
 	} else {
 		errorMsg := "Send failed"
 		if result.Error != nil {
@@ -1716,6 +1758,9 @@ func (s *Subscriber) sendTemplatedSMS(ctx context.Context, tenantID, templateNam
 	if result.Success {
 		s.notifRepo.UpdateStatus(ctx, notification.ID, models.StatusSent, result.ProviderID, "")
 		log.Printf("[SMS] Successfully sent to %s (provider_id: %s)", recipient, result.ProviderID)
+		if err := s.costTracker.RecordSend(ctx, notification, result.ProviderName, message); err != nil {
+			log.Printf("[SMS] Failed to record cost: %v", err)
+		}
 	} else {
 		errorMsg := "Send failed"
 		if result.Error != nil {
@@ -0,0 +1,120 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// tenantDeletionService is how tenant-service's deletion saga addresses this service
+	tenantDeletionService = "notification-service"
+
+	tenantDeletionRequested    = "tenant.deletion.requested"
+	tenantDeletionAcknowledged = "tenant.deletion.acknowledged"
+)
+
+// tenantDeletionRequest is the part of tenant-service's tenant.deletion.requested event needed
+// to purge a tenant
+type tenantDeletionRequest struct {
+	TenantID string   `json:"tenant_id"`
+	Services []string `json:"services"` // Services asked to purge; others ignore the request
+}
+
+// tenantDeletionAck reports the outcome of a purge back to tenant-service
+type tenantDeletionAck struct {
+	EventType   string    `json:"event_type"`
+	TenantID    string    `json:"tenant_id"`
+	Service     string    `json:"service"`
+	Status      string    `json:"status"` // "purged" or "failed"
+	ItemsPurged int64     `json:"items_purged,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// TenantPurger deletes everything a tenant owns and returns how many rows were removed. It must
+// be idempotent: tenant-service re-sends the request until it is acknowledged.
+type TenantPurger func(ctx context.Context, tenantID string) (int64, error)
+
+// SetTenantPurger enables purging deleted tenants on tenant-service's request
+func (s *Subscriber) SetTenantPurger(purger TenantPurger) {
+	s.tenantPurger = purger
+}
+
+// subscribeTenantDeletion consumes deletion requests with its own durable consumer, so they are
+// not lost to the best-effort tenant.> email consumer
+func (s *Subscriber) subscribeTenantDeletion(js nats.JetStreamContext) {
+	sub, err := js.QueueSubscribe(
+		tenantDeletionRequested,
+		"notification-service-tenant-deletion-workers",
+		s.handleTenantDeletionRequested,
+		nats.BindStream("TENANT_EVENTS"),
+		nats.Durable("notification-service-tenant-deletion"),
+		nats.ManualAck(),
+		nats.AckWait(2*time.Minute),
+		nats.MaxDeliver(5),
+	)
+	if err != nil {
+		log.Printf("[NATS] Warning: failed to subscribe to tenant deletion requests: %v", err)
+		return
+	}
+	s.subs = append(s.subs, sub)
+	log.Printf("[NATS] Subscribed to %s events", tenantDeletionRequested)
+}
+
+// handleTenantDeletionRequested purges the tenant and acknowledges the outcome to tenant-service.
+// A failed purge is reported rather than redelivered; tenant-service re-requests it later.
+func (s *Subscriber) handleTenantDeletionRequested(msg *nats.Msg) {
+	var request tenantDeletionRequest
+	if err := json.Unmarshal(msg.Data, &request); err != nil {
+		log.Printf("[NATS] Failed to unmarshal tenant deletion request: %v", err)
+		msg.Ack()
+		return
+	}
+	if request.TenantID == "" || !containsService(request.Services, tenantDeletionService) {
+		msg.Ack()
+		return
+	}
+
+	ack := tenantDeletionAck{
+		EventType: tenantDeletionAcknowledged,
+		TenantID:  request.TenantID,
+		Service:   tenantDeletionService,
+		Status:    "purged",
+	}
+	purged, err := s.tenantPurger(context.Background(), request.TenantID)
+	if err != nil {
+		log.Printf("[NATS] Failed to purge tenant %s: %v", request.TenantID, err)
+		ack.Status = "failed"
+		ack.Error = err.Error()
+	} else {
+		ack.ItemsPurged = purged
+		log.Printf("[NATS] Purged %d notification rows of deleted tenant %s", purged, request.TenantID)
+	}
+	ack.Timestamp = time.Now().UTC()
+
+	data, err := json.Marshal(ack)
+	if err == nil {
+		err = s.client.Publish(tenantDeletionAcknowledged, data)
+	}
+	if err != nil {
+		// Redelivered; purging again is harmless
+		log.Printf("[NATS] Failed to acknowledge deletion of tenant %s: %v", request.TenantID, err)
+		msg.Nak()
+		return
+	}
+	msg.Ack()
+}
+
+// containsService reports whether service is one of the services a deletion request names
+func containsService(services []string, service string) bool {
+	for _, s := range services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"notification-service/internal/models"
+)
+
+// CampaignRepository handles campaign and campaign recipient persistence
+type CampaignRepository interface {
+	Create(ctx context.Context, campaign *models.Campaign) error
+	Get(ctx context.Context, tenantID string, id uuid.UUID) (*models.Campaign, error)
+	List(ctx context.Context, tenantID string, filters CampaignFilters) ([]models.Campaign, int64, error)
+	Update(ctx context.Context, campaign *models.Campaign) error
+	Delete(ctx context.Context, tenantID string, id uuid.UUID) error
+	// Transition moves a campaign to another status if it is currently in one of from
+	Transition(ctx context.Context, tenantID string, id uuid.UUID, from []models.CampaignStatus, updates map[string]interface{}) (bool, error)
+
+	// Dispatcher operations
+	StartDue(ctx context.Context, now time.Time) (int64, error)
+	ListSending(ctx context.Context) ([]models.Campaign, error)
+	ClaimBatch(ctx context.Context, id uuid.UUID, now, until time.Time) (bool, error)
+	IncrementCounts(ctx context.Context, id uuid.UUID, sent, failed, skipped int) error
+	CompleteIfDone(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// Recipients
+	AddRecipients(ctx context.Context, recipients []models.CampaignRecipient) (int64, error)
+	CountRecipients(ctx context.Context, campaignID uuid.UUID) (int64, error)
+	ListRecipients(ctx context.Context, campaignID uuid.UUID, status string, limit, offset int) ([]models.CampaignRecipient, int64, error)
+	ClaimRecipients(ctx context.Context, campaignID uuid.UUID, limit int) ([]models.CampaignRecipient, error)
+	UpdateRecipient(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error
+	FailStaleRecipients(ctx context.Context, campaignID uuid.UUID, before time.Time) (int64, error)
+
+	// Stats
+	RecipientStatusCounts(ctx context.Context, campaignID uuid.UUID) (map[models.CampaignRecipientStatus]int64, error)
+	DeliveryCounts(ctx context.Context, campaignID uuid.UUID) (*CampaignDeliveryCounts, error)
+}
+
+// CampaignFilters for listing campaigns
+type CampaignFilters struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+// CampaignDeliveryCounts are provider-reported outcomes of a campaign's notifications
+type CampaignDeliveryCounts struct {
+	Delivered int64
+	Bounced   int64
+	Opened    int64
+}
+
+type campaignRepository struct {
+	db *gorm.DB
+}
+
+// NewCampaignRepository creates a new campaign repository
+func NewCampaignRepository(db *gorm.DB) CampaignRepository {
+	return &campaignRepository{db: db}
+}
+
+func (r *campaignRepository) Create(ctx context.Context, campaign *models.Campaign) error {
+	return r.db.WithContext(ctx).Create(campaign).Error
+}
+
+func (r *campaignRepository) Get(ctx context.Context, tenantID string, id uuid.UUID) (*models.Campaign, error) {
+	var campaign models.Campaign
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).First(&campaign).Error; err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+func (r *campaignRepository) List(ctx context.Context, tenantID string, filters CampaignFilters) ([]models.Campaign, int64, error) {
+	var campaigns []models.Campaign
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Campaign{}).Where("tenant_id = ?", tenantID)
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").
+		Limit(filters.Limit).
+		Offset(filters.Offset).
+		Find(&campaigns).Error
+	return campaigns, total, err
+}
+
+func (r *campaignRepository) Update(ctx context.Context, campaign *models.Campaign) error {
+	return r.db.WithContext(ctx).Save(campaign).Error
+}
+
+func (r *campaignRepository) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&models.Campaign{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("campaign_id = ?", id).Delete(&models.CampaignRecipient{}).Error
+	})
+}
+
+func (r *campaignRepository) Transition(ctx context.Context, tenantID string, id uuid.UUID, from []models.CampaignStatus, updates map[string]interface{}) (bool, error) {
+	updates["updated_at"] = time.Now()
+	result := r.db.WithContext(ctx).Model(&models.Campaign{}).
+		Where("tenant_id = ? AND id = ? AND status IN ?", tenantID, id, from).
+		Updates(updates)
+	return result.RowsAffected == 1, result.Error
+}
+
+// StartDue moves scheduled campaigns whose time has come to SENDING
+func (r *campaignRepository) StartDue(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Campaign{}).
+		Where("status = ? AND (scheduled_for IS NULL OR scheduled_for <= ?)", models.CampaignScheduled, now).
+		Updates(map[string]interface{}{
+			"status":     models.CampaignSending,
+			"started_at": now,
+			"updated_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *campaignRepository) ListSending(ctx context.Context) ([]models.Campaign, error) {
+	var campaigns []models.Campaign
+	err := r.db.WithContext(ctx).Where("status = ?", models.CampaignSending).
+		Order("started_at ASC").
+		Find(&campaigns).Error
+	return campaigns, err
+}
+
+// ClaimBatch takes the campaign's batch lease until the given time, so only one replica
+// sends each batch and the throttle holds regardless of the number of replicas
+func (r *campaignRepository) ClaimBatch(ctx context.Context, id uuid.UUID, now, until time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Campaign{}).
+		Where("id = ? AND status = ? AND (next_batch_at IS NULL OR next_batch_at <= ?)", id, models.CampaignSending, now).
+		Update("next_batch_at", until)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *campaignRepository) IncrementCounts(ctx context.Context, id uuid.UUID, sent, failed, skipped int) error {
+	return r.db.WithContext(ctx).Model(&models.Campaign{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sent_count":    gorm.Expr("sent_count + ?", sent),
+			"failed_count":  gorm.Expr("failed_count + ?", failed),
+			"skipped_count": gorm.Expr("skipped_count + ?", skipped),
+			"updated_at":    time.Now(),
+		}).Error
+}
+
+// CompleteIfDone marks a sending campaign COMPLETED once no recipient is pending or in flight
+func (r *campaignRepository) CompleteIfDone(ctx context.Context, id uuid.UUID) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&models.Campaign{}).
+		Where("id = ? AND status = ?", id, models.CampaignSending).
+		Where("NOT EXISTS (SELECT 1 FROM notification_campaign_recipients WHERE campaign_id = ? AND status IN ?)",
+			id, []models.CampaignRecipientStatus{models.RecipientPending, models.RecipientSending}).
+		Updates(map[string]interface{}{
+			"status":       models.CampaignCompleted,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// AddRecipients inserts recipients, ignoring addresses already in the campaign
+func (r *campaignRepository) AddRecipients(ctx context.Context, recipients []models.CampaignRecipient) (int64, error) {
+	if len(recipients) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(recipients, 500)
+	return result.RowsAffected, result.Error
+}
+
+func (r *campaignRepository) CountRecipients(ctx context.Context, campaignID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.CampaignRecipient{}).
+		Where("campaign_id = ?", campaignID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *campaignRepository) ListRecipients(ctx context.Context, campaignID uuid.UUID, status string, limit, offset int) ([]models.CampaignRecipient, int64, error) {
+	var recipients []models.CampaignRecipient
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.CampaignRecipient{}).Where("campaign_id = ?", campaignID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at ASC, email ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&recipients).Error
+	return recipients, total, err
+}
+
+// ClaimRecipients marks up to limit pending recipients SENDING and returns them;
+// locked rows are skipped so concurrent claims never return the same recipient
+func (r *campaignRepository) ClaimRecipients(ctx context.Context, campaignID uuid.UUID, limit int) ([]models.CampaignRecipient, error) {
+	var recipients []models.CampaignRecipient
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE notification_campaign_recipients SET status = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM notification_campaign_recipients
+			WHERE campaign_id = ? AND status = ?
+			ORDER BY created_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.RecipientSending, time.Now(), campaignID, models.RecipientPending, limit,
+	).Scan(&recipients).Error
+	return recipients, err
+}
+
+func (r *campaignRepository) UpdateRecipient(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	return r.db.WithContext(ctx).Model(&models.CampaignRecipient{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// FailStaleRecipients fails recipients left in flight by a replica that stopped mid-batch.
+// They are not retried because the send may already have reached the provider.
+func (r *campaignRepository) FailStaleRecipients(ctx context.Context, campaignID uuid.UUID, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.CampaignRecipient{}).
+		Where("campaign_id = ? AND status = ? AND updated_at < ?", campaignID, models.RecipientSending, before).
+		Updates(map[string]interface{}{
+			"status":        models.RecipientFailed,
+			"error_message": "send interrupted",
+			"updated_at":    time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *campaignRepository) RecipientStatusCounts(ctx context.Context, campaignID uuid.UUID) (map[models.CampaignRecipientStatus]int64, error) {
+	var rows []struct {
+		Status models.CampaignRecipientStatus
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.CampaignRecipient{}).
+		Select("status, COUNT(*) AS count").
+		Where("campaign_id = ?", campaignID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.CampaignRecipientStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *campaignRepository) DeliveryCounts(ctx context.Context, campaignID uuid.UUID) (*CampaignDeliveryCounts, error) {
+	var counts CampaignDeliveryCounts
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Select(`COUNT(*) FILTER (WHERE status = ? OR opened_at IS NOT NULL) AS delivered,
+			COUNT(*) FILTER (WHERE status = ?) AS bounced,
+			COUNT(*) FILTER (WHERE opened_at IS NOT NULL) AS opened`,
+			models.StatusDelivered, models.StatusBounced).
+		Where("campaign_id = ?", campaignID).
+		Scan(&counts).Error
+	return &counts, err
+}
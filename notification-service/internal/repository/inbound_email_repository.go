@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"notification-service/internal/models"
+)
+
+// InboundEmailRepository handles received email persistence
+type InboundEmailRepository interface {
+	Create(ctx context.Context, email *models.InboundEmail) error
+	GetByMessageID(ctx context.Context, messageID string) (*models.InboundEmail, error)
+	Update(ctx context.Context, email *models.InboundEmail) error
+}
+
+type inboundEmailRepository struct {
+	db *gorm.DB
+}
+
+// NewInboundEmailRepository creates a new inbound email repository
+func NewInboundEmailRepository(db *gorm.DB) InboundEmailRepository {
+	return &inboundEmailRepository{db: db}
+}
+
+func (r *inboundEmailRepository) Create(ctx context.Context, email *models.InboundEmail) error {
+	return r.db.WithContext(ctx).Create(email).Error
+}
+
+func (r *inboundEmailRepository) GetByMessageID(ctx context.Context, messageID string) (*models.InboundEmail, error) {
+	var email models.InboundEmail
+	if err := r.db.WithContext(ctx).Where("message_id = ?", messageID).First(&email).Error; err != nil {
+		return nil, err
+	}
+	return &email, nil
+}
+
+func (r *inboundEmailRepository) Update(ctx context.Context, email *models.InboundEmail) error {
+	return r.db.WithContext(ctx).Save(email).Error
+}
@@ -19,7 +19,10 @@ type NotificationRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetPending(ctx context.Context, limit int) ([]models.Notification, error)
 	GetScheduledReady(ctx context.Context, limit int) ([]models.Notification, error)
+	ClaimProviderDeferred(ctx context.Context, limit int) ([]models.Notification, error)
 	GetByRecipient(ctx context.Context, tenantID string, recipientID uuid.UUID, channel models.NotificationChannel) ([]models.Notification, error)
+	GetByProviderID(ctx context.Context, providerID string) (*models.Notification, error)
+	MarkOpened(ctx context.Context, id uuid.UUID, openedAt time.Time) (bool, error)
 }
 
 // NotificationFilters for listing notifications
@@ -130,6 +133,8 @@ func (r *notificationRepository) UpdateStatus(ctx context.Context, id uuid.UUID,
 		now := time.Now()
 		updates["failed_at"] = &now
 		updates["error_message"] = errorMsg
+	case models.StatusQueuedProviderDown:
+		updates["error_message"] = errorMsg
 	}
 
 	return r.db.WithContext(ctx).Model(&models.Notification{}).
@@ -137,6 +142,28 @@ func (r *notificationRepository) UpdateStatus(ctx context.Context, id uuid.UUID,
 		Updates(updates).Error
 }
 
+// GetByProviderID finds a notification by the provider's message ID (used by delivery webhooks)
+func (r *notificationRepository) GetByProviderID(ctx context.Context, providerID string) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.WithContext(ctx).Where("provider_id = ?", providerID).First(&notification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkOpened records the first open of an email (reported by provider open tracking).
+// Returns false when the email was already marked opened.
+func (r *notificationRepository) MarkOpened(ctx context.Context, id uuid.UUID, openedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND opened_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"opened_at":  &openedAt,
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
 func (r *notificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&models.Notification{}, id).Error
 }
@@ -161,6 +188,25 @@ func (r *notificationRepository) GetScheduledReady(ctx context.Context, limit in
 	return notifications, err
 }
 
+// ClaimProviderDeferred marks up to limit sends deferred while their provider was down SENDING
+// and returns them, oldest first; locked rows are skipped so replicas never replay the same send
+func (r *notificationRepository) ClaimProviderDeferred(ctx context.Context, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE notifications SET status = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE status = ? AND deleted_at IS NULL
+			ORDER BY created_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.StatusSending, time.Now(), models.StatusQueuedProviderDown, limit,
+	).Scan(&notifications).Error
+	return notifications, err
+}
+
 func (r *notificationRepository) GetByRecipient(ctx context.Context, tenantID string, recipientID uuid.UUID, channel models.NotificationChannel) ([]models.Notification, error) {
 	var notifications []models.Notification
 	query := r.db.WithContext(ctx).Where("tenant_id = ? AND recipient_id = ?", tenantID, recipientID)
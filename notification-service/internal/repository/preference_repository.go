@@ -13,6 +13,7 @@ type PreferenceRepository interface {
 	GetByUserID(ctx context.Context, tenantID string, userID uuid.UUID) (*models.NotificationPreference, error)
 	Upsert(ctx context.Context, pref *models.NotificationPreference) error
 	UpdatePushTokens(ctx context.Context, tenantID string, userID uuid.UUID, tokens []string) error
+	GetMarketingOptOuts(ctx context.Context, tenantID string, emails []string) (map[string]bool, error)
 }
 
 type preferenceRepository struct {
@@ -58,3 +59,25 @@ func (r *preferenceRepository) UpdatePushTokens(ctx context.Context, tenantID st
 		Where("tenant_id = ? AND user_id = ?", tenantID, userID).
 		Update("push_tokens", tokens).Error
 }
+
+// GetMarketingOptOuts returns which of the given emails have disabled email or marketing notifications
+func (r *preferenceRepository) GetMarketingOptOuts(ctx context.Context, tenantID string, emails []string) (map[string]bool, error) {
+	optOuts := make(map[string]bool)
+	if len(emails) == 0 {
+		return optOuts, nil
+	}
+
+	var found []string
+	err := r.db.WithContext(ctx).
+		Model(&models.NotificationPreference{}).
+		Where("tenant_id = ? AND LOWER(email) IN ?", tenantID, emails).
+		Where("email_enabled = ? OR marketing_enabled = ?", false, false).
+		Pluck("LOWER(email)", &found).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, email := range found {
+		optOuts[email] = true
+	}
+	return optOuts, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"notification-service/internal/models"
+)
+
+// SenderReputationRepository handles sending domain and deliverability stats persistence
+type SenderReputationRepository interface {
+	CreateDomain(ctx context.Context, domain *models.SendingDomain) error
+	GetDomain(ctx context.Context, domain string) (*models.SendingDomain, error)
+	GetDomainByID(ctx context.Context, tenantID string, id uuid.UUID) (*models.SendingDomain, error)
+	ListDomains(ctx context.Context, tenantID string) ([]models.SendingDomain, error)
+	ListAllDomains(ctx context.Context) ([]models.SendingDomain, error)
+	UpdateDomain(ctx context.Context, domain *models.SendingDomain) error
+	DeleteDomain(ctx context.Context, tenantID string, id uuid.UUID) error
+
+	IncrementStats(ctx context.Context, domain string, day time.Time, deltas models.SendingDomainDailyStats) error
+	GetStatsSince(ctx context.Context, domain string, since time.Time) ([]models.SendingDomainDailyStats, error)
+}
+
+type senderReputationRepository struct {
+	db *gorm.DB
+}
+
+// NewSenderReputationRepository creates a new sender reputation repository
+func NewSenderReputationRepository(db *gorm.DB) SenderReputationRepository {
+	return &senderReputationRepository{db: db}
+}
+
+func (r *senderReputationRepository) CreateDomain(ctx context.Context, domain *models.SendingDomain) error {
+	return r.db.WithContext(ctx).Create(domain).Error
+}
+
+func (r *senderReputationRepository) GetDomain(ctx context.Context, domain string) (*models.SendingDomain, error) {
+	var d models.SendingDomain
+	if err := r.db.WithContext(ctx).Where("domain = ?", domain).First(&d).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *senderReputationRepository) GetDomainByID(ctx context.Context, tenantID string, id uuid.UUID) (*models.SendingDomain, error) {
+	var d models.SendingDomain
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).First(&d).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *senderReputationRepository) ListDomains(ctx context.Context, tenantID string) ([]models.SendingDomain, error) {
+	var domains []models.SendingDomain
+	err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("created_at ASC").Find(&domains).Error
+	return domains, err
+}
+
+func (r *senderReputationRepository) ListAllDomains(ctx context.Context) ([]models.SendingDomain, error) {
+	var domains []models.SendingDomain
+	err := r.db.WithContext(ctx).Order("created_at ASC").Find(&domains).Error
+	return domains, err
+}
+
+func (r *senderReputationRepository) UpdateDomain(ctx context.Context, domain *models.SendingDomain) error {
+	return r.db.WithContext(ctx).Save(domain).Error
+}
+
+func (r *senderReputationRepository) DeleteDomain(ctx context.Context, tenantID string, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&models.SendingDomain{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// IncrementStats atomically adds deltas to the domain's counters for the given day
+func (r *senderReputationRepository) IncrementStats(ctx context.Context, domain string, day time.Time, deltas models.SendingDomainDailyStats) error {
+	row := models.SendingDomainDailyStats{
+		Domain:     domain,
+		Day:        day,
+		Sent:       deltas.Sent,
+		BulkSent:   deltas.BulkSent,
+		Delivered:  deltas.Delivered,
+		Bounced:    deltas.Bounced,
+		Complaints: deltas.Complaints,
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "domain"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"sent":       gorm.Expr("sending_domain_daily_stats.sent + ?", deltas.Sent),
+			"bulk_sent":  gorm.Expr("sending_domain_daily_stats.bulk_sent + ?", deltas.BulkSent),
+			"delivered":  gorm.Expr("sending_domain_daily_stats.delivered + ?", deltas.Delivered),
+			"bounced":    gorm.Expr("sending_domain_daily_stats.bounced + ?", deltas.Bounced),
+			"complaints": gorm.Expr("sending_domain_daily_stats.complaints + ?", deltas.Complaints),
+		}),
+	}).Create(&row).Error
+}
+
+func (r *senderReputationRepository) GetStatsSince(ctx context.Context, domain string, since time.Time) ([]models.SendingDomainDailyStats, error) {
+	var stats []models.SendingDomainDailyStats
+	err := r.db.WithContext(ctx).
+		Where("domain = ? AND day >= ?", domain, since).
+		Order("day ASC").
+		Find(&stats).Error
+	return stats, err
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"notification-service/internal/models"
+)
+
+// TenantDataRepository removes everything a tenant owns
+type TenantDataRepository interface {
+	// Purge permanently deletes the tenant's notifications (and their logs), templates,
+	// preferences, batches, campaigns, inbound emails, sending domains and usage records in one
+	// transaction and returns the number of rows removed
+	Purge(ctx context.Context, tenantID string) (int64, error)
+}
+
+type tenantDataRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantDataRepository creates a new tenant data repository
+func NewTenantDataRepository(db *gorm.DB) TenantDataRepository {
+	return &tenantDataRepository{db: db}
+}
+
+func (r *tenantDataRepository) Purge(ctx context.Context, tenantID string) (int64, error) {
+	if tenantID == "" || tenantID == "system" || tenantID == models.PlatformTenantID {
+		return 0, fmt.Errorf("refusing to purge tenant %q", tenantID)
+	}
+
+	var purged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Rows keyed by a parent go before the parent
+		dependents := []struct {
+			model  interface{}
+			column string
+			parent *gorm.DB
+		}{
+			{&models.NotificationLog{}, "notification_id", tx.Unscoped().Model(&models.Notification{}).Select("id").Where("tenant_id = ?", tenantID)},
+			{&models.SendingDomainDailyStats{}, "domain", tx.Model(&models.SendingDomain{}).Select("domain").Where("tenant_id = ?", tenantID)},
+		}
+		for _, d := range dependents {
+			result := tx.Where(d.column+" IN (?)", d.parent).Delete(d.model)
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+
+		tenantScoped := []interface{}{
+			&models.Notification{},
+			&models.NotificationTemplate{},
+			&models.NotificationPreference{},
+			&models.NotificationBatch{},
+			&models.CampaignRecipient{},
+			&models.Campaign{},
+			&models.InboundEmail{},
+			&models.SendingDomain{},
+			&models.NotificationCost{},
+			&models.UsageReport{},
+		}
+		for _, model := range tenantScoped {
+			result := tx.Unscoped().Where("tenant_id = ?", tenantID).Delete(model)
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge tenant data: %w", err)
+	}
+	return purged, nil
+}
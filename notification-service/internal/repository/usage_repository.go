@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"notification-service/internal/models"
+)
+
+// CostAggregate is the summed cost of a tenant's notifications for one channel and provider
+type CostAggregate struct {
+	Channel  models.NotificationChannel `json:"channel"`
+	Provider string                     `json:"provider"`
+	Messages int64                      `json:"messages"`
+	Units    int64                      `json:"units"`
+	Cost     float64                    `json:"cost"`
+}
+
+// UsageRepository handles notification cost and usage report persistence
+type UsageRepository interface {
+	CreateCost(ctx context.Context, cost *models.NotificationCost) error
+	AggregateCosts(ctx context.Context, tenantID string, from, to time.Time) ([]CostAggregate, error)
+	TenantsWithUsage(ctx context.Context, from, to time.Time) ([]string, error)
+	ClaimReport(ctx context.Context, report *models.UsageReport) (bool, error)
+	ReleaseReport(ctx context.Context, tenantID, period string) error
+}
+
+type usageRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(db *gorm.DB) UsageRepository {
+	return &usageRepository{db: db}
+}
+
+func (r *usageRepository) CreateCost(ctx context.Context, cost *models.NotificationCost) error {
+	return r.db.WithContext(ctx).Create(cost).Error
+}
+
+// AggregateCosts sums a tenant's costs in [from, to) by channel and provider
+func (r *usageRepository) AggregateCosts(ctx context.Context, tenantID string, from, to time.Time) ([]CostAggregate, error) {
+	var rows []CostAggregate
+	err := r.db.WithContext(ctx).
+		Model(&models.NotificationCost{}).
+		Select("channel, provider, COUNT(*) AS messages, COALESCE(SUM(units), 0) AS units, COALESCE(SUM(cost), 0) AS cost").
+		Where("tenant_id = ? AND created_at >= ? AND created_at < ?", tenantID, from, to).
+		Group("channel, provider").
+		Order("channel, provider").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// TenantsWithUsage lists tenants that sent notifications in [from, to)
+func (r *usageRepository) TenantsWithUsage(ctx context.Context, from, to time.Time) ([]string, error) {
+	var tenants []string
+	err := r.db.WithContext(ctx).
+		Model(&models.NotificationCost{}).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Distinct().
+		Pluck("tenant_id", &tenants).Error
+	return tenants, err
+}
+
+// ClaimReport inserts the report if it does not exist yet; false means another
+// replica already reported this tenant and period
+func (r *usageRepository) ClaimReport(ctx context.Context, report *models.UsageReport) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseReport removes a claim so the report is retried
+func (r *usageRepository) ReleaseReport(ctx context.Context, tenantID, period string) error {
+	return r.db.WithContext(ctx).
+		Where("tenant_id = ? AND period = ?", tenantID, period).
+		Delete(&models.UsageReport{}).Error
+}
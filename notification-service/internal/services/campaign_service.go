@@ -0,0 +1,917 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"notification-service/internal/models"
+	"notification-service/internal/repository"
+	"notification-service/internal/template"
+)
+
+// Errors returned by campaign management
+var (
+	ErrCampaignNotFound    = errors.New("campaign not found")
+	ErrInvalidCampaign     = errors.New("invalid campaign")
+	ErrCampaignState       = errors.New("campaign cannot be changed in its current status")
+	ErrSegmentsUnavailable = errors.New("segment audiences require Mautic to be configured")
+)
+
+const (
+	// segmentPageSize is the number of contacts fetched per Mautic request when resolving a segment
+	segmentPageSize = 200
+	// audienceResolveLease bounds how long one replica may spend resolving a segment before another may retry
+	audienceResolveLease = 5 * time.Minute
+	// staleRecipientAfter is how long a claimed recipient may stay in flight before it is failed
+	staleRecipientAfter = 10 * time.Minute
+)
+
+// CampaignSender delivers a rendered campaign notification through the provider chain
+type CampaignSender func(ctx context.Context, notification *models.Notification) error
+
+// SegmentSource lists the contacts of a marketing segment
+type SegmentSource interface {
+	ListSegmentContacts(ctx context.Context, segmentID, start, limit int) ([]SegmentContact, int, error)
+}
+
+// CampaignConfig configures campaign dispatch
+type CampaignConfig struct {
+	// DispatchInterval is how often sending campaigns are given a batch
+	DispatchInterval time.Duration
+	// DefaultThrottlePerMinute applies to campaigns created without a throttle rate
+	DefaultThrottlePerMinute int
+	// MaxThrottlePerMinute caps the throttle rate a merchant may choose
+	MaxThrottlePerMinute int
+	// SendConcurrency is the number of sends in flight per batch
+	SendConcurrency int
+}
+
+// CreateCampaignRequest creates a draft campaign
+type CreateCampaignRequest struct {
+	Name              string                      `json:"name" binding:"required,max=255"`
+	Description       string                      `json:"description"`
+	TemplateID        uuid.UUID                   `json:"templateId" binding:"required"`
+	AudienceType      models.CampaignAudienceType `json:"audienceType" binding:"required,oneof=SEGMENT LIST"`
+	SegmentID         int                         `json:"segmentId"`
+	Variables         map[string]interface{}      `json:"variables"`
+	ThrottlePerMinute int                         `json:"throttlePerMinute" binding:"min=0"`
+}
+
+// UpdateCampaignRequest updates a draft campaign; omitted fields are unchanged
+type UpdateCampaignRequest struct {
+	Name              *string                `json:"name" binding:"omitempty,max=255"`
+	Description       *string                `json:"description"`
+	TemplateID        *uuid.UUID             `json:"templateId"`
+	SegmentID         *int                   `json:"segmentId"`
+	Variables         map[string]interface{} `json:"variables"`
+	ThrottlePerMinute *int                   `json:"throttlePerMinute" binding:"omitempty,min=0"`
+}
+
+// CampaignRecipientInput is an uploaded recipient
+type CampaignRecipientInput struct {
+	Email     string                 `json:"email" binding:"required,email"`
+	FirstName string                 `json:"firstName"`
+	LastName  string                 `json:"lastName"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// AddRecipientsRequest uploads part of a LIST campaign's audience
+type AddRecipientsRequest struct {
+	Recipients []CampaignRecipientInput `json:"recipients" binding:"required,min=1,max=5000,dive"`
+}
+
+// CampaignStats is the progress and delivery outcome of a campaign
+type CampaignStats struct {
+	CampaignID   uuid.UUID             `json:"campaignId"`
+	Status       models.CampaignStatus `json:"status"`
+	Total        int64                 `json:"total"`
+	Pending      int64                 `json:"pending"` // Not yet sent, including in-flight
+	Sent         int64                 `json:"sent"`
+	Failed       int64                 `json:"failed"`
+	Skipped      int64                 `json:"skipped"` // Opted out of marketing email
+	Delivered    int64                 `json:"delivered"`
+	Bounced      int64                 `json:"bounced"`
+	Opened       int64                 `json:"opened"`
+	DeliveryRate float64               `json:"deliveryRate"` // Delivered / sent
+	OpenRate     float64               `json:"openRate"`     // Opened / delivered
+}
+
+// CampaignService manages campaigns and sends them in throttled batches.
+//
+// Campaign email goes out in the LOW priority lane: batches are sent by a background
+// dispatcher rather than on the request path, are counted as bulk against the sending
+// domain's reputation and warm-up limits, and do not consume the tenant's transactional
+// email rate limit.
+type CampaignService struct {
+	repo         repository.CampaignRepository
+	templateRepo repository.TemplateRepository
+	notifRepo    repository.NotificationRepository
+	prefRepo     repository.PreferenceRepository
+	sender       CampaignSender
+	segments     SegmentSource
+	reputation   *ReputationMonitor
+	available    func() bool
+	templateEng  *template.Engine
+	config       CampaignConfig
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewCampaignService creates a new campaign service
+func NewCampaignService(
+	repo repository.CampaignRepository,
+	templateRepo repository.TemplateRepository,
+	notifRepo repository.NotificationRepository,
+	prefRepo repository.PreferenceRepository,
+	sender CampaignSender,
+	config *CampaignConfig,
+) *CampaignService {
+	if config == nil {
+		config = &CampaignConfig{}
+	}
+	cfg := *config
+	if cfg.DispatchInterval <= 0 {
+		cfg.DispatchInterval = 10 * time.Second
+	}
+	if cfg.MaxThrottlePerMinute <= 0 {
+		cfg.MaxThrottlePerMinute = 3000
+	}
+	if cfg.DefaultThrottlePerMinute <= 0 || cfg.DefaultThrottlePerMinute > cfg.MaxThrottlePerMinute {
+		cfg.DefaultThrottlePerMinute = min(300, cfg.MaxThrottlePerMinute)
+	}
+	if cfg.SendConcurrency <= 0 {
+		cfg.SendConcurrency = 5
+	}
+
+	return &CampaignService{
+		repo:         repo,
+		templateRepo: templateRepo,
+		notifRepo:    notifRepo,
+		prefRepo:     prefRepo,
+		sender:       sender,
+		templateEng:  template.NewEngine(),
+		config:       cfg,
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// SetSegmentSource enables SEGMENT audiences
+func (s *CampaignService) SetSegmentSource(segments SegmentSource) {
+	s.segments = segments
+}
+
+// SetReputationMonitor gates batches on the sending domain's bulk allowance
+func (s *CampaignService) SetReputationMonitor(reputation *ReputationMonitor) {
+	s.reputation = reputation
+}
+
+// SetAvailability pauses batches while available reports the marketing provider is down
+func (s *CampaignService) SetAvailability(available func() bool) {
+	s.available = available
+}
+
+// Create creates a draft campaign
+func (s *CampaignService) Create(ctx context.Context, tenantID, createdBy string, req *CreateCampaignRequest) (*models.Campaign, error) {
+	tmpl, err := s.loadTemplate(ctx, tenantID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if req.AudienceType == models.AudienceSegment {
+		if err := s.validateSegment(req.SegmentID); err != nil {
+			return nil, err
+		}
+	}
+	throttle, err := s.throttle(req.ThrottlePerMinute)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := &models.Campaign{
+		TenantID:          tenantID,
+		Name:              strings.TrimSpace(req.Name),
+		Description:       req.Description,
+		TemplateID:        tmpl.ID,
+		Channel:           tmpl.Channel,
+		AudienceType:      req.AudienceType,
+		Status:            models.CampaignDraft,
+		ThrottlePerMinute: throttle,
+		CreatedBy:         createdBy,
+	}
+	if req.AudienceType == models.AudienceSegment {
+		campaign.SegmentID = req.SegmentID
+	}
+	if req.Variables != nil {
+		if campaign.Variables, err = json.Marshal(req.Variables); err != nil {
+			return nil, fmt.Errorf("%w: invalid variables", ErrInvalidCampaign)
+		}
+	}
+
+	if err := s.repo.Create(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// List returns the tenant's campaigns
+func (s *CampaignService) List(ctx context.Context, tenantID string, filters repository.CampaignFilters) ([]models.Campaign, int64, error) {
+	return s.repo.List(ctx, tenantID, filters)
+}
+
+// Get returns a campaign
+func (s *CampaignService) Get(ctx context.Context, tenantID string, id uuid.UUID) (*models.Campaign, error) {
+	campaign, err := s.repo.Get(ctx, tenantID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCampaignNotFound
+	}
+	return campaign, err
+}
+
+// Update changes a draft campaign
+func (s *CampaignService) Update(ctx context.Context, tenantID string, id uuid.UUID, req *UpdateCampaignRequest) (*models.Campaign, error) {
+	campaign, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != models.CampaignDraft {
+		return nil, ErrCampaignState
+	}
+
+	if req.Name != nil {
+		campaign.Name = strings.TrimSpace(*req.Name)
+		if campaign.Name == "" {
+			return nil, fmt.Errorf("%w: name is required", ErrInvalidCampaign)
+		}
+	}
+	if req.Description != nil {
+		campaign.Description = *req.Description
+	}
+	if req.TemplateID != nil {
+		tmpl, err := s.loadTemplate(ctx, tenantID, *req.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		campaign.TemplateID = tmpl.ID
+		campaign.Channel = tmpl.Channel
+	}
+	if req.SegmentID != nil {
+		if campaign.AudienceType != models.AudienceSegment {
+			return nil, fmt.Errorf("%w: segmentId only applies to SEGMENT audiences", ErrInvalidCampaign)
+		}
+		if err := s.validateSegment(*req.SegmentID); err != nil {
+			return nil, err
+		}
+		campaign.SegmentID = *req.SegmentID
+	}
+	if req.Variables != nil {
+		if campaign.Variables, err = json.Marshal(req.Variables); err != nil {
+			return nil, fmt.Errorf("%w: invalid variables", ErrInvalidCampaign)
+		}
+	}
+	if req.ThrottlePerMinute != nil {
+		if campaign.ThrottlePerMinute, err = s.throttle(*req.ThrottlePerMinute); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Update(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// Delete removes a campaign that is not scheduled or sending
+func (s *CampaignService) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	campaign, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	switch campaign.Status {
+	case models.CampaignScheduled, models.CampaignSending, models.CampaignPaused:
+		return ErrCampaignState
+	}
+	if err := s.repo.Delete(ctx, tenantID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCampaignNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AddRecipients adds uploaded recipients to a LIST campaign before it starts sending.
+// Addresses already in the campaign are ignored.
+func (s *CampaignService) AddRecipients(ctx context.Context, tenantID string, id uuid.UUID, req *AddRecipientsRequest) (int64, *models.Campaign, error) {
+	campaign, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return 0, nil, err
+	}
+	if campaign.AudienceType != models.AudienceList {
+		return 0, nil, fmt.Errorf("%w: recipients can only be uploaded to LIST audiences", ErrInvalidCampaign)
+	}
+	if campaign.Status != models.CampaignDraft && campaign.Status != models.CampaignScheduled {
+		return 0, nil, ErrCampaignState
+	}
+
+	recipients := make([]models.CampaignRecipient, 0, len(req.Recipients))
+	seen := make(map[string]bool, len(req.Recipients))
+	for _, input := range req.Recipients {
+		email := strings.ToLower(strings.TrimSpace(input.Email))
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		recipient := models.CampaignRecipient{
+			CampaignID: campaign.ID,
+			TenantID:   tenantID,
+			Email:      email,
+			FirstName:  input.FirstName,
+			LastName:   input.LastName,
+			Status:     models.RecipientPending,
+		}
+		if input.Variables != nil {
+			if recipient.Variables, err = json.Marshal(input.Variables); err != nil {
+				return 0, nil, fmt.Errorf("%w: invalid variables for %s", ErrInvalidCampaign, email)
+			}
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	added, err := s.repo.AddRecipients(ctx, recipients)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.refreshTotal(ctx, campaign); err != nil {
+		return added, nil, err
+	}
+	return added, campaign, nil
+}
+
+// ListRecipients returns a page of a campaign's recipients
+func (s *CampaignService) ListRecipients(ctx context.Context, tenantID string, id uuid.UUID, status string, limit, offset int) ([]models.CampaignRecipient, int64, error) {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListRecipients(ctx, id, status, limit, offset)
+}
+
+// Schedule queues a draft campaign to start at scheduledFor, or immediately if nil
+func (s *CampaignService) Schedule(ctx context.Context, tenantID string, id uuid.UUID, scheduledFor *time.Time) (*models.Campaign, error) {
+	campaign, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != models.CampaignDraft {
+		return nil, ErrCampaignState
+	}
+
+	switch campaign.AudienceType {
+	case models.AudienceList:
+		if campaign.TotalRecipients == 0 {
+			return nil, fmt.Errorf("%w: upload recipients before scheduling", ErrInvalidCampaign)
+		}
+	case models.AudienceSegment:
+		if err := s.validateSegment(campaign.SegmentID); err != nil {
+			return nil, err
+		}
+	}
+	// The template may have been deactivated or deleted since the campaign was drafted
+	if _, err := s.loadTemplate(ctx, tenantID, campaign.TemplateID); err != nil {
+		return nil, err
+	}
+
+	when := time.Now()
+	if scheduledFor != nil && scheduledFor.After(when) {
+		when = *scheduledFor
+	}
+	return s.transition(ctx, campaign, []models.CampaignStatus{models.CampaignDraft}, map[string]interface{}{
+		"status":        models.CampaignScheduled,
+		"scheduled_for": when,
+	})
+}
+
+// Pause suspends a scheduled or sending campaign; in-flight sends complete
+func (s *CampaignService) Pause(ctx context.Context, tenantID string, id uuid.UUID) (*models.Campaign, error) {
+	campaign, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, campaign, []models.CampaignStatus{models.CampaignScheduled, models.CampaignSending}, map[string]interface{}{
+		"status": models.CampaignPaused,
+	})
+}
+
+// Resume continues a paused campaign, waiting for its schedule if it had not started yet
+func (s *CampaignService) Resume(ctx context.Context, tenantID string, id uuid.UUID) (*models.Campaign, error) {
+	campaign, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	status := models.CampaignSending
+	if campaign.StartedAt == nil {
+		status = models.CampaignScheduled
+	}
+	return s.transition(ctx, campaign, []models.CampaignStatus{models.CampaignPaused}, map[string]interface{}{
+		"status": status,
+	})
+}
+
+// Cancel stops a campaign; recipients not yet sent are left pending
+func (s *CampaignService) Cancel(ctx context.Context, tenantID string, id uuid.UUID) (*models.Campaign, error) {
+	campaign, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, campaign, []models.CampaignStatus{
+		models.CampaignDraft, models.CampaignScheduled, models.CampaignSending, models.CampaignPaused,
+	}, map[string]interface{}{
+		"status":       models.CampaignCancelled,
+		"completed_at": time.Now(),
+	})
+}
+
+// Stats returns a campaign's send progress and provider-reported delivery and open counts
+func (s *CampaignService) Stats(ctx context.Context, tenantID string, id uuid.UUID) (*CampaignStats, error) {
+	campaign, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.RecipientStatusCounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.DeliveryCounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CampaignStats{
+		CampaignID: id,
+		Status:     campaign.Status,
+		Pending:    counts[models.RecipientPending] + counts[models.RecipientSending],
+		Sent:       counts[models.RecipientSent],
+		Failed:     counts[models.RecipientFailed],
+		Skipped:    counts[models.RecipientSkipped],
+		Delivered:  delivery.Delivered,
+		Bounced:    delivery.Bounced,
+		Opened:     delivery.Opened,
+	}
+	stats.Total = stats.Pending + stats.Sent + stats.Failed + stats.Skipped
+	if stats.Sent > 0 {
+		stats.DeliveryRate = float64(stats.Delivered) / float64(stats.Sent)
+	}
+	if stats.Delivered > 0 {
+		stats.OpenRate = float64(stats.Opened) / float64(stats.Delivered)
+	}
+	return stats, nil
+}
+
+// Start runs the dispatcher on the configured interval
+func (s *CampaignService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.config.DispatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.Dispatch(ctx)
+			}
+		}
+	}()
+
+	log.Printf("[CAMPAIGN] Dispatcher started (interval=%v, default throttle=%d/min)",
+		s.config.DispatchInterval, s.config.DefaultThrottlePerMinute)
+}
+
+// Stop stops the dispatcher
+func (s *CampaignService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// Dispatch starts due campaigns and sends one batch of each sending campaign
+func (s *CampaignService) Dispatch(ctx context.Context) {
+	now := time.Now()
+	if started, err := s.repo.StartDue(ctx, now); err != nil {
+		log.Printf("[CAMPAIGN] Failed to start scheduled campaigns: %v", err)
+	} else if started > 0 {
+		log.Printf("[CAMPAIGN] Started %d scheduled campaign(s)", started)
+	}
+
+	campaigns, err := s.repo.ListSending(ctx)
+	if err != nil {
+		log.Printf("[CAMPAIGN] Failed to list sending campaigns: %v", err)
+		return
+	}
+
+	for i := range campaigns {
+		campaign := &campaigns[i]
+
+		lease := now.Add(s.config.DispatchInterval)
+		if !campaign.AudienceResolved {
+			lease = now.Add(audienceResolveLease)
+		}
+		claimed, err := s.repo.ClaimBatch(ctx, campaign.ID, now, lease)
+		if err != nil {
+			log.Printf("[CAMPAIGN] Failed to claim batch for campaign %s: %v", campaign.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if !campaign.AudienceResolved {
+			s.resolveAudience(ctx, campaign)
+			continue
+		}
+		s.sendBatch(ctx, campaign)
+	}
+}
+
+// resolveAudience loads a segment's contacts into the campaign's recipients.
+// Inserts ignore existing addresses, so an interrupted resolution can safely be repeated.
+func (s *CampaignService) resolveAudience(ctx context.Context, campaign *models.Campaign) {
+	if campaign.AudienceType == models.AudienceSegment {
+		if s.segments == nil {
+			s.fail(ctx, campaign, ErrSegmentsUnavailable.Error())
+			return
+		}
+
+		for start := 0; ; start += segmentPageSize {
+			contacts, total, err := s.segments.ListSegmentContacts(ctx, campaign.SegmentID, start, segmentPageSize)
+			if errors.Is(err, ErrSegmentNotFound) {
+				s.fail(ctx, campaign, fmt.Sprintf("segment %d not found", campaign.SegmentID))
+				return
+			}
+			if err != nil {
+				// Retried after the resolve lease expires
+				log.Printf("[CAMPAIGN] Failed to load segment %d for campaign %s: %v", campaign.SegmentID, campaign.ID, err)
+				return
+			}
+
+			recipients := make([]models.CampaignRecipient, 0, len(contacts))
+			for _, contact := range contacts {
+				recipients = append(recipients, models.CampaignRecipient{
+					CampaignID: campaign.ID,
+					TenantID:   campaign.TenantID,
+					Email:      strings.ToLower(strings.TrimSpace(contact.Email)),
+					FirstName:  contact.FirstName,
+					LastName:   contact.LastName,
+					Status:     models.RecipientPending,
+				})
+			}
+			if _, err := s.repo.AddRecipients(ctx, recipients); err != nil {
+				log.Printf("[CAMPAIGN] Failed to store segment recipients for campaign %s: %v", campaign.ID, err)
+				return
+			}
+
+			if len(contacts) == 0 || start+segmentPageSize >= total {
+				break
+			}
+		}
+	}
+
+	total, err := s.repo.CountRecipients(ctx, campaign.ID)
+	if err != nil {
+		log.Printf("[CAMPAIGN] Failed to count recipients for campaign %s: %v", campaign.ID, err)
+		return
+	}
+	if _, err := s.repo.Transition(ctx, campaign.TenantID, campaign.ID, []models.CampaignStatus{models.CampaignSending}, map[string]interface{}{
+		"audience_resolved": true,
+		"total_recipients":  total,
+		"next_batch_at":     nil,
+	}); err != nil {
+		log.Printf("[CAMPAIGN] Failed to mark audience resolved for campaign %s: %v", campaign.ID, err)
+		return
+	}
+	log.Printf("[CAMPAIGN] Campaign %s audience resolved: %d recipient(s)", campaign.ID, total)
+}
+
+// sendBatch sends the campaign's share of the throttle for one dispatch interval
+func (s *CampaignService) sendBatch(ctx context.Context, campaign *models.Campaign) {
+	if failed, err := s.repo.FailStaleRecipients(ctx, campaign.ID, time.Now().Add(-staleRecipientAfter)); err != nil {
+		log.Printf("[CAMPAIGN] Failed to fail stale recipients of campaign %s: %v", campaign.ID, err)
+	} else if failed > 0 {
+		s.incrementCounts(ctx, campaign.ID, 0, int(failed), 0)
+	}
+
+	// Sends made now would only be deferred until the provider recovers
+	if s.available != nil && !s.available() {
+		s.recordError(ctx, campaign, "sending paused: marketing email provider is unavailable")
+		return
+	}
+
+	size := s.batchSize(campaign)
+
+	tmpl, err := s.loadTemplate(ctx, campaign.TenantID, campaign.TemplateID)
+	if err != nil {
+		s.fail(ctx, campaign, fmt.Sprintf("template unavailable: %v", err))
+		return
+	}
+
+	// Bulk sends share the sending domain's warm-up and reputation allowance
+	if s.reputation != nil {
+		domain, _, _ := s.reputation.Sender(campaign.TenantID)
+		decision, err := s.reputation.CheckBulkSend(ctx, domain)
+		if err != nil {
+			log.Printf("[CAMPAIGN] Sender reputation check error: %v", err)
+			// Continue even if the check fails (fail-open for availability)
+		} else if !decision.Allowed {
+			s.recordError(ctx, campaign, fmt.Sprintf("sending throttled: %s on %s", decision.Reason, decision.Domain))
+			return
+		} else if decision.DailyLimit > 0 {
+			size = min(size, max(decision.DailyLimit-int(decision.SentToday), 1))
+		}
+	}
+
+	recipients, err := s.repo.ClaimRecipients(ctx, campaign.ID, size)
+	if err != nil {
+		log.Printf("[CAMPAIGN] Failed to claim recipients for campaign %s: %v", campaign.ID, err)
+		return
+	}
+	if len(recipients) == 0 {
+		if done, err := s.repo.CompleteIfDone(ctx, campaign.ID); err != nil {
+			log.Printf("[CAMPAIGN] Failed to complete campaign %s: %v", campaign.ID, err)
+		} else if done {
+			log.Printf("[CAMPAIGN] Campaign %s completed", campaign.ID)
+		}
+		return
+	}
+
+	emails := make([]string, len(recipients))
+	for i, recipient := range recipients {
+		emails[i] = recipient.Email
+	}
+	optOuts, err := s.prefRepo.GetMarketingOptOuts(ctx, campaign.TenantID, emails)
+	if err != nil {
+		// Never send marketing email without checking opt-outs; release the batch for the next interval
+		log.Printf("[CAMPAIGN] Failed to load opt-outs for campaign %s: %v", campaign.ID, err)
+		for _, recipient := range recipients {
+			s.updateRecipient(ctx, recipient.ID, map[string]interface{}{"status": models.RecipientPending})
+		}
+		return
+	}
+
+	var (
+		mu                    sync.Mutex
+		sent, failed, skipped int
+		wg                    sync.WaitGroup
+	)
+	sem := make(chan struct{}, s.config.SendConcurrency)
+	for i := range recipients {
+		recipient := &recipients[i]
+		if optOuts[recipient.Email] {
+			s.updateRecipient(ctx, recipient.ID, map[string]interface{}{"status": models.RecipientSkipped})
+			skipped++
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ok := s.sendToRecipient(ctx, campaign, tmpl, recipient)
+			mu.Lock()
+			if ok {
+				sent++
+			} else {
+				failed++
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	s.incrementCounts(ctx, campaign.ID, sent, failed, skipped)
+}
+
+// sendToRecipient renders and sends the campaign to one recipient and records the outcome
+func (s *CampaignService) sendToRecipient(ctx context.Context, campaign *models.Campaign, tmpl *models.NotificationTemplate, recipient *models.CampaignRecipient) bool {
+	variables := make(map[string]interface{})
+	if len(campaign.Variables) > 0 {
+		_ = json.Unmarshal(campaign.Variables, &variables)
+	}
+	if len(recipient.Variables) > 0 {
+		_ = json.Unmarshal(recipient.Variables, &variables)
+	}
+	variables["email"] = recipient.Email
+	variables["firstName"] = recipient.FirstName
+	variables["lastName"] = recipient.LastName
+
+	notification, err := s.render(campaign, tmpl, recipient, variables)
+	if err != nil {
+		s.updateRecipient(ctx, recipient.ID, map[string]interface{}{
+			"status":        models.RecipientFailed,
+			"error_message": err.Error(),
+		})
+		return false
+	}
+
+	if err := s.notifRepo.Create(ctx, notification); err != nil {
+		log.Printf("[CAMPAIGN] Failed to create notification for campaign %s: %v", campaign.ID, err)
+		s.updateRecipient(ctx, recipient.ID, map[string]interface{}{
+			"status":        models.RecipientFailed,
+			"error_message": "failed to create notification",
+		})
+		return false
+	}
+
+	if err := s.sender(ctx, notification); err != nil {
+		s.updateRecipient(ctx, recipient.ID, map[string]interface{}{
+			"status":          models.RecipientFailed,
+			"notification_id": notification.ID,
+			"error_message":   err.Error(),
+		})
+		return false
+	}
+
+	s.updateRecipient(ctx, recipient.ID, map[string]interface{}{
+		"status":          models.RecipientSent,
+		"notification_id": notification.ID,
+		"sent_at":         time.Now(),
+	})
+	return true
+}
+
+// render builds the campaign notification for a recipient
+func (s *CampaignService) render(campaign *models.Campaign, tmpl *models.NotificationTemplate, recipient *models.CampaignRecipient, variables map[string]interface{}) (*models.Notification, error) {
+	notification := &models.Notification{
+		TenantID:       campaign.TenantID,
+		UserID:         uuid.MustParse("00000000-0000-0000-0000-000000000001"), // System user
+		Type:           "campaign." + strings.ToLower(string(campaign.Channel)),
+		SourceService:  "notification-service",
+		EntityType:     "campaign",
+		EntityID:       &campaign.ID,
+		Channel:        campaign.Channel,
+		Status:         models.StatusPending,
+		Priority:       models.PriorityLow,
+		TemplateID:     &tmpl.ID,
+		TemplateName:   tmpl.Name,
+		RecipientEmail: recipient.Email,
+		CampaignID:     &campaign.ID,
+	}
+
+	var err error
+	if tmpl.Subject != "" {
+		if notification.Subject, err = s.templateEng.RenderText(tmpl.Subject, variables); err != nil {
+			return nil, fmt.Errorf("failed to render subject: %w", err)
+		}
+	}
+	if tmpl.BodyTemplate != "" {
+		if notification.Body, err = s.templateEng.RenderText(tmpl.BodyTemplate, variables); err != nil {
+			return nil, fmt.Errorf("failed to render body: %w", err)
+		}
+	}
+	if tmpl.HTMLTemplate != "" {
+		if notification.BodyHTML, err = s.templateEng.RenderHTML(tmpl.HTMLTemplate, variables); err != nil {
+			return nil, fmt.Errorf("failed to render HTML body: %w", err)
+		}
+	}
+	notification.Title = notification.Subject
+	if notification.Title == "" {
+		notification.Title = campaign.Name
+	}
+	notification.Message = notification.Body
+
+	notification.Variables, _ = json.Marshal(variables)
+	notification.Metadata, _ = json.Marshal(map[string]interface{}{
+		"category":   "marketing",
+		"bulk":       true,
+		"campaignId": campaign.ID.String(),
+		"firstName":  recipient.FirstName,
+		"lastName":   recipient.LastName,
+	})
+	return notification, nil
+}
+
+// batchSize is the number of recipients a campaign may send per dispatch interval
+func (s *CampaignService) batchSize(campaign *models.Campaign) int {
+	throttle := campaign.ThrottlePerMinute
+	if throttle <= 0 {
+		throttle = s.config.DefaultThrottlePerMinute
+	}
+	size := int(float64(throttle) * s.config.DispatchInterval.Minutes())
+	return max(size, 1)
+}
+
+// throttle validates a requested throttle rate; 0 selects the default
+func (s *CampaignService) throttle(perMinute int) (int, error) {
+	if perMinute == 0 {
+		return s.config.DefaultThrottlePerMinute, nil
+	}
+	if perMinute < 0 || perMinute > s.config.MaxThrottlePerMinute {
+		return 0, fmt.Errorf("%w: throttlePerMinute must be between 1 and %d", ErrInvalidCampaign, s.config.MaxThrottlePerMinute)
+	}
+	return perMinute, nil
+}
+
+// loadTemplate returns an active email template owned by the tenant or the platform
+func (s *CampaignService) loadTemplate(ctx context.Context, tenantID string, templateID uuid.UUID) (*models.NotificationTemplate, error) {
+	tmpl, err := s.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl == nil || (tmpl.TenantID != tenantID && !tmpl.IsSystem) {
+		return nil, fmt.Errorf("%w: template not found", ErrInvalidCampaign)
+	}
+	if !tmpl.IsActive {
+		return nil, fmt.Errorf("%w: template is not active", ErrInvalidCampaign)
+	}
+	if tmpl.Channel != models.ChannelEmail {
+		return nil, fmt.Errorf("%w: campaigns require an EMAIL template", ErrInvalidCampaign)
+	}
+	return tmpl, nil
+}
+
+// validateSegment checks that segment audiences can be resolved
+func (s *CampaignService) validateSegment(segmentID int) error {
+	if s.segments == nil {
+		return ErrSegmentsUnavailable
+	}
+	if segmentID <= 0 {
+		return fmt.Errorf("%w: segmentId is required for SEGMENT audiences", ErrInvalidCampaign)
+	}
+	return nil
+}
+
+// transition applies a status change and returns the updated campaign
+func (s *CampaignService) transition(ctx context.Context, campaign *models.Campaign, from []models.CampaignStatus, updates map[string]interface{}) (*models.Campaign, error) {
+	ok, err := s.repo.Transition(ctx, campaign.TenantID, campaign.ID, from, updates)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCampaignState
+	}
+	return s.Get(ctx, campaign.TenantID, campaign.ID)
+}
+
+// refreshTotal recounts the campaign's recipients
+func (s *CampaignService) refreshTotal(ctx context.Context, campaign *models.Campaign) error {
+	total, err := s.repo.CountRecipients(ctx, campaign.ID)
+	if err != nil {
+		return err
+	}
+	campaign.TotalRecipients = int(total)
+	_, err = s.repo.Transition(ctx, campaign.TenantID, campaign.ID, []models.CampaignStatus{campaign.Status}, map[string]interface{}{
+		"total_recipients": total,
+	})
+	return err
+}
+
+// fail stops a campaign that cannot be sent
+func (s *CampaignService) fail(ctx context.Context, campaign *models.Campaign, reason string) {
+	log.Printf("[CAMPAIGN] Campaign %s failed: %s", campaign.ID, reason)
+	if _, err := s.repo.Transition(ctx, campaign.TenantID, campaign.ID, []models.CampaignStatus{models.CampaignSending}, map[string]interface{}{
+		"status":       models.CampaignFailed,
+		"last_error":   reason,
+		"completed_at": time.Now(),
+	}); err != nil {
+		log.Printf("[CAMPAIGN] Failed to mark campaign %s failed: %v", campaign.ID, err)
+	}
+}
+
+// recordError records a transient problem without stopping the campaign
+func (s *CampaignService) recordError(ctx context.Context, campaign *models.Campaign, reason string) {
+	if campaign.LastError == reason {
+		return
+	}
+	if _, err := s.repo.Transition(ctx, campaign.TenantID, campaign.ID, []models.CampaignStatus{models.CampaignSending}, map[string]interface{}{
+		"last_error": reason,
+	}); err != nil {
+		log.Printf("[CAMPAIGN] Failed to record error for campaign %s: %v", campaign.ID, err)
+	}
+}
+
+func (s *CampaignService) updateRecipient(ctx context.Context, id uuid.UUID, updates map[string]interface{}) {
+	if err := s.repo.UpdateRecipient(ctx, id, updates); err != nil {
+		log.Printf("[CAMPAIGN] Failed to update recipient %s: %v", id, err)
+	}
+}
+
+func (s *CampaignService) incrementCounts(ctx context.Context, id uuid.UUID, sent, failed, skipped int) {
+	if sent == 0 && failed == 0 && skipped == 0 {
+		return
+	}
+	if err := s.repo.IncrementCounts(ctx, id, sent, failed, skipped); err != nil {
+		log.Printf("[CAMPAIGN] Failed to update counts for campaign %s: %v", id, err)
+	}
+}
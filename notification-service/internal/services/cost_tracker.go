@@ -0,0 +1,421 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"notification-service/internal/models"
+	"notification-service/internal/repository"
+)
+
+// ErrInvalidUsagePeriod is returned for malformed or future usage periods
+var ErrInvalidUsagePeriod = errors.New("invalid period")
+
+// UsageEventSubject is the NATS subject monthly usage events are published on
+const UsageEventSubject = "usage.notifications.monthly"
+
+// SendEventSubject is the NATS subject a usage event is published on for every sent message
+const SendEventSubject = "usage.notifications.sent"
+
+// DefaultRateCard is the per-unit provider cost in USD: per SMS segment for SMS
+// providers, per message for email and push. Self-hosted providers cost nothing per message.
+var DefaultRateCard = map[string]float64{
+	"ses":      0.0001,
+	"sendgrid": 0.0006,
+	"twilio":   0.0079,
+	"sns":      0.00645,
+	"postal":   0,
+	"smtp":     0,
+	"mautic":   0,
+	"fcm":      0,
+}
+
+// ParseRateCard merges a JSON object of provider -> unit cost over DefaultRateCard
+func ParseRateCard(raw string) (map[string]float64, error) {
+	rates := make(map[string]float64, len(DefaultRateCard))
+	for provider, rate := range DefaultRateCard {
+		rates[provider] = rate
+	}
+	if strings.TrimSpace(raw) == "" {
+		return rates, nil
+	}
+
+	var overrides map[string]float64
+	if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+		return nil, fmt.Errorf("invalid rate card: %w", err)
+	}
+	for provider, rate := range overrides {
+		if rate < 0 {
+			return nil, fmt.Errorf("invalid rate card: negative rate for %s", provider)
+		}
+		rates[normalizeProviderName(provider)] = rate
+	}
+	return rates, nil
+}
+
+// normalizeProviderName maps provider display names ("AWS SES", "Postal-HTTP") to rate card keys
+func normalizeProviderName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimPrefix(name, "aws ")
+	if i := strings.IndexAny(name, "- "); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// CostTrackerConfig configures provider cost tracking and monthly usage reporting
+type CostTrackerConfig struct {
+	RateCard map[string]float64
+	Currency string
+	// SMSIncludedSegments is the SMS segments per month included in a tenant's plan; usage beyond is overage
+	SMSIncludedSegments int64
+	// SMSProvider is the rate card key SMS cost estimates are priced at (the primary SMS provider)
+	SMSProvider string
+	// ReportInterval is how often the reporter checks for unreported months
+	ReportInterval time.Duration
+}
+
+// UsagePublisher publishes usage events (implemented by the NATS client)
+type UsagePublisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// ChannelCost is a tenant's usage and cost for one channel
+type ChannelCost struct {
+	Channel  models.NotificationChannel `json:"channel"`
+	Messages int64                      `json:"messages"`
+	Units    int64                      `json:"units"`
+	Cost     float64                    `json:"cost"`
+}
+
+// SMSUsage is a tenant's SMS usage against its included allowance
+type SMSUsage struct {
+	Messages         int64   `json:"messages"`
+	Segments         int64   `json:"segments"`
+	IncludedSegments int64   `json:"includedSegments"`
+	OverageSegments  int64   `json:"overageSegments"`
+	OverageCost      float64 `json:"overageCost"` // Provider cost of the overage segments
+}
+
+// CostSummary is a tenant's notification costs for a period
+type CostSummary struct {
+	TenantID      string                     `json:"tenantId"`
+	Period        string                     `json:"period"` // YYYY-MM
+	From          time.Time                  `json:"from"`
+	To            time.Time                  `json:"to"`
+	Currency      string                     `json:"currency"`
+	TotalMessages int64                      `json:"totalMessages"`
+	TotalCost     float64                    `json:"totalCost"`
+	ByChannel     []ChannelCost              `json:"byChannel"`
+	ByProvider    []repository.CostAggregate `json:"byProvider"`
+	SMS           SMSUsage                   `json:"sms"`
+}
+
+// UsageEvent is published once per tenant for each completed month
+type UsageEvent struct {
+	EventType string    `json:"eventType"`
+	Timestamp time.Time `json:"timestamp"`
+	*CostSummary
+}
+
+// SendEvent reports a single sent message, letting tenant-service meter usage against plan quotas
+type SendEvent struct {
+	EventType      string                     `json:"eventType"`
+	Timestamp      time.Time                  `json:"timestamp"`
+	TenantID       string                     `json:"tenantId"`
+	NotificationID string                     `json:"notificationId"`
+	Channel        models.NotificationChannel `json:"channel"`
+	Units          int                        `json:"units"` // SMS segments; 1 for email and push
+}
+
+// CostTracker records per-message provider costs and reports monthly usage for billing
+type CostTracker struct {
+	repo      repository.UsageRepository
+	publisher UsagePublisher
+	config    CostTrackerConfig
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewCostTracker creates a new cost tracker
+func NewCostTracker(repo repository.UsageRepository, config *CostTrackerConfig) *CostTracker {
+	if config == nil {
+		config = &CostTrackerConfig{}
+	}
+	cfg := *config
+	if cfg.RateCard == nil {
+		cfg.RateCard = DefaultRateCard
+	}
+	if cfg.Currency == "" {
+		cfg.Currency = "USD"
+	}
+	if cfg.ReportInterval <= 0 {
+		cfg.ReportInterval = time.Hour
+	}
+	cfg.SMSProvider = normalizeProviderName(cfg.SMSProvider)
+	return &CostTracker{
+		repo:   repo,
+		config: cfg,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// SetPublisher enables per-message and monthly usage events
+func (t *CostTracker) SetPublisher(publisher UsagePublisher) {
+	t.publisher = publisher
+}
+
+// RecordSend records the cost of a successfully sent notification
+func (t *CostTracker) RecordSend(ctx context.Context, notification *models.Notification, providerName string, message *Message) error {
+	if t == nil {
+		return nil
+	}
+
+	units := 1
+	if notification.Channel == models.ChannelSMS {
+		units = SMSSegments(message.Body)
+	}
+
+	provider := normalizeProviderName(providerName)
+	rate, ok := t.config.RateCard[provider]
+	if !ok {
+		log.Printf("[COST] No rate configured for provider %q, recording zero cost", providerName)
+	}
+
+	if err := t.repo.CreateCost(ctx, &models.NotificationCost{
+		NotificationID: notification.ID,
+		TenantID:       notification.TenantID,
+		Channel:        notification.Channel,
+		Provider:       provider,
+		Units:          units,
+		UnitCost:       rate,
+		Cost:           rate * float64(units),
+		Currency:       t.config.Currency,
+	}); err != nil {
+		return err
+	}
+
+	t.publishSend(notification, units)
+	return nil
+}
+
+// EstimateSMS prices an SMS body at the primary SMS provider's segment rate and warns
+// when it needs UCS-2 or more than maxSegments segments
+func (t *CostTracker) EstimateSMS(body string, maxSegments int) *SMSEstimate {
+	estimate := &SMSEstimate{
+		SMSAnalysis: AnalyzeSMS(body),
+		MaxSegments: maxSegments,
+	}
+	if t != nil {
+		estimate.Provider = t.config.SMSProvider
+		estimate.UnitCost = t.config.RateCard[t.config.SMSProvider]
+		estimate.Currency = t.config.Currency
+	}
+	estimate.EstimatedCost = estimate.UnitCost * float64(estimate.Segments)
+
+	if estimate.Encoding == SMSEncodingUCS2 {
+		estimate.Warnings = append(estimate.Warnings, fmt.Sprintf(
+			"characters %s are not in the GSM-7 alphabet; the message is sent as UCS-2 with %d characters per segment",
+			strings.Join(estimate.NonGSMCharacters, " "), estimate.PerSegment))
+	}
+	if estimate.ExceedsMaxSegments() {
+		estimate.Warnings = append(estimate.Warnings, fmt.Sprintf(
+			"message is %d segments, more than the limit of %d", estimate.Segments, maxSegments))
+	}
+	return estimate
+}
+
+// publishSend publishes a SendEvent; failures are logged since the message was already sent
+func (t *CostTracker) publishSend(notification *models.Notification, units int) {
+	if t.publisher == nil {
+		return
+	}
+
+	data, err := json.Marshal(&SendEvent{
+		EventType:      SendEventSubject,
+		Timestamp:      time.Now().UTC(),
+		TenantID:       notification.TenantID,
+		NotificationID: notification.ID.String(),
+		Channel:        notification.Channel,
+		Units:          units,
+	})
+	if err == nil {
+		err = t.publisher.Publish(SendEventSubject, data)
+	}
+	if err != nil {
+		log.Printf("[COST] Failed to publish send usage for notification %s: %v", notification.ID, err)
+	}
+}
+
+// GetCosts aggregates a tenant's costs for a period ("YYYY-MM", "current" or "previous")
+func (t *CostTracker) GetCosts(ctx context.Context, tenantID, period string) (*CostSummary, error) {
+	from, to, label, err := ParseUsagePeriod(period, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	aggregates, err := t.repo.AggregateCosts(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &CostSummary{
+		TenantID:   tenantID,
+		Period:     label,
+		From:       from,
+		To:         to,
+		Currency:   t.config.Currency,
+		ByChannel:  []ChannelCost{},
+		ByProvider: aggregates,
+	}
+	if summary.ByProvider == nil {
+		summary.ByProvider = []repository.CostAggregate{}
+	}
+
+	var smsCost float64
+	channelIndex := make(map[models.NotificationChannel]int)
+	for _, a := range aggregates {
+		summary.TotalMessages += a.Messages
+		summary.TotalCost += a.Cost
+
+		i, ok := channelIndex[a.Channel]
+		if !ok {
+			summary.ByChannel = append(summary.ByChannel, ChannelCost{Channel: a.Channel})
+			i = len(summary.ByChannel) - 1
+			channelIndex[a.Channel] = i
+		}
+		cc := &summary.ByChannel[i]
+		cc.Messages += a.Messages
+		cc.Units += a.Units
+		cc.Cost += a.Cost
+
+		if a.Channel == models.ChannelSMS {
+			summary.SMS.Messages += a.Messages
+			summary.SMS.Segments += a.Units
+			smsCost += a.Cost
+		}
+	}
+
+	summary.SMS.IncludedSegments = t.config.SMSIncludedSegments
+	if overage := summary.SMS.Segments - t.config.SMSIncludedSegments; overage > 0 {
+		summary.SMS.OverageSegments = overage
+		summary.SMS.OverageCost = smsCost / float64(summary.SMS.Segments) * float64(overage)
+	}
+	return summary, nil
+}
+
+// Start periodically publishes usage events for the previous month
+func (t *CostTracker) Start(ctx context.Context) {
+	go func() {
+		t.reportPreviousMonth(ctx)
+
+		ticker := time.NewTicker(t.config.ReportInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.stopCh:
+				return
+			case <-ticker.C:
+				t.reportPreviousMonth(ctx)
+			}
+		}
+	}()
+
+	log.Printf("[COST] Usage reporter started (interval=%v)", t.config.ReportInterval)
+}
+
+// Stop stops the usage reporter
+func (t *CostTracker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stopCh)
+	})
+}
+
+// reportPreviousMonth publishes one usage event per tenant for the last completed month.
+// Reports are claimed in the database first so each is published once across replicas.
+func (t *CostTracker) reportPreviousMonth(ctx context.Context) {
+	if t.publisher == nil {
+		return
+	}
+
+	from, to, period, _ := ParseUsagePeriod("previous", time.Now())
+	tenants, err := t.repo.TenantsWithUsage(ctx, from, to)
+	if err != nil {
+		log.Printf("[COST] Failed to list tenants with usage for %s: %v", period, err)
+		return
+	}
+
+	for _, tenantID := range tenants {
+		summary, err := t.GetCosts(ctx, tenantID, period)
+		if err != nil {
+			log.Printf("[COST] Failed to aggregate usage for tenant %s (%s): %v", tenantID, period, err)
+			continue
+		}
+
+		claimed, err := t.repo.ClaimReport(ctx, &models.UsageReport{
+			TenantID:    tenantID,
+			Period:      period,
+			TotalCost:   summary.TotalCost,
+			SMSSegments: summary.SMS.Segments,
+			PublishedAt: time.Now(),
+		})
+		if err != nil {
+			log.Printf("[COST] Failed to claim usage report for tenant %s (%s): %v", tenantID, period, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		data, err := json.Marshal(&UsageEvent{
+			EventType:   UsageEventSubject,
+			Timestamp:   time.Now().UTC(),
+			CostSummary: summary,
+		})
+		if err == nil {
+			err = t.publisher.Publish(UsageEventSubject, data)
+		}
+		if err != nil {
+			log.Printf("[COST] Failed to publish usage for tenant %s (%s): %v", tenantID, period, err)
+			if err := t.repo.ReleaseReport(ctx, tenantID, period); err != nil {
+				log.Printf("[COST] Failed to release usage report claim for tenant %s (%s): %v", tenantID, period, err)
+			}
+			continue
+		}
+		log.Printf("[COST] Published %s usage for tenant %s (cost=%.4f %s, sms overage=%d)",
+			period, tenantID, summary.TotalCost, summary.Currency, summary.SMS.OverageSegments)
+	}
+}
+
+// ParseUsagePeriod resolves "YYYY-MM", "current" (default) or "previous" to a UTC month range
+func ParseUsagePeriod(period string, now time.Time) (from, to time.Time, label string, err error) {
+	now = now.UTC()
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	switch period {
+	case "", "current":
+		from = currentMonth
+	case "previous":
+		from = currentMonth.AddDate(0, -1, 0)
+	default:
+		from, err = time.Parse("2006-01", period)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("%w %q: use YYYY-MM, current or previous", ErrInvalidUsagePeriod, period)
+		}
+		if from.After(currentMonth) {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("%w %q: period is in the future", ErrInvalidUsagePeriod, period)
+		}
+	}
+
+	return from, from.AddDate(0, 1, 0), from.Format("2006-01"), nil
+}
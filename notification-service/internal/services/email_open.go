@@ -0,0 +1,40 @@
+package services
+
+import (
+	"time"
+
+	"notification-service/internal/models"
+)
+
+// EmailOpenedSubject is the NATS subject published when a tracked email is first opened.
+// notification-hub consumes it to mark the in-app copy of the notification read.
+const EmailOpenedSubject = "notification.email.opened"
+
+// EmailOpenedEvent reports the first open of an email sent for a platform event
+type EmailOpenedEvent struct {
+	EventType      string    `json:"eventType"`
+	Timestamp      time.Time `json:"timestamp"`
+	TenantID       string    `json:"tenantId"`
+	NotificationID string    `json:"notificationId"`
+	UserID         string    `json:"userId"`
+	SourceEventID  string    `json:"sourceEventId"` // sourceId of the event the email was sent for
+	OpenedAt       time.Time `json:"openedAt"`
+}
+
+// NewEmailOpenedEvent builds the read-sync event for an opened email.
+// Returns nil when the email has no recipient user or source event to match an in-app notification by.
+func NewEmailOpenedEvent(notification *models.Notification, openedAt time.Time) *EmailOpenedEvent {
+	if notification.RecipientID == nil || notification.SourceEventID == "" {
+		return nil
+	}
+	return &EmailOpenedEvent{
+		EventType:      EmailOpenedSubject,
+		Timestamp:      time.Now().UTC(),
+		TenantID:       notification.TenantID,
+		NotificationID: notification.ID.String(),
+		UserID:         notification.RecipientID.String(),
+		SourceEventID:  notification.SourceEventID,
+		OpenedAt:       openedAt.UTC(),
+	}
+}
+
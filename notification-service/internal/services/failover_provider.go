@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -15,8 +16,14 @@ type FailoverEmailProvider struct {
 	enableFailover  bool
 	maxRetries      int
 	retryDelay      time.Duration
+	healthMonitor   *ProviderHealthMonitor
+	marketing       Provider
 }
 
+// ErrMarketingProviderUnavailable is returned for marketing sends while the marketing provider is unhealthy
+// Callers defer the notification as QUEUED_PROVIDER_DOWN and replay it once the provider recovers
+var ErrMarketingProviderUnavailable = errors.New("marketing email provider is unavailable")
+
 // FailoverConfig configures the failover behavior
 type FailoverConfig struct {
 	EnableFailover bool
@@ -43,12 +50,81 @@ func NewFailoverEmailProvider(providers []Provider, config *FailoverConfig) *Fai
 		}
 	}
 
+	// Marketing sends are routed to Mautic when it is configured
+	var marketing Provider
+	for _, p := range validProviders {
+		if _, ok := p.(*MauticProvider); ok {
+			marketing = p
+			break
+		}
+	}
+
 	return &FailoverEmailProvider{
 		providers:      validProviders,
 		enableFailover: config.EnableFailover,
 		maxRetries:     config.MaxRetries,
 		retryDelay:     config.RetryDelay,
+		marketing:      marketing,
+	}
+}
+
+// SetHealthMonitor enables health-aware routing
+// Providers reported unhealthy by the monitor are skipped until they recover
+func (f *FailoverEmailProvider) SetHealthMonitor(monitor *ProviderHealthMonitor) {
+	f.healthMonitor = monitor
+	if monitor == nil {
+		return
+	}
+	for _, p := range f.providers {
+		monitor.Register(p)
+	}
+}
+
+// MarketingProviderName returns the name of the provider marketing sends are routed to, if any
+func (f *FailoverEmailProvider) MarketingProviderName() string {
+	if f.marketing == nil {
+		return ""
+	}
+	return f.marketing.GetName()
+}
+
+// MarketingAvailable reports whether marketing sends can currently be delivered
+func (f *FailoverEmailProvider) MarketingAvailable() bool {
+	return f.marketing == nil || f.healthMonitor.IsAvailable(f.marketing.GetName())
+}
+
+// isMarketingMessage reports whether the message was tagged as a marketing send
+func isMarketingMessage(message *Message) bool {
+	if message == nil || message.Metadata == nil {
+		return false
+	}
+	category, _ := message.Metadata["category"].(string)
+	return strings.EqualFold(category, "marketing")
+}
+
+// orderedProviders returns the chain to try for a message, skipping unhealthy providers
+// Marketing sends go to the marketing provider first when one is configured
+func (f *FailoverEmailProvider) orderedProviders(message *Message) []Provider {
+	ordered := make([]Provider, 0, len(f.providers))
+	if f.marketing != nil && isMarketingMessage(message) {
+		ordered = append(ordered, f.marketing)
+	}
+	for _, p := range f.providers {
+		if f.marketing != nil && isMarketingMessage(message) && p == f.marketing {
+			continue
+		}
+		ordered = append(ordered, p)
+	}
+
+	available := ordered[:0]
+	for _, p := range ordered {
+		if !f.healthMonitor.IsAvailable(p.GetName()) {
+			log.Printf("[FAILOVER] Skipping %s: marked unhealthy", p.GetName())
+			continue
+		}
+		available = append(available, p)
 	}
+	return available
 }
 
 // Send sends an email with automatic failover
@@ -61,12 +137,33 @@ func (f *FailoverEmailProvider) Send(ctx context.Context, message *Message) (*Se
 		}, fmt.Errorf("no email providers configured")
 	}
 
+	// Hold marketing sends back while the marketing provider is down rather than
+	// pushing them through the transactional chain
+	if isMarketingMessage(message) && !f.MarketingAvailable() {
+		err := fmt.Errorf("%w: %s is marked unhealthy", ErrMarketingProviderUnavailable, f.marketing.GetName())
+		return &SendResult{
+			ProviderName: f.marketing.GetName(),
+			Success:      false,
+			Error:        err,
+		}, err
+	}
+
+	providers := f.orderedProviders(message)
+	if len(providers) == 0 {
+		err := fmt.Errorf("no healthy email providers available")
+		return &SendResult{
+			ProviderName: "Failover",
+			Success:      false,
+			Error:        err,
+		}, err
+	}
+
 	startTime := time.Now()
 	var lastError error
 	var allErrors []string
 
 	// Try each provider in order
-	for i, provider := range f.providers {
+	for i, provider := range providers {
 		providerName := provider.GetName()
 
 		// Skip if context is cancelled
@@ -85,7 +182,7 @@ func (f *FailoverEmailProvider) Send(ctx context.Context, message *Message) (*Se
 				time.Sleep(f.retryDelay)
 			}
 
-			log.Printf("[FAILOVER] Attempting to send via %s (provider %d/%d)", providerName, i+1, len(f.providers))
+			log.Printf("[FAILOVER] Attempting to send via %s (provider %d/%d)", providerName, i+1, len(providers))
 
 			result, err := provider.Send(ctx, message)
 			if err == nil && result.Success {
@@ -173,24 +270,38 @@ func (f *FailoverEmailProvider) GetPrimaryProvider() Provider {
 
 // IsHealthy checks if at least one provider is available
 func (f *FailoverEmailProvider) IsHealthy() bool {
-	return len(f.providers) > 0
+	for _, p := range f.providers {
+		if f.healthMonitor.IsAvailable(p.GetName()) {
+			return true
+		}
+	}
+	return false
 }
 
 // ProviderStatus represents the status of a provider in the chain
 type ProviderStatus struct {
-	Name      string `json:"name"`
-	Position  int    `json:"position"`
-	Available bool   `json:"available"`
+	Name      string          `json:"name"`
+	Position  int             `json:"position"`
+	Available bool            `json:"available"`
+	Health    *ProviderHealth `json:"health,omitempty"`
 }
 
 // GetProviderStatuses returns status information for all providers
 func (f *FailoverEmailProvider) GetProviderStatuses() []ProviderStatus {
-	statuses := make([]ProviderStatus, len(f.providers))
-	for i, p := range f.providers {
+	return buildProviderStatuses(f.providers, f.healthMonitor)
+}
+
+// buildProviderStatuses reports chain position and, when monitored, probe results
+func buildProviderStatuses(providers []Provider, monitor *ProviderHealthMonitor) []ProviderStatus {
+	statuses := make([]ProviderStatus, len(providers))
+	for i, p := range providers {
 		statuses[i] = ProviderStatus{
 			Name:      p.GetName(),
 			Position:  i + 1,
-			Available: true, // Assume available if configured
+			Available: monitor.IsAvailable(p.GetName()),
+		}
+		if health, ok := monitor.Health(p.GetName()); ok {
+			statuses[i].Health = &health
 		}
 	}
 	return statuses
@@ -359,13 +470,5 @@ func (f *FailoverSMSProvider) IsHealthy() bool {
 
 // GetProviderStatuses returns status information for all SMS providers
 func (f *FailoverSMSProvider) GetProviderStatuses() []ProviderStatus {
-	statuses := make([]ProviderStatus, len(f.providers))
-	for i, p := range f.providers {
-		statuses[i] = ProviderStatus{
-			Name:      p.GetName(),
-			Position:  i + 1,
-			Available: true, // Assume available if configured
-		}
-	}
-	return statuses
+	return buildProviderStatuses(f.providers, nil)
 }
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeMautic serves the Mautic API endpoints used for health probes and sends
+type fakeMautic struct {
+	server  *httptest.Server
+	healthy atomic.Bool
+	mu      sync.Mutex
+	sends   int
+}
+
+func newFakeMautic(t *testing.T) *fakeMautic {
+	t.Helper()
+	f := &fakeMautic{}
+	f.healthy.Store(true)
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !f.healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/contacts":
+			w.Write([]byte(`{"total":0,"contacts":{}}`))
+		case r.URL.Path == "/api/contacts/new":
+			w.Write([]byte(`{"contact":{"id":7}}`))
+		case r.URL.Path == "/api/emails/new":
+			w.Write([]byte(`{"email":{"id":11}}`))
+		case strings.HasSuffix(r.URL.Path, "/send"):
+			f.mu.Lock()
+			f.sends++
+			f.mu.Unlock()
+			w.Write([]byte(`{"success":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeMautic) sendCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sends
+}
+
+// recordingProvider is a transactional provider that records what it sent
+type recordingProvider struct {
+	name string
+	mu   sync.Mutex
+	sent []*Message
+}
+
+func (p *recordingProvider) Send(ctx context.Context, message *Message) (*SendResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, message)
+	return &SendResult{ProviderName: p.name, ProviderID: "msg-1", Success: true}, nil
+}
+
+func (p *recordingProvider) GetName() string         { return p.name }
+func (p *recordingProvider) SupportsChannel() string { return "EMAIL" }
+
+func (p *recordingProvider) sentCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.sent)
+}
+
+func newMarketingChain(t *testing.T) (*FailoverEmailProvider, *ProviderHealthMonitor, *fakeMautic, *recordingProvider) {
+	t.Helper()
+	mautic := newFakeMautic(t)
+	postal := &recordingProvider{name: "Postal"}
+	chain := NewFailoverEmailProvider([]Provider{
+		postal,
+		NewMauticProvider(&ProviderConfig{MauticURL: mautic.server.URL, MauticUsername: "api", MauticPassword: "secret"}),
+	}, &FailoverConfig{EnableFailover: true})
+
+	monitor := NewProviderHealthMonitor(&HealthMonitorConfig{FailureThreshold: 1, Timeout: time.Second})
+	chain.SetHealthMonitor(monitor)
+	return chain, monitor, mautic, postal
+}
+
+func marketingMessage() *Message {
+	return &Message{
+		To:       "reader@example.com",
+		Subject:  "Spring sale",
+		BodyHTML: "<p>Spring sale</p>",
+		Metadata: map[string]interface{}{"category": "marketing"},
+	}
+}
+
+func TestFailoverRoutesMarketingToMautic(t *testing.T) {
+	chain, monitor, mautic, postal := newMarketingChain(t)
+	monitor.CheckAll(context.Background())
+
+	result, err := chain.Send(context.Background(), marketingMessage())
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if !result.Success || result.ProviderName != "Mautic" {
+		t.Fatalf("Send() = %+v, want a successful Mautic send", result)
+	}
+	if mautic.sendCount() != 1 || postal.sentCount() != 0 {
+		t.Fatalf("sends: mautic=%d postal=%d, want 1 and 0", mautic.sendCount(), postal.sentCount())
+	}
+}
+
+func TestFailoverFailsMarketingWhileMauticUnhealthy(t *testing.T) {
+	chain, monitor, mautic, postal := newMarketingChain(t)
+	mautic.healthy.Store(false)
+	monitor.CheckAll(context.Background())
+
+	if monitor.IsAvailable("Mautic") || chain.MarketingAvailable() {
+		t.Fatal("Mautic still available after a failed probe")
+	}
+
+	result, err := chain.Send(context.Background(), marketingMessage())
+	if !errors.Is(err, ErrMarketingProviderUnavailable) {
+		t.Fatalf("Send() error = %v, want ErrMarketingProviderUnavailable", err)
+	}
+	if result == nil || result.Success {
+		t.Fatalf("Send() = %+v, want a failed result so the notification is deferred", result)
+	}
+	if postal.sentCount() != 0 {
+		t.Fatal("marketing send fell through to the transactional provider")
+	}
+}
+
+func TestFailoverSkipsUnhealthyMauticForTransactional(t *testing.T) {
+	chain, monitor, mautic, postal := newMarketingChain(t)
+	mautic.healthy.Store(false)
+	monitor.CheckAll(context.Background())
+
+	result, err := chain.Send(context.Background(), &Message{To: "buyer@example.com", Subject: "Order confirmed"})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if result.ProviderName != "Postal" || postal.sentCount() != 1 {
+		t.Fatalf("Send() = %+v, want the transactional provider", result)
+	}
+}
+
+func TestFailoverResumesMarketingAfterRecovery(t *testing.T) {
+	chain, monitor, mautic, _ := newMarketingChain(t)
+	var recovered []string
+	monitor.OnRecovery(func(name string) { recovered = append(recovered, name) })
+
+	mautic.healthy.Store(false)
+	monitor.CheckAll(context.Background())
+	if _, err := chain.Send(context.Background(), marketingMessage()); !errors.Is(err, ErrMarketingProviderUnavailable) {
+		t.Fatalf("Send() error = %v, want ErrMarketingProviderUnavailable", err)
+	}
+
+	mautic.healthy.Store(true)
+	monitor.CheckAll(context.Background())
+	monitor.CheckAll(context.Background())
+	if len(recovered) != 1 || recovered[0] != chain.MarketingProviderName() {
+		t.Fatalf("recovery callbacks = %v, want one for Mautic", recovered)
+	}
+
+	// The retried send goes through once Mautic recovers
+	result, err := chain.Send(context.Background(), marketingMessage())
+	if err != nil || !result.Success {
+		t.Fatalf("Send() after recovery = %+v, %v", result, err)
+	}
+	if mautic.sendCount() != 1 {
+		t.Fatalf("mautic sends = %d, want 1", mautic.sendCount())
+	}
+	if health, _ := monitor.Health("Mautic"); !health.Healthy || health.ConsecutiveFailures != 0 {
+		t.Fatalf("Mautic health = %+v, want healthy with no failures", health)
+	}
+}
@@ -0,0 +1,578 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Tesseract-Nexus/go-shared/security"
+	"gorm.io/gorm"
+	"notification-service/internal/models"
+	"notification-service/internal/repository"
+)
+
+// SupportEmailReceivedSubject is the NATS subject published for every reply matched to a
+// tenant, whether or not it could be forwarded to the tenant's support email
+const SupportEmailReceivedSubject = "support.email.received"
+
+const (
+	// maxInboundBodyBytes caps each stored body part; longer replies are truncated
+	maxInboundBodyBytes = 256 * 1024
+	// maxMIMEDepth bounds nested multipart parsing
+	maxMIMEDepth = 5
+)
+
+var (
+	// ErrInvalidInboundEmail is returned for webhook payloads that are not a received email
+	ErrInvalidInboundEmail = errors.New("invalid inbound email payload")
+
+	// quotedReplyMarkers start the quoted original in common mail clients
+	quotedReplyMarkers = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^On .+wrote:\s*$`),
+		regexp.MustCompile(`(?m)^-{2,}\s*Original Message\s*-{2,}`),
+		regexp.MustCompile(`(?m)^_{10,}\s*$`),
+		regexp.MustCompile(`(?m)^From: .+$`),
+	}
+)
+
+// SupportEmailReceivedEvent reports a customer reply to an email sent for a tenant
+type SupportEmailReceivedEvent struct {
+	EventType      string    `json:"eventType"`
+	Timestamp      time.Time `json:"timestamp"`
+	TenantID       string    `json:"tenantId"`
+	InboundEmailID string    `json:"inboundEmailId"`
+	NotificationID string    `json:"notificationId"`
+	TemplateName   string    `json:"templateName,omitempty"` // Template of the email replied to
+	SourceEventID  string    `json:"sourceEventId,omitempty"`
+	FromAddress    string    `json:"fromAddress"`
+	FromName       string    `json:"fromName,omitempty"`
+	Subject        string    `json:"subject"`
+	ReplyText      string    `json:"replyText"` // Text body without the quoted original
+	TextBody       string    `json:"textBody"`
+	ForwardedTo    string    `json:"forwardedTo,omitempty"` // Empty when the reply was not forwarded
+	ReceivedAt     time.Time `json:"receivedAt"`
+}
+
+// InboundEmailService matches replies to the notifications they answer, forwards them to
+// the tenant's support email and publishes support.email.received
+type InboundEmailService struct {
+	inboundRepo          repository.InboundEmailRepository
+	notifRepo            repository.NotificationRepository
+	emailProvider        Provider
+	tenantClient         *TenantClient
+	replyRouter          *ReplyRouter
+	platformSupportEmail string         // Receives replies to platform (system) emails
+	publisher            UsagePublisher // Optional
+}
+
+// NewInboundEmailService creates a new inbound email service
+func NewInboundEmailService(
+	inboundRepo repository.InboundEmailRepository,
+	notifRepo repository.NotificationRepository,
+	emailProvider Provider,
+	tenantClient *TenantClient,
+	replyRouter *ReplyRouter,
+	platformSupportEmail string,
+) *InboundEmailService {
+	return &InboundEmailService{
+		inboundRepo:          inboundRepo,
+		notifRepo:            notifRepo,
+		emailProvider:        emailProvider,
+		tenantClient:         tenantClient,
+		replyRouter:          replyRouter,
+		platformSupportEmail: platformSupportEmail,
+	}
+}
+
+// SetPublisher enables publishing support.email.received events
+func (s *InboundEmailService) SetPublisher(publisher UsagePublisher) {
+	s.publisher = publisher
+}
+
+// Process stores a received email, matches it to the notification it replies to and routes it.
+// recipients are the envelope recipients, which carry the reply-to token. Returns the stored
+// email and whether it had already been processed (providers retry webhooks).
+func (s *InboundEmailService) Process(ctx context.Context, email *models.InboundEmail, recipients []string) (*models.InboundEmail, bool, error) {
+	existing, err := s.inboundRepo.GetByMessageID(ctx, email.MessageID)
+	if err == nil {
+		return existing, true, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	notification, err := s.match(ctx, email, recipients)
+	if err != nil {
+		return nil, false, err
+	}
+	if notification != nil {
+		email.TenantID = notification.TenantID
+		email.NotificationID = &notification.ID
+	}
+
+	switch {
+	case email.AutoSubmitted:
+		email.Status = models.InboundIgnored
+	case notification == nil:
+		email.Status = models.InboundUnmatched
+	default:
+		email.Status = models.InboundFailed
+	}
+	if err := s.inboundRepo.Create(ctx, email); err != nil {
+		return nil, false, fmt.Errorf("failed to store inbound email: %w", err)
+	}
+	if email.Status != models.InboundFailed {
+		log.Printf("[INBOUND] %s reply %s from %s", email.Status, email.MessageID, security.MaskEmail(email.FromAddress))
+		return email, false, nil
+	}
+
+	var failures []string
+	if err := s.forward(ctx, email, notification); err != nil {
+		failures = append(failures, err.Error())
+	}
+	published := false
+	if err := s.publish(email, notification); err != nil {
+		failures = append(failures, err.Error())
+	} else {
+		published = s.publisher != nil
+	}
+	switch {
+	case email.ForwardedTo != "":
+		email.Status = models.InboundForwarded
+	case published:
+		email.Status = models.InboundPublished
+	case len(failures) == 0:
+		failures = append(failures, "no support email configured and event publishing disabled")
+	}
+	if email.Status == models.InboundFailed {
+		email.ErrorMessage = strings.Join(failures, "; ")
+	}
+
+	if err := s.inboundRepo.Update(ctx, email); err != nil {
+		log.Printf("[INBOUND] Failed to update inbound email %s: %v", email.ID, err)
+	}
+	log.Printf("[INBOUND] Reply to notification %s from %s: %s", notification.ID, security.MaskEmail(email.FromAddress), email.Status)
+	return email, false, nil
+}
+
+// match finds the email notification a reply answers: by the signed reply-to address first,
+// then by the provider message IDs in In-Reply-To and References
+func (s *InboundEmailService) match(ctx context.Context, email *models.InboundEmail, recipients []string) (*models.Notification, error) {
+	for _, recipient := range recipients {
+		id, ok := s.replyRouter.Resolve(recipient)
+		if !ok {
+			continue
+		}
+		notification, err := s.notifRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if notification != nil && notification.Channel == models.ChannelEmail {
+			return notification, nil
+		}
+	}
+
+	for _, providerID := range providerIDCandidates(email.InReplyTo, email.References) {
+		notification, err := s.notifRepo.GetByProviderID(ctx, providerID)
+		if err == nil && notification.Channel == models.ChannelEmail {
+			return notification, nil
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// forward sends the reply to the tenant's support email, with Reply-To set to the customer
+// so the support team answers them directly. Tenants without a support email are skipped.
+func (s *InboundEmailService) forward(ctx context.Context, email *models.InboundEmail, notification *models.Notification) error {
+	if s.emailProvider == nil {
+		return nil
+	}
+	supportEmail := s.supportEmailFor(notification.TenantID)
+	if supportEmail == "" || strings.EqualFold(supportEmail, email.FromAddress) {
+		return nil
+	}
+
+	sender := email.FromAddress
+	if email.FromName != "" {
+		sender = fmt.Sprintf("%s <%s>", email.FromName, email.FromAddress)
+	}
+	preface := fmt.Sprintf("Reply from %s to \"%s\" sent to %s.", sender, notification.Subject, notification.RecipientEmail)
+
+	message := &Message{
+		To:      supportEmail,
+		Subject: email.Subject,
+		Body:    preface + "\n\n" + email.TextBody,
+		ReplyTo: email.FromAddress,
+	}
+	if email.HTMLBody != "" {
+		message.BodyHTML = "<p>" + html.EscapeString(preface) + "</p><hr>" + email.HTMLBody
+	}
+	if message.Subject == "" {
+		message.Subject = "Re: " + notification.Subject
+	}
+
+	result, err := s.emailProvider.Send(ctx, message)
+	if err == nil && !result.Success {
+		err = result.Error
+		if err == nil {
+			err = errors.New("send failed")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("forward to support email failed: %w", err)
+	}
+	email.ForwardedTo = supportEmail
+	return nil
+}
+
+// supportEmailFor returns the tenant's configured support email, or the platform support
+// email for platform (system) emails
+func (s *InboundEmailService) supportEmailFor(tenantID string) string {
+	if tenantID == "" || tenantID == "system" || tenantID == models.PlatformTenantID {
+		return s.platformSupportEmail
+	}
+	if s.tenantClient == nil {
+		return ""
+	}
+	info, err := s.tenantClient.GetTenantInfo(tenantID)
+	if err != nil || info == nil {
+		return ""
+	}
+	return info.SupportEmail
+}
+
+// publish publishes support.email.received for a matched reply
+func (s *InboundEmailService) publish(email *models.InboundEmail, notification *models.Notification) error {
+	if s.publisher == nil {
+		return nil
+	}
+	event := SupportEmailReceivedEvent{
+		EventType:      SupportEmailReceivedSubject,
+		Timestamp:      time.Now().UTC(),
+		TenantID:       email.TenantID,
+		InboundEmailID: email.ID.String(),
+		NotificationID: notification.ID.String(),
+		TemplateName:   notification.TemplateName,
+		SourceEventID:  notification.SourceEventID,
+		FromAddress:    email.FromAddress,
+		FromName:       email.FromName,
+		Subject:        email.Subject,
+		ReplyText:      ReplyText(email.TextBody),
+		TextBody:       email.TextBody,
+		ForwardedTo:    email.ForwardedTo,
+		ReceivedAt:     email.ReceivedAt.UTC(),
+	}
+	data, err := json.Marshal(event)
+	if err == nil {
+		err = s.publisher.Publish(SupportEmailReceivedSubject, data)
+	}
+	if err != nil {
+		return fmt.Errorf("publish %s failed: %w", SupportEmailReceivedSubject, err)
+	}
+	return nil
+}
+
+// ReplyText returns the new text of a reply, dropping the quoted original and quoted lines
+func ReplyText(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	for _, marker := range quotedReplyMarkers {
+		if loc := marker.FindStringIndex(body); loc != nil {
+			body = body[:loc[0]]
+		}
+	}
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), ">") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// providerIDCandidates returns the provider message IDs a reply may reference. Providers
+// return either the bare ID or the Message-ID local part (SES appends @<region>.amazonses.com).
+func providerIDCandidates(inReplyTo, references string) []string {
+	var candidates []string
+	seen := map[string]bool{}
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			candidates = append(candidates, id)
+		}
+	}
+	ids := messageIDs(inReplyTo)
+	refs := messageIDs(references)
+	// The most recent reference is the email being replied to
+	for i := len(refs) - 1; i >= 0; i-- {
+		ids = append(ids, refs[i])
+	}
+	for _, id := range ids {
+		add(id)
+		if local, _, ok := strings.Cut(id, "@"); ok {
+			add(local)
+		}
+	}
+	return candidates
+}
+
+// messageIDs splits a Message-ID list header into IDs without angle brackets
+func messageIDs(header string) []string {
+	var ids []string
+	for _, field := range strings.Fields(header) {
+		if id := strings.Trim(field, "<>,"); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// isAutoSubmitted reports whether an Auto-Submitted header marks an automatic message (RFC 3834)
+func isAutoSubmitted(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return value != "" && value != "no"
+}
+
+// ==================== POSTAL ====================
+
+// postalInboundMessage is a message delivered by a Postal HTTP endpoint route ("Hash" format)
+type postalInboundMessage struct {
+	ID            int64   `json:"id"`
+	RcptTo        string  `json:"rcpt_to"`
+	MailFrom      string  `json:"mail_from"`
+	Token         string  `json:"token"`
+	Subject       string  `json:"subject"`
+	MessageID     string  `json:"message_id"`
+	Timestamp     float64 `json:"timestamp"`
+	To            string  `json:"to"`
+	From          string  `json:"from"`
+	InReplyTo     string  `json:"in_reply_to"`
+	References    string  `json:"references"`
+	PlainBody     string  `json:"plain_body"`
+	HTMLBody      string  `json:"html_body"`
+	AutoSubmitted string  `json:"auto_submitted"`
+	Bounce        bool    `json:"bounce"`
+}
+
+// ParsePostalInbound parses a Postal inbound webhook. Returns the email and its envelope recipients.
+func ParsePostalInbound(body []byte) (*models.InboundEmail, []string, error) {
+	var msg postalInboundMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidInboundEmail, err)
+	}
+	if msg.RcptTo == "" && msg.To == "" {
+		return nil, nil, fmt.Errorf("%w: no recipient", ErrInvalidInboundEmail)
+	}
+
+	email := &models.InboundEmail{
+		Provider:      "postal",
+		MessageID:     strings.Trim(msg.MessageID, "<> "),
+		InReplyTo:     msg.InReplyTo,
+		References:    msg.References,
+		ToAddress:     msg.To,
+		Subject:       msg.Subject,
+		TextBody:      truncateBody(msg.PlainBody),
+		HTMLBody:      truncateBody(msg.HTMLBody),
+		AutoSubmitted: msg.Bounce || isAutoSubmitted(msg.AutoSubmitted),
+		ReceivedAt:    time.Now(),
+	}
+	if msg.Timestamp > 0 {
+		email.ReceivedAt = time.Unix(int64(msg.Timestamp), 0)
+	}
+	if email.MessageID == "" {
+		email.MessageID = fmt.Sprintf("postal-%d-%s", msg.ID, msg.Token)
+	}
+	setSender(email, msg.From, msg.MailFrom)
+	if email.FromAddress == "" {
+		return nil, nil, fmt.Errorf("%w: no sender", ErrInvalidInboundEmail)
+	}
+
+	recipients := []string{msg.RcptTo}
+	if addresses, err := mail.ParseAddressList(msg.To); err == nil {
+		for _, address := range addresses {
+			recipients = append(recipients, address.Address)
+		}
+	}
+	return email, recipients, nil
+}
+
+// ==================== AWS SES ====================
+
+// sesInboundNotification is an SES receipt rule notification delivered through SNS
+type sesInboundNotification struct {
+	NotificationType string `json:"notificationType"`
+	Mail             struct {
+		Timestamp   time.Time `json:"timestamp"`
+		Source      string    `json:"source"`
+		MessageID   string    `json:"messageId"`
+		Destination []string  `json:"destination"`
+		Headers     []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"headers"`
+		CommonHeaders struct {
+			From      []string `json:"from"`
+			To        []string `json:"to"`
+			MessageID string   `json:"messageId"`
+			Subject   string   `json:"subject"`
+		} `json:"commonHeaders"`
+	} `json:"mail"`
+	Receipt struct {
+		Recipients []string `json:"recipients"`
+	} `json:"receipt"`
+	Content string `json:"content"` // Raw MIME message; only present for the SNS action
+}
+
+// ParseSESInbound parses the SES notification carried in an SNS message. Returns the email and
+// its envelope recipients. The body is read from the raw message, which SES includes when the
+// receipt rule uses the SNS action (messages up to 150 KB).
+func ParseSESInbound(message []byte) (*models.InboundEmail, []string, error) {
+	var notification sesInboundNotification
+	if err := json.Unmarshal(message, &notification); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidInboundEmail, err)
+	}
+	if notification.NotificationType != "Received" {
+		return nil, nil, fmt.Errorf("%w: notification type %q", ErrInvalidInboundEmail, notification.NotificationType)
+	}
+
+	headers := mail.Header{}
+	for _, header := range notification.Mail.Headers {
+		key := strings.ToLower(header.Name)
+		headers[key] = append(headers[key], header.Value)
+	}
+	get := func(name string) string {
+		if values := headers[strings.ToLower(name)]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	common := notification.Mail.CommonHeaders
+	email := &models.InboundEmail{
+		Provider:      "ses",
+		MessageID:     strings.Trim(common.MessageID, "<> "),
+		InReplyTo:     get("In-Reply-To"),
+		References:    get("References"),
+		ToAddress:     strings.Join(common.To, ", "),
+		Subject:       common.Subject,
+		AutoSubmitted: isAutoSubmitted(get("Auto-Submitted")),
+		ReceivedAt:    notification.Mail.Timestamp,
+	}
+	if email.MessageID == "" {
+		email.MessageID = notification.Mail.MessageID
+	}
+	if email.ReceivedAt.IsZero() {
+		email.ReceivedAt = time.Now()
+	}
+	from := ""
+	if len(common.From) > 0 {
+		from = common.From[0]
+	}
+	setSender(email, from, notification.Mail.Source)
+	if email.FromAddress == "" {
+		return nil, nil, fmt.Errorf("%w: no sender", ErrInvalidInboundEmail)
+	}
+
+	if notification.Content != "" {
+		raw, err := mail.ReadMessage(strings.NewReader(notification.Content))
+		if err == nil {
+			text, htmlBody := extractBodies(raw.Header.Get("Content-Type"), raw.Header.Get("Content-Transfer-Encoding"), raw.Body, 0)
+			email.TextBody = truncateBody(text)
+			email.HTMLBody = truncateBody(htmlBody)
+		} else {
+			log.Printf("[INBOUND] Failed to parse SES message %s: %v", notification.Mail.MessageID, err)
+		}
+	}
+
+	recipients := notification.Receipt.Recipients
+	if len(recipients) == 0 {
+		recipients = notification.Mail.Destination
+	}
+	return email, recipients, nil
+}
+
+// setSender sets the From address and name from the From header, falling back to the envelope sender
+func setSender(email *models.InboundEmail, fromHeader, envelopeFrom string) {
+	if address, err := mail.ParseAddress(fromHeader); err == nil {
+		email.FromAddress = strings.ToLower(address.Address)
+		email.FromName = address.Name
+		return
+	}
+	email.FromAddress = strings.ToLower(strings.Trim(envelopeFrom, "<> "))
+}
+
+// extractBodies returns the first text/plain and text/html parts of a MIME entity
+func extractBodies(contentType, transferEncoding string, body io.Reader, depth int) (string, string) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxMIMEDepth || params["boundary"] == "" {
+			return "", ""
+		}
+		var text, htmlBody string
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			if strings.HasPrefix(strings.ToLower(part.Header.Get("Content-Disposition")), "attachment") {
+				continue
+			}
+			partText, partHTML := extractBodies(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part, depth+1)
+			if text == "" {
+				text = partText
+			}
+			if htmlBody == "" {
+				htmlBody = partHTML
+			}
+		}
+		return text, htmlBody
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", ""
+	}
+	var decoded io.Reader = body
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		decoded = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		decoded = quotedprintable.NewReader(body)
+	}
+	content, err := io.ReadAll(io.LimitReader(decoded, maxInboundBodyBytes+1))
+	if err != nil && len(content) == 0 {
+		return "", ""
+	}
+	if mediaType == "text/html" {
+		return "", string(content)
+	}
+	return string(content), ""
+}
+
+// truncateBody caps a body at maxInboundBodyBytes on a line boundary where possible
+func truncateBody(body string) string {
+	if len(body) <= maxInboundBodyBytes {
+		return body
+	}
+	body = body[:maxInboundBodyBytes]
+	if i := strings.LastIndexByte(body, '\n'); i > 0 {
+		body = body[:i]
+	}
+	return strings.ToValidUTF8(body, "")
+}
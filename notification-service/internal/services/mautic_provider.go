@@ -5,10 +5,14 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -342,12 +346,153 @@ func (p *MauticProvider) addContactToSegment(ctx context.Context, contactID, seg
 	return nil
 }
 
+// SegmentContact is a contact of a Mautic segment
+type SegmentContact struct {
+	Email     string
+	FirstName string
+	LastName  string
+}
+
+// ErrSegmentNotFound is returned when a Mautic segment does not exist
+var ErrSegmentNotFound = errors.New("segment not found")
+
+// ListSegmentContacts returns a page of a segment's contacts ordered by contact ID,
+// along with the number of contacts in the segment
+func (p *MauticProvider) ListSegmentContacts(ctx context.Context, segmentID, start, limit int) ([]SegmentContact, int, error) {
+	alias, err := p.segmentAlias(ctx, segmentID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := url.Values{}
+	query.Set("search", "segment:"+alias)
+	query.Set("start", strconv.Itoa(start))
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("orderBy", "id")
+	query.Set("orderByDir", "asc")
+	query.Set("minimal", "true")
+
+	var page struct {
+		Total    json.RawMessage `json:"total"`
+		Contacts json.RawMessage `json:"contacts"`
+	}
+	if _, err := p.getJSON(ctx, "/api/contacts?"+query.Encode(), &page); err != nil {
+		return nil, 0, err
+	}
+
+	total, _ := strconv.Atoi(strings.Trim(string(page.Total), `"`))
+
+	// Mautic returns contacts as an object keyed by ID, or an empty array when there are none
+	var contacts map[string]struct {
+		ID     int `json:"id"`
+		Fields struct {
+			All map[string]interface{} `json:"all"`
+		} `json:"fields"`
+	}
+	if len(page.Contacts) > 0 && page.Contacts[0] == '{' {
+		if err := json.Unmarshal(page.Contacts, &contacts); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	ids := make([]int, 0, len(contacts))
+	byID := make(map[int]SegmentContact, len(contacts))
+	for _, contact := range contacts {
+		email, _ := contact.Fields.All["email"].(string)
+		if email == "" {
+			continue
+		}
+		firstName, _ := contact.Fields.All["firstname"].(string)
+		lastName, _ := contact.Fields.All["lastname"].(string)
+		ids = append(ids, contact.ID)
+		byID[contact.ID] = SegmentContact{Email: email, FirstName: firstName, LastName: lastName}
+	}
+	sort.Ints(ids)
+
+	result := make([]SegmentContact, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, byID[id])
+	}
+	return result, total, nil
+}
+
+// segmentAlias looks up the alias used to search a segment's contacts
+func (p *MauticProvider) segmentAlias(ctx context.Context, segmentID int) (string, error) {
+	var segment struct {
+		List struct {
+			Alias string `json:"alias"`
+		} `json:"list"`
+	}
+	status, err := p.getJSON(ctx, fmt.Sprintf("/api/segments/%d", segmentID), &segment)
+	if status == http.StatusNotFound {
+		return "", ErrSegmentNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if segment.List.Alias == "" {
+		return "", ErrSegmentNotFound
+	}
+	return segment.List.Alias, nil
+}
+
+// getJSON performs an authenticated GET against the Mautic API, decodes the response
+// and returns the HTTP status code
+func (p *MauticProvider) getJSON(ctx context.Context, path string, dest interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+path, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	p.setAuthHeader(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, fmt.Errorf("Mautic API error: %d - %s", resp.StatusCode, string(respBody))
+	}
+
+	return resp.StatusCode, json.Unmarshal(respBody, dest)
+}
+
 // setAuthHeader sets the Basic Auth header for API requests
 func (p *MauticProvider) setAuthHeader(req *http.Request) {
 	auth := base64.StdEncoding.EncodeToString([]byte(p.username + ":" + p.password))
 	req.Header.Set("Authorization", "Basic "+auth)
 }
 
+// HealthCheck probes the Mautic API with a minimal authenticated request
+func (p *MauticProvider) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/api/contacts?limit=1&minimal=true", nil)
+	if err != nil {
+		return err
+	}
+
+	p.setAuthHeader(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Mautic health check failed: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 // GetName returns the provider name
 func (p *MauticProvider) GetName() string {
 	return "Mautic"
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// HealthChecker is implemented by providers that can actively probe their backend
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthMonitorConfig configures the provider health monitor
+type HealthMonitorConfig struct {
+	// Interval between health probes
+	Interval time.Duration
+	// Timeout for a single health probe
+	Timeout time.Duration
+	// FailureThreshold is the number of consecutive failed probes before a provider is marked unhealthy
+	FailureThreshold int
+}
+
+// ProviderHealth represents the observed health of a single provider
+type ProviderHealth struct {
+	Name                string     `json:"name"`
+	Healthy             bool       `json:"healthy"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastError           string     `json:"lastError,omitempty"`
+	LastCheckedAt       *time.Time `json:"lastCheckedAt,omitempty"`
+	UnhealthySince      *time.Time `json:"unhealthySince,omitempty"`
+}
+
+// ProviderHealthMonitor periodically probes providers and tracks their availability
+// Providers that are not registered are always considered available
+type ProviderHealthMonitor struct {
+	config     HealthMonitorConfig
+	mu         sync.RWMutex
+	checkers   map[string]HealthChecker
+	order      []string
+	health     map[string]*ProviderHealth
+	onRecovery []func(name string)
+	stopCh     chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewProviderHealthMonitor creates a new provider health monitor
+func NewProviderHealthMonitor(config *HealthMonitorConfig) *ProviderHealthMonitor {
+	if config == nil {
+		config = &HealthMonitorConfig{}
+	}
+	cfg := *config
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 2
+	}
+
+	return &ProviderHealthMonitor{
+		config:   cfg,
+		checkers: make(map[string]HealthChecker),
+		health:   make(map[string]*ProviderHealth),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Register adds a provider to the monitor if it supports health checks
+// Returns false if the provider does not implement HealthChecker
+func (m *ProviderHealthMonitor) Register(provider Provider) bool {
+	checker, ok := provider.(HealthChecker)
+	if !ok {
+		return false
+	}
+
+	name := provider.GetName()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.checkers[name]; !exists {
+		m.order = append(m.order, name)
+	}
+	m.checkers[name] = checker
+	m.health[name] = &ProviderHealth{Name: name, Healthy: true}
+	return true
+}
+
+// OnRecovery registers fn to be called when a provider that was marked unhealthy passes a probe
+func (m *ProviderHealthMonitor) OnRecovery(fn func(name string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onRecovery = append(m.onRecovery, fn)
+}
+
+// IsAvailable reports whether the named provider may be used for sending
+func (m *ProviderHealthMonitor) IsAvailable(name string) bool {
+	if m == nil {
+		return true
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	h, ok := m.health[name]
+	if !ok {
+		return true
+	}
+	return h.Healthy
+}
+
+// Health returns a snapshot of the named provider's health, if monitored
+func (m *ProviderHealthMonitor) Health(name string) (ProviderHealth, bool) {
+	if m == nil {
+		return ProviderHealth{}, false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	h, ok := m.health[name]
+	if !ok {
+		return ProviderHealth{}, false
+	}
+	return *h, true
+}
+
+// Statuses returns a snapshot of all monitored providers in registration order
+func (m *ProviderHealthMonitor) Statuses() []ProviderHealth {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]ProviderHealth, 0, len(m.order))
+	for _, name := range m.order {
+		statuses = append(statuses, *m.health[name])
+	}
+	return statuses
+}
+
+// Start runs an initial probe and then probes all registered providers on the configured interval
+func (m *ProviderHealthMonitor) Start(ctx context.Context) {
+	m.CheckAll(ctx)
+
+	go func() {
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				m.CheckAll(ctx)
+			}
+		}
+	}()
+
+	log.Printf("[HEALTH] Provider health monitor started (interval=%v, threshold=%d)", m.config.Interval, m.config.FailureThreshold)
+}
+
+// Stop stops the background probe loop
+func (m *ProviderHealthMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+}
+
+// CheckAll probes every registered provider once
+func (m *ProviderHealthMonitor) CheckAll(ctx context.Context) {
+	m.mu.RLock()
+	names := make([]string, len(m.order))
+	copy(names, m.order)
+	m.mu.RUnlock()
+
+	for _, name := range names {
+		m.check(ctx, name)
+	}
+}
+
+// check probes a single provider and updates its health state
+func (m *ProviderHealthMonitor) check(ctx context.Context, name string) {
+	m.mu.RLock()
+	checker := m.checkers[name]
+	m.mu.RUnlock()
+	if checker == nil {
+		return
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+	err := checker.HealthCheck(probeCtx)
+	cancel()
+
+	now := time.Now()
+	recovered := false
+
+	m.mu.Lock()
+	h := m.health[name]
+	h.LastCheckedAt = &now
+	if err != nil {
+		h.ConsecutiveFailures++
+		h.LastError = err.Error()
+		if h.Healthy && h.ConsecutiveFailures >= m.config.FailureThreshold {
+			h.Healthy = false
+			h.UnhealthySince = &now
+			log.Printf("[HEALTH] %s marked unhealthy after %d failed probes: %v", name, h.ConsecutiveFailures, err)
+		}
+	} else {
+		if !h.Healthy {
+			log.Printf("[HEALTH] %s recovered (was unhealthy since %v)", name, h.UnhealthySince.Format(time.RFC3339))
+			recovered = true
+		}
+		h.Healthy = true
+		h.ConsecutiveFailures = 0
+		h.LastError = ""
+		h.UnhealthySince = nil
+	}
+	callbacks := m.onRecovery
+	m.mu.Unlock()
+
+	if recovered {
+		for _, fn := range callbacks {
+			fn(name)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// replyAddressPrefix starts the local part of every routed reply-to address
+const replyAddressPrefix = "reply+"
+
+// ReplyRouter builds per-notification Reply-To addresses on the inbound email domain,
+// reply+<notification id>.<signature>@domain, and resolves them when a reply arrives.
+// The signature stops senders from attaching replies to arbitrary notifications.
+type ReplyRouter struct {
+	domain     string
+	signingKey []byte
+}
+
+// NewReplyRouter creates a reply router for the inbound domain
+func NewReplyRouter(domain, signingKey string) *ReplyRouter {
+	return &ReplyRouter{
+		domain:     strings.ToLower(strings.TrimSpace(domain)),
+		signingKey: []byte(signingKey),
+	}
+}
+
+// Address returns the Reply-To address for a notification. Returns "" on a nil router,
+// so callers can leave routing unconfigured.
+func (r *ReplyRouter) Address(notificationID uuid.UUID) string {
+	if r == nil || notificationID == uuid.Nil {
+		return ""
+	}
+	id := strings.ReplaceAll(notificationID.String(), "-", "")
+	return fmt.Sprintf("%s%s.%s@%s", replyAddressPrefix, id, r.sign(id), r.domain)
+}
+
+// Resolve returns the notification a reply-to address was issued for
+func (r *ReplyRouter) Resolve(address string) (uuid.UUID, bool) {
+	if r == nil {
+		return uuid.Nil, false
+	}
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+	local, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(address)), "@")
+	if !ok || domain != r.domain || !strings.HasPrefix(local, replyAddressPrefix) {
+		return uuid.Nil, false
+	}
+	id, signature, ok := strings.Cut(strings.TrimPrefix(local, replyAddressPrefix), ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(r.sign(id))) {
+		return uuid.Nil, false
+	}
+	notificationID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return notificationID, true
+}
+
+// sign returns a truncated HMAC of the notification ID; 64 bits keeps the local part short
+func (r *ReplyRouter) sign(id string) string {
+	mac := hmac.New(sha256.New, r.signingKey)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
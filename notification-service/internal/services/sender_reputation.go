@@ -0,0 +1,574 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"notification-service/internal/models"
+	"notification-service/internal/repository"
+)
+
+// Errors returned by sending domain management
+var (
+	ErrSendingDomainExists  = errors.New("tenant already has a sending domain")
+	ErrSendingDomainTaken   = errors.New("sending domain is registered to another tenant")
+	ErrInvalidSendingDomain = errors.New("invalid sending domain")
+)
+
+var sendingDomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// DefaultWarmupSchedule is the daily bulk volume allowed on each day of a new domain's warm-up
+var DefaultWarmupSchedule = []int{50, 100, 250, 500, 1000, 2500, 5000, 10000, 25000, 50000, 100000}
+
+// ReputationConfig configures sender reputation monitoring and warm-up
+type ReputationConfig struct {
+	// EvaluationInterval is how often reputation is recomputed and warm-up advanced
+	EvaluationInterval time.Duration
+	// WindowDays is the number of days of stats used to compute bounce and complaint rates
+	WindowDays int
+	// MinSampleSize is the minimum sends in the window before rates are acted upon
+	MinSampleSize int64
+	// Bounce/complaint rate thresholds for the DEGRADED and POOR levels
+	BounceRateDegraded    float64
+	BounceRatePoor        float64
+	ComplaintRateDegraded float64
+	ComplaintRatePoor     float64
+	// ThrottleFactor scales the bulk daily limit of DEGRADED domains
+	ThrottleFactor float64
+	// WarmupSchedule is the daily bulk limit for each warm-up day
+	WarmupSchedule []int
+	// PlatformDomain is the domain of the platform's default From address
+	PlatformDomain string
+}
+
+// SendDecision is the outcome of a bulk send check
+type SendDecision struct {
+	Allowed    bool                   `json:"allowed"`
+	Reason     string                 `json:"reason,omitempty"` // reputation_poor, daily_limit_reached
+	Domain     string                 `json:"domain"`
+	Reputation models.ReputationLevel `json:"reputation"`
+	DailyLimit int                    `json:"dailyLimit"` // Effective bulk limit today; 0 = unlimited
+	SentToday  int64                  `json:"sentToday"`
+	RetryAfter time.Duration          `json:"-"`
+}
+
+// SendingDomainView is a sending domain with its effective limits for today
+type SendingDomainView struct {
+	models.SendingDomain
+	EffectiveDailyLimit int                              `json:"effectiveDailyLimit"` // After reputation throttling; 0 = unlimited
+	BulkSentToday       int64                            `json:"bulkSentToday"`
+	Stats               []models.SendingDomainDailyStats `json:"stats,omitempty"`
+}
+
+// RegisterSendingDomainRequest registers a tenant sending domain and starts its warm-up
+type RegisterSendingDomainRequest struct {
+	Domain      string `json:"domain" binding:"required"`
+	FromAddress string `json:"fromAddress" binding:"required,email"`
+	FromName    string `json:"fromName"`
+}
+
+// ReputationMonitor tracks per-domain bounce and complaint rates, throttles bulk
+// sends from domains with degraded reputation, and ramps up new domains on a warm-up schedule
+type ReputationMonitor struct {
+	repo   repository.SenderReputationRepository
+	config ReputationConfig
+
+	mu       sync.RWMutex
+	domains  map[string]*models.SendingDomain // By domain name
+	byTenant map[string]string                // Tenant ID -> domain name
+	avgBulk  map[string]int64                 // Average daily bulk volume over the window
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewReputationMonitor creates a new reputation monitor
+func NewReputationMonitor(repo repository.SenderReputationRepository, config *ReputationConfig) *ReputationMonitor {
+	if config == nil {
+		config = &ReputationConfig{}
+	}
+	cfg := *config
+	if cfg.EvaluationInterval <= 0 {
+		cfg.EvaluationInterval = 5 * time.Minute
+	}
+	if cfg.WindowDays <= 0 {
+		cfg.WindowDays = 7
+	}
+	if cfg.MinSampleSize <= 0 {
+		cfg.MinSampleSize = 200
+	}
+	if cfg.BounceRateDegraded <= 0 {
+		cfg.BounceRateDegraded = 0.05
+	}
+	if cfg.BounceRatePoor <= 0 {
+		cfg.BounceRatePoor = 0.10
+	}
+	if cfg.ComplaintRateDegraded <= 0 {
+		cfg.ComplaintRateDegraded = 0.001
+	}
+	if cfg.ComplaintRatePoor <= 0 {
+		cfg.ComplaintRatePoor = 0.003
+	}
+	if cfg.ThrottleFactor <= 0 || cfg.ThrottleFactor > 1 {
+		cfg.ThrottleFactor = 0.5
+	}
+	if len(cfg.WarmupSchedule) == 0 {
+		cfg.WarmupSchedule = DefaultWarmupSchedule
+	}
+	cfg.PlatformDomain = strings.ToLower(cfg.PlatformDomain)
+
+	return &ReputationMonitor{
+		repo:     repo,
+		config:   cfg,
+		domains:  make(map[string]*models.SendingDomain),
+		byTenant: make(map[string]string),
+		avgBulk:  make(map[string]int64),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start registers the platform domain, runs an initial evaluation and then re-evaluates on the configured interval
+func (m *ReputationMonitor) Start(ctx context.Context) {
+	if err := m.ensurePlatformDomain(ctx); err != nil {
+		log.Printf("[REPUTATION] Failed to register platform sending domain: %v", err)
+	}
+	m.Evaluate(ctx)
+
+	go func() {
+		ticker := time.NewTicker(m.config.EvaluationInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				m.Evaluate(ctx)
+			}
+		}
+	}()
+
+	log.Printf("[REPUTATION] Sender reputation monitor started (interval=%v, window=%dd)", m.config.EvaluationInterval, m.config.WindowDays)
+}
+
+// Stop stops the background evaluation loop
+func (m *ReputationMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+}
+
+// ensurePlatformDomain tracks the platform's default domain; it is established, so it skips warm-up
+func (m *ReputationMonitor) ensurePlatformDomain(ctx context.Context) error {
+	if m.config.PlatformDomain == "" {
+		return nil
+	}
+	if _, err := m.repo.GetDomain(ctx, m.config.PlatformDomain); err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return m.repo.CreateDomain(ctx, &models.SendingDomain{
+		TenantID:   models.PlatformTenantID,
+		Domain:     m.config.PlatformDomain,
+		Status:     models.SendingDomainActive,
+		Reputation: models.ReputationGood,
+	})
+}
+
+// Sender returns the sending domain and From identity for a tenant's emails
+// Tenants without a registered domain send from the platform domain with the provider's default From
+func (m *ReputationMonitor) Sender(tenantID string) (domain, fromAddress, fromName string) {
+	if m == nil {
+		return "", "", ""
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if name, ok := m.byTenant[tenantID]; ok {
+		if d := m.domains[name]; d != nil {
+			return d.Domain, d.FromAddress, d.FromName
+		}
+	}
+	return m.config.PlatformDomain, "", ""
+}
+
+// CheckBulkSend reports whether a bulk (marketing) email may be sent from the domain now
+// Transactional email is never throttled by reputation or warm-up
+func (m *ReputationMonitor) CheckBulkSend(ctx context.Context, domain string) (*SendDecision, error) {
+	decision := &SendDecision{Allowed: true, Domain: domain, Reputation: models.ReputationGood}
+	if m == nil || domain == "" {
+		return decision, nil
+	}
+
+	m.mu.RLock()
+	d := m.domains[domain]
+	var limit int
+	if d != nil {
+		decision.Reputation = d.Reputation
+		limit = m.effectiveLimitLocked(d)
+	}
+	m.mu.RUnlock()
+	if d == nil {
+		return decision, nil
+	}
+	decision.DailyLimit = limit
+
+	if d.Reputation == models.ReputationPoor {
+		decision.Allowed = false
+		decision.Reason = "reputation_poor"
+		decision.RetryAfter = m.config.EvaluationInterval
+		return decision, nil
+	}
+	if limit == 0 {
+		return decision, nil
+	}
+
+	sent, err := m.bulkSentToday(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	decision.SentToday = sent
+	if sent >= int64(limit) {
+		decision.Allowed = false
+		decision.Reason = "daily_limit_reached"
+		decision.RetryAfter = time.Until(startOfDay(time.Now()).Add(24 * time.Hour))
+	}
+	return decision, nil
+}
+
+// RecordSend counts a successful send from the domain
+func (m *ReputationMonitor) RecordSend(ctx context.Context, domain string, bulk bool) error {
+	if m == nil || domain == "" {
+		return nil
+	}
+	deltas := models.SendingDomainDailyStats{Sent: 1}
+	if bulk {
+		deltas.BulkSent = 1
+	}
+	return m.repo.IncrementStats(ctx, domain, startOfDay(time.Now()), deltas)
+}
+
+// RecordEvent counts a delivery, bounce or spam complaint reported for the domain
+func (m *ReputationMonitor) RecordEvent(ctx context.Context, domain string, eventType models.DeliverabilityEventType) error {
+	if m == nil || domain == "" {
+		return nil
+	}
+	var deltas models.SendingDomainDailyStats
+	switch eventType {
+	case models.EventDelivered:
+		deltas.Delivered = 1
+	case models.EventBounce:
+		deltas.Bounced = 1
+	case models.EventComplaint:
+		deltas.Complaints = 1
+	default:
+		return fmt.Errorf("unknown deliverability event type: %s", eventType)
+	}
+	return m.repo.IncrementStats(ctx, domain, startOfDay(time.Now()), deltas)
+}
+
+// Evaluate recomputes reputation for every domain, advances warm-ups and refreshes the in-memory view
+func (m *ReputationMonitor) Evaluate(ctx context.Context) {
+	domains, err := m.repo.ListAllDomains(ctx)
+	if err != nil {
+		log.Printf("[REPUTATION] Failed to list sending domains: %v", err)
+		return
+	}
+
+	now := time.Now()
+	since := startOfDay(now).AddDate(0, 0, -(m.config.WindowDays - 1))
+	avgBulk := make(map[string]int64, len(domains))
+
+	for i := range domains {
+		d := &domains[i]
+		stats, err := m.repo.GetStatsSince(ctx, d.Domain, since)
+		if err != nil {
+			log.Printf("[REPUTATION] Failed to load stats for %s: %v", d.Domain, err)
+			continue
+		}
+
+		var sent, bulkSent, bounced, complaints int64
+		for _, s := range stats {
+			sent += s.Sent
+			bulkSent += s.BulkSent
+			bounced += s.Bounced
+			complaints += s.Complaints
+		}
+		avgBulk[d.Domain] = bulkSent / int64(m.config.WindowDays)
+
+		d.SampleSize = sent
+		d.BounceRate, d.ComplaintRate = 0, 0
+		if sent > 0 {
+			d.BounceRate = float64(bounced) / float64(sent)
+			d.ComplaintRate = float64(complaints) / float64(sent)
+		}
+
+		level := m.assess(d)
+		if level != d.Reputation {
+			log.Printf("[REPUTATION] %s reputation %s -> %s (bounce=%.2f%%, complaints=%.3f%%, sample=%d)",
+				d.Domain, d.Reputation, level, d.BounceRate*100, d.ComplaintRate*100, sent)
+			d.Reputation = level
+			d.ReputationSince = &now
+		}
+		d.EvaluatedAt = &now
+
+		m.advanceWarmup(d, now)
+
+		if err := m.repo.UpdateDomain(ctx, d); err != nil {
+			log.Printf("[REPUTATION] Failed to save evaluation for %s: %v", d.Domain, err)
+		}
+	}
+
+	m.mu.Lock()
+	m.domains = make(map[string]*models.SendingDomain, len(domains))
+	m.byTenant = make(map[string]string, len(domains))
+	for i := range domains {
+		d := domains[i]
+		m.domains[d.Domain] = &d
+		if d.TenantID != models.PlatformTenantID {
+			m.byTenant[d.TenantID] = d.Domain
+		}
+	}
+	m.avgBulk = avgBulk
+	m.mu.Unlock()
+}
+
+// assess maps a domain's rates to a reputation level; small samples are not acted upon
+func (m *ReputationMonitor) assess(d *models.SendingDomain) models.ReputationLevel {
+	if d.SampleSize < m.config.MinSampleSize {
+		return models.ReputationGood
+	}
+	if d.BounceRate >= m.config.BounceRatePoor || d.ComplaintRate >= m.config.ComplaintRatePoor {
+		return models.ReputationPoor
+	}
+	if d.BounceRate >= m.config.BounceRateDegraded || d.ComplaintRate >= m.config.ComplaintRateDegraded {
+		return models.ReputationDegraded
+	}
+	return models.ReputationGood
+}
+
+// advanceWarmup moves a warming domain one step per day while its reputation is good,
+// holds it while degraded and steps it back while poor
+func (m *ReputationMonitor) advanceWarmup(d *models.SendingDomain, now time.Time) {
+	if d.Status != models.SendingDomainWarming {
+		return
+	}
+	if d.WarmupAdvanced != nil && now.Sub(*d.WarmupAdvanced) < 24*time.Hour {
+		return
+	}
+
+	switch d.Reputation {
+	case models.ReputationGood:
+		d.WarmupDay++
+	case models.ReputationPoor:
+		if d.WarmupDay > 0 {
+			d.WarmupDay--
+		}
+	default:
+		return
+	}
+	d.WarmupAdvanced = &now
+
+	if d.WarmupDay >= len(m.config.WarmupSchedule) {
+		d.Status = models.SendingDomainActive
+		d.DailyLimit = 0
+		log.Printf("[REPUTATION] %s completed warm-up", d.Domain)
+		return
+	}
+	d.DailyLimit = m.config.WarmupSchedule[d.WarmupDay]
+}
+
+// effectiveLimitLocked returns today's bulk cap after throttling; caller holds m.mu
+func (m *ReputationMonitor) effectiveLimitLocked(d *models.SendingDomain) int {
+	limit := d.DailyLimit
+	if d.Reputation != models.ReputationDegraded {
+		return limit
+	}
+
+	// Throttle relative to the warm-up cap, or to recent volume once warm-up is complete
+	base := limit
+	if base == 0 {
+		base = int(m.avgBulk[d.Domain])
+	}
+	throttled := int(float64(base) * m.config.ThrottleFactor)
+	if throttled < m.config.WarmupSchedule[0] {
+		throttled = m.config.WarmupSchedule[0]
+	}
+	return throttled
+}
+
+func (m *ReputationMonitor) bulkSentToday(ctx context.Context, domain string) (int64, error) {
+	stats, err := m.repo.GetStatsSince(ctx, domain, startOfDay(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+	var sent int64
+	for _, s := range stats {
+		sent += s.BulkSent
+	}
+	return sent, nil
+}
+
+// RegisterDomain adds a tenant sending domain and starts its warm-up
+func (m *ReputationMonitor) RegisterDomain(ctx context.Context, tenantID string, req *RegisterSendingDomainRequest) (*models.SendingDomain, error) {
+	domain := strings.ToLower(strings.TrimSpace(req.Domain))
+	if !sendingDomainPattern.MatchString(domain) {
+		return nil, fmt.Errorf("%w: %q is not a valid domain name", ErrInvalidSendingDomain, req.Domain)
+	}
+	fromAddress := strings.ToLower(strings.TrimSpace(req.FromAddress))
+	if !strings.HasSuffix(fromAddress, "@"+domain) {
+		return nil, fmt.Errorf("%w: fromAddress must be an address at %s", ErrInvalidSendingDomain, domain)
+	}
+
+	existing, err := m.repo.ListDomains(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, ErrSendingDomainExists
+	}
+	if _, err := m.repo.GetDomain(ctx, domain); err == nil {
+		return nil, ErrSendingDomainTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	d := &models.SendingDomain{
+		TenantID:        tenantID,
+		Domain:          domain,
+		FromAddress:     fromAddress,
+		FromName:        req.FromName,
+		Status:          models.SendingDomainWarming,
+		WarmupDay:       0,
+		WarmupStartedAt: &now,
+		WarmupAdvanced:  &now,
+		DailyLimit:      m.config.WarmupSchedule[0],
+		Reputation:      models.ReputationGood,
+	}
+	if err := m.repo.CreateDomain(ctx, d); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	cached := *d
+	m.domains[d.Domain] = &cached
+	m.byTenant[tenantID] = d.Domain
+	m.mu.Unlock()
+
+	log.Printf("[REPUTATION] Tenant %s registered sending domain %s (warm-up limit %d/day)", tenantID, domain, d.DailyLimit)
+	return d, nil
+}
+
+// ListDomains returns the tenant's sending domains; the platform tenant ID lists every domain
+func (m *ReputationMonitor) ListDomains(ctx context.Context, tenantID string) ([]SendingDomainView, error) {
+	var domains []models.SendingDomain
+	var err error
+	if tenantID == models.PlatformTenantID {
+		domains, err = m.repo.ListAllDomains(ctx)
+	} else {
+		domains, err = m.repo.ListDomains(ctx, tenantID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]SendingDomainView, 0, len(domains))
+	for _, d := range domains {
+		view, err := m.view(ctx, d, false)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, *view)
+	}
+	return views, nil
+}
+
+// GetDomain returns a tenant's sending domain with its daily stats for the evaluation window
+func (m *ReputationMonitor) GetDomain(ctx context.Context, tenantID string, id uuid.UUID) (*SendingDomainView, error) {
+	d, err := m.repo.GetDomainByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return m.view(ctx, *d, true)
+}
+
+// DeleteDomain removes a tenant's sending domain; its emails revert to the platform domain
+func (m *ReputationMonitor) DeleteDomain(ctx context.Context, tenantID string, id uuid.UUID) error {
+	d, err := m.repo.GetDomainByID(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err := m.repo.DeleteDomain(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	delete(m.domains, d.Domain)
+	if m.byTenant[tenantID] == d.Domain {
+		delete(m.byTenant, tenantID)
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *ReputationMonitor) view(ctx context.Context, d models.SendingDomain, withStats bool) (*SendingDomainView, error) {
+	since := startOfDay(time.Now()).AddDate(0, 0, -(m.config.WindowDays - 1))
+	stats, err := m.repo.GetStatsSince(ctx, d.Domain, since)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &SendingDomainView{SendingDomain: d}
+	today := startOfDay(time.Now())
+	for _, s := range stats {
+		if !s.Day.Before(today) {
+			view.BulkSentToday += s.BulkSent
+		}
+	}
+	if withStats {
+		view.Stats = stats
+	}
+
+	m.mu.RLock()
+	view.EffectiveDailyLimit = m.effectiveLimitLocked(&d)
+	m.mu.RUnlock()
+	return view, nil
+}
+
+// IsBulkSend reports whether an email is a bulk (marketing) send subject to reputation throttling
+func IsBulkSend(metadata map[string]interface{}) bool {
+	if metadata == nil {
+		return false
+	}
+	if bulk, ok := metadata["bulk"].(bool); ok && bulk {
+		return true
+	}
+	category, _ := metadata["category"].(string)
+	return strings.EqualFold(category, "marketing")
+}
+
+// DomainOf returns the lower-cased domain part of an email address
+func DomainOf(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		address = address[i+1:]
+	}
+	return strings.ToLower(strings.Trim(strings.TrimSpace(address), ">"))
+}
+
+// startOfDay truncates t to midnight UTC, the boundary for daily stats
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
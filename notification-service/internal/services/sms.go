@@ -0,0 +1,196 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// SMS body encodings
+const (
+	SMSEncodingGSM7 = "GSM-7"
+	SMSEncodingUCS2 = "UCS-2"
+)
+
+// DefaultSMSMaxSegments is the longest SMS a tenant may send unless a template allows more
+const DefaultSMSMaxSegments = 3
+
+// gsm7Basic is the GSM 03.38 default alphabet; gsm7Extended characters take two septets
+const (
+	gsm7Basic    = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extended = "^{}\\[~]|€\f"
+)
+
+// SMSAnalysis describes how an SMS body is encoded and how many segments it is billed as
+type SMSAnalysis struct {
+	Encoding         string   `json:"encoding"`
+	Length           int      `json:"length"`                     // Septets for GSM-7, UTF-16 code units for UCS-2
+	Segments         int      `json:"segments"`                   // 0 for an empty body
+	PerSegment       int      `json:"perSegment"`                 // Capacity of each segment: 160/153 (GSM-7) or 70/67 (UCS-2)
+	Remaining        int      `json:"remaining"`                  // Characters left before another segment is needed
+	NonGSMCharacters []string `json:"nonGsmCharacters,omitempty"` // Characters forcing UCS-2
+}
+
+// AnalyzeSMS detects the encoding of an SMS body and counts its segments
+func AnalyzeSMS(body string) SMSAnalysis {
+	septets := 0
+	var nonGSM []string
+	seen := make(map[rune]bool)
+	for _, r := range body {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			septets++
+		case strings.ContainsRune(gsm7Extended, r):
+			septets += 2
+		default:
+			if !seen[r] {
+				seen[r] = true
+				nonGSM = append(nonGSM, string(r))
+			}
+		}
+	}
+
+	analysis := SMSAnalysis{Encoding: SMSEncodingGSM7, Length: septets}
+	single, multi := 160, 153
+	if len(nonGSM) > 0 {
+		analysis.Encoding = SMSEncodingUCS2
+		analysis.Length = len(utf16.Encode([]rune(body)))
+		analysis.NonGSMCharacters = nonGSM
+		single, multi = 70, 67
+	}
+
+	switch {
+	case analysis.Length == 0:
+		analysis.PerSegment = single
+	case analysis.Length <= single:
+		analysis.Segments = 1
+		analysis.PerSegment = single
+	default:
+		analysis.Segments = (analysis.Length + multi - 1) / multi
+		analysis.PerSegment = multi
+	}
+	analysis.Remaining = analysis.PerSegment*max(analysis.Segments, 1) - analysis.Length
+	return analysis
+}
+
+// SMSSegments returns the number of SMS segments a message body is billed as:
+// 160/153 septets per segment for GSM-7, 70/67 UTF-16 code units otherwise
+func SMSSegments(body string) int {
+	return max(AnalyzeSMS(body).Segments, 1)
+}
+
+// SMSEstimate is the encoding, segment count and estimated provider cost of an SMS
+type SMSEstimate struct {
+	SMSAnalysis
+	Provider      string   `json:"provider"`
+	UnitCost      float64  `json:"unitCost"` // Cost per segment
+	EstimatedCost float64  `json:"estimatedCost"`
+	Currency      string   `json:"currency"`
+	MaxSegments   int      `json:"maxSegments"`
+	SenderID      string   `json:"senderId,omitempty"`
+	Warnings      []string `json:"warnings,omitempty"`
+}
+
+// ExceedsMaxSegments reports whether the message is longer than its segment limit
+func (e *SMSEstimate) ExceedsMaxSegments() bool {
+	return e.MaxSegments > 0 && e.Segments > e.MaxSegments
+}
+
+// SMSPolicy holds the sender IDs and segment limit applied to outgoing SMS
+type SMSPolicy struct {
+	// SenderIDs maps a country calling code ("+44") to the alphanumeric sender ID or
+	// number used for recipients in that country; other countries use the provider default
+	SenderIDs map[string]string
+	// MaxSegments is the longest SMS allowed unless a template sets its own limit
+	MaxSegments int
+}
+
+// ParseSMSSenderIDs parses a JSON object of country calling code -> sender ID,
+// e.g. {"+44":"Tesseract","+91":"TSRHUB","+1":"+15551234567"}
+func ParseSMSSenderIDs(raw string) (map[string]string, error) {
+	senderIDs := make(map[string]string)
+	if strings.TrimSpace(raw) == "" {
+		return senderIDs, nil
+	}
+
+	var parsed map[string]string
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid SMS sender IDs: %w", err)
+	}
+	for code, senderID := range parsed {
+		code = "+" + strings.TrimLeft(strings.TrimSpace(code), "+")
+		if len(code) < 2 || len(code) > 5 || strings.Trim(code[1:], "0123456789") != "" {
+			return nil, fmt.Errorf("invalid SMS sender IDs: %q is not a country calling code", code)
+		}
+		senderID = strings.TrimSpace(senderID)
+		if !isValidSenderID(senderID) {
+			return nil, fmt.Errorf("invalid SMS sender IDs: %q for %s must be a phone number or 1-11 letters and digits", senderID, code)
+		}
+		senderIDs[code] = senderID
+	}
+	return senderIDs, nil
+}
+
+// isValidSenderID accepts E.164 numbers and alphanumeric sender IDs (up to 11 characters, at least one letter)
+func isValidSenderID(senderID string) bool {
+	if strings.HasPrefix(senderID, "+") {
+		digits := senderID[1:]
+		return len(digits) >= 7 && len(digits) <= 15 && strings.Trim(digits, "0123456789") == ""
+	}
+	if senderID == "" || len(senderID) > 11 {
+		return false
+	}
+	hasLetter := false
+	for _, r := range senderID {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+			hasLetter = true
+		case r >= '0' && r <= '9', r == ' ':
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
+
+// SenderID returns the sender ID configured for the recipient's country, matching the
+// longest calling code prefix, or "" to use the provider default
+func (p *SMSPolicy) SenderID(phone string) string {
+	if p == nil || len(p.SenderIDs) == 0 {
+		return ""
+	}
+	phone = normalizePhone(phone)
+	for n := min(len(phone), 5); n >= 2; n-- {
+		if senderID, ok := p.SenderIDs[phone[:n]]; ok {
+			return senderID
+		}
+	}
+	return ""
+}
+
+// MaxSegmentsFor returns the segment limit of a template, falling back to the policy default
+func (p *SMSPolicy) MaxSegmentsFor(templateMax int) int {
+	if templateMax > 0 {
+		return templateMax
+	}
+	if p == nil || p.MaxSegments <= 0 {
+		return DefaultSMSMaxSegments
+	}
+	return p.MaxSegments
+}
+
+// normalizePhone strips formatting from a phone number and converts a 00 prefix to +
+func normalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(phone) {
+		if r == '+' || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	normalized := b.String()
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
+	}
+	return normalized
+}
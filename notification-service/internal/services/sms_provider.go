@@ -35,7 +35,11 @@ func (p *TwilioProvider) Send(ctx context.Context, message *Message) (*SendResul
 	// Prepare form data
 	data := url.Values{}
 	data.Set("To", message.To)
-	data.Set("From", p.from)
+	from := p.from
+	if message.From != "" {
+		from = message.From // Country-specific sender ID
+	}
+	data.Set("From", from)
 	data.Set("Body", message.Body)
 
 	// Create request
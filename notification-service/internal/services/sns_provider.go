@@ -74,11 +74,16 @@ func (p *SNSProvider) Send(ctx context.Context, message *Message) (*SendResult,
 		StringValue: aws.String(smsType),
 	}
 
-	// Set sender ID if configured (not supported in all regions/countries)
-	if p.from != "" {
+	// Set sender ID if configured (not supported in all regions/countries);
+	// a country-specific sender ID on the message takes precedence
+	from := p.from
+	if message.From != "" {
+		from = message.From
+	}
+	if from != "" {
 		input.MessageAttributes["AWS.SNS.SMS.SenderID"] = types.MessageAttributeValue{
 			DataType:    aws.String("String"),
-			StringValue: aws.String(p.from),
+			StringValue: aws.String(from),
 		}
 	}
 
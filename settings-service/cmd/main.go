@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
@@ -21,12 +22,14 @@ import (
 
 	"settings-service/internal/cache"
 	"settings-service/internal/clients/frankfurter"
+	"settings-service/internal/clients/paymentgateway"
 	"settings-service/internal/config"
 	"settings-service/internal/events"
 	"settings-service/internal/handlers"
 	"settings-service/internal/health"
 	"settings-service/internal/middleware"
 	"settings-service/internal/models"
+	"settings-service/internal/redisconn"
 	"settings-service/internal/repository"
 	"settings-service/internal/services"
 	"settings-service/internal/workers"
@@ -92,28 +95,46 @@ func main() {
 	}()
 
 	// Initialize Redis client (graceful degradation if unavailable)
-	var redisClient *redis.Client
-	if cfg.Redis.URL != "" {
-		opt, err := redis.ParseURL(cfg.Redis.URL)
+	// redisClient stays a nil interface without Redis, so the caches see it as disabled
+	var redisConn *redisconn.Client
+	var redisClient redis.UniversalClient
+	if cfg.Redis.Enabled() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := redisconn.Connect(ctx, cfg.Redis, "settings-service")
 		if err != nil {
-			log.Printf("WARNING: Failed to parse Redis URL: %v (caching disabled)", err)
+			log.Printf("WARNING: %v (caching disabled)", err)
 		} else {
-			redisClient = redis.NewClient(opt)
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			if err := redisClient.Ping(ctx).Err(); err != nil {
-				log.Printf("WARNING: Failed to connect to Redis: %v (caching disabled)", err)
-				redisClient = nil
-			} else {
-				log.Println("✓ Redis connection established")
-			}
+			redisConn, redisClient = conn, conn
+			log.Printf("✓ Redis connection established (%s mode)", redisConn.Mode())
 		}
 	}
 
+	// Initialize the settings/theme read cache (write-through, invalidated by version on settings.* events)
+	var settingsCacheClient redis.UniversalClient
+	if cfg.Cache.Enabled {
+		settingsCacheClient = redisClient
+	}
+	settingsCache := cache.NewSettingsCache(settingsCacheClient, time.Duration(cfg.Cache.TTLSeconds)*time.Second)
+	if settingsCache.Enabled() {
+		go func() {
+			invalidate := func(ctx context.Context, tenantID, category, settingKey string) {
+				services.InvalidateFromEvent(settingsCache, tenantID, category, settingKey)
+			}
+			if _, err := events.SubscribeSettingsChanges(context.Background(), eventLogger, invalidate); err != nil {
+				log.Printf("WARNING: Failed to subscribe to settings events: %v (cache is invalidated by local writes only)", err)
+			}
+		}()
+		log.Println("✓ Settings read cache enabled")
+	}
+
 	// Initialize dependencies
 	settingsRepo := repository.NewSettingsRepository(db)
-	settingsService := services.NewSettingsService(settingsRepo)
+	settingsValidationWebhookRepo := repository.NewSettingsValidationWebhookRepository(db)
+	settingsValidationWebhookService := services.NewSettingsValidationWebhookService(settingsValidationWebhookRepo)
+	settingsService := services.NewCachedSettingsService(services.NewSettingsService(settingsRepo, settingsValidationWebhookService), settingsCache, events.SettingsEvents{})
 	settingsHandler := handlers.NewSettingsHandler(settingsService)
+	settingsValidationWebhookHandler := handlers.NewSettingsValidationWebhookHandler(settingsValidationWebhookService)
 
 	// Initialize tenant dependencies (for audit config)
 	// TenantHandler calls tenant-service via HTTP to get tenant info
@@ -121,7 +142,7 @@ func main() {
 
 	// Initialize storefront theme dependencies
 	storefrontThemeRepo := repository.NewStorefrontThemeRepository(db)
-	storefrontThemeService := services.NewStorefrontThemeService(storefrontThemeRepo)
+	storefrontThemeService := services.NewCachedStorefrontThemeService(services.NewStorefrontThemeService(storefrontThemeRepo), settingsCache, events.SettingsEvents{})
 	storefrontThemeHandler := handlers.NewStorefrontThemeHandler(storefrontThemeService)
 
 	// Initialize currency dependencies with Redis caching
@@ -132,11 +153,60 @@ func main() {
 	rateUpdater := workers.NewRateUpdater(currencyService, workers.DefaultUpdateInterval)
 	currencyHandler := handlers.NewCurrencyHandler(currencyService, rateUpdater)
 
+	// Initialize payment gateway settings (credentials encrypted at rest)
+	credentialCipher, err := services.NewCredentialCipher(cfg.Payments.CredentialsEncryptionKey)
+	if err != nil {
+		log.Fatal("Failed to initialize payment credential cipher:", err)
+	}
+	if !credentialCipher.Enabled() {
+		log.Println("WARNING: PAYMENT_CREDENTIALS_ENCRYPTION_KEY not set (payment gateway credentials cannot be saved)")
+	}
+	paymentSettingsRepo := repository.NewPaymentSettingsRepository(db)
+	paymentSettingsService := services.NewPaymentSettingsService(paymentSettingsRepo, credentialCipher, paymentgateway.NewDefaultClient(), events.SettingsEvents{})
+	paymentSettingsHandler := handlers.NewPaymentSettingsHandler(paymentSettingsService)
+
+	// Initialize notification routing settings (event type -> channels and recipients)
+	notificationRoutingRepo := repository.NewNotificationRoutingRepository(db)
+	notificationRoutingService := services.NewNotificationRoutingService(notificationRoutingRepo, events.SettingsEvents{})
+	notificationRoutingHandler := handlers.NewNotificationRoutingHandler(notificationRoutingService)
+
+	// Initialize scheduled settings/theme changes (applied by a background runner)
+	scheduledChangeRepo := repository.NewScheduledChangeRepository(db)
+	scheduledChangeService := services.NewScheduledChangeService(scheduledChangeRepo, settingsService, storefrontThemeService)
+	scheduledChangeRunner := workers.NewScheduledChangeRunner(scheduledChangeService, workers.DefaultScheduledChangeInterval)
+	scheduledChangeHandler := handlers.NewScheduledChangeHandler(scheduledChangeService)
+
+	// Initialize named settings/theme snapshots (restored atomically, cache invalidated afterwards)
+	settingsSnapshotRepo := repository.NewSettingsSnapshotRepository(db)
+	settingsSnapshotService := services.NewSettingsSnapshotService(settingsSnapshotRepo, settingsCache, events.SettingsEvents{})
+	settingsSnapshotHandler := handlers.NewSettingsSnapshotHandler(settingsSnapshotService)
+
+	// Purge deleted tenants when tenant-service's deletion saga asks (non-blocking)
+	tenantPurgeService := services.NewTenantPurgeService(repository.NewTenantDataRepository(db), settingsCache)
+	go func() {
+		purge := func(ctx context.Context, tenantID string) (int64, error) {
+			id, err := uuid.Parse(tenantID)
+			if err != nil {
+				return 0, fmt.Errorf("invalid tenant ID %q", tenantID)
+			}
+			return tenantPurgeService.PurgeTenant(id)
+		}
+		if _, err := events.SubscribeTenantDeletionRequested(context.Background(), eventLogger, purge); err != nil {
+			log.Printf("WARNING: Failed to subscribe to tenant deletion requests: %v (deleted tenants won't be purged)", err)
+		}
+	}()
+
 	// Start the rate updater
 	rateUpdater.Start()
 
+	// Start the scheduled change runner
+	scheduledChangeRunner.Start()
+
 	// Initialize health checker
 	healthChecker := health.NewHealthChecker(db, cfg.App.Version)
+	if redisConn != nil {
+		healthChecker.SetRedis(redisConn)
+	}
 
 	// Initialize RBAC middleware
 	staffServiceURL := os.Getenv("STAFF_SERVICE_URL")
@@ -147,7 +217,7 @@ func main() {
 	log.Println("✓ RBAC middleware initialized")
 
 	// Initialize Gin router
-	router := setupRouter(settingsHandler, storefrontThemeHandler, currencyHandler, tenantHandler, healthChecker, rbacMiddleware, cfg, eventLogger, redisClient)
+	router := setupRouter(settingsHandler, storefrontThemeHandler, currencyHandler, paymentSettingsHandler, notificationRoutingHandler, scheduledChangeHandler, settingsSnapshotHandler, settingsValidationWebhookHandler, tenantHandler, healthChecker, rbacMiddleware, cfg, eventLogger, redisConn)
 
 	// Mark service as ready
 	healthChecker.SetReady(true)
@@ -167,6 +237,10 @@ func main() {
 		<-sigChan
 		log.Println("Shutting down...")
 		rateUpdater.Stop()
+		scheduledChangeRunner.Stop()
+		if redisConn != nil {
+			redisConn.Close()
+		}
 		os.Exit(0)
 	}()
 
@@ -215,6 +289,16 @@ func runMigrations(db *gorm.DB) error {
 		&models.StorefrontThemeHistory{},
 		// Currency models
 		&models.ExchangeRate{},
+		// Payment settings models
+		&models.PaymentGatewayConfig{},
+		// Notification routing models
+		&models.NotificationRoute{},
+		// Scheduled change models
+		&models.ScheduledSettingsChange{},
+		// Settings snapshot models
+		&models.SettingsSnapshot{},
+		// Settings validation webhook models
+		&models.SettingsValidationWebhook{},
 	); err != nil {
 		log.Printf("⚠️  AutoMigrate warning: %v", err)
 		// Don't fail - the table may already exist with slightly different schema
@@ -225,7 +309,7 @@ func runMigrations(db *gorm.DB) error {
 }
 
 // setupRouter configures the Gin router with middleware and routes
-func setupRouter(settingsHandler *handlers.SettingsHandler, storefrontThemeHandler *handlers.StorefrontThemeHandler, currencyHandler *handlers.CurrencyHandler, tenantHandler *handlers.TenantHandler, healthChecker *health.HealthChecker, rbacMiddleware *rbac.Middleware, cfg *config.Config, logger *logrus.Logger, redisClient *redis.Client) *gin.Engine {
+func setupRouter(settingsHandler *handlers.SettingsHandler, storefrontThemeHandler *handlers.StorefrontThemeHandler, currencyHandler *handlers.CurrencyHandler, paymentSettingsHandler *handlers.PaymentSettingsHandler, notificationRoutingHandler *handlers.NotificationRoutingHandler, scheduledChangeHandler *handlers.ScheduledChangeHandler, settingsSnapshotHandler *handlers.SettingsSnapshotHandler, settingsValidationWebhookHandler *handlers.SettingsValidationWebhookHandler, tenantHandler *handlers.TenantHandler, healthChecker *health.HealthChecker, rbacMiddleware *rbac.Middleware, cfg *config.Config, logger *logrus.Logger, redisConn *redisconn.Client) *gin.Engine {
 	router := gin.New()
 
 	// Global middleware
@@ -248,7 +332,17 @@ func setupRouter(settingsHandler *handlers.SettingsHandler, storefrontThemeHandl
 		"/api/v1/public/storefront-theme", // Public theme endpoints
 	}
 
-	if redisClient != nil {
+	// The shared Redis rate limiter needs a single-node client; a cluster uses the in-memory limiter
+	var rateLimitClient *redis.Client
+	if redisConn != nil {
+		if single, ok := redisConn.Single(); ok {
+			rateLimitClient = single
+		} else {
+			log.Println("WARNING: Redis rate limiting is not available in cluster mode")
+		}
+	}
+
+	if rateLimitClient != nil {
 		rateLimitConfig := gosharedmw.RedisRateLimitConfig{
 			RequestsPerSecond: 100,
 			BurstSize:         200,
@@ -259,7 +353,7 @@ func setupRouter(settingsHandler *handlers.SettingsHandler, storefrontThemeHandl
 			ByIP:              true,
 			ByUser:            false,
 		}
-		router.Use(gosharedmw.RedisRateLimitMiddlewareWithConfig(redisClient, rateLimitConfig))
+		router.Use(gosharedmw.RedisRateLimitMiddlewareWithConfig(rateLimitClient, rateLimitConfig))
 		log.Println("✓ Redis-based rate limiting enabled (storefront-theme excluded)")
 	} else {
 		// Use custom in-memory rate limit config with same exclusions as Redis config
@@ -295,6 +389,23 @@ func setupRouter(settingsHandler *handlers.SettingsHandler, storefrontThemeHandl
 		// Tenant audit config - used by audit-service to get tenant database config
 		internalV1.GET("/tenants/:id/audit-config", tenantHandler.GetAuditConfig)
 		internalV1.GET("/tenants/audit-enabled", tenantHandler.ListAuditEnabledTenants)
+		// Notification routing - used by notification-service and notification-hub to pick channels and recipients
+		internalV1.GET("/tenants/:id/notification-routing/resolve", notificationRoutingHandler.ResolveInternal)
+		// Platform-wide settings validation webhooks - registered by product services to validate their settings
+		internalV1.GET("/platform/settings-validation-webhooks", settingsValidationWebhookHandler.ListWebhooks)
+		internalV1.POST("/platform/settings-validation-webhooks", settingsValidationWebhookHandler.CreateWebhook)
+		internalV1.GET("/platform/settings-validation-webhooks/:id", settingsValidationWebhookHandler.GetWebhook)
+		internalV1.PUT("/platform/settings-validation-webhooks/:id", settingsValidationWebhookHandler.UpdateWebhook)
+		internalV1.DELETE("/platform/settings-validation-webhooks/:id", settingsValidationWebhookHandler.DeleteWebhook)
+		internalV1.POST("/platform/settings-validation-webhooks/:id/rotate-secret", settingsValidationWebhookHandler.RotateSecret)
+	}
+
+	// Payment gateway credentials - decrypted secrets, so the caller must present the payment
+	// service's Istio mTLS identity; the X-Internal-Service header alone is not enough
+	paymentCredentialsV1 := router.Group("/api/v1")
+	paymentCredentialsV1.Use(middleware.ServiceIdentityMiddleware(cfg.Payments.CredentialCallers))
+	{
+		paymentCredentialsV1.GET("/tenants/:id/payment-gateways", paymentSettingsHandler.GetInternalCredentials)
 	}
 
 	// ========================================
@@ -343,6 +454,7 @@ func setupRouter(settingsHandler *handlers.SettingsHandler, storefrontThemeHandl
 			settings.GET("/", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), settingsHandler.ListSettings)
 			settings.GET("/context", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), settingsHandler.GetSettingsByContext)
 			settings.GET("/inherited", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), settingsHandler.GetInheritedSettings)
+			settings.GET("/explain", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), settingsHandler.ExplainSetting)
 			settings.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), settingsHandler.GetSettings)
 			settings.PUT("/:id", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), settingsHandler.UpdateSettings)
 			settings.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), settingsHandler.DeleteSettings)
@@ -383,6 +495,58 @@ func setupRouter(settingsHandler *handlers.SettingsHandler, storefrontThemeHandl
 			currency.POST("/refresh", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), currencyHandler.RefreshRates)
 			currency.GET("/status", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), currencyHandler.GetUpdaterStatus)
 		}
+
+		// Payment settings endpoints with RBAC
+		paymentSettings := v1.Group("/payment-settings")
+		{
+			paymentSettings.GET("/schemas", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), paymentSettingsHandler.GetSchemas)
+			paymentSettings.GET("/gateways", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), paymentSettingsHandler.ListGateways)
+			paymentSettings.GET("/gateways/:provider", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), paymentSettingsHandler.GetGateway)
+			paymentSettings.PUT("/gateways/:provider", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), paymentSettingsHandler.UpsertGateway)
+			paymentSettings.DELETE("/gateways/:provider", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), paymentSettingsHandler.DeleteGateway)
+			paymentSettings.POST("/gateways/:provider/test", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), paymentSettingsHandler.TestConnection)
+		}
+
+		// Notification routing endpoints with RBAC
+		notificationRouting := v1.Group("/notification-routing")
+		{
+			notificationRouting.GET("/event-types", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), notificationRoutingHandler.GetEventTypes)
+			notificationRouting.GET("/routes", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), notificationRoutingHandler.ListRoutes)
+			notificationRouting.GET("/routes/:eventType", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), notificationRoutingHandler.GetRoute)
+			notificationRouting.PUT("/routes/:eventType", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), notificationRoutingHandler.UpsertRoute)
+			notificationRouting.DELETE("/routes/:eventType", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), notificationRoutingHandler.DeleteRoute)
+		}
+
+		// Scheduled settings/theme changes with RBAC
+		scheduledChanges := v1.Group("/scheduled-changes")
+		{
+			scheduledChanges.POST("", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), scheduledChangeHandler.ScheduleChange)
+			scheduledChanges.GET("", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), scheduledChangeHandler.ListScheduledChanges)
+			scheduledChanges.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), scheduledChangeHandler.GetScheduledChange)
+			scheduledChanges.POST("/:id/cancel", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), scheduledChangeHandler.CancelScheduledChange)
+		}
+
+		// Settings/theme snapshots with RBAC
+		settingsSnapshots := v1.Group("/settings-snapshots")
+		{
+			settingsSnapshots.POST("", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), settingsSnapshotHandler.CreateSnapshot)
+			settingsSnapshots.GET("", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), settingsSnapshotHandler.ListSnapshots)
+			settingsSnapshots.GET("/diff", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), settingsSnapshotHandler.DiffSnapshots)
+			settingsSnapshots.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), settingsSnapshotHandler.GetSnapshot)
+			settingsSnapshots.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), settingsSnapshotHandler.DeleteSnapshot)
+			settingsSnapshots.POST("/:id/restore", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), settingsSnapshotHandler.RestoreSnapshot)
+		}
+
+		// Settings validation webhooks with RBAC
+		settingsValidationWebhooks := v1.Group("/settings-validation-webhooks")
+		{
+			settingsValidationWebhooks.GET("", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), settingsValidationWebhookHandler.ListWebhooks)
+			settingsValidationWebhooks.POST("", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), settingsValidationWebhookHandler.CreateWebhook)
+			settingsValidationWebhooks.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionSettingsRead), settingsValidationWebhookHandler.GetWebhook)
+			settingsValidationWebhooks.PUT("/:id", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), settingsValidationWebhookHandler.UpdateWebhook)
+			settingsValidationWebhooks.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), settingsValidationWebhookHandler.DeleteWebhook)
+			settingsValidationWebhooks.POST("/:id/rotate-secret", rbacMiddleware.RequirePermission(rbac.PermissionSettingsUpdate), settingsValidationWebhookHandler.RotateSecret)
+		}
 		// Note: Tenant audit config endpoints are registered above in the internal service group
 		// to allow service-to-service calls without user authentication
 	}
@@ -46,14 +46,14 @@ type CurrencyCache struct {
 	l1Cache sync.Map
 
 	// L2 cache (Redis) - optional
-	redisClient *redis.Client
+	redisClient redis.UniversalClient
 
 	// Whether Redis is available
 	redisEnabled bool
 }
 
 // NewCurrencyCache creates a new currency cache
-func NewCurrencyCache(redisClient *redis.Client) *CurrencyCache {
+func NewCurrencyCache(redisClient redis.UniversalClient) *CurrencyCache {
 	cache := &CurrencyCache{
 		redisClient:  redisClient,
 		redisEnabled: redisClient != nil,
@@ -168,14 +168,45 @@ func (c *CurrencyCache) InvalidateAll() {
 	// Clear L2 cache
 	if c.redisEnabled {
 		ctx := context.Background()
-		keys, err := c.redisClient.Keys(ctx, ExchangeRateKeyPrefix+"*").Result()
-		if err == nil && len(keys) > 0 {
-			c.redisClient.Del(ctx, keys...)
+		keys := append(c.rateKeys(ctx), AllRatesKey, SupportedCurrenciesKey)
+		// One DEL per key: a multi-key DEL fails in a cluster when the keys hash to different slots
+		pipe := c.redisClient.Pipeline()
+		for _, key := range keys {
+			pipe.Del(ctx, key)
 		}
-		c.redisClient.Del(ctx, AllRatesKey, SupportedCurrenciesKey)
+		pipe.Exec(ctx)
 	}
 }
 
+// shardedClient is implemented by redisconn.Client, which reaches every master of a cluster
+type shardedClient interface {
+	ForEachShard(ctx context.Context, fn func(ctx context.Context, shard *redis.Client) error) error
+}
+
+// rateKeys lists the cached exchange rate keys, scanning every cluster master in cluster mode
+func (c *CurrencyCache) rateKeys(ctx context.Context) []string {
+	var mu sync.Mutex
+	var keys []string
+	scan := func(ctx context.Context, client redis.Cmdable) error {
+		iter := client.Scan(ctx, 0, ExchangeRateKeyPrefix+"*", 100).Iterator()
+		for iter.Next(ctx) {
+			mu.Lock()
+			keys = append(keys, iter.Val())
+			mu.Unlock()
+		}
+		return iter.Err()
+	}
+
+	if sharded, ok := c.redisClient.(shardedClient); ok {
+		sharded.ForEachShard(ctx, func(ctx context.Context, shard *redis.Client) error {
+			return scan(ctx, shard)
+		})
+	} else {
+		scan(ctx, c.redisClient)
+	}
+	return keys
+}
+
 // setL1Cache sets a value in the L1 cache
 func (c *CurrencyCache) setL1Cache(key string, data interface{}) {
 	c.l1Cache.Store(key, L1CacheEntry{
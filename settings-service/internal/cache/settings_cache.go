@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"settings-service/internal/health"
+)
+
+const (
+	// Default TTL for cached settings and themes; invalidation normally happens long before
+	DefaultSettingsCacheTTL = 1 * time.Hour
+
+	// Redis key prefix for cached settings reads
+	SettingsCacheKeyPrefix = "settings:cache:"
+
+	// Upper bound for a single cache round trip; a slow Redis falls back to Postgres
+	settingsCacheOpTimeout = 100 * time.Millisecond
+)
+
+// Namespaces of the settings read cache
+const (
+	NamespaceSettings = "settings"
+	NamespaceTheme    = "theme"
+)
+
+// Invalidation sources recorded in metrics
+const (
+	InvalidationSourceWrite = "write"
+	InvalidationSourceEvent = "event"
+)
+
+// SettingsCache is a Redis read cache for settings and storefront themes.
+//
+// Entries are grouped by owner (tenant or storefront ID). Each owner has a version key holding
+// the time of its last invalidation and entries are stored under that version, so invalidating
+// an owner is a single SET that makes every older entry unreachable; they expire with the TTL.
+// A reader that loaded from Postgres before a concurrent write stores under the version it read,
+// which the write already superseded, so stale data is never served after an invalidation.
+type SettingsCache struct {
+	redisClient redis.UniversalClient
+	ttl         time.Duration
+}
+
+// NewSettingsCache creates a settings cache; a nil client disables caching
+func NewSettingsCache(redisClient redis.UniversalClient, ttl time.Duration) *SettingsCache {
+	if ttl <= 0 {
+		ttl = DefaultSettingsCacheTTL
+	}
+	return &SettingsCache{redisClient: redisClient, ttl: ttl}
+}
+
+// Enabled returns true if Redis is available
+func (c *SettingsCache) Enabled() bool {
+	return c != nil && c.redisClient != nil
+}
+
+// Get loads a cached value into dest. On a miss it returns the owner's current version, which
+// must be passed to Set so a value loaded before a concurrent invalidation is not served.
+func (c *SettingsCache) Get(namespace, owner, key string, dest interface{}) (int64, bool) {
+	if !c.Enabled() {
+		return 0, false
+	}
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), settingsCacheOpTimeout)
+	defer cancel()
+
+	version, err := c.version(ctx, namespace, owner)
+	if err != nil {
+		health.RecordCacheLookup(namespace, "error", time.Since(start))
+		return 0, false
+	}
+
+	data, err := c.redisClient.Get(ctx, c.entryKey(namespace, owner, version, key)).Bytes()
+	if err == redis.Nil {
+		health.RecordCacheLookup(namespace, "miss", time.Since(start))
+		return version, false
+	}
+	if err != nil || json.Unmarshal(data, dest) != nil {
+		health.RecordCacheLookup(namespace, "error", time.Since(start))
+		return 0, false
+	}
+
+	health.RecordCacheLookup(namespace, "hit", time.Since(start))
+	return version, true
+}
+
+// Set stores a value under the given owner version (from Get or Invalidate)
+func (c *SettingsCache) Set(namespace, owner string, version int64, key string, value interface{}) {
+	if !c.Enabled() || version == 0 {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), settingsCacheOpTimeout)
+	defer cancel()
+	c.redisClient.Set(ctx, c.entryKey(namespace, owner, version, key), data, c.ttl)
+}
+
+// Invalidate makes every cached entry of the owner unreachable and returns the new version,
+// which can be used to write the fresh value through
+func (c *SettingsCache) Invalidate(namespace, owner, source string) int64 {
+	if !c.Enabled() {
+		return 0
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), settingsCacheOpTimeout)
+	defer cancel()
+
+	version := time.Now().UnixNano()
+	if err := c.redisClient.Set(ctx, c.versionKey(namespace, owner), version, 0).Err(); err != nil {
+		return 0
+	}
+	health.RecordCacheInvalidation(namespace, source)
+	return version
+}
+
+// version returns the owner's current version, initializing it on first use
+func (c *SettingsCache) version(ctx context.Context, namespace, owner string) (int64, error) {
+	key := c.versionKey(namespace, owner)
+	value, err := c.redisClient.Get(ctx, key).Result()
+	if err == redis.Nil {
+		initial := time.Now().UnixNano()
+		set, err := c.redisClient.SetNX(ctx, key, initial, 0).Result()
+		if err != nil {
+			return 0, err
+		}
+		if set {
+			return initial, nil
+		}
+		value, err = c.redisClient.Get(ctx, key).Result()
+		if err != nil {
+			return 0, err
+		}
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// versionKey generates the version key of an owner
+func (c *SettingsCache) versionKey(namespace, owner string) string {
+	return fmt.Sprintf("%s%s:%s:version", SettingsCacheKeyPrefix, namespace, owner)
+}
+
+// entryKey generates the key of a cached entry
+func (c *SettingsCache) entryKey(namespace, owner string, version int64, key string) string {
+	return fmt.Sprintf("%s%s:%s:%d:%s", SettingsCacheKeyPrefix, namespace, owner, version, key)
+}
@@ -0,0 +1,142 @@
+package paymentgateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultTimeout = 10 * time.Second
+
+	StripeBaseURL        = "https://api.stripe.com"
+	RazorpayBaseURL      = "https://api.razorpay.com"
+	PayPalLiveBaseURL    = "https://api-m.paypal.com"
+	PayPalSandboxBaseURL = "https://api-m.sandbox.paypal.com"
+)
+
+// Client validates payment provider credentials with a lightweight authenticated call
+type Client struct {
+	httpClient           *http.Client
+	stripeBaseURL        string
+	razorpayBaseURL      string
+	paypalLiveBaseURL    string
+	paypalSandboxBaseURL string
+}
+
+// NewClient creates a new payment gateway client
+func NewClient(timeout time.Duration) *Client {
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		stripeBaseURL:        StripeBaseURL,
+		razorpayBaseURL:      RazorpayBaseURL,
+		paypalLiveBaseURL:    PayPalLiveBaseURL,
+		paypalSandboxBaseURL: PayPalSandboxBaseURL,
+	}
+}
+
+// NewDefaultClient creates a new client with default settings
+func NewDefaultClient() *Client {
+	return NewClient(DefaultTimeout)
+}
+
+// VerifyStripe checks a Stripe secret key by reading the account balance
+func (c *Client) VerifyStripe(ctx context.Context, secretKey string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.stripeBaseURL+"/v1/balance", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+secretKey)
+
+	return c.do(req, "stripe")
+}
+
+// VerifyRazorpay checks a Razorpay key pair by listing a single payment
+func (c *Client) VerifyRazorpay(ctx context.Context, keyID, keySecret string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.razorpayBaseURL+"/v1/payments?count=1", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(keyID, keySecret)
+
+	return c.do(req, "razorpay")
+}
+
+// VerifyPayPal checks PayPal REST credentials by requesting an OAuth access token
+func (c *Client) VerifyPayPal(ctx context.Context, clientID, clientSecret string, live bool) error {
+	baseURL := c.paypalSandboxBaseURL
+	if live {
+		baseURL = c.paypalLiveBaseURL
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(clientID, clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.do(req, "paypal")
+}
+
+// do executes the request and converts non-2xx responses into errors
+// The response body is only used to extract a provider error message; it is never logged
+func (c *Client) do(req *http.Request, provider string) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if msg := extractErrorMessage(body); msg != "" {
+		return fmt.Errorf("%s rejected credentials (status %d): %s", provider, resp.StatusCode, msg)
+	}
+	return fmt.Errorf("%s rejected credentials (status %d)", provider, resp.StatusCode)
+}
+
+// extractErrorMessage pulls the human readable message out of the provider error formats
+// Stripe and Razorpay: {"error": {"message"|"description": ...}}, PayPal: {"error_description": ...}
+func extractErrorMessage(body []byte) string {
+	var payload struct {
+		Error            json.RawMessage `json:"error"`
+		ErrorDescription string          `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	if payload.ErrorDescription != "" {
+		return payload.ErrorDescription
+	}
+
+	var nested struct {
+		Message     string `json:"message"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(payload.Error, &nested); err == nil {
+		if nested.Message != "" {
+			return nested.Message
+		}
+		return nested.Description
+	}
+	return ""
+}
@@ -3,14 +3,18 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 
-	"github.com/Tesseract-Nexus/go-shared/secrets")
+	"github.com/Tesseract-Nexus/go-shared/secrets"
+	"settings-service/internal/redisconn")
 
 type Config struct {
-	Server   ServerConfig   `json:"server"`
-	Database DatabaseConfig `json:"database"`
-	App      AppConfig      `json:"app"`
-	Redis    RedisConfig    `json:"redis"`
+	Server   ServerConfig     `json:"server"`
+	Database DatabaseConfig   `json:"database"`
+	App      AppConfig        `json:"app"`
+	Redis    redisconn.Config `json:"redis"`
+	Cache    CacheConfig      `json:"cache"`
+	Payments PaymentsConfig   `json:"payments"`
 }
 
 type ServerConfig struct {
@@ -34,12 +38,19 @@ type AppConfig struct {
 	Version     string `json:"version"`
 }
 
-type RedisConfig struct {
-	Host     string `json:"host"`
-	Port     string `json:"port"`
-	Password string `json:"password"`
-	DB       string `json:"db"`
-	URL      string `json:"url"` // Built from components or can be overridden
+// PaymentsConfig configures payment gateway settings
+type PaymentsConfig struct {
+	// CredentialsEncryptionKey is a base64 encoded 32-byte AES key for gateway secrets
+	CredentialsEncryptionKey string `json:"-"`
+	// CredentialCallers are the Istio service identities (SPIFFE IDs or service account
+	// names) allowed to read decrypted gateway credentials (default: payment-service)
+	CredentialCallers []string `json:"credential_callers"`
+}
+
+// CacheConfig controls the Redis read cache for settings and storefront themes
+type CacheConfig struct {
+	Enabled    bool `json:"enabled"`     // (default: true; requires Redis)
+	TTLSeconds int  `json:"ttl_seconds"` // (default: 3600)
 }
 
 // NewConfig creates a new configuration instance with environment variables
@@ -63,37 +74,18 @@ func NewConfig() *Config {
 			Debug:       getBoolEnv("DEBUG", true),
 			Version:     getEnv("VERSION", "1.0.0"),
 		},
-		Redis: buildRedisConfig(),
-	}
-}
-
-// buildRedisConfig builds the Redis configuration from environment variables
-func buildRedisConfig() RedisConfig {
-	// First check for explicit REDIS_URL override
-	if url := os.Getenv("REDIS_URL"); url != "" {
-		return RedisConfig{URL: url}
-	}
-
-	// Build URL from separate components
-	host := getEnv("REDIS_HOST", "redis.redis-marketplace.svc.cluster.local")
-	port := getEnv("REDIS_PORT", "6379")
-	password := os.Getenv("REDIS_PASSWORD")
-	db := getEnv("REDIS_DB", "0")
-
-	// Build Redis URL with or without password
-	var url string
-	if password != "" {
-		url = "redis://:" + password + "@" + host + ":" + port + "/" + db
-	} else {
-		url = "redis://" + host + ":" + port + "/" + db
-	}
-
-	return RedisConfig{
-		Host:     host,
-		Port:     port,
-		Password: password,
-		DB:       db,
-		URL:      url,
+		// REDIS_URL, REDIS_MODE (standalone, sentinel, cluster), REDIS_HOST/REDIS_PORT, REDIS_TLS, ...
+		Redis: redisconn.ConfigFromEnv(redisconn.Config{
+			Addrs: []string{"redis.redis-marketplace.svc.cluster.local:6379"},
+		}),
+		Cache: CacheConfig{
+			Enabled:    getBoolEnv("SETTINGS_CACHE_ENABLED", true),
+			TTLSeconds: getIntEnv("SETTINGS_CACHE_TTL_SECONDS", 3600),
+		},
+		Payments: PaymentsConfig{
+			CredentialsEncryptionKey: os.Getenv("PAYMENT_CREDENTIALS_ENCRYPTION_KEY"),
+			CredentialCallers:        getListEnv("PAYMENT_CREDENTIALS_CALLERS", []string{"payment-service"}),
+		},
 	}
 }
 
@@ -133,4 +125,29 @@ func getBoolEnv(key string, fallback bool) bool {
 		}
 	}
 	return fallback
+}
+
+// getListEnv gets a comma-separated environment variable with fallback
+func getListEnv(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getIntEnv gets integer environment variable with fallback
+func getIntEnv(key string, fallback int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
 }
\ No newline at end of file
@@ -111,3 +111,25 @@ func (p *Publisher) Close() {
 		p.publisher.Close()
 	}
 }
+
+// SettingsEvents publishes through the singleton publisher once it has been initialized
+// Events are dropped (not errored) while NATS is unavailable, matching the rest of the service
+type SettingsEvents struct{}
+
+// PublishSettingUpdated publishes a setting updated event if the publisher is ready
+func (SettingsEvents) PublishSettingUpdated(ctx context.Context, tenantID, settingKey, category string, oldValue, newValue interface{}, changedBy, changedByName string) error {
+	p := GetPublisher()
+	if p == nil {
+		return nil
+	}
+	return p.PublishSettingUpdated(ctx, tenantID, settingKey, category, oldValue, newValue, changedBy, changedByName)
+}
+
+// PublishSettingCreated publishes a setting created event if the publisher is ready
+func (SettingsEvents) PublishSettingCreated(ctx context.Context, tenantID, settingKey, category string, value interface{}, changedBy, changedByName string) error {
+	p := GetPublisher()
+	if p == nil {
+		return nil
+	}
+	return p.PublishSettingCreated(ctx, tenantID, settingKey, category, value, changedBy, changedByName)
+}
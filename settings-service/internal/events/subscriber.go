@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/sirupsen/logrus"
+)
+
+// settingsCacheConsumer is the durable consumer shared by all replicas; the cache lives in
+// Redis, so each event only needs to be handled once
+const settingsCacheConsumer = "settings-service-cache"
+
+// SettingsChangeHandler handles a settings change event
+type SettingsChangeHandler func(ctx context.Context, tenantID, category, settingKey string)
+
+// SubscribeSettingsChanges consumes settings.updated, settings.created and settings.bulk_updated
+// events and passes them to handler. It returns nil without subscribing when NATS is not configured.
+func SubscribeSettingsChanges(ctx context.Context, logger *logrus.Logger, handler SettingsChangeHandler) (*events.Subscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		logger.Warn("NATS_URL not set, settings cache invalidation events disabled")
+		return nil, nil
+	}
+
+	config := events.DefaultSubscriberConfig(natsURL, settingsCacheConsumer)
+	config.Name = "settings-service-cache"
+
+	sub, err := events.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := []string{events.SettingsUpdated, events.SettingsCreated, events.SettingsBulkUpdated}
+	err = sub.Subscribe(ctx, events.StreamSettings, subjects, func(ctx context.Context, msg *events.Message) error {
+		var event events.SettingsEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			// Acknowledged and dropped; redelivery would not make it parse
+			logger.WithError(err).WithField("subject", msg.Subject).Warn("Dropping malformed settings event")
+			return nil
+		}
+		handler(ctx, event.TenantID, event.SettingCategory, event.SettingKey)
+		return nil
+	})
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	return sub, nil
+}
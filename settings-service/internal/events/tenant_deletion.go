@@ -0,0 +1,134 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// serviceName is how tenant-service's deletion saga addresses this service
+	serviceName = "settings-service"
+
+	tenantDeletionRequested    = "tenant.deletion.requested"
+	tenantDeletionAcknowledged = "tenant.deletion.acknowledged"
+
+	// tenantDeletionConsumer is the durable consumer shared by all replicas, so each request is
+	// purged once
+	tenantDeletionConsumer = "settings-service-tenant-deletion"
+)
+
+// tenantDeletionRequestedEvent is the part of tenant-service's tenant.deletion.requested event
+// needed to purge a tenant
+type tenantDeletionRequestedEvent struct {
+	TenantID string   `json:"tenant_id"`
+	Services []string `json:"services"` // Services asked to purge; others ignore the request
+}
+
+// tenantDeletionAcknowledgedEvent reports the outcome of a purge back to tenant-service
+type tenantDeletionAcknowledgedEvent struct {
+	EventType   string    `json:"event_type"`
+	TenantID    string    `json:"tenant_id"`
+	Service     string    `json:"service"`
+	Status      string    `json:"status"` // "purged" or "failed"
+	ItemsPurged int64     `json:"items_purged,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+func (e *tenantDeletionAcknowledgedEvent) Validate() error {
+	if e.TenantID == "" {
+		return events.ErrMissingTenantID
+	}
+	return nil
+}
+
+func (e *tenantDeletionAcknowledgedEvent) GetSubject() string { return tenantDeletionAcknowledged }
+func (e *tenantDeletionAcknowledgedEvent) GetStream() string  { return events.StreamTenants }
+
+// PublishTenantDeletionAcknowledged reports a tenant purge to tenant-service; purgeErr is nil when
+// the purge succeeded
+func (p *Publisher) PublishTenantDeletionAcknowledged(ctx context.Context, tenantID string, itemsPurged int64, purgeErr error) error {
+	event := &tenantDeletionAcknowledgedEvent{
+		EventType:   tenantDeletionAcknowledged,
+		TenantID:    tenantID,
+		Service:     serviceName,
+		Status:      "purged",
+		ItemsPurged: itemsPurged,
+		Timestamp:   time.Now().UTC(),
+	}
+	if purgeErr != nil {
+		event.Status = "failed"
+		event.Error = purgeErr.Error()
+	}
+
+	return p.publisher.Publish(ctx, event)
+}
+
+// TenantPurgeHandler deletes everything a tenant owns and returns how many items were removed.
+// It must be idempotent: tenant-service re-sends the request until it is acknowledged.
+type TenantPurgeHandler func(ctx context.Context, tenantID string) (int64, error)
+
+// SubscribeTenantDeletionRequested consumes tenant-service's deletion requests addressed to this
+// service, purges the tenant with handler and acknowledges the outcome. It returns nil without
+// subscribing when NATS is not configured.
+func SubscribeTenantDeletionRequested(ctx context.Context, logger *logrus.Logger, handler TenantPurgeHandler) (*events.Subscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		logger.Warn("NATS_URL not set, tenant deletion requests will not be processed")
+		return nil, nil
+	}
+
+	config := events.DefaultSubscriberConfig(natsURL, tenantDeletionConsumer)
+	config.Name = "settings-service-tenant-deletion"
+
+	sub, err := events.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sub.Subscribe(ctx, events.StreamTenants, []string{tenantDeletionRequested}, func(ctx context.Context, msg *events.Message) error {
+		var event tenantDeletionRequestedEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			// Acknowledged and dropped; redelivery would not make it parse
+			logger.WithError(err).WithField("subject", msg.Subject).Warn("Dropping malformed tenant deletion request")
+			return nil
+		}
+		if event.TenantID == "" || !addressedTo(event.Services, serviceName) {
+			return nil
+		}
+
+		// Redeliver until the publisher is up so the acknowledgment is not lost
+		publisher := GetPublisher()
+		if publisher == nil {
+			return fmt.Errorf("events publisher not initialized")
+		}
+
+		purged, purgeErr := handler(ctx, event.TenantID)
+		if purgeErr != nil {
+			logger.WithError(purgeErr).WithField("tenant_id", event.TenantID).Error("Failed to purge tenant settings")
+		}
+		// A failed purge is reported rather than redelivered; tenant-service re-requests it later
+		return publisher.PublishTenantDeletionAcknowledged(ctx, event.TenantID, purged, purgeErr)
+	})
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	return sub, nil
+}
+
+// addressedTo reports whether service is one of the services a deletion request names
+func addressedTo(services []string, service string) bool {
+	for _, s := range services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
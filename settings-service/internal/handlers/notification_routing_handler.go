@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"settings-service/internal/models"
+	"settings-service/internal/services"
+)
+
+// NotificationRoutingHandler handles notification routing settings requests
+type NotificationRoutingHandler struct {
+	service services.NotificationRoutingService
+}
+
+// NewNotificationRoutingHandler creates a new notification routing handler
+func NewNotificationRoutingHandler(service services.NotificationRoutingService) *NotificationRoutingHandler {
+	return &NotificationRoutingHandler{service: service}
+}
+
+// GetEventTypes returns the routable event type catalog and supported channels
+// @Summary Get notification event types
+// @Description List commonly routed event types and the supported delivery channels
+// @Tags notification-routing
+// @Produce json
+// @Success 200 {object} models.NotificationRoutingResponse
+// @Router /api/v1/notification-routing/event-types [get]
+func (h *NotificationRoutingHandler) GetEventTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, models.NotificationRoutingResponse{
+		Success: true,
+		Data:    h.service.GetEventTypes(),
+	})
+}
+
+// ListRoutes lists the tenant's notification routes
+// @Summary List notification routes
+// @Description List the tenant's event type to channel and recipient mappings
+// @Tags notification-routing
+// @Produce json
+// @Success 200 {object} models.NotificationRoutingResponse
+// @Failure 400 {object} models.NotificationRoutingResponse
+// @Failure 500 {object} models.NotificationRoutingResponse
+// @Router /api/v1/notification-routing/routes [get]
+func (h *NotificationRoutingHandler) ListRoutes(c *gin.Context) {
+	tenantID, ok := requireNotificationTenant(c)
+	if !ok {
+		return
+	}
+
+	routes, err := h.service.ListRoutes(tenantID)
+	if err != nil {
+		respondNotificationRoutingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NotificationRoutingResponse{
+		Success: true,
+		Data:    routes,
+	})
+}
+
+// GetRoute returns the tenant's route for an event type
+// @Summary Get notification route
+// @Description Get the tenant's route for an event type or wildcard (order.*, *)
+// @Tags notification-routing
+// @Produce json
+// @Param eventType path string true "Event type (e.g. order.created, low_stock, order.*)"
+// @Success 200 {object} models.NotificationRoutingResponse
+// @Failure 404 {object} models.NotificationRoutingResponse
+// @Router /api/v1/notification-routing/routes/{eventType} [get]
+func (h *NotificationRoutingHandler) GetRoute(c *gin.Context) {
+	tenantID, ok := requireNotificationTenant(c)
+	if !ok {
+		return
+	}
+
+	route, err := h.service.GetRoute(tenantID, c.Param("eventType"))
+	if err != nil {
+		respondNotificationRoutingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NotificationRoutingResponse{
+		Success: true,
+		Data:    route,
+	})
+}
+
+// UpsertRoute creates or replaces the tenant's route for an event type
+// @Summary Create or update notification route
+// @Description Set the channels and recipients (roles, users, webhook) notified about an event type
+// @Tags notification-routing
+// @Accept json
+// @Produce json
+// @Param eventType path string true "Event type (e.g. order.created, low_stock, order.*)"
+// @Param request body models.UpsertNotificationRouteRequest true "Route"
+// @Success 200 {object} models.NotificationRoutingResponse
+// @Failure 400 {object} models.NotificationRoutingResponse
+// @Failure 500 {object} models.NotificationRoutingResponse
+// @Router /api/v1/notification-routing/routes/{eventType} [put]
+func (h *NotificationRoutingHandler) UpsertRoute(c *gin.Context) {
+	tenantID, ok := requireNotificationTenant(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpsertNotificationRouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NotificationRoutingResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	route, err := h.service.UpsertRoute(c.Request.Context(), tenantID, c.Param("eventType"), &req, getUserID(c))
+	if err != nil {
+		respondNotificationRoutingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NotificationRoutingResponse{
+		Success: true,
+		Data:    route,
+		Message: "Notification route saved",
+	})
+}
+
+// DeleteRoute removes the tenant's route for an event type
+// @Summary Delete notification route
+// @Description Remove a route; the event falls back to broader routes or notification defaults
+// @Tags notification-routing
+// @Produce json
+// @Param eventType path string true "Event type"
+// @Success 200 {object} models.NotificationRoutingResponse
+// @Failure 404 {object} models.NotificationRoutingResponse
+// @Router /api/v1/notification-routing/routes/{eventType} [delete]
+func (h *NotificationRoutingHandler) DeleteRoute(c *gin.Context) {
+	tenantID, ok := requireNotificationTenant(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteRoute(c.Request.Context(), tenantID, c.Param("eventType"), getUserID(c)); err != nil {
+		respondNotificationRoutingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NotificationRoutingResponse{
+		Success: true,
+		Message: "Notification route deleted",
+	})
+}
+
+// ResolveInternal returns the route that applies to an event for a tenant
+// Only reachable through the internal service route group
+// @Summary Resolve notification route (internal)
+// @Description Returns the channels and recipients for an event, used by notification-service and notification-hub
+// @Tags notification-routing
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param event query string true "Event type (e.g. order.created)"
+// @Success 200 {object} models.NotificationRoutingResponse
+// @Failure 400 {object} models.NotificationRoutingResponse
+// @Router /api/v1/tenants/{id}/notification-routing/resolve [get]
+func (h *NotificationRoutingHandler) ResolveInternal(c *gin.Context) {
+	tenantIDStr := c.Param("id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		namespace := uuid.MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+		tenantID = uuid.NewSHA1(namespace, []byte(tenantIDStr))
+	}
+
+	resolved, err := h.service.Resolve(tenantID, c.Query("event"))
+	if err != nil {
+		respondNotificationRoutingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NotificationRoutingResponse{
+		Success: true,
+		Data:    resolved,
+	})
+}
+
+// requireNotificationTenant resolves the tenant from the request, writing a 400 if missing
+func requireNotificationTenant(c *gin.Context) (uuid.UUID, bool) {
+	tenantID, _ := parseTenantID(c)
+	if tenantID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, models.NotificationRoutingResponse{
+			Success: false,
+			Message: "Tenant ID is required",
+		})
+		return uuid.Nil, false
+	}
+	return tenantID, true
+}
+
+// respondNotificationRoutingError maps notification routing service errors to HTTP responses
+func respondNotificationRoutingError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidNotificationEvent), errors.Is(err, services.ErrInvalidNotificationRoute):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrNotificationRouteNotFound):
+		status = http.StatusNotFound
+	}
+
+	c.JSON(status, models.NotificationRoutingResponse{
+		Success: false,
+		Message: err.Error(),
+	})
+}
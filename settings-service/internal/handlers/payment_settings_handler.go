@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"settings-service/internal/models"
+	"settings-service/internal/services"
+)
+
+// PaymentSettingsHandler handles payment gateway settings requests
+type PaymentSettingsHandler struct {
+	service services.PaymentSettingsService
+}
+
+// NewPaymentSettingsHandler creates a new payment settings handler
+func NewPaymentSettingsHandler(service services.PaymentSettingsService) *PaymentSettingsHandler {
+	return &PaymentSettingsHandler{service: service}
+}
+
+// GetSchemas returns the configuration schema of every supported provider
+// @Summary Get payment provider schemas
+// @Description List supported payment providers and the fields each one accepts
+// @Tags payment-settings
+// @Produce json
+// @Success 200 {object} models.PaymentSettingsResponse
+// @Router /api/v1/payment-settings/schemas [get]
+func (h *PaymentSettingsHandler) GetSchemas(c *gin.Context) {
+	c.JSON(http.StatusOK, models.PaymentSettingsResponse{
+		Success: true,
+		Data:    h.service.GetSchemas(),
+	})
+}
+
+// ListGateways lists the tenant's payment gateway configs
+// @Summary List payment gateways
+// @Description List the tenant's payment gateway configs with masked credentials
+// @Tags payment-settings
+// @Produce json
+// @Success 200 {object} models.PaymentSettingsResponse
+// @Failure 400 {object} models.PaymentSettingsResponse
+// @Failure 500 {object} models.PaymentSettingsResponse
+// @Router /api/v1/payment-settings/gateways [get]
+func (h *PaymentSettingsHandler) ListGateways(c *gin.Context) {
+	tenantID, ok := requirePaymentTenant(c)
+	if !ok {
+		return
+	}
+
+	gateways, err := h.service.ListGateways(tenantID)
+	if err != nil {
+		respondPaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaymentSettingsResponse{
+		Success: true,
+		Data:    gateways,
+	})
+}
+
+// GetGateway returns the tenant's config for a single provider
+// @Summary Get payment gateway
+// @Description Get the tenant's config for a payment provider with masked credentials
+// @Tags payment-settings
+// @Produce json
+// @Param provider path string true "Provider (stripe, razorpay, paypal)"
+// @Success 200 {object} models.PaymentSettingsResponse
+// @Failure 404 {object} models.PaymentSettingsResponse
+// @Router /api/v1/payment-settings/gateways/{provider} [get]
+func (h *PaymentSettingsHandler) GetGateway(c *gin.Context) {
+	tenantID, ok := requirePaymentTenant(c)
+	if !ok {
+		return
+	}
+
+	gateway, err := h.service.GetGateway(tenantID, paymentProviderParam(c))
+	if err != nil {
+		respondPaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaymentSettingsResponse{
+		Success: true,
+		Data:    gateway,
+	})
+}
+
+// UpsertGateway creates or updates the tenant's config for a provider
+// @Summary Create or update payment gateway
+// @Description Validate and store a payment gateway config; secret credentials are encrypted at rest
+// @Tags payment-settings
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider (stripe, razorpay, paypal)"
+// @Param request body models.UpsertPaymentGatewayRequest true "Gateway config"
+// @Success 200 {object} models.PaymentSettingsResponse
+// @Failure 400 {object} models.PaymentSettingsResponse
+// @Failure 500 {object} models.PaymentSettingsResponse
+// @Router /api/v1/payment-settings/gateways/{provider} [put]
+func (h *PaymentSettingsHandler) UpsertGateway(c *gin.Context) {
+	tenantID, ok := requirePaymentTenant(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpsertPaymentGatewayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.PaymentSettingsResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	gateway, err := h.service.UpsertGateway(c.Request.Context(), tenantID, paymentProviderParam(c), &req, getUserID(c))
+	if err != nil {
+		respondPaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaymentSettingsResponse{
+		Success: true,
+		Data:    gateway,
+		Message: "Payment gateway saved",
+	})
+}
+
+// DeleteGateway removes the tenant's config for a provider
+// @Summary Delete payment gateway
+// @Description Remove a payment gateway config and its encrypted credentials
+// @Tags payment-settings
+// @Produce json
+// @Param provider path string true "Provider (stripe, razorpay, paypal)"
+// @Success 200 {object} models.PaymentSettingsResponse
+// @Failure 404 {object} models.PaymentSettingsResponse
+// @Router /api/v1/payment-settings/gateways/{provider} [delete]
+func (h *PaymentSettingsHandler) DeleteGateway(c *gin.Context) {
+	tenantID, ok := requirePaymentTenant(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteGateway(c.Request.Context(), tenantID, paymentProviderParam(c), getUserID(c)); err != nil {
+		respondPaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaymentSettingsResponse{
+		Success: true,
+		Message: "Payment gateway deleted",
+	})
+}
+
+// TestConnection validates gateway credentials against the provider
+// @Summary Test payment gateway connection
+// @Description Validate stored or supplied credentials with a live call to the provider
+// @Tags payment-settings
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider (stripe, razorpay, paypal)"
+// @Param request body models.TestPaymentGatewayRequest false "Optional credentials to test before saving"
+// @Success 200 {object} models.PaymentSettingsResponse
+// @Failure 400 {object} models.PaymentSettingsResponse
+// @Router /api/v1/payment-settings/gateways/{provider}/test [post]
+func (h *PaymentSettingsHandler) TestConnection(c *gin.Context) {
+	tenantID, ok := requirePaymentTenant(c)
+	if !ok {
+		return
+	}
+
+	var req models.TestPaymentGatewayRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.PaymentSettingsResponse{
+				Success: false,
+				Message: "Invalid request body: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	result, err := h.service.TestConnection(c.Request.Context(), tenantID, paymentProviderParam(c), &req)
+	if err != nil {
+		respondPaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaymentSettingsResponse{
+		Success: result.Success,
+		Data:    result,
+		Message: result.Message,
+	})
+}
+
+// GetInternalCredentials returns decrypted credentials of a tenant's enabled gateways
+// Only reachable by the payment service's mTLS identity
+// @Summary Get payment gateway credentials (internal)
+// @Description Returns decrypted credentials of enabled gateways for checkout services
+// @Tags payment-settings
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} models.PaymentSettingsResponse
+// @Failure 400 {object} models.PaymentSettingsResponse
+// @Router /api/v1/tenants/{id}/payment-gateways [get]
+func (h *PaymentSettingsHandler) GetInternalCredentials(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.PaymentSettingsResponse{
+			Success: false,
+			Message: "Tenant ID must be a UUID",
+		})
+		return
+	}
+
+	credentials, err := h.service.ListCredentials(tenantID)
+	if err != nil {
+		respondPaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaymentSettingsResponse{
+		Success: true,
+		Data:    credentials,
+	})
+}
+
+// requirePaymentTenant resolves the tenant from the request, writing a 400 if missing
+func requirePaymentTenant(c *gin.Context) (uuid.UUID, bool) {
+	tenantID, _ := parseTenantID(c)
+	if tenantID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, models.PaymentSettingsResponse{
+			Success: false,
+			Message: "Tenant ID is required",
+		})
+		return uuid.Nil, false
+	}
+	return tenantID, true
+}
+
+func paymentProviderParam(c *gin.Context) string {
+	return strings.ToLower(c.Param("provider"))
+}
+
+// respondPaymentError maps payment settings service errors to HTTP responses
+func respondPaymentError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrUnsupportedPaymentProvider), errors.Is(err, services.ErrInvalidPaymentConfig):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrPaymentGatewayNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrCredentialEncryptionDisabled):
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, models.PaymentSettingsResponse{
+		Success: false,
+		Message: err.Error(),
+	})
+}
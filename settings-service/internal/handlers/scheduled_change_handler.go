@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"settings-service/internal/models"
+	"settings-service/internal/services"
+)
+
+// ScheduledChangeHandler handles scheduled settings and theme change requests
+type ScheduledChangeHandler struct {
+	service services.ScheduledChangeService
+}
+
+// NewScheduledChangeHandler creates a new scheduled change handler
+func NewScheduledChangeHandler(service services.ScheduledChangeService) *ScheduledChangeHandler {
+	return &ScheduledChangeHandler{service: service}
+}
+
+// ScheduleChange schedules a settings or theme change for a future time
+// @Summary Schedule a settings change
+// @Description Stage a settings or storefront theme update (or preset) to apply at a local time in the tenant's timezone
+// @Tags scheduled-changes
+// @Accept json
+// @Produce json
+// @Param request body models.CreateScheduledChangeRequest true "Scheduled change"
+// @Success 201 {object} models.ScheduledChangeResponse
+// @Failure 400 {object} models.ScheduledChangeResponse
+// @Failure 500 {object} models.ScheduledChangeResponse
+// @Router /api/v1/scheduled-changes [post]
+func (h *ScheduledChangeHandler) ScheduleChange(c *gin.Context) {
+	tenantID, ok := requireScheduledChangeTenant(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateScheduledChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ScheduledChangeResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	change, err := h.service.Schedule(tenantID, &req, getUserID(c))
+	if err != nil {
+		respondScheduledChangeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ScheduledChangeResponse{
+		Success: true,
+		Data:    change,
+		Message: "Change scheduled",
+	})
+}
+
+// ListScheduledChanges lists the tenant's scheduled changes
+// @Summary List scheduled changes
+// @Description List scheduled changes, soonest first; defaults to pending changes
+// @Tags scheduled-changes
+// @Produce json
+// @Param status query string false "Status filter (pending, applied, cancelled, failed, all)"
+// @Success 200 {object} models.ScheduledChangeResponse
+// @Failure 400 {object} models.ScheduledChangeResponse
+// @Router /api/v1/scheduled-changes [get]
+func (h *ScheduledChangeHandler) ListScheduledChanges(c *gin.Context) {
+	tenantID, ok := requireScheduledChangeTenant(c)
+	if !ok {
+		return
+	}
+
+	status := c.DefaultQuery("status", models.ScheduledStatusPending)
+	if status == "all" {
+		status = ""
+	}
+
+	changes, err := h.service.List(tenantID, status)
+	if err != nil {
+		respondScheduledChangeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ScheduledChangeResponse{
+		Success: true,
+		Data:    changes,
+	})
+}
+
+// GetScheduledChange returns a single scheduled change
+// @Summary Get scheduled change
+// @Tags scheduled-changes
+// @Produce json
+// @Param id path string true "Scheduled change ID"
+// @Success 200 {object} models.ScheduledChangeResponse
+// @Failure 404 {object} models.ScheduledChangeResponse
+// @Router /api/v1/scheduled-changes/{id} [get]
+func (h *ScheduledChangeHandler) GetScheduledChange(c *gin.Context) {
+	tenantID, ok := requireScheduledChangeTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := scheduledChangeIDParam(c)
+	if !ok {
+		return
+	}
+
+	change, err := h.service.Get(tenantID, id)
+	if err != nil {
+		respondScheduledChangeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ScheduledChangeResponse{
+		Success: true,
+		Data:    change,
+	})
+}
+
+// CancelScheduledChange cancels a pending scheduled change
+// @Summary Cancel scheduled change
+// @Tags scheduled-changes
+// @Produce json
+// @Param id path string true "Scheduled change ID"
+// @Success 200 {object} models.ScheduledChangeResponse
+// @Failure 404 {object} models.ScheduledChangeResponse
+// @Failure 409 {object} models.ScheduledChangeResponse
+// @Router /api/v1/scheduled-changes/{id}/cancel [post]
+func (h *ScheduledChangeHandler) CancelScheduledChange(c *gin.Context) {
+	tenantID, ok := requireScheduledChangeTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := scheduledChangeIDParam(c)
+	if !ok {
+		return
+	}
+
+	change, err := h.service.Cancel(tenantID, id, getUserID(c))
+	if err != nil {
+		respondScheduledChangeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ScheduledChangeResponse{
+		Success: true,
+		Data:    change,
+		Message: "Scheduled change cancelled",
+	})
+}
+
+// requireScheduledChangeTenant resolves the tenant from the request, writing a 400 if missing
+func requireScheduledChangeTenant(c *gin.Context) (uuid.UUID, bool) {
+	tenantID, _ := parseTenantID(c)
+	if tenantID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, models.ScheduledChangeResponse{
+			Success: false,
+			Message: "Tenant ID is required",
+		})
+		return uuid.Nil, false
+	}
+	return tenantID, true
+}
+
+func scheduledChangeIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ScheduledChangeResponse{
+			Success: false,
+			Message: "Invalid scheduled change ID",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondScheduledChangeError maps scheduled change service errors to HTTP responses
+func respondScheduledChangeError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidScheduledChange):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrScheduledChangeNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrScheduledChangeNotPending):
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, models.ScheduledChangeResponse{
+		Success: false,
+		Message: err.Error(),
+	})
+}
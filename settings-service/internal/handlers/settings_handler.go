@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -37,6 +38,7 @@ func NewSettingsHandler(settingsService services.SettingsService) *SettingsHandl
 // @Param settings body models.CreateSettingsRequest true "Settings data"
 // @Success 201 {object} models.SettingsResponse
 // @Failure 400 {object} models.SettingsResponse
+// @Failure 422 {object} models.SettingsValidationWebhookResponse "Rejected by a validation webhook"
 // @Failure 500 {object} models.SettingsResponse
 // @Router /api/v1/settings [post]
 func (h *SettingsHandler) CreateSettings(c *gin.Context) {
@@ -162,6 +164,9 @@ func (h *SettingsHandler) CreateSettings(c *gin.Context) {
 	
 	settings, err := h.settingsService.CreateSettings(&req, authUserID)
 	if err != nil {
+		if respondSettingsRejected(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.SettingsResponse{
 			Success: false,
 			Message: "Failed to create settings: " + err.Error(),
@@ -410,6 +415,96 @@ func (h *SettingsHandler) GetInheritedSettings(c *gin.Context) {
 	})
 }
 
+// ExplainSetting explains how a setting's effective value was resolved
+// @Summary Explain a setting value
+// @Description Show the inheritance chain (platform default -> tenant -> storefront -> user) for a key, which level supplied the effective value and any preset that set it
+// @Tags settings
+// @Produce json
+// @Param key query string true "Dotted settings key, e.g. theme.colorMode"
+// @Param applicationId query string false "Application (storefront) ID"
+// @Param tenantId query string false "Tenant ID (optional, uses JWT claim if not provided)"
+// @Param userId query string false "User ID (optional, uses JWT claim if not provided)"
+// @Success 200 {object} models.SettingsExplainResponse
+// @Failure 400 {object} models.SettingsResponse
+// @Failure 500 {object} models.SettingsResponse
+// @Router /api/v1/settings/explain [get]
+func (h *SettingsHandler) ExplainSetting(c *gin.Context) {
+	key := c.Query("key")
+	if key == "" {
+		c.JSON(http.StatusBadRequest, models.SettingsResponse{
+			Success: false,
+			Message: "key query parameter is required",
+		})
+		return
+	}
+
+	tenantIDStr := c.GetString("tenant_id")
+	if tenantIDStr == "" {
+		tenantIDStr = c.Query("tenantId")
+	}
+	if tenantIDStr == "" {
+		c.JSON(http.StatusBadRequest, models.SettingsResponse{
+			Success: false,
+			Message: "Tenant ID is required (from JWT claim or tenantId query parameter)",
+		})
+		return
+	}
+
+	// Non-UUID IDs map to the same deterministic UUIDs as the other context endpoints
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		namespace := uuid.MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+		tenantID = uuid.NewSHA1(namespace, []byte(tenantIDStr))
+	}
+
+	context := models.SettingsContext{TenantID: tenantID}
+	if applicationIDStr := c.Query("applicationId"); applicationIDStr != "" {
+		applicationID, err := uuid.Parse(applicationIDStr)
+		if err != nil {
+			namespace := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+			applicationID = uuid.NewSHA1(namespace, []byte(applicationIDStr))
+		}
+		context.ApplicationID = applicationID
+	}
+
+	userIDStr := c.Query("userId")
+	if userIDStr == "" {
+		userIDStr = c.GetString("user_id")
+	}
+	if userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.SettingsResponse{
+				Success: false,
+				Message: "Invalid user ID format",
+			})
+			return
+		}
+		context.UserID = &userID
+	}
+
+	explanation, err := h.settingsService.ExplainSetting(context, key)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSettingsKey) {
+			c.JSON(http.StatusBadRequest, models.SettingsResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.SettingsResponse{
+			Success: false,
+			Message: "Failed to explain setting: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsExplainResponse{
+		Success: true,
+		Data:    *explanation,
+	})
+}
+
 // UpdateSettings updates existing settings
 // @Summary Update settings
 // @Description Update existing settings by ID
@@ -423,6 +518,7 @@ func (h *SettingsHandler) GetInheritedSettings(c *gin.Context) {
 // @Success 200 {object} models.SettingsResponse
 // @Failure 400 {object} models.SettingsResponse
 // @Failure 404 {object} models.SettingsResponse
+// @Failure 422 {object} models.SettingsValidationWebhookResponse "Rejected by a validation webhook"
 // @Failure 500 {object} models.SettingsResponse
 // @Router /api/v1/settings/{id} [put]
 func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
@@ -455,6 +551,9 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 	
 	settings, err := h.settingsService.UpdateSettings(id, &req, userID)
 	if err != nil {
+		if respondSettingsRejected(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.SettingsResponse{
 			Success: false,
 			Message: "Failed to update settings: " + err.Error(),
@@ -730,6 +829,7 @@ func (h *SettingsHandler) ListPresets(c *gin.Context) {
 // @Success 200 {object} models.SettingsResponse
 // @Failure 400 {object} models.SettingsResponse
 // @Failure 404 {object} models.SettingsResponse
+// @Failure 422 {object} models.SettingsValidationWebhookResponse "Rejected by a validation webhook"
 // @Failure 500 {object} models.SettingsResponse
 // @Router /api/v1/settings/{settingsId}/apply-preset/{presetId} [post]
 func (h *SettingsHandler) ApplyPreset(c *gin.Context) {
@@ -763,6 +863,9 @@ func (h *SettingsHandler) ApplyPreset(c *gin.Context) {
 	
 	settings, err := h.settingsService.ApplyPreset(settingsID, presetID, userID)
 	if err != nil {
+		if respondSettingsRejected(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.SettingsResponse{
 			Success: false,
 			Message: "Failed to apply preset: " + err.Error(),
@@ -834,7 +937,9 @@ func (h *SettingsHandler) GetSettingsHistory(c *gin.Context) {
 // @Param tenantId query string true "Tenant ID (storefront ID)"
 // @Param applicationId query string true "Application ID"
 // @Param scope query string true "Settings scope"
+// @Param If-None-Match header string false "ETag of a previous response"
 // @Success 200 {object} models.SettingsResponse
+// @Success 304 "Not modified"
 // @Failure 400 {object} models.SettingsResponse
 // @Failure 404 {object} models.SettingsResponse
 // @Router /api/v1/public/settings/context [get]
@@ -892,14 +997,14 @@ func (h *SettingsHandler) GetPublicSettingsByContext(c *gin.Context) {
 	if err != nil {
 		// For public access, return empty response instead of error
 		// This allows storefronts to gracefully handle missing settings
-		c.JSON(http.StatusOK, models.SettingsResponse{
+		respondWithETag(c, http.StatusOK, models.SettingsResponse{
 			Success: true,
 			Message: "Settings not found for this context",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SettingsResponse{
+	respondWithETag(c, http.StatusOK, models.SettingsResponse{
 		Success: true,
 		Data:    *settings,
 	})
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"settings-service/internal/models"
+	"settings-service/internal/services"
+)
+
+// SettingsSnapshotHandler handles settings snapshot requests
+type SettingsSnapshotHandler struct {
+	service services.SettingsSnapshotService
+}
+
+// NewSettingsSnapshotHandler creates a new settings snapshot handler
+func NewSettingsSnapshotHandler(service services.SettingsSnapshotService) *SettingsSnapshotHandler {
+	return &SettingsSnapshotHandler{service: service}
+}
+
+// CreateSnapshot snapshots all of the tenant's settings and storefront themes
+// @Summary Create a settings snapshot
+// @Description Store a named, compressed copy of all tenant settings and storefront themes, e.g. before a deploy
+// @Tags settings-snapshots
+// @Accept json
+// @Produce json
+// @Param request body models.CreateSettingsSnapshotRequest true "Snapshot"
+// @Success 201 {object} models.SettingsSnapshotResponse
+// @Failure 400 {object} models.SettingsSnapshotResponse
+// @Failure 409 {object} models.SettingsSnapshotResponse
+// @Router /api/v1/settings-snapshots [post]
+func (h *SettingsSnapshotHandler) CreateSnapshot(c *gin.Context) {
+	tenantID, ok := requireSnapshotTenant(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSettingsSnapshotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.SettingsSnapshotResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	snapshot, err := h.service.Create(tenantID, &req, getUserID(c))
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SettingsSnapshotResponse{
+		Success: true,
+		Data:    snapshot,
+		Message: "Snapshot created",
+	})
+}
+
+// ListSnapshots lists the tenant's settings snapshots
+// @Summary List settings snapshots
+// @Description List snapshots, newest first, optionally only those taken for a deploy
+// @Tags settings-snapshots
+// @Produce json
+// @Param deployRef query string false "Deploy reference filter"
+// @Success 200 {object} models.SettingsSnapshotResponse
+// @Router /api/v1/settings-snapshots [get]
+func (h *SettingsSnapshotHandler) ListSnapshots(c *gin.Context) {
+	tenantID, ok := requireSnapshotTenant(c)
+	if !ok {
+		return
+	}
+
+	snapshots, err := h.service.List(tenantID, c.Query("deployRef"))
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsSnapshotResponse{
+		Success: true,
+		Data:    snapshots,
+	})
+}
+
+// GetSnapshot returns a single settings snapshot
+// @Summary Get settings snapshot
+// @Tags settings-snapshots
+// @Produce json
+// @Param id path string true "Snapshot ID"
+// @Success 200 {object} models.SettingsSnapshotResponse
+// @Failure 404 {object} models.SettingsSnapshotResponse
+// @Router /api/v1/settings-snapshots/{id} [get]
+func (h *SettingsSnapshotHandler) GetSnapshot(c *gin.Context) {
+	tenantID, ok := requireSnapshotTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := snapshotIDParam(c)
+	if !ok {
+		return
+	}
+
+	snapshot, err := h.service.Get(tenantID, id)
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsSnapshotResponse{
+		Success: true,
+		Data:    snapshot,
+	})
+}
+
+// DeleteSnapshot deletes a settings snapshot
+// @Summary Delete settings snapshot
+// @Tags settings-snapshots
+// @Produce json
+// @Param id path string true "Snapshot ID"
+// @Success 200 {object} models.SettingsSnapshotResponse
+// @Failure 404 {object} models.SettingsSnapshotResponse
+// @Router /api/v1/settings-snapshots/{id} [delete]
+func (h *SettingsSnapshotHandler) DeleteSnapshot(c *gin.Context) {
+	tenantID, ok := requireSnapshotTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := snapshotIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(tenantID, id); err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsSnapshotResponse{
+		Success: true,
+		Message: "Snapshot deleted",
+	})
+}
+
+// RestoreSnapshot restores the tenant's settings and themes from a snapshot
+// @Summary Restore settings snapshot
+// @Description Atomically replace all tenant settings and storefront themes with the snapshot; the current state is snapshotted first
+// @Tags settings-snapshots
+// @Produce json
+// @Param id path string true "Snapshot ID"
+// @Success 200 {object} models.SettingsSnapshotResponse
+// @Failure 404 {object} models.SettingsSnapshotResponse
+// @Failure 422 {object} models.SettingsSnapshotResponse
+// @Router /api/v1/settings-snapshots/{id}/restore [post]
+func (h *SettingsSnapshotHandler) RestoreSnapshot(c *gin.Context) {
+	tenantID, ok := requireSnapshotTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := snapshotIDParam(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Restore(tenantID, id, getUserID(c))
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsSnapshotResponse{
+		Success: true,
+		Data:    result,
+		Message: "Snapshot restored",
+	})
+}
+
+// DiffSnapshots compares two snapshots, or a snapshot and the current settings
+// @Summary Diff settings snapshots
+// @Description Field-level differences between two snapshots; either side may be "current"
+// @Tags settings-snapshots
+// @Produce json
+// @Param from query string true "Snapshot ID or current"
+// @Param to query string false "Snapshot ID or current (default current)"
+// @Success 200 {object} models.SettingsSnapshotResponse
+// @Failure 400 {object} models.SettingsSnapshotResponse
+// @Failure 404 {object} models.SettingsSnapshotResponse
+// @Router /api/v1/settings-snapshots/diff [get]
+func (h *SettingsSnapshotHandler) DiffSnapshots(c *gin.Context) {
+	tenantID, ok := requireSnapshotTenant(c)
+	if !ok {
+		return
+	}
+
+	from := c.Query("from")
+	if from == "" {
+		c.JSON(http.StatusBadRequest, models.SettingsSnapshotResponse{
+			Success: false,
+			Message: "from is required",
+		})
+		return
+	}
+
+	diff, err := h.service.Diff(tenantID, from, c.DefaultQuery("to", services.SnapshotCurrent))
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsSnapshotResponse{
+		Success: true,
+		Data:    diff,
+	})
+}
+
+// requireSnapshotTenant resolves the tenant from the request, writing a 400 if missing
+func requireSnapshotTenant(c *gin.Context) (uuid.UUID, bool) {
+	tenantID, _ := parseTenantID(c)
+	if tenantID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, models.SettingsSnapshotResponse{
+			Success: false,
+			Message: "Tenant ID is required",
+		})
+		return uuid.Nil, false
+	}
+	return tenantID, true
+}
+
+func snapshotIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.SettingsSnapshotResponse{
+			Success: false,
+			Message: "Invalid snapshot ID",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondSnapshotError maps settings snapshot service errors to HTTP responses
+func respondSnapshotError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidSettingsSnapshot):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrSettingsSnapshotNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrSettingsSnapshotExists):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrSettingsSnapshotCorrupt):
+		status = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(status, models.SettingsSnapshotResponse{
+		Success: false,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"settings-service/internal/models"
+	"settings-service/internal/services"
+)
+
+// SettingsValidationWebhookHandler handles settings validation webhook requests.
+// Routes in the internal service group manage the platform-wide webhooks of product teams;
+// the authenticated routes manage the caller's tenant webhooks.
+type SettingsValidationWebhookHandler struct {
+	service services.SettingsValidationWebhookService
+}
+
+// NewSettingsValidationWebhookHandler creates a new settings validation webhook handler
+func NewSettingsValidationWebhookHandler(service services.SettingsValidationWebhookService) *SettingsValidationWebhookHandler {
+	return &SettingsValidationWebhookHandler{service: service}
+}
+
+// ListWebhooks lists the registered validation webhooks
+// @Summary List settings validation webhooks
+// @Description List the tenant's validation webhooks, or the platform-wide ones on the internal route
+// @Tags settings-validation-webhooks
+// @Produce json
+// @Success 200 {object} models.SettingsValidationWebhookResponse
+// @Failure 400 {object} models.SettingsValidationWebhookResponse
+// @Router /api/v1/settings-validation-webhooks [get]
+// @Router /api/v1/platform/settings-validation-webhooks [get]
+func (h *SettingsValidationWebhookHandler) ListWebhooks(c *gin.Context) {
+	tenantID, ok := requireValidationWebhookOwner(c)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.service.List(tenantID)
+	if err != nil {
+		respondValidationWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsValidationWebhookResponse{
+		Success: true,
+		Data:    webhooks,
+	})
+}
+
+// GetWebhook returns a validation webhook
+// @Summary Get settings validation webhook
+// @Tags settings-validation-webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.SettingsValidationWebhookResponse
+// @Failure 404 {object} models.SettingsValidationWebhookResponse
+// @Router /api/v1/settings-validation-webhooks/{id} [get]
+// @Router /api/v1/platform/settings-validation-webhooks/{id} [get]
+func (h *SettingsValidationWebhookHandler) GetWebhook(c *gin.Context) {
+	tenantID, ok := requireValidationWebhookOwner(c)
+	if !ok {
+		return
+	}
+	id, ok := parseValidationWebhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.service.Get(tenantID, id)
+	if err != nil {
+		respondValidationWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsValidationWebhookResponse{
+		Success: true,
+		Data:    webhook,
+	})
+}
+
+// CreateWebhook registers a validation webhook; the signing secret is only returned in this response
+// @Summary Register settings validation webhook
+// @Description Register an endpoint that validates proposed settings before they are saved
+// @Tags settings-validation-webhooks
+// @Accept json
+// @Produce json
+// @Param request body models.CreateSettingsValidationWebhookRequest true "Webhook"
+// @Success 201 {object} models.SettingsValidationWebhookResponse
+// @Failure 400 {object} models.SettingsValidationWebhookResponse
+// @Router /api/v1/settings-validation-webhooks [post]
+// @Router /api/v1/platform/settings-validation-webhooks [post]
+func (h *SettingsValidationWebhookHandler) CreateWebhook(c *gin.Context) {
+	tenantID, ok := requireValidationWebhookOwner(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSettingsValidationWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.SettingsValidationWebhookResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	webhook, secret, err := h.service.Create(tenantID, &req, getUserID(c))
+	if err != nil {
+		respondValidationWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SettingsValidationWebhookResponse{
+		Success: true,
+		Data:    models.CreatedSettingsValidationWebhook{Webhook: webhook, Secret: secret},
+		Message: "Validation webhook registered; store the secret, it is not shown again",
+	})
+}
+
+// UpdateWebhook changes a validation webhook
+// @Summary Update settings validation webhook
+// @Tags settings-validation-webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param request body models.UpdateSettingsValidationWebhookRequest true "Fields to change"
+// @Success 200 {object} models.SettingsValidationWebhookResponse
+// @Failure 400 {object} models.SettingsValidationWebhookResponse
+// @Failure 404 {object} models.SettingsValidationWebhookResponse
+// @Router /api/v1/settings-validation-webhooks/{id} [put]
+// @Router /api/v1/platform/settings-validation-webhooks/{id} [put]
+func (h *SettingsValidationWebhookHandler) UpdateWebhook(c *gin.Context) {
+	tenantID, ok := requireValidationWebhookOwner(c)
+	if !ok {
+		return
+	}
+	id, ok := parseValidationWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSettingsValidationWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.SettingsValidationWebhookResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	webhook, err := h.service.Update(tenantID, id, &req, getUserID(c))
+	if err != nil {
+		respondValidationWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsValidationWebhookResponse{
+		Success: true,
+		Data:    webhook,
+		Message: "Validation webhook updated",
+	})
+}
+
+// DeleteWebhook removes a validation webhook
+// @Summary Delete settings validation webhook
+// @Tags settings-validation-webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.SettingsValidationWebhookResponse
+// @Failure 404 {object} models.SettingsValidationWebhookResponse
+// @Router /api/v1/settings-validation-webhooks/{id} [delete]
+// @Router /api/v1/platform/settings-validation-webhooks/{id} [delete]
+func (h *SettingsValidationWebhookHandler) DeleteWebhook(c *gin.Context) {
+	tenantID, ok := requireValidationWebhookOwner(c)
+	if !ok {
+		return
+	}
+	id, ok := parseValidationWebhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(tenantID, id); err != nil {
+		respondValidationWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsValidationWebhookResponse{
+		Success: true,
+		Message: "Validation webhook deleted",
+	})
+}
+
+// RotateSecret replaces the signing secret of a validation webhook
+// @Summary Rotate settings validation webhook secret
+// @Tags settings-validation-webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.SettingsValidationWebhookResponse
+// @Failure 404 {object} models.SettingsValidationWebhookResponse
+// @Router /api/v1/settings-validation-webhooks/{id}/rotate-secret [post]
+// @Router /api/v1/platform/settings-validation-webhooks/{id}/rotate-secret [post]
+func (h *SettingsValidationWebhookHandler) RotateSecret(c *gin.Context) {
+	tenantID, ok := requireValidationWebhookOwner(c)
+	if !ok {
+		return
+	}
+	id, ok := parseValidationWebhookID(c)
+	if !ok {
+		return
+	}
+
+	secret, err := h.service.RotateSecret(tenantID, id, getUserID(c))
+	if err != nil {
+		respondValidationWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SettingsValidationWebhookResponse{
+		Success: true,
+		Data:    gin.H{"secret": secret},
+		Message: "Validation webhook secret rotated",
+	})
+}
+
+// requireValidationWebhookOwner returns the tenant whose webhooks are managed, or nil for the
+// platform-wide webhooks on the internal service routes, writing a 400 if the tenant is missing
+func requireValidationWebhookOwner(c *gin.Context) (*uuid.UUID, bool) {
+	if c.GetString("internal_service") != "" {
+		return nil, true
+	}
+
+	tenantID, _ := parseTenantID(c)
+	if tenantID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, models.SettingsValidationWebhookResponse{
+			Success: false,
+			Message: "Tenant ID is required",
+		})
+		return nil, false
+	}
+	return &tenantID, true
+}
+
+// parseValidationWebhookID parses the webhook ID path parameter, writing a 400 if it is malformed
+func parseValidationWebhookID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.SettingsValidationWebhookResponse{
+			Success: false,
+			Message: "Invalid webhook ID",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondValidationWebhookError maps validation webhook service errors to HTTP responses
+func respondValidationWebhookError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidValidationWebhook):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrValidationWebhookNotFound):
+		status = http.StatusNotFound
+	}
+
+	c.JSON(status, models.SettingsValidationWebhookResponse{
+		Success: false,
+		Message: err.Error(),
+	})
+}
+
+// respondSettingsRejected writes a 422 with the violations when a validation webhook rejected
+// a settings write, returning false for any other error
+func respondSettingsRejected(c *gin.Context, err error) bool {
+	var rejected *services.SettingsValidationError
+	if !errors.As(err, &rejected) {
+		return false
+	}
+
+	c.JSON(http.StatusUnprocessableEntity, models.SettingsValidationWebhookResponse{
+		Success: false,
+		Data:    rejected.Violations,
+		Message: rejected.Error(),
+	})
+	return true
+}
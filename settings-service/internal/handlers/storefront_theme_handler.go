@@ -84,7 +84,9 @@ func getUserID(c *gin.Context) *uuid.UUID {
 // @Tags storefront-theme
 // @Produce json
 // @Param tenantId path string true "Storefront ID (path param named tenantId for backward compatibility)"
+// @Param If-None-Match header string false "ETag of a previous response"
 // @Success 200 {object} models.StorefrontThemeResponse
+// @Success 304 "Not modified"
 // @Failure 400 {object} models.StorefrontThemeResponse
 // @Failure 500 {object} models.StorefrontThemeResponse
 // @Router /api/v1/storefront-theme/{tenantId} [get]
@@ -132,7 +134,7 @@ func (h *StorefrontThemeHandler) GetStorefrontTheme(c *gin.Context) {
 		settings.TenantID = tenantID
 	}
 
-	c.JSON(http.StatusOK, models.StorefrontThemeResponse{
+	respondWithETag(c, http.StatusOK, models.StorefrontThemeResponse{
 		Success: true,
 		Data:    settings,
 	})
@@ -294,7 +296,7 @@ func (h *StorefrontThemeHandler) DeleteStorefrontTheme(c *gin.Context) {
 func (h *StorefrontThemeHandler) GetThemePresets(c *gin.Context) {
 	presets := h.service.GetPresets()
 
-	c.JSON(http.StatusOK, gin.H{
+	respondWithETag(c, http.StatusOK, gin.H{
 		"success": true,
 		"data":    presets,
 	})
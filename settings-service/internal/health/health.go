@@ -1,6 +1,7 @@
 package health
 
 import (
+	"context"
 	"net/http"
 	"sync/atomic"
 	"time"
@@ -15,11 +16,17 @@ import (
 // HealthChecker manages health check state and metrics
 type HealthChecker struct {
 	db        *gorm.DB
+	redis     redisChecker // optional; only caches and rate limits depend on it
 	ready     atomic.Bool
 	startTime time.Time
 	version   string
 }
 
+// redisChecker pings Redis; redisconn.Client implements it
+type redisChecker interface {
+	Check(ctx context.Context) error
+}
+
 // Prometheus metrics
 var (
 	httpRequestsTotal = promauto.NewCounterVec(
@@ -67,6 +74,31 @@ var (
 		},
 		[]string{"operation", "status"},
 	)
+
+	cacheRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "settings_service_cache_requests_total",
+			Help: "Total number of read cache lookups by result (hit, miss, error)",
+		},
+		[]string{"cache", "result"},
+	)
+
+	cacheReadDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "settings_service_cache_read_duration_seconds",
+			Help:    "Read cache lookup duration in seconds",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1},
+		},
+		[]string{"cache", "result"},
+	)
+
+	cacheInvalidations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "settings_service_cache_invalidations_total",
+			Help: "Total number of read cache invalidations by source (write, event)",
+		},
+		[]string{"cache", "source"},
+	)
 )
 
 // NewHealthChecker creates a new health checker instance
@@ -83,6 +115,22 @@ func NewHealthChecker(db *gorm.DB, version string) *HealthChecker {
 	return hc
 }
 
+// SetRedis adds Redis to the health report. Redis is optional, so it never fails readiness.
+func (h *HealthChecker) SetRedis(redis redisChecker) {
+	h.redis = redis
+}
+
+// redisStatus reports "connected", "disconnected" or "disabled" when Redis is not in use
+func (h *HealthChecker) redisStatus(ctx context.Context) string {
+	if h.redis == nil {
+		return "disabled"
+	}
+	if err := h.redis.Check(ctx); err != nil {
+		return "disconnected"
+	}
+	return "connected"
+}
+
 // SetReady marks the service as ready to receive traffic
 func (h *HealthChecker) SetReady(ready bool) {
 	h.ready.Store(ready)
@@ -142,6 +190,7 @@ func (h *HealthChecker) ReadyzHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "ready",
+		"redis":  h.redisStatus(c.Request.Context()),
 	})
 }
 
@@ -162,6 +211,9 @@ func (h *HealthChecker) HealthHandler(c *gin.Context) {
 		"database": gin.H{
 			"status": dbStatus,
 		},
+		"redis": gin.H{
+			"status": h.redisStatus(c.Request.Context()),
+		},
 	})
 }
 
@@ -218,3 +270,14 @@ func RecordSettingsOperation(operation string, success bool) {
 	}
 	settingsOperations.WithLabelValues(operation, status).Inc()
 }
+
+// RecordCacheLookup records a read cache lookup and how long it took
+func RecordCacheLookup(cache, result string, duration time.Duration) {
+	cacheRequests.WithLabelValues(cache, result).Inc()
+	cacheReadDuration.WithLabelValues(cache, result).Observe(duration.Seconds())
+}
+
+// RecordCacheInvalidation records a read cache invalidation
+func RecordCacheInvalidation(cache, source string) {
+	cacheInvalidations.WithLabelValues(cache, source).Inc()
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -143,3 +144,62 @@ func InternalServiceMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// ServiceIdentityMiddleware admits only callers whose Istio mTLS identity is allowed.
+// The sidecar sets X-Forwarded-Client-Cert from the verified peer certificate, so unlike
+// X-Internal-Service it cannot be chosen by the caller. Allowed entries are SPIFFE IDs
+// (spiffe://cluster.local/ns/payments/sa/payment-service) or service account names
+func ServiceIdentityMiddleware(allowed []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := peerSPIFFEID(c.GetHeader("X-Forwarded-Client-Cert"))
+		if identity == "" {
+			c.AbortWithStatusJSON(401, gin.H{
+				"success": false,
+				"error":   "Service identity required",
+			})
+			return
+		}
+
+		if !identityAllowed(identity, allowed) {
+			c.AbortWithStatusJSON(403, gin.H{
+				"success": false,
+				"error":   "Unauthorized service",
+			})
+			return
+		}
+
+		c.Set("internal_service", identity)
+		c.Next()
+	}
+}
+
+// peerSPIFFEID returns the SPIFFE URI of the immediate peer from an XFCC header;
+// when proxies append elements, the last one describes the closest hop
+func peerSPIFFEID(xfcc string) string {
+	if xfcc == "" {
+		return ""
+	}
+	elements := strings.Split(xfcc, ",")
+	for _, field := range strings.Split(elements[len(elements)-1], ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok || !strings.EqualFold(key, "URI") {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		if strings.HasPrefix(value, "spiffe://") {
+			return value
+		}
+	}
+	return ""
+}
+
+// identityAllowed matches a SPIFFE ID against full IDs and service account names
+func identityAllowed(identity string, allowed []string) bool {
+	_, serviceAccount, _ := strings.Cut(identity, "/sa/")
+	for _, entry := range allowed {
+		if entry == identity || (serviceAccount != "" && entry == serviceAccount) {
+			return true
+		}
+	}
+	return false
+}
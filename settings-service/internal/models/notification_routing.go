@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Notification delivery channels a route can target
+const (
+	NotificationChannelEmail   = "email"
+	NotificationChannelSMS     = "sms"
+	NotificationChannelPush    = "push"
+	NotificationChannelInApp   = "in_app"
+	NotificationChannelWebhook = "webhook"
+)
+
+// NotificationChannels lists every supported delivery channel
+var NotificationChannels = []string{
+	NotificationChannelEmail,
+	NotificationChannelSMS,
+	NotificationChannelPush,
+	NotificationChannelInApp,
+	NotificationChannelWebhook,
+}
+
+// NotificationRoutingCategory is the settings event category used for routing changes
+const NotificationRoutingCategory = "notification_routing"
+
+// NotificationRouteWildcard matches every event type without a more specific route
+const NotificationRouteWildcard = "*"
+
+// NotificationEventType describes a domain event merchants commonly route
+type NotificationEventType struct {
+	EventType   string `json:"eventType"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+}
+
+// NotificationEventTypes is the catalog shown in the admin routing UI
+// Routes are not limited to these; any event type (or "order.*" style prefix) can be routed
+var NotificationEventTypes = []NotificationEventType{
+	{EventType: "order.created", Label: "New order", Description: "A customer placed an order"},
+	{EventType: "order.cancelled", Label: "Order cancelled", Description: "An order was cancelled"},
+	{EventType: "refund.requested", Label: "Refund requested", Description: "A customer requested a refund"},
+	{EventType: "payment.failed", Label: "Payment failed", Description: "A payment attempt failed"},
+	{EventType: "inventory.low_stock", Label: "Low stock", Description: "A product fell below its stock threshold"},
+	{EventType: "inventory.out_of_stock", Label: "Out of stock", Description: "A product sold out"},
+	{EventType: "review.created", Label: "New review", Description: "A customer submitted a review"},
+	{EventType: "ticket.created", Label: "New support ticket", Description: "A customer opened a support ticket"},
+	{EventType: "vendor.created", Label: "Vendor application", Description: "A vendor applied to the marketplace"},
+}
+
+// NotificationEventAliases maps short event names used by merchants to their domain event type
+var NotificationEventAliases = map[string]string{
+	"low_stock":    "inventory.low_stock",
+	"out_of_stock": "inventory.out_of_stock",
+}
+
+// NotificationRoute maps a domain event type to the channels and recipients notified for a tenant
+type NotificationRoute struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID   uuid.UUID      `json:"tenantId" gorm:"type:uuid;not null;uniqueIndex:idx_notification_route_tenant_event"`
+	EventType  string         `json:"eventType" gorm:"type:varchar(128);not null;uniqueIndex:idx_notification_route_tenant_event"`
+	IsEnabled  bool           `json:"isEnabled" gorm:"default:true"`
+	Channels   datatypes.JSON `json:"channels" gorm:"type:jsonb"`
+	Roles      datatypes.JSON `json:"roles" gorm:"type:jsonb"`
+	UserIDs    datatypes.JSON `json:"userIds" gorm:"type:jsonb"`
+	WebhookURL string         `json:"webhookUrl,omitempty" gorm:"type:varchar(2048)"`
+	CreatedBy  *uuid.UUID     `json:"createdBy,omitempty" gorm:"type:uuid"`
+	UpdatedBy  *uuid.UUID     `json:"updatedBy,omitempty" gorm:"type:uuid"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+}
+
+// TableName returns the table name for the NotificationRoute model
+func (NotificationRoute) TableName() string {
+	return "notification_routes"
+}
+
+// UpsertNotificationRouteRequest represents a request to create or replace a route
+type UpsertNotificationRouteRequest struct {
+	IsEnabled  *bool       `json:"isEnabled,omitempty"`
+	Channels   []string    `json:"channels" binding:"required,min=1"`
+	Roles      []string    `json:"roles,omitempty"`
+	UserIDs    []uuid.UUID `json:"userIds,omitempty"`
+	WebhookURL string      `json:"webhookUrl,omitempty"`
+}
+
+// ResolvedNotificationRoute is the routing decision returned to notification services
+// When Matched is false no route applies and callers fall back to their built-in defaults
+type ResolvedNotificationRoute struct {
+	TenantID     string      `json:"tenantId"`
+	EventType    string      `json:"eventType"`
+	Matched      bool        `json:"matched"`
+	MatchedRoute string      `json:"matchedRoute,omitempty"`
+	Enabled      bool        `json:"enabled"`
+	Channels     []string    `json:"channels"`
+	Roles        []string    `json:"roles"`
+	UserIDs      []uuid.UUID `json:"userIds"`
+	WebhookURL   string      `json:"webhookUrl,omitempty"`
+}
+
+// NotificationRoutingResponse represents the API response for notification routing operations
+type NotificationRoutingResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
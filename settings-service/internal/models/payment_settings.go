@@ -0,0 +1,169 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Supported payment gateway providers
+const (
+	PaymentProviderStripe   = "stripe"
+	PaymentProviderRazorpay = "razorpay"
+	PaymentProviderPayPal   = "paypal"
+)
+
+// Payment gateway modes
+const (
+	PaymentModeTest = "test"
+	PaymentModeLive = "live"
+)
+
+// Connection test results recorded on a gateway config
+const (
+	PaymentTestStatusSuccess = "success"
+	PaymentTestStatusFailed  = "failed"
+)
+
+// PaymentSettingsCategory is the settings event category used for payment gateway changes
+const PaymentSettingsCategory = "payment"
+
+// PaymentGatewayConfig stores a tenant's configuration for a single payment provider
+// Secret credentials are encrypted at rest and never serialized; only masked hints are returned
+type PaymentGatewayConfig struct {
+	ID                   uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID             uuid.UUID      `json:"tenantId" gorm:"type:uuid;not null;uniqueIndex:idx_payment_gateway_tenant_provider"`
+	Provider             string         `json:"provider" gorm:"type:varchar(32);not null;uniqueIndex:idx_payment_gateway_tenant_provider"`
+	Mode                 string         `json:"mode" gorm:"type:varchar(8);not null;default:'test'"`
+	IsEnabled            bool           `json:"isEnabled" gorm:"default:false"`
+	IsDefault            bool           `json:"isDefault" gorm:"default:false"`
+	Config               datatypes.JSON `json:"config" gorm:"type:jsonb"`
+	EncryptedCredentials string         `json:"-" gorm:"type:text"`
+	CredentialHints      datatypes.JSON `json:"credentialHints" gorm:"type:jsonb"`
+	LastTestedAt         *time.Time     `json:"lastTestedAt,omitempty"`
+	LastTestStatus       string         `json:"lastTestStatus,omitempty" gorm:"type:varchar(16)"`
+	LastTestMessage      string         `json:"lastTestMessage,omitempty" gorm:"type:text"`
+	CreatedBy            *uuid.UUID     `json:"createdBy,omitempty" gorm:"type:uuid"`
+	UpdatedBy            *uuid.UUID     `json:"updatedBy,omitempty" gorm:"type:uuid"`
+	CreatedAt            time.Time      `json:"createdAt"`
+	UpdatedAt            time.Time      `json:"updatedAt"`
+	DeletedAt            gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for the PaymentGatewayConfig model
+func (PaymentGatewayConfig) TableName() string {
+	return "payment_gateway_configs"
+}
+
+// PaymentSchemaField describes a single configurable field of a payment provider
+type PaymentSchemaField struct {
+	Key         string `json:"key"`
+	Label       string `json:"label"`
+	Required    bool   `json:"required"`
+	Secret      bool   `json:"secret"`
+	Prefix      string `json:"prefix,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PaymentProviderSchema describes the fields a payment provider accepts
+type PaymentProviderSchema struct {
+	Provider    string               `json:"provider"`
+	DisplayName string               `json:"displayName"`
+	Modes       []string             `json:"modes"`
+	Fields      []PaymentSchemaField `json:"fields"`
+}
+
+// SecretFields returns the schema fields that must be stored encrypted
+func (s PaymentProviderSchema) SecretFields() []PaymentSchemaField {
+	var fields []PaymentSchemaField
+	for _, f := range s.Fields {
+		if f.Secret {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// PaymentProviderSchemas lists the supported payment providers and their fields
+var PaymentProviderSchemas = map[string]PaymentProviderSchema{
+	PaymentProviderStripe: {
+		Provider:    PaymentProviderStripe,
+		DisplayName: "Stripe",
+		Modes:       []string{PaymentModeTest, PaymentModeLive},
+		Fields: []PaymentSchemaField{
+			{Key: "publishableKey", Label: "Publishable key", Required: true, Prefix: "pk_"},
+			{Key: "accountId", Label: "Connected account ID", Prefix: "acct_", Description: "Only required for Stripe Connect"},
+			{Key: "secretKey", Label: "Secret key", Required: true, Secret: true, Prefix: "sk_"},
+			{Key: "webhookSecret", Label: "Webhook signing secret", Secret: true, Prefix: "whsec_"},
+		},
+	},
+	PaymentProviderRazorpay: {
+		Provider:    PaymentProviderRazorpay,
+		DisplayName: "Razorpay",
+		Modes:       []string{PaymentModeTest, PaymentModeLive},
+		Fields: []PaymentSchemaField{
+			{Key: "keyId", Label: "Key ID", Required: true, Prefix: "rzp_"},
+			{Key: "keySecret", Label: "Key secret", Required: true, Secret: true},
+			{Key: "webhookSecret", Label: "Webhook secret", Secret: true},
+		},
+	},
+	PaymentProviderPayPal: {
+		Provider:    PaymentProviderPayPal,
+		DisplayName: "PayPal",
+		Modes:       []string{PaymentModeTest, PaymentModeLive},
+		Fields: []PaymentSchemaField{
+			{Key: "clientId", Label: "Client ID", Required: true},
+			{Key: "merchantId", Label: "Merchant ID"},
+			{Key: "webhookId", Label: "Webhook ID"},
+			{Key: "clientSecret", Label: "Client secret", Required: true, Secret: true},
+		},
+	},
+}
+
+// UpsertPaymentGatewayRequest represents a request to create or update a payment gateway config
+// Omitted credentials keep their stored values so non-secret fields can be edited without re-entering secrets
+type UpsertPaymentGatewayRequest struct {
+	Mode        string            `json:"mode" binding:"required,oneof=test live"`
+	IsEnabled   *bool             `json:"isEnabled,omitempty"`
+	IsDefault   *bool             `json:"isDefault,omitempty"`
+	Config      map[string]string `json:"config,omitempty"`
+	Credentials map[string]string `json:"credentials,omitempty"`
+}
+
+// TestPaymentGatewayRequest represents a request to validate gateway credentials
+// When credentials are omitted the stored credentials are tested
+type TestPaymentGatewayRequest struct {
+	Mode        string            `json:"mode,omitempty" binding:"omitempty,oneof=test live"`
+	Config      map[string]string `json:"config,omitempty"`
+	Credentials map[string]string `json:"credentials,omitempty"`
+}
+
+// PaymentGatewayTestResult is the outcome of a connection test against a provider
+type PaymentGatewayTestResult struct {
+	Provider  string    `json:"provider"`
+	Mode      string    `json:"mode"`
+	Success   bool      `json:"success"`
+	Message   string    `json:"message"`
+	TestedAt  time.Time `json:"testedAt"`
+	LatencyMs int64     `json:"latencyMs"`
+}
+
+// PaymentGatewayCredentials is the decrypted gateway config returned to internal services
+type PaymentGatewayCredentials struct {
+	Provider    string            `json:"provider"`
+	Mode        string            `json:"mode"`
+	IsEnabled   bool              `json:"isEnabled"`
+	IsDefault   bool              `json:"isDefault"`
+	Config      map[string]string `json:"config"`
+	Credentials map[string]string `json:"credentials"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// PaymentSettingsResponse represents the API response for payment settings operations
+type PaymentSettingsResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Targets a scheduled change can apply to
+const (
+	ScheduledTargetSettings        = "settings"
+	ScheduledTargetStorefrontTheme = "storefront_theme"
+)
+
+// Actions a scheduled change can perform
+const (
+	ScheduledActionUpdate      = "update"       // Apply the stored partial update
+	ScheduledActionApplyPreset = "apply_preset" // Apply a settings or theme preset
+)
+
+// Scheduled change lifecycle
+const (
+	ScheduledStatusPending   = "pending"
+	ScheduledStatusApplying  = "applying"
+	ScheduledStatusApplied   = "applied"
+	ScheduledStatusCancelled = "cancelled"
+	ScheduledStatusFailed    = "failed"
+)
+
+// ScheduledSettingsChange is a settings or theme change staged now and applied at ApplyAt
+// ScheduledFor and Timezone keep the wall-clock time the merchant chose; ApplyAt is the UTC instant
+type ScheduledSettingsChange struct {
+	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID     uuid.UUID      `json:"tenantId" gorm:"type:uuid;not null;index"`
+	TargetType   string         `json:"targetType" gorm:"type:varchar(32);not null"`
+	TargetID     uuid.UUID      `json:"targetId" gorm:"type:uuid;not null;index"`
+	Action       string         `json:"action" gorm:"type:varchar(32);not null;default:'update'"`
+	PresetID     string         `json:"presetId,omitempty" gorm:"type:varchar(64)"`
+	Payload      datatypes.JSON `json:"payload,omitempty" gorm:"type:jsonb"`
+	Description  string         `json:"description,omitempty" gorm:"type:varchar(255)"`
+	ScheduledFor string         `json:"scheduledFor" gorm:"type:varchar(32);not null"`
+	Timezone     string         `json:"timezone" gorm:"type:varchar(64);not null"`
+	ApplyAt      time.Time      `json:"applyAt" gorm:"not null;index:idx_scheduled_change_due,priority:2"`
+	Status       string         `json:"status" gorm:"type:varchar(16);not null;default:'pending';index:idx_scheduled_change_due,priority:1"`
+	Attempts     int            `json:"attempts" gorm:"default:0"`
+	LastError    string         `json:"lastError,omitempty" gorm:"type:text"`
+	AppliedAt    *time.Time     `json:"appliedAt,omitempty"`
+	CancelledAt  *time.Time     `json:"cancelledAt,omitempty"`
+	CancelledBy  *uuid.UUID     `json:"cancelledBy,omitempty" gorm:"type:uuid"`
+	CreatedBy    *uuid.UUID     `json:"createdBy,omitempty" gorm:"type:uuid"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+}
+
+// TableName returns the table name for the ScheduledSettingsChange model
+func (ScheduledSettingsChange) TableName() string {
+	return "scheduled_settings_changes"
+}
+
+// CreateScheduledChangeRequest represents a request to schedule a settings or theme change
+// ScheduledFor is a local date-time ("2026-12-01T00:00") interpreted in Timezone; when Timezone
+// is omitted the tenant's localization timezone is used. An RFC3339 timestamp with an offset is
+// also accepted and used as-is.
+type CreateScheduledChangeRequest struct {
+	TargetType   string                        `json:"targetType" binding:"required,oneof=settings storefront_theme"`
+	TargetID     uuid.UUID                     `json:"targetId" binding:"required"`
+	Action       string                        `json:"action,omitempty" binding:"omitempty,oneof=update apply_preset"`
+	PresetID     string                        `json:"presetId,omitempty"`
+	Settings     *UpdateSettingsRequest        `json:"settings,omitempty"`
+	Theme        *UpdateStorefrontThemeRequest `json:"theme,omitempty"`
+	ScheduledFor string                        `json:"scheduledFor" binding:"required"`
+	Timezone     string                        `json:"timezone,omitempty"`
+	Description  string                        `json:"description,omitempty" binding:"max=255"`
+}
+
+// ScheduledChangeResponse represents the API response for scheduled change operations
+type ScheduledChangeResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
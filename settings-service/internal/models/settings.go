@@ -12,6 +12,9 @@ import (
 // CORE SETTINGS MODELS
 // ==========================================
 
+// SettingsCategory is the settings event category used for core settings changes
+const SettingsCategory = "settings"
+
 type SettingsContext struct {
 	TenantID      uuid.UUID `json:"tenantId" gorm:"type:uuid;not null"`
 	ApplicationID uuid.UUID `json:"applicationId" gorm:"type:uuid;not null"`
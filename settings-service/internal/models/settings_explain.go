@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ==========================================
+// SETTINGS RESOLUTION DIAGNOSTICS
+// ==========================================
+
+// Resolution levels, from least to most specific
+const (
+	SettingsLevelPlatform   = "platform"
+	SettingsLevelTenant     = "tenant"
+	SettingsLevelStorefront = "storefront"
+	SettingsLevelUser       = "user"
+)
+
+// Where a resolution level got its value from
+const (
+	SettingsSourceRecord         = "settings"         // A stored settings record
+	SettingsSourceBuiltInDefault = "built_in_default" // The service's hard-coded defaults
+	SettingsSourceNone           = "none"             // No settings at this level
+)
+
+// SettingsPresetSource identifies the preset that set a value
+type SettingsPresetSource struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+// SettingsResolutionStep is one level of the inheritance chain for a key
+type SettingsResolutionStep struct {
+	Level      string                `json:"level"`
+	Scope      string                `json:"scope"`
+	Source     string                `json:"source"`
+	SettingsID *uuid.UUID            `json:"settingsId,omitempty"`
+	HasValue   bool                  `json:"hasValue"`
+	Value      interface{}           `json:"value,omitempty"`
+	UpdatedAt  *time.Time            `json:"updatedAt,omitempty"`
+	Preset     *SettingsPresetSource `json:"preset,omitempty"`
+	Skipped    string                `json:"skipped,omitempty"` // Why the level was not consulted
+}
+
+// SettingsExplanation explains how the effective value of a setting was resolved
+type SettingsExplanation struct {
+	Key            string                   `json:"key"`
+	EffectiveValue interface{}              `json:"effectiveValue"`
+	EffectiveLevel string                   `json:"effectiveLevel,omitempty"` // Most specific level that has the key
+	Preset         *SettingsPresetSource    `json:"preset,omitempty"`         // Preset that set the effective value
+	ServedLevel    string                   `json:"servedLevel,omitempty"`    // Level whose record /settings/inherited returns
+	Chain          []SettingsResolutionStep `json:"chain"`
+}
+
+type SettingsExplainResponse struct {
+	Success bool                `json:"success"`
+	Data    SettingsExplanation `json:"data,omitempty"`
+	Message string              `json:"message,omitempty"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettingsSnapshotFormatVersion is the version of SettingsSnapshotContent written by this service
+const SettingsSnapshotFormatVersion = 1
+
+// Targets a snapshot diff entry can refer to
+const (
+	SnapshotTargetSettings        = "settings"
+	SnapshotTargetStorefrontTheme = "storefront_theme"
+)
+
+// Kinds of snapshot diff entries
+const (
+	SnapshotChangeAdded   = "added"
+	SnapshotChangeRemoved = "removed"
+	SnapshotChangeChanged = "changed"
+)
+
+// SettingsSnapshot is a named, gzip-compressed copy of all of a tenant's settings and storefront
+// themes, taken e.g. before a deploy or a risky migration and restorable as a whole.
+// DeployRef binds the snapshot to the release it was taken for (git SHA, tag or deploy ID).
+type SettingsSnapshot struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID        uuid.UUID  `json:"tenantId" gorm:"type:uuid;not null;uniqueIndex:idx_settings_snapshot_tenant_name,priority:1"`
+	Name            string     `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_settings_snapshot_tenant_name,priority:2"`
+	Description     string     `json:"description,omitempty" gorm:"type:varchar(500)"`
+	DeployRef       string     `json:"deployRef,omitempty" gorm:"type:varchar(255);index"`
+	Automatic       bool       `json:"automatic" gorm:"default:false"` // Taken by the service before a restore
+	FormatVersion   int        `json:"formatVersion" gorm:"not null;default:1"`
+	Data            []byte     `json:"-" gorm:"type:bytea;not null"`              // gzip-compressed SettingsSnapshotContent
+	Checksum        string     `json:"checksum" gorm:"type:varchar(64);not null"` // SHA-256 of the uncompressed content
+	SizeBytes       int        `json:"sizeBytes"`
+	CompressedBytes int        `json:"compressedBytes"`
+	SettingsCount   int        `json:"settingsCount"`
+	ThemeCount      int        `json:"themeCount"`
+	CreatedBy       *uuid.UUID `json:"createdBy,omitempty" gorm:"type:uuid"`
+	CreatedAt       time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	LastRestoredAt  *time.Time `json:"lastRestoredAt,omitempty"`
+	LastRestoredBy  *uuid.UUID `json:"lastRestoredBy,omitempty" gorm:"type:uuid"`
+}
+
+// TableName returns the table name for the SettingsSnapshot model
+func (SettingsSnapshot) TableName() string {
+	return "settings_snapshots"
+}
+
+// SettingsSnapshotContent is the uncompressed body of a snapshot
+type SettingsSnapshotContent struct {
+	FormatVersion int                       `json:"formatVersion"`
+	TakenAt       time.Time                 `json:"takenAt"`
+	Settings      []Settings                `json:"settings"`
+	Themes        []StorefrontThemeSettings `json:"themes"`
+}
+
+// CreateSettingsSnapshotRequest represents a request to snapshot the tenant's settings and themes
+type CreateSettingsSnapshotRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description,omitempty" binding:"max=500"`
+	DeployRef   string `json:"deployRef,omitempty" binding:"max=255"`
+}
+
+// RestoreSettingsSnapshotResult describes a completed restore
+type RestoreSettingsSnapshotResult struct {
+	Snapshot         *SettingsSnapshot `json:"snapshot"`
+	PreRestoreID     uuid.UUID         `json:"preRestoreSnapshotId"` // Automatic snapshot of the state before the restore
+	SettingsRestored int               `json:"settingsRestored"`
+	SettingsRemoved  int               `json:"settingsRemoved"` // Settings created after the snapshot, soft-deleted
+	ThemesRestored   int               `json:"themesRestored"`
+	ThemesRemoved    int               `json:"themesRemoved"`
+}
+
+// SettingsSnapshotChange is one difference between two snapshots
+// Path is a dotted JSON path within the record, empty when the whole record was added or removed
+type SettingsSnapshotChange struct {
+	Target   string      `json:"target"`
+	RecordID uuid.UUID   `json:"recordId"`
+	Label    string      `json:"label"` // Scope of settings, storefront ID of themes
+	Change   string      `json:"change"`
+	Path     string      `json:"path,omitempty"`
+	Before   interface{} `json:"before,omitempty"`
+	After    interface{} `json:"after,omitempty"`
+}
+
+// SettingsSnapshotDiff is the difference between two snapshots, or a snapshot and the current state
+type SettingsSnapshotDiff struct {
+	From    string                   `json:"from"` // Snapshot name, or "current"
+	To      string                   `json:"to"`
+	Changes []SettingsSnapshotChange `json:"changes"`
+}
+
+// SettingsSnapshotResponse represents the API response for settings snapshot operations
+type SettingsSnapshotResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
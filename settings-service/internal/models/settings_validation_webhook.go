@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Default and bounds for how long settings writes wait on a validation webhook
+const (
+	DefaultValidationWebhookTimeoutMs = 3000
+	MinValidationWebhookTimeoutMs     = 500
+	MaxValidationWebhookTimeoutMs     = 10000
+)
+
+// Outcomes recorded for the last call of a validation webhook
+const (
+	ValidationWebhookStatusAccepted = "accepted"
+	ValidationWebhookStatusRejected = "rejected"
+	ValidationWebhookStatusFailed   = "failed"
+)
+
+// SettingsValidationWebhook validates proposed settings before they are persisted.
+// A webhook without a tenant is platform-wide and is registered by internal product services;
+// ApplicationID, Scope and Sections narrow which settings writes it is called for.
+type SettingsValidationWebhook struct {
+	ID            uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID      *uuid.UUID     `json:"tenantId,omitempty" gorm:"type:uuid;index"`
+	ApplicationID *uuid.UUID     `json:"applicationId,omitempty" gorm:"type:uuid;index"`
+	Scope         string         `json:"scope,omitempty" gorm:"type:varchar(50)"`
+	Sections      datatypes.JSON `json:"sections" gorm:"type:jsonb"`
+	Name          string         `json:"name" gorm:"type:varchar(255);not null"`
+	URL           string         `json:"url" gorm:"type:varchar(2048);not null"`
+	Secret        string         `json:"-" gorm:"type:varchar(128);not null"`
+	TimeoutMs     int            `json:"timeoutMs" gorm:"default:3000"`
+	FailOpen      bool           `json:"failOpen" gorm:"default:false"`
+	IsEnabled     bool           `json:"isEnabled" gorm:"default:true"`
+	LastCalledAt  *time.Time     `json:"lastCalledAt,omitempty"`
+	LastStatus    string         `json:"lastStatus,omitempty" gorm:"type:varchar(20)"`
+	LastError     string         `json:"lastError,omitempty" gorm:"type:varchar(1000)"`
+	CreatedBy     *uuid.UUID     `json:"createdBy,omitempty" gorm:"type:uuid"`
+	UpdatedBy     *uuid.UUID     `json:"updatedBy,omitempty" gorm:"type:uuid"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+}
+
+// TableName returns the table name for the SettingsValidationWebhook model
+func (SettingsValidationWebhook) TableName() string {
+	return "settings_validation_webhooks"
+}
+
+// CreateSettingsValidationWebhookRequest represents a request to register a validation webhook
+type CreateSettingsValidationWebhookRequest struct {
+	Name          string     `json:"name" binding:"required"`
+	URL           string     `json:"url" binding:"required"`
+	ApplicationID *uuid.UUID `json:"applicationId,omitempty"`
+	Scope         string     `json:"scope,omitempty"`
+	Sections      []string   `json:"sections,omitempty"`
+	TimeoutMs     int        `json:"timeoutMs,omitempty"`
+	FailOpen      bool       `json:"failOpen,omitempty"`
+	IsEnabled     *bool      `json:"isEnabled,omitempty"`
+}
+
+// UpdateSettingsValidationWebhookRequest represents a partial update of a validation webhook
+type UpdateSettingsValidationWebhookRequest struct {
+	Name          *string    `json:"name,omitempty"`
+	URL           *string    `json:"url,omitempty"`
+	ApplicationID *uuid.UUID `json:"applicationId,omitempty"`
+	Scope         *string    `json:"scope,omitempty"`
+	Sections      *[]string  `json:"sections,omitempty"`
+	TimeoutMs     *int       `json:"timeoutMs,omitempty"`
+	FailOpen      *bool      `json:"failOpen,omitempty"`
+	IsEnabled     *bool      `json:"isEnabled,omitempty"`
+}
+
+// CreatedSettingsValidationWebhook is returned once on registration; the signing secret is not shown again
+type CreatedSettingsValidationWebhook struct {
+	Webhook *SettingsValidationWebhook `json:"webhook"`
+	Secret  string                     `json:"secret"`
+}
+
+// SettingsValidationRequest is the payload POSTed to a validation webhook.
+// Proposed and Current only hold the sections the webhook watches; Current is empty on create.
+type SettingsValidationRequest struct {
+	ID              uuid.UUID                 `json:"id"`
+	Operation       string                    `json:"operation"`
+	SettingsID      uuid.UUID                 `json:"settingsId"`
+	Context         SettingsContext           `json:"context"`
+	ChangedSections []string                  `json:"changedSections"`
+	Proposed        map[string]datatypes.JSON `json:"proposed"`
+	Current         map[string]datatypes.JSON `json:"current,omitempty"`
+	Timestamp       time.Time                 `json:"timestamp"`
+}
+
+// SettingsValidationResult is the response expected from a validation webhook
+type SettingsValidationResult struct {
+	Valid  bool                          `json:"valid"`
+	Errors []SettingsValidationViolation `json:"errors,omitempty"`
+}
+
+// SettingsValidationViolation is a single problem a validation webhook found in the proposed settings
+type SettingsValidationViolation struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	Webhook string `json:"webhook,omitempty"`
+}
+
+// SettingsValidationWebhookResponse represents the API response for validation webhook operations
+type SettingsValidationWebhookResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
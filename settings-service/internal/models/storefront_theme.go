@@ -8,6 +8,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// StorefrontThemeCategory is the settings event category used for storefront theme changes
+const StorefrontThemeCategory = "storefront_theme"
+
 // StorefrontThemeSettings represents the storefront theme configuration
 // This model stores storefront-specific theme settings
 // Each tenant (vendor) can have multiple storefronts, each with its own theme
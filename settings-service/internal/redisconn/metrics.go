@@ -0,0 +1,166 @@
+package redisconn
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	namespace = "tesseract"
+	subsystem = "redis"
+)
+
+var (
+	commandsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "commands_total",
+			Help:      "Redis commands by client, command and result (ok, error); a missing key counts as ok",
+		},
+		[]string{"client", "command", "result"},
+	)
+
+	commandDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "command_duration_seconds",
+			Help:      "Duration of Redis round trips by client and kind (command, pipeline)",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+		[]string{"client", "kind"},
+	)
+
+	up = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "up",
+			Help:      "Whether the last health check reached Redis (1) or not (0)",
+		},
+		[]string{"client"},
+	)
+)
+
+// metricsHook counts and times the commands a client sends
+type metricsHook struct {
+	name string
+}
+
+func (h metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		commandDuration.WithLabelValues(h.name, "command").Observe(time.Since(start).Seconds())
+		commandsTotal.WithLabelValues(h.name, cmd.Name(), commandResult(err)).Inc()
+		return err
+	}
+}
+
+func (h metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		commandDuration.WithLabelValues(h.name, "pipeline").Observe(time.Since(start).Seconds())
+		for _, cmd := range cmds {
+			commandsTotal.WithLabelValues(h.name, cmd.Name(), commandResult(cmd.Err())).Inc()
+		}
+		return err
+	}
+}
+
+func commandResult(err error) string {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return "ok"
+	}
+	return "error"
+}
+
+// poolCollector reports a client's connection pool statistics
+type poolCollector struct {
+	client redis.UniversalClient
+
+	hits     *prometheus.Desc
+	misses   *prometheus.Desc
+	timeouts *prometheus.Desc
+	conns    *prometheus.Desc
+	stale    *prometheus.Desc
+}
+
+var (
+	// poolCollectorsMu guards poolCollectors, the collector registered per client name
+	poolCollectorsMu sync.Mutex
+	poolCollectors   = map[string]*poolCollector{}
+)
+
+// registerPoolCollector reports client's pool under name, replacing a previous client of
+// the same name
+func registerPoolCollector(name string, client redis.UniversalClient) *poolCollector {
+	labels := prometheus.Labels{"client": name}
+	desc := func(metric, help string, variable ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, metric), help, variable, labels)
+	}
+	collector := &poolCollector{
+		client:   client,
+		hits:     desc("pool_hits_total", "Times a free connection was found in the pool"),
+		misses:   desc("pool_misses_total", "Times no free connection was found in the pool"),
+		timeouts: desc("pool_timeouts_total", "Times waiting for a pool connection timed out"),
+		conns:    desc("pool_connections", "Connections in the pool by state (total, idle)", "state"),
+		stale:    desc("pool_stale_connections_total", "Stale connections removed from the pool"),
+	}
+
+	poolCollectorsMu.Lock()
+	defer poolCollectorsMu.Unlock()
+	if previous, ok := poolCollectors[name]; ok {
+		prometheus.Unregister(previous)
+	}
+	if err := prometheus.Register(collector); err != nil {
+		return collector
+	}
+	poolCollectors[name] = collector
+	return collector
+}
+
+// unregister stops reporting the pool, unless another client has taken over the name
+func (p *poolCollector) unregister() {
+	poolCollectorsMu.Lock()
+	defer poolCollectorsMu.Unlock()
+	for name, collector := range poolCollectors {
+		if collector == p {
+			prometheus.Unregister(p)
+			delete(poolCollectors, name)
+		}
+	}
+}
+
+func (p *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.hits
+	ch <- p.misses
+	ch <- p.timeouts
+	ch <- p.conns
+	ch <- p.stale
+}
+
+func (p *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := p.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(p.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(p.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(p.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(p.conns, prometheus.GaugeValue, float64(stats.TotalConns), "total")
+	ch <- prometheus.MustNewConstMetric(p.conns, prometheus.GaugeValue, float64(stats.IdleConns), "idle")
+	ch <- prometheus.MustNewConstMetric(p.stale, prometheus.CounterValue, float64(stats.StaleConns))
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"settings-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// NotificationRoutingRepository defines the interface for notification route data access
+type NotificationRoutingRepository interface {
+	// GetByEventType retrieves a tenant's route for a single event type
+	GetByEventType(tenantID uuid.UUID, eventType string) (*models.NotificationRoute, error)
+
+	// FindByEventTypes retrieves a tenant's routes for any of the given event types
+	FindByEventTypes(tenantID uuid.UUID, eventTypes []string) ([]models.NotificationRoute, error)
+
+	// ListByTenant retrieves all routes for a tenant
+	ListByTenant(tenantID uuid.UUID) ([]models.NotificationRoute, error)
+
+	// Save creates or updates a route
+	Save(route *models.NotificationRoute) error
+
+	// Delete removes a tenant's route for an event type
+	Delete(tenantID uuid.UUID, eventType string) error
+}
+
+type notificationRoutingRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRoutingRepository creates a new notification routing repository
+func NewNotificationRoutingRepository(db *gorm.DB) NotificationRoutingRepository {
+	return &notificationRoutingRepository{db: db}
+}
+
+// GetByEventType retrieves a tenant's route for a single event type
+func (r *notificationRoutingRepository) GetByEventType(tenantID uuid.UUID, eventType string) (*models.NotificationRoute, error) {
+	var route models.NotificationRoute
+	err := r.db.Where("tenant_id = ? AND event_type = ?", tenantID, eventType).
+		First(&route).Error
+	if err != nil {
+		return nil, err
+	}
+	return &route, nil
+}
+
+// FindByEventTypes retrieves a tenant's routes for any of the given event types
+func (r *notificationRoutingRepository) FindByEventTypes(tenantID uuid.UUID, eventTypes []string) ([]models.NotificationRoute, error) {
+	var routes []models.NotificationRoute
+	err := r.db.Where("tenant_id = ? AND event_type IN ?", tenantID, eventTypes).
+		Find(&routes).Error
+	if err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// ListByTenant retrieves all routes for a tenant
+func (r *notificationRoutingRepository) ListByTenant(tenantID uuid.UUID) ([]models.NotificationRoute, error) {
+	var routes []models.NotificationRoute
+	err := r.db.Where("tenant_id = ?", tenantID).
+		Order("event_type ASC").
+		Find(&routes).Error
+	if err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// Save creates or updates a route
+func (r *notificationRoutingRepository) Save(route *models.NotificationRoute) error {
+	if route.ID == uuid.Nil {
+		route.ID = uuid.New()
+	}
+
+	return r.db.Save(route).Error
+}
+
+// Delete removes a tenant's route for an event type
+func (r *notificationRoutingRepository) Delete(tenantID uuid.UUID, eventType string) error {
+	result := r.db.
+		Where("tenant_id = ? AND event_type = ?", tenantID, eventType).
+		Delete(&models.NotificationRoute{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
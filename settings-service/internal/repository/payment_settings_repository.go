@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"settings-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// PaymentSettingsRepository defines the interface for payment gateway config data access
+type PaymentSettingsRepository interface {
+	// GetByProvider retrieves a tenant's config for a single provider
+	GetByProvider(tenantID uuid.UUID, provider string) (*models.PaymentGatewayConfig, error)
+
+	// ListByTenant retrieves all gateway configs for a tenant
+	ListByTenant(tenantID uuid.UUID) ([]models.PaymentGatewayConfig, error)
+
+	// Save creates or updates a gateway config
+	// When the config is the tenant default, every other provider loses its default flag
+	Save(config *models.PaymentGatewayConfig) error
+
+	// UpdateTestResult records the outcome of a connection test
+	UpdateTestResult(config *models.PaymentGatewayConfig) error
+
+	// Delete permanently removes a gateway config so encrypted credentials are not retained
+	Delete(tenantID uuid.UUID, provider string) error
+}
+
+type paymentSettingsRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentSettingsRepository creates a new payment settings repository
+func NewPaymentSettingsRepository(db *gorm.DB) PaymentSettingsRepository {
+	return &paymentSettingsRepository{db: db}
+}
+
+// GetByProvider retrieves a tenant's config for a single provider
+func (r *paymentSettingsRepository) GetByProvider(tenantID uuid.UUID, provider string) (*models.PaymentGatewayConfig, error) {
+	var config models.PaymentGatewayConfig
+	err := r.db.Where("tenant_id = ? AND provider = ?", tenantID, provider).
+		First(&config).Error
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// ListByTenant retrieves all gateway configs for a tenant
+func (r *paymentSettingsRepository) ListByTenant(tenantID uuid.UUID) ([]models.PaymentGatewayConfig, error) {
+	var configs []models.PaymentGatewayConfig
+	err := r.db.Where("tenant_id = ?", tenantID).
+		Order("is_default DESC, provider ASC").
+		Find(&configs).Error
+	if err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// Save creates or updates a gateway config
+func (r *paymentSettingsRepository) Save(config *models.PaymentGatewayConfig) error {
+	if config.ID == uuid.Nil {
+		config.ID = uuid.New()
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if config.IsDefault {
+			if err := tx.Model(&models.PaymentGatewayConfig{}).
+				Where("tenant_id = ? AND provider <> ?", config.TenantID, config.Provider).
+				Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(config).Error
+	})
+}
+
+// UpdateTestResult records the outcome of a connection test
+func (r *paymentSettingsRepository) UpdateTestResult(config *models.PaymentGatewayConfig) error {
+	return r.db.Model(&models.PaymentGatewayConfig{}).
+		Where("id = ?", config.ID).
+		Updates(map[string]interface{}{
+			"last_tested_at":    config.LastTestedAt,
+			"last_test_status":  config.LastTestStatus,
+			"last_test_message": config.LastTestMessage,
+		}).Error
+}
+
+// Delete permanently removes a gateway config
+func (r *paymentSettingsRepository) Delete(tenantID uuid.UUID, provider string) error {
+	result := r.db.Unscoped().
+		Where("tenant_id = ? AND provider = ?", tenantID, provider).
+		Delete(&models.PaymentGatewayConfig{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
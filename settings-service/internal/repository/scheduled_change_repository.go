@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"settings-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// ScheduledChangeRepository defines the interface for scheduled settings change data access
+type ScheduledChangeRepository interface {
+	// Create stores a new scheduled change
+	Create(change *models.ScheduledSettingsChange) error
+
+	// GetByID retrieves a tenant's scheduled change
+	GetByID(tenantID, id uuid.UUID) (*models.ScheduledSettingsChange, error)
+
+	// ListByTenant retrieves a tenant's scheduled changes, optionally filtered by status
+	ListByTenant(tenantID uuid.UUID, status string) ([]models.ScheduledSettingsChange, error)
+
+	// ClaimDue marks up to limit due pending changes as applying and returns them
+	// Changes stuck in applying for longer than staleAfter are reclaimed
+	ClaimDue(now time.Time, staleAfter time.Duration, limit int) ([]models.ScheduledSettingsChange, error)
+
+	// SaveResult records the outcome of applying a change
+	SaveResult(change *models.ScheduledSettingsChange) error
+
+	// Cancel marks a pending change as cancelled; returns gorm.ErrRecordNotFound if it is not pending
+	Cancel(tenantID, id uuid.UUID, cancelledBy *uuid.UUID) error
+}
+
+type scheduledChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduledChangeRepository creates a new scheduled change repository
+func NewScheduledChangeRepository(db *gorm.DB) ScheduledChangeRepository {
+	return &scheduledChangeRepository{db: db}
+}
+
+// Create stores a new scheduled change
+func (r *scheduledChangeRepository) Create(change *models.ScheduledSettingsChange) error {
+	if change.ID == uuid.Nil {
+		change.ID = uuid.New()
+	}
+	return r.db.Create(change).Error
+}
+
+// GetByID retrieves a tenant's scheduled change
+func (r *scheduledChangeRepository) GetByID(tenantID, id uuid.UUID) (*models.ScheduledSettingsChange, error) {
+	var change models.ScheduledSettingsChange
+	err := r.db.Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&change).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// ListByTenant retrieves a tenant's scheduled changes, soonest first
+func (r *scheduledChangeRepository) ListByTenant(tenantID uuid.UUID, status string) ([]models.ScheduledSettingsChange, error) {
+	var changes []models.ScheduledSettingsChange
+	query := r.db.Where("tenant_id = ?", tenantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("apply_at ASC").Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ClaimDue marks up to limit due changes as applying and returns them
+// Each row is claimed with a conditional update so concurrent replicas never apply a change twice
+func (r *scheduledChangeRepository) ClaimDue(now time.Time, staleAfter time.Duration, limit int) ([]models.ScheduledSettingsChange, error) {
+	var candidates []models.ScheduledSettingsChange
+	err := r.db.
+		Where("(status = ? AND apply_at <= ?) OR (status = ? AND updated_at < ?)",
+			models.ScheduledStatusPending, now, models.ScheduledStatusApplying, now.Add(-staleAfter)).
+		Order("apply_at ASC").
+		Limit(limit).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]models.ScheduledSettingsChange, 0, len(candidates))
+	for _, change := range candidates {
+		result := r.db.Model(&models.ScheduledSettingsChange{}).
+			Where("id = ? AND status = ? AND updated_at = ?", change.ID, change.Status, change.UpdatedAt).
+			Updates(map[string]interface{}{
+				"status":     models.ScheduledStatusApplying,
+				"attempts":   gorm.Expr("attempts + 1"),
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue // Claimed by another replica or cancelled meanwhile
+		}
+		change.Status = models.ScheduledStatusApplying
+		change.Attempts++
+		change.UpdatedAt = now
+		claimed = append(claimed, change)
+	}
+	return claimed, nil
+}
+
+// SaveResult records the outcome of applying a change
+func (r *scheduledChangeRepository) SaveResult(change *models.ScheduledSettingsChange) error {
+	return r.db.Model(&models.ScheduledSettingsChange{}).
+		Where("id = ?", change.ID).
+		Updates(map[string]interface{}{
+			"status":     change.Status,
+			"last_error": change.LastError,
+			"applied_at": change.AppliedAt,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// Cancel marks a pending change as cancelled
+func (r *scheduledChangeRepository) Cancel(tenantID, id uuid.UUID, cancelledBy *uuid.UUID) error {
+	now := time.Now()
+	result := r.db.Model(&models.ScheduledSettingsChange{}).
+		Where("id = ? AND tenant_id = ? AND status = ?", id, tenantID, models.ScheduledStatusPending).
+		Updates(map[string]interface{}{
+			"status":       models.ScheduledStatusCancelled,
+			"cancelled_at": now,
+			"cancelled_by": cancelledBy,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ type SettingsRepository interface {
 	// History operations
 	CreateHistory(history *models.SettingsHistory) error
 	GetHistory(settingsID uuid.UUID, limit int) ([]models.SettingsHistory, error)
+	GetLatestHistoryByOperation(settingsID uuid.UUID, operation string) (*models.SettingsHistory, error)
 }
 
 type settingsRepository struct {
@@ -249,4 +250,17 @@ func (r *settingsRepository) GetHistory(settingsID uuid.UUID, limit int) ([]mode
 		Find(&history).Error
 		
 	return history, err
-}
\ No newline at end of file
+}
+
+// GetLatestHistoryByOperation returns the most recent history entry of an operation, or nil if there is none
+func (r *settingsRepository) GetLatestHistoryByOperation(settingsID uuid.UUID, operation string) (*models.SettingsHistory, error) {
+	var history []models.SettingsHistory
+	err := r.db.Where("settings_id = ? AND operation = ?", settingsID, operation).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&history).Error
+	if err != nil || len(history) == 0 {
+		return nil, err
+	}
+	return &history[0], nil
+}
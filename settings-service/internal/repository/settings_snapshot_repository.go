@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"settings-service/internal/models"
+)
+
+// automaticSnapshotsKept bounds how many automatic pre-restore snapshots a tenant keeps
+const automaticSnapshotsKept = 10
+
+// SettingsSnapshotRepository defines the interface for settings snapshot data access
+type SettingsSnapshotRepository interface {
+	// Create stores a new snapshot
+	Create(snapshot *models.SettingsSnapshot) error
+
+	// GetByID retrieves a tenant's snapshot including its data
+	GetByID(tenantID, id uuid.UUID) (*models.SettingsSnapshot, error)
+
+	// ListByTenant retrieves a tenant's snapshots without their data, newest first,
+	// optionally filtered by deploy reference
+	ListByTenant(tenantID uuid.UUID, deployRef string) ([]models.SettingsSnapshot, error)
+
+	// Delete removes a tenant's snapshot; returns gorm.ErrRecordNotFound if it does not exist
+	Delete(tenantID, id uuid.UUID) error
+
+	// LoadTenantState reads all of a tenant's live settings and storefront themes
+	LoadTenantState(tenantID uuid.UUID) (*models.SettingsSnapshotContent, error)
+
+	// Restore replaces a tenant's settings and storefront themes with the snapshot content in
+	// one transaction. preRestore is called with the locked current state and returns the
+	// automatic snapshot saved alongside the restore.
+	Restore(snapshot *models.SettingsSnapshot, content *models.SettingsSnapshotContent, userID *uuid.UUID,
+		preRestore func(current *models.SettingsSnapshotContent) (*models.SettingsSnapshot, error)) (*SnapshotRestore, error)
+}
+
+// SnapshotRestore lists the records written and removed by a restore
+type SnapshotRestore struct {
+	PreRestore      *models.SettingsSnapshot
+	Settings        []models.Settings
+	RemovedSettings []models.Settings
+	Themes          []models.StorefrontThemeSettings
+	RemovedThemes   []models.StorefrontThemeSettings
+}
+
+type settingsSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewSettingsSnapshotRepository creates a new settings snapshot repository
+func NewSettingsSnapshotRepository(db *gorm.DB) SettingsSnapshotRepository {
+	return &settingsSnapshotRepository{db: db}
+}
+
+// Create stores a new snapshot
+func (r *settingsSnapshotRepository) Create(snapshot *models.SettingsSnapshot) error {
+	if snapshot.ID == uuid.Nil {
+		snapshot.ID = uuid.New()
+	}
+	return r.db.Create(snapshot).Error
+}
+
+// GetByID retrieves a tenant's snapshot including its data
+func (r *settingsSnapshotRepository) GetByID(tenantID, id uuid.UUID) (*models.SettingsSnapshot, error) {
+	var snapshot models.SettingsSnapshot
+	err := r.db.Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// ListByTenant retrieves a tenant's snapshots without their data, newest first
+func (r *settingsSnapshotRepository) ListByTenant(tenantID uuid.UUID, deployRef string) ([]models.SettingsSnapshot, error) {
+	var snapshots []models.SettingsSnapshot
+	query := r.db.Omit("data").Where("tenant_id = ?", tenantID)
+	if deployRef != "" {
+		query = query.Where("deploy_ref = ?", deployRef)
+	}
+	err := query.Order("created_at DESC").Find(&snapshots).Error
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// Delete removes a tenant's snapshot
+func (r *settingsSnapshotRepository) Delete(tenantID, id uuid.UUID) error {
+	result := r.db.Where("id = ? AND tenant_id = ?", id, tenantID).Delete(&models.SettingsSnapshot{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// LoadTenantState reads all of a tenant's live settings and storefront themes
+func (r *settingsSnapshotRepository) LoadTenantState(tenantID uuid.UUID) (*models.SettingsSnapshotContent, error) {
+	return loadTenantState(r.db, tenantID)
+}
+
+func loadTenantState(db *gorm.DB, tenantID uuid.UUID) (*models.SettingsSnapshotContent, error) {
+	content := &models.SettingsSnapshotContent{
+		FormatVersion: models.SettingsSnapshotFormatVersion,
+		TakenAt:       time.Now().UTC(),
+		Settings:      []models.Settings{},
+		Themes:        []models.StorefrontThemeSettings{},
+	}
+	if err := db.Where("tenant_id = ?", tenantID).Order("created_at ASC").Find(&content.Settings).Error; err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	if err := db.Where("tenant_id = ?", tenantID).Order("created_at ASC").Find(&content.Themes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load storefront themes: %w", err)
+	}
+	return content, nil
+}
+
+// Restore replaces a tenant's settings and storefront themes with the snapshot content.
+// Snapshot records are matched to current ones by settings context and storefront ID so the
+// unique indexes hold; matched records keep their ID and get the next version, unmatched
+// current records are soft-deleted. History is written for every record in the same transaction.
+func (r *settingsSnapshotRepository) Restore(snapshot *models.SettingsSnapshot, content *models.SettingsSnapshotContent, userID *uuid.UUID,
+	preRestore func(current *models.SettingsSnapshotContent) (*models.SettingsSnapshot, error)) (*SnapshotRestore, error) {
+	restore := &SnapshotRestore{}
+	tenantID := snapshot.TenantID
+	reason := fmt.Sprintf("Restored from snapshot %q", snapshot.Name)
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the tenant's rows so concurrent writes wait for the restore
+		locked := tx.Clauses(clause.Locking{Strength: "UPDATE"})
+		var currentSettings []models.Settings
+		if err := locked.Where("tenant_id = ?", tenantID).Find(&currentSettings).Error; err != nil {
+			return fmt.Errorf("failed to lock settings: %w", err)
+		}
+		var currentThemes []models.StorefrontThemeSettings
+		if err := locked.Where("tenant_id = ?", tenantID).Find(&currentThemes).Error; err != nil {
+			return fmt.Errorf("failed to lock storefront themes: %w", err)
+		}
+
+		pre, err := preRestore(&models.SettingsSnapshotContent{
+			FormatVersion: models.SettingsSnapshotFormatVersion,
+			TakenAt:       time.Now().UTC(),
+			Settings:      currentSettings,
+			Themes:        currentThemes,
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(pre).Error; err != nil {
+			return fmt.Errorf("failed to save pre-restore snapshot: %w", err)
+		}
+		restore.PreRestore = pre
+
+		if err := restoreSettings(tx, tenantID, currentSettings, content.Settings, userID, reason, restore); err != nil {
+			return err
+		}
+		if err := restoreThemes(tx, tenantID, currentThemes, content.Themes, userID, reason, restore); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.SettingsSnapshot{}).
+			Where("id = ?", snapshot.ID).
+			Updates(map[string]interface{}{
+				"last_restored_at": now,
+				"last_restored_by": userID,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to mark snapshot restored: %w", err)
+		}
+		snapshot.LastRestoredAt = &now
+		snapshot.LastRestoredBy = userID
+
+		return pruneAutomaticSnapshots(tx, tenantID, automaticSnapshotsKept)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restore, nil
+}
+
+// restoreSettings soft-deletes current settings missing from the snapshot, then writes the snapshot's
+// settings; deletes go first because the context unique indexes only cover live rows
+func restoreSettings(tx *gorm.DB, tenantID uuid.UUID, current, snapshot []models.Settings, userID *uuid.UUID, reason string, restore *SnapshotRestore) error {
+	currentByContext := make(map[string]models.Settings, len(current))
+	for _, s := range current {
+		currentByContext[SettingsContextKey(&s)] = s
+	}
+	inSnapshot := make(map[string]bool, len(snapshot))
+	for i := range snapshot {
+		inSnapshot[SettingsContextKey(&snapshot[i])] = true
+	}
+
+	for _, s := range current {
+		if inSnapshot[SettingsContextKey(&s)] {
+			continue
+		}
+		if err := tx.Delete(&models.Settings{}, "id = ?", s.ID).Error; err != nil {
+			return fmt.Errorf("failed to remove settings %s: %w", s.ID, err)
+		}
+		if err := createSettingsHistory(tx, s.ID, "delete", nil, userID, reason); err != nil {
+			return err
+		}
+		restore.RemovedSettings = append(restore.RemovedSettings, s)
+	}
+
+	for _, s := range snapshot {
+		s.TenantID = tenantID
+		s.DeletedAt = gorm.DeletedAt{}
+		if existing, ok := currentByContext[SettingsContextKey(&s)]; ok {
+			s.ID = existing.ID
+			s.CreatedAt = existing.CreatedAt
+			s.Version = existing.Version + 1
+		} else {
+			// Write into the soft-deleted row if the record was deleted since the snapshot
+			var deleted models.Settings
+			if err := tx.Unscoped().Select("id", "version").Where("id = ?", s.ID).Limit(1).Find(&deleted).Error; err != nil {
+				return fmt.Errorf("failed to load settings %s: %w", s.ID, err)
+			}
+			if deleted.ID != uuid.Nil {
+				s.Version = deleted.Version + 1
+			}
+		}
+		if err := tx.Unscoped().Save(&s).Error; err != nil {
+			return fmt.Errorf("failed to restore settings %s: %w", s.ID, err)
+		}
+		if err := createSettingsHistory(tx, s.ID, "restore_snapshot", map[string]interface{}{
+			"version": s.Version,
+		}, userID, reason); err != nil {
+			return err
+		}
+		restore.Settings = append(restore.Settings, s)
+	}
+	return nil
+}
+
+// restoreThemes writes the snapshot's storefront themes and soft-deletes current ones missing from it.
+// Storefront IDs are unique across deleted rows too, so a theme is restored into the row of its storefront.
+func restoreThemes(tx *gorm.DB, tenantID uuid.UUID, current, snapshot []models.StorefrontThemeSettings, userID *uuid.UUID, reason string, restore *SnapshotRestore) error {
+	inSnapshot := make(map[uuid.UUID]bool, len(snapshot))
+	for _, t := range snapshot {
+		inSnapshot[t.StorefrontID] = true
+	}
+	for _, t := range current {
+		if inSnapshot[t.StorefrontID] {
+			continue
+		}
+		if err := tx.Delete(&models.StorefrontThemeSettings{}, "id = ?", t.ID).Error; err != nil {
+			return fmt.Errorf("failed to remove storefront theme %s: %w", t.ID, err)
+		}
+		restore.RemovedThemes = append(restore.RemovedThemes, t)
+	}
+
+	for _, t := range snapshot {
+		var existing models.StorefrontThemeSettings
+		if err := tx.Unscoped().Where("storefront_id = ?", t.StorefrontID).Limit(1).Find(&existing).Error; err != nil {
+			return fmt.Errorf("failed to load storefront theme %s: %w", t.StorefrontID, err)
+		}
+		if existing.ID != uuid.Nil {
+			if existing.TenantID != tenantID {
+				return fmt.Errorf("storefront %s belongs to another tenant", t.StorefrontID)
+			}
+			t.ID = existing.ID
+			t.CreatedAt = existing.CreatedAt
+			t.CreatedBy = existing.CreatedBy
+			t.Version = existing.Version + 1
+		}
+		t.TenantID = tenantID
+		t.UpdatedBy = userID
+		t.DeletedAt = gorm.DeletedAt{}
+		if err := tx.Unscoped().Save(&t).Error; err != nil {
+			return fmt.Errorf("failed to restore storefront theme %s: %w", t.StorefrontID, err)
+		}
+
+		themeSnapshot, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Errorf("failed to create theme history snapshot: %w", err)
+		}
+		summary := reason
+		if err := tx.Create(&models.StorefrontThemeHistory{
+			ID:              uuid.New(),
+			ThemeSettingsID: t.ID,
+			TenantID:        tenantID,
+			Version:         t.Version,
+			Snapshot:        datatypes.JSON(themeSnapshot),
+			ChangeSummary:   &summary,
+			CreatedBy:       userID,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to save theme history: %w", err)
+		}
+		restore.Themes = append(restore.Themes, t)
+	}
+	return nil
+}
+
+func createSettingsHistory(tx *gorm.DB, settingsID uuid.UUID, operation string, changes map[string]interface{}, userID *uuid.UUID, reason string) error {
+	var changesJSON []byte
+	if changes != nil {
+		changesJSON, _ = json.Marshal(changes)
+	}
+	if err := tx.Create(&models.SettingsHistory{
+		ID:         uuid.New(),
+		SettingsID: settingsID,
+		Operation:  operation,
+		Changes:    changesJSON,
+		UserID:     userID,
+		Reason:     &reason,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to save settings history: %w", err)
+	}
+	return nil
+}
+
+// pruneAutomaticSnapshots keeps only the newest keep automatic snapshots of a tenant
+func pruneAutomaticSnapshots(tx *gorm.DB, tenantID uuid.UUID, keep int) error {
+	keepIDs := tx.Model(&models.SettingsSnapshot{}).
+		Select("id").
+		Where("tenant_id = ? AND automatic = ?", tenantID, true).
+		Order("created_at DESC").
+		Limit(keep)
+	return tx.Where("tenant_id = ? AND automatic = ? AND id NOT IN (?)", tenantID, true, keepIDs).
+		Delete(&models.SettingsSnapshot{}).Error
+}
+
+// SettingsContextKey identifies a settings record by its context (tenant, application, user, scope),
+// which is unique among live settings
+func SettingsContextKey(s *models.Settings) string {
+	userID := ""
+	if s.UserID != nil {
+		userID = s.UserID.String()
+	}
+	return s.ApplicationID.String() + "|" + s.Scope + "|" + userID
+}
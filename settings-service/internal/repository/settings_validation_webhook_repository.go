@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"settings-service/internal/models"
+)
+
+// SettingsValidationWebhookRepository defines the interface for validation webhook data access.
+// A nil tenant ID addresses the platform-wide webhooks registered by internal services.
+type SettingsValidationWebhookRepository interface {
+	// ListByTenant retrieves the webhooks registered for a tenant, or the platform-wide ones
+	ListByTenant(tenantID *uuid.UUID) ([]models.SettingsValidationWebhook, error)
+
+	// GetByID retrieves a webhook registered for a tenant, or a platform-wide one
+	GetByID(tenantID *uuid.UUID, id uuid.UUID) (*models.SettingsValidationWebhook, error)
+
+	// FindForContext retrieves the enabled tenant and platform-wide webhooks that apply to a settings context
+	FindForContext(context models.SettingsContext) ([]models.SettingsValidationWebhook, error)
+
+	// Save creates or updates a webhook
+	Save(webhook *models.SettingsValidationWebhook) error
+
+	// Delete removes a webhook registered for a tenant, or a platform-wide one
+	Delete(tenantID *uuid.UUID, id uuid.UUID) error
+
+	// RecordCall stores the outcome of the webhook's latest call
+	RecordCall(id uuid.UUID, status, lastError string, calledAt time.Time) error
+}
+
+type settingsValidationWebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewSettingsValidationWebhookRepository creates a new validation webhook repository
+func NewSettingsValidationWebhookRepository(db *gorm.DB) SettingsValidationWebhookRepository {
+	return &settingsValidationWebhookRepository{db: db}
+}
+
+// ListByTenant retrieves the webhooks registered for a tenant, or the platform-wide ones
+func (r *settingsValidationWebhookRepository) ListByTenant(tenantID *uuid.UUID) ([]models.SettingsValidationWebhook, error) {
+	var webhooks []models.SettingsValidationWebhook
+	err := r.ownedBy(tenantID).
+		Order("created_at ASC").
+		Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetByID retrieves a webhook registered for a tenant, or a platform-wide one
+func (r *settingsValidationWebhookRepository) GetByID(tenantID *uuid.UUID, id uuid.UUID) (*models.SettingsValidationWebhook, error) {
+	var webhook models.SettingsValidationWebhook
+	err := r.ownedBy(tenantID).
+		Where("id = ?", id).
+		First(&webhook).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// FindForContext retrieves the enabled tenant and platform-wide webhooks that apply to a settings context
+func (r *settingsValidationWebhookRepository) FindForContext(context models.SettingsContext) ([]models.SettingsValidationWebhook, error) {
+	var webhooks []models.SettingsValidationWebhook
+	err := r.db.
+		Where("is_enabled = ?", true).
+		Where("tenant_id = ? OR tenant_id IS NULL", context.TenantID).
+		Where("application_id = ? OR application_id IS NULL", context.ApplicationID).
+		Where("scope = ? OR scope = '' OR scope IS NULL", context.Scope).
+		Order("created_at ASC").
+		Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Save creates or updates a webhook
+func (r *settingsValidationWebhookRepository) Save(webhook *models.SettingsValidationWebhook) error {
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+
+	return r.db.Save(webhook).Error
+}
+
+// Delete removes a webhook registered for a tenant, or a platform-wide one
+func (r *settingsValidationWebhookRepository) Delete(tenantID *uuid.UUID, id uuid.UUID) error {
+	result := r.ownedBy(tenantID).
+		Where("id = ?", id).
+		Delete(&models.SettingsValidationWebhook{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RecordCall stores the outcome of the webhook's latest call
+func (r *settingsValidationWebhookRepository) RecordCall(id uuid.UUID, status, lastError string, calledAt time.Time) error {
+	return r.db.Model(&models.SettingsValidationWebhook{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"last_called_at": calledAt,
+			"last_status":    status,
+			"last_error":     lastError,
+		}).Error
+}
+
+// ownedBy scopes a query to a tenant's webhooks, or to the platform-wide ones for a nil tenant
+func (r *settingsValidationWebhookRepository) ownedBy(tenantID *uuid.UUID) *gorm.DB {
+	if tenantID == nil {
+		return r.db.Where("tenant_id IS NULL")
+	}
+	return r.db.Where("tenant_id = ?", *tenantID)
+}
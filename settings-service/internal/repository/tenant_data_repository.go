@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"settings-service/internal/models"
+)
+
+// TenantDataRepository defines the interface for removing everything a tenant owns
+type TenantDataRepository interface {
+	// Purge permanently deletes the tenant's settings (and their history), storefront themes,
+	// payment gateways, notification routes, scheduled changes, snapshots and tenant-scoped
+	// validation webhooks in one transaction. It returns the number of rows removed and the
+	// storefronts whose themes were deleted.
+	Purge(tenantID uuid.UUID) (int64, []uuid.UUID, error)
+}
+
+type tenantDataRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantDataRepository creates a new tenant data repository
+func NewTenantDataRepository(db *gorm.DB) TenantDataRepository {
+	return &tenantDataRepository{db: db}
+}
+
+// Purge permanently deletes the tenant's data, soft-deleted rows included
+func (r *tenantDataRepository) Purge(tenantID uuid.UUID) (int64, []uuid.UUID, error) {
+	var purged int64
+	var storefrontIDs []uuid.UUID
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.StorefrontThemeSettings{}).
+			Where("tenant_id = ?", tenantID).
+			Pluck("storefront_id", &storefrontIDs).Error; err != nil {
+			return err
+		}
+
+		// History rows reference settings by ID, so they go before the settings
+		history := tx.Where("settings_id IN (?)", tx.Unscoped().Model(&models.Settings{}).Select("id").Where("tenant_id = ?", tenantID)).
+			Delete(&models.SettingsHistory{})
+		if history.Error != nil {
+			return history.Error
+		}
+		purged += history.RowsAffected
+
+		tenantScoped := []interface{}{
+			&models.Settings{},
+			&models.StorefrontThemeHistory{},
+			&models.StorefrontThemeSettings{},
+			&models.PaymentGatewayConfig{},
+			&models.NotificationRoute{},
+			&models.ScheduledSettingsChange{},
+			&models.SettingsSnapshot{},
+			&models.SettingsValidationWebhook{}, // Platform-wide webhooks have no tenant and are kept
+		}
+		for _, model := range tenantScoped {
+			result := tx.Unscoped().Where("tenant_id = ?", tenantID).Delete(model)
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to purge tenant data: %w", err)
+	}
+
+	return purged, storefrontIDs, nil
+}
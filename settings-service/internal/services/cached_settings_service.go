@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"settings-service/internal/cache"
+	"settings-service/internal/models"
+)
+
+// cachedSettingsService serves settings-by-context reads from the settings cache.
+// Writes go to Postgres first, then invalidate the tenant's cache version, write the new value
+// through and publish settings.updated so other consumers drop their copies.
+type cachedSettingsService struct {
+	SettingsService
+	cache     *cache.SettingsCache
+	publisher SettingsEventPublisher
+}
+
+// NewCachedSettingsService wraps a settings service with the Redis read cache
+func NewCachedSettingsService(inner SettingsService, settingsCache *cache.SettingsCache, publisher SettingsEventPublisher) SettingsService {
+	return &cachedSettingsService{SettingsService: inner, cache: settingsCache, publisher: publisher}
+}
+
+// GetSettingsByContext returns cached settings, loading them from Postgres on a miss
+func (s *cachedSettingsService) GetSettingsByContext(context models.SettingsContext) (*models.Settings, error) {
+	owner := context.TenantID.String()
+	key := settingsContextKey(context)
+
+	var cached models.Settings
+	version, hit := s.cache.Get(cache.NamespaceSettings, owner, key, &cached)
+	if hit {
+		return &cached, nil
+	}
+
+	settings, err := s.SettingsService.GetSettingsByContext(context)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(cache.NamespaceSettings, owner, version, key, settings)
+	return settings, nil
+}
+
+// CreateSettings creates settings and writes them through to the cache
+func (s *cachedSettingsService) CreateSettings(req *models.CreateSettingsRequest, userID *uuid.UUID) (*models.Settings, error) {
+	settings, err := s.SettingsService.CreateSettings(req, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.changed(settings, userID, true)
+	return settings, nil
+}
+
+// UpdateSettings updates settings and writes them through to the cache
+func (s *cachedSettingsService) UpdateSettings(id uuid.UUID, req *models.UpdateSettingsRequest, userID *uuid.UUID) (*models.Settings, error) {
+	settings, err := s.SettingsService.UpdateSettings(id, req, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.changed(settings, userID, false)
+	return settings, nil
+}
+
+// DeleteSettings deletes settings and invalidates the tenant's cached settings
+func (s *cachedSettingsService) DeleteSettings(id uuid.UUID, userID *uuid.UUID) error {
+	existing, err := s.SettingsService.GetSettings(id)
+	if err != nil {
+		return err
+	}
+	if err := s.SettingsService.DeleteSettings(id, userID); err != nil {
+		return err
+	}
+	s.cache.Invalidate(cache.NamespaceSettings, existing.TenantID.String(), cache.InvalidationSourceWrite)
+	s.publish(existing, userID, false)
+	return nil
+}
+
+// ApplyPreset applies a preset and writes the result through to the cache
+func (s *cachedSettingsService) ApplyPreset(settingsID, presetID uuid.UUID, userID *uuid.UUID) (*models.Settings, error) {
+	settings, err := s.SettingsService.ApplyPreset(settingsID, presetID, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.changed(settings, userID, false)
+	return settings, nil
+}
+
+// MergeSettings merges settings and writes the result through to the cache
+func (s *cachedSettingsService) MergeSettings(baseID, overrideID uuid.UUID, userID *uuid.UUID) (*models.Settings, error) {
+	settings, err := s.SettingsService.MergeSettings(baseID, overrideID, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.changed(settings, userID, false)
+	return settings, nil
+}
+
+// changed invalidates the tenant's cached settings, writes the new value through and publishes the change
+func (s *cachedSettingsService) changed(settings *models.Settings, userID *uuid.UUID, created bool) {
+	owner := settings.TenantID.String()
+	version := s.cache.Invalidate(cache.NamespaceSettings, owner, cache.InvalidationSourceWrite)
+	s.cache.Set(cache.NamespaceSettings, owner, version, settingsContextKey(settingsContextOf(settings)), settings)
+	s.publish(settings, userID, created)
+}
+
+// publish emits a settings event so the caches of other replicas and services are invalidated
+func (s *cachedSettingsService) publish(settings *models.Settings, userID *uuid.UUID, created bool) {
+	if s.publisher == nil {
+		return
+	}
+
+	changedBy := ""
+	if userID != nil {
+		changedBy = userID.String()
+	}
+	settingKey := "settings." + settings.ID.String()
+	value := map[string]interface{}{
+		"applicationId": settings.ApplicationID.String(),
+		"scope":         settings.Scope,
+	}
+
+	var err error
+	if created {
+		err = s.publisher.PublishSettingCreated(context.Background(), settings.TenantID.String(), settingKey, models.SettingsCategory, value, changedBy, "")
+	} else {
+		err = s.publisher.PublishSettingUpdated(context.Background(), settings.TenantID.String(), settingKey, models.SettingsCategory, nil, value, changedBy, "")
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to publish settings event for tenant %s: %v", settings.TenantID, err)
+	}
+}
+
+// themeSettingKeyPrefix prefixes the storefront ID in theme settings event keys
+const themeSettingKeyPrefix = "storefront_theme."
+
+// cachedStorefrontThemeService serves storefront theme reads from the settings cache.
+// Themes are looked up by storefront ID and, for older callers, by tenant ID, so a write
+// invalidates every ID the theme can be read by.
+type cachedStorefrontThemeService struct {
+	StorefrontThemeService
+	cache     *cache.SettingsCache
+	publisher SettingsEventPublisher
+}
+
+// NewCachedStorefrontThemeService wraps a storefront theme service with the Redis read cache
+func NewCachedStorefrontThemeService(inner StorefrontThemeService, settingsCache *cache.SettingsCache, publisher SettingsEventPublisher) StorefrontThemeService {
+	return &cachedStorefrontThemeService{StorefrontThemeService: inner, cache: settingsCache, publisher: publisher}
+}
+
+// GetByStorefrontID returns the cached theme of a storefront
+func (s *cachedStorefrontThemeService) GetByStorefrontID(storefrontID uuid.UUID) (*models.StorefrontThemeSettings, error) {
+	return s.get(storefrontID, "storefront", s.StorefrontThemeService.GetByStorefrontID)
+}
+
+// GetByTenantID returns the cached theme of a tenant
+func (s *cachedStorefrontThemeService) GetByTenantID(tenantID uuid.UUID) (*models.StorefrontThemeSettings, error) {
+	return s.get(tenantID, "tenant", s.StorefrontThemeService.GetByTenantID)
+}
+
+// CreateOrUpdate creates or updates a theme and writes it through to the cache
+func (s *cachedStorefrontThemeService) CreateOrUpdate(tenantID uuid.UUID, req *models.CreateStorefrontThemeRequest, userID *uuid.UUID) (*models.StorefrontThemeSettings, error) {
+	return s.write(tenantID, userID, func() (*models.StorefrontThemeSettings, error) {
+		return s.StorefrontThemeService.CreateOrUpdate(tenantID, req, userID)
+	})
+}
+
+// CreateOrUpdateByStorefrontID creates or updates a theme and writes it through to the cache
+func (s *cachedStorefrontThemeService) CreateOrUpdateByStorefrontID(storefrontID, tenantID uuid.UUID, req *models.CreateStorefrontThemeRequest, userID *uuid.UUID) (*models.StorefrontThemeSettings, error) {
+	return s.write(storefrontID, userID, func() (*models.StorefrontThemeSettings, error) {
+		return s.StorefrontThemeService.CreateOrUpdateByStorefrontID(storefrontID, tenantID, req, userID)
+	})
+}
+
+// Update updates a theme and writes it through to the cache
+func (s *cachedStorefrontThemeService) Update(tenantID uuid.UUID, req *models.UpdateStorefrontThemeRequest, userID *uuid.UUID) (*models.StorefrontThemeSettings, error) {
+	return s.write(tenantID, userID, func() (*models.StorefrontThemeSettings, error) {
+		return s.StorefrontThemeService.Update(tenantID, req, userID)
+	})
+}
+
+// ApplyPreset applies a theme preset and writes the result through to the cache
+func (s *cachedStorefrontThemeService) ApplyPreset(tenantID uuid.UUID, presetID string, userID *uuid.UUID) (*models.StorefrontThemeSettings, error) {
+	return s.write(tenantID, userID, func() (*models.StorefrontThemeSettings, error) {
+		return s.StorefrontThemeService.ApplyPreset(tenantID, presetID, userID)
+	})
+}
+
+// CloneTheme clones a theme and writes the copy through to the cache
+func (s *cachedStorefrontThemeService) CloneTheme(sourceTenantID, targetTenantID uuid.UUID, userID *uuid.UUID) (*models.StorefrontThemeSettings, error) {
+	return s.write(targetTenantID, userID, func() (*models.StorefrontThemeSettings, error) {
+		return s.StorefrontThemeService.CloneTheme(sourceTenantID, targetTenantID, userID)
+	})
+}
+
+// RestoreVersion restores a theme version and writes the result through to the cache
+func (s *cachedStorefrontThemeService) RestoreVersion(tenantID uuid.UUID, version int, userID *uuid.UUID) (*models.StorefrontThemeSettings, error) {
+	return s.write(tenantID, userID, func() (*models.StorefrontThemeSettings, error) {
+		return s.StorefrontThemeService.RestoreVersion(tenantID, version, userID)
+	})
+}
+
+// Delete deletes a theme and invalidates every ID it could be read by
+func (s *cachedStorefrontThemeService) Delete(tenantID uuid.UUID) error {
+	existing, err := s.StorefrontThemeService.GetByTenantID(tenantID)
+	if err != nil {
+		return err
+	}
+	if err := s.StorefrontThemeService.Delete(tenantID); err != nil {
+		return err
+	}
+	s.invalidate(tenantID, existing)
+	s.publish(existing, nil)
+	return nil
+}
+
+// get reads a theme through the cache; defaults for unknown IDs are cached too
+func (s *cachedStorefrontThemeService) get(id uuid.UUID, kind string, load func(uuid.UUID) (*models.StorefrontThemeSettings, error)) (*models.StorefrontThemeSettings, error) {
+	owner := id.String()
+
+	var cached models.StorefrontThemeSettings
+	version, hit := s.cache.Get(cache.NamespaceTheme, owner, kind, &cached)
+	if hit {
+		return &cached, nil
+	}
+
+	settings, err := load(id)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(cache.NamespaceTheme, owner, version, kind, settings)
+	return settings, nil
+}
+
+// write runs a theme write, invalidates the affected IDs and writes the theme through by storefront ID
+func (s *cachedStorefrontThemeService) write(id uuid.UUID, userID *uuid.UUID, apply func() (*models.StorefrontThemeSettings, error)) (*models.StorefrontThemeSettings, error) {
+	settings, err := apply()
+	if err != nil {
+		return nil, err
+	}
+	versions := s.invalidate(id, settings)
+	if version := versions[settings.StorefrontID]; version != 0 {
+		s.cache.Set(cache.NamespaceTheme, settings.StorefrontID.String(), version, "storefront", settings)
+	}
+	s.publish(settings, userID)
+	return settings, nil
+}
+
+// invalidate bumps the cache version of the written ID and of the theme's tenant and storefront
+func (s *cachedStorefrontThemeService) invalidate(id uuid.UUID, settings *models.StorefrontThemeSettings) map[uuid.UUID]int64 {
+	versions := map[uuid.UUID]int64{}
+	for _, owner := range []uuid.UUID{id, settings.TenantID, settings.StorefrontID} {
+		if owner == uuid.Nil {
+			continue
+		}
+		if _, done := versions[owner]; done {
+			continue
+		}
+		versions[owner] = s.cache.Invalidate(cache.NamespaceTheme, owner.String(), cache.InvalidationSourceWrite)
+	}
+	return versions
+}
+
+// publish emits a settings event so the caches of other replicas and services are invalidated
+func (s *cachedStorefrontThemeService) publish(settings *models.StorefrontThemeSettings, userID *uuid.UUID) {
+	if s.publisher == nil || settings.TenantID == uuid.Nil {
+		return
+	}
+
+	changedBy := ""
+	if userID != nil {
+		changedBy = userID.String()
+	}
+	settingKey := ThemeSettingKey(settings.StorefrontID)
+	value := map[string]interface{}{
+		"storefrontId":  settings.StorefrontID.String(),
+		"themeTemplate": settings.ThemeTemplate,
+	}
+	if err := s.publisher.PublishSettingUpdated(context.Background(), settings.TenantID.String(), settingKey, models.StorefrontThemeCategory, nil, value, changedBy, ""); err != nil {
+		log.Printf("WARNING: Failed to publish storefront theme event for tenant %s: %v", settings.TenantID, err)
+	}
+}
+
+// InvalidateFromEvent invalidates the cached settings or theme named by a settings event.
+// Writes made by this service have already invalidated and written through, so the event
+// costs them one extra miss; it keeps writers on other replicas and services consistent.
+func InvalidateFromEvent(settingsCache *cache.SettingsCache, tenantID, category, settingKey string) {
+	switch category {
+	case models.SettingsCategory:
+		settingsCache.Invalidate(cache.NamespaceSettings, tenantID, cache.InvalidationSourceEvent)
+	case models.StorefrontThemeCategory:
+		settingsCache.Invalidate(cache.NamespaceTheme, tenantID, cache.InvalidationSourceEvent)
+		if storefrontID, err := uuid.Parse(strings.TrimPrefix(settingKey, themeSettingKeyPrefix)); err == nil {
+			settingsCache.Invalidate(cache.NamespaceTheme, storefrontID.String(), cache.InvalidationSourceEvent)
+		}
+	}
+}
+
+// ThemeSettingKey is the settings event key of a storefront's theme
+func ThemeSettingKey(storefrontID uuid.UUID) string {
+	return themeSettingKeyPrefix + storefrontID.String()
+}
+
+// settingsContextOf returns the context settings are looked up by
+func settingsContextOf(settings *models.Settings) models.SettingsContext {
+	return models.SettingsContext{
+		TenantID:      settings.TenantID,
+		ApplicationID: settings.ApplicationID,
+		UserID:        settings.UserID,
+		Scope:         settings.Scope,
+	}
+}
+
+// settingsContextKey is the cache key of a settings context within its tenant
+func settingsContextKey(context models.SettingsContext) string {
+	user := "-"
+	if context.UserID != nil {
+		user = context.UserID.String()
+	}
+	return fmt.Sprintf("%s:%s:%s", context.ApplicationID, context.Scope, user)
+}
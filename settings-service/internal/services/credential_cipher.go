@@ -0,0 +1,128 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrCredentialEncryptionDisabled is returned when secrets are written without an encryption key configured
+var ErrCredentialEncryptionDisabled = errors.New("credential encryption key is not configured")
+
+// credentialCipherVersion prefixes ciphertexts so the key/algorithm can be rotated later
+const credentialCipherVersion = "v1:"
+
+// CredentialCipher encrypts secret settings values with AES-256-GCM
+type CredentialCipher struct {
+	aead cipher.AEAD
+}
+
+// NewCredentialCipher creates a cipher from a base64 encoded 32-byte key
+// An empty key returns a disabled cipher that refuses to encrypt or decrypt
+func NewCredentialCipher(encodedKey string) (*CredentialCipher, error) {
+	if encodedKey == "" {
+		return &CredentialCipher{}, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential encryption key encoding: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("credential encryption key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return &CredentialCipher{aead: aead}, nil
+}
+
+// Enabled reports whether an encryption key is configured
+func (c *CredentialCipher) Enabled() bool {
+	return c != nil && c.aead != nil
+}
+
+// EncryptMap encrypts a set of secret values into a single ciphertext
+// additionalData binds the ciphertext to its owner (e.g. tenant and provider)
+func (c *CredentialCipher) EncryptMap(values map[string]string, additionalData string) (string, error) {
+	if !c.Enabled() {
+		return "", ErrCredentialEncryptionDisabled
+	}
+
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, plaintext, []byte(additionalData))
+	return credentialCipherVersion + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptMap decrypts a ciphertext produced by EncryptMap
+func (c *CredentialCipher) DecryptMap(ciphertext, additionalData string) (map[string]string, error) {
+	values := map[string]string{}
+	if ciphertext == "" {
+		return values, nil
+	}
+	if !c.Enabled() {
+		return nil, ErrCredentialEncryptionDisabled
+	}
+
+	if !strings.HasPrefix(ciphertext, credentialCipherVersion) {
+		return nil, errors.New("unsupported credential ciphertext version")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, credentialCipherVersion))
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential ciphertext: %w", err)
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("credential ciphertext too short")
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(additionalData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
+	}
+	return values, nil
+}
+
+// MaskSecret returns a hint that identifies a secret without revealing it
+// Known key prefixes (sk_test_, rzp_live_, ...) are preserved along with the last four characters
+func MaskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) <= 8 {
+		return "****"
+	}
+
+	prefix := ""
+	if idx := strings.LastIndex(secret[:len(secret)-4], "_"); idx > 0 && idx < 12 {
+		prefix = secret[:idx+1]
+	}
+	return prefix + "****" + secret[len(secret)-4:]
+}
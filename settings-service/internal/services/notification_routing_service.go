@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"settings-service/internal/models"
+	"settings-service/internal/repository"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidNotificationEvent is returned for malformed event types
+	ErrInvalidNotificationEvent = errors.New("invalid notification event type")
+	// ErrInvalidNotificationRoute wraps route validation failures
+	ErrInvalidNotificationRoute = errors.New("invalid notification route")
+	// ErrNotificationRouteNotFound is returned when the tenant has no route for the event type
+	ErrNotificationRouteNotFound = errors.New("notification route not found")
+)
+
+// notificationEventPattern accepts dotted event types ("order.created") and prefix wildcards ("order.*")
+var notificationEventPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z0-9_]+)*(\.\*)?$`)
+
+// NotificationRoutingService defines the interface for notification routing settings
+type NotificationRoutingService interface {
+	// GetEventTypes returns the catalog of commonly routed event types and channels
+	GetEventTypes() map[string]interface{}
+
+	// ListRoutes returns all routes for a tenant
+	ListRoutes(tenantID uuid.UUID) ([]models.NotificationRoute, error)
+
+	// GetRoute returns a tenant's route for an event type
+	GetRoute(tenantID uuid.UUID, eventType string) (*models.NotificationRoute, error)
+
+	// UpsertRoute validates and stores a route, then publishes a settings event
+	UpsertRoute(ctx context.Context, tenantID uuid.UUID, eventType string, req *models.UpsertNotificationRouteRequest, userID *uuid.UUID) (*models.NotificationRoute, error)
+
+	// DeleteRoute removes a route and publishes a settings event
+	DeleteRoute(ctx context.Context, tenantID uuid.UUID, eventType string, userID *uuid.UUID) error
+
+	// Resolve returns the most specific route for an event, used by notification services
+	Resolve(tenantID uuid.UUID, eventType string) (*models.ResolvedNotificationRoute, error)
+}
+
+type notificationRoutingService struct {
+	repo      repository.NotificationRoutingRepository
+	publisher SettingsEventPublisher
+}
+
+// NewNotificationRoutingService creates a new notification routing service
+func NewNotificationRoutingService(repo repository.NotificationRoutingRepository, publisher SettingsEventPublisher) NotificationRoutingService {
+	return &notificationRoutingService{
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+// GetEventTypes returns the catalog of commonly routed event types and channels
+func (s *notificationRoutingService) GetEventTypes() map[string]interface{} {
+	return map[string]interface{}{
+		"eventTypes": models.NotificationEventTypes,
+		"channels":   models.NotificationChannels,
+	}
+}
+
+// ListRoutes returns all routes for a tenant
+func (s *notificationRoutingService) ListRoutes(tenantID uuid.UUID) ([]models.NotificationRoute, error) {
+	return s.repo.ListByTenant(tenantID)
+}
+
+// GetRoute returns a tenant's route for an event type
+func (s *notificationRoutingService) GetRoute(tenantID uuid.UUID, eventType string) (*models.NotificationRoute, error) {
+	eventType, err := NormalizeNotificationEvent(eventType)
+	if err != nil {
+		return nil, err
+	}
+	return s.getRoute(tenantID, eventType)
+}
+
+// UpsertRoute validates and stores a route
+func (s *notificationRoutingService) UpsertRoute(ctx context.Context, tenantID uuid.UUID, eventType string, req *models.UpsertNotificationRouteRequest, userID *uuid.UUID) (*models.NotificationRoute, error) {
+	eventType, err := NormalizeNotificationEvent(eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, roles, webhookURL, err := validateNotificationRoute(req)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.getRoute(tenantID, eventType)
+	if err != nil && !errors.Is(err, ErrNotificationRouteNotFound) {
+		return nil, err
+	}
+
+	route := &models.NotificationRoute{
+		TenantID:  tenantID,
+		EventType: eventType,
+		IsEnabled: true,
+		CreatedBy: userID,
+	}
+	var oldValue map[string]interface{}
+	if existing != nil {
+		oldValue = routeSnapshot(existing)
+		route.ID = existing.ID
+		route.IsEnabled = existing.IsEnabled
+		route.CreatedBy = existing.CreatedBy
+		route.CreatedAt = existing.CreatedAt
+	}
+	if req.IsEnabled != nil {
+		route.IsEnabled = *req.IsEnabled
+	}
+
+	userIDs := req.UserIDs
+	if userIDs == nil {
+		userIDs = []uuid.UUID{}
+	}
+	route.Channels = mustJSON(channels)
+	route.Roles = mustJSON(roles)
+	route.UserIDs = mustJSON(userIDs)
+	route.WebhookURL = webhookURL
+	route.UpdatedBy = userID
+	route.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(route); err != nil {
+		return nil, fmt.Errorf("failed to save notification route: %w", err)
+	}
+
+	s.publish(ctx, tenantID, eventType, oldValue, routeSnapshot(route), userID)
+	return route, nil
+}
+
+// DeleteRoute removes a route
+func (s *notificationRoutingService) DeleteRoute(ctx context.Context, tenantID uuid.UUID, eventType string, userID *uuid.UUID) error {
+	eventType, err := NormalizeNotificationEvent(eventType)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.getRoute(tenantID, eventType)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(tenantID, eventType); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotificationRouteNotFound
+		}
+		return fmt.Errorf("failed to delete notification route: %w", err)
+	}
+
+	s.publish(ctx, tenantID, eventType, routeSnapshot(existing), nil, userID)
+	return nil
+}
+
+// Resolve returns the most specific route for an event: an exact match first, then
+// prefix wildcards from the longest ("order.payment.*") to "*". A disabled route still
+// matches, so merchants can mute an event without falling back to a broader route.
+func (s *notificationRoutingService) Resolve(tenantID uuid.UUID, eventType string) (*models.ResolvedNotificationRoute, error) {
+	eventType, err := NormalizeNotificationEvent(eventType)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(eventType, ".*") || eventType == models.NotificationRouteWildcard {
+		return nil, fmt.Errorf("%w: cannot resolve a wildcard", ErrInvalidNotificationEvent)
+	}
+
+	candidates := routeCandidates(eventType)
+	routes, err := s.repo.FindByEventTypes(tenantID, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification routes: %w", err)
+	}
+
+	resolved := &models.ResolvedNotificationRoute{
+		TenantID:  tenantID.String(),
+		EventType: eventType,
+		Channels:  []string{},
+		Roles:     []string{},
+		UserIDs:   []uuid.UUID{},
+	}
+
+	byEvent := make(map[string]*models.NotificationRoute, len(routes))
+	for i := range routes {
+		byEvent[routes[i].EventType] = &routes[i]
+	}
+	for _, candidate := range candidates {
+		route, ok := byEvent[candidate]
+		if !ok {
+			continue
+		}
+		resolved.Matched = true
+		resolved.MatchedRoute = route.EventType
+		resolved.Enabled = route.IsEnabled
+		if route.IsEnabled {
+			decodeJSONInto(route.Channels, &resolved.Channels)
+			decodeJSONInto(route.Roles, &resolved.Roles)
+			decodeJSONInto(route.UserIDs, &resolved.UserIDs)
+			resolved.WebhookURL = route.WebhookURL
+		}
+		break
+	}
+	return resolved, nil
+}
+
+// NormalizeNotificationEvent lower-cases an event type, expands aliases such as
+// "low_stock" and validates its format
+func NormalizeNotificationEvent(eventType string) (string, error) {
+	eventType = strings.ToLower(strings.TrimSpace(eventType))
+	if alias, ok := models.NotificationEventAliases[eventType]; ok {
+		eventType = alias
+	}
+	if eventType == models.NotificationRouteWildcard {
+		return eventType, nil
+	}
+	if len(eventType) > 128 || !notificationEventPattern.MatchString(eventType) {
+		return "", ErrInvalidNotificationEvent
+	}
+	return eventType, nil
+}
+
+// routeCandidates lists the route keys that can match an event, most specific first
+func routeCandidates(eventType string) []string {
+	candidates := []string{eventType}
+	parts := strings.Split(eventType, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		candidates = append(candidates, strings.Join(parts[:i], ".")+".*")
+	}
+	return append(candidates, models.NotificationRouteWildcard)
+}
+
+// validateNotificationRoute checks channels and recipients, returning normalized values
+func validateNotificationRoute(req *models.UpsertNotificationRouteRequest) ([]string, []string, string, error) {
+	supported := make(map[string]bool, len(models.NotificationChannels))
+	for _, channel := range models.NotificationChannels {
+		supported[channel] = true
+	}
+
+	channels := make([]string, 0, len(req.Channels))
+	seen := make(map[string]bool, len(req.Channels))
+	hasWebhook, hasPersonal := false, false
+	for _, channel := range req.Channels {
+		channel = strings.ToLower(strings.TrimSpace(channel))
+		if !supported[channel] {
+			return nil, nil, "", fmt.Errorf("%w: unsupported channel %q", ErrInvalidNotificationRoute, channel)
+		}
+		if seen[channel] {
+			continue
+		}
+		seen[channel] = true
+		channels = append(channels, channel)
+		if channel == models.NotificationChannelWebhook {
+			hasWebhook = true
+		} else {
+			hasPersonal = true
+		}
+	}
+	if len(channels) == 0 {
+		return nil, nil, "", fmt.Errorf("%w: at least one channel is required", ErrInvalidNotificationRoute)
+	}
+
+	roles := make([]string, 0, len(req.Roles))
+	seenRoles := make(map[string]bool, len(req.Roles))
+	for _, role := range req.Roles {
+		role = strings.ToLower(strings.TrimSpace(role))
+		if role == "" || seenRoles[role] {
+			continue
+		}
+		seenRoles[role] = true
+		roles = append(roles, role)
+	}
+	if hasPersonal && len(roles) == 0 && len(req.UserIDs) == 0 {
+		return nil, nil, "", fmt.Errorf("%w: roles or userIds are required for %s", ErrInvalidNotificationRoute, strings.Join(channels, ", "))
+	}
+
+	webhookURL := strings.TrimSpace(req.WebhookURL)
+	switch {
+	case hasWebhook && webhookURL == "":
+		return nil, nil, "", fmt.Errorf("%w: webhookUrl is required for the webhook channel", ErrInvalidNotificationRoute)
+	case !hasWebhook && webhookURL != "":
+		return nil, nil, "", fmt.Errorf("%w: webhookUrl requires the webhook channel", ErrInvalidNotificationRoute)
+	case hasWebhook:
+		parsed, err := url.Parse(webhookURL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return nil, nil, "", fmt.Errorf("%w: webhookUrl must be an absolute https URL", ErrInvalidNotificationRoute)
+		}
+	}
+
+	return channels, roles, webhookURL, nil
+}
+
+func (s *notificationRoutingService) getRoute(tenantID uuid.UUID, eventType string) (*models.NotificationRoute, error) {
+	route, err := s.repo.GetByEventType(tenantID, eventType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotificationRouteNotFound
+		}
+		return nil, fmt.Errorf("failed to load notification route: %w", err)
+	}
+	return route, nil
+}
+
+// publish emits a settings event so notification services can drop cached routes
+func (s *notificationRoutingService) publish(ctx context.Context, tenantID uuid.UUID, eventType string, oldValue, newValue map[string]interface{}, userID *uuid.UUID) {
+	if s.publisher == nil {
+		return
+	}
+
+	changedBy := ""
+	if userID != nil {
+		changedBy = userID.String()
+	}
+	settingKey := "notifications.routing." + eventType
+
+	var err error
+	if oldValue == nil {
+		err = s.publisher.PublishSettingCreated(ctx, tenantID.String(), settingKey, models.NotificationRoutingCategory, newValue, changedBy, "")
+	} else {
+		err = s.publisher.PublishSettingUpdated(ctx, tenantID.String(), settingKey, models.NotificationRoutingCategory, oldValue, newValue, changedBy, "")
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to publish notification routing event for tenant %s: %v", tenantID, err)
+	}
+}
+
+// routeSnapshot returns the view of a route used in settings events
+func routeSnapshot(route *models.NotificationRoute) map[string]interface{} {
+	return map[string]interface{}{
+		"eventType":  route.EventType,
+		"isEnabled":  route.IsEnabled,
+		"channels":   route.Channels,
+		"roles":      route.Roles,
+		"userIds":    route.UserIDs,
+		"webhookUrl": route.WebhookURL,
+	}
+}
+
+func mustJSON(value interface{}) datatypes.JSON {
+	raw, _ := json.Marshal(value)
+	return datatypes.JSON(raw)
+}
+
+func decodeJSONInto(raw datatypes.JSON, target interface{}) {
+	if len(raw) == 0 {
+		return
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		log.Printf("WARNING: Failed to decode notification route field: %v", err)
+	}
+}
@@ -0,0 +1,480 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"settings-service/internal/clients/paymentgateway"
+	"settings-service/internal/models"
+	"settings-service/internal/repository"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnsupportedPaymentProvider is returned for providers without a schema
+	ErrUnsupportedPaymentProvider = errors.New("unsupported payment provider")
+	// ErrPaymentGatewayNotFound is returned when the tenant has not configured the provider
+	ErrPaymentGatewayNotFound = errors.New("payment gateway not configured")
+	// ErrInvalidPaymentConfig wraps schema validation failures
+	ErrInvalidPaymentConfig = errors.New("invalid payment gateway configuration")
+)
+
+// paymentTestTimeout bounds a single connection test against a provider
+const paymentTestTimeout = 15 * time.Second
+
+// SettingsEventPublisher publishes settings change events (satisfied by events.SettingsEvents)
+type SettingsEventPublisher interface {
+	PublishSettingUpdated(ctx context.Context, tenantID, settingKey, category string, oldValue, newValue interface{}, changedBy, changedByName string) error
+	PublishSettingCreated(ctx context.Context, tenantID, settingKey, category string, value interface{}, changedBy, changedByName string) error
+}
+
+// PaymentSettingsService defines the interface for payment gateway settings
+type PaymentSettingsService interface {
+	// GetSchemas returns the field schema of every supported provider
+	GetSchemas() []models.PaymentProviderSchema
+
+	// ListGateways returns all gateway configs for a tenant (secrets masked)
+	ListGateways(tenantID uuid.UUID) ([]models.PaymentGatewayConfig, error)
+
+	// GetGateway returns a tenant's config for a provider (secrets masked)
+	GetGateway(tenantID uuid.UUID, provider string) (*models.PaymentGatewayConfig, error)
+
+	// UpsertGateway validates, encrypts and stores a gateway config, then publishes a settings event
+	UpsertGateway(ctx context.Context, tenantID uuid.UUID, provider string, req *models.UpsertPaymentGatewayRequest, userID *uuid.UUID) (*models.PaymentGatewayConfig, error)
+
+	// DeleteGateway removes a gateway config and publishes a settings event
+	DeleteGateway(ctx context.Context, tenantID uuid.UUID, provider string, userID *uuid.UUID) error
+
+	// TestConnection validates credentials against the provider API
+	TestConnection(ctx context.Context, tenantID uuid.UUID, provider string, req *models.TestPaymentGatewayRequest) (*models.PaymentGatewayTestResult, error)
+
+	// ListCredentials returns decrypted configs of enabled gateways for internal checkout services
+	ListCredentials(tenantID uuid.UUID) ([]models.PaymentGatewayCredentials, error)
+}
+
+type paymentSettingsService struct {
+	repo      repository.PaymentSettingsRepository
+	cipher    *CredentialCipher
+	client    *paymentgateway.Client
+	publisher SettingsEventPublisher
+}
+
+// NewPaymentSettingsService creates a new payment settings service
+func NewPaymentSettingsService(
+	repo repository.PaymentSettingsRepository,
+	cipher *CredentialCipher,
+	client *paymentgateway.Client,
+	publisher SettingsEventPublisher,
+) PaymentSettingsService {
+	return &paymentSettingsService{
+		repo:      repo,
+		cipher:    cipher,
+		client:    client,
+		publisher: publisher,
+	}
+}
+
+// GetSchemas returns the field schema of every supported provider
+func (s *paymentSettingsService) GetSchemas() []models.PaymentProviderSchema {
+	schemas := make([]models.PaymentProviderSchema, 0, len(models.PaymentProviderSchemas))
+	for _, schema := range models.PaymentProviderSchemas {
+		schemas = append(schemas, schema)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Provider < schemas[j].Provider
+	})
+	return schemas
+}
+
+// ListGateways returns all gateway configs for a tenant
+func (s *paymentSettingsService) ListGateways(tenantID uuid.UUID) ([]models.PaymentGatewayConfig, error) {
+	return s.repo.ListByTenant(tenantID)
+}
+
+// GetGateway returns a tenant's config for a provider
+func (s *paymentSettingsService) GetGateway(tenantID uuid.UUID, provider string) (*models.PaymentGatewayConfig, error) {
+	if _, ok := models.PaymentProviderSchemas[provider]; !ok {
+		return nil, ErrUnsupportedPaymentProvider
+	}
+	return s.getGateway(tenantID, provider)
+}
+
+// UpsertGateway validates, encrypts and stores a gateway config
+func (s *paymentSettingsService) UpsertGateway(ctx context.Context, tenantID uuid.UUID, provider string, req *models.UpsertPaymentGatewayRequest, userID *uuid.UUID) (*models.PaymentGatewayConfig, error) {
+	schema, ok := models.PaymentProviderSchemas[provider]
+	if !ok {
+		return nil, ErrUnsupportedPaymentProvider
+	}
+
+	existing, err := s.getGateway(tenantID, provider)
+	if err != nil && !errors.Is(err, ErrPaymentGatewayNotFound) {
+		return nil, err
+	}
+
+	var oldSnapshot map[string]interface{}
+	gateway := &models.PaymentGatewayConfig{
+		TenantID:  tenantID,
+		Provider:  provider,
+		CreatedBy: userID,
+	}
+	if existing != nil {
+		oldSnapshot = gatewaySnapshot(existing)
+		gateway = existing
+	}
+
+	config, secrets, err := s.mergeValues(schema, gateway, req.Config, req.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateGatewayValues(schema, req.Mode, config, secrets); err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.cipher.EncryptMap(secrets, credentialAAD(tenantID, provider))
+	if err != nil {
+		return nil, err
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	hintsJSON, err := json.Marshal(credentialHints(secrets))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credential hints: %w", err)
+	}
+
+	// Stored test results no longer describe the new keys
+	if len(req.Credentials) > 0 || gateway.Mode != req.Mode {
+		gateway.LastTestedAt = nil
+		gateway.LastTestStatus = ""
+		gateway.LastTestMessage = ""
+	}
+
+	gateway.Mode = req.Mode
+	if req.IsEnabled != nil {
+		gateway.IsEnabled = *req.IsEnabled
+	}
+	if req.IsDefault != nil {
+		gateway.IsDefault = *req.IsDefault
+	}
+	gateway.Config = datatypes.JSON(configJSON)
+	gateway.CredentialHints = datatypes.JSON(hintsJSON)
+	gateway.EncryptedCredentials = encrypted
+	gateway.UpdatedBy = userID
+
+	if err := s.repo.Save(gateway); err != nil {
+		return nil, fmt.Errorf("failed to save payment gateway: %w", err)
+	}
+
+	newSnapshot := gatewaySnapshot(gateway)
+	newSnapshot["credentialsUpdated"] = len(req.Credentials) > 0
+	s.publish(ctx, tenantID, provider, oldSnapshot, newSnapshot, userID)
+
+	return gateway, nil
+}
+
+// DeleteGateway removes a gateway config
+func (s *paymentSettingsService) DeleteGateway(ctx context.Context, tenantID uuid.UUID, provider string, userID *uuid.UUID) error {
+	if _, ok := models.PaymentProviderSchemas[provider]; !ok {
+		return ErrUnsupportedPaymentProvider
+	}
+
+	existing, err := s.getGateway(tenantID, provider)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(tenantID, provider); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPaymentGatewayNotFound
+		}
+		return fmt.Errorf("failed to delete payment gateway: %w", err)
+	}
+
+	s.publish(ctx, tenantID, provider, gatewaySnapshot(existing), nil, userID)
+	return nil
+}
+
+// TestConnection validates credentials against the provider API
+// Request values override stored ones; results are recorded only when the stored config is tested unchanged
+func (s *paymentSettingsService) TestConnection(ctx context.Context, tenantID uuid.UUID, provider string, req *models.TestPaymentGatewayRequest) (*models.PaymentGatewayTestResult, error) {
+	schema, ok := models.PaymentProviderSchemas[provider]
+	if !ok {
+		return nil, ErrUnsupportedPaymentProvider
+	}
+
+	existing, err := s.getGateway(tenantID, provider)
+	if err != nil && !errors.Is(err, ErrPaymentGatewayNotFound) {
+		return nil, err
+	}
+
+	base := &models.PaymentGatewayConfig{TenantID: tenantID, Provider: provider}
+	mode := models.PaymentModeTest
+	if existing != nil {
+		base = existing
+		mode = existing.Mode
+	}
+	if req.Mode != "" {
+		mode = req.Mode
+	}
+
+	config, secrets, err := s.mergeValues(schema, base, req.Config, req.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateGatewayValues(schema, mode, config, secrets); err != nil {
+		return nil, err
+	}
+
+	testCtx, cancel := context.WithTimeout(ctx, paymentTestTimeout)
+	defer cancel()
+
+	started := time.Now()
+	verifyErr := s.verify(testCtx, provider, mode, config, secrets)
+
+	result := &models.PaymentGatewayTestResult{
+		Provider:  provider,
+		Mode:      mode,
+		Success:   verifyErr == nil,
+		Message:   "Connection successful",
+		TestedAt:  started.UTC(),
+		LatencyMs: time.Since(started).Milliseconds(),
+	}
+	if verifyErr != nil {
+		result.Message = verifyErr.Error()
+	}
+
+	log.Printf("Payment gateway test for tenant %s provider %s (%s): success=%t latency=%dms",
+		tenantID, provider, mode, result.Success, result.LatencyMs)
+
+	overridden := len(req.Config) > 0 || len(req.Credentials) > 0 || (req.Mode != "" && existing != nil && req.Mode != existing.Mode)
+	if existing != nil && !overridden {
+		existing.LastTestedAt = &result.TestedAt
+		existing.LastTestStatus = models.PaymentTestStatusSuccess
+		if !result.Success {
+			existing.LastTestStatus = models.PaymentTestStatusFailed
+		}
+		existing.LastTestMessage = result.Message
+		if err := s.repo.UpdateTestResult(existing); err != nil {
+			log.Printf("WARNING: Failed to record payment gateway test result: %v", err)
+		}
+	}
+
+	return result, nil
+}
+
+// ListCredentials returns decrypted configs of enabled gateways
+func (s *paymentSettingsService) ListCredentials(tenantID uuid.UUID) ([]models.PaymentGatewayCredentials, error) {
+	gateways, err := s.repo.ListByTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.PaymentGatewayCredentials, 0, len(gateways))
+	for i := range gateways {
+		gateway := &gateways[i]
+		if !gateway.IsEnabled {
+			continue
+		}
+
+		config, err := decodeConfig(gateway.Config)
+		if err != nil {
+			return nil, err
+		}
+		secrets, err := s.cipher.DecryptMap(gateway.EncryptedCredentials, credentialAAD(tenantID, gateway.Provider))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s credentials: %w", gateway.Provider, err)
+		}
+
+		result = append(result, models.PaymentGatewayCredentials{
+			Provider:    gateway.Provider,
+			Mode:        gateway.Mode,
+			IsEnabled:   gateway.IsEnabled,
+			IsDefault:   gateway.IsDefault,
+			Config:      config,
+			Credentials: secrets,
+			UpdatedAt:   gateway.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+// getGateway loads a gateway config, translating not-found errors
+func (s *paymentSettingsService) getGateway(tenantID uuid.UUID, provider string) (*models.PaymentGatewayConfig, error) {
+	gateway, err := s.repo.GetByProvider(tenantID, provider)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentGatewayNotFound
+		}
+		return nil, fmt.Errorf("failed to load payment gateway: %w", err)
+	}
+	return gateway, nil
+}
+
+// mergeValues overlays request values on top of the stored config and decrypted secrets
+// An empty string in the request clears the stored value
+func (s *paymentSettingsService) mergeValues(schema models.PaymentProviderSchema, gateway *models.PaymentGatewayConfig, configUpdates, secretUpdates map[string]string) (map[string]string, map[string]string, error) {
+	fields := make(map[string]models.PaymentSchemaField, len(schema.Fields))
+	for _, f := range schema.Fields {
+		fields[f.Key] = f
+	}
+
+	config, err := decodeConfig(gateway.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets, err := s.cipher.DecryptMap(gateway.EncryptedCredentials, credentialAAD(gateway.TenantID, gateway.Provider))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt stored credentials: %w", err)
+	}
+
+	for key, value := range configUpdates {
+		field, ok := fields[key]
+		if !ok || field.Secret {
+			return nil, nil, fmt.Errorf("%w: unknown config field %q for %s", ErrInvalidPaymentConfig, key, schema.DisplayName)
+		}
+		setOrClear(config, key, value)
+	}
+	for key, value := range secretUpdates {
+		field, ok := fields[key]
+		if !ok || !field.Secret {
+			return nil, nil, fmt.Errorf("%w: unknown credential %q for %s", ErrInvalidPaymentConfig, key, schema.DisplayName)
+		}
+		setOrClear(secrets, key, value)
+	}
+
+	return config, secrets, nil
+}
+
+// validateGatewayValues enforces required fields, key prefixes and test/live key consistency
+func validateGatewayValues(schema models.PaymentProviderSchema, mode string, config, secrets map[string]string) error {
+	for _, field := range schema.Fields {
+		value := config[field.Key]
+		if field.Secret {
+			value = secrets[field.Key]
+		}
+
+		if value == "" {
+			if field.Required {
+				return fmt.Errorf("%w: %s is required", ErrInvalidPaymentConfig, field.Label)
+			}
+			continue
+		}
+		if field.Prefix != "" && !strings.HasPrefix(value, field.Prefix) {
+			return fmt.Errorf("%w: %s must start with %q", ErrInvalidPaymentConfig, field.Label, field.Prefix)
+		}
+		if modePrefix := modeKeyPrefix(schema.Provider, field.Key, mode); modePrefix != "" && !strings.HasPrefix(value, modePrefix) {
+			return fmt.Errorf("%w: %s is not a %s mode key", ErrInvalidPaymentConfig, field.Label, mode)
+		}
+	}
+	return nil
+}
+
+// modeKeyPrefix returns the prefix a key must carry for the given mode, if the provider encodes it
+func modeKeyPrefix(provider, key, mode string) string {
+	switch provider {
+	case models.PaymentProviderStripe:
+		switch key {
+		case "publishableKey":
+			return "pk_" + mode + "_"
+		case "secretKey":
+			return "sk_" + mode + "_"
+		}
+	case models.PaymentProviderRazorpay:
+		if key == "keyId" {
+			return "rzp_" + mode + "_"
+		}
+	}
+	return ""
+}
+
+// verify performs the provider-specific credential check
+func (s *paymentSettingsService) verify(ctx context.Context, provider, mode string, config, secrets map[string]string) error {
+	switch provider {
+	case models.PaymentProviderStripe:
+		return s.client.VerifyStripe(ctx, secrets["secretKey"])
+	case models.PaymentProviderRazorpay:
+		return s.client.VerifyRazorpay(ctx, config["keyId"], secrets["keySecret"])
+	case models.PaymentProviderPayPal:
+		return s.client.VerifyPayPal(ctx, config["clientId"], secrets["clientSecret"], mode == models.PaymentModeLive)
+	default:
+		return ErrUnsupportedPaymentProvider
+	}
+}
+
+// publish emits a settings event so checkout services can reload gateway config
+// Snapshots never contain secrets
+func (s *paymentSettingsService) publish(ctx context.Context, tenantID uuid.UUID, provider string, oldValue, newValue map[string]interface{}, userID *uuid.UUID) {
+	if s.publisher == nil {
+		return
+	}
+
+	changedBy := ""
+	if userID != nil {
+		changedBy = userID.String()
+	}
+	settingKey := "payment.gateways." + provider
+
+	var err error
+	if oldValue == nil {
+		err = s.publisher.PublishSettingCreated(ctx, tenantID.String(), settingKey, models.PaymentSettingsCategory, newValue, changedBy, "")
+	} else {
+		err = s.publisher.PublishSettingUpdated(ctx, tenantID.String(), settingKey, models.PaymentSettingsCategory, oldValue, newValue, changedBy, "")
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to publish payment settings event for tenant %s: %v", tenantID, err)
+	}
+}
+
+// gatewaySnapshot returns the non-secret view of a gateway used in settings events
+func gatewaySnapshot(gateway *models.PaymentGatewayConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"provider":  gateway.Provider,
+		"mode":      gateway.Mode,
+		"isEnabled": gateway.IsEnabled,
+		"isDefault": gateway.IsDefault,
+		"config":    gateway.Config,
+	}
+}
+
+// credentialHints masks every stored secret for display
+func credentialHints(secrets map[string]string) map[string]string {
+	hints := make(map[string]string, len(secrets))
+	for key, value := range secrets {
+		hints[key] = MaskSecret(value)
+	}
+	return hints
+}
+
+// credentialAAD binds encrypted credentials to their tenant and provider
+func credentialAAD(tenantID uuid.UUID, provider string) string {
+	return tenantID.String() + ":" + provider
+}
+
+func decodeConfig(raw datatypes.JSON) (map[string]string, error) {
+	config := map[string]string{}
+	if len(raw) == 0 {
+		return config, nil
+	}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, fmt.Errorf("failed to decode gateway config: %w", err)
+	}
+	return config, nil
+}
+
+func setOrClear(values map[string]string, key, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		delete(values, key)
+		return
+	}
+	values[key] = value
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"settings-service/internal/models"
+	"settings-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidScheduledChange wraps scheduled change validation failures
+	ErrInvalidScheduledChange = errors.New("invalid scheduled change")
+	// ErrScheduledChangeNotFound is returned when the change does not exist for the tenant
+	ErrScheduledChangeNotFound = errors.New("scheduled change not found")
+	// ErrScheduledChangeNotPending is returned when cancelling a change that already ran or was cancelled
+	ErrScheduledChangeNotPending = errors.New("scheduled change is no longer pending")
+)
+
+const (
+	// scheduledChangeBatchSize bounds how many due changes one runner tick applies
+	scheduledChangeBatchSize = 50
+	// scheduledChangeStaleAfter reclaims changes left in applying by a crashed replica
+	scheduledChangeStaleAfter = 10 * time.Minute
+	// scheduledChangeMaxAttempts is how often a failing change is retried before it is marked failed
+	scheduledChangeMaxAttempts = 3
+)
+
+// scheduledLocalLayouts are the accepted wall-clock formats for ScheduledFor
+var scheduledLocalLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+
+// ScheduledChangeService defines the interface for scheduled settings and theme changes
+type ScheduledChangeService interface {
+	// Schedule validates and stores a change to apply at a future time
+	Schedule(tenantID uuid.UUID, req *models.CreateScheduledChangeRequest, userID *uuid.UUID) (*models.ScheduledSettingsChange, error)
+
+	// List returns a tenant's scheduled changes, optionally filtered by status
+	List(tenantID uuid.UUID, status string) ([]models.ScheduledSettingsChange, error)
+
+	// Get returns a tenant's scheduled change
+	Get(tenantID, id uuid.UUID) (*models.ScheduledSettingsChange, error)
+
+	// Cancel cancels a pending change
+	Cancel(tenantID, id uuid.UUID, userID *uuid.UUID) (*models.ScheduledSettingsChange, error)
+
+	// ApplyDue applies every change whose time has come and returns how many were applied
+	ApplyDue(ctx context.Context) (int, error)
+}
+
+type scheduledChangeService struct {
+	repo            repository.ScheduledChangeRepository
+	settingsService SettingsService
+	themeService    StorefrontThemeService
+	now             func() time.Time
+}
+
+// NewScheduledChangeService creates a new scheduled change service
+func NewScheduledChangeService(repo repository.ScheduledChangeRepository, settingsService SettingsService, themeService StorefrontThemeService) ScheduledChangeService {
+	return &scheduledChangeService{
+		repo:            repo,
+		settingsService: settingsService,
+		themeService:    themeService,
+		now:             time.Now,
+	}
+}
+
+// Schedule validates and stores a change to apply at a future time
+func (s *scheduledChangeService) Schedule(tenantID uuid.UUID, req *models.CreateScheduledChangeRequest, userID *uuid.UUID) (*models.ScheduledSettingsChange, error) {
+	action := req.Action
+	if action == "" {
+		action = models.ScheduledActionUpdate
+	}
+
+	change := &models.ScheduledSettingsChange{
+		TenantID:    tenantID,
+		TargetType:  req.TargetType,
+		TargetID:    req.TargetID,
+		Action:      action,
+		PresetID:    strings.TrimSpace(req.PresetID),
+		Description: strings.TrimSpace(req.Description),
+		Status:      models.ScheduledStatusPending,
+		CreatedBy:   userID,
+	}
+
+	var payload interface{}
+	switch {
+	case action == models.ScheduledActionApplyPreset:
+		if change.PresetID == "" {
+			return nil, fmt.Errorf("%w: presetId is required for apply_preset", ErrInvalidScheduledChange)
+		}
+		if req.TargetType == models.ScheduledTargetSettings {
+			if _, err := uuid.Parse(change.PresetID); err != nil {
+				return nil, fmt.Errorf("%w: settings presetId must be a UUID", ErrInvalidScheduledChange)
+			}
+		} else if !s.hasThemePreset(change.PresetID) {
+			return nil, fmt.Errorf("%w: unknown theme preset %q", ErrInvalidScheduledChange, change.PresetID)
+		}
+	case req.TargetType == models.ScheduledTargetSettings:
+		if req.Settings == nil {
+			return nil, fmt.Errorf("%w: settings is required to update settings", ErrInvalidScheduledChange)
+		}
+		payload = req.Settings
+	default:
+		if req.Theme == nil {
+			return nil, fmt.Errorf("%w: theme is required to update a storefront theme", ErrInvalidScheduledChange)
+		}
+		payload = req.Theme
+	}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode scheduled change: %w", err)
+		}
+		change.Payload = raw
+	}
+
+	// The target must exist now; it is loaded again when the change is applied
+	var targetSettings *models.Settings
+	switch req.TargetType {
+	case models.ScheduledTargetSettings:
+		settings, err := s.settingsService.GetSettings(req.TargetID)
+		if err != nil || settings.TenantID != tenantID {
+			return nil, fmt.Errorf("%w: settings %s not found", ErrInvalidScheduledChange, req.TargetID)
+		}
+		targetSettings = settings
+	case models.ScheduledTargetStorefrontTheme:
+		if _, err := s.themeService.GetByTenantID(req.TargetID); err != nil {
+			return nil, fmt.Errorf("%w: storefront theme %s not found", ErrInvalidScheduledChange, req.TargetID)
+		}
+	}
+
+	timezone := strings.TrimSpace(req.Timezone)
+	if timezone == "" {
+		timezone = s.tenantTimezone(tenantID, targetSettings)
+	}
+	applyAt, scheduledFor, err := resolveScheduledTime(req.ScheduledFor, timezone)
+	if err != nil {
+		return nil, err
+	}
+	if !applyAt.After(s.now()) {
+		return nil, fmt.Errorf("%w: scheduledFor must be in the future", ErrInvalidScheduledChange)
+	}
+	change.ApplyAt = applyAt
+	change.ScheduledFor = scheduledFor
+	change.Timezone = timezone
+
+	if err := s.repo.Create(change); err != nil {
+		return nil, fmt.Errorf("failed to save scheduled change: %w", err)
+	}
+	return change, nil
+}
+
+// List returns a tenant's scheduled changes
+func (s *scheduledChangeService) List(tenantID uuid.UUID, status string) ([]models.ScheduledSettingsChange, error) {
+	return s.repo.ListByTenant(tenantID, status)
+}
+
+// Get returns a tenant's scheduled change
+func (s *scheduledChangeService) Get(tenantID, id uuid.UUID) (*models.ScheduledSettingsChange, error) {
+	change, err := s.repo.GetByID(tenantID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduledChangeNotFound
+		}
+		return nil, err
+	}
+	return change, nil
+}
+
+// Cancel cancels a pending change
+func (s *scheduledChangeService) Cancel(tenantID, id uuid.UUID, userID *uuid.UUID) (*models.ScheduledSettingsChange, error) {
+	if err := s.repo.Cancel(tenantID, id, userID); err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		// Distinguish a missing change from one that already ran
+		if _, getErr := s.Get(tenantID, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, ErrScheduledChangeNotPending
+	}
+	return s.Get(tenantID, id)
+}
+
+// ApplyDue applies every change whose time has come
+// A failing change is retried on later ticks until scheduledChangeMaxAttempts, then marked failed
+func (s *scheduledChangeService) ApplyDue(ctx context.Context) (int, error) {
+	changes, err := s.repo.ClaimDue(s.now(), scheduledChangeStaleAfter, scheduledChangeBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim scheduled changes: %w", err)
+	}
+
+	applied := 0
+	for i := range changes {
+		if ctx.Err() != nil {
+			break
+		}
+		change := &changes[i]
+		if err := s.apply(change); err != nil {
+			change.LastError = err.Error()
+			change.Status = models.ScheduledStatusPending
+			if change.Attempts >= scheduledChangeMaxAttempts {
+				change.Status = models.ScheduledStatusFailed
+			}
+			log.Printf("WARNING: Scheduled change %s for tenant %s failed (attempt %d): %v", change.ID, change.TenantID, change.Attempts, err)
+		} else {
+			now := s.now()
+			change.Status = models.ScheduledStatusApplied
+			change.AppliedAt = &now
+			change.LastError = ""
+			applied++
+			log.Printf("Applied scheduled %s change %s for tenant %s", change.TargetType, change.ID, change.TenantID)
+		}
+		if err := s.repo.SaveResult(change); err != nil {
+			log.Printf("WARNING: Failed to record result of scheduled change %s: %v", change.ID, err)
+		}
+	}
+	return applied, nil
+}
+
+// apply runs a single change through the regular settings or theme update path,
+// so history records and validation are the same as for an immediate change
+func (s *scheduledChangeService) apply(change *models.ScheduledSettingsChange) error {
+	switch change.TargetType {
+	case models.ScheduledTargetSettings:
+		if change.Action == models.ScheduledActionApplyPreset {
+			presetID, err := uuid.Parse(change.PresetID)
+			if err != nil {
+				return fmt.Errorf("invalid preset id: %w", err)
+			}
+			_, err = s.settingsService.ApplyPreset(change.TargetID, presetID, change.CreatedBy)
+			return err
+		}
+		var req models.UpdateSettingsRequest
+		if err := json.Unmarshal(change.Payload, &req); err != nil {
+			return fmt.Errorf("failed to decode settings payload: %w", err)
+		}
+		_, err := s.settingsService.UpdateSettings(change.TargetID, &req, change.CreatedBy)
+		return err
+
+	case models.ScheduledTargetStorefrontTheme:
+		if change.Action == models.ScheduledActionApplyPreset {
+			_, err := s.themeService.ApplyPreset(change.TargetID, change.PresetID, change.CreatedBy)
+			return err
+		}
+		var req models.UpdateStorefrontThemeRequest
+		if err := json.Unmarshal(change.Payload, &req); err != nil {
+			return fmt.Errorf("failed to decode theme payload: %w", err)
+		}
+		_, err := s.themeService.Update(change.TargetID, &req, change.CreatedBy)
+		return err
+	}
+	return fmt.Errorf("unsupported target type %q", change.TargetType)
+}
+
+func (s *scheduledChangeService) hasThemePreset(presetID string) bool {
+	for _, preset := range s.themeService.GetPresets() {
+		if preset.ID == presetID {
+			return true
+		}
+	}
+	return false
+}
+
+// tenantTimezone returns the timezone from the target settings or the tenant's settings, defaulting to UTC
+func (s *scheduledChangeService) tenantTimezone(tenantID uuid.UUID, target *models.Settings) string {
+	if tz := settingsTimezone(target); tz != "" {
+		return tz
+	}
+	tenantSettings, err := s.settingsService.GetInheritedSettings(models.SettingsContext{TenantID: tenantID, Scope: "tenant"})
+	if err == nil {
+		if tz := settingsTimezone(tenantSettings); tz != "" {
+			return tz
+		}
+	}
+	return "UTC"
+}
+
+func settingsTimezone(settings *models.Settings) string {
+	if settings == nil || len(settings.Localization) == 0 {
+		return ""
+	}
+	var localization models.LocalizationSettings
+	if err := json.Unmarshal(settings.Localization, &localization); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(localization.Timezone)
+}
+
+// resolveScheduledTime converts the requested time to a UTC instant. Wall-clock times are
+// interpreted in timezone; times with an explicit offset are used as-is. It returns the
+// instant and the wall-clock time in timezone for display.
+func resolveScheduledTime(value, timezone string) (time.Time, string, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("%w: unknown timezone %q", ErrInvalidScheduledChange, timezone)
+	}
+
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), t.In(loc).Format(scheduledLocalLayouts[0]), nil
+	}
+	for _, layout := range scheduledLocalLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), t.Format(scheduledLocalLayouts[0]), nil
+		}
+	}
+	return time.Time{}, "", fmt.Errorf("%w: scheduledFor must be a local date-time like 2026-12-01T00:00 or an RFC3339 timestamp", ErrInvalidScheduledChange)
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"settings-service/internal/models"
+)
+
+// historyOperationApplyPreset marks history entries written when a preset is applied
+const historyOperationApplyPreset = "apply_preset"
+
+// ErrInvalidSettingsKey is returned when a key does not name a settings section
+var ErrInvalidSettingsKey = errors.New("invalid settings key")
+
+// settingsSections maps the first segment of a key to the section it reads
+var settingsSections = map[string]func(*models.Settings) datatypes.JSON{
+	"branding":        func(s *models.Settings) datatypes.JSON { return s.Branding },
+	"theme":           func(s *models.Settings) datatypes.JSON { return s.Theme },
+	"layout":          func(s *models.Settings) datatypes.JSON { return s.Layout },
+	"animations":      func(s *models.Settings) datatypes.JSON { return s.Animations },
+	"localization":    func(s *models.Settings) datatypes.JSON { return s.Localization },
+	"ecommerce":       func(s *models.Settings) datatypes.JSON { return s.Ecommerce },
+	"security":        func(s *models.Settings) datatypes.JSON { return s.Security },
+	"notifications":   func(s *models.Settings) datatypes.JSON { return s.Notifications },
+	"marketing":       func(s *models.Settings) datatypes.JSON { return s.Marketing },
+	"integrations":    func(s *models.Settings) datatypes.JSON { return s.Integrations },
+	"performance":     func(s *models.Settings) datatypes.JSON { return s.Performance },
+	"compliance":      func(s *models.Settings) datatypes.JSON { return s.Compliance },
+	"features":        func(s *models.Settings) datatypes.JSON { return s.Features },
+	"userPreferences": func(s *models.Settings) datatypes.JSON { return s.UserPreferences },
+	"application":     func(s *models.Settings) datatypes.JSON { return s.Application },
+}
+
+// resolutionLevel is one level of the inheritance chain and the context it is stored under
+type resolutionLevel struct {
+	level   string
+	context models.SettingsContext
+	skipped string
+}
+
+// ExplainSetting resolves a key (e.g. "theme.colorMode") through platform, tenant, storefront
+// and user settings, reporting what each level holds and which one supplies the effective value
+func (s *settingsService) ExplainSetting(context models.SettingsContext, key string) (*models.SettingsExplanation, error) {
+	path := strings.Split(key, ".")
+	section, ok := settingsSections[path[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q does not start with a settings section (e.g. theme.colorMode)", ErrInvalidSettingsKey, key)
+	}
+	for _, segment := range path {
+		if segment == "" {
+			return nil, fmt.Errorf("%w: %q has an empty segment", ErrInvalidSettingsKey, key)
+		}
+	}
+
+	explanation := &models.SettingsExplanation{Key: key}
+	for _, level := range s.resolutionLevels(context) {
+		step := models.SettingsResolutionStep{
+			Level:  level.level,
+			Scope:  level.context.Scope,
+			Source: models.SettingsSourceNone,
+		}
+		if level.skipped != "" {
+			step.Skipped = level.skipped
+			explanation.Chain = append(explanation.Chain, step)
+			continue
+		}
+
+		settings, err := s.settingsRepo.GetByContext(level.context)
+		switch {
+		case err == nil:
+			step.Source = models.SettingsSourceRecord
+			step.SettingsID = &settings.ID
+			step.UpdatedAt = &settings.UpdatedAt
+			step.Value, step.HasValue = lookupSettingsPath(section(settings), path[1:])
+			if step.HasValue {
+				step.Preset = s.presetForValue(settings.ID, path, step.Value)
+			}
+			explanation.ServedLevel = level.level
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if level.level == models.SettingsLevelPlatform {
+				if defaults, ok := s.builtInDefault(path[0]); ok {
+					step.Source = models.SettingsSourceBuiltInDefault
+					step.Value, step.HasValue = lookupSettingsPath(defaults, path[1:])
+				}
+			}
+		default:
+			return nil, err
+		}
+
+		if step.HasValue {
+			explanation.EffectiveValue = step.Value
+			explanation.EffectiveLevel = step.Level
+			explanation.Preset = step.Preset
+		}
+		explanation.Chain = append(explanation.Chain, step)
+	}
+
+	return explanation, nil
+}
+
+// resolutionLevels lists the inheritance chain from least to most specific,
+// using the same contexts as GetInheritedSettings
+func (s *settingsService) resolutionLevels(context models.SettingsContext) []resolutionLevel {
+	levels := []resolutionLevel{
+		{level: models.SettingsLevelPlatform, context: models.SettingsContext{Scope: "global"}},
+		{level: models.SettingsLevelTenant, context: models.SettingsContext{TenantID: context.TenantID, Scope: "tenant"}},
+		{level: models.SettingsLevelStorefront, context: models.SettingsContext{TenantID: context.TenantID, ApplicationID: context.ApplicationID, Scope: "application"}},
+		{level: models.SettingsLevelUser, context: models.SettingsContext{TenantID: context.TenantID, ApplicationID: context.ApplicationID, UserID: context.UserID, Scope: "user"}},
+	}
+	if context.ApplicationID == uuid.Nil {
+		levels[2].skipped = "no applicationId given"
+		levels[3].skipped = "no applicationId given"
+	} else if context.UserID == nil {
+		levels[3].skipped = "no userId given"
+	}
+	return levels
+}
+
+// presetForValue reports the last preset applied to a settings record if it set this value
+func (s *settingsService) presetForValue(settingsID uuid.UUID, path []string, value interface{}) *models.SettingsPresetSource {
+	history, err := s.settingsRepo.GetLatestHistoryByOperation(settingsID, historyOperationApplyPreset)
+	if err != nil || history == nil {
+		return nil
+	}
+
+	var changes struct {
+		PresetID uuid.UUID `json:"presetId"`
+	}
+	if err := json.Unmarshal(history.Changes, &changes); err != nil || changes.PresetID == uuid.Nil {
+		return nil
+	}
+	preset, err := s.settingsRepo.GetPresetByID(changes.PresetID)
+	if err != nil {
+		return nil
+	}
+
+	// The value is attributed to the preset only while it still holds the preset's value
+	presetValue, ok := lookupSettingsPath(preset.Settings, path)
+	if !ok || !reflect.DeepEqual(presetValue, value) {
+		return nil
+	}
+	return &models.SettingsPresetSource{
+		ID:        preset.ID,
+		Name:      preset.Name,
+		AppliedAt: history.CreatedAt,
+	}
+}
+
+// builtInDefault returns the hard-coded default of a section, if the service has one
+func (s *settingsService) builtInDefault(section string) (datatypes.JSON, bool) {
+	var defaults interface{}
+	switch section {
+	case "branding":
+		defaults = s.getDefaultBranding()
+	case "theme":
+		defaults = s.getDefaultTheme()
+	case "layout":
+		defaults = s.getDefaultLayout()
+	case "animations":
+		defaults = s.getDefaultAnimations()
+	case "localization":
+		defaults = s.getDefaultLocalization()
+	case "features":
+		defaults = s.getDefaultFeatures()
+	case "userPreferences":
+		defaults = s.getDefaultUserPreferences()
+	case "application":
+		defaults = s.getDefaultApplication()
+	default:
+		return nil, false
+	}
+	data, err := structToJSON(defaults)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// lookupSettingsPath walks a dotted path through a JSON document
+func lookupSettingsPath(data []byte, path []string) (interface{}, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+	var current interface{}
+	if err := json.Unmarshal(data, &current); err != nil || current == nil {
+		return nil, false
+	}
+	for _, segment := range path {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[segment]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -34,16 +35,20 @@ type SettingsService interface {
 	GetInheritedSettings(context models.SettingsContext) (*models.Settings, error)
 	ValidateSettings(settings *models.Settings) ([]models.SettingsValidation, error)
 	GetSettingsHistory(settingsID uuid.UUID, limit int) ([]models.SettingsHistory, error)
+	ExplainSetting(context models.SettingsContext, key string) (*models.SettingsExplanation, error)
 }
 
 type settingsService struct {
 	settingsRepo repository.SettingsRepository
+	validator    SettingsChangeValidator
 }
 
 // NewSettingsService creates a new settings service
-func NewSettingsService(settingsRepo repository.SettingsRepository) SettingsService {
+// validator, if not nil, is consulted before settings are created or changed
+func NewSettingsService(settingsRepo repository.SettingsRepository, validator SettingsChangeValidator) SettingsService {
 	return &settingsService{
 		settingsRepo: settingsRepo,
+		validator:    validator,
 	}
 }
 
@@ -282,6 +287,9 @@ func (s *settingsService) CreateSettings(req *models.CreateSettingsRequest, user
 	} else if len(validationErrors) > 0 {
 		return nil, fmt.Errorf("validation failed: %v", validationErrors)
 	}
+	if err := s.validateChange("create", settings, nil); err != nil {
+		return nil, err
+	}
 	
 	// Save settings
 	if err := s.settingsRepo.Create(settings); err != nil {
@@ -303,6 +311,11 @@ func (s *settingsService) GetSettingsByContext(context models.SettingsContext) (
 }
 
 func (s *settingsService) UpdateSettings(id uuid.UUID, req *models.UpdateSettingsRequest, userID *uuid.UUID) (*models.Settings, error) {
+	return s.updateSettings(id, req, userID, "update", "Settings updated", nil)
+}
+
+// updateSettings applies an update and records it in the history under the given operation
+func (s *settingsService) updateSettings(id uuid.UUID, req *models.UpdateSettingsRequest, userID *uuid.UUID, operation, reason string, details map[string]interface{}) (*models.Settings, error) {
 	// Get existing settings
 	settings, err := s.settingsRepo.GetByID(id)
 	if err != nil {
@@ -427,6 +440,9 @@ func (s *settingsService) UpdateSettings(id uuid.UUID, req *models.UpdateSetting
 	} else if len(validationErrors) > 0 {
 		return nil, fmt.Errorf("validation failed: %v", validationErrors)
 	}
+	if err := s.validateChange(operation, settings, &originalSettings); err != nil {
+		return nil, err
+	}
 	
 	// Update settings
 	if err := s.settingsRepo.Update(settings); err != nil {
@@ -435,7 +451,10 @@ func (s *settingsService) UpdateSettings(id uuid.UUID, req *models.UpdateSetting
 	
 	// Create history record with changes
 	changes := s.calculateChanges(&originalSettings, settings)
-	s.createHistoryRecord(settings.ID, "update", changes, userID, "Settings updated")
+	for k, v := range details {
+		changes[k] = v
+	}
+	s.createHistoryRecord(settings.ID, operation, changes, userID, reason)
 	
 	return settings, nil
 }
@@ -508,8 +527,12 @@ func (s *settingsService) ApplyPreset(settingsID, presetID uuid.UUID, userID *uu
 		return nil, err
 	}
 	
-	// Apply preset to current settings
-	return s.UpdateSettings(settingsID, &presetSettings, userID)
+	// Apply preset to current settings, recording which preset was used
+	return s.updateSettings(settingsID, &presetSettings, userID, historyOperationApplyPreset,
+		fmt.Sprintf("Applied preset %s", preset.Name), map[string]interface{}{
+			"presetId":   preset.ID,
+			"presetName": preset.Name,
+		})
 }
 
 // ==========================================
@@ -549,6 +572,10 @@ func (s *settingsService) MergeSettings(baseID, overrideID uuid.UUID, userID *uu
 	merged.UserID = base.UserID
 	merged.Scope = base.Scope
 	
+	if err := s.validateChange("merge", &merged, nil); err != nil {
+		return nil, err
+	}
+	
 	// Save merged settings
 	if err := s.settingsRepo.Create(&merged); err != nil {
 		return nil, err
@@ -655,6 +682,14 @@ func (s *settingsService) createHistoryRecord(settingsID uuid.UUID, operation st
 	s.settingsRepo.CreateHistory(history)
 }
 
+// validateChange runs the registered validation webhooks against a proposed write
+func (s *settingsService) validateChange(operation string, proposed, current *models.Settings) error {
+	if s.validator == nil {
+		return nil
+	}
+	return s.validator.ValidateChange(context.Background(), operation, proposed, current)
+}
+
 func (s *settingsService) calculateChanges(original, updated *models.Settings) map[string]interface{} {
 	changes := make(map[string]interface{})
 	
@@ -0,0 +1,450 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"settings-service/internal/cache"
+	"settings-service/internal/models"
+	"settings-service/internal/repository"
+)
+
+var (
+	// ErrInvalidSettingsSnapshot wraps settings snapshot validation failures
+	ErrInvalidSettingsSnapshot = errors.New("invalid settings snapshot")
+	// ErrSettingsSnapshotNotFound is returned when the snapshot does not exist for the tenant
+	ErrSettingsSnapshotNotFound = errors.New("settings snapshot not found")
+	// ErrSettingsSnapshotExists is returned when the tenant already has a snapshot with the name
+	ErrSettingsSnapshotExists = errors.New("a settings snapshot with this name already exists")
+	// ErrSettingsSnapshotCorrupt is returned when stored snapshot data fails its checksum
+	ErrSettingsSnapshotCorrupt = errors.New("settings snapshot data is corrupt")
+)
+
+// SnapshotCurrent names the tenant's live settings in a snapshot diff
+const SnapshotCurrent = "current"
+
+// snapshotIgnoredFields are record fields that change on every write and are left out of diffs
+var snapshotIgnoredFields = []string{"id", "tenantId", "version", "createdAt", "updatedAt", "deletedAt", "createdBy", "updatedBy"}
+
+// SettingsSnapshotService defines the interface for named snapshots of a tenant's settings and themes
+type SettingsSnapshotService interface {
+	// Create snapshots all of the tenant's settings and storefront themes
+	Create(tenantID uuid.UUID, req *models.CreateSettingsSnapshotRequest, userID *uuid.UUID) (*models.SettingsSnapshot, error)
+
+	// List returns the tenant's snapshots, newest first, optionally filtered by deploy reference
+	List(tenantID uuid.UUID, deployRef string) ([]models.SettingsSnapshot, error)
+
+	// Get returns a tenant's snapshot
+	Get(tenantID, id uuid.UUID) (*models.SettingsSnapshot, error)
+
+	// Delete removes a tenant's snapshot
+	Delete(tenantID, id uuid.UUID) error
+
+	// Restore atomically replaces the tenant's settings and themes with a snapshot,
+	// taking an automatic snapshot of the current state first
+	Restore(tenantID, id uuid.UUID, userID *uuid.UUID) (*models.RestoreSettingsSnapshotResult, error)
+
+	// Diff compares two snapshots; from and to are snapshot IDs or "current"
+	Diff(tenantID uuid.UUID, from, to string) (*models.SettingsSnapshotDiff, error)
+}
+
+type settingsSnapshotService struct {
+	repo      repository.SettingsSnapshotRepository
+	cache     *cache.SettingsCache
+	publisher SettingsEventPublisher
+	now       func() time.Time
+}
+
+// NewSettingsSnapshotService creates a new settings snapshot service. After a restore the
+// settings cache is invalidated and a settings event is published for every record written.
+func NewSettingsSnapshotService(repo repository.SettingsSnapshotRepository, settingsCache *cache.SettingsCache, publisher SettingsEventPublisher) SettingsSnapshotService {
+	return &settingsSnapshotService{
+		repo:      repo,
+		cache:     settingsCache,
+		publisher: publisher,
+		now:       time.Now,
+	}
+}
+
+// Create snapshots all of the tenant's settings and storefront themes
+func (s *settingsSnapshotService) Create(tenantID uuid.UUID, req *models.CreateSettingsSnapshotRequest, userID *uuid.UUID) (*models.SettingsSnapshot, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidSettingsSnapshot)
+	}
+
+	content, err := s.repo.LoadTenantState(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := encodeSettingsSnapshot(content)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.TenantID = tenantID
+	snapshot.Name = name
+	snapshot.Description = strings.TrimSpace(req.Description)
+	snapshot.DeployRef = strings.TrimSpace(req.DeployRef)
+	snapshot.CreatedBy = userID
+
+	if err := s.repo.Create(snapshot); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+			return nil, ErrSettingsSnapshotExists
+		}
+		return nil, fmt.Errorf("failed to save settings snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// List returns the tenant's snapshots, newest first
+func (s *settingsSnapshotService) List(tenantID uuid.UUID, deployRef string) ([]models.SettingsSnapshot, error) {
+	return s.repo.ListByTenant(tenantID, strings.TrimSpace(deployRef))
+}
+
+// Get returns a tenant's snapshot
+func (s *settingsSnapshotService) Get(tenantID, id uuid.UUID) (*models.SettingsSnapshot, error) {
+	snapshot, err := s.repo.GetByID(tenantID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSettingsSnapshotNotFound
+		}
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Delete removes a tenant's snapshot
+func (s *settingsSnapshotService) Delete(tenantID, id uuid.UUID) error {
+	if err := s.repo.Delete(tenantID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSettingsSnapshotNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Restore atomically replaces the tenant's settings and themes with a snapshot
+func (s *settingsSnapshotService) Restore(tenantID, id uuid.UUID, userID *uuid.UUID) (*models.RestoreSettingsSnapshotResult, error) {
+	snapshot, err := s.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	content, err := decodeSettingsSnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	restore, err := s.repo.Restore(snapshot, content, userID, func(current *models.SettingsSnapshotContent) (*models.SettingsSnapshot, error) {
+		pre, err := encodeSettingsSnapshot(current)
+		if err != nil {
+			return nil, err
+		}
+		pre.ID = uuid.New()
+		pre.TenantID = tenantID
+		pre.Name = fmt.Sprintf("pre-restore-%s-%s", now.Format("20060102T150405Z"), snapshot.ID.String()[:8])
+		pre.Description = truncate(fmt.Sprintf("Automatic snapshot before restoring %q", snapshot.Name), 500)
+		pre.Automatic = true
+		pre.CreatedBy = userID
+		return pre, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore settings snapshot: %w", err)
+	}
+
+	s.invalidate(tenantID, restore, snapshot, userID)
+	log.Printf("Restored settings snapshot %q for tenant %s (%d settings, %d themes; removed %d settings, %d themes)",
+		snapshot.Name, tenantID, len(restore.Settings), len(restore.Themes), len(restore.RemovedSettings), len(restore.RemovedThemes))
+
+	return &models.RestoreSettingsSnapshotResult{
+		Snapshot:         snapshot,
+		PreRestoreID:     restore.PreRestore.ID,
+		SettingsRestored: len(restore.Settings),
+		SettingsRemoved:  len(restore.RemovedSettings),
+		ThemesRestored:   len(restore.Themes),
+		ThemesRemoved:    len(restore.RemovedThemes),
+	}, nil
+}
+
+// invalidate drops the tenant's cached settings and themes and publishes a settings event per
+// record written, as the regular write path does
+func (s *settingsSnapshotService) invalidate(tenantID uuid.UUID, restore *repository.SnapshotRestore, snapshot *models.SettingsSnapshot, userID *uuid.UUID) {
+	s.cache.Invalidate(cache.NamespaceSettings, tenantID.String(), cache.InvalidationSourceWrite)
+	s.cache.Invalidate(cache.NamespaceTheme, tenantID.String(), cache.InvalidationSourceWrite)
+	themes := append(append([]models.StorefrontThemeSettings{}, restore.Themes...), restore.RemovedThemes...)
+	for _, theme := range themes {
+		s.cache.Invalidate(cache.NamespaceTheme, theme.StorefrontID.String(), cache.InvalidationSourceWrite)
+	}
+
+	if s.publisher == nil {
+		return
+	}
+	changedBy := ""
+	if userID != nil {
+		changedBy = userID.String()
+	}
+	value := map[string]interface{}{
+		"snapshotId":   snapshot.ID.String(),
+		"snapshotName": snapshot.Name,
+	}
+	ctx := context.Background()
+	settings := append(append([]models.Settings{}, restore.Settings...), restore.RemovedSettings...)
+	for _, record := range settings {
+		if err := s.publisher.PublishSettingUpdated(ctx, tenantID.String(), "settings."+record.ID.String(), models.SettingsCategory, nil, value, changedBy, ""); err != nil {
+			log.Printf("WARNING: Failed to publish settings event for tenant %s: %v", tenantID, err)
+		}
+	}
+	for _, theme := range themes {
+		if err := s.publisher.PublishSettingUpdated(ctx, tenantID.String(), ThemeSettingKey(theme.StorefrontID), models.StorefrontThemeCategory, nil, value, changedBy, ""); err != nil {
+			log.Printf("WARNING: Failed to publish storefront theme event for tenant %s: %v", tenantID, err)
+		}
+	}
+}
+
+// Diff compares two snapshots, or a snapshot and the current state
+func (s *settingsSnapshotService) Diff(tenantID uuid.UUID, from, to string) (*models.SettingsSnapshotDiff, error) {
+	fromContent, fromName, err := s.loadDiffSide(tenantID, from)
+	if err != nil {
+		return nil, err
+	}
+	toContent, toName, err := s.loadDiffSide(tenantID, to)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SettingsSnapshotDiff{
+		From:    fromName,
+		To:      toName,
+		Changes: DiffSettingsSnapshots(fromContent, toContent),
+	}, nil
+}
+
+// loadDiffSide loads a snapshot by ID, or the live state for "current"
+func (s *settingsSnapshotService) loadDiffSide(tenantID uuid.UUID, ref string) (*models.SettingsSnapshotContent, string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || ref == SnapshotCurrent {
+		content, err := s.repo.LoadTenantState(tenantID)
+		return content, SnapshotCurrent, err
+	}
+	id, err := uuid.Parse(ref)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %q is not a snapshot ID or %q", ErrInvalidSettingsSnapshot, ref, SnapshotCurrent)
+	}
+	snapshot, err := s.Get(tenantID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	content, err := decodeSettingsSnapshot(snapshot)
+	return content, snapshot.Name, err
+}
+
+// DiffSettingsSnapshots lists the differences between two snapshot contents. Settings are matched
+// by context and themes by storefront ID; nested JSON objects are compared field by field.
+func DiffSettingsSnapshots(from, to *models.SettingsSnapshotContent) []models.SettingsSnapshotChange {
+	changes := []models.SettingsSnapshotChange{}
+
+	fromSettings, toSettings := map[string]snapshotRecord{}, map[string]snapshotRecord{}
+	for i := range from.Settings {
+		fromSettings[repository.SettingsContextKey(&from.Settings[i])] = settingsRecord(&from.Settings[i])
+	}
+	for i := range to.Settings {
+		toSettings[repository.SettingsContextKey(&to.Settings[i])] = settingsRecord(&to.Settings[i])
+	}
+	changes = append(changes, diffRecords(models.SnapshotTargetSettings, fromSettings, toSettings)...)
+
+	fromThemes, toThemes := map[string]snapshotRecord{}, map[string]snapshotRecord{}
+	for i := range from.Themes {
+		fromThemes[from.Themes[i].StorefrontID.String()] = themeRecord(&from.Themes[i])
+	}
+	for i := range to.Themes {
+		toThemes[to.Themes[i].StorefrontID.String()] = themeRecord(&to.Themes[i])
+	}
+	changes = append(changes, diffRecords(models.SnapshotTargetStorefrontTheme, fromThemes, toThemes)...)
+	return changes
+}
+
+// snapshotRecord is a settings or theme record prepared for diffing
+type snapshotRecord struct {
+	id     uuid.UUID
+	label  string
+	fields map[string]interface{}
+}
+
+func settingsRecord(s *models.Settings) snapshotRecord {
+	label := s.Scope
+	if s.ApplicationID != uuid.Nil {
+		label += " (application " + s.ApplicationID.String() + ")"
+	}
+	if s.UserID != nil {
+		label += " (user " + s.UserID.String() + ")"
+	}
+	return snapshotRecord{id: s.ID, label: label, fields: recordFields(s)}
+}
+
+func themeRecord(t *models.StorefrontThemeSettings) snapshotRecord {
+	return snapshotRecord{id: t.ID, label: "storefront " + t.StorefrontID.String(), fields: recordFields(t)}
+}
+
+// recordFields converts a record to its JSON object without the fields that change on every write
+func recordFields(record interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(raw, &fields)
+	for _, field := range snapshotIgnoredFields {
+		delete(fields, field)
+	}
+	return fields
+}
+
+func diffRecords(target string, from, to map[string]snapshotRecord) []models.SettingsSnapshotChange {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []models.SettingsSnapshotChange
+	for _, key := range keys {
+		before, inFrom := from[key]
+		after, inTo := to[key]
+		switch {
+		case !inFrom:
+			changes = append(changes, models.SettingsSnapshotChange{Target: target, RecordID: after.id, Label: after.label, Change: models.SnapshotChangeAdded})
+		case !inTo:
+			changes = append(changes, models.SettingsSnapshotChange{Target: target, RecordID: before.id, Label: before.label, Change: models.SnapshotChangeRemoved})
+		default:
+			for _, c := range diffValues("", before.fields, after.fields) {
+				c.Target, c.RecordID, c.Label = target, after.id, after.label
+				changes = append(changes, c)
+			}
+		}
+	}
+	return changes
+}
+
+// diffValues compares two decoded JSON values, descending into objects; arrays are compared whole
+func diffValues(path string, before, after interface{}) []models.SettingsSnapshotChange {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if !beforeIsMap || !afterIsMap {
+		if reflect.DeepEqual(before, after) {
+			return nil
+		}
+		change := models.SnapshotChangeChanged
+		switch {
+		case before == nil:
+			change = models.SnapshotChangeAdded
+		case after == nil:
+			change = models.SnapshotChangeRemoved
+		}
+		return []models.SettingsSnapshotChange{{Change: change, Path: path, Before: before, After: after}}
+	}
+
+	keys := make([]string, 0, len(beforeMap)+len(afterMap))
+	for key := range beforeMap {
+		keys = append(keys, key)
+	}
+	for key := range afterMap {
+		if _, ok := beforeMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []models.SettingsSnapshotChange
+	for _, key := range keys {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		changes = append(changes, diffValues(childPath, beforeMap[key], afterMap[key])...)
+	}
+	return changes
+}
+
+// encodeSettingsSnapshot compresses snapshot content into a new snapshot record
+func encodeSettingsSnapshot(content *models.SettingsSnapshotContent) (*models.SettingsSnapshot, error) {
+	content.FormatVersion = models.SettingsSnapshotFormatVersion
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settings snapshot: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress settings snapshot: %w", err)
+	}
+	if _, err := zw.Write(raw); err != nil {
+		return nil, fmt.Errorf("failed to compress settings snapshot: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress settings snapshot: %w", err)
+	}
+
+	sum := sha256.Sum256(raw)
+	return &models.SettingsSnapshot{
+		FormatVersion:   content.FormatVersion,
+		Data:            buf.Bytes(),
+		Checksum:        hex.EncodeToString(sum[:]),
+		SizeBytes:       len(raw),
+		CompressedBytes: buf.Len(),
+		SettingsCount:   len(content.Settings),
+		ThemeCount:      len(content.Themes),
+	}, nil
+}
+
+// decodeSettingsSnapshot decompresses a snapshot and verifies its checksum
+func decodeSettingsSnapshot(snapshot *models.SettingsSnapshot) (*models.SettingsSnapshotContent, error) {
+	if snapshot.FormatVersion > models.SettingsSnapshotFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidSettingsSnapshot, snapshot.FormatVersion)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(snapshot.Data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSettingsSnapshotCorrupt, err)
+	}
+	defer zr.Close()
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSettingsSnapshotCorrupt, err)
+	}
+	sum := sha256.Sum256(raw)
+	if hex.EncodeToString(sum[:]) != snapshot.Checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrSettingsSnapshotCorrupt)
+	}
+
+	var content models.SettingsSnapshotContent
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSettingsSnapshotCorrupt, err)
+	}
+	return &content, nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
@@ -0,0 +1,654 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"settings-service/internal/models"
+	"settings-service/internal/repository"
+)
+
+// Validation webhook request headers; receivers verify X-Settings-Signature with SignSettingsValidationPayload
+const (
+	ValidationWebhookHeaderSignature = "X-Settings-Signature"
+	ValidationWebhookHeaderTimestamp = "X-Settings-Timestamp"
+	ValidationWebhookHeaderID        = "X-Settings-Webhook-Id"
+)
+
+// maxValidationResponseBytes caps how much of a webhook response is read
+const maxValidationResponseBytes = 64 << 10
+
+// maxViolationFieldLength caps the settings key a webhook can flag in a violation
+const maxViolationFieldLength = 200
+
+var (
+	// errValidationWebhookTargetBlocked is returned by the dialer when a tenant webhook resolves
+	// to an address inside the platform
+	errValidationWebhookTargetBlocked = errors.New("webhook address is not public")
+	// errInvalidValidationWebhookResponse is returned when a webhook's body is not a verdict
+	errInvalidValidationWebhookResponse = errors.New("invalid webhook response")
+)
+
+var (
+	// ErrInvalidValidationWebhook wraps validation webhook registration failures
+	ErrInvalidValidationWebhook = errors.New("invalid settings validation webhook")
+	// ErrValidationWebhookNotFound is returned when the webhook does not exist for the tenant
+	ErrValidationWebhookNotFound = errors.New("settings validation webhook not found")
+	// ErrSettingsValidationRejected is matched by errors.Is when a validation webhook rejects a settings change
+	ErrSettingsValidationRejected = errors.New("settings change rejected by validation webhook")
+)
+
+// SettingsValidationError carries the violations reported by the webhooks that rejected a settings change
+type SettingsValidationError struct {
+	Violations []models.SettingsValidationViolation
+}
+
+// Error lists the violations in a single message
+func (e *SettingsValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		if violation.Field != "" {
+			messages = append(messages, violation.Field+": "+violation.Message)
+		} else {
+			messages = append(messages, violation.Message)
+		}
+	}
+	return fmt.Sprintf("%s: %s", ErrSettingsValidationRejected, strings.Join(messages, "; "))
+}
+
+// Unwrap lets errors.Is match ErrSettingsValidationRejected
+func (e *SettingsValidationError) Unwrap() error {
+	return ErrSettingsValidationRejected
+}
+
+// SettingsChangeValidator validates a settings write before it is persisted.
+// current is nil when the settings are being created.
+type SettingsChangeValidator interface {
+	ValidateChange(ctx context.Context, operation string, proposed, current *models.Settings) error
+}
+
+// SettingsValidationWebhookService defines the interface for settings validation webhooks.
+// A nil tenant ID manages the platform-wide webhooks registered by internal services.
+type SettingsValidationWebhookService interface {
+	SettingsChangeValidator
+
+	// List returns the webhooks registered for a tenant, or the platform-wide ones
+	List(tenantID *uuid.UUID) ([]models.SettingsValidationWebhook, error)
+
+	// Get returns a webhook registered for a tenant, or a platform-wide one
+	Get(tenantID *uuid.UUID, id uuid.UUID) (*models.SettingsValidationWebhook, error)
+
+	// Create validates and registers a webhook, returning its signing secret
+	Create(tenantID *uuid.UUID, req *models.CreateSettingsValidationWebhookRequest, userID *uuid.UUID) (*models.SettingsValidationWebhook, string, error)
+
+	// Update applies a partial update to a webhook
+	Update(tenantID *uuid.UUID, id uuid.UUID, req *models.UpdateSettingsValidationWebhookRequest, userID *uuid.UUID) (*models.SettingsValidationWebhook, error)
+
+	// Delete removes a webhook
+	Delete(tenantID *uuid.UUID, id uuid.UUID) error
+
+	// RotateSecret replaces a webhook's signing secret and returns the new one
+	RotateSecret(tenantID *uuid.UUID, id uuid.UUID, userID *uuid.UUID) (string, error)
+}
+
+type settingsValidationWebhookService struct {
+	repo           repository.SettingsValidationWebhookRepository
+	platformClient *http.Client // Platform-wide webhooks, which may call in-cluster services
+	tenantClient   *http.Client // Tenant webhooks, which may only reach public addresses
+}
+
+// NewSettingsValidationWebhookService creates a new settings validation webhook service
+func NewSettingsValidationWebhookService(repo repository.SettingsValidationWebhookRepository) SettingsValidationWebhookService {
+	return &settingsValidationWebhookService{
+		repo:           repo,
+		platformClient: newValidationWebhookClient(false),
+		tenantClient:   newValidationWebhookClient(true),
+	}
+}
+
+// newValidationWebhookClient returns the client calling validation webhooks. With publicOnly it
+// refuses to connect to addresses inside the platform. Redirects are never followed.
+func newValidationWebhookClient(publicOnly bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if publicOnly {
+		// Checked on the resolved address so DNS can't point a tenant webhook at the cluster
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+				return errValidationWebhookTargetBlocked
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		// Per-call timeouts come from each webhook; this only guards against a missing deadline
+		Timeout: time.Duration(models.MaxValidationWebhookTimeoutMs) * time.Millisecond,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// A redirect is not a verdict; it could also lead past the address check
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// List returns the webhooks registered for a tenant, or the platform-wide ones
+func (s *settingsValidationWebhookService) List(tenantID *uuid.UUID) ([]models.SettingsValidationWebhook, error) {
+	return s.repo.ListByTenant(tenantID)
+}
+
+// Get returns a webhook registered for a tenant, or a platform-wide one
+func (s *settingsValidationWebhookService) Get(tenantID *uuid.UUID, id uuid.UUID) (*models.SettingsValidationWebhook, error) {
+	webhook, err := s.repo.GetByID(tenantID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrValidationWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to load settings validation webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// Create validates and registers a webhook, returning its signing secret
+func (s *settingsValidationWebhookService) Create(tenantID *uuid.UUID, req *models.CreateSettingsValidationWebhookRequest, userID *uuid.UUID) (*models.SettingsValidationWebhook, string, error) {
+	secret, err := newValidationWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	webhook := &models.SettingsValidationWebhook{
+		TenantID:      tenantID,
+		ApplicationID: req.ApplicationID,
+		Scope:         req.Scope,
+		Name:          req.Name,
+		URL:           req.URL,
+		Secret:        secret,
+		TimeoutMs:     req.TimeoutMs,
+		FailOpen:      req.FailOpen,
+		IsEnabled:     req.IsEnabled == nil || *req.IsEnabled,
+		CreatedBy:     userID,
+		UpdatedBy:     userID,
+	}
+	if err := validateValidationWebhook(webhook, req.Sections); err != nil {
+		return nil, "", err
+	}
+
+	if err := s.repo.Save(webhook); err != nil {
+		return nil, "", fmt.Errorf("failed to save settings validation webhook: %w", err)
+	}
+	return webhook, secret, nil
+}
+
+// Update applies a partial update to a webhook
+func (s *settingsValidationWebhookService) Update(tenantID *uuid.UUID, id uuid.UUID, req *models.UpdateSettingsValidationWebhookRequest, userID *uuid.UUID) (*models.SettingsValidationWebhook, error) {
+	webhook, err := s.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		webhook.Name = *req.Name
+	}
+	if req.URL != nil {
+		webhook.URL = *req.URL
+	}
+	if req.ApplicationID != nil {
+		webhook.ApplicationID = req.ApplicationID
+		if *req.ApplicationID == uuid.Nil {
+			webhook.ApplicationID = nil
+		}
+	}
+	if req.Scope != nil {
+		webhook.Scope = *req.Scope
+	}
+	if req.TimeoutMs != nil {
+		webhook.TimeoutMs = *req.TimeoutMs
+	}
+	if req.FailOpen != nil {
+		webhook.FailOpen = *req.FailOpen
+	}
+	if req.IsEnabled != nil {
+		webhook.IsEnabled = *req.IsEnabled
+	}
+
+	var sections []string
+	if req.Sections != nil {
+		sections = *req.Sections
+	} else {
+		decodeJSONInto(webhook.Sections, &sections)
+	}
+	if err := validateValidationWebhook(webhook, sections); err != nil {
+		return nil, err
+	}
+	webhook.UpdatedBy = userID
+
+	if err := s.repo.Save(webhook); err != nil {
+		return nil, fmt.Errorf("failed to save settings validation webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// Delete removes a webhook
+func (s *settingsValidationWebhookService) Delete(tenantID *uuid.UUID, id uuid.UUID) error {
+	if err := s.repo.Delete(tenantID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrValidationWebhookNotFound
+		}
+		return fmt.Errorf("failed to delete settings validation webhook: %w", err)
+	}
+	return nil
+}
+
+// RotateSecret replaces a webhook's signing secret and returns the new one
+func (s *settingsValidationWebhookService) RotateSecret(tenantID *uuid.UUID, id uuid.UUID, userID *uuid.UUID) (string, error) {
+	webhook, err := s.Get(tenantID, id)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := newValidationWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	webhook.Secret = secret
+	webhook.UpdatedBy = userID
+
+	if err := s.repo.Save(webhook); err != nil {
+		return "", fmt.Errorf("failed to save settings validation webhook: %w", err)
+	}
+	return secret, nil
+}
+
+// ValidateChange calls every enabled webhook that applies to the settings context and watches a
+// changed section, in parallel. The change is rejected when any webhook answers valid=false, or
+// when a webhook fails or times out and is not configured to fail open.
+func (s *settingsValidationWebhookService) ValidateChange(ctx context.Context, operation string, proposed, current *models.Settings) error {
+	settingsContext := models.SettingsContext{
+		TenantID:      proposed.TenantID,
+		ApplicationID: proposed.ApplicationID,
+		UserID:        proposed.UserID,
+		Scope:         proposed.Scope,
+	}
+	webhooks, err := s.repo.FindForContext(settingsContext)
+	if err != nil {
+		return fmt.Errorf("failed to load settings validation webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	changed := changedSettingsSections(proposed, current)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	violations := make([][]models.SettingsValidationViolation, len(webhooks))
+	var wg sync.WaitGroup
+	for i := range webhooks {
+		webhook := &webhooks[i]
+		watched := watchedSections(webhook, changed)
+		if len(watched) == 0 {
+			continue
+		}
+
+		payload := &models.SettingsValidationRequest{
+			ID:              uuid.New(),
+			Operation:       operation,
+			SettingsID:      proposed.ID,
+			Context:         settingsContext,
+			ChangedSections: watched,
+			Proposed:        sectionValues(proposed, watched),
+			Timestamp:       time.Now().UTC(),
+		}
+		if current != nil {
+			payload.Current = sectionValues(current, watched)
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			violations[i] = s.callWebhook(ctx, webhook, payload)
+		}(i)
+	}
+	wg.Wait()
+
+	var rejected []models.SettingsValidationViolation
+	for _, v := range violations {
+		rejected = append(rejected, v...)
+	}
+	if len(rejected) > 0 {
+		return &SettingsValidationError{Violations: rejected}
+	}
+	return nil
+}
+
+// callWebhook POSTs a signed validation request and returns the violations that reject the change.
+// Transport errors and unexpected responses reject the change unless the webhook fails open.
+// Nothing the webhook returns is passed on verbatim: violations keep the settings key they flag
+// and the webhook's own messages are only logged.
+func (s *settingsValidationWebhookService) callWebhook(ctx context.Context, webhook *models.SettingsValidationWebhook, payload *models.SettingsValidationRequest) []models.SettingsValidationViolation {
+	result, err := s.post(ctx, webhook, payload)
+	calledAt := time.Now()
+
+	if err != nil {
+		s.recordCall(webhook, models.ValidationWebhookStatusFailed, describeValidationWebhookError(ctx, err), calledAt)
+		if webhook.FailOpen {
+			log.Printf("WARNING: Settings validation webhook %s (%s) failed, allowing change (fail open): %v", webhook.Name, webhook.ID, err)
+			return nil
+		}
+		log.Printf("WARNING: Settings validation webhook %s (%s) failed, rejecting change: %v", webhook.Name, webhook.ID, err)
+		return []models.SettingsValidationViolation{{
+			Message: "validation webhook unavailable",
+			Webhook: webhook.Name,
+		}}
+	}
+
+	if result.Valid {
+		s.recordCall(webhook, models.ValidationWebhookStatusAccepted, "", calledAt)
+		return nil
+	}
+
+	s.recordCall(webhook, models.ValidationWebhookStatusRejected, "", calledAt)
+	if len(result.Errors) == 0 {
+		return []models.SettingsValidationViolation{{
+			Message: "rejected by validation webhook",
+			Webhook: webhook.Name,
+		}}
+	}
+	violations := make([]models.SettingsValidationViolation, 0, len(result.Errors))
+	for _, violation := range result.Errors {
+		log.Printf("Settings validation webhook %s (%s) rejected change: %s: %s", webhook.Name, webhook.ID, violation.Field, violation.Message)
+		violations = append(violations, models.SettingsValidationViolation{
+			Field:   violationField(violation.Field),
+			Message: "rejected by validation webhook",
+			Webhook: webhook.Name,
+		})
+	}
+	return violations
+}
+
+// violationField returns the settings key a webhook flagged, or "" when it is not one
+func violationField(field string) string {
+	field = strings.TrimSpace(field)
+	if field == "" || len(field) > maxViolationFieldLength {
+		return ""
+	}
+	for _, r := range field {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("._-[]", r)) {
+			return ""
+		}
+	}
+	section, _, _ := strings.Cut(field, ".")
+	if _, ok := settingsSections[section]; !ok {
+		return ""
+	}
+	return field
+}
+
+// describeValidationWebhookError summarizes a failed call for the webhook's lastError, which its
+// owner can read; the full error is only logged
+func describeValidationWebhookError(ctx context.Context, err error) string {
+	var statusErr *validationWebhookStatusError
+	switch {
+	case errors.As(err, &statusErr):
+		return statusErr.Error()
+	case errors.Is(err, errValidationWebhookTargetBlocked):
+		return "webhook address is not allowed"
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "webhook timed out"
+	case errors.Is(err, errInvalidValidationWebhookResponse):
+		return errInvalidValidationWebhookResponse.Error()
+	default:
+		return "webhook request failed"
+	}
+}
+
+// validationWebhookStatusError is returned when a webhook answers with a non-2xx status
+type validationWebhookStatusError struct {
+	StatusCode int
+}
+
+func (e *validationWebhookStatusError) Error() string {
+	return fmt.Sprintf("webhook returned HTTP %d", e.StatusCode)
+}
+
+// post sends the validation request and decodes the webhook's verdict
+func (s *settingsValidationWebhookService) post(ctx context.Context, webhook *models.SettingsValidationWebhook, payload *models.SettingsValidationRequest) (*models.SettingsValidationResult, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode validation request: %w", err)
+	}
+
+	timeout := webhook.TimeoutMs
+	if timeout <= 0 {
+		timeout = models.DefaultValidationWebhookTimeoutMs
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build validation request: %w", err)
+	}
+	timestamp := strconv.FormatInt(payload.Timestamp.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ValidationWebhookHeaderID, webhook.ID.String())
+	req.Header.Set(ValidationWebhookHeaderTimestamp, timestamp)
+	req.Header.Set(ValidationWebhookHeaderSignature, SignSettingsValidationPayload(webhook.Secret, timestamp, body))
+
+	client := s.platformClient
+	if webhook.TenantID != nil {
+		client = s.tenantClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &validationWebhookStatusError{StatusCode: resp.StatusCode}
+	}
+
+	var result models.SettingsValidationResult
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxValidationResponseBytes)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidValidationWebhookResponse, err)
+	}
+	return &result, nil
+}
+
+// recordCall stores the webhook's latest outcome so tenants can debug rejected changes
+func (s *settingsValidationWebhookService) recordCall(webhook *models.SettingsValidationWebhook, status, lastError string, calledAt time.Time) {
+	if len(lastError) > 1000 {
+		lastError = lastError[:1000]
+	}
+	if err := s.repo.RecordCall(webhook.ID, status, lastError, calledAt); err != nil {
+		log.Printf("WARNING: Failed to record settings validation webhook call for %s: %v", webhook.ID, err)
+	}
+}
+
+// SignSettingsValidationPayload returns the X-Settings-Signature of a request body:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the webhook secret
+func SignSettingsValidationPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateValidationWebhook checks a webhook's fields, normalizing its sections and timeout.
+// Tenant webhooks must use public HTTPS endpoints; platform-wide webhooks may call in-cluster services.
+func validateValidationWebhook(webhook *models.SettingsValidationWebhook, sections []string) error {
+	webhook.Name = strings.TrimSpace(webhook.Name)
+	if webhook.Name == "" || len(webhook.Name) > 255 {
+		return fmt.Errorf("%w: name is required and must be at most 255 characters", ErrInvalidValidationWebhook)
+	}
+
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || parsed.Host == "" || len(webhook.URL) > 2048 {
+		return fmt.Errorf("%w: url must be an absolute URL", ErrInvalidValidationWebhook)
+	}
+	if webhook.TenantID == nil {
+		if parsed.Scheme != "https" && parsed.Scheme != "http" {
+			return fmt.Errorf("%w: url must use http or https", ErrInvalidValidationWebhook)
+		}
+	} else {
+		if parsed.Scheme != "https" {
+			return fmt.Errorf("%w: url must use https", ErrInvalidValidationWebhook)
+		}
+		host := parsed.Hostname()
+		if ip := net.ParseIP(host); (ip != nil && isPrivateIP(ip)) || strings.EqualFold(host, "localhost") {
+			return fmt.Errorf("%w: url must not point at a private address", ErrInvalidValidationWebhook)
+		}
+	}
+
+	webhook.Scope = strings.TrimSpace(webhook.Scope)
+	if len(webhook.Scope) > 50 {
+		return fmt.Errorf("%w: scope must be at most 50 characters", ErrInvalidValidationWebhook)
+	}
+
+	normalized := make([]string, 0, len(sections))
+	seen := make(map[string]bool, len(sections))
+	for _, section := range sections {
+		section = strings.TrimSpace(section)
+		if _, ok := settingsSections[section]; !ok {
+			return fmt.Errorf("%w: unknown settings section %q", ErrInvalidValidationWebhook, section)
+		}
+		if seen[section] {
+			continue
+		}
+		seen[section] = true
+		normalized = append(normalized, section)
+	}
+	sort.Strings(normalized)
+	webhook.Sections = mustJSON(normalized)
+
+	switch {
+	case webhook.TimeoutMs == 0:
+		webhook.TimeoutMs = models.DefaultValidationWebhookTimeoutMs
+	case webhook.TimeoutMs < models.MinValidationWebhookTimeoutMs || webhook.TimeoutMs > models.MaxValidationWebhookTimeoutMs:
+		return fmt.Errorf("%w: timeoutMs must be between %d and %d", ErrInvalidValidationWebhook,
+			models.MinValidationWebhookTimeoutMs, models.MaxValidationWebhookTimeoutMs)
+	}
+	return nil
+}
+
+// isPrivateIP reports whether ip is internal to the platform
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast()
+}
+
+// changedSettingsSections lists the sections a write changes, sorted by name.
+// On create every non-empty section counts as changed.
+func changedSettingsSections(proposed, current *models.Settings) []string {
+	var changed []string
+	for name, section := range settingsSections {
+		value := section(proposed)
+		if current == nil {
+			if !isEmptySection(value) {
+				changed = append(changed, name)
+			}
+			continue
+		}
+		if !jsonEqual(value, section(current)) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// watchedSections returns the changed sections a webhook validates; a webhook without sections watches all
+func watchedSections(webhook *models.SettingsValidationWebhook, changed []string) []string {
+	var sections []string
+	decodeJSONInto(webhook.Sections, &sections)
+	if len(sections) == 0 {
+		return changed
+	}
+
+	watched := make(map[string]bool, len(sections))
+	for _, section := range sections {
+		watched[section] = true
+	}
+	var matched []string
+	for _, section := range changed {
+		if watched[section] {
+			matched = append(matched, section)
+		}
+	}
+	return matched
+}
+
+// sectionValues returns the named sections of a settings record
+func sectionValues(settings *models.Settings, sections []string) map[string]datatypes.JSON {
+	values := make(map[string]datatypes.JSON, len(sections))
+	for _, name := range sections {
+		value := settingsSections[name](settings)
+		if len(value) == 0 {
+			value = datatypes.JSON("null")
+		}
+		values[name] = value
+	}
+	return values
+}
+
+// isEmptySection reports whether a section holds no settings
+func isEmptySection(value datatypes.JSON) bool {
+	trimmed := strings.TrimSpace(string(value))
+	return trimmed == "" || trimmed == "{}" || trimmed == "null"
+}
+
+// jsonEqual compares two JSON documents ignoring key order and whitespace
+func jsonEqual(a, b datatypes.JSON) bool {
+	if isEmptySection(a) || isEmptySection(b) {
+		return isEmptySection(a) == isEmptySection(b)
+	}
+	var left, right interface{}
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return bytes.Equal(a, b)
+	}
+	leftJSON, _ := json.Marshal(left)
+	rightJSON, _ := json.Marshal(right)
+	return bytes.Equal(leftJSON, rightJSON)
+}
+
+// newValidationWebhookSecret generates a random signing secret
+func newValidationWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
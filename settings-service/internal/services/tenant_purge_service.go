@@ -0,0 +1,43 @@
+package services
+
+import (
+	"log"
+
+	"github.com/google/uuid"
+	"settings-service/internal/cache"
+	"settings-service/internal/repository"
+)
+
+// TenantPurgeService removes a deleted tenant's settings for tenant-service's deletion saga
+type TenantPurgeService interface {
+	// PurgeTenant permanently deletes the tenant's data and returns how many rows were removed.
+	// Purging a tenant that is already gone succeeds with nothing removed.
+	PurgeTenant(tenantID uuid.UUID) (int64, error)
+}
+
+type tenantPurgeService struct {
+	repo          repository.TenantDataRepository
+	settingsCache *cache.SettingsCache
+}
+
+// NewTenantPurgeService creates a new tenant purge service
+func NewTenantPurgeService(repo repository.TenantDataRepository, settingsCache *cache.SettingsCache) TenantPurgeService {
+	return &tenantPurgeService{repo: repo, settingsCache: settingsCache}
+}
+
+// PurgeTenant deletes the tenant's data and drops its cached settings and themes
+func (s *tenantPurgeService) PurgeTenant(tenantID uuid.UUID) (int64, error) {
+	purged, storefrontIDs, err := s.repo.Purge(tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	s.settingsCache.Invalidate(cache.NamespaceSettings, tenantID.String(), cache.InvalidationSourceWrite)
+	s.settingsCache.Invalidate(cache.NamespaceTheme, tenantID.String(), cache.InvalidationSourceWrite)
+	for _, storefrontID := range storefrontIDs {
+		s.settingsCache.Invalidate(cache.NamespaceTheme, storefrontID.String(), cache.InvalidationSourceWrite)
+	}
+
+	log.Printf("Purged %d settings rows of deleted tenant %s", purged, tenantID)
+	return purged, nil
+}
@@ -0,0 +1,115 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"settings-service/internal/services"
+)
+
+// DefaultScheduledChangeInterval is how often due scheduled changes are checked
+const DefaultScheduledChangeInterval = 30 * time.Second
+
+// ScheduledChangeRunner periodically applies scheduled settings and theme changes
+type ScheduledChangeRunner struct {
+	service  services.ScheduledChangeService
+	interval time.Duration
+	stopChan chan struct{}
+	doneChan chan struct{}
+	mu       sync.Mutex
+	running  bool
+	lastRun  time.Time
+	lastErr  error
+}
+
+// NewScheduledChangeRunner creates a new scheduled change runner
+func NewScheduledChangeRunner(service services.ScheduledChangeService, interval time.Duration) *ScheduledChangeRunner {
+	if interval == 0 {
+		interval = DefaultScheduledChangeInterval
+	}
+
+	return &ScheduledChangeRunner{
+		service:  service,
+		interval: interval,
+		stopChan: make(chan struct{}),
+		doneChan: make(chan struct{}),
+	}
+}
+
+// Start begins the scheduled change loop
+func (r *ScheduledChangeRunner) Start() {
+	r.mu.Lock()
+	if r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = true
+	r.mu.Unlock()
+
+	go r.run()
+	log.Printf("Scheduled change runner started with interval: %v", r.interval)
+}
+
+// Stop stops the scheduled change loop, waiting for an in-flight run to finish
+func (r *ScheduledChangeRunner) Stop() {
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = false
+	r.mu.Unlock()
+
+	close(r.stopChan)
+	<-r.doneChan
+	log.Println("Scheduled change runner stopped")
+}
+
+// LastRun returns the time of the last run and its error, if any
+func (r *ScheduledChangeRunner) LastRun() (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastRun, r.lastErr
+}
+
+// run is the main loop
+func (r *ScheduledChangeRunner) run() {
+	defer close(r.doneChan)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-r.stopChan
+		cancel()
+	}()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.applyDue(ctx)
+
+		select {
+		case <-r.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyDue applies due changes once
+func (r *ScheduledChangeRunner) applyDue(ctx context.Context) {
+	applied, err := r.service.ApplyDue(ctx)
+	if err != nil {
+		log.Printf("Scheduled change run failed: %v", err)
+	} else if applied > 0 {
+		log.Printf("Applied %d scheduled settings changes", applied)
+	}
+
+	r.mu.Lock()
+	r.lastRun = time.Now()
+	r.lastErr = err
+	r.mu.Unlock()
+}
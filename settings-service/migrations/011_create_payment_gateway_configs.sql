@@ -0,0 +1,53 @@
+-- Migration: Create payment_gateway_configs table
+-- Stores per-tenant payment provider configuration (Stripe, Razorpay, PayPal)
+-- Secret credentials are AES-256-GCM encrypted by the service before they reach the database
+
+CREATE TABLE IF NOT EXISTS payment_gateway_configs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    mode VARCHAR(8) NOT NULL DEFAULT 'test',
+    is_enabled BOOLEAN DEFAULT FALSE,
+    is_default BOOLEAN DEFAULT FALSE,
+    config JSONB,
+    encrypted_credentials TEXT,
+    credential_hints JSONB,
+    last_tested_at TIMESTAMP WITH TIME ZONE,
+    last_test_status VARCHAR(16),
+    last_test_message TEXT,
+    created_by UUID,
+    updated_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT chk_payment_gateway_mode CHECK (mode IN ('test', 'live'))
+);
+
+-- One config per provider per tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_gateway_tenant_provider
+    ON payment_gateway_configs(tenant_id, provider);
+
+CREATE INDEX IF NOT EXISTS idx_payment_gateway_configs_deleted_at
+    ON payment_gateway_configs(deleted_at);
+
+-- Create trigger for updated_at
+CREATE OR REPLACE FUNCTION update_payment_gateway_configs_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_payment_gateway_configs_updated_at ON payment_gateway_configs;
+
+CREATE TRIGGER trigger_payment_gateway_configs_updated_at
+    BEFORE UPDATE ON payment_gateway_configs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_payment_gateway_configs_updated_at();
+
+-- Add comments for documentation
+COMMENT ON TABLE payment_gateway_configs IS 'Per-tenant payment gateway configuration';
+COMMENT ON COLUMN payment_gateway_configs.config IS 'Non-secret provider fields (publishable keys, client IDs)';
+COMMENT ON COLUMN payment_gateway_configs.encrypted_credentials IS 'AES-256-GCM encrypted secret fields, bound to tenant_id and provider';
+COMMENT ON COLUMN payment_gateway_configs.credential_hints IS 'Masked secret values for display (e.g. sk_test_****1234)';
@@ -0,0 +1,30 @@
+-- Migration: Create settings_snapshots table
+-- Named, gzip-compressed copies of all of a tenant's settings and storefront themes,
+-- optionally bound to a deploy, restorable as a whole
+
+CREATE TABLE IF NOT EXISTS settings_snapshots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500),
+    deploy_ref VARCHAR(255),
+    automatic BOOLEAN DEFAULT FALSE,
+    format_version INTEGER NOT NULL DEFAULT 1,
+    data BYTEA NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    size_bytes INTEGER,
+    compressed_bytes INTEGER,
+    settings_count INTEGER,
+    theme_count INTEGER,
+    created_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_restored_at TIMESTAMP WITH TIME ZONE,
+    last_restored_by UUID
+);
+
+-- Snapshot names are unique per tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_settings_snapshot_tenant_name
+    ON settings_snapshots(tenant_id, name);
+
+CREATE INDEX IF NOT EXISTS idx_settings_snapshots_deploy_ref
+    ON settings_snapshots(deploy_ref);
@@ -1,20 +1,27 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"tenant-router-service/internal/certbackup"
 	"tenant-router-service/internal/config"
 	"tenant-router-service/internal/database"
 	"tenant-router-service/internal/handlers"
+	"tenant-router-service/internal/hostprobe"
 	"tenant-router-service/internal/k8s"
 	"tenant-router-service/internal/keycloak"
 	"tenant-router-service/internal/models"
@@ -23,6 +30,7 @@ import (
 	"tenant-router-service/internal/reconciler"
 	"tenant-router-service/internal/repository"
 	"tenant-router-service/internal/services"
+	"tenant-router-service/internal/storage"
 )
 
 func main() {
@@ -42,8 +50,9 @@ func main() {
 		log.Fatalf("Failed to run database migrations: %v", err)
 	}
 
-	// Initialize repository
+	// Initialize repositories
 	tenantHostRepo := repository.NewTenantHostRepository(db)
+	domainRouteRepo := repository.NewDomainRouteRepository(db)
 
 	// Initialize Kubernetes client
 	k8sClient, err := k8s.NewClient(cfg)
@@ -72,8 +81,29 @@ func main() {
 	// Initialize router service for VS sync operations
 	routerService := services.NewRouterService(k8sClient, tenantHostRepo, cfg)
 
+	// Initialize certificate backup (optional - restores TLS secrets deleted by accident)
+	var certBackup *certbackup.Service
+	if cfg.CertBackup.Enabled {
+		certBackup, err = newCertBackupService(k8sClient, tenantHostRepo, cfg)
+		if err != nil {
+			log.Printf("Warning: Failed to initialize certificate backup: %v (certificate backups disabled)", err)
+		} else {
+			log.Printf("Certificate backup enabled (bucket: %s, interval: %dm)", cfg.CertBackup.Bucket, cfg.CertBackup.IntervalMinutes)
+		}
+	}
+
+	// Initialize host probes (verifies provisioned hosts actually answer over HTTPS)
+	var hostProbe *hostprobe.Service
+	if cfg.HostProbe.Enabled {
+		hostProbe = hostprobe.NewService(tenantHostRepo, cfg)
+		log.Printf("Host probes enabled (interval: %dm, max attempts: %d)", cfg.HostProbe.IntervalMinutes, cfg.HostProbe.MaxAttempts)
+	}
+
 	// Initialize reconciler (Kubebuilder pattern)
 	tenantReconciler := reconciler.NewTenantReconciler(k8sClient, keycloakClient, tenantHostRepo, cfg)
+	if hostProbe != nil {
+		tenantReconciler.SetHostProber(hostProbe)
+	}
 
 	// Start reconciler workers (number of workers can be configured)
 	workerCount := 3
@@ -92,11 +122,15 @@ func main() {
 	defer cancel()
 
 	if natsSubscriber != nil {
+		natsSubscriber.SetDomainRoutes(domainRouteRepo)
 		if err := natsSubscriber.Start(ctx); err != nil {
 			log.Printf("Warning: Failed to start NATS subscriptions: %v (service will run without event-driven provisioning)", err)
 			natsSubscriber = nil // Set to nil so health check knows NATS is not active
 		}
 	}
+	if natsSubscriber != nil && hostProbe != nil {
+		hostProbe.SetPublisher(natsSubscriber)
+	}
 
 	// Initialize health handler
 	healthHandler := handlers.NewHealthHandler(k8sClient, natsSubscriber, db)
@@ -114,8 +148,11 @@ func main() {
 	router.GET("/health", healthHandler.Health)
 	router.GET("/ready", healthHandler.Ready)
 
-	// Metrics endpoint for observability
-	router.GET("/metrics", func(c *gin.Context) {
+	// Prometheus metrics (reconcile counters, queue depth, durations, per-slug failures)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// JSON view of the reconciler metrics for quick inspection
+	router.GET("/debug/metrics", func(c *gin.Context) {
 		metrics := tenantReconciler.GetMetrics()
 		c.JSON(http.StatusOK, gin.H{
 			"reconciler": gin.H{
@@ -186,12 +223,56 @@ func main() {
 		}()
 	}
 
+	// Start certificate backup job (uploads new or renewed TLS secrets to the backup bucket)
+	if certBackup != nil {
+		go func() {
+			ticker := time.NewTicker(time.Duration(cfg.CertBackup.IntervalMinutes) * time.Minute)
+			defer ticker.Stop()
+
+			// Run once at startup after a short delay
+			time.Sleep(2 * time.Minute)
+			runCertBackup(ctx, certBackup)
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					runCertBackup(ctx, certBackup)
+				}
+			}
+		}()
+	}
+
+	// Start host probe job (retries provisioned tenants whose hosts are not reachable yet)
+	if hostProbe != nil {
+		go func() {
+			ticker := time.NewTicker(time.Duration(cfg.HostProbe.IntervalMinutes) * time.Minute)
+			defer ticker.Stop()
+
+			// Run once at startup after a short delay
+			time.Sleep(1 * time.Minute)
+			runHostProbe(ctx, hostProbe)
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					runHostProbe(ctx, hostProbe)
+				}
+			}
+		}()
+	}
+
 	// API endpoints for tenant host management
 	api := router.Group("/api/v1")
 	{
 		// Register a new tenant host (for manual provisioning or NATS event replay)
 		// POST /api/v1/hosts
 		// Body: {"slug": "...", "tenant_id": "...", "admin_host": "...", "storefront_host": "...", ...}
+		// With ?dry_run=true the Certificate, Gateway and VirtualService manifests that would be
+		// applied are returned as YAML and nothing is written to the cluster or database
 		api.POST("/hosts", func(c *gin.Context) {
 			var req struct {
 				Slug              string `json:"slug" binding:"required"`
@@ -205,12 +286,20 @@ func main() {
 				Product           string `json:"product"`
 				BusinessName      string `json:"business_name"`
 				Email             string `json:"email"`
+				ForceHTTPS        bool   `json:"force_https"`    // redirect plain HTTP to HTTPS (custom domains)
+				PreferredHost     string `json:"preferred_host"` // "www" or "apex" (requires storefront_www_host)
 			}
 			if err := c.ShouldBindJSON(&req); err != nil {
 				c.JSON(http.StatusBadRequest, gin.H{"error": "slug and tenant_id are required"})
 				return
 			}
 
+			canonical := models.CanonicalPolicy{ForceHTTPS: req.ForceHTTPS, PreferredHost: req.PreferredHost}
+			if err := canonical.Validate(req.StorefrontWwwHost); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+
 			// Use config domain if hosts not provided
 			domain := cfg.Domain.BaseDomain
 			if req.AdminHost == "" {
@@ -239,6 +328,23 @@ func main() {
 				Product:           req.Product,
 				BusinessName:      req.BusinessName,
 				Email:             req.Email,
+				ForceHTTPS:        canonical.ForceHTTPS,
+				PreferredHost:     canonical.PreferredHost,
+			}
+
+			if dryRun, _ := strconv.ParseBool(c.Query("dry_run")); dryRun {
+				manifests, err := tenantReconciler.RenderCreate(c.Request.Context(), event)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+				out, err := k8s.ManifestsYAML(manifests)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+				c.Data(http.StatusOK, "application/yaml", out)
+				return
 			}
 
 			if err := tenantReconciler.EnqueueCreate(event); err != nil {
@@ -255,6 +361,7 @@ func main() {
 				"storefront_www_host": req.StorefrontWwwHost,
 				"api_host":           req.APIHost,
 				"is_custom_domain":   req.IsCustomDomain,
+				"canonical_policy":   canonical,
 			})
 		})
 
@@ -322,6 +429,53 @@ func main() {
 			})
 		})
 
+		// Resolve which tenant serves a host through its custom domains
+		// GET /api/v1/routes/resolve?host=east.shop.example.com
+		// An exact domain wins over a wildcard domain (*.shop.example.com) covering the same host
+		api.GET("/routes/resolve", func(c *gin.Context) {
+			host := models.NormalizeHost(c.Query("host"))
+			if host == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "host is required"})
+				return
+			}
+
+			route, err := domainRouteRepo.Resolve(c.Request.Context(), host)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			if route == nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "no route for host", "host": host})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"host":  host,
+				"route": route,
+			})
+		})
+
+		// List the custom domain routes of a tenant
+		// GET /api/v1/routes?tenant_id=...
+		api.GET("/routes", func(c *gin.Context) {
+			tenantID := c.Query("tenant_id")
+			if tenantID == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "tenant_id is required"})
+				return
+			}
+
+			routes, err := domainRouteRepo.ListByTenantID(c.Request.Context(), tenantID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"total":  len(routes),
+				"routes": routes,
+			})
+		})
+
 		// Sync endpoint - manually trigger reconciliation for a tenant
 		api.POST("/hosts/:slug/sync", func(c *gin.Context) {
 			slug := c.Param("slug")
@@ -371,6 +525,37 @@ func main() {
 				"storefront_vs_patched": record.StorefrontVSPatched,
 				"provisioned_at":       record.ProvisionedAt,
 				"last_error":           record.LastError,
+				"maintenance":          record.Maintenance(),
+				"rate_limit":           record.RateLimit(),
+				"traffic_weights":      record.SubsetWeights(),
+				"canonical_policy":     record.CanonicalPolicy(),
+				"reachability": gin.H{
+					"status":      record.ReachabilityStatus,
+					"attempts":    record.ReachabilityAttempts,
+					"checked_at":  record.ReachabilityCheckedAt,
+					"verified_at": record.ReachabilityVerifiedAt,
+					"hosts":       record.HostProbeResults(),
+				},
+			})
+		})
+
+		// Probe a tenant's hosts over HTTPS now
+		// POST /api/v1/hosts/:slug/probe
+		api.POST("/hosts/:slug/probe", func(c *gin.Context) {
+			if hostProbe == nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "host probes are not enabled"})
+				return
+			}
+
+			report, err := hostProbe.Probe(c.Request.Context(), c.Param("slug"))
+			if err != nil {
+				c.JSON(hostProbeErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"success": true,
+				"report":  report,
 			})
 		})
 
@@ -388,6 +573,287 @@ func main() {
 			})
 		})
 
+		// Import many tenant hosts at once, e.g. to rebuild a fresh cluster from an export when
+		// NATS replay isn't available
+		// POST /api/v1/hosts/bulk
+		// Body: [{"slug": "...", "tenant_id": "...", ...}, ...] (same fields as POST /api/v1/hosts)
+		// Entries are validated and deduplicated; valid ones are queued for provisioning in order.
+		// With ?dry_run=true only the validation results are returned.
+		api.POST("/hosts/bulk", func(c *gin.Context) {
+			var definitions []models.HostDefinition
+			if err := c.ShouldBindJSON(&definitions); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "body must be a JSON array of host definitions"})
+				return
+			}
+			if len(definitions) == 0 || len(definitions) > models.MaxBulkHosts {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("between 1 and %d host definitions are required", models.MaxBulkHosts)})
+				return
+			}
+
+			valid, results := models.PrepareBulkHosts(definitions, cfg.Domain.BaseDomain)
+			duplicates, invalid := 0, 0
+			for _, result := range results {
+				switch result.Status {
+				case models.BulkHostDuplicate:
+					duplicates++
+				case models.BulkHostInvalid:
+					invalid++
+				}
+			}
+			summary := gin.H{
+				"total":      len(definitions),
+				"queued":     len(valid),
+				"duplicates": duplicates,
+				"invalid":    invalid,
+				"results":    results,
+			}
+
+			if dryRun, _ := strconv.ParseBool(c.Query("dry_run")); dryRun {
+				summary["dry_run"] = true
+				c.JSON(http.StatusOK, summary)
+				return
+			}
+			if len(valid) == 0 {
+				c.JSON(http.StatusBadRequest, summary)
+				return
+			}
+
+			events := make([]*models.TenantCreatedEvent, len(valid))
+			for i := range valid {
+				events[i] = valid[i].Event()
+			}
+			tenantReconciler.EnqueueCreateBatch(events)
+
+			c.JSON(http.StatusAccepted, summary)
+		})
+
+		// Export the routing table of every tenant host, in the format accepted by POST /api/v1/hosts/bulk
+		// GET /api/v1/hosts/export?format=json|csv
+		api.GET("/hosts/export", func(c *gin.Context) {
+			format := c.DefaultQuery("format", "json")
+			if format != "json" && format != "csv" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+				return
+			}
+
+			records, _, err := tenantHostRepo.List(c.Request.Context(), nil, 0, 0)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			sort.Slice(records, func(i, j int) bool { return records[i].Slug < records[j].Slug })
+
+			definitions := make([]models.HostDefinition, len(records))
+			for i := range records {
+				definitions[i] = models.HostDefinitionFromRecord(&records[i])
+			}
+
+			filename := fmt.Sprintf("tenant-hosts-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+			c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+			if format == "csv" {
+				var buf bytes.Buffer
+				if err := models.WriteHostsCSV(&buf, definitions); err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+				c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+				return
+			}
+			c.JSON(http.StatusOK, definitions)
+		})
+
+		// Back up a tenant's TLS secret now
+		// POST /api/v1/hosts/:slug/certificate/backup
+		api.POST("/hosts/:slug/certificate/backup", func(c *gin.Context) {
+			if certBackup == nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "certificate backup is not enabled"})
+				return
+			}
+
+			result, err := certBackup.Backup(c.Request.Context(), c.Param("slug"))
+			if err != nil {
+				c.JSON(certBackupErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"success": true,
+				"backup":  result,
+			})
+		})
+
+		// Restore a tenant's TLS secret from its latest backup
+		// POST /api/v1/hosts/:slug/certificate/restore
+		// Body (optional): {"force": true} to replace an existing secret holding a different certificate
+		api.POST("/hosts/:slug/certificate/restore", func(c *gin.Context) {
+			if certBackup == nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "certificate backup is not enabled"})
+				return
+			}
+
+			var req struct {
+				Force bool `json:"force"`
+			}
+			if c.Request.ContentLength > 0 {
+				if err := c.ShouldBindJSON(&req); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+					return
+				}
+			}
+
+			result, err := certBackup.Restore(c.Request.Context(), c.Param("slug"), req.Force)
+			if err != nil {
+				c.JSON(certBackupErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"success": true,
+				"restore": result,
+			})
+		})
+
+		// Put a tenant into maintenance mode: its hosts answer with a 503 maintenance page
+		// POST /api/v1/hosts/:slug/maintenance
+		// Body (optional): {"message": "Back at 10:00 UTC", "retry_after_seconds": 3600}
+		api.POST("/hosts/:slug/maintenance", func(c *gin.Context) {
+			var policy models.MaintenancePolicy
+			if c.Request.ContentLength > 0 {
+				if err := c.ShouldBindJSON(&policy); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+					return
+				}
+			}
+
+			record, err := routerService.SetMaintenanceMode(c.Request.Context(), c.Param("slug"), policy)
+			if err != nil {
+				c.JSON(trafficPolicyErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"success":     true,
+				"slug":        record.Slug,
+				"maintenance": record.Maintenance(),
+				"since":       record.MaintenanceSince,
+			})
+		})
+
+		// Take a tenant out of maintenance mode
+		// DELETE /api/v1/hosts/:slug/maintenance
+		api.DELETE("/hosts/:slug/maintenance", func(c *gin.Context) {
+			record, err := routerService.ClearMaintenanceMode(c.Request.Context(), c.Param("slug"))
+			if err != nil {
+				c.JSON(trafficPolicyErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"success":     true,
+				"slug":        record.Slug,
+				"maintenance": nil,
+			})
+		})
+
+		// Set a tenant's rate limit, applied as annotations on its VirtualServices
+		// PUT /api/v1/hosts/:slug/rate-limit
+		// Body: {"requests_per_unit": 100, "unit": "second", "burst": 50}
+		api.PUT("/hosts/:slug/rate-limit", func(c *gin.Context) {
+			var policy models.RateLimitPolicy
+			if err := c.ShouldBindJSON(&policy); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+				return
+			}
+
+			record, err := routerService.SetRateLimit(c.Request.Context(), c.Param("slug"), policy)
+			if err != nil {
+				c.JSON(trafficPolicyErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"success":    true,
+				"slug":       record.Slug,
+				"rate_limit": record.RateLimit(),
+			})
+		})
+
+		// Remove a tenant's rate limit so the platform default applies
+		// DELETE /api/v1/hosts/:slug/rate-limit
+		api.DELETE("/hosts/:slug/rate-limit", func(c *gin.Context) {
+			record, err := routerService.ClearRateLimit(c.Request.Context(), c.Param("slug"))
+			if err != nil {
+				c.JSON(trafficPolicyErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"success":    true,
+				"slug":       record.Slug,
+				"rate_limit": nil,
+			})
+		})
+
+		// Split a tenant's traffic between destination subsets for a blue/green cutover
+		// PUT /api/v1/hosts/:slug/traffic-weights
+		// Body: {"weights": [{"subset": "blue", "weight": 90}, {"subset": "green", "weight": 10}]}
+		api.PUT("/hosts/:slug/traffic-weights", func(c *gin.Context) {
+			var req struct {
+				Weights []models.SubsetWeight `json:"weights" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "weights are required"})
+				return
+			}
+
+			record, err := routerService.SetTrafficWeights(c.Request.Context(), c.Param("slug"), req.Weights)
+			if err != nil {
+				c.JSON(trafficPolicyErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			respondTrafficWeights(c, tenantReconciler, record)
+		})
+
+		// Send all of a tenant's traffic to the template destinations again
+		// DELETE /api/v1/hosts/:slug/traffic-weights
+		api.DELETE("/hosts/:slug/traffic-weights", func(c *gin.Context) {
+			record, err := routerService.ClearTrafficWeights(c.Request.Context(), c.Param("slug"))
+			if err != nil {
+				c.JSON(trafficPolicyErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			respondTrafficWeights(c, tenantReconciler, record)
+		})
+
+		// Set a tenant's canonicalization policy: redirect HTTP to HTTPS and the storefront to www or apex
+		// PUT /api/v1/hosts/:slug/canonical-policy
+		// Body: {"force_https": true, "preferred_host": "www"}
+		api.PUT("/hosts/:slug/canonical-policy", func(c *gin.Context) {
+			var policy models.CanonicalPolicy
+			if err := c.ShouldBindJSON(&policy); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+				return
+			}
+
+			record, err := routerService.SetCanonicalPolicy(c.Request.Context(), c.Param("slug"), policy)
+			if err != nil {
+				c.JSON(trafficPolicyErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			respondCanonicalPolicy(c, tenantReconciler, record)
+		})
+
+		// Remove a tenant's canonical redirects
+		// DELETE /api/v1/hosts/:slug/canonical-policy
+		api.DELETE("/hosts/:slug/canonical-policy", func(c *gin.Context) {
+			record, err := routerService.ClearCanonicalPolicy(c.Request.Context(), c.Param("slug"))
+			if err != nil {
+				c.JSON(trafficPolicyErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			respondCanonicalPolicy(c, tenantReconciler, record)
+		})
+
 		// Sync VirtualService routes for a specific tenant
 		// POST /api/v1/hosts/:slug/sync-routes
 		// Body: {"vs_type": "api"} // admin, storefront, or api
@@ -521,6 +987,119 @@ func runCleanup(ctx context.Context, repo repository.TenantHostRepository, reten
 	log.Printf("[Cleanup] Cleanup completed: %d records permanently deleted", deleted)
 }
 
+// newCertBackupService creates the certificate backup service backed by the configured GCS bucket
+func newCertBackupService(k8sClient *k8s.Client, repo repository.TenantHostRepository, cfg *config.Config) (*certbackup.Service, error) {
+	store, err := storage.NewGCSStore(context.Background(), cfg.CertBackup.Bucket, cfg.CertBackup.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	return certbackup.NewService(k8sClient, store, repo, cfg)
+}
+
+// runCertBackup backs up the TLS secrets of all provisioned tenants
+func runCertBackup(ctx context.Context, certBackup *certbackup.Service) {
+	results, err := certBackup.BackupAll(ctx)
+	backedUp := 0
+	for _, result := range results {
+		if result.Status == "backed_up" {
+			backedUp++
+		}
+	}
+	if err != nil {
+		log.Printf("[CertBackup] Backup run finished with errors: %v (%d backed up)", err, backedUp)
+		return
+	}
+	log.Printf("[CertBackup] Backup run completed: %d of %d certificates backed up, rest unchanged or not issued", backedUp, len(results))
+}
+
+// certBackupErrorStatus maps certificate backup errors to HTTP status codes
+func certBackupErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, certbackup.ErrHostNotFound), errors.Is(err, certbackup.ErrBackupNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, certbackup.ErrSecretExists):
+		return http.StatusConflict
+	case errors.Is(err, certbackup.ErrCertificateMismatch):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// runHostProbe probes the hosts of provisioned tenants that have not been verified yet
+func runHostProbe(ctx context.Context, hostProbe *hostprobe.Service) {
+	reports, err := hostProbe.ProbePending(ctx)
+	verified := 0
+	for _, report := range reports {
+		if report.Status == models.ReachabilityVerified {
+			verified++
+		}
+	}
+	if err != nil {
+		log.Printf("[HostProbe] Probe run finished with errors: %v (%d verified)", err, verified)
+		return
+	}
+	if len(reports) > 0 {
+		log.Printf("[HostProbe] Probe run completed: %d of %d tenants verified", verified, len(reports))
+	}
+}
+
+// hostProbeErrorStatus maps host probe errors to HTTP status codes
+func hostProbeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, hostprobe.ErrHostNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, hostprobe.ErrNotProvisioned):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// trafficPolicyErrorStatus maps maintenance and rate limit errors to HTTP status codes
+func trafficPolicyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTenantHostNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidTrafficPolicy):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondTrafficWeights enqueues the reconciliation that renders a tenant's stored traffic
+// split onto its VirtualServices
+func respondTrafficWeights(c *gin.Context, tenantReconciler *reconciler.TenantReconciler, record *models.TenantHostRecord) {
+	if err := tenantReconciler.EnqueueSync(c.Request.Context(), record.Slug); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"slug":    record.Slug,
+		"weights": record.SubsetWeights(),
+		"message": fmt.Sprintf("Traffic weights saved, sync enqueued for tenant %s", record.Slug),
+	})
+}
+
+// respondCanonicalPolicy enqueues the reconciliation that renders a tenant's stored
+// canonicalization policy onto its VirtualServices
+func respondCanonicalPolicy(c *gin.Context, tenantReconciler *reconciler.TenantReconciler, record *models.TenantHostRecord) {
+	if err := tenantReconciler.EnqueueSync(c.Request.Context(), record.Slug); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success":          true,
+		"slug":             record.Slug,
+		"canonical_policy": record.CanonicalPolicy(),
+		"message":          fmt.Sprintf("Canonical policy saved, sync enqueued for tenant %s", record.Slug),
+	})
+}
+
 // syncGatewayIP fetches the custom domain gateway IP from K8s and stores it in Redis
 func syncGatewayIP(ctx context.Context, k8sClient *k8s.Client, redis *redisClient.Client) {
 	ip, err := k8sClient.GetCustomDomainGatewayIP(ctx)
@@ -0,0 +1,99 @@
+package certbackup
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"tenant-router-service/internal/models"
+)
+
+// Keys of a kubernetes.io/tls secret
+const (
+	tlsCertKey = "tls.crt"
+	tlsKeyKey  = "tls.key"
+)
+
+// ErrCertificateMismatch is returned when a backed-up certificate cannot serve the tenant's hosts
+var ErrCertificateMismatch = errors.New("certificate does not match tenant hosts")
+
+// Bundle is the backed-up content of a tenant TLS secret
+type Bundle struct {
+	Slug        string            `json:"slug"`
+	Secret      *models.TLSSecret `json:"secret"`
+	DNSNames    []string          `json:"dns_names"`
+	NotAfter    time.Time         `json:"not_after"`
+	Fingerprint string            `json:"fingerprint"` // SHA-256 of the leaf certificate
+	BackedUpAt  time.Time         `json:"backed_up_at"`
+}
+
+// newBundle inspects a TLS secret and wraps it for backup
+func newBundle(slug string, secret *models.TLSSecret) (*Bundle, error) {
+	leaf, err := parseLeaf(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bundle{
+		Slug:        slug,
+		Secret:      secret,
+		DNSNames:    leaf.DNSNames,
+		NotAfter:    leaf.NotAfter,
+		Fingerprint: fingerprint(leaf),
+		BackedUpAt:  time.Now().UTC(),
+	}, nil
+}
+
+// Verify checks that the bundle holds a valid key pair that covers every host and is not expired
+func (b *Bundle) Verify(hosts []string, now time.Time) error {
+	if b.Secret == nil {
+		return fmt.Errorf("%w: backup has no secret", ErrCertificateMismatch)
+	}
+	leaf, err := parseLeaf(b.Secret)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCertificateMismatch, err)
+	}
+	if fingerprint(leaf) != b.Fingerprint {
+		return fmt.Errorf("%w: certificate fingerprint differs from the one recorded at backup", ErrCertificateMismatch)
+	}
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("%w: certificate expired at %s", ErrCertificateMismatch, leaf.NotAfter.Format(time.RFC3339))
+	}
+	for _, host := range hosts {
+		if err := leaf.VerifyHostname(host); err != nil {
+			return fmt.Errorf("%w: %v", ErrCertificateMismatch, err)
+		}
+	}
+	return nil
+}
+
+// parseLeaf returns the leaf certificate of a TLS secret after checking it matches the private key
+func parseLeaf(secret *models.TLSSecret) (*x509.Certificate, error) {
+	certPEM, keyPEM := secret.Data[tlsCertKey], secret.Data[tlsKeyKey]
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return nil, fmt.Errorf("secret %s/%s is missing %s or %s", secret.Namespace, secret.Name, tlsCertKey, tlsKeyKey)
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return nil, fmt.Errorf("secret %s/%s has an invalid key pair: %w", secret.Namespace, secret.Name, err)
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("secret %s/%s has no PEM certificate", secret.Namespace, secret.Name)
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate in %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	return leaf, nil
+}
+
+func fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,135 @@
+package certbackup
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"tenant-router-service/internal/models"
+)
+
+func newTestSecret(t *testing.T, dnsNames []string, notAfter time.Time) *models.TLSSecret {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	return &models.TLSSecret{
+		Namespace: "istio-ingress",
+		Name:      "acme-tenant-tls",
+		Data: map[string][]byte{
+			tlsCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			tlsKeyKey:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}
+}
+
+func TestBundleVerify_MatchingHosts(t *testing.T) {
+	now := time.Now()
+	secret := newTestSecret(t, []string{"acme.com", "www.acme.com", "admin.acme.com"}, now.Add(30*24*time.Hour))
+
+	bundle, err := newBundle("acme", secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := bundle.Verify([]string{"acme.com", "admin.acme.com"}, now); err != nil {
+		t.Errorf("expected certificate to match, got %v", err)
+	}
+}
+
+func TestBundleVerify_Rejects(t *testing.T) {
+	now := time.Now()
+	valid := newTestSecret(t, []string{"acme.com"}, now.Add(30*24*time.Hour))
+	other := newTestSecret(t, []string{"acme.com"}, now.Add(30*24*time.Hour))
+
+	testCases := []struct {
+		name   string
+		bundle func() *Bundle
+		hosts  []string
+		at     time.Time
+	}{
+		{
+			name:   "host not covered",
+			bundle: func() *Bundle { b, _ := newBundle("acme", valid); return b },
+			hosts:  []string{"acme.com", "admin.acme.com"},
+			at:     now,
+		},
+		{
+			name:   "expired",
+			bundle: func() *Bundle { b, _ := newBundle("acme", valid); return b },
+			hosts:  []string{"acme.com"},
+			at:     now.Add(31 * 24 * time.Hour),
+		},
+		{
+			name: "key does not match certificate",
+			bundle: func() *Bundle {
+				b, _ := newBundle("acme", valid)
+				mixed := *valid
+				mixed.Data = map[string][]byte{tlsCertKey: valid.Data[tlsCertKey], tlsKeyKey: other.Data[tlsKeyKey]}
+				b.Secret = &mixed
+				return b
+			},
+			hosts: []string{"acme.com"},
+			at:    now,
+		},
+		{
+			name: "certificate replaced after backup",
+			bundle: func() *Bundle {
+				b, _ := newBundle("acme", valid)
+				b.Secret = other
+				return b
+			},
+			hosts: []string{"acme.com"},
+			at:    now,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.bundle().Verify(tc.hosts, tc.at)
+		if !errors.Is(err, ErrCertificateMismatch) {
+			t.Errorf("%s: expected ErrCertificateMismatch, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestCertificateHosts(t *testing.T) {
+	record := &models.TenantHostRecord{
+		StorefrontHost:    "acme.com",
+		AdminHost:         "admin.acme.com",
+		StorefrontWwwHost: "www.acme.com",
+		APIHost:           "api.acme.com",
+		IsCustomDomain:    true,
+	}
+	if got := certificateHosts(record); len(got) != 4 {
+		t.Errorf("expected 4 hosts for a custom domain, got %v", got)
+	}
+
+	record.IsCustomDomain = false
+	if got := certificateHosts(record); len(got) != 2 {
+		t.Errorf("expected storefront and admin hosts for a default domain, got %v", got)
+	}
+}
@@ -0,0 +1,313 @@
+package certbackup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Tesseract-Nexus/go-shared/security"
+
+	"tenant-router-service/internal/config"
+	"tenant-router-service/internal/models"
+	"tenant-router-service/internal/repository"
+	"tenant-router-service/internal/storage"
+)
+
+// Errors returned by Restore
+var (
+	ErrHostNotFound   = errors.New("tenant host not found")
+	ErrBackupNotFound = errors.New("no certificate backup found")
+	ErrSecretExists   = errors.New("a different TLS secret already exists")
+)
+
+// fingerprintMetadataKey stores the certificate fingerprint on the backup object
+// so unchanged certificates are not uploaded again
+const fingerprintMetadataKey = "fingerprint"
+
+// SecretClient reads and writes TLS secrets in the cluster
+type SecretClient interface {
+	GetTLSSecret(ctx context.Context, namespace, name string) (*models.TLSSecret, error)
+	ApplyTLSSecret(ctx context.Context, secret *models.TLSSecret) error
+}
+
+// ObjectStore persists encrypted backups
+// Get and Metadata return storage.ErrObjectNotFound when the object does not exist
+type ObjectStore interface {
+	Put(ctx context.Context, name string, data []byte, metadata map[string]string) error
+	Get(ctx context.Context, name string) ([]byte, error)
+	Metadata(ctx context.Context, name string) (map[string]string, error)
+}
+
+// BackupResult describes the outcome of backing up one tenant certificate
+type BackupResult struct {
+	Slug        string    `json:"slug"`
+	Secret      string    `json:"secret"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	NotAfter    time.Time `json:"not_after,omitempty"`
+	Status      string    `json:"status"` // backed_up, unchanged, no_secret
+}
+
+// RestoreResult describes a restored certificate
+type RestoreResult struct {
+	Slug        string    `json:"slug"`
+	Secret      string    `json:"secret"`
+	Fingerprint string    `json:"fingerprint"`
+	DNSNames    []string  `json:"dns_names"`
+	NotAfter    time.Time `json:"not_after"`
+	BackedUpAt  time.Time `json:"backed_up_at"`
+	Status      string    `json:"status"` // restored, unchanged
+}
+
+// Service backs up issued tenant TLS secrets to an encrypted bucket and restores them
+type Service struct {
+	secrets   SecretClient
+	store     ObjectStore
+	repo      repository.TenantHostRepository
+	encryptor *security.PIIEncryptor
+	config    *config.Config
+}
+
+// NewService creates a certificate backup service
+// The backup payload is encrypted with AES-256-GCM on top of the bucket's own encryption at rest
+func NewService(secrets SecretClient, store ObjectStore, repo repository.TenantHostRepository, cfg *config.Config) (*Service, error) {
+	encryptor, err := security.NewPIIEncryptorFromBase64(cfg.CertBackup.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate backup encryption key: %w", err)
+	}
+
+	return &Service{
+		secrets:   secrets,
+		store:     store,
+		repo:      repo,
+		encryptor: encryptor,
+		config:    cfg,
+	}, nil
+}
+
+// BackupAll backs up the certificates of all provisioned tenants
+func (s *Service) BackupAll(ctx context.Context) ([]BackupResult, error) {
+	records, err := s.repo.ListByStatus(ctx, models.HostStatusProvisioned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provisioned hosts: %w", err)
+	}
+
+	results := make([]BackupResult, 0, len(records))
+	var failed int
+	for i := range records {
+		result, err := s.backupRecord(ctx, &records[i])
+		if err != nil {
+			failed++
+			log.Printf("[CertBackup] Failed to back up certificate for %s: %v", records[i].Slug, err)
+			continue
+		}
+		results = append(results, *result)
+	}
+
+	if failed > 0 {
+		return results, fmt.Errorf("failed to back up %d of %d certificates", failed, len(records))
+	}
+	return results, nil
+}
+
+// Backup backs up the certificate of one tenant
+func (s *Service) Backup(ctx context.Context, slug string) (*BackupResult, error) {
+	record, err := s.getRecord(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.backupRecord(ctx, record)
+}
+
+func (s *Service) backupRecord(ctx context.Context, record *models.TenantHostRecord) (*BackupResult, error) {
+	namespace, name := s.secretLocation(record)
+	result := &BackupResult{Slug: record.Slug, Secret: fmt.Sprintf("%s/%s", namespace, name)}
+
+	secret, err := s.secrets.GetTLSSecret(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		// Not issued yet, or the tenant is served by the wildcard certificate
+		result.Status = "no_secret"
+		return result, nil
+	}
+
+	bundle, err := newBundle(record.Slug, secret)
+	if err != nil {
+		return nil, err
+	}
+	result.Fingerprint = bundle.Fingerprint
+	result.NotAfter = bundle.NotAfter
+
+	metadata, err := s.store.Metadata(ctx, objectName(record.Slug))
+	if err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, err
+	}
+	if err == nil && metadata[fingerprintMetadataKey] == bundle.Fingerprint {
+		result.Status = "unchanged"
+		return result, nil
+	}
+
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode backup: %w", err)
+	}
+	encrypted, err := s.encryptor.Encrypt(string(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt backup: %w", err)
+	}
+
+	startTime := time.Now()
+	err = s.store.Put(ctx, objectName(record.Slug), []byte(encrypted), map[string]string{
+		fingerprintMetadataKey: bundle.Fingerprint,
+		"not_after":            bundle.NotAfter.Format(time.RFC3339),
+	})
+	s.logActivity(ctx, record, "backup_certificate", namespace, err, time.Since(startTime))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[CertBackup] Backed up certificate %s for %s (expires %s)", result.Secret, record.Slug, bundle.NotAfter.Format(time.RFC3339))
+	result.Status = "backed_up"
+	return result, nil
+}
+
+// Restore recreates a tenant's TLS secret from its latest backup
+// The backed-up certificate must cover all of the tenant's hosts and still be valid.
+// An existing secret holding a different certificate is only replaced when force is set.
+func (s *Service) Restore(ctx context.Context, slug string, force bool) (*RestoreResult, error) {
+	record, err := s.getRecord(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.store.Get(ctx, objectName(slug))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, fmt.Errorf("%w for %s", ErrBackupNotFound, slug)
+	}
+	if err != nil {
+		return nil, err
+	}
+	payload, err := s.encryptor.Decrypt(string(encrypted))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt backup: %w", err)
+	}
+	var bundle Bundle
+	if err := json.Unmarshal([]byte(payload), &bundle); err != nil {
+		return nil, fmt.Errorf("failed to decode backup: %w", err)
+	}
+
+	if err := bundle.Verify(certificateHosts(record), time.Now()); err != nil {
+		return nil, err
+	}
+
+	namespace, name := s.secretLocation(record)
+	result := &RestoreResult{
+		Slug:        slug,
+		Secret:      fmt.Sprintf("%s/%s", namespace, name),
+		Fingerprint: bundle.Fingerprint,
+		DNSNames:    bundle.DNSNames,
+		NotAfter:    bundle.NotAfter,
+		BackedUpAt:  bundle.BackedUpAt,
+	}
+
+	existing, err := s.secrets.GetTLSSecret(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if leaf, err := parseLeaf(existing); err == nil && fingerprint(leaf) == bundle.Fingerprint {
+			result.Status = "unchanged"
+			return result, nil
+		}
+		if !force {
+			return nil, fmt.Errorf("%w: %s", ErrSecretExists, result.Secret)
+		}
+	}
+
+	// Restore into the tenant's current location even if the backup was taken elsewhere
+	secret := *bundle.Secret
+	secret.Namespace = namespace
+	secret.Name = name
+
+	startTime := time.Now()
+	err = s.secrets.ApplyTLSSecret(ctx, &secret)
+	s.logActivity(ctx, record, "restore_certificate", namespace, err, time.Since(startTime))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[CertBackup] Restored certificate %s for %s from backup taken at %s", result.Secret, slug, bundle.BackedUpAt.Format(time.RFC3339))
+	result.Status = "restored"
+	return result, nil
+}
+
+func (s *Service) getRecord(ctx context.Context, slug string) (*models.TenantHostRecord, error) {
+	record, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("%w: %s", ErrHostNotFound, slug)
+	}
+	return record, nil
+}
+
+// secretLocation returns where the reconciler put the tenant's certificate secret
+func (s *Service) secretLocation(record *models.TenantHostRecord) (string, string) {
+	name := record.CertName
+	if name == "" {
+		name = fmt.Sprintf("%s-tenant-tls", record.Slug)
+	}
+
+	namespace := record.CertificateNamespace
+	if namespace == "" {
+		if record.IsCustomDomain {
+			namespace = s.config.Kubernetes.CustomDomainGatewayNS
+		} else {
+			namespace = s.config.Kubernetes.Namespace
+		}
+	}
+	return namespace, name
+}
+
+func (s *Service) logActivity(ctx context.Context, record *models.TenantHostRecord, action, namespace string, err error, duration time.Duration) {
+	activityLog := &models.ProvisioningActivityLog{
+		TenantHostID: record.ID,
+		Action:       action,
+		Resource:     "Secret",
+		Namespace:    namespace,
+		Success:      err == nil,
+		Duration:     duration.Milliseconds(),
+	}
+	if err != nil {
+		activityLog.ErrorMessage = err.Error()
+	}
+	if logErr := s.repo.LogActivity(ctx, activityLog); logErr != nil {
+		log.Printf("[CertBackup] Failed to log activity: %v", logErr)
+	}
+}
+
+// certificateHosts lists the hosts the tenant's certificate is issued for (see reconcileCertificate)
+func certificateHosts(record *models.TenantHostRecord) []string {
+	hosts := []string{record.StorefrontHost}
+	if record.AdminHost != "" && record.AdminHost != record.StorefrontHost {
+		hosts = append(hosts, record.AdminHost)
+	}
+	if record.IsCustomDomain {
+		if record.StorefrontWwwHost != "" {
+			hosts = append(hosts, record.StorefrontWwwHost)
+		}
+		if record.APIHost != "" {
+			hosts = append(hosts, record.APIHost)
+		}
+	}
+	return hosts
+}
+
+func objectName(slug string) string {
+	return fmt.Sprintf("%s.json.enc", slug)
+}
@@ -17,6 +17,29 @@ type Config struct {
 	Kubernetes K8sConfig
 	Domain     DomainConfig
 	Keycloak   KeycloakConfig
+	CertBackup CertBackupConfig
+	HostProbe  HostProbeConfig
+}
+
+// HostProbeConfig holds configuration for probing tenant hosts over HTTPS after provisioning
+type HostProbeConfig struct {
+	Enabled             bool
+	InitialDelaySeconds int    // Wait after provisioning before the first probe (certificate issuance, DNS)
+	IntervalMinutes     int    // How often unverified hosts are probed again
+	TimeoutSeconds      int    // Per-request timeout
+	MaxAttempts         int    // Stop automatic probing after this many failed runs
+	AdminPath           string // Path requested on the admin host
+	StorefrontPath      string // Path requested on the storefront hosts
+	APIPath             string // Path requested on the API host
+}
+
+// CertBackupConfig holds configuration for backing up issued TLS secrets to object storage
+type CertBackupConfig struct {
+	Enabled         bool
+	Bucket          string // GCS bucket holding the encrypted backups
+	Prefix          string // Object prefix inside the bucket
+	EncryptionKey   string // Base64-encoded 32-byte AES-256 key
+	IntervalMinutes int
 }
 
 // KeycloakConfig holds Keycloak admin API configuration for redirect URI management
@@ -145,6 +168,23 @@ func LoadConfig() *Config {
 			AdminClientSecret: getEnv("KEYCLOAK_ADMIN_CLIENT_SECRET", ""),
 			ClientIDs:         getEnvStringSlice("KEYCLOAK_CLIENT_IDS", "storefront-web,marketplace-dashboard"),
 		},
+		CertBackup: CertBackupConfig{
+			Enabled:         getEnvBool("CERT_BACKUP_ENABLED", false),
+			Bucket:          getEnv("CERT_BACKUP_BUCKET", ""),
+			Prefix:          getEnv("CERT_BACKUP_PREFIX", "tenant-certificates"),
+			EncryptionKey:   secrets.GetSecretOrEnv("CERT_BACKUP_KEY_SECRET_NAME", "CERT_BACKUP_ENCRYPTION_KEY", ""),
+			IntervalMinutes: getEnvInt("CERT_BACKUP_INTERVAL_MINUTES", 360),
+		},
+		HostProbe: HostProbeConfig{
+			Enabled:             getEnvBool("HOST_PROBE_ENABLED", true),
+			InitialDelaySeconds: getEnvInt("HOST_PROBE_INITIAL_DELAY_SECONDS", 60),
+			IntervalMinutes:     getEnvInt("HOST_PROBE_INTERVAL_MINUTES", 5),
+			TimeoutSeconds:      getEnvInt("HOST_PROBE_TIMEOUT_SECONDS", 10),
+			MaxAttempts:         getEnvInt("HOST_PROBE_MAX_ATTEMPTS", 12),
+			AdminPath:           getEnv("HOST_PROBE_ADMIN_PATH", "/"),
+			StorefrontPath:      getEnv("HOST_PROBE_STOREFRONT_PATH", "/"),
+			APIPath:             getEnv("HOST_PROBE_API_PATH", "/health"),
+		},
 	}
 }
 
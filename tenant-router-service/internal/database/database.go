@@ -62,6 +62,7 @@ func AutoMigrate(db *gorm.DB) error {
 	modelsToMigrate := []interface{}{
 		&models.TenantHostRecord{},
 		&models.ProvisioningActivityLog{},
+		&models.DomainRoute{},
 	}
 
 	for _, model := range modelsToMigrate {
@@ -92,6 +93,11 @@ func createIndexes(db *gorm.DB) error {
 		 ON tenant_host_records (status, retry_count, last_retry_at)
 		 WHERE status = 'failed'`,
 
+		// Index for finding provisioned records whose hosts still need a reachability probe
+		`CREATE INDEX IF NOT EXISTS idx_tenant_host_reachability
+		 ON tenant_host_records (reachability_status, reachability_attempts)
+		 WHERE status = 'provisioned'`,
+
 		// Index for activity logs lookup
 		`CREATE INDEX IF NOT EXISTS idx_activity_created
 		 ON provisioning_activity_logs (tenant_host_id, created_at DESC)`,
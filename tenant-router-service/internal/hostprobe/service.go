@@ -0,0 +1,271 @@
+package hostprobe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"tenant-router-service/internal/config"
+	"tenant-router-service/internal/models"
+	"tenant-router-service/internal/repository"
+)
+
+// Errors returned by Probe
+var (
+	ErrHostNotFound   = errors.New("tenant host not found")
+	ErrNotProvisioned = errors.New("tenant host is not provisioned")
+)
+
+// EventTypeProvisioningVerified is the event type published once all hosts of a tenant are reachable
+const EventTypeProvisioningVerified = "provisioning.verified"
+
+// EventPublisher publishes provisioning events
+type EventPublisher interface {
+	PublishProvisioningVerified(ctx context.Context, event *models.ProvisioningVerifiedEvent) error
+}
+
+// Target is one host URL to probe
+type Target struct {
+	Role string
+	Host string
+	URL  string
+}
+
+// Report describes the outcome of probing all hosts of one tenant
+type Report struct {
+	Slug      string                    `json:"slug"`
+	Status    models.ReachabilityStatus `json:"status"`
+	Hosts     []models.HostProbeResult  `json:"hosts"`
+	CheckedAt time.Time                 `json:"checked_at"`
+}
+
+// Service probes provisioned tenant hosts over HTTPS and records whether they are reachable
+// This catches tenants that are marked provisioned but still answer 404 or fail TLS
+type Service struct {
+	repo      repository.TenantHostRepository
+	client    *http.Client
+	publisher EventPublisher
+	config    *config.Config
+}
+
+// NewService creates a host probe service
+// Redirects are not followed so a redirect to a login page or the www host counts as reachable
+// without probing a host outside the tenant
+func NewService(repo repository.TenantHostRepository, cfg *config.Config) *Service {
+	return &Service{
+		repo: repo,
+		client: &http.Client{
+			Timeout: time.Duration(cfg.HostProbe.TimeoutSeconds) * time.Second,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		config: cfg,
+	}
+}
+
+// SetPublisher sets the publisher for provisioning.verified events
+func (s *Service) SetPublisher(publisher EventPublisher) {
+	s.publisher = publisher
+}
+
+// ProbePending probes every provisioned tenant whose hosts have not been verified yet
+func (s *Service) ProbePending(ctx context.Context) ([]Report, error) {
+	records, err := s.repo.ListUnverified(ctx, s.config.HostProbe.MaxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unverified hosts: %w", err)
+	}
+
+	reports := make([]Report, 0, len(records))
+	var failed int
+	for i := range records {
+		report, err := s.probeRecord(ctx, &records[i])
+		if err != nil {
+			failed++
+			log.Printf("[HostProbe] Failed to probe hosts for %s: %v", records[i].Slug, err)
+			continue
+		}
+		reports = append(reports, *report)
+	}
+
+	if failed > 0 {
+		return reports, fmt.Errorf("failed to probe %d of %d tenants", failed, len(records))
+	}
+	return reports, nil
+}
+
+// Probe probes the hosts of one tenant now, regardless of earlier attempts
+func (s *Service) Probe(ctx context.Context, slug string) (*Report, error) {
+	record, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrHostNotFound
+	}
+	if record.Status != models.HostStatusProvisioned {
+		return nil, ErrNotProvisioned
+	}
+	return s.probeRecord(ctx, record)
+}
+
+// ScheduleProbe probes the hosts of a tenant once the initial delay has passed
+// Called right after a tenant is marked provisioned; certificate issuance and DNS
+// propagation usually need a moment, and the periodic job retries anything still unreachable
+func (s *Service) ScheduleProbe(slug string) {
+	delay := time.Duration(s.config.HostProbe.InitialDelaySeconds) * time.Second
+	time.AfterFunc(delay, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		if _, err := s.Probe(ctx, slug); err != nil {
+			log.Printf("[HostProbe] Post-provisioning probe for %s failed: %v", slug, err)
+		}
+	})
+}
+
+func (s *Service) probeRecord(ctx context.Context, record *models.TenantHostRecord) (*Report, error) {
+	targets := s.targets(record)
+	results := make([]models.HostProbeResult, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, s.probeTarget(ctx, target))
+	}
+
+	status := models.SummarizeProbes(results)
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode probe results: %w", err)
+	}
+	if err := s.repo.UpdateReachability(ctx, record.Slug, status, string(data)); err != nil {
+		return nil, fmt.Errorf("failed to store probe results: %w", err)
+	}
+
+	report := &Report{
+		Slug:      record.Slug,
+		Status:    status,
+		Hosts:     results,
+		CheckedAt: time.Now(),
+	}
+	s.logProbe(ctx, record.ID, report)
+
+	if status != models.ReachabilityVerified {
+		log.Printf("[HostProbe] Tenant %s is provisioned but not reachable: %s", record.Slug, describeFailures(results))
+		return report, nil
+	}
+
+	log.Printf("[HostProbe] All %d hosts of tenant %s are reachable", len(results), record.Slug)
+	// Only announce the transition, not every manual re-probe of an already verified tenant
+	if record.ReachabilityStatus != models.ReachabilityVerified {
+		s.publishVerified(ctx, record, report)
+	}
+	return report, nil
+}
+
+// targets returns the host URLs to probe for a tenant
+func (s *Service) targets(record *models.TenantHostRecord) []Target {
+	cfg := s.config.HostProbe
+	apiHost := record.APIHost
+	if apiHost == "" {
+		apiHost = fmt.Sprintf("%s-api.%s", record.Slug, s.config.Domain.BaseDomain)
+	}
+
+	targets := []Target{
+		newTarget(models.HostRoleAdmin, record.AdminHost, cfg.AdminPath),
+		newTarget(models.HostRoleStorefront, record.StorefrontHost, cfg.StorefrontPath),
+	}
+	if record.StorefrontWwwHost != "" {
+		targets = append(targets, newTarget(models.HostRoleStorefrontWww, record.StorefrontWwwHost, cfg.StorefrontPath))
+	}
+	return append(targets, newTarget(models.HostRoleAPI, apiHost, cfg.APIPath))
+}
+
+func newTarget(role, host, path string) Target {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return Target{Role: role, Host: host, URL: "https://" + host + path}
+}
+
+// probeTarget requests one host and classifies the response
+func (s *Service) probeTarget(ctx context.Context, target Target) models.HostProbeResult {
+	result := models.HostProbeResult{
+		Role:      target.Role,
+		Host:      target.Host,
+		URL:       target.URL,
+		CheckedAt: time.Now(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.URL, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("User-Agent", "tenant-router-service/host-probe")
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		// DNS, connection and TLS certificate errors all end up here
+		result.Error = err.Error()
+		return result
+	}
+	resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	result.Reachable = models.IsReachableStatusCode(resp.StatusCode)
+	if !result.Reachable {
+		result.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	return result
+}
+
+func (s *Service) publishVerified(ctx context.Context, record *models.TenantHostRecord, report *Report) {
+	if s.publisher == nil {
+		return
+	}
+	event := &models.ProvisioningVerifiedEvent{
+		EventType:  EventTypeProvisioningVerified,
+		TenantID:   record.TenantID,
+		Slug:       record.Slug,
+		Hosts:      report.Hosts,
+		VerifiedAt: report.CheckedAt,
+		Timestamp:  time.Now(),
+	}
+	if err := s.publisher.PublishProvisioningVerified(ctx, event); err != nil {
+		log.Printf("[HostProbe] Failed to publish %s for %s: %v", EventTypeProvisioningVerified, record.Slug, err)
+	}
+}
+
+func (s *Service) logProbe(ctx context.Context, tenantHostID uuid.UUID, report *Report) {
+	activity := &models.ProvisioningActivityLog{
+		TenantHostID: tenantHostID,
+		Action:       "probe_hosts",
+		Resource:     "hosts",
+		Success:      report.Status == models.ReachabilityVerified,
+	}
+	if !activity.Success {
+		activity.ErrorMessage = describeFailures(report.Hosts)
+	}
+	if err := s.repo.LogActivity(ctx, activity); err != nil {
+		log.Printf("[HostProbe] Failed to log probe activity for %s: %v", report.Slug, err)
+	}
+}
+
+// describeFailures summarizes the unreachable hosts for logs
+func describeFailures(results []models.HostProbeResult) string {
+	var failures []string
+	for _, result := range results {
+		if !result.Reachable {
+			failures = append(failures, fmt.Sprintf("%s (%s): %s", result.Host, result.Role, result.Error))
+		}
+	}
+	return strings.Join(failures, "; ")
+}
@@ -0,0 +1,108 @@
+package hostprobe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tenant-router-service/internal/config"
+	"tenant-router-service/internal/models"
+)
+
+func newTestService(t *testing.T, handler http.HandlerFunc) (*Service, string) {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	svc := NewService(nil, &config.Config{HostProbe: config.HostProbeConfig{TimeoutSeconds: 5}})
+	// Trust the test server certificate but keep the no-redirect policy
+	svc.client.Transport = server.Client().Transport
+	return svc, strings.TrimPrefix(server.URL, "https://")
+}
+
+func TestProbeTarget_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		path      string
+		status    int
+		reachable bool
+	}{
+		{"/ok", http.StatusOK, true},
+		{"/login", http.StatusFound, true},
+		{"/private", http.StatusUnauthorized, true},
+		{"/missing", http.StatusNotFound, false},
+		{"/broken", http.StatusServiceUnavailable, false},
+	}
+
+	svc, host := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		for _, tc := range testCases {
+			if r.URL.Path == tc.path {
+				if tc.status == http.StatusFound {
+					http.Redirect(w, r, "/missing", tc.status)
+					return
+				}
+				w.WriteHeader(tc.status)
+				return
+			}
+		}
+	})
+
+	for _, tc := range testCases {
+		result := svc.probeTarget(context.Background(), newTarget(models.HostRoleAdmin, host, tc.path))
+		if result.StatusCode != tc.status {
+			t.Errorf("%s: expected status %d, got %d (%s)", tc.path, tc.status, result.StatusCode, result.Error)
+		}
+		if result.Reachable != tc.reachable {
+			t.Errorf("%s: expected reachable=%v, got %v", tc.path, tc.reachable, result.Reachable)
+		}
+	}
+}
+
+func TestProbeTarget_UntrustedCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	svc := NewService(nil, &config.Config{HostProbe: config.HostProbeConfig{TimeoutSeconds: 5}})
+	result := svc.probeTarget(context.Background(), newTarget(models.HostRoleStorefront, strings.TrimPrefix(server.URL, "https://"), "/"))
+	if result.Reachable {
+		t.Error("expected host with an untrusted certificate to be unreachable")
+	}
+	if result.Error == "" {
+		t.Error("expected the TLS error to be recorded")
+	}
+}
+
+func TestTargets(t *testing.T) {
+	svc := NewService(nil, &config.Config{
+		Domain: config.DomainConfig{BaseDomain: "tesserix.app"},
+		HostProbe: config.HostProbeConfig{
+			AdminPath:      "/",
+			StorefrontPath: "/",
+			APIPath:        "health",
+		},
+	})
+
+	record := &models.TenantHostRecord{
+		Slug:              "acme",
+		AdminHost:         "admin.acme.com",
+		StorefrontHost:    "acme.com",
+		StorefrontWwwHost: "www.acme.com",
+	}
+	targets := svc.targets(record)
+
+	expected := []string{
+		"https://admin.acme.com/",
+		"https://acme.com/",
+		"https://www.acme.com/",
+		"https://acme-api.tesserix.app/health",
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("expected %d targets, got %d", len(expected), len(targets))
+	}
+	for i, url := range expected {
+		if targets[i].URL != url {
+			t.Errorf("expected target %d to be %s, got %s", i, url, targets[i].URL)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package k8s
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/protobuf/proto"
+	networkingv1beta1 "istio.io/api/networking/v1beta1"
+	istionetworkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+
+	"tenant-router-service/internal/models"
+)
+
+// Names of the HTTP routes redirecting requests to a tenant's canonical URLs
+const (
+	CanonicalHostRouteName  = "canonical-host-redirect"
+	CanonicalHTTPSRouteName = "canonical-https-redirect"
+)
+
+// AnnotationCanonicalPolicy records the canonicalization policy rendered on a tenant VirtualService
+const AnnotationCanonicalPolicy = "tenant-router-service/canonical-policy"
+
+// canonicalRedirectCode is a permanent redirect, so search engines index the canonical URL only
+const canonicalRedirectCode = 301
+
+// ApplyTenantCanonicalPolicy renders a tenant's canonicalization policy as redirect routes on
+// its VirtualServices; a zero policy removes them. VirtualServices already carrying the policy
+// are left untouched. Returns the names of the VirtualServices that were updated.
+func (c *Client) ApplyTenantCanonicalPolicy(ctx context.Context, slug, storefrontHost, storefrontWwwHost string, isCustomDomain bool, policy models.CanonicalPolicy) ([]string, error) {
+	updated, err := c.updateTenantVirtualServices(ctx, slug, storefrontWwwHost != "",
+		c.canonicalPolicyMutation(slug, storefrontHost, storefrontWwwHost, isCustomDomain, policy))
+	if err != nil {
+		return updated, err
+	}
+	if len(updated) > 0 {
+		log.Printf("[K8s] Applied canonical policy %q to %v", policy.String(), updated)
+	}
+	return updated, nil
+}
+
+// RenderTenantCanonicalPolicy adds a tenant's canonical redirects to its rendered VirtualServices
+func (c *Client) RenderTenantCanonicalPolicy(manifests []Manifest, slug, storefrontHost, storefrontWwwHost string, isCustomDomain bool, policy models.CanonicalPolicy) {
+	if policy.IsZero() {
+		return
+	}
+	mutate := c.canonicalPolicyMutation(slug, storefrontHost, storefrontWwwHost, isCustomDomain, policy)
+	for _, m := range manifests {
+		if vs, ok := m.Object.(*istionetworkingv1beta1.VirtualService); ok {
+			mutate(vs)
+		}
+	}
+}
+
+// canonicalPolicyMutation returns the change rendering a canonicalization policy on one of the
+// tenant's VirtualServices:
+//   - the storefront host that is not preferred redirects every request to the preferred one
+//   - plain HTTP is redirected to HTTPS on custom domains only; platform hosts are served
+//     through the Cloudflare tunnel, which reaches the gateway over plain HTTP after
+//     Cloudflare already upgraded the client, so matching on the scheme there would loop
+func (c *Client) canonicalPolicyMutation(slug, storefrontHost, storefrontWwwHost string, isCustomDomain bool, policy models.CanonicalPolicy) func(vs *istionetworkingv1beta1.VirtualService) bool {
+	storefrontVSName := c.tenantVirtualServiceName(slug, c.config.Kubernetes.StorefrontVSName, "")
+	wwwVSName := c.tenantVirtualServiceName(slug, c.config.Kubernetes.StorefrontVSName, "www")
+
+	return func(vs *istionetworkingv1beta1.VirtualService) bool {
+		redirectHost := ""
+		switch {
+		case policy.PreferredHost == models.PreferredHostWww && vs.Name == storefrontVSName:
+			redirectHost = storefrontWwwHost
+		case policy.PreferredHost == models.PreferredHostApex && vs.Name == wwwVSName:
+			redirectHost = storefrontHost
+		}
+		return applyCanonicalRoutes(vs, policy.String(), canonicalRoutes(redirectHost, policy.ForceHTTPS && isCustomDomain))
+	}
+}
+
+// canonicalRoutes builds the redirect routes of a VirtualService: to redirectHost when set,
+// then from HTTP to HTTPS when forceHTTPS is set
+func canonicalRoutes(redirectHost string, forceHTTPS bool) []*networkingv1beta1.HTTPRoute {
+	var routes []*networkingv1beta1.HTTPRoute
+	if redirectHost != "" {
+		routes = append(routes, &networkingv1beta1.HTTPRoute{
+			Name: CanonicalHostRouteName,
+			Redirect: &networkingv1beta1.HTTPRedirect{
+				Authority:    redirectHost,
+				Scheme:       "https",
+				RedirectPort: &networkingv1beta1.HTTPRedirect_DerivePort{DerivePort: networkingv1beta1.HTTPRedirect_FROM_PROTOCOL_DEFAULT},
+				RedirectCode: canonicalRedirectCode,
+			},
+		})
+	}
+	if forceHTTPS {
+		routes = append(routes, &networkingv1beta1.HTTPRoute{
+			Name: CanonicalHTTPSRouteName,
+			Match: []*networkingv1beta1.HTTPMatchRequest{{
+				Scheme: &networkingv1beta1.StringMatch{
+					MatchType: &networkingv1beta1.StringMatch_Exact{Exact: "http"},
+				},
+			}},
+			Redirect: &networkingv1beta1.HTTPRedirect{
+				Scheme:       "https",
+				RedirectPort: &networkingv1beta1.HTTPRedirect_DerivePort{DerivePort: networkingv1beta1.HTTPRedirect_FROM_PROTOCOL_DEFAULT},
+				RedirectCode: canonicalRedirectCode,
+			},
+		})
+	}
+	return routes
+}
+
+// applyCanonicalRoutes replaces the canonical redirect routes of a VirtualService and records
+// the policy in an annotation. The routes go first, after the ACME challenge route so
+// certificates of custom domains can still be issued for every host. Reports whether the
+// VirtualService changed.
+func applyCanonicalRoutes(vs *istionetworkingv1beta1.VirtualService, value string, canonical []*networkingv1beta1.HTTPRoute) bool {
+	if vs.Annotations[AnnotationCanonicalPolicy] == value && routesEqual(findCanonicalRoutes(vs.Spec.Http), canonical) {
+		return false
+	}
+
+	routes := make([]*networkingv1beta1.HTTPRoute, 0, len(vs.Spec.Http)+len(canonical))
+	for _, route := range vs.Spec.Http {
+		if !isCanonicalRoute(route) {
+			routes = append(routes, route)
+		}
+	}
+	vs.Spec.Http = insertAfterACMERoute(routes, canonical...)
+
+	if value == "" {
+		delete(vs.Annotations, AnnotationCanonicalPolicy)
+		return true
+	}
+	if vs.Annotations == nil {
+		vs.Annotations = make(map[string]string)
+	}
+	vs.Annotations[AnnotationCanonicalPolicy] = value
+	return true
+}
+
+// findCanonicalRoutes returns the canonical redirect routes of a VirtualService, in order
+func findCanonicalRoutes(routes []*networkingv1beta1.HTTPRoute) []*networkingv1beta1.HTTPRoute {
+	var canonical []*networkingv1beta1.HTTPRoute
+	for _, route := range routes {
+		if isCanonicalRoute(route) {
+			canonical = append(canonical, route)
+		}
+	}
+	return canonical
+}
+
+func isCanonicalRoute(route *networkingv1beta1.HTTPRoute) bool {
+	return route.Name == CanonicalHostRouteName || route.Name == CanonicalHTTPSRouteName
+}
+
+// routesEqual reports whether two lists of routes are identical
+func routesEqual(a, b []*networkingv1beta1.HTTPRoute) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
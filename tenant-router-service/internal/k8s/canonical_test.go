@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"testing"
+
+	networkingv1beta1 "istio.io/api/networking/v1beta1"
+	istionetworkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+
+	"tenant-router-service/internal/models"
+)
+
+func tenantVS(name string) *istionetworkingv1beta1.VirtualService {
+	vs := &istionetworkingv1beta1.VirtualService{}
+	vs.Name = name
+	vs.Spec.Http = []*networkingv1beta1.HTTPRoute{{Name: "acme-challenge"}, {Name: "default"}}
+	return vs
+}
+
+func TestCanonicalPolicyMutation_PreferWww(t *testing.T) {
+	c := testClient()
+	policy := models.CanonicalPolicy{ForceHTTPS: true, PreferredHost: models.PreferredHostWww}
+	mutate := c.canonicalPolicyMutation("acme", "acme.com", "www.acme.com", true, policy)
+
+	storefront := tenantVS("acme-storefront-vs")
+	if !mutate(storefront) {
+		t.Fatal("expected the storefront VirtualService to change")
+	}
+	names := routeNames(storefront)
+	if len(names) != 4 || names[0] != "acme-challenge" || names[1] != CanonicalHostRouteName || names[2] != CanonicalHTTPSRouteName {
+		t.Fatalf("expected canonical routes after acme-challenge, got %v", names)
+	}
+	redirect := storefront.Spec.Http[1].Redirect
+	if redirect.Authority != "www.acme.com" || redirect.Scheme != "https" || redirect.RedirectCode != 301 {
+		t.Errorf("unexpected host redirect %+v", redirect)
+	}
+	if storefront.Annotations[AnnotationCanonicalPolicy] != policy.String() {
+		t.Errorf("expected canonical policy annotation, got %q", storefront.Annotations[AnnotationCanonicalPolicy])
+	}
+	if mutate(storefront) {
+		t.Error("expected re-applying the same policy to change nothing")
+	}
+
+	www := tenantVS("acme-storefront-www-vs")
+	mutate(www)
+	if names := routeNames(www); len(names) != 3 || names[1] != CanonicalHTTPSRouteName {
+		t.Errorf("expected only the HTTPS redirect on the preferred host, got %v", names)
+	}
+}
+
+func TestCanonicalPolicyMutation_PreferApex(t *testing.T) {
+	c := testClient()
+	mutate := c.canonicalPolicyMutation("acme", "acme.com", "www.acme.com", true, models.CanonicalPolicy{PreferredHost: models.PreferredHostApex})
+
+	www := tenantVS("acme-storefront-www-vs")
+	mutate(www)
+	if names := routeNames(www); len(names) != 3 || names[1] != CanonicalHostRouteName {
+		t.Fatalf("expected host redirect on the www VirtualService, got %v", names)
+	}
+	if got := www.Spec.Http[1].Redirect.Authority; got != "acme.com" {
+		t.Errorf("expected redirect to the apex, got %q", got)
+	}
+
+	storefront := tenantVS("acme-storefront-vs")
+	if !mutate(storefront) {
+		t.Error("expected the annotation to be set on the storefront VirtualService")
+	}
+	if names := routeNames(storefront); len(names) != 2 {
+		t.Errorf("expected no redirect on the preferred host, got %v", names)
+	}
+}
+
+func TestCanonicalPolicyMutation_PlatformDomainSkipsHTTPSRedirect(t *testing.T) {
+	c := testClient()
+	mutate := c.canonicalPolicyMutation("acme", "acme.tesserix.app", "", false, models.CanonicalPolicy{ForceHTTPS: true})
+
+	vs := tenantVS("acme-admin-vs")
+	mutate(vs)
+	if findCanonicalRoutes(vs.Spec.Http) != nil {
+		t.Errorf("expected no HTTPS redirect behind the Cloudflare tunnel, got %v", routeNames(vs))
+	}
+}
+
+func TestApplyCanonicalRoutes_Remove(t *testing.T) {
+	vs := tenantVS("acme-storefront-vs")
+	applyCanonicalRoutes(vs, "force_https=true,preferred_host=", canonicalRoutes("", true))
+	if !applyCanonicalRoutes(vs, "", nil) {
+		t.Fatal("expected removing the policy to change the VirtualService")
+	}
+	if names := routeNames(vs); len(names) != 2 || names[1] != "default" {
+		t.Errorf("expected canonical routes removed, got %v", names)
+	}
+	if _, ok := vs.Annotations[AnnotationCanonicalPolicy]; ok {
+		t.Error("expected canonical policy annotation removed")
+	}
+}
@@ -13,6 +13,8 @@ import (
 	networkingv1beta1 "istio.io/api/networking/v1beta1"
 	securityv1beta1 "istio.io/api/security/v1beta1"
 	typev1beta1 "istio.io/api/type/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	istioversionedclient "istio.io/client-go/pkg/clientset/versioned"
 	istionetworkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	istiosecurityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
@@ -22,6 +24,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	"tenant-router-service/internal/config"
+	"tenant-router-service/internal/models"
 )
 
 // Client wraps Kubernetes clients for Istio and cert-manager
@@ -129,7 +132,25 @@ func (c *Client) createCertificateInNamespace(ctx context.Context, slug string,
 	}
 
 	// Create Certificate resource
-	cert := &certmanagerv1.Certificate{
+	cert := newCertificate(slug, domains, namespace, clusterIssuer)
+
+	_, err = c.certmanager.CertmanagerV1().Certificates(namespace).Create(ctx, cert, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	log.Printf("[K8s] Created Certificate %s for domains: %v in namespace %s", certName, domains, namespace)
+	return nil
+}
+
+// newCertificate builds the Certificate resource for a tenant's domains
+func newCertificate(slug string, domains []string, namespace, clusterIssuer string) *certmanagerv1.Certificate {
+	certName := fmt.Sprintf("%s-tenant-tls", slug)
+	return &certmanagerv1.Certificate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: certmanagerv1.SchemeGroupVersion.String(),
+			Kind:       "Certificate",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      certName,
 			Namespace: namespace,
@@ -147,14 +168,6 @@ func (c *Client) createCertificateInNamespace(ctx context.Context, slug string,
 			},
 		},
 	}
-
-	_, err = c.certmanager.CertmanagerV1().Certificates(namespace).Create(ctx, cert, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create certificate: %w", err)
-	}
-
-	log.Printf("[K8s] Created Certificate %s for domains: %v in namespace %s", certName, domains, namespace)
-	return nil
 }
 
 // DeleteCertificate deletes a Certificate resource for a tenant
@@ -206,6 +219,23 @@ func (c *Client) CreateDedicatedGateway(ctx context.Context, slug string, domain
 		return gatewayName, nil
 	}
 
+	// Create the Gateway resource using client-go types
+	gatewayResource := c.newDedicatedGateway(slug, domains, certSecretName)
+
+	_, err = c.istio.NetworkingV1beta1().Gateways(namespace).Create(ctx, gatewayResource, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create gateway %s: %w", gatewayName, err)
+	}
+
+	log.Printf("[K8s] Created dedicated Gateway %s for domains: %v", gatewayName, domains)
+	return gatewayName, nil
+}
+
+// newDedicatedGateway builds the dedicated Gateway of a custom domain tenant
+func (c *Client) newDedicatedGateway(slug string, domains []string, certSecretName string) *istionetworkingv1beta1.Gateway {
+	namespace := c.config.Kubernetes.CustomDomainGatewayNS
+	gatewayName := fmt.Sprintf("%s-gateway", slug)
+
 	// Build HTTPS server with TLS settings
 	httpsServer := &networkingv1beta1.Server{
 		Port: &networkingv1beta1.Port{
@@ -231,8 +261,11 @@ func (c *Client) CreateDedicatedGateway(ctx context.Context, slug string, domain
 		Hosts: domains,
 	}
 
-	// Create the Gateway resource using client-go types
-	gatewayResource := &istionetworkingv1beta1.Gateway{
+	return &istionetworkingv1beta1.Gateway{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.istio.io/v1beta1",
+			Kind:       "Gateway",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      gatewayName,
 			Namespace: namespace,
@@ -249,14 +282,6 @@ func (c *Client) CreateDedicatedGateway(ctx context.Context, slug string, domain
 			Servers: []*networkingv1beta1.Server{httpsServer, httpServer},
 		},
 	}
-
-	_, err = c.istio.NetworkingV1beta1().Gateways(namespace).Create(ctx, gatewayResource, metav1.CreateOptions{})
-	if err != nil {
-		return "", fmt.Errorf("failed to create gateway %s: %w", gatewayName, err)
-	}
-
-	log.Printf("[K8s] Created dedicated Gateway %s for domains: %v", gatewayName, domains)
-	return gatewayName, nil
 }
 
 // DeleteDedicatedGateway deletes a dedicated Gateway for a custom domain
@@ -278,14 +303,10 @@ func (c *Client) DeleteDedicatedGateway(ctx context.Context, slug string) error
 func (c *Client) CreateDedicatedAuthorizationPolicy(ctx context.Context, slug string, hosts []string) error {
 	namespace := c.config.Kubernetes.SharedAuthPolicyNamespace
 	policyName := fmt.Sprintf("%s-custom-domain-policy", slug)
-	selector := c.config.Kubernetes.SharedAuthPolicySelector
 
 	if namespace == "" {
 		namespace = "istio-ingress"
 	}
-	if selector == "" {
-		selector = "custom-ingressgateway"
-	}
 
 	// Check if policy already exists
 	_, err := c.istio.SecurityV1beta1().AuthorizationPolicies(namespace).Get(ctx, policyName, metav1.GetOptions{})
@@ -295,7 +316,35 @@ func (c *Client) CreateDedicatedAuthorizationPolicy(ctx context.Context, slug st
 	}
 
 	// Create the AuthorizationPolicy
+	policy := c.newDedicatedAuthorizationPolicy(slug, hosts)
+
+	_, err = c.istio.SecurityV1beta1().AuthorizationPolicies(namespace).Create(ctx, policy, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create AuthorizationPolicy %s: %w", policyName, err)
+	}
+
+	log.Printf("[K8s] Created dedicated AuthorizationPolicy %s for hosts: %v", policyName, hosts)
+	return nil
+}
+
+// newDedicatedAuthorizationPolicy builds the AuthorizationPolicy allowing a custom domain's hosts
+func (c *Client) newDedicatedAuthorizationPolicy(slug string, hosts []string) *istiosecurityv1beta1.AuthorizationPolicy {
+	namespace := c.config.Kubernetes.SharedAuthPolicyNamespace
+	policyName := fmt.Sprintf("%s-custom-domain-policy", slug)
+	selector := c.config.Kubernetes.SharedAuthPolicySelector
+
+	if namespace == "" {
+		namespace = "istio-ingress"
+	}
+	if selector == "" {
+		selector = "custom-ingressgateway"
+	}
+
 	policy := &istiosecurityv1beta1.AuthorizationPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "security.istio.io/v1beta1",
+			Kind:       "AuthorizationPolicy",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      policyName,
 			Namespace: namespace,
@@ -323,14 +372,7 @@ func (c *Client) CreateDedicatedAuthorizationPolicy(ctx context.Context, slug st
 			},
 		},
 	}
-
-	_, err = c.istio.SecurityV1beta1().AuthorizationPolicies(namespace).Create(ctx, policy, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create AuthorizationPolicy %s: %w", policyName, err)
-	}
-
-	log.Printf("[K8s] Created dedicated AuthorizationPolicy %s for hosts: %v", policyName, hosts)
-	return nil
+	return policy
 }
 
 // DeleteDedicatedAuthorizationPolicy deletes the dedicated AuthorizationPolicy for a custom domain
@@ -372,53 +414,7 @@ func (c *Client) PatchGatewayServer(ctx context.Context, slug, adminHost, storef
 	}
 
 	if operation == "add" {
-		// Check if servers already exist
-		adminExists := false
-		storefrontExists := false
-		for _, server := range gateway.Spec.Servers {
-			for _, host := range server.Hosts {
-				if host == adminHost {
-					adminExists = true
-				}
-				if host == storefrontHost {
-					storefrontExists = true
-				}
-			}
-		}
-
-		// Add admin server if not exists
-		if !adminExists {
-			adminServer := &networkingv1beta1.Server{
-				Port: &networkingv1beta1.Port{
-					Number:   443,
-					Name:     fmt.Sprintf("https-%s-admin", slug),
-					Protocol: "HTTPS",
-				},
-				Hosts: []string{adminHost},
-				Tls: &networkingv1beta1.ServerTLSSettings{
-					Mode:           networkingv1beta1.ServerTLSSettings_SIMPLE,
-					CredentialName: certSecretName,
-				},
-			}
-			gateway.Spec.Servers = append(gateway.Spec.Servers, adminServer)
-		}
-
-		// Add storefront server if not exists
-		if !storefrontExists {
-			storefrontServer := &networkingv1beta1.Server{
-				Port: &networkingv1beta1.Port{
-					Number:   443,
-					Name:     fmt.Sprintf("https-%s-store", slug),
-					Protocol: "HTTPS",
-				},
-				Hosts: []string{storefrontHost},
-				Tls: &networkingv1beta1.ServerTLSSettings{
-					Mode:           networkingv1beta1.ServerTLSSettings_SIMPLE,
-					CredentialName: certSecretName,
-				},
-			}
-			gateway.Spec.Servers = append(gateway.Spec.Servers, storefrontServer)
-		}
+		addGatewayServers(gateway, slug, adminHost, storefrontHost, certSecretName)
 	} else if operation == "remove" {
 		// Remove servers for this tenant
 		var filteredServers []*networkingv1beta1.Server
@@ -447,6 +443,58 @@ func (c *Client) PatchGatewayServer(ctx context.Context, slug, adminHost, storef
 	return namespace, nil
 }
 
+// addGatewayServers adds HTTPS servers for a tenant's admin and storefront hosts to the
+// shared Gateway, skipping hosts that already have a server
+func addGatewayServers(gateway *istionetworkingv1beta1.Gateway, slug, adminHost, storefrontHost, certSecretName string) {
+	// Check if servers already exist
+	adminExists := false
+	storefrontExists := false
+	for _, server := range gateway.Spec.Servers {
+		for _, host := range server.Hosts {
+			if host == adminHost {
+				adminExists = true
+			}
+			if host == storefrontHost {
+				storefrontExists = true
+			}
+		}
+	}
+
+	// Add admin server if not exists
+	if !adminExists {
+		adminServer := &networkingv1beta1.Server{
+			Port: &networkingv1beta1.Port{
+				Number:   443,
+				Name:     fmt.Sprintf("https-%s-admin", slug),
+				Protocol: "HTTPS",
+			},
+			Hosts: []string{adminHost},
+			Tls: &networkingv1beta1.ServerTLSSettings{
+				Mode:           networkingv1beta1.ServerTLSSettings_SIMPLE,
+				CredentialName: certSecretName,
+			},
+		}
+		gateway.Spec.Servers = append(gateway.Spec.Servers, adminServer)
+	}
+
+	// Add storefront server if not exists
+	if !storefrontExists {
+		storefrontServer := &networkingv1beta1.Server{
+			Port: &networkingv1beta1.Port{
+				Number:   443,
+				Name:     fmt.Sprintf("https-%s-store", slug),
+				Protocol: "HTTPS",
+			},
+			Hosts: []string{storefrontHost},
+			Tls: &networkingv1beta1.ServerTLSSettings{
+				Mode:           networkingv1beta1.ServerTLSSettings_SIMPLE,
+				CredentialName: certSecretName,
+			},
+		}
+		gateway.Spec.Servers = append(gateway.Spec.Servers, storefrontServer)
+	}
+}
+
 // VSHostsPatch represents a JSON patch for VirtualService hosts
 type VSHostsPatch struct {
 	Op    string `json:"op"`
@@ -597,6 +645,32 @@ func (c *Client) createVirtualServiceWithGateway(ctx context.Context, slug, tena
 		return fmt.Errorf("failed to get template VirtualService %s: %w", templateVSName, err)
 	}
 
+	newVSName := c.tenantVirtualServiceName(slug, templateVSName, nameSuffix)
+
+	// Check if VS already exists (idempotent operation)
+	_, err = c.istio.NetworkingV1beta1().VirtualServices(vsLocation.Namespace).Get(ctx, newVSName, metav1.GetOptions{})
+	if err == nil {
+		log.Printf("[K8s] VirtualService %s already exists, skipping creation", newVSName)
+		return nil
+	}
+
+	// Create a new VirtualService by deep copying the template
+	newVS := c.newTenantVirtualService(templateVS, newVSName, vsLocation.Namespace, slug, tenantID, templateVSName, tenantHost, adminHost, storefrontHost, cloudflareProxied, isCustomDomain, dedicatedGatewayName)
+
+	// Create the new VirtualService
+	_, err = c.istio.NetworkingV1beta1().VirtualServices(vsLocation.Namespace).Create(ctx, newVS, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create VirtualService %s: %w", newVSName, err)
+	}
+
+	log.Printf("[K8s] Created VirtualService %s for tenant %s with host %s (copied from template %s)",
+		newVSName, slug, tenantHost, templateVSName)
+	return nil
+}
+
+// tenantVirtualServiceName returns the name of the tenant VirtualService copied from a template,
+// e.g. "acme-storefront-vs" or "acme-storefront-www-vs" with the "www" suffix
+func (c *Client) tenantVirtualServiceName(slug, templateVSName, nameSuffix string) string {
 	// Determine the new VS name based on template type
 	var newVSName string
 	switch templateVSName {
@@ -616,16 +690,19 @@ func (c *Client) createVirtualServiceWithGateway(ctx context.Context, slug, tena
 		newVSName = fmt.Sprintf("%s-%s-vs", newVSName[:len(newVSName)-3], nameSuffix)
 	}
 
-	// Check if VS already exists (idempotent operation)
-	_, err = c.istio.NetworkingV1beta1().VirtualServices(vsLocation.Namespace).Get(ctx, newVSName, metav1.GetOptions{})
-	if err == nil {
-		log.Printf("[K8s] VirtualService %s already exists, skipping creation", newVSName)
-		return nil
-	}
+	return newVSName
+}
 
-	// Create a new VirtualService by deep copying the template
+// newTenantVirtualService builds a tenant VirtualService from the template: the tenant host,
+// the gateway for default or custom domains, tenant-specific CORS origins and tenant headers
+func (c *Client) newTenantVirtualService(templateVS *istionetworkingv1beta1.VirtualService, newVSName, namespace, slug, tenantID, templateVSName, tenantHost, adminHost, storefrontHost string, cloudflareProxied, isCustomDomain bool, dedicatedGatewayName string) *istionetworkingv1beta1.VirtualService {
+	// Deep copy the template
 	// This preserves all routes, timeouts, retries, destinations, and other configurations
 	newVS := templateVS.DeepCopy()
+	newVS.TypeMeta = metav1.TypeMeta{
+		APIVersion: "networking.istio.io/v1beta1",
+		Kind:       "VirtualService",
+	}
 
 	// Determine cloudflare-proxied annotation value
 	// - "true": DNS will be proxied through Cloudflare (DDoS protection, WAF)
@@ -637,7 +714,7 @@ func (c *Client) createVirtualServiceWithGateway(ctx context.Context, slug, tena
 
 	newVS.ObjectMeta = metav1.ObjectMeta{
 		Name:      newVSName,
-		Namespace: vsLocation.Namespace,
+		Namespace: namespace,
 		Labels: map[string]string{
 			"app.kubernetes.io/managed-by": "tenant-router-service",
 			"app.kubernetes.io/component":  "virtualservice",
@@ -766,15 +843,7 @@ func (c *Client) createVirtualServiceWithGateway(ctx context.Context, slug, tena
 			slug, tenantID, slug, isCustomDomain)
 	}
 
-	// Create the new VirtualService
-	_, err = c.istio.NetworkingV1beta1().VirtualServices(vsLocation.Namespace).Create(ctx, newVS, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create VirtualService %s: %w", newVSName, err)
-	}
-
-	log.Printf("[K8s] Created VirtualService %s for tenant %s with host %s (copied from template %s)",
-		newVSName, slug, tenantHost, templateVSName)
-	return nil
+	return newVS
 }
 
 // UpdateTenantVirtualService updates an existing tenant VirtualService with routes from the template
@@ -852,6 +921,21 @@ func (c *Client) UpdateTenantVirtualService(ctx context.Context, slug, templateV
 		updatedRoutes[i] = routeCopy
 	}
 
+	// Keep the tenant's traffic split between subsets
+	if weights, err := models.ParseSubsetWeights(tenantVS.Annotations[AnnotationTrafficWeights]); err == nil && len(weights) > 0 {
+		splitRouteDestinations(updatedRoutes, weights, nil)
+	}
+
+	// Keep the tenant's canonical redirects
+	if canonical := findCanonicalRoutes(tenantVS.Spec.Http); len(canonical) > 0 {
+		updatedRoutes = insertAfterACMERoute(updatedRoutes, canonical...)
+	}
+
+	// Keep the tenant in maintenance if it was
+	if maintenance := findMaintenanceRoute(tenantVS.Spec.Http); maintenance != nil {
+		updatedRoutes = withMaintenanceRoute(updatedRoutes, maintenance)
+	}
+
 	// Update the tenant VS with new routes (preserving hosts)
 	tenantVS.Spec.Http = updatedRoutes
 
@@ -1102,6 +1186,60 @@ func (c *Client) GetCertificateStatus(ctx context.Context, slug string) (string,
 	return "unknown", nil
 }
 
+// GetTLSSecret returns a TLS secret, or nil if it does not exist
+func (c *Client) GetTLSSecret(ctx context.Context, namespace, name string) (*models.TLSSecret, error) {
+	secret, err := c.core.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+
+	return &models.TLSSecret{
+		Namespace:   secret.Namespace,
+		Name:        secret.Name,
+		Data:        secret.Data,
+		Labels:      secret.Labels,
+		Annotations: secret.Annotations,
+	}, nil
+}
+
+// ApplyTLSSecret creates a TLS secret, replacing the data of an existing one
+func (c *Client) ApplyTLSSecret(ctx context.Context, tlsSecret *models.TLSSecret) error {
+	secrets := c.core.CoreV1().Secrets(tlsSecret.Namespace)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        tlsSecret.Name,
+			Namespace:   tlsSecret.Namespace,
+			Labels:      tlsSecret.Labels,
+			Annotations: tlsSecret.Annotations,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: tlsSecret.Data,
+	}
+
+	existing, err := secrets.Get(ctx, tlsSecret.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create secret %s/%s: %w", tlsSecret.Namespace, tlsSecret.Name, err)
+		}
+		log.Printf("[K8s] Created TLS secret %s/%s", tlsSecret.Namespace, tlsSecret.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get secret %s/%s: %w", tlsSecret.Namespace, tlsSecret.Name, err)
+	}
+
+	secret.ResourceVersion = existing.ResourceVersion
+	if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update secret %s/%s: %w", tlsSecret.Namespace, tlsSecret.Name, err)
+	}
+	log.Printf("[K8s] Replaced TLS secret %s/%s", tlsSecret.Namespace, tlsSecret.Name)
+	return nil
+}
+
 // IsConnected checks if the Kubernetes connection is healthy
 func (c *Client) IsConnected(ctx context.Context) bool {
 	_, err := c.istio.NetworkingV1beta1().VirtualServices(c.config.Kubernetes.Namespace).List(ctx, metav1.ListOptions{Limit: 1})
@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Actions a rendered manifest would be applied with
+const (
+	ManifestActionCreate = "create"
+	ManifestActionUpdate = "update"
+)
+
+// Manifest is a Kubernetes resource rendered exactly as provisioning would apply it,
+// without touching the cluster
+type Manifest struct {
+	Action string      // create or update
+	Kind   string      // e.g. "VirtualService"
+	Name   string      // namespace/name
+	Object interface{} // typed Kubernetes object
+}
+
+// RenderCertificate renders the Certificate created for a tenant's domains; custom domain
+// certificates live in the custom domain gateway namespace and use the HTTP-01 issuer
+func (c *Client) RenderCertificate(slug string, domains []string, isCustomDomain bool) Manifest {
+	namespace := c.config.Kubernetes.Namespace
+	issuer := c.config.Kubernetes.ClusterIssuer
+	if isCustomDomain {
+		namespace = c.config.Kubernetes.CustomDomainGatewayNS
+		issuer = c.config.Kubernetes.CustomDomainClusterIssuer
+	}
+	cert := newCertificate(slug, domains, namespace, issuer)
+	return newManifest(ManifestActionCreate, cert.Kind, cert.ObjectMeta, cert)
+}
+
+// RenderDedicatedGateway renders the dedicated Gateway created for a custom domain tenant
+func (c *Client) RenderDedicatedGateway(slug string, domains []string, certSecretName string) Manifest {
+	gateway := c.newDedicatedGateway(slug, domains, certSecretName)
+	return newManifest(ManifestActionCreate, gateway.Kind, gateway.ObjectMeta, gateway)
+}
+
+// RenderDedicatedAuthorizationPolicy renders the AuthorizationPolicy created for a custom domain tenant
+func (c *Client) RenderDedicatedAuthorizationPolicy(slug string, hosts []string) Manifest {
+	policy := c.newDedicatedAuthorizationPolicy(slug, hosts)
+	return newManifest(ManifestActionCreate, policy.Kind, policy.ObjectMeta, policy)
+}
+
+// RenderGatewayServerPatch renders the shared Gateway as it would look after adding the
+// tenant's servers. The current Gateway is read from the cluster but not modified.
+func (c *Client) RenderGatewayServerPatch(ctx context.Context, slug, adminHost, storefrontHost string) (Manifest, error) {
+	gatewayName := c.config.Kubernetes.GatewayName
+	certSecretName := fmt.Sprintf("%s/%s-tenant-tls", c.config.Kubernetes.Namespace, slug)
+
+	namespace, err := c.FindGatewayByName(ctx, gatewayName)
+	if err != nil {
+		return Manifest{}, err
+	}
+	current, err := c.istio.NetworkingV1beta1().Gateways(namespace).Get(ctx, gatewayName, metav1.GetOptions{})
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to get gateway: %w", err)
+	}
+
+	gateway := current.DeepCopy()
+	gateway.APIVersion = "networking.istio.io/v1beta1"
+	gateway.Kind = "Gateway"
+	addGatewayServers(gateway, slug, adminHost, storefrontHost, certSecretName)
+	return newManifest(ManifestActionUpdate, gateway.Kind, gateway.ObjectMeta, gateway), nil
+}
+
+// RenderTenantVirtualService renders a tenant VirtualService copied from a template.
+// The template is read from the cluster but nothing is created.
+func (c *Client) RenderTenantVirtualService(ctx context.Context, slug, tenantID, templateVSName, tenantHost, adminHost, storefrontHost, nameSuffix string, cloudflareProxied, isCustomDomain bool, dedicatedGatewayName string) (Manifest, error) {
+	vsLocation, err := c.FindVirtualServiceByName(ctx, templateVSName)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to find template VirtualService %s: %w", templateVSName, err)
+	}
+	templateVS, err := c.istio.NetworkingV1beta1().VirtualServices(vsLocation.Namespace).Get(ctx, templateVSName, metav1.GetOptions{})
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to get template VirtualService %s: %w", templateVSName, err)
+	}
+
+	newVSName := c.tenantVirtualServiceName(slug, templateVSName, nameSuffix)
+	vs := c.newTenantVirtualService(templateVS, newVSName, vsLocation.Namespace, slug, tenantID, templateVSName, tenantHost, adminHost, storefrontHost, cloudflareProxied, isCustomDomain, dedicatedGatewayName)
+	return newManifest(ManifestActionCreate, vs.Kind, vs.ObjectMeta, vs), nil
+}
+
+func newManifest(action, kind string, meta metav1.ObjectMeta, obj interface{}) Manifest {
+	return Manifest{
+		Action: action,
+		Kind:   kind,
+		Name:   meta.Namespace + "/" + meta.Name,
+		Object: obj,
+	}
+}
+
+// ManifestsYAML encodes manifests as a multi-document YAML stream; each document is
+// preceded by a comment naming the action it would be applied with
+func ManifestsYAML(manifests []Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, m := range manifests {
+		out, err := yaml.Marshal(m.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s %s: %w", m.Kind, m.Name, err)
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		fmt.Fprintf(&buf, "# %s %s %s\n", m.Action, m.Kind, m.Name)
+		buf.Write(out)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,143 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	networkingv1beta1 "istio.io/api/networking/v1beta1"
+	istionetworkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"tenant-router-service/internal/config"
+)
+
+func testClient() *Client {
+	return &Client{config: &config.Config{
+		Kubernetes: config.K8sConfig{
+			Namespace:                 "devtest",
+			AdminVSName:               "admin-vs-template",
+			StorefrontVSName:          "storefront-vs-template",
+			APIVSName:                 "api-vs-template",
+			ClusterIssuer:             "letsencrypt-dns",
+			CustomDomainGateway:       "custom-domain-gateway",
+			CustomDomainGatewayNS:     "istio-ingress",
+			CustomDomainClusterIssuer: "letsencrypt-http",
+			SharedAuthPolicySelector:  "custom-ingressgateway",
+		},
+		Domain: config.DomainConfig{BaseDomain: "tesserix.app"},
+	}}
+}
+
+func TestRenderCertificate(t *testing.T) {
+	c := testClient()
+
+	m := c.RenderCertificate("acme", []string{"acme-admin.tesserix.app", "acme.tesserix.app"}, false)
+	if m.Action != ManifestActionCreate || m.Kind != "Certificate" || m.Name != "devtest/acme-tenant-tls" {
+		t.Errorf("unexpected manifest %s %s %s", m.Action, m.Kind, m.Name)
+	}
+
+	m = c.RenderCertificate("acme", []string{"shop.example.com"}, true)
+	if m.Name != "istio-ingress/acme-tenant-tls" {
+		t.Errorf("expected custom domain certificate in istio-ingress, got %s", m.Name)
+	}
+	out, err := ManifestsYAML([]Manifest{m})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"# create Certificate istio-ingress/acme-tenant-tls\n",
+		"apiVersion: cert-manager.io/v1\n",
+		"kind: Certificate\n",
+		"- shop.example.com\n",
+		"name: letsencrypt-http\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected YAML to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestManifestsYAML_DedicatedGateway(t *testing.T) {
+	c := testClient()
+	domains := []string{"shop.example.com", "www.shop.example.com"}
+
+	out, err := ManifestsYAML([]Manifest{
+		c.RenderDedicatedGateway("acme", domains, "acme-tenant-tls"),
+		c.RenderDedicatedAuthorizationPolicy("acme", domains),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	yaml := string(out)
+	if strings.Count(yaml, "---\n") != 1 {
+		t.Errorf("expected two YAML documents, got:\n%s", yaml)
+	}
+	for _, want := range []string{
+		"# create Gateway istio-ingress/acme-gateway\n",
+		"apiVersion: networking.istio.io/v1beta1\n",
+		"credentialName: acme-tenant-tls\n",
+		"# create AuthorizationPolicy istio-ingress/acme-custom-domain-policy\n",
+		"apiVersion: security.istio.io/v1beta1\n",
+	} {
+		if !strings.Contains(yaml, want) {
+			t.Errorf("expected YAML to contain %q, got:\n%s", want, yaml)
+		}
+	}
+}
+
+func TestAddGatewayServers(t *testing.T) {
+	gateway := &istionetworkingv1beta1.Gateway{}
+	gateway.Spec.Servers = []*networkingv1beta1.Server{
+		{Hosts: []string{"acme-admin.tesserix.app"}},
+	}
+
+	addGatewayServers(gateway, "acme", "acme-admin.tesserix.app", "acme.tesserix.app", "devtest/acme-tenant-tls")
+
+	if len(gateway.Spec.Servers) != 2 {
+		t.Fatalf("expected only the storefront server to be added, got %d servers", len(gateway.Spec.Servers))
+	}
+	if name := gateway.Spec.Servers[1].Port.Name; name != "https-acme-store" {
+		t.Errorf("expected https-acme-store, got %s", name)
+	}
+}
+
+func TestNewTenantVirtualService(t *testing.T) {
+	c := testClient()
+	template := &istionetworkingv1beta1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Name: "storefront-vs-template", Namespace: "devtest"},
+	}
+	template.Spec.Hosts = []string{"template.internal"}
+	template.Spec.Http = []*networkingv1beta1.HTTPRoute{
+		{Name: "default", CorsPolicy: &networkingv1beta1.CorsPolicy{}},
+	}
+
+	name := c.tenantVirtualServiceName("acme", "storefront-vs-template", "www")
+	if name != "acme-storefront-www-vs" {
+		t.Errorf("expected acme-storefront-www-vs, got %s", name)
+	}
+
+	vs := c.newTenantVirtualService(template, name, "devtest", "acme", "tenant-1", "storefront-vs-template",
+		"www.shop.example.com", "admin.shop.example.com", "shop.example.com", false, true, "acme-gateway")
+
+	if vs.Kind != "VirtualService" || vs.Name != name {
+		t.Errorf("unexpected object %s %s", vs.Kind, vs.Name)
+	}
+	if len(vs.Spec.Hosts) != 1 || vs.Spec.Hosts[0] != "www.shop.example.com" {
+		t.Errorf("expected tenant host, got %v", vs.Spec.Hosts)
+	}
+	if len(vs.Spec.Gateways) != 1 || vs.Spec.Gateways[0] != "istio-ingress/acme-gateway" {
+		t.Errorf("expected dedicated gateway, got %v", vs.Spec.Gateways)
+	}
+	if len(vs.Spec.Http) != 2 || vs.Spec.Http[0].Name != "acme-challenge" {
+		t.Fatalf("expected ACME challenge route first, got %d routes", len(vs.Spec.Http))
+	}
+	if got := vs.Spec.Http[1].Headers.Request.Set["x-jwt-claim-tenant-id"]; got != "tenant-1" {
+		t.Errorf("expected tenant header, got %q", got)
+	}
+	if len(vs.Spec.Http[1].CorsPolicy.AllowOrigins) != 3 {
+		t.Errorf("expected tenant CORS origins, got %d", len(vs.Spec.Http[1].CorsPolicy.AllowOrigins))
+	}
+	if len(template.Spec.Http) != 1 || template.Spec.Hosts[0] != "template.internal" {
+		t.Error("template VirtualService must not be modified")
+	}
+}
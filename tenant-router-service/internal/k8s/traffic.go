@@ -0,0 +1,288 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	networkingv1beta1 "istio.io/api/networking/v1beta1"
+	istionetworkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"tenant-router-service/internal/models"
+)
+
+// MaintenanceRouteName is the name of the HTTP route answering requests while a tenant is in maintenance
+const MaintenanceRouteName = "tenant-maintenance"
+
+// acmeChallengeRouteName is the route custom domain VirtualServices keep first for HTTP-01 validation
+const acmeChallengeRouteName = "acme-challenge"
+
+// Annotations describing the traffic policy of a tenant VirtualService
+// The rate limit annotations are read by the mesh rate-limit configuration
+const (
+	AnnotationMaintenance       = "tenant-router-service/maintenance"
+	AnnotationRateLimitRequests = "tenant-router-service/rate-limit-requests-per-unit"
+	AnnotationRateLimitUnit     = "tenant-router-service/rate-limit-unit"
+	AnnotationRateLimitBurst    = "tenant-router-service/rate-limit-burst"
+	AnnotationTrafficWeights    = "tenant-router-service/traffic-weights"
+)
+
+// ApplyTenantTrafficPolicy sets maintenance mode and the rate limit on all of a tenant's
+// VirtualServices; a nil policy removes it. VirtualServices that do not exist are skipped.
+// Returns the names of the VirtualServices that were updated.
+func (c *Client) ApplyTenantTrafficPolicy(ctx context.Context, slug string, hasWwwHost bool, businessName string, maintenance *models.MaintenancePolicy, rateLimit *models.RateLimitPolicy) ([]string, error) {
+	updated, err := c.updateTenantVirtualServices(ctx, slug, hasWwwHost, func(vs *istionetworkingv1beta1.VirtualService) bool {
+		applyMaintenanceRoute(vs, maintenance, businessName)
+		applyRateLimitAnnotations(vs, rateLimit)
+		return true
+	})
+	if err != nil {
+		return updated, err
+	}
+	log.Printf("[K8s] Applied traffic policy to %v (maintenance=%v, rate_limit=%v)", updated, maintenance != nil, rateLimit != nil)
+	return updated, nil
+}
+
+// ApplyTenantTrafficWeights splits the routes of all of a tenant's VirtualServices between
+// destination subsets; no weights send all traffic to the template destinations again.
+// VirtualServices already carrying the split are left untouched.
+// Returns the names of the VirtualServices that were updated.
+func (c *Client) ApplyTenantTrafficWeights(ctx context.Context, slug string, hasWwwHost bool, weights []models.SubsetWeight) ([]string, error) {
+	updated, err := c.updateTenantVirtualServices(ctx, slug, hasWwwHost, func(vs *istionetworkingv1beta1.VirtualService) bool {
+		return applySubsetWeights(vs, weights)
+	})
+	if err != nil {
+		return updated, err
+	}
+	if len(updated) > 0 {
+		log.Printf("[K8s] Applied traffic weights %q to %v", models.FormatSubsetWeights(weights), updated)
+	}
+	return updated, nil
+}
+
+// updateTenantVirtualServices applies a change to the admin, storefront, www and API
+// VirtualServices of a tenant, retrying on conflicts. mutate reports whether it changed the
+// VirtualService; unchanged and missing VirtualServices are not updated.
+func (c *Client) updateTenantVirtualServices(ctx context.Context, slug string, hasWwwHost bool, mutate func(vs *istionetworkingv1beta1.VirtualService) bool) ([]string, error) {
+	type tenantVS struct {
+		templateVSName string
+		nameSuffix     string
+	}
+	virtualServices := []tenantVS{
+		{c.config.Kubernetes.AdminVSName, ""},
+		{c.config.Kubernetes.StorefrontVSName, ""},
+		{c.config.Kubernetes.APIVSName, ""},
+	}
+	if hasWwwHost {
+		virtualServices = append(virtualServices, tenantVS{c.config.Kubernetes.StorefrontVSName, "www"})
+	}
+
+	var updated []string
+	var errs []string
+	for _, tvs := range virtualServices {
+		vsName := c.tenantVirtualServiceName(slug, tvs.templateVSName, tvs.nameSuffix)
+		vsLocation, err := c.FindVirtualServiceByName(ctx, tvs.templateVSName)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", vsName, err))
+			continue
+		}
+
+		changed := false
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			vs, err := c.istio.NetworkingV1beta1().VirtualServices(vsLocation.Namespace).Get(ctx, vsName, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				log.Printf("[K8s] VirtualService %s not found, skipping", vsName)
+				changed = false
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if changed = mutate(vs); !changed {
+				return nil
+			}
+			_, err = c.istio.NetworkingV1beta1().VirtualServices(vsLocation.Namespace).Update(ctx, vs, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", vsName, err))
+			continue
+		}
+		if changed {
+			updated = append(updated, vsName)
+		}
+	}
+
+	if len(errs) > 0 {
+		return updated, fmt.Errorf("failed to update VirtualServices: %s", strings.Join(errs, "; "))
+	}
+	return updated, nil
+}
+
+// applyMaintenanceRoute adds or removes the route answering every request with a 503
+// maintenance page. The route goes first, after the ACME challenge route so certificates
+// of custom domains can still be issued and renewed during maintenance.
+func applyMaintenanceRoute(vs *istionetworkingv1beta1.VirtualService, policy *models.MaintenancePolicy, businessName string) {
+	routes := make([]*networkingv1beta1.HTTPRoute, 0, len(vs.Spec.Http)+1)
+	for _, route := range vs.Spec.Http {
+		if route.Name != MaintenanceRouteName {
+			routes = append(routes, route)
+		}
+	}
+	vs.Spec.Http = routes
+
+	if policy == nil {
+		delete(vs.Annotations, AnnotationMaintenance)
+		return
+	}
+
+	responseHeaders := map[string]string{
+		"Content-Type":  "text/html; charset=utf-8",
+		"Cache-Control": "no-store",
+	}
+	if policy.RetryAfterSeconds > 0 {
+		responseHeaders["Retry-After"] = strconv.Itoa(policy.RetryAfterSeconds)
+	}
+	vs.Spec.Http = withMaintenanceRoute(vs.Spec.Http, &networkingv1beta1.HTTPRoute{
+		Name: MaintenanceRouteName,
+		DirectResponse: &networkingv1beta1.HTTPDirectResponse{
+			Status: 503,
+			Body: &networkingv1beta1.HTTPBody{
+				Specifier: &networkingv1beta1.HTTPBody_String_{String_: policy.Page(businessName)},
+			},
+		},
+		Headers: &networkingv1beta1.Headers{
+			Response: &networkingv1beta1.Headers_HeaderOperations{Set: responseHeaders},
+		},
+	})
+	if vs.Annotations == nil {
+		vs.Annotations = make(map[string]string)
+	}
+	vs.Annotations[AnnotationMaintenance] = "true"
+}
+
+// withMaintenanceRoute inserts the maintenance route first, or second after the ACME challenge route
+func withMaintenanceRoute(routes []*networkingv1beta1.HTTPRoute, maintenance *networkingv1beta1.HTTPRoute) []*networkingv1beta1.HTTPRoute {
+	return insertAfterACMERoute(routes, maintenance)
+}
+
+// insertAfterACMERoute inserts routes first, or after the ACME challenge route when there is one
+func insertAfterACMERoute(routes []*networkingv1beta1.HTTPRoute, inserted ...*networkingv1beta1.HTTPRoute) []*networkingv1beta1.HTTPRoute {
+	at := 0
+	if len(routes) > 0 && routes[0].Name == acmeChallengeRouteName {
+		at = 1
+	}
+	result := make([]*networkingv1beta1.HTTPRoute, 0, len(routes)+len(inserted))
+	result = append(result, routes[:at]...)
+	result = append(result, inserted...)
+	return append(result, routes[at:]...)
+}
+
+// findMaintenanceRoute returns the maintenance route of a VirtualService, or nil
+func findMaintenanceRoute(routes []*networkingv1beta1.HTTPRoute) *networkingv1beta1.HTTPRoute {
+	for _, route := range routes {
+		if route.Name == MaintenanceRouteName {
+			return route
+		}
+	}
+	return nil
+}
+
+// applyRateLimitAnnotations sets or removes the rate limit annotations of a VirtualService
+func applyRateLimitAnnotations(vs *istionetworkingv1beta1.VirtualService, policy *models.RateLimitPolicy) {
+	if policy == nil {
+		delete(vs.Annotations, AnnotationRateLimitRequests)
+		delete(vs.Annotations, AnnotationRateLimitUnit)
+		delete(vs.Annotations, AnnotationRateLimitBurst)
+		return
+	}
+	if vs.Annotations == nil {
+		vs.Annotations = make(map[string]string)
+	}
+	vs.Annotations[AnnotationRateLimitRequests] = strconv.Itoa(policy.RequestsPerUnit)
+	vs.Annotations[AnnotationRateLimitUnit] = policy.Unit
+	vs.Annotations[AnnotationRateLimitBurst] = strconv.Itoa(policy.Burst)
+}
+
+// applySubsetWeights splits the routes of a VirtualService between destination subsets and
+// records the split in an annotation. Reports whether the VirtualService changed.
+func applySubsetWeights(vs *istionetworkingv1beta1.VirtualService, weights []models.SubsetWeight) bool {
+	value := models.FormatSubsetWeights(weights)
+	previous := vs.Annotations[AnnotationTrafficWeights]
+	if value == previous {
+		return false
+	}
+	previousWeights, _ := models.ParseSubsetWeights(previous)
+	splitRouteDestinations(vs.Spec.Http, weights, previousWeights)
+
+	if len(weights) == 0 {
+		delete(vs.Annotations, AnnotationTrafficWeights)
+		return true
+	}
+	if vs.Annotations == nil {
+		vs.Annotations = make(map[string]string)
+	}
+	vs.Annotations[AnnotationTrafficWeights] = value
+	return true
+}
+
+// splitRouteDestinations sends the requests of each route to the subsets of its destination
+// by weight, or back to the plain destination without weights. Routes are split when they have
+// a single destination without a subset, or when they carry the previous split; routes the
+// template already splits, redirects, direct responses and the ACME challenge are left alone.
+func splitRouteDestinations(routes []*networkingv1beta1.HTTPRoute, weights, previous []models.SubsetWeight) {
+	for _, route := range routes {
+		if route.Name == acmeChallengeRouteName || len(route.Route) == 0 {
+			continue
+		}
+		if !isPlainDestination(route.Route) && !isSplitDestination(route.Route, previous) {
+			continue
+		}
+
+		base := route.Route[0]
+		if len(weights) == 0 {
+			route.Route = []*networkingv1beta1.HTTPRouteDestination{{
+				Destination: &networkingv1beta1.Destination{Host: base.Destination.Host, Port: base.Destination.Port},
+				Headers:     base.Headers,
+			}}
+			continue
+		}
+
+		destinations := make([]*networkingv1beta1.HTTPRouteDestination, 0, len(weights))
+		for _, w := range weights {
+			destinations = append(destinations, &networkingv1beta1.HTTPRouteDestination{
+				Destination: &networkingv1beta1.Destination{
+					Host:   base.Destination.Host,
+					Subset: w.Subset,
+					Port:   base.Destination.Port,
+				},
+				Weight:  int32(w.Weight),
+				Headers: base.Headers,
+			})
+		}
+		route.Route = destinations
+	}
+}
+
+// isPlainDestination reports whether a route sends everything to one destination without a subset
+func isPlainDestination(destinations []*networkingv1beta1.HTTPRouteDestination) bool {
+	return len(destinations) == 1 && destinations[0].Destination != nil && destinations[0].Destination.Subset == ""
+}
+
+// isSplitDestination reports whether a route carries a split between the given subsets of one destination
+func isSplitDestination(destinations []*networkingv1beta1.HTTPRouteDestination, weights []models.SubsetWeight) bool {
+	if len(weights) == 0 || len(destinations) != len(weights) {
+		return false
+	}
+	for i, d := range destinations {
+		if d.Destination == nil || d.Destination.Host != destinations[0].Destination.Host || d.Destination.Subset != weights[i].Subset {
+			return false
+		}
+	}
+	return true
+}
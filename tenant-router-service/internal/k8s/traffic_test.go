@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"testing"
+
+	networkingv1beta1 "istio.io/api/networking/v1beta1"
+	istionetworkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+
+	"tenant-router-service/internal/models"
+)
+
+func routeNames(vs *istionetworkingv1beta1.VirtualService) []string {
+	var names []string
+	for _, route := range vs.Spec.Http {
+		names = append(names, route.Name)
+	}
+	return names
+}
+
+func TestApplyMaintenanceRoute(t *testing.T) {
+	vs := &istionetworkingv1beta1.VirtualService{}
+	vs.Spec.Http = []*networkingv1beta1.HTTPRoute{{Name: "acme-challenge"}, {Name: "api"}, {Name: "default"}}
+
+	policy := &models.MaintenancePolicy{Message: "Upgrading", RetryAfterSeconds: 600}
+	applyMaintenanceRoute(vs, policy, "Acme")
+	applyMaintenanceRoute(vs, policy, "Acme") // re-applying replaces the route
+
+	names := routeNames(vs)
+	if len(names) != 4 || names[0] != "acme-challenge" || names[1] != MaintenanceRouteName {
+		t.Fatalf("expected maintenance route after acme-challenge, got %v", names)
+	}
+	route := vs.Spec.Http[1]
+	if route.DirectResponse == nil || route.DirectResponse.Status != 503 {
+		t.Fatalf("expected 503 direct response, got %+v", route.DirectResponse)
+	}
+	if got := route.Headers.Response.Set["Retry-After"]; got != "600" {
+		t.Errorf("expected Retry-After 600, got %q", got)
+	}
+	if vs.Annotations[AnnotationMaintenance] != "true" {
+		t.Error("expected maintenance annotation")
+	}
+
+	applyMaintenanceRoute(vs, nil, "Acme")
+	if names := routeNames(vs); len(names) != 3 || names[1] != "api" {
+		t.Errorf("expected maintenance route removed, got %v", names)
+	}
+	if _, ok := vs.Annotations[AnnotationMaintenance]; ok {
+		t.Error("expected maintenance annotation removed")
+	}
+}
+
+func TestWithMaintenanceRoute_NoACMEChallenge(t *testing.T) {
+	routes := []*networkingv1beta1.HTTPRoute{{Name: "api"}}
+	routes = withMaintenanceRoute(routes, &networkingv1beta1.HTTPRoute{Name: MaintenanceRouteName})
+	if len(routes) != 2 || routes[0].Name != MaintenanceRouteName {
+		t.Errorf("expected maintenance route first, got %v", routes)
+	}
+	if findMaintenanceRoute(routes) != routes[0] {
+		t.Error("expected findMaintenanceRoute to return the maintenance route")
+	}
+}
+
+func TestApplyRateLimitAnnotations(t *testing.T) {
+	vs := &istionetworkingv1beta1.VirtualService{}
+
+	applyRateLimitAnnotations(vs, &models.RateLimitPolicy{RequestsPerUnit: 100, Unit: models.RateLimitUnitMinute, Burst: 20})
+	if vs.Annotations[AnnotationRateLimitRequests] != "100" || vs.Annotations[AnnotationRateLimitUnit] != "minute" || vs.Annotations[AnnotationRateLimitBurst] != "20" {
+		t.Errorf("unexpected annotations %v", vs.Annotations)
+	}
+
+	applyRateLimitAnnotations(vs, nil)
+	if len(vs.Annotations) != 0 {
+		t.Errorf("expected rate limit annotations removed, got %v", vs.Annotations)
+	}
+}
+
+func TestApplySubsetWeights(t *testing.T) {
+	backend := &networkingv1beta1.Destination{Host: "storefront.devtest.svc.cluster.local", Port: &networkingv1beta1.PortSelector{Number: 80}}
+	vs := &istionetworkingv1beta1.VirtualService{}
+	vs.Spec.Http = []*networkingv1beta1.HTTPRoute{
+		{Name: "acme-challenge", Route: []*networkingv1beta1.HTTPRouteDestination{{Destination: &networkingv1beta1.Destination{Host: "acme-http-solver"}}}},
+		{Name: "default", Route: []*networkingv1beta1.HTTPRouteDestination{{Destination: backend}}},
+		{Name: "redirect", Redirect: &networkingv1beta1.HTTPRedirect{Uri: "/"}},
+	}
+
+	weights := []models.SubsetWeight{{Subset: "blue", Weight: 90}, {Subset: "green", Weight: 10}}
+	if !applySubsetWeights(vs, weights) {
+		t.Fatal("expected VirtualService to change")
+	}
+	if applySubsetWeights(vs, weights) {
+		t.Error("expected re-applying the same split to be a no-op")
+	}
+
+	route := vs.Spec.Http[1].Route
+	if len(route) != 2 || route[0].Destination.Subset != "blue" || route[0].Weight != 90 || route[1].Destination.Subset != "green" || route[1].Weight != 10 {
+		t.Fatalf("unexpected split %v", route)
+	}
+	if route[1].Destination.Host != backend.Host || route[1].Destination.Port.Number != 80 {
+		t.Errorf("expected split to keep the destination host and port, got %v", route[1].Destination)
+	}
+	if len(vs.Spec.Http[0].Route) != 1 || vs.Spec.Http[0].Route[0].Destination.Subset != "" {
+		t.Error("expected ACME challenge route to be left alone")
+	}
+	if vs.Annotations[AnnotationTrafficWeights] != "blue=90,green=10" {
+		t.Errorf("unexpected annotation %q", vs.Annotations[AnnotationTrafficWeights])
+	}
+
+	// Cut over to green, then remove the split
+	applySubsetWeights(vs, []models.SubsetWeight{{Subset: "blue", Weight: 0}, {Subset: "green", Weight: 100}})
+	if route := vs.Spec.Http[1].Route; len(route) != 2 || route[1].Weight != 100 {
+		t.Fatalf("expected split to be updated, got %v", route)
+	}
+	applySubsetWeights(vs, nil)
+	if route := vs.Spec.Http[1].Route; len(route) != 1 || route[0].Destination.Subset != "" || route[0].Weight != 0 {
+		t.Errorf("expected plain destination after removing the split, got %v", route)
+	}
+	if _, ok := vs.Annotations[AnnotationTrafficWeights]; ok {
+		t.Error("expected traffic weights annotation removed")
+	}
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reconcile results
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// queueDepthFunc reports the current work queue depth; set by the reconciler on start
+var queueDepthFunc atomic.Pointer[func() int]
+
+var (
+	reconcileTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tesseract",
+			Subsystem: "tenant_router",
+			Name:      "reconcile_total",
+			Help:      "Reconciliations processed, by operation and result",
+		},
+		[]string{"operation", "result"},
+	)
+
+	reconcileRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tesseract",
+			Subsystem: "tenant_router",
+			Name:      "reconcile_retries_total",
+			Help:      "Failed reconciliations requeued with backoff, by operation",
+		},
+		[]string{"operation"},
+	)
+
+	reconcileDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tesseract",
+			Subsystem: "tenant_router",
+			Name:      "reconcile_duration_seconds",
+			Help:      "Time spent reconciling one tenant, by operation and result",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		},
+		[]string{"operation", "result"},
+	)
+
+	reconcileFailuresBySlug = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tesseract",
+			Subsystem: "tenant_router",
+			Name:      "reconcile_failures_total",
+			Help:      "Failed reconciliations, by tenant slug and operation",
+		},
+		[]string{"slug", "operation"},
+	)
+
+	reconcileWorkers = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "tesseract",
+			Subsystem: "tenant_router",
+			Name:      "reconcile_workers",
+			Help:      "Reconciler workers running",
+		},
+	)
+
+	_ = promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: "tesseract",
+			Subsystem: "tenant_router",
+			Name:      "reconcile_queue_depth",
+			Help:      "Items waiting in the reconciler work queue",
+		},
+		func() float64 {
+			if fn := queueDepthFunc.Load(); fn != nil {
+				return float64((*fn)())
+			}
+			return 0
+		},
+	)
+)
+
+// SetQueueDepthFunc makes the queue depth gauge report fn
+func SetQueueDepthFunc(fn func() int) {
+	queueDepthFunc.Store(&fn)
+}
+
+// SetWorkers records the number of reconciler workers
+func SetWorkers(workers int) {
+	reconcileWorkers.Set(float64(workers))
+}
+
+// ObserveReconcile records a processed reconciliation; failures are also counted per slug
+func ObserveReconcile(slug, operation string, duration time.Duration, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+		reconcileFailuresBySlug.WithLabelValues(slug, operation).Inc()
+	}
+	reconcileTotal.WithLabelValues(operation, result).Inc()
+	reconcileDuration.WithLabelValues(operation, result).Observe(duration.Seconds())
+}
+
+// RecordRetry counts a failed reconciliation requeued with backoff
+func RecordRetry(operation string) {
+	reconcileRetries.WithLabelValues(operation).Inc()
+}
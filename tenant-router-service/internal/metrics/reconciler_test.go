@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveReconcile_CountsFailuresPerSlug(t *testing.T) {
+	ObserveReconcile("acme", "create", 2*time.Second, errors.New("boom"))
+	ObserveReconcile("acme", "create", time.Second, errors.New("boom"))
+	ObserveReconcile("acme", "create", time.Second, nil)
+
+	if got := testutil.ToFloat64(reconcileFailuresBySlug.WithLabelValues("acme", "create")); got != 2 {
+		t.Errorf("failures for acme = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(reconcileTotal.WithLabelValues("create", ResultFailure)); got != 2 {
+		t.Errorf("failed reconciles = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(reconcileTotal.WithLabelValues("create", ResultSuccess)); got != 1 {
+		t.Errorf("successful reconciles = %v, want 1", got)
+	}
+}
+
+func TestQueueDepthGauge(t *testing.T) {
+	SetQueueDepthFunc(func() int { return 7 })
+
+	expected := `
+# HELP tesseract_tenant_router_reconcile_queue_depth Items waiting in the reconciler work queue
+# TYPE tesseract_tenant_router_reconcile_queue_depth gauge
+tesseract_tenant_router_reconcile_queue_depth 7
+`
+	if err := testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected), "tesseract_tenant_router_reconcile_queue_depth"); err != nil {
+		t.Error(err)
+	}
+}
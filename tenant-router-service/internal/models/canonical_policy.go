@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Preferred storefront hosts of a canonicalization policy
+const (
+	PreferredHostWww  = "www"  // www.example.com; the apex redirects to it
+	PreferredHostApex = "apex" // example.com; the www host redirects to it
+)
+
+// CanonicalPolicy makes every storefront page reachable under a single URL so search engines
+// don't see duplicate content: plain HTTP is redirected to HTTPS, and the storefront host that
+// is not preferred redirects to the one that is
+type CanonicalPolicy struct {
+	ForceHTTPS    bool   `json:"force_https"`
+	PreferredHost string `json:"preferred_host,omitempty"` // www, apex, or empty to serve both hosts
+}
+
+// Validate checks the policy and normalizes the preferred host. A preferred host needs the
+// tenant's storefront www host to redirect between.
+func (p *CanonicalPolicy) Validate(storefrontWwwHost string) error {
+	p.PreferredHost = strings.ToLower(strings.TrimSpace(p.PreferredHost))
+	switch p.PreferredHost {
+	case "":
+	case PreferredHostWww, PreferredHostApex:
+		if storefrontWwwHost == "" {
+			return fmt.Errorf("preferred_host requires a storefront www host")
+		}
+	default:
+		return fmt.Errorf("preferred_host must be www or apex")
+	}
+	return nil
+}
+
+// IsZero reports whether the policy adds no redirects
+func (p CanonicalPolicy) IsZero() bool {
+	return !p.ForceHTTPS && p.PreferredHost == ""
+}
+
+// String encodes the policy as "force_https=true,preferred_host=www"; a zero policy encodes as ""
+func (p CanonicalPolicy) String() string {
+	if p.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("force_https=%t,preferred_host=%s", p.ForceHTTPS, p.PreferredHost)
+}
+
+// CanonicalPolicy returns the tenant's canonicalization policy
+func (t *TenantHostRecord) CanonicalPolicy() CanonicalPolicy {
+	return CanonicalPolicy{ForceHTTPS: t.ForceHTTPS, PreferredHost: t.PreferredHost}
+}
@@ -0,0 +1,33 @@
+package models
+
+import "testing"
+
+func TestCanonicalPolicyValidate(t *testing.T) {
+	policy := CanonicalPolicy{ForceHTTPS: true, PreferredHost: " WWW "}
+	if err := policy.Validate("www.acme.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.PreferredHost != PreferredHostWww {
+		t.Errorf("expected normalized preferred host, got %q", policy.PreferredHost)
+	}
+
+	if err := (&CanonicalPolicy{PreferredHost: "apex"}).Validate(""); err == nil {
+		t.Error("expected error for a preferred host without a www host")
+	}
+	if err := (&CanonicalPolicy{PreferredHost: "shop"}).Validate("www.acme.com"); err == nil {
+		t.Error("expected error for an unknown preferred host")
+	}
+	if err := (&CanonicalPolicy{ForceHTTPS: true}).Validate(""); err != nil {
+		t.Errorf("unexpected error for HTTPS only: %v", err)
+	}
+}
+
+func TestCanonicalPolicyString(t *testing.T) {
+	if got := (CanonicalPolicy{}).String(); got != "" {
+		t.Errorf("expected empty encoding for the zero policy, got %q", got)
+	}
+	got := CanonicalPolicy{ForceHTTPS: true, PreferredHost: PreferredHostApex}.String()
+	if got != "force_https=true,preferred_host=apex" {
+		t.Errorf("unexpected encoding %q", got)
+	}
+}
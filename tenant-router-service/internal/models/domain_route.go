@@ -0,0 +1,63 @@
+package models
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WildcardPrefix is the leading label of wildcard domain patterns
+const WildcardPrefix = "*."
+
+// DomainRoute maps an active custom domain to the tenant that serves it
+// Pattern is either an exact host (shop.example.com) or a wildcard (*.shop.example.com).
+// A wildcard matches exactly one extra label: east.shop.example.com, but neither
+// shop.example.com nor a.east.shop.example.com.
+type DomainRoute struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	DomainID   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_domain_route_domain_id" json:"domain_id"` // custom-domain-service domain ID
+	Pattern    string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_domain_route_pattern" json:"pattern"`
+	IsWildcard bool      `gorm:"default:false" json:"is_wildcard"`
+	TenantID   string    `gorm:"type:varchar(255);not null;index:idx_domain_route_tenant_id" json:"tenant_id"`
+	TenantSlug string    `gorm:"type:varchar(63);not null" json:"tenant_slug"`
+	TargetType string    `gorm:"type:varchar(20);not null;default:'storefront'" json:"target_type"`
+	IsPrimary  bool      `gorm:"default:false" json:"is_primary"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for DomainRoute
+func (DomainRoute) TableName() string {
+	return "domain_routes"
+}
+
+// Matches reports whether the route serves host
+func (r *DomainRoute) Matches(host string) bool {
+	host = NormalizeHost(host)
+	if r.IsWildcard {
+		return WildcardPatternFor(host) == r.Pattern
+	}
+	return host == r.Pattern
+}
+
+// NormalizeHost lowercases a Host header value and strips its port and trailing dot
+func NormalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// WildcardPatternFor returns the only wildcard pattern that can match host, e.g.
+// *.shop.example.com for east.shop.example.com. Hosts directly under a TLD and
+// wildcard patterns themselves have none, so an empty string is returned.
+func WildcardPatternFor(host string) string {
+	label, parent, ok := strings.Cut(host, ".")
+	if !ok || label == "" || label == "*" || !strings.Contains(parent, ".") {
+		return ""
+	}
+	return WildcardPrefix + parent
+}
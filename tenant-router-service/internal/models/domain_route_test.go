@@ -0,0 +1,85 @@
+package models
+
+import "testing"
+
+func TestNormalizeHost(t *testing.T) {
+	tests := map[string]string{
+		"Shop.Example.com":       "shop.example.com",
+		"shop.example.com:443":   "shop.example.com",
+		"shop.example.com.":      "shop.example.com",
+		" east.shop.example.com": "east.shop.example.com",
+		"*.shop.example.com":     "*.shop.example.com",
+	}
+
+	for in, want := range tests {
+		if got := NormalizeHost(in); got != want {
+			t.Errorf("NormalizeHost(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWildcardPatternFor(t *testing.T) {
+	tests := map[string]string{
+		"east.shop.example.com": "*.shop.example.com",
+		"shop.example.com":      "*.example.com",
+		"example.com":           "",
+		"*.shop.example.com":    "",
+		"localhost":             "",
+	}
+
+	for host, want := range tests {
+		if got := WildcardPatternFor(host); got != want {
+			t.Errorf("WildcardPatternFor(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestDomainRoute_Matches(t *testing.T) {
+	wildcard := &DomainRoute{Pattern: "*.shop.example.com", IsWildcard: true}
+	exact := &DomainRoute{Pattern: "shop.example.com"}
+
+	tests := []struct {
+		route *DomainRoute
+		host  string
+		want  bool
+	}{
+		{wildcard, "east.shop.example.com", true},
+		{wildcard, "West.Shop.Example.com:8443", true},
+		{wildcard, "shop.example.com", false},
+		{wildcard, "a.east.shop.example.com", false},
+		{wildcard, "east.shop.example.org", false},
+		{exact, "shop.example.com", true},
+		{exact, "east.shop.example.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.route.Matches(tt.host); got != tt.want {
+			t.Errorf("%s.Matches(%q) = %v, want %v", tt.route.Pattern, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestCustomDomainEvent_Route(t *testing.T) {
+	event := CustomDomainEvent{
+		EventType:  "domain.activated",
+		TenantID:   "tenant-1",
+		DomainID:   "domain-1",
+		Domain:     "*.Shop.Example.com",
+		DomainType: "wildcard",
+		TenantSlug: "acme",
+	}
+
+	route := event.Route()
+	if route.Pattern != "*.shop.example.com" || !route.IsWildcard {
+		t.Errorf("expected wildcard route *.shop.example.com, got %q (wildcard=%v)", route.Pattern, route.IsWildcard)
+	}
+	if route.TargetType != "storefront" {
+		t.Errorf("expected default target type storefront, got %q", route.TargetType)
+	}
+
+	event.Domain = "shop.example.com"
+	event.DomainType = "subdomain"
+	if route := event.Route(); route.IsWildcard {
+		t.Errorf("expected exact route for %s", event.Domain)
+	}
+}
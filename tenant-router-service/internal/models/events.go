@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // TenantCreatedEvent represents the event received when a tenant is created
 // This matches the event published by tenant-service
@@ -19,6 +22,9 @@ type TenantCreatedEvent struct {
 	APIHost           string `json:"api_host"`             // e.g., "mystore-api.tesserix.app" or "api.customdomain.com"
 	BaseDomain        string `json:"base_domain"`          // e.g., "tesserix.app"
 	IsCustomDomain    bool   `json:"is_custom_domain"`     // true if using custom domain
+	// Canonicalization policy of the storefront (optional)
+	ForceHTTPS        bool   `json:"force_https,omitempty"`     // redirect plain HTTP to HTTPS
+	PreferredHost     string `json:"preferred_host,omitempty"`  // "www" or "apex" (only with a storefront www host)
 	Timestamp         time.Time `json:"timestamp"`
 }
 
@@ -54,3 +60,102 @@ type ProvisionResult struct {
 	Errors         []string `json:"errors,omitempty"`
 	Success        bool     `json:"success"`
 }
+
+// ProvisioningVerifiedEvent is published once every provisioned host of a tenant answered over HTTPS
+type ProvisioningVerifiedEvent struct {
+	EventType  string            `json:"event_type"`
+	TenantID   string            `json:"tenant_id"`
+	Slug       string            `json:"slug"`
+	Hosts      []HostProbeResult `json:"hosts"`
+	VerifiedAt time.Time         `json:"verified_at"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// TenantSlugChangedEvent is received when a tenant is renamed. Routes for the new hosts are
+// provisioned; the old slug's routes stay until its TenantSlugReleasedEvent.
+type TenantSlugChangedEvent struct {
+	EventType         string    `json:"event_type"`
+	TenantID          string    `json:"tenant_id"`
+	OldSlug           string    `json:"old_slug"`
+	NewSlug           string    `json:"new_slug"`
+	AdminHost         string    `json:"admin_host"`
+	StorefrontHost    string    `json:"storefront_host"`
+	APIHost           string    `json:"api_host"`
+	BaseDomain        string    `json:"base_domain"`
+	RedirectExpiresAt time.Time `json:"redirect_expires_at"`
+	Timestamp         time.Time `json:"timestamp"`
+}
+
+// CreatedEvent returns the event provisioning the new slug. The product, business name and
+// email are carried over from the old slug's record when there is one.
+func (e *TenantSlugChangedEvent) CreatedEvent(previous *TenantHostRecord) *TenantCreatedEvent {
+	created := &TenantCreatedEvent{
+		EventType:      e.EventType,
+		TenantID:       e.TenantID,
+		Slug:           e.NewSlug,
+		AdminHost:      e.AdminHost,
+		StorefrontHost: e.StorefrontHost,
+		APIHost:        e.APIHost,
+		BaseDomain:     e.BaseDomain,
+		Timestamp:      e.Timestamp,
+	}
+	if previous != nil {
+		created.Product = previous.Product
+		created.BusinessName = previous.BusinessName
+		created.Email = previous.Email
+	}
+	return created
+}
+
+// TenantSlugReleasedEvent is received when a renamed tenant's former slug stops redirecting
+// (or the tenant is deleted), so the former slug's routes are removed
+type TenantSlugReleasedEvent struct {
+	EventType   string    `json:"event_type"`
+	TenantID    string    `json:"tenant_id"`
+	Slug        string    `json:"slug"`
+	CurrentSlug string    `json:"current_slug"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// DeletedEvent returns the event removing the former slug's routes; the hosts come from its record
+func (e *TenantSlugReleasedEvent) DeletedEvent() *TenantDeletedEvent {
+	return &TenantDeletedEvent{
+		EventType: e.EventType,
+		TenantID:  e.TenantID,
+		Slug:      e.Slug,
+		Timestamp: e.Timestamp,
+	}
+}
+
+// CustomDomainEvent is received from custom-domain-service on the DOMAIN_EVENTS stream
+// Only the fields needed to route the domain are decoded.
+type CustomDomainEvent struct {
+	EventType  string    `json:"eventType"`
+	TenantID   string    `json:"tenantId"`
+	DomainID   string    `json:"domainId"`
+	Domain     string    `json:"domain"`
+	DomainType string    `json:"domainType,omitempty"` // apex, subdomain or wildcard
+	TenantSlug string    `json:"tenantSlug,omitempty"`
+	Status     string    `json:"status"`
+	IsPrimary  bool      `json:"isPrimary,omitempty"`
+	TargetType string    `json:"targetType,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Route returns the domain route the event registers
+func (e *CustomDomainEvent) Route() *DomainRoute {
+	pattern := NormalizeHost(e.Domain)
+	targetType := e.TargetType
+	if targetType == "" {
+		targetType = "storefront"
+	}
+	return &DomainRoute{
+		DomainID:   e.DomainID,
+		Pattern:    pattern,
+		IsWildcard: e.DomainType == "wildcard" || strings.HasPrefix(pattern, WildcardPrefix),
+		TenantID:   e.TenantID,
+		TenantSlug: e.TenantSlug,
+		TargetType: targetType,
+		IsPrimary:  e.IsPrimary,
+	}
+}
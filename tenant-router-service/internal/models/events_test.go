@@ -137,3 +137,58 @@ func TestProvisionResult_Failure(t *testing.T) {
 		t.Errorf("expected 2 errors, got %d", len(result.Errors))
 	}
 }
+
+func TestTenantSlugChangedEvent_CreatedEvent(t *testing.T) {
+	event := TenantSlugChangedEvent{
+		EventType:      "tenant.slug.changed",
+		TenantID:       "test-tenant-id",
+		OldSlug:        "old-store",
+		NewSlug:        "new-store",
+		AdminHost:      "new-store-admin.tesserix.app",
+		StorefrontHost: "new-store.tesserix.app",
+		APIHost:        "new-store-api.tesserix.app",
+		BaseDomain:     "tesserix.app",
+	}
+	previous := &TenantHostRecord{
+		TenantID:     "test-tenant-id",
+		Slug:         "old-store",
+		Product:      "marketplace",
+		BusinessName: "Test Business",
+		Email:        "test@example.com",
+	}
+
+	created := event.CreatedEvent(previous)
+	if created.Slug != "new-store" || created.TenantID != event.TenantID {
+		t.Errorf("expected new-store for test-tenant-id, got %s for %s", created.Slug, created.TenantID)
+	}
+	if created.AdminHost != event.AdminHost || created.StorefrontHost != event.StorefrontHost || created.APIHost != event.APIHost {
+		t.Errorf("expected hosts from the event, got %s, %s, %s", created.AdminHost, created.StorefrontHost, created.APIHost)
+	}
+	if created.Product != "marketplace" || created.BusinessName != "Test Business" || created.Email != "test@example.com" {
+		t.Errorf("expected product, business name and email from the old record, got %+v", created)
+	}
+	if created.IsCustomDomain {
+		t.Error("renamed tenants are on the platform domain")
+	}
+
+	if created := event.CreatedEvent(nil); created.Slug != "new-store" || created.Product != "" {
+		t.Errorf("expected a bare create event without an old record, got %+v", created)
+	}
+}
+
+func TestTenantSlugReleasedEvent_DeletedEvent(t *testing.T) {
+	data := []byte(`{"event_type":"tenant.slug.released","tenant_id":"test-tenant-id","slug":"old-store","current_slug":"new-store"}`)
+
+	var event TenantSlugReleasedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("failed to unmarshal event: %v", err)
+	}
+
+	deleted := event.DeletedEvent()
+	if deleted.Slug != "old-store" || deleted.TenantID != "test-tenant-id" {
+		t.Errorf("expected delete of old-store for test-tenant-id, got %s for %s", deleted.Slug, deleted.TenantID)
+	}
+	if deleted.AdminHost != "" || deleted.StorefrontHost != "" {
+		t.Errorf("expected hosts to come from the record, got %s, %s", deleted.AdminHost, deleted.StorefrontHost)
+	}
+}
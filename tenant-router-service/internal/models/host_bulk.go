@@ -0,0 +1,246 @@
+package models
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaxBulkHosts bounds the number of host definitions accepted by one bulk import
+const MaxBulkHosts = 1000
+
+// Bulk import result statuses
+const (
+	BulkHostQueued    = "queued"    // Valid; enqueued for provisioning (or would be, in a dry run)
+	BulkHostDuplicate = "duplicate" // Identical to an earlier definition in the batch; skipped
+	BulkHostInvalid   = "invalid"   // Failed validation or conflicts with an earlier definition
+)
+
+var (
+	// hostSlugRegex matches slugs usable as the first label of a tenant host
+	hostSlugRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*[a-z0-9]$`)
+	// hostnameLabelRegex matches one DNS label of a hostname
+	hostnameLabelRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+)
+
+// HostDefinition describes one tenant's hosts. It is the body of POST /api/v1/hosts, an entry of
+// a bulk import and a row of the export, so an export can be imported into a fresh cluster as is.
+type HostDefinition struct {
+	Slug              string `json:"slug"`
+	TenantID          string `json:"tenant_id"`
+	AdminHost         string `json:"admin_host"`
+	StorefrontHost    string `json:"storefront_host"`
+	StorefrontWwwHost string `json:"storefront_www_host,omitempty"`
+	APIHost           string `json:"api_host"`
+	BaseDomain        string `json:"base_domain"`
+	IsCustomDomain    bool   `json:"is_custom_domain"`
+	Product           string `json:"product,omitempty"`
+	BusinessName      string `json:"business_name,omitempty"`
+	Email             string `json:"email,omitempty"`
+	ForceHTTPS        bool   `json:"force_https,omitempty"`
+	PreferredHost     string `json:"preferred_host,omitempty"`
+}
+
+// HostDefinitionFromRecord returns the definition a tenant host record was provisioned from
+func HostDefinitionFromRecord(record *TenantHostRecord) HostDefinition {
+	return HostDefinition{
+		Slug:              record.Slug,
+		TenantID:          record.TenantID,
+		AdminHost:         record.AdminHost,
+		StorefrontHost:    record.StorefrontHost,
+		StorefrontWwwHost: record.StorefrontWwwHost,
+		APIHost:           record.APIHost,
+		BaseDomain:        record.BaseDomain,
+		IsCustomDomain:    record.IsCustomDomain,
+		Product:           record.Product,
+		BusinessName:      record.BusinessName,
+		Email:             record.Email,
+		ForceHTTPS:        record.ForceHTTPS,
+		PreferredHost:     record.PreferredHost,
+	}
+}
+
+// Normalize trims and lowercases the definition and fills in the hosts derived from the slug
+// and base domain, the same defaults POST /api/v1/hosts applies
+func (d *HostDefinition) Normalize(baseDomain string) {
+	d.Slug = strings.ToLower(strings.TrimSpace(d.Slug))
+	d.TenantID = strings.TrimSpace(d.TenantID)
+	d.BaseDomain = strings.ToLower(strings.TrimSpace(d.BaseDomain))
+	if d.BaseDomain == "" {
+		d.BaseDomain = baseDomain
+	}
+	for _, host := range []*string{&d.AdminHost, &d.StorefrontHost, &d.StorefrontWwwHost, &d.APIHost} {
+		*host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(*host)), ".")
+	}
+	if d.AdminHost == "" {
+		d.AdminHost = fmt.Sprintf("%s-admin.%s", d.Slug, d.BaseDomain)
+	}
+	if d.StorefrontHost == "" {
+		d.StorefrontHost = fmt.Sprintf("%s.%s", d.Slug, d.BaseDomain)
+	}
+	if d.APIHost == "" {
+		d.APIHost = fmt.Sprintf("%s-api.%s", d.Slug, d.BaseDomain)
+	}
+	d.Product = strings.TrimSpace(d.Product)
+	d.BusinessName = strings.TrimSpace(d.BusinessName)
+	d.Email = strings.TrimSpace(d.Email)
+	d.PreferredHost = strings.ToLower(strings.TrimSpace(d.PreferredHost))
+}
+
+// Validate checks a normalized definition
+func (d *HostDefinition) Validate() error {
+	if len(d.Slug) < 2 || len(d.Slug) > 63 || !hostSlugRegex.MatchString(d.Slug) {
+		return fmt.Errorf("invalid slug %q", d.Slug)
+	}
+	if d.TenantID == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+	for _, host := range d.Hosts() {
+		if err := validateHostname(host); err != nil {
+			return err
+		}
+	}
+	if d.StorefrontWwwHost != "" && !d.IsCustomDomain {
+		return fmt.Errorf("storefront_www_host is only used with custom domains")
+	}
+	canonical := d.CanonicalPolicy()
+	return canonical.Validate(d.StorefrontWwwHost)
+}
+
+// Hosts returns the hostnames the definition routes
+func (d *HostDefinition) Hosts() []string {
+	hosts := []string{d.AdminHost, d.StorefrontHost, d.APIHost}
+	if d.StorefrontWwwHost != "" {
+		hosts = append(hosts, d.StorefrontWwwHost)
+	}
+	return hosts
+}
+
+// Event returns the tenant created event that provisions the definition
+func (d *HostDefinition) Event() *TenantCreatedEvent {
+	return &TenantCreatedEvent{
+		TenantID:          d.TenantID,
+		Slug:              d.Slug,
+		AdminHost:         d.AdminHost,
+		StorefrontHost:    d.StorefrontHost,
+		StorefrontWwwHost: d.StorefrontWwwHost,
+		APIHost:           d.APIHost,
+		BaseDomain:        d.BaseDomain,
+		IsCustomDomain:    d.IsCustomDomain,
+		Product:           d.Product,
+		BusinessName:      d.BusinessName,
+		Email:             d.Email,
+		ForceHTTPS:        d.ForceHTTPS,
+		PreferredHost:     d.PreferredHost,
+	}
+}
+
+// CanonicalPolicy returns the canonicalization policy of the definition
+func (d *HostDefinition) CanonicalPolicy() CanonicalPolicy {
+	return CanonicalPolicy{ForceHTTPS: d.ForceHTTPS, PreferredHost: d.PreferredHost}
+}
+
+func validateHostname(host string) error {
+	if host == "" || len(host) > 253 {
+		return fmt.Errorf("invalid host %q", host)
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("host %q must be fully qualified", host)
+	}
+	for _, label := range labels {
+		if len(label) > 63 || !hostnameLabelRegex.MatchString(label) {
+			return fmt.Errorf("invalid host %q", host)
+		}
+	}
+	return nil
+}
+
+// BulkHostResult is the outcome of one entry of a bulk import
+type BulkHostResult struct {
+	Index  int    `json:"index"`
+	Slug   string `json:"slug"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PrepareBulkHosts normalizes and validates a bulk import. Definitions repeated verbatim are
+// skipped as duplicates; a slug or host already claimed by a different definition earlier in
+// the batch makes the later one invalid. Returns the definitions to provision, in order, and a
+// result for every entry.
+func PrepareBulkHosts(definitions []HostDefinition, baseDomain string) ([]HostDefinition, []BulkHostResult) {
+	valid := make([]HostDefinition, 0, len(definitions))
+	results := make([]BulkHostResult, len(definitions))
+	bySlug := make(map[string]HostDefinition, len(definitions))
+	hostOwners := make(map[string]string)
+
+	for i, definition := range definitions {
+		definition.Normalize(baseDomain)
+		results[i] = BulkHostResult{Index: i, Slug: definition.Slug, Status: BulkHostInvalid}
+
+		if err := definition.Validate(); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		if previous, ok := bySlug[definition.Slug]; ok {
+			if previous == definition {
+				results[i].Status = BulkHostDuplicate
+			} else {
+				results[i].Error = fmt.Sprintf("slug %q is defined differently earlier in the batch", definition.Slug)
+			}
+			continue
+		}
+		if conflict := claimedHost(definition, hostOwners); conflict != "" {
+			results[i].Error = fmt.Sprintf("host %q is already used by %q", conflict, hostOwners[conflict])
+			continue
+		}
+
+		bySlug[definition.Slug] = definition
+		for _, host := range definition.Hosts() {
+			hostOwners[host] = definition.Slug
+		}
+		results[i].Status = BulkHostQueued
+		valid = append(valid, definition)
+	}
+
+	return valid, results
+}
+
+// claimedHost returns a host of the definition that another slug already routes, or ""
+func claimedHost(definition HostDefinition, hostOwners map[string]string) string {
+	for _, host := range definition.Hosts() {
+		if owner, ok := hostOwners[host]; ok && owner != definition.Slug {
+			return host
+		}
+	}
+	return ""
+}
+
+// hostCSVHeader is the header row of the CSV export, in the JSON field names
+var hostCSVHeader = []string{
+	"slug", "tenant_id", "admin_host", "storefront_host", "storefront_www_host", "api_host",
+	"base_domain", "is_custom_domain", "product", "business_name", "email", "force_https", "preferred_host",
+}
+
+// WriteHostsCSV writes host definitions as CSV with a header row
+func WriteHostsCSV(w io.Writer, definitions []HostDefinition) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(hostCSVHeader); err != nil {
+		return err
+	}
+	for _, d := range definitions {
+		row := []string{
+			d.Slug, d.TenantID, d.AdminHost, d.StorefrontHost, d.StorefrontWwwHost, d.APIHost,
+			d.BaseDomain, strconv.FormatBool(d.IsCustomDomain), d.Product, d.BusinessName, d.Email,
+			strconv.FormatBool(d.ForceHTTPS), d.PreferredHost,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHostDefinitionNormalizeDefaults(t *testing.T) {
+	d := HostDefinition{Slug: " Acme ", TenantID: "t-1", StorefrontHost: "Shop.Example.com."}
+	d.Normalize("tesserix.app")
+
+	if d.Slug != "acme" || d.BaseDomain != "tesserix.app" {
+		t.Fatalf("unexpected slug/base domain: %q %q", d.Slug, d.BaseDomain)
+	}
+	if d.AdminHost != "acme-admin.tesserix.app" || d.APIHost != "acme-api.tesserix.app" {
+		t.Errorf("unexpected derived hosts: %q %q", d.AdminHost, d.APIHost)
+	}
+	if d.StorefrontHost != "shop.example.com" {
+		t.Errorf("expected normalized storefront host, got %q", d.StorefrontHost)
+	}
+	if err := d.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestHostDefinitionValidate(t *testing.T) {
+	cases := map[string]HostDefinition{
+		"bad slug":           {Slug: "-acme", TenantID: "t-1"},
+		"missing tenant":     {Slug: "acme"},
+		"bad host":           {Slug: "acme", TenantID: "t-1", AdminHost: "admin_acme.example.com"},
+		"unqualified host":   {Slug: "acme", TenantID: "t-1", APIHost: "localhost"},
+		"www without custom": {Slug: "acme", TenantID: "t-1", StorefrontWwwHost: "www.acme.com"},
+		"prefer www no www":  {Slug: "acme", TenantID: "t-1", PreferredHost: "www"},
+	}
+	for name, d := range cases {
+		d.Normalize("tesserix.app")
+		if err := d.Validate(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestPrepareBulkHosts(t *testing.T) {
+	definitions := []HostDefinition{
+		{Slug: "acme", TenantID: "t-1"},
+		{Slug: "ACME", TenantID: "t-1"},                                        // same after normalizing
+		{Slug: "acme", TenantID: "t-2"},                                        // conflicting redefinition
+		{Slug: "globex", TenantID: "t-3", StorefrontHost: "acme.tesserix.app"}, // host taken by acme
+		{Slug: "initech", TenantID: "t-4"},
+		{Slug: "", TenantID: "t-5"},
+	}
+
+	valid, results := PrepareBulkHosts(definitions, "tesserix.app")
+
+	if len(valid) != 2 || valid[0].Slug != "acme" || valid[1].Slug != "initech" {
+		t.Fatalf("unexpected valid definitions: %+v", valid)
+	}
+	want := []string{BulkHostQueued, BulkHostDuplicate, BulkHostInvalid, BulkHostInvalid, BulkHostQueued, BulkHostInvalid}
+	for i, status := range want {
+		if results[i].Status != status {
+			t.Errorf("result %d: status %q, want %q (%s)", i, results[i].Status, status, results[i].Error)
+		}
+		if results[i].Index != i {
+			t.Errorf("result %d: index %d", i, results[i].Index)
+		}
+	}
+	if !strings.Contains(results[3].Error, "acme.tesserix.app") {
+		t.Errorf("expected host conflict error, got %q", results[3].Error)
+	}
+}
+
+func TestWriteHostsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	d := HostDefinition{Slug: "acme", TenantID: "t-1", AdminHost: "acme-admin.tesserix.app", StorefrontHost: "acme.tesserix.app",
+		APIHost: "acme-api.tesserix.app", BaseDomain: "tesserix.app", BusinessName: "Acme, Inc."}
+	if err := WriteHostsCSV(&buf, []HostDefinition{d}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and one row, got %d lines", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "slug,tenant_id,admin_host") {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `,false,,"Acme, Inc.",`) {
+		t.Errorf("unexpected row: %s", lines[1])
+	}
+}
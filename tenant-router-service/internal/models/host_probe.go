@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ReachabilityStatus represents whether the provisioned hosts of a tenant answer over HTTPS
+type ReachabilityStatus string
+
+const (
+	ReachabilityUnchecked   ReachabilityStatus = "unchecked"   // Not probed since the last provisioning
+	ReachabilityVerified    ReachabilityStatus = "verified"    // Every host answered over HTTPS
+	ReachabilityUnreachable ReachabilityStatus = "unreachable" // At least one host failed the last probe
+)
+
+// Host roles probed after provisioning
+const (
+	HostRoleAdmin         = "admin"
+	HostRoleStorefront    = "storefront"
+	HostRoleStorefrontWww = "storefront_www"
+	HostRoleAPI           = "api"
+)
+
+// HostProbeResult is the outcome of probing one tenant host
+type HostProbeResult struct {
+	Role       string    `json:"role"`
+	Host       string    `json:"host"`
+	URL        string    `json:"url"`
+	Reachable  bool      `json:"reachable"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	LatencyMs  int64     `json:"latency_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// IsReachableStatusCode reports whether an HTTP status code shows the host is routed to a live backend
+// Redirects and auth challenges count as reachable; 404 means the route is missing and 5xx means
+// the gateway has no healthy upstream
+func IsReachableStatusCode(code int) bool {
+	switch {
+	case code >= 200 && code < 400:
+		return true
+	case code == 401 || code == 403:
+		return true
+	default:
+		return false
+	}
+}
+
+// SummarizeProbes returns the overall reachability of a set of probe results
+func SummarizeProbes(results []HostProbeResult) ReachabilityStatus {
+	if len(results) == 0 {
+		return ReachabilityUnchecked
+	}
+	for _, result := range results {
+		if !result.Reachable {
+			return ReachabilityUnreachable
+		}
+	}
+	return ReachabilityVerified
+}
+
+// HostProbeResults decodes the results of the latest reachability probe
+func (t *TenantHostRecord) HostProbeResults() []HostProbeResult {
+	if t.ReachabilityResults == "" {
+		return nil
+	}
+	var results []HostProbeResult
+	if err := json.Unmarshal([]byte(t.ReachabilityResults), &results); err != nil {
+		return nil
+	}
+	return results
+}
@@ -0,0 +1,56 @@
+package models
+
+import "testing"
+
+func TestIsReachableStatusCode(t *testing.T) {
+	testCases := map[int]bool{
+		200: true,
+		204: true,
+		301: true,
+		302: true,
+		401: true,
+		403: true,
+		404: false,
+		500: false,
+		502: false,
+		503: false,
+	}
+
+	for code, expected := range testCases {
+		if got := IsReachableStatusCode(code); got != expected {
+			t.Errorf("expected IsReachableStatusCode(%d) = %v, got %v", code, expected, got)
+		}
+	}
+}
+
+func TestSummarizeProbes(t *testing.T) {
+	if status := SummarizeProbes(nil); status != ReachabilityUnchecked {
+		t.Errorf("expected unchecked for no results, got %s", status)
+	}
+
+	results := []HostProbeResult{
+		{Role: HostRoleAdmin, Reachable: true},
+		{Role: HostRoleStorefront, Reachable: true},
+	}
+	if status := SummarizeProbes(results); status != ReachabilityVerified {
+		t.Errorf("expected verified, got %s", status)
+	}
+
+	results = append(results, HostProbeResult{Role: HostRoleAPI, Reachable: false, StatusCode: 404})
+	if status := SummarizeProbes(results); status != ReachabilityUnreachable {
+		t.Errorf("expected unreachable, got %s", status)
+	}
+}
+
+func TestTenantHostRecord_HostProbeResults(t *testing.T) {
+	record := &TenantHostRecord{}
+	if results := record.HostProbeResults(); results != nil {
+		t.Errorf("expected no results for an unprobed record, got %v", results)
+	}
+
+	record.ReachabilityResults = `[{"role":"admin","host":"acme-admin.tesserix.app","reachable":true,"status_code":200}]`
+	results := record.HostProbeResults()
+	if len(results) != 1 || results[0].Host != "acme-admin.tesserix.app" || !results[0].Reachable {
+		t.Errorf("unexpected decoded results: %+v", results)
+	}
+}
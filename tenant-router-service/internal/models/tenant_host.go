@@ -49,6 +49,31 @@ type TenantHostRecord struct {
 	StorefrontWwwVSNamespace string `gorm:"type:varchar(255)" json:"storefront_www_vs_namespace,omitempty"`
 	APIVSNamespace           string `gorm:"type:varchar(255)" json:"api_vs_namespace,omitempty"`
 
+	// Reachability tracking - HTTPS probes of the hosts after provisioning
+	ReachabilityStatus     ReachabilityStatus `gorm:"type:varchar(50);not null;default:'unchecked'" json:"reachability_status"`
+	ReachabilityResults    string             `gorm:"type:text" json:"-"` // JSON-encoded []HostProbeResult from the latest probe
+	ReachabilityAttempts   int                `gorm:"default:0" json:"reachability_attempts"`
+	ReachabilityCheckedAt  *time.Time         `json:"reachability_checked_at,omitempty"`
+	ReachabilityVerifiedAt *time.Time         `json:"reachability_verified_at,omitempty"`
+
+	// Maintenance mode - the tenant VirtualServices answer every request with a 503 maintenance page
+	MaintenanceMode       bool       `gorm:"default:false" json:"maintenance_mode"`
+	MaintenanceMessage    string     `gorm:"type:text" json:"maintenance_message,omitempty"`
+	MaintenanceRetryAfter int        `gorm:"default:0" json:"maintenance_retry_after_seconds,omitempty"`
+	MaintenanceSince      *time.Time `json:"maintenance_since,omitempty"`
+
+	// Per-tenant rate limit, applied as annotations on the tenant VirtualServices (0 = platform default)
+	RateLimitRequests int    `gorm:"default:0" json:"rate_limit_requests,omitempty"`
+	RateLimitUnit     string `gorm:"type:varchar(10)" json:"rate_limit_unit,omitempty"`
+	RateLimitBurst    int    `gorm:"default:0" json:"rate_limit_burst,omitempty"`
+
+	// Blue/green traffic split between destination subsets, e.g. "blue=90,green=10" (empty = no split)
+	TrafficWeights string `gorm:"type:varchar(255)" json:"traffic_weights,omitempty"`
+
+	// Canonicalization policy - redirects to HTTPS and to the preferred storefront host
+	ForceHTTPS    bool   `gorm:"default:false" json:"force_https"`
+	PreferredHost string `gorm:"type:varchar(10)" json:"preferred_host,omitempty"` // "www", "apex" or empty to serve both
+
 	// Error tracking
 	LastError    string     `gorm:"type:text" json:"last_error,omitempty"`
 	RetryCount   int        `gorm:"default:0" json:"retry_count"`
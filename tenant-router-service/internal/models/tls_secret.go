@@ -0,0 +1,11 @@
+package models
+
+// TLSSecret is the portable content of a Kubernetes TLS secret
+// Labels and annotations are kept so cert-manager keeps owning a restored secret
+type TLSSecret struct {
+	Namespace   string            `json:"namespace"`
+	Name        string            `json:"name"`
+	Data        map[string][]byte `json:"data"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaintenanceMessage is shown on the maintenance page when no message is given
+const DefaultMaintenanceMessage = "We're performing maintenance and will be back shortly."
+
+// maxMaintenanceMessageLength bounds the message embedded in the VirtualService
+const maxMaintenanceMessageLength = 1000
+
+// Rate limit units
+const (
+	RateLimitUnitSecond = "second"
+	RateLimitUnitMinute = "minute"
+	RateLimitUnitHour   = "hour"
+)
+
+// MaintenancePolicy puts a tenant into maintenance mode: every request to its hosts is
+// answered with a 503 maintenance page until the policy is removed
+type MaintenancePolicy struct {
+	Message           string `json:"message,omitempty"`
+	RetryAfterSeconds int    `json:"retry_after_seconds,omitempty"` // Sent as Retry-After when set
+}
+
+// Validate checks the policy and applies the default message
+func (p *MaintenancePolicy) Validate() error {
+	p.Message = strings.TrimSpace(p.Message)
+	if p.Message == "" {
+		p.Message = DefaultMaintenanceMessage
+	}
+	if len(p.Message) > maxMaintenanceMessageLength {
+		return fmt.Errorf("message must be at most %d characters", maxMaintenanceMessageLength)
+	}
+	if p.RetryAfterSeconds < 0 || p.RetryAfterSeconds > 7*24*3600 {
+		return fmt.Errorf("retry_after_seconds must be between 0 and 604800")
+	}
+	return nil
+}
+
+// Page renders the maintenance page served for the tenant
+func (p *MaintenancePolicy) Page(businessName string) string {
+	title := "Down for maintenance"
+	if businessName != "" {
+		title = businessName + " is down for maintenance"
+	}
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>%s</title>
+<style>body{font-family:system-ui,sans-serif;display:flex;align-items:center;justify-content:center;min-height:100vh;margin:0;background:#f7f7f8;color:#1f2937}main{max-width:32rem;padding:2rem;text-align:center}h1{font-size:1.5rem}</style>
+</head>
+<body><main><h1>%s</h1><p>%s</p></main></body>
+</html>
+`, html.EscapeString(title), html.EscapeString(title), html.EscapeString(p.Message))
+}
+
+// RateLimitPolicy is a per-tenant request rate limit, applied as annotations on the tenant
+// VirtualServices for the mesh rate-limit configuration to pick up
+type RateLimitPolicy struct {
+	RequestsPerUnit int    `json:"requests_per_unit"`
+	Unit            string `json:"unit"`            // second, minute or hour
+	Burst           int    `json:"burst,omitempty"` // Extra requests allowed above the rate, 0 for none
+}
+
+// Validate checks the policy and normalizes the unit
+func (p *RateLimitPolicy) Validate() error {
+	p.Unit = strings.ToLower(strings.TrimSpace(p.Unit))
+	switch p.Unit {
+	case RateLimitUnitSecond, RateLimitUnitMinute, RateLimitUnitHour:
+	default:
+		return fmt.Errorf("unit must be second, minute or hour")
+	}
+	if p.RequestsPerUnit <= 0 {
+		return fmt.Errorf("requests_per_unit must be positive")
+	}
+	if p.Burst < 0 {
+		return fmt.Errorf("burst must not be negative")
+	}
+	return nil
+}
+
+// Maintenance returns the tenant's maintenance policy, or nil when it is not in maintenance
+func (t *TenantHostRecord) Maintenance() *MaintenancePolicy {
+	if !t.MaintenanceMode {
+		return nil
+	}
+	return &MaintenancePolicy{Message: t.MaintenanceMessage, RetryAfterSeconds: t.MaintenanceRetryAfter}
+}
+
+// RateLimit returns the tenant's rate limit, or nil when the platform default applies
+func (t *TenantHostRecord) RateLimit() *RateLimitPolicy {
+	if t.RateLimitRequests <= 0 {
+		return nil
+	}
+	return &RateLimitPolicy{RequestsPerUnit: t.RateLimitRequests, Unit: t.RateLimitUnit, Burst: t.RateLimitBurst}
+}
+
+// maxSubsetWeights bounds the number of destination subsets a tenant's traffic is split between
+const maxSubsetWeights = 5
+
+// subsetNameRegex matches Istio DestinationRule subset names
+var subsetNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// SubsetWeight is the share of a tenant's requests sent to one destination subset, e.g. during
+// a blue/green cutover between backend versions
+type SubsetWeight struct {
+	Subset string `json:"subset"` // Subset defined in the backend's DestinationRule
+	Weight int    `json:"weight"` // Percent of requests; the weights of a split add up to 100
+}
+
+// ValidateSubsetWeights checks a traffic split between destination subsets
+func ValidateSubsetWeights(weights []SubsetWeight) error {
+	if len(weights) < 2 || len(weights) > maxSubsetWeights {
+		return fmt.Errorf("traffic must be split between 2 and %d subsets", maxSubsetWeights)
+	}
+	seen := make(map[string]bool, len(weights))
+	total := 0
+	for _, w := range weights {
+		if len(w.Subset) > 63 || !subsetNameRegex.MatchString(w.Subset) {
+			return fmt.Errorf("invalid subset name %q", w.Subset)
+		}
+		if seen[w.Subset] {
+			return fmt.Errorf("duplicate subset %q", w.Subset)
+		}
+		seen[w.Subset] = true
+		if w.Weight < 0 || w.Weight > 100 {
+			return fmt.Errorf("weight of subset %q must be between 0 and 100", w.Subset)
+		}
+		total += w.Weight
+	}
+	if total != 100 {
+		return fmt.Errorf("weights must add up to 100, got %d", total)
+	}
+	return nil
+}
+
+// FormatSubsetWeights encodes a traffic split as "blue=90,green=10"; no weights encode as ""
+func FormatSubsetWeights(weights []SubsetWeight) string {
+	parts := make([]string, 0, len(weights))
+	for _, w := range weights {
+		parts = append(parts, fmt.Sprintf("%s=%d", w.Subset, w.Weight))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseSubsetWeights decodes a traffic split encoded by FormatSubsetWeights
+func ParseSubsetWeights(s string) ([]SubsetWeight, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var weights []SubsetWeight
+	for _, part := range strings.Split(s, ",") {
+		subset, weight, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid subset weight %q", part)
+		}
+		n, err := strconv.Atoi(weight)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subset weight %q", part)
+		}
+		weights = append(weights, SubsetWeight{Subset: subset, Weight: n})
+	}
+	return weights, nil
+}
+
+// SubsetWeights returns the tenant's traffic split, or nil when all traffic goes to the
+// destinations of the template routes
+func (t *TenantHostRecord) SubsetWeights() []SubsetWeight {
+	weights, err := ParseSubsetWeights(t.TrafficWeights)
+	if err != nil {
+		return nil
+	}
+	return weights
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaintenancePolicyValidate(t *testing.T) {
+	policy := MaintenancePolicy{Message: "  "}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.Message != DefaultMaintenanceMessage {
+		t.Errorf("expected default message, got %q", policy.Message)
+	}
+
+	policy = MaintenancePolicy{Message: strings.Repeat("a", maxMaintenanceMessageLength+1)}
+	if err := policy.Validate(); err == nil {
+		t.Error("expected error for long message")
+	}
+
+	policy = MaintenancePolicy{RetryAfterSeconds: -1}
+	if err := policy.Validate(); err == nil {
+		t.Error("expected error for negative retry_after_seconds")
+	}
+}
+
+func TestMaintenancePolicyPage(t *testing.T) {
+	policy := MaintenancePolicy{Message: "Back at <b>10:00</b>"}
+	page := policy.Page("Acme & Co")
+
+	if !strings.Contains(page, "<title>Acme &amp; Co is down for maintenance</title>") {
+		t.Errorf("expected escaped business name in title, got:\n%s", page)
+	}
+	if !strings.Contains(page, "Back at &lt;b&gt;10:00&lt;/b&gt;") {
+		t.Errorf("expected escaped message, got:\n%s", page)
+	}
+	if !strings.Contains(policy.Page(""), "<title>Down for maintenance</title>") {
+		t.Error("expected generic title without business name")
+	}
+}
+
+func TestRateLimitPolicyValidate(t *testing.T) {
+	policy := RateLimitPolicy{RequestsPerUnit: 100, Unit: " Minute "}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.Unit != RateLimitUnitMinute {
+		t.Errorf("expected unit to be normalized, got %q", policy.Unit)
+	}
+
+	invalid := []RateLimitPolicy{
+		{RequestsPerUnit: 100, Unit: "day"},
+		{RequestsPerUnit: 0, Unit: RateLimitUnitSecond},
+		{RequestsPerUnit: 10, Unit: RateLimitUnitSecond, Burst: -1},
+	}
+	for _, policy := range invalid {
+		if err := policy.Validate(); err == nil {
+			t.Errorf("expected error for %+v", policy)
+		}
+	}
+}
+
+func TestTenantHostRecordTrafficPolicy(t *testing.T) {
+	record := &TenantHostRecord{}
+	if record.Maintenance() != nil || record.RateLimit() != nil {
+		t.Error("expected no traffic policy by default")
+	}
+
+	record.MaintenanceMode = true
+	record.MaintenanceMessage = "Upgrading"
+	record.RateLimitRequests = 50
+	record.RateLimitUnit = RateLimitUnitSecond
+	if m := record.Maintenance(); m == nil || m.Message != "Upgrading" {
+		t.Errorf("unexpected maintenance policy %+v", m)
+	}
+	if r := record.RateLimit(); r == nil || r.RequestsPerUnit != 50 || r.Unit != RateLimitUnitSecond {
+		t.Errorf("unexpected rate limit %+v", r)
+	}
+}
+
+func TestValidateSubsetWeights(t *testing.T) {
+	valid := []SubsetWeight{{Subset: "blue", Weight: 90}, {Subset: "green", Weight: 10}}
+	if err := ValidateSubsetWeights(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	invalid := [][]SubsetWeight{
+		{{Subset: "blue", Weight: 100}},
+		{{Subset: "blue", Weight: 90}, {Subset: "green", Weight: 20}},
+		{{Subset: "blue", Weight: 50}, {Subset: "blue", Weight: 50}},
+		{{Subset: "Blue", Weight: 50}, {Subset: "green", Weight: 50}},
+		{{Subset: "blue", Weight: 110}, {Subset: "green", Weight: -10}},
+	}
+	for _, weights := range invalid {
+		if err := ValidateSubsetWeights(weights); err == nil {
+			t.Errorf("expected error for %+v", weights)
+		}
+	}
+}
+
+func TestSubsetWeightsRoundTrip(t *testing.T) {
+	weights := []SubsetWeight{{Subset: "blue", Weight: 90}, {Subset: "green", Weight: 10}}
+	encoded := FormatSubsetWeights(weights)
+	if encoded != "blue=90,green=10" {
+		t.Errorf("unexpected encoding %q", encoded)
+	}
+
+	record := &TenantHostRecord{TrafficWeights: encoded}
+	decoded := record.SubsetWeights()
+	if len(decoded) != 2 || decoded[0] != weights[0] || decoded[1] != weights[1] {
+		t.Errorf("expected %+v, got %+v", weights, decoded)
+	}
+
+	if weights, err := ParseSubsetWeights(""); err != nil || weights != nil {
+		t.Errorf("expected no weights for empty string, got %+v, %v", weights, err)
+	}
+	if _, err := ParseSubsetWeights("blue=ninety"); err == nil {
+		t.Error("expected error for invalid weight")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"tenant-router-service/internal/config"
 	"tenant-router-service/internal/models"
 	"tenant-router-service/internal/reconciler"
+	"tenant-router-service/internal/repository"
 )
 
 // Event subjects
@@ -19,6 +20,20 @@ const (
 	SubjectTenantCreated = "tenant.created"
 	SubjectTenantDeleted = "tenant.deleted"
 	StreamName           = "TENANT_EVENTS"
+
+	// SubjectTenantSlugChanged and SubjectTenantSlugReleased track tenant renames: the new slug
+	// is provisioned on change, the old slug's routes removed once its redirect expired
+	SubjectTenantSlugChanged  = "tenant.slug.changed"
+	SubjectTenantSlugReleased = "tenant.slug.released"
+
+	// SubjectProvisioningVerified is published once all hosts of a provisioned tenant answer over HTTPS
+	SubjectProvisioningVerified = "provisioning.verified"
+
+	// Custom domain events published by custom-domain-service: active domains (including
+	// wildcards such as *.shop.example.com) are registered as routes, removed ones unregistered
+	SubjectDomainActivated = "domain.activated"
+	SubjectDomainRemoved   = "domain.removed"
+	DomainStreamName       = "DOMAIN_EVENTS"
 )
 
 // Subscriber handles NATS JetStream subscriptions
@@ -28,6 +43,9 @@ type Subscriber struct {
 	reconciler *reconciler.TenantReconciler
 	config     *config.Config
 	subs       []*nats.Subscription
+
+	// domainRoutes stores custom domain routes; domain events are only consumed when set
+	domainRoutes repository.DomainRouteRepository
 }
 
 // NewSubscriber creates a new NATS subscriber
@@ -112,6 +130,11 @@ func NewSubscriber(cfg *config.Config, reconciler *reconciler.TenantReconciler)
 	}, nil
 }
 
+// SetDomainRoutes enables registering custom domain routes from domain events
+func (s *Subscriber) SetDomainRoutes(routes repository.DomainRouteRepository) {
+	s.domainRoutes = routes
+}
+
 // Start begins subscribing to tenant events
 func (s *Subscriber) Start(ctx context.Context) error {
 	log.Printf("[NATS] Starting subscriptions...")
@@ -164,6 +187,27 @@ func (s *Subscriber) Start(ctx context.Context) error {
 	s.subs = append(s.subs, sub)
 	log.Printf("[NATS] Subscribed to tenant.> events on stream %s with queue group tenant-router-workers", StreamName)
 
+	// Custom domain routes are optional: the stream belongs to custom-domain-service and may not exist yet
+	if s.domainRoutes != nil {
+		domainSub, err := s.js.QueueSubscribe(
+			"domain.>",
+			"tenant-router-workers",
+			s.handleDomainEvent,
+			nats.Durable("tenant-router-domain-consumer"),
+			nats.DeliverNew(),
+			nats.ManualAck(),
+			nats.AckWait(30*time.Second),
+			nats.MaxDeliver(5),
+			nats.BindStream(DomainStreamName),
+		)
+		if err != nil {
+			log.Printf("[NATS] Warning: Failed to subscribe to domain events: %v (custom domain routes will not be registered)", err)
+		} else {
+			s.subs = append(s.subs, domainSub)
+			log.Printf("[NATS] Subscribed to domain.> events on stream %s with queue group tenant-router-workers", DomainStreamName)
+		}
+	}
+
 	log.Printf("[NATS] All subscriptions started")
 	return nil
 }
@@ -178,6 +222,10 @@ func (s *Subscriber) handleTenantEvent(msg *nats.Msg) {
 		s.handleTenantCreated(msg)
 	case SubjectTenantDeleted:
 		s.handleTenantDeleted(msg)
+	case SubjectTenantSlugChanged:
+		s.handleTenantSlugChanged(msg)
+	case SubjectTenantSlugReleased:
+		s.handleTenantSlugReleased(msg)
 	default:
 		// Ignore other tenant events (tenant.updated, tenant.verified, etc.)
 		log.Printf("[NATS] Ignoring event on subject: %s", subject)
@@ -237,6 +285,102 @@ func (s *Subscriber) handleTenantDeleted(msg *nats.Msg) {
 	msg.Ack()
 }
 
+// handleTenantSlugChanged processes tenant.slug.changed events
+func (s *Subscriber) handleTenantSlugChanged(msg *nats.Msg) {
+	log.Printf("[NATS] Received %s event", SubjectTenantSlugChanged)
+
+	var event models.TenantSlugChangedEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Printf("[NATS] Failed to unmarshal tenant.slug.changed event: %v", err)
+		msg.Ack()
+		return
+	}
+
+	log.Printf("[NATS] Processing tenant.slug.changed: %s -> %s tenant_id=%s", event.OldSlug, event.NewSlug, event.TenantID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.reconciler.EnqueueSlugChange(ctx, &event); err != nil {
+		log.Printf("[NATS] Failed to enqueue slug change for %s: %v", event.NewSlug, err)
+		msg.Nak()
+		return
+	}
+
+	log.Printf("[NATS] Enqueued tenant.slug.changed for %s", event.NewSlug)
+	msg.Ack()
+}
+
+// handleTenantSlugReleased processes tenant.slug.released events
+func (s *Subscriber) handleTenantSlugReleased(msg *nats.Msg) {
+	log.Printf("[NATS] Received %s event", SubjectTenantSlugReleased)
+
+	var event models.TenantSlugReleasedEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Printf("[NATS] Failed to unmarshal tenant.slug.released event: %v", err)
+		msg.Ack()
+		return
+	}
+
+	log.Printf("[NATS] Processing tenant.slug.released: slug=%s tenant_id=%s", event.Slug, event.TenantID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.reconciler.EnqueueSlugRelease(ctx, &event); err != nil {
+		log.Printf("[NATS] Failed to enqueue slug release for %s: %v", event.Slug, err)
+		msg.Nak()
+		return
+	}
+
+	log.Printf("[NATS] Enqueued tenant.slug.released for %s", event.Slug)
+	msg.Ack()
+}
+
+// handleDomainEvent registers and unregisters custom domain routes
+func (s *Subscriber) handleDomainEvent(msg *nats.Msg) {
+	switch msg.Subject {
+	case SubjectDomainActivated, SubjectDomainRemoved:
+	default:
+		// Other lifecycle steps (domain.added, domain.verified, ...) do not change routing
+		msg.Ack()
+		return
+	}
+
+	var event models.CustomDomainEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Printf("[NATS] Failed to unmarshal %s event: %v", msg.Subject, err)
+		msg.Ack()
+		return
+	}
+	if event.DomainID == "" || event.Domain == "" {
+		log.Printf("[NATS] Ignoring %s event without domain", msg.Subject)
+		msg.Ack()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if msg.Subject == SubjectDomainRemoved {
+		if err := s.domainRoutes.DeleteByDomainID(ctx, event.DomainID); err != nil {
+			log.Printf("[NATS] Failed to remove route for %s: %v", event.Domain, err)
+			msg.Nak()
+			return
+		}
+		log.Printf("[NATS] Removed route for %s (tenant %s)", event.Domain, event.TenantSlug)
+		msg.Ack()
+		return
+	}
+
+	route := event.Route()
+	if err := s.domainRoutes.Upsert(ctx, route); err != nil {
+		log.Printf("[NATS] Failed to register route for %s: %v", event.Domain, err)
+		msg.Nak()
+		return
+	}
+	log.Printf("[NATS] Registered route %s -> %s (wildcard=%t)", route.Pattern, route.TenantSlug, route.IsWildcard)
+	msg.Ack()
+}
+
 // Stop stops all subscriptions gracefully
 // This is called during shutdown to properly release the consumer binding
 func (s *Subscriber) Stop() error {
@@ -269,6 +413,20 @@ func (s *Subscriber) Stop() error {
 	return nil
 }
 
+// PublishProvisioningVerified publishes a provisioning.verified event
+// The subject is outside the TENANT_EVENTS stream, so it goes out over core NATS
+func (s *Subscriber) PublishProvisioningVerified(ctx context.Context, event *models.ProvisioningVerifiedEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := s.conn.Publish(SubjectProvisioningVerified, data); err != nil {
+		return fmt.Errorf("failed to publish %s: %w", SubjectProvisioningVerified, err)
+	}
+	log.Printf("[NATS] Published %s for %s", SubjectProvisioningVerified, event.Slug)
+	return nil
+}
+
 // IsConnected returns true if connected to NATS
 func (s *Subscriber) IsConnected() bool {
 	return s.conn != nil && s.conn.IsConnected()
@@ -13,6 +13,7 @@ import (
 	"tenant-router-service/internal/config"
 	"tenant-router-service/internal/k8s"
 	"tenant-router-service/internal/keycloak"
+	"tenant-router-service/internal/metrics"
 	"tenant-router-service/internal/models"
 	"tenant-router-service/internal/repository"
 )
@@ -34,14 +35,16 @@ type Condition struct {
 
 // Conditions constants
 const (
-	ConditionCertificateReady        = "CertificateReady"
-	ConditionGatewayConfigured       = "GatewayConfigured"
-	ConditionAdminVSConfigured       = "AdminVSConfigured"
-	ConditionStorefrontConfigured    = "StorefrontVSConfigured"
-	ConditionStorefrontWwwConfigured = "StorefrontWwwVSConfigured"
-	ConditionAPIVSConfigured         = "APIVSConfigured"
-	ConditionAuthPolicyConfigured    = "AuthPolicyConfigured"
-	ConditionReady                   = "Ready"
+	ConditionCertificateReady          = "CertificateReady"
+	ConditionGatewayConfigured         = "GatewayConfigured"
+	ConditionAdminVSConfigured         = "AdminVSConfigured"
+	ConditionStorefrontConfigured      = "StorefrontVSConfigured"
+	ConditionStorefrontWwwConfigured   = "StorefrontWwwVSConfigured"
+	ConditionAPIVSConfigured           = "APIVSConfigured"
+	ConditionAuthPolicyConfigured      = "AuthPolicyConfigured"
+	ConditionTrafficWeightsConfigured  = "TrafficWeightsConfigured"
+	ConditionCanonicalPolicyConfigured = "CanonicalPolicyConfigured"
+	ConditionReady                     = "Ready"
 
 	StatusTrue    = "True"
 	StatusFalse   = "False"
@@ -62,6 +65,11 @@ type WorkItem struct {
 	Attempts  int
 }
 
+// HostProber verifies that the hosts of a provisioned tenant are reachable
+type HostProber interface {
+	ScheduleProbe(slug string)
+}
+
 // TenantReconciler reconciles tenant routing configuration
 // Follows Kubebuilder reconciler pattern with work queue and rate limiting
 type TenantReconciler struct {
@@ -69,6 +77,7 @@ type TenantReconciler struct {
 	keycloakClient *keycloak.Client
 	repo           repository.TenantHostRepository
 	config         *config.Config
+	hostProber     HostProber // optional - probes hosts over HTTPS after provisioning
 
 	// Work queue for processing events
 	workQueue  chan *WorkItem
@@ -128,9 +137,16 @@ func NewTenantReconciler(k8sClient *k8s.Client, keycloakClient *keycloak.Client,
 	}
 }
 
+// SetHostProber sets the prober that verifies hosts after provisioning
+func (r *TenantReconciler) SetHostProber(prober HostProber) {
+	r.hostProber = prober
+}
+
 // Start begins processing the work queue with specified number of workers
 func (r *TenantReconciler) Start(workers int) {
 	log.Printf("[Reconciler] Starting with %d workers", workers)
+	metrics.SetWorkers(workers)
+	metrics.SetQueueDepthFunc(func() int { return len(r.workQueue) })
 
 	for i := 0; i < workers; i++ {
 		r.wg.Add(1)
@@ -198,6 +214,7 @@ func (r *TenantReconciler) ReconcileOnStartup() {
 				if record.Status != models.HostStatusProvisioned {
 					log.Printf("[Reconciler] Marking %s as provisioned (all resources exist)", record.Slug)
 					r.repo.MarkProvisioned(ctx, record.Slug)
+					r.scheduleHostProbe(record.Slug)
 				}
 			}
 		}
@@ -279,6 +296,34 @@ func (r *TenantReconciler) EnqueueCreate(event *models.TenantCreatedEvent) error
 	}
 }
 
+// EnqueueCreateBatch queues create operations in the background. Unlike EnqueueCreate it waits
+// for room in the work queue instead of failing when it is full, so bulk imports larger than the
+// queue are provisioned in full.
+func (r *TenantReconciler) EnqueueCreateBatch(events []*models.TenantCreatedEvent) {
+	go func() {
+		for i, event := range events {
+			item := &WorkItem{
+				Key:       event.Slug,
+				Event:     event,
+				Operation: "create",
+				AddedAt:   time.Now(),
+				Attempts:  0,
+			}
+
+			select {
+			case r.workQueue <- item:
+				r.metrics.mu.Lock()
+				r.metrics.CurrentQueueDepth++
+				r.metrics.mu.Unlock()
+			case <-r.ctx.Done():
+				log.Printf("[Reconciler] Shutting down, %d of %d bulk creates not enqueued", len(events)-i, len(events))
+				return
+			}
+		}
+		log.Printf("[Reconciler] Enqueued %d bulk creates", len(events))
+	}()
+}
+
 // EnqueueDelete adds a delete operation to the work queue
 func (r *TenantReconciler) EnqueueDelete(event *models.TenantDeletedEvent) error {
 	item := &WorkItem{
@@ -303,6 +348,39 @@ func (r *TenantReconciler) EnqueueDelete(event *models.TenantDeletedEvent) error
 	}
 }
 
+// EnqueueSlugChange provisions routes for a renamed tenant's new slug. The old slug's record
+// and routes are left in place so its hosts keep working while tenant-service redirects it.
+func (r *TenantReconciler) EnqueueSlugChange(ctx context.Context, event *models.TenantSlugChangedEvent) error {
+	previous, err := r.repo.GetBySlug(ctx, event.OldSlug)
+	if err != nil {
+		return fmt.Errorf("failed to get record for %s: %w", event.OldSlug, err)
+	}
+	if previous != nil && previous.TenantID != event.TenantID {
+		log.Printf("[Reconciler] Record for %s belongs to tenant %s, not %s; provisioning %s without it",
+			event.OldSlug, previous.TenantID, event.TenantID, event.NewSlug)
+		previous = nil
+	}
+	return r.EnqueueCreate(event.CreatedEvent(previous))
+}
+
+// EnqueueSlugRelease removes the routes of a renamed tenant's former slug once it stopped
+// redirecting. A record since claimed by another tenant is left alone.
+func (r *TenantReconciler) EnqueueSlugRelease(ctx context.Context, event *models.TenantSlugReleasedEvent) error {
+	record, err := r.repo.GetBySlug(ctx, event.Slug)
+	if err != nil {
+		return fmt.Errorf("failed to get record for %s: %w", event.Slug, err)
+	}
+	if record == nil {
+		log.Printf("[Reconciler] No record found for released slug %s, nothing to delete", event.Slug)
+		return nil
+	}
+	if record.TenantID != event.TenantID {
+		log.Printf("[Reconciler] Released slug %s now belongs to tenant %s, not deleting", event.Slug, record.TenantID)
+		return nil
+	}
+	return r.EnqueueDelete(event.DeletedEvent())
+}
+
 // worker processes items from the work queue
 func (r *TenantReconciler) worker(id int) {
 	defer r.wg.Done()
@@ -366,6 +444,7 @@ func (r *TenantReconciler) processItem(workerID int, item *WorkItem) {
 	}
 
 	duration := time.Since(startTime)
+	metrics.ObserveReconcile(item.Key, item.Operation, duration, err)
 
 	r.metrics.mu.Lock()
 	r.metrics.ReconcileDuration = duration
@@ -387,6 +466,7 @@ func (r *TenantReconciler) processItem(workerID int, item *WorkItem) {
 			r.metrics.mu.Lock()
 			r.metrics.RetryCount++
 			r.metrics.mu.Unlock()
+			metrics.RecordRetry(item.Operation)
 
 			go func() {
 				select {
@@ -423,26 +503,6 @@ func (r *TenantReconciler) calculateBackoff(attempt int) time.Duration {
 func (r *TenantReconciler) reconcileCreate(ctx context.Context, event *models.TenantCreatedEvent) (ReconcileResult, error) {
 	log.Printf("[Reconciler] Reconciling create for %s (custom_domain=%v)", event.Slug, event.IsCustomDomain)
 
-	// Use host URLs from event (provided by tenant-service)
-	adminHost := event.AdminHost
-	storefrontHost := event.StorefrontHost
-	storefrontWwwHost := event.StorefrontWwwHost
-	apiHost := event.APIHost
-
-	// Fallback to config-based generation if not provided
-	domain := r.config.Domain.BaseDomain
-	if adminHost == "" || storefrontHost == "" {
-		adminHost = fmt.Sprintf("%s-admin.%s", event.Slug, domain)
-		storefrontHost = fmt.Sprintf("%s.%s", event.Slug, domain)
-	}
-
-	// API host for mobile/external access (use provided or generate based on slug)
-	if apiHost == "" {
-		apiHost = fmt.Sprintf("%s-api.%s", event.Slug, domain)
-	}
-
-	certName := fmt.Sprintf("%s-tenant-tls", event.Slug)
-
 	// Check if already exists in database (idempotency)
 	existing, err := r.repo.GetBySlug(ctx, event.Slug)
 	if err != nil {
@@ -459,21 +519,7 @@ func (r *TenantReconciler) reconcileCreate(ctx context.Context, event *models.Te
 		record = existing
 	} else {
 		// Create new record
-		record = &models.TenantHostRecord{
-			TenantID:          event.TenantID,
-			Slug:              event.Slug,
-			AdminHost:         adminHost,
-			StorefrontHost:    storefrontHost,
-			StorefrontWwwHost: storefrontWwwHost,
-			APIHost:           apiHost,
-			BaseDomain:        event.BaseDomain,
-			IsCustomDomain:    event.IsCustomDomain,
-			CertName:          certName,
-			Status:            models.HostStatusPending,
-			Product:           event.Product,
-			BusinessName:      event.BusinessName,
-			Email:             event.Email,
-		}
+		record = r.hostRecordFromEvent(event)
 		if err := r.repo.Create(ctx, record); err != nil {
 			return ReconcileResult{Requeue: true}, fmt.Errorf("failed to create record: %w", err)
 		}
@@ -512,16 +558,7 @@ func (r *TenantReconciler) reconcileCreate(ctx context.Context, event *models.Te
 		if record.IsCustomDomain {
 			// Custom domains get their own dedicated gateway for better isolation
 			// This avoids SNI conflicts and allows per-domain TLS certificates
-			domains := []string{record.StorefrontHost}
-			if record.AdminHost != "" && record.AdminHost != record.StorefrontHost {
-				domains = append(domains, record.AdminHost)
-			}
-			if record.StorefrontWwwHost != "" {
-				domains = append(domains, record.StorefrontWwwHost)
-			}
-			if record.APIHost != "" {
-				domains = append(domains, record.APIHost)
-			}
+			domains := customDomainHosts(record)
 
 			certSecretName := fmt.Sprintf("%s-tenant-tls", record.Slug)
 			gatewayName, err := r.k8sClient.CreateDedicatedGateway(ctx, record.Slug, domains, certSecretName)
@@ -691,6 +728,50 @@ func (r *TenantReconciler) reconcileCreate(ctx context.Context, event *models.Te
 		})
 	}
 
+	// 6b. Blue/green traffic split between destination subsets, if one is set
+	if len(record.SubsetWeights()) > 0 {
+		if err := r.reconcileTrafficWeights(ctx, record); err != nil {
+			conditions = append(conditions, Condition{
+				Type:               ConditionTrafficWeightsConfigured,
+				Status:             StatusFalse,
+				LastTransitionTime: time.Now(),
+				Reason:             ReasonFailed,
+				Message:            err.Error(),
+			})
+			r.updateConditions(ctx, record, conditions)
+			return ReconcileResult{Requeue: true, RequeueAfter: 30 * time.Second}, err
+		}
+		conditions = append(conditions, Condition{
+			Type:               ConditionTrafficWeightsConfigured,
+			Status:             StatusTrue,
+			LastTransitionTime: time.Now(),
+			Reason:             ReasonProvisioned,
+			Message:            fmt.Sprintf("Traffic split %s applied", record.TrafficWeights),
+		})
+	}
+
+	// 6c. HTTPS and www/apex redirects, if the tenant has a canonicalization policy
+	if !record.CanonicalPolicy().IsZero() {
+		if err := r.reconcileCanonicalPolicy(ctx, record); err != nil {
+			conditions = append(conditions, Condition{
+				Type:               ConditionCanonicalPolicyConfigured,
+				Status:             StatusFalse,
+				LastTransitionTime: time.Now(),
+				Reason:             ReasonFailed,
+				Message:            err.Error(),
+			})
+			r.updateConditions(ctx, record, conditions)
+			return ReconcileResult{Requeue: true, RequeueAfter: 30 * time.Second}, err
+		}
+		conditions = append(conditions, Condition{
+			Type:               ConditionCanonicalPolicyConfigured,
+			Status:             StatusTrue,
+			LastTransitionTime: time.Now(),
+			Reason:             ReasonProvisioned,
+			Message:            fmt.Sprintf("Canonical policy %s applied", record.CanonicalPolicy()),
+		})
+	}
+
 	// NOTE: For custom domain tenants, we NO LONGER create platform subdomain VirtualServices
 	// Custom domains use the dedicated custom-domain-gateway with direct A record access
 	// (LoadBalancer IP: 34.151.169.37). There's no need for CNAME targets on platform subdomains.
@@ -751,6 +832,8 @@ func (r *TenantReconciler) reconcileCreate(ctx context.Context, event *models.Te
 
 	if err := r.repo.MarkProvisioned(ctx, record.Slug); err != nil {
 		log.Printf("[Reconciler] Failed to mark as provisioned: %v", err)
+	} else {
+		r.scheduleHostProbe(record.Slug)
 	}
 
 	r.logActivity(ctx, record.ID, "reconcile_complete", "all", "", true, "", time.Duration(0))
@@ -758,6 +841,119 @@ func (r *TenantReconciler) reconcileCreate(ctx context.Context, event *models.Te
 	return ReconcileResult{}, nil
 }
 
+// hostRecordFromEvent builds the pending host record for a created tenant
+// Hosts missing from the event are generated from the slug and the configured base domain
+func (r *TenantReconciler) hostRecordFromEvent(event *models.TenantCreatedEvent) *models.TenantHostRecord {
+	// Use host URLs from event (provided by tenant-service)
+	adminHost := event.AdminHost
+	storefrontHost := event.StorefrontHost
+	apiHost := event.APIHost
+
+	// Fallback to config-based generation if not provided
+	domain := r.config.Domain.BaseDomain
+	if adminHost == "" || storefrontHost == "" {
+		adminHost = fmt.Sprintf("%s-admin.%s", event.Slug, domain)
+		storefrontHost = fmt.Sprintf("%s.%s", event.Slug, domain)
+	}
+
+	// API host for mobile/external access (use provided or generate based on slug)
+	if apiHost == "" {
+		apiHost = fmt.Sprintf("%s-api.%s", event.Slug, domain)
+	}
+
+	return &models.TenantHostRecord{
+		TenantID:          event.TenantID,
+		Slug:              event.Slug,
+		AdminHost:         adminHost,
+		StorefrontHost:    storefrontHost,
+		StorefrontWwwHost: event.StorefrontWwwHost,
+		APIHost:           apiHost,
+		BaseDomain:        event.BaseDomain,
+		IsCustomDomain:    event.IsCustomDomain,
+		CertName:          fmt.Sprintf("%s-tenant-tls", event.Slug),
+		Status:            models.HostStatusPending,
+		Product:           event.Product,
+		BusinessName:      event.BusinessName,
+		Email:             event.Email,
+		ForceHTTPS:        event.ForceHTTPS,
+		PreferredHost:     event.PreferredHost,
+	}
+}
+
+// customDomainHosts returns the hosts served by a custom domain tenant's certificate and dedicated gateway
+func customDomainHosts(record *models.TenantHostRecord) []string {
+	domains := []string{record.StorefrontHost}
+	if record.AdminHost != "" && record.AdminHost != record.StorefrontHost {
+		domains = append(domains, record.AdminHost)
+	}
+	if record.StorefrontWwwHost != "" {
+		domains = append(domains, record.StorefrontWwwHost)
+	}
+	if record.APIHost != "" {
+		domains = append(domains, record.APIHost)
+	}
+	return domains
+}
+
+// RenderCreate renders the Kubernetes resources reconcileCreate would apply for the event,
+// in the same order and with the same custom domain and gateway decisions, without writing
+// to the cluster or the database. Templates and the shared Gateway are read from the cluster.
+// Every resource is rendered, including ones an existing tenant already has.
+func (r *TenantReconciler) RenderCreate(ctx context.Context, event *models.TenantCreatedEvent) ([]k8s.Manifest, error) {
+	record := r.hostRecordFromEvent(event)
+	manifests := make([]k8s.Manifest, 0, 7)
+
+	// 1. Certificate and 2. Gateway
+	var dedicatedGatewayName string
+	if record.IsCustomDomain {
+		domains := customDomainHosts(record)
+		dedicatedGatewayName = fmt.Sprintf("%s-gateway", record.Slug)
+		manifests = append(manifests,
+			r.k8sClient.RenderCertificate(record.Slug, domains, true),
+			r.k8sClient.RenderDedicatedGateway(record.Slug, domains, record.CertName),
+			r.k8sClient.RenderDedicatedAuthorizationPolicy(record.Slug, domains),
+		)
+	} else {
+		manifests = append(manifests, r.k8sClient.RenderCertificate(record.Slug, []string{record.AdminHost, record.StorefrontHost}, false))
+		if !r.config.Kubernetes.SkipGatewayPatch {
+			gateway, err := r.k8sClient.RenderGatewayServerPatch(ctx, record.Slug, record.AdminHost, record.StorefrontHost)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, gateway)
+		}
+	}
+
+	// 3-6. Admin, storefront, storefront www and API VirtualServices
+	type virtualService struct {
+		templateVSName    string
+		host              string
+		nameSuffix        string
+		cloudflareProxied bool
+	}
+	virtualServices := []virtualService{
+		{r.config.Kubernetes.AdminVSName, record.AdminHost, "", !record.IsCustomDomain},
+		{r.config.Kubernetes.StorefrontVSName, record.StorefrontHost, "", !record.IsCustomDomain},
+	}
+	if record.StorefrontWwwHost != "" {
+		virtualServices = append(virtualServices, virtualService{r.config.Kubernetes.StorefrontVSName, record.StorefrontWwwHost, "www", false})
+	}
+	virtualServices = append(virtualServices, virtualService{r.config.Kubernetes.APIVSName, record.APIHost, "", !record.IsCustomDomain})
+
+	for _, vs := range virtualServices {
+		manifest, err := r.k8sClient.RenderTenantVirtualService(ctx, record.Slug, record.TenantID, vs.templateVSName, vs.host,
+			record.AdminHost, record.StorefrontHost, vs.nameSuffix, vs.cloudflareProxied, record.IsCustomDomain, dedicatedGatewayName)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+	}
+	r.k8sClient.RenderTenantCanonicalPolicy(manifests, record.Slug, record.StorefrontHost, record.StorefrontWwwHost,
+		record.IsCustomDomain, record.CanonicalPolicy())
+
+	return manifests, nil
+}
+
 // reconcileDelete handles tenant deletion
 func (r *TenantReconciler) reconcileDelete(ctx context.Context, event *models.TenantDeletedEvent) (ReconcileResult, error) {
 	log.Printf("[Reconciler] Reconciling delete for %s", event.Slug)
@@ -911,10 +1107,51 @@ func (r *TenantReconciler) ensureState(ctx context.Context, record *models.Tenan
 		return r.reconcileCreate(ctx, event)
 	}
 
+	// Render the traffic split (or its removal) onto the existing VirtualServices
+	if err := r.reconcileTrafficWeights(ctx, record); err != nil {
+		return ReconcileResult{Requeue: true, RequeueAfter: 30 * time.Second}, err
+	}
+
+	// Render the canonical redirects (or their removal) onto the existing VirtualServices
+	if err := r.reconcileCanonicalPolicy(ctx, record); err != nil {
+		return ReconcileResult{Requeue: true, RequeueAfter: 30 * time.Second}, err
+	}
+
 	log.Printf("[Reconciler] %s state verified - all K8s resources exist", record.Slug)
 	return ReconcileResult{}, nil
 }
 
+// reconcileTrafficWeights renders the tenant's traffic split as weighted HTTPRoute destinations
+// on its VirtualServices. VirtualServices already carrying the split are not updated.
+func (r *TenantReconciler) reconcileTrafficWeights(ctx context.Context, record *models.TenantHostRecord) error {
+	startTime := time.Now()
+	updated, err := r.k8sClient.ApplyTenantTrafficWeights(ctx, record.Slug, record.StorefrontWwwHost != "", record.SubsetWeights())
+	if err != nil {
+		r.logActivity(ctx, record.ID, "apply_traffic_weights", "VirtualService", "", false, err.Error(), time.Since(startTime))
+		return err
+	}
+	if len(updated) > 0 {
+		r.logActivity(ctx, record.ID, "apply_traffic_weights", "VirtualService", "", true, "", time.Since(startTime))
+	}
+	return nil
+}
+
+// reconcileCanonicalPolicy renders the tenant's canonicalization policy as redirect routes on
+// its VirtualServices. VirtualServices already carrying the policy are not updated.
+func (r *TenantReconciler) reconcileCanonicalPolicy(ctx context.Context, record *models.TenantHostRecord) error {
+	startTime := time.Now()
+	updated, err := r.k8sClient.ApplyTenantCanonicalPolicy(ctx, record.Slug, record.StorefrontHost, record.StorefrontWwwHost,
+		record.IsCustomDomain, record.CanonicalPolicy())
+	if err != nil {
+		r.logActivity(ctx, record.ID, "apply_canonical_policy", "VirtualService", "", false, err.Error(), time.Since(startTime))
+		return err
+	}
+	if len(updated) > 0 {
+		r.logActivity(ctx, record.ID, "apply_canonical_policy", "VirtualService", "", true, "", time.Since(startTime))
+	}
+	return nil
+}
+
 // reconcileCertificate creates or verifies the certificate
 // For custom domains, certificates are created in the custom domain gateway namespace (istio-ingress)
 // using HTTP-01 challenge with Let's Encrypt. For default domains, the wildcard cert is used.
@@ -926,16 +1163,7 @@ func (r *TenantReconciler) reconcileCertificate(ctx context.Context, record *mod
 	if record.IsCustomDomain {
 		// Custom domain: create certificate in istio-ingress namespace with HTTP-01 challenge
 		// Collect all custom domain hosts for the certificate
-		domains := []string{record.StorefrontHost}
-		if record.AdminHost != "" && record.AdminHost != record.StorefrontHost {
-			domains = append(domains, record.AdminHost)
-		}
-		if record.StorefrontWwwHost != "" {
-			domains = append(domains, record.StorefrontWwwHost)
-		}
-		if record.APIHost != "" {
-			domains = append(domains, record.APIHost)
-		}
+		domains := customDomainHosts(record)
 
 		err = r.k8sClient.CreateCustomDomainCertificate(ctx, record.Slug, domains)
 		namespace = r.config.Kubernetes.CustomDomainGatewayNS
@@ -1114,6 +1342,13 @@ func (r *TenantReconciler) updateConditions(ctx context.Context, record *models.
 	}
 }
 
+// scheduleHostProbe queues an HTTPS reachability probe of a freshly provisioned tenant
+func (r *TenantReconciler) scheduleHostProbe(slug string) {
+	if r.hostProber != nil {
+		r.hostProber.ScheduleProbe(slug)
+	}
+}
+
 // logActivity logs a provisioning activity
 func (r *TenantReconciler) logActivity(ctx context.Context, tenantHostID uuid.UUID, action, resource, namespace string, success bool, errorMsg string, duration time.Duration) {
 	activityLog := &models.ProvisioningActivityLog{
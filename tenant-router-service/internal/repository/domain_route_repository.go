@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tenant-router-service/internal/models"
+)
+
+// DomainRouteRepository defines the interface for custom domain route data access
+type DomainRouteRepository interface {
+	Upsert(ctx context.Context, route *models.DomainRoute) error
+	DeleteByDomainID(ctx context.Context, domainID string) error
+	Resolve(ctx context.Context, host string) (*models.DomainRoute, error)
+	ListByTenantID(ctx context.Context, tenantID string) ([]models.DomainRoute, error)
+}
+
+// domainRouteRepository implements DomainRouteRepository
+type domainRouteRepository struct {
+	db *gorm.DB
+}
+
+// NewDomainRouteRepository creates a new DomainRouteRepository
+func NewDomainRouteRepository(db *gorm.DB) DomainRouteRepository {
+	return &domainRouteRepository{db: db}
+}
+
+// Upsert registers a route, replacing the one stored for the same domain
+// A pattern left behind by a removed domain is handed over to the new domain.
+func (r *domainRouteRepository) Upsert(ctx context.Context, route *models.DomainRoute) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("pattern = ? AND domain_id <> ?", route.Pattern, route.DomainID).
+			Delete(&models.DomainRoute{}).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "domain_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"pattern", "is_wildcard", "tenant_id", "tenant_slug", "target_type", "is_primary", "updated_at"}),
+		}).Create(route).Error
+	})
+}
+
+// DeleteByDomainID removes the route of a domain
+func (r *domainRouteRepository) DeleteByDomainID(ctx context.Context, domainID string) error {
+	return r.db.WithContext(ctx).Where("domain_id = ?", domainID).Delete(&models.DomainRoute{}).Error
+}
+
+// Resolve returns the route serving host, or nil if there is none
+// An exact route wins over a wildcard covering the same host.
+func (r *domainRouteRepository) Resolve(ctx context.Context, host string) (*models.DomainRoute, error) {
+	host = models.NormalizeHost(host)
+	patterns := []string{host}
+	if wildcard := models.WildcardPatternFor(host); wildcard != "" {
+		patterns = append(patterns, wildcard)
+	}
+
+	var routes []models.DomainRoute
+	if err := r.db.WithContext(ctx).Where("pattern IN ?", patterns).Find(&routes).Error; err != nil {
+		return nil, err
+	}
+
+	var match *models.DomainRoute
+	for i := range routes {
+		if !routes[i].Matches(host) {
+			continue
+		}
+		if match == nil || match.IsWildcard {
+			match = &routes[i]
+		}
+	}
+	return match, nil
+}
+
+// ListByTenantID lists the routes of a tenant's custom domains
+func (r *domainRouteRepository) ListByTenantID(ctx context.Context, tenantID string) ([]models.DomainRoute, error) {
+	var routes []models.DomainRoute
+	err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("pattern").Find(&routes).Error
+	return routes, err
+}
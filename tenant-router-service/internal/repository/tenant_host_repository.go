@@ -35,6 +35,16 @@ type TenantHostRepository interface {
 	MarkProvisioned(ctx context.Context, slug string) error
 	MarkFailed(ctx context.Context, slug string, errorMsg string) error
 
+	// Reachability probes
+	ListUnverified(ctx context.Context, maxAttempts int) ([]models.TenantHostRecord, error)
+	UpdateReachability(ctx context.Context, slug string, status models.ReachabilityStatus, results string) error
+
+	// Traffic policy
+	SetMaintenance(ctx context.Context, slug string, policy *models.MaintenancePolicy) error
+	SetRateLimit(ctx context.Context, slug string, policy *models.RateLimitPolicy) error
+	SetTrafficWeights(ctx context.Context, slug string, weights []models.SubsetWeight) error
+	SetCanonicalPolicy(ctx context.Context, slug string, policy models.CanonicalPolicy) error
+
 	// Activity logging
 	LogActivity(ctx context.Context, log *models.ProvisioningActivityLog) error
 	GetActivityLogs(ctx context.Context, tenantHostID uuid.UUID, limit int) ([]models.ProvisioningActivityLog, error)
@@ -254,9 +264,11 @@ func (r *tenantHostRepository) MarkProvisioned(ctx context.Context, slug string)
 		Model(&models.TenantHostRecord{}).
 		Where("slug = ?", slug).
 		Updates(map[string]interface{}{
-			"status":         models.HostStatusProvisioned,
-			"provisioned_at": now,
-			"last_error":     "",
+			"status":                models.HostStatusProvisioned,
+			"provisioned_at":        now,
+			"last_error":            "",
+			"reachability_status":   models.ReachabilityUnchecked,
+			"reachability_attempts": 0,
 		}).Error
 }
 
@@ -274,6 +286,93 @@ func (r *tenantHostRepository) MarkFailed(ctx context.Context, slug string, erro
 		UpdateColumn("retry_count", gorm.Expr("retry_count + 1")).Error
 }
 
+// ListUnverified retrieves provisioned records whose hosts have not passed a reachability probe yet
+// Records that failed maxAttempts probes are left alone until they are provisioned again
+func (r *tenantHostRepository) ListUnverified(ctx context.Context, maxAttempts int) ([]models.TenantHostRecord, error) {
+	var records []models.TenantHostRecord
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND reachability_status <> ? AND reachability_attempts < ?",
+			models.HostStatusProvisioned, models.ReachabilityVerified, maxAttempts).
+		Order("provisioned_at ASC").
+		Find(&records).Error
+	return records, err
+}
+
+// UpdateReachability stores the outcome of a reachability probe
+func (r *tenantHostRepository) UpdateReachability(ctx context.Context, slug string, status models.ReachabilityStatus, results string) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"reachability_status":     status,
+		"reachability_results":    results,
+		"reachability_checked_at": now,
+		"reachability_attempts":   gorm.Expr("reachability_attempts + 1"),
+	}
+	if status == models.ReachabilityVerified {
+		updates["reachability_verified_at"] = now
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.TenantHostRecord{}).
+		Where("slug = ?", slug).
+		Updates(updates).Error
+}
+
+// SetMaintenance stores the maintenance policy of a tenant; nil takes it out of maintenance
+func (r *tenantHostRepository) SetMaintenance(ctx context.Context, slug string, policy *models.MaintenancePolicy) error {
+	updates := map[string]interface{}{
+		"maintenance_mode":        false,
+		"maintenance_message":     "",
+		"maintenance_retry_after": 0,
+		"maintenance_since":       nil,
+	}
+	if policy != nil {
+		updates["maintenance_mode"] = true
+		updates["maintenance_message"] = policy.Message
+		updates["maintenance_retry_after"] = policy.RetryAfterSeconds
+		updates["maintenance_since"] = gorm.Expr("COALESCE(maintenance_since, ?)", time.Now())
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.TenantHostRecord{}).
+		Where("slug = ?", slug).
+		Updates(updates).Error
+}
+
+// SetRateLimit stores the rate limit of a tenant; nil restores the platform default
+func (r *tenantHostRepository) SetRateLimit(ctx context.Context, slug string, policy *models.RateLimitPolicy) error {
+	updates := map[string]interface{}{
+		"rate_limit_requests": 0,
+		"rate_limit_unit":     "",
+		"rate_limit_burst":    0,
+	}
+	if policy != nil {
+		updates["rate_limit_requests"] = policy.RequestsPerUnit
+		updates["rate_limit_unit"] = policy.Unit
+		updates["rate_limit_burst"] = policy.Burst
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.TenantHostRecord{}).
+		Where("slug = ?", slug).
+		Updates(updates).Error
+}
+
+// SetTrafficWeights stores the traffic split of a tenant; nil removes the split
+func (r *tenantHostRepository) SetTrafficWeights(ctx context.Context, slug string, weights []models.SubsetWeight) error {
+	return r.db.WithContext(ctx).
+		Model(&models.TenantHostRecord{}).
+		Where("slug = ?", slug).
+		Update("traffic_weights", models.FormatSubsetWeights(weights)).Error
+}
+
+// SetCanonicalPolicy stores the canonicalization policy of a tenant; a zero policy removes it
+func (r *tenantHostRepository) SetCanonicalPolicy(ctx context.Context, slug string, policy models.CanonicalPolicy) error {
+	return r.db.WithContext(ctx).
+		Model(&models.TenantHostRecord{}).
+		Where("slug = ?", slug).
+		Updates(map[string]interface{}{
+			"force_https":    policy.ForceHTTPS,
+			"preferred_host": policy.PreferredHost,
+		}).Error
+}
+
 // LogActivity logs a provisioning activity
 func (r *tenantHostRepository) LogActivity(ctx context.Context, log *models.ProvisioningActivityLog) error {
 	return r.db.WithContext(ctx).Create(log).Error
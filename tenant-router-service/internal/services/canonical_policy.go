@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"tenant-router-service/internal/models"
+)
+
+// SetCanonicalPolicy stores a tenant's canonicalization policy (force HTTPS, www or apex);
+// the reconciler renders it as redirect routes on the tenant's VirtualServices
+func (s *RouterService) SetCanonicalPolicy(ctx context.Context, slug string, policy models.CanonicalPolicy) (*models.TenantHostRecord, error) {
+	record, err := s.getTrafficPolicyRecord(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(record.StorefrontWwwHost); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrafficPolicy, err)
+	}
+	return s.saveCanonicalPolicy(ctx, slug, policy)
+}
+
+// ClearCanonicalPolicy removes a tenant's canonical redirects so every host serves its own requests again
+func (s *RouterService) ClearCanonicalPolicy(ctx context.Context, slug string) (*models.TenantHostRecord, error) {
+	if _, err := s.getTrafficPolicyRecord(ctx, slug); err != nil {
+		return nil, err
+	}
+	return s.saveCanonicalPolicy(ctx, slug, models.CanonicalPolicy{})
+}
+
+// saveCanonicalPolicy stores a tenant's canonicalization policy
+func (s *RouterService) saveCanonicalPolicy(ctx context.Context, slug string, policy models.CanonicalPolicy) (*models.TenantHostRecord, error) {
+	if err := s.repo.SetCanonicalPolicy(ctx, slug, policy); err != nil {
+		return nil, fmt.Errorf("failed to save canonical policy: %w", err)
+	}
+	log.Printf("[RouterService] Tenant %s canonical policy set to %q", slug, policy.String())
+	return s.repo.GetBySlug(ctx, slug)
+}
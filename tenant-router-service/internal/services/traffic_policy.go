@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"tenant-router-service/internal/models"
+)
+
+// ErrTenantHostNotFound is returned when no tenant host record exists for a slug
+var ErrTenantHostNotFound = errors.New("tenant host not found")
+
+// ErrInvalidTrafficPolicy is returned when a maintenance or rate limit policy is invalid
+var ErrInvalidTrafficPolicy = errors.New("invalid traffic policy")
+
+// SetMaintenanceMode puts a tenant into maintenance: all of its VirtualServices answer with a
+// 503 maintenance page. Calling it again while in maintenance updates the message.
+func (s *RouterService) SetMaintenanceMode(ctx context.Context, slug string, policy models.MaintenancePolicy) (*models.TenantHostRecord, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrafficPolicy, err)
+	}
+	record, err := s.getTrafficPolicyRecord(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyTrafficPolicy(ctx, record, &policy, record.RateLimit(), "enable_maintenance"); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetMaintenance(ctx, slug, &policy); err != nil {
+		return nil, fmt.Errorf("failed to save maintenance mode: %w", err)
+	}
+	log.Printf("[RouterService] Tenant %s is in maintenance mode", slug)
+	return s.repo.GetBySlug(ctx, slug)
+}
+
+// ClearMaintenanceMode takes a tenant out of maintenance
+func (s *RouterService) ClearMaintenanceMode(ctx context.Context, slug string) (*models.TenantHostRecord, error) {
+	record, err := s.getTrafficPolicyRecord(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyTrafficPolicy(ctx, record, nil, record.RateLimit(), "disable_maintenance"); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetMaintenance(ctx, slug, nil); err != nil {
+		return nil, fmt.Errorf("failed to save maintenance mode: %w", err)
+	}
+	log.Printf("[RouterService] Tenant %s is out of maintenance mode", slug)
+	return s.repo.GetBySlug(ctx, slug)
+}
+
+// SetRateLimit applies a per-tenant rate limit to the tenant's VirtualServices
+func (s *RouterService) SetRateLimit(ctx context.Context, slug string, policy models.RateLimitPolicy) (*models.TenantHostRecord, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrafficPolicy, err)
+	}
+	record, err := s.getTrafficPolicyRecord(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyTrafficPolicy(ctx, record, record.Maintenance(), &policy, "set_rate_limit"); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetRateLimit(ctx, slug, &policy); err != nil {
+		return nil, fmt.Errorf("failed to save rate limit: %w", err)
+	}
+	log.Printf("[RouterService] Tenant %s rate limited to %d requests per %s (burst %d)", slug, policy.RequestsPerUnit, policy.Unit, policy.Burst)
+	return s.repo.GetBySlug(ctx, slug)
+}
+
+// ClearRateLimit removes a tenant's rate limit so the platform default applies
+func (s *RouterService) ClearRateLimit(ctx context.Context, slug string) (*models.TenantHostRecord, error) {
+	record, err := s.getTrafficPolicyRecord(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyTrafficPolicy(ctx, record, record.Maintenance(), nil, "clear_rate_limit"); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetRateLimit(ctx, slug, nil); err != nil {
+		return nil, fmt.Errorf("failed to save rate limit: %w", err)
+	}
+	log.Printf("[RouterService] Tenant %s rate limit cleared", slug)
+	return s.repo.GetBySlug(ctx, slug)
+}
+
+// getTrafficPolicyRecord loads the record of a tenant whose traffic policy is being changed
+func (s *RouterService) getTrafficPolicyRecord(ctx context.Context, slug string) (*models.TenantHostRecord, error) {
+	record, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant record: %w", err)
+	}
+	if record == nil || record.Status == models.HostStatusDeleting || record.Status == models.HostStatusDeleted {
+		return nil, ErrTenantHostNotFound
+	}
+	return record, nil
+}
+
+// applyTrafficPolicy applies the full traffic policy to the tenant's VirtualServices. The
+// database is only updated by the caller once the cluster reflects the new policy.
+func (s *RouterService) applyTrafficPolicy(ctx context.Context, record *models.TenantHostRecord, maintenance *models.MaintenancePolicy, rateLimit *models.RateLimitPolicy, action string) error {
+	startTime := time.Now()
+	_, err := s.k8sClient.ApplyTenantTrafficPolicy(ctx, record.Slug, record.StorefrontWwwHost != "", record.BusinessName, maintenance, rateLimit)
+	if err != nil {
+		s.logActivity(ctx, record.ID, action, "VirtualService", "", false, err.Error(), time.Since(startTime))
+		return fmt.Errorf("failed to apply traffic policy: %w", err)
+	}
+	s.logActivity(ctx, record.ID, action, "VirtualService", "", true, "", time.Since(startTime))
+	return nil
+}
+
+// SetTrafficWeights stores a blue/green traffic split between destination subsets for a tenant;
+// the reconciler renders it as weighted routes on the tenant's VirtualServices
+func (s *RouterService) SetTrafficWeights(ctx context.Context, slug string, weights []models.SubsetWeight) (*models.TenantHostRecord, error) {
+	if err := models.ValidateSubsetWeights(weights); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrafficPolicy, err)
+	}
+	return s.saveTrafficWeights(ctx, slug, weights)
+}
+
+// ClearTrafficWeights removes a tenant's traffic split so all requests go to the template destinations
+func (s *RouterService) ClearTrafficWeights(ctx context.Context, slug string) (*models.TenantHostRecord, error) {
+	return s.saveTrafficWeights(ctx, slug, nil)
+}
+
+// saveTrafficWeights stores a tenant's traffic split
+func (s *RouterService) saveTrafficWeights(ctx context.Context, slug string, weights []models.SubsetWeight) (*models.TenantHostRecord, error) {
+	if _, err := s.getTrafficPolicyRecord(ctx, slug); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetTrafficWeights(ctx, slug, weights); err != nil {
+		return nil, fmt.Errorf("failed to save traffic weights: %w", err)
+	}
+	log.Printf("[RouterService] Tenant %s traffic weights set to %q", slug, models.FormatSubsetWeights(weights))
+	return s.repo.GetBySlug(ctx, slug)
+}
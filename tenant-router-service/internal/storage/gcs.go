@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	gcs "google.golang.org/api/storage/v1"
+)
+
+// ErrObjectNotFound is returned when an object does not exist in the bucket
+var ErrObjectNotFound = errors.New("object not found")
+
+// GCSStore reads and writes objects in a single Google Cloud Storage bucket
+// Credentials come from the pod's workload identity (application default credentials)
+type GCSStore struct {
+	service *gcs.Service
+	bucket  string
+	prefix  string
+}
+
+// NewGCSStore creates a store for objects under prefix in bucket
+func NewGCSStore(ctx context.Context, bucket, prefix string) (*GCSStore, error) {
+	if bucket == "" {
+		return nil, errors.New("bucket is required")
+	}
+
+	service, err := gcs.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	return &GCSStore{
+		service: service,
+		bucket:  bucket,
+		prefix:  prefix,
+	}, nil
+}
+
+// Put uploads an object, replacing any existing version
+func (s *GCSStore) Put(ctx context.Context, name string, data []byte, metadata map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	object := &gcs.Object{
+		Name:        s.objectName(name),
+		ContentType: "application/octet-stream",
+		Metadata:    metadata,
+	}
+	if _, err := s.service.Objects.Insert(s.bucket, object).Media(bytes.NewReader(data)).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", object.Name, err)
+	}
+	return nil
+}
+
+// Get downloads an object
+func (s *GCSStore) Get(ctx context.Context, name string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	resp, err := s.service.Objects.Get(s.bucket, s.objectName(name)).Context(ctx).Download()
+	if err != nil {
+		return nil, s.wrapError(name, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.objectName(name), err)
+	}
+	return data, nil
+}
+
+// Metadata returns the custom metadata of an object
+func (s *GCSStore) Metadata(ctx context.Context, name string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	object, err := s.service.Objects.Get(s.bucket, s.objectName(name)).Context(ctx).Do()
+	if err != nil {
+		return nil, s.wrapError(name, err)
+	}
+	return object.Metadata, nil
+}
+
+func (s *GCSStore) objectName(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	return fmt.Sprintf("%s/%s", s.prefix, name)
+}
+
+func (s *GCSStore) wrapError(name string, err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrObjectNotFound, s.objectName(name))
+	}
+	return fmt.Errorf("failed to get %s: %w", s.objectName(name), err)
+}
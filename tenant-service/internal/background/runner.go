@@ -15,6 +15,19 @@ type Runner struct {
 	draftSvc          *services.DraftService
 	deactivationSvc   *services.CustomerDeactivationService
 	reconciliationSvc *services.TenantReconciliationService
+	deletionSagaSvc   *services.TenantDeletionSagaService
+	keySvc            *services.TenantKeyService
+	staffSyncSvc      *services.StaffMembershipSyncService
+	webhookSvc        *services.WebhookDeliveryService
+	exportSvc         *services.TenantExportService
+	offboardingSvc    *services.OffboardingService
+	membershipSvc     *services.MembershipService
+	analyticsSvc      *services.OnboardingAnalyticsService
+	usageSvc          *services.UsageService
+	subscriptionSvc   *services.SubscriptionService
+	billingSvc        *services.BillingService
+	slugChangeSvc     *services.SlugChangeService
+	sessionAdminSvc   *services.OnboardingSessionAdminService
 	config            config.DraftConfig
 	stopCh            chan struct{}
 	wg                sync.WaitGroup
@@ -22,6 +35,22 @@ type Runner struct {
 	reminderTicker    *time.Ticker
 	purgeTicker       *time.Ticker         // For purging deactivated accounts
 	reconcileTicker   *time.Ticker         // For reconciling stuck tenants
+	deletionTicker    *time.Ticker         // For re-requesting unacknowledged tenant purges
+	reencryptTicker   *time.Ticker         // For re-encrypting credentials after key rotation
+	staffSyncTicker   *time.Ticker         // For reconciling staff-service records with memberships
+	webhookTicker     *time.Ticker         // For delivering and retrying webhook events
+	exportTicker      *time.Ticker         // For purging expired tenant exports and resuming stale ones
+	tenantPurgeTicker *time.Ticker         // For purging tenants whose deletion grace period elapsed
+	invitationTicker  *time.Ticker         // For purging expired and revoked member invitations
+	funnelTicker      *time.Ticker         // For refreshing the onboarding funnel summary
+	usageTicker       *time.Ticker         // For member quota checks and pruning metered event keys
+	trialTicker       *time.Ticker         // For moving tenants whose trial ended to the default plan
+	// For suspending tenants past the payment grace period
+	billingTicker *time.Ticker
+	// For releasing former slugs whose redirect expired
+	slugRedirectTicker *time.Ticker
+	// For purging onboarding sessions stuck before completion
+	sessionPurgeTicker *time.Ticker
 }
 
 // NewRunner creates a new background runner
@@ -43,6 +72,71 @@ func (r *Runner) SetReconciliationService(svc *services.TenantReconciliationServ
 	r.reconciliationSvc = svc
 }
 
+// SetDeletionSagaService sets the tenant deletion saga coordinator
+func (r *Runner) SetDeletionSagaService(svc *services.TenantDeletionSagaService) {
+	r.deletionSagaSvc = svc
+}
+
+// SetKeyService sets the tenant key service for credential re-encryption
+func (r *Runner) SetKeyService(svc *services.TenantKeyService) {
+	r.keySvc = svc
+}
+
+// SetStaffSyncService sets the staff membership reconciliation service
+func (r *Runner) SetStaffSyncService(svc *services.StaffMembershipSyncService) {
+	r.staffSyncSvc = svc
+}
+
+// SetWebhookDeliveryService sets the webhook delivery service for delivery and retry jobs
+func (r *Runner) SetWebhookDeliveryService(svc *services.WebhookDeliveryService) {
+	r.webhookSvc = svc
+}
+
+// SetExportService sets the tenant export service for archive retention jobs
+func (r *Runner) SetExportService(svc *services.TenantExportService) {
+	r.exportSvc = svc
+}
+
+// SetOffboardingService sets the offboarding service for purging tenants after their deletion grace period
+func (r *Runner) SetOffboardingService(svc *services.OffboardingService) {
+	r.offboardingSvc = svc
+}
+
+// SetMembershipService sets the membership service for invitation cleanup jobs
+func (r *Runner) SetMembershipService(svc *services.MembershipService) {
+	r.membershipSvc = svc
+}
+
+// SetOnboardingAnalyticsService sets the onboarding analytics service for funnel summary refreshes
+func (r *Runner) SetOnboardingAnalyticsService(svc *services.OnboardingAnalyticsService) {
+	r.analyticsSvc = svc
+}
+
+// SetUsageService sets the usage service for member quota checks and event key pruning
+func (r *Runner) SetUsageService(svc *services.UsageService) {
+	r.usageSvc = svc
+}
+
+// SetSubscriptionService sets the subscription service for trial expiry
+func (r *Runner) SetSubscriptionService(svc *services.SubscriptionService) {
+	r.subscriptionSvc = svc
+}
+
+// SetBillingService sets the billing service for suspending past-due tenants
+func (r *Runner) SetBillingService(svc *services.BillingService) {
+	r.billingSvc = svc
+}
+
+// SetSlugChangeService sets the slug change service for expiring slug redirects
+func (r *Runner) SetSlugChangeService(svc *services.SlugChangeService) {
+	r.slugChangeSvc = svc
+}
+
+// SetSessionAdminService sets the onboarding session admin service for the stale session purge job
+func (r *Runner) SetSessionAdminService(svc *services.OnboardingSessionAdminService) {
+	r.sessionAdminSvc = svc
+}
+
 // Start begins the background job processing
 func (r *Runner) Start() {
 	log.Println("Starting background job runner...")
@@ -85,6 +179,136 @@ func (r *Runner) Start() {
 		go r.runReconciliationJob()
 	}
 
+	// Start tenant deletion saga retry job (runs every saga retry interval)
+	if r.deletionSagaSvc != nil {
+		deletionInterval := r.deletionSagaSvc.RetryInterval()
+		r.deletionTicker = time.NewTicker(deletionInterval)
+		log.Printf("Tenant deletion saga retry job scheduled every %v", deletionInterval)
+
+		r.wg.Add(1)
+		go r.runDeletionSagaJob()
+	}
+
+	// Start credential re-encryption job (resumes re-encryption interrupted by restarts)
+	if r.keySvc != nil {
+		reencryptInterval := r.keySvc.ReencryptInterval()
+		r.reencryptTicker = time.NewTicker(reencryptInterval)
+		log.Printf("Credential re-encryption job scheduled every %v", reencryptInterval)
+
+		r.wg.Add(1)
+		go r.runReencryptJob()
+	}
+
+	// Start staff membership reconciliation job
+	if r.staffSyncSvc != nil {
+		staffSyncInterval := r.staffSyncSvc.Interval()
+		r.staffSyncTicker = time.NewTicker(staffSyncInterval)
+		log.Printf("Staff membership reconciliation job scheduled every %v", staffSyncInterval)
+
+		r.wg.Add(1)
+		go r.runStaffSyncJob()
+	}
+
+	// Start webhook delivery job (sends pending events and retries failures with backoff)
+	if r.webhookSvc != nil {
+		webhookInterval := r.webhookSvc.Interval()
+		r.webhookTicker = time.NewTicker(webhookInterval)
+		log.Printf("Webhook delivery job scheduled every %v", webhookInterval)
+
+		r.wg.Add(1)
+		go r.runWebhookDeliveryJob()
+	}
+
+	// Start tenant export maintenance job (runs hourly)
+	if r.exportSvc != nil {
+		exportInterval := time.Hour
+		r.exportTicker = time.NewTicker(exportInterval)
+		log.Printf("Tenant export maintenance job scheduled every %v", exportInterval)
+
+		r.wg.Add(1)
+		go r.runExportMaintenanceJob()
+	}
+
+	// Start scheduled tenant purge job (offboards tenants whose grace period elapsed)
+	if r.offboardingSvc != nil {
+		tenantPurgeInterval := r.offboardingSvc.PurgeInterval()
+		r.tenantPurgeTicker = time.NewTicker(tenantPurgeInterval)
+		log.Printf("Scheduled tenant purge job scheduled every %v", tenantPurgeInterval)
+
+		r.wg.Add(1)
+		go r.runTenantPurgeJob()
+	}
+
+	// Start member invitation cleanup job
+	if r.membershipSvc != nil {
+		invitationInterval := r.membershipSvc.InvitationCleanupInterval()
+		r.invitationTicker = time.NewTicker(invitationInterval)
+		log.Printf("Invitation cleanup job scheduled every %v", invitationInterval)
+
+		r.wg.Add(1)
+		go r.runInvitationCleanupJob()
+	}
+
+	// Start onboarding funnel refresh job
+	if r.analyticsSvc != nil {
+		funnelInterval := r.analyticsSvc.RefreshInterval()
+		r.funnelTicker = time.NewTicker(funnelInterval)
+		log.Printf("Onboarding funnel refresh job scheduled every %v", funnelInterval)
+
+		r.wg.Add(1)
+		go r.runFunnelRefreshJob()
+	}
+
+	// Start usage quota job
+	if r.usageSvc != nil {
+		usageInterval := r.usageSvc.CheckInterval()
+		r.usageTicker = time.NewTicker(usageInterval)
+		log.Printf("Usage quota job scheduled every %v", usageInterval)
+
+		r.wg.Add(1)
+		go r.runUsageJob()
+	}
+
+	// Start trial expiry job
+	if r.subscriptionSvc != nil {
+		trialInterval := r.subscriptionSvc.TrialCheckInterval()
+		r.trialTicker = time.NewTicker(trialInterval)
+		log.Printf("Trial expiry job scheduled every %v", trialInterval)
+
+		r.wg.Add(1)
+		go r.runTrialExpiryJob()
+	}
+
+	// Start past-due suspension job
+	if r.billingSvc != nil {
+		billingInterval := r.billingSvc.SuspensionCheckInterval()
+		r.billingTicker = time.NewTicker(billingInterval)
+		log.Printf("Past-due suspension job scheduled every %v", billingInterval)
+
+		r.wg.Add(1)
+		go r.runSuspensionJob()
+	}
+
+	// Start slug redirect expiry job
+	if r.slugChangeSvc != nil {
+		redirectInterval := r.slugChangeSvc.RedirectCheckInterval()
+		r.slugRedirectTicker = time.NewTicker(redirectInterval)
+		log.Printf("Slug redirect expiry job scheduled every %v", redirectInterval)
+
+		r.wg.Add(1)
+		go r.runSlugRedirectJob()
+	}
+
+	// Start stale onboarding session purge job
+	if r.sessionAdminSvc != nil {
+		purgeInterval := r.sessionAdminSvc.PurgeInterval()
+		r.sessionPurgeTicker = time.NewTicker(purgeInterval)
+		log.Printf("Stale onboarding session purge job scheduled every %v", purgeInterval)
+
+		r.wg.Add(1)
+		go r.runSessionPurgeJob()
+	}
+
 	log.Println("Background job runner started successfully")
 }
 
@@ -105,6 +329,45 @@ func (r *Runner) Stop() {
 	if r.reconcileTicker != nil {
 		r.reconcileTicker.Stop()
 	}
+	if r.deletionTicker != nil {
+		r.deletionTicker.Stop()
+	}
+	if r.reencryptTicker != nil {
+		r.reencryptTicker.Stop()
+	}
+	if r.staffSyncTicker != nil {
+		r.staffSyncTicker.Stop()
+	}
+	if r.webhookTicker != nil {
+		r.webhookTicker.Stop()
+	}
+	if r.exportTicker != nil {
+		r.exportTicker.Stop()
+	}
+	if r.tenantPurgeTicker != nil {
+		r.tenantPurgeTicker.Stop()
+	}
+	if r.invitationTicker != nil {
+		r.invitationTicker.Stop()
+	}
+	if r.funnelTicker != nil {
+		r.funnelTicker.Stop()
+	}
+	if r.usageTicker != nil {
+		r.usageTicker.Stop()
+	}
+	if r.trialTicker != nil {
+		r.trialTicker.Stop()
+	}
+	if r.billingTicker != nil {
+		r.billingTicker.Stop()
+	}
+	if r.slugRedirectTicker != nil {
+		r.slugRedirectTicker.Stop()
+	}
+	if r.sessionPurgeTicker != nil {
+		r.sessionPurgeTicker.Stop()
+	}
 
 	// Wait for goroutines to finish with timeout
 	done := make(chan struct{})
@@ -282,3 +545,447 @@ func (r *Runner) executeReconciliation() {
 		}
 	}
 }
+
+// runDeletionSagaJob re-requests purges from services that have not acknowledged
+func (r *Runner) runDeletionSagaJob() {
+	defer r.wg.Done()
+
+	// Run immediately on start to pick up requests that lapsed while service was down
+	r.executeDeletionSagaRetry()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Deletion saga job stopping...")
+			return
+		case <-r.deletionTicker.C:
+			r.executeDeletionSagaRetry()
+		}
+	}
+}
+
+// executeDeletionSagaRetry re-publishes deletion requests for lagging services
+func (r *Runner) executeDeletionSagaRetry() {
+	if r.deletionSagaSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	retried, err := r.deletionSagaSvc.RetryLaggards(ctx)
+	if err != nil {
+		log.Printf("Error in tenant deletion saga job: %v", err)
+	} else if retried > 0 {
+		log.Printf("Tenant deletion saga job completed: %d service purges re-requested", retried)
+	}
+}
+
+// runReencryptJob moves credentials onto each tenant's active key after rotation
+func (r *Runner) runReencryptJob() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Re-encryption job stopping...")
+			return
+		case <-r.reencryptTicker.C:
+			r.executeReencrypt()
+		}
+	}
+}
+
+// executeReencrypt re-encrypts credentials of tenants with rotated keys
+func (r *Runner) executeReencrypt() {
+	if r.keySvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	count, err := r.keySvc.ReencryptPending(ctx)
+	if err != nil {
+		log.Printf("Error in credential re-encryption job: %v", err)
+	} else if count > 0 {
+		log.Printf("Credential re-encryption job completed: %d credentials re-encrypted", count)
+	}
+}
+
+// runStaffSyncJob reconciles staff-service records with tenant memberships periodically
+func (r *Runner) runStaffSyncJob() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Staff membership reconciliation job stopping...")
+			return
+		case <-r.staffSyncTicker.C:
+			r.executeStaffSync()
+		}
+	}
+}
+
+// executeStaffSync creates missing staff memberships and records conflicts
+func (r *Runner) executeStaffSync() {
+	if r.staffSyncSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	result, err := r.staffSyncSvc.ReconcileAll(ctx, false)
+	if err != nil {
+		log.Printf("Error in staff membership reconciliation job: %v", err)
+	} else if result.MembershipsCreated > 0 || len(result.Errors) > 0 {
+		log.Printf("Staff membership reconciliation job completed: tenants=%d, created=%d, errors=%d",
+			result.TenantsChecked, result.MembershipsCreated, len(result.Errors))
+	}
+}
+
+// runWebhookDeliveryJob delivers due webhook events periodically
+func (r *Runner) runWebhookDeliveryJob() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Webhook delivery job stopping...")
+			return
+		case <-r.webhookTicker.C:
+			r.executeWebhookDelivery()
+		}
+	}
+}
+
+// executeWebhookDelivery sends pending events and dead-letters those out of retries
+func (r *Runner) executeWebhookDelivery() {
+	if r.webhookSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	delivered, err := r.webhookSvc.DeliverDue(ctx)
+	if err != nil {
+		log.Printf("Error in webhook delivery job: %v", err)
+	} else if delivered > 0 {
+		log.Printf("Webhook delivery job completed: %d events attempted", delivered)
+	}
+}
+
+// runExportMaintenanceJob expires tenant export archives and restarts interrupted exports periodically
+func (r *Runner) runExportMaintenanceJob() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Tenant export maintenance job stopping...")
+			return
+		case <-r.exportTicker.C:
+			r.executeExportMaintenance()
+		}
+	}
+}
+
+// executeExportMaintenance drops expired archives and resumes stale exports
+func (r *Runner) executeExportMaintenance() {
+	if r.exportSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	if err := r.exportSvc.RunMaintenance(ctx); err != nil {
+		log.Printf("Error in tenant export maintenance job: %v", err)
+	}
+}
+
+// runTenantPurgeJob purges tenants whose deletion grace period has elapsed periodically
+func (r *Runner) runTenantPurgeJob() {
+	defer r.wg.Done()
+
+	// Run immediately on start to catch deletions that fell due while the service was down
+	r.executeTenantPurge()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Scheduled tenant purge job stopping...")
+			return
+		case <-r.tenantPurgeTicker.C:
+			r.executeTenantPurge()
+		}
+	}
+}
+
+// executeTenantPurge offboards tenants that are past their restore window
+func (r *Runner) executeTenantPurge() {
+	if r.offboardingSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	purged, err := r.offboardingSvc.PurgeScheduledDeletions(ctx)
+	if err != nil {
+		log.Printf("Error in scheduled tenant purge job: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("Scheduled tenant purge job completed: %d tenants purged", purged)
+	}
+}
+
+// runInvitationCleanupJob purges expired and revoked invitations periodically
+func (r *Runner) runInvitationCleanupJob() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Invitation cleanup job stopping...")
+			return
+		case <-r.invitationTicker.C:
+			r.executeInvitationCleanup()
+		}
+	}
+}
+
+// executeInvitationCleanup deletes invitations past the retention window
+func (r *Runner) executeInvitationCleanup() {
+	if r.membershipSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	purged, err := r.membershipSvc.CleanupInvitations(ctx)
+	if err != nil {
+		log.Printf("Error in invitation cleanup job: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("Invitation cleanup job completed: %d invitations purged", purged)
+	}
+}
+
+// runFunnelRefreshJob rebuilds the onboarding funnel summary periodically
+func (r *Runner) runFunnelRefreshJob() {
+	defer r.wg.Done()
+
+	// Run immediately on start so the funnel is populated after a deploy
+	r.executeFunnelRefresh()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Onboarding funnel refresh job stopping...")
+			return
+		case <-r.funnelTicker.C:
+			r.executeFunnelRefresh()
+		}
+	}
+}
+
+// executeFunnelRefresh recomputes the funnel summary for the lookback window
+func (r *Runner) executeFunnelRefresh() {
+	if r.analyticsSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	rows, err := r.analyticsSvc.RefreshFunnelSummary(ctx)
+	if err != nil {
+		log.Printf("Error in onboarding funnel refresh job: %v", err)
+		return
+	}
+	log.Printf("Onboarding funnel refresh job completed: %d summary rows written", rows)
+}
+
+// runUsageJob checks member quotas and prunes metered event keys periodically
+func (r *Runner) runUsageJob() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Usage quota job stopping...")
+			return
+		case <-r.usageTicker.C:
+			r.executeUsageJob()
+		}
+	}
+}
+
+// executeUsageJob reports tenants over their member quota and prunes old metered event keys
+func (r *Runner) executeUsageJob() {
+	if r.usageSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	exceeded, err := r.usageSvc.CheckMemberQuotas(ctx)
+	if err != nil {
+		log.Printf("Error in usage quota job: %v", err)
+	} else if exceeded > 0 {
+		log.Printf("Usage quota job: %d tenants over their member quota", exceeded)
+	}
+
+	pruned, err := r.usageSvc.PruneEvents(ctx)
+	if err != nil {
+		log.Printf("Error pruning usage events: %v", err)
+		return
+	}
+	if pruned > 0 {
+		log.Printf("Usage quota job: %d metered event keys pruned", pruned)
+	}
+}
+
+// runTrialExpiryJob moves tenants whose trial ended to the default plan periodically
+func (r *Runner) runTrialExpiryJob() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Trial expiry job stopping...")
+			return
+		case <-r.trialTicker.C:
+			r.executeTrialExpiryJob()
+		}
+	}
+}
+
+// executeTrialExpiryJob moves tenants whose trial ended to the default plan
+func (r *Runner) executeTrialExpiryJob() {
+	if r.subscriptionSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	expired, err := r.subscriptionSvc.ExpireTrials(ctx)
+	if err != nil {
+		log.Printf("Error in trial expiry job: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("Trial expiry job: %d tenants moved to the default plan", expired)
+	}
+}
+
+// runSuspensionJob suspends tenants whose payment stayed failed past the grace period periodically
+func (r *Runner) runSuspensionJob() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Past-due suspension job stopping...")
+			return
+		case <-r.billingTicker.C:
+			r.executeSuspensionJob()
+		}
+	}
+}
+
+// executeSuspensionJob suspends tenants whose payment stayed failed past the grace period
+func (r *Runner) executeSuspensionJob() {
+	if r.billingSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	suspended, err := r.billingSvc.SuspendOverdueTenants(ctx)
+	if err != nil {
+		log.Printf("Error in past-due suspension job: %v", err)
+		return
+	}
+	if suspended > 0 {
+		log.Printf("Past-due suspension job: %d tenants suspended", suspended)
+	}
+}
+
+// runSlugRedirectJob releases former slugs whose redirect expired periodically
+func (r *Runner) runSlugRedirectJob() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Slug redirect expiry job stopping...")
+			return
+		case <-r.slugRedirectTicker.C:
+			r.executeSlugRedirectJob()
+		}
+	}
+}
+
+// executeSlugRedirectJob releases former slugs whose redirect expired
+func (r *Runner) executeSlugRedirectJob() {
+	if r.slugChangeSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	released, err := r.slugChangeSvc.ExpireRedirects(ctx)
+	if err != nil {
+		log.Printf("Error in slug redirect expiry job: %v", err)
+		return
+	}
+	if released > 0 {
+		log.Printf("Slug redirect expiry job: %d former slugs released", released)
+	}
+}
+
+// runSessionPurgeJob purges onboarding sessions stuck before completion periodically
+func (r *Runner) runSessionPurgeJob() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Println("Stale onboarding session purge job stopping...")
+			return
+		case <-r.sessionPurgeTicker.C:
+			r.executeSessionPurgeJob()
+		}
+	}
+}
+
+// executeSessionPurgeJob purges onboarding sessions stuck before completion
+func (r *Runner) executeSessionPurgeJob() {
+	if r.sessionAdminSvc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	purged, err := r.sessionAdminSvc.PurgeStaleSessions(ctx)
+	if err != nil {
+		log.Printf("Error in stale onboarding session purge job: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("Stale onboarding session purge job: %d sessions purged", purged)
+	}
+}
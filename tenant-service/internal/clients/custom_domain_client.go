@@ -197,3 +197,62 @@ func (c *CustomDomainClient) ValidateDomain(ctx context.Context, domain string)
 
 	return &response, nil
 }
+
+// VerifyTXTResponse represents the result of a TXT record check
+type VerifyTXTResponse struct {
+	Host          string   `json:"host"`
+	ExpectedValue string   `json:"expected_value"`
+	Verified      bool     `json:"verified"`
+	RecordsFound  []string `json:"records_found,omitempty"`
+	Message       string   `json:"message"`
+}
+
+// VerifyTXT asks custom-domain-service whether host has a TXT record with the expected value
+// Used to verify ownership of email domains for domain-based auto-join
+func (c *CustomDomainClient) VerifyTXT(ctx context.Context, host, expectedValue string) (*VerifyTXTResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/internal/verify-txt", c.baseURL)
+
+	body, err := json.Marshal(map[string]string{
+		"host":           host,
+		"expected_value": expectedValue,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call custom-domain-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var apiResp DomainAPIResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal API response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if apiResp.Error != "" {
+			return nil, fmt.Errorf("custom-domain-service error: %s", apiResp.Error)
+		}
+		return nil, fmt.Errorf("custom-domain-service returned status %d", resp.StatusCode)
+	}
+
+	var response VerifyTXTResponse
+	if len(apiResp.Data) > 0 {
+		if err := json.Unmarshal(apiResp.Data, &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal TXT verification response: %w", err)
+		}
+	}
+	return &response, nil
+}
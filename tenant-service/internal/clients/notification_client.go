@@ -836,3 +836,327 @@ func renderGoodbyeEmailTemplate(data *GoodbyeEmailData) string {
 </body>
 </html>`, data.FirstName, data.StoreName, purgeDate, data.ReactivationURL, data.StoreName)
 }
+
+// DataExportReadyEmailData contains data for the tenant data export email
+type DataExportReadyEmailData struct {
+	Email        string
+	FirstName    string
+	StoreName    string
+	DownloadLink string
+	ExpiresAt    time.Time
+}
+
+// SendDataExportReadyEmail tells the requester their tenant data export can be downloaded
+func (c *NotificationClient) SendDataExportReadyEmail(ctx context.Context, data *DataExportReadyEmailData) error {
+	expires := data.ExpiresAt.UTC().Format("January 2, 2006 15:04 MST")
+
+	req := &NotificationSendRequest{
+		Channel:        "EMAIL",
+		RecipientEmail: data.Email,
+		Subject:        fmt.Sprintf("Your %s data export is ready", data.StoreName),
+		Body:           fmt.Sprintf("Your data export for %s is ready. Download it here: %s. The link expires on %s.", data.StoreName, data.DownloadLink, expires),
+		BodyHTML:       renderDataExportReadyEmailTemplate(data, expires),
+	}
+
+	var response struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	return c.makeRequest(ctx, "POST", "/api/v1/notifications/send", req, &response)
+}
+
+// renderDataExportReadyEmailTemplate generates the data export email
+func renderDataExportReadyEmailTemplate(data *DataExportReadyEmailData, expires string) string {
+	firstName := data.FirstName
+	if firstName == "" {
+		firstName = "there"
+	}
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Your Data Export Is Ready</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Source Sans 3', 'Inter', 'Segoe UI', 'Helvetica Neue', Arial, system-ui, -apple-system, BlinkMacSystemFont, sans-serif; background-color: #F8FAFC;">
+    <table role="presentation" style="width: 100%%; border-collapse: collapse;">
+        <tr>
+            <td align="center" style="padding: 40px 0;">
+                <table role="presentation" style="width: 600px; max-width: 100%%; border-collapse: collapse; background-color: #ffffff; border-radius: 10px; border: 1px solid #E2E8F0;">
+                    <tr>
+                        <td style="background-color: #0F172A; padding: 40px 40px 30px; border-radius: 10px 10px 0 0; text-align: center;">
+                            <h1 style="color: #ffffff; margin: 0; font-size: 24px; font-weight: 600;">
+                                Your Data Export Is Ready
+                            </h1>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="padding: 40px;">
+                            <p style="color: #334155; font-size: 16px; line-height: 1.6; margin: 0 0 24px;">
+                                Hi %s,
+                            </p>
+                            <p style="color: #334155; font-size: 16px; line-height: 1.6; margin: 0 0 24px;">
+                                The data export you requested for <strong>%s</strong> has been generated. It contains your store profile, team memberships, onboarding information and activity and sign-in history.
+                            </p>
+                            <table role="presentation" cellspacing="0" cellpadding="0" border="0" style="margin: 16px auto 32px;">
+                                <tr>
+                                    <td style="background-color: #0F172A; border-radius: 10px;">
+                                        <a href="%s" target="_blank" style="display: inline-block; padding: 18px 48px; font-size: 16px; font-weight: 600; color: #ffffff; text-decoration: none; border-radius: 10px;">
+                                            Download Export
+                                        </a>
+                                    </td>
+                                </tr>
+                            </table>
+                            <p style="color: #64748B; font-size: 14px; line-height: 1.6; margin: 0;">
+                                This link expires on %s. If you did not request this export, please contact your store owner or our support team.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`, template.HTMLEscapeString(firstName), template.HTMLEscapeString(data.StoreName), data.DownloadLink, expires)
+}
+
+// DomainVerificationCodeEmailData contains data for the email domain ownership code
+type DomainVerificationCodeEmailData struct {
+	Email     string
+	StoreName string
+	Domain    string
+	Code      string
+	ExpiresAt time.Time
+}
+
+// SendDomainVerificationCodeEmail sends the code that proves control of an email domain
+// before the tenant lets anyone with an address at that domain join
+func (c *NotificationClient) SendDomainVerificationCodeEmail(ctx context.Context, data *DomainVerificationCodeEmailData) error {
+	expires := data.ExpiresAt.UTC().Format("January 2, 2006 15:04 MST")
+
+	req := &NotificationSendRequest{
+		Channel:        "EMAIL",
+		RecipientEmail: data.Email,
+		Subject:        fmt.Sprintf("Verify %s for %s", data.Domain, data.StoreName),
+		Body:           fmt.Sprintf("Your code to verify %s for %s is %s. It expires on %s.", data.Domain, data.StoreName, data.Code, expires),
+		BodyHTML:       renderDomainVerificationCodeEmailTemplate(data, expires),
+		Priority:       "high",
+	}
+
+	var response struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	return c.makeRequest(ctx, "POST", "/api/v1/notifications/send", req, &response)
+}
+
+// renderDomainVerificationCodeEmailTemplate generates the email domain verification code email
+func renderDomainVerificationCodeEmailTemplate(data *DomainVerificationCodeEmailData, expires string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Verify Your Email Domain</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Source Sans 3', 'Inter', 'Segoe UI', 'Helvetica Neue', Arial, system-ui, -apple-system, BlinkMacSystemFont, sans-serif; background-color: #F8FAFC;">
+    <table role="presentation" style="width: 100%%; border-collapse: collapse;">
+        <tr>
+            <td align="center" style="padding: 40px 0;">
+                <table role="presentation" style="width: 600px; max-width: 100%%; border-collapse: collapse; background-color: #ffffff; border-radius: 10px; border: 1px solid #E2E8F0;">
+                    <tr>
+                        <td style="background-color: #0F172A; padding: 40px 40px 30px; border-radius: 10px 10px 0 0; text-align: center;">
+                            <h1 style="color: #ffffff; margin: 0; font-size: 24px; font-weight: 600;">
+                                Verify Your Email Domain
+                            </h1>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="padding: 40px;">
+                            <p style="color: #334155; font-size: 16px; line-height: 1.6; margin: 0 0 24px;">
+                                <strong>%s</strong> wants to let anyone with an <strong>@%s</strong> address join the team. Enter this code to confirm you control the domain:
+                            </p>
+                            <p style="color: #0F172A; font-size: 32px; font-weight: 700; letter-spacing: 8px; text-align: center; margin: 16px 0 32px;">
+                                %s
+                            </p>
+                            <p style="color: #64748B; font-size: 14px; line-height: 1.6; margin: 0;">
+                                The code expires on %s. If you did not expect this email, you can ignore it.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`, template.HTMLEscapeString(data.StoreName), template.HTMLEscapeString(data.Domain), data.Code, expires)
+}
+
+// EmailChangeVerificationEmailData contains data for the link confirming a new login email
+type EmailChangeVerificationEmailData struct {
+	Email       string // The new address
+	FirstName   string
+	StoreName   string
+	ConfirmLink string
+	ExpiresAt   time.Time
+}
+
+// SendEmailChangeVerificationEmail sends the link that confirms a user controls their new login email
+func (c *NotificationClient) SendEmailChangeVerificationEmail(ctx context.Context, data *EmailChangeVerificationEmailData) error {
+	expires := data.ExpiresAt.UTC().Format("January 2, 2006 15:04 MST")
+
+	req := &NotificationSendRequest{
+		Channel:        "EMAIL",
+		RecipientEmail: data.Email,
+		Subject:        fmt.Sprintf("Confirm your new email for %s", data.StoreName),
+		Body:           fmt.Sprintf("Confirm %s as your new sign-in email for %s: %s. The link expires on %s.", data.Email, data.StoreName, data.ConfirmLink, expires),
+		BodyHTML:       renderEmailChangeVerificationEmailTemplate(data, expires),
+		Priority:       "high",
+	}
+
+	var response struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	return c.makeRequest(ctx, "POST", "/api/v1/notifications/send", req, &response)
+}
+
+// renderEmailChangeVerificationEmailTemplate generates the email change confirmation email
+func renderEmailChangeVerificationEmailTemplate(data *EmailChangeVerificationEmailData, expires string) string {
+	firstName := data.FirstName
+	if firstName == "" {
+		firstName = "there"
+	}
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Confirm Your New Email</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Source Sans 3', 'Inter', 'Segoe UI', 'Helvetica Neue', Arial, system-ui, -apple-system, BlinkMacSystemFont, sans-serif; background-color: #F8FAFC;">
+    <table role="presentation" style="width: 100%%; border-collapse: collapse;">
+        <tr>
+            <td align="center" style="padding: 40px 0;">
+                <table role="presentation" style="width: 600px; max-width: 100%%; border-collapse: collapse; background-color: #ffffff; border-radius: 10px; border: 1px solid #E2E8F0;">
+                    <tr>
+                        <td style="background-color: #0F172A; padding: 40px 40px 30px; border-radius: 10px 10px 0 0; text-align: center;">
+                            <h1 style="color: #ffffff; margin: 0; font-size: 24px; font-weight: 600;">
+                                Confirm Your New Email
+                            </h1>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="padding: 40px;">
+                            <p style="color: #334155; font-size: 16px; line-height: 1.6; margin: 0 0 24px;">
+                                Hi %s,
+                            </p>
+                            <p style="color: #334155; font-size: 16px; line-height: 1.6; margin: 0 0 24px;">
+                                You asked to sign in to <strong>%s</strong> with <strong>%s</strong>. Your current email keeps working until you confirm this change.
+                            </p>
+                            <table role="presentation" cellspacing="0" cellpadding="0" border="0" style="margin: 16px auto 32px;">
+                                <tr>
+                                    <td style="background-color: #0F172A; border-radius: 10px;">
+                                        <a href="%s" target="_blank" style="display: inline-block; padding: 18px 48px; font-size: 16px; font-weight: 600; color: #ffffff; text-decoration: none; border-radius: 10px;">
+                                            Confirm Email
+                                        </a>
+                                    </td>
+                                </tr>
+                            </table>
+                            <p style="color: #64748B; font-size: 14px; line-height: 1.6; margin: 0;">
+                                This link expires on %s. If you did not ask for this change, you can ignore this email.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`, template.HTMLEscapeString(firstName), template.HTMLEscapeString(data.StoreName), template.HTMLEscapeString(data.Email), data.ConfirmLink, expires)
+}
+
+// EmailChangedNoticeEmailData contains data for the notice sent to a user's previous login email
+type EmailChangedNoticeEmailData struct {
+	Email     string // The previous address
+	NewEmail  string
+	FirstName string
+	StoreName string
+}
+
+// SendEmailChangedNoticeEmail tells the previous address that the login email was changed
+func (c *NotificationClient) SendEmailChangedNoticeEmail(ctx context.Context, data *EmailChangedNoticeEmailData) error {
+	req := &NotificationSendRequest{
+		Channel:        "EMAIL",
+		RecipientEmail: data.Email,
+		Subject:        fmt.Sprintf("Your sign-in email for %s was changed", data.StoreName),
+		Body:           fmt.Sprintf("The sign-in email of your %s account was changed to %s. If you did not make this change, contact support right away.", data.StoreName, maskNoticeEmail(data.NewEmail)),
+		BodyHTML:       renderEmailChangedNoticeEmailTemplate(data),
+		Priority:       "high",
+	}
+
+	var response struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	return c.makeRequest(ctx, "POST", "/api/v1/notifications/send", req, &response)
+}
+
+// renderEmailChangedNoticeEmailTemplate generates the email changed notice
+func renderEmailChangedNoticeEmailTemplate(data *EmailChangedNoticeEmailData) string {
+	firstName := data.FirstName
+	if firstName == "" {
+		firstName = "there"
+	}
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Your Sign-In Email Was Changed</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Source Sans 3', 'Inter', 'Segoe UI', 'Helvetica Neue', Arial, system-ui, -apple-system, BlinkMacSystemFont, sans-serif; background-color: #F8FAFC;">
+    <table role="presentation" style="width: 100%%; border-collapse: collapse;">
+        <tr>
+            <td align="center" style="padding: 40px 0;">
+                <table role="presentation" style="width: 600px; max-width: 100%%; border-collapse: collapse; background-color: #ffffff; border-radius: 10px; border: 1px solid #E2E8F0;">
+                    <tr>
+                        <td style="background-color: #0F172A; padding: 40px 40px 30px; border-radius: 10px 10px 0 0; text-align: center;">
+                            <h1 style="color: #ffffff; margin: 0; font-size: 24px; font-weight: 600;">
+                                Your Sign-In Email Was Changed
+                            </h1>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="padding: 40px;">
+                            <p style="color: #334155; font-size: 16px; line-height: 1.6; margin: 0 0 24px;">
+                                Hi %s,
+                            </p>
+                            <p style="color: #334155; font-size: 16px; line-height: 1.6; margin: 0 0 24px;">
+                                The sign-in email of your <strong>%s</strong> account was changed to <strong>%s</strong>. This address will no longer work for signing in.
+                            </p>
+                            <p style="color: #64748B; font-size: 14px; line-height: 1.6; margin: 0;">
+                                If you did not make this change, contact your store owner or our support team right away.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`, template.HTMLEscapeString(firstName), template.HTMLEscapeString(data.StoreName), template.HTMLEscapeString(maskNoticeEmail(data.NewEmail)))
+}
+
+// maskNoticeEmail hides most of the local part of an address (jane@example.com -> j***@example.com)
+func maskNoticeEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return "***"
+	}
+	return email[:1] + "***" + email[at:]
+}
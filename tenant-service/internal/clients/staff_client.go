@@ -188,6 +188,7 @@ type StaffMemberInfo struct {
 	TenantID       uuid.UUID  `json:"tenant_id"`
 	AccountStatus  string     `json:"account_status"`
 	IsActive       bool       `json:"is_active"`
+	Role           string     `json:"role,omitempty"`
 }
 
 // GetStaffByEmailResponse represents the response from getting staff by email
@@ -328,3 +329,67 @@ func (c *StaffClient) SyncKeycloakUserID(ctx context.Context, tenantID, staffID
 
 	return nil
 }
+
+// ListStaffResponse represents a page of staff members for a tenant
+type ListStaffResponse struct {
+	Success bool `json:"success"`
+	Data    *struct {
+		Staff []StaffMemberInfo `json:"staff"`
+		Total int               `json:"total"`
+	} `json:"data,omitempty"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// staffPageSize is the page size used when listing a tenant's staff
+const staffPageSize = 100
+
+// ListTenantStaff returns every staff member of a tenant, including inactive ones
+// Used by the membership reconciliation job to detect staff without memberships
+func (c *StaffClient) ListTenantStaff(ctx context.Context, tenantID uuid.UUID) ([]StaffMemberInfo, error) {
+	var all []StaffMemberInfo
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/api/v1/internal/staff?page=%d&limit=%d&include_inactive=true", c.baseURL, page, staffPageSize)
+		httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("x-jwt-claim-tenant-id", tenantID.String())
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		var response ListStaffResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			if response.Error != nil {
+				return nil, fmt.Errorf("staff service error: %s - %s", response.Error.Code, response.Error.Message)
+			}
+			return nil, fmt.Errorf("staff service returned status %d: %s", resp.StatusCode, string(body))
+		}
+
+		if response.Data == nil {
+			return all, nil
+		}
+
+		all = append(all, response.Data.Staff...)
+		if len(response.Data.Staff) < staffPageSize || (response.Data.Total > 0 && len(all) >= response.Data.Total) {
+			return all, nil
+		}
+	}
+}
@@ -3,15 +3,17 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets"
+	"tenant-service/internal/redisconn"
 )
 
 // Config holds all configuration for the service
 type Config struct {
 	Server       ServerConfig
 	Database     DatabaseConfig
-	Redis        RedisConfig
+	Redis        redisconn.Config
 	App          AppConfig
 	Email        EmailConfig
 	SMS          SMSConfig
@@ -20,14 +22,23 @@ type Config struct {
 	Draft        DraftConfig
 	Verification VerificationConfig
 	URL          URLConfig
-}
-
-// RedisConfig holds Redis configuration
-type RedisConfig struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	Deletion     DeletionConfig
+	Encryption   EncryptionConfig
+	StaffSync    StaffSyncConfig
+	Webhook      WebhookConfig
+	Export       ExportConfig
+	Password     PasswordPolicyConfig
+	Invitation   InvitationConfig
+	Funnel       FunnelConfig
+	SSO          SSOConfig
+	JoinDomain   JoinDomainConfig
+	Usage        UsageConfig
+	Subscription SubscriptionConfig
+	Billing      BillingConfig
+	SlugChange   SlugChangeConfig
+	Seed         SeedConfig
+	SessionPurge SessionPurgeConfig
+	Passkey      PasskeyConfig
 }
 
 // DraftConfig holds draft persistence configuration
@@ -38,6 +49,140 @@ type DraftConfig struct {
 	CleanupInterval  int // Cleanup job interval in minutes (default: 15)
 }
 
+// DeletionConfig holds the cross-service tenant deletion saga configuration
+type DeletionConfig struct {
+	Participants         []string // Services that must acknowledge a tenant purge
+	RetryIntervalMinutes int      // Minutes to wait for an acknowledgment before re-requesting (default: 15)
+	MaxAttempts          int      // Maximum deletion requests per service before giving up (default: 10)
+	GraceDays            int            // Days a deleted tenant can be restored before it is purged (default: 30, 0 = purge immediately)
+	GraceDaysByTier      map[string]int // Per pricing tier overrides of GraceDays
+	PurgeIntervalMinutes int            // Interval of the job purging tenants whose grace period elapsed (default: 60)
+}
+
+// GracePeriodDays returns the restore window for a tenant on the given pricing tier
+func (c DeletionConfig) GracePeriodDays(tier string) int {
+	if days, ok := c.GraceDaysByTier[tier]; ok {
+		return days
+	}
+	return c.GraceDays
+}
+
+// EncryptionConfig holds per-tenant credential encryption configuration
+type EncryptionConfig struct {
+	MasterKey                string // Base64 encoded 32-byte KMS master key wrapping tenant data keys
+	MasterKeyID              string // Identifier recorded on each wrapped key (default: "local-v1")
+	ReencryptIntervalMinutes int    // Interval of the background re-encryption job (default: 10)
+}
+
+// StaffSyncConfig holds the staff-service membership reconciliation configuration
+type StaffSyncConfig struct {
+	Enabled         bool // Run the scheduled reconciliation job (default: true)
+	IntervalMinutes int  // Interval of the scheduled reconciliation job (default: 60)
+	CreateMissing   bool // Create memberships missing for active staff; false only reports drift (default: true)
+}
+
+// WebhookConfig holds outbound webhook delivery configuration
+type WebhookConfig struct {
+	DeliveryEnabled         bool // Run the scheduled delivery/retry job (default: true)
+	DeliveryIntervalSeconds int  // Interval of the delivery/retry job (default: 60)
+	TimeoutSeconds          int  // HTTP timeout for a single delivery attempt (default: 10)
+}
+
+// ExportConfig holds tenant data export (data portability) configuration
+type ExportConfig struct {
+	PublicBaseURL  string // Base URL of this service used in download links (default: tenant admin URL)
+	RetentionHours int    // How long generated archives stay downloadable (default: 168)
+}
+
+// PasswordPolicyConfig holds tenant password policy enforcement configuration
+type PasswordPolicyConfig struct {
+	BreachCheckURL            string // HIBP k-anonymity range API base URL (default: https://api.pwnedpasswords.com/range/)
+	BreachCheckTimeoutSeconds int    // Timeout for a breach lookup; lookups that fail are allowed through (default: 3)
+}
+
+// InvitationConfig holds member invitation lifecycle configuration
+type InvitationConfig struct {
+	TTLHours               int // How long an invitation can be accepted (default: 168)
+	RetentionDays          int // Days expired or revoked invitations stay listed before cleanup (default: 30)
+	CleanupIntervalMinutes int // Interval of the expired invitation cleanup job (default: 60)
+}
+
+// FunnelConfig holds onboarding funnel analytics configuration
+type FunnelConfig struct {
+	RefreshIntervalMinutes int // Interval of the funnel summary refresh job (default: 15)
+	LookbackDays           int // Days of sessions recomputed by each refresh (default: 30)
+	MaxRangeDays           int // Longest from/to range accepted by the funnel endpoint (default: 366)
+}
+
+// SSOConfig holds tenant single sign-on configuration
+type SSOConfig struct {
+	KeycloakBaseURL           string // Keycloak base URL, used to build the IdP broker redirect URI
+	KeycloakRealm             string // Realm the tenant identity providers are created in
+	MetadataFetchTimeout      int    // Timeout in seconds for fetching SAML metadata by URL (default: 10)
+	MetadataMaxBytes          int    // Largest SAML metadata document accepted (default: 1048576)
+	CertificateMinDays        int    // Minimum remaining validity of the IdP signing certificate (default: 7)
+	AllowPrivateMetadataHosts bool   // Fetch IdP documents from private addresses, e.g. an in-cluster IdP in development (default: false)
+}
+
+// JoinDomainConfig holds email domain-based auto-join configuration
+type JoinDomainConfig struct {
+	EmailCodeTTLMinutes  int      // How long an emailed domain verification code is valid (default: 30)
+	EmailCodeMaxAttempts int      // Wrong codes before a new one has to be sent (default: 5)
+	BlockedDomains       []string // Extra domains that can never be claimed, on top of public email providers
+}
+
+// UsageConfig holds tenant usage metering and plan quota configuration
+type UsageConfig struct {
+	EnforceQuotas        bool // Reject member invites/imports and API key use over the plan quota (default: false)
+	EventRetentionDays   int  // Days metered event keys are kept for de-duplicating redeliveries (default: 30)
+	CheckIntervalMinutes int  // Interval of the job checking member quotas and pruning event keys (default: 60)
+}
+
+// SubscriptionConfig holds tenant plan and trial configuration
+type SubscriptionConfig struct {
+	DefaultPlan               string // Plan of new tenants and of tenants whose trial expired (default: "free")
+	TrialPlan                 string // Plan new tenants trial for the template's trial_period_days; empty disables trials
+	TrialCheckIntervalMinutes int    // Interval of the job moving expired trials to the default plan (default: 15)
+}
+
+// BillingConfig holds the billing provider webhook and payment failure configuration
+type BillingConfig struct {
+	WebhookSecret                  string // Shared secret the provider signs webhooks with; empty rejects all webhooks
+	SignatureToleranceSeconds      int    // Maximum age of a signed webhook (default: 300)
+	PaymentGraceDays               int    // Days a past-due tenant keeps its plan before suspension (default: 7, 0 = suspend immediately)
+	SuspensionCheckIntervalMinutes int    // Interval of the job suspending tenants past the grace period (default: 60)
+}
+
+// SlugChangeConfig holds tenant slug rename configuration
+type SlugChangeConfig struct {
+	RedirectDays         int // Days the old slug keeps redirecting and stays reserved (default: 30)
+	CooldownDays         int // Days between renames of the same tenant (default: 30, 0 = no cooldown)
+	CheckIntervalMinutes int // Interval of the job expiring redirects (default: 60)
+}
+
+// SeedConfig holds the seed profile of default templates and reserved slugs
+type SeedConfig struct {
+	Profile string // Profile applied at startup (default: APP_ENV, falling back to "base")
+	Dir     string // Directory of profiles overriding the built-in ones (default: none)
+}
+
+// SessionPurgeConfig holds the cleanup of onboarding sessions that never completed
+type SessionPurgeConfig struct {
+	StaleDays       int // Days a pending session may go without updates before it is purged (default: 30, 0 = never)
+	IntervalMinutes int // Interval of the purge job (default: 360)
+	MaxBulkSessions int // Maximum sessions per admin bulk action (default: 500)
+}
+
+// PasskeyConfig holds WebAuthn relying party configuration for passkey logins
+type PasskeyConfig struct {
+	RPID                  string   // Relying party ID passkeys are bound to (default: BASE_DOMAIN, covering every tenant subdomain)
+	RPDisplayName         string   // Name authenticators show next to the passkey (default: "Tesseract Hub")
+	ExtraOrigins          []string // Origins allowed besides https://{slug}-admin.{BASE_DOMAIN}, e.g. local admin URLs
+	Attestation           string   // Attestation requested at registration: "none", "indirect" or "direct" (default: "none")
+	ChallengeTTLSeconds   int      // How long a registration or login ceremony may take (default: 300)
+	MaxCredentialsPerUser int      // Passkeys a user can register per tenant (default: 10)
+}
+
 // VerificationConfig holds email verification configuration
 type VerificationConfig struct {
 	Method                 string // "otp" or "link" (default: "link")
@@ -164,12 +309,8 @@ func New() *Config {
 			CustomDomainServiceURL:  getEnvWithDefault("CUSTOM_DOMAIN_SERVICE_URL", "http://custom-domain-service.marketplace.svc.cluster.local:8093"),
 			TenantRouterServiceURL:  getEnvWithDefault("TENANT_ROUTER_SERVICE_URL", "http://tenant-router-service.marketplace.svc.cluster.local:8089"),
 		},
-		Redis: RedisConfig{
-			Host:     getEnvWithDefault("REDIS_HOST", "localhost"),
-			Port:     getEnvWithDefault("REDIS_PORT", "6379"),
-			Password: getEnvWithDefault("REDIS_PASSWORD", ""),
-			DB:       getEnvAsIntWithDefault("REDIS_DB", 0),
-		},
+		// REDIS_URL, REDIS_MODE (standalone, sentinel, cluster), REDIS_HOST/REDIS_PORT, REDIS_TLS, ...
+		Redis: redisconn.ConfigFromEnv(redisconn.Config{Addrs: []string{"localhost:6379"}}),
 		Draft: DraftConfig{
 			ExpiryHours:      getEnvAsIntWithDefault("DRAFT_EXPIRY_HOURS", 168), // 7 days
 			ReminderInterval: getEnvAsIntWithDefault("DRAFT_REMINDER_INTERVAL_HOURS", 24),
@@ -184,6 +325,98 @@ func New() *Config {
 			CustomDomainGatewayIP: getEnvWithDefault("CUSTOM_DOMAIN_GATEWAY_IP", ""), // LoadBalancer IP for custom domains
 			CloudflareTunnelID:    getEnvWithDefault("CLOUDFLARE_TUNNEL_ID", ""),     // Deprecated
 		},
+		Deletion: DeletionConfig{
+			Participants:         getEnvAsListWithDefault("TENANT_DELETION_PARTICIPANTS", defaultDeletionParticipants),
+			RetryIntervalMinutes: getEnvAsIntWithDefault("TENANT_DELETION_RETRY_INTERVAL_MINS", 15),
+			MaxAttempts:          getEnvAsIntWithDefault("TENANT_DELETION_MAX_ATTEMPTS", 10),
+			GraceDays:            getEnvAsIntWithDefault("TENANT_DELETION_GRACE_DAYS", 30),
+			GraceDaysByTier:      getEnvAsIntMap("TENANT_DELETION_GRACE_DAYS_BY_TIER"),
+			PurgeIntervalMinutes: getEnvAsIntWithDefault("TENANT_DELETION_PURGE_INTERVAL_MINS", 60),
+		},
+		Encryption: EncryptionConfig{
+			MasterKey:                getEnvWithDefault("TENANT_KMS_MASTER_KEY", ""),
+			MasterKeyID:              getEnvWithDefault("TENANT_KMS_MASTER_KEY_ID", "local-v1"),
+			ReencryptIntervalMinutes: getEnvAsIntWithDefault("TENANT_KEY_REENCRYPT_INTERVAL_MINS", 10),
+		},
+		StaffSync: StaffSyncConfig{
+			Enabled:         getEnvAsBoolWithDefault("STAFF_MEMBERSHIP_SYNC_ENABLED", true),
+			IntervalMinutes: getEnvAsIntWithDefault("STAFF_MEMBERSHIP_SYNC_INTERVAL_MINS", 60),
+			CreateMissing:   getEnvAsBoolWithDefault("STAFF_MEMBERSHIP_SYNC_CREATE_MISSING", true),
+		},
+		Webhook: WebhookConfig{
+			DeliveryEnabled:         getEnvAsBoolWithDefault("WEBHOOK_DELIVERY_ENABLED", true),
+			DeliveryIntervalSeconds: getEnvAsIntWithDefault("WEBHOOK_DELIVERY_INTERVAL_SECS", 60),
+			TimeoutSeconds:          getEnvAsIntWithDefault("WEBHOOK_DELIVERY_TIMEOUT_SECS", 10),
+		},
+		Export: ExportConfig{
+			PublicBaseURL:  getEnvWithDefault("TENANT_EXPORT_PUBLIC_BASE_URL", ""),
+			RetentionHours: getEnvAsIntWithDefault("TENANT_EXPORT_RETENTION_HOURS", 168),
+		},
+		Password: PasswordPolicyConfig{
+			BreachCheckURL:            getEnvWithDefault("PASSWORD_BREACH_CHECK_URL", "https://api.pwnedpasswords.com/range/"),
+			BreachCheckTimeoutSeconds: getEnvAsIntWithDefault("PASSWORD_BREACH_CHECK_TIMEOUT_SECS", 3),
+		},
+		Invitation: InvitationConfig{
+			TTLHours:               getEnvAsIntWithDefault("MEMBER_INVITATION_TTL_HOURS", 168),
+			RetentionDays:          getEnvAsIntWithDefault("MEMBER_INVITATION_RETENTION_DAYS", 30),
+			CleanupIntervalMinutes: getEnvAsIntWithDefault("MEMBER_INVITATION_CLEANUP_INTERVAL_MINS", 60),
+		},
+		Funnel: FunnelConfig{
+			RefreshIntervalMinutes: getEnvAsIntWithDefault("ONBOARDING_FUNNEL_REFRESH_INTERVAL_MINS", 15),
+			LookbackDays:           getEnvAsIntWithDefault("ONBOARDING_FUNNEL_LOOKBACK_DAYS", 30),
+			MaxRangeDays:           getEnvAsIntWithDefault("ONBOARDING_FUNNEL_MAX_RANGE_DAYS", 366),
+		},
+		SSO: SSOConfig{
+			KeycloakBaseURL:           getEnvWithDefault("KEYCLOAK_BASE_URL", "https://devtest-internal-idp.tesserix.app"),
+			KeycloakRealm:             getEnvWithDefault("KEYCLOAK_REALM", "tesserix-internal"),
+			MetadataFetchTimeout:      getEnvAsIntWithDefault("SSO_METADATA_FETCH_TIMEOUT_SECONDS", 10),
+			MetadataMaxBytes:          getEnvAsIntWithDefault("SSO_METADATA_MAX_BYTES", 1048576),
+			CertificateMinDays:        getEnvAsIntWithDefault("SSO_CERTIFICATE_MIN_DAYS", 7),
+			AllowPrivateMetadataHosts: getEnvAsBoolWithDefault("SSO_ALLOW_PRIVATE_METADATA_HOSTS", false),
+		},
+		JoinDomain: JoinDomainConfig{
+			EmailCodeTTLMinutes:  getEnvAsIntWithDefault("JOIN_DOMAIN_EMAIL_CODE_TTL_MINS", 30),
+			EmailCodeMaxAttempts: getEnvAsIntWithDefault("JOIN_DOMAIN_EMAIL_CODE_MAX_ATTEMPTS", 5),
+			BlockedDomains:       getEnvAsListWithDefault("JOIN_DOMAIN_BLOCKED_DOMAINS", nil),
+		},
+		Usage: UsageConfig{
+			EnforceQuotas:        getEnvAsBoolWithDefault("TENANT_QUOTA_ENFORCEMENT_ENABLED", false),
+			EventRetentionDays:   getEnvAsIntWithDefault("TENANT_USAGE_EVENT_RETENTION_DAYS", 30),
+			CheckIntervalMinutes: getEnvAsIntWithDefault("TENANT_USAGE_CHECK_INTERVAL_MINS", 60),
+		},
+		Subscription: SubscriptionConfig{
+			DefaultPlan:               getEnvWithDefault("TENANT_DEFAULT_PLAN", "free"),
+			TrialPlan:                 getEnvWithDefault("TENANT_TRIAL_PLAN", ""),
+			TrialCheckIntervalMinutes: getEnvAsIntWithDefault("TENANT_TRIAL_CHECK_INTERVAL_MINS", 15),
+		},
+		Billing: BillingConfig{
+			WebhookSecret:                  getEnvWithDefault("BILLING_WEBHOOK_SECRET", ""),
+			SignatureToleranceSeconds:      getEnvAsIntWithDefault("BILLING_WEBHOOK_TOLERANCE_SECS", 300),
+			PaymentGraceDays:               getEnvAsIntWithDefault("BILLING_PAYMENT_GRACE_DAYS", 7),
+			SuspensionCheckIntervalMinutes: getEnvAsIntWithDefault("BILLING_SUSPENSION_CHECK_INTERVAL_MINS", 60),
+		},
+		SlugChange: SlugChangeConfig{
+			RedirectDays:         getEnvAsIntWithDefault("TENANT_SLUG_REDIRECT_DAYS", 30),
+			CooldownDays:         getEnvAsIntWithDefault("TENANT_SLUG_CHANGE_COOLDOWN_DAYS", 30),
+			CheckIntervalMinutes: getEnvAsIntWithDefault("TENANT_SLUG_REDIRECT_CHECK_INTERVAL_MINS", 60),
+		},
+		Seed: SeedConfig{
+			Profile: getEnvWithDefault("SEED_PROFILE", getEnvWithDefault("APP_ENV", "development")),
+			Dir:     getEnvWithDefault("SEED_PROFILE_DIR", ""),
+		},
+		SessionPurge: SessionPurgeConfig{
+			StaleDays:       getEnvAsIntWithDefault("ONBOARDING_STALE_SESSION_DAYS", 30),
+			IntervalMinutes: getEnvAsIntWithDefault("ONBOARDING_SESSION_PURGE_INTERVAL_MINS", 360),
+			MaxBulkSessions: getEnvAsIntWithDefault("ONBOARDING_SESSION_MAX_BULK", 500),
+		},
+		Passkey: PasskeyConfig{
+			RPID:                  getEnvWithDefault("WEBAUTHN_RP_ID", getEnvWithDefault("BASE_DOMAIN", "tesserix.app")),
+			RPDisplayName:         getEnvWithDefault("WEBAUTHN_RP_DISPLAY_NAME", "Tesseract Hub"),
+			ExtraOrigins:          getEnvAsListWithDefault("WEBAUTHN_EXTRA_ORIGINS", nil),
+			Attestation:           getEnvWithDefault("WEBAUTHN_ATTESTATION", "none"),
+			ChallengeTTLSeconds:   getEnvAsIntWithDefault("WEBAUTHN_CHALLENGE_TTL_SECONDS", 300),
+			MaxCredentialsPerUser: getEnvAsIntWithDefault("WEBAUTHN_MAX_CREDENTIALS_PER_USER", 10),
+		},
 		URL: URLConfig{
 			// Base domain for subdomain-based tenant URLs
 			// Pattern: {slug}-admin.{baseDomain} for admin, {slug}-store.{baseDomain} for storefront
@@ -219,3 +452,40 @@ func getEnvAsBoolWithDefault(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// defaultDeletionParticipants are the services that consume tenant.deletion.requested and
+// acknowledge the purge
+var defaultDeletionParticipants = []string{"document-service", "settings-service", "notification-service", "audit-service"}
+
+// getEnvAsListWithDefault gets a comma-separated environment variable as a list
+func getEnvAsListWithDefault(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return defaultValue
+	}
+	return items
+}
+
+// getEnvAsIntMap parses a comma-separated list of key:int pairs (e.g. "free:7,enterprise:90")
+func getEnvAsIntMap(key string) map[string]int {
+	result := make(map[string]int)
+	for _, item := range getEnvAsListWithDefault(key, nil) {
+		name, value, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		if intValue, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			result[strings.TrimSpace(name)] = intValue
+		}
+	}
+	return result
+}
@@ -46,11 +46,23 @@ type ValidateCredentialsRequest struct {
 	TenantID    string `json:"tenant_id"`    // Either tenant_id or tenant_slug required
 	TenantSlug  string `json:"tenant_slug"`  // Either tenant_id or tenant_slug required
 	AuthContext string `json:"auth_context"` // "customer" or "staff" - controls whether staff fallback is allowed
+	MFACode     string `json:"mfa_code"`     // TOTP or recovery code, required to complete login when MFA is enabled
+	RememberMe  bool   `json:"remember_me"`  // Request the tenant's remember-me session lifetime
 }
 
 // ValidateCredentials validates tenant-specific credentials
 // POST /api/v1/auth/validate
 // This endpoint is called by auth-bff during the login flow
+// @Summary Validate tenant credentials
+// @Description Validates an email/password pair against a specific tenant; called by auth-bff during login. Returns 401 with lockout details on failure
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ValidateCredentialsRequest true "Credentials"
+// @Success 200 {object} services.ValidateCredentialsResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/auth/validate [post]
 func (h *AuthHandler) ValidateCredentials(c *gin.Context) {
 	var req ValidateCredentialsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -86,6 +98,8 @@ func (h *AuthHandler) ValidateCredentials(c *gin.Context) {
 		TenantID:    tenantID,
 		TenantSlug:  req.TenantSlug,
 		AuthContext: req.AuthContext, // Pass auth context to control staff fallback
+		MFACode:     req.MFACode,
+		RememberMe:  req.RememberMe,
 		IPAddress:   clientIP,
 		UserAgent:   userAgent,
 	})
@@ -99,23 +113,34 @@ func (h *AuthHandler) ValidateCredentials(c *gin.Context) {
 		// NOTE: Staff authentication should go through Keycloak, not tenant_credentials.
 		// Staff members have their passwords stored in Keycloak during account activation.
 		// The auth-bff handles Keycloak authentication for all users including staff.
-
-		// Return authentication failure with error details
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success":            false,
-			"valid":              false,
-			"error_code":         result.ErrorCode,
-			"message":            result.ErrorMessage,
-			"account_locked":     result.AccountLocked,
-			"locked_until":       result.LockedUntil,
-			"remaining_attempts": result.RemainingAttempts,
-			"tenant_id":          result.TenantID,
-			"tenant_slug":        result.TenantSlug,
-		})
+		writeLoginFailure(c, result)
 		return
 	}
 
-	// Return successful authentication
+	SuccessResponse(c, http.StatusOK, "Credentials validated successfully", loginSuccessResponse(result))
+}
+
+// writeLoginFailure returns a failed password or passkey login with its error details
+func writeLoginFailure(c *gin.Context, result *services.ValidateCredentialsResponse) {
+	c.JSON(http.StatusUnauthorized, gin.H{
+		"success":            false,
+		"valid":              false,
+		"error_code":         result.ErrorCode,
+		"message":            result.ErrorMessage,
+		"account_locked":     result.AccountLocked,
+		"locked_until":       result.LockedUntil,
+		"remaining_attempts": result.RemainingAttempts,
+		"mfa_required":       result.MFARequired,
+		"passkey_required":   result.PasskeyRequired,
+		"tenant_id":          result.TenantID,
+		"tenant_slug":        result.TenantSlug,
+		"sso_required":       result.SSO != nil,
+		"sso":                result.SSO, // idp_hint to redirect the login to the tenant's IdP
+	})
+}
+
+// loginSuccessResponse builds the response of a successful password or passkey login
+func loginSuccessResponse(result *services.ValidateCredentialsResponse) gin.H {
 	response := gin.H{
 		"valid":        true,
 		"user_id":      result.UserID,
@@ -128,22 +153,49 @@ func (h *AuthHandler) ValidateCredentials(c *gin.Context) {
 		"mfa_required": result.MFARequired,
 		"mfa_enabled":  result.MFAEnabled,
 	}
+	if result.MFAEnrollmentRequired {
+		response["mfa_enrollment_required"] = true
+	}
+	if result.PasswordChangeRequired {
+		response["password_change_required"] = true
+	}
+	if result.PasskeyEnrollmentRequired {
+		response["passkey_enrollment_required"] = true
+	}
+	if result.PasskeyEnrollmentSuggested {
+		response["passkey_enrollment_suggested"] = true
+	}
 
-	// Include tokens if they were obtained (direct grant)
+	// Include tokens if they were obtained (direct grant or token exchange)
 	if result.AccessToken != "" {
 		response["access_token"] = result.AccessToken
 		response["refresh_token"] = result.RefreshToken
 		response["id_token"] = result.IDToken
 		response["expires_in"] = result.ExpiresIn
+		if result.RefreshExpiresIn > 0 {
+			response["refresh_expires_in"] = result.RefreshExpiresIn
+		}
+		if result.SessionID != "" {
+			response["session_id"] = result.SessionID
+		}
 	}
-
-	SuccessResponse(c, http.StatusOK, "Credentials validated successfully", response)
+	return response
 }
 
 // GetUserTenantsForAuth returns all tenants a user has access to for login selection
 // POST /api/v1/auth/tenants
 // This endpoint is called when a user enters their email to show tenant selection
 // It combines tenants from both tenant_users (owners/admins) and staff (employees)
+// @Summary List tenants for login
+// @Description Returns the tenants an email can sign in to, combining owner/admin memberships and staff records, for the tenant picker
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body object true "{\"email\": \"user@example.com\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/auth/tenants [post]
 func (h *AuthHandler) GetUserTenantsForAuth(c *gin.Context) {
 	var req struct {
 		Email string `json:"email" binding:"required,email"`
@@ -218,6 +270,17 @@ type ChangePasswordRequest struct {
 
 // ChangePassword changes a user's password for a specific tenant
 // POST /api/v1/auth/change-password
+// @Summary Change password
+// @Description Changes the current user's password for a tenant after checking the current one
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "Passwords"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/auth/change-password [post]
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	var req ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -250,6 +313,10 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 
 	// Change password
 	if err := h.authSvc.ChangePassword(c.Request.Context(), userID, tenantID, req.CurrentPassword, req.NewPassword, &userID); err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
 		ErrorResponse(c, http.StatusBadRequest, "Failed to change password", err)
 		return
 	}
@@ -266,6 +333,17 @@ type SetPasswordRequest struct {
 // SetPassword sets a password for a user in a specific tenant
 // This is used during password reset flow
 // POST /api/v1/auth/set-password
+// @Summary Set password
+// @Description Sets the current user's password for a tenant, e.g. after verification or during the reset flow
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SetPasswordRequest true "Password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/auth/set-password [post]
 func (h *AuthHandler) SetPassword(c *gin.Context) {
 	var req SetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -298,6 +376,10 @@ func (h *AuthHandler) SetPassword(c *gin.Context) {
 
 	// Set password
 	if err := h.authSvc.SetPassword(c.Request.Context(), userID, tenantID, req.Password, &userID); err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
 		ErrorResponse(c, http.StatusBadRequest, "Failed to set password", err)
 		return
 	}
@@ -313,6 +395,18 @@ type UnlockAccountRequest struct {
 
 // UnlockAccount unlocks a locked user account (admin operation)
 // POST /api/v1/auth/unlock-account
+// @Summary Unlock account
+// @Description Unlocks a user locked out of a tenant after failed logins (tenant admins only)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UnlockAccountRequest true "Account to unlock"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/auth/unlock-account [post]
 func (h *AuthHandler) UnlockAccount(c *gin.Context) {
 	var req UnlockAccountRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -370,6 +464,15 @@ func (h *AuthHandler) UnlockAccount(c *gin.Context) {
 
 // CheckAccountStatus returns the account status for a user in a tenant
 // POST /api/v1/auth/account-status
+// @Summary Check account status
+// @Description Reports whether an account is locked in a tenant and until when
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body object true "{\"email\": \"user@example.com\", \"tenant_slug\": \"acme\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/auth/account-status [post]
 func (h *AuthHandler) CheckAccountStatus(c *gin.Context) {
 	var req struct {
 		Email      string `json:"email" binding:"required,email"`
@@ -380,17 +483,9 @@ func (h *AuthHandler) CheckAccountStatus(c *gin.Context) {
 		return
 	}
 
-	// This is a lightweight check without full credential validation
+	// This is a lightweight check without credential validation; it does not count as a login attempt
 	// Used to show users if their account is locked before they enter password
-	result, err := h.authSvc.ValidateCredentials(c.Request.Context(), &services.ValidateCredentialsRequest{
-		Email:                  req.Email,
-		Password:               "dummy-password-for-status-check", // Will be ignored
-		TenantSlug:             req.TenantSlug,
-		IPAddress:              c.ClientIP(),
-		UserAgent:              c.GetHeader("User-Agent"),
-		SkipPasswordValidation: true,
-	})
-
+	status, err := h.authSvc.GetAccountStatus(c.Request.Context(), req.Email, req.TenantSlug, c.ClientIP())
 	if err != nil {
 		// Return generic response to avoid info leakage
 		c.JSON(http.StatusOK, gin.H{
@@ -403,10 +498,10 @@ func (h *AuthHandler) CheckAccountStatus(c *gin.Context) {
 	// Only return account locked status, not whether credentials are valid
 	c.JSON(http.StatusOK, gin.H{
 		"success":            true,
-		"account_exists":     result.ErrorCode != "USER_NOT_FOUND" && result.ErrorCode != "NO_ACCESS",
-		"account_locked":     result.AccountLocked,
-		"locked_until":       result.LockedUntil,
-		"remaining_attempts": result.RemainingAttempts,
+		"account_exists":     status.AccountExists,
+		"account_locked":     status.AccountLocked,
+		"locked_until":       status.LockedUntil,
+		"remaining_attempts": status.RemainingAttempts,
 	})
 }
 
@@ -432,6 +527,16 @@ type AcceptInvitationPublicRequest struct {
 
 // RegisterCustomer registers a new customer for storefront direct registration
 // POST /api/v1/auth/register
+// @Summary Register customer
+// @Description Registers a storefront customer directly in a tenant
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RegisterCustomerRequest true "Customer"
+// @Success 201 {object} services.RegisterCustomerResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/auth/register [post]
 func (h *AuthHandler) RegisterCustomer(c *gin.Context) {
 	var req RegisterCustomerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -492,6 +597,12 @@ func (h *AuthHandler) RegisterCustomer(c *gin.Context) {
 		response["refresh_token"] = result.RefreshToken
 		response["id_token"] = result.IDToken
 		response["expires_in"] = result.ExpiresIn
+		if result.RefreshExpiresIn > 0 {
+			response["refresh_expires_in"] = result.RefreshExpiresIn
+		}
+		if result.SessionID != "" {
+			response["session_id"] = result.SessionID
+		}
 	}
 
 	SuccessResponse(c, http.StatusCreated, "Customer registered successfully", response)
@@ -499,6 +610,15 @@ func (h *AuthHandler) RegisterCustomer(c *gin.Context) {
 
 // AcceptInvitationPublic accepts a tenant invitation and creates the user if needed
 // POST /api/v1/invitations/accept-public
+// @Summary Accept invitation without a session
+// @Description Accepts a tenant invitation by token, creating the user with the given password when they do not exist yet
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Param request body AcceptInvitationPublicRequest true "Invitation token and password"
+// @Success 200 {object} services.AcceptInvitationPublicResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/invitations/accept-public [post]
 func (h *AuthHandler) AcceptInvitationPublic(c *gin.Context) {
 	var req AcceptInvitationPublicRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -532,6 +652,18 @@ type DeactivateAccountRequest struct {
 
 // DeactivateAccount handles customer self-service account deactivation
 // POST /api/v1/auth/deactivate-account
+// @Summary Deactivate account
+// @Description Deactivates the current customer's account; it can be reactivated within 90 days
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeactivateAccountRequest true "Deactivation"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/auth/deactivate-account [post]
 func (h *AuthHandler) DeactivateAccount(c *gin.Context) {
 	if h.deactivationSvc == nil {
 		ErrorResponse(c, http.StatusServiceUnavailable, "Deactivation service not available", nil)
@@ -607,6 +739,16 @@ type CheckDeactivatedRequest struct {
 
 // CheckDeactivatedAccount checks if an account is in deactivated state
 // POST /api/v1/auth/check-deactivated
+// @Summary Check deactivated account
+// @Description Reports whether an account is deactivated and can still be reactivated
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body CheckDeactivatedRequest true "Account"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/auth/check-deactivated [post]
 func (h *AuthHandler) CheckDeactivatedAccount(c *gin.Context) {
 	if h.deactivationSvc == nil {
 		ErrorResponse(c, http.StatusServiceUnavailable, "Deactivation service not available", nil)
@@ -645,6 +787,17 @@ type ReactivateAccountRequest struct {
 
 // ReactivateAccount reactivates a deactivated account
 // POST /api/v1/auth/reactivate-account
+// @Summary Reactivate account
+// @Description Reactivates a deactivated account within the 90-day window
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ReactivateAccountRequest true "Account and password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/auth/reactivate-account [post]
 func (h *AuthHandler) ReactivateAccount(c *gin.Context) {
 	if h.deactivationSvc == nil {
 		ErrorResponse(c, http.StatusServiceUnavailable, "Deactivation service not available", nil)
@@ -697,6 +850,16 @@ type RequestPasswordResetRequest struct {
 
 // RequestPasswordReset initiates the password reset flow
 // POST /api/v1/auth/request-password-reset
+// @Summary Request password reset
+// @Description Emails a password reset link; always succeeds so registered emails cannot be discovered
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RequestPasswordResetRequest true "Email and tenant"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/auth/request-password-reset [post]
 func (h *AuthHandler) RequestPasswordReset(c *gin.Context) {
 	if h.passwordResetSvc == nil {
 		ErrorResponse(c, http.StatusServiceUnavailable, "Password reset service not available", nil)
@@ -743,6 +906,15 @@ type ValidateResetTokenRequest struct {
 
 // ValidateResetToken validates a password reset token
 // POST /api/v1/auth/validate-reset-token
+// @Summary Validate password reset token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ValidateResetTokenRequest true "Reset token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/auth/validate-reset-token [post]
 func (h *AuthHandler) ValidateResetToken(c *gin.Context) {
 	if h.passwordResetSvc == nil {
 		ErrorResponse(c, http.StatusServiceUnavailable, "Password reset service not available", nil)
@@ -784,6 +956,16 @@ type ResetPasswordHandlerRequest struct {
 
 // ResetPassword resets the password using a valid token
 // POST /api/v1/auth/reset-password
+// @Summary Reset password
+// @Description Sets a new password using a valid reset token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordHandlerRequest true "Reset token and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/auth/reset-password [post]
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	if h.passwordResetSvc == nil {
 		ErrorResponse(c, http.StatusServiceUnavailable, "Password reset service not available", nil)
@@ -1019,6 +1201,16 @@ type SyncCustomersToEventsRequest struct {
 // SyncCustomersToEvents triggers sync of existing customer users to customer.registered events
 // POST /internal/sync-customers
 // This is an internal endpoint used for one-time migration when deploying to production
+// @Summary Sync customers to events
+// @Description Publishes customer.registered events for existing customers, optionally of one tenant (internal one-time migration)
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param request body SyncCustomersToEventsRequest false "Tenant to sync"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /internal/sync-customers [post]
 func (h *AuthHandler) SyncCustomersToEvents(c *gin.Context) {
 	var req SyncCustomersToEventsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
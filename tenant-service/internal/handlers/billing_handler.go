@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"tenant-service/internal/services"
+)
+
+// maxBillingWebhookBytes bounds the body read before the signature is checked
+const maxBillingWebhookBytes = 1 << 20
+
+// BillingHandler receives subscription events from the billing provider
+type BillingHandler struct {
+	billingService *services.BillingService
+}
+
+// NewBillingHandler creates a new billing handler
+func NewBillingHandler(billingService *services.BillingService) *BillingHandler {
+	return &BillingHandler{billingService: billingService}
+}
+
+// HandleWebhook applies a signed billing provider event
+// @Summary Billing provider webhook
+// @Description Applies trial_started, plan_changed, payment_failed, payment_succeeded and subscription_cancelled events.
+// @Description X-Billing-Signature is the hex HMAC-SHA256 of "<X-Billing-Timestamp>.<body>" with the shared webhook secret.
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Param X-Billing-Timestamp header string true "Unix time the event was signed"
+// @Param X-Billing-Signature header string true "Signature, optionally prefixed with sha256="
+// @Param request body services.BillingWebhookEvent true "Billing event"
+// @Success 200 {object} services.BillingEventResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/webhooks/billing [post]
+func (h *BillingHandler) HandleWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBillingWebhookBytes))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Failed to read request body", err)
+		return
+	}
+
+	result, err := h.billingService.HandleWebhook(c.Request.Context(), payload,
+		c.GetHeader("X-Billing-Timestamp"), c.GetHeader("X-Billing-Signature"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Billing event processed", result)
+}
+
+// respondError maps billing webhook errors to status codes
+func (h *BillingHandler) respondError(c *gin.Context, err error) {
+	if validationErr, ok := services.IsValidationError(err); ok {
+		ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrBillingWebhookDisabled):
+		ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	case errors.Is(err, services.ErrBillingSignatureInvalid):
+		ErrorResponse(c, http.StatusUnauthorized, err.Error(), nil)
+	case errors.Is(err, services.ErrPlanNotFound):
+		ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	case err.Error() == "tenant not found":
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to process billing event", err)
+	}
+}
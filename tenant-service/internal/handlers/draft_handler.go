@@ -19,6 +19,16 @@ func NewDraftHandler(draftSvc *services.DraftService) *DraftHandler {
 }
 
 // SaveDraft handles POST /api/v1/onboarding/draft/save
+// @Summary Save onboarding draft
+// @Description Stores the partially filled onboarding form of a session
+// @Tags drafts
+// @Accept json
+// @Produce json
+// @Param request body services.SaveDraftRequest true "Draft"
+// @Success 200 {object} services.SaveDraftResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/draft/save [post]
 func (h *DraftHandler) SaveDraft(c *gin.Context) {
 	var req services.SaveDraftRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -42,6 +52,14 @@ func (h *DraftHandler) SaveDraft(c *gin.Context) {
 }
 
 // GetDraft handles GET /api/v1/onboarding/draft/:sessionId
+// @Summary Get onboarding draft
+// @Tags drafts
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Success 200 {object} services.GetDraftResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/draft/{sessionId} [get]
 func (h *DraftHandler) GetDraft(c *gin.Context) {
 	sessionIDStr := c.Param("sessionId")
 	sessionID, err := uuid.Parse(sessionIDStr)
@@ -65,6 +83,16 @@ func (h *DraftHandler) GetDraft(c *gin.Context) {
 }
 
 // ProcessHeartbeat handles POST /api/v1/onboarding/draft/heartbeat
+// @Summary Draft heartbeat
+// @Description Keeps a session's draft alive while the onboarding form is open
+// @Tags drafts
+// @Accept json
+// @Produce json
+// @Param request body object true "{\"session_id\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/draft/heartbeat [post]
 func (h *DraftHandler) ProcessHeartbeat(c *gin.Context) {
 	var req struct {
 		SessionID uuid.UUID `json:"session_id" binding:"required"`
@@ -84,6 +112,16 @@ func (h *DraftHandler) ProcessHeartbeat(c *gin.Context) {
 }
 
 // MarkBrowserClosed handles POST /api/v1/onboarding/draft/browser-close
+// @Summary Record browser close
+// @Description Records that the onboarding form was closed so reminder emails can be scheduled
+// @Tags drafts
+// @Accept json
+// @Produce json
+// @Param request body object true "{\"session_id\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/draft/browser-close [post]
 func (h *DraftHandler) MarkBrowserClosed(c *gin.Context) {
 	var req struct {
 		SessionID uuid.UUID `json:"session_id" binding:"required"`
@@ -103,6 +141,14 @@ func (h *DraftHandler) MarkBrowserClosed(c *gin.Context) {
 }
 
 // DeleteDraft handles DELETE /api/v1/onboarding/draft/:sessionId
+// @Summary Delete onboarding draft
+// @Tags drafts
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/draft/{sessionId} [delete]
 func (h *DraftHandler) DeleteDraft(c *gin.Context) {
 	sessionIDStr := c.Param("sessionId")
 	sessionID, err := uuid.Parse(sessionIDStr)
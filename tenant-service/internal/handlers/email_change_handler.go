@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// EmailChangeHandler lets users change their login email after verifying the new address
+type EmailChangeHandler struct {
+	authSvc *services.TenantAuthService
+}
+
+// NewEmailChangeHandler creates a new email change handler
+func NewEmailChangeHandler(authSvc *services.TenantAuthService) *EmailChangeHandler {
+	return &EmailChangeHandler{authSvc: authSvc}
+}
+
+// RequestEmailChangeRequest represents a request to change the current user's login email
+type RequestEmailChangeRequest struct {
+	TenantID        string `json:"tenant_id" binding:"required"`
+	NewEmail        string `json:"new_email" binding:"required,email"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+// ConfirmEmailChangeRequest represents the confirmation of an email change
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// RequestEmailChange sends a confirmation link to the new address
+// POST /api/v1/auth/email-change
+// @Summary Request login email change
+// @Description Re-checks the current password and sends a confirmation link to the new address; the current email keeps working until it is followed
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RequestEmailChangeRequest true "New email and current password"
+// @Success 202 {object} services.EmailChangeRequestResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/auth/email-change [post]
+func (h *EmailChangeHandler) RequestEmailChange(c *gin.Context) {
+	var req RequestEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", nil)
+		return
+	}
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", nil)
+		return
+	}
+
+	result, err := h.authSvc.RequestEmailChange(c.Request.Context(), &services.RequestEmailChangeInput{
+		UserID:          userID,
+		TenantID:        tenantID,
+		NewEmail:        req.NewEmail,
+		CurrentPassword: req.CurrentPassword,
+		IPAddress:       c.ClientIP(),
+		UserAgent:       c.Request.UserAgent(),
+	})
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		if conflictErr, ok := services.IsConflictError(err); ok {
+			ErrorResponse(c, http.StatusConflict, conflictErr.Message, err)
+			return
+		}
+		if errors.Is(err, services.ErrEmailChangeNoMembership) {
+			ErrorResponse(c, http.StatusForbidden, "You are not a member of this tenant", nil)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to request email change", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusAccepted, "Confirmation link sent to the new email address", result)
+}
+
+// ConfirmEmailChange applies the change with the token from the emailed link
+// POST /api/v1/auth/email-change/confirm
+// @Summary Confirm login email change
+// @Description Applies the email change with the token from the confirmation link
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ConfirmEmailChangeRequest true "Confirmation token"
+// @Success 200 {object} services.EmailChangeConfirmResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/auth/email-change/confirm [post]
+func (h *EmailChangeHandler) ConfirmEmailChange(c *gin.Context) {
+	var req ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	result, err := h.authSvc.ConfirmEmailChange(c.Request.Context(), &services.ConfirmEmailChangeInput{
+		Token:     req.Token,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrEmailChangeInvalidToken) {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid or expired link. Please request the email change again.", nil)
+			return
+		}
+		if conflictErr, ok := services.IsConflictError(err); ok {
+			ErrorResponse(c, http.StatusConflict, conflictErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to change email", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Email changed. Sign in with your new email from now on.", result)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// EncryptionKeyHandler handles per-tenant data-encryption key management
+type EncryptionKeyHandler struct {
+	keyService *services.TenantKeyService
+}
+
+// NewEncryptionKeyHandler creates a new encryption key handler
+func NewEncryptionKeyHandler(keyService *services.TenantKeyService) *EncryptionKeyHandler {
+	return &EncryptionKeyHandler{keyService: keyService}
+}
+
+// GetEncryptionKeys returns the tenant's key versions and re-encryption progress
+// @Summary Get tenant encryption keys
+// @Description List data-encryption key versions and how many credential rows still await re-encryption (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.TenantKeyStatus
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/encryption-keys [get]
+func (h *EncryptionKeyHandler) GetEncryptionKeys(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.keyService.GetKeyStatus(c.Request.Context(), tenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get encryption keys", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Encryption keys retrieved", status)
+}
+
+// RotateEncryptionKey creates a new key version and re-encrypts credentials in the background
+// @Summary Rotate tenant encryption key
+// @Description Create a new data-encryption key version; existing credential rows are re-encrypted in the background (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 202 {object} models.TenantEncryptionKey
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/encryption-keys/rotate [post]
+func (h *EncryptionKeyHandler) RotateEncryptionKey(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.keyService.RotateKey(c.Request.Context(), tenantID, &userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to rotate encryption key", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusAccepted, "Encryption key rotated; re-encryption started", key)
+}
+
+// authorize resolves the tenant and user and checks the user may manage keys
+func (h *EncryptionKeyHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	if h.keyService == nil {
+		ErrorResponse(c, http.StatusServiceUnavailable, "Tenant encryption keys are not configured", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.keyService.AuthorizeKeyManagement(c.Request.Context(), tenantID, userID); err != nil {
+		if errors.Is(err, services.ErrKeyManagementForbidden) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return uuid.Nil, uuid.Nil, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to verify permissions", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"runtime"
 	"time"
@@ -16,6 +17,12 @@ var startTime = time.Now()
 type HealthHandler struct {
 	db         *gorm.DB
 	natsClient *natsClient.Client
+	redis      redisChecker // optional
+}
+
+// redisChecker pings Redis; the tenant-service Redis client implements it
+type redisChecker interface {
+	Check(ctx context.Context) error
 }
 
 // NewHealthHandler creates a new health handler
@@ -33,6 +40,11 @@ func NewHealthHandlerWithNATS(db *gorm.DB, nc *natsClient.Client) *HealthHandler
 	}
 }
 
+// SetRedis adds a Redis check to the detailed health and readiness reports
+func (h *HealthHandler) SetRedis(redis redisChecker) {
+	h.redis = redis
+}
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status    string           `json:"status"`
@@ -99,9 +111,35 @@ func (h *HealthHandler) performHealthChecks() map[string]Check {
 	natsCheck := h.checkNATS()
 	checks["nats"] = natsCheck
 
+	// Redis check
+	checks["redis"] = h.checkRedis(context.Background())
+
 	return checks
 }
 
+// checkRedis checks Redis connectivity. Redis only caches, so a failure is reported as
+// degraded rather than unhealthy.
+func (h *HealthHandler) checkRedis(ctx context.Context) Check {
+	if h.redis == nil {
+		return Check{
+			Status:  "disabled",
+			Message: "Redis not configured or unreachable at startup",
+		}
+	}
+
+	if err := h.redis.Check(ctx); err != nil {
+		return Check{
+			Status:  "degraded",
+			Message: "Redis ping failed",
+		}
+	}
+
+	return Check{
+		Status:  "healthy",
+		Message: "Redis connected",
+	}
+}
+
 // checkNATS checks NATS connectivity
 func (h *HealthHandler) checkNATS() Check {
 	if h.natsClient == nil {
@@ -210,6 +248,9 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 		allReady = false
 	}
 
+	// Redis check (optional - drafts, sessions and lookups fall back to PostgreSQL)
+	response.Checks["redis"] = h.checkRedis(c.Request.Context())
+
 	// Set overall status
 	if allReady {
 		response.Status = "ready"
@@ -0,0 +1,409 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// Claim headers Istio sets from the validated JWT. Domain join reads them directly rather than
+// through IstioAuth, which still falls back to the caller-supplied X-User-* headers.
+const (
+	jwtClaimSubHeader           = "x-jwt-claim-sub"
+	jwtClaimEmailHeader         = "x-jwt-claim-email"
+	jwtClaimEmailVerifiedHeader = "x-jwt-claim-email-verified"
+)
+
+// JoinDomainHandler handles email domain auto-join: verified domains, the approval queue and
+// the join performed on a user's login
+type JoinDomainHandler struct {
+	joinDomainService *services.JoinDomainService
+}
+
+// NewJoinDomainHandler creates a new join domain handler
+func NewJoinDomainHandler(joinDomainService *services.JoinDomainService) *JoinDomainHandler {
+	return &JoinDomainHandler{joinDomainService: joinDomainService}
+}
+
+// DomainJoinRequest carries the profile used when the login has no tenant user yet
+type DomainJoinRequest struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// ListJoinDomains returns the tenant's join domains
+// @Summary List join domains
+// @Description Returns the email domains whose users may join the tenant, with the TXT record to publish for pending DNS verification (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {array} services.JoinDomainResponse
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/join-domains [get]
+func (h *JoinDomainHandler) ListJoinDomains(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	domains, err := h.joinDomainService.ListDomains(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list join domains")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Join domains retrieved", domains)
+}
+
+// AddJoinDomain claims an email domain for auto-join
+// @Summary Add join domain
+// @Description Starts verification of an email domain. dns_txt returns the TXT record to publish; email sends a code to verification_email, which must be an address at the domain. Public email providers cannot be claimed (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.AddJoinDomainRequest true "Join domain"
+// @Success 201 {object} services.JoinDomainResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/join-domains [post]
+func (h *JoinDomainHandler) AddJoinDomain(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req services.AddJoinDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	domain, err := h.joinDomainService.AddDomain(c.Request.Context(), tenantID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to add join domain")
+		return
+	}
+	SuccessResponse(c, http.StatusCreated, "Join domain added", domain)
+}
+
+// VerifyJoinDomain checks the TXT record or emailed code
+// @Summary Verify join domain
+// @Description Looks up the TXT record through custom-domain-service, or checks the emailed code, and marks the domain verified. A domain can only be verified by one tenant (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param domainId path string true "Join domain ID"
+// @Param request body services.VerifyJoinDomainRequest false "Emailed code"
+// @Success 200 {object} services.JoinDomainResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/join-domains/{domainId}/verify [post]
+func (h *JoinDomainHandler) VerifyJoinDomain(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	domainID, ok := parseUUIDParam(c, "domainId", "Invalid join domain ID format")
+	if !ok {
+		return
+	}
+
+	var req services.VerifyJoinDomainRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+			return
+		}
+	}
+
+	domain, err := h.joinDomainService.VerifyDomain(c.Request.Context(), tenantID, domainID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to verify join domain")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Join domain verified", domain)
+}
+
+// ResendJoinDomainCode sends a new verification code
+// @Summary Resend join domain verification code
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param domainId path string true "Join domain ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/join-domains/{domainId}/resend-code [post]
+func (h *JoinDomainHandler) ResendJoinDomainCode(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	domainID, ok := parseUUIDParam(c, "domainId", "Invalid join domain ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.joinDomainService.ResendCode(c.Request.Context(), tenantID, domainID); err != nil {
+		h.respondError(c, err, "Failed to resend verification code")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Verification code sent", nil)
+}
+
+// UpdateJoinDomain changes how a join domain admits users
+// @Summary Update join domain
+// @Description Sets join_mode (auto or approval), default_role (manager, member or viewer) or enabled (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param domainId path string true "Join domain ID"
+// @Param request body services.UpdateJoinDomainRequest true "Join policy"
+// @Success 200 {object} services.JoinDomainResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/join-domains/{domainId} [patch]
+func (h *JoinDomainHandler) UpdateJoinDomain(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	domainID, ok := parseUUIDParam(c, "domainId", "Invalid join domain ID format")
+	if !ok {
+		return
+	}
+
+	var req services.UpdateJoinDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	domain, err := h.joinDomainService.UpdateDomain(c.Request.Context(), tenantID, domainID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update join domain")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Join domain updated", domain)
+}
+
+// DeleteJoinDomain removes a join domain
+// @Summary Delete join domain
+// @Description Stops admitting users from the domain and drops its pending join requests; existing members are kept (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param domainId path string true "Join domain ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/join-domains/{domainId} [delete]
+func (h *JoinDomainHandler) DeleteJoinDomain(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	domainID, ok := parseUUIDParam(c, "domainId", "Invalid join domain ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.joinDomainService.DeleteDomain(c.Request.Context(), tenantID, domainID); err != nil {
+		h.respondError(c, err, "Failed to delete join domain")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Join domain deleted", nil)
+}
+
+// ListJoinRequests returns the tenant's join requests
+// @Summary List join requests
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param status query string false "pending, approved or rejected"
+// @Success 200 {array} models.TenantJoinRequest
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/join-requests [get]
+func (h *JoinDomainHandler) ListJoinRequests(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	requests, err := h.joinDomainService.ListJoinRequests(c.Request.Context(), tenantID, c.Query("status"))
+	if err != nil {
+		h.respondError(c, err, "Failed to list join requests")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Join requests retrieved", requests)
+}
+
+// ApproveJoinRequest admits the requester with the domain's default role
+// @Summary Approve join request
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param requestId path string true "Join request ID"
+// @Success 200 {object} models.TenantJoinRequest
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/join-requests/{requestId}/approve [post]
+func (h *JoinDomainHandler) ApproveJoinRequest(c *gin.Context) {
+	h.reviewJoinRequest(c, true)
+}
+
+// RejectJoinRequest declines a join request
+// @Summary Reject join request
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param requestId path string true "Join request ID"
+// @Success 200 {object} models.TenantJoinRequest
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/join-requests/{requestId}/reject [post]
+func (h *JoinDomainHandler) RejectJoinRequest(c *gin.Context) {
+	h.reviewJoinRequest(c, false)
+}
+
+// JoinByEmailDomain joins the caller to tenants that verified their email domain
+// @Summary Join tenants by email domain
+// @Description Called by auth-bff on login. Creates memberships in tenants that verified the caller's email domain in auto mode and opens join requests for tenants in approval mode. The email is taken from the JWT and must be verified
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DomainJoinRequest false "Profile for a new tenant user"
+// @Success 200 {object} services.DomainJoinResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/auth/domain-join [post]
+func (h *JoinDomainHandler) JoinByEmailDomain(c *gin.Context) {
+	// The email decides which tenants the caller joins, so only the JWT's own claims count
+	userIDStr := c.GetHeader(jwtClaimSubHeader)
+	email := strings.TrimSpace(c.GetHeader(jwtClaimEmailHeader))
+	if userIDStr == "" || email == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "A login token with an email claim is required", nil)
+		return
+	}
+	keycloakID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return
+	}
+	if !strings.EqualFold(c.GetHeader(jwtClaimEmailVerifiedHeader), "true") {
+		ErrorResponse(c, http.StatusForbidden, "Email address must be verified to join by email domain", nil)
+		return
+	}
+
+	var req DomainJoinRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+			return
+		}
+	}
+
+	result, err := h.joinDomainService.JoinByEmailDomain(c.Request.Context(), keycloakID, email, req.FirstName, req.LastName)
+	if err != nil {
+		h.respondError(c, err, "Failed to join by email domain")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Email domain join processed", result)
+}
+
+func (h *JoinDomainHandler) reviewJoinRequest(c *gin.Context, approve bool) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	requestID, ok := parseUUIDParam(c, "requestId", "Invalid join request ID format")
+	if !ok {
+		return
+	}
+
+	request, err := h.joinDomainService.ReviewJoinRequest(c.Request.Context(), tenantID, requestID, userID, approve)
+	if err != nil {
+		h.respondError(c, err, "Failed to review join request")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Join request "+request.Status, request)
+}
+
+// respondError maps join domain service errors to HTTP responses
+func (h *JoinDomainHandler) respondError(c *gin.Context, err error, fallback string) {
+	if validationErr, ok := services.IsValidationError(err); ok {
+		ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrJoinDomainNotFound), errors.Is(err, services.ErrJoinRequestNotFound):
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrJoinDomainVerificationUnavailable):
+		ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, fallback, err)
+	}
+}
+
+// authorize resolves the tenant and user and checks the user may manage join domains
+func (h *JoinDomainHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.joinDomainService.AuthorizeManage(c.Request.Context(), tenantID, userID); err != nil {
+		if errors.Is(err, services.ErrJoinDomainForbidden) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return uuid.Nil, uuid.Nil, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to verify permissions", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
+
+func parseUUIDParam(c *gin.Context, name, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, message, err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
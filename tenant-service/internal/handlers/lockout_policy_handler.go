@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// LockoutPolicyHandler handles per-tenant login lockout policy management
+type LockoutPolicyHandler struct {
+	policyService *services.LockoutPolicyService
+}
+
+// NewLockoutPolicyHandler creates a new lockout policy handler
+func NewLockoutPolicyHandler(policyService *services.LockoutPolicyService) *LockoutPolicyHandler {
+	return &LockoutPolicyHandler{policyService: policyService}
+}
+
+// GetLockoutPolicy returns the tenant's lockout policy
+// @Summary Get tenant lockout policy
+// @Description Returns the failed-login thresholds and tiered lockout durations; defaults when the tenant has none (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.LockoutPolicy
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/lockout-policy [get]
+func (h *LockoutPolicyHandler) GetLockoutPolicy(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.policyService.GetPolicy(c.Request.Context(), tenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get lockout policy", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Lockout policy retrieved", policy)
+}
+
+// UpdateLockoutPolicy changes the tenant's lockout policy
+// @Summary Update tenant lockout policy
+// @Description Set failures per tier (max_attempts), progressive tiers, tier durations in minutes and the reset window; omitted fields are unchanged (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.UpdateLockoutPolicyRequest true "Policy changes"
+// @Success 200 {object} services.LockoutPolicy
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/lockout-policy [put]
+func (h *LockoutPolicyHandler) UpdateLockoutPolicy(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateLockoutPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	policy, err := h.policyService.UpdatePolicy(c.Request.Context(), tenantID, userID, req)
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update lockout policy", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Lockout policy updated", policy)
+}
+
+// authorize resolves the tenant and user and checks the user may manage the lockout policy
+func (h *LockoutPolicyHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.policyService.AuthorizeManage(c.Request.Context(), tenantID, userID); err != nil {
+		if errors.Is(err, services.ErrLockoutPolicyForbidden) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return uuid.Nil, uuid.Nil, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to verify permissions", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
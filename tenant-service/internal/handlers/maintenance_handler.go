@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// MaintenanceHandler manages platform and per-tenant read-only maintenance mode
+type MaintenanceHandler struct {
+	maintenanceService *services.MaintenanceService
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(maintenanceService *services.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenanceService: maintenanceService}
+}
+
+// ListMaintenance returns every active maintenance flag
+// @Summary List maintenance flags
+// @Description Platform and tenant read-only flags that are currently active
+// @Tags internal
+// @Produce json
+// @Success 200 {array} models.MaintenanceFlag
+// @Router /internal/maintenance [get]
+func (h *MaintenanceHandler) ListMaintenance(c *gin.Context) {
+	flags, err := h.maintenanceService.List(c.Request.Context())
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to list maintenance flags", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Maintenance flags retrieved", flags)
+}
+
+// EnablePlatformMaintenance puts every tenant in read-only mode
+// @Summary Enable platform maintenance mode
+// @Description Reject write requests for all tenants with 503 until disabled or expired
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param request body services.EnableMaintenanceRequest true "Maintenance details"
+// @Success 200 {object} models.MaintenanceFlag
+// @Router /internal/maintenance/platform [put]
+func (h *MaintenanceHandler) EnablePlatformMaintenance(c *gin.Context) {
+	h.enable(c, nil)
+}
+
+// DisablePlatformMaintenance lifts platform read-only mode
+// @Summary Disable platform maintenance mode
+// @Tags internal
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /internal/maintenance/platform [delete]
+func (h *MaintenanceHandler) DisablePlatformMaintenance(c *gin.Context) {
+	h.disable(c, nil)
+}
+
+// GetTenantMaintenance returns the read-only state that applies to a tenant
+// @Summary Get tenant maintenance status
+// @Tags internal
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.MaintenanceStatus
+// @Router /internal/maintenance/tenants/{id} [get]
+func (h *MaintenanceHandler) GetTenantMaintenance(c *gin.Context) {
+	tenantID, ok := parseMaintenanceTenantID(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.maintenanceService.Status(c.Request.Context(), tenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get maintenance status", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Maintenance status retrieved", status)
+}
+
+// EnableTenantMaintenance puts one tenant in read-only mode
+// @Summary Enable tenant maintenance mode
+// @Description Reject write requests for the tenant with 503 until disabled or expired
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body services.EnableMaintenanceRequest true "Maintenance details"
+// @Success 200 {object} models.MaintenanceFlag
+// @Router /internal/maintenance/tenants/{id} [put]
+func (h *MaintenanceHandler) EnableTenantMaintenance(c *gin.Context) {
+	tenantID, ok := parseMaintenanceTenantID(c)
+	if !ok {
+		return
+	}
+	h.enable(c, &tenantID)
+}
+
+// DisableTenantMaintenance lifts read-only mode for one tenant
+// @Summary Disable tenant maintenance mode
+// @Tags internal
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /internal/maintenance/tenants/{id} [delete]
+func (h *MaintenanceHandler) DisableTenantMaintenance(c *gin.Context) {
+	tenantID, ok := parseMaintenanceTenantID(c)
+	if !ok {
+		return
+	}
+	h.disable(c, &tenantID)
+}
+
+func (h *MaintenanceHandler) enable(c *gin.Context, tenantID *uuid.UUID) {
+	var req services.EnableMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.EnabledBy == "" {
+		req.EnabledBy = c.GetHeader("X-Internal-Service")
+	}
+
+	flag, err := h.maintenanceService.Enable(c.Request.Context(), tenantID, req)
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to enable maintenance mode", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Maintenance mode enabled", flag)
+}
+
+func (h *MaintenanceHandler) disable(c *gin.Context, tenantID *uuid.UUID) {
+	if err := h.maintenanceService.Disable(c.Request.Context(), tenantID); err != nil {
+		if errors.Is(err, services.ErrMaintenanceFlagNotFound) {
+			ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to disable maintenance mode", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Maintenance mode disabled", nil)
+}
+
+func parseMaintenanceTenantID(c *gin.Context) (uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, false
+	}
+	return tenantID, true
+}
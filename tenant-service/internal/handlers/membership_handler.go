@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"tenant-service/internal/clients"
+	"tenant-service/internal/listquery"
 	"tenant-service/internal/services"
 	sharedMiddleware "github.com/Tesseract-Nexus/go-shared/middleware"
 )
@@ -38,6 +40,15 @@ func NewMembershipHandlerWithStaff(membershipSvc *services.MembershipService, st
 // GetUserTenants returns all tenants the current user has access to
 // For platform_owner users, returns all tenants in the platform
 // GET /api/v1/users/me/tenants
+// @Summary List my tenants
+// @Description Returns the tenants the current user belongs to, including staff tenants; platform owners get every tenant
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/users/me/tenants [get]
 func (h *MembershipHandler) GetUserTenants(c *gin.Context) {
 	// Get user ID from IstioAuth context (set by IstioAuth middleware from JWT claims)
 	userIDVal, _ := c.Get("user_id")
@@ -171,6 +182,14 @@ func (h *MembershipHandler) GetUserTenants(c *gin.Context) {
 
 // GetUserDefaultTenant returns the user's default tenant
 // GET /api/v1/users/me/tenants/default
+// @Summary Get my default tenant
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/users/me/tenants/default [get]
 func (h *MembershipHandler) GetUserDefaultTenant(c *gin.Context) {
 	// Get user ID from context (set by IstioAuth middleware from JWT claims)
 	userIDVal, _ := c.Get("user_id")
@@ -218,6 +237,17 @@ func (h *MembershipHandler) GetUserDefaultTenant(c *gin.Context) {
 
 // SetUserDefaultTenant sets the user's default tenant
 // PUT /api/v1/users/me/tenants/default
+// @Summary Set my default tenant
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body object true "{\"tenant_id\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/users/me/tenants/default [put]
 func (h *MembershipHandler) SetUserDefaultTenant(c *gin.Context) {
 	// Get user ID from context (set by IstioAuth middleware from JWT claims)
 	userIDVal, _ := c.Get("user_id")
@@ -270,6 +300,16 @@ func (h *MembershipHandler) SetUserDefaultTenant(c *gin.Context) {
 
 // GetTenantContext returns the context for accessing a specific tenant
 // GET /api/v1/tenants/:slug/context
+// @Summary Get tenant context
+// @Description Returns the tenant and the current user's role and permissions in it
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant slug"
+// @Success 200 {object} services.UserTenantContext
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/context [get]
 func (h *MembershipHandler) GetTenantContext(c *gin.Context) {
 	// Get user ID from context (set by IstioAuth middleware from JWT claims)
 	userIDVal, _ := c.Get("user_id")
@@ -307,6 +347,15 @@ func (h *MembershipHandler) GetTenantContext(c *gin.Context) {
 
 // VerifyTenantAccess checks if the user has access to a tenant
 // GET /api/v1/tenants/:id/access
+// @Summary Verify tenant access
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant slug"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/access [get]
 func (h *MembershipHandler) VerifyTenantAccess(c *gin.Context) {
 	// Get user ID from context (set by IstioAuth middleware from JWT claims)
 	userIDVal, _ := c.Get("user_id")
@@ -345,6 +394,20 @@ func (h *MembershipHandler) VerifyTenantAccess(c *gin.Context) {
 
 // InviteMember invites a new member to a tenant
 // POST /api/v1/tenants/:tenantId/members/invite
+// @Summary Invite member
+// @Description Invites someone to the tenant by email with a role
+// @Tags members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body object true "{\"email\": \"...\", \"role\": \"member\"}"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/members/invite [post]
 func (h *MembershipHandler) InviteMember(c *gin.Context) {
 	// Get user ID from context (set by IstioAuth middleware from JWT claims)
 	userIDVal, _ := c.Get("user_id")
@@ -388,6 +451,10 @@ func (h *MembershipHandler) InviteMember(c *gin.Context) {
 
 	resp, err := h.membershipSvc.InviteMember(c.Request.Context(), inviteReq)
 	if err != nil {
+		if errors.Is(err, services.ErrQuotaExceeded) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return
+		}
 		ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
@@ -398,8 +465,111 @@ func (h *MembershipHandler) InviteMember(c *gin.Context) {
 	})
 }
 
+// ImportMembers links or invites a batch of members from a CSV or JSON payload
+// POST /api/v1/tenants/:id/members/import
+// Accepts text/csv (header: email,role,name), a multipart "file" upload, or JSON {"members": [...]}.
+// Rows are processed independently and reported in a per-row result.
+// @Summary Import members
+// @Description Links or invites a batch of members from text/csv (header: email,role,name), a multipart "file" upload or JSON {"members": [...]}; rows are reported individually
+// @Tags members
+// @Accept json
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.MemberImportResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/members/import [post]
+func (h *MembershipHandler) ImportMembers(c *gin.Context) {
+	// Get user ID from context (set by IstioAuth middleware from JWT claims)
+	userIDVal, _ := c.Get("user_id")
+	userIDStr := ""
+	if userIDVal != nil {
+		userIDStr = userIDVal.(string)
+	}
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	importedBy, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", nil)
+		return
+	}
+
+	tenantIDStr := c.Param("id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", nil)
+		return
+	}
+
+	var rows []services.MemberImportRow
+	switch c.ContentType() {
+	case "text/csv", "application/csv":
+		rows, err = services.ParseMemberImportCSV(c.Request.Body)
+	case "multipart/form-data":
+		file, fileErr := c.FormFile("file")
+		if fileErr != nil {
+			ErrorResponse(c, http.StatusBadRequest, "CSV file is required", fileErr)
+			return
+		}
+		f, openErr := file.Open()
+		if openErr != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Failed to read CSV file", openErr)
+			return
+		}
+		defer f.Close()
+		rows, err = services.ParseMemberImportCSV(f)
+	default:
+		var req struct {
+			Members []services.MemberImportRow `json:"members" binding:"required"`
+		}
+		if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid request body", bindErr)
+			return
+		}
+		rows = req.Members
+	}
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusBadRequest, "Invalid import payload", err)
+		return
+	}
+
+	result, err := h.membershipSvc.ImportMembers(c.Request.Context(), tenantID, importedBy, rows)
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Members imported", result)
+}
+
 // AcceptInvitation accepts a member invitation
 // POST /api/v1/invitations/accept
+// @Summary Accept invitation
+// @Description Accepts an invitation for the signed-in user
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body object true "{\"token\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/invitations/accept [post]
 func (h *MembershipHandler) AcceptInvitation(c *gin.Context) {
 	// Get user ID from context (set by IstioAuth middleware from JWT claims)
 	userIDVal, _ := c.Get("user_id")
@@ -437,8 +607,299 @@ func (h *MembershipHandler) AcceptInvitation(c *gin.Context) {
 	})
 }
 
+// memberListQuery declares the sorting and filtering accepted by ListMembers
+var memberListQuery = listquery.Config{
+	Sorts: map[string]string{
+		"created_at":       "created_at",
+		"accepted_at":      "accepted_at",
+		"last_accessed_at": "last_accessed_at",
+		"role":             "role",
+	},
+	DefaultSort: "created_at",
+	Filters: map[string]listquery.Field{
+		"role":             {Column: "role", Ops: []string{listquery.OpEq, listquery.OpIn, listquery.OpNotIn}},
+		"is_active":        {Column: "is_active", Type: listquery.TypeBool},
+		"created_at":       {Column: "created_at", Type: listquery.TypeTime},
+		"last_accessed_at": {Column: "last_accessed_at", Type: listquery.TypeTime},
+	},
+}
+
+// ListMembers lists the tenant's members
+// GET /api/v1/tenants/:id/members?role[in]=admin,manager&is_active=true
+// @Summary List members
+// @Description Lists the tenant's members; supports page/limit or cursor pagination, sort=-created_at and filters such as role[in]=admin,manager
+// @Tags members
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param role query string false "Role"
+// @Param is_active query bool false "Only active or inactive members"
+// @Param sort query string false "Sort key (created_at, accepted_at, last_accessed_at, role), prefix with - for descending"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/members [get]
+func (h *MembershipHandler) ListMembers(c *gin.Context) {
+	tenantID, userID, ok := invitationActor(c)
+	if !ok {
+		return
+	}
+
+	q, err := listquery.Parse(c.Request.URL.Query(), memberListQuery)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	members, total, next, err := h.membershipSvc.ListMembers(c.Request.Context(), tenantID, userID, q)
+	if err != nil {
+		respondInvitationError(c, err, "Failed to list members")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Members retrieved", gin.H{
+		"members":    members,
+		"pagination": q.Meta(total, next),
+	})
+}
+
+// invitationListQuery declares the sorting and filtering accepted by ListInvitations
+var invitationListQuery = listquery.Config{
+	Sorts: map[string]string{
+		"invited_at":            "invited_at",
+		"invitation_expires_at": "invitation_expires_at",
+		"invited_email":         "invited_email",
+	},
+	DefaultSort: "-invited_at",
+	Filters: map[string]listquery.Field{
+		"role":          {Column: "role", Ops: []string{listquery.OpEq, listquery.OpIn}},
+		"invited_email": {Column: "invited_email", Ops: []string{listquery.OpEq, listquery.OpLike}},
+		"invited_at":    {Column: "invited_at", Type: listquery.TypeTime},
+	},
+}
+
+// ListInvitations lists the tenant's pending, expired and revoked invitations
+// GET /api/v1/tenants/:id/members/invitations?status=pending
+// @Summary List invitations
+// @Description Lists the tenant's unaccepted invitations; supports page/limit or cursor pagination, sort=-invited_at and filters such as role[in]=admin,manager
+// @Tags members
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param status query string false "pending, expired or revoked"
+// @Param sort query string false "Sort key (invited_at, invitation_expires_at, invited_email), prefix with - for descending"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/members/invitations [get]
+func (h *MembershipHandler) ListInvitations(c *gin.Context) {
+	tenantID, userID, ok := invitationActor(c)
+	if !ok {
+		return
+	}
+
+	q, err := listquery.Parse(c.Request.URL.Query(), invitationListQuery)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	invitations, total, next, err := h.membershipSvc.ListInvitations(c.Request.Context(), tenantID, userID, c.Query("status"), q)
+	if err != nil {
+		respondInvitationError(c, err, "Failed to list invitations")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Invitations retrieved", gin.H{
+		"invitations": invitations,
+		"total":       total,
+		"pagination":  q.Meta(total, next),
+	})
+}
+
+// activityListQuery declares the sorting and filtering accepted by ListActivity
+var activityListQuery = listquery.Config{
+	DefaultLimit: 50,
+	MaxLimit:     200,
+	Sorts: map[string]string{
+		"created_at": "created_at",
+	},
+	DefaultSort: "-created_at",
+	Filters: map[string]listquery.Field{
+		"action":        {Column: "action", Ops: []string{listquery.OpEq, listquery.OpIn, listquery.OpLike}},
+		"resource_type": {Column: "resource_type", Ops: []string{listquery.OpEq, listquery.OpIn}},
+		"resource_id":   {Column: "resource_id", Type: listquery.TypeUUID, Ops: []string{listquery.OpEq}},
+		"user_id":       {Column: "user_id", Type: listquery.TypeUUID, Ops: []string{listquery.OpEq, listquery.OpIn}},
+		"created_at":    {Column: "created_at", Type: listquery.TypeTime},
+	},
+}
+
+// ListActivity lists the tenant's activity log
+// GET /api/v1/tenants/:id/activity?action[like]=member.&created_at[gte]=2025-01-01
+// @Summary List tenant activity
+// @Description Lists the tenant's activity log (owners and admins); supports page/limit or cursor pagination and filters such as action[in]=member.invited,member.removed
+// @Tags members
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param action query string false "Action"
+// @Param resource_type query string false "Resource type"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/activity [get]
+func (h *MembershipHandler) ListActivity(c *gin.Context) {
+	tenantID, userID, ok := invitationActor(c)
+	if !ok {
+		return
+	}
+
+	q, err := listquery.Parse(c.Request.URL.Query(), activityListQuery)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	activity, total, next, err := h.membershipSvc.ListActivity(c.Request.Context(), tenantID, userID, q)
+	if err != nil {
+		respondInvitationError(c, err, "Failed to list activity")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Activity retrieved", gin.H{
+		"activity":   activity,
+		"pagination": q.Meta(total, next),
+	})
+}
+
+// ResendInvitation issues a new token and expiry for an invitation and sends it again
+// POST /api/v1/tenants/:id/members/invitations/:invId/resend
+// @Summary Resend invitation
+// @Description Issues a new token and expiry for an invitation and sends it again
+// @Tags members
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param invId path string true "Invitation ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/members/invitations/{invId}/resend [post]
+func (h *MembershipHandler) ResendInvitation(c *gin.Context) {
+	tenantID, userID, ok := invitationActor(c)
+	if !ok {
+		return
+	}
+	invitationID, err := uuid.Parse(c.Param("invId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid invitation ID", nil)
+		return
+	}
+
+	resp, err := h.membershipSvc.ResendInvitation(c.Request.Context(), tenantID, invitationID, userID)
+	if err != nil {
+		respondInvitationError(c, err, "Failed to resend invitation")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Invitation resent", gin.H{
+		"invitation_token": resp.InvitationToken,
+		"expires_at":       resp.ExpiresAt,
+	})
+}
+
+// RevokeInvitation cancels an invitation
+// DELETE /api/v1/tenants/:id/members/invitations/:invId
+// @Summary Revoke invitation
+// @Tags members
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param invId path string true "Invitation ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/members/invitations/{invId} [delete]
+func (h *MembershipHandler) RevokeInvitation(c *gin.Context) {
+	tenantID, userID, ok := invitationActor(c)
+	if !ok {
+		return
+	}
+	invitationID, err := uuid.Parse(c.Param("invId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid invitation ID", nil)
+		return
+	}
+
+	if err := h.membershipSvc.RevokeInvitation(c.Request.Context(), tenantID, invitationID, userID); err != nil {
+		respondInvitationError(c, err, "Failed to revoke invitation")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Invitation revoked", nil)
+}
+
+// invitationActor extracts the tenant ID and the authenticated user ID for invitation management
+func invitationActor(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userIDVal, _ := c.Get("user_id")
+	userIDStr := ""
+	if userIDVal != nil {
+		userIDStr = userIDVal.(string)
+	}
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, userID, true
+}
+
+// respondInvitationError maps invitation and member listing errors to HTTP responses
+func respondInvitationError(c *gin.Context, err error, fallback string) {
+	if validationErr, ok := services.IsValidationError(err); ok {
+		ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrInvitationForbidden),
+		errors.Is(err, services.ErrNotTenantMember),
+		errors.Is(err, services.ErrActivityLogForbidden):
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, services.ErrInvitationNotFound):
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrInvitationRevoked):
+		ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, fallback, err)
+	}
+}
+
 // RemoveMember removes a member from a tenant
 // DELETE /api/v1/tenants/:tenantId/members/:memberId
+// @Summary Remove member
+// @Tags members
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param memberId path string true "Member user ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/members/{memberId} [delete]
 func (h *MembershipHandler) RemoveMember(c *gin.Context) {
 	// Get user ID from context (set by IstioAuth middleware from JWT claims)
 	userIDVal, _ := c.Get("user_id")
@@ -479,8 +940,22 @@ func (h *MembershipHandler) RemoveMember(c *gin.Context) {
 	SuccessResponse(c, http.StatusOK, "Member removed", nil)
 }
 
-// UpdateMemberRole updates a member's role
+// UpdateMemberRole updates a member's role: admin, manager, member, viewer or one of the tenant's custom roles
 // PUT /api/v1/tenants/:tenantId/members/:memberId/role
+// @Summary Update member role
+// @Description Sets a member's role: admin, manager, member, viewer or one of the tenant's custom roles
+// @Tags members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param memberId path string true "Member user ID"
+// @Param request body object true "{\"role\": \"manager\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/members/{memberId}/role [put]
 func (h *MembershipHandler) UpdateMemberRole(c *gin.Context) {
 	// Get user ID from context (set by IstioAuth middleware from JWT claims)
 	userIDVal, _ := c.Get("user_id")
@@ -514,7 +989,7 @@ func (h *MembershipHandler) UpdateMemberRole(c *gin.Context) {
 	}
 
 	var req struct {
-		Role string `json:"role" binding:"required,oneof=admin manager member viewer"`
+		Role string `json:"role" binding:"required,max=50"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
@@ -531,6 +1006,14 @@ func (h *MembershipHandler) UpdateMemberRole(c *gin.Context) {
 
 // ValidateSlug validates if a slug is available
 // GET /api/v1/validation/slug
+// @Summary Validate slug
+// @Tags validation
+// @Produce json
+// @Param slug query string true "Slug"
+// @Param exclude_tenant_id query string false "Tenant whose own slug is allowed"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/validation/slug [get]
 func (h *MembershipHandler) ValidateSlug(c *gin.Context) {
 	slug := c.Query("slug")
 	if slug == "" {
@@ -560,6 +1043,14 @@ func (h *MembershipHandler) ValidateSlug(c *gin.Context) {
 
 // GenerateSlug generates a unique slug from a name
 // GET /api/v1/validation/slug/generate
+// @Summary Generate slug
+// @Description Generates an available slug from a name
+// @Tags validation
+// @Produce json
+// @Param name query string true "Name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/validation/slug/generate [get]
 func (h *MembershipHandler) GenerateSlug(c *gin.Context) {
 	name := c.Query("name")
 	if name == "" {
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// MFAHandler handles TOTP enrollment, verification and recovery codes for the authenticated user
+type MFAHandler struct {
+	authSvc *services.TenantAuthService
+}
+
+// NewMFAHandler creates a new MFA handler
+func NewMFAHandler(authSvc *services.TenantAuthService) *MFAHandler {
+	return &MFAHandler{authSvc: authSvc}
+}
+
+// MFACodeRequest carries the tenant and a TOTP or recovery code
+type MFACodeRequest struct {
+	TenantID string `json:"tenant_id" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFAEnrollRequest identifies the tenant to enroll MFA for
+type MFAEnrollRequest struct {
+	TenantID string `json:"tenant_id" binding:"required"`
+}
+
+// GetMFAStatus returns the user's MFA state for a tenant
+// GET /api/v1/auth/mfa?tenant_id=...
+// @Summary Get MFA status
+// @Tags mfa
+// @Produce json
+// @Security BearerAuth
+// @Param tenant_id query string true "Tenant ID"
+// @Success 200 {object} services.MFAStatus
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/auth/mfa [get]
+func (h *MFAHandler) GetMFAStatus(c *gin.Context) {
+	userID, ok := mfaUserID(c)
+	if !ok {
+		return
+	}
+	tenantID, err := uuid.Parse(c.Query("tenant_id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", err)
+		return
+	}
+
+	status, err := h.authSvc.GetMFAStatus(c.Request.Context(), userID, tenantID)
+	if err != nil {
+		h.handleError(c, "Failed to get MFA status", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "MFA status retrieved", status)
+}
+
+// EnrollTOTP starts TOTP enrollment and returns the secret and otpauth URL for the authenticator app
+// POST /api/v1/auth/mfa/enroll
+// @Summary Enroll TOTP
+// @Description Starts TOTP enrollment and returns the secret and QR code; verify a code to finish
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFAEnrollRequest true "Tenant"
+// @Success 200 {object} services.MFAEnrollment
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/auth/mfa/enroll [post]
+func (h *MFAHandler) EnrollTOTP(c *gin.Context) {
+	userID, ok := mfaUserID(c)
+	if !ok {
+		return
+	}
+	var req MFAEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", err)
+		return
+	}
+
+	enrollment, err := h.authSvc.EnrollTOTP(c.Request.Context(), userID, tenantID)
+	if err != nil {
+		h.handleError(c, "Failed to start MFA enrollment", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Scan the QR code and verify a code to finish enrollment", enrollment)
+}
+
+// VerifyMFA confirms a pending enrollment (returning recovery codes once) or verifies a code
+// POST /api/v1/auth/mfa/verify
+// @Summary Verify MFA code
+// @Description Confirms a pending enrollment, returning recovery codes once, or verifies a TOTP or recovery code
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "Tenant and code"
+// @Success 200 {object} services.MFAVerifyResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/auth/mfa/verify [post]
+func (h *MFAHandler) VerifyMFA(c *gin.Context) {
+	userID, tenantID, code, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.authSvc.VerifyMFA(c.Request.Context(), userID, tenantID, code)
+	if err != nil {
+		h.handleError(c, "Failed to verify MFA code", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "MFA code verified", result)
+}
+
+// DisableMFA turns MFA off after verifying a TOTP or recovery code
+// POST /api/v1/auth/mfa/disable
+// @Summary Disable MFA
+// @Description Turns MFA off after verifying a TOTP or recovery code
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "Tenant and code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/auth/mfa/disable [post]
+func (h *MFAHandler) DisableMFA(c *gin.Context) {
+	userID, tenantID, code, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authSvc.DisableMFAWithCode(c.Request.Context(), userID, tenantID, code); err != nil {
+		h.handleError(c, "Failed to disable MFA", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "MFA disabled", nil)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after verifying a TOTP code
+// POST /api/v1/auth/mfa/recovery-codes
+// @Summary Regenerate recovery codes
+// @Description Issues new recovery codes after verifying a code; previous codes stop working
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "Tenant and code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/auth/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, tenantID, code, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.authSvc.RegenerateRecoveryCodes(c.Request.Context(), userID, tenantID, code)
+	if err != nil {
+		h.handleError(c, "Failed to regenerate recovery codes", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Recovery codes regenerated; previous codes no longer work", gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+func (h *MFAHandler) bindCode(c *gin.Context) (uuid.UUID, uuid.UUID, string, bool) {
+	userID, ok := mfaUserID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", err)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	return userID, tenantID, req.Code, true
+}
+
+func (h *MFAHandler) handleError(c *gin.Context, message string, err error) {
+	if validationErr, ok := services.IsValidationError(err); ok {
+		ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrInvalidMFACode):
+		ErrorResponse(c, http.StatusUnauthorized, err.Error(), nil)
+	case errors.Is(err, services.ErrMFANoMembership), errors.Is(err, services.ErrMFARequiredByPolicy):
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, services.ErrMFAAlreadyEnabled), errors.Is(err, services.ErrMFANotEnrolled),
+		errors.Is(err, services.ErrMFANotEnabled):
+		ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, services.ErrMasterKeyNotConfigured):
+		ErrorResponse(c, http.StatusServiceUnavailable, "MFA is not available: encryption is not configured", nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, message, err)
+	}
+}
+
+func mfaUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
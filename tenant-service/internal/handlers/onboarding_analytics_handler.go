@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	sharedMiddleware "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"tenant-service/internal/services"
+)
+
+// OnboardingAnalyticsHandler serves onboarding funnel analytics
+type OnboardingAnalyticsHandler struct {
+	analyticsService *services.OnboardingAnalyticsService
+}
+
+// NewOnboardingAnalyticsHandler creates a new onboarding analytics handler
+func NewOnboardingAnalyticsHandler(analyticsService *services.OnboardingAnalyticsService) *OnboardingAnalyticsHandler {
+	return &OnboardingAnalyticsHandler{analyticsService: analyticsService}
+}
+
+// GetFunnel returns how many onboarding sessions reached each stage
+// @Summary Onboarding funnel
+// @Description Sessions started, completed per template step, email verified and finished, with drop-off per stage (platform owners only). Served from a summary refreshed by a background job.
+// @Tags onboarding
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First session start day, YYYY-MM-DD or RFC3339 (default: 29 days before to)"
+// @Param to query string false "Last session start day, YYYY-MM-DD or RFC3339 (default: today)"
+// @Param applicationType query string false "Filter by application type (ecommerce, saas, marketplace, b2b)"
+// @Success 200 {object} services.OnboardingFunnel
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/onboarding/analytics/funnel [get]
+func (h *OnboardingAnalyticsHandler) GetFunnel(c *gin.Context) {
+	// SECURITY: The funnel spans every tenant's onboarding, so only platform owners may read it
+	if !sharedMiddleware.IsPlatformOwner(c) {
+		ErrorResponse(c, http.StatusForbidden, "Only platform owners can view onboarding analytics", nil)
+		return
+	}
+
+	from, ok := parseFunnelDate(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseFunnelDate(c, "to")
+	if !ok {
+		return
+	}
+
+	funnel, err := h.analyticsService.GetFunnel(c.Request.Context(), services.OnboardingFunnelQuery{
+		From:            from,
+		To:              to,
+		ApplicationType: c.Query("applicationType"),
+	})
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get onboarding funnel", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Onboarding funnel retrieved", funnel)
+}
+
+// parseFunnelDate reads an optional date query parameter; a zero time means not set
+func parseFunnelDate(c *gin.Context, name string) (time.Time, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return time.Time{}, true
+	}
+	if parsed, err := time.Parse("2006-01-02", raw); err == nil {
+		return parsed, true
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid "+name+" parameter, expected YYYY-MM-DD or RFC3339", err)
+		return time.Time{}, false
+	}
+	return parsed, true
+}
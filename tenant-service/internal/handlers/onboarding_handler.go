@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"tenant-service/internal/listquery"
 	"tenant-service/internal/models"
 	"tenant-service/internal/services"
 )
@@ -54,6 +55,16 @@ func NewOnboardingHandler(
 }
 
 // StartOnboarding starts a new onboarding session
+// @Summary Start onboarding
+// @Description Creates an onboarding session from the given or default template of the application type
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param request body services.StartOnboardingRequest true "Session"
+// @Success 201 {object} models.OnboardingSession
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions [post]
 func (h *OnboardingHandler) StartOnboarding(c *gin.Context) {
 	var req services.StartOnboardingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -88,6 +99,15 @@ func (h *OnboardingHandler) StartOnboarding(c *gin.Context) {
 }
 
 // GetOnboardingSession retrieves an onboarding session
+// @Summary Get onboarding session
+// @Tags onboarding
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param include query []string false "Relations to load (repeatable)" collectionFormat(multi)
+// @Success 200 {object} SessionResponseWithStoreSetup
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId} [get]
 func (h *OnboardingHandler) GetOnboardingSession(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -136,6 +156,17 @@ func (h *OnboardingHandler) GetOnboardingSession(c *gin.Context) {
 }
 
 // UpdateBusinessInformation updates business information for a session
+// @Summary Update business information
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param request body models.BusinessInformation true "Business information"
+// @Success 200 {object} models.BusinessInformation
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/business-information [post]
+// @Router /api/v1/onboarding/sessions/{sessionId}/business-information [put]
 func (h *OnboardingHandler) UpdateBusinessInformation(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -151,6 +182,14 @@ func (h *OnboardingHandler) UpdateBusinessInformation(c *gin.Context) {
 
 	updatedBusinessInfo, err := h.onboardingService.UpdateBusinessInformation(c.Request.Context(), sessionID, &businessInfo)
 	if err != nil {
+		if fieldErr, ok := services.IsCustomFieldValidationError(err); ok {
+			errs := make(map[string]string, len(fieldErr.Errors))
+			for _, fe := range fieldErr.Errors {
+				errs["custom_fields."+fe.Key] = fe.Message
+			}
+			ValidationErrorResponse(c, errs)
+			return
+		}
 		// Check if it's a validation error (e.g., business name already taken)
 		if validationErr, ok := services.IsValidationError(err); ok {
 			c.JSON(http.StatusConflict, gin.H{
@@ -169,7 +208,99 @@ func (h *OnboardingHandler) UpdateBusinessInformation(c *gin.Context) {
 	SuccessResponse(c, http.StatusOK, "Business information updated successfully", updatedBusinessInfo)
 }
 
+// GetSessionCustomFields returns the template's custom fields with the session's answers
+// @Summary Get session custom fields
+// @Description Returns the template's custom fields with the session's answers
+// @Tags onboarding
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Success 200 {array} services.CustomFieldValue
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/custom-fields [get]
+func (h *OnboardingHandler) GetSessionCustomFields(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("sessionId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid session ID", err)
+		return
+	}
+
+	fields, err := h.onboardingService.GetSessionCustomFields(c.Request.Context(), sessionID)
+	if err != nil {
+		ErrorResponse(c, http.StatusNotFound, "Failed to get custom fields", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Custom fields retrieved successfully", fields)
+}
+
+// QueryCustomFields finds a template's sessions by a typed custom field value
+// Query params: key (required), op (eq, ne, contains, gt, gte, lt, lte, exists), value, page, page_size
+// @Summary Query custom fields
+// @Description Finds a template's sessions by a typed custom field value
+// @Tags templates
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Param key query string true "Custom field key"
+// @Param op query string false "eq, ne, contains, gt, gte, lt, lte or exists"
+// @Param value query string false "Value to compare with"
+// @Param page query int false "Page"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId}/custom-fields/query [get]
+func (h *OnboardingHandler) QueryCustomFields(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid template ID", err)
+		return
+	}
+
+	key := c.Query("key")
+	if key == "" {
+		ErrorResponse(c, http.StatusBadRequest, "Custom field key is required", nil)
+		return
+	}
+
+	q, err := listquery.Parse(c.Request.URL.Query(), listquery.Config{})
+	if err == nil && q.Mode == listquery.ModeCursor {
+		err = &listquery.Error{Param: "cursor", Message: "custom field queries support page pagination only"}
+	}
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	q.Offset = (q.Page - 1) * q.Limit
+
+	matches, total, err := h.onboardingService.QueryCustomFields(c.Request.Context(), templateID, key, c.Query("op"), c.Query("value"), q.Page, q.Limit)
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ValidationErrorResponse(c, map[string]string{validationErr.Field: validationErr.Message})
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to query custom fields", err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"results":    matches,
+		"pagination": q.Meta(total, ""),
+	}
+
+	SuccessResponse(c, http.StatusOK, "Custom field query completed successfully", response)
+}
+
 // UpdateContactInformation updates contact information for a session
+// @Summary Update contact information
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param request body models.ContactInformation true "Contact information"
+// @Success 200 {object} models.ContactInformation
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/contact-information [post]
 func (h *OnboardingHandler) UpdateContactInformation(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -213,6 +344,17 @@ type UpdateBusinessAddressRequest struct {
 }
 
 // UpdateBusinessAddress updates business address for a session
+// @Summary Update business address
+// @Description Saves the business address and, unless it is the same, the billing address
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param request body UpdateBusinessAddressRequest true "Addresses"
+// @Success 200 {object} models.BusinessAddress
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/business-addresses [post]
 func (h *OnboardingHandler) UpdateBusinessAddress(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -283,6 +425,17 @@ type UpdateStoreSetupRequest struct {
 }
 
 // UpdateStoreSetup saves store setup to application_configurations
+// @Summary Update store setup
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param request body UpdateStoreSetupRequest true "Store setup"
+// @Success 200 {object} models.ApplicationConfiguration
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/store-setup [post]
+// @Router /api/v1/onboarding/sessions/{sessionId}/store-setup [put]
 func (h *OnboardingHandler) UpdateStoreSetup(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -320,6 +473,14 @@ func (h *OnboardingHandler) UpdateStoreSetup(c *gin.Context) {
 }
 
 // CompleteOnboarding completes an onboarding session
+// @Summary Complete onboarding
+// @Tags onboarding
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Success 200 {object} models.OnboardingSession
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/complete [post]
 func (h *OnboardingHandler) CompleteOnboarding(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -337,6 +498,14 @@ func (h *OnboardingHandler) CompleteOnboarding(c *gin.Context) {
 }
 
 // GetProgress retrieves onboarding progress
+// @Summary Get onboarding progress
+// @Tags onboarding
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/progress [get]
 func (h *OnboardingHandler) GetProgress(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -354,6 +523,14 @@ func (h *OnboardingHandler) GetProgress(c *gin.Context) {
 }
 
 // GetTasks retrieves tasks for a session
+// @Summary List onboarding tasks
+// @Tags onboarding
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Success 200 {array} models.OnboardingTask
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/tasks [get]
 func (h *OnboardingHandler) GetTasks(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -371,6 +548,17 @@ func (h *OnboardingHandler) GetTasks(c *gin.Context) {
 }
 
 // UpdateTaskStatus updates task status
+// @Summary Update onboarding task
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param taskId path string true "Task ID"
+// @Param request body object true "{\"status\": \"completed\", \"completion_data\": {}}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/tasks/{taskId} [put]
 func (h *OnboardingHandler) UpdateTaskStatus(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -405,6 +593,16 @@ func (h *OnboardingHandler) UpdateTaskStatus(c *gin.Context) {
 // ValidateSubdomain validates subdomain availability with suggestions
 // If session_id is provided, the slug will be reserved for that session to prevent race conditions
 // Optional storefront_slug can be passed to also save the storefront URL slug
+// @Summary Validate subdomain
+// @Description Checks subdomain availability with suggestions; with session_id the slug is reserved for that session
+// @Tags validation
+// @Produce json
+// @Param subdomain query string true "Subdomain"
+// @Param session_id query string false "Onboarding session to reserve the slug for"
+// @Param storefront_slug query string false "Storefront slug to save with the reservation"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/validation/subdomain [get]
 func (h *OnboardingHandler) ValidateSubdomain(c *gin.Context) {
 	subdomain := c.Query("subdomain")
 	if subdomain == "" {
@@ -449,6 +647,15 @@ func (h *OnboardingHandler) ValidateSubdomain(c *gin.Context) {
 
 // ValidateStorefront validates storefront slug availability with suggestions
 // If session_id is provided, the slug will be reserved and persisted to the session
+// @Summary Validate storefront slug
+// @Description Checks storefront slug availability with suggestions; with session_id the slug is reserved and saved to the session
+// @Tags validation
+// @Produce json
+// @Param storefront_slug query string true "Storefront slug"
+// @Param session_id query string false "Onboarding session"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/validation/storefront [get]
 func (h *OnboardingHandler) ValidateStorefront(c *gin.Context) {
 	storefrontSlug := c.Query("storefront_slug")
 	if storefrontSlug == "" {
@@ -488,6 +695,15 @@ func (h *OnboardingHandler) ValidateStorefront(c *gin.Context) {
 // ValidateBusinessName validates business name availability with suggestions
 // GET /api/v1/validation/business-name?business_name=MyStore
 // Optional: ?session_id=<uuid> to exclude current session's business info from check
+// @Summary Validate business name
+// @Description Checks business name availability with suggestions
+// @Tags validation
+// @Produce json
+// @Param business_name query string true "Business name"
+// @Param session_id query string false "Onboarding session whose own name is allowed"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/validation/business-name [get]
 func (h *OnboardingHandler) ValidateBusinessName(c *gin.Context) {
 	businessName := c.Query("business_name")
 	if businessName == "" {
@@ -538,6 +754,18 @@ func (h *OnboardingHandler) ListSessions(c *gin.Context) {
 }
 
 // CompleteAccountSetup completes account setup by creating tenant and user account
+// @Summary Complete account setup
+// @Description Creates the tenant and the owner account for a verified onboarding session
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param request body object true "{\"password\": \"...\", \"auth_method\": \"password|social\", \"timezone\": \"...\", \"currency\": \"...\", \"business_model\": \"...\"}"
+// @Success 201 {object} services.CompleteAccountSetupResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/account-setup [post]
 func (h *OnboardingHandler) CompleteAccountSetup(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
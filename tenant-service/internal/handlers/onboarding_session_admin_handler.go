@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	sharedMiddleware "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/listquery"
+	"tenant-service/internal/services"
+)
+
+// OnboardingSessionAdminHandler lets support staff list and clean up onboarding sessions
+type OnboardingSessionAdminHandler struct {
+	adminService *services.OnboardingSessionAdminService
+}
+
+// NewOnboardingSessionAdminHandler creates a new onboarding session admin handler
+func NewOnboardingSessionAdminHandler(adminService *services.OnboardingSessionAdminService) *OnboardingSessionAdminHandler {
+	return &OnboardingSessionAdminHandler{adminService: adminService}
+}
+
+// BulkSessionRequest selects the sessions of a bulk action
+type BulkSessionRequest struct {
+	SessionIDs []uuid.UUID `json:"session_ids" binding:"required"`
+}
+
+var adminSessionListQuery = listquery.Config{
+	DefaultLimit: 50,
+	MaxLimit:     200,
+	Sorts: map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"expires_at": "expires_at",
+	},
+	DefaultSort: "-created_at",
+	Filters: map[string]listquery.Field{
+		"status":           {Column: "status", Ops: []string{listquery.OpEq, listquery.OpIn, listquery.OpNotIn}},
+		"application_type": {Column: "application_type", Ops: []string{listquery.OpEq, listquery.OpIn}},
+		"tenant_id":        {Column: "tenant_id", Type: listquery.TypeUUID},
+		"created_at":       {Column: "created_at", Type: listquery.TypeTime},
+		"updated_at":       {Column: "updated_at", Type: listquery.TypeTime},
+	},
+}
+
+// ListSessions lists onboarding sessions for support staff
+// @Summary List onboarding sessions
+// @Description List onboarding sessions of all tenants to find duplicate or stale ones (platform owners only). Supports page/limit or cursor pagination, sort=-created_at and filters such as status[in]=started,in_progress
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Session status"
+// @Param email query string false "Primary contact email"
+// @Param min_age_days query int false "Only sessions created at least this many days ago"
+// @Param duplicates query bool false "Only sessions whose primary contact email started other sessions too"
+// @Param sort query string false "Sort key (created_at, updated_at, expires_at), prefix with - for descending"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/admin/onboarding/sessions [get]
+func (h *OnboardingSessionAdminHandler) ListSessions(c *gin.Context) {
+	if !h.requirePlatformOwner(c) {
+		return
+	}
+
+	q, err := listquery.Parse(c.Request.URL.Query(), adminSessionListQuery)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	filter := services.OnboardingSessionFilter{
+		Email:          c.Query("email"),
+		DuplicatesOnly: c.Query("duplicates") == "true",
+	}
+	if raw := c.Query("min_age_days"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 0 {
+			ErrorResponse(c, http.StatusBadRequest, "min_age_days must be a non-negative number", nil)
+			return
+		}
+		filter.MinAgeDays = days
+	}
+
+	sessions, total, next, err := h.adminService.ListSessions(c.Request.Context(), q, filter)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to list onboarding sessions", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Onboarding sessions retrieved", gin.H{
+		"sessions":   sessions,
+		"pagination": q.Meta(total, next),
+	})
+}
+
+// AbandonSessions marks onboarding sessions as abandoned
+// @Summary Bulk-abandon onboarding sessions
+// @Description Marks sessions as abandoned and drops their drafts (platform owners only). Completed sessions and sessions that created a tenant are skipped.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkSessionRequest true "Sessions to abandon"
+// @Success 200 {object} services.OnboardingSessionBulkResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/admin/onboarding/sessions/abandon [post]
+func (h *OnboardingSessionAdminHandler) AbandonSessions(c *gin.Context) {
+	h.bulk(c, "abandoned", h.adminService.AbandonSessions)
+}
+
+// DeleteSessions deletes onboarding sessions
+// @Summary Bulk-delete onboarding sessions
+// @Description Deletes sessions with their onboarding data (platform owners only). Completed sessions and sessions that created a tenant are skipped.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkSessionRequest true "Sessions to delete"
+// @Success 200 {object} services.OnboardingSessionBulkResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/admin/onboarding/sessions/delete [post]
+func (h *OnboardingSessionAdminHandler) DeleteSessions(c *gin.Context) {
+	h.bulk(c, "deleted", h.adminService.DeleteSessions)
+}
+
+// PurgeStaleSessions purges sessions stuck before completion now
+// @Summary Purge stale onboarding sessions
+// @Description Deletes sessions pending without updates for longer than ONBOARDING_STALE_SESSION_DAYS (also runs on a schedule)
+// @Tags internal
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /internal/onboarding/sessions/purge [post]
+func (h *OnboardingSessionAdminHandler) PurgeStaleSessions(c *gin.Context) {
+	purged, err := h.adminService.PurgeStaleSessions(c.Request.Context())
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to purge stale onboarding sessions", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Stale onboarding sessions purged", gin.H{"purged": purged})
+}
+
+func (h *OnboardingSessionAdminHandler) bulk(c *gin.Context, action string, apply func(ctx context.Context, ids []uuid.UUID) (*services.OnboardingSessionBulkResult, error)) {
+	if !h.requirePlatformOwner(c) {
+		return
+	}
+
+	var req BulkSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	result, err := apply(c.Request.Context(), req.SessionIDs)
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update onboarding sessions", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Onboarding sessions "+action, result)
+}
+
+// requirePlatformOwner rejects callers that are not platform owners
+// SECURITY: Sessions span every tenant's onboarding and hold contact details of prospects.
+func (h *OnboardingSessionAdminHandler) requirePlatformOwner(c *gin.Context) bool {
+	if !sharedMiddleware.IsPlatformOwner(c) {
+		ErrorResponse(c, http.StatusForbidden, "Only platform owners can manage onboarding sessions", nil)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// PasskeyHandler handles WebAuthn passkey registration, passkey logins and the tenant passkey policy
+type PasskeyHandler struct {
+	authSvc *services.TenantAuthService
+}
+
+// NewPasskeyHandler creates a new passkey handler
+func NewPasskeyHandler(authSvc *services.TenantAuthService) *PasskeyHandler {
+	return &PasskeyHandler{authSvc: authSvc}
+}
+
+// BeginPasskeyRegistrationRequest identifies the tenant to register a passkey for
+type BeginPasskeyRegistrationRequest struct {
+	TenantID string `json:"tenant_id" binding:"required"`
+}
+
+// FinishPasskeyRegistrationRequest carries the attestation returned by navigator.credentials.create()
+type FinishPasskeyRegistrationRequest struct {
+	TenantID    string          `json:"tenant_id" binding:"required"`
+	ChallengeID string          `json:"challenge_id" binding:"required"`
+	Name        string          `json:"name"` // Label shown in the passkey list, e.g. "MacBook Touch ID"
+	Credential  json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// BeginPasskeyLoginRequest starts a passkey login; the email is optional
+type BeginPasskeyLoginRequest struct {
+	TenantID   string `json:"tenant_id"`   // Either tenant_id or tenant_slug required
+	TenantSlug string `json:"tenant_slug"` // Either tenant_id or tenant_slug required
+	Email      string `json:"email"`       // Limits the ceremony to the user's passkeys; omit for username-less login
+}
+
+// FinishPasskeyLoginRequest carries the assertion returned by navigator.credentials.get()
+type FinishPasskeyLoginRequest struct {
+	ChallengeID string          `json:"challenge_id" binding:"required"`
+	Credential  json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+	RememberMe  bool            `json:"remember_me"` // Request the tenant's remember-me session lifetime
+}
+
+// ListPasskeys returns the user's passkeys for a tenant
+// GET /api/v1/auth/passkeys?tenant_id=...
+// @Summary List passkeys
+// @Tags passkeys
+// @Produce json
+// @Security BearerAuth
+// @Param tenant_id query string true "Tenant ID"
+// @Success 200 {array} models.WebAuthnCredential
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/auth/passkeys [get]
+func (h *PasskeyHandler) ListPasskeys(c *gin.Context) {
+	userID, ok := mfaUserID(c)
+	if !ok {
+		return
+	}
+	tenantID, err := uuid.Parse(c.Query("tenant_id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", err)
+		return
+	}
+
+	passkeys, err := h.authSvc.ListPasskeys(c.Request.Context(), userID, tenantID)
+	if err != nil {
+		h.handleError(c, "Failed to list passkeys", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Passkeys retrieved", passkeys)
+}
+
+// BeginPasskeyRegistration returns the options for navigator.credentials.create()
+// POST /api/v1/auth/passkeys/register/begin
+// @Summary Start passkey registration
+// @Description Returns the WebAuthn creation options and a challenge ID; pass the options to navigator.credentials.create() and send the result to the finish endpoint
+// @Tags passkeys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BeginPasskeyRegistrationRequest true "Tenant"
+// @Success 200 {object} services.PasskeyCeremony
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/auth/passkeys/register/begin [post]
+func (h *PasskeyHandler) BeginPasskeyRegistration(c *gin.Context) {
+	userID, ok := mfaUserID(c)
+	if !ok {
+		return
+	}
+	var req BeginPasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", err)
+		return
+	}
+
+	ceremony, err := h.authSvc.BeginPasskeyRegistration(c.Request.Context(), userID, tenantID)
+	if err != nil {
+		h.handleError(c, "Failed to start passkey registration", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Passkey registration started", ceremony)
+}
+
+// FinishPasskeyRegistration validates the attestation and stores the passkey
+// POST /api/v1/auth/passkeys/register/finish
+// @Summary Finish passkey registration
+// @Description Validates the attestation returned by navigator.credentials.create() and stores the passkey
+// @Tags passkeys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body FinishPasskeyRegistrationRequest true "Attestation"
+// @Success 201 {object} models.WebAuthnCredential
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/auth/passkeys/register/finish [post]
+func (h *PasskeyHandler) FinishPasskeyRegistration(c *gin.Context) {
+	userID, ok := mfaUserID(c)
+	if !ok {
+		return
+	}
+	var req FinishPasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", err)
+		return
+	}
+	challengeID, err := uuid.Parse(req.ChallengeID)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid challenge ID", err)
+		return
+	}
+
+	passkey, err := h.authSvc.FinishPasskeyRegistration(c.Request.Context(), &services.FinishPasskeyRegistrationInput{
+		UserID:      userID,
+		TenantID:    tenantID,
+		ChallengeID: challengeID,
+		Name:        req.Name,
+		Credential:  req.Credential,
+		IPAddress:   c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+	})
+	if err != nil {
+		h.handleError(c, "Failed to register passkey", err)
+		return
+	}
+	SuccessResponse(c, http.StatusCreated, "Passkey registered", passkey)
+}
+
+// DeletePasskey removes one of the user's passkeys
+// DELETE /api/v1/auth/passkeys/:passkeyId?tenant_id=...
+// @Summary Remove a passkey
+// @Description Removes a passkey; the last one cannot be removed while the tenant requires passkeys for the user's role
+// @Tags passkeys
+// @Produce json
+// @Security BearerAuth
+// @Param passkeyId path string true "Passkey ID"
+// @Param tenant_id query string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/auth/passkeys/{passkeyId} [delete]
+func (h *PasskeyHandler) DeletePasskey(c *gin.Context) {
+	userID, ok := mfaUserID(c)
+	if !ok {
+		return
+	}
+	passkeyID, err := uuid.Parse(c.Param("passkeyId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid passkey ID", err)
+		return
+	}
+	tenantID, err := uuid.Parse(c.Query("tenant_id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", err)
+		return
+	}
+
+	if err := h.authSvc.DeletePasskey(c.Request.Context(), userID, tenantID, passkeyID, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		h.handleError(c, "Failed to remove passkey", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Passkey removed", gin.H{"id": passkeyID})
+}
+
+// BeginPasskeyLogin returns the options for navigator.credentials.get()
+// POST /api/v1/auth/passkeys/login/begin
+// @Summary Start passkey login
+// @Description Returns the WebAuthn request options and a challenge ID; without an email the browser offers every passkey it holds for the tenant
+// @Tags passkeys
+// @Accept json
+// @Produce json
+// @Param request body BeginPasskeyLoginRequest true "Tenant and optional email"
+// @Success 200 {object} services.PasskeyCeremony
+// @Failure 400 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/auth/passkeys/login/begin [post]
+func (h *PasskeyHandler) BeginPasskeyLogin(c *gin.Context) {
+	var req BeginPasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	var tenantID uuid.UUID
+	if req.TenantID != "" {
+		var err error
+		if tenantID, err = uuid.Parse(req.TenantID); err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid tenant_id format", err)
+			return
+		}
+	}
+
+	ceremony, err := h.authSvc.BeginPasskeyLogin(c.Request.Context(), &services.BeginPasskeyLoginInput{
+		TenantID:   tenantID,
+		TenantSlug: req.TenantSlug,
+		Email:      req.Email,
+	})
+	if err != nil {
+		h.handleError(c, "Failed to start passkey login", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Passkey login started", ceremony)
+}
+
+// FinishPasskeyLogin verifies the assertion and returns tokens like a password login
+// POST /api/v1/auth/passkeys/login/finish
+// @Summary Finish passkey login
+// @Description Verifies the assertion returned by navigator.credentials.get() and signs the user in; the response matches /api/v1/auth/validate
+// @Tags passkeys
+// @Accept json
+// @Produce json
+// @Param request body FinishPasskeyLoginRequest true "Assertion"
+// @Success 200 {object} services.ValidateCredentialsResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/auth/passkeys/login/finish [post]
+func (h *PasskeyHandler) FinishPasskeyLogin(c *gin.Context) {
+	var req FinishPasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	challengeID, err := uuid.Parse(req.ChallengeID)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid challenge ID", err)
+		return
+	}
+
+	result, err := h.authSvc.FinishPasskeyLogin(c.Request.Context(), &services.FinishPasskeyLoginInput{
+		ChallengeID: challengeID,
+		Credential:  req.Credential,
+		IPAddress:   c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+		RememberMe:  req.RememberMe,
+	})
+	if err != nil {
+		h.handleError(c, "Failed to complete passkey login", err)
+		return
+	}
+	if !result.Valid {
+		writeLoginFailure(c, result)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Passkey login successful", loginSuccessResponse(result))
+}
+
+// GetPasskeyPolicy returns the tenant's passkey policy
+// @Summary Get tenant passkey policy
+// @Description Returns whether passkeys are off, preferred or required and the roles this applies to (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.PasskeyPolicy
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/passkey-policy [get]
+func (h *PasskeyHandler) GetPasskeyPolicy(c *gin.Context) {
+	tenantID, _, ok := h.authorizePolicy(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.authSvc.GetPasskeyPolicy(c.Request.Context(), tenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get passkey policy", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Passkey policy retrieved", policy)
+}
+
+// UpdatePasskeyPolicy changes the tenant's passkey policy
+// @Summary Update tenant passkey policy
+// @Description Set policy to off, prefer (members of the listed roles are prompted to add a passkey) or require (members of the listed roles who have a passkey cannot sign in with a password); omitted fields are unchanged (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.UpdatePasskeyPolicyRequest true "Policy changes"
+// @Success 200 {object} services.PasskeyPolicy
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/passkey-policy [put]
+func (h *PasskeyHandler) UpdatePasskeyPolicy(c *gin.Context) {
+	tenantID, userID, ok := h.authorizePolicy(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdatePasskeyPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	policy, err := h.authSvc.UpdatePasskeyPolicy(c.Request.Context(), tenantID, userID, req)
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update passkey policy", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Passkey policy updated", policy)
+}
+
+// authorizePolicy resolves the tenant and user and checks the user may manage the passkey policy
+func (h *PasskeyHandler) authorizePolicy(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, ok := mfaUserID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.authSvc.AuthorizePasskeyPolicy(c.Request.Context(), tenantID, userID); err != nil {
+		if errors.Is(err, services.ErrPasskeyPolicyForbidden) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return uuid.Nil, uuid.Nil, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to verify permissions", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
+
+func (h *PasskeyHandler) handleError(c *gin.Context, message string, err error) {
+	if validationErr, ok := services.IsValidationError(err); ok {
+		ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+		return
+	}
+	if conflictErr, ok := services.IsConflictError(err); ok {
+		ErrorResponse(c, http.StatusConflict, conflictErr.Message, err)
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrPasskeyChallengeInvalid), errors.Is(err, services.ErrPasskeyVerificationFailed):
+		ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, services.ErrMFANoMembership), errors.Is(err, services.ErrPasskeyRequiredByPolicy):
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, services.ErrPasskeyNotFound):
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrPasskeyLimitReached):
+		ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, services.ErrPasskeysNotConfigured):
+		ErrorResponse(c, http.StatusServiceUnavailable, "Passkeys are not available", nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, message, err)
+	}
+}
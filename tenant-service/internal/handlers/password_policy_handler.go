@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// PasswordPolicyHandler handles per-tenant password policy management
+type PasswordPolicyHandler struct {
+	policyService *services.PasswordPolicyService
+}
+
+// NewPasswordPolicyHandler creates a new password policy handler
+func NewPasswordPolicyHandler(policyService *services.PasswordPolicyService) *PasswordPolicyHandler {
+	return &PasswordPolicyHandler{policyService: policyService}
+}
+
+// GetPasswordPolicy returns the tenant's password policy
+// @Summary Get tenant password policy
+// @Description Returns the password complexity, rotation and breached-password settings; defaults when the tenant has none (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.PasswordPolicy
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/password-policy [get]
+func (h *PasswordPolicyHandler) GetPasswordPolicy(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.policyService.GetPolicy(c.Request.Context(), tenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get password policy", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Password policy retrieved", policy)
+}
+
+// UpdatePasswordPolicy changes the tenant's password policy
+// @Summary Update tenant password policy
+// @Description Set min/max length, required character classes, rotation interval (rotation_days, 0 disables) and breached-password checking; omitted fields are unchanged (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.UpdatePasswordPolicyRequest true "Policy changes"
+// @Success 200 {object} services.PasswordPolicy
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/password-policy [put]
+func (h *PasswordPolicyHandler) UpdatePasswordPolicy(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdatePasswordPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	policy, err := h.policyService.UpdatePolicy(c.Request.Context(), tenantID, userID, req)
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update password policy", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Password policy updated", policy)
+}
+
+// ResetPasswordPolicy restores the default password policy
+// @Summary Reset tenant password policy
+// @Description Restore the default password policy; other auth policy settings are kept (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.PasswordPolicy
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/password-policy [delete]
+func (h *PasswordPolicyHandler) ResetPasswordPolicy(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.policyService.ResetPolicy(c.Request.Context(), tenantID, userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to reset password policy", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Password policy reset to defaults", policy)
+}
+
+// authorize resolves the tenant and user and checks the user may manage the password policy
+func (h *PasswordPolicyHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.policyService.AuthorizeManage(c.Request.Context(), tenantID, userID); err != nil {
+		if errors.Is(err, services.ErrPasswordPolicyForbidden) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return uuid.Nil, uuid.Nil, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to verify permissions", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"tenant-service/internal/services"
+)
+
+// SecurityEventHandler exposes synthetic security event injection for alert pipeline testing
+type SecurityEventHandler struct {
+	securityEventService *services.SecurityEventService
+}
+
+// NewSecurityEventHandler creates a new security event handler
+func NewSecurityEventHandler(securityEventService *services.SecurityEventService) *SecurityEventHandler {
+	return &SecurityEventHandler{securityEventService: securityEventService}
+}
+
+// InjectSyntheticEvent publishes a synthetic security event
+// @Summary Inject a synthetic security event
+// @Description Publish a security.* event marked synthetic=true so audit alerting can be tested
+// @Description without failing real logins. Audit-service records it like a real event.
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param request body services.InjectSecurityEventRequest true "Synthetic event"
+// @Success 202 {object} nats.SecurityEvent
+// @Failure 400 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /internal/security-events/synthetic [post]
+func (h *SecurityEventHandler) InjectSyntheticEvent(c *gin.Context) {
+	var req services.InjectSecurityEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.InjectedBy == "" {
+		req.InjectedBy = c.GetHeader("X-Internal-Service")
+	}
+
+	event, err := h.securityEventService.InjectSynthetic(c.Request.Context(), req)
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		if errors.Is(err, services.ErrSecurityEventsUnavailable) {
+			ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to publish security event", err)
+		return
+	}
+	SuccessResponse(c, http.StatusAccepted, "Synthetic security event published", event)
+}
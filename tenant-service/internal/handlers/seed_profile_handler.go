@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"tenant-service/internal/seeds"
+	"tenant-service/internal/services"
+)
+
+// SeedProfileHandler previews and applies seed profiles of default templates and reserved slugs
+type SeedProfileHandler struct {
+	seedService *services.SeedProfileService
+}
+
+// NewSeedProfileHandler creates a new seed profile handler
+func NewSeedProfileHandler(seedService *services.SeedProfileService) *SeedProfileHandler {
+	return &SeedProfileHandler{seedService: seedService}
+}
+
+// DiffProfile reports what applying a seed profile would change
+// @Summary Dry-run a seed profile
+// @Description Lists the templates and reserved slugs a seed profile would create or update, without changing anything
+// @Tags internal
+// @Produce json
+// @Param profile query string false "Profile name (defaults to the profile configured for this environment)"
+// @Success 200 {object} services.SeedPlan
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Router /internal/seeds/diff [get]
+func (h *SeedProfileHandler) DiffProfile(c *gin.Context) {
+	plan, err := h.seedService.Plan(c.Request.Context(), c.Query("profile"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Seed profile diff computed", plan)
+}
+
+// ApplyProfile applies a seed profile
+// @Summary Apply a seed profile
+// @Description Creates and updates templates and reserved slugs whose profile entries changed since they were last applied
+// @Tags internal
+// @Produce json
+// @Param profile query string false "Profile name (defaults to the profile configured for this environment)"
+// @Success 200 {object} services.SeedPlan
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Router /internal/seeds/apply [post]
+func (h *SeedProfileHandler) ApplyProfile(c *gin.Context) {
+	plan, err := h.seedService.Apply(c.Request.Context(), c.Query("profile"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Seed profile applied", plan)
+}
+
+// respondError maps seed profile errors to status codes
+func (h *SeedProfileHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, seeds.ErrProfileNotFound):
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, seeds.ErrInvalidProfile):
+		ErrorResponse(c, http.StatusUnprocessableEntity, err.Error(), nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to apply seed profile", err)
+	}
+}
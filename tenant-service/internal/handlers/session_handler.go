@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// sessionIDHeader identifies the session making the request (the session_id returned at login)
+const sessionIDHeader = "X-Session-ID"
+
+// SessionHandler lets users list and revoke their active sessions per tenant
+type SessionHandler struct {
+	authSvc *services.TenantAuthService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(authSvc *services.TenantAuthService) *SessionHandler {
+	return &SessionHandler{authSvc: authSvc}
+}
+
+// ListSessions returns the user's active sessions for a tenant
+// GET /api/v1/auth/sessions?tenant_id=...
+// @Summary List my sessions
+// @Description Lists the current user's active sessions in a tenant, marking the current one
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Param tenant_id query string true "Tenant ID"
+// @Success 200 {array} models.TenantAuthSession
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/auth/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	subject, tenantID, ok := sessionOwner(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authSvc.ListSessions(c.Request.Context(), subject, tenantID, c.GetHeader(sessionIDHeader))
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to list sessions", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Sessions retrieved", sessions)
+}
+
+// RevokeSession ends one of the user's sessions for a tenant
+// DELETE /api/v1/auth/sessions/:sessionId?tenant_id=...
+// @Summary Revoke session
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Param sessionId path string true "Session ID"
+// @Param tenant_id query string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/auth/sessions/{sessionId} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	subject, tenantID, ok := sessionOwner(c)
+	if !ok {
+		return
+	}
+
+	err := h.authSvc.RevokeSession(c.Request.Context(), subject, tenantID, c.Param("sessionId"), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			ErrorResponse(c, http.StatusNotFound, "Session not found", nil)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke session", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Session revoked", nil)
+}
+
+// GetSessionStatus tells other services whether a session is still active (internal)
+// GET /internal/auth/sessions/:sessionId
+// @Summary Get session status
+// @Description Reports whether a session is still valid (internal, used by auth-bff)
+// @Tags internal
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} services.SessionStatus
+// @Failure 404 {object} map[string]interface{}
+// @Router /internal/auth/sessions/{sessionId} [get]
+func (h *SessionHandler) GetSessionStatus(c *gin.Context) {
+	status, err := h.authSvc.GetSessionStatus(c.Request.Context(), c.Param("sessionId"))
+	if err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			ErrorResponse(c, http.StatusNotFound, "Session not found", nil)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get session status", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Session status retrieved", status)
+}
+
+// sessionOwner returns the authenticated subject and the tenant_id query parameter
+func sessionOwner(c *gin.Context) (string, uuid.UUID, bool) {
+	subject := getUserID(c)
+	if subject == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return "", uuid.Nil, false
+	}
+	tenantID, err := uuid.Parse(c.Query("tenant_id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID", err)
+		return "", uuid.Nil, false
+	}
+	return subject, tenantID, true
+}
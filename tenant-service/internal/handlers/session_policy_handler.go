@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// SessionPolicyHandler handles per-tenant login session policy management
+type SessionPolicyHandler struct {
+	policyService *services.SessionPolicyService
+}
+
+// NewSessionPolicyHandler creates a new session policy handler
+func NewSessionPolicyHandler(policyService *services.SessionPolicyService) *SessionPolicyHandler {
+	return &SessionPolicyHandler{policyService: policyService}
+}
+
+// GetSessionPolicy returns the tenant's session policy
+// @Summary Get tenant session policy
+// @Description Returns the absolute session lifetime, idle timeout and remember-me duration; defaults when the tenant has none (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.SessionPolicy
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/session-policy [get]
+func (h *SessionPolicyHandler) GetSessionPolicy(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.policyService.GetPolicy(c.Request.Context(), tenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get session policy", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Session policy retrieved", policy)
+}
+
+// UpdateSessionPolicy changes the tenant's session policy
+// @Summary Update tenant session policy
+// @Description Set the absolute session lifetime and idle timeout in minutes and the remember-me duration in days (0 turns idle timeout or remember-me off); omitted fields are unchanged. Applies to sessions started afterwards (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.UpdateSessionPolicyRequest true "Policy changes"
+// @Success 200 {object} services.SessionPolicy
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/session-policy [put]
+func (h *SessionPolicyHandler) UpdateSessionPolicy(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateSessionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	policy, err := h.policyService.UpdatePolicy(c.Request.Context(), tenantID, userID, req)
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update session policy", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Session policy updated", policy)
+}
+
+// authorize resolves the tenant and user and checks the user may manage the session policy
+func (h *SessionPolicyHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.policyService.AuthorizeManage(c.Request.Context(), tenantID, userID); err != nil {
+		if errors.Is(err, services.ErrSessionPolicyForbidden) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return uuid.Nil, uuid.Nil, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to verify permissions", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
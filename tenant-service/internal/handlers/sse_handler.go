@@ -151,6 +151,14 @@ func NewSSEHandler() *SSEHandler {
 
 // StreamSessionEvents handles SSE connections for a specific session
 // GET /api/v1/onboarding/sessions/:sessionId/events
+// @Summary Stream onboarding session events
+// @Description Server-sent events for an onboarding session, e.g. when its email is verified or it completes
+// @Tags onboarding
+// @Produce text/event-stream
+// @Param sessionId path string true "Onboarding session ID"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/events [get]
 func (h *SSEHandler) StreamSessionEvents(c *gin.Context) {
 	sessionIDStr := c.Param("sessionId")
 	if sessionIDStr == "" {
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/listquery"
+	"tenant-service/internal/services"
+)
+
+// StaffSyncHandler exposes the staff-service membership reconciliation to internal callers
+type StaffSyncHandler struct {
+	syncService *services.StaffMembershipSyncService
+}
+
+// NewStaffSyncHandler creates a new staff sync handler
+func NewStaffSyncHandler(syncService *services.StaffMembershipSyncService) *StaffSyncHandler {
+	return &StaffSyncHandler{syncService: syncService}
+}
+
+// Reconcile runs the staff membership reconciliation on demand
+// @Summary Reconcile staff memberships
+// @Description Compare staff-service records with tenant memberships, create missing memberships and record conflicts. Pass tenant_id to reconcile a single tenant and dry_run=true to only report drift.
+// @Tags internal
+// @Produce json
+// @Param tenant_id query string false "Tenant ID (defaults to all active tenants)"
+// @Param dry_run query bool false "Report drift without creating memberships or recording conflicts"
+// @Success 200 {object} services.StaffSyncResult
+// @Failure 409 {object} map[string]interface{}
+// @Router /internal/staff-sync/reconcile [post]
+func (h *StaffSyncHandler) Reconcile(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+
+	var (
+		result *services.StaffSyncResult
+		err    error
+	)
+	if tenantIDStr := c.Query("tenant_id"); tenantIDStr != "" {
+		tenantID, parseErr := uuid.Parse(tenantIDStr)
+		if parseErr != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", parseErr)
+			return
+		}
+		result, err = h.syncService.ReconcileTenant(c.Request.Context(), tenantID, dryRun)
+	} else {
+		result, err = h.syncService.ReconcileAll(c.Request.Context(), dryRun)
+	}
+
+	if err != nil {
+		if errors.Is(err, services.ErrStaffSyncInProgress) {
+			ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Staff membership reconciliation failed", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Staff membership reconciliation completed", result)
+}
+
+// conflictListQuery declares the sorting and filtering accepted by ListConflicts
+var conflictListQuery = listquery.Config{
+	DefaultLimit: 50,
+	MaxLimit:     500,
+	Sorts: map[string]string{
+		"last_seen_at":  "last_seen_at",
+		"first_seen_at": "first_seen_at",
+		"occurrences":   "occurrences",
+	},
+	DefaultSort: "-last_seen_at",
+	Filters: map[string]listquery.Field{
+		"tenant_id":    {Column: "tenant_id", Type: listquery.TypeUUID, Ops: []string{listquery.OpEq, listquery.OpIn}},
+		"kind":         {Column: "kind", Ops: []string{listquery.OpEq, listquery.OpIn, listquery.OpNotIn}},
+		"email":        {Column: "email", Ops: []string{listquery.OpEq, listquery.OpLike}},
+		"last_seen_at": {Column: "last_seen_at", Type: listquery.TypeTime},
+	},
+}
+
+// ListConflicts returns conflicts recorded by the reconciliation job
+// @Summary List staff membership conflicts
+// @Description List staff/membership disagreements that need manual resolution. Supports page/limit or cursor pagination, sort=-last_seen_at and filters such as kind[in]=role_mismatch,staff_inactive
+// @Tags internal
+// @Produce json
+// @Param tenant_id query string false "Tenant ID"
+// @Param kind query string false "Conflict kind"
+// @Param include_resolved query bool false "Include conflicts that have since been resolved"
+// @Param sort query string false "Sort key (last_seen_at, first_seen_at, occurrences), prefix with - for descending"
+// @Param cursor query string false "Cursor from a previous page (empty to start cursor pagination)"
+// @Success 200 {array} models.MembershipSyncConflict
+// @Router /internal/staff-sync/conflicts [get]
+func (h *StaffSyncHandler) ListConflicts(c *gin.Context) {
+	q, err := listquery.Parse(c.Request.URL.Query(), conflictListQuery)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	conflicts, total, next, err := h.syncService.ListConflicts(c.Request.Context(), q, c.Query("include_resolved") == "true")
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to list staff membership conflicts", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Staff membership conflicts retrieved", gin.H{
+		"conflicts":  conflicts,
+		"pagination": q.Meta(total, next),
+	})
+}
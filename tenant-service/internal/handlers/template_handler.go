@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"tenant-service/internal/listquery"
 	"tenant-service/internal/models"
 	"tenant-service/internal/services"
 )
@@ -23,6 +25,15 @@ func NewTemplateHandler(templateService *services.TemplateService) *TemplateHand
 }
 
 // CreateTemplate creates a new onboarding template
+// @Summary Create template
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param request body models.OnboardingTemplate true "Template"
+// @Success 201 {object} models.OnboardingTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates [post]
 func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
 	var template models.OnboardingTemplate
 	if err := c.ShouldBindJSON(&template); err != nil {
@@ -32,6 +43,10 @@ func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
 
 	createdTemplate, err := h.templateService.CreateTemplate(c.Request.Context(), &template)
 	if err != nil {
+		if _, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid template", err)
+			return
+		}
 		ErrorResponse(c, http.StatusInternalServerError, "Failed to create template", err)
 		return
 	}
@@ -40,6 +55,14 @@ func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
 }
 
 // GetTemplate retrieves a template by ID
+// @Summary Get template
+// @Tags templates
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Success 200 {object} models.OnboardingTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId} [get]
 func (h *TemplateHandler) GetTemplate(c *gin.Context) {
 	templateID, err := uuid.Parse(c.Param("templateId"))
 	if err != nil {
@@ -57,6 +80,14 @@ func (h *TemplateHandler) GetTemplate(c *gin.Context) {
 }
 
 // GetTemplatesByApplicationType retrieves templates by application type
+// @Summary List templates by application type
+// @Tags templates
+// @Produce json
+// @Param applicationType path string true "Application type"
+// @Success 200 {array} models.OnboardingTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/by-type/{applicationType} [get]
 func (h *TemplateHandler) GetTemplatesByApplicationType(c *gin.Context) {
 	applicationType := c.Param("applicationType")
 	if applicationType == "" {
@@ -74,6 +105,14 @@ func (h *TemplateHandler) GetTemplatesByApplicationType(c *gin.Context) {
 }
 
 // GetDefaultTemplate retrieves the default template for an application type
+// @Summary Get default template
+// @Tags templates
+// @Produce json
+// @Param applicationType path string true "Application type"
+// @Success 200 {object} models.OnboardingTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/default/{applicationType} [get]
 func (h *TemplateHandler) GetDefaultTemplate(c *gin.Context) {
 	applicationType := c.Param("applicationType")
 	if applicationType == "" {
@@ -91,6 +130,12 @@ func (h *TemplateHandler) GetDefaultTemplate(c *gin.Context) {
 }
 
 // GetActiveTemplates retrieves all active templates
+// @Summary List active templates
+// @Tags templates
+// @Produce json
+// @Success 200 {array} models.OnboardingTemplate
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/active [get]
 func (h *TemplateHandler) GetActiveTemplates(c *gin.Context) {
 	templates, err := h.templateService.GetActiveTemplates(c.Request.Context())
 	if err != nil {
@@ -102,6 +147,17 @@ func (h *TemplateHandler) GetActiveTemplates(c *gin.Context) {
 }
 
 // UpdateTemplate updates an existing template
+// @Summary Update template
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Param request body models.OnboardingTemplate true "Template"
+// @Success 200 {object} models.OnboardingTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId} [put]
 func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
 	templateID, err := uuid.Parse(c.Param("templateId"))
 	if err != nil {
@@ -119,6 +175,14 @@ func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
 
 	updatedTemplate, err := h.templateService.UpdateTemplate(c.Request.Context(), &template)
 	if err != nil {
+		if errors.Is(err, services.ErrTemplateDraftPending) {
+			ErrorResponse(c, http.StatusConflict, "Template has an unpublished draft", err)
+			return
+		}
+		if _, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid template", err)
+			return
+		}
 		ErrorResponse(c, http.StatusInternalServerError, "Failed to update template", err)
 		return
 	}
@@ -127,6 +191,15 @@ func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
 }
 
 // SetDefaultTemplate sets a template as default for its application type
+// @Summary Set default template
+// @Description Makes the template the default for its application type
+// @Tags templates
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId}/set-default [post]
 func (h *TemplateHandler) SetDefaultTemplate(c *gin.Context) {
 	templateID, err := uuid.Parse(c.Param("templateId"))
 	if err != nil {
@@ -143,6 +216,14 @@ func (h *TemplateHandler) SetDefaultTemplate(c *gin.Context) {
 }
 
 // DeleteTemplate deletes a template
+// @Summary Delete template
+// @Tags templates
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId} [delete]
 func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
 	templateID, err := uuid.Parse(c.Param("templateId"))
 	if err != nil {
@@ -158,47 +239,67 @@ func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
 	SuccessResponse(c, http.StatusOK, "Template deleted successfully", nil)
 }
 
-// ListTemplates lists templates with pagination and filtering
-func (h *TemplateHandler) ListTemplates(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+// templateListQuery declares the sorting and filtering accepted by ListTemplates
+var templateListQuery = listquery.Config{
+	Sorts: map[string]string{
+		"created_at":       "created_at",
+		"updated_at":       "updated_at",
+		"name":             "name",
+		"application_type": "application_type",
+	},
+	DefaultSort: "-created_at",
+	Filters: map[string]listquery.Field{
+		"application_type": {Column: "application_type", Ops: []string{listquery.OpEq, listquery.OpIn}},
+		"is_active":        {Column: "is_active", Type: listquery.TypeBool},
+		"is_default":       {Column: "is_default", Type: listquery.TypeBool},
+		"name":             {Column: "name", Ops: []string{listquery.OpEq, listquery.OpLike}},
+		"created_at":       {Column: "created_at", Type: listquery.TypeTime},
+	},
+}
 
-	// Build filters from query params
-	filters := make(map[string]interface{})
-	if applicationType := c.Query("application_type"); applicationType != "" {
-		filters["application_type"] = applicationType
-	}
-	if isActive := c.Query("is_active"); isActive != "" {
-		if active, err := strconv.ParseBool(isActive); err == nil {
-			filters["is_active"] = active
-		}
-	}
-	if isDefault := c.Query("is_default"); isDefault != "" {
-		if defaultFlag, err := strconv.ParseBool(isDefault); err == nil {
-			filters["is_default"] = defaultFlag
-		}
+// ListTemplates lists templates with pagination, sorting and filtering
+// @Summary List templates
+// @Description Lists templates; supports page/limit or cursor pagination, sort=-created_at and filters such as application_type[in]=ecommerce,saas
+// @Tags templates
+// @Produce json
+// @Param application_type query string false "Application type"
+// @Param is_active query bool false "Only active templates"
+// @Param is_default query bool false "Only default templates"
+// @Param sort query string false "Sort key (created_at, updated_at, name, application_type), prefix with - for descending"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates [get]
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	q, err := listquery.Parse(c.Request.URL.Query(), templateListQuery)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
 	}
 
-	templates, total, err := h.templateService.ListTemplates(c.Request.Context(), page, pageSize, filters)
+	templates, total, next, err := h.templateService.ListTemplates(c.Request.Context(), q)
 	if err != nil {
 		ErrorResponse(c, http.StatusInternalServerError, "Failed to list templates", err)
 		return
 	}
 
 	response := map[string]interface{}{
-		"templates": templates,
-		"pagination": map[string]interface{}{
-			"page":        page,
-			"page_size":   pageSize,
-			"total":       total,
-			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
-		},
+		"templates":  templates,
+		"pagination": q.Meta(total, next),
 	}
 
 	SuccessResponse(c, http.StatusOK, "Templates listed successfully", response)
 }
 
 // ValidateTemplateConfiguration validates template configuration
+// @Summary Validate template configuration
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param request body object true "{\"configuration\": {}}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/validate-config [post]
 func (h *TemplateHandler) ValidateTemplateConfiguration(c *gin.Context) {
 	var req struct {
 		Configuration map[string]interface{} `json:"configuration" binding:"required"`
@@ -216,3 +317,218 @@ func (h *TemplateHandler) ValidateTemplateConfiguration(c *gin.Context) {
 
 	SuccessResponse(c, http.StatusOK, "Template configuration is valid", nil)
 }
+
+// ListTemplateVersions lists the draft, published and archived versions of a template
+// GET /api/v1/onboarding/templates/:templateId/versions
+// @Summary List template versions
+// @Description Lists the draft, published and archived versions of a template
+// @Tags templates
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Success 200 {array} models.OnboardingTemplateVersion
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId}/versions [get]
+func (h *TemplateHandler) ListTemplateVersions(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid template ID", err)
+		return
+	}
+
+	versions, err := h.templateService.ListVersions(c.Request.Context(), templateID)
+	if err != nil {
+		h.respondVersionError(c, "Failed to list template versions", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Template versions retrieved successfully", versions)
+}
+
+// GetTemplateVersion retrieves one version of a template
+// GET /api/v1/onboarding/templates/:templateId/versions/:version
+// @Summary Get template version
+// @Tags templates
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} models.OnboardingTemplateVersion
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId}/versions/{version} [get]
+func (h *TemplateHandler) GetTemplateVersion(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid template ID", err)
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid template version", err)
+		return
+	}
+
+	v, err := h.templateService.GetVersion(c.Request.Context(), templateID, version)
+	if err != nil {
+		h.respondVersionError(c, "Failed to get template version", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Template version retrieved successfully", v)
+}
+
+// SaveTemplateDraft creates or updates the draft version of a template
+// PUT /api/v1/onboarding/templates/:templateId/draft
+// @Summary Save template draft
+// @Description Creates or updates the draft version of a template
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Param request body services.TemplateDraftRequest true "Draft"
+// @Success 200 {object} models.OnboardingTemplateVersion
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId}/draft [put]
+func (h *TemplateHandler) SaveTemplateDraft(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid template ID", err)
+		return
+	}
+
+	var req services.TemplateDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	draft, err := h.templateService.SaveDraft(c.Request.Context(), templateID, &req)
+	if err != nil {
+		h.respondVersionError(c, "Failed to save template draft", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Template draft saved successfully", draft)
+}
+
+// DiscardTemplateDraft deletes the draft version of a template
+// DELETE /api/v1/onboarding/templates/:templateId/draft
+// @Summary Discard template draft
+// @Tags templates
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId}/draft [delete]
+func (h *TemplateHandler) DiscardTemplateDraft(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid template ID", err)
+		return
+	}
+
+	if err := h.templateService.DiscardDraft(c.Request.Context(), templateID); err != nil {
+		h.respondVersionError(c, "Failed to discard template draft", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Template draft discarded successfully", nil)
+}
+
+// PublishTemplate publishes the draft version of a template
+// POST /api/v1/onboarding/templates/:templateId/publish
+// @Summary Publish template draft
+// @Description Publishes the draft version, archiving the previously published one
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Param request body object false "{\"change_notes\": \"...\"}"
+// @Success 200 {object} models.OnboardingTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId}/publish [post]
+func (h *TemplateHandler) PublishTemplate(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid template ID", err)
+		return
+	}
+
+	var req struct {
+		ChangeNotes string `json:"change_notes"`
+	}
+	// The body is optional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+			return
+		}
+	}
+
+	template, err := h.templateService.PublishDraft(c.Request.Context(), templateID, req.ChangeNotes)
+	if err != nil {
+		h.respondVersionError(c, "Failed to publish template", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Template version published successfully", template)
+}
+
+// DiffTemplateVersions compares two versions of a template
+// GET /api/v1/onboarding/templates/:templateId/diff?from=1&to=2
+// @Summary Diff template versions
+// @Tags templates
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Param from query int true "Version to compare from"
+// @Param to query int true "Version to compare to"
+// @Success 200 {object} services.TemplateVersionDiff
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/onboarding/templates/{templateId}/diff [get]
+func (h *TemplateHandler) DiffTemplateVersions(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid template ID", err)
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 1 {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid from version", err)
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil || to < 1 {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid to version", err)
+		return
+	}
+
+	diff, err := h.templateService.DiffVersions(c.Request.Context(), templateID, from, to)
+	if err != nil {
+		h.respondVersionError(c, "Failed to diff template versions", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Template versions compared successfully", diff)
+}
+
+// respondVersionError maps template versioning errors to HTTP responses
+func (h *TemplateHandler) respondVersionError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrTemplateNotFound):
+		ErrorResponse(c, http.StatusNotFound, "Template not found", err)
+	case errors.Is(err, services.ErrTemplateVersionNotFound):
+		ErrorResponse(c, http.StatusNotFound, "Template version not found", err)
+	case errors.Is(err, services.ErrTemplateDraftNotFound):
+		ErrorResponse(c, http.StatusNotFound, "Template has no draft version", err)
+	default:
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, message, err)
+	}
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// TenantAPIKeyHandler handles tenant API key management and validation
+type TenantAPIKeyHandler struct {
+	apiKeyService *services.TenantAPIKeyService
+}
+
+// NewTenantAPIKeyHandler creates a new tenant API key handler
+func NewTenantAPIKeyHandler(apiKeyService *services.TenantAPIKeyService) *TenantAPIKeyHandler {
+	return &TenantAPIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// ValidateAPIKeyRequest is sent by other services to resolve a presented API key
+type ValidateAPIKeyRequest struct {
+	Key           string `json:"key" binding:"required"`
+	RequiredScope string `json:"required_scope"`
+}
+
+// CreateAPIKey mints a new scoped API key
+// @Summary Create tenant API key
+// @Description Mint a read_only, member_management or full API key; the key is only returned in this response (owner only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.CreateAPIKeyRequest true "Key name, scope and optional expiry"
+// @Success 201 {object} services.CreatedAPIKey
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/api-keys [post]
+func (h *TenantAPIKeyHandler) CreateAPIKey(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req services.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	key, err := h.apiKeyService.Create(c.Request.Context(), tenantID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to create API key")
+		return
+	}
+
+	SuccessResponse(c, http.StatusCreated, "API key created; store it now, it will not be shown again", key)
+}
+
+// ListAPIKeys returns the tenant's API keys without their secrets
+// @Summary List tenant API keys
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {array} models.TenantAPIKey
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/api-keys [get]
+func (h *TenantAPIKeyHandler) ListAPIKeys(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.apiKeyService.List(c.Request.Context(), tenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to list API keys", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "API keys retrieved", keys)
+}
+
+// RotateAPIKey replaces a key with a new one of the same name and scope
+// @Summary Rotate tenant API key
+// @Description Issue a replacement key; the old key is revoked immediately or after grace_period_minutes (owner only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param keyId path string true "API key ID"
+// @Param request body services.RotateAPIKeyRequest false "Optional grace period for the old key"
+// @Success 201 {object} services.CreatedAPIKey
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/api-keys/{keyId}/rotate [post]
+func (h *TenantAPIKeyHandler) RotateAPIKey(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	keyID, err := uuid.Parse(c.Param("keyId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid API key ID format", err)
+		return
+	}
+
+	var req services.RotateAPIKeyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+			return
+		}
+	}
+
+	key, err := h.apiKeyService.Rotate(c.Request.Context(), tenantID, keyID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to rotate API key")
+		return
+	}
+
+	SuccessResponse(c, http.StatusCreated, "API key rotated; store the new key now, it will not be shown again", key)
+}
+
+// RevokeAPIKey disables a key immediately
+// @Summary Revoke tenant API key
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param keyId path string true "API key ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/api-keys/{keyId} [delete]
+func (h *TenantAPIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	keyID, err := uuid.Parse(c.Param("keyId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid API key ID format", err)
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request.Context(), tenantID, keyID, userID); err != nil {
+		h.respondError(c, err, "Failed to revoke API key")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "API key revoked", nil)
+}
+
+// ValidateAPIKey resolves an API key for service-to-service calls
+// @Summary Validate tenant API key (internal)
+// @Description Returns the tenant and scope of a valid key; 401 for unknown, revoked or expired keys, 403 when required_scope is not covered and 429 when the tenant's monthly API call quota is used up (quota enforcement only)
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param X-Internal-Service header string true "Internal service name"
+// @Param request body ValidateAPIKeyRequest true "Presented key and optional required scope"
+// @Success 200 {object} services.APIKeyValidation
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /internal/api-keys/validate [post]
+func (h *TenantAPIKeyHandler) ValidateAPIKey(c *gin.Context) {
+	var req ValidateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	result, err := h.apiKeyService.Validate(c.Request.Context(), req.Key, req.RequiredScope)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAPIKeyInvalid):
+			ErrorResponse(c, http.StatusUnauthorized, err.Error(), nil)
+		case errors.Is(err, services.ErrAPIKeyInsufficientScope):
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+		case errors.Is(err, services.ErrQuotaExceeded):
+			ErrorResponse(c, http.StatusTooManyRequests, err.Error(), nil)
+		default:
+			h.respondError(c, err, "Failed to validate API key")
+		}
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "API key is valid", result)
+}
+
+// authorize resolves the tenant and user and checks the user owns the tenant
+func (h *TenantAPIKeyHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.apiKeyService.AuthorizeManage(c.Request.Context(), tenantID, userID); err != nil {
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
+
+// respondError maps tenant API key service errors to HTTP responses
+func (h *TenantAPIKeyHandler) respondError(c *gin.Context, err error, fallback string) {
+	if validationErr, ok := services.IsValidationError(err); ok {
+		ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrAPIKeyRevoked), errors.Is(err, services.ErrAPIKeyRotated):
+		ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, fallback, err)
+	}
+}
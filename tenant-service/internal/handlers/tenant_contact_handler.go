@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// TenantContactHandler handles verified changes of the tenant's business contact
+type TenantContactHandler struct {
+	contactService *services.ContactChangeService
+}
+
+// NewTenantContactHandler creates a new tenant contact handler
+func NewTenantContactHandler(contactService *services.ContactChangeService) *TenantContactHandler {
+	return &TenantContactHandler{contactService: contactService}
+}
+
+// GetContact returns the business contact
+// @Summary Get tenant business contact
+// @Description Returns the active business contact email and phone and any changes awaiting verification (owner or admin only).
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.TenantContact
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/contact [get]
+func (h *TenantContactHandler) GetContact(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	contact, err := h.contactService.GetContact(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Tenant contact retrieved", contact)
+}
+
+// RequestChange starts a contact change
+// @Summary Request business contact change
+// @Description Sends a verification code to the new email (by email) or phone (by SMS). The current value stays active until the code is verified; a new request cancels the pending change of the same field.
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.RequestContactChangeRequest true "Field and new value"
+// @Success 202 {object} services.ContactChangeResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/contact/changes [post]
+func (h *TenantContactHandler) RequestChange(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req services.RequestContactChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	result, err := h.contactService.RequestChange(c.Request.Context(), tenantID, req, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusAccepted, "Verification code sent", result)
+}
+
+// VerifyChange commits a contact change
+// @Summary Verify business contact change
+// @Description Verifies the code sent to the new value and makes it the active business contact. The change is logged with the before and after values.
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param changeId path string true "Contact change ID"
+// @Param request body services.VerifyContactChangeRequest true "Verification code"
+// @Success 200 {object} services.TenantContact
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/contact/changes/{changeId}/verify [post]
+func (h *TenantContactHandler) VerifyChange(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	changeID, err := uuid.Parse(c.Param("changeId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid change ID format", err)
+		return
+	}
+
+	var req services.VerifyContactChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	contact, err := h.contactService.VerifyChange(c.Request.Context(), tenantID, changeID, req.Code, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Tenant contact changed", contact)
+}
+
+// ResendCode resends the verification code of a pending change
+// @Summary Resend business contact verification code
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param changeId path string true "Contact change ID"
+// @Success 200 {object} services.ContactChangeResult
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/contact/changes/{changeId}/resend [post]
+func (h *TenantContactHandler) ResendCode(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	changeID, err := uuid.Parse(c.Param("changeId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid change ID format", err)
+		return
+	}
+
+	result, err := h.contactService.ResendCode(c.Request.Context(), tenantID, changeID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Verification code resent", result)
+}
+
+// CancelChange cancels a pending change
+// @Summary Cancel business contact change
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param changeId path string true "Contact change ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/contact/changes/{changeId} [delete]
+func (h *TenantContactHandler) CancelChange(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	changeID, err := uuid.Parse(c.Param("changeId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid change ID format", err)
+		return
+	}
+
+	if err := h.contactService.CancelChange(c.Request.Context(), tenantID, changeID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Contact change cancelled", nil)
+}
+
+// authorize parses the tenant and user IDs and checks the user may change the contact,
+// writing the error response if not
+func (h *TenantContactHandler) authorize(c *gin.Context) (tenantID, userID uuid.UUID, ok bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err = uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.contactService.AuthorizeChange(c.Request.Context(), tenantID, userID); err != nil {
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, userID, true
+}
+
+// respondError maps contact change errors to status codes
+func (h *TenantContactHandler) respondError(c *gin.Context, err error) {
+	if validationErr, ok := services.IsValidationError(err); ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   validationErr.Message,
+			"code":    "VALIDATION_ERROR",
+			"field":   validationErr.Field,
+		})
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrContactChangeNotFound), err.Error() == "tenant not found":
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrContactChangeInvalidCode):
+		ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, services.ErrContactChangeNotPending),
+		errors.Is(err, services.ErrContactChangeStale):
+		ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, services.ErrContactVerificationUnavailable):
+		ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to change tenant contact", err)
+	}
+}
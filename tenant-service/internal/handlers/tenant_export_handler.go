@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+// TenantExportHandler handles tenant data export (data portability) requests
+type TenantExportHandler struct {
+	exportService *services.TenantExportService
+}
+
+// NewTenantExportHandler creates a new tenant export handler
+func NewTenantExportHandler(exportService *services.TenantExportService) *TenantExportHandler {
+	return &TenantExportHandler{exportService: exportService}
+}
+
+// RequestExport starts generating a data export for the tenant
+// @Summary Request tenant data export
+// @Description Queue a ZIP archive of the tenant's memberships, onboarding data, activity and auth audit logs; the owner is emailed a download link when it is ready (owner only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 202 {object} models.TenantExport
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/export [post]
+func (h *TenantExportHandler) RequestExport(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	export, created, err := h.exportService.RequestExport(c.Request.Context(), tenantID, userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to request tenant export", err)
+		return
+	}
+
+	message := "Tenant export started; a download link will be emailed when it is ready"
+	if !created {
+		message = "A tenant export is already in progress"
+	}
+	SuccessResponse(c, http.StatusAccepted, message, export)
+}
+
+// DownloadExport streams the latest (or a specific) completed export archive
+// @Summary Download tenant data export
+// @Description Stream the ZIP archive of the latest completed export, or of export_id (owner only)
+// @Tags tenants
+// @Produce application/zip
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param export_id query string false "Export ID (default: latest completed export)"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/export [get]
+func (h *TenantExportHandler) DownloadExport(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var exportID *uuid.UUID
+	if raw := c.Query("export_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid export ID format", err)
+			return
+		}
+		exportID = &id
+	}
+
+	export, err := h.exportService.OpenArchive(c.Request.Context(), tenantID, exportID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	h.sendArchive(c, export)
+}
+
+// ListExports returns the tenant's export history
+// @Summary List tenant data exports
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {array} models.TenantExport
+// @Router /api/v1/tenants/{id}/exports [get]
+func (h *TenantExportHandler) ListExports(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	exports, err := h.exportService.ListExports(c.Request.Context(), tenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to list tenant exports", err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Tenant exports retrieved", exports)
+}
+
+// GetExport returns the status of one export
+// @Summary Get tenant data export status
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param exportId path string true "Export ID"
+// @Success 200 {object} models.TenantExport
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/exports/{exportId} [get]
+func (h *TenantExportHandler) GetExport(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	exportID, err := uuid.Parse(c.Param("exportId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid export ID format", err)
+		return
+	}
+
+	export, err := h.exportService.GetExport(c.Request.Context(), tenantID, exportID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Tenant export retrieved", export)
+}
+
+// DownloadExportByToken streams an export archive from the emailed download link
+// @Summary Download tenant data export by link
+// @Description Public download for the tokenized link sent when an export completes
+// @Tags tenants
+// @Produce application/zip
+// @Param exportId path string true "Export ID"
+// @Param token query string true "Download token"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenant-exports/{exportId}/download [get]
+func (h *TenantExportHandler) DownloadExportByToken(c *gin.Context) {
+	exportID, err := uuid.Parse(c.Param("exportId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusNotFound, "Tenant export not found", nil)
+		return
+	}
+
+	export, err := h.exportService.OpenArchiveByToken(c.Request.Context(), exportID, c.Query("token"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	h.sendArchive(c, export)
+}
+
+func (h *TenantExportHandler) sendArchive(c *gin.Context, export *models.TenantExport) {
+	filename := fmt.Sprintf("tenant-export-%s-%s.zip", export.TenantID, export.CreatedAt.UTC().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-SHA256", export.Checksum)
+	c.Data(http.StatusOK, "application/zip", export.Archive)
+}
+
+// authorize resolves the tenant and user and checks the user owns the tenant
+func (h *TenantExportHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.exportService.AuthorizeExport(c.Request.Context(), tenantID, userID); err != nil {
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
+
+// respondError maps tenant export service errors to HTTP responses
+func (h *TenantExportHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTenantExportNotFound):
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrTenantExportNotReady):
+		ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to load tenant export", err)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -25,6 +26,7 @@ func getUserID(c *gin.Context) string {
 type TenantHandler struct {
 	tenantService      *services.TenantService
 	offboardingService *services.OffboardingService
+	deletionSagaSvc    *services.TenantDeletionSagaService
 }
 
 // NewTenantHandler creates a new tenant handler
@@ -35,6 +37,11 @@ func NewTenantHandler(tenantService *services.TenantService, offboardingService
 	}
 }
 
+// SetDeletionSagaService sets the tenant deletion saga coordinator for the deletion status endpoint
+func (h *TenantHandler) SetDeletionSagaService(svc *services.TenantDeletionSagaService) {
+	h.deletionSagaSvc = svc
+}
+
 // CreateTenantForUserRequest represents the request to create a tenant for an existing user
 type CreateTenantForUserRequest struct {
 	Name           string `json:"name" binding:"required,min=2"`
@@ -51,6 +58,7 @@ type CreateTenantForUserRequest struct {
 // @Tags tenants
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param X-User-ID header string true "Authenticated user ID"
 // @Param request body CreateTenantForUserRequest true "Tenant creation request"
 // @Success 201 {object} map[string]interface{}
@@ -106,6 +114,7 @@ func (h *TenantHandler) CreateTenantForUser(c *gin.Context) {
 // @Description Checks if a tenant slug is available
 // @Tags tenants
 // @Produce json
+// @Security BearerAuth
 // @Param slug query string true "Slug to check"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
@@ -132,13 +141,14 @@ type DeleteTenantRequest struct {
 	Reason           string `json:"reason"`
 }
 
-// DeleteTenant deletes a tenant and archives all data
+// DeleteTenant schedules a tenant for deletion; it is archived and purged once the grace period elapses
 // @Summary Delete tenant
-// @Description Permanently deletes a tenant and archives all data for audit purposes. Only the tenant owner can perform this action.
+// @Description Puts a tenant in pending_deletion for the grace period of its plan, after which it is permanently deleted and archived for audit purposes. The owner can restore it until then. Only the tenant owner can perform this action.
 // @Tags tenants
 // @Accept json
 // @Produce json
-// @Param tenantId path string true "Tenant ID"
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
 // @Param X-User-ID header string true "Authenticated user ID"
 // @Param request body DeleteTenantRequest true "Deletion request with confirmation"
 // @Success 200 {object} map[string]interface{}
@@ -146,8 +156,9 @@ type DeleteTenantRequest struct {
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
-// @Router /api/v1/tenants/{tenantId} [delete]
+// @Router /api/v1/tenants/{id} [delete]
 func (h *TenantHandler) DeleteTenant(c *gin.Context) {
 	// Get tenant ID from path
 	tenantIDStr := c.Param("id")
@@ -200,6 +211,10 @@ func (h *TenantHandler) DeleteTenant(c *gin.Context) {
 			ErrorResponse(c, http.StatusBadRequest, errMsg, nil)
 			return
 		}
+		if errors.Is(err, services.ErrTenantPendingDeletion) {
+			ErrorResponse(c, http.StatusConflict, errMsg, nil)
+			return
+		}
 		ErrorResponse(c, http.StatusInternalServerError, "Failed to delete tenant", err)
 		return
 	}
@@ -212,14 +227,15 @@ func (h *TenantHandler) DeleteTenant(c *gin.Context) {
 // @Description Gets tenant information and requirements for deletion. Only the tenant owner can access this.
 // @Tags tenants
 // @Produce json
-// @Param tenantId path string true "Tenant ID"
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
 // @Param X-User-ID header string true "Authenticated user ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
-// @Router /api/v1/tenants/{tenantId}/deletion [get]
+// @Router /api/v1/tenants/{id}/deletion [get]
 func (h *TenantHandler) GetTenantDeletionInfo(c *gin.Context) {
 	// Get tenant ID from path
 	tenantIDStr := c.Param("id")
@@ -261,6 +277,122 @@ func (h *TenantHandler) GetTenantDeletionInfo(c *gin.Context) {
 	SuccessResponse(c, http.StatusOK, "Tenant deletion info retrieved", info)
 }
 
+// GetTenantDeletionStatus reports which services have purged a deleted tenant's data
+// @Summary Get tenant deletion status
+// @Description Shows per-service purge acknowledgments for a deleted tenant. Only the former owner or the user who deleted the tenant can access this.
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param X-User-ID header string true "Authenticated user ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/deletion/status [get]
+func (h *TenantHandler) GetTenantDeletionStatus(c *gin.Context) {
+	if h.deletionSagaSvc == nil {
+		ErrorResponse(c, http.StatusServiceUnavailable, "Tenant deletion tracking is not available", nil)
+		return
+	}
+
+	// Get tenant ID from path
+	tenantIDStr := c.Param("id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return
+	}
+
+	// Get user ID from Istio auth context or legacy header
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return
+	}
+
+	status, deleted, err := h.deletionSagaSvc.GetStatus(c.Request.Context(), tenantID)
+	if err != nil {
+		if errors.Is(err, services.ErrDeletionNotFound) {
+			ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get deletion status", err)
+		return
+	}
+
+	// Memberships are gone once the tenant is deleted, so authorize against the archived record
+	if deleted.OwnerUserID != userID && deleted.DeletedByUserID != userID {
+		ErrorResponse(c, http.StatusForbidden, "only the tenant owner can view deletion status", nil)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Tenant deletion status retrieved", status)
+}
+
+// RestoreTenant cancels a pending tenant deletion during its grace period
+// @Summary Restore tenant
+// @Description Cancels a scheduled deletion and returns the tenant to its previous status. Only the tenant owner can perform this action.
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param X-User-ID header string true "Authenticated user ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/restore [post]
+func (h *TenantHandler) RestoreTenant(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return
+	}
+
+	tenant, err := h.offboardingService.RestoreTenant(c.Request.Context(), tenantID, userID)
+	if err != nil {
+		switch {
+		case err.Error() == "tenant not found":
+			ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+		case errors.Is(err, services.ErrTenantRestoreForbidden):
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+		case errors.Is(err, services.ErrTenantNotPendingDeletion):
+			ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+		default:
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to restore tenant", err)
+		}
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Tenant restored successfully", map[string]interface{}{
+		"tenant_id": tenant.ID.String(),
+		"slug":      tenant.Slug,
+		"status":    tenant.Status,
+	})
+}
+
 // GetTenantInfo returns basic tenant information for internal service-to-service calls
 // This endpoint doesn't require user authentication, only internal service header
 // @Summary Get tenant info (internal)
@@ -335,6 +467,45 @@ func (h *TenantHandler) GetTenantBySlug(c *gin.Context) {
 	SuccessResponse(c, http.StatusOK, "Tenant info retrieved", info)
 }
 
+// BulkGetTenants resolves many tenants by ID and/or slug for internal service-to-service calls
+// @Summary Bulk tenant lookup (internal)
+// @Description Resolves up to 500 tenant IDs and slugs in one call. Tenants that are not found or fail to load are reported per entry instead of failing the request.
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param X-Internal-Service header string true "Internal service name"
+// @Param request body services.BulkTenantLookupRequest true "Tenant IDs and slugs"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /internal/tenants/bulk [post]
+func (h *TenantHandler) BulkGetTenants(c *gin.Context) {
+	// Verify internal service header
+	internalService := c.GetHeader("X-Internal-Service")
+	if internalService == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "Internal service header required", nil)
+		return
+	}
+
+	var req services.BulkTenantLookupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	result, err := h.tenantService.BulkGetTenants(c.Request.Context(), &req)
+	if err != nil {
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to look up tenants", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Tenants retrieved", result)
+}
+
 // GetTenantOnboardingData returns onboarding data for a tenant (for settings pre-population)
 // This endpoint implements multi-tenant security by verifying user access before returning data.
 // @Summary Get tenant onboarding data
@@ -342,6 +513,7 @@ func (h *TenantHandler) GetTenantBySlug(c *gin.Context) {
 // @Description This data is used to pre-populate settings pages. Requires active membership to tenant.
 // @Tags tenants
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tenant ID (UUID)"
 // @Param X-User-ID header string true "Authenticated user ID"
 // @Success 200 {object} map[string]interface{} "Onboarding data retrieved successfully"
@@ -397,6 +569,7 @@ func (h *TenantHandler) GetTenantOnboardingData(c *gin.Context) {
 // @Description Returns the GrowthBook SDK key and org ID for a tenant
 // @Tags tenants
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tenant ID or slug"
 // @Success 200 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -426,6 +599,7 @@ func (h *TenantHandler) GetTenantGrowthBookConfig(c *gin.Context) {
 // @Description Returns the GrowthBook SDK client key for a tenant
 // @Tags tenants
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tenant ID or slug"
 // @Success 200 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
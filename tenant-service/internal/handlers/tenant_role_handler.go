@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// TenantRoleHandler handles tenant-defined roles and internal permission checks
+type TenantRoleHandler struct {
+	roleService *services.TenantRoleService
+}
+
+// NewTenantRoleHandler creates a new tenant role handler
+func NewTenantRoleHandler(roleService *services.TenantRoleService) *TenantRoleHandler {
+	return &TenantRoleHandler{roleService: roleService}
+}
+
+// ListRoles returns the built-in and custom roles of a tenant
+// @Summary List tenant roles
+// @Description Returns the built-in roles with their fixed permissions and the roles the tenant defined (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.TenantRolesResponse
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/roles [get]
+func (h *TenantRoleHandler) ListRoles(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	roles, err := h.roleService.ListRoles(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list roles")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Roles retrieved", roles)
+}
+
+// GetRole returns one of the tenant's custom roles
+// @Summary Get tenant role
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param roleId path string true "Role ID"
+// @Success 200 {object} models.TenantRole
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/roles/{roleId} [get]
+func (h *TenantRoleHandler) GetRole(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	roleID, ok := parseRoleID(c)
+	if !ok {
+		return
+	}
+
+	role, err := h.roleService.GetRole(c.Request.Context(), tenantID, roleID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get role")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Role retrieved", role)
+}
+
+// CreateRole defines a custom role
+// @Summary Create tenant role
+// @Description Defines a role with a set of resource:action permissions (e.g. orders:view, catalog:*) that can be assigned to members (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.SaveRoleRequest true "Role"
+// @Success 201 {object} models.TenantRole
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/roles [post]
+func (h *TenantRoleHandler) CreateRole(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req services.SaveRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	role, err := h.roleService.CreateRole(c.Request.Context(), tenantID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to create role")
+		return
+	}
+	SuccessResponse(c, http.StatusCreated, "Role created", role)
+}
+
+// UpdateRole replaces a custom role
+// @Summary Update tenant role
+// @Description Replaces the role's name and permissions; members holding the role get the new permissions immediately (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param roleId path string true "Role ID"
+// @Param request body services.SaveRoleRequest true "Role"
+// @Success 200 {object} models.TenantRole
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/roles/{roleId} [put]
+func (h *TenantRoleHandler) UpdateRole(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	roleID, ok := parseRoleID(c)
+	if !ok {
+		return
+	}
+
+	var req services.SaveRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(c.Request.Context(), tenantID, roleID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update role")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Role updated", role)
+}
+
+// DeleteRole removes a custom role no member holds
+// @Summary Delete tenant role
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param roleId path string true "Role ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/roles/{roleId} [delete]
+func (h *TenantRoleHandler) DeleteRole(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	roleID, ok := parseRoleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.roleService.DeleteRole(c.Request.Context(), tenantID, roleID, userID); err != nil {
+		h.respondError(c, err, "Failed to delete role")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Role deleted", nil)
+}
+
+// CheckPermission reports whether a member holds a permission (internal)
+// @Summary Check member permission
+// @Description Called by staff-service and settings-service to enforce built-in and custom role permissions consistently
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body services.PermissionCheckRequest true "User and permission"
+// @Success 200 {object} services.PermissionCheckResult
+// @Failure 400 {object} map[string]interface{}
+// @Router /internal/tenants/{id}/permissions/check [post]
+func (h *TenantRoleHandler) CheckPermission(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return
+	}
+
+	var req services.PermissionCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	result, err := h.roleService.CheckPermission(c.Request.Context(), tenantID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to check permission")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "Permission checked", result)
+}
+
+// respondError maps role service errors to HTTP responses
+func (h *TenantRoleHandler) respondError(c *gin.Context, err error, fallback string) {
+	if validationErr, ok := services.IsValidationError(err); ok {
+		ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrRoleNotFound):
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrRoleNameTaken), errors.Is(err, services.ErrRoleInUse):
+		ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, fallback, err)
+	}
+}
+
+// authorize resolves the tenant and user and checks the user may manage roles
+func (h *TenantRoleHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.roleService.AuthorizeManage(c.Request.Context(), tenantID, userID); err != nil {
+		if errors.Is(err, services.ErrRoleForbidden) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return uuid.Nil, uuid.Nil, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to verify permissions", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
+
+func parseRoleID(c *gin.Context) (uuid.UUID, bool) {
+	roleID, err := uuid.Parse(c.Param("roleId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid role ID format", err)
+		return uuid.Nil, false
+	}
+	return roleID, true
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/repository"
+	"tenant-service/internal/services"
+)
+
+// TenantSlugHandler handles tenant slug renames
+type TenantSlugHandler struct {
+	slugService *services.SlugChangeService
+}
+
+// NewTenantSlugHandler creates a new tenant slug handler
+func NewTenantSlugHandler(slugService *services.SlugChangeService) *TenantSlugHandler {
+	return &TenantSlugHandler{slugService: slugService}
+}
+
+// ChangeSlug renames the tenant
+// @Summary Change tenant slug
+// @Description Renames the tenant and moves its admin, storefront and API URLs to the new slug (owner only).
+// @Description The old slug keeps resolving to the tenant and stays reserved for TENANT_SLUG_REDIRECT_DAYS; renames are limited by TENANT_SLUG_CHANGE_COOLDOWN_DAYS.
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.ChangeSlugRequest true "New slug"
+// @Success 200 {object} services.SlugChangeResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/slug [put]
+func (h *TenantSlugHandler) ChangeSlug(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return
+	}
+
+	if err := h.slugService.AuthorizeChange(c.Request.Context(), tenantID, userID); err != nil {
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	var req services.ChangeSlugRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	result, err := h.slugService.ChangeSlug(c.Request.Context(), tenantID, req, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Tenant slug changed", result)
+}
+
+// respondError maps slug change errors to status codes
+func (h *TenantSlugHandler) respondError(c *gin.Context, err error) {
+	// Unavailable slugs come back with alternatives, like onboarding slug checks
+	if validationErr, ok := services.IsValidationError(err); ok {
+		c.JSON(http.StatusConflict, gin.H{
+			"success":     false,
+			"error":       validationErr.Message,
+			"code":        "VALIDATION_ERROR",
+			"field":       validationErr.Field,
+			"suggestions": validationErr.Suggestions,
+		})
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrSlugTaken):
+		ErrorResponse(c, http.StatusConflict, "This name was just taken. Try another slug", nil)
+	case errors.Is(err, services.ErrSlugUnchanged),
+		errors.Is(err, services.ErrSlugChangeCustomDomain),
+		errors.Is(err, services.ErrSlugChangeNotActive):
+		ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, services.ErrSlugChangeCooldown):
+		ErrorResponse(c, http.StatusTooManyRequests, err.Error(), nil)
+	case err.Error() == "tenant not found":
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to change slug", err)
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// TenantSSOHandler handles per-tenant single sign-on configuration and SSO member provisioning
+type TenantSSOHandler struct {
+	ssoService *services.TenantSSOService
+}
+
+// NewTenantSSOHandler creates a new tenant SSO handler
+func NewTenantSSOHandler(ssoService *services.TenantSSOService) *TenantSSOHandler {
+	return &TenantSSOHandler{ssoService: ssoService}
+}
+
+// GetSSOConfig returns the tenant's SSO configuration
+// @Summary Get tenant SSO configuration
+// @Description Returns the identity provider setup, routed email domains, group to role mappings and the redirect URI to register at the IdP (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.SSOConfigResponse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/sso [get]
+func (h *TenantSSOHandler) GetSSOConfig(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	config, err := h.ssoService.GetConfig(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get SSO configuration")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "SSO configuration retrieved", config)
+}
+
+// SaveSSOConfig creates or replaces the tenant's SSO configuration
+// @Summary Save tenant SSO configuration
+// @Description Provisions the tenant's OIDC or SAML identity provider in Keycloak. SAML takes metadata_xml or metadata_url; OIDC takes the endpoints, client_id and client_secret (omit the secret to keep the current one). Group role mappings may grant admin, manager, member or viewer (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.SaveSSOConfigRequest true "SSO configuration"
+// @Success 200 {object} services.SSOConfigResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/sso [put]
+func (h *TenantSSOHandler) SaveSSOConfig(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req services.SaveSSOConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	config, err := h.ssoService.SaveConfig(c.Request.Context(), tenantID, userID, req)
+	if err != nil {
+		if errors.Is(err, services.ErrSSOProvisioningFailed) {
+			// The configuration was stored with the failure so it shows up in GET
+			c.JSON(http.StatusBadGateway, gin.H{
+				"success": false,
+				"message": "SSO configuration saved but the identity provider could not be provisioned",
+				"data":    config, // provisioning_error has Keycloak's reason
+			})
+			return
+		}
+		h.respondError(c, err, "Failed to save SSO configuration")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "SSO configuration saved", config)
+}
+
+// DeleteSSOConfig removes the tenant's identity provider and SSO configuration
+// @Summary Delete tenant SSO configuration
+// @Description Deletes the Keycloak identity provider; staff go back to password login (owner/admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/sso [delete]
+func (h *TenantSSOHandler) DeleteSSOConfig(c *gin.Context) {
+	tenantID, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	if err := h.ssoService.DeleteConfig(c.Request.Context(), tenantID, userID); err != nil {
+		h.respondError(c, err, "Failed to delete SSO configuration")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "SSO configuration deleted", nil)
+}
+
+// ValidateSAMLMetadata checks a SAML metadata document without saving it
+// @Summary Validate SAML metadata
+// @Description Parses uploaded (metadata_xml) or remote (metadata_url) IdP metadata and returns the entity ID, endpoints and signing certificate expiry (owner/admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.ValidateSAMLMetadataRequest true "Metadata"
+// @Success 200 {object} services.SAMLMetadata
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/sso/metadata/validate [post]
+func (h *TenantSSOHandler) ValidateSAMLMetadata(c *gin.Context) {
+	if _, _, ok := h.authorize(c); !ok {
+		return
+	}
+
+	var req services.ValidateSAMLMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	metadata, err := h.ssoService.ValidateMetadata(c.Request.Context(), req)
+	if err != nil {
+		h.respondError(c, err, "Failed to validate SAML metadata")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "SAML metadata is valid", metadata)
+}
+
+// TestSSOConnection checks Keycloak can reach the tenant's identity provider
+// @Summary Test tenant SSO connection
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/sso/test [post]
+func (h *TenantSSOHandler) TestSSOConnection(c *gin.Context) {
+	tenantID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.ssoService.TestConnection(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err, "Failed to test SSO connection")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, result.Message, result)
+}
+
+// ProvisionSSOMember creates or updates the caller's membership after an SSO login
+// @Summary Provision SSO member
+// @Description Called by auth-bff after a login brokered through a tenant identity provider. Creates the tenant user and membership on first login and re-derives the role from the IdP groups on every login; owners are never changed
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.ProvisionSSOMemberRequest true "Broker login details"
+// @Success 200 {object} services.ProvisionSSOMemberResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/auth/sso/provision [post]
+func (h *TenantSSOHandler) ProvisionSSOMember(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return
+	}
+
+	var req services.ProvisionSSOMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+	req.KeycloakID = userID
+
+	result, err := h.ssoService.ProvisionMember(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, services.ErrSSOProvisionMismatch) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return
+		}
+		h.respondError(c, err, "Failed to provision SSO member")
+		return
+	}
+	SuccessResponse(c, http.StatusOK, "SSO member provisioned", result)
+}
+
+// respondError maps SSO service errors to HTTP responses
+func (h *TenantSSOHandler) respondError(c *gin.Context, err error, fallback string) {
+	if validationErr, ok := services.IsValidationError(err); ok {
+		ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrSSOConfigNotFound):
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrSSOUnavailable):
+		ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	case errors.Is(err, services.ErrSSOProvisioningFailed):
+		ErrorResponse(c, http.StatusBadGateway, fallback, err)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, fallback, err)
+	}
+}
+
+// authorize resolves the tenant and user and checks the user may manage single sign-on
+func (h *TenantSSOHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if err := h.ssoService.AuthorizeManage(c.Request.Context(), tenantID, userID); err != nil {
+		if errors.Is(err, services.ErrSSOConfigForbidden) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return uuid.Nil, uuid.Nil, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to verify permissions", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+// TenantSubscriptionHandler exposes plans, tenant subscriptions and plan feature flags
+type TenantSubscriptionHandler struct {
+	subscriptionService *services.SubscriptionService
+}
+
+// NewTenantSubscriptionHandler creates a new tenant subscription handler
+func NewTenantSubscriptionHandler(subscriptionService *services.SubscriptionService) *TenantSubscriptionHandler {
+	return &TenantSubscriptionHandler{subscriptionService: subscriptionService}
+}
+
+// ListPlans returns the active plans
+// @Summary List plans
+// @Description Active subscription plans with prices, pricing tier and feature flags, cheapest first
+// @Tags plans
+// @Produce json
+// @Success 200 {array} models.TenantPlan
+// @Router /api/v1/plans [get]
+func (h *TenantSubscriptionHandler) ListPlans(c *gin.Context) {
+	plans, err := h.subscriptionService.ListPlans(c.Request.Context())
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to list plans", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Plans retrieved", plans)
+}
+
+// GetSubscription returns the tenant's plan, trial and feature flags
+// @Summary Get tenant subscription
+// @Description The tenant's effective plan, trial end and feature flags (owner or admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.TenantSubscriptionInfo
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/subscription [get]
+func (h *TenantSubscriptionHandler) GetSubscription(c *gin.Context) {
+	tenantID, userID, ok := h.parseTenantAndUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.subscriptionService.AuthorizeView(c.Request.Context(), tenantID, userID); err != nil {
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	info, err := h.subscriptionService.GetSubscription(c.Request.Context(), tenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get subscription", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Subscription retrieved", info)
+}
+
+// ChangePlan upgrades or downgrades the tenant to a self-service plan
+// @Summary Change tenant plan
+// @Description Upgrade or downgrade to a self-service plan (owner only). Downgrades are rejected while the tenant has more members than the plan allows.
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.ChangePlanRequest true "Plan"
+// @Success 200 {object} services.TenantSubscriptionInfo
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/subscription [put]
+func (h *TenantSubscriptionHandler) ChangePlan(c *gin.Context) {
+	tenantID, userID, ok := h.parseTenantAndUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.subscriptionService.AuthorizeManage(c.Request.Context(), tenantID, userID); err != nil {
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	var req services.ChangePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	info, err := h.subscriptionService.ChangePlan(c.Request.Context(), tenantID, req, &userID, true)
+	if err != nil {
+		h.changeError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Plan changed", info)
+}
+
+// AssignPlan assigns any active plan, optionally as a trial (internal, e.g. billing or sales tooling)
+// @Summary Assign tenant plan (internal)
+// @Description Assign any active plan; trial_days starts a trial that returns to the default plan when it ends
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body services.ChangePlanRequest true "Plan"
+// @Success 200 {object} services.TenantSubscriptionInfo
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /internal/tenants/{id}/subscription [put]
+func (h *TenantSubscriptionHandler) AssignPlan(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return
+	}
+
+	var req services.ChangePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	info, err := h.subscriptionService.ChangePlan(c.Request.Context(), tenantID, req, nil, false)
+	if err != nil {
+		h.changeError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Plan assigned", info)
+}
+
+// GetFeatures evaluates the tenant's feature flags for other services
+// @Summary Evaluate tenant features (internal)
+// @Description Effective feature flags (plan plus overrides); ?feature= narrows the result to one flag
+// @Tags internal
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param feature query string false "Feature key"
+// @Success 200 {object} services.FeatureEvaluation
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /internal/tenants/{id}/features [get]
+func (h *TenantSubscriptionHandler) GetFeatures(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return
+	}
+
+	feature := c.Query("feature")
+	if feature != "" && !models.IsKnownFeature(feature) {
+		ErrorResponse(c, http.StatusBadRequest, "Unknown feature", nil)
+		return
+	}
+
+	evaluation, err := h.subscriptionService.EvaluateFeatures(c.Request.Context(), tenantID)
+	if err != nil {
+		if err.Error() == "tenant not found" {
+			ErrorResponse(c, http.StatusNotFound, "Tenant not found", nil)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to evaluate features", err)
+		return
+	}
+
+	if feature != "" {
+		evaluation.Features = map[string]bool{feature: evaluation.Features[feature]}
+	}
+
+	SuccessResponse(c, http.StatusOK, "Features evaluated", evaluation)
+}
+
+// UpdateFeatureOverrides grants or revokes single features for a tenant (internal)
+// @Summary Override tenant features (internal)
+// @Description Grant (true) or revoke (false) features on top of the plan; null removes an override
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body services.UpdateFeatureOverridesRequest true "Overrides"
+// @Success 200 {object} services.TenantSubscriptionInfo
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /internal/tenants/{id}/features [put]
+func (h *TenantSubscriptionHandler) UpdateFeatureOverrides(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return
+	}
+
+	var req services.UpdateFeatureOverridesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	info, err := h.subscriptionService.UpdateFeatureOverrides(c.Request.Context(), tenantID, req)
+	if err != nil {
+		h.changeError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Feature overrides updated", info)
+}
+
+// parseTenantAndUser reads the tenant ID path parameter and the authenticated user
+func (h *TenantSubscriptionHandler) parseTenantAndUser(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
+
+// changeError maps plan change errors to status codes
+func (h *TenantSubscriptionHandler) changeError(c *gin.Context, err error) {
+	if validationErr, ok := services.IsValidationError(err); ok {
+		ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrPlanNotFound):
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrPlanNotSelfService):
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, services.ErrPlanUnchanged), errors.Is(err, services.ErrPlanMemberLimit):
+		ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case err.Error() == "tenant not found":
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	default:
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to change plan", err)
+	}
+}
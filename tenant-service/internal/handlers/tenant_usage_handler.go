@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/services"
+)
+
+// TenantUsageHandler exposes metered tenant usage against plan quotas
+type TenantUsageHandler struct {
+	usageService *services.UsageService
+}
+
+// NewTenantUsageHandler creates a new tenant usage handler
+func NewTenantUsageHandler(usageService *services.UsageService) *TenantUsageHandler {
+	return &TenantUsageHandler{usageService: usageService}
+}
+
+// GetTenantUsage returns the tenant's usage of members, API calls, storage and emails
+// @Summary Get tenant usage
+// @Description Current usage of each metered quota against the tenant's plan; limit -1 is unlimited (owner or admin only)
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} services.TenantUsage
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/usage [get]
+func (h *TenantUsageHandler) GetTenantUsage(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return
+	}
+
+	if err := h.usageService.AuthorizeView(c.Request.Context(), tenantID, userID); err != nil {
+		ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	usage, err := h.usageService.GetUsage(c.Request.Context(), tenantID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get tenant usage", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Tenant usage retrieved", usage)
+}
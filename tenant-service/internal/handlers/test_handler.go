@@ -39,6 +39,16 @@ func IsTestMode() bool {
 
 // VerifyEmailForTest directly marks an email as verified for testing purposes
 // POST /api/v1/test/verify-email
+// @Summary Verify email (test only)
+// @Description Marks a session's email as verified; only available in dev/test environments
+// @Tags test
+// @Accept json
+// @Produce json
+// @Param request body object true "{\"session_id\": \"...\", \"email\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/test/verify-email [post]
 func (h *TestHandler) VerifyEmailForTest(c *gin.Context) {
 	if !IsTestMode() {
 		ErrorResponse(c, http.StatusForbidden, "Test endpoints are only available in dev/test environments", nil)
@@ -76,6 +86,17 @@ func (h *TestHandler) VerifyEmailForTest(c *gin.Context) {
 
 // GetVerificationTokenForTest retrieves the verification token for a session
 // GET /api/v1/test/verification-token
+// @Summary Get verification token (test only)
+// @Description Returns the verification token sent for a session; only available in dev/test environments
+// @Tags test
+// @Produce json
+// @Param sessionId query string true "Onboarding session ID"
+// @Param email query string true "Email"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/test/verification-token [get]
 func (h *TestHandler) GetVerificationTokenForTest(c *gin.Context) {
 	if !IsTestMode() {
 		ErrorResponse(c, http.StatusForbidden, "Test endpoints are only available in dev/test environments", nil)
@@ -118,6 +139,15 @@ func (h *TestHandler) GetVerificationTokenForTest(c *gin.Context) {
 
 // GetOTPForTest retrieves the OTP code for a session (if OTP method is used)
 // GET /api/v1/test/otp
+// @Summary Get OTP (test only)
+// @Description Not implemented; verification uses links
+// @Tags test
+// @Produce json
+// @Param sessionId query string true "Onboarding session ID"
+// @Param email query string true "Email"
+// @Failure 403 {object} map[string]interface{}
+// @Failure 501 {object} map[string]interface{}
+// @Router /api/v1/test/otp [get]
 func (h *TestHandler) GetOTPForTest(c *gin.Context) {
 	if !IsTestMode() {
 		ErrorResponse(c, http.StatusForbidden, "Test endpoints are only available in dev/test environments", nil)
@@ -30,6 +30,16 @@ func (h *VerificationHandler) SetDraftService(draftService *services.DraftServic
 }
 
 // StartEmailVerification starts email verification process
+// @Summary Start email verification
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param request body object true "{\"email\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/verification/email [post]
 func (h *VerificationHandler) StartEmailVerification(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -65,6 +75,16 @@ func (h *VerificationHandler) StartEmailVerification(c *gin.Context) {
 }
 
 // StartPhoneVerification starts phone verification process
+// @Summary Start phone verification
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param request body object true "{\"phone\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/verification/phone [post]
 func (h *VerificationHandler) StartPhoneVerification(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -100,6 +120,15 @@ func (h *VerificationHandler) StartPhoneVerification(c *gin.Context) {
 }
 
 // VerifyCode verifies a verification code
+// @Summary Verify code
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param request body object true "{\"code\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/verification/verify [post]
 func (h *VerificationHandler) VerifyCode(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -161,6 +190,16 @@ func (h *VerificationHandler) VerifyCode(c *gin.Context) {
 }
 
 // ResendVerificationCode resends a verification code
+// @Summary Resend verification code
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param request body object true "{\"verification_type\": \"email\", \"target_value\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/verification/resend [post]
 func (h *VerificationHandler) ResendVerificationCode(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -198,6 +237,16 @@ func (h *VerificationHandler) ResendVerificationCode(c *gin.Context) {
 
 // ResendVerificationByEmail resends verification email using only email address
 // This is used when a verification link has expired and the user doesn't have the session ID
+// @Summary Resend verification by email
+// @Description Resends the verification link of the pending session of an email, for expired links; responds the same whether or not the email is registered
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param request body object true "{\"email\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/verify/resend-by-email [post]
 func (h *VerificationHandler) ResendVerificationByEmail(c *gin.Context) {
 	var req struct {
 		Email string `json:"email" binding:"required,email"`
@@ -243,6 +292,14 @@ func (h *VerificationHandler) ResendVerificationByEmail(c *gin.Context) {
 }
 
 // GetVerificationStatus gets verification status for a session
+// @Summary Get verification status
+// @Tags verification
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/verification/status [get]
 func (h *VerificationHandler) GetVerificationStatus(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -260,6 +317,16 @@ func (h *VerificationHandler) GetVerificationStatus(c *gin.Context) {
 }
 
 // CheckVerification checks if a specific verification type is verified
+// @Summary Check verification
+// @Description Reports whether a verification type (email or phone) is verified for the session
+// @Tags verification
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Param type path string true "Verification type"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/verification/{type}/check [get]
 func (h *VerificationHandler) CheckVerification(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -289,6 +356,15 @@ func (h *VerificationHandler) CheckVerification(c *gin.Context) {
 
 // GetDNSConfig returns DNS configuration for a session's custom domain
 // Used by the UI to display DNS setup instructions including ACME CNAME for SSL
+// @Summary Get custom domain DNS configuration
+// @Description Returns the DNS records, including the ACME CNAME for SSL, to set up the session's custom domain
+// @Tags verification
+// @Produce json
+// @Param sessionId path string true "Onboarding session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/onboarding/sessions/{sessionId}/verification/dns-config [get]
 func (h *VerificationHandler) GetDNSConfig(c *gin.Context) {
 	sessionID, err := uuid.Parse(c.Param("sessionId"))
 	if err != nil {
@@ -337,6 +413,12 @@ func (h *VerificationHandler) GetDNSConfig(c *gin.Context) {
 }
 
 // GetVerificationMethod returns the current verification method (otp or link)
+// @Summary Get verification method
+// @Description Returns whether email verification uses an OTP or a link
+// @Tags verification
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/verify/method [get]
 func (h *VerificationHandler) GetVerificationMethod(c *gin.Context) {
 	method := h.verificationService.GetVerificationMethod()
 
@@ -348,6 +430,15 @@ func (h *VerificationHandler) GetVerificationMethod(c *gin.Context) {
 }
 
 // VerifyByToken verifies email using a token from verification link
+// @Summary Verify email by token
+// @Description Verifies the email of an onboarding session with the token from the verification link
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param request body object true "{\"token\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/verify/token [post]
 func (h *VerificationHandler) VerifyByToken(c *gin.Context) {
 	var req struct {
 		Token string `json:"token" binding:"required"`
@@ -394,6 +485,14 @@ func (h *VerificationHandler) VerifyByToken(c *gin.Context) {
 }
 
 // GetTokenInfo retrieves information about a verification token (GET with query param)
+// @Summary Get verification token info
+// @Tags verification
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/verify/token-info [get]
 func (h *VerificationHandler) GetTokenInfo(c *gin.Context) {
 	token := c.Query("token")
 	if token == "" {
@@ -405,6 +504,16 @@ func (h *VerificationHandler) GetTokenInfo(c *gin.Context) {
 }
 
 // GetTokenInfoPost retrieves information about a verification token (POST with body)
+// @Summary Get verification token info
+// @Description Same as the GET variant with the token in the body so it stays out of access logs
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param request body object true "{\"token\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/verify/token-info [post]
 func (h *VerificationHandler) GetTokenInfoPost(c *gin.Context) {
 	var req struct {
 		Token string `json:"token" binding:"required"`
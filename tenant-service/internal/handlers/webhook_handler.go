@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"tenant-service/internal/listquery"
+	"tenant-service/internal/services"
+)
+
+// WebhookHandler exposes webhook delivery history, dead letters and replay to integrators
+type WebhookHandler struct {
+	deliveryService *services.WebhookDeliveryService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(deliveryService *services.WebhookDeliveryService) *WebhookHandler {
+	return &WebhookHandler{deliveryService: deliveryService}
+}
+
+// deadLetterListQuery declares the sorting and filtering accepted by ListDeadLetters
+var deadLetterListQuery = listquery.Config{
+	DefaultLimit: 50,
+	MaxLimit:     200,
+	Sorts: map[string]string{
+		"dead_lettered_at": "dead_lettered_at",
+		"created_at":       "created_at",
+		"attempts":         "attempts",
+	},
+	DefaultSort: "-dead_lettered_at",
+	Filters: map[string]listquery.Field{
+		"event_type":       {Column: "event_type", Ops: []string{listquery.OpEq, listquery.OpIn, listquery.OpLike}},
+		"webhook_url":      {Column: "webhook_url", Ops: []string{listquery.OpEq, listquery.OpLike}},
+		"response_status":  {Column: "response_status", Type: listquery.TypeInt},
+		"dead_lettered_at": {Column: "dead_lettered_at", Type: listquery.TypeTime},
+		"created_at":       {Column: "created_at", Type: listquery.TypeTime},
+	},
+}
+
+// ListEventAttempts returns the delivery attempt history of a webhook event
+// @Summary List webhook delivery attempts
+// @Description Every delivery attempt of the event (automatic and manual replays) with status code, response excerpt, error and duration (owner/admin only)
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param eventId path string true "Webhook event ID"
+// @Success 200 {array} models.WebhookDeliveryAttempt
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/webhooks/events/{eventId}/attempts [get]
+func (h *WebhookHandler) ListEventAttempts(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid webhook event ID format", err)
+		return
+	}
+
+	event, attempts, err := h.deliveryService.ListAttempts(c.Request.Context(), tenantID, eventID)
+	if err != nil {
+		h.respondError(c, "Failed to list webhook delivery attempts", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Webhook delivery attempts retrieved", gin.H{
+		"event":    event,
+		"attempts": attempts,
+	})
+}
+
+// ListDeadLetters returns webhook events that exhausted their retries
+// @Summary List dead-lettered webhook events
+// @Description Events whose deliveries all failed. Supports page/limit or cursor pagination, sort=-dead_lettered_at and filters such as event_type, webhook_url and response_status[gte]=500 (owner/admin only)
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param event_type query string false "Event type"
+// @Param webhook_url query string false "Endpoint URL"
+// @Param sort query string false "Sort key (dead_lettered_at, created_at, attempts), prefix with - for descending"
+// @Param cursor query string false "Cursor from a previous page (empty to start cursor pagination)"
+// @Success 200 {array} models.WebhookEvent
+// @Router /api/v1/tenants/{id}/webhooks/dead-letters [get]
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	q, err := listquery.Parse(c.Request.URL.Query(), deadLetterListQuery)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	events, total, next, err := h.deliveryService.ListDeadLetters(c.Request.Context(), tenantID, q)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to list dead-lettered webhook events", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Dead-lettered webhook events retrieved", gin.H{
+		"events":     events,
+		"pagination": q.Meta(total, next),
+	})
+}
+
+// ReplayEvent resends a single webhook event immediately
+// @Summary Replay webhook event
+// @Description Resend a dead-lettered or delivered event now and record the attempt (owner/admin only)
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param eventId path string true "Webhook event ID"
+// @Success 200 {object} services.WebhookReplayResult
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/webhooks/events/{eventId}/replay [post]
+func (h *WebhookHandler) ReplayEvent(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid webhook event ID format", err)
+		return
+	}
+
+	result, err := h.deliveryService.Replay(c.Request.Context(), tenantID, eventID)
+	if err != nil {
+		h.respondError(c, "Failed to replay webhook event", err)
+		return
+	}
+
+	message := "Webhook event replayed"
+	if !result.Success {
+		message = "Webhook event replay failed"
+	}
+	SuccessResponse(c, http.StatusOK, message, result)
+}
+
+// ReplayDeadLetters resends dead-lettered events in bulk
+// @Summary Bulk replay dead-lettered webhook events
+// @Description Resend up to 100 dead-lettered events selected by event_ids or by event_type/webhook_url; returns a per-event result (owner/admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param request body services.WebhookBulkReplayRequest true "Events to replay"
+// @Success 200 {object} services.WebhookBulkReplayResult
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/tenants/{id}/webhooks/dead-letters/replay [post]
+func (h *WebhookHandler) ReplayDeadLetters(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req services.WebhookBulkReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	result, err := h.deliveryService.BulkReplay(c.Request.Context(), tenantID, req)
+	if err != nil {
+		h.respondError(c, "Failed to replay dead-lettered webhook events", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Dead-lettered webhook events replayed", result)
+}
+
+// GetEndpointStats returns delivery failure statistics per endpoint
+// @Summary Webhook endpoint statistics
+// @Description Attempts, failure rate, average latency, dead letters and last error per endpoint URL (owner/admin only)
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tenant ID"
+// @Param since query string false "RFC3339 start of the window (default: 7 days ago)"
+// @Success 200 {array} services.WebhookEndpointStats
+// @Router /api/v1/tenants/{id}/webhooks/stats [get]
+func (h *WebhookHandler) GetEndpointStats(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var since time.Time
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid since parameter, expected RFC3339", err)
+			return
+		}
+		since = parsed
+	}
+
+	stats, err := h.deliveryService.EndpointStats(c.Request.Context(), tenantID, since)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get webhook endpoint statistics", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Webhook endpoint statistics retrieved", stats)
+}
+
+// authorize resolves the tenant and checks the user may manage its webhooks
+func (h *WebhookHandler) authorize(c *gin.Context) (uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return uuid.Nil, false
+	}
+
+	userIDStr := getUserID(c)
+	if userIDStr == "" {
+		ErrorResponse(c, http.StatusUnauthorized, "User ID is required", nil)
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid user ID format", err)
+		return uuid.Nil, false
+	}
+
+	if err := h.deliveryService.AuthorizeWebhookAccess(c.Request.Context(), tenantID, userID); err != nil {
+		if errors.Is(err, services.ErrWebhookAccessForbidden) {
+			ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return uuid.Nil, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to verify permissions", err)
+		return uuid.Nil, false
+	}
+
+	return tenantID, true
+}
+
+// respondError maps webhook delivery service errors to HTTP responses
+func (h *WebhookHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrWebhookEventNotFound):
+		ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrWebhookEventNotReplayable):
+		ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	default:
+		if validationErr, ok := services.IsValidationError(err); ok {
+			ErrorResponse(c, http.StatusBadRequest, validationErr.Message, err)
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, message, err)
+	}
+}
@@ -0,0 +1,227 @@
+package listquery
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Page is the pagination block returned alongside list results
+type Page struct {
+	Mode       string `json:"mode"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Total      int64  `json:"total"`
+	TotalPages int64  `json:"total_pages,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// cursor is the opaque position token of cursor pagination
+type cursor struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v,omitempty"`
+	Key   interface{} `json:"k"`
+}
+
+// schemaCache caches parsed model schemas used to read cursor values
+var schemaCache = &sync.Map{}
+
+// FilterScope applies the parsed filters; use it for both the count and the page query
+func (q *Query) FilterScope() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, f := range q.Filters {
+			switch f.Op {
+			case OpEq:
+				db = db.Where(fmt.Sprintf("%s = ?", f.Column), f.Value)
+			case OpNe:
+				db = db.Where(fmt.Sprintf("%s <> ?", f.Column), f.Value)
+			case OpIn:
+				db = db.Where(fmt.Sprintf("%s IN ?", f.Column), f.Value)
+			case OpNotIn:
+				db = db.Where(fmt.Sprintf("%s NOT IN ?", f.Column), f.Value)
+			case OpGt:
+				db = db.Where(fmt.Sprintf("%s > ?", f.Column), f.Value)
+			case OpGte:
+				db = db.Where(fmt.Sprintf("%s >= ?", f.Column), f.Value)
+			case OpLt:
+				db = db.Where(fmt.Sprintf("%s < ?", f.Column), f.Value)
+			case OpLte:
+				db = db.Where(fmt.Sprintf("%s <= ?", f.Column), f.Value)
+			case OpLike:
+				db = db.Where(fmt.Sprintf("%s ILIKE ?", f.Column), "%"+escapeLike(f.Value.(string))+"%")
+			case OpIsNull:
+				if f.Value.(bool) {
+					db = db.Where(fmt.Sprintf("%s IS NULL", f.Column))
+				} else {
+					db = db.Where(fmt.Sprintf("%s IS NOT NULL", f.Column))
+				}
+			}
+		}
+		return db
+	}
+}
+
+// PageScope applies sorting and the page window
+// In cursor mode one extra row is fetched; pass the results through NextPage.
+func (q *Query) PageScope() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if q.Mode == ModeCursor {
+			return q.cursorScope(db)
+		}
+		for _, s := range q.Sorts {
+			db = db.Order(orderBy(s.Column, s.Desc))
+		}
+		return db.Order(orderBy(q.tieBreaker, false)).Offset(q.Offset).Limit(q.Limit)
+	}
+}
+
+func (q *Query) cursorScope(db *gorm.DB) *gorm.DB {
+	column, desc := q.cursorSort()
+	cmp := ">"
+	if desc {
+		cmp = "<"
+	}
+
+	if q.cursor != nil {
+		if column == q.tieBreaker {
+			db = db.Where(fmt.Sprintf("%s %s ?", column, cmp), q.cursor.Key)
+		} else {
+			db = db.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", column, cmp, column, q.tieBreaker, cmp),
+				q.cursor.Value, q.cursor.Value, q.cursor.Key)
+		}
+	}
+
+	if column != q.tieBreaker {
+		db = db.Order(orderBy(column, desc))
+	}
+	return db.Order(orderBy(q.tieBreaker, desc)).Limit(q.Limit + 1)
+}
+
+// NextPage trims the extra row fetched in cursor mode and returns the cursor of the next page
+// The sort and tie-breaker columns must be non-null fields of T.
+func NextPage[T any](q *Query, items []T) ([]T, string, error) {
+	if q.Mode != ModeCursor || len(items) <= q.Limit {
+		return items, "", nil
+	}
+	items = items[:q.Limit]
+
+	last := items[len(items)-1]
+	s, err := schema.Parse(&last, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read cursor fields: %w", err)
+	}
+
+	column, _ := q.cursorSort()
+	next := cursor{Sort: q.sortSignature()}
+	if next.Key, err = fieldValue(s, reflect.ValueOf(last), q.tieBreaker); err != nil {
+		return nil, "", err
+	}
+	if column != q.tieBreaker {
+		if next.Value, err = fieldValue(s, reflect.ValueOf(last), column); err != nil {
+			return nil, "", err
+		}
+	}
+
+	token, err := json.Marshal(next)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return items, base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// Meta builds the pagination block for a response
+func (q *Query) Meta(total int64, nextCursor string) Page {
+	page := Page{
+		Mode:       q.Mode,
+		PageSize:   q.Limit,
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+		Total:      total,
+		NextCursor: nextCursor,
+	}
+	if q.Mode == ModeCursor {
+		page.HasMore = nextCursor != ""
+		return page
+	}
+	page.Page = q.Page
+	page.TotalPages = (total + int64(q.Limit) - 1) / int64(q.Limit)
+	page.HasMore = int64(q.Offset+q.Limit) < total
+	return page
+}
+
+// cursorSort returns the column and direction cursor pagination orders by
+func (q *Query) cursorSort() (string, bool) {
+	if len(q.Sorts) == 0 {
+		return q.tieBreaker, false
+	}
+	return q.Sorts[0].Column, q.Sorts[0].Desc
+}
+
+// sortSignature identifies the sort order a cursor was issued for
+func (q *Query) sortSignature() string {
+	if len(q.Sorts) == 0 {
+		return ""
+	}
+	if q.Sorts[0].Desc {
+		return "-" + q.Sorts[0].Key
+	}
+	return q.Sorts[0].Key
+}
+
+func decodeCursor(token string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	if c.Key == nil {
+		return nil, fmt.Errorf("cursor has no key")
+	}
+	return &c, nil
+}
+
+func fieldValue(s *schema.Schema, value reflect.Value, column string) (interface{}, error) {
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		column = column[i+1:]
+	}
+	field := s.LookUpField(column)
+	if field == nil {
+		return nil, fmt.Errorf("cursor column %q is not a field of %s", column, s.Name)
+	}
+	v, _ := field.ValueOf(context.Background(), value)
+	return v, nil
+}
+
+func orderBy(column string, desc bool) string {
+	if desc {
+		return column + " DESC"
+	}
+	return column + " ASC"
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
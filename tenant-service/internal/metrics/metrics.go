@@ -0,0 +1,212 @@
+// Package metrics holds the tenant-service business metrics. Observations carry the
+// request's trace ID as an exemplar, so a latency spike on a dashboard links to its trace.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	namespace = "tesseract"
+	subsystem = "tenant"
+)
+
+// Onboarding session outcomes
+const (
+	SessionStarted       = "started"
+	SessionCompleted     = "completed"
+	SessionTenantCreated = "tenant_created"
+	SessionFailed        = "failed"
+)
+
+// Onboarding steps timed by the step duration histogram
+const (
+	StepApplicationConfiguration = "application_configuration"
+	StepBusinessInformation      = "business_information"
+	StepContactInformation       = "contact_information"
+	StepBusinessAddress          = "business_address"
+	StepEmailVerification        = "email_verification"
+	StepAccountSetup             = "account_setup"
+)
+
+// Verification attempt results
+const (
+	VerificationSuccess = "success"
+	VerificationFailed  = "failed"  // Wrong code or token email mismatch
+	VerificationExpired = "expired" // Token not found or expired
+	VerificationError   = "error"   // verification-service or Redis unavailable
+)
+
+// Call results recorded on duration histograms
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var (
+	onboardingSessions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "onboarding_sessions_total",
+		Help:      "Total number of onboarding sessions by outcome",
+	}, []string{"application_type", "status"})
+
+	verificationAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "verification_attempts_total",
+		Help:      "Total number of verification attempts",
+	}, []string{"type", "status"})
+
+	verificationCodesGenerated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "verification_codes_generated_total",
+		Help:      "Total number of verification codes and links sent",
+	}, []string{"type"})
+
+	onboardingStepDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "onboarding_step_duration_seconds",
+		Help:      "Time taken to process each onboarding step",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"step", "result"})
+
+	keycloakRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "keycloak_request_duration_seconds",
+		Help:      "Latency of Keycloak admin and token calls",
+		Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"operation", "result"})
+)
+
+// RecordOnboardingSession records an onboarding session reaching the given status
+func RecordOnboardingSession(ctx context.Context, applicationType, status string) {
+	if applicationType == "" {
+		applicationType = "unknown"
+	}
+	addWithExemplar(ctx, onboardingSessions.WithLabelValues(applicationType, status))
+}
+
+// RecordVerificationAttempt records an email or phone verification attempt and its result
+func RecordVerificationAttempt(ctx context.Context, verificationType, status string) {
+	addWithExemplar(ctx, verificationAttempts.WithLabelValues(verificationType, status))
+}
+
+// RecordVerificationCodeGenerated records a verification code or link sent
+func RecordVerificationCodeGenerated(ctx context.Context, verificationType string) {
+	addWithExemplar(ctx, verificationCodesGenerated.WithLabelValues(verificationType))
+}
+
+// ObserveOnboardingStep records how long an onboarding step took. It is meant to be
+// deferred with the function's named error: defer ObserveOnboardingStep(ctx, step, time.Now(), &err)
+func ObserveOnboardingStep(ctx context.Context, step string, start time.Time, err *error) {
+	result := ResultSuccess
+	if err != nil && *err != nil {
+		result = ResultError
+	}
+	observeWithExemplar(ctx, onboardingStepDuration.WithLabelValues(step, result), time.Since(start))
+}
+
+// ObserveKeycloakCall records the latency and outcome of a Keycloak call started at start
+func ObserveKeycloakCall(ctx context.Context, operation string, start time.Time, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultError
+	}
+	observeWithExemplar(ctx, keycloakRequestDuration.WithLabelValues(operation, result), time.Since(start))
+}
+
+type traceIDKey struct{}
+
+// WithTraceID returns a context carrying the request's trace ID
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	if traceID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID stored by WithTraceID, or ""
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// TraceIDFromHeaders extracts the trace ID propagated by the mesh: W3C traceparent first,
+// then Zipkin B3 (single header or X-B3-TraceId)
+func TraceIDFromHeaders(header http.Header) string {
+	if traceparent := header.Get("traceparent"); traceparent != "" {
+		// version-traceid-parentid-flags
+		parts := strings.Split(traceparent, "-")
+		if len(parts) == 4 && isTraceID(parts[1]) {
+			return parts[1]
+		}
+	}
+	if b3 := header.Get("b3"); b3 != "" {
+		if traceID, _, _ := strings.Cut(b3, "-"); isTraceID(traceID) {
+			return traceID
+		}
+	}
+	if traceID := header.Get("X-B3-TraceId"); isTraceID(traceID) {
+		return traceID
+	}
+	return ""
+}
+
+// isTraceID reports whether s is a 64 or 128 bit hex trace ID that is not all zeros
+func isTraceID(s string) bool {
+	if len(s) != 16 && len(s) != 32 {
+		return false
+	}
+	nonZero := false
+	for _, r := range s {
+		switch {
+		case r == '0':
+		case (r >= '1' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F'):
+			nonZero = true
+		default:
+			return false
+		}
+	}
+	return nonZero
+}
+
+// exemplarLabels returns the exemplar of an observation made for ctx, or nil without a trace
+func exemplarLabels(ctx context.Context) prometheus.Labels {
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		return prometheus.Labels{"trace_id": traceID}
+	}
+	return nil
+}
+
+func addWithExemplar(ctx context.Context, counter prometheus.Counter) {
+	if labels := exemplarLabels(ctx); labels != nil {
+		if adder, ok := counter.(prometheus.ExemplarAdder); ok {
+			adder.AddWithExemplar(1, labels)
+			return
+		}
+	}
+	counter.Inc()
+}
+
+func observeWithExemplar(ctx context.Context, observer prometheus.Observer, duration time.Duration) {
+	if labels := exemplarLabels(ctx); labels != nil {
+		if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+			exemplarObserver.ObserveWithExemplar(duration.Seconds(), labels)
+			return
+		}
+	}
+	observer.Observe(duration.Seconds())
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"tenant-service/internal/metrics"
 )
 
 // RequestID middleware generates or extracts correlation IDs for request tracing
@@ -27,6 +28,19 @@ func RequestID() gin.HandlerFunc {
 	}
 }
 
+// TraceContext stores the mesh-propagated trace ID in the request context so business
+// metrics recorded while handling the request link to its trace
+func TraceContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if traceID := metrics.TraceIDFromHeaders(c.Request.Header); traceID != "" {
+			c.Set("trace_id", traceID)
+			c.Request = c.Request.WithContext(metrics.WithTraceID(c.Request.Context(), traceID))
+		}
+
+		c.Next()
+	}
+}
+
 // StructuredLogger middleware logs requests with structured fields
 func StructuredLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Custom onboarding field types
+const (
+	CustomFieldTypeText        = "text"
+	CustomFieldTypeNumber      = "number"
+	CustomFieldTypeBoolean     = "boolean"
+	CustomFieldTypeDate        = "date"
+	CustomFieldTypeEmail       = "email"
+	CustomFieldTypeURL         = "url"
+	CustomFieldTypeSelect      = "select"
+	CustomFieldTypeMultiSelect = "multiselect"
+)
+
+// CustomFieldStepBusinessInformation binds a custom field to the business-information step
+// Fields without a step are bound to this step.
+const CustomFieldStepBusinessInformation = "business_information"
+
+// CustomFieldDefinition describes an extra onboarding question defined by a template
+// Values are collected on the bound step and stored in that step's custom_fields JSONB.
+type CustomFieldDefinition struct {
+	Key         string   `json:"key"`                   // Storage key, e.g. "liquor_license_number"
+	Label       string   `json:"label"`                 // Question shown to the merchant
+	Type        string   `json:"type"`                  // text, number, boolean, date, email, url, select, multiselect
+	Required    bool     `json:"required"`              // Must be answered to save the step
+	Step        string   `json:"step,omitempty"`        // Onboarding step collecting the field (default: business_information)
+	HelpText    string   `json:"help_text,omitempty"`   // Hint shown below the input
+	Placeholder string   `json:"placeholder,omitempty"` // Input placeholder
+	Options     []string `json:"options,omitempty"`     // Allowed values for select/multiselect
+	Pattern     string   `json:"pattern,omitempty"`     // Regular expression text values must match
+	MinLength   *int     `json:"min_length,omitempty"`  // Minimum text length
+	MaxLength   *int     `json:"max_length,omitempty"`  // Maximum text length
+	Min         *float64 `json:"min,omitempty"`         // Minimum number value
+	Max         *float64 `json:"max,omitempty"`         // Maximum number value
+	Order       int      `json:"order,omitempty"`       // Display order within the step
+}
+
+// BoundStep returns the onboarding step the field is collected on
+func (d CustomFieldDefinition) BoundStep() string {
+	if d.Step == "" {
+		return CustomFieldStepBusinessInformation
+	}
+	return d.Step
+}
+
+// CustomFieldDefinitions parses the template's custom field definitions
+func (t *OnboardingTemplate) CustomFieldDefinitions() ([]CustomFieldDefinition, error) {
+	if len(t.CustomFields) == 0 || string(t.CustomFields) == "null" {
+		return nil, nil
+	}
+	var defs []CustomFieldDefinition
+	if err := json.Unmarshal(t.CustomFields, &defs); err != nil {
+		return nil, fmt.Errorf("invalid custom field definitions: %w", err)
+	}
+	return defs, nil
+}
+
+// CustomFieldsForStep returns the template's custom fields bound to the given step
+func (t *OnboardingTemplate) CustomFieldsForStep(step string) ([]CustomFieldDefinition, error) {
+	defs, err := t.CustomFieldDefinitions()
+	if err != nil {
+		return nil, err
+	}
+	var bound []CustomFieldDefinition
+	for _, def := range defs {
+		if def.BoundStep() == step {
+			bound = append(bound, def)
+		}
+	}
+	return bound, nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultEmailChangeTokenExpiry is how long the link sent to the new address stays valid
+const DefaultEmailChangeTokenExpiry = 24 * time.Hour
+
+// Auth events of the email-change flow, logged in TenantAuthAuditLog
+const (
+	AuthEventEmailChangeRequested = "email_change_requested"
+	AuthEventEmailChanged         = "email_changed"
+)
+
+// EmailChangeToken is a pending change of a user's login email. The current address keeps
+// working until the link sent to NewEmail is followed; only the token's hash is stored.
+type EmailChangeToken struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Token    string    `json:"-" gorm:"type:varchar(255);not null;uniqueIndex"`
+	UserID   uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	TenantID uuid.UUID `json:"tenant_id" gorm:"type:uuid;not null;index"` // Tenant the change was requested from
+	OldEmail string    `json:"old_email" gorm:"size:255;not null"`
+	NewEmail string    `json:"new_email" gorm:"size:255;not null;index"`
+
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null;index"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+
+	RequestedIP    string `json:"requested_ip" gorm:"size:45"`
+	RequestedAgent string `json:"requested_agent"`
+	ConfirmedIP    string `json:"confirmed_ip" gorm:"size:45"`
+	ConfirmedAgent string `json:"confirmed_agent"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for EmailChangeToken
+func (EmailChangeToken) TableName() string {
+	return "email_change_tokens"
+}
+
+func (e *EmailChangeToken) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.ExpiresAt.IsZero() {
+		e.ExpiresAt = time.Now().Add(DefaultEmailChangeTokenExpiry)
+	}
+	return nil
+}
+
+// IsPending reports whether the change can still be confirmed at now
+func (e *EmailChangeToken) IsPending(now time.Time) bool {
+	return e.ConfirmedAt == nil && e.CancelledAt == nil && now.Before(e.ExpiresAt)
+}
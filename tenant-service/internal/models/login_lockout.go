@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// LockoutTierCount is the number of progressive lockout tiers; the last tier is the maximum
+const LockoutTierCount = 4
+
+// LockoutSettings are the effective brute-force lockout thresholds for a tenant
+type LockoutSettings struct {
+	MaxAttempts int                   `json:"max_attempts"` // Failures per tier before a lockout
+	Progressive bool                  `json:"progressive"`  // False = every lockout uses tier 1
+	TierMinutes [LockoutTierCount]int `json:"tier_minutes"` // Lockout length per tier
+	ResetHours  int                   `json:"reset_hours"`  // Failures are forgotten after this long without one
+}
+
+// DefaultLockoutSettings apply to tenants without an auth policy
+func DefaultLockoutSettings() LockoutSettings {
+	return LockoutSettings{
+		MaxAttempts: 5,
+		Progressive: true,
+		TierMinutes: [LockoutTierCount]int{10, 60, 360, 1440},
+		ResetHours:  48,
+	}
+}
+
+// LockoutSettings returns the policy's lockout thresholds, falling back to the defaults for
+// unset values. Safe to call on a nil policy.
+func (p *TenantAuthPolicy) LockoutSettings() LockoutSettings {
+	settings := DefaultLockoutSettings()
+	if p == nil {
+		return settings
+	}
+	if p.MaxLoginAttempts > 0 {
+		settings.MaxAttempts = p.MaxLoginAttempts
+	}
+	settings.Progressive = p.EnableProgressiveLockout
+	for i, minutes := range []int{p.Tier1LockoutMinutes, p.Tier2LockoutMinutes, p.Tier3LockoutMinutes, p.Tier4LockoutMinutes} {
+		if minutes > 0 {
+			settings.TierMinutes[i] = minutes
+		}
+	}
+	if p.LockoutResetHours > 0 {
+		settings.ResetHours = p.LockoutResetHours
+	}
+	return settings
+}
+
+// LockoutFor returns the tier and lockout length after totalFailed failures.
+// Tier n starts at n*MaxAttempts failures and the last tier applies beyond it.
+func (s LockoutSettings) LockoutFor(totalFailed int) (int, time.Duration) {
+	tier := 1
+	if s.Progressive && s.MaxAttempts > 0 {
+		tier = totalFailed / s.MaxAttempts
+		if tier < 1 {
+			tier = 1
+		}
+		if tier > LockoutTierCount {
+			tier = LockoutTierCount
+		}
+	}
+	return tier, time.Duration(s.TierMinutes[tier-1]) * time.Minute
+}
+
+// ResetAfter is how long failures are remembered
+func (s LockoutSettings) ResetAfter() time.Duration {
+	return time.Duration(s.ResetHours) * time.Hour
+}
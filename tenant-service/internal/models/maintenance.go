@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Maintenance flag scopes
+const (
+	// MaintenanceScopePlatform puts every tenant in read-only mode
+	MaintenanceScopePlatform = "platform"
+	// MaintenanceScopeTenant puts a single tenant in read-only mode
+	MaintenanceScopeTenant = "tenant"
+)
+
+// MaintenanceFlag puts the platform or one tenant in read-only mode while data migrations run.
+// Write endpoints reject requests with 503 until the flag is removed or ExpiresAt passes.
+type MaintenanceFlag struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ScopeKey  string     `json:"-" gorm:"size:64;not null;uniqueIndex"` // "platform" or "tenant:<id>"
+	Scope     string     `json:"scope" gorm:"size:20;not null"`
+	TenantID  *uuid.UUID `json:"tenant_id,omitempty" gorm:"type:uuid;index"`
+	Reason    string     `json:"reason" gorm:"size:255"`
+	Message   string     `json:"message" gorm:"type:text"` // Shown to API clients
+	EnabledBy string     `json:"enabled_by" gorm:"size:255"`
+	StartedAt time.Time  `json:"started_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Flag lifts automatically after this time
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for MaintenanceFlag
+func (MaintenanceFlag) TableName() string {
+	return "maintenance_flags"
+}
+
+func (m *MaintenanceFlag) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActive reports whether the flag still blocks writes at the given time
+func (m *MaintenanceFlag) IsActive(now time.Time) bool {
+	return m.ExpiresAt == nil || now.Before(*m.ExpiresAt)
+}
+
+// MaintenanceScopeKey returns the unique key of a platform (nil tenant) or tenant flag
+func MaintenanceScopeKey(tenantID *uuid.UUID) string {
+	if tenantID == nil {
+		return MaintenanceScopePlatform
+	}
+	return MaintenanceScopeTenant + ":" + tenantID.String()
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Staff membership sync conflict kinds
+const (
+	// SyncConflictRoleMismatch means the staff role maps to a different membership role
+	SyncConflictRoleMismatch = "role_mismatch"
+	// SyncConflictMembershipInactive means active staff has a deactivated membership
+	SyncConflictMembershipInactive = "membership_inactive"
+	// SyncConflictStaffInactive means inactive staff still has an active membership
+	SyncConflictStaffInactive = "staff_inactive"
+	// SyncConflictUnlinkedStaff means active staff has no Keycloak user to build a membership for
+	SyncConflictUnlinkedStaff = "unlinked_staff"
+	// SyncConflictCreateFailed means the missing membership could not be created
+	SyncConflictCreateFailed = "create_failed"
+)
+
+// MembershipSyncConflict records a disagreement between staff-service and tenant memberships
+// that the reconciliation job will not fix automatically. One row exists per
+// (tenant, staff, kind); it is resolved once a later run no longer detects it.
+type MembershipSyncConflict struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID       uuid.UUID  `json:"tenant_id" gorm:"type:uuid;not null;uniqueIndex:idx_membership_sync_conflict"`
+	StaffID        uuid.UUID  `json:"staff_id" gorm:"type:uuid;not null;uniqueIndex:idx_membership_sync_conflict"`
+	Kind           string     `json:"kind" gorm:"size:50;not null;uniqueIndex:idx_membership_sync_conflict"`
+	UserID         *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid"`
+	Email          string     `json:"email" gorm:"size:255"`
+	StaffRole      string     `json:"staff_role" gorm:"size:50"`
+	MembershipRole string     `json:"membership_role" gorm:"size:50"`
+	Details        string     `json:"details,omitempty" gorm:"type:text"`
+	Occurrences    int        `json:"occurrences" gorm:"default:1"`
+	FirstSeenAt    time.Time  `json:"first_seen_at"`
+	LastSeenAt     time.Time  `json:"last_seen_at"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty" gorm:"index"`
+}
+
+// TableName specifies the table name for MembershipSyncConflict
+func (MembershipSyncConflict) TableName() string {
+	return "membership_sync_conflicts"
+}
+
+func (c *MembershipSyncConflict) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
@@ -85,11 +85,12 @@ type OnboardingTemplate struct {
 	Name            string    `json:"name" gorm:"not null" validate:"required,min=2,max=255"`
 	Description     string    `json:"description"`
 	ApplicationType string    `json:"application_type" gorm:"not null" validate:"required,oneof=ecommerce saas marketplace b2b"`
-	Version         int       `json:"version" gorm:"default:1"`
+	Version         int       `json:"version" gorm:"default:1"` // Currently published version; content fields mirror it
 	IsActive        bool      `json:"is_active" gorm:"default:true"`
 	IsDefault       bool      `json:"is_default" gorm:"default:false"`
 	TemplateConfig  JSONB     `json:"template_config" gorm:"type:jsonb;default:'{}'"`
 	Steps           JSONB     `json:"steps" gorm:"type:jsonb;default:'[]'"`
+	CustomFields    JSONB     `json:"custom_fields" gorm:"type:jsonb;default:'[]'"` // []CustomFieldDefinition
 	Metadata        JSONB     `json:"metadata" gorm:"type:jsonb;default:'{}'"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
@@ -100,6 +101,7 @@ type OnboardingSession struct {
 	ID                 uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	TenantID           *uuid.UUID `json:"tenant_id" gorm:"type:uuid;index"`
 	TemplateID         uuid.UUID  `json:"template_id" gorm:"type:uuid;not null"`
+	TemplateVersion    int        `json:"template_version" gorm:"default:0"` // Template version pinned at start (0 = started before versioning)
 	ApplicationType    string     `json:"application_type" gorm:"not null;index" validate:"required"`
 	Status             string     `json:"status" gorm:"default:'started';index" validate:"oneof=started in_progress completed failed abandoned draft"`
 	CurrentStep        string     `json:"current_step" gorm:"index"`
@@ -166,8 +168,11 @@ type BusinessInformation struct {
 	ExistingStorePlatforms JSONB `json:"existing_store_platforms" gorm:"type:jsonb;default:'[]'"`
 	HasExistingStore       bool  `json:"has_existing_store" gorm:"default:false"`
 	MigrationInterest      bool  `json:"migration_interest" gorm:"default:false"`
-	CreatedAt              time.Time `json:"created_at"`
-	UpdatedAt              time.Time `json:"updated_at"`
+	// Answers to the template's custom fields bound to this step, keyed by field key
+	// Values are normalized to their field type (numbers, booleans, YYYY-MM-DD dates, string lists)
+	CustomFields JSONB     `json:"custom_fields" gorm:"type:jsonb;default:'{}'"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // ContactInformation represents contact details
@@ -318,9 +323,11 @@ type OnboardingNotification struct {
 }
 
 // WebhookEvent represents webhook events for integration
+// Events that exhaust MaxAttempts move to failed and are dead-lettered until replayed
 type WebhookEvent struct {
 	ID                  uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	OnboardingSessionID uuid.UUID  `json:"onboarding_session_id" gorm:"type:uuid;not null"`
+	TenantID            *uuid.UUID `json:"tenant_id" gorm:"type:uuid;index"`
 	EventType           string     `json:"event_type" gorm:"not null" validate:"required"`
 	Payload             JSONB      `json:"payload" gorm:"type:jsonb;not null;default:'{}'"`
 	WebhookURL          string     `json:"webhook_url"`
@@ -330,6 +337,10 @@ type WebhookEvent struct {
 	NextRetryAt         *time.Time `json:"next_retry_at" gorm:"index"`
 	ResponseStatus      int        `json:"response_status"`
 	ResponseBody        string     `json:"response_body"`
+	LastError           string     `json:"last_error,omitempty"`
+	DeliveredAt         *time.Time `json:"delivered_at,omitempty"`
+	DeadLetteredAt      *time.Time `json:"dead_lettered_at,omitempty" gorm:"index"`
+	ReplayCount         int        `json:"replay_count" gorm:"default:0"`
 	CreatedAt           time.Time  `json:"created_at"`
 	UpdatedAt           time.Time  `json:"updated_at"`
 }
@@ -388,7 +399,7 @@ type Tenant struct {
 	FaviconURL   string    `json:"favicon_url"`
 	BusinessType string    `json:"business_type"`
 	Industry     string    `json:"industry"`
-	Status       string    `json:"status" gorm:"default:'creating';index" validate:"oneof=creating active inactive suspended"`
+	Status       string    `json:"status" gorm:"default:'creating';index" validate:"oneof=creating active inactive suspended pending_deletion"`
 	Mode         string    `json:"mode" gorm:"default:'development'" validate:"oneof=development production"`
 
 	// Tenant URLs - stored for both custom domain and default tesserix.app domains
@@ -401,6 +412,9 @@ type Tenant struct {
 	UseCustomDomain bool   `json:"use_custom_domain" gorm:"default:false"` // Whether custom domain is active
 	CustomDomain    string `json:"custom_domain" gorm:"size:255"`          // Base custom domain (e.g., yahvismartfarm.com)
 
+	// Last slug rename; the old slug keeps redirecting for a while (see TenantSlugRedirect)
+	SlugChangedAt *time.Time `json:"slug_changed_at,omitempty"`
+
 	// Business model: ONLINE_STORE (single vendor, D2C) or MARKETPLACE (multi-vendor)
 	BusinessModel string `json:"business_model" gorm:"type:varchar(50);default:'ONLINE_STORE';index" validate:"omitempty,oneof=ONLINE_STORE MARKETPLACE"`
 
@@ -420,6 +434,16 @@ type Tenant struct {
 	TrialEndsAt          *time.Time `json:"trial_ends_at"`
 	BillingEmail         string     `json:"billing_email" gorm:"size:255"`
 
+	// Billing provider state cached from its webhooks. Past-due tenants keep their plan until
+	// PaymentFailedAt plus the grace period, then are suspended until a payment succeeds.
+	BillingStatus          string     `json:"billing_status,omitempty" gorm:"size:20;index"`
+	BillingCustomerID      string     `json:"-" gorm:"size:255"`
+	BillingSubscriptionID  string     `json:"-" gorm:"size:255"`
+	BillingPeriodEndsAt    *time.Time `json:"billing_period_ends_at,omitempty"`
+	PaymentFailedAt        *time.Time `json:"payment_failed_at,omitempty"`
+	BillingUpdatedAt       *time.Time `json:"billing_updated_at,omitempty"`
+	StatusBeforeSuspension string     `json:"-" gorm:"size:50"` // Status restored when billing reinstates the tenant
+
 	// Owner tracking (user_id from auth-service)
 	OwnerUserID *uuid.UUID `json:"owner_user_id" gorm:"type:uuid;index"`
 
@@ -436,6 +460,14 @@ type Tenant struct {
 	GrowthBookEnabled       bool       `json:"growthbook_enabled" gorm:"default:false"`
 	GrowthBookProvisionedAt *time.Time `json:"growthbook_provisioned_at,omitempty"`
 
+	// Grace-period deletion: while Status is pending_deletion the owner can restore the tenant
+	// until DeletionScheduledFor, after which the background job purges it
+	DeletionRequestedAt  *time.Time `json:"deletion_requested_at,omitempty"`
+	DeletionRequestedBy  *uuid.UUID `json:"deletion_requested_by,omitempty" gorm:"type:uuid"`
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for,omitempty" gorm:"index"`
+	DeletionReason       string     `json:"deletion_reason,omitempty" gorm:"type:text"`
+	StatusBeforeDeletion string     `json:"-" gorm:"size:50"` // Status restored if the deletion is cancelled
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
@@ -443,6 +475,9 @@ type Tenant struct {
 	Memberships []UserTenantMembership `json:"memberships,omitempty" gorm:"foreignKey:TenantID"`
 }
 
+// TenantStatusPendingDeletion marks a tenant that has been deleted but can still be restored
+const TenantStatusPendingDeletion = "pending_deletion"
+
 // User represents a global user account (can belong to multiple tenants via memberships)
 // This follows the GitHub organization model where one email can be part of multiple orgs/tenants
 // The user-tenant relationship is managed through UserTenantMembership
@@ -480,10 +515,11 @@ type UserTenantMembership struct {
 	// manager: Can manage products, orders (not settings)
 	// member: Read-only access with limited actions
 	// viewer: Read-only access
-	Role string `json:"role" gorm:"size:50;not null;default:'member'" validate:"oneof=owner admin manager member viewer"`
+	// or the name of a TenantRole the tenant defined
+	Role string `json:"role" gorm:"size:50;not null;default:'member'"`
 
-	// Fine-grained permissions as JSONB
-	// Example: {"products": ["read", "write"], "orders": ["read"]}
+	// Permissions copied from the member's custom role as JSONB (empty for built-in roles)
+	// Example: ["orders:view", "catalog:*"]
 	Permissions JSONB `json:"permissions" gorm:"type:jsonb;default:'{}'"`
 
 	// Is this the user's default/primary tenant?
@@ -493,11 +529,16 @@ type UserTenantMembership struct {
 	IsActive bool `json:"is_active" gorm:"default:true"`
 
 	// Invitation tracking
-	InvitedBy           *uuid.UUID `json:"invited_by" gorm:"type:uuid"`
-	InvitedAt           *time.Time `json:"invited_at"`
-	InvitationToken     string     `json:"invitation_token,omitempty" gorm:"size:255;index"`
-	InvitationExpiresAt *time.Time `json:"invitation_expires_at"`
-	AcceptedAt          *time.Time `json:"accepted_at"`
+	InvitedBy            *uuid.UUID `json:"invited_by" gorm:"type:uuid"`
+	InvitedAt            *time.Time `json:"invited_at"`
+	InvitedEmail         string     `json:"invited_email,omitempty" gorm:"size:255;index"`
+	InvitationToken      string     `json:"invitation_token,omitempty" gorm:"size:255;index"`
+	InvitationExpiresAt  *time.Time `json:"invitation_expires_at"`
+	InvitationSentCount  int        `json:"invitation_sent_count"`
+	InvitationLastSentAt *time.Time `json:"invitation_last_sent_at"`
+	InvitationRevokedAt  *time.Time `json:"invitation_revoked_at"`
+	InvitationRevokedBy  *uuid.UUID `json:"invitation_revoked_by" gorm:"type:uuid"`
+	AcceptedAt           *time.Time `json:"accepted_at"`
 
 	// Activity tracking
 	LastAccessedAt *time.Time `json:"last_accessed_at"`
@@ -514,6 +555,28 @@ func (UserTenantMembership) TableName() string {
 	return "user_tenant_memberships"
 }
 
+// Invitation statuses
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusExpired  = "expired"
+	InvitationStatusRevoked  = "revoked"
+)
+
+// InvitationStatus returns the lifecycle status of an invitation membership at now
+func (m *UserTenantMembership) InvitationStatus(now time.Time) string {
+	switch {
+	case m.AcceptedAt != nil:
+		return InvitationStatusAccepted
+	case m.InvitationRevokedAt != nil:
+		return InvitationStatusRevoked
+	case m.InvitationExpiresAt != nil && !m.InvitationExpiresAt.After(now):
+		return InvitationStatusExpired
+	default:
+		return InvitationStatusPending
+	}
+}
+
 // TenantActivityLog represents audit trail for tenant activities
 type TenantActivityLog struct {
 	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
@@ -629,6 +692,9 @@ type PricingTierConfig struct {
 	MaxUsers     int      `json:"max_users"`      // -1 for unlimited
 	MaxStorageMB int      `json:"max_storage_mb"` // -1 for unlimited
 	IsEnabled    bool     `json:"is_enabled"`     // Whether this tier can be selected
+
+	MaxAPICallsPerMonth int `json:"max_api_calls_per_month"` // -1 for unlimited
+	MaxEmailsPerMonth   int `json:"max_emails_per_month"`    // -1 for unlimited
 }
 
 // GetPricingTiers returns all available pricing tiers with their configurations
@@ -651,6 +717,9 @@ func GetPricingTiers() map[string]PricingTierConfig {
 			MaxUsers:     2,
 			MaxStorageMB: 500,
 			IsEnabled:    true, // Only free tier is enabled for now
+
+			MaxAPICallsPerMonth: 10000,
+			MaxEmailsPerMonth:   1000,
 		},
 		PricingTierStarter: {
 			Name:         PricingTierStarter,
@@ -669,6 +738,9 @@ func GetPricingTiers() map[string]PricingTierConfig {
 			MaxUsers:     5,
 			MaxStorageMB: 2048,
 			IsEnabled:    false, // Disabled until monetization
+
+			MaxAPICallsPerMonth: 100000,
+			MaxEmailsPerMonth:   10000,
 		},
 		PricingTierProfessional: {
 			Name:         PricingTierProfessional,
@@ -688,6 +760,9 @@ func GetPricingTiers() map[string]PricingTierConfig {
 			MaxUsers:     15,
 			MaxStorageMB: 10240,
 			IsEnabled:    false, // Disabled until monetization
+
+			MaxAPICallsPerMonth: 1000000,
+			MaxEmailsPerMonth:   100000,
 		},
 		PricingTierEnterprise: {
 			Name:         PricingTierEnterprise,
@@ -707,6 +782,9 @@ func GetPricingTiers() map[string]PricingTierConfig {
 			MaxUsers:     -1,    // Unlimited
 			MaxStorageMB: -1,    // Unlimited
 			IsEnabled:    false, // Disabled until monetization
+
+			MaxAPICallsPerMonth: -1, // Unlimited
+			MaxEmailsPerMonth:   -1, // Unlimited
 		},
 	}
 }
@@ -717,6 +795,8 @@ const (
 	ActivityMemberAccepted    = "member.accepted"
 	ActivityMemberRemoved     = "member.removed"
 	ActivityMemberRoleChanged = "member.role_changed"
+	ActivityInvitationResent  = "invitation.resent"
+	ActivityInvitationRevoked = "invitation.revoked"
 	ActivitySettingsUpdated   = "settings.updated"
 	ActivityTenantCreated     = "tenant.created"
 	ActivityTenantUpdated     = "tenant.updated"
@@ -751,6 +831,9 @@ type TenantCredential struct {
 	MFABackupCodes JSONB      `json:"-" gorm:"type:jsonb;default:'[]'"`
 	MFALastUsedAt  *time.Time `json:"mfa_last_used_at"`
 
+	// Version of the tenant data-encryption key protecting MFASecret (0 = not yet encrypted)
+	KeyVersion int `json:"key_version" gorm:"default:0;index"`
+
 	// Login security
 	LoginAttempts         int        `json:"login_attempts" gorm:"default:0"`
 	LastLoginAttemptAt    *time.Time `json:"last_login_attempt_at"`
@@ -815,13 +898,18 @@ type TenantAuthPolicy struct {
 	PasswordSpecialChars        string `json:"password_special_chars" gorm:"size:100"`
 	PasswordExpiryDays          *int   `json:"password_expiry_days"`                   // NULL = no expiry
 	PasswordHistoryCount        int    `json:"password_history_count" gorm:"default:5"` // Prevent reuse of last N passwords
+	PasswordCheckBreached       bool   `json:"password_check_breached" gorm:"default:false"` // Reject passwords found in known breaches (HIBP range API)
 
 	// Login policy
 	MaxLoginAttempts       int `json:"max_login_attempts" gorm:"default:5"`
 	LockoutDurationMinutes int `json:"lockout_duration_minutes" gorm:"default:30"`
-	SessionTimeoutMinutes  int `json:"session_timeout_minutes" gorm:"default:480"` // 8 hours default
+	SessionTimeoutMinutes  int `json:"session_timeout_minutes" gorm:"default:480"` // 8 hours default; absolute session lifetime
 	MaxConcurrentSessions  int `json:"max_concurrent_sessions" gorm:"default:5"`
 
+	// Session lifetime policy (absolute lifetime is SessionTimeoutMinutes)
+	SessionIdleTimeoutMinutes int `json:"session_idle_timeout_minutes" gorm:"default:0"` // 0 = no idle timeout
+	SessionRememberMeDays     int `json:"session_remember_me_days" gorm:"default:0"`     // 0 = remember-me disabled
+
 	// Progressive lockout policy
 	// Strict 2-tier lockout: first lockout is temporary, second is permanent
 	// Tier 1 (5 attempts): 30 minutes lockout
@@ -830,12 +918,19 @@ type TenantAuthPolicy struct {
 	Tier1LockoutMinutes       int  `json:"tier1_lockout_minutes" gorm:"default:30"`  // 5 failed attempts = 30 min lock
 	PermanentLockoutThreshold int  `json:"permanent_lockout_threshold" gorm:"default:7"` // 7 failed attempts = permanent lock
 	LockoutResetHours         int  `json:"lockout_reset_hours" gorm:"default:24"` // Reset tier after N hours of no failures
+	Tier2LockoutMinutes       int  `json:"tier2_lockout_minutes" gorm:"default:60"`    // 2x max_login_attempts failures
+	Tier3LockoutMinutes       int  `json:"tier3_lockout_minutes" gorm:"default:360"`   // 3x max_login_attempts failures
+	Tier4LockoutMinutes       int  `json:"tier4_lockout_minutes" gorm:"default:1440"`  // 4x or more (maximum, still auto-unlocks)
 
 	// MFA policy
 	MFARequired        bool  `json:"mfa_required" gorm:"default:false"`
 	MFARequiredForRoles JSONB `json:"mfa_required_for_roles" gorm:"type:jsonb;default:'[\"owner\", \"admin\"]'"`
 	MFAAllowedTypes    JSONB `json:"mfa_allowed_types" gorm:"type:jsonb;default:'[\"totp\", \"email\"]'"`
 
+	// Passkey (WebAuthn) policy
+	PasskeyPolicy           string `json:"passkey_policy" gorm:"size:20;default:'off'"` // off, prefer, require
+	PasskeyRequiredForRoles JSONB  `json:"passkey_required_for_roles" gorm:"type:jsonb;default:'[\"owner\", \"admin\"]'"`
+
 	// IP and device restrictions
 	IPWhitelistEnabled        bool  `json:"ip_whitelist_enabled" gorm:"default:false"`
 	IPWhitelist               JSONB `json:"ip_whitelist" gorm:"type:jsonb;default:'[]'"`
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Onboarding funnel stages. Template steps are keyed FunnelStepPrefix + task_id and
+// ordered by the task order_index, between the started and email verified stages.
+const (
+	FunnelStageStarted       = "started"
+	FunnelStageEmailVerified = "email_verified"
+	FunnelStageCompleted     = "completed"
+	FunnelStepPrefix         = "step:"
+
+	FunnelStageStartedOrder       = 0
+	FunnelStageEmailVerifiedOrder = 1000
+	FunnelStageCompletedOrder     = 1001
+)
+
+// OnboardingFunnelDaily is the materialized onboarding funnel summary: how many sessions
+// started on a day reached each stage. It is rebuilt by the funnel refresh job and only read
+// by the analytics endpoint, so funnel queries never scan the session tables.
+type OnboardingFunnelDaily struct {
+	Day             time.Time `json:"day" gorm:"type:date;primaryKey"`
+	ApplicationType string    `json:"application_type" gorm:"size:50;primaryKey"`
+	StageKey        string    `json:"stage_key" gorm:"size:150;primaryKey"`
+	StageName       string    `json:"stage_name" gorm:"size:255"`
+	StageOrder      int       `json:"stage_order"`
+	Sessions        int64     `json:"sessions"`
+	RefreshedAt     time.Time `json:"refreshed_at"`
+}
+
+// TableName specifies the table name for OnboardingFunnelDaily
+func (OnboardingFunnelDaily) TableName() string {
+	return "onboarding_funnel_daily"
+}
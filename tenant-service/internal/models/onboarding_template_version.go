@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Onboarding template version states: draft -> published -> archived
+// A template has at most one draft and one published version at a time; publishing a draft
+// archives the previously published version.
+const (
+	TemplateVersionStatusDraft     = "draft"
+	TemplateVersionStatusPublished = "published"
+	TemplateVersionStatusArchived  = "archived"
+)
+
+// OnboardingTemplateVersion is an immutable snapshot of a template's content once published
+// Sessions are pinned to the version they started with, so publishing a new version never
+// changes the steps or custom fields of in-flight sessions.
+type OnboardingTemplateVersion struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TemplateID     uuid.UUID  `json:"template_id" gorm:"type:uuid;not null;uniqueIndex:idx_template_version"`
+	Version        int        `json:"version" gorm:"not null;uniqueIndex:idx_template_version"`
+	Status         string     `json:"status" gorm:"size:20;not null;default:'draft';index"`
+	Name           string     `json:"name" gorm:"not null"`
+	Description    string     `json:"description"`
+	TemplateConfig JSONB      `json:"template_config" gorm:"type:jsonb;default:'{}'"`
+	Steps          JSONB      `json:"steps" gorm:"type:jsonb;default:'[]'"`
+	CustomFields   JSONB      `json:"custom_fields" gorm:"type:jsonb;default:'[]'"` // []CustomFieldDefinition
+	ChangeNotes    string     `json:"change_notes,omitempty" gorm:"type:text"`
+	PublishedAt    *time.Time `json:"published_at,omitempty"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for OnboardingTemplateVersion
+func (OnboardingTemplateVersion) TableName() string {
+	return "onboarding_template_versions"
+}
+
+func (v *OnboardingTemplateVersion) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+// NewTemplateVersion snapshots the template's current content as the given version
+func NewTemplateVersion(t *OnboardingTemplate, version int, status string) *OnboardingTemplateVersion {
+	return &OnboardingTemplateVersion{
+		TemplateID:     t.ID,
+		Version:        version,
+		Status:         status,
+		Name:           t.Name,
+		Description:    t.Description,
+		TemplateConfig: t.TemplateConfig,
+		Steps:          t.Steps,
+		CustomFields:   t.CustomFields,
+	}
+}
+
+// Apply returns a copy of the template with this version's content
+func (v *OnboardingTemplateVersion) Apply(t *OnboardingTemplate) *OnboardingTemplate {
+	pinned := *t
+	pinned.Version = v.Version
+	pinned.Name = v.Name
+	pinned.Description = v.Description
+	pinned.TemplateConfig = v.TemplateConfig
+	pinned.Steps = v.Steps
+	pinned.CustomFields = v.CustomFields
+	return &pinned
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Seed record kinds
+const (
+	SeedKindTemplate     = "template"
+	SeedKindReservedSlug = "reserved_slug"
+)
+
+// SeedRecord remembers the checksum of a seed profile entry when it was last applied
+// Entries whose checksum has not changed are skipped, so applying a profile is idempotent
+// and rows edited by admins are only touched when the profile itself changes.
+type SeedRecord struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Kind      string     `json:"kind" gorm:"size:30;not null;uniqueIndex:idx_seed_record_key"`
+	Key       string     `json:"key" gorm:"size:100;not null;uniqueIndex:idx_seed_record_key"` // Template key or reserved slug
+	Checksum  string     `json:"checksum" gorm:"size:64;not null"`
+	Profile   string     `json:"profile" gorm:"size:50;not null"` // Profile that last applied the entry
+	TargetID  *uuid.UUID `json:"target_id,omitempty" gorm:"type:uuid"`
+	AppliedAt time.Time  `json:"applied_at" gorm:"not null"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for SeedRecord
+func (SeedRecord) TableName() string {
+	return "seed_records"
+}
+
+func (r *SeedRecord) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
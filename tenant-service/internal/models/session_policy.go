@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// SessionSettings are the effective session lifetime controls for a tenant
+type SessionSettings struct {
+	AbsoluteMinutes int `json:"absolute_minutes"` // Longest a session lasts from login
+	IdleMinutes     int `json:"idle_minutes"`     // Inactivity that ends a session; 0 = no idle timeout
+	RememberMeDays  int `json:"remember_me_days"` // Absolute lifetime of remember-me logins; 0 = remember-me disabled
+}
+
+// DefaultSessionSettings apply to tenants without an auth policy
+func DefaultSessionSettings() SessionSettings {
+	return SessionSettings{AbsoluteMinutes: 480}
+}
+
+// SessionSettings returns the policy's session lifetime controls, falling back to the
+// defaults for unset values. Safe to call on a nil policy.
+func (p *TenantAuthPolicy) SessionSettings() SessionSettings {
+	settings := DefaultSessionSettings()
+	if p == nil {
+		return settings
+	}
+	if p.SessionTimeoutMinutes > 0 {
+		settings.AbsoluteMinutes = p.SessionTimeoutMinutes
+	}
+	if p.SessionIdleTimeoutMinutes > 0 {
+		settings.IdleMinutes = p.SessionIdleTimeoutMinutes
+	}
+	if p.SessionRememberMeDays > 0 {
+		settings.RememberMeDays = p.SessionRememberMeDays
+	}
+	return settings
+}
+
+// Lifetime is the absolute lifetime of a new session. Remember-me only extends it when the
+// tenant allows remember-me logins.
+func (s SessionSettings) Lifetime(rememberMe bool) time.Duration {
+	if rememberMe && s.RememberMeDays > 0 {
+		return time.Duration(s.RememberMeDays) * 24 * time.Hour
+	}
+	return time.Duration(s.AbsoluteMinutes) * time.Minute
+}
+
+// IdleTimeout is how long a session may go unused; 0 means sessions never idle out
+func (s SessionSettings) IdleTimeout() time.Duration {
+	return time.Duration(s.IdleMinutes) * time.Minute
+}
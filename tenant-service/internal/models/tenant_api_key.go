@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tenant API key scopes, from least to most privileged
+const (
+	APIKeyScopeReadOnly         = "read_only"
+	APIKeyScopeMemberManagement = "member_management"
+	APIKeyScopeFull             = "full"
+)
+
+var apiKeyScopeRank = map[string]int{
+	APIKeyScopeReadOnly:         1,
+	APIKeyScopeMemberManagement: 2,
+	APIKeyScopeFull:             3,
+}
+
+// IsValidAPIKeyScope reports whether scope is a known API key scope
+func IsValidAPIKeyScope(scope string) bool {
+	_, ok := apiKeyScopeRank[scope]
+	return ok
+}
+
+// APIKeyScopeAllows reports whether a key with scope may perform an operation that requires
+// the required scope; each scope includes everything the lower scopes allow
+func APIKeyScopeAllows(scope, required string) bool {
+	have, ok := apiKeyScopeRank[scope]
+	if !ok {
+		return false
+	}
+	need, ok := apiKeyScopeRank[required]
+	if !ok {
+		return false
+	}
+	return have >= need
+}
+
+// TenantAPIKey is a tenant-scoped credential for integrations (e.g. storefronts) that call
+// global services without a Keycloak JWT. Only the SHA-256 of the key is stored; the
+// plaintext is returned once when the key is created or rotated.
+type TenantAPIKey struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID      uuid.UUID  `json:"tenant_id" gorm:"type:uuid;not null;index"`
+	Name          string     `json:"name" gorm:"size:100;not null"`
+	Scope         string     `json:"scope" gorm:"size:30;not null"`
+	KeyPrefix     string     `json:"key_prefix" gorm:"size:20;not null"` // Non-secret leading characters shown in listings
+	KeyHash       string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	CreatedBy     uuid.UUID  `json:"created_by" gorm:"type:uuid;not null"`
+	RotatedFromID *uuid.UUID `json:"rotated_from_id,omitempty" gorm:"type:uuid"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy     *uuid.UUID `json:"revoked_by,omitempty" gorm:"type:uuid"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantAPIKey
+func (TenantAPIKey) TableName() string {
+	return "tenant_api_keys"
+}
+
+func (k *TenantAPIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActive reports whether the key can still be used at the given time
+func (k *TenantAPIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(now)
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantAuthSession records a login to a tenant so the user can see and revoke it.
+// The ID is the Keycloak session ID ("sid" claim) when the token carries one.
+type TenantAuthSession struct {
+	ID                 string     `json:"id" gorm:"size:255;primary_key"`
+	TenantID           uuid.UUID  `json:"tenant_id" gorm:"type:uuid;not null;index:idx_tenant_auth_sessions_owner"`
+	Subject            string     `json:"-" gorm:"size:255;not null;index:idx_tenant_auth_sessions_owner"` // Keycloak user ID (JWT sub), falls back to UserID
+	UserID             *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid"`                              // Local user or staff ID
+	AuthContext        string     `json:"auth_context,omitempty" gorm:"size:20"`                           // customer or staff
+	IPAddress          string     `json:"ip_address,omitempty" gorm:"size:45"`
+	UserAgent          string     `json:"user_agent,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	LastSeenAt         time.Time  `json:"last_seen_at"`
+	ExpiresAt          time.Time  `json:"expires_at" gorm:"index"`
+	IdleTimeoutMinutes int        `json:"idle_timeout_minutes,omitempty" gorm:"default:0"` // From the tenant's policy at login; 0 = none
+	RememberMe         bool       `json:"remember_me" gorm:"default:false"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy          string     `json:"revoked_by,omitempty" gorm:"size:255"`
+	RevokedReason      string     `json:"revoked_reason,omitempty" gorm:"size:50"`
+	CurrentSession     bool       `json:"current" gorm:"-"` // Set when listing, for the session making the request
+}
+
+// TableName specifies the table name for TenantAuthSession
+func (TenantAuthSession) TableName() string {
+	return "tenant_auth_sessions"
+}
+
+// IsActive reports whether the session has neither been revoked nor expired at now
+func (s *TenantAuthSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt) && !s.IsIdle(now)
+}
+
+// IsIdle reports whether the session has gone unused for longer than its idle timeout
+func (s *TenantAuthSession) IsIdle(now time.Time) bool {
+	return s.IdleTimeoutMinutes > 0 && !now.Before(s.IdleExpiresAt())
+}
+
+// IdleExpiresAt is when the session idles out unless it is used again; zero without an idle timeout
+func (s *TenantAuthSession) IdleExpiresAt() time.Time {
+	if s.IdleTimeoutMinutes <= 0 {
+		return time.Time{}
+	}
+	return s.LastSeenAt.Add(time.Duration(s.IdleTimeoutMinutes) * time.Minute)
+}
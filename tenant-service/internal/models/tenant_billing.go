@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Billing provider events consumed from the billing webhook
+const (
+	BillingEventTrialStarted          = "trial_started"
+	BillingEventPlanChanged           = "plan_changed"
+	BillingEventPaymentFailed         = "payment_failed"
+	BillingEventPaymentSucceeded      = "payment_succeeded"
+	BillingEventSubscriptionCancelled = "subscription_cancelled"
+)
+
+// Billing states cached on the tenant. An empty state means the tenant has never been billed.
+const (
+	BillingStatusTrialing  = "trialing"
+	BillingStatusActive    = "active"
+	BillingStatusPastDue   = "past_due"  // A payment failed; the tenant keeps its plan during the grace period
+	BillingStatusSuspended = "suspended" // The grace period ended unpaid; the tenant is suspended
+	BillingStatusCancelled = "cancelled" // Moved to the default plan
+)
+
+// TenantStatusSuspended is the tenant status while billing keeps it suspended
+const TenantStatusSuspended = "suspended"
+
+// Subscription change kinds caused by billing, recorded in the tenant activity log
+const (
+	SubscriptionChangeSuspended  = "suspended"
+	SubscriptionChangeReinstated = "reinstated"
+	SubscriptionChangeCancelled  = "cancelled"
+)
+
+// BillingEvent records a processed billing provider webhook so redeliveries are ignored
+type BillingEvent struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ProviderID  string     `json:"provider_id" gorm:"size:255;not null;uniqueIndex"` // The provider's event ID
+	Type        string     `json:"type" gorm:"size:50;not null"`
+	TenantID    *uuid.UUID `json:"tenant_id,omitempty" gorm:"type:uuid;index"`
+	Payload     JSONB      `json:"payload" gorm:"type:jsonb"`
+	Result      string     `json:"result" gorm:"size:255"` // What the event changed, or why it was ignored
+	ProcessedAt time.Time  `json:"processed_at"`
+}
+
+// TableName specifies the table name for BillingEvent
+func (BillingEvent) TableName() string {
+	return "tenant_billing_events"
+}
+
+func (e *BillingEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsBillingSuspended reports whether billing has suspended the tenant, which revokes every feature
+func (t *Tenant) IsBillingSuspended() bool {
+	return t.BillingStatus == BillingStatusSuspended
+}
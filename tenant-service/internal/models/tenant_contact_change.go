@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultContactChangeExpiry is how long a pending contact change can be verified
+const DefaultContactChangeExpiry = 24 * time.Hour
+
+// Business contact fields that can be changed after onboarding
+const (
+	ContactFieldEmail = "email"
+	ContactFieldPhone = "phone"
+)
+
+// Activities of the contact change flow, logged in TenantActivityLog
+const (
+	ActivityTenantContactChangeRequested = "tenant.contact_change_requested"
+	ActivityTenantContactChanged         = "tenant.contact_changed"
+)
+
+// TenantContactChange is a pending change of the tenant's business contact email or phone.
+// The old value stays active until the code sent to NewValue is verified.
+type TenantContactChange struct {
+	ID               uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID         uuid.UUID `json:"tenant_id" gorm:"type:uuid;not null;index"`
+	Field            string    `json:"field" gorm:"type:varchar(20);not null"` // email, phone
+	OldValue         string    `json:"old_value" gorm:"size:255"`
+	NewValue         string    `json:"new_value" gorm:"size:255;not null"`
+	PhoneCountryCode string    `json:"phone_country_code,omitempty" gorm:"type:varchar(10)"` // ISO country code of a new phone
+	RequestedBy      uuid.UUID `json:"requested_by" gorm:"type:uuid;not null"`
+
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null;index"`
+	VerifiedAt  *time.Time `json:"verified_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantContactChange
+func (TenantContactChange) TableName() string {
+	return "tenant_contact_changes"
+}
+
+func (c *TenantContactChange) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	if c.ExpiresAt.IsZero() {
+		c.ExpiresAt = time.Now().Add(DefaultContactChangeExpiry)
+	}
+	return nil
+}
+
+// IsPending reports whether the change can still be verified at now
+func (c *TenantContactChange) IsPending(now time.Time) bool {
+	return c.VerifiedAt == nil && c.CancelledAt == nil && now.Before(c.ExpiresAt)
+}
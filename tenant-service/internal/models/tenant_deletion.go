@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tenant deletion saga participant statuses
+const (
+	// DeletionStatusPending means the purge was requested and no acknowledgment has arrived yet
+	DeletionStatusPending = "pending"
+	// DeletionStatusPurged means the service acknowledged that all tenant data was purged
+	DeletionStatusPurged = "purged"
+	// DeletionStatusFailed means the service reported a purge failure; it will be re-requested
+	DeletionStatusFailed = "failed"
+	// DeletionStatusExhausted means the service never confirmed within the maximum attempts
+	DeletionStatusExhausted = "exhausted"
+)
+
+// TenantDeletionStatus tracks one service's acknowledgment of a tenant purge
+// One row exists per (deleted tenant, participating service)
+type TenantDeletionStatus struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID        uuid.UUID  `json:"tenant_id" gorm:"type:uuid;not null;uniqueIndex:idx_tenant_deletion_service"`
+	DeletedTenantID uuid.UUID  `json:"deleted_tenant_id" gorm:"type:uuid;not null;index"`
+	Slug            string     `json:"slug" gorm:"size:100;not null"`
+	ServiceName     string     `json:"service_name" gorm:"size:100;not null;uniqueIndex:idx_tenant_deletion_service"`
+	Status          string     `json:"status" gorm:"size:20;not null;default:'pending';index"`
+	Attempts        int        `json:"attempts" gorm:"default:0"`
+	ItemsPurged     int64      `json:"items_purged" gorm:"default:0"`
+	LastError       string     `json:"last_error,omitempty" gorm:"type:text"`
+	LastRequestedAt *time.Time `json:"last_requested_at"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantDeletionStatus
+func (TenantDeletionStatus) TableName() string {
+	return "tenant_deletion_statuses"
+}
+
+func (s *TenantDeletionStatus) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsTerminal reports whether the service no longer needs to be re-requested
+func (s *TenantDeletionStatus) IsTerminal() bool {
+	return s.Status == DeletionStatusPurged || s.Status == DeletionStatusExhausted
+}
+
+// Acknowledge applies a participant's purge acknowledgment
+// Returns false when the service had already purged, so duplicate acknowledgments change nothing
+func (s *TenantDeletionStatus) Acknowledge(status string, itemsPurged int64, errMsg string, now time.Time) bool {
+	if s.Status == DeletionStatusPurged {
+		return false
+	}
+
+	s.AcknowledgedAt = &now
+	s.ItemsPurged = itemsPurged
+	if status == DeletionStatusPurged {
+		s.Status = DeletionStatusPurged
+		s.LastError = ""
+	} else {
+		s.Status = DeletionStatusFailed
+		s.LastError = errMsg
+	}
+	return true
+}
+
+// NeedsRetry reports whether the purge should be re-requested: the service has not purged and
+// was last asked before the cutoff
+func (s *TenantDeletionStatus) NeedsRetry(cutoff time.Time) bool {
+	if s.Status != DeletionStatusPending && s.Status != DeletionStatusFailed {
+		return false
+	}
+	return s.LastRequestedAt == nil || s.LastRequestedAt.Before(cutoff)
+}
+
+// Exhaust marks the service exhausted once maxAttempts requests went unconfirmed
+// Returns false while attempts remain
+func (s *TenantDeletionStatus) Exhaust(maxAttempts int) bool {
+	if s.IsTerminal() || s.Attempts < maxAttempts {
+		return false
+	}
+
+	s.Status = DeletionStatusExhausted
+	if s.LastError == "" {
+		s.LastError = fmt.Sprintf("no purge acknowledgment after %d attempts", s.Attempts)
+	}
+	return true
+}
+
+// AllPurged reports whether every participant of a deletion saga has purged
+func AllPurged(statuses []TenantDeletionStatus) bool {
+	if len(statuses) == 0 {
+		return false
+	}
+	for _, status := range statuses {
+		if status.Status != DeletionStatusPurged {
+			return false
+		}
+	}
+	return true
+}
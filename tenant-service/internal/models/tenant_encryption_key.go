@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tenant encryption key statuses
+const (
+	// EncryptionKeyStatusActive is the key used for all new encryptions (one per tenant)
+	EncryptionKeyStatusActive = "active"
+	// EncryptionKeyStatusDecryptOnly is a rotated key still protecting rows awaiting re-encryption
+	EncryptionKeyStatusDecryptOnly = "decrypt_only"
+	// EncryptionKeyStatusRetired is a rotated key that no longer protects any rows
+	EncryptionKeyStatusRetired = "retired"
+)
+
+// TenantEncryptionKey is a per-tenant data-encryption key (DEK)
+// The key material is only stored wrapped (encrypted) by the KMS master key identified by MasterKeyID
+type TenantEncryptionKey struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID    uuid.UUID  `json:"tenant_id" gorm:"type:uuid;not null;uniqueIndex:idx_tenant_key_version"`
+	Version     int        `json:"version" gorm:"not null;uniqueIndex:idx_tenant_key_version"`
+	Status      string     `json:"status" gorm:"size:20;not null;default:'active';index"`
+	Algorithm   string     `json:"algorithm" gorm:"size:30;not null;default:'AES-256-GCM'"`
+	WrappedKey  string     `json:"-" gorm:"type:text;not null"`
+	MasterKeyID string     `json:"master_key_id" gorm:"size:255;not null"`
+	CreatedBy   *uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RotatedAt   *time.Time `json:"rotated_at"`
+	RetiredAt   *time.Time `json:"retired_at"`
+}
+
+// TableName specifies the table name for TenantEncryptionKey
+func (TenantEncryptionKey) TableName() string {
+	return "tenant_encryption_keys"
+}
+
+func (k *TenantEncryptionKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
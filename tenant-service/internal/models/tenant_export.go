@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tenant export statuses
+const (
+	TenantExportStatusPending   = "pending"
+	TenantExportStatusRunning   = "running"
+	TenantExportStatusCompleted = "completed"
+	TenantExportStatusFailed    = "failed"
+	TenantExportStatusExpired   = "expired"
+)
+
+// TenantExport is an asynchronously generated ZIP archive of the tenant data held by
+// tenant-service (GDPR data portability). The archive is dropped once ExpiresAt passes.
+type TenantExport struct {
+	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID          uuid.UUID  `json:"tenant_id" gorm:"type:uuid;not null;index"`
+	RequestedBy       uuid.UUID  `json:"requested_by" gorm:"type:uuid;not null"`
+	Status            string     `json:"status" gorm:"size:20;not null;default:'pending';index"`
+	Archive           []byte     `json:"-" gorm:"type:bytea"`
+	SizeBytes         int64      `json:"size_bytes"`
+	Checksum          string     `json:"checksum,omitempty" gorm:"size:64"` // SHA-256 of the archive
+	RecordCounts      JSONB      `json:"record_counts,omitempty" gorm:"type:jsonb"`
+	DownloadTokenHash string     `json:"-" gorm:"size:64"`
+	Error             string     `json:"error,omitempty" gorm:"type:text"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	DownloadedAt      *time.Time `json:"downloaded_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantExport
+func (TenantExport) TableName() string {
+	return "tenant_exports"
+}
+
+func (e *TenantExport) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Ways a tenant proves it controls an email domain
+const (
+	JoinDomainVerificationDNS   = "dns_txt" // TXT record checked through custom-domain-service
+	JoinDomainVerificationEmail = "email"   // Code sent to an address at the domain
+)
+
+// Join domain verification states
+const (
+	JoinDomainStatusPending  = "pending"
+	JoinDomainStatusVerified = "verified"
+)
+
+// What happens when someone with an address at a verified domain signs in
+const (
+	JoinModeAuto     = "auto"     // Membership is created immediately with the default role
+	JoinModeApproval = "approval" // A join request waits for an owner or admin
+)
+
+// Join request states
+const (
+	JoinRequestStatusPending  = "pending"
+	JoinRequestStatusApproved = "approved"
+	JoinRequestStatusRejected = "rejected"
+)
+
+// TenantJoinDomain lets anyone with an email address at Domain join the tenant once the
+// tenant has proven it controls the domain. A domain can be verified by one tenant only.
+type TenantJoinDomain struct {
+	ID                 uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID           uuid.UUID  `json:"tenant_id" gorm:"type:uuid;not null;uniqueIndex:idx_tenant_join_domain"`
+	Domain             string     `json:"domain" gorm:"size:255;not null;uniqueIndex:idx_tenant_join_domain;index"`
+	Status             string     `json:"status" gorm:"size:20;not null;default:'pending'"`
+	VerificationMethod string     `json:"verification_method" gorm:"size:20;not null"`
+	VerificationToken  string     `json:"-" gorm:"size:64;not null"` // Published in the TXT record
+	VerificationEmail  string     `json:"verification_email,omitempty" gorm:"size:255"`
+	EmailCodeHash      string     `json:"-" gorm:"size:64"` // SHA-256 of the emailed code
+	EmailCodeExpiresAt *time.Time `json:"-"`
+	EmailCodeAttempts  int        `json:"-" gorm:"default:0"`
+	VerifiedAt         *time.Time `json:"verified_at,omitempty"`
+	VerifiedBy         *uuid.UUID `json:"verified_by,omitempty" gorm:"type:uuid"`
+	JoinMode           string     `json:"join_mode" gorm:"size:20;not null;default:'approval'"`
+	DefaultRole        string     `json:"default_role" gorm:"size:50;not null;default:'member'"`
+	Enabled            bool       `json:"enabled" gorm:"default:true"`
+	CreatedBy          uuid.UUID  `json:"created_by" gorm:"type:uuid"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantJoinDomain
+func (TenantJoinDomain) TableName() string {
+	return "tenant_join_domains"
+}
+
+func (d *TenantJoinDomain) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsVerified reports whether the tenant has proven it controls the domain
+func (d *TenantJoinDomain) IsVerified() bool {
+	return d.Status == JoinDomainStatusVerified
+}
+
+// TenantJoinRequest is a request to join a tenant through a verified domain in approval mode
+type TenantJoinRequest struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID   uuid.UUID  `json:"tenant_id" gorm:"type:uuid;not null;index"`
+	DomainID   uuid.UUID  `json:"domain_id" gorm:"type:uuid;not null;index"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Email      string     `json:"email" gorm:"size:255;not null"`
+	Status     string     `json:"status" gorm:"size:20;not null;default:'pending';index"`
+	Role       string     `json:"role" gorm:"size:50;not null"` // Role granted on approval
+	ReviewedBy *uuid.UUID `json:"reviewed_by,omitempty" gorm:"type:uuid"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantJoinRequest
+func (TenantJoinRequest) TableName() string {
+	return "tenant_join_requests"
+}
+
+func (r *TenantJoinRequest) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Plan feature flags evaluated by other services
+const (
+	FeatureAPIAccess          = "api_access"
+	FeatureAdvancedAnalytics  = "advanced_analytics"
+	FeatureCustomThemes       = "custom_themes"
+	FeatureCustomDomain       = "custom_domain"
+	FeatureWhiteLabel         = "white_label"
+	FeatureCustomIntegrations = "custom_integrations"
+	FeaturePrioritySupport    = "priority_support"
+	FeatureSSO                = "sso"
+)
+
+// Subscription states
+const (
+	SubscriptionStatusTrialing = "trialing" // On the plan until TrialEndsAt, then moved to the default plan
+	SubscriptionStatusActive   = "active"
+)
+
+// Billing cycles
+const (
+	BillingCycleMonthly = "monthly"
+	BillingCycleYearly  = "yearly"
+)
+
+// Subscription change kinds, recorded in the tenant activity log
+const (
+	SubscriptionChangeAssigned     = "assigned"
+	SubscriptionChangeUpgrade      = "upgrade"
+	SubscriptionChangeDowngrade    = "downgrade"
+	SubscriptionChangeTrialStarted = "trial_started"
+	SubscriptionChangeTrialExpired = "trial_expired"
+)
+
+// ActivitySubscriptionChanged is the activity log action of plan changes
+const ActivitySubscriptionChanged = "subscription.changed"
+
+// TenantPlan is a subscription plan. PricingTier links it to the tier whose quotas apply
+// (see GetPricingTiers); Features holds the flags the plan grants.
+type TenantPlan struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Code         string    `json:"code" gorm:"size:50;not null;uniqueIndex"`
+	Name         string    `json:"name" gorm:"size:100;not null"`
+	Description  string    `json:"description" gorm:"size:255"`
+	PricingTier  string    `json:"pricing_tier" gorm:"size:50;not null"`
+	MonthlyPrice float64   `json:"monthly_price"`
+	YearlyPrice  float64   `json:"yearly_price"`
+	Features     JSONB     `json:"features" gorm:"type:jsonb;default:'{}'"` // feature key -> enabled
+	Rank         int       `json:"rank" gorm:"not null;default:0"`          // Higher ranks are upgrades
+	SelfService  bool      `json:"self_service" gorm:"default:false"`       // Owners can pick it themselves
+	IsActive     bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantPlan
+func (TenantPlan) TableName() string {
+	return "tenant_plans"
+}
+
+func (p *TenantPlan) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// FeatureFlags decodes the plan's feature flags
+func (p *TenantPlan) FeatureFlags() map[string]bool {
+	return decodeFeatureFlags(p.Features)
+}
+
+// TenantSubscription is a tenant's current plan. Tenants without a row are on the plan of
+// their pricing tier. FeatureOverrides grant or revoke single features for this tenant.
+type TenantSubscription struct {
+	ID               uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID         uuid.UUID   `json:"tenant_id" gorm:"type:uuid;not null;uniqueIndex"`
+	PlanID           uuid.UUID   `json:"plan_id" gorm:"type:uuid;not null;index"`
+	Plan             *TenantPlan `json:"plan,omitempty" gorm:"foreignKey:PlanID"`
+	Status           string      `json:"status" gorm:"size:20;not null;default:'active';index"`
+	BillingCycle     string      `json:"billing_cycle" gorm:"size:20;not null;default:'monthly'"`
+	TrialEndsAt      *time.Time  `json:"trial_ends_at,omitempty" gorm:"index"`
+	FeatureOverrides JSONB       `json:"feature_overrides" gorm:"type:jsonb;default:'{}'"`
+	StartedAt        time.Time   `json:"started_at"`
+	ChangedBy        *uuid.UUID  `json:"changed_by,omitempty" gorm:"type:uuid"` // Nil for system changes
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantSubscription
+func (TenantSubscription) TableName() string {
+	return "tenant_subscriptions"
+}
+
+func (s *TenantSubscription) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsTrialing reports whether the subscription is in an unexpired trial
+func (s *TenantSubscription) IsTrialing(now time.Time) bool {
+	return s.Status == SubscriptionStatusTrialing && s.TrialEndsAt != nil && now.Before(*s.TrialEndsAt)
+}
+
+// Overrides decodes the tenant's feature overrides
+func (s *TenantSubscription) Overrides() map[string]bool {
+	return decodeFeatureFlags(s.FeatureOverrides)
+}
+
+// decodeFeatureFlags decodes a feature key -> enabled object, ignoring non-boolean values
+func decodeFeatureFlags(data JSONB) map[string]bool {
+	flags := map[string]bool{}
+	if len(data) == 0 {
+		return flags
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return flags
+	}
+	for key, value := range raw {
+		if enabled, ok := value.(bool); ok {
+			flags[key] = enabled
+		}
+	}
+	return flags
+}
+
+// DefaultPlanFeatures returns the feature flags of each pricing tier's plan
+func DefaultPlanFeatures() map[string]map[string]bool {
+	return map[string]map[string]bool{
+		PricingTierFree: {},
+		PricingTierStarter: {
+			FeatureAPIAccess:         true,
+			FeatureAdvancedAnalytics: true,
+			FeatureCustomThemes:      true,
+			FeatureCustomDomain:      true,
+		},
+		PricingTierProfessional: {
+			FeatureAPIAccess:          true,
+			FeatureAdvancedAnalytics:  true,
+			FeatureCustomThemes:       true,
+			FeatureCustomDomain:       true,
+			FeatureWhiteLabel:         true,
+			FeatureCustomIntegrations: true,
+			FeaturePrioritySupport:    true,
+		},
+		PricingTierEnterprise: {
+			FeatureAPIAccess:          true,
+			FeatureAdvancedAnalytics:  true,
+			FeatureCustomThemes:       true,
+			FeatureCustomDomain:       true,
+			FeatureWhiteLabel:         true,
+			FeatureCustomIntegrations: true,
+			FeaturePrioritySupport:    true,
+			FeatureSSO:                true,
+		},
+	}
+}
+
+// IsKnownFeature reports whether key is one of the plan feature flags
+func IsKnownFeature(key string) bool {
+	switch key {
+	case FeatureAPIAccess, FeatureAdvancedAnalytics, FeatureCustomThemes, FeatureCustomDomain,
+		FeatureWhiteLabel, FeatureCustomIntegrations, FeaturePrioritySupport, FeatureSSO:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PermissionWildcard grants every permission; "orders:*" grants every orders permission
+const PermissionWildcard = "*"
+
+// BuiltInRolePermissions are the permissions of the fixed membership roles. Permissions use the
+// platform's resource:action names (see go-shared rbac), e.g. "orders:view" or "catalog:*".
+func BuiltInRolePermissions() map[string][]string {
+	return map[string][]string{
+		MembershipRoleOwner: {PermissionWildcard},
+		MembershipRoleAdmin: {PermissionWildcard},
+		MembershipRoleManager: {
+			"catalog:*", "inventory:*", "orders:*", "returns:*", "customers:*", "reviews:*",
+			"marketing:*", "analytics:view",
+		},
+		MembershipRoleMember: {
+			"catalog:*", "inventory:*", "orders:view", "orders:edit", "orders:fulfill",
+			"returns:read", "customers:view", "reviews:view",
+		},
+		MembershipRoleViewer: {
+			"catalog:products:view", "catalog:categories:view", "inventory:stock:view",
+			"orders:view", "returns:read", "customers:view", "reviews:view", "analytics:view",
+		},
+	}
+}
+
+// IsBuiltInRole reports whether role is one of the fixed membership roles
+func IsBuiltInRole(role string) bool {
+	_, ok := BuiltInRolePermissions()[role]
+	return ok
+}
+
+// PermissionGranted reports whether any of grants covers permission. A grant covers the
+// permission itself, and a grant ending in ":*" covers every permission below its prefix.
+func PermissionGranted(grants []string, permission string) bool {
+	for _, grant := range grants {
+		switch {
+		case grant == PermissionWildcard || grant == permission:
+			return true
+		case strings.HasSuffix(grant, ":*") && strings.HasPrefix(permission, strings.TrimSuffix(grant, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// EscalatedPermissions returns the grants of next that previous does not cover, i.e. what a
+// role change adds. It is empty when the change keeps or narrows access.
+func EscalatedPermissions(previous, next []string) []string {
+	var added []string
+	for _, grant := range next {
+		if !PermissionGranted(previous, grant) {
+			added = append(added, grant)
+		}
+	}
+	return added
+}
+
+// TenantRole is a role a tenant defined on top of the built-in ones. Assigning it to a member
+// copies its permissions onto the membership, and edits are copied to every member holding it.
+type TenantRole struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID    uuid.UUID `json:"tenant_id" gorm:"type:uuid;not null;uniqueIndex:idx_tenant_roles_tenant_name"`
+	Name        string    `json:"name" gorm:"size:50;not null;uniqueIndex:idx_tenant_roles_tenant_name"` // Stored in memberships.role
+	DisplayName string    `json:"display_name" gorm:"size:100;not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	Permissions JSONB     `json:"permissions" gorm:"type:jsonb;not null;default:'[]'"` // []string of resource:action grants
+	CreatedBy   uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	UpdatedBy   uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantRole
+func (TenantRole) TableName() string {
+	return "tenant_roles"
+}
+
+func (r *TenantRole) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// PermissionList returns the role's permission grants
+func (r *TenantRole) PermissionList() []string {
+	return permissionList(r.Permissions)
+}
+
+// PermissionList returns the permissions granted by the membership: the permissions copied
+// from its custom role, or the built-in role's defaults
+func (m *UserTenantMembership) PermissionList() []string {
+	if permissions, ok := BuiltInRolePermissions()[m.Role]; ok {
+		return permissions
+	}
+	return permissionList(m.Permissions)
+}
+
+// permissionList decodes a []string of grants; anything else (such as the legacy '{}' default) grants nothing
+func permissionList(data JSONB) []string {
+	var permissions []string
+	if len(data) == 0 {
+		return permissions
+	}
+	_ = json.Unmarshal(data, &permissions)
+	return permissions
+}
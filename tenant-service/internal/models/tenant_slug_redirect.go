@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ActivityTenantSlugChanged is the activity log action of slug renames
+const ActivityTenantSlugChanged = "tenant.slug_changed"
+
+// TenantSlugRedirect maps a tenant's former slug to its current one after a rename.
+// Until ExpiresAt, lookups by the old slug resolve to the tenant and its old hosts keep
+// routing; the old slug's reservation stays active so nobody else can claim it. When the
+// redirect expires the reservation is released and the old routes are removed.
+type TenantSlugRedirect struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID  uuid.UUID  `json:"tenant_id" gorm:"type:uuid;not null;index"`
+	OldSlug   string     `json:"old_slug" gorm:"size:50;not null;uniqueIndex"`
+	NewSlug   string     `json:"new_slug" gorm:"size:50;not null"` // Repointed when the tenant is renamed again
+	ChangedBy *uuid.UUID `json:"changed_by,omitempty" gorm:"type:uuid"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for TenantSlugRedirect
+func (TenantSlugRedirect) TableName() string {
+	return "tenant_slug_redirects"
+}
+
+func (r *TenantSlugRedirect) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
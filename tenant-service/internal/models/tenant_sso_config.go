@@ -0,0 +1,115 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SSO protocols supported for tenant identity providers
+const (
+	SSOProtocolOIDC = "oidc"
+	SSOProtocolSAML = "saml"
+)
+
+// SSO provisioning states of the tenant's Keycloak identity provider
+const (
+	SSOProvisioningPending     = "pending"
+	SSOProvisioningProvisioned = "provisioned"
+	SSOProvisioningFailed      = "failed"
+)
+
+// TenantSSOConfig is an enterprise tenant's staff single sign-on setup. The identity provider
+// itself lives in Keycloak under Alias; this row records what was provisioned, which email
+// domains are routed to it and how IdP groups map to membership roles. OIDC client secrets
+// are handed to Keycloak and never stored here.
+type TenantSSOConfig struct {
+	ID                uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID          uuid.UUID `json:"tenant_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Protocol          string    `json:"protocol" gorm:"size:10;not null"`
+	Alias             string    `json:"alias" gorm:"size:100;not null;uniqueIndex"` // Keycloak identity provider alias
+	DisplayName       string    `json:"display_name" gorm:"size:255"`
+	Enabled           bool      `json:"enabled" gorm:"default:false"`
+	Enforced          bool      `json:"enforced" gorm:"default:false"`          // Password login is refused for matching domains
+	Domains           JSONB     `json:"domains" gorm:"type:jsonb;default:'[]'"` // []string, lower-case email domains
+	GroupsClaim       string    `json:"groups_claim" gorm:"size:100;default:'groups'"`
+	GroupRoleMappings JSONB     `json:"group_role_mappings" gorm:"type:jsonb;default:'{}'"` // map[group]membership role
+	DefaultRole       string    `json:"default_role" gorm:"size:50;default:'member'"`
+	// OIDC
+	DiscoveryURL     string `json:"discovery_url,omitempty" gorm:"size:500"` // Endpoints below were read from it
+	Issuer           string `json:"issuer,omitempty" gorm:"size:500"`
+	AuthorizationURL string `json:"authorization_url,omitempty" gorm:"size:500"`
+	TokenURL         string `json:"token_url,omitempty" gorm:"size:500"`
+	JWKSURL          string `json:"jwks_url,omitempty" gorm:"size:500"`
+	ClientID         string `json:"client_id,omitempty" gorm:"size:255"`
+	// SAML
+	MetadataURL          string     `json:"metadata_url,omitempty" gorm:"size:500"`
+	IdPEntityID          string     `json:"idp_entity_id,omitempty" gorm:"size:500"`
+	SSOURL               string     `json:"sso_url,omitempty" gorm:"size:500"`
+	SLOURL               string     `json:"slo_url,omitempty" gorm:"size:500"`
+	SigningCertificate   string     `json:"signing_certificate,omitempty" gorm:"type:text"` // Base64 DER, as in the metadata
+	CertificateExpiresAt *time.Time `json:"certificate_expires_at,omitempty"`
+	// Provisioning
+	ProvisioningStatus string     `json:"provisioning_status" gorm:"size:20;not null;default:'pending'"`
+	ProvisioningError  string     `json:"provisioning_error,omitempty" gorm:"type:text"`
+	ProvisionedAt      *time.Time `json:"provisioned_at,omitempty"`
+	CreatedBy          uuid.UUID  `json:"created_by" gorm:"type:uuid"`
+	UpdatedBy          uuid.UUID  `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantSSOConfig
+func (TenantSSOConfig) TableName() string {
+	return "tenant_sso_configs"
+}
+
+func (c *TenantSSOConfig) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// DomainList returns the email domains routed to the tenant's identity provider
+func (c *TenantSSOConfig) DomainList() []string {
+	var domains []string
+	if len(c.Domains) == 0 {
+		return domains
+	}
+	_ = json.Unmarshal(c.Domains, &domains)
+	return domains
+}
+
+// RoleMappings returns the IdP group to membership role mappings
+func (c *TenantSSOConfig) RoleMappings() map[string]string {
+	mappings := map[string]string{}
+	if len(c.GroupRoleMappings) == 0 {
+		return mappings
+	}
+	_ = json.Unmarshal(c.GroupRoleMappings, &mappings)
+	return mappings
+}
+
+// MatchesEmail reports whether the email's domain is one of the configured SSO domains
+func (c *TenantSSOConfig) MatchesEmail(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+	for _, d := range c.DomainList() {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// RoutesLogin reports whether logins for the email must go through the identity provider
+func (c *TenantSSOConfig) RoutesLogin(email string) bool {
+	return c.Enabled && c.Enforced && c.ProvisioningStatus == SSOProvisioningProvisioned && c.MatchesEmail(email)
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Metered usage metrics
+const (
+	UsageMetricMembers  = "members"   // Active memberships (counted live)
+	UsageMetricAPICalls = "api_calls" // Tenant API key validations per month
+	UsageMetricStorage  = "storage"   // Bytes stored in document-service
+	UsageMetricEmails   = "emails"    // Emails sent by notification-service per month
+)
+
+// UsagePeriodFormat is the layout of monthly usage periods (YYYY-MM)
+const UsagePeriodFormat = "2006-01"
+
+// TenantUsageCounter is a tenant's running value of a metric.
+// Monthly metrics have one row per period; running totals (storage) use an empty period.
+type TenantUsageCounter struct {
+	TenantID  uuid.UUID `json:"tenant_id" gorm:"type:uuid;primaryKey"`
+	Metric    string    `json:"metric" gorm:"size:30;primaryKey"`
+	Period    string    `json:"period" gorm:"size:7;primaryKey"`
+	Value     int64     `json:"value" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for TenantUsageCounter
+func (TenantUsageCounter) TableName() string {
+	return "tenant_usage_counters"
+}
+
+// TenantUsageEvent records an external event already counted, so redelivered events
+// (JetStream is at-least-once) are not metered twice
+type TenantUsageEvent struct {
+	EventKey  string    `gorm:"size:150;primaryKey"` // e.g. "document.uploaded:<document id>"
+	TenantID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for TenantUsageEvent
+func (TenantUsageEvent) TableName() string {
+	return "tenant_usage_events"
+}
+
+// TenantQuotaNotice records that tenant.quota.exceeded was published for a metric and
+// period, so it is sent once per breach rather than on every counted use
+type TenantQuotaNotice struct {
+	TenantID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Metric     string    `gorm:"size:30;primaryKey"`
+	Period     string    `gorm:"size:7;primaryKey"`
+	NotifiedAt time.Time
+}
+
+// TableName specifies the table name for TenantQuotaNotice
+func (TenantQuotaNotice) TableName() string {
+	return "tenant_quota_notices"
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultWebAuthnChallengeExpiry is how long a registration or login ceremony may take
+const DefaultWebAuthnChallengeExpiry = 5 * time.Minute
+
+// Passkey policy values of TenantAuthPolicy.PasskeyPolicy
+const (
+	PasskeyPolicyOff     = "off"     // Passkeys are optional
+	PasskeyPolicyPrefer  = "prefer"  // Users in PasskeyRequiredForRoles are prompted to enroll a passkey
+	PasskeyPolicyRequire = "require" // Users in PasskeyRequiredForRoles with a passkey cannot sign in with a password
+)
+
+// WebAuthn ceremonies a challenge belongs to
+const (
+	WebAuthnCeremonyRegistration = "registration"
+	WebAuthnCeremonyLogin        = "login"
+)
+
+// Auth events of the passkey flows, logged in TenantAuthAuditLog
+const (
+	AuthEventPasskeyRegistered = "passkey_registered"
+	AuthEventPasskeyRemoved    = "passkey_removed"
+	AuthEventPasskeyLogin      = "passkey_login"
+	AuthEventPasskeyFailed     = "passkey_failed"
+)
+
+// WebAuthnCredential is a passkey a user registered for signing in to one tenant.
+// The same authenticator registered for two tenants is stored twice.
+type WebAuthnCredential struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID   uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	TenantID uuid.UUID `json:"tenant_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_webauthn_credentials_tenant_credential"`
+
+	// Credential record from the attestation
+	CredentialID    string `json:"credential_id" gorm:"size:1024;not null;uniqueIndex:idx_webauthn_credentials_tenant_credential"` // base64url, as sent by the browser
+	PublicKey       []byte `json:"-" gorm:"type:bytea;not null"`                                                                   // COSE-encoded public key
+	AttestationType string `json:"attestation_type" gorm:"size:50"`
+	AAGUID          string `json:"aaguid,omitempty" gorm:"size:36"` // Authenticator model, all zeros for most passkey providers
+	Transports      JSONB  `json:"transports" gorm:"type:jsonb;default:'[]'"`
+	Flags           int    `json:"-" gorm:"default:0"` // Raw authenticator flags of the registration (backup eligibility must not change)
+	BackupEligible  bool   `json:"backup_eligible" gorm:"default:false"`
+	BackupState     bool   `json:"backup_state" gorm:"default:false"` // Synced passkey (iCloud Keychain, Google Password Manager, ...)
+	SignCount       int64  `json:"-" gorm:"default:0"`
+	CloneWarning    bool   `json:"clone_warning" gorm:"default:false"` // Signature counter went backwards at least once
+
+	Name       string     `json:"name" gorm:"size:100"` // User-chosen label, e.g. "MacBook Touch ID"
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for WebAuthnCredential
+func (WebAuthnCredential) TableName() string {
+	return "tenant_webauthn_credentials"
+}
+
+func (w *WebAuthnCredential) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// WebAuthnChallenge holds the server side of a registration or login ceremony until the
+// browser returns the authenticator response. Each challenge can be used only once.
+type WebAuthnChallenge struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TenantID    uuid.UUID  `json:"tenant_id" gorm:"type:uuid;not null;index"`
+	UserID      *uuid.UUID `json:"user_id" gorm:"type:uuid;index"` // NULL for discoverable (username-less) logins
+	Ceremony    string     `json:"ceremony" gorm:"size:20;not null"`
+	SessionData JSONB      `json:"-" gorm:"type:jsonb;not null"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null;index"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for WebAuthnChallenge
+func (WebAuthnChallenge) TableName() string {
+	return "tenant_webauthn_challenges"
+}
+
+func (w *WebAuthnChallenge) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	if w.ExpiresAt.IsZero() {
+		w.ExpiresAt = time.Now().Add(DefaultWebAuthnChallengeExpiry)
+	}
+	return nil
+}
+
+// IsUsable reports whether the challenge belongs to the ceremony and has not expired at now
+func (w *WebAuthnChallenge) IsUsable(ceremony string, now time.Time) bool {
+	return w.Ceremony == ceremony && now.Before(w.ExpiresAt)
+}
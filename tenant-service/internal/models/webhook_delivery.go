@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Webhook event statuses
+const (
+	// WebhookStatusPending means the event is awaiting its first delivery or a retry
+	WebhookStatusPending = "pending"
+	// WebhookStatusSent means the endpoint acknowledged the event with a 2xx response
+	WebhookStatusSent = "sent"
+	// WebhookStatusFailed means every attempt failed and the event is dead-lettered
+	WebhookStatusFailed = "failed"
+)
+
+// Webhook delivery attempt triggers
+const (
+	// WebhookTriggerAutomatic is a delivery made by the background delivery job
+	WebhookTriggerAutomatic = "automatic"
+	// WebhookTriggerManualReplay is a delivery requested through the replay API
+	WebhookTriggerManualReplay = "manual_replay"
+)
+
+// WebhookDeliveryAttempt records one HTTP delivery attempt of a webhook event
+type WebhookDeliveryAttempt struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	WebhookEventID uuid.UUID  `json:"webhook_event_id" gorm:"type:uuid;not null;index"`
+	TenantID       *uuid.UUID `json:"tenant_id" gorm:"type:uuid;index"`
+	WebhookURL     string     `json:"webhook_url" gorm:"size:2048;index"`
+	AttemptNumber  int        `json:"attempt_number"`
+	Trigger        string     `json:"trigger" gorm:"size:20;not null;default:'automatic'"`
+	Success        bool       `json:"success"`
+	StatusCode     int        `json:"status_code"`
+	ResponseBody   string     `json:"response_body,omitempty" gorm:"type:text"`
+	Error          string     `json:"error,omitempty" gorm:"type:text"`
+	DurationMs     int64      `json:"duration_ms"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"index"`
+}
+
+// TableName specifies the table name for WebhookDeliveryAttempt
+func (WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
+
+func (a *WebhookDeliveryAttempt) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -21,8 +21,35 @@ const (
 	EventTenantVerificationRequested = "tenant.verification.requested"
 	EventTenantOnboardingCompleted   = "tenant.onboarding.completed"
 	EventCustomerRegistered          = "customer.registered"
+	EventCustomerPurged              = "customer.purged"
+	EventTenantDeletionRequested     = "tenant.deletion.requested"
+	EventTenantDeletionAcknowledged  = "tenant.deletion.acknowledged"
+	EventTenantMemberAdded           = "tenant.member.added"
+	EventTenantMemberInvited         = "tenant.member.invited"
+	EventTenantQuotaExceeded         = "tenant.quota.exceeded"
+	EventTenantPlanChanged           = "tenant.plan.changed"
+	EventTenantSlugChanged           = "tenant.slug.changed"
+	EventTenantSlugReleased          = "tenant.slug.released"
 )
 
+// Usage events published by other services and metered by tenant-service
+const (
+	SubjectDocumentUploaded = "document.uploaded"        // DOCUMENT_EVENTS, from document-service
+	SubjectDocumentDeleted  = "document.deleted"         // DOCUMENT_EVENTS, from document-service
+	SubjectNotificationSent = "usage.notifications.sent" // USAGE_EVENTS, from notification-service
+)
+
+// SubjectMembershipCacheInvalidated tells every tenant-service instance to drop cached lookups.
+// It is outside tenant.> so the messages are neither persisted in TENANT_EVENTS nor
+// delivered to other services' tenant event consumers.
+const SubjectMembershipCacheInvalidated = "cache.membership.invalidated"
+
+// MembershipCacheInvalidatedEvent lists the cache keys whose lookups changed
+type MembershipCacheInvalidatedEvent struct {
+	Keys      []string  `json:"keys"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // TenantCreatedEvent is published when a new tenant is created
 type TenantCreatedEvent struct {
 	EventType    string    `json:"event_type"`
@@ -57,6 +84,30 @@ type TenantDeletedEvent struct {
 	Timestamp      time.Time `json:"timestamp"`
 }
 
+// TenantDeletionRequestedEvent asks participating services to purge a deleted tenant's data
+// Services listed in Services must reply with a TenantDeletionAcknowledgedEvent once purged
+// The same request may be re-published for services that have not acknowledged; purges must be idempotent
+type TenantDeletionRequestedEvent struct {
+	EventType   string    `json:"event_type"`
+	TenantID    string    `json:"tenant_id"`
+	Slug        string    `json:"slug"`
+	Services    []string  `json:"services"`
+	Attempt     int       `json:"attempt"`
+	RequestedAt time.Time `json:"requested_at"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// TenantDeletionAcknowledgedEvent is published by a participating service after purging tenant data
+type TenantDeletionAcknowledgedEvent struct {
+	EventType   string    `json:"event_type"`
+	TenantID    string    `json:"tenant_id"`
+	Service     string    `json:"service"`
+	Status      string    `json:"status"` // "purged" or "failed"
+	ItemsPurged int64     `json:"items_purged,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
 // SessionCompletedEvent is published when an onboarding session is completed (after email verification)
 // This triggers document migration from onboarding storage to tenant storage
 type SessionCompletedEvent struct {
@@ -104,6 +155,102 @@ type CustomerRegisteredEvent struct {
 	TenantSlug    string    `json:"tenantSlug,omitempty"`
 }
 
+// CustomerPurgedEvent is published when a deactivated customer account is permanently purged
+// This triggers audit-service to pseudonymize the customer in historical audit logs
+type CustomerPurgedEvent struct {
+	EventType     string    `json:"eventType"`
+	TenantID      string    `json:"tenantId"`
+	Timestamp     time.Time `json:"timestamp"`
+	CustomerID    string    `json:"customerId"`
+	CustomerEmail string    `json:"customerEmail"`
+}
+
+// TenantQuotaExceededEvent is published when a tenant's usage of a metric exceeds its plan quota
+// It is sent once per metric and period (monthly metrics) or per breach (members, storage)
+type TenantQuotaExceededEvent struct {
+	EventType   string    `json:"event_type"`
+	TenantID    string    `json:"tenant_id"`
+	Metric      string    `json:"metric"`           // members, api_calls, storage, emails
+	Period      string    `json:"period,omitempty"` // YYYY-MM for monthly metrics
+	Used        int64     `json:"used"`
+	Limit       int64     `json:"limit"`
+	PricingTier string    `json:"pricing_tier"`
+	Enforced    bool      `json:"enforced"` // Whether tenant-service rejects further use
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// TenantPlanChangedEvent is published when a tenant's plan or trial changes or billing suspends
+// or reinstates it, so services caching feature flags can refresh them
+type TenantPlanChangedEvent struct {
+	EventType        string     `json:"event_type"`
+	TenantID         string     `json:"tenant_id"`
+	PlanCode         string     `json:"plan_code"`
+	PreviousPlanCode string     `json:"previous_plan_code,omitempty"`
+	PricingTier      string     `json:"pricing_tier"`
+	Status           string     `json:"status"` // trialing or active
+	Change           string     `json:"change"` // assigned, upgrade, downgrade, trial_started, trial_expired, suspended, reinstated, cancelled
+	TrialEndsAt      *time.Time `json:"trial_ends_at,omitempty"`
+	Timestamp        time.Time  `json:"timestamp"`
+}
+
+// TenantSlugChangedEvent is published when a tenant is renamed, so tenant-router-service
+// provisions routes for the new hosts. The old hosts keep routing until the redirect expires.
+type TenantSlugChangedEvent struct {
+	EventType         string    `json:"event_type"`
+	TenantID          string    `json:"tenant_id"`
+	OldSlug           string    `json:"old_slug"`
+	NewSlug           string    `json:"new_slug"`
+	AdminHost         string    `json:"admin_host"`      // e.g., "newstore-admin.tesserix.app"
+	StorefrontHost    string    `json:"storefront_host"` // e.g., "newstore.tesserix.app"
+	APIHost           string    `json:"api_host"`        // e.g., "newstore-api.tesserix.app"
+	BaseDomain        string    `json:"base_domain"`
+	RedirectExpiresAt time.Time `json:"redirect_expires_at"`
+	Timestamp         time.Time `json:"timestamp"`
+}
+
+// TenantSlugReleasedEvent is published when a renamed tenant's old slug stops redirecting,
+// so its routes can be removed and the slug claimed by other tenants
+type TenantSlugReleasedEvent struct {
+	EventType   string    `json:"event_type"`
+	TenantID    string    `json:"tenant_id"`
+	Slug        string    `json:"slug"`         // The released former slug
+	CurrentSlug string    `json:"current_slug"` // The slug the tenant now uses
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// DocumentUsageEvent is the part of a document-service event needed for storage metering
+type DocumentUsageEvent struct {
+	EventType  string `json:"eventType"`
+	TenantID   string `json:"tenantId"`
+	DocumentID string `json:"documentId"`
+	FileSize   int64  `json:"fileSize"`
+}
+
+// NotificationSentEvent is published by notification-service for every sent message
+type NotificationSentEvent struct {
+	EventType      string `json:"eventType"`
+	TenantID       string `json:"tenantId"`
+	NotificationID string `json:"notificationId"`
+	Channel        string `json:"channel"`
+	Units          int    `json:"units"`
+}
+
+// TenantMemberEvent is published when a user is linked to or invited into a tenant
+// Invited events carry the invitation token so notification-service can send the invite email
+type TenantMemberEvent struct {
+	EventType           string     `json:"eventType"`
+	TenantID            string     `json:"tenantId"`
+	Timestamp           time.Time  `json:"timestamp"`
+	UserID              string     `json:"userId,omitempty"`
+	Email               string     `json:"email"`
+	Name                string     `json:"name,omitempty"`
+	Role                string     `json:"role"`
+	InvitedBy           string     `json:"invitedBy"`
+	InvitationToken     string     `json:"invitationToken,omitempty"`
+	InvitationExpiresAt *time.Time `json:"invitationExpiresAt,omitempty"`
+	Source              string     `json:"source,omitempty"` // e.g. "bulk_import"
+}
+
 // Client wraps the NATS connection
 type Client struct {
 	conn *nats.Conn
@@ -299,6 +446,47 @@ func (c *Client) PublishTenantDeleted(ctx context.Context, event *TenantDeletedE
 	return nil
 }
 
+// PublishTenantDeletionRequested publishes a tenant deletion request with retry logic
+func (c *Client) PublishTenantDeletionRequested(ctx context.Context, event *TenantDeletionRequestedEvent) error {
+	if c == nil || c.js == nil {
+		return fmt.Errorf("NATS client not initialized")
+	}
+
+	event.EventType = EventTenantDeletionRequested
+	event.Timestamp = time.Now().UTC()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	// Publish with JetStream for guaranteed delivery with retry
+	var ack *nats.PubAck
+	maxRetries := 3
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		ack, err = c.js.Publish(EventTenantDeletionRequested, data)
+		if err == nil {
+			break
+		}
+		log.Printf("[NATS] Attempt %d/%d: Failed to publish %s event: %v", attempt, maxRetries, EventTenantDeletionRequested, err)
+		if attempt < maxRetries {
+			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("context cancelled while retrying publish: %w", ctx.Err())
+			case <-time.After(backoff):
+				continue
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to publish event after %d attempts: %w", maxRetries, err)
+	}
+
+	log.Printf("[NATS] Published %s event for tenant %s to %v (attempt %d, seq: %d)", EventTenantDeletionRequested, event.TenantID, event.Services, event.Attempt, ack.Sequence)
+	return nil
+}
+
 // PublishTenantVerificationRequested publishes a verification requested event
 // This triggers notification-service to send verification email
 func (c *Client) PublishTenantVerificationRequested(ctx context.Context, event *TenantVerificationRequestedEvent) error {
@@ -340,8 +528,75 @@ func (c *Client) PublishCustomerRegistered(ctx context.Context, event interface{
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
-	// Ensure CUSTOMER_EVENTS stream exists
-	_, err = c.js.AddStream(&nats.StreamConfig{
+	c.ensureCustomerEventsStream()
+
+	// Publish with JetStream for guaranteed delivery
+	ack, err := c.js.Publish(EventCustomerRegistered, data)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	log.Printf("[NATS] Published %s event (seq: %d)", EventCustomerRegistered, ack.Sequence)
+	return nil
+}
+
+// PublishCustomerPurged publishes a customer purged event
+// Consumers must remove or pseudonymize the customer's personal data
+func (c *Client) PublishCustomerPurged(ctx context.Context, event *CustomerPurgedEvent) error {
+	if c == nil || c.js == nil {
+		log.Printf("[NATS] Client not initialized, skipping customer.purged publish")
+		return nil
+	}
+
+	event.EventType = EventCustomerPurged
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	c.ensureCustomerEventsStream()
+
+	ack, err := c.js.Publish(EventCustomerPurged, data)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	log.Printf("[NATS] Published %s event for tenant %s (seq: %d)", EventCustomerPurged, event.TenantID, ack.Sequence)
+	return nil
+}
+
+// PublishTenantMemberEvent publishes a tenant.member.added or tenant.member.invited event
+func (c *Client) PublishTenantMemberEvent(ctx context.Context, event *TenantMemberEvent) error {
+	if c == nil || c.js == nil {
+		log.Printf("[NATS] Client not initialized, skipping %s publish", event.EventType)
+		return nil
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ack, err := c.js.Publish(event.EventType, data)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	log.Printf("[NATS] Published %s event for tenant %s (seq: %d)", event.EventType, event.TenantID, ack.Sequence)
+	return nil
+}
+
+// ensureCustomerEventsStream creates the CUSTOMER_EVENTS stream if it does not exist
+func (c *Client) ensureCustomerEventsStream() {
+	_, err := c.js.AddStream(&nats.StreamConfig{
 		Name:        "CUSTOMER_EVENTS",
 		Description: "Stream for customer lifecycle events",
 		Subjects:    []string{"customer.>"},
@@ -354,15 +609,6 @@ func (c *Client) PublishCustomerRegistered(ctx context.Context, event interface{
 	if err != nil && err != nats.ErrStreamNameAlreadyInUse {
 		log.Printf("[NATS] Warning: Could not create CUSTOMER_EVENTS stream: %v", err)
 	}
-
-	// Publish with JetStream for guaranteed delivery
-	ack, err := c.js.Publish(EventCustomerRegistered, data)
-	if err != nil {
-		return fmt.Errorf("failed to publish event: %w", err)
-	}
-
-	log.Printf("[NATS] Published %s event (seq: %d)", EventCustomerRegistered, ack.Sequence)
-	return nil
 }
 
 // Close closes the NATS connection
@@ -407,3 +653,255 @@ func (c *Client) SubscribeSessionCompleted(handler SessionCompletedHandler) erro
 	log.Printf("[NATS] Subscribed to %s events for SSE broadcasting", EventSessionCompleted)
 	return nil
 }
+
+// TenantDeletionAcknowledgedHandler is a callback for tenant deletion acknowledgments
+// Returning an error leaves the message unacknowledged so JetStream redelivers it
+type TenantDeletionAcknowledgedHandler func(event *TenantDeletionAcknowledgedEvent) error
+
+// SubscribeTenantDeletionAcknowledged subscribes to purge acknowledgments from participating services
+// Uses a durable queue consumer so acknowledgments sent while tenant-service is down are not lost
+func (c *Client) SubscribeTenantDeletionAcknowledged(handler TenantDeletionAcknowledgedHandler) error {
+	if c == nil || c.js == nil {
+		return fmt.Errorf("NATS client not initialized")
+	}
+
+	_, err := c.js.QueueSubscribe(
+		EventTenantDeletionAcknowledged,
+		"tenant-service-deletion-workers",
+		func(msg *nats.Msg) {
+			var event TenantDeletionAcknowledgedEvent
+			if err := json.Unmarshal(msg.Data, &event); err != nil {
+				log.Printf("[NATS] Failed to unmarshal tenant deletion acknowledgment: %v", err)
+				// Ack anyway to prevent infinite retries for malformed messages
+				msg.Ack()
+				return
+			}
+
+			log.Printf("[NATS] Received %s from %s for tenant %s (status: %s)", EventTenantDeletionAcknowledged, event.Service, event.TenantID, event.Status)
+			if err := handler(&event); err != nil {
+				log.Printf("[NATS] Failed to process tenant deletion acknowledgment: %v", err)
+				msg.Nak()
+				return
+			}
+			msg.Ack()
+		},
+		nats.Durable("tenant-service-deletion-acks"),
+		nats.ManualAck(),
+		nats.AckWait(30*time.Second),
+		nats.MaxDeliver(5),
+		nats.BindStream("TENANT_EVENTS"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to tenant deletion acknowledgments: %w", err)
+	}
+
+	log.Printf("[NATS] Subscribed to %s events", EventTenantDeletionAcknowledged)
+	return nil
+}
+
+// PublishMembershipCacheInvalidated tells other instances to drop cached lookups for the given keys
+// Uses core NATS: a lost message only leaves a lookup stale until its cache TTL expires
+func (c *Client) PublishMembershipCacheInvalidated(ctx context.Context, keys []string) error {
+	if c == nil || c.conn == nil || len(keys) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(&MembershipCacheInvalidatedEvent{
+		Keys:      keys,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := c.conn.Publish(SubjectMembershipCacheInvalidated, data); err != nil {
+		return fmt.Errorf("failed to publish cache invalidation: %w", err)
+	}
+	return nil
+}
+
+// MembershipCacheInvalidatedHandler is a callback for membership cache invalidations
+type MembershipCacheInvalidatedHandler func(event *MembershipCacheInvalidatedEvent)
+
+// SubscribeMembershipCacheInvalidated subscribes to cache invalidations from all instances
+// Every instance receives every message, including its own, since each holds its own in-memory cache
+func (c *Client) SubscribeMembershipCacheInvalidated(handler MembershipCacheInvalidatedHandler) error {
+	if c == nil || c.conn == nil {
+		return fmt.Errorf("NATS client not initialized")
+	}
+
+	_, err := c.conn.Subscribe(SubjectMembershipCacheInvalidated, func(msg *nats.Msg) {
+		var event MembershipCacheInvalidatedEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			log.Printf("[NATS] Failed to unmarshal cache invalidation: %v", err)
+			return
+		}
+		handler(&event)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to cache invalidations: %w", err)
+	}
+
+	log.Printf("[NATS] Subscribed to %s", SubjectMembershipCacheInvalidated)
+	return nil
+}
+
+// PublishTenantQuotaExceeded notifies billing and notification services of a quota breach
+func (c *Client) PublishTenantQuotaExceeded(ctx context.Context, event *TenantQuotaExceededEvent) error {
+	if c == nil || c.js == nil {
+		log.Printf("[NATS] Client not initialized, skipping %s publish", EventTenantQuotaExceeded)
+		return nil
+	}
+
+	event.EventType = EventTenantQuotaExceeded
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ack, err := c.js.Publish(EventTenantQuotaExceeded, data)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	log.Printf("[NATS] Published %s event for tenant %s (%s: %d/%d, seq: %d)", EventTenantQuotaExceeded, event.TenantID, event.Metric, event.Used, event.Limit, ack.Sequence)
+	return nil
+}
+
+// PublishTenantPlanChanged notifies other services of a plan change
+func (c *Client) PublishTenantPlanChanged(ctx context.Context, event *TenantPlanChangedEvent) error {
+	if c == nil || c.js == nil {
+		log.Printf("[NATS] Client not initialized, skipping %s publish", EventTenantPlanChanged)
+		return nil
+	}
+
+	event.EventType = EventTenantPlanChanged
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ack, err := c.js.Publish(EventTenantPlanChanged, data)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	log.Printf("[NATS] Published %s event for tenant %s (%s -> %s, seq: %d)", EventTenantPlanChanged, event.TenantID, event.PreviousPlanCode, event.PlanCode, ack.Sequence)
+	return nil
+}
+
+// PublishTenantSlugChanged notifies other services that a tenant was renamed
+func (c *Client) PublishTenantSlugChanged(ctx context.Context, event *TenantSlugChangedEvent) error {
+	if c == nil || c.js == nil {
+		log.Printf("[NATS] Client not initialized, skipping %s publish", EventTenantSlugChanged)
+		return nil
+	}
+
+	event.EventType = EventTenantSlugChanged
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ack, err := c.js.Publish(EventTenantSlugChanged, data)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	log.Printf("[NATS] Published %s event for tenant %s (%s -> %s, seq: %d)", EventTenantSlugChanged, event.TenantID, event.OldSlug, event.NewSlug, ack.Sequence)
+	return nil
+}
+
+// PublishTenantSlugReleased notifies other services that a former slug stopped redirecting
+func (c *Client) PublishTenantSlugReleased(ctx context.Context, event *TenantSlugReleasedEvent) error {
+	if c == nil || c.js == nil {
+		log.Printf("[NATS] Client not initialized, skipping %s publish", EventTenantSlugReleased)
+		return nil
+	}
+
+	event.EventType = EventTenantSlugReleased
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ack, err := c.js.Publish(EventTenantSlugReleased, data)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	log.Printf("[NATS] Published %s event for tenant %s (%s, seq: %d)", EventTenantSlugReleased, event.TenantID, event.Slug, ack.Sequence)
+	return nil
+}
+
+// DocumentUsageHandler is a callback for document usage events
+// Returning an error leaves the message unacknowledged so JetStream redelivers it
+type DocumentUsageHandler func(event *DocumentUsageEvent) error
+
+// SubscribeDocumentUsage subscribes to document-service upload and deletion events for storage metering
+func (c *Client) SubscribeDocumentUsage(handler DocumentUsageHandler) error {
+	return subscribeUsage(c, "document.>", "DOCUMENT_EVENTS", "tenant-service-usage-documents", handler)
+}
+
+// NotificationSentHandler is a callback for notification usage events
+// Returning an error leaves the message unacknowledged so JetStream redelivers it
+type NotificationSentHandler func(event *NotificationSentEvent) error
+
+// SubscribeNotificationSent subscribes to notification-service send events for email metering
+func (c *Client) SubscribeNotificationSent(handler NotificationSentHandler) error {
+	return subscribeUsage(c, SubjectNotificationSent, "USAGE_EVENTS", "tenant-service-usage-notifications", handler)
+}
+
+// subscribeUsage attaches a durable queue consumer to another service's stream
+// Malformed messages are acknowledged and dropped; handler errors are redelivered
+func subscribeUsage[T any](c *Client, subject, stream, durable string, handler func(event *T) error) error {
+	if c == nil || c.js == nil {
+		return fmt.Errorf("NATS client not initialized")
+	}
+
+	_, err := c.js.QueueSubscribe(
+		subject,
+		durable,
+		func(msg *nats.Msg) {
+			var event T
+			if err := json.Unmarshal(msg.Data, &event); err != nil {
+				log.Printf("[NATS] Failed to unmarshal %s usage event: %v", msg.Subject, err)
+				msg.Ack()
+				return
+			}
+			if err := handler(&event); err != nil {
+				log.Printf("[NATS] Failed to meter %s usage event: %v", msg.Subject, err)
+				msg.Nak()
+				return
+			}
+			msg.Ack()
+		},
+		nats.Durable(durable),
+		nats.ManualAck(),
+		nats.AckWait(30*time.Second),
+		nats.MaxDeliver(5),
+		nats.BindStream(stream),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+
+	log.Printf("[NATS] Subscribed to %s usage events", subject)
+	return nil
+}
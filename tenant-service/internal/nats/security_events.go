@@ -0,0 +1,143 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+)
+
+// Security event types, published on the SECURITY_EVENTS stream ("security.>")
+const (
+	EventSecurityLoginFailed    = "security.login_failed"
+	EventSecurityAccountLocked  = "security.account_locked"
+	EventSecurityPasswordReset  = "security.password_reset"
+	EventSecurityRoleEscalation = "security.role_escalation"
+	EventSecurityImpersonation  = "security.impersonation"
+)
+
+// SecurityEventSchemaVersion is the version of the SecurityEvent schema. Fields are only ever
+// added within a version; consumers must ignore fields they do not know.
+const SecurityEventSchemaVersion = 1
+
+// Security event severities
+const (
+	SecuritySeverityLow      = "low"
+	SecuritySeverityMedium   = "medium"
+	SecuritySeverityHigh     = "high"
+	SecuritySeverityCritical = "critical"
+)
+
+// Security event outcomes
+const (
+	SecurityOutcomeSuccess = "success"
+	SecurityOutcomeFailure = "failure"
+)
+
+// securityEventSeverities maps each security event type to its default severity
+var securityEventSeverities = map[string]string{
+	EventSecurityLoginFailed:    SecuritySeverityMedium,
+	EventSecurityAccountLocked:  SecuritySeverityHigh,
+	EventSecurityPasswordReset:  SecuritySeverityMedium,
+	EventSecurityRoleEscalation: SecuritySeverityHigh,
+	EventSecurityImpersonation:  SecuritySeverityCritical,
+}
+
+// IsSecurityEventType reports whether eventType is a known security event type
+func IsSecurityEventType(eventType string) bool {
+	_, ok := securityEventSeverities[eventType]
+	return ok
+}
+
+// IsSecuritySeverity reports whether severity is a known security event severity
+func IsSecuritySeverity(severity string) bool {
+	switch severity {
+	case SecuritySeverityLow, SecuritySeverityMedium, SecuritySeverityHigh, SecuritySeverityCritical:
+		return true
+	}
+	return false
+}
+
+// SecurityEvent is the standardized payload of every security.* event. The field names follow
+// the audit-service event conventions so it can record and alert on them without a mapping.
+type SecurityEvent struct {
+	EventType     string                 `json:"eventType"`
+	SchemaVersion int                    `json:"schemaVersion"`
+	EventID       string                 `json:"eventId"`
+	TenantID      string                 `json:"tenantId"`
+	Severity      string                 `json:"severity"`          // low, medium, high or critical
+	Outcome       string                 `json:"outcome"`           // success or failure
+	ActorID       string                 `json:"actorId,omitempty"` // User performing the action, if known
+	ActorEmail    string                 `json:"actorEmail,omitempty"`
+	TargetUserID  string                 `json:"targetUserId,omitempty"` // User the action applies to
+	TargetEmail   string                 `json:"targetEmail,omitempty"`
+	IPAddress     string                 `json:"ipAddress,omitempty"`
+	UserAgent     string                 `json:"userAgent,omitempty"`
+	Reason        string                 `json:"reason,omitempty"`
+	Synthetic     bool                   `json:"synthetic"` // Injected to test the alert pipeline, not a real event
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Timestamp     time.Time              `json:"timestamp"`
+}
+
+// PublishSecurityEvent publishes a security event, filling in the schema version, event ID,
+// default severity and timestamp
+func (c *Client) PublishSecurityEvent(ctx context.Context, event *SecurityEvent) error {
+	if c == nil || c.js == nil {
+		log.Printf("[NATS] Client not initialized, skipping %s publish", event.EventType)
+		return nil
+	}
+
+	severity, ok := securityEventSeverities[event.EventType]
+	if !ok {
+		return fmt.Errorf("unknown security event type %q", event.EventType)
+	}
+	event.SchemaVersion = SecurityEventSchemaVersion
+	if event.EventID == "" {
+		event.EventID = uuid.New().String()
+	}
+	if event.Severity == "" {
+		event.Severity = severity
+	}
+	if event.Outcome == "" {
+		event.Outcome = SecurityOutcomeSuccess
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	c.ensureSecurityEventsStream()
+
+	ack, err := c.js.Publish(event.EventType, data)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	log.Printf("[NATS] Published %s event for tenant %s (seq: %d, synthetic: %v)", event.EventType, event.TenantID, ack.Sequence, event.Synthetic)
+	return nil
+}
+
+// ensureSecurityEventsStream creates the SECURITY_EVENTS stream if it does not exist
+func (c *Client) ensureSecurityEventsStream() {
+	_, err := c.js.AddStream(&nats.StreamConfig{
+		Name:        "SECURITY_EVENTS",
+		Description: "Stream for security events consumed by audit alerting",
+		Subjects:    []string{"security.>"},
+		Storage:     nats.FileStorage,
+		Retention:   nats.LimitsPolicy,
+		MaxAge:      24 * time.Hour * 30, // 30 days, long enough for incident review
+		MaxMsgs:     1000000,
+		Discard:     nats.DiscardOld,
+	})
+	if err != nil && err != nats.ErrStreamNameAlreadyInUse {
+		log.Printf("[NATS] Warning: Could not create SECURITY_EVENTS stream: %v", err)
+	}
+}
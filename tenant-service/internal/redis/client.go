@@ -4,31 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
-	"tenant-service/internal/config"
+	"tenant-service/internal/redisconn"
 )
 
-// Client wraps the Redis client with application-specific methods
+// Client wraps the Redis client with application-specific methods.
+// Multi-key reads, deletes and scans go through getMany, deleteKeys and scanKeys so they
+// also work when REDIS_MODE=cluster spreads the keys over several nodes.
 type Client struct {
-	rdb *redis.Client
+	rdb *redisconn.Client
 }
 
 // NewClient creates a new Redis client
-func NewClient(cfg config.RedisConfig) (*Client, error) {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
-		Password: cfg.Password,
-		DB:       cfg.DB,
-	})
-
+func NewClient(cfg redisconn.Config) (*Client, error) {
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := rdb.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	rdb, err := redisconn.Connect(ctx, cfg, "tenant-service")
+	if err != nil {
+		return nil, err
 	}
 
 	return &Client{rdb: rdb}, nil
@@ -44,6 +42,65 @@ func (c *Client) Ping(ctx context.Context) error {
 	return c.rdb.Ping(ctx).Err()
 }
 
+// Check pings every Redis node holding keys and records the result in the Redis metrics
+func (c *Client) Check(ctx context.Context) error {
+	return c.rdb.Check(ctx)
+}
+
+// getMany reads keys with pipelined GETs, which unlike MGET works when the keys hash to
+// different cluster slots. Missing keys are nil, as with MGET.
+func (c *Client) getMany(ctx context.Context, keys []string) ([]interface{}, error) {
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, cmd := range cmds {
+		value, err := cmd.Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// deleteKeys deletes keys with pipelined DELs, which unlike a multi-key DEL works when the
+// keys hash to different cluster slots
+func (c *Client) deleteKeys(ctx context.Context, keys ...string) error {
+	pipe := c.rdb.Pipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, key)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// scanKeys returns the keys matching pattern on every node holding keys
+func (c *Client) scanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var mu sync.Mutex
+	var keys []string
+
+	err := c.rdb.ForEachShard(ctx, func(ctx context.Context, shard *redis.Client) error {
+		iter := shard.Scan(ctx, 0, pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			mu.Lock()
+			keys = append(keys, iter.Val())
+			mu.Unlock()
+		}
+		return iter.Err()
+	})
+	return keys, err
+}
+
 // Key prefixes
 const (
 	DraftKeyPrefix           = "draft:"
@@ -191,21 +248,9 @@ func (c *Client) UpdateReminderStatus(ctx context.Context, status *ReminderStatu
 
 // GetAllDraftKeys returns all draft keys (for cleanup job)
 func (c *Client) GetAllDraftKeys(ctx context.Context) ([]string, error) {
-	pattern := DraftKeyPrefix + "*"
-	var cursor uint64
-	var keys []string
-
-	for {
-		var batch []string
-		var err error
-		batch, cursor, err = c.rdb.Scan(ctx, cursor, pattern, 100).Result()
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan keys: %w", err)
-		}
-		keys = append(keys, batch...)
-		if cursor == 0 {
-			break
-		}
+	keys, err := c.scanKeys(ctx, DraftKeyPrefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan keys: %w", err)
 	}
 
 	return keys, nil
@@ -369,9 +414,12 @@ func (c *Client) GetVerificationTokenBySession(ctx context.Context, sessionID, e
 	pattern := fmt.Sprintf("verification:token:*:%s:%s", sessionID, email)
 	var token string
 
-	iter := c.rdb.Scan(ctx, 0, pattern, 100).Iterator()
-	for iter.Next(ctx) {
-		key := iter.Val()
+	keys, err := c.scanKeys(ctx, pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to scan for verification token: %w", err)
+	}
+
+	for _, key := range keys {
 		// Extract token from key: verification:token:{token}:{sessionID}:{email}
 		parts := make([]string, 0)
 		current := ""
@@ -392,10 +440,6 @@ func (c *Client) GetVerificationTokenBySession(ctx context.Context, sessionID, e
 		}
 	}
 
-	if err := iter.Err(); err != nil {
-		return "", fmt.Errorf("failed to scan for verification token: %w", err)
-	}
-
 	if token == "" {
 		return "", fmt.Errorf("verification token not found for session %s and email %s", sessionID, email)
 	}
@@ -425,3 +469,259 @@ func (c *Client) GetCustomDomainGatewayIP(ctx context.Context) (string, error) {
 	}
 	return ip, nil
 }
+
+// TenantSummaryPrefix caches tenant summaries for internal bulk lookups, keyed by ID or "slug:<slug>"
+const TenantSummaryPrefix = "tenant:summary:"
+
+// GetTenantSummaries returns cached summary JSON for the given lookup keys
+// Keys that are not cached are absent from the returned map
+func (c *Client) GetTenantSummaries(ctx context.Context, keys []string) (map[string][]byte, error) {
+	if len(keys) == 0 {
+		return map[string][]byte{}, nil
+	}
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = TenantSummaryPrefix + key
+	}
+
+	values, err := c.getMany(ctx, redisKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant summaries: %w", err)
+	}
+
+	found := make(map[string][]byte, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			found[keys[i]] = []byte(s)
+		}
+	}
+	return found, nil
+}
+
+// SaveTenantSummaries caches summary JSON for the given lookup keys
+func (c *Client) SaveTenantSummaries(ctx context.Context, summaries map[string][]byte, ttl time.Duration) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	pipe := c.rdb.Pipeline()
+	for key, data := range summaries {
+		pipe.Set(ctx, TenantSummaryPrefix+key, data, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save tenant summaries: %w", err)
+	}
+	return nil
+}
+
+// MaintenancePrefix caches maintenance flags, keyed by scope ("platform" or "tenant:<id>")
+const MaintenancePrefix = "maintenance:"
+
+// GetMaintenanceFlags returns cached flag JSON for the given scope keys
+// Keys that are not cached are absent from the returned map
+func (c *Client) GetMaintenanceFlags(ctx context.Context, keys []string) (map[string][]byte, error) {
+	if len(keys) == 0 {
+		return map[string][]byte{}, nil
+	}
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = MaintenancePrefix + key
+	}
+
+	values, err := c.getMany(ctx, redisKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance flags: %w", err)
+	}
+
+	found := make(map[string][]byte, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			found[keys[i]] = []byte(s)
+		}
+	}
+	return found, nil
+}
+
+// SaveMaintenanceFlag caches flag JSON (or an "off" marker) for a scope key
+func (c *Client) SaveMaintenanceFlag(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	if err := c.rdb.Set(ctx, MaintenancePrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save maintenance flag: %w", err)
+	}
+	return nil
+}
+
+// MembershipCachePrefix caches tenant and membership lookups, keyed by "slug:<slug>" or "user:<id>"
+const MembershipCachePrefix = "membership:cache:"
+
+// GetMembershipCacheEntry returns a cached lookup, or nil if it is not cached
+func (c *Client) GetMembershipCacheEntry(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.rdb.Get(ctx, MembershipCachePrefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get membership cache entry: %w", err)
+	}
+	return data, nil
+}
+
+// SaveMembershipCacheEntry caches a lookup for a key
+func (c *Client) SaveMembershipCacheEntry(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	if err := c.rdb.Set(ctx, MembershipCachePrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save membership cache entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteMembershipCacheEntries removes cached lookups for the given keys
+func (c *Client) DeleteMembershipCacheEntries(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = MembershipCachePrefix + key
+	}
+
+	if err := c.deleteKeys(ctx, redisKeys...); err != nil {
+		return fmt.Errorf("failed to delete membership cache entries: %w", err)
+	}
+	return nil
+}
+
+// Auth session registry keys: one JSON value per session plus a set of session IDs per tenant user
+const (
+	AuthSessionPrefix  = "auth:session:"
+	AuthSessionsPrefix = "auth:sessions:" // auth:sessions:<tenant_id>:<subject>
+)
+
+func authSessionsKey(tenantID, subject string) string {
+	return AuthSessionsPrefix + tenantID + ":" + subject
+}
+
+// SaveAuthSession caches session JSON and adds it to the owner's session set
+func (c *Client) SaveAuthSession(ctx context.Context, tenantID, subject, sessionID string, data []byte, ttl time.Duration) error {
+	setKey := authSessionsKey(tenantID, subject)
+
+	pipe := c.rdb.TxPipeline()
+	pipe.Set(ctx, AuthSessionPrefix+sessionID, data, ttl)
+	pipe.SAdd(ctx, setKey, sessionID)
+	// The set lives as long as the newest session; expired members are pruned on read
+	pipe.ExpireGT(ctx, setKey, ttl)
+	pipe.ExpireNX(ctx, setKey, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save auth session: %w", err)
+	}
+	return nil
+}
+
+// GetAuthSessions returns cached session JSON keyed by session ID for a tenant user.
+// found is false when the owner has no session set in Redis, so the caller should fall back to the database.
+func (c *Client) GetAuthSessions(ctx context.Context, tenantID, subject string) (sessions map[string][]byte, found bool, err error) {
+	setKey := authSessionsKey(tenantID, subject)
+
+	ids, err := c.rdb.SMembers(ctx, setKey).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get auth sessions: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, false, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = AuthSessionPrefix + id
+	}
+	values, err := c.getMany(ctx, keys)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get auth sessions: %w", err)
+	}
+
+	sessions = make(map[string][]byte, len(values))
+	var expired []interface{}
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			sessions[ids[i]] = []byte(s)
+		} else {
+			expired = append(expired, ids[i])
+		}
+	}
+	if len(expired) > 0 {
+		c.rdb.SRem(ctx, setKey, expired...)
+	}
+	return sessions, true, nil
+}
+
+// DeleteAuthSession removes a session from the cache
+func (c *Client) DeleteAuthSession(ctx context.Context, tenantID, subject, sessionID string) error {
+	pipe := c.rdb.TxPipeline()
+	pipe.Del(ctx, AuthSessionPrefix+sessionID)
+	pipe.SRem(ctx, authSessionsKey(tenantID, subject), sessionID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete auth session: %w", err)
+	}
+	return nil
+}
+
+// Login lockout keys: attempt state per tenant+email+IP, and an index of those keys per tenant+email
+// so an admin unlock can clear every IP's counter
+const (
+	LoginLockoutPrefix      = "auth:lockout:tenant:"
+	LoginLockoutIndexPrefix = "auth:lockout:tenant:index:"
+)
+
+// GetLoginLockout returns the attempt state stored under key, or nil if there is none
+func (c *Client) GetLoginLockout(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.rdb.Get(ctx, LoginLockoutPrefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login lockout: %w", err)
+	}
+	return data, nil
+}
+
+// SaveLoginLockout stores attempt state and records key in the index
+func (c *Client) SaveLoginLockout(ctx context.Context, indexKey, key string, data []byte, ttl time.Duration) error {
+	pipe := c.rdb.TxPipeline()
+	pipe.Set(ctx, LoginLockoutPrefix+key, data, ttl)
+	pipe.SAdd(ctx, LoginLockoutIndexPrefix+indexKey, key)
+	pipe.Expire(ctx, LoginLockoutIndexPrefix+indexKey, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save login lockout: %w", err)
+	}
+	return nil
+}
+
+// DeleteLoginLockout removes the attempt state stored under key
+func (c *Client) DeleteLoginLockout(ctx context.Context, indexKey, key string) error {
+	pipe := c.rdb.TxPipeline()
+	pipe.Del(ctx, LoginLockoutPrefix+key)
+	pipe.SRem(ctx, LoginLockoutIndexPrefix+indexKey, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete login lockout: %w", err)
+	}
+	return nil
+}
+
+// ClearLoginLockouts removes every attempt state recorded in the index and returns how many were removed
+func (c *Client) ClearLoginLockouts(ctx context.Context, indexKey string) (int, error) {
+	keys, err := c.rdb.SMembers(ctx, LoginLockoutIndexPrefix+indexKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get login lockouts: %w", err)
+	}
+
+	toDelete := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		toDelete = append(toDelete, LoginLockoutPrefix+key)
+	}
+	toDelete = append(toDelete, LoginLockoutIndexPrefix+indexKey)
+	if err := c.deleteKeys(ctx, toDelete...); err != nil {
+		return 0, fmt.Errorf("failed to clear login lockouts: %w", err)
+	}
+	return len(keys), nil
+}
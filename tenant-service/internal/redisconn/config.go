@@ -0,0 +1,151 @@
+// Package redisconn builds go-redis clients for standalone, Sentinel and Cluster
+// deployments from a single configuration, with TLS, a health check and Prometheus
+// metrics, so every service reaches Redis the same way.
+//
+// ConfigFromEnv reads:
+//
+//	REDIS_URL                       redis:// or rediss:// URL; wins over the address settings below
+//	REDIS_MODE                      standalone (default), sentinel or cluster
+//	REDIS_ADDRS                     comma-separated host:port list (sentinels or cluster seed nodes)
+//	REDIS_HOST, REDIS_PORT          single address when REDIS_ADDRS is not set (port defaults to 6379)
+//	REDIS_SENTINEL_MASTER           Sentinel master name (sentinel mode)
+//	REDIS_SENTINEL_PASSWORD         password of the sentinels themselves, if different
+//	REDIS_USERNAME, REDIS_PASSWORD  ACL user and password
+//	REDIS_DB                        database number (ignored in cluster mode)
+//	REDIS_TLS                       use TLS (implied by a rediss:// URL)
+//	REDIS_TLS_SERVER_NAME           server name to verify, when it differs from the host
+//	REDIS_TLS_CA_FILE               PEM bundle of extra CAs to trust
+//	REDIS_TLS_INSECURE_SKIP_VERIFY  skip certificate verification (development only)
+//	REDIS_POOL_SIZE                 connections per node (default: go-redis default)
+//	REDIS_DIAL_TIMEOUT, REDIS_READ_TIMEOUT, REDIS_WRITE_TIMEOUT  Go durations, e.g. 500ms
+//
+// The package only depends on go-redis, the Prometheus client and the standard
+// library so it can move to go-shared unchanged. Until it is published there,
+// tenant-service, settings-service and notification-hub carry identical copies;
+// change all three together.
+package redisconn
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mode is how the Redis deployment is reached
+type Mode string
+
+const (
+	// ModeStandalone talks to a single Redis server
+	ModeStandalone Mode = "standalone"
+	// ModeSentinel discovers the current master through Redis Sentinel
+	ModeSentinel Mode = "sentinel"
+	// ModeCluster shards keys across a Redis Cluster
+	ModeCluster Mode = "cluster"
+)
+
+// Config describes how to reach Redis. Zero values leave the go-redis defaults in place.
+type Config struct {
+	URL              string   // redis:// or rediss:// URL; in sentinel and cluster mode further nodes go in ?addr=
+	Mode             Mode     // Defaults to standalone
+	Addrs            []string // host:port of the server, the sentinels or the cluster seed nodes
+	MasterName       string   // Sentinel master name
+	Username         string
+	Password         string
+	SentinelPassword string // Password of the sentinels, when they require a different one
+	DB               int    // Database number; cluster mode only has 0
+	TLS              TLSConfig
+	PoolSize         int
+	DialTimeout      time.Duration
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+}
+
+// TLSConfig enables TLS towards Redis
+type TLSConfig struct {
+	Enabled            bool
+	ServerName         string // Name to verify when it differs from the dialed host
+	CAFile             string // PEM bundle of extra CAs to trust
+	InsecureSkipVerify bool   // Development only
+}
+
+// Enabled reports whether an address or URL is configured
+func (c Config) Enabled() bool {
+	return c.URL != "" || len(c.Addrs) > 0
+}
+
+// ConfigFromEnv overlays the REDIS_* environment variables on defaults, which carries the
+// service's own default address and timeouts. Malformed numbers and durations keep the default.
+func ConfigFromEnv(defaults Config) Config {
+	cfg := defaults
+
+	cfg.URL = envString("REDIS_URL", cfg.URL)
+	cfg.Mode = Mode(strings.ToLower(envString("REDIS_MODE", string(cfg.Mode))))
+
+	if addrs := splitAddrs(os.Getenv("REDIS_ADDRS")); len(addrs) > 0 {
+		cfg.Addrs = addrs
+	} else if host := os.Getenv("REDIS_HOST"); host != "" {
+		cfg.Addrs = []string{net.JoinHostPort(host, envString("REDIS_PORT", "6379"))}
+	} else if port := os.Getenv("REDIS_PORT"); port != "" && len(cfg.Addrs) == 1 {
+		// REDIS_PORT alone moves the default host to another port
+		if host, _, err := net.SplitHostPort(cfg.Addrs[0]); err == nil {
+			cfg.Addrs = []string{net.JoinHostPort(host, port)}
+		}
+	}
+
+	cfg.MasterName = envString("REDIS_SENTINEL_MASTER", cfg.MasterName)
+	cfg.SentinelPassword = envString("REDIS_SENTINEL_PASSWORD", cfg.SentinelPassword)
+	cfg.Username = envString("REDIS_USERNAME", cfg.Username)
+	cfg.Password = envString("REDIS_PASSWORD", cfg.Password)
+	cfg.DB = envInt("REDIS_DB", cfg.DB)
+
+	cfg.TLS.Enabled = envBool("REDIS_TLS", cfg.TLS.Enabled)
+	cfg.TLS.ServerName = envString("REDIS_TLS_SERVER_NAME", cfg.TLS.ServerName)
+	cfg.TLS.CAFile = envString("REDIS_TLS_CA_FILE", cfg.TLS.CAFile)
+	cfg.TLS.InsecureSkipVerify = envBool("REDIS_TLS_INSECURE_SKIP_VERIFY", cfg.TLS.InsecureSkipVerify)
+
+	cfg.PoolSize = envInt("REDIS_POOL_SIZE", cfg.PoolSize)
+	cfg.DialTimeout = envDuration("REDIS_DIAL_TIMEOUT", cfg.DialTimeout)
+	cfg.ReadTimeout = envDuration("REDIS_READ_TIMEOUT", cfg.ReadTimeout)
+	cfg.WriteTimeout = envDuration("REDIS_WRITE_TIMEOUT", cfg.WriteTimeout)
+	return cfg
+}
+
+func splitAddrs(value string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(value, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func envString(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func envInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func envBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func envDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -164,8 +164,25 @@ func (r *CredentialRepository) UpdatePassword(ctx context.Context, userID, tenan
 	return nil
 }
 
+// RecordPasswordChange restarts the rotation clock after a password is set outside this table (Keycloak)
+func (r *CredentialRepository) RecordPasswordChange(ctx context.Context, userID, tenantID uuid.UUID, changedAt time.Time, expiresAt *time.Time) error {
+	if err := r.db.WithContext(ctx).
+		Model(&models.TenantCredential{}).
+		Where("user_id = ? AND tenant_id = ?", userID, tenantID).
+		Updates(map[string]interface{}{
+			"last_password_change_at":    changedAt,
+			"password_expires_at":        expiresAt,
+			"password_rotation_required": false,
+			"updated_at":                 changedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to record password change: %w", err)
+	}
+	return nil
+}
+
 // RecordLoginAttempt records a login attempt and handles progressive lockout logic
-// Time-based progressive lockout (NO permanent locks):
+// Time-based progressive lockout (NO permanent locks), defaults shown; tenants can override
+// the attempts and tier durations in their auth policy:
 // - Tier 1 (5 attempts): 10 minutes lockout
 // - Tier 2 (10 attempts): 1 hour lockout
 // - Tier 3 (15 attempts): 6 hours lockout
@@ -190,28 +207,8 @@ func (r *CredentialRepository) RecordLoginAttempt(ctx context.Context, userID, t
 		return err
 	}
 
-	// Default policy values - time-based progressive system (NO permanent locks)
-	maxAttempts := 5         // First lockout at 5 attempts
-	enableProgressive := true
-	tier1Minutes := 10       // 10 minute lockout (Tier 1)
-	tier2Minutes := 60       // 1 hour lockout (Tier 2)
-	tier3Minutes := 360      // 6 hour lockout (Tier 3)
-	tier4Minutes := 1440     // 24 hour lockout (Tier 4 - max, NOT permanent)
-	tier2Threshold := 10     // Tier 2 at 10 attempts
-	tier3Threshold := 15     // Tier 3 at 15 attempts
-	tier4Threshold := 20     // Tier 4 at 20+ attempts
-	lockoutResetHours := 48  // Reset counters after 48 hours of no failures
-
-	if policy != nil {
-		maxAttempts = policy.MaxLoginAttempts
-		enableProgressive = policy.EnableProgressiveLockout
-		if policy.Tier1LockoutMinutes > 0 {
-			tier1Minutes = policy.Tier1LockoutMinutes
-		}
-		if policy.LockoutResetHours > 0 {
-			lockoutResetHours = policy.LockoutResetHours
-		}
-	}
+	// Thresholds come from the tenant's policy, falling back to the defaults (NO permanent locks)
+	settings := policy.LockoutSettings()
 
 	now := time.Now()
 	updates := map[string]interface{}{
@@ -233,7 +230,7 @@ func (r *CredentialRepository) RecordLoginAttempt(ctx context.Context, userID, t
 		totalFailed := credential.TotalFailedAttempts
 		if credential.LastLoginAttemptAt != nil {
 			hoursSinceLastFailure := now.Sub(*credential.LastLoginAttemptAt).Hours()
-			if hoursSinceLastFailure >= float64(lockoutResetHours) {
+			if hoursSinceLastFailure >= float64(settings.ResetHours) {
 				// Reset progressive tracking after configured hours of inactivity
 				totalFailed = 0
 				updates["lockout_count"] = 0
@@ -249,40 +246,15 @@ func (r *CredentialRepository) RecordLoginAttempt(ctx context.Context, userID, t
 		updates["last_login_attempt_at"] = now
 
 		// Time-based progressive lockout (NO permanent locks - all lockouts auto-unlock)
-		if newAttempts >= maxAttempts {
+		if newAttempts >= settings.MaxAttempts {
 			lockoutCount := credential.LockoutCount + 1
 			updates["lockout_count"] = lockoutCount
 
-			// Determine lockout duration based on total failed attempts across sessions
-			var lockoutMinutes int
-			var tier int
-
-			if enableProgressive {
-				if totalFailed >= tier4Threshold {
-					// Tier 4: Maximum lockout (24 hours) - still auto-unlocks
-					lockoutMinutes = tier4Minutes
-					tier = 4
-				} else if totalFailed >= tier3Threshold {
-					// Tier 3: 6 hour lockout
-					lockoutMinutes = tier3Minutes
-					tier = 3
-				} else if totalFailed >= tier2Threshold {
-					// Tier 2: 1 hour lockout
-					lockoutMinutes = tier2Minutes
-					tier = 2
-				} else {
-					// Tier 1: 10 minute lockout
-					lockoutMinutes = tier1Minutes
-					tier = 1
-				}
-			} else {
-				// Progressive disabled - use tier 1 lockout
-				lockoutMinutes = tier1Minutes
-				tier = 1
-			}
+			// Tier is based on total failed attempts across sessions (tier n at n x max attempts)
+			tier, lockoutDuration := settings.LockoutFor(totalFailed)
 
 			updates["current_tier"] = tier
-			lockedUntil := now.Add(time.Duration(lockoutMinutes) * time.Minute)
+			lockedUntil := now.Add(lockoutDuration)
 			updates["locked_until"] = lockedUntil
 			// Never set permanently_locked = true - all lockouts are time-based
 		}
@@ -340,10 +312,7 @@ func (r *CredentialRepository) GetLockoutStatus(ctx context.Context, userID, ten
 		return nil, err
 	}
 
-	maxAttempts := 5
-	if policy != nil {
-		maxAttempts = policy.MaxLoginAttempts
-	}
+	maxAttempts := policy.LockoutSettings().MaxAttempts
 
 	status := &LockoutStatus{
 		CurrentTier:         credential.CurrentTier,
@@ -567,12 +536,14 @@ func (r *CredentialRepository) GetUserAuthAuditLogs(ctx context.Context, tenantI
 // ============================================================================
 
 // EnableMFA enables MFA for a tenant credential
-func (r *CredentialRepository) EnableMFA(ctx context.Context, userID, tenantID uuid.UUID, mfaType, mfaSecret string) error {
+// mfaSecret must already be encrypted with the tenant key identified by keyVersion
+func (r *CredentialRepository) EnableMFA(ctx context.Context, userID, tenantID uuid.UUID, mfaType, mfaSecret string, keyVersion int) error {
 	now := time.Now()
 	updates := map[string]interface{}{
 		"mfa_enabled": true,
 		"mfa_type":    mfaType,
-		"mfa_secret":  mfaSecret, // Should be encrypted before storing
+		"mfa_secret":  mfaSecret,
+		"key_version": keyVersion,
 		"updated_at":  now,
 	}
 
@@ -594,6 +565,7 @@ func (r *CredentialRepository) DisableMFA(ctx context.Context, userID, tenantID
 		"mfa_type":         nil,
 		"mfa_secret":       nil,
 		"mfa_backup_codes": "[]",
+		"key_version":      0,
 		"updated_at":       now,
 	}
 
@@ -607,19 +579,100 @@ func (r *CredentialRepository) DisableMFA(ctx context.Context, userID, tenantID
 	return nil
 }
 
-// GetMFASecret retrieves the MFA secret for TOTP verification
-func (r *CredentialRepository) GetMFASecret(ctx context.Context, userID, tenantID uuid.UUID) (string, error) {
+// GetMFASecret retrieves the stored (encrypted) MFA secret and the key version protecting it
+func (r *CredentialRepository) GetMFASecret(ctx context.Context, userID, tenantID uuid.UUID) (string, int, error) {
 	var credential models.TenantCredential
 	if err := r.db.WithContext(ctx).
-		Select("mfa_secret").
+		Select("mfa_secret", "key_version").
 		Where("user_id = ? AND tenant_id = ? AND mfa_enabled = ?", userID, tenantID, true).
 		First(&credential).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return "", fmt.Errorf("MFA not enabled")
+			return "", 0, fmt.Errorf("MFA not enabled")
 		}
-		return "", fmt.Errorf("failed to get MFA secret: %w", err)
+		return "", 0, fmt.Errorf("failed to get MFA secret: %w", err)
+	}
+	return credential.MFASecret, credential.KeyVersion, nil
+}
+
+// SavePendingMFASecret stores a new MFA secret awaiting confirmation; MFA stays disabled until ActivateMFA
+// mfaSecret must already be encrypted with the tenant key identified by keyVersion
+func (r *CredentialRepository) SavePendingMFASecret(ctx context.Context, userID, tenantID uuid.UUID, mfaType, mfaSecret string, keyVersion int) error {
+	updates := map[string]interface{}{
+		"mfa_enabled":      false,
+		"mfa_type":         mfaType,
+		"mfa_secret":       mfaSecret,
+		"mfa_backup_codes": "[]",
+		"mfa_last_used_at": nil,
+		"key_version":      keyVersion,
+		"updated_at":       time.Now(),
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&models.TenantCredential{}).
+		Where("user_id = ? AND tenant_id = ? AND mfa_enabled = ?", userID, tenantID, false).
+		Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to save pending MFA secret: %w", err)
+	}
+	return nil
+}
+
+// ActivateMFA enables a pending MFA secret and stores the hashed recovery codes
+func (r *CredentialRepository) ActivateMFA(ctx context.Context, userID, tenantID uuid.UUID, backupCodes models.JSONB, usedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.TenantCredential{}).
+		Where("user_id = ? AND tenant_id = ? AND mfa_enabled = ? AND mfa_secret IS NOT NULL AND mfa_secret <> ''", userID, tenantID, false).
+		Updates(map[string]interface{}{
+			"mfa_enabled":      true,
+			"mfa_backup_codes": backupCodes,
+			"mfa_last_used_at": usedAt,
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to activate MFA: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SetMFABackupCodes replaces the hashed recovery codes of an MFA-enabled credential
+func (r *CredentialRepository) SetMFABackupCodes(ctx context.Context, userID, tenantID uuid.UUID, backupCodes models.JSONB) error {
+	if err := r.db.WithContext(ctx).
+		Model(&models.TenantCredential{}).
+		Where("user_id = ? AND tenant_id = ? AND mfa_enabled = ?", userID, tenantID, true).
+		Updates(map[string]interface{}{
+			"mfa_backup_codes": backupCodes,
+			"updated_at":       time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to store MFA recovery codes: %w", err)
+	}
+	return nil
+}
+
+// ConsumeMFABackupCode atomically removes a hashed recovery code; false means it was not (or no longer) valid
+func (r *CredentialRepository) ConsumeMFABackupCode(ctx context.Context, userID, tenantID uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.TenantCredential{}).
+		Where("user_id = ? AND tenant_id = ? AND mfa_enabled = ? AND jsonb_exists(mfa_backup_codes, ?)", userID, tenantID, true, codeHash).
+		Updates(map[string]interface{}{
+			"mfa_backup_codes": gorm.Expr("mfa_backup_codes - ?::text", codeHash),
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to consume MFA recovery code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimMFATimeStep records a TOTP time step as used so the same code cannot be replayed.
+// mfa_last_used_at holds the start of the last accepted step; false means the step was already used.
+func (r *CredentialRepository) ClaimMFATimeStep(ctx context.Context, userID, tenantID uuid.UUID, stepStart time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.TenantCredential{}).
+		Where("user_id = ? AND tenant_id = ? AND mfa_enabled = ? AND (mfa_last_used_at IS NULL OR mfa_last_used_at < ?)", userID, tenantID, true, stepStart).
+		Update("mfa_last_used_at", stepStart)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record MFA usage: %w", result.Error)
 	}
-	return credential.MFASecret, nil
+	return result.RowsAffected > 0, nil
 }
 
 // RecordMFAUsage records when MFA was last used
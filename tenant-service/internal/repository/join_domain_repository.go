@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+)
+
+// JoinDomainRepository handles email domain auto-join database operations
+type JoinDomainRepository struct {
+	db *gorm.DB
+}
+
+// NewJoinDomainRepository creates a new join domain repository
+func NewJoinDomainRepository(db *gorm.DB) *JoinDomainRepository {
+	return &JoinDomainRepository{db: db}
+}
+
+// ============================================================================
+// Join Domain Operations
+// ============================================================================
+
+// CreateDomain stores a new join domain
+func (r *JoinDomainRepository) CreateDomain(ctx context.Context, domain *models.TenantJoinDomain) error {
+	if err := r.db.WithContext(ctx).Create(domain).Error; err != nil {
+		return fmt.Errorf("failed to create join domain: %w", err)
+	}
+	return nil
+}
+
+// ListDomains returns a tenant's join domains
+func (r *JoinDomainRepository) ListDomains(ctx context.Context, tenantID uuid.UUID) ([]models.TenantJoinDomain, error) {
+	var domains []models.TenantJoinDomain
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("domain ASC").Find(&domains).Error; err != nil {
+		return nil, fmt.Errorf("failed to list join domains: %w", err)
+	}
+	return domains, nil
+}
+
+// GetDomain retrieves one of a tenant's join domains, or nil if it does not exist
+func (r *JoinDomainRepository) GetDomain(ctx context.Context, tenantID, domainID uuid.UUID) (*models.TenantJoinDomain, error) {
+	var domain models.TenantJoinDomain
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, domainID).First(&domain).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join domain: %w", err)
+	}
+	return &domain, nil
+}
+
+// GetDomainByName retrieves a tenant's join domain by domain name, or nil if it does not exist
+func (r *JoinDomainRepository) GetDomainByName(ctx context.Context, tenantID uuid.UUID, name string) (*models.TenantJoinDomain, error) {
+	var domain models.TenantJoinDomain
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND domain = ?", tenantID, name).First(&domain).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join domain: %w", err)
+	}
+	return &domain, nil
+}
+
+// UpdateDomain saves changes to a join domain
+func (r *JoinDomainRepository) UpdateDomain(ctx context.Context, domain *models.TenantJoinDomain) error {
+	if err := r.db.WithContext(ctx).Save(domain).Error; err != nil {
+		return fmt.Errorf("failed to update join domain: %w", err)
+	}
+	return nil
+}
+
+// DeleteDomain deletes a join domain and its pending join requests
+func (r *JoinDomainRepository) DeleteDomain(ctx context.Context, domain *models.TenantJoinDomain) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("domain_id = ? AND status = ?", domain.ID, models.JoinRequestStatusPending).
+			Delete(&models.TenantJoinRequest{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(domain).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete join domain: %w", err)
+	}
+	return nil
+}
+
+// FindVerifiedDomainOwner returns the tenant that verified the domain, other than
+// excludeTenantID, or uuid.Nil if none has
+func (r *JoinDomainRepository) FindVerifiedDomainOwner(ctx context.Context, name string, excludeTenantID uuid.UUID) (uuid.UUID, error) {
+	var domain models.TenantJoinDomain
+	err := r.db.WithContext(ctx).Select("tenant_id").
+		Where("domain = ? AND status = ? AND tenant_id <> ?", name, models.JoinDomainStatusVerified, excludeTenantID).
+		First(&domain).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to check join domain owner: %w", err)
+	}
+	return domain.TenantID, nil
+}
+
+// ListJoinableDomains returns the verified, enabled join domains for an email domain
+func (r *JoinDomainRepository) ListJoinableDomains(ctx context.Context, name string) ([]models.TenantJoinDomain, error) {
+	var domains []models.TenantJoinDomain
+	if err := r.db.WithContext(ctx).
+		Where("domain = ? AND status = ? AND enabled = ?", name, models.JoinDomainStatusVerified, true).
+		Find(&domains).Error; err != nil {
+		return nil, fmt.Errorf("failed to list joinable domains: %w", err)
+	}
+	return domains, nil
+}
+
+// ============================================================================
+// Join Request Operations
+// ============================================================================
+
+// CreateJoinRequest stores a new join request
+func (r *JoinDomainRepository) CreateJoinRequest(ctx context.Context, request *models.TenantJoinRequest) error {
+	if err := r.db.WithContext(ctx).Create(request).Error; err != nil {
+		return fmt.Errorf("failed to create join request: %w", err)
+	}
+	return nil
+}
+
+// GetLatestJoinRequest returns the user's most recent join request for the tenant, or nil
+func (r *JoinDomainRepository) GetLatestJoinRequest(ctx context.Context, tenantID, userID uuid.UUID) (*models.TenantJoinRequest, error) {
+	var request models.TenantJoinRequest
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND user_id = ?", tenantID, userID).
+		Order("created_at DESC").First(&request).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join request: %w", err)
+	}
+	return &request, nil
+}
+
+// GetJoinRequest retrieves one of a tenant's join requests, or nil if it does not exist
+func (r *JoinDomainRepository) GetJoinRequest(ctx context.Context, tenantID, requestID uuid.UUID) (*models.TenantJoinRequest, error) {
+	var request models.TenantJoinRequest
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, requestID).First(&request).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join request: %w", err)
+	}
+	return &request, nil
+}
+
+// ListJoinRequests returns a tenant's join requests, optionally filtered by status, newest first
+func (r *JoinDomainRepository) ListJoinRequests(ctx context.Context, tenantID uuid.UUID, status string) ([]models.TenantJoinRequest, error) {
+	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var requests []models.TenantJoinRequest
+	if err := query.Order("created_at DESC").Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("failed to list join requests: %w", err)
+	}
+	return requests, nil
+}
+
+// UpdateJoinRequest saves changes to a join request
+func (r *JoinDomainRepository) UpdateJoinRequest(ctx context.Context, request *models.TenantJoinRequest) error {
+	if err := r.db.WithContext(ctx).Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update join request: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+)
+
+const (
+	// lookupCacheTTL bounds how stale a shared lookup can get if an invalidation is lost
+	lookupCacheTTL = 5 * time.Minute
+	// lookupLocalCacheTTL keeps hot lookups in memory between Redis round trips
+	lookupLocalCacheTTL = 15 * time.Second
+	// lookupLocalCacheMaxEntries caps the in-memory layer; expired entries are swept when it fills up
+	lookupLocalCacheMaxEntries = 10000
+)
+
+// MembershipCacheStoreInterface defines the shared cache backing tenant and membership lookups
+type MembershipCacheStoreInterface interface {
+	GetMembershipCacheEntry(ctx context.Context, key string) ([]byte, error)
+	SaveMembershipCacheEntry(ctx context.Context, key string, data []byte, ttl time.Duration) error
+	DeleteMembershipCacheEntries(ctx context.Context, keys []string) error
+}
+
+// MembershipCachePublisherInterface defines how other instances are told to drop cached lookups
+type MembershipCachePublisherInterface interface {
+	PublishMembershipCacheInvalidated(ctx context.Context, keys []string) error
+}
+
+type lookupCacheEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// tenantSlugCacheKey is the cache key for a tenant-by-slug lookup
+func tenantSlugCacheKey(slug string) string {
+	return "slug:" + slug
+}
+
+// userMembershipsCacheKey is the cache key for a memberships-by-user lookup
+func userMembershipsCacheKey(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+// SetCache enables the read-through cache for tenant-by-slug and memberships-by-user lookups.
+// Lookups are served from memory, then Redis, then Postgres; writes made through this
+// repository invalidate the affected keys on every instance.
+func (r *MembershipRepository) SetCache(store MembershipCacheStoreInterface) {
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	r.cache = store
+	r.localCache = make(map[string]lookupCacheEntry)
+}
+
+// SetCachePublisher sets the publisher that propagates invalidations to other instances
+func (r *MembershipRepository) SetCachePublisher(publisher MembershipCachePublisherInterface) {
+	r.cachePublisher = publisher
+}
+
+// EvictCachedLookups drops in-memory lookups for the given keys.
+// Called for invalidations received from other instances, which have already cleared Redis.
+func (r *MembershipRepository) EvictCachedLookups(keys []string) {
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	for _, key := range keys {
+		delete(r.localCache, key)
+	}
+}
+
+// InvalidateUserMembershipsCache drops cached memberships for the given users
+func (r *MembershipRepository) InvalidateUserMembershipsCache(ctx context.Context, userIDs ...uuid.UUID) {
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID != uuid.Nil {
+			keys = append(keys, userMembershipsCacheKey(userID))
+		}
+	}
+	r.invalidateLookups(ctx, keys)
+}
+
+// InvalidateTenantCache drops the cached tenant and the cached memberships of its members.
+// Call it after writing to the tenant directly; the tenant row must still exist.
+func (r *MembershipRepository) InvalidateTenantCache(ctx context.Context, tenantID uuid.UUID) {
+	if r.cache == nil {
+		return
+	}
+	r.invalidateLookups(ctx, r.tenantCacheKeys(ctx, tenantID))
+}
+
+// InvalidateDeletedTenantCache drops cached lookups for a tenant that no longer exists
+func (r *MembershipRepository) InvalidateDeletedTenantCache(ctx context.Context, slug string, memberIDs []uuid.UUID) {
+	keys := []string{tenantSlugCacheKey(slug)}
+	for _, userID := range memberIDs {
+		if userID != uuid.Nil {
+			keys = append(keys, userMembershipsCacheKey(userID))
+		}
+	}
+	r.invalidateLookups(ctx, keys)
+}
+
+// tenantCacheKeys returns the keys whose lookups embed the tenant's current state
+func (r *MembershipRepository) tenantCacheKeys(ctx context.Context, tenantID uuid.UUID) []string {
+	var keys []string
+
+	var slugs []string
+	if err := r.db.WithContext(ctx).Model(&models.Tenant{}).
+		Where("id = ?", tenantID).
+		Pluck("slug", &slugs).Error; err != nil {
+		log.Printf("[MembershipRepository] WARN: failed to resolve slug for cache invalidation of tenant %s: %v", tenantID, err)
+	}
+	for _, slug := range slugs {
+		keys = append(keys, tenantSlugCacheKey(slug))
+	}
+
+	var userIDs []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&models.UserTenantMembership{}).
+		Where("tenant_id = ? AND is_active = ? AND user_id <> ?", tenantID, true, uuid.Nil).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		log.Printf("[MembershipRepository] WARN: failed to resolve members for cache invalidation of tenant %s: %v", tenantID, err)
+	}
+	for _, userID := range userIDs {
+		keys = append(keys, userMembershipsCacheKey(userID))
+	}
+
+	return keys
+}
+
+// invalidateLookups drops keys locally and in Redis, then tells the other instances.
+// Failures are logged: the TTLs bound how long a missed invalidation stays visible.
+func (r *MembershipRepository) invalidateLookups(ctx context.Context, keys []string) {
+	if r.cache == nil || len(keys) == 0 {
+		return
+	}
+
+	r.EvictCachedLookups(keys)
+	if err := r.cache.DeleteMembershipCacheEntries(ctx, keys); err != nil {
+		log.Printf("[MembershipRepository] WARN: failed to invalidate cached lookups: %v", err)
+	}
+	if r.cachePublisher != nil {
+		if err := r.cachePublisher.PublishMembershipCacheInvalidated(ctx, keys); err != nil {
+			log.Printf("[MembershipRepository] WARN: failed to publish cache invalidation: %v", err)
+		}
+	}
+}
+
+// cachedLookup returns the cached JSON for a key, or nil on a miss or when caching is disabled
+func (r *MembershipRepository) cachedLookup(ctx context.Context, key string) []byte {
+	if r.cache == nil {
+		return nil
+	}
+
+	r.cacheMu.RLock()
+	entry, ok := r.localCache[key]
+	r.cacheMu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.data
+	}
+
+	data, err := r.cache.GetMembershipCacheEntry(ctx, key)
+	if err != nil {
+		log.Printf("[MembershipRepository] WARN: failed to read cached lookup: %v", err)
+		return nil
+	}
+	if data != nil {
+		r.remember(key, data)
+	}
+	return data
+}
+
+// storeLookup caches a lookup result in memory and Redis
+func (r *MembershipRepository) storeLookup(ctx context.Context, key string, value interface{}) {
+	if r.cache == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("[MembershipRepository] WARN: failed to encode lookup for cache: %v", err)
+		return
+	}
+
+	r.remember(key, data)
+	if err := r.cache.SaveMembershipCacheEntry(ctx, key, data, lookupCacheTTL); err != nil {
+		log.Printf("[MembershipRepository] WARN: failed to cache lookup: %v", err)
+	}
+}
+
+// remember stores a lookup in the in-memory layer
+func (r *MembershipRepository) remember(key string, data []byte) {
+	now := time.Now()
+
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	if len(r.localCache) >= lookupLocalCacheMaxEntries {
+		for k, entry := range r.localCache {
+			if !now.Before(entry.expiresAt) {
+				delete(r.localCache, k)
+			}
+		}
+		if len(r.localCache) >= lookupLocalCacheMaxEntries {
+			r.localCache = make(map[string]lookupCacheEntry)
+		}
+	}
+	r.localCache[key] = lookupCacheEntry{data: data, expiresAt: now.Add(lookupLocalCacheTTL)}
+}
+
+// findMembership returns the membership for the tenant, or nil if the user is not a member
+func findMembership(memberships []models.UserTenantMembership, tenantID uuid.UUID) *models.UserTenantMembership {
+	for i := range memberships {
+		if memberships[i].TenantID == tenantID {
+			return &memberships[i]
+		}
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -9,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"tenant-service/internal/listquery"
 	"tenant-service/internal/models"
 	"gorm.io/gorm"
 )
@@ -18,6 +21,12 @@ type MembershipRepository struct {
 	db                 *gorm.DB
 	tenantRouterClient TenantRouterClientInterface
 	vendorClient       VendorClientInterface
+
+	// Read-through lookup cache, enabled by SetCache
+	cache          MembershipCacheStoreInterface
+	cachePublisher MembershipCachePublisherInterface
+	cacheMu        sync.RWMutex
+	localCache     map[string]lookupCacheEntry
 }
 
 // TenantRouterClientInterface defines the interface for tenant-router-service client
@@ -56,12 +65,31 @@ func (r *MembershipRepository) SetVendorClient(client VendorClientInterface) {
 // ============================================================================
 
 // GetTenantBySlug retrieves a tenant by its URL slug
-// Falls back to storefront slug lookup if tenant slug not found
+// Falls back to the slug redirects of renamed tenants, then to storefront slug lookup if tenant slug not found
 // This handles the case where storefront slug differs from tenant slug
+// Active tenants found by their own slug are cached; cached tenants lack fields hidden from JSON,
+// so load the tenant by ID before saving changes to it
 func (r *MembershipRepository) GetTenantBySlug(ctx context.Context, slug string) (*models.Tenant, error) {
 	var tenant models.Tenant
+	key := tenantSlugCacheKey(slug)
+	if data := r.cachedLookup(ctx, key); data != nil {
+		if err := json.Unmarshal(data, &tenant); err == nil {
+			return &tenant, nil
+		}
+		tenant = models.Tenant{}
+	}
+
 	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&tenant).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
+			// A renamed tenant is still found by its former slug until the redirect expires
+			redirected, redirectErr := r.GetTenantBySlugRedirect(ctx, slug)
+			if redirectErr != nil {
+				return nil, redirectErr
+			}
+			if redirected != nil {
+				return redirected, nil
+			}
+
 			// Try storefront slug lookup as fallback
 			if r.vendorClient != nil {
 				storefront, sfErr := r.vendorClient.GetStorefrontBySlug(ctx, slug)
@@ -79,6 +107,12 @@ func (r *MembershipRepository) GetTenantBySlug(ctx context.Context, slug string)
 		}
 		return nil, fmt.Errorf("failed to get tenant by slug: %w", err)
 	}
+
+	// Tenants in transitional states (creating, pending deletion, ...) are written directly by
+	// several services, so only settled tenants are cached
+	if tenant.Status == "active" {
+		r.storeLookup(ctx, key, &tenant)
+	}
 	return &tenant, nil
 }
 
@@ -96,10 +130,18 @@ func (r *MembershipRepository) GetTenantByID(ctx context.Context, tenantID uuid.
 
 // UpdateTenant updates a tenant's details
 func (r *MembershipRepository) UpdateTenant(ctx context.Context, tenant *models.Tenant) error {
+	// Resolve the keys before saving so a renamed slug is invalidated too
+	var staleKeys []string
+	if r.cache != nil {
+		staleKeys = r.tenantCacheKeys(ctx, tenant.ID)
+	}
+
 	tenant.UpdatedAt = time.Now()
 	if err := r.db.WithContext(ctx).Save(tenant).Error; err != nil {
 		return fmt.Errorf("failed to update tenant: %w", err)
 	}
+
+	r.invalidateLookups(ctx, append(staleKeys, tenantSlugCacheKey(tenant.Slug)))
 	return nil
 }
 
@@ -288,7 +330,10 @@ func (r *MembershipRepository) ValidateAndReserveSlug(ctx context.Context, reque
 	if result.Available {
 		_, err := r.ReserveSlug(ctx, result.Slug, sessionID, reservedBy)
 		if err != nil {
-			// Race condition - someone else grabbed it
+			if !errors.Is(err, ErrSlugTaken) {
+				return nil, err
+			}
+			// Race condition - another session reserved it after validation
 			result.Available = false
 			result.Message = "This name was just taken. Try one of these alternatives:"
 			result.Suggestions, _ = r.generateSlugSuggestions(ctx, result.Slug, 5)
@@ -522,66 +567,106 @@ func (r *MembershipRepository) IsSlugReservedOrTaken(ctx context.Context, slug s
 	return true, &reservation, nil
 }
 
-// ReserveSlug reserves a slug for an onboarding session (temporary hold)
+// ReserveSlug reserves a slug for an onboarding session (temporary hold).
+// The check and write happen under a per-slug lock, so two sessions can never both hold the
+// same slug; a session reserving a slug it already holds just extends the hold.
+// Returns ErrSlugTaken if another session or tenant holds the slug.
 func (r *MembershipRepository) ReserveSlug(ctx context.Context, slug string, sessionID uuid.UUID, reservedBy string) (*models.TenantSlugReservation, error) {
-	expiresAt := time.Now().Add(models.DefaultSlugReservationDuration)
-
-	reservation := &models.TenantSlugReservation{
-		Slug:       slug,
-		Status:     models.SlugReservationPending,
-		SessionID:  &sessionID,
-		ReservedBy: reservedBy,
-		ExpiresAt:  &expiresAt,
-	}
-
-	// Use upsert to handle race conditions
-	result := r.db.WithContext(ctx).
-		Where("slug = ?", slug).
-		Assign(map[string]interface{}{
-			"status":      models.SlugReservationPending,
-			"session_id":  sessionID,
-			"reserved_by": reservedBy,
-			"expires_at":  expiresAt,
-			"updated_at":  time.Now(),
-			"released_at": nil,
-		}).
-		FirstOrCreate(reservation)
-
-	if result.Error != nil {
-		return nil, fmt.Errorf("failed to reserve slug: %w", result.Error)
+	var reservation *models.TenantSlugReservation
+	err := r.withSlugLock(ctx, slug, func(tx *gorm.DB, existing *models.TenantSlugReservation) error {
+		now := time.Now()
+		expiresAt := now.Add(models.DefaultSlugReservationDuration)
+
+		switch EvaluateSlugReservation(existing, sessionID, now) {
+		case SlugClaimReject:
+			return ErrSlugTaken
+		case SlugClaimNoop:
+			reservation = existing
+			return nil
+		case SlugClaimCreate:
+			reservation = &models.TenantSlugReservation{
+				Slug:       slug,
+				Status:     models.SlugReservationPending,
+				SessionID:  &sessionID,
+				ReservedBy: reservedBy,
+				ExpiresAt:  &expiresAt,
+			}
+			return tx.Create(reservation).Error
+		case SlugClaimRenew:
+			reservation = existing
+			return tx.Model(existing).Updates(map[string]interface{}{
+				"expires_at": expiresAt,
+				"updated_at": now,
+			}).Error
+		default:
+			reservation = existing
+			return tx.Model(existing).Updates(map[string]interface{}{
+				"status":      models.SlugReservationPending,
+				"session_id":  sessionID,
+				"tenant_id":   nil,
+				"reserved_by": reservedBy,
+				"expires_at":  expiresAt,
+				"updated_at":  now,
+				"released_at": nil,
+			}).Error
+		}
+	})
+	if err != nil {
+		if errors.Is(err, ErrSlugTaken) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reserve slug: %w", err)
 	}
-
 	return reservation, nil
 }
 
 // ActivateSlugReservation converts a pending reservation to active (when tenant is created)
 func (r *MembershipRepository) ActivateSlugReservation(ctx context.Context, slug string, tenantID uuid.UUID) error {
-	result := r.db.WithContext(ctx).
-		Model(&models.TenantSlugReservation{}).
-		Where("slug = ?", slug).
-		Updates(map[string]interface{}{
-			"status":     models.SlugReservationActive,
-			"tenant_id":  tenantID,
-			"expires_at": nil, // No expiry for active
-			"updated_at": time.Now(),
-		})
+	return r.activateSlug(ctx, slug, nil, tenantID)
+}
 
-	if result.Error != nil {
-		return fmt.Errorf("failed to activate slug reservation: %w", result.Error)
-	}
+// ActivateSlugReservationForSession converts the session's pending reservation to active.
+// Activating again for the same tenant is a no-op; returns ErrSlugTaken if another session
+// or tenant holds the slug.
+func (r *MembershipRepository) ActivateSlugReservationForSession(ctx context.Context, slug string, sessionID, tenantID uuid.UUID) error {
+	return r.activateSlug(ctx, slug, &sessionID, tenantID)
+}
 
-	// If no existing reservation, create one
-	if result.RowsAffected == 0 {
-		reservation := &models.TenantSlugReservation{
-			Slug:     slug,
-			Status:   models.SlugReservationActive,
-			TenantID: &tenantID,
+func (r *MembershipRepository) activateSlug(ctx context.Context, slug string, sessionID *uuid.UUID, tenantID uuid.UUID) error {
+	err := r.withSlugLock(ctx, slug, func(tx *gorm.DB, existing *models.TenantSlugReservation) error {
+		now := time.Now()
+		switch EvaluateSlugActivation(existing, sessionID, tenantID, now) {
+		case SlugClaimReject:
+			return ErrSlugTaken
+		case SlugClaimNoop:
+			return nil
+		case SlugClaimCreate:
+			return tx.Create(&models.TenantSlugReservation{
+				Slug:      slug,
+				Status:    models.SlugReservationActive,
+				SessionID: sessionID,
+				TenantID:  &tenantID,
+			}).Error
+		default:
+			updates := map[string]interface{}{
+				"status":      models.SlugReservationActive,
+				"tenant_id":   tenantID,
+				"expires_at":  nil, // No expiry for active
+				"updated_at":  now,
+				"released_at": nil,
+			}
+			if sessionID != nil {
+				updates["session_id"] = *sessionID
+			}
+			return tx.Model(existing).Updates(updates).Error
 		}
-		if err := r.db.WithContext(ctx).Create(reservation).Error; err != nil {
-			return fmt.Errorf("failed to create active slug reservation: %w", err)
+	})
+	if err != nil {
+		if errors.Is(err, ErrSlugTaken) {
+			return err
 		}
+		return fmt.Errorf("failed to activate slug reservation: %w", err)
 	}
-
 	return nil
 }
 
@@ -706,11 +791,21 @@ func (r *MembershipRepository) CreateMembership(ctx context.Context, membership
 	if err := r.db.WithContext(ctx).Create(membership).Error; err != nil {
 		return fmt.Errorf("failed to create membership: %w", err)
 	}
+	r.InvalidateUserMembershipsCache(ctx, membership.UserID)
 	return nil
 }
 
 // GetMembership retrieves a specific membership by user and tenant
+// Active memberships are served from the user's cached memberships when caching is enabled
 func (r *MembershipRepository) GetMembership(ctx context.Context, userID, tenantID uuid.UUID) (*models.UserTenantMembership, error) {
+	if r.cache != nil {
+		if memberships, err := r.GetUserMemberships(ctx, userID); err == nil {
+			if membership := findMembership(memberships, tenantID); membership != nil {
+				return membership, nil
+			}
+		}
+	}
+
 	var membership models.UserTenantMembership
 	if err := r.db.WithContext(ctx).
 		Preload("Tenant").
@@ -725,8 +820,17 @@ func (r *MembershipRepository) GetMembership(ctx context.Context, userID, tenant
 }
 
 // GetUserMemberships retrieves all active memberships for a user
+// Cached when caching is enabled; LastAccessedAt and the resulting order may lag by up to the cache TTL
 func (r *MembershipRepository) GetUserMemberships(ctx context.Context, userID uuid.UUID) ([]models.UserTenantMembership, error) {
 	var memberships []models.UserTenantMembership
+	key := userMembershipsCacheKey(userID)
+	if data := r.cachedLookup(ctx, key); data != nil {
+		if err := json.Unmarshal(data, &memberships); err == nil {
+			return memberships, nil
+		}
+		memberships = nil
+	}
+
 	if err := r.db.WithContext(ctx).
 		Preload("Tenant").
 		Where("user_id = ? AND is_active = ?", userID, true).
@@ -734,6 +838,8 @@ func (r *MembershipRepository) GetUserMemberships(ctx context.Context, userID uu
 		Find(&memberships).Error; err != nil {
 		return nil, fmt.Errorf("failed to get user memberships: %w", err)
 	}
+
+	r.storeLookup(ctx, key, memberships)
 	return memberships, nil
 }
 
@@ -750,16 +856,71 @@ func (r *MembershipRepository) GetUserByKeycloakID(ctx context.Context, keycloak
 	return &user, nil
 }
 
-// GetTenantMemberships retrieves all memberships for a tenant
-func (r *MembershipRepository) GetTenantMemberships(ctx context.Context, tenantID uuid.UUID) ([]models.UserTenantMembership, error) {
+// CreateUser creates a user record
+func (r *MembershipRepository) CreateUser(ctx context.Context, user *models.User) error {
+	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// UpdateUser saves changes to a user record
+func (r *MembershipRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+// GetUserByEmail retrieves a user by email (case-insensitive)
+func (r *MembershipRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("LOWER(email) = LOWER(?)", email).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil // Not found is not an error - the user may need an invitation instead
+		}
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+	return &user, nil
+}
+
+// ListTenantMemberships lists a tenant's memberships matching the list query
+// Pending invitations (no user yet) are listed by ListInvitations instead
+func (r *MembershipRepository) ListTenantMemberships(ctx context.Context, tenantID uuid.UUID, q *listquery.Query) ([]models.UserTenantMembership, int64, string, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.UserTenantMembership{}).
+		Where("tenant_id = ? AND user_id <> ?", tenantID, uuid.Nil).
+		Scopes(q.FilterScope())
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to count tenant memberships: %w", err)
+	}
+
 	var memberships []models.UserTenantMembership
+	if err := query.Scopes(q.PageScope()).Find(&memberships).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to get tenant memberships: %w", err)
+	}
+
+	memberships, next, err := listquery.NextPage(q, memberships)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return memberships, total, next, nil
+}
+
+// GetUsersByIDs retrieves the users with the given IDs; unknown IDs are skipped
+func (r *MembershipRepository) GetUsersByIDs(ctx context.Context, userIDs []uuid.UUID) ([]models.User, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var users []models.User
 	if err := r.db.WithContext(ctx).
-		Where("tenant_id = ? AND is_active = ?", tenantID, true).
-		Order("role, created_at").
-		Find(&memberships).Error; err != nil {
-		return nil, fmt.Errorf("failed to get tenant memberships: %w", err)
+		Where("id IN ?", userIDs).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
-	return memberships, nil
+	return users, nil
 }
 
 // GetUserDefaultMembership retrieves the user's default tenant membership
@@ -794,11 +955,13 @@ func (r *MembershipRepository) UpdateMembership(ctx context.Context, membership
 	if err := r.db.WithContext(ctx).Save(membership).Error; err != nil {
 		return fmt.Errorf("failed to update membership: %w", err)
 	}
+	r.InvalidateUserMembershipsCache(ctx, membership.UserID)
 	return nil
 }
 
 // SetDefaultMembership sets a membership as the user's default
 func (r *MembershipRepository) SetDefaultMembership(ctx context.Context, userID, tenantID uuid.UUID) error {
+	defer r.InvalidateUserMembershipsCache(ctx, userID)
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Unset current default
 		if err := tx.Model(&models.UserTenantMembership{}).
@@ -825,6 +988,7 @@ func (r *MembershipRepository) SetDefaultMembership(ctx context.Context, userID,
 }
 
 // UpdateLastAccessed updates the last accessed time for a membership
+// Runs on every tenant context lookup, so it does not invalidate the cached memberships
 func (r *MembershipRepository) UpdateLastAccessed(ctx context.Context, userID, tenantID uuid.UUID) error {
 	now := time.Now()
 	if err := r.db.WithContext(ctx).Model(&models.UserTenantMembership{}).
@@ -846,6 +1010,7 @@ func (r *MembershipRepository) DeactivateMembership(ctx context.Context, userID,
 		}).Error; err != nil {
 		return fmt.Errorf("failed to deactivate membership: %w", err)
 	}
+	r.InvalidateUserMembershipsCache(ctx, userID)
 	return nil
 }
 
@@ -853,6 +1018,10 @@ func (r *MembershipRepository) DeactivateMembership(ctx context.Context, userID,
 // Supports both direct user_id match and keycloak_id lookup for backward compatibility
 // with users who were created before the Keycloak ID sync was implemented
 func (r *MembershipRepository) HasAccess(ctx context.Context, userID, tenantID uuid.UUID) (bool, error) {
+	if r.cache != nil {
+		return r.hasCachedAccess(ctx, userID, tenantID)
+	}
+
 	// First, try direct user_id match (works for new users where local ID = Keycloak ID)
 	var count int64
 	if err := r.db.WithContext(ctx).Model(&models.UserTenantMembership{}).
@@ -882,6 +1051,27 @@ func (r *MembershipRepository) HasAccess(ctx context.Context, userID, tenantID u
 	return false, nil
 }
 
+// hasCachedAccess is HasAccess answered from the users' cached memberships
+func (r *MembershipRepository) hasCachedAccess(ctx context.Context, userID, tenantID uuid.UUID) (bool, error) {
+	memberships, err := r.GetUserMemberships(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check access: %w", err)
+	}
+	if findMembership(memberships, tenantID) != nil {
+		return true, nil
+	}
+
+	localUser, err := r.GetUserByKeycloakID(ctx, userID)
+	if err != nil || localUser == nil || localUser.ID == userID {
+		return false, nil
+	}
+	memberships, err = r.GetUserMemberships(ctx, localUser.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check access by keycloak_id: %w", err)
+	}
+	return findMembership(memberships, tenantID) != nil, nil
+}
+
 // HasAccessBySlug checks if a user has access to a tenant by slug
 func (r *MembershipRepository) HasAccessBySlug(ctx context.Context, userID uuid.UUID, slug string) (bool, *models.Tenant, error) {
 	tenant, err := r.GetTenantBySlug(ctx, slug)
@@ -927,6 +1117,31 @@ func (r *MembershipRepository) GetUserRole(ctx context.Context, userID, tenantID
 	return "", fmt.Errorf("no active membership found")
 }
 
+// GetActiveMembership returns the user's active membership in the tenant, or nil if there is none.
+// Like GetUserRole, userID may be a Keycloak ID that maps to a local user.
+func (r *MembershipRepository) GetActiveMembership(ctx context.Context, userID, tenantID uuid.UUID) (*models.UserTenantMembership, error) {
+	userIDs := []uuid.UUID{userID}
+	var localUser models.User
+	if err := r.db.WithContext(ctx).
+		Select("id").
+		Where("keycloak_id = ?", userID).
+		First(&localUser).Error; err == nil && localUser.ID != userID {
+		userIDs = append(userIDs, localUser.ID)
+	}
+
+	var membership models.UserTenantMembership
+	err := r.db.WithContext(ctx).
+		Where("user_id IN ? AND tenant_id = ? AND is_active = ?", userIDs, tenantID, true).
+		First(&membership).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+	return &membership, nil
+}
+
 // ============================================================================
 // Invitation Operations
 // ============================================================================
@@ -937,14 +1152,17 @@ func (r *MembershipRepository) CreateInvitation(ctx context.Context, tenantID, i
 	// In production, you might want to handle this differently
 	now := time.Now()
 	membership := &models.UserTenantMembership{
-		UserID:              uuid.Nil, // Will be set when invitation is accepted
-		TenantID:            tenantID,
-		Role:                role,
-		IsActive:            false, // Not active until accepted
-		InvitedBy:           &invitedBy,
-		InvitedAt:           &now,
-		InvitationToken:     token,
-		InvitationExpiresAt: &expiresAt,
+		UserID:               uuid.Nil, // Will be set when invitation is accepted
+		TenantID:             tenantID,
+		Role:                 role,
+		IsActive:             false, // Not active until accepted
+		InvitedBy:            &invitedBy,
+		InvitedAt:            &now,
+		InvitedEmail:         strings.ToLower(strings.TrimSpace(email)),
+		InvitationToken:      token,
+		InvitationExpiresAt:  &expiresAt,
+		InvitationSentCount:  1,
+		InvitationLastSentAt: &now,
 	}
 
 	if err := r.db.WithContext(ctx).Create(membership).Error; err != nil {
@@ -981,6 +1199,10 @@ func (r *MembershipRepository) AcceptInvitation(ctx context.Context, token strin
 		return nil, fmt.Errorf("failed to get invitation: %w", err)
 	}
 
+	if membership.InvitationRevokedAt != nil {
+		return nil, fmt.Errorf("invitation has been revoked")
+	}
+
 	// Check if expired
 	if membership.InvitationExpiresAt != nil && membership.InvitationExpiresAt.Before(time.Now()) {
 		return nil, fmt.Errorf("invitation has expired")
@@ -1003,9 +1225,117 @@ func (r *MembershipRepository) AcceptInvitation(ctx context.Context, token strin
 		return nil, fmt.Errorf("failed to accept invitation: %w", err)
 	}
 
+	r.InvalidateUserMembershipsCache(ctx, userID)
 	return &membership, nil
 }
 
+// ListInvitations returns the tenant's invitations that have not been accepted, matching the list query
+// status is matched as models.UserTenantMembership.InvitationStatus computes it at now ("" for all)
+func (r *MembershipRepository) ListInvitations(ctx context.Context, tenantID uuid.UUID, status string, now time.Time, q *listquery.Query) ([]models.UserTenantMembership, int64, string, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.UserTenantMembership{}).
+		Where("tenant_id = ? AND user_id = ? AND accepted_at IS NULL", tenantID, uuid.Nil).
+		Scopes(q.FilterScope())
+
+	switch status {
+	case models.InvitationStatusRevoked:
+		query = query.Where("invitation_revoked_at IS NOT NULL")
+	case models.InvitationStatusExpired:
+		query = query.Where("invitation_revoked_at IS NULL AND invitation_expires_at <= ?", now)
+	case models.InvitationStatusPending:
+		query = query.Where("invitation_revoked_at IS NULL AND (invitation_expires_at IS NULL OR invitation_expires_at > ?)", now)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to count invitations: %w", err)
+	}
+
+	var invitations []models.UserTenantMembership
+	if err := query.Scopes(q.PageScope()).Find(&invitations).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to list invitations: %w", err)
+	}
+
+	invitations, next, err := listquery.NextPage(q, invitations)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return invitations, total, next, nil
+}
+
+// GetInvitation retrieves an unaccepted invitation of a tenant by ID; returns nil if not found
+func (r *MembershipRepository) GetInvitation(ctx context.Context, tenantID, invitationID uuid.UUID) (*models.UserTenantMembership, error) {
+	var invitation models.UserTenantMembership
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ? AND user_id = ? AND accepted_at IS NULL", invitationID, tenantID, uuid.Nil).
+		First(&invitation).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	return &invitation, nil
+}
+
+// RenewInvitation replaces an invitation's token and expiry and counts another send.
+// The previous token stops working immediately.
+func (r *MembershipRepository) RenewInvitation(ctx context.Context, invitation *models.UserTenantMembership, token string, expiresAt time.Time) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).
+		Model(&models.UserTenantMembership{}).
+		Where("id = ? AND accepted_at IS NULL AND invitation_revoked_at IS NULL", invitation.ID).
+		Updates(map[string]interface{}{
+			"invitation_token":        token,
+			"invitation_expires_at":   expiresAt,
+			"invitation_sent_count":   gorm.Expr("COALESCE(invitation_sent_count, 0) + 1"),
+			"invitation_last_sent_at": now,
+			"updated_at":              now,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to renew invitation: %w", err)
+	}
+
+	invitation.InvitationToken = token
+	invitation.InvitationExpiresAt = &expiresAt
+	invitation.InvitationSentCount++
+	invitation.InvitationLastSentAt = &now
+	invitation.UpdatedAt = now
+	return nil
+}
+
+// RevokeInvitation marks an invitation revoked and clears its token so it can no longer be accepted
+func (r *MembershipRepository) RevokeInvitation(ctx context.Context, invitation *models.UserTenantMembership, revokedBy uuid.UUID) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).
+		Model(&models.UserTenantMembership{}).
+		Where("id = ? AND accepted_at IS NULL", invitation.ID).
+		Updates(map[string]interface{}{
+			"invitation_token":      "",
+			"invitation_revoked_at": now,
+			"invitation_revoked_by": revokedBy,
+			"updated_at":            now,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	invitation.InvitationToken = ""
+	invitation.InvitationRevokedAt = &now
+	invitation.InvitationRevokedBy = &revokedBy
+	invitation.UpdatedAt = now
+	return nil
+}
+
+// PurgeInvitations deletes unaccepted invitations that expired or were revoked before the cutoff
+func (r *MembershipRepository) PurgeInvitations(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND accepted_at IS NULL", uuid.Nil).
+		Where("invitation_expires_at < ? OR invitation_revoked_at < ?", before, before).
+		Delete(&models.UserTenantMembership{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge invitations: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
 // ============================================================================
 // Activity Log Operations
 // ============================================================================
@@ -1018,18 +1348,28 @@ func (r *MembershipRepository) LogActivity(ctx context.Context, log *models.Tena
 	return nil
 }
 
-// GetTenantActivityLog retrieves activity logs for a tenant
-func (r *MembershipRepository) GetTenantActivityLog(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]models.TenantActivityLog, error) {
-	var logs []models.TenantActivityLog
-	if err := r.db.WithContext(ctx).
+// GetTenantActivityLog retrieves the activity log entries of a tenant matching the list query
+func (r *MembershipRepository) GetTenantActivityLog(ctx context.Context, tenantID uuid.UUID, q *listquery.Query) ([]models.TenantActivityLog, int64, string, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.TenantActivityLog{}).
 		Where("tenant_id = ?", tenantID).
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&logs).Error; err != nil {
-		return nil, fmt.Errorf("failed to get activity log: %w", err)
+		Scopes(q.FilterScope())
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to count activity log: %w", err)
+	}
+
+	var logs []models.TenantActivityLog
+	if err := query.Scopes(q.PageScope()).Find(&logs).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to get activity log: %w", err)
+	}
+
+	logs, next, err := listquery.NextPage(q, logs)
+	if err != nil {
+		return nil, 0, "", err
 	}
-	return logs, nil
+	return logs, total, next, nil
 }
 
 // ============================================================================
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -276,6 +278,101 @@ func (r *BusinessInformationRepository) GetBySessionID(ctx context.Context, sess
 	return &businessInfo, nil
 }
 
+// Custom field query operators
+const (
+	CustomFieldOpExists   = "exists"
+	CustomFieldOpEq       = "eq"
+	CustomFieldOpNe       = "ne"
+	CustomFieldOpContains = "contains"
+	CustomFieldOpGt       = "gt"
+	CustomFieldOpGte      = "gte"
+	CustomFieldOpLt       = "lt"
+	CustomFieldOpLte      = "lte"
+)
+
+// customFieldComparisons maps range operators to SQL
+var customFieldComparisons = map[string]string{
+	CustomFieldOpGt:  ">",
+	CustomFieldOpGte: ">=",
+	CustomFieldOpLt:  "<",
+	CustomFieldOpLte: "<=",
+}
+
+// CustomFieldFilter selects business information by a typed custom field value
+// Value must already be normalized to the field type (float64, bool, string, []string).
+type CustomFieldFilter struct {
+	Key   string
+	Type  string
+	Op    string
+	Value interface{}
+}
+
+// FindByCustomField returns business information of a template's sessions matching a custom field filter
+func (r *BusinessInformationRepository) FindByCustomField(ctx context.Context, templateID uuid.UUID, filter CustomFieldFilter, page, pageSize int) ([]models.BusinessInformation, int64, error) {
+	var results []models.BusinessInformation
+	var total int64
+
+	sessionIDs := r.db.Model(&models.OnboardingSession{}).Select("id").Where("template_id = ?", templateID)
+	query := r.db.WithContext(ctx).Model(&models.BusinessInformation{}).
+		Where("onboarding_session_id IN (?)", sessionIDs)
+
+	switch filter.Op {
+	case CustomFieldOpExists:
+		query = query.Where("jsonb_exists(custom_fields, ?)", filter.Key)
+	case CustomFieldOpEq, CustomFieldOpNe:
+		doc, err := json.Marshal(map[string]interface{}{filter.Key: filter.Value})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode custom field filter: %w", err)
+		}
+		if filter.Op == CustomFieldOpEq {
+			// Containment uses the GIN index on custom_fields
+			query = query.Where("custom_fields @> ?::jsonb", string(doc))
+		} else {
+			query = query.Where("jsonb_exists(custom_fields, ?) AND NOT custom_fields @> ?::jsonb", filter.Key, string(doc))
+		}
+	case CustomFieldOpContains:
+		if filter.Type == models.CustomFieldTypeMultiSelect {
+			doc, err := json.Marshal(map[string]interface{}{filter.Key: []interface{}{filter.Value}})
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to encode custom field filter: %w", err)
+			}
+			query = query.Where("custom_fields @> ?::jsonb", string(doc))
+		} else {
+			pattern := "%" + escapeLike(fmt.Sprint(filter.Value)) + "%"
+			query = query.Where("custom_fields->>? ILIKE ?", filter.Key, pattern)
+		}
+	default:
+		cmp, ok := customFieldComparisons[filter.Op]
+		if !ok {
+			return nil, 0, fmt.Errorf("unsupported custom field operator: %s", filter.Op)
+		}
+		if filter.Type == models.CustomFieldTypeNumber {
+			// Guard the cast so values stored before a type change cannot fail the query
+			query = query.Where("(CASE WHEN jsonb_typeof(custom_fields->?) = 'number' THEN (custom_fields->>?)::numeric END) "+cmp+" ?",
+				filter.Key, filter.Key, filter.Value)
+		} else {
+			// Dates are stored as YYYY-MM-DD, which sorts lexically
+			query = query.Where("custom_fields->>? "+cmp+" ?", filter.Key, filter.Value)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count custom field matches: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&results).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query custom fields: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// escapeLike escapes LIKE wildcards in a user-supplied search term
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 // ContactInformationRepository handles contact information operations
 type ContactInformationRepository struct {
 	db *gorm.DB
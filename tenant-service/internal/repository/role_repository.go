@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+)
+
+// RoleRepository handles tenant-defined role database operations
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *gorm.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// List returns the tenant's custom roles ordered by name
+func (r *RoleRepository) List(ctx context.Context, tenantID uuid.UUID) ([]models.TenantRole, error) {
+	var roles []models.TenantRole
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("name ASC").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	return roles, nil
+}
+
+// GetByID retrieves a tenant's custom role, or nil if it does not exist
+func (r *RoleRepository) GetByID(ctx context.Context, tenantID, roleID uuid.UUID) (*models.TenantRole, error) {
+	return r.first(ctx, "tenant_id = ? AND id = ?", tenantID, roleID)
+}
+
+// GetByName retrieves a tenant's custom role by name, or nil if it does not exist
+func (r *RoleRepository) GetByName(ctx context.Context, tenantID uuid.UUID, name string) (*models.TenantRole, error) {
+	return r.first(ctx, "tenant_id = ? AND name = ?", tenantID, name)
+}
+
+func (r *RoleRepository) first(ctx context.Context, query string, args ...interface{}) (*models.TenantRole, error) {
+	var role models.TenantRole
+	err := r.db.WithContext(ctx).Where(query, args...).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	return &role, nil
+}
+
+// Create stores a new custom role
+func (r *RoleRepository) Create(ctx context.Context, role *models.TenantRole) error {
+	if err := r.db.WithContext(ctx).Create(role).Error; err != nil {
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+	return nil
+}
+
+// SaveAndSyncMembers updates a custom role and copies its name and permissions onto every
+// membership that holds it (under its previous name). Returns the affected user IDs.
+func (r *RoleRepository) SaveAndSyncMembers(ctx context.Context, role *models.TenantRole, previousName string) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(role).Error; err != nil {
+			return fmt.Errorf("failed to update role: %w", err)
+		}
+		if err := tx.Model(&models.UserTenantMembership{}).
+			Where("tenant_id = ? AND role = ?", role.TenantID, previousName).
+			Pluck("user_id", &userIDs).Error; err != nil {
+			return fmt.Errorf("failed to find role members: %w", err)
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.UserTenantMembership{}).
+			Where("tenant_id = ? AND role = ?", role.TenantID, previousName).
+			Updates(map[string]interface{}{
+				"role":        role.Name,
+				"permissions": role.Permissions,
+				"updated_at":  time.Now(),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to update role members: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// Delete removes a custom role
+func (r *RoleRepository) Delete(ctx context.Context, tenantID, roleID uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, roleID).Delete(&models.TenantRole{}).Error; err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	return nil
+}
+
+// CountMembers returns how many memberships (including pending invitations) hold the role
+func (r *RoleRepository) CountMembers(ctx context.Context, tenantID uuid.UUID, name string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.UserTenantMembership{}).
+		Where("tenant_id = ? AND role = ?", tenantID, name).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count role members: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSlugTaken is returned when a slug is held by another session or tenant
+var ErrSlugTaken = errors.New("slug is already reserved")
+
+const (
+	// slugLockNamespace is the first key of the two-key advisory lock, keeping slug locks
+	// apart from any other advisory locks taken on the same database
+	slugLockNamespace = 4201
+	// slugLockTimeout bounds how long a reservation waits for a concurrent one on the same slug
+	slugLockTimeout = "5s"
+)
+
+// SlugClaimAction is the outcome of evaluating a reservation or activation against the current row
+type SlugClaimAction int
+
+const (
+	// SlugClaimCreate inserts a new reservation row (the slug has never been reserved)
+	SlugClaimCreate SlugClaimAction = iota
+	// SlugClaimTakeover overwrites a released or expired row
+	SlugClaimTakeover
+	// SlugClaimRenew refreshes a row the caller already holds
+	SlugClaimRenew
+	// SlugClaimNoop leaves the row unchanged because the caller already holds it in the target state
+	SlugClaimNoop
+	// SlugClaimReject refuses the claim because someone else holds the slug
+	SlugClaimReject
+)
+
+// EvaluateSlugReservation decides what a session's reservation request does to the existing row.
+// Repeated reservations by the same session are idempotent: they only extend the hold.
+func EvaluateSlugReservation(existing *models.TenantSlugReservation, sessionID uuid.UUID, now time.Time) SlugClaimAction {
+	if existing == nil {
+		return SlugClaimCreate
+	}
+	heldBySession := existing.SessionID != nil && *existing.SessionID == sessionID
+
+	switch existing.Status {
+	case models.SlugReservationActive:
+		if heldBySession {
+			return SlugClaimNoop
+		}
+		return SlugClaimReject
+	case models.SlugReservationPending:
+		if heldBySession {
+			return SlugClaimRenew
+		}
+		if reservationExpired(existing, now) {
+			return SlugClaimTakeover
+		}
+		return SlugClaimReject
+	default:
+		return SlugClaimTakeover
+	}
+}
+
+// EvaluateSlugActivation decides what activating a slug for a tenant does to the existing row.
+// sessionID is the onboarding session that reserved the slug, or nil when the tenant is created
+// directly; only that session's pending hold (or an expired one) can be converted.
+func EvaluateSlugActivation(existing *models.TenantSlugReservation, sessionID *uuid.UUID, tenantID uuid.UUID, now time.Time) SlugClaimAction {
+	if existing == nil {
+		return SlugClaimCreate
+	}
+
+	switch existing.Status {
+	case models.SlugReservationActive:
+		if existing.TenantID != nil && *existing.TenantID == tenantID {
+			return SlugClaimNoop
+		}
+		return SlugClaimReject
+	case models.SlugReservationPending:
+		if sessionID != nil && existing.SessionID != nil && *existing.SessionID == *sessionID {
+			return SlugClaimTakeover
+		}
+		if reservationExpired(existing, now) {
+			return SlugClaimTakeover
+		}
+		return SlugClaimReject
+	default:
+		return SlugClaimTakeover
+	}
+}
+
+func reservationExpired(reservation *models.TenantSlugReservation, now time.Time) bool {
+	return reservation.ExpiresAt != nil && reservation.ExpiresAt.Before(now)
+}
+
+// withSlugLock runs fn in a transaction holding a Postgres advisory lock for the slug, so
+// concurrent reservations and activations of the same slug across replicas are serialized.
+// The lock is released when the transaction ends.
+func (r *MembershipRepository) withSlugLock(ctx context.Context, slug string, fn func(tx *gorm.DB, existing *models.TenantSlugReservation) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("SET LOCAL lock_timeout = '%s'", slugLockTimeout)).Error; err != nil {
+			return fmt.Errorf("failed to set slug lock timeout: %w", err)
+		}
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, hashtext(?))", slugLockNamespace, slug).Error; err != nil {
+			return fmt.Errorf("failed to acquire slug lock: %w", err)
+		}
+
+		// The slug column is unique, so there is at most one row whatever its status
+		var reservation models.TenantSlugReservation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("slug = ?", slug).
+			First(&reservation).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to load slug reservation: %w", err)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fn(tx, nil)
+		}
+		return fn(tx, &reservation)
+	})
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+)
+
+// GetTenantBySlugRedirect returns the tenant a former slug still redirects to, or nil when the
+// slug has no unexpired redirect. Redirected lookups are not cached, so they stop resolving
+// as soon as the redirect expires.
+func (r *MembershipRepository) GetTenantBySlugRedirect(ctx context.Context, slug string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	err := r.db.WithContext(ctx).
+		Select("tenants.*").
+		Joins("JOIN tenant_slug_redirects ON tenant_slug_redirects.tenant_id = tenants.id").
+		Where("tenant_slug_redirects.old_slug = ? AND tenant_slug_redirects.expires_at > ?", slug, time.Now()).
+		First(&tenant).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve slug redirect: %w", err)
+	}
+	return &tenant, nil
+}
+
+// ReleaseTenantSlug releases one slug the tenant holds, leaving its other reservations active.
+// Used for a renamed tenant's former slug once its redirect expires.
+func (r *MembershipRepository) ReleaseTenantSlug(ctx context.Context, slug string, tenantID uuid.UUID) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).
+		Model(&models.TenantSlugReservation{}).
+		Where("slug = ? AND tenant_id = ? AND status = ?", slug, tenantID, models.SlugReservationActive).
+		Updates(map[string]interface{}{
+			"status":      models.SlugReservationReleased,
+			"released_at": now,
+			"updated_at":  now,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to release tenant slug: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+)
+
+// SSOConfigRepository handles tenant SSO configuration database operations
+type SSOConfigRepository struct {
+	db *gorm.DB
+}
+
+// NewSSOConfigRepository creates a new SSO config repository
+func NewSSOConfigRepository(db *gorm.DB) *SSOConfigRepository {
+	return &SSOConfigRepository{db: db}
+}
+
+// GetByTenantID retrieves a tenant's SSO configuration, or nil if it has none
+func (r *SSOConfigRepository) GetByTenantID(ctx context.Context, tenantID uuid.UUID) (*models.TenantSSOConfig, error) {
+	var config models.TenantSSOConfig
+	err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).First(&config).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SSO config: %w", err)
+	}
+	return &config, nil
+}
+
+// Save creates or updates a tenant's SSO configuration
+func (r *SSOConfigRepository) Save(ctx context.Context, config *models.TenantSSOConfig) error {
+	if err := r.db.WithContext(ctx).Save(config).Error; err != nil {
+		return fmt.Errorf("failed to save SSO config: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a tenant's SSO configuration
+func (r *SSOConfigRepository) Delete(ctx context.Context, tenantID uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Delete(&models.TenantSSOConfig{}).Error; err != nil {
+		return fmt.Errorf("failed to delete SSO config: %w", err)
+	}
+	return nil
+}
+
+// FindDomainOwner returns the ID of another tenant whose SSO configuration claims the email
+// domain, or uuid.Nil if no other tenant does
+func (r *SSOConfigRepository) FindDomainOwner(ctx context.Context, domain string, excludeTenantID uuid.UUID) (uuid.UUID, error) {
+	contains, err := json.Marshal([]string{domain})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	var config models.TenantSSOConfig
+	err = r.db.WithContext(ctx).Select("tenant_id").
+		Where("tenant_id <> ? AND domains @> ?", excludeTenantID, string(contains)).
+		First(&config).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to check SSO domain: %w", err)
+	}
+	return config.TenantID, nil
+}
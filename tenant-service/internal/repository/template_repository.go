@@ -2,13 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"tenant-service/internal/listquery"
 	"tenant-service/internal/models"
 	"gorm.io/gorm"
 )
 
+// ErrTemplateNotFound is returned when an onboarding template does not exist
+var ErrTemplateNotFound = errors.New("onboarding template not found")
+
 // TemplateRepository handles onboarding template operations
 type TemplateRepository struct {
 	db *gorm.DB
@@ -27,7 +33,21 @@ func (r *TemplateRepository) CreateTemplate(ctx context.Context, template *model
 		template.ID = uuid.New()
 	}
 
-	if err := r.db.WithContext(ctx).Create(template).Error; err != nil {
+	if template.Version < 1 {
+		template.Version = 1
+	}
+
+	// Create the template together with its first published version
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(template).Error; err != nil {
+			return err
+		}
+		version := models.NewTemplateVersion(template, template.Version, models.TemplateVersionStatusPublished)
+		now := time.Now()
+		version.PublishedAt = &now
+		return tx.Create(version).Error
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to create onboarding template: %w", err)
 	}
 
@@ -40,7 +60,7 @@ func (r *TemplateRepository) GetTemplateByID(ctx context.Context, id uuid.UUID)
 
 	if err := r.db.WithContext(ctx).First(&template, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("onboarding template not found")
+			return nil, ErrTemplateNotFound
 		}
 		return nil, fmt.Errorf("failed to get onboarding template: %w", err)
 	}
@@ -96,7 +116,13 @@ func (r *TemplateRepository) UpdateTemplate(ctx context.Context, template *model
 
 // DeleteTemplate deletes an onboarding template
 func (r *TemplateRepository) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Delete(&models.OnboardingTemplate{}, id).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&models.OnboardingTemplateVersion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.OnboardingTemplate{}, id).Error
+	})
+	if err != nil {
 		return fmt.Errorf("failed to delete onboarding template: %w", err)
 	}
 
@@ -104,36 +130,26 @@ func (r *TemplateRepository) DeleteTemplate(ctx context.Context, id uuid.UUID) e
 }
 
 // ListTemplates lists templates with pagination and filters
-func (r *TemplateRepository) ListTemplates(ctx context.Context, page, pageSize int, filters map[string]interface{}) ([]models.OnboardingTemplate, int64, error) {
+func (r *TemplateRepository) ListTemplates(ctx context.Context, q *listquery.Query) ([]models.OnboardingTemplate, int64, string, error) {
 	var templates []models.OnboardingTemplate
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&models.OnboardingTemplate{})
-
-	// Apply filters
-	for field, value := range filters {
-		switch field {
-		case "application_type":
-			query = query.Where("application_type = ?", value)
-		case "is_active":
-			query = query.Where("is_active = ?", value)
-		case "is_default":
-			query = query.Where("is_default = ?", value)
-		}
-	}
+	query := r.db.WithContext(ctx).Model(&models.OnboardingTemplate{}).Scopes(q.FilterScope())
 
 	// Count total
 	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to count templates: %w", err)
+		return nil, 0, "", fmt.Errorf("failed to count templates: %w", err)
 	}
 
-	// Apply pagination
-	offset := (page - 1) * pageSize
-	if err := query.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&templates).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to list templates: %w", err)
+	if err := query.Scopes(q.PageScope()).Find(&templates).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to list templates: %w", err)
 	}
 
-	return templates, total, nil
+	templates, next, err := listquery.NextPage(q, templates)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return templates, total, next, nil
 }
 
 // SetDefaultTemplate sets a template as default for its application type
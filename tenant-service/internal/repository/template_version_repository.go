@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+)
+
+// EnsureBaselineVersion records the template's current content as its published version
+// Templates created before versioning have no version rows; they get one on first use.
+func (r *TemplateRepository) EnsureBaselineVersion(ctx context.Context, template *models.OnboardingTemplate) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.OnboardingTemplateVersion{}).
+		Where("template_id = ?", template.ID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to count template versions: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	version := template.Version
+	if version < 1 {
+		version = 1
+	}
+	baseline := models.NewTemplateVersion(template, version, models.TemplateVersionStatusPublished)
+	publishedAt := template.UpdatedAt
+	baseline.PublishedAt = &publishedAt
+	if err := r.db.WithContext(ctx).Create(baseline).Error; err != nil {
+		return fmt.Errorf("failed to create baseline template version: %w", err)
+	}
+	return nil
+}
+
+// ListTemplateVersions returns all versions of a template, newest first
+func (r *TemplateRepository) ListTemplateVersions(ctx context.Context, templateID uuid.UUID) ([]models.OnboardingTemplateVersion, error) {
+	var versions []models.OnboardingTemplateVersion
+	if err := r.db.WithContext(ctx).Where("template_id = ?", templateID).
+		Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list template versions: %w", err)
+	}
+	return versions, nil
+}
+
+// GetTemplateVersion retrieves one version of a template, or nil if it does not exist
+func (r *TemplateRepository) GetTemplateVersion(ctx context.Context, templateID uuid.UUID, version int) (*models.OnboardingTemplateVersion, error) {
+	var v models.OnboardingTemplateVersion
+	err := r.db.WithContext(ctx).Where("template_id = ? AND version = ?", templateID, version).First(&v).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template version: %w", err)
+	}
+	return &v, nil
+}
+
+// GetDraftVersion retrieves the unpublished draft of a template, or nil if there is none
+func (r *TemplateRepository) GetDraftVersion(ctx context.Context, templateID uuid.UUID) (*models.OnboardingTemplateVersion, error) {
+	var v models.OnboardingTemplateVersion
+	err := r.db.WithContext(ctx).Where("template_id = ? AND status = ?", templateID, models.TemplateVersionStatusDraft).
+		First(&v).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template draft: %w", err)
+	}
+	return &v, nil
+}
+
+// CreateDraftVersion stores a new draft numbered after the latest version
+func (r *TemplateRepository) CreateDraftVersion(ctx context.Context, draft *models.OnboardingTemplateVersion) (*models.OnboardingTemplateVersion, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&models.OnboardingTemplateVersion{}).Where("template_id = ?", draft.TemplateID).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		draft.Version = latest + 1
+		draft.Status = models.TemplateVersionStatusDraft
+		return tx.Create(draft).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template draft: %w", err)
+	}
+	return draft, nil
+}
+
+// UpdateTemplateVersion saves changes to a template version
+func (r *TemplateRepository) UpdateTemplateVersion(ctx context.Context, version *models.OnboardingTemplateVersion) (*models.OnboardingTemplateVersion, error) {
+	if err := r.db.WithContext(ctx).Save(version).Error; err != nil {
+		return nil, fmt.Errorf("failed to update template version: %w", err)
+	}
+	return version, nil
+}
+
+// DeleteTemplateVersion deletes a template version
+func (r *TemplateRepository) DeleteTemplateVersion(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Delete(&models.OnboardingTemplateVersion{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete template version: %w", err)
+	}
+	return nil
+}
+
+// PublishTemplateVersion publishes a draft: the previously published version is archived and
+// the template's content is replaced with the draft's, so new sessions start on it
+func (r *TemplateRepository) PublishTemplateVersion(ctx context.Context, template *models.OnboardingTemplate, draft *models.OnboardingTemplateVersion) (*models.OnboardingTemplate, error) {
+	now := time.Now()
+	published := draft.Apply(template)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.OnboardingTemplateVersion{}).
+			Where("template_id = ? AND status = ?", template.ID, models.TemplateVersionStatusPublished).
+			Updates(map[string]interface{}{
+				"status":      models.TemplateVersionStatusArchived,
+				"archived_at": now,
+			}).Error; err != nil {
+			return err
+		}
+
+		draft.Status = models.TemplateVersionStatusPublished
+		draft.PublishedAt = &now
+		if err := tx.Save(draft).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.OnboardingTemplate{}).Where("id = ?", template.ID).Updates(map[string]interface{}{
+			"version":         published.Version,
+			"name":            published.Name,
+			"description":     published.Description,
+			"template_config": published.TemplateConfig,
+			"steps":           published.Steps,
+			"custom_fields":   published.CustomFields,
+			"updated_at":      now,
+		}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish template version: %w", err)
+	}
+
+	published.UpdatedAt = now
+	return published, nil
+}
+
+// GetTemplateForSession returns the template with the content of the version a session is pinned to
+// Sessions started before versioning (version 0) use the currently published content.
+func (r *TemplateRepository) GetTemplateForSession(ctx context.Context, templateID uuid.UUID, version int) (*models.OnboardingTemplate, error) {
+	template, err := r.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 || version == template.Version {
+		return template, nil
+	}
+
+	pinned, err := r.GetTemplateVersion(ctx, templateID, version)
+	if err != nil {
+		return nil, err
+	}
+	if pinned == nil {
+		// Version rows are only missing if they were deleted by hand; fall back to the live content
+		return template, nil
+	}
+	return pinned.Apply(template), nil
+}
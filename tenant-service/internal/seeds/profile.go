@@ -0,0 +1,348 @@
+// Package seeds loads the declarative seed profiles of default onboarding templates and
+// reserved slugs. Profiles are JSON files named after the environment they apply to; the
+// ones in profiles/ are built into the binary and a directory on disk (SEED_PROFILE_DIR)
+// can add or override profiles without a new build.
+package seeds
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"tenant-service/internal/models"
+)
+
+//go:embed profiles/*.json
+var embeddedProfiles embed.FS
+
+// BaseProfile is the profile every environment falls back to when it has none of its own
+const BaseProfile = "base"
+
+// maxExtendsDepth bounds profile inheritance chains
+const maxExtendsDepth = 5
+
+var (
+	// ErrProfileNotFound is returned when no profile with the requested name exists
+	ErrProfileNotFound = errors.New("seed profile not found")
+	// ErrInvalidProfile is returned when a profile cannot be parsed or declares invalid entries
+	ErrInvalidProfile = errors.New("invalid seed profile")
+)
+
+var (
+	profileNamePattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+	reservedSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+)
+
+// Profile is a declarative set of default templates and reserved slugs
+// A profile can extend another one: its entries replace the parent's entries with the same
+// key (templates) or slug (reserved slugs) and everything else is inherited.
+type Profile struct {
+	Name          string             `json:"name"`
+	Extends       string             `json:"extends,omitempty"`
+	Templates     []TemplateSeed     `json:"templates"`
+	ReservedSlugs []ReservedSlugSeed `json:"reserved_slugs"`
+}
+
+// TemplateSeed declares an onboarding template
+// Key identifies the template across profile revisions; renaming the template keeps the key.
+// Metadata is only written when the template is created.
+type TemplateSeed struct {
+	Key             string          `json:"key"`
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	ApplicationType string          `json:"application_type"`
+	IsDefault       bool            `json:"is_default"`
+	Active          *bool           `json:"active,omitempty"` // Defaults to true; false retires the template
+	TemplateConfig  json.RawMessage `json:"template_config,omitempty"`
+	Steps           json.RawMessage `json:"steps,omitempty"`
+	CustomFields    json.RawMessage `json:"custom_fields,omitempty"`
+	Metadata        json.RawMessage `json:"metadata,omitempty"`
+}
+
+// ReservedSlugSeed declares a reserved slug
+type ReservedSlugSeed struct {
+	Slug     string `json:"slug"`
+	Reason   string `json:"reason"`
+	Category string `json:"category"`
+	Active   *bool  `json:"active,omitempty"` // Defaults to true; false releases the slug
+}
+
+// IsActive reports whether the template is offered for onboarding
+func (t TemplateSeed) IsActive() bool {
+	return t.Active == nil || *t.Active
+}
+
+// IsActive reports whether the slug is reserved
+func (s ReservedSlugSeed) IsActive() bool {
+	return s.Active == nil || *s.Active
+}
+
+// Checksum identifies the declared content of the template
+func (t TemplateSeed) Checksum() string {
+	return checksum(t)
+}
+
+// Checksum identifies the declared content of the reserved slug
+func (s ReservedSlugSeed) Checksum() string {
+	return checksum(s)
+}
+
+// Model returns the onboarding template the seed creates
+func (t TemplateSeed) Model() *models.OnboardingTemplate {
+	return &models.OnboardingTemplate{
+		Name:            t.Name,
+		Description:     t.Description,
+		ApplicationType: t.ApplicationType,
+		Version:         1,
+		IsActive:        t.IsActive(),
+		IsDefault:       t.IsDefault,
+		TemplateConfig:  jsonOrDefault(t.TemplateConfig, "{}"),
+		Steps:           jsonOrDefault(t.Steps, "[]"),
+		CustomFields:    jsonOrDefault(t.CustomFields, "[]"),
+		Metadata:        jsonOrDefault(t.Metadata, "{}"),
+	}
+}
+
+// Checksum identifies the resolved content of the profile, whatever its name
+func (p *Profile) Checksum() string {
+	return checksum(struct {
+		Templates     []TemplateSeed     `json:"templates"`
+		ReservedSlugs []ReservedSlugSeed `json:"reserved_slugs"`
+	}{p.Templates, p.ReservedSlugs})
+}
+
+// Loader reads profiles from an optional directory, falling back to the built-in ones
+type Loader struct {
+	dir string
+}
+
+// NewLoader creates a profile loader; profiles in dir take precedence over built-in profiles
+func NewLoader(dir string) *Loader {
+	return &Loader{dir: dir}
+}
+
+// Load reads the named profile, resolves what it extends and validates the result
+func (l *Loader) Load(name string) (*Profile, error) {
+	profile, err := l.resolve(name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidProfile, name, err)
+	}
+	return profile, nil
+}
+
+func (l *Loader) resolve(name string, chain []string) (*Profile, error) {
+	for _, seen := range chain {
+		if seen == name {
+			return nil, fmt.Errorf("%w: %q extends itself through %s", ErrInvalidProfile, name, strings.Join(chain, " -> "))
+		}
+	}
+	if len(chain) > maxExtendsDepth {
+		return nil, fmt.Errorf("%w: %q extends more than %d profiles", ErrInvalidProfile, chain[0], maxExtendsDepth)
+	}
+
+	data, err := l.read(name)
+	if err != nil {
+		return nil, err
+	}
+	var profile Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidProfile, name, err)
+	}
+	profile.Name = name
+	if profile.Extends == "" {
+		return &profile, nil
+	}
+
+	parent, err := l.resolve(profile.Extends, append(chain, name))
+	if err != nil {
+		return nil, err
+	}
+	return Merge(parent, &profile), nil
+}
+
+func (l *Loader) read(name string) ([]byte, error) {
+	if !profileNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: invalid name %q", ErrProfileNotFound, name)
+	}
+	file := name + ".json"
+
+	if l.dir != "" {
+		data, err := os.ReadFile(filepath.Join(l.dir, file))
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read seed profile %q: %w", name, err)
+		}
+	}
+
+	data, err := embeddedProfiles.ReadFile("profiles/" + file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed profile %q: %w", name, err)
+	}
+	return data, nil
+}
+
+// Merge returns the child profile with the parent's entries it does not override
+// Overridden entries keep the parent's position so the merged order is stable.
+func Merge(parent, child *Profile) *Profile {
+	merged := &Profile{Name: child.Name, Extends: child.Extends}
+
+	templates := make(map[string]TemplateSeed, len(child.Templates))
+	for _, t := range child.Templates {
+		templates[t.Key] = t
+	}
+	for _, t := range parent.Templates {
+		if override, ok := templates[t.Key]; ok {
+			merged.Templates = append(merged.Templates, override)
+			delete(templates, t.Key)
+			continue
+		}
+		merged.Templates = append(merged.Templates, t)
+	}
+	for _, t := range child.Templates {
+		if _, ok := templates[t.Key]; ok {
+			merged.Templates = append(merged.Templates, t)
+		}
+	}
+
+	slugs := make(map[string]ReservedSlugSeed, len(child.ReservedSlugs))
+	for _, s := range child.ReservedSlugs {
+		slugs[s.Slug] = s
+	}
+	for _, s := range parent.ReservedSlugs {
+		if override, ok := slugs[s.Slug]; ok {
+			merged.ReservedSlugs = append(merged.ReservedSlugs, override)
+			delete(slugs, s.Slug)
+			continue
+		}
+		merged.ReservedSlugs = append(merged.ReservedSlugs, s)
+	}
+	for _, s := range child.ReservedSlugs {
+		if _, ok := slugs[s.Slug]; ok {
+			merged.ReservedSlugs = append(merged.ReservedSlugs, s)
+		}
+	}
+
+	return merged
+}
+
+// Validate checks the entries of a resolved profile
+func (p *Profile) Validate() error {
+	templateKeys := make(map[string]bool, len(p.Templates))
+	defaults := make(map[string]string)
+	for _, t := range p.Templates {
+		if t.Key == "" {
+			return fmt.Errorf("template %q has no key", t.Name)
+		}
+		if templateKeys[t.Key] {
+			return fmt.Errorf("template key %q is declared twice", t.Key)
+		}
+		templateKeys[t.Key] = true
+
+		if t.Name == "" {
+			return fmt.Errorf("template %q has no name", t.Key)
+		}
+		switch t.ApplicationType {
+		case "ecommerce", "saas", "marketplace", "b2b":
+		default:
+			return fmt.Errorf("template %q has unsupported application type %q", t.Key, t.ApplicationType)
+		}
+		if err := checkJSONKind(t.TemplateConfig, '{'); err != nil {
+			return fmt.Errorf("template %q template_config: %w", t.Key, err)
+		}
+		if err := checkJSONKind(t.Steps, '['); err != nil {
+			return fmt.Errorf("template %q steps: %w", t.Key, err)
+		}
+		if err := checkJSONKind(t.CustomFields, '['); err != nil {
+			return fmt.Errorf("template %q custom_fields: %w", t.Key, err)
+		}
+		if err := checkJSONKind(t.Metadata, '{'); err != nil {
+			return fmt.Errorf("template %q metadata: %w", t.Key, err)
+		}
+
+		if t.IsDefault && t.IsActive() {
+			if other, ok := defaults[t.ApplicationType]; ok {
+				return fmt.Errorf("templates %q and %q are both the default for %s", other, t.Key, t.ApplicationType)
+			}
+			defaults[t.ApplicationType] = t.Key
+		}
+	}
+
+	slugs := make(map[string]bool, len(p.ReservedSlugs))
+	for _, s := range p.ReservedSlugs {
+		if !reservedSlugPattern.MatchString(s.Slug) {
+			return fmt.Errorf("reserved slug %q is not a valid slug", s.Slug)
+		}
+		if slugs[s.Slug] {
+			return fmt.Errorf("reserved slug %q is declared twice", s.Slug)
+		}
+		slugs[s.Slug] = true
+
+		if s.Reason == "" {
+			return fmt.Errorf("reserved slug %q has no reason", s.Slug)
+		}
+		switch s.Category {
+		case models.ReservedSlugCategorySystem, models.ReservedSlugCategoryBrand,
+			models.ReservedSlugCategoryInfrastructure, models.ReservedSlugCategoryOffensive:
+		default:
+			return fmt.Errorf("reserved slug %q has unsupported category %q", s.Slug, s.Category)
+		}
+	}
+
+	return nil
+}
+
+// checkJSONKind checks that a declared JSON value is an object ('{') or an array ('[')
+func checkJSONKind(data json.RawMessage, open byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" || trimmed == "null" {
+		return nil
+	}
+	if trimmed[0] != open {
+		if open == '{' {
+			return errors.New("must be a JSON object")
+		}
+		return errors.New("must be a JSON array")
+	}
+	return nil
+}
+
+// checksum hashes the canonical JSON of v: object keys sorted, insignificant whitespace dropped
+func checksum(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	var canonical interface{}
+	if err := json.Unmarshal(data, &canonical); err == nil {
+		if normalized, err := json.Marshal(canonical); err == nil {
+			data = normalized
+		}
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func jsonOrDefault(data json.RawMessage, fallback string) models.JSONB {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil || compact.Len() == 0 || compact.String() == "null" {
+		return models.JSONB(fallback)
+	}
+	return models.JSONB(compact.Bytes())
+}
@@ -0,0 +1,546 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+)
+
+var (
+	// ErrBillingWebhookDisabled is returned when no webhook secret is configured
+	ErrBillingWebhookDisabled = errors.New("billing webhook is not configured")
+	// ErrBillingSignatureInvalid is returned for unsigned, mis-signed or stale webhooks
+	ErrBillingSignatureInvalid = errors.New("invalid billing webhook signature")
+)
+
+// BillingWebhookEvent is a subscription event sent by the billing provider
+type BillingWebhookEvent struct {
+	ID        string           `json:"id"`   // The provider's event ID; redeliveries reuse it
+	Type      string           `json:"type"` // trial_started, plan_changed, payment_failed, payment_succeeded, subscription_cancelled
+	CreatedAt time.Time        `json:"created_at"`
+	Data      BillingEventData `json:"data"`
+}
+
+// BillingEventData is the subscription an event is about. The tenant is found by TenantID,
+// or by CustomerID once an earlier event linked the customer to the tenant.
+type BillingEventData struct {
+	TenantID         string     `json:"tenant_id"`
+	CustomerID       string     `json:"customer_id"`
+	SubscriptionID   string     `json:"subscription_id"`
+	PlanCode         string     `json:"plan_code"`
+	BillingCycle     string     `json:"billing_cycle"`
+	TrialEndsAt      *time.Time `json:"trial_ends_at"`
+	CurrentPeriodEnd *time.Time `json:"current_period_end"`
+}
+
+// BillingEventResult reports what a webhook changed
+type BillingEventResult struct {
+	EventID       string     `json:"event_id"`
+	TenantID      *uuid.UUID `json:"tenant_id,omitempty"`
+	Duplicate     bool       `json:"duplicate"`
+	BillingStatus string     `json:"billing_status,omitempty"`
+	Result        string     `json:"result"`
+}
+
+// BillingService applies billing provider subscription events to tenants. The provider's state
+// is cached on the tenant, plan changes go through the SubscriptionService, and tenants whose
+// payment stays failed past the grace period are suspended, which revokes every feature.
+type BillingService struct {
+	db            *gorm.DB
+	subscriptions *SubscriptionService
+	config        config.BillingConfig
+}
+
+// NewBillingService creates a new billing service
+func NewBillingService(db *gorm.DB, subscriptions *SubscriptionService, cfg config.BillingConfig) *BillingService {
+	return &BillingService{
+		db:            db,
+		subscriptions: subscriptions,
+		config:        cfg,
+	}
+}
+
+// SuspensionCheckInterval returns the interval of the past-due suspension job
+func (s *BillingService) SuspensionCheckInterval() time.Duration {
+	if s.config.SuspensionCheckIntervalMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(s.config.SuspensionCheckIntervalMinutes) * time.Minute
+}
+
+// SignBillingPayload returns the signature of a webhook body: hex HMAC-SHA256 of "<timestamp>.<body>"
+func SignBillingPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyBillingSignature checks a webhook was signed with secret no longer than tolerance before now.
+// signature may carry a "sha256=" prefix.
+func VerifyBillingSignature(secret string, payload []byte, timestamp, signature string, tolerance time.Duration, now time.Time) error {
+	if secret == "" {
+		return ErrBillingWebhookDisabled
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrBillingSignatureInvalid
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrBillingSignatureInvalid
+	}
+
+	expected := SignBillingPayload(secret, timestamp, payload)
+	signature = strings.TrimPrefix(strings.TrimSpace(signature), "sha256=")
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return ErrBillingSignatureInvalid
+	}
+	return nil
+}
+
+// HandleWebhook verifies and applies a billing provider webhook. Redelivered events are
+// acknowledged without being applied again.
+func (s *BillingService) HandleWebhook(ctx context.Context, payload []byte, timestamp, signature string) (*BillingEventResult, error) {
+	tolerance := time.Duration(s.config.SignatureToleranceSeconds) * time.Second
+	if tolerance <= 0 {
+		tolerance = 5 * time.Minute
+	}
+	if err := VerifyBillingSignature(s.config.WebhookSecret, payload, timestamp, signature, tolerance, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var event BillingWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, NewValidationError("body", "invalid billing event: "+err.Error(), nil)
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, NewValidationError("id", "billing event id and type are required", nil)
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	var processed int64
+	if err := s.db.WithContext(ctx).Model(&models.BillingEvent{}).Where("provider_id = ?", event.ID).Count(&processed).Error; err != nil {
+		return nil, fmt.Errorf("failed to check billing event: %w", err)
+	}
+	if processed > 0 {
+		return &BillingEventResult{EventID: event.ID, Duplicate: true, Result: "already processed"}, nil
+	}
+
+	result, err := s.ApplyEvent(ctx, &event)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.BillingEvent{
+		ProviderID:  event.ID,
+		Type:        event.Type,
+		TenantID:    result.TenantID,
+		Payload:     models.JSONB(payload),
+		Result:      result.Result,
+		ProcessedAt: time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(record).Error; err != nil {
+		// The event is applied; a redelivery re-applies it, which every handler tolerates
+		log.Printf("[BillingService] Failed to record billing event %s: %v", event.ID, err)
+	}
+	return result, nil
+}
+
+// ApplyEvent applies a billing event to its tenant. Events older than the tenant's last
+// billing update are ignored, since providers do not guarantee delivery order.
+func (s *BillingService) ApplyEvent(ctx context.Context, event *BillingWebhookEvent) (*BillingEventResult, error) {
+	result := &BillingEventResult{EventID: event.ID}
+
+	tenant, err := s.findTenant(ctx, event.Data)
+	if err != nil {
+		return nil, err
+	}
+	result.TenantID = &tenant.ID
+	result.BillingStatus = tenant.BillingStatus
+
+	if tenant.Status == models.TenantStatusPendingDeletion {
+		result.Result = "ignored: tenant is pending deletion"
+		return result, nil
+	}
+	if tenant.BillingUpdatedAt != nil && event.CreatedAt.Before(*tenant.BillingUpdatedAt) {
+		result.Result = "ignored: older than the tenant's billing state"
+		return result, nil
+	}
+
+	switch event.Type {
+	case models.BillingEventTrialStarted:
+		err = s.applyTrialStarted(ctx, tenant, event, result)
+	case models.BillingEventPlanChanged:
+		err = s.applyPlanChanged(ctx, tenant, event, result)
+	case models.BillingEventPaymentFailed:
+		err = s.applyPaymentFailed(ctx, tenant, event, result)
+	case models.BillingEventPaymentSucceeded:
+		err = s.applyPaymentSucceeded(ctx, tenant, event, result)
+	case models.BillingEventSubscriptionCancelled:
+		err = s.applyCancelled(ctx, tenant, event, result)
+	default:
+		result.Result = fmt.Sprintf("ignored: unhandled event type %q", event.Type)
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[BillingService] Tenant %s %s: %s", tenant.ID, event.Type, result.Result)
+	return result, nil
+}
+
+// SuspendOverdueTenants suspends past-due tenants whose grace period ended
+func (s *BillingService) SuspendOverdueTenants(ctx context.Context) (int, error) {
+	cutoff := time.Now().AddDate(0, 0, -s.graceDays())
+	var overdue []models.Tenant
+	if err := s.db.WithContext(ctx).
+		Select("id").
+		Where("billing_status = ? AND payment_failed_at <= ?", models.BillingStatusPastDue, cutoff).
+		Limit(500).
+		Find(&overdue).Error; err != nil {
+		return 0, fmt.Errorf("failed to find overdue tenants: %w", err)
+	}
+
+	suspended := 0
+	for _, tenant := range overdue {
+		changed, err := s.suspend(ctx, tenant.ID)
+		if err != nil {
+			log.Printf("[BillingService] Failed to suspend tenant %s: %v", tenant.ID, err)
+			continue
+		}
+		if changed {
+			suspended++
+		}
+	}
+	return suspended, nil
+}
+
+// applyTrialStarted starts a trial of the event's plan
+func (s *BillingService) applyTrialStarted(ctx context.Context, tenant *models.Tenant, event *BillingWebhookEvent, result *BillingEventResult) error {
+	if event.Data.TrialEndsAt == nil || !event.Data.TrialEndsAt.After(time.Now()) {
+		return NewValidationError("trial_ends_at", "trial_started requires a future trial_ends_at", nil)
+	}
+	trialDays := int(math.Ceil(time.Until(*event.Data.TrialEndsAt).Hours() / 24))
+	if trialDays > MaxTrialDays {
+		trialDays = MaxTrialDays
+	}
+
+	plan, err := s.applyProviderPlan(ctx, tenant.ID, event.Data, trialDays, models.SubscriptionChangeTrialStarted)
+	if err != nil {
+		return err
+	}
+
+	if err := s.saveState(ctx, tenant.ID, event, map[string]interface{}{
+		"billing_status":    models.BillingStatusTrialing,
+		"payment_failed_at": nil,
+	}); err != nil {
+		return err
+	}
+	result.BillingStatus = models.BillingStatusTrialing
+	result.Result = fmt.Sprintf("trial of %s for %d days", plan.Code, trialDays)
+	return nil
+}
+
+// applyPlanChanged moves the tenant to the event's plan. Member quotas are not checked: the
+// provider has already billed the change, and the usage job reports tenants over quota.
+func (s *BillingService) applyPlanChanged(ctx context.Context, tenant *models.Tenant, event *BillingWebhookEvent, result *BillingEventResult) error {
+	plan, err := s.applyProviderPlan(ctx, tenant.ID, event.Data, 0, "")
+	if err != nil {
+		return err
+	}
+
+	// A plan change does not settle an outstanding payment
+	status := tenant.BillingStatus
+	if status != models.BillingStatusPastDue && status != models.BillingStatusSuspended {
+		status = models.BillingStatusActive
+	}
+	if err := s.saveState(ctx, tenant.ID, event, map[string]interface{}{"billing_status": status}); err != nil {
+		return err
+	}
+	result.BillingStatus = status
+	result.Result = "plan changed to " + plan.Code
+	return nil
+}
+
+// applyPaymentFailed marks the tenant past due; it is suspended once the grace period ends
+func (s *BillingService) applyPaymentFailed(ctx context.Context, tenant *models.Tenant, event *BillingWebhookEvent, result *BillingEventResult) error {
+	if tenant.BillingStatus == models.BillingStatusPastDue || tenant.BillingStatus == models.BillingStatusSuspended {
+		// Dunning retries keep the first failure, so they do not extend the grace period
+		if err := s.saveState(ctx, tenant.ID, event, map[string]interface{}{}); err != nil {
+			return err
+		}
+		result.Result = "already " + tenant.BillingStatus
+		return nil
+	}
+
+	if err := s.saveState(ctx, tenant.ID, event, map[string]interface{}{
+		"billing_status":    models.BillingStatusPastDue,
+		"payment_failed_at": event.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	result.BillingStatus = models.BillingStatusPastDue
+	result.Result = fmt.Sprintf("past due, suspended after %d days", s.graceDays())
+
+	if s.graceDays() == 0 {
+		if _, err := s.suspend(ctx, tenant.ID); err != nil {
+			return err
+		}
+		result.BillingStatus = models.BillingStatusSuspended
+		result.Result = "suspended"
+	}
+	return nil
+}
+
+// applyPaymentSucceeded settles a past-due tenant and reinstates a suspended one
+func (s *BillingService) applyPaymentSucceeded(ctx context.Context, tenant *models.Tenant, event *BillingWebhookEvent, result *BillingEventResult) error {
+	wasSuspended := tenant.IsBillingSuspended()
+	if err := s.saveState(ctx, tenant.ID, event, map[string]interface{}{
+		"billing_status":    models.BillingStatusActive,
+		"payment_failed_at": nil,
+	}); err != nil {
+		return err
+	}
+	result.BillingStatus = models.BillingStatusActive
+	result.Result = "payment received"
+
+	if wasSuspended {
+		if err := s.reinstate(ctx, tenant.ID, models.SubscriptionChangeReinstated); err != nil {
+			return err
+		}
+		result.Result = "payment received, reinstated"
+	}
+	return nil
+}
+
+// applyCancelled moves the tenant to the default plan. Nothing is owed any more, so a
+// suspended tenant is reinstated on that plan.
+func (s *BillingService) applyCancelled(ctx context.Context, tenant *models.Tenant, event *BillingWebhookEvent, result *BillingEventResult) error {
+	plan, err := s.subscriptions.planByCode(s.db.WithContext(ctx), s.subscriptions.defaultPlanCode())
+	if err != nil {
+		return fmt.Errorf("failed to load default plan: %w", err)
+	}
+	err = s.subscriptions.applyPlan(ctx, tenant.ID, plan, models.BillingCycleMonthly, 0, nil, models.SubscriptionChangeCancelled, false)
+	if err != nil && !errors.Is(err, ErrPlanUnchanged) {
+		return err
+	}
+
+	wasSuspended := tenant.IsBillingSuspended()
+	if err := s.saveState(ctx, tenant.ID, event, map[string]interface{}{
+		"billing_status":    models.BillingStatusCancelled,
+		"payment_failed_at": nil,
+	}); err != nil {
+		return err
+	}
+	if wasSuspended {
+		if err := s.reinstate(ctx, tenant.ID, models.SubscriptionChangeCancelled); err != nil {
+			return err
+		}
+	}
+	result.BillingStatus = models.BillingStatusCancelled
+	result.Result = "cancelled, moved to " + plan.Code
+	return nil
+}
+
+// applyProviderPlan applies the event's plan through the subscription service
+func (s *BillingService) applyProviderPlan(ctx context.Context, tenantID uuid.UUID, data BillingEventData, trialDays int, change string) (*models.TenantPlan, error) {
+	code := strings.ToLower(strings.TrimSpace(data.PlanCode))
+	if code == "" {
+		return nil, NewValidationError("plan_code", "plan_code is required", nil)
+	}
+	cycle := data.BillingCycle
+	if cycle == "" {
+		cycle = models.BillingCycleMonthly
+	}
+	if cycle != models.BillingCycleMonthly && cycle != models.BillingCycleYearly {
+		return nil, NewValidationError("billing_cycle", "billing_cycle must be monthly or yearly", nil)
+	}
+
+	plan, err := s.subscriptions.planByCode(s.db.WithContext(ctx), code)
+	if err != nil {
+		return nil, err
+	}
+	err = s.subscriptions.applyPlan(ctx, tenantID, plan, cycle, trialDays, nil, change, false)
+	if err != nil && !errors.Is(err, ErrPlanUnchanged) {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// saveState caches the provider's subscription state on the tenant
+func (s *BillingService) saveState(ctx context.Context, tenantID uuid.UUID, event *BillingWebhookEvent, updates map[string]interface{}) error {
+	updates["billing_updated_at"] = event.CreatedAt
+	if event.Data.CustomerID != "" {
+		updates["billing_customer_id"] = event.Data.CustomerID
+	}
+	if event.Data.SubscriptionID != "" {
+		updates["billing_subscription_id"] = event.Data.SubscriptionID
+	}
+	if event.Data.CurrentPeriodEnd != nil {
+		updates["billing_period_ends_at"] = event.Data.CurrentPeriodEnd
+	}
+
+	if err := s.db.WithContext(ctx).Model(&models.Tenant{}).Where("id = ?", tenantID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to save billing state: %w", err)
+	}
+	s.invalidate(ctx, tenantID)
+	return nil
+}
+
+// suspend suspends a past-due tenant; it reports false when the tenant was no longer past due
+func (s *BillingService) suspend(ctx context.Context, tenantID uuid.UUID) (bool, error) {
+	suspended := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tenant models.Tenant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status", "billing_status").
+			First(&tenant, "id = ?", tenantID).Error; err != nil {
+			return fmt.Errorf("failed to lock tenant: %w", err)
+		}
+		if tenant.BillingStatus != models.BillingStatusPastDue || tenant.Status == models.TenantStatusPendingDeletion {
+			return nil
+		}
+
+		// billing_updated_at is left alone: a payment made before the suspension still reinstates
+		updates := map[string]interface{}{"billing_status": models.BillingStatusSuspended}
+		if tenant.Status != models.TenantStatusSuspended {
+			updates["status"] = models.TenantStatusSuspended
+			updates["status_before_suspension"] = tenant.Status
+		}
+		if err := tx.Model(&models.Tenant{}).Where("id = ?", tenantID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to suspend tenant: %w", err)
+		}
+		suspended = true
+		return s.logChange(tx, tenantID, models.SubscriptionChangeSuspended)
+	})
+	if err != nil || !suspended {
+		return false, err
+	}
+
+	log.Printf("[BillingService] Suspended tenant %s after unpaid grace period", tenantID)
+	s.invalidate(ctx, tenantID)
+	s.publishChange(ctx, tenantID, models.SubscriptionChangeSuspended)
+	return true, nil
+}
+
+// reinstate restores the status a tenant had before billing suspended it
+func (s *BillingService) reinstate(ctx context.Context, tenantID uuid.UUID, change string) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tenant models.Tenant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status", "status_before_suspension").
+			First(&tenant, "id = ?", tenantID).Error; err != nil {
+			return fmt.Errorf("failed to lock tenant: %w", err)
+		}
+		if tenant.Status != models.TenantStatusSuspended {
+			return nil
+		}
+
+		status := tenant.StatusBeforeSuspension
+		if status == "" {
+			status = "active"
+		}
+		if err := tx.Model(&models.Tenant{}).Where("id = ?", tenantID).Updates(map[string]interface{}{
+			"status":                   status,
+			"status_before_suspension": "",
+		}).Error; err != nil {
+			return fmt.Errorf("failed to reinstate tenant: %w", err)
+		}
+		return s.logChange(tx, tenantID, change)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[BillingService] Reinstated tenant %s (%s)", tenantID, change)
+	s.invalidate(ctx, tenantID)
+	s.publishChange(ctx, tenantID, change)
+	return nil
+}
+
+// logChange writes a billing suspension or reinstatement to the tenant activity log
+func (s *BillingService) logChange(tx *gorm.DB, tenantID uuid.UUID, change string) error {
+	details, _ := models.NewJSONB(map[string]interface{}{"change": change, "source": "billing"})
+	if err := tx.Create(&models.TenantActivityLog{
+		TenantID:     tenantID,
+		UserID:       uuid.Nil,
+		Action:       models.ActivitySubscriptionChanged,
+		ResourceType: "subscription",
+		Details:      details,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to log billing change: %w", err)
+	}
+	return nil
+}
+
+// publishChange tells services caching feature flags to refresh them after a suspension or reinstatement
+func (s *BillingService) publishChange(ctx context.Context, tenantID uuid.UUID, change string) {
+	info, err := s.subscriptions.GetSubscription(ctx, tenantID)
+	if err != nil {
+		log.Printf("[BillingService] Failed to load subscription of tenant %s: %v", tenantID, err)
+		return
+	}
+	s.subscriptions.publishPlanChanged(ctx, tenantID, info.Plan, info.Plan.Code, info.Status, change, info.TrialEndsAt)
+}
+
+// findTenant resolves the event's tenant by ID or by the provider's customer ID
+func (s *BillingService) findTenant(ctx context.Context, data BillingEventData) (*models.Tenant, error) {
+	query := s.db.WithContext(ctx).Select("id", "status", "billing_status", "billing_updated_at")
+
+	var tenant models.Tenant
+	var err error
+	switch {
+	case data.TenantID != "":
+		tenantID, parseErr := uuid.Parse(data.TenantID)
+		if parseErr != nil {
+			return nil, NewValidationError("tenant_id", "invalid tenant_id", nil)
+		}
+		err = query.First(&tenant, "id = ?", tenantID).Error
+	case data.CustomerID != "":
+		err = query.First(&tenant, "billing_customer_id = ?", data.CustomerID).Error
+	default:
+		return nil, NewValidationError("tenant_id", "tenant_id or customer_id is required", nil)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("tenant not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	return &tenant, nil
+}
+
+// invalidate drops cached tenant lookups after the billing state changed
+func (s *BillingService) invalidate(ctx context.Context, tenantID uuid.UUID) {
+	if s.subscriptions.cache != nil {
+		s.subscriptions.cache.InvalidateTenantCache(ctx, tenantID)
+	}
+}
+
+// graceDays returns the days a past-due tenant keeps its plan
+func (s *BillingService) graceDays() int {
+	if s.config.PaymentGraceDays < 0 {
+		return 0
+	}
+	return s.config.PaymentGraceDays
+}
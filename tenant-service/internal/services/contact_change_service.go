@@ -0,0 +1,470 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tenant-service/internal/clients"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+// ContactChangeVerificationPurpose is the verification-service purpose of contact change codes
+const ContactChangeVerificationPurpose = "tenant_contact_change"
+
+var (
+	// ErrContactChangeForbidden is returned when the user is not an owner or admin of the tenant
+	ErrContactChangeForbidden = errors.New("only tenant owners and admins can change the business contact")
+	// ErrContactChangeNotFound is returned for unknown changes or changes of another tenant
+	ErrContactChangeNotFound = errors.New("contact change not found")
+	// ErrContactChangeNotPending is returned for expired, cancelled or already verified changes
+	ErrContactChangeNotPending = errors.New("contact change is no longer pending")
+	// ErrContactChangeInvalidCode is returned when verification-service rejects the code
+	ErrContactChangeInvalidCode = errors.New("invalid or expired verification code")
+	// ErrContactChangeStale is returned when the contact was changed since the change was requested
+	ErrContactChangeStale = errors.New("the business contact was changed since this change was requested")
+	// ErrContactVerificationUnavailable is returned when no verification client is configured
+	ErrContactVerificationUnavailable = errors.New("contact verification is not available")
+)
+
+// e164Pattern matches phone numbers in E.164 format
+var e164Pattern = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+
+// RequestContactChangeRequest asks to change the business contact email or phone
+type RequestContactChangeRequest struct {
+	Field            string `json:"field" binding:"required,oneof=email phone"`
+	Value            string `json:"value" binding:"required"`
+	PhoneCountryCode string `json:"phone_country_code"` // ISO country code of a new phone (e.g., AU, US, IN)
+}
+
+// VerifyContactChangeRequest confirms a contact change with the code sent to the new value
+type VerifyContactChangeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TenantContact is the tenant's active business contact and its pending changes
+type TenantContact struct {
+	TenantID         uuid.UUID                    `json:"tenant_id"`
+	Email            string                       `json:"email"`
+	Phone            string                       `json:"phone"`
+	PhoneCountryCode string                       `json:"phone_country_code"`
+	BillingEmail     string                       `json:"billing_email"`
+	PendingChanges   []models.TenantContactChange `json:"pending_changes"`
+}
+
+// ContactChangeResult is a pending contact change and its verification code
+type ContactChangeResult struct {
+	Change          *models.TenantContactChange `json:"change"`
+	CodeExpiresAt   time.Time                   `json:"code_expires_at"`
+	ResendInSeconds *int                        `json:"resend_in_seconds,omitempty"`
+}
+
+// ContactChangeService changes the tenant's business contact email and phone. A change only
+// takes effect once the code sent to the new value is verified through verification-service;
+// until then the old value stays active.
+type ContactChangeService struct {
+	db                 *gorm.DB
+	membershipRepo     *repository.MembershipRepository
+	verificationClient *clients.VerificationClient
+}
+
+// NewContactChangeService creates a new contact change service. membershipRepo should be the
+// cache-enabled repository so members do not see the old contact from stale lookups.
+func NewContactChangeService(db *gorm.DB, membershipRepo *repository.MembershipRepository, verificationClient *clients.VerificationClient) *ContactChangeService {
+	return &ContactChangeService{
+		db:                 db,
+		membershipRepo:     membershipRepo,
+		verificationClient: verificationClient,
+	}
+}
+
+// AuthorizeChange checks the user is an owner or admin of the tenant
+func (s *ContactChangeService) AuthorizeChange(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrContactChangeForbidden
+	}
+	return nil
+}
+
+// NormalizeContactValue normalizes a requested email (trimmed, lowercased) or phone (E.164,
+// separators removed) and checks it differs from the current value
+func NormalizeContactValue(field, current, requested string) (string, error) {
+	var value string
+	switch field {
+	case models.ContactFieldEmail:
+		value = strings.ToLower(strings.TrimSpace(requested))
+		if addr, err := mail.ParseAddress(value); err != nil || addr.Address != value {
+			return "", NewValidationError("value", "Enter a valid email address", nil)
+		}
+		if strings.EqualFold(value, strings.TrimSpace(current)) {
+			return "", NewValidationError("value", "The new email is the same as the current one", nil)
+		}
+	case models.ContactFieldPhone:
+		value = normalizePhone(requested)
+		if !e164Pattern.MatchString(value) {
+			return "", NewValidationError("value", "Enter the phone number in international format, e.g. +61412345678", nil)
+		}
+		if value == normalizePhone(current) {
+			return "", NewValidationError("value", "The new phone number is the same as the current one", nil)
+		}
+	default:
+		return "", NewValidationError("field", "field must be email or phone", nil)
+	}
+	return value, nil
+}
+
+// normalizePhone removes spaces, dashes, dots and parentheses from a phone number
+func normalizePhone(phone string) string {
+	return strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(strings.TrimSpace(phone))
+}
+
+// GetContact returns the tenant's active business contact and its pending changes
+func (s *ContactChangeService) GetContact(ctx context.Context, tenantID uuid.UUID) (*TenantContact, error) {
+	var tenant models.Tenant
+	if err := s.db.WithContext(ctx).First(&tenant, "id = ?", tenantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("tenant not found")
+		}
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	contact, err := primaryContact(s.db.WithContext(ctx), tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TenantContact{
+		TenantID:       tenantID,
+		Email:          tenant.BillingEmail,
+		BillingEmail:   tenant.BillingEmail,
+		PendingChanges: []models.TenantContactChange{},
+	}
+	if contact != nil {
+		result.Email = contact.Email
+		result.Phone = contact.Phone
+		result.PhoneCountryCode = contact.PhoneCountryCode
+	}
+
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND verified_at IS NULL AND cancelled_at IS NULL AND expires_at > ?", tenantID, time.Now()).
+		Order("created_at DESC").
+		Find(&result.PendingChanges).Error; err != nil {
+		return nil, fmt.Errorf("failed to get pending contact changes: %w", err)
+	}
+	return result, nil
+}
+
+// RequestChange sends a verification code to the new email or phone and records the pending
+// change. A new request for the same field cancels the pending one.
+func (s *ContactChangeService) RequestChange(ctx context.Context, tenantID uuid.UUID, req RequestContactChangeRequest, requestedBy uuid.UUID) (*ContactChangeResult, error) {
+	if s.verificationClient == nil {
+		return nil, ErrContactVerificationUnavailable
+	}
+
+	current, err := s.GetContact(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	oldValue := current.Email
+	if req.Field == models.ContactFieldPhone {
+		if current.Phone == "" {
+			return nil, NewValidationError("field", "This tenant has no business contact phone to change", nil)
+		}
+		oldValue = current.Phone
+	}
+	newValue, err := NormalizeContactValue(req.Field, oldValue, req.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &models.TenantContactChange{
+		TenantID:    tenantID,
+		Field:       req.Field,
+		OldValue:    oldValue,
+		NewValue:    newValue,
+		RequestedBy: requestedBy,
+	}
+	if req.Field == models.ContactFieldPhone {
+		change.PhoneCountryCode = strings.ToUpper(strings.TrimSpace(req.PhoneCountryCode))
+	}
+
+	// Sent first: a code for a change that failed to save is harmless, a saved change
+	// without a code is not
+	code, err := s.verificationClient.SendCode(ctx, &clients.SendVerificationCodeRequest{
+		Recipient: newValue,
+		Channel:   contactChannel(req.Field),
+		Purpose:   ContactChangeVerificationPurpose,
+		TenantID:  &tenantID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&models.TenantContactChange{}).
+			Where("tenant_id = ? AND field = ? AND verified_at IS NULL AND cancelled_at IS NULL", tenantID, req.Field).
+			Update("cancelled_at", now).Error; err != nil {
+			return fmt.Errorf("failed to cancel pending contact change: %w", err)
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return fmt.Errorf("failed to create contact change: %w", err)
+		}
+
+		details, _ := models.NewJSONB(map[string]interface{}{
+			"field":     req.Field,
+			"before":    oldValue,
+			"requested": newValue,
+		})
+		if err := tx.Create(&models.TenantActivityLog{
+			TenantID:     tenantID,
+			UserID:       requestedBy,
+			Action:       models.ActivityTenantContactChangeRequested,
+			ResourceType: "tenant",
+			ResourceID:   &tenantID,
+			Details:      details,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to log contact change request: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContactChangeResult{
+		Change:          change,
+		CodeExpiresAt:   code.ExpiresAt,
+		ResendInSeconds: code.ResendIn,
+	}, nil
+}
+
+// ResendCode sends a new verification code for a pending change
+func (s *ContactChangeService) ResendCode(ctx context.Context, tenantID, changeID uuid.UUID) (*ContactChangeResult, error) {
+	if s.verificationClient == nil {
+		return nil, ErrContactVerificationUnavailable
+	}
+	change, err := s.getPendingChange(ctx, tenantID, changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.verificationClient.ResendCode(ctx, &clients.ResendCodeRequest{
+		Recipient: change.NewValue,
+		Channel:   contactChannel(change.Field),
+		Purpose:   ContactChangeVerificationPurpose,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resend verification code: %w", err)
+	}
+	return &ContactChangeResult{
+		Change:          change,
+		CodeExpiresAt:   code.ExpiresAt,
+		ResendInSeconds: code.ResendIn,
+	}, nil
+}
+
+// VerifyChange checks the code with verification-service and commits the change, logging the
+// before and after values. The change is rejected if the contact was changed in the meantime.
+func (s *ContactChangeService) VerifyChange(ctx context.Context, tenantID, changeID uuid.UUID, code string, verifiedBy uuid.UUID) (*TenantContact, error) {
+	if s.verificationClient == nil {
+		return nil, ErrContactVerificationUnavailable
+	}
+	change, err := s.getPendingChange(ctx, tenantID, changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	verification, err := s.verificationClient.VerifyCode(ctx, &clients.VerifyCodeRequest{
+		Recipient: change.NewValue,
+		Code:      strings.TrimSpace(code),
+		Purpose:   ContactChangeVerificationPurpose,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify code: %w", err)
+	}
+	if !verification.Verified {
+		return nil, ErrContactChangeInvalidCode
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var locked models.TenantContactChange
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&locked, "id = ? AND tenant_id = ?", changeID, tenantID).Error; err != nil {
+			return fmt.Errorf("failed to lock contact change: %w", err)
+		}
+		// Checked again under the lock in case a concurrent verify or cancel won
+		if !locked.IsPending(now) {
+			return ErrContactChangeNotPending
+		}
+
+		var tenant models.Tenant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tenant, "id = ?", tenantID).Error; err != nil {
+			return fmt.Errorf("failed to lock tenant: %w", err)
+		}
+		contact, err := primaryContact(tx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		if err := applyContactChange(tx, &tenant, contact, &locked, now); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&locked).Updates(map[string]interface{}{
+			"verified_at": now,
+			"updated_at":  now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to mark contact change verified: %w", err)
+		}
+
+		details, _ := models.NewJSONB(map[string]interface{}{
+			"field":     locked.Field,
+			"before":    locked.OldValue,
+			"after":     locked.NewValue,
+			"change_id": locked.ID,
+		})
+		if err := tx.Create(&models.TenantActivityLog{
+			TenantID:     tenantID,
+			UserID:       verifiedBy,
+			Action:       models.ActivityTenantContactChanged,
+			ResourceType: "tenant",
+			ResourceID:   &tenantID,
+			Details:      details,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to log contact change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[ContactChangeService] Tenant %s business contact %s changed by %s", tenantID, change.Field, verifiedBy)
+
+	// Cached memberships embed the tenant, including its billing email
+	s.membershipRepo.InvalidateTenantCache(ctx, tenantID)
+	return s.GetContact(ctx, tenantID)
+}
+
+// CancelChange cancels a pending change; the old value stays active
+func (s *ContactChangeService) CancelChange(ctx context.Context, tenantID, changeID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Model(&models.TenantContactChange{}).
+		Where("id = ? AND tenant_id = ? AND verified_at IS NULL AND cancelled_at IS NULL", changeID, tenantID).
+		Update("cancelled_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to cancel contact change: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrContactChangeNotFound
+	}
+	return nil
+}
+
+// getPendingChange returns a change of the tenant that can still be verified
+func (s *ContactChangeService) getPendingChange(ctx context.Context, tenantID, changeID uuid.UUID) (*models.TenantContactChange, error) {
+	var change models.TenantContactChange
+	err := s.db.WithContext(ctx).First(&change, "id = ? AND tenant_id = ?", changeID, tenantID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrContactChangeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact change: %w", err)
+	}
+	if !change.IsPending(time.Now()) {
+		return nil, ErrContactChangeNotPending
+	}
+	return &change, nil
+}
+
+// applyContactChange writes the new value to the primary contact. A new email also becomes the
+// billing email when billing went to the old contact email, as it does after onboarding.
+func applyContactChange(tx *gorm.DB, tenant *models.Tenant, contact *models.ContactInformation, change *models.TenantContactChange, now time.Time) error {
+	switch change.Field {
+	case models.ContactFieldEmail:
+		current := tenant.BillingEmail
+		if contact != nil {
+			current = contact.Email
+		}
+		if !strings.EqualFold(current, change.OldValue) {
+			return ErrContactChangeStale
+		}
+		if contact != nil {
+			if err := tx.Model(contact).Updates(map[string]interface{}{
+				"email":      change.NewValue,
+				"updated_at": now,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to update contact email: %w", err)
+			}
+		}
+		if tenant.BillingEmail == "" || strings.EqualFold(tenant.BillingEmail, change.OldValue) {
+			if err := tx.Model(&models.Tenant{}).Where("id = ?", tenant.ID).Updates(map[string]interface{}{
+				"billing_email": change.NewValue,
+				"updated_at":    now,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to update billing email: %w", err)
+			}
+		}
+	case models.ContactFieldPhone:
+		if contact == nil || contact.Phone != change.OldValue {
+			return ErrContactChangeStale
+		}
+		updates := map[string]interface{}{
+			"phone":      change.NewValue,
+			"updated_at": now,
+		}
+		if change.PhoneCountryCode != "" {
+			updates["phone_country_code"] = change.PhoneCountryCode
+		}
+		if err := tx.Model(contact).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update contact phone: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown contact field %q", change.Field)
+	}
+	return nil
+}
+
+// primaryContact returns the primary contact of the tenant's completed onboarding session,
+// or nil for tenants created without onboarding
+func primaryContact(db *gorm.DB, tenantID uuid.UUID) (*models.ContactInformation, error) {
+	var session models.OnboardingSession
+	err := db.Where("tenant_id = ? AND status = ?", tenantID, "completed").
+		Preload("ContactInformation").
+		Order("completed_at DESC").
+		First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get onboarding session for tenant: %w", err)
+	}
+
+	for i := range session.ContactInformation {
+		if session.ContactInformation[i].IsPrimaryContact {
+			return &session.ContactInformation[i], nil
+		}
+	}
+	if len(session.ContactInformation) > 0 {
+		return &session.ContactInformation[0], nil
+	}
+	return nil, nil
+}
+
+// contactChannel returns the verification channel of a contact field
+func contactChannel(field string) string {
+	if field == models.ContactFieldPhone {
+		return "sms"
+	}
+	return "email"
+}
@@ -0,0 +1,466 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+// customFieldKeyPattern restricts custom field keys to safe JSON object keys
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// customFieldDateLayout is the storage format of date custom fields
+const customFieldDateLayout = "2006-01-02"
+
+// CustomFieldError describes why a single custom field value was rejected
+type CustomFieldError struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// CustomFieldValidationError is returned when submitted custom field values are invalid
+type CustomFieldValidationError struct {
+	Errors []CustomFieldError `json:"errors"`
+}
+
+func (e *CustomFieldValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", fe.Key, fe.Message))
+	}
+	return "invalid custom fields: " + strings.Join(msgs, "; ")
+}
+
+// IsCustomFieldValidationError checks if an error is a CustomFieldValidationError
+func IsCustomFieldValidationError(err error) (*CustomFieldValidationError, bool) {
+	var fieldErr *CustomFieldValidationError
+	if errors.As(err, &fieldErr) {
+		return fieldErr, true
+	}
+	return nil, false
+}
+
+// ValidateCustomFieldDefinitions checks a template's custom field definitions
+func ValidateCustomFieldDefinitions(defs []models.CustomFieldDefinition) error {
+	seen := make(map[string]bool, len(defs))
+	for i, def := range defs {
+		if !customFieldKeyPattern.MatchString(def.Key) {
+			return NewValidationError("custom_fields", fmt.Sprintf("field %d: key %q must be lowercase letters, digits and underscores, starting with a letter", i, def.Key), nil)
+		}
+		if seen[def.Key] {
+			return NewValidationError("custom_fields", fmt.Sprintf("duplicate field key %q", def.Key), nil)
+		}
+		seen[def.Key] = true
+
+		if strings.TrimSpace(def.Label) == "" {
+			return NewValidationError("custom_fields", fmt.Sprintf("field %q: label is required", def.Key), nil)
+		}
+
+		switch def.Type {
+		case models.CustomFieldTypeText, models.CustomFieldTypeEmail, models.CustomFieldTypeURL,
+			models.CustomFieldTypeNumber, models.CustomFieldTypeBoolean, models.CustomFieldTypeDate:
+		case models.CustomFieldTypeSelect, models.CustomFieldTypeMultiSelect:
+			if len(def.Options) == 0 {
+				return NewValidationError("custom_fields", fmt.Sprintf("field %q: %s fields require options", def.Key, def.Type), nil)
+			}
+		default:
+			return NewValidationError("custom_fields", fmt.Sprintf("field %q: unsupported type %q", def.Key, def.Type), nil)
+		}
+
+		if def.Pattern != "" {
+			if _, err := regexp.Compile(def.Pattern); err != nil {
+				return NewValidationError("custom_fields", fmt.Sprintf("field %q: invalid pattern: %v", def.Key, err), nil)
+			}
+		}
+		if def.MinLength != nil && def.MaxLength != nil && *def.MinLength > *def.MaxLength {
+			return NewValidationError("custom_fields", fmt.Sprintf("field %q: min_length exceeds max_length", def.Key), nil)
+		}
+		if def.Min != nil && def.Max != nil && *def.Min > *def.Max {
+			return NewValidationError("custom_fields", fmt.Sprintf("field %q: min exceeds max", def.Key), nil)
+		}
+	}
+	return nil
+}
+
+// ValidateCustomFieldValues validates submitted values against the step's field definitions
+// It returns the values normalized to their field types, ready to be stored as JSONB.
+func ValidateCustomFieldValues(defs []models.CustomFieldDefinition, values map[string]interface{}) (map[string]interface{}, error) {
+	normalized := make(map[string]interface{}, len(defs))
+	var fieldErrs []CustomFieldError
+
+	known := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		known[def.Key] = true
+
+		raw, present := values[def.Key]
+		if !present || isEmptyCustomFieldValue(raw) {
+			if def.Required {
+				fieldErrs = append(fieldErrs, CustomFieldError{Key: def.Key, Message: fmt.Sprintf("%s is required", def.Label)})
+			}
+			continue
+		}
+
+		value, err := normalizeCustomFieldValue(def, raw)
+		if err != nil {
+			fieldErrs = append(fieldErrs, CustomFieldError{Key: def.Key, Message: err.Error()})
+			continue
+		}
+		normalized[def.Key] = value
+	}
+
+	for key := range values {
+		if !known[key] {
+			fieldErrs = append(fieldErrs, CustomFieldError{Key: key, Message: "unknown field for this onboarding step"})
+		}
+	}
+
+	if len(fieldErrs) > 0 {
+		return nil, &CustomFieldValidationError{Errors: fieldErrs}
+	}
+	return normalized, nil
+}
+
+// ParseCustomFieldQueryValue converts a query string value to the field's storage type
+func ParseCustomFieldQueryValue(def models.CustomFieldDefinition, raw string) (interface{}, error) {
+	switch def.Type {
+	case models.CustomFieldTypeMultiSelect:
+		// Querying a multiselect matches a single option
+		return normalizeCustomFieldValue(models.CustomFieldDefinition{Type: models.CustomFieldTypeSelect, Options: def.Options}, raw)
+	case models.CustomFieldTypeText, models.CustomFieldTypeEmail, models.CustomFieldTypeURL:
+		// Query values are not subject to length or pattern rules
+		return raw, nil
+	default:
+		return normalizeCustomFieldValue(models.CustomFieldDefinition{Type: def.Type, Options: def.Options}, raw)
+	}
+}
+
+func normalizeCustomFieldValue(def models.CustomFieldDefinition, raw interface{}) (interface{}, error) {
+	switch def.Type {
+	case models.CustomFieldTypeNumber:
+		n, err := customFieldNumber(raw)
+		if err != nil {
+			return nil, err
+		}
+		if def.Min != nil && n < *def.Min {
+			return nil, fmt.Errorf("must be at least %v", *def.Min)
+		}
+		if def.Max != nil && n > *def.Max {
+			return nil, fmt.Errorf("must be at most %v", *def.Max)
+		}
+		return n, nil
+
+	case models.CustomFieldTypeBoolean:
+		switch v := raw.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.New("must be true or false")
+			}
+			return b, nil
+		}
+		return nil, errors.New("must be true or false")
+
+	case models.CustomFieldTypeDate:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("must be a date (YYYY-MM-DD)")
+		}
+		if t, err := time.Parse(customFieldDateLayout, s); err == nil {
+			return t.Format(customFieldDateLayout), nil
+		}
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t.Format(customFieldDateLayout), nil
+		}
+		return nil, errors.New("must be a date (YYYY-MM-DD)")
+
+	case models.CustomFieldTypeSelect:
+		s, ok := raw.(string)
+		if !ok || !containsOption(def.Options, s) {
+			return nil, fmt.Errorf("must be one of: %s", strings.Join(def.Options, ", "))
+		}
+		return s, nil
+
+	case models.CustomFieldTypeMultiSelect:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return nil, errors.New("must be a list of options")
+		}
+		selected := make([]string, 0, len(items))
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok || !containsOption(def.Options, s) {
+				return nil, fmt.Errorf("options must be among: %s", strings.Join(def.Options, ", "))
+			}
+			if !containsOption(selected, s) {
+				selected = append(selected, s)
+			}
+		}
+		return selected, nil
+
+	default: // text, email, url
+		s, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("must be text")
+		}
+		s = strings.TrimSpace(s)
+		length := utf8.RuneCountInString(s)
+		if def.MinLength != nil && length < *def.MinLength {
+			return nil, fmt.Errorf("must be at least %d characters", *def.MinLength)
+		}
+		if def.MaxLength != nil && length > *def.MaxLength {
+			return nil, fmt.Errorf("must be at most %d characters", *def.MaxLength)
+		}
+		if def.Pattern != "" {
+			re, err := regexp.Compile(def.Pattern)
+			if err != nil || !re.MatchString(s) {
+				return nil, errors.New("has an invalid format")
+			}
+		}
+		switch def.Type {
+		case models.CustomFieldTypeEmail:
+			if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+				return nil, errors.New("must be a valid email address")
+			}
+		case models.CustomFieldTypeURL:
+			if u, err := url.ParseRequestURI(s); err != nil || u.Host == "" {
+				return nil, errors.New("must be a valid URL")
+			}
+		}
+		return s, nil
+	}
+}
+
+func customFieldNumber(raw interface{}) (float64, error) {
+	var n float64
+	switch v := raw.(type) {
+	case float64:
+		n = v
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, errors.New("must be a number")
+		}
+		n = f
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, errors.New("must be a number")
+		}
+		n = f
+	default:
+		return 0, errors.New("must be a number")
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, errors.New("must be a number")
+	}
+	return n, nil
+}
+
+func isEmptyCustomFieldValue(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(val) == ""
+	case []interface{}:
+		return len(val) == 0
+	}
+	return false
+}
+
+func containsOption(options []string, value string) bool {
+	for _, o := range options {
+		if o == value {
+			return true
+		}
+	}
+	return false
+}
+
+// customFieldOps lists the query operators supported by each field type
+var customFieldOps = map[string][]string{
+	models.CustomFieldTypeText:        {repository.CustomFieldOpEq, repository.CustomFieldOpNe, repository.CustomFieldOpContains, repository.CustomFieldOpExists},
+	models.CustomFieldTypeEmail:       {repository.CustomFieldOpEq, repository.CustomFieldOpNe, repository.CustomFieldOpContains, repository.CustomFieldOpExists},
+	models.CustomFieldTypeURL:         {repository.CustomFieldOpEq, repository.CustomFieldOpNe, repository.CustomFieldOpContains, repository.CustomFieldOpExists},
+	models.CustomFieldTypeSelect:      {repository.CustomFieldOpEq, repository.CustomFieldOpNe, repository.CustomFieldOpExists},
+	models.CustomFieldTypeMultiSelect: {repository.CustomFieldOpContains, repository.CustomFieldOpExists},
+	models.CustomFieldTypeBoolean:     {repository.CustomFieldOpEq, repository.CustomFieldOpNe, repository.CustomFieldOpExists},
+	models.CustomFieldTypeNumber:      {repository.CustomFieldOpEq, repository.CustomFieldOpNe, repository.CustomFieldOpGt, repository.CustomFieldOpGte, repository.CustomFieldOpLt, repository.CustomFieldOpLte, repository.CustomFieldOpExists},
+	models.CustomFieldTypeDate:        {repository.CustomFieldOpEq, repository.CustomFieldOpNe, repository.CustomFieldOpGt, repository.CustomFieldOpGte, repository.CustomFieldOpLt, repository.CustomFieldOpLte, repository.CustomFieldOpExists},
+}
+
+// CustomFieldValue pairs a custom field definition with a session's typed answer
+type CustomFieldValue struct {
+	models.CustomFieldDefinition
+	Value interface{} `json:"value"`
+}
+
+// CustomFieldMatch is a session whose custom field matched a query
+type CustomFieldMatch struct {
+	OnboardingSessionID uuid.UUID   `json:"onboarding_session_id"`
+	BusinessName        string      `json:"business_name"`
+	TenantSlug          string      `json:"tenant_slug,omitempty"`
+	Value               interface{} `json:"value"`
+}
+
+// GetSessionCustomFields returns the session template's custom fields with their typed answers
+func (s *OnboardingService) GetSessionCustomFields(ctx context.Context, sessionID uuid.UUID) ([]CustomFieldValue, error) {
+	session, err := s.onboardingRepo.GetSessionByID(ctx, sessionID, []string{"business_information"})
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	template, err := s.templateRepo.GetTemplateForSession(ctx, session.TemplateID, session.TemplateVersion)
+	if err != nil {
+		return nil, err
+	}
+	defs, err := template.CustomFieldDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	answers := map[string]map[string]interface{}{}
+	if session.BusinessInformation != nil && len(session.BusinessInformation.CustomFields) > 0 {
+		values := map[string]interface{}{}
+		if err := json.Unmarshal(session.BusinessInformation.CustomFields, &values); err == nil {
+			answers[models.CustomFieldStepBusinessInformation] = values
+		}
+	}
+
+	fields := make([]CustomFieldValue, 0, len(defs))
+	for _, def := range defs {
+		fields = append(fields, CustomFieldValue{
+			CustomFieldDefinition: def,
+			Value:                 answers[def.BoundStep()][def.Key],
+		})
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].BoundStep() != fields[j].BoundStep() {
+			return fields[i].BoundStep() < fields[j].BoundStep()
+		}
+		return fields[i].Order < fields[j].Order
+	})
+
+	return fields, nil
+}
+
+// QueryCustomFields finds a template's sessions by a custom field value
+// The value is parsed according to the field type, so numbers and dates compare by value.
+func (s *OnboardingService) QueryCustomFields(ctx context.Context, templateID uuid.UUID, key, op, rawValue string, page, pageSize int) ([]CustomFieldMatch, int64, error) {
+	template, err := s.templateRepo.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defs, err := template.CustomFieldDefinitions()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var def *models.CustomFieldDefinition
+	for i := range defs {
+		if defs[i].Key == key {
+			def = &defs[i]
+			break
+		}
+	}
+	if def == nil {
+		return nil, 0, NewValidationError("key", fmt.Sprintf("template has no custom field %q", key), nil)
+	}
+	if def.BoundStep() != models.CustomFieldStepBusinessInformation {
+		return nil, 0, NewValidationError("key", fmt.Sprintf("custom field %q is not stored on business information", key), nil)
+	}
+
+	if op == "" {
+		op = repository.CustomFieldOpEq
+		if def.Type == models.CustomFieldTypeMultiSelect {
+			op = repository.CustomFieldOpContains
+		}
+	}
+	if !containsOption(customFieldOps[def.Type], op) {
+		return nil, 0, NewValidationError("op", fmt.Sprintf("operator %q is not supported for %s fields", op, def.Type), customFieldOps[def.Type])
+	}
+
+	filter := repository.CustomFieldFilter{Key: def.Key, Type: def.Type, Op: op}
+	if op != repository.CustomFieldOpExists {
+		value, err := ParseCustomFieldQueryValue(*def, rawValue)
+		if err != nil {
+			return nil, 0, NewValidationError("value", err.Error(), def.Options)
+		}
+		filter.Value = value
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	results, total, err := s.businessRepo.FindByCustomField(ctx, templateID, filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matches := make([]CustomFieldMatch, 0, len(results))
+	for _, bi := range results {
+		values := map[string]interface{}{}
+		_ = json.Unmarshal(bi.CustomFields, &values)
+		matches = append(matches, CustomFieldMatch{
+			OnboardingSessionID: bi.OnboardingSessionID,
+			BusinessName:        bi.BusinessName,
+			TenantSlug:          bi.TenantSlug,
+			Value:               values[def.Key],
+		})
+	}
+	return matches, total, nil
+}
+
+// validateStepCustomFields validates submitted custom field answers for a step
+// against the session's pinned template version and returns them normalized for storage
+func (s *OnboardingService) validateStepCustomFields(ctx context.Context, session *models.OnboardingSession, step string, submitted models.JSONB) (models.JSONB, error) {
+	values := map[string]interface{}{}
+	if len(submitted) > 0 && string(submitted) != "null" {
+		if err := json.Unmarshal(submitted, &values); err != nil {
+			return nil, &CustomFieldValidationError{Errors: []CustomFieldError{{Key: "custom_fields", Message: "must be an object keyed by field key"}}}
+		}
+	}
+
+	template, err := s.templateRepo.GetTemplateForSession(ctx, session.TemplateID, session.TemplateVersion)
+	if err != nil {
+		if len(values) == 0 {
+			// Nothing to validate; don't block the step on a template lookup
+			log.Printf("[OnboardingService] Warning: could not load template %s for custom fields: %v", session.TemplateID, err)
+			return models.NewJSONB(values)
+		}
+		return nil, fmt.Errorf("failed to load onboarding template: %w", err)
+	}
+
+	defs, err := template.CustomFieldsForStep(step)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := ValidateCustomFieldValues(defs, values)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewJSONB(normalized)
+}
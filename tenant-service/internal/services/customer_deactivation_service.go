@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"tenant-service/internal/clients"
 	"tenant-service/internal/models"
+	natsClient "tenant-service/internal/nats"
 	"tenant-service/internal/repository"
 	"github.com/Tesseract-Nexus/go-shared/auth"
 	"gorm.io/gorm"
@@ -20,8 +21,14 @@ type CustomerDeactivationService struct {
 	db                 *gorm.DB
 	membershipRepo     *repository.MembershipRepository
 	notificationClient *clients.NotificationClient
-	keycloakClient     *auth.KeycloakAdminClient
+	keycloakClient     *timedKeycloakClient
 	keycloakConfig     *KeycloakAuthConfig
+	eventPublisher     CustomerPurgedPublisher
+}
+
+// CustomerPurgedPublisher publishes customer.purged events
+type CustomerPurgedPublisher interface {
+	PublishCustomerPurged(ctx context.Context, event *natsClient.CustomerPurgedEvent) error
 }
 
 // NewCustomerDeactivationService creates a new customer deactivation service
@@ -36,11 +43,16 @@ func NewCustomerDeactivationService(
 		db:                 db,
 		membershipRepo:     membershipRepo,
 		notificationClient: notificationClient,
-		keycloakClient:     keycloakClient,
+		keycloakClient:     newTimedKeycloakClient(keycloakClient),
 		keycloakConfig:     keycloakConfig,
 	}
 }
 
+// SetEventPublisher sets the publisher used to announce purged accounts
+func (s *CustomerDeactivationService) SetEventPublisher(publisher CustomerPurgedPublisher) {
+	s.eventPublisher = publisher
+}
+
 // DeactivateCustomerRequest represents a customer self-deactivation request
 type DeactivateCustomerRequest struct {
 	UserID   uuid.UUID `json:"user_id"`
@@ -165,6 +177,7 @@ func (s *CustomerDeactivationService) DeactivateCustomer(ctx context.Context, re
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	s.membershipRepo.InvalidateUserMembershipsCache(ctx, req.UserID)
 
 	// Send goodbye email asynchronously
 	if s.notificationClient != nil {
@@ -330,6 +343,7 @@ func (s *CustomerDeactivationService) ReactivateCustomer(ctx context.Context, re
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	s.membershipRepo.InvalidateUserMembershipsCache(ctx, deactivated.UserID)
 
 	log.Printf("[CustomerDeactivationService] Account reactivated for %s on tenant %s", req.Email, req.TenantSlug)
 
@@ -415,5 +429,18 @@ func (s *CustomerDeactivationService) purgeAccount(ctx context.Context, account
 	}
 
 	log.Printf("[CustomerDeactivationService] Purged account: %s (tenant: %s)", account.Email, account.TenantID)
+
+	// Let downstream services pseudonymize the customer; the purge itself has already committed
+	if s.eventPublisher != nil {
+		event := &natsClient.CustomerPurgedEvent{
+			TenantID:      account.TenantID.String(),
+			Timestamp:     now,
+			CustomerID:    account.UserID.String(),
+			CustomerEmail: account.Email,
+		}
+		if err := s.eventPublisher.PublishCustomerPurged(ctx, event); err != nil {
+			log.Printf("[CustomerDeactivationService] Warning: Failed to publish customer.purged event for tenant %s: %v", account.TenantID, err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,623 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/clients"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+var (
+	// ErrJoinDomainForbidden is returned when the user may not manage the tenant's join domains
+	ErrJoinDomainForbidden = errors.New("only tenant owners and admins can manage join domains")
+	// ErrJoinDomainNotFound is returned when the join domain does not exist for the tenant
+	ErrJoinDomainNotFound = errors.New("join domain not found")
+	// ErrJoinRequestNotFound is returned when the join request does not exist for the tenant
+	ErrJoinRequestNotFound = errors.New("join request not found")
+	// ErrJoinDomainVerificationUnavailable is returned when the verification backend is not configured
+	ErrJoinDomainVerificationUnavailable = errors.New("domain verification is unavailable")
+)
+
+const (
+	joinDomainTXTPrefix = "_tesserix-join."
+	joinDomainTXTValue  = "tesserix-join="
+)
+
+// publicEmailDomains are mailbox providers no tenant can claim: anyone can get an address there
+var publicEmailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "yahoo.com": true, "outlook.com": true,
+	"hotmail.com": true, "live.com": true, "icloud.com": true, "me.com": true,
+	"aol.com": true, "proton.me": true, "protonmail.com": true, "gmx.com": true,
+	"yandex.com": true, "mail.com": true, "zoho.com": true,
+}
+
+// joinDomainRoles are the roles a domain may grant; owner and admin always need an invitation
+var joinDomainRoles = map[string]bool{
+	models.MembershipRoleManager: true,
+	models.MembershipRoleMember:  true,
+	models.MembershipRoleViewer:  true,
+}
+
+// AddJoinDomainRequest claims an email domain for auto-join
+type AddJoinDomainRequest struct {
+	Domain             string `json:"domain" binding:"required"`
+	VerificationMethod string `json:"verification_method" binding:"required"` // dns_txt or email
+	VerificationEmail  string `json:"verification_email"`                     // Required for email; must be at the domain
+	JoinMode           string `json:"join_mode"`                              // auto or approval (default)
+	DefaultRole        string `json:"default_role"`                           // manager, member (default) or viewer
+}
+
+// UpdateJoinDomainRequest changes how a join domain admits users
+type UpdateJoinDomainRequest struct {
+	JoinMode    *string `json:"join_mode"`
+	DefaultRole *string `json:"default_role"`
+	Enabled     *bool   `json:"enabled"`
+}
+
+// VerifyJoinDomainRequest completes domain verification; Code is required for email verification
+type VerifyJoinDomainRequest struct {
+	Code string `json:"code"`
+}
+
+// JoinDomainTXTRecord is the DNS record that proves control of a domain
+type JoinDomainTXTRecord struct {
+	Type  string `json:"type"`
+	Host  string `json:"host"`
+	Value string `json:"value"`
+}
+
+// JoinDomainResponse is a join domain with the record to publish while it is pending
+type JoinDomainResponse struct {
+	*models.TenantJoinDomain
+	TXTRecord *JoinDomainTXTRecord `json:"txt_record,omitempty"`
+}
+
+// DomainJoinResult lists the tenants a login joined or asked to join through verified domains
+type DomainJoinResult struct {
+	Joined  []DomainJoinTenant `json:"joined"`
+	Pending []DomainJoinTenant `json:"pending"`
+}
+
+// DomainJoinTenant is one tenant a login was admitted to or is waiting for
+type DomainJoinTenant struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	Domain   string    `json:"domain"`
+	Role     string    `json:"role"`
+}
+
+// JoinDomainService lets tenants admit anyone with an email address at a domain they have
+// proven they control, either directly or through an approval queue
+type JoinDomainService struct {
+	joinRepo           *repository.JoinDomainRepository
+	membershipRepo     *repository.MembershipRepository
+	customDomainClient *clients.CustomDomainClient
+	notificationClient *clients.NotificationClient
+	cfg                config.JoinDomainConfig
+}
+
+// NewJoinDomainService creates a new join domain service
+func NewJoinDomainService(db *gorm.DB, customDomainClient *clients.CustomDomainClient, notificationClient *clients.NotificationClient, cfg config.JoinDomainConfig) *JoinDomainService {
+	return &JoinDomainService{
+		joinRepo:           repository.NewJoinDomainRepository(db),
+		membershipRepo:     repository.NewMembershipRepository(db),
+		customDomainClient: customDomainClient,
+		notificationClient: notificationClient,
+		cfg:                cfg,
+	}
+}
+
+// AuthorizeManage checks the user is an owner or admin of the tenant
+func (s *JoinDomainService) AuthorizeManage(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrJoinDomainForbidden
+	}
+	return nil
+}
+
+// ListDomains returns the tenant's join domains
+func (s *JoinDomainService) ListDomains(ctx context.Context, tenantID uuid.UUID) ([]JoinDomainResponse, error) {
+	domains, err := s.joinRepo.ListDomains(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]JoinDomainResponse, 0, len(domains))
+	for i := range domains {
+		responses = append(responses, *s.toResponse(&domains[i]))
+	}
+	return responses, nil
+}
+
+// AddDomain claims an email domain for the tenant. For DNS verification the response carries the
+// TXT record to publish; for email verification a code is sent to the verification address.
+func (s *JoinDomainService) AddDomain(ctx context.Context, tenantID, userID uuid.UUID, req AddJoinDomainRequest) (*JoinDomainResponse, error) {
+	domain, err := s.normalizeClaimableDomain(req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.joinRepo.GetDomainByName(ctx, tenantID, domain)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, NewValidationError("domain", fmt.Sprintf("%s has already been added", domain), nil)
+	}
+	if err := s.checkUnclaimed(ctx, tenantID, domain); err != nil {
+		return nil, err
+	}
+
+	joinMode, defaultRole, err := validateJoinPolicy(req.JoinMode, req.DefaultRole)
+	if err != nil {
+		return nil, err
+	}
+	token, err := randomHex(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	joinDomain := &models.TenantJoinDomain{
+		TenantID:           tenantID,
+		Domain:             domain,
+		Status:             models.JoinDomainStatusPending,
+		VerificationMethod: req.VerificationMethod,
+		VerificationToken:  token,
+		JoinMode:           joinMode,
+		DefaultRole:        defaultRole,
+		Enabled:            true,
+		CreatedBy:          userID,
+	}
+
+	switch req.VerificationMethod {
+	case models.JoinDomainVerificationDNS:
+		if s.customDomainClient == nil {
+			return nil, ErrJoinDomainVerificationUnavailable
+		}
+	case models.JoinDomainVerificationEmail:
+		if s.notificationClient == nil {
+			return nil, ErrJoinDomainVerificationUnavailable
+		}
+		email := strings.ToLower(strings.TrimSpace(req.VerificationEmail))
+		if EmailDomain(email) != domain {
+			return nil, NewValidationError("verification_email", fmt.Sprintf("verification email must be an address at %s", domain), nil)
+		}
+		joinDomain.VerificationEmail = email
+	default:
+		return nil, NewValidationError("verification_method", "verification_method must be dns_txt or email", nil)
+	}
+
+	if err := s.joinRepo.CreateDomain(ctx, joinDomain); err != nil {
+		return nil, err
+	}
+	if joinDomain.VerificationMethod == models.JoinDomainVerificationEmail {
+		if err := s.sendCode(ctx, joinDomain); err != nil {
+			return nil, err
+		}
+	}
+	log.Printf("[JoinDomainService] Tenant %s added join domain %s (%s verification)", tenantID, domain, joinDomain.VerificationMethod)
+	return s.toResponse(joinDomain), nil
+}
+
+// VerifyDomain checks the TXT record or emailed code and marks the domain verified. A failed
+// check is returned as a validation error describing what was found.
+func (s *JoinDomainService) VerifyDomain(ctx context.Context, tenantID, domainID, userID uuid.UUID, req VerifyJoinDomainRequest) (*JoinDomainResponse, error) {
+	joinDomain, err := s.getDomain(ctx, tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if joinDomain.IsVerified() {
+		return s.toResponse(joinDomain), nil
+	}
+	if err := s.checkUnclaimed(ctx, tenantID, joinDomain.Domain); err != nil {
+		return nil, err
+	}
+
+	switch joinDomain.VerificationMethod {
+	case models.JoinDomainVerificationDNS:
+		if s.customDomainClient == nil {
+			return nil, ErrJoinDomainVerificationUnavailable
+		}
+		record := txtRecordFor(joinDomain)
+		result, err := s.customDomainClient.VerifyTXT(ctx, record.Host, record.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check TXT record: %w", err)
+		}
+		if !result.Verified {
+			return nil, NewValidationError("domain", result.Message, nil)
+		}
+	case models.JoinDomainVerificationEmail:
+		if err := s.checkCode(ctx, joinDomain, req.Code); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	joinDomain.Status = models.JoinDomainStatusVerified
+	joinDomain.VerifiedAt = &now
+	joinDomain.VerifiedBy = &userID
+	joinDomain.EmailCodeHash = ""
+	joinDomain.EmailCodeExpiresAt = nil
+	if err := s.joinRepo.UpdateDomain(ctx, joinDomain); err != nil {
+		return nil, err
+	}
+	log.Printf("[JoinDomainService] Tenant %s verified join domain %s", tenantID, joinDomain.Domain)
+	return s.toResponse(joinDomain), nil
+}
+
+// ResendCode sends a new verification code for a pending email-verified domain
+func (s *JoinDomainService) ResendCode(ctx context.Context, tenantID, domainID uuid.UUID) error {
+	joinDomain, err := s.getDomain(ctx, tenantID, domainID)
+	if err != nil {
+		return err
+	}
+	if joinDomain.IsVerified() || joinDomain.VerificationMethod != models.JoinDomainVerificationEmail {
+		return NewValidationError("domain", "only pending domains verified by email can be sent a new code", nil)
+	}
+	if s.notificationClient == nil {
+		return ErrJoinDomainVerificationUnavailable
+	}
+	return s.sendCode(ctx, joinDomain)
+}
+
+// UpdateDomain changes a join domain's mode, default role or enabled flag
+func (s *JoinDomainService) UpdateDomain(ctx context.Context, tenantID, domainID uuid.UUID, req UpdateJoinDomainRequest) (*JoinDomainResponse, error) {
+	joinDomain, err := s.getDomain(ctx, tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	joinMode, defaultRole := joinDomain.JoinMode, joinDomain.DefaultRole
+	if req.JoinMode != nil {
+		joinMode = *req.JoinMode
+	}
+	if req.DefaultRole != nil {
+		defaultRole = *req.DefaultRole
+	}
+	if joinDomain.JoinMode, joinDomain.DefaultRole, err = validateJoinPolicy(joinMode, defaultRole); err != nil {
+		return nil, err
+	}
+	if req.Enabled != nil {
+		joinDomain.Enabled = *req.Enabled
+	}
+	if err := s.joinRepo.UpdateDomain(ctx, joinDomain); err != nil {
+		return nil, err
+	}
+	return s.toResponse(joinDomain), nil
+}
+
+// DeleteDomain removes a join domain and its pending join requests; existing members are kept
+func (s *JoinDomainService) DeleteDomain(ctx context.Context, tenantID, domainID uuid.UUID) error {
+	joinDomain, err := s.getDomain(ctx, tenantID, domainID)
+	if err != nil {
+		return err
+	}
+	return s.joinRepo.DeleteDomain(ctx, joinDomain)
+}
+
+// ListJoinRequests returns the tenant's join requests, optionally filtered by status
+func (s *JoinDomainService) ListJoinRequests(ctx context.Context, tenantID uuid.UUID, status string) ([]models.TenantJoinRequest, error) {
+	switch status {
+	case "", models.JoinRequestStatusPending, models.JoinRequestStatusApproved, models.JoinRequestStatusRejected:
+	default:
+		return nil, NewValidationError("status", "status must be pending, approved or rejected", nil)
+	}
+	return s.joinRepo.ListJoinRequests(ctx, tenantID, status)
+}
+
+// ReviewJoinRequest approves or rejects a pending join request. Approval creates the membership
+// with the role recorded on the request.
+func (s *JoinDomainService) ReviewJoinRequest(ctx context.Context, tenantID, requestID, reviewerID uuid.UUID, approve bool) (*models.TenantJoinRequest, error) {
+	request, err := s.joinRepo.GetJoinRequest(ctx, tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, ErrJoinRequestNotFound
+	}
+	if request.Status != models.JoinRequestStatusPending {
+		return nil, NewValidationError("status", fmt.Sprintf("join request has already been %s", request.Status), nil)
+	}
+
+	now := time.Now()
+	if approve {
+		if err := s.ensureMembership(ctx, request.UserID, tenantID, request.Role, &reviewerID); err != nil {
+			return nil, err
+		}
+		request.Status = models.JoinRequestStatusApproved
+	} else {
+		request.Status = models.JoinRequestStatusRejected
+	}
+	request.ReviewedBy = &reviewerID
+	request.ReviewedAt = &now
+	if err := s.joinRepo.UpdateJoinRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	log.Printf("[JoinDomainService] Join request %s for tenant %s %s by %s", request.ID, tenantID, request.Status, reviewerID)
+	return request, nil
+}
+
+// JoinByEmailDomain admits a signed-in user to every tenant that verified their email domain
+// and enabled auto-join. Tenants in approval mode get a join request instead; a rejected
+// request is not re-opened by later logins.
+func (s *JoinDomainService) JoinByEmailDomain(ctx context.Context, keycloakID uuid.UUID, email, firstName, lastName string) (*DomainJoinResult, error) {
+	result := &DomainJoinResult{Joined: []DomainJoinTenant{}, Pending: []DomainJoinTenant{}}
+	domain := EmailDomain(email)
+	if domain == "" {
+		return nil, NewValidationError("email", "authenticated user has no email address", nil)
+	}
+	domains, err := s.joinRepo.ListJoinableDomains(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	if len(domains) == 0 {
+		return result, nil
+	}
+
+	user, err := findOrCreateMemberUser(ctx, s.membershipRepo, keycloakID, email, firstName, lastName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, joinDomain := range domains {
+		membership, err := s.membershipRepo.GetMembership(ctx, user.ID, joinDomain.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		if membership != nil {
+			continue
+		}
+		entry := DomainJoinTenant{TenantID: joinDomain.TenantID, Domain: joinDomain.Domain, Role: joinDomain.DefaultRole}
+
+		if joinDomain.JoinMode == models.JoinModeAuto {
+			if err := s.ensureMembership(ctx, user.ID, joinDomain.TenantID, joinDomain.DefaultRole, nil); err != nil {
+				return nil, err
+			}
+			log.Printf("[JoinDomainService] %s joined tenant %s as %s through %s", user.ID, joinDomain.TenantID, joinDomain.DefaultRole, joinDomain.Domain)
+			result.Joined = append(result.Joined, entry)
+			continue
+		}
+
+		latest, err := s.joinRepo.GetLatestJoinRequest(ctx, joinDomain.TenantID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if latest != nil && latest.Status != models.JoinRequestStatusApproved {
+			if latest.Status == models.JoinRequestStatusPending {
+				result.Pending = append(result.Pending, entry)
+			}
+			continue
+		}
+		request := &models.TenantJoinRequest{
+			TenantID: joinDomain.TenantID,
+			DomainID: joinDomain.ID,
+			UserID:   user.ID,
+			Email:    user.Email,
+			Status:   models.JoinRequestStatusPending,
+			Role:     joinDomain.DefaultRole,
+		}
+		if err := s.joinRepo.CreateJoinRequest(ctx, request); err != nil {
+			return nil, err
+		}
+		result.Pending = append(result.Pending, entry)
+	}
+	return result, nil
+}
+
+// EmailDomain returns the lower-cased domain of an email address, or "" if it has none
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// IsPublicEmailDomain reports whether the domain is a public mailbox provider
+func IsPublicEmailDomain(domain string) bool {
+	return publicEmailDomains[strings.ToLower(domain)]
+}
+
+func (s *JoinDomainService) normalizeClaimableDomain(domain string) (string, error) {
+	domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@")
+	if !ssoDomainPattern.MatchString(domain) {
+		return "", NewValidationError("domain", fmt.Sprintf("%s is not a valid email domain", domain), nil)
+	}
+	if IsPublicEmailDomain(domain) {
+		return "", NewValidationError("domain", fmt.Sprintf("%s is a public email provider and cannot be claimed", domain), nil)
+	}
+	for _, blocked := range s.cfg.BlockedDomains {
+		if strings.EqualFold(strings.TrimSpace(blocked), domain) {
+			return "", NewValidationError("domain", fmt.Sprintf("%s cannot be claimed", domain), nil)
+		}
+	}
+	return domain, nil
+}
+
+// checkUnclaimed rejects a domain another tenant has already verified
+func (s *JoinDomainService) checkUnclaimed(ctx context.Context, tenantID uuid.UUID, domain string) error {
+	owner, err := s.joinRepo.FindVerifiedDomainOwner(ctx, domain, tenantID)
+	if err != nil {
+		return err
+	}
+	if owner != uuid.Nil {
+		return NewValidationError("domain", fmt.Sprintf("%s has already been verified by another tenant", domain), nil)
+	}
+	return nil
+}
+
+func (s *JoinDomainService) getDomain(ctx context.Context, tenantID, domainID uuid.UUID) (*models.TenantJoinDomain, error) {
+	joinDomain, err := s.joinRepo.GetDomain(ctx, tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if joinDomain == nil {
+		return nil, ErrJoinDomainNotFound
+	}
+	return joinDomain, nil
+}
+
+func (s *JoinDomainService) sendCode(ctx context.Context, joinDomain *models.TenantJoinDomain) error {
+	code, err := randomDigits(6)
+	if err != nil {
+		return fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	expiresAt := time.Now().Add(time.Duration(s.cfg.EmailCodeTTLMinutes) * time.Minute)
+	joinDomain.EmailCodeHash = hashJoinCode(joinDomain.ID, code)
+	joinDomain.EmailCodeExpiresAt = &expiresAt
+	joinDomain.EmailCodeAttempts = 0
+	if err := s.joinRepo.UpdateDomain(ctx, joinDomain); err != nil {
+		return err
+	}
+
+	storeName := joinDomain.Domain
+	if tenant, err := s.membershipRepo.GetTenantByID(ctx, joinDomain.TenantID); err == nil {
+		storeName = tenant.Name
+		if tenant.DisplayName != "" {
+			storeName = tenant.DisplayName
+		}
+	}
+	return s.notificationClient.SendDomainVerificationCodeEmail(ctx, &clients.DomainVerificationCodeEmailData{
+		Email:     joinDomain.VerificationEmail,
+		StoreName: storeName,
+		Domain:    joinDomain.Domain,
+		Code:      code,
+		ExpiresAt: expiresAt,
+	})
+}
+
+func (s *JoinDomainService) checkCode(ctx context.Context, joinDomain *models.TenantJoinDomain, code string) error {
+	if joinDomain.EmailCodeHash == "" || joinDomain.EmailCodeExpiresAt == nil || time.Now().After(*joinDomain.EmailCodeExpiresAt) {
+		return NewValidationError("code", "verification code has expired, request a new one", nil)
+	}
+	if joinDomain.EmailCodeAttempts >= s.cfg.EmailCodeMaxAttempts {
+		return NewValidationError("code", "too many incorrect codes, request a new one", nil)
+	}
+	expected := hashJoinCode(joinDomain.ID, strings.TrimSpace(code))
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(joinDomain.EmailCodeHash)) == 1 {
+		return nil
+	}
+	joinDomain.EmailCodeAttempts++
+	if err := s.joinRepo.UpdateDomain(ctx, joinDomain); err != nil {
+		return err
+	}
+	return NewValidationError("code", "verification code is incorrect", nil)
+}
+
+// ensureMembership creates an active membership unless the user already has one
+func (s *JoinDomainService) ensureMembership(ctx context.Context, userID, tenantID uuid.UUID, role string, invitedBy *uuid.UUID) error {
+	membership, err := s.membershipRepo.GetMembership(ctx, userID, tenantID)
+	if err != nil {
+		return err
+	}
+	if membership != nil {
+		return nil
+	}
+	now := time.Now()
+	return s.membershipRepo.CreateMembership(ctx, &models.UserTenantMembership{
+		UserID:     userID,
+		TenantID:   tenantID,
+		Role:       role,
+		IsActive:   true,
+		InvitedBy:  invitedBy,
+		AcceptedAt: &now,
+	})
+}
+
+func (s *JoinDomainService) toResponse(joinDomain *models.TenantJoinDomain) *JoinDomainResponse {
+	response := &JoinDomainResponse{TenantJoinDomain: joinDomain}
+	if joinDomain.VerificationMethod == models.JoinDomainVerificationDNS && !joinDomain.IsVerified() {
+		record := txtRecordFor(joinDomain)
+		response.TXTRecord = &record
+	}
+	return response
+}
+
+func validateJoinPolicy(joinMode, defaultRole string) (string, string, error) {
+	if joinMode == "" {
+		joinMode = models.JoinModeApproval
+	}
+	if joinMode != models.JoinModeAuto && joinMode != models.JoinModeApproval {
+		return "", "", NewValidationError("join_mode", "join_mode must be auto or approval", nil)
+	}
+	if defaultRole == "" {
+		defaultRole = models.MembershipRoleMember
+	}
+	if !joinDomainRoles[defaultRole] {
+		return "", "", NewValidationError("default_role", "default_role must be manager, member or viewer", nil)
+	}
+	return joinMode, defaultRole, nil
+}
+
+func txtRecordFor(joinDomain *models.TenantJoinDomain) JoinDomainTXTRecord {
+	return JoinDomainTXTRecord{
+		Type:  "TXT",
+		Host:  joinDomainTXTPrefix + joinDomain.Domain,
+		Value: joinDomainTXTValue + joinDomain.VerificationToken,
+	}
+}
+
+func hashJoinCode(domainID uuid.UUID, code string) string {
+	sum := sha256.Sum256([]byte(domainID.String() + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func randomDigits(n int) (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	v, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", n, v), nil
+}
+
+// findOrCreateMemberUser returns the tenant-service user for a Keycloak identity, matching an
+// existing user by email and linking the Keycloak ID, or creating one
+func findOrCreateMemberUser(ctx context.Context, repo *repository.MembershipRepository, keycloakID uuid.UUID, email, firstName, lastName string) (*models.User, error) {
+	user, err := repo.GetUserByKeycloakID(ctx, keycloakID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		if user, err = repo.GetUserByEmail(ctx, email); err != nil {
+			return nil, err
+		}
+	}
+	if user == nil {
+		user = &models.User{
+			KeycloakID: &keycloakID,
+			Email:      strings.ToLower(strings.TrimSpace(email)),
+			FirstName:  firstName,
+			LastName:   lastName,
+			Status:     "active",
+		}
+		if err := repo.CreateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	} else if user.KeycloakID == nil {
+		user.KeycloakID = &keycloakID
+		if err := repo.UpdateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrMasterKeyNotConfigured is returned when per-tenant encryption is used without a master key
+var ErrMasterKeyNotConfigured = errors.New("KMS master key is not configured")
+
+// KeyWrapper wraps and unwraps tenant data-encryption keys with a KMS master key
+// Implementations must be safe for concurrent use
+type KeyWrapper interface {
+	// KeyID identifies the master key so wrapped keys can be traced (and re-wrapped) after master key rotation
+	KeyID() string
+	// Wrap encrypts a data-encryption key
+	Wrap(ctx context.Context, dek []byte) (string, error)
+	// Unwrap decrypts a data-encryption key produced by Wrap
+	Unwrap(ctx context.Context, wrapped string) ([]byte, error)
+}
+
+// LocalKeyWrapper wraps keys with an AES-256-GCM master key held in process memory
+// The master key is provisioned from the secret store; swap for a cloud KMS wrapper without changing callers
+type LocalKeyWrapper struct {
+	keyID string
+	aead  cipher.AEAD
+}
+
+// NewLocalKeyWrapper creates a key wrapper from a base64 encoded 32-byte master key
+func NewLocalKeyWrapper(keyID, encodedKey string) (*LocalKeyWrapper, error) {
+	if encodedKey == "" {
+		return nil, ErrMasterKeyNotConfigured
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key encoding: %w", err)
+	}
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+
+	return &LocalKeyWrapper{keyID: keyID, aead: aead}, nil
+}
+
+// KeyID returns the master key identifier
+func (w *LocalKeyWrapper) KeyID() string {
+	return w.keyID
+}
+
+// Wrap encrypts a data-encryption key with the master key
+func (w *LocalKeyWrapper) Wrap(ctx context.Context, dek []byte) (string, error) {
+	return sealBase64(w.aead, dek, []byte(w.keyID))
+}
+
+// Unwrap decrypts a data-encryption key with the master key
+func (w *LocalKeyWrapper) Unwrap(ctx context.Context, wrapped string) ([]byte, error) {
+	return openBase64(w.aead, wrapped, []byte(w.keyID))
+}
+
+// newAESGCM creates an AES-256-GCM AEAD from a 32-byte key
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealBase64 encrypts plaintext and returns base64(nonce || ciphertext)
+func sealBase64(aead cipher.AEAD, plaintext, additionalData []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, additionalData)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openBase64 decrypts a value produced by sealBase64
+func openBase64(aead cipher.AEAD, encoded string, additionalData []byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext encoding: %w", err)
+	}
+	nonceSize := aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("ciphertext too short")
+	}
+	plaintext, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
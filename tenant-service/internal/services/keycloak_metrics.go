@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tesseract-Nexus/go-shared/auth"
+	"tenant-service/internal/metrics"
+)
+
+// timedKeycloakClient records the latency of the Keycloak calls tenant-service makes.
+// Methods it does not override pass through to the embedded client untimed.
+type timedKeycloakClient struct {
+	*auth.KeycloakAdminClient
+}
+
+// newTimedKeycloakClient wraps client; a nil client stays nil so existing nil checks keep working
+func newTimedKeycloakClient(client *auth.KeycloakAdminClient) *timedKeycloakClient {
+	if client == nil {
+		return nil
+	}
+	return &timedKeycloakClient{KeycloakAdminClient: client}
+}
+
+func (c *timedKeycloakClient) GetTokenWithPassword(ctx context.Context, clientID, clientSecret, username, password string) (*auth.TokenResponse, error) {
+	start := time.Now()
+	tokens, err := c.KeycloakAdminClient.GetTokenWithPassword(ctx, clientID, clientSecret, username, password)
+	metrics.ObserveKeycloakCall(ctx, "get_token", start, err)
+	return tokens, err
+}
+
+func (c *timedKeycloakClient) GetTokensForValidatedUser(ctx context.Context, keycloakUserID, clientID, clientSecret string) (*auth.TokenResponse, error) {
+	start := time.Now()
+	tokens, err := c.KeycloakAdminClient.GetTokensForValidatedUser(ctx, keycloakUserID, clientID, clientSecret)
+	metrics.ObserveKeycloakCall(ctx, "token_exchange", start, err)
+	return tokens, err
+}
+
+func (c *timedKeycloakClient) GetUserByEmail(ctx context.Context, email string) (*auth.UserRepresentation, error) {
+	start := time.Now()
+	user, err := c.KeycloakAdminClient.GetUserByEmail(ctx, email)
+	metrics.ObserveKeycloakCall(ctx, "get_user_by_email", start, err)
+	return user, err
+}
+
+func (c *timedKeycloakClient) CreateUser(ctx context.Context, user auth.UserRepresentation) (string, error) {
+	start := time.Now()
+	userID, err := c.KeycloakAdminClient.CreateUser(ctx, user)
+	metrics.ObserveKeycloakCall(ctx, "create_user", start, err)
+	return userID, err
+}
+
+func (c *timedKeycloakClient) DeleteUser(ctx context.Context, userID string) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.DeleteUser(ctx, userID)
+	metrics.ObserveKeycloakCall(ctx, "delete_user", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) SetUserPassword(ctx context.Context, userID string, password string, temporary bool) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.SetUserPassword(ctx, userID, password, temporary)
+	metrics.ObserveKeycloakCall(ctx, "set_user_password", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) UpdateUserAttributes(ctx context.Context, userID string, attributes map[string][]string) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.UpdateUserAttributes(ctx, userID, attributes)
+	metrics.ObserveKeycloakCall(ctx, "update_user_attributes", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) GetUserByID(ctx context.Context, userID string) (*auth.UserRepresentation, error) {
+	start := time.Now()
+	user, err := c.KeycloakAdminClient.GetUserByID(ctx, userID)
+	metrics.ObserveKeycloakCall(ctx, "get_user_by_id", start, err)
+	return user, err
+}
+
+func (c *timedKeycloakClient) UpdateUser(ctx context.Context, userID string, user auth.UserRepresentation) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.UpdateUser(ctx, userID, user)
+	metrics.ObserveKeycloakCall(ctx, "update_user", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) AssignRealmRole(ctx context.Context, userID string, roleName string) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.AssignRealmRole(ctx, userID, roleName)
+	metrics.ObserveKeycloakCall(ctx, "assign_realm_role", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) GetUserRealmRoles(ctx context.Context, userID string) ([]auth.RoleRepresentation, error) {
+	start := time.Now()
+	roles, err := c.KeycloakAdminClient.GetUserRealmRoles(ctx, userID)
+	metrics.ObserveKeycloakCall(ctx, "get_user_realm_roles", start, err)
+	return roles, err
+}
+
+func (c *timedKeycloakClient) CreateOrganizationForTenant(ctx context.Context, tenantID, tenantName, tenantSlug string) (string, error) {
+	start := time.Now()
+	orgID, err := c.KeycloakAdminClient.CreateOrganizationForTenant(ctx, tenantID, tenantName, tenantSlug)
+	metrics.ObserveKeycloakCall(ctx, "create_organization", start, err)
+	return orgID, err
+}
+
+func (c *timedKeycloakClient) GetOrganization(ctx context.Context, orgID string) (*auth.OrganizationRepresentation, error) {
+	start := time.Now()
+	org, err := c.KeycloakAdminClient.GetOrganization(ctx, orgID)
+	metrics.ObserveKeycloakCall(ctx, "get_organization", start, err)
+	return org, err
+}
+
+func (c *timedKeycloakClient) UpdateOrganization(ctx context.Context, orgID string, org auth.OrganizationRepresentation) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.UpdateOrganization(ctx, orgID, org)
+	metrics.ObserveKeycloakCall(ctx, "update_organization", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) AddOrganizationMember(ctx context.Context, orgID, userID string) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.AddOrganizationMember(ctx, orgID, userID)
+	metrics.ObserveKeycloakCall(ctx, "add_organization_member", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) IsOrganizationMember(ctx context.Context, orgID, userID string) (bool, error) {
+	start := time.Now()
+	isMember, err := c.KeycloakAdminClient.IsOrganizationMember(ctx, orgID, userID)
+	metrics.ObserveKeycloakCall(ctx, "is_organization_member", start, err)
+	return isMember, err
+}
+
+func (c *timedKeycloakClient) AddClientRedirectURIs(ctx context.Context, clientID string, newRedirectURIs []string) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.AddClientRedirectURIs(ctx, clientID, newRedirectURIs)
+	metrics.ObserveKeycloakCall(ctx, "add_client_redirect_uris", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) RemoveClientRedirectURIs(ctx context.Context, clientID string, urisToRemove []string) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.RemoveClientRedirectURIs(ctx, clientID, urisToRemove)
+	metrics.ObserveKeycloakCall(ctx, "remove_client_redirect_uris", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) GetIdentityProvider(ctx context.Context, alias string) (*auth.IdentityProviderConfig, error) {
+	start := time.Now()
+	idp, err := c.KeycloakAdminClient.GetIdentityProvider(ctx, alias)
+	metrics.ObserveKeycloakCall(ctx, "get_identity_provider", start, err)
+	return idp, err
+}
+
+func (c *timedKeycloakClient) CreateIdentityProvider(ctx context.Context, config auth.IdentityProviderConfig) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.CreateIdentityProvider(ctx, config)
+	metrics.ObserveKeycloakCall(ctx, "create_identity_provider", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) UpdateIdentityProvider(ctx context.Context, alias string, config auth.IdentityProviderConfig) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.UpdateIdentityProvider(ctx, alias, config)
+	metrics.ObserveKeycloakCall(ctx, "update_identity_provider", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) DeleteIdentityProvider(ctx context.Context, alias string) error {
+	start := time.Now()
+	err := c.KeycloakAdminClient.DeleteIdentityProvider(ctx, alias)
+	metrics.ObserveKeycloakCall(ctx, "delete_identity_provider", start, err)
+	return err
+}
+
+func (c *timedKeycloakClient) TestIdentityProvider(ctx context.Context, alias string) (*auth.IdPTestResult, error) {
+	start := time.Now()
+	result, err := c.KeycloakAdminClient.TestIdentityProvider(ctx, alias)
+	metrics.ObserveKeycloakCall(ctx, "test_identity_provider", start, err)
+	return result, err
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+const (
+	lockoutPolicyMaxAttempts   = 50
+	lockoutPolicyMaxMinutes    = 7 * 24 * 60
+	lockoutPolicyMaxResetHours = 720
+)
+
+// ErrLockoutPolicyForbidden is returned when the user may not manage the tenant's lockout policy
+var ErrLockoutPolicyForbidden = errors.New("only tenant owners and admins can manage the lockout policy")
+
+// LockoutPolicy is the brute-force lockout section of a tenant's auth policy
+type LockoutPolicy struct {
+	MaxAttempts  int        `json:"max_attempts"` // Failures per tier; tier n locks at n x max_attempts
+	Progressive  bool       `json:"progressive"`  // False = every lockout uses tier 1
+	Tier1Minutes int        `json:"tier1_minutes"`
+	Tier2Minutes int        `json:"tier2_minutes"`
+	Tier3Minutes int        `json:"tier3_minutes"`
+	Tier4Minutes int        `json:"tier4_minutes"` // Maximum; lockouts always expire
+	ResetHours   int        `json:"reset_hours"`   // Failures are forgotten after this long without one
+	IsDefault    bool       `json:"is_default"`    // True when the tenant has no stored auth policy
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdateLockoutPolicyRequest changes the lockout policy; omitted fields keep their current value
+type UpdateLockoutPolicyRequest struct {
+	MaxAttempts  *int  `json:"max_attempts"`
+	Progressive  *bool `json:"progressive"`
+	Tier1Minutes *int  `json:"tier1_minutes"`
+	Tier2Minutes *int  `json:"tier2_minutes"`
+	Tier3Minutes *int  `json:"tier3_minutes"`
+	Tier4Minutes *int  `json:"tier4_minutes"`
+	ResetHours   *int  `json:"reset_hours"`
+}
+
+// LockoutPolicyService manages the per-tenant login lockout thresholds
+type LockoutPolicyService struct {
+	credentialRepo *repository.CredentialRepository
+	membershipRepo *repository.MembershipRepository
+}
+
+// NewLockoutPolicyService creates a new lockout policy service
+func NewLockoutPolicyService(db *gorm.DB) *LockoutPolicyService {
+	return &LockoutPolicyService{
+		credentialRepo: repository.NewCredentialRepository(db),
+		membershipRepo: repository.NewMembershipRepository(db),
+	}
+}
+
+// AuthorizeManage checks the user is an owner or admin of the tenant
+func (s *LockoutPolicyService) AuthorizeManage(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrLockoutPolicyForbidden
+	}
+	return nil
+}
+
+// GetPolicy returns the tenant's lockout policy, or the defaults when none is stored
+func (s *LockoutPolicyService) GetPolicy(ctx context.Context, tenantID uuid.UUID) (*LockoutPolicy, error) {
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return toLockoutPolicy(policy), nil
+}
+
+// UpdatePolicy applies changes to the tenant's lockout policy, creating the auth policy if needed
+func (s *LockoutPolicyService) UpdatePolicy(ctx context.Context, tenantID, userID uuid.UUID, req UpdateLockoutPolicyRequest) (*LockoutPolicy, error) {
+	if err := validateLockoutPolicyRequest(req); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		if policy, err = s.credentialRepo.CreateAuthPolicy(ctx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.MaxAttempts != nil {
+		policy.MaxLoginAttempts = *req.MaxAttempts
+	}
+	if req.Progressive != nil {
+		policy.EnableProgressiveLockout = *req.Progressive
+	}
+	if req.Tier1Minutes != nil {
+		policy.Tier1LockoutMinutes = *req.Tier1Minutes
+	}
+	if req.Tier2Minutes != nil {
+		policy.Tier2LockoutMinutes = *req.Tier2Minutes
+	}
+	if req.Tier3Minutes != nil {
+		policy.Tier3LockoutMinutes = *req.Tier3Minutes
+	}
+	if req.Tier4Minutes != nil {
+		policy.Tier4LockoutMinutes = *req.Tier4Minutes
+	}
+	if req.ResetHours != nil {
+		policy.LockoutResetHours = *req.ResetHours
+	}
+	settings := policy.LockoutSettings()
+	for i := 1; i < models.LockoutTierCount; i++ {
+		if settings.TierMinutes[i] < settings.TierMinutes[i-1] {
+			return nil, NewValidationError(fmt.Sprintf("tier%d_minutes", i+1), "lockout tiers must not get shorter", nil)
+		}
+	}
+	policy.LockoutDurationMinutes = settings.TierMinutes[0]
+	policy.UpdatedBy = &userID
+
+	if err := s.credentialRepo.UpdateAuthPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	log.Printf("[LockoutPolicyService] Lockout policy for tenant %s updated by %s", tenantID, userID)
+	return toLockoutPolicy(policy), nil
+}
+
+func toLockoutPolicy(policy *models.TenantAuthPolicy) *LockoutPolicy {
+	settings := policy.LockoutSettings()
+	result := &LockoutPolicy{
+		MaxAttempts:  settings.MaxAttempts,
+		Progressive:  settings.Progressive,
+		Tier1Minutes: settings.TierMinutes[0],
+		Tier2Minutes: settings.TierMinutes[1],
+		Tier3Minutes: settings.TierMinutes[2],
+		Tier4Minutes: settings.TierMinutes[3],
+		ResetHours:   settings.ResetHours,
+		IsDefault:    policy == nil,
+	}
+	if policy != nil {
+		updatedAt := policy.UpdatedAt
+		result.UpdatedAt = &updatedAt
+	}
+	return result
+}
+
+// validateLockoutPolicyRequest checks the ranges of the supplied fields
+func validateLockoutPolicyRequest(req UpdateLockoutPolicyRequest) error {
+	if req.MaxAttempts != nil && (*req.MaxAttempts < 1 || *req.MaxAttempts > lockoutPolicyMaxAttempts) {
+		return NewValidationError("max_attempts", fmt.Sprintf("max_attempts must be between 1 and %d", lockoutPolicyMaxAttempts), nil)
+	}
+	tiers := []struct {
+		field string
+		value *int
+	}{
+		{"tier1_minutes", req.Tier1Minutes},
+		{"tier2_minutes", req.Tier2Minutes},
+		{"tier3_minutes", req.Tier3Minutes},
+		{"tier4_minutes", req.Tier4Minutes},
+	}
+	for _, tier := range tiers {
+		if tier.value != nil && (*tier.value < 1 || *tier.value > lockoutPolicyMaxMinutes) {
+			return NewValidationError(tier.field, fmt.Sprintf("%s must be between 1 and %d", tier.field, lockoutPolicyMaxMinutes), nil)
+		}
+	}
+	if req.ResetHours != nil && (*req.ResetHours < 1 || *req.ResetHours > lockoutPolicyMaxResetHours) {
+		return NewValidationError("reset_hours", fmt.Sprintf("reset_hours must be between 1 and %d", lockoutPolicyMaxResetHours), nil)
+	}
+	return nil
+}
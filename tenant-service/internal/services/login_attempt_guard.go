@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+	"tenant-service/internal/redis"
+)
+
+// LoginAttemptStatus is the brute-force state of one tenant+email+IP combination
+type LoginAttemptStatus struct {
+	Locked            bool       `json:"locked"`
+	LockedUntil       *time.Time `json:"locked_until,omitempty"`
+	Tier              int        `json:"tier,omitempty"`
+	RemainingAttempts int        `json:"remaining_attempts"`
+}
+
+// loginAttemptState is what is stored per tenant+email+IP
+type loginAttemptState struct {
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+	Tier          int        `json:"tier,omitempty"`
+}
+
+// localAttemptPruneSize is the number of in-memory entries at which expired ones are swept
+const localAttemptPruneSize = 10000
+
+// localAttemptEntry is a loginAttemptState held in memory when Redis is not available
+type localAttemptEntry struct {
+	indexKey  string
+	state     loginAttemptState
+	expiresAt time.Time
+}
+
+// LoginAttemptGuard counts failed logins per tenant, email and client IP and locks out the
+// combination using the tenant's tiered lockout settings. It complements the per-credential
+// counters in tenant_credentials: it also covers unknown emails and staff logins, and one IP
+// hammering an account does not lock the owner out from everywhere else.
+//
+// State lives in Redis so every replica sees it; without Redis (or when it errors) the guard
+// falls back to process memory.
+type LoginAttemptGuard struct {
+	cache *redis.Client // optional
+
+	mu    sync.Mutex
+	local map[string]*localAttemptEntry
+}
+
+// NewLoginAttemptGuard creates a login attempt guard; cache may be nil
+func NewLoginAttemptGuard(cache *redis.Client) *LoginAttemptGuard {
+	return &LoginAttemptGuard{
+		cache: cache,
+		local: make(map[string]*localAttemptEntry),
+	}
+}
+
+// Status returns the current state without counting an attempt
+func (g *LoginAttemptGuard) Status(ctx context.Context, tenantID uuid.UUID, email, ipAddress string, settings models.LockoutSettings) LoginAttemptStatus {
+	_, key := loginAttemptKeys(tenantID, email, ipAddress)
+	state := g.load(ctx, key)
+	return loginAttemptStatusAt(state, settings, time.Now())
+}
+
+// RecordFailure counts a failed attempt, locking the combination every MaxAttempts failures
+func (g *LoginAttemptGuard) RecordFailure(ctx context.Context, tenantID uuid.UUID, email, ipAddress string, settings models.LockoutSettings) LoginAttemptStatus {
+	indexKey, key := loginAttemptKeys(tenantID, email, ipAddress)
+	now := time.Now()
+
+	state := g.load(ctx, key)
+	state = nextLoginAttemptState(state, settings, now)
+
+	// Keep the state while it is locked and for the reset window after the last failure
+	ttl := settings.ResetAfter()
+	if state.LockedUntil != nil && time.Until(*state.LockedUntil) > ttl {
+		ttl = time.Until(*state.LockedUntil)
+	}
+	g.save(ctx, indexKey, key, state, ttl)
+
+	return loginAttemptStatusAt(state, settings, now)
+}
+
+// Reset forgets the failures of one combination after a successful login
+func (g *LoginAttemptGuard) Reset(ctx context.Context, tenantID uuid.UUID, email, ipAddress string) {
+	indexKey, key := loginAttemptKeys(tenantID, email, ipAddress)
+	if g.cache != nil {
+		if err := g.cache.DeleteLoginLockout(ctx, indexKey, key); err != nil {
+			log.Printf("[LoginAttemptGuard] Warning: Failed to reset login attempts in Redis: %v", err)
+		}
+	}
+	g.mu.Lock()
+	delete(g.local, key)
+	g.mu.Unlock()
+}
+
+// ClearForEmail removes the lockouts of an email in a tenant from every IP (admin unlock)
+func (g *LoginAttemptGuard) ClearForEmail(ctx context.Context, tenantID uuid.UUID, email string) {
+	indexKey, _ := loginAttemptKeys(tenantID, email, "")
+	if g.cache != nil {
+		if _, err := g.cache.ClearLoginLockouts(ctx, indexKey); err != nil {
+			log.Printf("[LoginAttemptGuard] Warning: Failed to clear login lockouts in Redis: %v", err)
+		}
+	}
+	g.mu.Lock()
+	for key, entry := range g.local {
+		if entry.indexKey == indexKey {
+			delete(g.local, key)
+		}
+	}
+	g.mu.Unlock()
+}
+
+// nextLoginAttemptState applies one failed attempt at now to state. Failures older than the
+// reset window are forgotten; every MaxAttempts-th failure locks at the tier for the running total.
+func nextLoginAttemptState(state loginAttemptState, settings models.LockoutSettings, now time.Time) loginAttemptState {
+	if state.Failures > 0 && now.Sub(state.LastFailureAt) >= settings.ResetAfter() {
+		state = loginAttemptState{}
+	}
+
+	state.Failures++
+	state.LastFailureAt = now
+	if settings.MaxAttempts > 0 && state.Failures%settings.MaxAttempts == 0 {
+		tier, duration := settings.LockoutFor(state.Failures)
+		lockedUntil := now.Add(duration)
+		state.Tier = tier
+		state.LockedUntil = &lockedUntil
+	}
+	return state
+}
+
+// loginAttemptStatusAt derives the externally visible status of state at now
+func loginAttemptStatusAt(state loginAttemptState, settings models.LockoutSettings, now time.Time) LoginAttemptStatus {
+	if state.LockedUntil != nil && state.LockedUntil.After(now) {
+		return LoginAttemptStatus{Locked: true, LockedUntil: state.LockedUntil, Tier: state.Tier}
+	}
+	if state.Failures > 0 && now.Sub(state.LastFailureAt) >= settings.ResetAfter() {
+		state = loginAttemptState{}
+	}
+	remaining := settings.MaxAttempts
+	if settings.MaxAttempts > 0 {
+		remaining = settings.MaxAttempts - state.Failures%settings.MaxAttempts
+	}
+	return LoginAttemptStatus{Tier: state.Tier, RemainingAttempts: remaining}
+}
+
+func (g *LoginAttemptGuard) load(ctx context.Context, key string) loginAttemptState {
+	var state loginAttemptState
+	if g.cache != nil {
+		data, err := g.cache.GetLoginLockout(ctx, key)
+		if err == nil {
+			if data != nil {
+				if err := json.Unmarshal(data, &state); err != nil {
+					log.Printf("[LoginAttemptGuard] Warning: Ignoring unreadable login attempt state: %v", err)
+				}
+			}
+			return state
+		}
+		log.Printf("[LoginAttemptGuard] Warning: Redis unavailable, using local login attempt state: %v", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if entry, ok := g.local[key]; ok {
+		if time.Now().Before(entry.expiresAt) {
+			return entry.state
+		}
+		delete(g.local, key)
+	}
+	return state
+}
+
+func (g *LoginAttemptGuard) save(ctx context.Context, indexKey, key string, state loginAttemptState, ttl time.Duration) {
+	if g.cache != nil {
+		data, err := json.Marshal(state)
+		if err == nil {
+			err = g.cache.SaveLoginLockout(ctx, indexKey, key, data, ttl)
+		}
+		if err == nil {
+			return
+		}
+		log.Printf("[LoginAttemptGuard] Warning: Redis unavailable, keeping login attempt state locally: %v", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.local) >= localAttemptPruneSize {
+		now := time.Now()
+		for k, entry := range g.local {
+			if !now.Before(entry.expiresAt) {
+				delete(g.local, k)
+			}
+		}
+	}
+	g.local[key] = &localAttemptEntry{indexKey: indexKey, state: state, expiresAt: time.Now().Add(ttl)}
+}
+
+// loginAttemptKeys returns the index key (tenant+email) and state key (tenant+email+IP).
+// Emails and IPs are hashed so they do not appear in Redis key names.
+func loginAttemptKeys(tenantID uuid.UUID, email, ipAddress string) (string, string) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	indexSum := sha256.Sum256([]byte(tenantID.String() + ":" + email))
+	keySum := sha256.Sum256([]byte(tenantID.String() + ":" + email + ":" + ipAddress))
+	indexKey := tenantID.String() + ":" + hex.EncodeToString(indexSum[:16])
+	return indexKey, indexKey + ":" + hex.EncodeToString(keySum[:16])
+}
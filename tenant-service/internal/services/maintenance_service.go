@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+	"tenant-service/internal/redis"
+)
+
+const (
+	// maintenanceRedisTTL bounds how long a replica can serve a flag from Redis without re-reading it
+	maintenanceRedisTTL = time.Minute
+	// maintenanceLocalTTL bounds how long a replica serves a flag from memory; this is the
+	// worst-case delay before a toggle made on another replica takes effect
+	maintenanceLocalTTL = 5 * time.Second
+	// maintenanceOffMarker caches the absence of a flag so idle tenants do not hit the database
+	maintenanceOffMarker = "off"
+)
+
+// ErrMaintenanceFlagNotFound is returned when disabling a flag that is not set
+var ErrMaintenanceFlagNotFound = errors.New("maintenance mode is not enabled for this scope")
+
+// EnableMaintenanceRequest enables read-only mode for the platform or a tenant
+type EnableMaintenanceRequest struct {
+	Reason          string     `json:"reason" binding:"required,max=255"`
+	Message         string     `json:"message"`
+	EnabledBy       string     `json:"enabled_by"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	DurationMinutes int        `json:"duration_minutes"`
+}
+
+// MaintenanceStatus describes the read-only state that applies to a tenant
+type MaintenanceStatus struct {
+	ReadOnly bool                    `json:"read_only"`
+	Platform *models.MaintenanceFlag `json:"platform,omitempty"`
+	Tenant   *models.MaintenanceFlag `json:"tenant,omitempty"`
+}
+
+type maintenanceCacheEntry struct {
+	flag      *models.MaintenanceFlag
+	expiresAt time.Time
+}
+
+// MaintenanceService manages platform and per-tenant read-only maintenance flags.
+// Flags are stored in the database and cached in Redis and in memory so the write-path
+// middleware does not query the database per request.
+type MaintenanceService struct {
+	db    *gorm.DB
+	cache *redis.Client
+
+	mu    sync.RWMutex
+	local map[string]maintenanceCacheEntry
+}
+
+// NewMaintenanceService creates a new maintenance service; redisClient may be nil
+func NewMaintenanceService(db *gorm.DB, redisClient *redis.Client) *MaintenanceService {
+	return &MaintenanceService{
+		db:    db,
+		cache: redisClient,
+		local: make(map[string]maintenanceCacheEntry),
+	}
+}
+
+// Enable puts the platform (nil tenant) or a tenant in read-only mode, replacing any existing flag
+func (s *MaintenanceService) Enable(ctx context.Context, tenantID *uuid.UUID, req EnableMaintenanceRequest) (*models.MaintenanceFlag, error) {
+	now := time.Now()
+	expiresAt, err := maintenanceExpiry(req, now)
+	if err != nil {
+		return nil, err
+	}
+
+	key := models.MaintenanceScopeKey(tenantID)
+	scope := models.MaintenanceScopePlatform
+	if tenantID != nil {
+		scope = models.MaintenanceScopeTenant
+	}
+
+	var flag models.MaintenanceFlag
+	err = s.db.WithContext(ctx).Where("scope_key = ?", key).First(&flag).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load maintenance flag: %w", err)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) || !flag.IsActive(now) {
+		flag.StartedAt = now
+	}
+	flag.ScopeKey = key
+	flag.Scope = scope
+	flag.TenantID = tenantID
+	flag.Reason = strings.TrimSpace(req.Reason)
+	flag.Message = strings.TrimSpace(req.Message)
+	flag.EnabledBy = req.EnabledBy
+	flag.ExpiresAt = expiresAt
+
+	if err := s.db.WithContext(ctx).Save(&flag).Error; err != nil {
+		return nil, fmt.Errorf("failed to save maintenance flag: %w", err)
+	}
+
+	s.store(ctx, key, &flag)
+	log.Printf("[Maintenance] Read-only mode enabled for %s by %q: %s", key, req.EnabledBy, flag.Reason)
+	return &flag, nil
+}
+
+// Disable lifts read-only mode for the platform (nil tenant) or a tenant
+func (s *MaintenanceService) Disable(ctx context.Context, tenantID *uuid.UUID) error {
+	key := models.MaintenanceScopeKey(tenantID)
+	result := s.db.WithContext(ctx).Where("scope_key = ?", key).Delete(&models.MaintenanceFlag{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete maintenance flag: %w", result.Error)
+	}
+
+	s.store(ctx, key, nil)
+	if result.RowsAffected == 0 {
+		return ErrMaintenanceFlagNotFound
+	}
+	log.Printf("[Maintenance] Read-only mode disabled for %s", key)
+	return nil
+}
+
+// List returns every flag that is still active
+func (s *MaintenanceService) List(ctx context.Context) ([]models.MaintenanceFlag, error) {
+	var flags []models.MaintenanceFlag
+	if err := s.db.WithContext(ctx).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("started_at DESC").
+		Find(&flags).Error; err != nil {
+		return nil, fmt.Errorf("failed to list maintenance flags: %w", err)
+	}
+	return flags, nil
+}
+
+// Status returns the platform and tenant flags that apply to a tenant, read from the database
+func (s *MaintenanceService) Status(ctx context.Context, tenantID uuid.UUID) (*MaintenanceStatus, error) {
+	platform, err := s.load(ctx, models.MaintenanceScopeKey(nil))
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := s.load(ctx, models.MaintenanceScopeKey(&tenantID))
+	if err != nil {
+		return nil, err
+	}
+	return &MaintenanceStatus{
+		ReadOnly: platform != nil || tenant != nil,
+		Platform: platform,
+		Tenant:   tenant,
+	}, nil
+}
+
+// ActiveMaintenance returns the flag blocking writes for a tenant, or nil when writes are allowed.
+// The platform flag wins over a tenant flag; tenantID may be empty for requests without a tenant.
+func (s *MaintenanceService) ActiveMaintenance(ctx context.Context, tenantID string) (*models.MaintenanceFlag, error) {
+	keys := []string{models.MaintenanceScopeKey(nil)}
+	if id, err := uuid.Parse(tenantID); err == nil {
+		keys = append(keys, models.MaintenanceScopeKey(&id))
+	}
+
+	flags, err := s.lookup(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, key := range keys {
+		if flag := flags[key]; flag != nil && flag.IsActive(now) {
+			return flag, nil
+		}
+	}
+	return nil, nil
+}
+
+// lookup resolves flags from memory, then Redis, then the database
+func (s *MaintenanceService) lookup(ctx context.Context, keys []string) (map[string]*models.MaintenanceFlag, error) {
+	now := time.Now()
+	flags := make(map[string]*models.MaintenanceFlag, len(keys))
+	var missing []string
+
+	s.mu.RLock()
+	for _, key := range keys {
+		if entry, ok := s.local[key]; ok && now.Before(entry.expiresAt) {
+			flags[key] = entry.flag
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	s.mu.RUnlock()
+	if len(missing) == 0 {
+		return flags, nil
+	}
+
+	if s.cache != nil {
+		cached, err := s.cache.GetMaintenanceFlags(ctx, missing)
+		if err != nil {
+			log.Printf("[Maintenance] Redis lookup failed, reading flags from database: %v", err)
+		} else {
+			remaining := missing[:0]
+			for _, key := range missing {
+				data, ok := cached[key]
+				if !ok {
+					remaining = append(remaining, key)
+					continue
+				}
+				var flag *models.MaintenanceFlag
+				if string(data) != maintenanceOffMarker {
+					flag = &models.MaintenanceFlag{}
+					if err := json.Unmarshal(data, flag); err != nil {
+						remaining = append(remaining, key)
+						continue
+					}
+				}
+				flags[key] = flag
+				s.remember(key, flag)
+			}
+			missing = remaining
+		}
+	}
+
+	for _, key := range missing {
+		flag, err := s.load(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		flags[key] = flag
+		s.store(ctx, key, flag)
+	}
+	return flags, nil
+}
+
+// load reads an active flag from the database; nil means none is set
+func (s *MaintenanceService) load(ctx context.Context, key string) (*models.MaintenanceFlag, error) {
+	var flag models.MaintenanceFlag
+	err := s.db.WithContext(ctx).Where("scope_key = ?", key).First(&flag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load maintenance flag: %w", err)
+	}
+	if !flag.IsActive(time.Now()) {
+		return nil, nil
+	}
+	return &flag, nil
+}
+
+// store caches a flag (or its absence) in memory and Redis
+func (s *MaintenanceService) store(ctx context.Context, key string, flag *models.MaintenanceFlag) {
+	s.remember(key, flag)
+	if s.cache == nil {
+		return
+	}
+
+	data := []byte(maintenanceOffMarker)
+	ttl := maintenanceRedisTTL
+	if flag != nil {
+		encoded, err := json.Marshal(flag)
+		if err != nil {
+			return
+		}
+		data = encoded
+		if flag.ExpiresAt != nil {
+			if remaining := time.Until(*flag.ExpiresAt); remaining > 0 && remaining < ttl {
+				ttl = remaining
+			}
+		}
+	}
+	if err := s.cache.SaveMaintenanceFlag(ctx, key, data, ttl); err != nil {
+		log.Printf("[Maintenance] Failed to cache flag %s: %v", key, err)
+	}
+}
+
+func (s *MaintenanceService) remember(key string, flag *models.MaintenanceFlag) {
+	s.mu.Lock()
+	s.local[key] = maintenanceCacheEntry{flag: flag, expiresAt: time.Now().Add(maintenanceLocalTTL)}
+	s.mu.Unlock()
+}
+
+// maintenanceExpiry resolves the optional expiry from an absolute time or a duration
+func maintenanceExpiry(req EnableMaintenanceRequest, now time.Time) (*time.Time, error) {
+	if req.ExpiresAt != nil && req.DurationMinutes != 0 {
+		return nil, NewValidationError("expires_at", "set either expires_at or duration_minutes, not both", nil)
+	}
+	if req.DurationMinutes < 0 {
+		return nil, NewValidationError("duration_minutes", "duration_minutes must be positive", nil)
+	}
+	if req.DurationMinutes > 0 {
+		expiresAt := now.Add(time.Duration(req.DurationMinutes) * time.Minute)
+		return &expiresAt, nil
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, NewValidationError("expires_at", "expires_at must be in the future", nil)
+	}
+	return req.ExpiresAt, nil
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+	"tenant-service/internal/nats"
+)
+
+// MaxMemberImportRows is the maximum number of members accepted by one bulk import
+const MaxMemberImportRows = 500
+
+// Per-row outcomes of a member import
+const (
+	MemberImportLinked  = "linked"  // Existing user added (or re-activated) as a member
+	MemberImportInvited = "invited" // No account yet; a pending invitation was created
+	MemberImportSkipped = "skipped" // Already an active member or a duplicate row
+	MemberImportFailed  = "failed"
+)
+
+// importableRoles are the roles that may be assigned through an import (never owner)
+var importableRoles = map[string]bool{
+	models.MembershipRoleAdmin:   true,
+	models.MembershipRoleManager: true,
+	models.MembershipRoleMember:  true,
+	models.MembershipRoleViewer:  true,
+}
+
+// MemberEventPublisher publishes membership events (satisfied by *nats.Client)
+type MemberEventPublisher interface {
+	PublishTenantMemberEvent(ctx context.Context, event *nats.TenantMemberEvent) error
+}
+
+// MemberImportRow is a single member to import
+type MemberImportRow struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	Name  string `json:"name"`
+}
+
+// MemberImportRowResult reports the outcome for one input row
+type MemberImportRowResult struct {
+	Row       int        `json:"row"` // 1-based position in the payload (CSV header excluded)
+	Email     string     `json:"email"`
+	Role      string     `json:"role,omitempty"`
+	Status    string     `json:"status"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// MemberImportResult is the per-row report of a bulk import
+// Rows succeed or fail independently: one bad row does not fail the whole import
+type MemberImportResult struct {
+	Total   int                     `json:"total"`
+	Linked  int                     `json:"linked"`
+	Invited int                     `json:"invited"`
+	Skipped int                     `json:"skipped"`
+	Failed  int                     `json:"failed"`
+	Results []MemberImportRowResult `json:"results"`
+}
+
+// SetEventPublisher enables publishing of membership events
+func (s *MembershipService) SetEventPublisher(publisher MemberEventPublisher) {
+	s.publisher = publisher
+}
+
+// ParseMemberImportCSV reads members from CSV with a header row.
+// Required column: email. Optional: role (defaults to member) and name,
+// or first_name/last_name which are joined into the name.
+func ParseMemberImportCSV(r io.Reader) ([]MemberImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, NewValidationError("file", "CSV is empty", nil)
+	}
+	if err != nil {
+		return nil, NewValidationError("file", fmt.Sprintf("invalid CSV: %v", err), nil)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		name = strings.ReplaceAll(name, " ", "_")
+		columns[name] = i
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, NewValidationError("file", "CSV header must include an email column", nil)
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []MemberImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, NewValidationError("file", fmt.Sprintf("invalid CSV: %v", err), nil)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue // Blank line
+		}
+
+		name := field(record, "name")
+		if name == "" {
+			name = strings.TrimSpace(field(record, "first_name") + " " + field(record, "last_name"))
+		}
+		rows = append(rows, MemberImportRow{
+			Email: field(record, "email"),
+			Role:  field(record, "role"),
+			Name:  name,
+		})
+		if len(rows) > MaxMemberImportRows {
+			return nil, NewValidationError("members", fmt.Sprintf("at most %d members may be imported at once", MaxMemberImportRows), nil)
+		}
+	}
+	return rows, nil
+}
+
+// ImportMembers links existing users to the tenant and invites everyone else in one batch.
+// The importer must be an owner or admin. Each row is reported individually and a
+// tenant.member.added / tenant.member.invited event is published per successful row.
+func (s *MembershipService) ImportMembers(ctx context.Context, tenantID, importedBy uuid.UUID, rows []MemberImportRow) (*MemberImportResult, error) {
+	if len(rows) == 0 {
+		return nil, NewValidationError("members", "at least one member is required", nil)
+	}
+	if len(rows) > MaxMemberImportRows {
+		return nil, NewValidationError("members", fmt.Sprintf("at most %d members may be imported at once", MaxMemberImportRows), nil)
+	}
+
+	importerRole, err := s.membershipRepo.GetUserRole(ctx, importedBy, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify importer role: %w", err)
+	}
+	if importerRole != models.MembershipRoleOwner && importerRole != models.MembershipRoleAdmin {
+		return nil, fmt.Errorf("only owners and admins can import members")
+	}
+
+	result := &MemberImportResult{
+		Total:   len(rows),
+		Results: make([]MemberImportRowResult, 0, len(rows)),
+	}
+	seen := make(map[string]int, len(rows))
+
+	for i, row := range rows {
+		rowResult := MemberImportRowResult{Row: i + 1}
+		email, role, err := NormalizeMemberImportRow(row)
+		rowResult.Email, rowResult.Role = email, role
+
+		switch {
+		case err != nil:
+			rowResult.Status = MemberImportFailed
+			rowResult.Error = err.Error()
+		case seen[email] > 0:
+			rowResult.Status = MemberImportSkipped
+			rowResult.Error = fmt.Sprintf("duplicate of row %d", seen[email])
+		default:
+			seen[email] = i + 1
+			s.importMember(ctx, tenantID, importedBy, email, role, strings.TrimSpace(row.Name), &rowResult)
+		}
+
+		switch rowResult.Status {
+		case MemberImportLinked:
+			result.Linked++
+		case MemberImportInvited:
+			result.Invited++
+		case MemberImportSkipped:
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+		result.Results = append(result.Results, rowResult)
+	}
+
+	log.Printf("[MembershipService] Imported members into tenant %s: %d linked, %d invited, %d skipped, %d failed",
+		tenantID, result.Linked, result.Invited, result.Skipped, result.Failed)
+	return result, nil
+}
+
+// NormalizeMemberImportRow validates a row, returning the lower-cased email and role (default member)
+func NormalizeMemberImportRow(row MemberImportRow) (string, string, error) {
+	email := strings.ToLower(strings.TrimSpace(row.Email))
+	if email == "" {
+		return "", "", errors.New("email is required")
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return email, "", errors.New("invalid email address")
+	}
+
+	role := strings.ToLower(strings.TrimSpace(row.Role))
+	if role == "" {
+		role = models.MembershipRoleMember
+	}
+	if !importableRoles[role] {
+		return email, role, fmt.Errorf("invalid role %q (allowed: admin, manager, member, viewer)", role)
+	}
+	return email, role, nil
+}
+
+// importMember links an existing user or creates an invitation, filling in the row result
+func (s *MembershipService) importMember(ctx context.Context, tenantID, importedBy uuid.UUID, email, role, name string, rowResult *MemberImportRowResult) {
+	fail := func(err error) {
+		rowResult.Status = MemberImportFailed
+		rowResult.Error = err.Error()
+	}
+
+	user, err := s.membershipRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	if user == nil {
+		token, expiresAt, err := s.createInvitation(ctx, tenantID, importedBy, email, role)
+		if err != nil {
+			fail(err)
+			return
+		}
+		rowResult.Status = MemberImportInvited
+		rowResult.ExpiresAt = &expiresAt
+		s.publishMemberEvent(ctx, &nats.TenantMemberEvent{
+			EventType:           nats.EventTenantMemberInvited,
+			TenantID:            tenantID.String(),
+			Email:               email,
+			Name:                name,
+			Role:                role,
+			InvitedBy:           importedBy.String(),
+			InvitationToken:     token,
+			InvitationExpiresAt: &expiresAt,
+			Source:              "bulk_import",
+		})
+		return
+	}
+
+	rowResult.UserID = &user.ID
+	membership, err := s.membershipRepo.GetMembership(ctx, user.ID, tenantID)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	if membership != nil && membership.IsActive {
+		rowResult.Status = MemberImportSkipped
+		rowResult.Role = membership.Role
+		rowResult.Error = "already a member"
+		return
+	}
+	if err := s.checkMemberQuota(ctx, tenantID); err != nil {
+		fail(err)
+		return
+	}
+
+	now := time.Now()
+	switch {
+	case membership != nil:
+		// Previously removed member: re-activate with the imported role
+		membership.Role = role
+		membership.IsActive = true
+		membership.InvitedBy = &importedBy
+		membership.InvitedAt = &now
+		membership.AcceptedAt = &now
+		membership.Tenant = nil
+		if err := s.membershipRepo.UpdateMembership(ctx, membership); err != nil {
+			fail(err)
+			return
+		}
+	default:
+		if err := s.membershipRepo.CreateMembership(ctx, &models.UserTenantMembership{
+			UserID:     user.ID,
+			TenantID:   tenantID,
+			Role:       role,
+			IsActive:   true,
+			InvitedBy:  &importedBy,
+			InvitedAt:  &now,
+			AcceptedAt: &now,
+		}); err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	if name == "" {
+		name = strings.TrimSpace(user.FirstName + " " + user.LastName)
+	}
+	rowResult.Status = MemberImportLinked
+	s.publishMemberEvent(ctx, &nats.TenantMemberEvent{
+		EventType: nats.EventTenantMemberAdded,
+		TenantID:  tenantID.String(),
+		UserID:    user.ID.String(),
+		Email:     email,
+		Name:      name,
+		Role:      role,
+		InvitedBy: importedBy.String(),
+		Source:    "bulk_import",
+	})
+}
+
+// publishMemberEvent publishes a membership event; failures are logged, not returned,
+// because the membership change has already been committed
+func (s *MembershipService) publishMemberEvent(ctx context.Context, event *nats.TenantMemberEvent) {
+	if s.publisher == nil {
+		return
+	}
+	if err := s.publisher.PublishTenantMemberEvent(ctx, event); err != nil {
+		log.Printf("[MembershipService] Warning: failed to publish %s for tenant %s: %v", event.EventType, event.TenantID, err)
+	}
+}
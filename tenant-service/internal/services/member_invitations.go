@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"tenant-service/internal/config"
+	"tenant-service/internal/listquery"
+	"tenant-service/internal/models"
+	"tenant-service/internal/nats"
+)
+
+const (
+	defaultInvitationTTL             = 7 * 24 * time.Hour
+	defaultInvitationCleanupInterval = time.Hour
+)
+
+var (
+	// ErrInvitationForbidden is returned when the user may not manage the tenant's invitations
+	ErrInvitationForbidden = errors.New("only owners and admins can manage invitations")
+	// ErrInvitationNotFound is returned when the invitation does not exist, belongs to another tenant or was accepted
+	ErrInvitationNotFound = errors.New("invitation not found")
+	// ErrInvitationRevoked is returned when resending or revoking an invitation that was already revoked
+	ErrInvitationRevoked = errors.New("invitation has been revoked")
+)
+
+// InvitationSummary is a pending, expired or revoked invitation as listed to tenant admins
+type InvitationSummary struct {
+	ID         uuid.UUID  `json:"id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	Status     string     `json:"status"`
+	InvitedBy  *uuid.UUID `json:"invited_by,omitempty"`
+	InvitedAt  *time.Time `json:"invited_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	SentCount  int        `json:"sent_count"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// SetInvitationConfig sets the invitation TTL and cleanup settings
+func (s *MembershipService) SetInvitationConfig(cfg config.InvitationConfig) {
+	s.invitationCfg = cfg
+}
+
+// invitationTTL returns how long a new or resent invitation stays valid
+func (s *MembershipService) invitationTTL() time.Duration {
+	if s.invitationCfg.TTLHours <= 0 {
+		return defaultInvitationTTL
+	}
+	return time.Duration(s.invitationCfg.TTLHours) * time.Hour
+}
+
+// InvitationCleanupInterval returns how often expired and revoked invitations are purged
+func (s *MembershipService) InvitationCleanupInterval() time.Duration {
+	if s.invitationCfg.CleanupIntervalMinutes <= 0 {
+		return defaultInvitationCleanupInterval
+	}
+	return time.Duration(s.invitationCfg.CleanupIntervalMinutes) * time.Minute
+}
+
+// ListInvitations returns the tenant's unaccepted invitations matching the list query, optionally filtered by status
+func (s *MembershipService) ListInvitations(ctx context.Context, tenantID, requestedBy uuid.UUID, status string, q *listquery.Query) ([]InvitationSummary, int64, string, error) {
+	if err := s.authorizeInvitations(ctx, tenantID, requestedBy); err != nil {
+		return nil, 0, "", err
+	}
+	switch status {
+	case "", models.InvitationStatusPending, models.InvitationStatusExpired, models.InvitationStatusRevoked:
+	default:
+		return nil, 0, "", NewValidationError("status", "status must be one of pending, expired, revoked", nil)
+	}
+
+	now := time.Now()
+	invitations, total, next, err := s.membershipRepo.ListInvitations(ctx, tenantID, status, now, q)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	summaries := make([]InvitationSummary, 0, len(invitations))
+	for i := range invitations {
+		summaries = append(summaries, toInvitationSummary(&invitations[i], now))
+	}
+	return summaries, total, next, nil
+}
+
+// ResendInvitation issues a fresh token and expiry for a pending or expired invitation and
+// publishes tenant.member.invited again so notification-service re-sends the email
+func (s *MembershipService) ResendInvitation(ctx context.Context, tenantID, invitationID, resentBy uuid.UUID) (*InviteMemberResponse, error) {
+	invitation, err := s.getManagedInvitation(ctx, tenantID, invitationID, resentBy)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.invitationTTL())
+	if err := s.membershipRepo.RenewInvitation(ctx, invitation, token, expiresAt); err != nil {
+		return nil, err
+	}
+
+	if invitation.InvitedEmail != "" {
+		s.publishMemberEvent(ctx, &nats.TenantMemberEvent{
+			EventType:           nats.EventTenantMemberInvited,
+			TenantID:            tenantID.String(),
+			Email:               invitation.InvitedEmail,
+			Role:                invitation.Role,
+			InvitedBy:           resentBy.String(),
+			InvitationToken:     token,
+			InvitationExpiresAt: &expiresAt,
+			Source:              "resend",
+		})
+	}
+	s.logInvitationActivity(ctx, tenantID, resentBy, models.ActivityInvitationResent, invitation)
+
+	return &InviteMemberResponse{
+		InvitationToken: token,
+		ExpiresAt:       expiresAt,
+	}, nil
+}
+
+// RevokeInvitation cancels an invitation so its token can no longer be accepted
+func (s *MembershipService) RevokeInvitation(ctx context.Context, tenantID, invitationID, revokedBy uuid.UUID) error {
+	invitation, err := s.getManagedInvitation(ctx, tenantID, invitationID, revokedBy)
+	if err != nil {
+		return err
+	}
+
+	if err := s.membershipRepo.RevokeInvitation(ctx, invitation, revokedBy); err != nil {
+		return err
+	}
+	s.logInvitationActivity(ctx, tenantID, revokedBy, models.ActivityInvitationRevoked, invitation)
+	return nil
+}
+
+// CleanupInvitations deletes invitations that expired or were revoked longer ago than the retention window
+func (s *MembershipService) CleanupInvitations(ctx context.Context) (int64, error) {
+	retention := time.Duration(s.invitationCfg.RetentionDays) * 24 * time.Hour
+	if retention < 0 {
+		retention = 0
+	}
+	return s.membershipRepo.PurgeInvitations(ctx, time.Now().Add(-retention))
+}
+
+// getManagedInvitation loads an invitation the user may resend or revoke
+func (s *MembershipService) getManagedInvitation(ctx context.Context, tenantID, invitationID, userID uuid.UUID) (*models.UserTenantMembership, error) {
+	if err := s.authorizeInvitations(ctx, tenantID, userID); err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.membershipRepo.GetInvitation(ctx, tenantID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil {
+		return nil, ErrInvitationNotFound
+	}
+	if invitation.InvitationRevokedAt != nil {
+		return nil, ErrInvitationRevoked
+	}
+	return invitation, nil
+}
+
+// authorizeInvitations checks the user is an owner or admin of the tenant
+func (s *MembershipService) authorizeInvitations(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrInvitationForbidden
+	}
+	return nil
+}
+
+// logInvitationActivity records an invitation change in the tenant activity log
+func (s *MembershipService) logInvitationActivity(ctx context.Context, tenantID, userID uuid.UUID, action string, invitation *models.UserTenantMembership) {
+	details := map[string]interface{}{
+		"email": invitation.InvitedEmail,
+		"role":  invitation.Role,
+	}
+	if err := s.LogTenantActivity(ctx, tenantID, userID, action, "invitation", &invitation.ID, details, "", ""); err != nil {
+		log.Printf("[MembershipService] Warning: failed to log %s for tenant %s: %v", action, tenantID, err)
+	}
+}
+
+func toInvitationSummary(invitation *models.UserTenantMembership, now time.Time) InvitationSummary {
+	return InvitationSummary{
+		ID:         invitation.ID,
+		Email:      invitation.InvitedEmail,
+		Role:       invitation.Role,
+		Status:     invitation.InvitationStatus(now),
+		InvitedBy:  invitation.InvitedBy,
+		InvitedAt:  invitation.InvitedAt,
+		ExpiresAt:  invitation.InvitationExpiresAt,
+		SentCount:  invitation.InvitationSentCount,
+		LastSentAt: invitation.InvitationLastSentAt,
+		RevokedAt:  invitation.InvitationRevokedAt,
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"tenant-service/internal/listquery"
+	"tenant-service/internal/models"
+)
+
+var (
+	// ErrNotTenantMember is returned when listing the members of a tenant the user does not belong to
+	ErrNotTenantMember = errors.New("user is not a member of this tenant")
+	// ErrActivityLogForbidden is returned when a non owner/admin reads the tenant activity log
+	ErrActivityLogForbidden = errors.New("only owners and admins can view the activity log")
+)
+
+// TenantMemberSummary is a tenant member as listed to other members
+type TenantMemberSummary struct {
+	UserID         uuid.UUID  `json:"user_id"`
+	Email          string     `json:"email,omitempty"`
+	FirstName      string     `json:"first_name,omitempty"`
+	LastName       string     `json:"last_name,omitempty"`
+	Role           string     `json:"role"`
+	IsActive       bool       `json:"is_active"`
+	InvitedBy      *uuid.UUID `json:"invited_by,omitempty"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// ListMembers lists the tenant's members; any active member may list them
+func (s *MembershipService) ListMembers(ctx context.Context, tenantID, requestedBy uuid.UUID, q *listquery.Query) ([]TenantMemberSummary, int64, string, error) {
+	if _, err := s.membershipRepo.GetUserRole(ctx, requestedBy, tenantID); err != nil {
+		return nil, 0, "", ErrNotTenantMember
+	}
+
+	memberships, total, next, err := s.membershipRepo.ListTenantMemberships(ctx, tenantID, q)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(memberships))
+	for _, membership := range memberships {
+		userIDs = append(userIDs, membership.UserID)
+	}
+	users, err := s.membershipRepo.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	usersByID := make(map[uuid.UUID]models.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	members := make([]TenantMemberSummary, 0, len(memberships))
+	for _, membership := range memberships {
+		member := TenantMemberSummary{
+			UserID:         membership.UserID,
+			Role:           membership.Role,
+			IsActive:       membership.IsActive,
+			InvitedBy:      membership.InvitedBy,
+			AcceptedAt:     membership.AcceptedAt,
+			LastAccessedAt: membership.LastAccessedAt,
+			CreatedAt:      membership.CreatedAt,
+		}
+		if user, ok := usersByID[membership.UserID]; ok {
+			member.Email = user.Email
+			member.FirstName = user.FirstName
+			member.LastName = user.LastName
+		}
+		members = append(members, member)
+	}
+	return members, total, next, nil
+}
+
+// ListActivity lists the tenant's activity log; only owners and admins may read it
+func (s *MembershipService) ListActivity(ctx context.Context, tenantID, requestedBy uuid.UUID, q *listquery.Query) ([]models.TenantActivityLog, int64, string, error) {
+	role, err := s.membershipRepo.GetUserRole(ctx, requestedBy, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return nil, 0, "", ErrActivityLogForbidden
+	}
+	return s.membershipRepo.GetTenantActivityLog(ctx, tenantID, q)
+}
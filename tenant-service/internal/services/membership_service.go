@@ -9,13 +9,53 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"tenant-service/internal/config"
 	"tenant-service/internal/models"
+	natsClient "tenant-service/internal/nats"
 	"tenant-service/internal/repository"
 )
 
 // MembershipService handles user-tenant membership business logic
 type MembershipService struct {
 	membershipRepo *repository.MembershipRepository
+	publisher      MemberEventPublisher
+	invitationCfg  config.InvitationConfig
+	memberQuota    MemberQuotaChecker
+	roleResolver   MemberRoleResolver
+	securityEvents SecurityEventPublisher
+}
+
+// MemberQuotaChecker checks a tenant has room for another member (satisfied by *UsageService)
+type MemberQuotaChecker interface {
+	CheckMemberQuota(ctx context.Context, tenantID uuid.UUID) error
+}
+
+// SetMemberQuotaChecker enforces the plan's member quota on invitations and imports
+func (s *MembershipService) SetMemberQuotaChecker(checker MemberQuotaChecker) {
+	s.memberQuota = checker
+}
+
+// checkMemberQuota returns ErrQuotaExceeded when the tenant has no room for another member
+func (s *MembershipService) checkMemberQuota(ctx context.Context, tenantID uuid.UUID) error {
+	if s.memberQuota == nil {
+		return nil
+	}
+	return s.memberQuota.CheckMemberQuota(ctx, tenantID)
+}
+
+// MemberRoleResolver resolves the permissions stored on a membership for a role (satisfied by *TenantRoleService)
+type MemberRoleResolver interface {
+	ResolveMemberRole(ctx context.Context, tenantID uuid.UUID, role string) (models.JSONB, error)
+}
+
+// SetRoleResolver lets members be assigned the tenant's custom roles
+func (s *MembershipService) SetRoleResolver(resolver MemberRoleResolver) {
+	s.roleResolver = resolver
+}
+
+// SetSecurityEventPublisher enables security.role_escalation events for role changes that widen access
+func (s *MembershipService) SetSecurityEventPublisher(publisher SecurityEventPublisher) {
+	s.securityEvents = publisher
 }
 
 // NewMembershipService creates a new membership service
@@ -25,6 +65,16 @@ func NewMembershipService(membershipRepo *repository.MembershipRepository) *Memb
 	}
 }
 
+// InvalidateTenantCache drops cached lookups for a tenant after it was written outside the membership repository
+func (s *MembershipService) InvalidateTenantCache(ctx context.Context, tenantID uuid.UUID) {
+	s.membershipRepo.InvalidateTenantCache(ctx, tenantID)
+}
+
+// InvalidateDeletedTenantCache drops cached lookups for a purged tenant and its former members
+func (s *MembershipService) InvalidateDeletedTenantCache(ctx context.Context, slug string, memberIDs []uuid.UUID) {
+	s.membershipRepo.InvalidateDeletedTenantCache(ctx, slug, memberIDs)
+}
+
 // ============================================================================
 // Tenant Context Operations
 // ============================================================================
@@ -38,6 +88,12 @@ type UserTenantContext struct {
 	Role       string    `json:"role"`
 	IsOwner    bool      `json:"is_owner"`
 	IsDefault  bool      `json:"is_default"`
+
+	// Subscription state cached from billing, so clients can show trial and payment banners
+	TenantStatus  string     `json:"tenant_status"`
+	PricingTier   string     `json:"pricing_tier"`
+	BillingStatus string     `json:"billing_status,omitempty"`
+	TrialEndsAt   *time.Time `json:"trial_ends_at,omitempty"`
 }
 
 // GetUserTenantContext retrieves the full context for a user accessing a tenant by slug
@@ -70,6 +126,11 @@ func (s *MembershipService) GetUserTenantContext(ctx context.Context, userID uui
 		Role:       membership.Role,
 		IsOwner:    membership.Role == models.MembershipRoleOwner,
 		IsDefault:  membership.IsDefault,
+
+		TenantStatus:  tenant.Status,
+		PricingTier:   tenant.PricingTier,
+		BillingStatus: tenant.BillingStatus,
+		TrialEndsAt:   tenant.TrialEndsAt,
 	}, nil
 }
 
@@ -390,20 +451,9 @@ func (s *MembershipService) InviteMember(ctx context.Context, req *InviteMemberR
 		return nil, fmt.Errorf("only owners and admins can invite members")
 	}
 
-	// Generate invitation token
-	tokenBytes := make([]byte, 32)
-	if _, err := rand.Read(tokenBytes); err != nil {
-		return nil, fmt.Errorf("failed to generate invitation token: %w", err)
-	}
-	token := base64.URLEncoding.EncodeToString(tokenBytes)
-
-	// Set expiry (7 days)
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
-
-	// Create invitation
-	_, err = s.membershipRepo.CreateInvitation(ctx, req.TenantID, req.InvitedBy, req.Email, req.Role, token, expiresAt)
+	token, expiresAt, err := s.createInvitation(ctx, req.TenantID, req.InvitedBy, req.Email, req.Role)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create invitation: %w", err)
+		return nil, err
 	}
 
 	return &InviteMemberResponse{
@@ -412,6 +462,33 @@ func (s *MembershipService) InviteMember(ctx context.Context, req *InviteMemberR
 	}, nil
 }
 
+// createInvitation creates a pending membership with a fresh invitation token
+func (s *MembershipService) createInvitation(ctx context.Context, tenantID, invitedBy uuid.UUID, email, role string) (string, time.Time, error) {
+	if err := s.checkMemberQuota(ctx, tenantID); err != nil {
+		return "", time.Time{}, err
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(s.invitationTTL())
+
+	if _, err := s.membershipRepo.CreateInvitation(ctx, tenantID, invitedBy, email, role, token, expiresAt); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create invitation: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// generateInvitationToken returns a random URL-safe invitation token
+func generateInvitationToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	return base64.URLEncoding.EncodeToString(tokenBytes), nil
+}
+
 // AcceptInvitation accepts a member invitation
 func (s *MembershipService) AcceptInvitation(ctx context.Context, token string, userID uuid.UUID) (*models.UserTenantMembership, error) {
 	return s.membershipRepo.AcceptInvitation(ctx, token, userID)
@@ -460,14 +537,53 @@ func (s *MembershipService) UpdateMemberRole(ctx context.Context, tenantID, memb
 		return fmt.Errorf("cannot change the owner's role")
 	}
 
+	// Owner is transferred, never assigned; other roles are built-in or defined by the tenant
+	if newRole == models.MembershipRoleOwner {
+		return fmt.Errorf("the owner role cannot be assigned")
+	}
+	permissions := models.JSONB(`[]`)
+	if !models.IsBuiltInRole(newRole) {
+		if s.roleResolver == nil {
+			return ErrRoleNotFound
+		}
+		permissions, err = s.roleResolver.ResolveMemberRole(ctx, tenantID, newRole)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get membership and update
 	membership, err := s.membershipRepo.GetMembership(ctx, memberUserID, tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to get membership: %w", err)
 	}
+	if membership == nil {
+		return fmt.Errorf("membership not found")
+	}
 
+	previousRole := membership.Role
+	previousPermissions := membership.PermissionList()
 	membership.Role = newRole
-	return s.membershipRepo.UpdateMembership(ctx, membership)
+	membership.Permissions = permissions
+	if err := s.membershipRepo.UpdateMembership(ctx, membership); err != nil {
+		return err
+	}
+
+	if escalated := models.EscalatedPermissions(previousPermissions, membership.PermissionList()); len(escalated) > 0 {
+		publishSecurityEvent(s.securityEvents, &natsClient.SecurityEvent{
+			EventType:    natsClient.EventSecurityRoleEscalation,
+			TenantID:     tenantID.String(),
+			ActorID:      updatedBy.String(),
+			TargetUserID: memberUserID.String(),
+			Reason:       "member_role_changed",
+			Metadata: map[string]interface{}{
+				"previous_role":    previousRole,
+				"new_role":         newRole,
+				"escalated_grants": escalated,
+			},
+		})
+	}
+	return nil
 }
 
 // ============================================================================
@@ -544,12 +660,24 @@ func (s *MembershipService) ValidateAndReserveSlug(ctx context.Context, slug str
 	return s.membershipRepo.ValidateAndReserveSlug(ctx, slug, sessionID, reservedBy)
 }
 
+// ReserveSlug places or extends the session's hold on a slug without re-validating it
+// Returns repository.ErrSlugTaken if another session or tenant holds the slug
+func (s *MembershipService) ReserveSlug(ctx context.Context, slug string, sessionID uuid.UUID, reservedBy string) (*models.TenantSlugReservation, error) {
+	return s.membershipRepo.ReserveSlug(ctx, slug, sessionID, reservedBy)
+}
+
 // ActivateSlugReservation converts a pending slug reservation to active (permanent)
 // This should be called after the tenant is successfully created
 func (s *MembershipService) ActivateSlugReservation(ctx context.Context, slug string, tenantID uuid.UUID) error {
 	return s.membershipRepo.ActivateSlugReservation(ctx, slug, tenantID)
 }
 
+// ActivateSlugReservationForSession converts the onboarding session's own reservation to active
+// Safe to retry; returns repository.ErrSlugTaken if another session or tenant holds the slug
+func (s *MembershipService) ActivateSlugReservationForSession(ctx context.Context, slug string, sessionID, tenantID uuid.UUID) error {
+	return s.membershipRepo.ActivateSlugReservationForSession(ctx, slug, sessionID, tenantID)
+}
+
 // DeleteMembershipInternal removes a membership without permission checks
 // This is used for cleanup during onboarding failures - should NOT be exposed via API
 func (s *MembershipService) DeleteMembershipInternal(ctx context.Context, userID, tenantID uuid.UUID) error {
@@ -3,16 +3,31 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
 	"github.com/google/uuid"
+	"tenant-service/internal/config"
 	"tenant-service/internal/models"
 	natsClient "tenant-service/internal/nats"
 	"github.com/Tesseract-Nexus/go-shared/auth"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// scheduledPurgeLease is how long a replica holds a due tenant before another may retry its purge
+const scheduledPurgeLease = time.Hour
+
+var (
+	// ErrTenantPendingDeletion is returned when deleting a tenant that is already scheduled for deletion
+	ErrTenantPendingDeletion = errors.New("tenant is already scheduled for deletion")
+	// ErrTenantNotPendingDeletion is returned when restoring a tenant that is not scheduled for deletion
+	ErrTenantNotPendingDeletion = errors.New("tenant is not scheduled for deletion")
+	// ErrTenantRestoreForbidden is returned when a non-owner tries to restore a tenant
+	ErrTenantRestoreForbidden = errors.New("only the tenant owner can restore the tenant")
 )
 
 // OffboardingService handles tenant deletion and offboarding logic
@@ -20,7 +35,9 @@ type OffboardingService struct {
 	db             *gorm.DB
 	membershipSvc  *MembershipService
 	natsClient     *natsClient.Client
-	keycloakClient *auth.KeycloakAdminClient
+	keycloakClient *timedKeycloakClient
+	deletionSaga   *TenantDeletionSagaService
+	config         config.DeletionConfig
 }
 
 // NewOffboardingService creates a new offboarding service
@@ -34,8 +51,26 @@ func NewOffboardingService(
 		db:             db,
 		membershipSvc:  membershipSvc,
 		natsClient:     natsClient,
-		keycloakClient: keycloakClient,
+		keycloakClient: newTimedKeycloakClient(keycloakClient),
+	}
+}
+
+// SetDeletionSaga sets the cross-service deletion saga coordinator
+func (s *OffboardingService) SetDeletionSaga(saga *TenantDeletionSagaService) {
+	s.deletionSaga = saga
+}
+
+// SetDeletionConfig sets the grace period applied before deleted tenants are purged
+func (s *OffboardingService) SetDeletionConfig(cfg config.DeletionConfig) {
+	s.config = cfg
+}
+
+// PurgeInterval returns how often tenants whose grace period elapsed are purged
+func (s *OffboardingService) PurgeInterval() time.Duration {
+	if s.config.PurgeIntervalMinutes <= 0 {
+		return time.Hour
 	}
+	return time.Duration(s.config.PurgeIntervalMinutes) * time.Minute
 }
 
 // DeleteTenantRequest represents the request to delete a tenant
@@ -48,13 +83,16 @@ type DeleteTenantRequest struct {
 
 // DeleteTenantResponse represents the response after deleting a tenant
 type DeleteTenantResponse struct {
-	Message          string    `json:"message"`
-	TenantID         string    `json:"tenant_id"`
-	ArchivedRecordID string    `json:"archived_record_id"`
-	ArchivedAt       time.Time `json:"archived_at"`
+	Message              string     `json:"message"`
+	TenantID             string     `json:"tenant_id"`
+	Status               string     `json:"status"`
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for,omitempty"`
+	ArchivedRecordID     string     `json:"archived_record_id,omitempty"`
+	ArchivedAt           *time.Time `json:"archived_at,omitempty"`
 }
 
-// DeleteTenant deletes a tenant and archives all data for audit purposes
+// DeleteTenant puts a tenant in pending_deletion for the grace period of its pricing tier.
+// The owner can restore it until the period elapses; with no grace period it is purged immediately.
 func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenantRequest) (*DeleteTenantResponse, error) {
 	// 1. Get the tenant with memberships
 	var tenant models.Tenant
@@ -92,6 +130,191 @@ func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenant
 		return nil, fmt.Errorf("invalid confirmation text: expected '%s'", expectedConfirmation)
 	}
 
+	if tenant.Status == models.TenantStatusPendingDeletion {
+		return nil, ErrTenantPendingDeletion
+	}
+
+	graceDays := s.config.GracePeriodDays(tenant.PricingTier)
+	if graceDays <= 0 {
+		return s.purgeTenant(ctx, &tenant, req.UserID, ownerEmail, req.Reason)
+	}
+
+	// 4. Schedule the purge; the tenant stays intact so the owner can restore it
+	now := time.Now()
+	scheduledFor := now.AddDate(0, 0, graceDays)
+	result := s.db.WithContext(ctx).
+		Model(&models.Tenant{}).
+		Where("id = ? AND status <> ?", tenant.ID, models.TenantStatusPendingDeletion).
+		Updates(map[string]interface{}{
+			"status":                 models.TenantStatusPendingDeletion,
+			"status_before_deletion": tenant.Status,
+			"deletion_requested_at":  now,
+			"deletion_requested_by":  req.UserID,
+			"deletion_scheduled_for": scheduledFor,
+			"deletion_reason":        req.Reason,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to schedule tenant deletion: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTenantPendingDeletion
+	}
+
+	s.membershipSvc.InvalidateTenantCache(ctx, tenant.ID)
+
+	log.Printf("[OffboardingService] Tenant %s (ID: %s) scheduled for deletion on %s", tenant.Slug, tenant.ID, scheduledFor.Format(time.RFC3339))
+
+	return &DeleteTenantResponse{
+		Message:              fmt.Sprintf("Tenant scheduled for deletion in %d days", graceDays),
+		TenantID:             tenant.ID.String(),
+		Status:               models.TenantStatusPendingDeletion,
+		DeletionScheduledFor: &scheduledFor,
+	}, nil
+}
+
+// RestoreTenant cancels a pending deletion and returns the tenant to its previous status.
+// Restoring is possible until the purge job has removed the tenant.
+func (s *OffboardingService) RestoreTenant(ctx context.Context, tenantID, userID uuid.UUID) (*models.Tenant, error) {
+	var tenant models.Tenant
+	if err := s.db.WithContext(ctx).
+		Preload("Memberships").
+		First(&tenant, "id = ?", tenantID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("tenant not found")
+		}
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	isOwner := false
+	for _, membership := range tenant.Memberships {
+		if membership.UserID == userID && membership.Role == models.MembershipRoleOwner {
+			isOwner = true
+			break
+		}
+	}
+	if !isOwner {
+		return nil, ErrTenantRestoreForbidden
+	}
+
+	if tenant.Status != models.TenantStatusPendingDeletion {
+		return nil, ErrTenantNotPendingDeletion
+	}
+
+	status := tenant.StatusBeforeDeletion
+	if status == "" {
+		status = "active"
+	}
+
+	// The status condition serialises with the purge transaction, which locks the row
+	result := s.db.WithContext(ctx).
+		Model(&models.Tenant{}).
+		Where("id = ? AND status = ?", tenant.ID, models.TenantStatusPendingDeletion).
+		Updates(map[string]interface{}{
+			"status":                 status,
+			"status_before_deletion": "",
+			"deletion_requested_at":  nil,
+			"deletion_requested_by":  nil,
+			"deletion_scheduled_for": nil,
+			"deletion_reason":        "",
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to restore tenant: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTenantNotPendingDeletion
+	}
+
+	s.membershipSvc.InvalidateTenantCache(ctx, tenant.ID)
+
+	log.Printf("[OffboardingService] Restored tenant %s (ID: %s) to status %s", tenant.Slug, tenant.ID, status)
+
+	tenant.Status = status
+	tenant.StatusBeforeDeletion = ""
+	tenant.DeletionRequestedAt = nil
+	tenant.DeletionRequestedBy = nil
+	tenant.DeletionScheduledFor = nil
+	tenant.DeletionReason = ""
+	return &tenant, nil
+}
+
+// PurgeScheduledDeletions offboards tenants whose grace period has elapsed and returns how many were purged.
+// Each tenant is claimed with a lease so replicas do not purge the same tenant twice.
+func (s *OffboardingService) PurgeScheduledDeletions(ctx context.Context) (int, error) {
+	var due []models.Tenant
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND deletion_scheduled_for <= ?", models.TenantStatusPendingDeletion, time.Now()).
+		Order("deletion_scheduled_for ASC").
+		Limit(50).
+		Find(&due).Error; err != nil {
+		return 0, fmt.Errorf("failed to list tenants due for deletion: %w", err)
+	}
+
+	purged := 0
+	for i := range due {
+		tenant := &due[i]
+
+		now := time.Now()
+		claim := s.db.WithContext(ctx).
+			Model(&models.Tenant{}).
+			Where("id = ? AND status = ? AND deletion_scheduled_for <= ?", tenant.ID, models.TenantStatusPendingDeletion, now).
+			Update("deletion_scheduled_for", now.Add(scheduledPurgeLease))
+		if claim.Error != nil {
+			log.Printf("[OffboardingService] Failed to claim tenant %s for purge: %v", tenant.ID, claim.Error)
+			continue
+		}
+		if claim.RowsAffected == 0 {
+			continue // Restored or claimed by another replica
+		}
+
+		if err := s.db.WithContext(ctx).
+			Preload("Memberships").
+			First(tenant, "id = ?", tenant.ID).Error; err != nil {
+			log.Printf("[OffboardingService] Failed to reload tenant %s for purge: %v", tenant.ID, err)
+			continue
+		}
+
+		requestedBy := uuid.Nil
+		if tenant.DeletionRequestedBy != nil {
+			requestedBy = *tenant.DeletionRequestedBy
+		} else if tenant.OwnerUserID != nil {
+			requestedBy = *tenant.OwnerUserID
+		}
+		var ownerEmail string
+		var user models.User
+		if err := s.db.WithContext(ctx).First(&user, "id = ?", requestedBy).Error; err == nil {
+			ownerEmail = user.Email
+		}
+
+		if _, err := s.purgeTenant(ctx, tenant, requestedBy, ownerEmail, tenant.DeletionReason); err != nil {
+			log.Printf("[OffboardingService] Failed to purge tenant %s after grace period: %v - will be retried", tenant.Slug, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// purgeTenant archives and permanently deletes a tenant, then runs the offboarding side effects
+// (Keycloak cleanup, tenant.deleted event for router and K8s cleanup, cross-service deletion saga)
+func (s *OffboardingService) purgeTenant(ctx context.Context, tenant *models.Tenant, userID uuid.UUID, ownerEmail, reason string) (*DeleteTenantResponse, error) {
+	// Members whose cached memberships include the tenant, invalidated once it is gone
+	var memberIDs []uuid.UUID
+	if err := s.db.WithContext(ctx).
+		Model(&models.UserTenantMembership{}).
+		Where("tenant_id = ?", tenant.ID).
+		Pluck("user_id", &memberIDs).Error; err != nil {
+		log.Printf("[OffboardingService] Warning: Failed to list members of tenant %s: %v", tenant.ID, err)
+	}
+
+	// Former slugs still redirecting to the tenant, whose routes are removed with it
+	var formerSlugs []string
+	if err := s.db.WithContext(ctx).
+		Model(&models.TenantSlugRedirect{}).
+		Where("tenant_id = ?", tenant.ID).
+		Pluck("old_slug", &formerSlugs).Error; err != nil {
+		log.Printf("[OffboardingService] Warning: Failed to list former slugs of tenant %s: %v", tenant.ID, err)
+	}
+
 	// 4. Start transaction
 	tx := s.db.Begin()
 	if tx.Error != nil {
@@ -104,6 +327,23 @@ func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenant
 		}
 	}()
 
+	// Lock the tenant and re-check its status so a concurrent restore cannot race the purge
+	var locked models.Tenant
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		First(&locked, "id = ?", tenant.ID).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("tenant not found")
+		}
+		return nil, fmt.Errorf("failed to lock tenant: %w", err)
+	}
+	if locked.Status != tenant.Status {
+		tx.Rollback()
+		return nil, fmt.Errorf("tenant status changed to %s during deletion", locked.Status)
+	}
+
 	// 5. Create JSON snapshot of tenant data
 	tenantJSON, err := json.Marshal(tenant)
 	if err != nil {
@@ -122,7 +362,7 @@ func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenant
 	var vendors []map[string]interface{}
 	if err := tx.WithContext(ctx).
 		Table("vendors").
-		Where("tenant_id = ?", req.TenantID.String()).
+		Where("tenant_id = ?", tenant.ID.String()).
 		Find(&vendors).Error; err != nil {
 		log.Printf("[OffboardingService] Warning: Failed to fetch vendors: %v", err)
 		vendors = []map[string]interface{}{}
@@ -155,14 +395,14 @@ func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenant
 		OriginalTenantID: tenant.ID,
 		Slug:             tenant.Slug,
 		BusinessName:     tenant.Name,
-		OwnerUserID:      req.UserID,
+		OwnerUserID:      userID,
 		OwnerEmail:       ownerEmail,
 		TenantData:       models.JSONB(tenantJSON),
 		MembershipsData:  models.JSONB(membershipsJSON),
 		VendorsData:      models.JSONB(vendorsJSON),
 		StorefrontsData:  models.JSONB(storefrontsJSON),
-		DeletedByUserID:  req.UserID,
-		DeletionReason:   req.Reason,
+		DeletedByUserID:  userID,
+		DeletionReason:   reason,
 	}
 
 	if err := tx.WithContext(ctx).Create(deletedTenant).Error; err != nil {
@@ -174,7 +414,7 @@ func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenant
 
 	// 8. Delete user_tenant_memberships
 	if err := tx.WithContext(ctx).
-		Where("tenant_id = ?", req.TenantID).
+		Where("tenant_id = ?", tenant.ID).
 		Delete(&models.UserTenantMembership{}).Error; err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to delete memberships: %w", err)
@@ -195,17 +435,51 @@ func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenant
 
 	// 8c. Delete vendors
 	result := tx.WithContext(ctx).
-		Exec("DELETE FROM vendors WHERE tenant_id = ?", req.TenantID.String())
+		Exec("DELETE FROM vendors WHERE tenant_id = ?", tenant.ID.String())
 	if result.Error != nil {
 		log.Printf("[OffboardingService] Warning: Failed to delete vendors: %v", result.Error)
 	} else {
 		log.Printf("[OffboardingService] Deleted %d vendors for tenant %s", result.RowsAffected, tenant.ID)
 	}
 
+	// 8d. Delete usage metering (counters, quota notices, metered event keys)
+	for _, usageModel := range []interface{}{&models.TenantUsageCounter{}, &models.TenantQuotaNotice{}, &models.TenantUsageEvent{}} {
+		if err := tx.WithContext(ctx).
+			Where("tenant_id = ?", tenant.ID).
+			Delete(usageModel).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to delete usage records: %w", err)
+		}
+	}
+
+	// 8e. Delete the subscription (plan, trial and feature overrides)
+	if err := tx.WithContext(ctx).
+		Where("tenant_id = ?", tenant.ID).
+		Delete(&models.TenantSubscription{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to delete subscription: %w", err)
+	}
+
+	// 8f. Delete custom roles (memberships holding them were deleted above)
+	if err := tx.WithContext(ctx).
+		Where("tenant_id = ?", tenant.ID).
+		Delete(&models.TenantRole{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to delete roles: %w", err)
+	}
+
+	// 8g. Delete redirects from former slugs (their reservations are released below)
+	if err := tx.WithContext(ctx).
+		Where("tenant_id = ?", tenant.ID).
+		Delete(&models.TenantSlugRedirect{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to delete slug redirects: %w", err)
+	}
+
 	// 9. Release slug reservation
 	if err := tx.WithContext(ctx).
 		Model(&models.TenantSlugReservation{}).
-		Where("tenant_id = ?", req.TenantID).
+		Where("tenant_id = ?", tenant.ID).
 		Updates(map[string]interface{}{
 			"status":      models.SlugReservationReleased,
 			"released_at": time.Now(),
@@ -217,7 +491,7 @@ func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenant
 	// 10. Hard delete the tenant (we have the archived copy)
 	if err := tx.WithContext(ctx).
 		Unscoped(). // Bypass soft delete
-		Delete(&models.Tenant{}, "id = ?", req.TenantID).Error; err != nil {
+		Delete(&models.Tenant{}, "id = ?", tenant.ID).Error; err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to delete tenant: %w", err)
 	}
@@ -228,6 +502,7 @@ func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenant
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	s.membershipSvc.InvalidateDeletedTenantCache(ctx, tenant.Slug, memberIDs)
 
 	// 11b. Disable Keycloak Organization (don't delete - preserve audit trail)
 	// This prevents any new logins to this tenant's identity context
@@ -275,10 +550,31 @@ func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenant
 		} else {
 			log.Printf("[OffboardingService] Published tenant.deleted event for %s", tenant.Slug)
 		}
+
+		// Former slugs still had routes while they redirected
+		for _, formerSlug := range formerSlugs {
+			if err := s.natsClient.PublishTenantSlugReleased(publishCtx, &natsClient.TenantSlugReleasedEvent{
+				TenantID:    tenant.ID.String(),
+				Slug:        formerSlug,
+				CurrentSlug: tenant.Slug,
+			}); err != nil {
+				log.Printf("[OffboardingService] Warning: Failed to publish tenant.slug.released for %s: %v", formerSlug, err)
+			}
+		}
 	} else {
 		log.Printf("[OffboardingService] WARNING: NATS client not initialized, tenant.deleted event not published")
 	}
 
+	// 12b. Start the deletion saga so other services purge their tenant data
+	// Failures are retried by the background runner; the local deletion has already committed
+	if s.deletionSaga != nil {
+		sagaCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.deletionSaga.Start(sagaCtx, deletedTenant); err != nil {
+			log.Printf("[OffboardingService] WARNING: Failed to start deletion saga for tenant %s: %v - will be retried", tenant.Slug, err)
+		}
+	}
+
 	// 13. Remove Keycloak redirect URIs for the deleted tenant
 	if s.keycloakClient != nil {
 		baseDomain := os.Getenv("BASE_DOMAIN")
@@ -318,8 +614,9 @@ func (s *OffboardingService) DeleteTenant(ctx context.Context, req *DeleteTenant
 	return &DeleteTenantResponse{
 		Message:          "Tenant deleted successfully",
 		TenantID:         tenant.ID.String(),
+		Status:           "deleted",
 		ArchivedRecordID: deletedTenant.ID.String(),
-		ArchivedAt:       deletedTenant.DeletedAt,
+		ArchivedAt:       &deletedTenant.DeletedAt,
 	}, nil
 }
 
@@ -360,6 +657,9 @@ func (s *OffboardingService) GetTenantForDeletion(ctx context.Context, tenantID,
 		"created_at":    tenant.CreatedAt,
 		"is_owner":      isOwner,
 		"confirmation_required": fmt.Sprintf("DELETE %s", tenant.Slug),
+		"status":                 tenant.Status,
+		"grace_period_days":      s.config.GracePeriodDays(tenant.PricingTier),
+		"deletion_scheduled_for": tenant.DeletionScheduledFor,
 	}, nil
 }
 
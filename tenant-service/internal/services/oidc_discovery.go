@@ -0,0 +1,57 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+// OIDCDiscovery is what tenant SSO needs from an IdP's OpenID Connect discovery document
+type OIDCDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCDiscoveryURL returns the discovery document URL for an issuer URL; URLs that already
+// point at the document are returned unchanged
+func OIDCDiscoveryURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasSuffix(raw, oidcDiscoveryPath) {
+		return raw
+	}
+	return strings.TrimSuffix(raw, "/") + oidcDiscoveryPath
+}
+
+// ParseOIDCDiscovery validates a discovery document fetched from discoveryURL. The issuer must
+// be the URL the document was published under, as OpenID Connect Discovery requires.
+func ParseOIDCDiscovery(data []byte, discoveryURL string) (*OIDCDiscovery, error) {
+	var discovery OIDCDiscovery
+	if err := json.Unmarshal(data, &discovery); err != nil {
+		return nil, NewValidationError("discovery_url", "discovery_url did not return an OpenID configuration document", nil)
+	}
+
+	fields := []struct {
+		name, value string
+	}{
+		{"issuer", discovery.Issuer},
+		{"authorization_endpoint", discovery.AuthorizationEndpoint},
+		{"token_endpoint", discovery.TokenEndpoint},
+		{"jwks_uri", discovery.JWKSURI},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			return nil, NewValidationError("discovery_url", "discovery document has no "+field.name, nil)
+		}
+		if err := validateHTTPSURL(field.name, field.value); err != nil {
+			return nil, NewValidationError("discovery_url", "discovery document "+field.name+" must be an https URL", nil)
+		}
+	}
+
+	if OIDCDiscoveryURL(discovery.Issuer) != OIDCDiscoveryURL(discoveryURL) {
+		return nil, NewValidationError("discovery_url", "discovery document issuer does not match discovery_url", nil)
+	}
+	return &discovery, nil
+}
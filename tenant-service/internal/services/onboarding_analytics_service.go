@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+)
+
+const (
+	defaultFunnelRefreshInterval = 15 * time.Minute
+	defaultFunnelLookbackDays    = 30
+	defaultFunnelMaxRangeDays    = 366
+	// defaultFunnelRangeDays is the funnel window when no from date is given
+	defaultFunnelRangeDays = 30
+)
+
+// funnelApplicationTypes are the application types accepted by the funnel filter
+var funnelApplicationTypes = map[string]bool{
+	"ecommerce":   true,
+	"saas":        true,
+	"marketplace": true,
+	"b2b":         true,
+}
+
+// refreshFunnelSQL rebuilds the funnel summary for sessions started on or after the given day.
+// Every step a session has a task for is counted, so steps nobody completed still show up with 0.
+const refreshFunnelSQL = `
+INSERT INTO onboarding_funnel_daily (day, application_type, stage_key, stage_name, stage_order, sessions, refreshed_at)
+SELECT (s.started_at AT TIME ZONE 'UTC')::date, s.application_type, @started, 'Session started', @startedOrder, COUNT(*), @now
+FROM onboarding_sessions s
+WHERE s.started_at >= @since
+GROUP BY 1, 2
+UNION ALL
+SELECT (s.started_at AT TIME ZONE 'UTC')::date, s.application_type, @stepPrefix || t.task_id, MIN(t.name), MIN(t.order_index),
+       COUNT(DISTINCT s.id) FILTER (WHERE t.status IN ('completed', 'skipped')), @now
+FROM onboarding_sessions s
+JOIN onboarding_tasks t ON t.onboarding_session_id = s.id
+WHERE s.started_at >= @since
+GROUP BY 1, 2, t.task_id
+UNION ALL
+SELECT (s.started_at AT TIME ZONE 'UTC')::date, s.application_type, @emailVerified, 'Email verified', @emailVerifiedOrder, COUNT(DISTINCT s.id), @now
+FROM onboarding_sessions s
+JOIN verification_records v ON v.onboarding_session_id = s.id
+WHERE s.started_at >= @since AND v.verification_type = 'email' AND v.status = 'verified'
+GROUP BY 1, 2
+UNION ALL
+SELECT (s.started_at AT TIME ZONE 'UTC')::date, s.application_type, @completed, 'Onboarding completed', @completedOrder, COUNT(*), @now
+FROM onboarding_sessions s
+WHERE s.started_at >= @since AND s.status = 'completed'
+GROUP BY 1, 2`
+
+// OnboardingFunnelQuery selects the sessions included in a funnel
+type OnboardingFunnelQuery struct {
+	From            time.Time // First session start day (inclusive)
+	To              time.Time // Last session start day (inclusive)
+	ApplicationType string    // Optional filter
+}
+
+// OnboardingFunnelStage is one stage of the onboarding funnel
+type OnboardingFunnelStage struct {
+	Key            string  `json:"key"`
+	Name           string  `json:"name"`
+	Order          int     `json:"order"`
+	Sessions       int64   `json:"sessions"`
+	ConversionRate float64 `json:"conversion_rate"` // Share of started sessions that reached this stage
+	DropOff        int64   `json:"drop_off"`        // Sessions lost since the previous stage
+	DropOffRate    float64 `json:"drop_off_rate"`   // DropOff as a share of the previous stage
+}
+
+// OnboardingFunnel is the drop-off view of onboarding sessions over a date range
+type OnboardingFunnel struct {
+	From              string                  `json:"from"`
+	To                string                  `json:"to"`
+	ApplicationType   string                  `json:"application_type,omitempty"`
+	SessionsStarted   int64                   `json:"sessions_started"`
+	SessionsCompleted int64                   `json:"sessions_completed"`
+	CompletionRate    float64                 `json:"completion_rate"`
+	Stages            []OnboardingFunnelStage `json:"stages"`
+	RefreshedAt       *time.Time              `json:"refreshed_at,omitempty"`
+}
+
+// OnboardingAnalyticsService maintains the onboarding funnel summary and serves funnel queries
+type OnboardingAnalyticsService struct {
+	db  *gorm.DB
+	cfg config.FunnelConfig
+}
+
+// NewOnboardingAnalyticsService creates a new onboarding analytics service
+func NewOnboardingAnalyticsService(db *gorm.DB, cfg config.FunnelConfig) *OnboardingAnalyticsService {
+	return &OnboardingAnalyticsService{db: db, cfg: cfg}
+}
+
+// RefreshInterval returns how often the funnel summary is rebuilt
+func (s *OnboardingAnalyticsService) RefreshInterval() time.Duration {
+	if s.cfg.RefreshIntervalMinutes <= 0 {
+		return defaultFunnelRefreshInterval
+	}
+	return time.Duration(s.cfg.RefreshIntervalMinutes) * time.Minute
+}
+
+// RefreshFunnelSummary recomputes the funnel summary for sessions started within the lookback window.
+// Older days are final because sessions expire long before the window closes. When the summary is
+// empty (first run), all sessions are summarized.
+func (s *OnboardingAnalyticsService) RefreshFunnelSummary(ctx context.Context) (int64, error) {
+	lookbackDays := s.cfg.LookbackDays
+	if lookbackDays <= 0 {
+		lookbackDays = defaultFunnelLookbackDays
+	}
+	now := time.Now().UTC()
+	since := truncateToDay(now).AddDate(0, 0, -lookbackDays)
+
+	var existing int64
+	if err := s.db.WithContext(ctx).Model(&models.OnboardingFunnelDaily{}).Limit(1).Count(&existing).Error; err != nil {
+		return 0, fmt.Errorf("failed to check funnel summary: %w", err)
+	}
+	if existing == 0 {
+		since = time.Time{}
+	}
+
+	var rows int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("day >= ?", since).Delete(&models.OnboardingFunnelDaily{}).Error; err != nil {
+			return fmt.Errorf("failed to clear funnel summary: %w", err)
+		}
+		result := tx.Exec(refreshFunnelSQL, map[string]interface{}{
+			"since":              since,
+			"now":                now,
+			"started":            models.FunnelStageStarted,
+			"startedOrder":       models.FunnelStageStartedOrder,
+			"stepPrefix":         models.FunnelStepPrefix,
+			"emailVerified":      models.FunnelStageEmailVerified,
+			"emailVerifiedOrder": models.FunnelStageEmailVerifiedOrder,
+			"completed":          models.FunnelStageCompleted,
+			"completedOrder":     models.FunnelStageCompletedOrder,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to rebuild funnel summary: %w", result.Error)
+		}
+		rows = result.RowsAffected
+		return nil
+	})
+	return rows, err
+}
+
+// GetFunnel returns the funnel of sessions started between query.From and query.To
+func (s *OnboardingAnalyticsService) GetFunnel(ctx context.Context, query OnboardingFunnelQuery) (*OnboardingFunnel, error) {
+	from, to, err := s.normalizeFunnelRange(query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+	if query.ApplicationType != "" && !funnelApplicationTypes[query.ApplicationType] {
+		return nil, NewValidationError("applicationType", "applicationType must be one of ecommerce, saas, marketplace, b2b", nil)
+	}
+
+	db := s.db.WithContext(ctx).Model(&models.OnboardingFunnelDaily{}).
+		Where("day BETWEEN ? AND ?", from, to)
+	if query.ApplicationType != "" {
+		db = db.Where("application_type = ?", query.ApplicationType)
+	}
+
+	var rows []models.OnboardingFunnelDaily
+	if err := db.Session(&gorm.Session{}).
+		Select("stage_key, MIN(stage_name) AS stage_name, MIN(stage_order) AS stage_order, SUM(sessions) AS sessions").
+		Group("stage_key").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query funnel summary: %w", err)
+	}
+
+	funnel := BuildOnboardingFunnel(rows)
+	funnel.From = from.Format("2006-01-02")
+	funnel.To = to.Format("2006-01-02")
+	funnel.ApplicationType = query.ApplicationType
+
+	var refreshedAt *time.Time
+	if err := s.db.WithContext(ctx).Model(&models.OnboardingFunnelDaily{}).
+		Select("MAX(refreshed_at)").Scan(&refreshedAt).Error; err == nil {
+		funnel.RefreshedAt = refreshedAt
+	}
+	return funnel, nil
+}
+
+// normalizeFunnelRange applies the default range and validates from/to
+func (s *OnboardingAnalyticsService) normalizeFunnelRange(from, to time.Time) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	to = truncateToDay(to)
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -(defaultFunnelRangeDays - 1))
+	}
+	from = truncateToDay(from)
+
+	if from.After(to) {
+		return from, to, NewValidationError("from", "from must not be after to", nil)
+	}
+	maxRangeDays := s.cfg.MaxRangeDays
+	if maxRangeDays <= 0 {
+		maxRangeDays = defaultFunnelMaxRangeDays
+	}
+	if to.Sub(from) >= time.Duration(maxRangeDays)*24*time.Hour {
+		return from, to, NewValidationError("from", fmt.Sprintf("date range must not exceed %d days", maxRangeDays), nil)
+	}
+	return from, to, nil
+}
+
+// BuildOnboardingFunnel orders summarized stages and computes conversion and drop-off.
+// Drop-off is relative to the previous stage; a stage reached by more sessions than the
+// previous one (optional or out-of-order steps) reports no drop-off.
+func BuildOnboardingFunnel(rows []models.OnboardingFunnelDaily) *OnboardingFunnel {
+	sorted := make([]models.OnboardingFunnelDaily, len(rows))
+	copy(sorted, rows)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].StageOrder != sorted[j].StageOrder {
+			return sorted[i].StageOrder < sorted[j].StageOrder
+		}
+		return sorted[i].StageKey < sorted[j].StageKey
+	})
+
+	funnel := &OnboardingFunnel{Stages: make([]OnboardingFunnelStage, 0, len(sorted))}
+	for _, row := range sorted {
+		switch row.StageKey {
+		case models.FunnelStageStarted:
+			funnel.SessionsStarted = row.Sessions
+		case models.FunnelStageCompleted:
+			funnel.SessionsCompleted = row.Sessions
+		}
+	}
+
+	var previous int64
+	for i, row := range sorted {
+		stage := OnboardingFunnelStage{
+			Key:            row.StageKey,
+			Name:           row.StageName,
+			Order:          row.StageOrder,
+			Sessions:       row.Sessions,
+			ConversionRate: funnelRate(row.Sessions, funnel.SessionsStarted),
+		}
+		if i > 0 && previous > row.Sessions {
+			stage.DropOff = previous - row.Sessions
+			stage.DropOffRate = funnelRate(stage.DropOff, previous)
+		}
+		previous = row.Sessions
+		funnel.Stages = append(funnel.Stages, stage)
+	}
+
+	funnel.CompletionRate = funnelRate(funnel.SessionsCompleted, funnel.SessionsStarted)
+	return funnel
+}
+
+// funnelRate returns part/whole rounded to four decimals, or 0 when whole is 0
+func funnelRate(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(int64(float64(part)/float64(whole)*10000+0.5)) / 10000
+}
+
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
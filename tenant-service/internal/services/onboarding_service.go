@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/google/uuid"
 	"tenant-service/internal/clients"
+	"tenant-service/internal/metrics"
 	"tenant-service/internal/models"
 	natsClient "tenant-service/internal/nats"
 	"tenant-service/internal/repository"
@@ -79,6 +81,7 @@ type OnboardingService struct {
 	onboardingRepo       *repository.OnboardingRepository
 	taskRepo             *repository.TaskRepository
 	businessRepo         *repository.BusinessInformationRepository
+	templateRepo         *repository.TemplateRepository
 	contactRepo          *repository.ContactInformationRepository
 	credentialRepo       *repository.CredentialRepository
 	verificationSvc      *VerificationService
@@ -90,11 +93,22 @@ type OnboardingService struct {
 	tenantRouterClient   *clients.TenantRouterClient
 	customDomainClient   *clients.CustomDomainClient
 	natsClient           *natsClient.Client
-	keycloakClient       *auth.KeycloakAdminClient
+	keycloakClient       *timedKeycloakClient
 	keycloakConfig       *KeycloakOnboardingConfig
+	subscriptionStarter  TenantSubscriptionStarter
 	db                   *gorm.DB
 }
 
+// TenantSubscriptionStarter puts a new tenant on its first plan (satisfied by *SubscriptionService)
+type TenantSubscriptionStarter interface {
+	StartTenantSubscription(ctx context.Context, tenantID uuid.UUID, trialDays int) error
+}
+
+// SetSubscriptionStarter enables plan assignment (and trials) for tenants created by onboarding
+func (s *OnboardingService) SetSubscriptionStarter(starter TenantSubscriptionStarter) {
+	s.subscriptionStarter = starter
+}
+
 // KeycloakOnboardingConfig holds Keycloak configuration for onboarding
 type KeycloakOnboardingConfig struct {
 	ClientID         string // Public client ID for password grant (e.g., "tesserix-onboarding")
@@ -149,6 +163,7 @@ func NewOnboardingService(
 		onboardingRepo:       onboardingRepo,
 		taskRepo:             taskRepo,
 		businessRepo:         repository.NewBusinessInformationRepository(db),
+		templateRepo:         repository.NewTemplateRepository(db),
 		contactRepo:          repository.NewContactInformationRepository(db),
 		credentialRepo:       repository.NewCredentialRepository(db),
 		verificationSvc:      verificationSvc,
@@ -160,7 +175,7 @@ func NewOnboardingService(
 		tenantRouterClient:   tenantRouterClient,
 		customDomainClient:   customDomainClient,
 		natsClient:           nc,
-		keycloakClient:       keycloakClient,
+		keycloakClient:       newTimedKeycloakClient(keycloakClient),
 		keycloakConfig:       keycloakConfig,
 		db:                   db,
 	}
@@ -255,6 +270,13 @@ type StartOnboardingRequest struct {
 
 // StartOnboarding creates a new onboarding session
 func (s *OnboardingService) StartOnboarding(ctx context.Context, req *StartOnboardingRequest) (*models.OnboardingSession, error) {
+	// Pin the session to the template version it starts with, so publishing a new
+	// version does not change the flow of sessions already in progress
+	template, err := s.templateRepo.GetTemplateByID(ctx, req.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load onboarding template: %w", err)
+	}
+
 	// Create onboarding session
 	metadata, _ := models.NewJSONB(req.Metadata)
 	now := time.Now()
@@ -263,6 +285,7 @@ func (s *OnboardingService) StartOnboarding(ctx context.Context, req *StartOnboa
 	session := &models.OnboardingSession{
 		ID:                 uuid.New(),
 		TemplateID:         req.TemplateID,
+		TemplateVersion:    template.Version,
 		ApplicationType:    req.ApplicationType,
 		Status:             "started",
 		CurrentStep:        "business_information",
@@ -284,6 +307,7 @@ func (s *OnboardingService) StartOnboarding(ctx context.Context, req *StartOnboa
 		return nil, fmt.Errorf("failed to initialize session tasks: %w", err)
 	}
 
+	metrics.RecordOnboardingSession(ctx, createdSession.ApplicationType, metrics.SessionStarted)
 	return createdSession, nil
 }
 
@@ -299,7 +323,8 @@ func (s *OnboardingService) GetPendingSessionByEmail(ctx context.Context, email
 }
 
 // SaveApplicationConfiguration saves or updates an application configuration for a session
-func (s *OnboardingService) SaveApplicationConfiguration(ctx context.Context, sessionID uuid.UUID, config *models.ApplicationConfiguration) (*models.ApplicationConfiguration, error) {
+func (s *OnboardingService) SaveApplicationConfiguration(ctx context.Context, sessionID uuid.UUID, config *models.ApplicationConfiguration) (_ *models.ApplicationConfiguration, err error) {
+	defer metrics.ObserveOnboardingStep(ctx, metrics.StepApplicationConfiguration, time.Now(), &err)
 	// Verify session exists and is active
 	session, err := s.onboardingRepo.GetSessionByID(ctx, sessionID, nil)
 	if err != nil {
@@ -336,7 +361,8 @@ func (s *OnboardingService) SaveApplicationConfiguration(ctx context.Context, se
 }
 
 // UpdateBusinessInformation updates business information for a session
-func (s *OnboardingService) UpdateBusinessInformation(ctx context.Context, sessionID uuid.UUID, businessInfo *models.BusinessInformation) (*models.BusinessInformation, error) {
+func (s *OnboardingService) UpdateBusinessInformation(ctx context.Context, sessionID uuid.UUID, businessInfo *models.BusinessInformation) (_ *models.BusinessInformation, err error) {
+	defer metrics.ObserveOnboardingStep(ctx, metrics.StepBusinessInformation, time.Now(), &err)
 	// Verify session exists and is active
 	session, err := s.onboardingRepo.GetSessionByID(ctx, sessionID, nil)
 	if err != nil {
@@ -347,6 +373,13 @@ func (s *OnboardingService) UpdateBusinessInformation(ctx context.Context, sessi
 		return nil, fmt.Errorf("cannot update business information for session in %s status", session.Status)
 	}
 
+	// Validate the template's custom fields for this step and store them normalized
+	customFields, err := s.validateStepCustomFields(ctx, session, models.CustomFieldStepBusinessInformation, businessInfo.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+	businessInfo.CustomFields = customFields
+
 	// Validate business name uniqueness before saving
 	// This ensures no two tenants can have the same business name
 	if businessInfo.BusinessName != "" {
@@ -384,7 +417,8 @@ func (s *OnboardingService) UpdateBusinessInformation(ctx context.Context, sessi
 }
 
 // UpdateContactInformation adds contact information for a session
-func (s *OnboardingService) UpdateContactInformation(ctx context.Context, sessionID uuid.UUID, contact *models.ContactInformation) (*models.ContactInformation, error) {
+func (s *OnboardingService) UpdateContactInformation(ctx context.Context, sessionID uuid.UUID, contact *models.ContactInformation) (_ *models.ContactInformation, err error) {
+	defer metrics.ObserveOnboardingStep(ctx, metrics.StepContactInformation, time.Now(), &err)
 	// Verify session exists and is active
 	session, err := s.onboardingRepo.GetSessionByID(ctx, sessionID, nil)
 	if err != nil {
@@ -415,7 +449,8 @@ func (s *OnboardingService) UpdateContactInformation(ctx context.Context, sessio
 }
 
 // UpdateBusinessAddress adds business address for a session
-func (s *OnboardingService) UpdateBusinessAddress(ctx context.Context, sessionID uuid.UUID, address *models.BusinessAddress) (*models.BusinessAddress, error) {
+func (s *OnboardingService) UpdateBusinessAddress(ctx context.Context, sessionID uuid.UUID, address *models.BusinessAddress) (_ *models.BusinessAddress, err error) {
+	defer metrics.ObserveOnboardingStep(ctx, metrics.StepBusinessAddress, time.Now(), &err)
 	// Verify session exists and is active
 	session, err := s.onboardingRepo.GetSessionByID(ctx, sessionID, nil)
 	if err != nil {
@@ -478,6 +513,7 @@ func (s *OnboardingService) CompleteOnboarding(ctx context.Context, sessionID uu
 	if err != nil {
 		return nil, fmt.Errorf("failed to update session: %w", err)
 	}
+	metrics.RecordOnboardingSession(ctx, session.ApplicationType, metrics.SessionCompleted)
 
 	// Trigger post-completion tasks (webhooks, notifications, etc.)
 	go s.handlePostCompletion(context.Background(), sessionID)
@@ -652,6 +688,22 @@ func (s *OnboardingService) ValidateAndReserveSlug(ctx context.Context, slug str
 	return result, nil
 }
 
+// sessionCanUseSlug re-confirms the session's hold on a slug right before tenant creation, so a
+// hold that expired and was picked up by another session is not used twice
+func (s *OnboardingService) sessionCanUseSlug(ctx context.Context, slug string, sessionID uuid.UUID) bool {
+	if s.membershipSvc == nil {
+		return true
+	}
+	if _, err := s.membershipSvc.ReserveSlug(ctx, slug, sessionID, sessionID.String()); err != nil {
+		if errors.Is(err, repository.ErrSlugTaken) {
+			return false
+		}
+		// Fall back to the tenants table check rather than blocking onboarding
+		log.Printf("[OnboardingService] Warning: Failed to confirm slug reservation '%s': %v", slug, err)
+	}
+	return true
+}
+
 // UpdateStorefrontSlug updates the storefront slug in the session's business information
 // This is called when a user validates a storefront slug with session_id
 func (s *OnboardingService) UpdateStorefrontSlug(ctx context.Context, sessionID uuid.UUID, storefrontSlug string) error {
@@ -842,6 +894,7 @@ func (s *OnboardingService) CompleteEmailVerificationTask(ctx context.Context, s
 	if _, err := s.onboardingRepo.UpdateSession(ctx, session); err != nil {
 		return fmt.Errorf("failed to update session status: %w", err)
 	}
+	metrics.RecordOnboardingSession(ctx, session.ApplicationType, metrics.SessionCompleted)
 
 	log.Printf("Session %s marked as completed after email verification", sessionID)
 
@@ -928,13 +981,20 @@ type KeycloakSetupResult struct {
 }
 
 // CompleteAccountSetup creates tenant and user account from onboarding session
-func (s *OnboardingService) CompleteAccountSetup(ctx context.Context, sessionID uuid.UUID, password, authMethod, timezone, currency, businessModel string) (*CompleteAccountSetupResponse, error) {
+func (s *OnboardingService) CompleteAccountSetup(ctx context.Context, sessionID uuid.UUID, password, authMethod, timezone, currency, businessModel string) (_ *CompleteAccountSetupResponse, err error) {
+	defer metrics.ObserveOnboardingStep(ctx, metrics.StepAccountSetup, time.Now(), &err)
+
 	// Get onboarding session with all related data including application_configurations
 	// which contains store setup data (currency, timezone) saved during onboarding
 	session, err := s.onboardingRepo.GetSessionByID(ctx, sessionID, []string{"business_information", "contact_information", "business_addresses", "application_configurations"})
 	if err != nil {
 		return nil, fmt.Errorf("session not found: %w", err)
 	}
+	defer func() {
+		if err != nil {
+			metrics.RecordOnboardingSession(ctx, session.ApplicationType, metrics.SessionFailed)
+		}
+	}()
 
 	// Extract currency/timezone from application_configurations if not provided in request
 	// This ensures user selections from onboarding are preserved
@@ -1098,8 +1158,8 @@ func (s *OnboardingService) CompleteAccountSetup(ctx context.Context, sessionID
 		// Verify it's still available (double-check to catch any edge cases)
 		var slugCount int64
 		tx.WithContext(ctx).Model(&models.Tenant{}).Where("slug = ?", slug).Count(&slugCount)
-		if slugCount > 0 {
-			// Reserved slug was somehow taken - this should be rare
+		if slugCount > 0 || !s.sessionCanUseSlug(ctx, slug, sessionID) {
+			// Reserved slug was somehow taken (e.g. the hold expired) - this should be rare
 			// Fall back to generating a unique variant
 			log.Printf("[OnboardingService] Warning: Reserved slug '%s' was taken, generating variant", slug)
 			originalSlug := slug
@@ -1107,7 +1167,7 @@ func (s *OnboardingService) CompleteAccountSetup(ctx context.Context, sessionID
 			for {
 				slug = fmt.Sprintf("%s-%d", originalSlug, counter)
 				tx.WithContext(ctx).Model(&models.Tenant{}).Where("slug = ?", slug).Count(&slugCount)
-				if slugCount == 0 {
+				if slugCount == 0 && s.sessionCanUseSlug(ctx, slug, sessionID) {
 					break
 				}
 				counter++
@@ -1123,7 +1183,7 @@ func (s *OnboardingService) CompleteAccountSetup(ctx context.Context, sessionID
 		originalSlug := slug
 		for {
 			tx.WithContext(ctx).Model(&models.Tenant{}).Where("slug = ?", slug).Count(&slugCount)
-			if slugCount == 0 {
+			if slugCount == 0 && s.sessionCanUseSlug(ctx, slug, sessionID) {
 				break
 			}
 			counter++
@@ -1356,6 +1416,15 @@ func (s *OnboardingService) CompleteAccountSetup(ctx context.Context, sessionID
 		return nil, fmt.Errorf("membership service not configured - cannot complete onboarding")
 	}
 
+	// Put the tenant on its first plan, trialing when the template grants trial days.
+	// Failures leave the tenant on the plan of its pricing tier.
+	if s.subscriptionStarter != nil {
+		trialDays := s.templateTrialDays(ctx, session)
+		if subErr := s.subscriptionStarter.StartTenantSubscription(ctx, tenantID, trialDays); subErr != nil {
+			log.Printf("[OnboardingService] Warning: Failed to start subscription for tenant %s: %v", tenantID, subErr)
+		}
+	}
+
 	// ============================================================================
 	// KEYCLOAK ORGANIZATION MEMBERSHIP
 	// Add owner to the organization for identity isolation
@@ -1526,7 +1595,7 @@ func (s *OnboardingService) CompleteAccountSetup(ctx context.Context, sessionID
 	// Activate the slug reservation (convert from pending to active)
 	// This permanently claims the slug for this tenant
 	if s.membershipSvc != nil {
-		if activateErr := s.membershipSvc.ActivateSlugReservation(ctx, slug, tenantID); activateErr != nil {
+		if activateErr := s.membershipSvc.ActivateSlugReservationForSession(ctx, slug, sessionID, tenantID); activateErr != nil {
 			log.Printf("Warning: Failed to activate slug reservation for '%s': %v", slug, activateErr)
 		} else {
 			log.Printf("[OnboardingService] Activated slug reservation '%s' for tenant %s", slug, tenantID)
@@ -1984,6 +2053,7 @@ func (s *OnboardingService) CompleteAccountSetup(ctx context.Context, sessionID
 		IsCustomDomain: isCustomDomainUsed,
 	})
 
+	metrics.RecordOnboardingSession(ctx, session.ApplicationType, metrics.SessionTenantCreated)
 	return response, nil
 }
 
@@ -2502,3 +2572,18 @@ func (s *OnboardingService) updateUserTenantAttributes(ctx context.Context, user
 
 	return s.keycloakClient.UpdateUserAttributes(ctx, user.ID, updatedAttributes)
 }
+
+// templateTrialDays reads trial_period_days from the template the session is pinned to
+func (s *OnboardingService) templateTrialDays(ctx context.Context, session *models.OnboardingSession) int {
+	template, err := s.templateRepo.GetTemplateForSession(ctx, session.TemplateID, session.TemplateVersion)
+	if err != nil || len(template.TemplateConfig) == 0 {
+		return 0
+	}
+	var cfg struct {
+		TrialPeriodDays int `json:"trial_period_days"`
+	}
+	if err := json.Unmarshal(template.TemplateConfig, &cfg); err != nil {
+		return 0
+	}
+	return cfg.TrialPeriodDays
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/config"
+	"tenant-service/internal/listquery"
+	"tenant-service/internal/models"
+	"tenant-service/internal/redis"
+)
+
+const (
+	defaultSessionPurgeInterval = 6 * time.Hour
+	defaultSessionMaxBulk       = 500
+)
+
+// pendingSessionStatuses are the statuses of sessions that have not finished onboarding yet
+var pendingSessionStatuses = []string{"started", "pending", "in_progress", "draft"}
+
+// primaryEmailCondition matches sessions whose primary contact has the given email
+const primaryEmailCondition = `EXISTS (SELECT 1 FROM contact_information ci
+	WHERE ci.onboarding_session_id = onboarding_sessions.id AND ci.is_primary = true AND LOWER(ci.email) = LOWER(?))`
+
+// duplicateSessionCondition matches sessions whose primary contact email also started another session
+const duplicateSessionCondition = `EXISTS (SELECT 1 FROM contact_information ci
+	JOIN contact_information other ON LOWER(other.email) = LOWER(ci.email) AND other.is_primary = true
+		AND other.onboarding_session_id <> ci.onboarding_session_id
+	WHERE ci.onboarding_session_id = onboarding_sessions.id AND ci.is_primary = true)`
+
+// OnboardingSessionFilter narrows the admin session listing beyond the list query filters
+type OnboardingSessionFilter struct {
+	Email          string // Primary contact email (case-insensitive)
+	MinAgeDays     int    // Only sessions created at least this many days ago
+	DuplicatesOnly bool   // Only sessions whose primary contact email started other sessions too
+}
+
+// AdminOnboardingSession is an onboarding session as listed to support staff
+type AdminOnboardingSession struct {
+	ID                 uuid.UUID  `json:"id"`
+	TenantID           *uuid.UUID `json:"tenant_id,omitempty"`
+	ApplicationType    string     `json:"application_type"`
+	Status             string     `json:"status"`
+	CurrentStep        string     `json:"current_step"`
+	ProgressPercentage int        `json:"progress_percentage"`
+	PrimaryEmail       string     `json:"primary_email,omitempty"`
+	BusinessName       string     `json:"business_name,omitempty"`
+	ReminderCount      int        `json:"reminder_count"`
+	ExpiresAt          time.Time  `json:"expires_at"`
+	DraftExpiresAt     *time.Time `json:"draft_expires_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// OnboardingSessionBulkResult reports a bulk action on onboarding sessions
+type OnboardingSessionBulkResult struct {
+	Requested int         `json:"requested"`
+	Affected  int64       `json:"affected"`
+	Skipped   []uuid.UUID `json:"skipped,omitempty"` // Unknown, completed or tenant-owning sessions
+}
+
+// OnboardingSessionAdminService lets support staff find and clean up duplicate or stale onboarding sessions
+type OnboardingSessionAdminService struct {
+	db          *gorm.DB
+	redisClient *redis.Client
+	cfg         config.SessionPurgeConfig
+}
+
+// NewOnboardingSessionAdminService creates a new onboarding session admin service
+// redisClient is optional; when set, drafts of removed sessions are dropped from Redis too.
+func NewOnboardingSessionAdminService(db *gorm.DB, redisClient *redis.Client, cfg config.SessionPurgeConfig) *OnboardingSessionAdminService {
+	return &OnboardingSessionAdminService{db: db, redisClient: redisClient, cfg: cfg}
+}
+
+// PurgeInterval returns how often stale sessions are purged
+func (s *OnboardingSessionAdminService) PurgeInterval() time.Duration {
+	if s.cfg.IntervalMinutes <= 0 {
+		return defaultSessionPurgeInterval
+	}
+	return time.Duration(s.cfg.IntervalMinutes) * time.Minute
+}
+
+// ListSessions returns onboarding sessions matching the list query and filter
+func (s *OnboardingSessionAdminService) ListSessions(ctx context.Context, q *listquery.Query, filter OnboardingSessionFilter) ([]AdminOnboardingSession, int64, string, error) {
+	query := s.db.WithContext(ctx).Model(&models.OnboardingSession{}).Scopes(q.FilterScope())
+	if email := strings.TrimSpace(filter.Email); email != "" {
+		query = query.Where(primaryEmailCondition, email)
+	}
+	if filter.MinAgeDays > 0 {
+		query = query.Where("created_at <= ?", time.Now().AddDate(0, 0, -filter.MinAgeDays))
+	}
+	if filter.DuplicatesOnly {
+		query = query.Where(duplicateSessionCondition)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to count onboarding sessions: %w", err)
+	}
+
+	var sessions []models.OnboardingSession
+	if err := query.Scopes(q.PageScope()).
+		Preload("ContactInformation", "is_primary = ?", true).
+		Preload("BusinessInformation").
+		Find(&sessions).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to list onboarding sessions: %w", err)
+	}
+
+	sessions, next, err := listquery.NextPage(q, sessions)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	items := make([]AdminOnboardingSession, 0, len(sessions))
+	for i := range sessions {
+		items = append(items, toAdminOnboardingSession(&sessions[i]))
+	}
+	return items, total, next, nil
+}
+
+// AbandonSessions marks sessions as abandoned and drops their drafts
+// Completed sessions and sessions that already created a tenant are skipped.
+func (s *OnboardingSessionAdminService) AbandonSessions(ctx context.Context, ids []uuid.UUID) (*OnboardingSessionBulkResult, error) {
+	return s.bulk(ctx, ids, func(tx *gorm.DB, eligible []uuid.UUID) (int64, error) {
+		result := tx.Model(&models.OnboardingSession{}).
+			Where("id IN ?", eligible).
+			Updates(map[string]interface{}{
+				"status":            "abandoned",
+				"draft_saved_at":    nil,
+				"draft_expires_at":  nil,
+				"draft_form_data":   nil,
+				"browser_closed_at": nil,
+			})
+		return result.RowsAffected, result.Error
+	})
+}
+
+// DeleteSessions deletes sessions together with their onboarding data
+// Completed sessions and sessions that already created a tenant are skipped.
+func (s *OnboardingSessionAdminService) DeleteSessions(ctx context.Context, ids []uuid.UUID) (*OnboardingSessionBulkResult, error) {
+	return s.bulk(ctx, ids, func(tx *gorm.DB, eligible []uuid.UUID) (int64, error) {
+		// Business details, contacts, tasks and verifications are removed by ON DELETE CASCADE
+		result := tx.Where("id IN ?", eligible).Delete(&models.OnboardingSession{})
+		return result.RowsAffected, result.Error
+	})
+}
+
+// PurgeStaleSessions deletes sessions stuck before completion without updates for longer than StaleDays
+func (s *OnboardingSessionAdminService) PurgeStaleSessions(ctx context.Context) (int64, error) {
+	staleDays := s.cfg.StaleDays
+	if staleDays <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -staleDays)
+
+	var ids []uuid.UUID
+	if err := s.db.WithContext(ctx).Model(&models.OnboardingSession{}).
+		Where("status IN ? AND tenant_id IS NULL AND updated_at < ?", pendingSessionStatuses, cutoff).
+		Limit(s.maxBulk()).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, fmt.Errorf("failed to find stale onboarding sessions: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result := s.db.WithContext(ctx).
+		Where("id IN ? AND status IN ? AND tenant_id IS NULL", ids, pendingSessionStatuses).
+		Delete(&models.OnboardingSession{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge stale onboarding sessions: %w", result.Error)
+	}
+
+	s.dropDrafts(ctx, ids)
+	log.Printf("[OnboardingSessionAdmin] Purged %d sessions pending since before %s", result.RowsAffected, cutoff.Format(time.RFC3339))
+	return result.RowsAffected, nil
+}
+
+// bulk applies a bulk action to the requested sessions that may still be changed
+func (s *OnboardingSessionAdminService) bulk(ctx context.Context, ids []uuid.UUID, apply func(tx *gorm.DB, eligible []uuid.UUID) (int64, error)) (*OnboardingSessionBulkResult, error) {
+	ids = uniqueUUIDs(ids)
+	if len(ids) == 0 {
+		return nil, NewValidationError("session_ids", "at least one session ID is required", nil)
+	}
+	if limit := s.maxBulk(); len(ids) > limit {
+		return nil, NewValidationError("session_ids", fmt.Sprintf("at most %d sessions can be changed at once", limit), nil)
+	}
+
+	result := &OnboardingSessionBulkResult{Requested: len(ids)}
+	var eligible []uuid.UUID
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.OnboardingSession{}).
+			Where("id IN ? AND status <> ? AND tenant_id IS NULL", ids, "completed").
+			Pluck("id", &eligible).Error; err != nil {
+			return fmt.Errorf("failed to load onboarding sessions: %w", err)
+		}
+		if len(eligible) == 0 {
+			return nil
+		}
+
+		affected, err := apply(tx, eligible)
+		if err != nil {
+			return fmt.Errorf("failed to update onboarding sessions: %w", err)
+		}
+		result.Affected = affected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[uuid.UUID]bool, len(eligible))
+	for _, id := range eligible {
+		changed[id] = true
+	}
+	for _, id := range ids {
+		if !changed[id] {
+			result.Skipped = append(result.Skipped, id)
+		}
+	}
+
+	s.dropDrafts(ctx, eligible)
+	return result, nil
+}
+
+// dropDrafts removes the Redis drafts of sessions; failures only leave drafts to expire on their own
+func (s *OnboardingSessionAdminService) dropDrafts(ctx context.Context, ids []uuid.UUID) {
+	if s.redisClient == nil {
+		return
+	}
+	for _, id := range ids {
+		if err := s.redisClient.DeleteDraft(ctx, id.String()); err != nil {
+			log.Printf("[OnboardingSessionAdmin] Warning: failed to delete draft of session %s from Redis: %v", id, err)
+		}
+	}
+}
+
+func (s *OnboardingSessionAdminService) maxBulk() int {
+	if s.cfg.MaxBulkSessions <= 0 {
+		return defaultSessionMaxBulk
+	}
+	return s.cfg.MaxBulkSessions
+}
+
+func toAdminOnboardingSession(session *models.OnboardingSession) AdminOnboardingSession {
+	item := AdminOnboardingSession{
+		ID:                 session.ID,
+		TenantID:           session.TenantID,
+		ApplicationType:    session.ApplicationType,
+		Status:             session.Status,
+		CurrentStep:        session.CurrentStep,
+		ProgressPercentage: session.ProgressPercentage,
+		ReminderCount:      session.ReminderCount,
+		ExpiresAt:          session.ExpiresAt,
+		DraftExpiresAt:     session.DraftExpiresAt,
+		CreatedAt:          session.CreatedAt,
+		UpdatedAt:          session.UpdatedAt,
+	}
+	if len(session.ContactInformation) > 0 {
+		item.PrimaryEmail = session.ContactInformation[0].Email
+	}
+	if session.BusinessInformation != nil {
+		item.BusinessName = session.BusinessInformation.BusinessName
+	}
+	return item
+}
+
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
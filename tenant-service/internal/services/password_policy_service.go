@@ -0,0 +1,419 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+const (
+	// DefaultPasswordSpecialChars is used when a policy requires special characters without listing them
+	DefaultPasswordSpecialChars = "!@#$%^&*()_+-=[]{}|;:,.<>?"
+
+	passwordPolicyMinLength       = 8
+	passwordPolicyMaxLength       = 256
+	passwordPolicyMaxRotationDays = 730
+)
+
+// ErrPasswordPolicyForbidden is returned when the user may not manage the tenant's password policy
+var ErrPasswordPolicyForbidden = errors.New("only tenant owners and admins can manage the password policy")
+
+// BreachedPasswordChecker reports whether a password appears in a known breach corpus
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// HIBPChecker checks passwords against the Have I Been Pwned range API using k-anonymity:
+// only the first five hex characters of the password's SHA-1 leave the service
+type HIBPChecker struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewHIBPChecker creates a breach checker for the given range API base URL
+func NewHIBPChecker(baseURL string, timeout time.Duration) *HIBPChecker {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return &HIBPChecker{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// IsBreached looks up the password's hash suffix in the range returned for its prefix
+func (c *HIBPChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+prefix, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to build breach lookup request: %w", err)
+	}
+	// Padding hides the real number of matches for the prefix from observers
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("breach lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("breach lookup returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || !strings.EqualFold(candidate, suffix) {
+			continue
+		}
+		// Padding entries carry a count of zero
+		return strings.TrimSpace(count) != "0", nil
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read breach lookup response: %w", err)
+	}
+	return false, nil
+}
+
+// PasswordPolicy is the password section of a tenant's auth policy
+type PasswordPolicy struct {
+	MinLength           int        `json:"min_length"`
+	MaxLength           int        `json:"max_length"`
+	RequireUppercase    bool       `json:"require_uppercase"`
+	RequireLowercase    bool       `json:"require_lowercase"`
+	RequireNumbers      bool       `json:"require_numbers"`
+	RequireSpecialChars bool       `json:"require_special_chars"`
+	SpecialChars        string     `json:"special_chars"`
+	RotationDays        int        `json:"rotation_days"` // 0 = passwords never expire
+	CheckBreached       bool       `json:"check_breached"`
+	IsDefault           bool       `json:"is_default"` // True when the tenant has not customised its policy
+	UpdatedAt           *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdatePasswordPolicyRequest changes the password policy; omitted fields keep their current value
+type UpdatePasswordPolicyRequest struct {
+	MinLength           *int    `json:"min_length"`
+	MaxLength           *int    `json:"max_length"`
+	RequireUppercase    *bool   `json:"require_uppercase"`
+	RequireLowercase    *bool   `json:"require_lowercase"`
+	RequireNumbers      *bool   `json:"require_numbers"`
+	RequireSpecialChars *bool   `json:"require_special_chars"`
+	SpecialChars        *string `json:"special_chars"`
+	RotationDays        *int    `json:"rotation_days"`
+	CheckBreached       *bool   `json:"check_breached"`
+}
+
+// PasswordPolicyService manages per-tenant password policies and enforces them when passwords are set
+type PasswordPolicyService struct {
+	credentialRepo *repository.CredentialRepository
+	membershipRepo *repository.MembershipRepository
+	breachChecker  BreachedPasswordChecker
+}
+
+// NewPasswordPolicyService creates a new password policy service; breachChecker may be nil to disable breach checks
+func NewPasswordPolicyService(db *gorm.DB, breachChecker BreachedPasswordChecker) *PasswordPolicyService {
+	return &PasswordPolicyService{
+		credentialRepo: repository.NewCredentialRepository(db),
+		membershipRepo: repository.NewMembershipRepository(db),
+		breachChecker:  breachChecker,
+	}
+}
+
+// AuthorizeManage checks the user is an owner or admin of the tenant
+func (s *PasswordPolicyService) AuthorizeManage(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrPasswordPolicyForbidden
+	}
+	return nil
+}
+
+// GetPolicy returns the tenant's password policy, or the defaults when none is stored
+func (s *PasswordPolicyService) GetPolicy(ctx context.Context, tenantID uuid.UUID) (*PasswordPolicy, error) {
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return defaultPasswordPolicy(), nil
+	}
+	return toPasswordPolicy(policy), nil
+}
+
+// UpdatePolicy applies changes to the tenant's password policy, creating the auth policy if needed
+func (s *PasswordPolicyService) UpdatePolicy(ctx context.Context, tenantID, userID uuid.UUID, req UpdatePasswordPolicyRequest) (*PasswordPolicy, error) {
+	if err := validatePasswordPolicyRequest(req); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.loadOrCreate(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MinLength != nil {
+		policy.PasswordMinLength = *req.MinLength
+	}
+	if req.MaxLength != nil {
+		policy.PasswordMaxLength = *req.MaxLength
+	}
+	if policy.PasswordMaxLength < policy.PasswordMinLength {
+		return nil, NewValidationError("max_length", "max_length must not be less than min_length", nil)
+	}
+	if req.RequireUppercase != nil {
+		policy.PasswordRequireUppercase = *req.RequireUppercase
+	}
+	if req.RequireLowercase != nil {
+		policy.PasswordRequireLowercase = *req.RequireLowercase
+	}
+	if req.RequireNumbers != nil {
+		policy.PasswordRequireNumbers = *req.RequireNumbers
+	}
+	if req.RequireSpecialChars != nil {
+		policy.PasswordRequireSpecialChars = *req.RequireSpecialChars
+	}
+	if req.SpecialChars != nil {
+		policy.PasswordSpecialChars = *req.SpecialChars
+	}
+	if req.RotationDays != nil {
+		if *req.RotationDays == 0 {
+			policy.PasswordExpiryDays = nil
+		} else {
+			days := *req.RotationDays
+			policy.PasswordExpiryDays = &days
+		}
+	}
+	if req.CheckBreached != nil {
+		policy.PasswordCheckBreached = *req.CheckBreached
+	}
+	policy.UpdatedBy = &userID
+
+	if err := s.credentialRepo.UpdateAuthPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	log.Printf("[PasswordPolicyService] Password policy for tenant %s updated by %s", tenantID, userID)
+	return toPasswordPolicy(policy), nil
+}
+
+// ResetPolicy restores the default password policy, leaving the rest of the auth policy untouched
+func (s *PasswordPolicyService) ResetPolicy(ctx context.Context, tenantID, userID uuid.UUID) (*PasswordPolicy, error) {
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return defaultPasswordPolicy(), nil
+	}
+
+	defaults := defaultPasswordPolicy()
+	policy.PasswordMinLength = defaults.MinLength
+	policy.PasswordMaxLength = defaults.MaxLength
+	policy.PasswordRequireUppercase = defaults.RequireUppercase
+	policy.PasswordRequireLowercase = defaults.RequireLowercase
+	policy.PasswordRequireNumbers = defaults.RequireNumbers
+	policy.PasswordRequireSpecialChars = defaults.RequireSpecialChars
+	policy.PasswordSpecialChars = defaults.SpecialChars
+	policy.PasswordExpiryDays = nil
+	policy.PasswordCheckBreached = defaults.CheckBreached
+	policy.UpdatedBy = &userID
+
+	if err := s.credentialRepo.UpdateAuthPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	log.Printf("[PasswordPolicyService] Password policy for tenant %s reset by %s", tenantID, userID)
+	return toPasswordPolicy(policy), nil
+}
+
+// ValidatePassword checks a new password against the tenant's stored policy.
+// Tenants without a stored policy only get the request-level minimum length check.
+func (s *PasswordPolicyService) ValidatePassword(ctx context.Context, tenantID uuid.UUID, password string) error {
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return nil
+	}
+	return s.CheckPassword(ctx, policy, password)
+}
+
+// CheckPassword validates a password against a policy's complexity rules and, when enabled, the breach corpus.
+// Breach lookups that fail are logged and allowed so an outage of the lookup API does not block password changes.
+func (s *PasswordPolicyService) CheckPassword(ctx context.Context, policy *models.TenantAuthPolicy, password string) error {
+	if err := checkPasswordComplexity(password, policy); err != nil {
+		return err
+	}
+
+	if policy.PasswordCheckBreached && s.breachChecker != nil {
+		breached, err := s.breachChecker.IsBreached(ctx, password)
+		if err != nil {
+			log.Printf("[PasswordPolicyService] Warning: breached-password check skipped: %v", err)
+			return nil
+		}
+		if breached {
+			return NewValidationError("password", "this password has appeared in a data breach; choose a different password", nil)
+		}
+	}
+	return nil
+}
+
+// RecordPasswordChange restarts the user's rotation clock after a password is set
+func (s *PasswordPolicyService) RecordPasswordChange(ctx context.Context, userID, tenantID uuid.UUID) error {
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var expiresAt *time.Time
+	if policy != nil && policy.PasswordExpiryDays != nil && *policy.PasswordExpiryDays > 0 {
+		expiry := now.AddDate(0, 0, *policy.PasswordExpiryDays)
+		expiresAt = &expiry
+	}
+	return s.credentialRepo.RecordPasswordChange(ctx, userID, tenantID, now, expiresAt)
+}
+
+// PasswordChangeRequired reports whether the credential's password is due for rotation under the policy.
+// The expiry is derived from the last change so that shortening the rotation interval applies immediately.
+func PasswordChangeRequired(policy *models.TenantAuthPolicy, credential *models.TenantCredential, now time.Time) bool {
+	if credential == nil {
+		return false
+	}
+	if credential.PasswordRotationRequired {
+		return true
+	}
+	if policy == nil || policy.PasswordExpiryDays == nil || *policy.PasswordExpiryDays <= 0 {
+		return false
+	}
+	changedAt := credential.PasswordSetAt
+	if credential.LastPasswordChangeAt != nil {
+		changedAt = *credential.LastPasswordChangeAt
+	}
+	return !now.Before(changedAt.AddDate(0, 0, *policy.PasswordExpiryDays))
+}
+
+func (s *PasswordPolicyService) loadOrCreate(ctx context.Context, tenantID uuid.UUID) (*models.TenantAuthPolicy, error) {
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		return policy, nil
+	}
+	return s.credentialRepo.CreateAuthPolicy(ctx, tenantID)
+}
+
+// validatePasswordPolicyRequest checks the fields that can be validated without the stored policy
+func validatePasswordPolicyRequest(req UpdatePasswordPolicyRequest) error {
+	if req.MinLength != nil && (*req.MinLength < passwordPolicyMinLength || *req.MinLength > passwordPolicyMaxLength) {
+		return NewValidationError("min_length", fmt.Sprintf("min_length must be between %d and %d", passwordPolicyMinLength, passwordPolicyMaxLength), nil)
+	}
+	if req.MaxLength != nil && (*req.MaxLength < passwordPolicyMinLength || *req.MaxLength > passwordPolicyMaxLength) {
+		return NewValidationError("max_length", fmt.Sprintf("max_length must be between %d and %d", passwordPolicyMinLength, passwordPolicyMaxLength), nil)
+	}
+	if req.MinLength != nil && req.MaxLength != nil && *req.MaxLength < *req.MinLength {
+		return NewValidationError("max_length", "max_length must not be less than min_length", nil)
+	}
+	if req.RotationDays != nil && (*req.RotationDays < 0 || *req.RotationDays > passwordPolicyMaxRotationDays) {
+		return NewValidationError("rotation_days", fmt.Sprintf("rotation_days must be between 0 and %d", passwordPolicyMaxRotationDays), nil)
+	}
+	if req.SpecialChars != nil {
+		if *req.SpecialChars == "" || len(*req.SpecialChars) > 100 {
+			return NewValidationError("special_chars", "special_chars must contain between 1 and 100 characters", nil)
+		}
+		for _, c := range *req.SpecialChars {
+			if c > unicode.MaxASCII || !unicode.IsPrint(c) || unicode.IsLetter(c) || unicode.IsDigit(c) || unicode.IsSpace(c) {
+				return NewValidationError("special_chars", "special_chars may only contain printable ASCII symbols", nil)
+			}
+		}
+	}
+	return nil
+}
+
+// checkPasswordComplexity validates a password against the policy's length and character class rules
+func checkPasswordComplexity(password string, policy *models.TenantAuthPolicy) error {
+	if len(password) < policy.PasswordMinLength {
+		return NewValidationError("password", fmt.Sprintf("password must be at least %d characters", policy.PasswordMinLength), nil)
+	}
+	if policy.PasswordMaxLength > 0 && len(password) > policy.PasswordMaxLength {
+		return NewValidationError("password", fmt.Sprintf("password must be at most %d characters", policy.PasswordMaxLength), nil)
+	}
+
+	specialChars := policy.PasswordSpecialChars
+	if specialChars == "" {
+		specialChars = DefaultPasswordSpecialChars
+	}
+	var hasUpper, hasLower, hasNumber, hasSpecial bool
+	for _, c := range password {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			hasUpper = true
+		case c >= 'a' && c <= 'z':
+			hasLower = true
+		case c >= '0' && c <= '9':
+			hasNumber = true
+		case strings.ContainsRune(specialChars, c):
+			hasSpecial = true
+		}
+	}
+
+	if policy.PasswordRequireUppercase && !hasUpper {
+		return NewValidationError("password", "password must contain at least one uppercase letter", nil)
+	}
+	if policy.PasswordRequireLowercase && !hasLower {
+		return NewValidationError("password", "password must contain at least one lowercase letter", nil)
+	}
+	if policy.PasswordRequireNumbers && !hasNumber {
+		return NewValidationError("password", "password must contain at least one number", nil)
+	}
+	if policy.PasswordRequireSpecialChars && !hasSpecial {
+		return NewValidationError("password", "password must contain at least one special character", nil)
+	}
+	return nil
+}
+
+func defaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:           8,
+		MaxLength:           128,
+		RequireUppercase:    true,
+		RequireLowercase:    true,
+		RequireNumbers:      true,
+		RequireSpecialChars: false,
+		SpecialChars:        DefaultPasswordSpecialChars,
+		IsDefault:           true,
+	}
+}
+
+func toPasswordPolicy(policy *models.TenantAuthPolicy) *PasswordPolicy {
+	result := &PasswordPolicy{
+		MinLength:           policy.PasswordMinLength,
+		MaxLength:           policy.PasswordMaxLength,
+		RequireUppercase:    policy.PasswordRequireUppercase,
+		RequireLowercase:    policy.PasswordRequireLowercase,
+		RequireNumbers:      policy.PasswordRequireNumbers,
+		RequireSpecialChars: policy.PasswordRequireSpecialChars,
+		SpecialChars:        policy.PasswordSpecialChars,
+		CheckBreached:       policy.PasswordCheckBreached,
+		UpdatedAt:           &policy.UpdatedAt,
+	}
+	if policy.PasswordExpiryDays != nil {
+		result.RotationDays = *policy.PasswordExpiryDays
+	}
+	return result
+}
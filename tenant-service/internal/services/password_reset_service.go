@@ -14,6 +14,7 @@ import (
 	"github.com/Tesseract-Nexus/go-shared/auth"
 	"tenant-service/internal/clients"
 	"tenant-service/internal/models"
+	natsClient "tenant-service/internal/nats"
 	"tenant-service/internal/repository"
 	"gorm.io/gorm"
 )
@@ -22,8 +23,10 @@ import (
 type PasswordResetService struct {
 	db                 *gorm.DB
 	membershipRepo     *repository.MembershipRepository
-	keycloakClient     *auth.KeycloakAdminClient
+	keycloakClient     *timedKeycloakClient
 	notificationClient *clients.NotificationClient
+	passwordPolicy     *PasswordPolicyService
+	securityEvents     SecurityEventPublisher
 	baseDomain         string // e.g., "tesserix.app" - used to construct tenant-specific URLs
 }
 
@@ -36,12 +39,23 @@ func NewPasswordResetService(db *gorm.DB, keycloakClient *auth.KeycloakAdminClie
 	return &PasswordResetService{
 		db:                 db,
 		membershipRepo:     repository.NewMembershipRepository(db),
-		keycloakClient:     keycloakClient,
+		keycloakClient:     newTimedKeycloakClient(keycloakClient),
 		notificationClient: notificationClient,
+		passwordPolicy:     NewPasswordPolicyService(db, nil),
 		baseDomain:         baseDomain,
 	}
 }
 
+// SetPasswordPolicyService replaces the default password policy service (e.g. to enable breach checks)
+func (s *PasswordResetService) SetPasswordPolicyService(passwordPolicy *PasswordPolicyService) {
+	s.passwordPolicy = passwordPolicy
+}
+
+// SetSecurityEventPublisher enables security.password_reset events for completed resets
+func (s *PasswordResetService) SetSecurityEventPublisher(publisher SecurityEventPublisher) {
+	s.securityEvents = publisher
+}
+
 // getStorefrontURL constructs the tenant-specific storefront URL
 // URL pattern: https://{slug}-store.{baseDomain}
 func (s *PasswordResetService) getStorefrontURL(tenantSlug string) string {
@@ -251,6 +265,17 @@ func (s *PasswordResetService) ResetPassword(ctx context.Context, input *ResetPa
 		}, nil
 	}
 
+	// Validate the new password against the tenant's policy; the token stays usable for another attempt
+	if err := s.passwordPolicy.ValidatePassword(ctx, tokenRecord.TenantID, input.NewPassword); err != nil {
+		if validationErr, ok := IsValidationError(err); ok {
+			return &ResetPasswordOutput{
+				Success: false,
+				Message: validationErr.Message,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to validate password: %w", err)
+	}
+
 	// Update password in Keycloak
 	if s.keycloakClient == nil {
 		return nil, fmt.Errorf("authentication service not properly configured")
@@ -277,6 +302,11 @@ func (s *PasswordResetService) ResetPassword(ctx context.Context, input *ResetPa
 		log.Printf("[PasswordResetService] Warning: Failed to mark token as used: %v", err)
 	}
 
+	// Restart the rotation clock
+	if err := s.passwordPolicy.RecordPasswordChange(ctx, user.ID, tokenRecord.TenantID); err != nil {
+		log.Printf("[PasswordResetService] Warning: Failed to record password change: %v", err)
+	}
+
 	// Log password reset event
 	auditLog := &models.TenantAuthAuditLog{
 		TenantID:    tokenRecord.TenantID,
@@ -291,6 +321,18 @@ func (s *PasswordResetService) ResetPassword(ctx context.Context, input *ResetPa
 		log.Printf("[PasswordResetService] Warning: Failed to log password reset event: %v", auditErr)
 	}
 
+	publishSecurityEvent(s.securityEvents, &natsClient.SecurityEvent{
+		EventType:    natsClient.EventSecurityPasswordReset,
+		TenantID:     tokenRecord.TenantID.String(),
+		ActorID:      user.ID.String(),
+		ActorEmail:   user.Email,
+		TargetUserID: user.ID.String(),
+		TargetEmail:  user.Email,
+		IPAddress:    input.IPAddress,
+		UserAgent:    input.UserAgent,
+		Reason:       "password_reset_token",
+	})
+
 	log.Printf("[PasswordResetService] Password reset successful for user %s", user.Email)
 
 	return &ResetPasswordOutput{
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	samlBindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	samlBindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+)
+
+// SAMLMetadata is what tenant SSO needs from an IdP's SAML metadata document
+type SAMLMetadata struct {
+	EntityID             string    `json:"entity_id"`
+	SSOURL               string    `json:"sso_url"`
+	SSOBinding           string    `json:"sso_binding"`
+	SLOURL               string    `json:"slo_url,omitempty"`
+	SigningCertificate   string    `json:"signing_certificate"` // Base64 DER
+	CertificateSubject   string    `json:"certificate_subject"`
+	CertificateExpiresAt time.Time `json:"certificate_expires_at"`
+	WantsSignedRequests  bool      `json:"wants_signed_requests"`
+}
+
+type samlEntityDescriptor struct {
+	XMLName          xml.Name              `xml:"EntityDescriptor"`
+	EntityID         string                `xml:"entityID,attr"`
+	IDPSSODescriptor *samlIDPSSODescriptor `xml:"IDPSSODescriptor"`
+}
+
+type samlIDPSSODescriptor struct {
+	WantAuthnRequestsSigned bool                `xml:"WantAuthnRequestsSigned,attr"`
+	KeyDescriptors          []samlKeyDescriptor `xml:"KeyDescriptor"`
+	SingleSignOnServices    []samlEndpoint      `xml:"SingleSignOnService"`
+	SingleLogoutServices    []samlEndpoint      `xml:"SingleLogoutService"`
+}
+
+type samlKeyDescriptor struct {
+	Use          string   `xml:"use,attr"`
+	Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+type samlEndpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
+// ParseSAMLMetadata validates an IdP metadata document and extracts the entity ID, endpoints and
+// signing certificate. The certificate must be valid for at least minValidity after now.
+func ParseSAMLMetadata(data []byte, now time.Time, minValidity time.Duration) (*SAMLMetadata, error) {
+	var descriptor samlEntityDescriptor
+	if err := xml.Unmarshal(data, &descriptor); err != nil {
+		return nil, NewValidationError("metadata", "metadata is not a SAML EntityDescriptor document", nil)
+	}
+	if strings.TrimSpace(descriptor.EntityID) == "" {
+		return nil, NewValidationError("metadata", "metadata has no entityID", nil)
+	}
+	idp := descriptor.IDPSSODescriptor
+	if idp == nil {
+		return nil, NewValidationError("metadata", "metadata does not describe an identity provider (no IDPSSODescriptor)", nil)
+	}
+
+	metadata := &SAMLMetadata{
+		EntityID:            strings.TrimSpace(descriptor.EntityID),
+		WantsSignedRequests: idp.WantAuthnRequestsSigned,
+	}
+
+	// Keycloak posts the AuthnRequest when the IdP supports it, otherwise redirects
+	for _, binding := range []string{samlBindingHTTPPost, samlBindingHTTPRedirect} {
+		if endpoint := findSAMLEndpoint(idp.SingleSignOnServices, binding); endpoint != "" {
+			metadata.SSOURL = endpoint
+			metadata.SSOBinding = binding
+			break
+		}
+	}
+	if metadata.SSOURL == "" {
+		return nil, NewValidationError("metadata", "metadata has no HTTP-POST or HTTP-Redirect SingleSignOnService", nil)
+	}
+	if !strings.HasPrefix(metadata.SSOURL, "https://") {
+		return nil, NewValidationError("metadata", "SingleSignOnService location must use https", nil)
+	}
+	metadata.SLOURL = findSAMLEndpoint(idp.SingleLogoutServices, samlBindingHTTPPost)
+	if metadata.SLOURL == "" {
+		metadata.SLOURL = findSAMLEndpoint(idp.SingleLogoutServices, samlBindingHTTPRedirect)
+	}
+
+	encoded := samlSigningCertificate(idp.KeyDescriptors)
+	if encoded == "" {
+		return nil, NewValidationError("metadata", "metadata has no signing certificate", nil)
+	}
+	cert, err := parseSAMLCertificate(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if now.Before(cert.NotBefore) {
+		return nil, NewValidationError("metadata", "signing certificate is not valid yet", nil)
+	}
+	if cert.NotAfter.Before(now.Add(minValidity)) {
+		return nil, NewValidationError("metadata", fmt.Sprintf("signing certificate expires %s", cert.NotAfter.Format(time.RFC3339)), nil)
+	}
+
+	metadata.SigningCertificate = encoded
+	metadata.CertificateSubject = cert.Subject.String()
+	metadata.CertificateExpiresAt = cert.NotAfter
+	return metadata, nil
+}
+
+func findSAMLEndpoint(endpoints []samlEndpoint, binding string) string {
+	for _, endpoint := range endpoints {
+		if endpoint.Binding == binding && strings.TrimSpace(endpoint.Location) != "" {
+			return strings.TrimSpace(endpoint.Location)
+		}
+	}
+	return ""
+}
+
+// samlSigningCertificate returns the first signing certificate; key descriptors without a
+// use attribute apply to both signing and encryption
+func samlSigningCertificate(descriptors []samlKeyDescriptor) string {
+	for _, use := range []string{"signing", ""} {
+		for _, descriptor := range descriptors {
+			if descriptor.Use != use {
+				continue
+			}
+			for _, cert := range descriptor.Certificates {
+				if compact := compactBase64(cert); compact != "" {
+					return compact
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func parseSAMLCertificate(encoded string) (*x509.Certificate, error) {
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, NewValidationError("metadata", "signing certificate is not valid base64", nil)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, NewValidationError("metadata", "signing certificate is not a valid X.509 certificate", nil)
+	}
+	return cert, nil
+}
+
+// compactBase64 strips the whitespace metadata documents wrap certificates with
+func compactBase64(value string) string {
+	return strings.Join(strings.Fields(value), "")
+}
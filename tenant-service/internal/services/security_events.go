@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	natsClient "tenant-service/internal/nats"
+)
+
+// securityEventPublishTimeout bounds a background security event publish
+const securityEventPublishTimeout = 5 * time.Second
+
+// ErrSecurityEventsUnavailable is returned when no security event publisher is configured
+var ErrSecurityEventsUnavailable = errors.New("security event publishing is not configured")
+
+// SecurityEventPublisher publishes security.* events (satisfied by *nats.Client)
+type SecurityEventPublisher interface {
+	PublishSecurityEvent(ctx context.Context, event *natsClient.SecurityEvent) error
+}
+
+// publishSecurityEvent publishes a security event in the background so logins and password
+// changes never wait on NATS; failures are logged and do not affect the caller
+func publishSecurityEvent(publisher SecurityEventPublisher, event *natsClient.SecurityEvent) {
+	if publisher == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), securityEventPublishTimeout)
+		defer cancel()
+		if err := publisher.PublishSecurityEvent(ctx, event); err != nil {
+			log.Printf("[SecurityEvents] Warning: Failed to publish %s for tenant %s: %v", event.EventType, event.TenantID, err)
+		}
+	}()
+}
+
+// InjectSecurityEventRequest describes a synthetic security event for testing the alert pipeline
+type InjectSecurityEventRequest struct {
+	EventType   string                 `json:"event_type" binding:"required"` // e.g. security.login_failed
+	TenantID    string                 `json:"tenant_id" binding:"required"`
+	Severity    string                 `json:"severity"` // Defaults to the event type's severity
+	Outcome     string                 `json:"outcome"`  // success or failure, defaults to success
+	ActorID     string                 `json:"actor_id"`
+	ActorEmail  string                 `json:"actor_email"`
+	TargetEmail string                 `json:"target_email"`
+	IPAddress   string                 `json:"ip_address"`
+	Reason      string                 `json:"reason"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	InjectedBy  string                 `json:"injected_by"` // Defaults to the calling service
+}
+
+// SecurityEventService injects synthetic security events so the audit alert pipeline can be
+// exercised end to end (e.g. during penetration tests) without triggering real lockouts
+type SecurityEventService struct {
+	publisher SecurityEventPublisher
+}
+
+// NewSecurityEventService creates a security event service; publisher may be nil
+func NewSecurityEventService(publisher SecurityEventPublisher) *SecurityEventService {
+	return &SecurityEventService{publisher: publisher}
+}
+
+// InjectSynthetic publishes a security event marked as synthetic and returns it as published
+func (s *SecurityEventService) InjectSynthetic(ctx context.Context, req InjectSecurityEventRequest) (*natsClient.SecurityEvent, error) {
+	if !natsClient.IsSecurityEventType(req.EventType) {
+		return nil, NewValidationError("event_type", "Unknown security event type", []string{
+			natsClient.EventSecurityLoginFailed,
+			natsClient.EventSecurityAccountLocked,
+			natsClient.EventSecurityPasswordReset,
+			natsClient.EventSecurityRoleEscalation,
+			natsClient.EventSecurityImpersonation,
+		})
+	}
+	if _, err := uuid.Parse(req.TenantID); err != nil {
+		return nil, NewValidationError("tenant_id", "Tenant ID must be a UUID", nil)
+	}
+	if req.Severity != "" && !natsClient.IsSecuritySeverity(req.Severity) {
+		return nil, NewValidationError("severity", "Severity must be low, medium, high or critical", nil)
+	}
+	if req.Outcome != "" && req.Outcome != natsClient.SecurityOutcomeSuccess && req.Outcome != natsClient.SecurityOutcomeFailure {
+		return nil, NewValidationError("outcome", "Outcome must be success or failure", nil)
+	}
+	if s.publisher == nil {
+		return nil, ErrSecurityEventsUnavailable
+	}
+
+	metadata := make(map[string]interface{}, len(req.Metadata)+1)
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+	metadata["injected_by"] = req.InjectedBy
+
+	event := &natsClient.SecurityEvent{
+		EventType:   req.EventType,
+		TenantID:    req.TenantID,
+		Severity:    req.Severity,
+		Outcome:     req.Outcome,
+		ActorID:     req.ActorID,
+		ActorEmail:  req.ActorEmail,
+		TargetEmail: req.TargetEmail,
+		IPAddress:   req.IPAddress,
+		Reason:      req.Reason,
+		Synthetic:   true,
+		Metadata:    metadata,
+	}
+	if err := s.publisher.PublishSecurityEvent(ctx, event); err != nil {
+		return nil, err
+	}
+	log.Printf("[SecurityEvents] Injected synthetic %s for tenant %s (by %s)", event.EventType, event.TenantID, req.InjectedBy)
+	return event, nil
+}
@@ -0,0 +1,431 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+	"tenant-service/internal/seeds"
+)
+
+// seedLockNamespace keeps the seed profile advisory lock apart from the per-slug locks (4201),
+// so replicas starting together apply a profile one at a time
+const seedLockNamespace = 4202
+
+// Seed plan actions
+const (
+	SeedActionCreate    = "create"    // The entry does not exist yet
+	SeedActionUpdate    = "update"    // The entry exists and differs from the profile
+	SeedActionRecord    = "record"    // The entry already matches the profile; only its checksum is recorded
+	SeedActionUnchanged = "unchanged" // The checksum matches the one last applied
+	SeedActionSkip      = "skip"      // The entry cannot be applied now (see Reason)
+	SeedActionOrphaned  = "orphaned"  // Applied before but no longer in the profile; left as is
+)
+
+// SeedChange is what applying a profile does to one template or reserved slug
+type SeedChange struct {
+	Kind   string   `json:"kind"` // template or reserved_slug
+	Key    string   `json:"key"`  // Template key or reserved slug
+	Action string   `json:"action"`
+	Fields []string `json:"fields,omitempty"` // Fields an update changes
+	Reason string   `json:"reason,omitempty"`
+}
+
+// SeedPlan is the outcome of applying a profile, or of a dry run of it
+type SeedPlan struct {
+	Profile   string       `json:"profile"`
+	Checksum  string       `json:"checksum"`
+	DryRun    bool         `json:"dry_run"`
+	Changes   []SeedChange `json:"changes"` // Every entry except unchanged ones
+	Unchanged int          `json:"unchanged"`
+}
+
+func (p *SeedPlan) add(change SeedChange) {
+	if change.Action == SeedActionUnchanged {
+		p.Unchanged++
+		return
+	}
+	p.Changes = append(p.Changes, change)
+}
+
+// SeedProfileService applies the seed profiles of default onboarding templates and reserved slugs
+// Entries are matched to rows by template key or slug and skipped while their checksum is the
+// one last applied. Template content changes are published as a new template version, so
+// sessions already in flight keep the version they started with.
+type SeedProfileService struct {
+	db      *gorm.DB
+	loader  *seeds.Loader
+	profile string
+}
+
+// NewSeedProfileService creates a new seed profile service
+func NewSeedProfileService(db *gorm.DB, cfg config.SeedConfig) *SeedProfileService {
+	return &SeedProfileService{
+		db:      db,
+		loader:  seeds.NewLoader(cfg.Dir),
+		profile: cfg.Profile,
+	}
+}
+
+// Plan reports what applying the profile would change without changing anything
+// An empty name plans the profile configured for this environment.
+func (s *SeedProfileService) Plan(ctx context.Context, name string) (*SeedPlan, error) {
+	return s.run(ctx, name, true)
+}
+
+// Apply applies the profile; an empty name applies the profile configured for this environment
+func (s *SeedProfileService) Apply(ctx context.Context, name string) (*SeedPlan, error) {
+	return s.run(ctx, name, false)
+}
+
+func (s *SeedProfileService) run(ctx context.Context, name string, dryRun bool) (*SeedPlan, error) {
+	profile, err := s.loadProfile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &SeedPlan{Profile: profile.Name, Checksum: profile.Checksum(), DryRun: dryRun, Changes: []SeedChange{}}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if !dryRun {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, hashtext(?))", seedLockNamespace, "seed-profile").Error; err != nil {
+				return fmt.Errorf("failed to acquire seed profile lock: %w", err)
+			}
+		}
+
+		var records []models.SeedRecord
+		if err := tx.Find(&records).Error; err != nil {
+			return fmt.Errorf("failed to load seed records: %w", err)
+		}
+		recorded := make(map[string]*models.SeedRecord, len(records))
+		for i := range records {
+			recorded[records[i].Kind+"/"+records[i].Key] = &records[i]
+		}
+
+		for _, seed := range profile.Templates {
+			key := models.SeedKindTemplate + "/" + seed.Key
+			change, err := s.applyTemplate(ctx, tx, profile.Name, seed, recorded[key], dryRun)
+			if err != nil {
+				return fmt.Errorf("template %q: %w", seed.Key, err)
+			}
+			delete(recorded, key)
+			plan.add(change)
+		}
+
+		for _, seed := range profile.ReservedSlugs {
+			key := models.SeedKindReservedSlug + "/" + seed.Slug
+			change, err := s.applyReservedSlug(tx, profile.Name, seed, recorded[key], dryRun)
+			if err != nil {
+				return fmt.Errorf("reserved slug %q: %w", seed.Slug, err)
+			}
+			delete(recorded, key)
+			plan.add(change)
+		}
+
+		for _, record := range records {
+			if _, ok := recorded[record.Kind+"/"+record.Key]; ok {
+				plan.add(SeedChange{Kind: record.Kind, Key: record.Key, Action: SeedActionOrphaned,
+					Reason: fmt.Sprintf("last applied by profile %s", record.Profile)})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply seed profile %s: %w", profile.Name, err)
+	}
+
+	return plan, nil
+}
+
+// loadProfile loads the named profile, or the configured one (falling back to the base profile
+// for environments that have none of their own)
+func (s *SeedProfileService) loadProfile(name string) (*seeds.Profile, error) {
+	if name != "" {
+		return s.loader.Load(name)
+	}
+
+	profile, err := s.loader.Load(s.profile)
+	if errors.Is(err, seeds.ErrProfileNotFound) && s.profile != seeds.BaseProfile {
+		log.Printf("[SeedProfile] No seed profile named %q, using %q", s.profile, seeds.BaseProfile)
+		return s.loader.Load(seeds.BaseProfile)
+	}
+	return profile, err
+}
+
+func (s *SeedProfileService) applyTemplate(ctx context.Context, tx *gorm.DB, profileName string, seed seeds.TemplateSeed, record *models.SeedRecord, dryRun bool) (SeedChange, error) {
+	change := SeedChange{Kind: models.SeedKindTemplate, Key: seed.Key}
+	checksum := seed.Checksum()
+	if record != nil && record.Checksum == checksum {
+		change.Action = SeedActionUnchanged
+		return change, nil
+	}
+
+	// Recorded templates are found by ID so renames apply; unrecorded ones (seeded before
+	// profiles existed, or created by hand) are adopted by application type and name
+	var existing *models.OnboardingTemplate
+	if record != nil && record.TargetID != nil {
+		var template models.OnboardingTemplate
+		err := tx.Where("id = ?", *record.TargetID).First(&template).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return change, fmt.Errorf("failed to load template: %w", err)
+		}
+		if err == nil {
+			existing = &template
+		}
+	}
+	if existing == nil {
+		var template models.OnboardingTemplate
+		err := tx.Where("application_type = ? AND name = ?", seed.ApplicationType, seed.Name).
+			Order("created_at ASC").
+			First(&template).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return change, fmt.Errorf("failed to load template: %w", err)
+		}
+		if err == nil {
+			existing = &template
+		}
+	}
+
+	templateRepo := repository.NewTemplateRepository(tx)
+
+	if existing == nil {
+		change.Action = SeedActionCreate
+		if dryRun {
+			return change, nil
+		}
+		created, err := templateRepo.CreateTemplate(ctx, seed.Model())
+		if err != nil {
+			return change, err
+		}
+		if err := setSeededTemplateDefault(tx, created, seed.IsDefault); err != nil {
+			return change, err
+		}
+		return change, saveSeedRecord(tx, models.SeedKindTemplate, seed.Key, checksum, profileName, created.ID)
+	}
+
+	change.Fields = TemplateSeedChanges(seed, existing)
+	if len(change.Fields) == 0 {
+		change.Action = SeedActionRecord
+		if dryRun {
+			return change, nil
+		}
+		return change, saveSeedRecord(tx, models.SeedKindTemplate, seed.Key, checksum, profileName, existing.ID)
+	}
+
+	contentChanged := false
+	for _, field := range change.Fields {
+		switch field {
+		case "name", "description", "template_config", "steps", "custom_fields":
+			contentChanged = true
+		}
+	}
+	if contentChanged {
+		draft, err := templateRepo.GetDraftVersion(ctx, existing.ID)
+		if err != nil {
+			return change, err
+		}
+		if draft != nil {
+			// Not recorded, so the next apply retries once the draft is published or discarded
+			change.Action = SeedActionSkip
+			change.Reason = ErrTemplateDraftPending.Error()
+			return change, nil
+		}
+	}
+
+	change.Action = SeedActionUpdate
+	if dryRun {
+		return change, nil
+	}
+
+	template := existing
+	if contentChanged {
+		if err := templateRepo.EnsureBaselineVersion(ctx, existing); err != nil {
+			return change, err
+		}
+		declared := seed.Model()
+		version := models.NewTemplateVersion(existing, 0, models.TemplateVersionStatusDraft)
+		version.Name = declared.Name
+		version.Description = declared.Description
+		version.TemplateConfig = declared.TemplateConfig
+		version.Steps = declared.Steps
+		version.CustomFields = declared.CustomFields
+		version.ChangeNotes = fmt.Sprintf("Applied seed profile %s", profileName)
+		if _, err := templateRepo.CreateDraftVersion(ctx, version); err != nil {
+			return change, err
+		}
+		published, err := templateRepo.PublishTemplateVersion(ctx, existing, version)
+		if err != nil {
+			return change, err
+		}
+		template = published
+	}
+
+	if err := tx.Model(&models.OnboardingTemplate{}).Where("id = ?", template.ID).Updates(map[string]interface{}{
+		"application_type": seed.ApplicationType,
+		"is_active":        seed.IsActive(),
+		"updated_at":       time.Now(),
+	}).Error; err != nil {
+		return change, fmt.Errorf("failed to update template settings: %w", err)
+	}
+	template.ApplicationType = seed.ApplicationType
+	if err := setSeededTemplateDefault(tx, template, seed.IsDefault); err != nil {
+		return change, err
+	}
+
+	return change, saveSeedRecord(tx, models.SeedKindTemplate, seed.Key, checksum, profileName, template.ID)
+}
+
+func (s *SeedProfileService) applyReservedSlug(tx *gorm.DB, profileName string, seed seeds.ReservedSlugSeed, record *models.SeedRecord, dryRun bool) (SeedChange, error) {
+	change := SeedChange{Kind: models.SeedKindReservedSlug, Key: seed.Slug}
+	checksum := seed.Checksum()
+	if record != nil && record.Checksum == checksum {
+		change.Action = SeedActionUnchanged
+		return change, nil
+	}
+
+	var existing models.ReservedSlug
+	err := tx.Where("slug = ?", seed.Slug).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return change, fmt.Errorf("failed to load reserved slug: %w", err)
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		change.Action = SeedActionCreate
+		if dryRun {
+			return change, nil
+		}
+		reserved := &models.ReservedSlug{
+			Slug:      seed.Slug,
+			Reason:    seed.Reason,
+			Category:  seed.Category,
+			IsActive:  seed.IsActive(),
+			CreatedBy: "system",
+		}
+		if err := tx.Create(reserved).Error; err != nil {
+			return change, fmt.Errorf("failed to create reserved slug: %w", err)
+		}
+		return change, saveSeedRecord(tx, models.SeedKindReservedSlug, seed.Slug, checksum, profileName, reserved.ID)
+	}
+
+	change.Fields = ReservedSlugSeedChanges(seed, &existing)
+	change.Action = SeedActionUpdate
+	if len(change.Fields) == 0 {
+		change.Action = SeedActionRecord
+	}
+	if dryRun {
+		return change, nil
+	}
+
+	if len(change.Fields) > 0 {
+		// is_active is a bool, so a map keeps false from being skipped as a zero value
+		if err := tx.Model(&models.ReservedSlug{}).Where("id = ?", existing.ID).Updates(map[string]interface{}{
+			"reason":     seed.Reason,
+			"category":   seed.Category,
+			"is_active":  seed.IsActive(),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return change, fmt.Errorf("failed to update reserved slug: %w", err)
+		}
+	}
+	return change, saveSeedRecord(tx, models.SeedKindReservedSlug, seed.Slug, checksum, profileName, existing.ID)
+}
+
+// TemplateSeedChanges lists the fields in which a template differs from its seed
+func TemplateSeedChanges(seed seeds.TemplateSeed, template *models.OnboardingTemplate) []string {
+	fields := []string{}
+	if template.Name != seed.Name {
+		fields = append(fields, "name")
+	}
+	if template.Description != seed.Description {
+		fields = append(fields, "description")
+	}
+	if !seedJSONEqual(seed.TemplateConfig, template.TemplateConfig, "{}") {
+		fields = append(fields, "template_config")
+	}
+	if !seedJSONEqual(seed.Steps, template.Steps, "[]") {
+		fields = append(fields, "steps")
+	}
+	if !seedJSONEqual(seed.CustomFields, template.CustomFields, "[]") {
+		fields = append(fields, "custom_fields")
+	}
+	if template.ApplicationType != seed.ApplicationType {
+		fields = append(fields, "application_type")
+	}
+	if template.IsActive != seed.IsActive() {
+		fields = append(fields, "is_active")
+	}
+	if template.IsDefault != seed.IsDefault {
+		fields = append(fields, "is_default")
+	}
+	return fields
+}
+
+// ReservedSlugSeedChanges lists the fields in which a reserved slug differs from its seed
+func ReservedSlugSeedChanges(seed seeds.ReservedSlugSeed, reserved *models.ReservedSlug) []string {
+	fields := []string{}
+	if reserved.Reason != seed.Reason {
+		fields = append(fields, "reason")
+	}
+	if reserved.Category != seed.Category {
+		fields = append(fields, "category")
+	}
+	if reserved.IsActive != seed.IsActive() {
+		fields = append(fields, "is_active")
+	}
+	return fields
+}
+
+// seedJSONEqual compares declared and stored JSON, treating missing values as the empty default
+func seedJSONEqual(declared json.RawMessage, stored models.JSONB, empty string) bool {
+	a, b := models.JSONB(declared), stored
+	if isEmptyJSON(a) {
+		a = models.JSONB(empty)
+	}
+	if isEmptyJSON(b) {
+		b = models.JSONB(empty)
+	}
+	return templateContentEqual(a, b)
+}
+
+// setSeededTemplateDefault makes the template the default of its application type, or stops it being one
+func setSeededTemplateDefault(tx *gorm.DB, template *models.OnboardingTemplate, isDefault bool) error {
+	if isDefault {
+		if err := tx.Model(&models.OnboardingTemplate{}).
+			Where("application_type = ? AND is_default = ? AND id <> ?", template.ApplicationType, true, template.ID).
+			Update("is_default", false).Error; err != nil {
+			return fmt.Errorf("failed to unset existing default templates: %w", err)
+		}
+	}
+	if err := tx.Model(&models.OnboardingTemplate{}).Where("id = ?", template.ID).
+		Update("is_default", isDefault).Error; err != nil {
+		return fmt.Errorf("failed to set default template: %w", err)
+	}
+	return nil
+}
+
+func saveSeedRecord(tx *gorm.DB, kind, key, checksum, profileName string, targetID uuid.UUID) error {
+	now := time.Now()
+	record := &models.SeedRecord{
+		Kind:      kind,
+		Key:       key,
+		Checksum:  checksum,
+		Profile:   profileName,
+		TargetID:  &targetID,
+		AppliedAt: now,
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kind"}, {Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"checksum": checksum, "profile": profileName, "target_id": targetID, "applied_at": now, "updated_at": now}),
+	}).Create(record).Error; err != nil {
+		return fmt.Errorf("failed to record seed checksum: %w", err)
+	}
+	return nil
+}
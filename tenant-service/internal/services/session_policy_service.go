@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+const (
+	sessionPolicyMinMinutes        = 5
+	sessionPolicyMaxMinutes        = 30 * 24 * 60
+	sessionPolicyMaxRememberMeDays = 90
+)
+
+// ErrSessionPolicyForbidden is returned when the user may not manage the tenant's session policy
+var ErrSessionPolicyForbidden = errors.New("only tenant owners and admins can manage the session policy")
+
+// SessionPolicy is the session lifetime section of a tenant's auth policy. It applies to
+// sessions started after it changes.
+type SessionPolicy struct {
+	AbsoluteTimeoutMinutes int        `json:"absolute_timeout_minutes"` // Longest a session lasts from login
+	IdleTimeoutMinutes     int        `json:"idle_timeout_minutes"`     // 0 = no idle timeout
+	RememberMeDays         int        `json:"remember_me_days"`         // 0 = remember-me disabled
+	IsDefault              bool       `json:"is_default"`               // True when the tenant has no stored auth policy
+	UpdatedAt              *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdateSessionPolicyRequest changes the session policy; omitted fields keep their current value
+type UpdateSessionPolicyRequest struct {
+	AbsoluteTimeoutMinutes *int `json:"absolute_timeout_minutes"`
+	IdleTimeoutMinutes     *int `json:"idle_timeout_minutes"`
+	RememberMeDays         *int `json:"remember_me_days"`
+}
+
+// SessionPolicyService manages the per-tenant session lifetime controls
+type SessionPolicyService struct {
+	credentialRepo *repository.CredentialRepository
+	membershipRepo *repository.MembershipRepository
+}
+
+// NewSessionPolicyService creates a new session policy service
+func NewSessionPolicyService(db *gorm.DB) *SessionPolicyService {
+	return &SessionPolicyService{
+		credentialRepo: repository.NewCredentialRepository(db),
+		membershipRepo: repository.NewMembershipRepository(db),
+	}
+}
+
+// AuthorizeManage checks the user is an owner or admin of the tenant
+func (s *SessionPolicyService) AuthorizeManage(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrSessionPolicyForbidden
+	}
+	return nil
+}
+
+// GetPolicy returns the tenant's session policy, or the defaults when none is stored
+func (s *SessionPolicyService) GetPolicy(ctx context.Context, tenantID uuid.UUID) (*SessionPolicy, error) {
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return toSessionPolicy(policy), nil
+}
+
+// UpdatePolicy applies changes to the tenant's session policy, creating the auth policy if needed
+func (s *SessionPolicyService) UpdatePolicy(ctx context.Context, tenantID, userID uuid.UUID, req UpdateSessionPolicyRequest) (*SessionPolicy, error) {
+	if err := validateSessionPolicyRequest(req); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		if policy, err = s.credentialRepo.CreateAuthPolicy(ctx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.AbsoluteTimeoutMinutes != nil {
+		policy.SessionTimeoutMinutes = *req.AbsoluteTimeoutMinutes
+	}
+	if req.IdleTimeoutMinutes != nil {
+		policy.SessionIdleTimeoutMinutes = *req.IdleTimeoutMinutes
+	}
+	if req.RememberMeDays != nil {
+		policy.SessionRememberMeDays = *req.RememberMeDays
+	}
+	settings := policy.SessionSettings()
+	if settings.IdleMinutes > settings.AbsoluteMinutes {
+		return nil, NewValidationError("idle_timeout_minutes", "idle_timeout_minutes must not exceed absolute_timeout_minutes", nil)
+	}
+	if settings.RememberMeDays > 0 && settings.Lifetime(true) < settings.Lifetime(false) {
+		return nil, NewValidationError("remember_me_days", "remember-me sessions must not be shorter than the absolute timeout", nil)
+	}
+	policy.UpdatedBy = &userID
+
+	if err := s.credentialRepo.UpdateAuthPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	log.Printf("[SessionPolicyService] Session policy for tenant %s updated by %s", tenantID, userID)
+	return toSessionPolicy(policy), nil
+}
+
+func toSessionPolicy(policy *models.TenantAuthPolicy) *SessionPolicy {
+	settings := policy.SessionSettings()
+	result := &SessionPolicy{
+		AbsoluteTimeoutMinutes: settings.AbsoluteMinutes,
+		IdleTimeoutMinutes:     settings.IdleMinutes,
+		RememberMeDays:         settings.RememberMeDays,
+		IsDefault:              policy == nil,
+	}
+	if policy != nil {
+		updatedAt := policy.UpdatedAt
+		result.UpdatedAt = &updatedAt
+	}
+	return result
+}
+
+// validateSessionPolicyRequest checks the ranges of the supplied fields
+func validateSessionPolicyRequest(req UpdateSessionPolicyRequest) error {
+	if req.AbsoluteTimeoutMinutes != nil && (*req.AbsoluteTimeoutMinutes < sessionPolicyMinMinutes || *req.AbsoluteTimeoutMinutes > sessionPolicyMaxMinutes) {
+		return NewValidationError("absolute_timeout_minutes", fmt.Sprintf("absolute_timeout_minutes must be between %d and %d", sessionPolicyMinMinutes, sessionPolicyMaxMinutes), nil)
+	}
+	if req.IdleTimeoutMinutes != nil && *req.IdleTimeoutMinutes != 0 && (*req.IdleTimeoutMinutes < sessionPolicyMinMinutes || *req.IdleTimeoutMinutes > sessionPolicyMaxMinutes) {
+		return NewValidationError("idle_timeout_minutes", fmt.Sprintf("idle_timeout_minutes must be 0 (off) or between %d and %d", sessionPolicyMinMinutes, sessionPolicyMaxMinutes), nil)
+	}
+	if req.RememberMeDays != nil && (*req.RememberMeDays < 0 || *req.RememberMeDays > sessionPolicyMaxRememberMeDays) {
+		return NewValidationError("remember_me_days", fmt.Sprintf("remember_me_days must be between 0 (off) and %d", sessionPolicyMaxRememberMeDays), nil)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+	"tenant-service/internal/redis"
+)
+
+// ErrSessionNotFound is returned when revoking a session that does not exist, belongs to
+// someone else, or is no longer active
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session revocation reasons recorded on the session and in the audit log
+const (
+	SessionRevokedByUser      = "user_revoked"
+	SessionRevokedIdleTimeout = "idle_timeout"
+)
+
+// sessionTouchInterval limits how often activity on a session is written back
+const sessionTouchInterval = time.Minute
+
+// SessionRegistry tracks active login sessions per tenant user. PostgreSQL is the source of
+// truth; Redis serves listings and status checks when it is available.
+type SessionRegistry struct {
+	db    *gorm.DB
+	cache *redis.Client // optional
+}
+
+// NewSessionRegistry creates a session registry; cache may be nil
+func NewSessionRegistry(db *gorm.DB, cache *redis.Client) *SessionRegistry {
+	return &SessionRegistry{db: db, cache: cache}
+}
+
+// Register records a new session
+func (r *SessionRegistry) Register(ctx context.Context, session *models.TenantAuthSession) error {
+	now := time.Now()
+	session.CreatedAt = now
+	session.LastSeenAt = now
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return fmt.Errorf("failed to register session: %w", err)
+	}
+	r.cacheSession(ctx, session)
+	return nil
+}
+
+// List returns the active sessions of a tenant user, newest first
+func (r *SessionRegistry) List(ctx context.Context, tenantID, subject string) ([]models.TenantAuthSession, error) {
+	now := time.Now()
+
+	if r.cache != nil {
+		cached, found, err := r.cache.GetAuthSessions(ctx, tenantID, subject)
+		if err != nil {
+			log.Printf("[SessionRegistry] Warning: Redis unavailable, listing sessions from database: %v", err)
+		} else if found {
+			sessions := make([]models.TenantAuthSession, 0, len(cached))
+			for id, data := range cached {
+				var session models.TenantAuthSession
+				if err := json.Unmarshal(data, &session); err != nil {
+					log.Printf("[SessionRegistry] Warning: Dropping unreadable cached session %s: %v", id, err)
+					continue
+				}
+				if session.IsActive(now) {
+					sessions = append(sessions, session)
+				}
+			}
+			sort.Slice(sessions, func(i, j int) bool { return sessions[i].CreatedAt.After(sessions[j].CreatedAt) })
+			return sessions, nil
+		}
+	}
+
+	var sessions []models.TenantAuthSession
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND subject = ? AND revoked_at IS NULL AND expires_at > ?", tenantID, subject, now).
+		Where("idle_timeout_minutes = 0 OR last_seen_at + idle_timeout_minutes * INTERVAL '1 minute' > ?", now).
+		Order("created_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// Revoke ends one of a tenant user's sessions and returns it
+func (r *SessionRegistry) Revoke(ctx context.Context, tenantID, subject, sessionID, revokedBy, reason string) (*models.TenantAuthSession, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&models.TenantAuthSession{}).
+		Where("id = ? AND tenant_id = ? AND subject = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, tenantID, subject, now).
+		Updates(map[string]interface{}{
+			"revoked_at":     now,
+			"revoked_by":     revokedBy,
+			"revoked_reason": reason,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to revoke session: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	if r.cache != nil {
+		if err := r.cache.DeleteAuthSession(ctx, tenantID, subject, sessionID); err != nil {
+			log.Printf("[SessionRegistry] Warning: Failed to remove revoked session %s from cache: %v", sessionID, err)
+		}
+	}
+
+	var session models.TenantAuthSession
+	if err := r.db.WithContext(ctx).Where("id = ?", sessionID).First(&session).Error; err != nil {
+		return nil, fmt.Errorf("failed to load revoked session: %w", err)
+	}
+	return &session, nil
+}
+
+// Get returns a session by ID, or ErrSessionNotFound
+func (r *SessionRegistry) Get(ctx context.Context, sessionID string) (*models.TenantAuthSession, error) {
+	var session models.TenantAuthSession
+	if err := r.db.WithContext(ctx).Where("id = ?", sessionID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return &session, nil
+}
+
+// Touch records that an active session was used, writing at most once per sessionTouchInterval
+func (r *SessionRegistry) Touch(ctx context.Context, session *models.TenantAuthSession) error {
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) < sessionTouchInterval {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&models.TenantAuthSession{}).
+		Where("id = ? AND revoked_at IS NULL", session.ID).
+		Update("last_seen_at", now).Error; err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	session.LastSeenAt = now
+	r.cacheSession(ctx, session)
+	return nil
+}
+
+// cacheSession writes an active session to Redis; failures only cost the fast path
+func (r *SessionRegistry) cacheSession(ctx context.Context, session *models.TenantAuthSession) {
+	if r.cache == nil {
+		return
+	}
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+	// json:"-" drops the owner, which is already part of the set key
+	if err := r.cache.SaveAuthSession(ctx, session.TenantID.String(), session.Subject, session.ID, data, ttl); err != nil {
+		log.Printf("[SessionRegistry] Warning: Failed to cache session %s: %v", session.ID, err)
+	}
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/nats"
+	"tenant-service/internal/repository"
+)
+
+var (
+	// ErrSlugChangeForbidden is returned when the user does not own the tenant
+	ErrSlugChangeForbidden = errors.New("only tenant owners can change the slug")
+	// ErrSlugUnchanged is returned when the tenant already uses the requested slug
+	ErrSlugUnchanged = errors.New("tenant already uses this slug")
+	// ErrSlugChangeCustomDomain is returned for tenants served from their own domain
+	ErrSlugChangeCustomDomain = errors.New("tenants using a custom domain cannot change their slug")
+	// ErrSlugChangeNotActive is returned for tenants being created, suspended or deleted
+	ErrSlugChangeNotActive = errors.New("only active tenants can change their slug")
+	// ErrSlugChangeCooldown is returned when the tenant was renamed too recently
+	ErrSlugChangeCooldown = errors.New("the slug was changed too recently")
+)
+
+// SlugEventPublisher publishes slug rename events (satisfied by *nats.Client)
+type SlugEventPublisher interface {
+	PublishTenantSlugChanged(ctx context.Context, event *nats.TenantSlugChangedEvent) error
+	PublishTenantSlugReleased(ctx context.Context, event *nats.TenantSlugReleasedEvent) error
+}
+
+// ChangeSlugRequest renames a tenant
+type ChangeSlugRequest struct {
+	Slug string `json:"slug" binding:"required"`
+}
+
+// SlugChangeResult is a tenant's new slug and URLs, and until when the old slug redirects
+type SlugChangeResult struct {
+	TenantID          uuid.UUID `json:"tenant_id"`
+	OldSlug           string    `json:"old_slug"`
+	NewSlug           string    `json:"new_slug"`
+	AdminURL          string    `json:"admin_url"`
+	StorefrontURL     string    `json:"storefront_url"`
+	APIURL            string    `json:"api_url"`
+	RedirectExpiresAt time.Time `json:"redirect_expires_at"`
+}
+
+// SlugChangeService renames tenants. The new slug is reserved and the tenant, its URLs and
+// the redirect from the old slug are written in one transaction; memberships reference the
+// tenant by ID, so dropping their cached lookups is enough for members to see the new slug.
+// tenant-router-service provisions routes for the new hosts from tenant.slug.changed and
+// removes the old ones from tenant.slug.released, published when the redirect expires.
+type SlugChangeService struct {
+	db             *gorm.DB
+	membershipRepo *repository.MembershipRepository
+	publisher      SlugEventPublisher
+	config         config.SlugChangeConfig
+	baseDomain     string
+}
+
+// NewSlugChangeService creates a new slug change service. membershipRepo should be the
+// cache-enabled repository so renamed tenants are not served from stale lookups.
+func NewSlugChangeService(db *gorm.DB, membershipRepo *repository.MembershipRepository, cfg config.SlugChangeConfig, baseDomain string) *SlugChangeService {
+	if baseDomain == "" {
+		baseDomain = "tesserix.app"
+	}
+	return &SlugChangeService{
+		db:             db,
+		membershipRepo: membershipRepo,
+		config:         cfg,
+		baseDomain:     baseDomain,
+	}
+}
+
+// SetEventPublisher enables tenant.slug.changed and tenant.slug.released events
+func (s *SlugChangeService) SetEventPublisher(publisher SlugEventPublisher) {
+	s.publisher = publisher
+}
+
+// RedirectCheckInterval returns the interval of the redirect expiry job
+func (s *SlugChangeService) RedirectCheckInterval() time.Duration {
+	if s.config.CheckIntervalMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(s.config.CheckIntervalMinutes) * time.Minute
+}
+
+// redirectDuration returns how long an old slug keeps redirecting
+func (s *SlugChangeService) redirectDuration() time.Duration {
+	if s.config.RedirectDays <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(s.config.RedirectDays) * 24 * time.Hour
+}
+
+// AuthorizeChange checks the user owns the tenant
+func (s *SlugChangeService) AuthorizeChange(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || role != models.MembershipRoleOwner {
+		return ErrSlugChangeForbidden
+	}
+	return nil
+}
+
+// CheckSlugChange reports whether a tenant in this state may be renamed now
+func CheckSlugChange(tenant *models.Tenant, cooldown time.Duration, now time.Time) error {
+	if tenant.UseCustomDomain {
+		return ErrSlugChangeCustomDomain
+	}
+	if tenant.Status != "active" {
+		return ErrSlugChangeNotActive
+	}
+	if cooldown > 0 && tenant.SlugChangedAt != nil && now.Before(tenant.SlugChangedAt.Add(cooldown)) {
+		return ErrSlugChangeCooldown
+	}
+	return nil
+}
+
+// ChangeSlug renames the tenant. The requested slug is validated like an onboarding slug,
+// except that a tenant may take back one of its own former slugs while it still redirects.
+func (s *SlugChangeService) ChangeSlug(ctx context.Context, tenantID uuid.UUID, req ChangeSlugRequest, changedBy uuid.UUID) (*SlugChangeResult, error) {
+	var tenant models.Tenant
+	if err := s.db.WithContext(ctx).First(&tenant, "id = ?", tenantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("tenant not found")
+		}
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	cooldown := time.Duration(s.config.CooldownDays) * 24 * time.Hour
+	if err := CheckSlugChange(&tenant, cooldown, time.Now()); err != nil {
+		return nil, err
+	}
+
+	newSlug, formerSlug, err := s.validateSlug(ctx, &tenant, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reserve first: the reservation's advisory lock serializes this rename with onboarding
+	// sessions and other tenants claiming the same slug. A former slug is already reserved.
+	if err := s.membershipRepo.ActivateSlugReservation(ctx, newSlug, tenantID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.rename(ctx, tenantID, newSlug, changedBy, cooldown)
+	if err != nil {
+		if !formerSlug {
+			if releaseErr := s.membershipRepo.ReleaseTenantSlug(ctx, newSlug, tenantID); releaseErr != nil {
+				log.Printf("[SlugChangeService] Failed to release slug %s after failed rename of tenant %s: %v", newSlug, tenantID, releaseErr)
+			}
+		}
+		return nil, err
+	}
+
+	log.Printf("[SlugChangeService] Tenant %s renamed %s -> %s (old slug redirects until %s)",
+		tenantID, result.OldSlug, result.NewSlug, result.RedirectExpiresAt.Format(time.RFC3339))
+
+	// Members' cached memberships embed the tenant, and the old slug's cached lookup would
+	// otherwise shadow the redirect
+	s.membershipRepo.InvalidateDeletedTenantCache(ctx, result.OldSlug, nil)
+	s.membershipRepo.InvalidateTenantCache(ctx, tenantID)
+	s.publishSlugChanged(ctx, result)
+	return result, nil
+}
+
+// validateSlug normalizes the requested slug and checks it is available to the tenant.
+// formerSlug reports that the slug is one of the tenant's own redirecting former slugs.
+func (s *SlugChangeService) validateSlug(ctx context.Context, tenant *models.Tenant, requested string) (slug string, formerSlug bool, err error) {
+	validation, err := s.membershipRepo.ValidateSlugWithSuggestions(ctx, requested, nil)
+	if err != nil {
+		return "", false, err
+	}
+	if validation.Slug == tenant.Slug {
+		return "", false, ErrSlugUnchanged
+	}
+
+	// The tenant's former slugs stay reserved to it while they redirect
+	redirected, err := s.membershipRepo.GetTenantBySlugRedirect(ctx, validation.Slug)
+	if err != nil {
+		return "", false, err
+	}
+	if redirected != nil && redirected.ID == tenant.ID {
+		return validation.Slug, true, nil
+	}
+
+	if !validation.Available {
+		return "", false, NewValidationError("slug", validation.Message, validation.Suggestions)
+	}
+	return validation.Slug, false, nil
+}
+
+// rename writes the new slug and URLs, the redirect from the old slug and the activity log
+func (s *SlugChangeService) rename(ctx context.Context, tenantID uuid.UUID, newSlug string, changedBy uuid.UUID, cooldown time.Duration) (*SlugChangeResult, error) {
+	now := time.Now()
+	adminHost, storefrontHost, apiHost := TenantHosts(newSlug, s.baseDomain)
+	result := &SlugChangeResult{
+		TenantID:          tenantID,
+		NewSlug:           newSlug,
+		AdminURL:          "https://" + adminHost,
+		StorefrontURL:     "https://" + storefrontHost,
+		APIURL:            "https://" + apiHost,
+		RedirectExpiresAt: now.Add(s.redirectDuration()),
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tenant models.Tenant
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tenant, "id = ?", tenantID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("tenant not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock tenant: %w", err)
+		}
+
+		// Checked again under the lock in case a concurrent rename or deletion won
+		if tenant.Slug == newSlug {
+			return ErrSlugUnchanged
+		}
+		if err := CheckSlugChange(&tenant, cooldown, now); err != nil {
+			return err
+		}
+		result.OldSlug = tenant.Slug
+
+		if err := tx.Model(&models.Tenant{}).Where("id = ?", tenantID).Updates(map[string]interface{}{
+			"slug":            newSlug,
+			"subdomain":       newSlug,
+			"admin_url":       result.AdminURL,
+			"storefront_url":  result.StorefrontURL,
+			"api_url":         result.APIURL,
+			"slug_changed_at": now,
+			"updated_at":      now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to rename tenant: %w", err)
+		}
+
+		// Taking back a former slug ends its redirect; the other former slugs follow the rename
+		if err := tx.Where("tenant_id = ? AND old_slug = ?", tenantID, newSlug).Delete(&models.TenantSlugRedirect{}).Error; err != nil {
+			return fmt.Errorf("failed to remove slug redirect: %w", err)
+		}
+		if err := tx.Model(&models.TenantSlugRedirect{}).Where("tenant_id = ?", tenantID).Update("new_slug", newSlug).Error; err != nil {
+			return fmt.Errorf("failed to repoint slug redirects: %w", err)
+		}
+		if err := tx.Create(&models.TenantSlugRedirect{
+			TenantID:  tenantID,
+			OldSlug:   tenant.Slug,
+			NewSlug:   newSlug,
+			ChangedBy: &changedBy,
+			ExpiresAt: result.RedirectExpiresAt,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to create slug redirect: %w", err)
+		}
+
+		details, _ := models.NewJSONB(map[string]interface{}{
+			"old_slug":            tenant.Slug,
+			"new_slug":            newSlug,
+			"redirect_expires_at": result.RedirectExpiresAt,
+		})
+		if err := tx.Create(&models.TenantActivityLog{
+			TenantID:     tenantID,
+			UserID:       changedBy,
+			Action:       models.ActivityTenantSlugChanged,
+			ResourceType: "tenant",
+			ResourceID:   &tenantID,
+			Details:      details,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to log slug change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExpireRedirects releases former slugs whose redirect ended: the reservation is released so
+// other tenants can claim the slug, and tenant-router-service removes the old routes
+func (s *SlugChangeService) ExpireRedirects(ctx context.Context) (int, error) {
+	var expired []models.TenantSlugRedirect
+	if err := s.db.WithContext(ctx).
+		Where("expires_at <= ?", time.Now()).
+		Order("expires_at ASC").
+		Limit(500).
+		Find(&expired).Error; err != nil {
+		return 0, fmt.Errorf("failed to find expired slug redirects: %w", err)
+	}
+
+	released := 0
+	for _, redirect := range expired {
+		// Released before the redirect is deleted, so a failure is retried on the next run
+		if err := s.membershipRepo.ReleaseTenantSlug(ctx, redirect.OldSlug, redirect.TenantID); err != nil {
+			log.Printf("[SlugChangeService] Failed to release former slug %s of tenant %s: %v", redirect.OldSlug, redirect.TenantID, err)
+			continue
+		}
+		if err := s.db.WithContext(ctx).Delete(&redirect).Error; err != nil {
+			log.Printf("[SlugChangeService] Failed to delete slug redirect %s: %v", redirect.OldSlug, err)
+			continue
+		}
+		released++
+
+		if s.publisher != nil {
+			if err := s.publisher.PublishTenantSlugReleased(ctx, &nats.TenantSlugReleasedEvent{
+				TenantID:    redirect.TenantID.String(),
+				Slug:        redirect.OldSlug,
+				CurrentSlug: redirect.NewSlug,
+			}); err != nil {
+				log.Printf("[SlugChangeService] Failed to publish slug release of %s for tenant %s: %v", redirect.OldSlug, redirect.TenantID, err)
+			}
+		}
+	}
+	return released, nil
+}
+
+// publishSlugChanged notifies tenant-router-service to provision the new hosts
+func (s *SlugChangeService) publishSlugChanged(ctx context.Context, result *SlugChangeResult) {
+	if s.publisher == nil {
+		return
+	}
+	adminHost, storefrontHost, apiHost := TenantHosts(result.NewSlug, s.baseDomain)
+	if err := s.publisher.PublishTenantSlugChanged(ctx, &nats.TenantSlugChangedEvent{
+		TenantID:          result.TenantID.String(),
+		OldSlug:           result.OldSlug,
+		NewSlug:           result.NewSlug,
+		AdminHost:         adminHost,
+		StorefrontHost:    storefrontHost,
+		APIHost:           apiHost,
+		BaseDomain:        s.baseDomain,
+		RedirectExpiresAt: result.RedirectExpiresAt,
+	}); err != nil {
+		log.Printf("[SlugChangeService] Failed to publish slug change for tenant %s: %v", result.TenantID, err)
+	}
+}
+
+// TenantHosts returns the admin, storefront and API hosts of a tenant on the platform domain
+func TenantHosts(slug, baseDomain string) (adminHost, storefrontHost, apiHost string) {
+	return fmt.Sprintf("%s-admin.%s", slug, baseDomain),
+		fmt.Sprintf("%s.%s", slug, baseDomain),
+		fmt.Sprintf("%s-api.%s", slug, baseDomain)
+}
@@ -0,0 +1,471 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tenant-service/internal/clients"
+	"tenant-service/internal/config"
+	"tenant-service/internal/listquery"
+	"tenant-service/internal/models"
+)
+
+// ErrStaffSyncInProgress is returned when a reconciliation run is already in progress
+var ErrStaffSyncInProgress = errors.New("staff membership reconciliation already in progress")
+
+// Drift kinds reported by the reconciliation metrics
+const (
+	driftMissingMembership = "missing_membership"
+	driftMembershipNoStaff = "membership_without_staff"
+)
+
+var (
+	staffSyncRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tesseract",
+		Subsystem: "tenant",
+		Name:      "staff_sync_runs_total",
+		Help:      "Staff membership reconciliation runs by result",
+	}, []string{"result"})
+
+	staffSyncMembershipsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tesseract",
+		Subsystem: "tenant",
+		Name:      "staff_sync_memberships_created_total",
+		Help:      "Memberships created for staff that had none",
+	})
+
+	staffSyncDrift = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tesseract",
+		Subsystem: "tenant",
+		Name:      "staff_sync_drift",
+		Help:      "Staff/membership disagreements found by the last full reconciliation run, by kind",
+	}, []string{"kind"})
+
+	staffSyncLastRun = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tesseract",
+		Subsystem: "tenant",
+		Name:      "staff_sync_last_run_timestamp_seconds",
+		Help:      "Unix time of the last completed full reconciliation run",
+	})
+)
+
+// StaffDirectory lists the staff members of a tenant (implemented by clients.StaffClient)
+type StaffDirectory interface {
+	ListTenantStaff(ctx context.Context, tenantID uuid.UUID) ([]clients.StaffMemberInfo, error)
+}
+
+// StaffMembershipSyncService reconciles staff-service records with tenant memberships
+// Staff created directly in staff-service may lack a UserTenantMembership row, which
+// makes tenant access checks fail. Missing memberships are created; disagreements that
+// need a human decision (role mismatches, deactivations) are recorded as conflicts.
+type StaffMembershipSyncService struct {
+	db            *gorm.DB
+	staff         StaffDirectory
+	interval      time.Duration
+	createMissing bool
+	mu            sync.Mutex
+}
+
+// StaffSyncTenantResult contains the reconciliation outcome for one tenant
+type StaffSyncTenantResult struct {
+	TenantID                uuid.UUID                       `json:"tenant_id"`
+	StaffChecked            int                             `json:"staff_checked"`
+	MissingMemberships      int                             `json:"missing_memberships"`
+	MembershipsCreated      int                             `json:"memberships_created"`
+	MembershipsWithoutStaff int                             `json:"memberships_without_staff"`
+	Conflicts               []models.MembershipSyncConflict `json:"conflicts"`
+}
+
+// StaffSyncResult contains the outcome of a reconciliation run
+type StaffSyncResult struct {
+	StartedAt          time.Time                `json:"started_at"`
+	Duration           string                   `json:"duration"`
+	DryRun             bool                     `json:"dry_run"`
+	CreateMissing      bool                     `json:"create_missing"` // False when only reporting drift
+	TenantsChecked     int                      `json:"tenants_checked"`
+	StaffChecked       int                      `json:"staff_checked"`
+	MembershipsCreated int                      `json:"memberships_created"`
+	Drift              map[string]int           `json:"drift"`
+	Tenants            []*StaffSyncTenantResult `json:"tenants,omitempty"` // Only tenants with drift
+	Errors             []string                 `json:"errors,omitempty"`
+}
+
+// NewStaffMembershipSyncService creates a new staff membership reconciliation service
+func NewStaffMembershipSyncService(db *gorm.DB, staff StaffDirectory, cfg config.StaffSyncConfig) *StaffMembershipSyncService {
+	interval := time.Duration(cfg.IntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &StaffMembershipSyncService{
+		db:            db,
+		staff:         staff,
+		interval:      interval,
+		createMissing: cfg.CreateMissing,
+	}
+}
+
+// Interval returns how often the scheduled reconciliation runs
+func (s *StaffMembershipSyncService) Interval() time.Duration {
+	return s.interval
+}
+
+// ReconcileAll reconciles every active tenant and publishes drift metrics
+func (s *StaffMembershipSyncService) ReconcileAll(ctx context.Context, dryRun bool) (*StaffSyncResult, error) {
+	if !s.mu.TryLock() {
+		return nil, ErrStaffSyncInProgress
+	}
+	defer s.mu.Unlock()
+
+	var tenantIDs []uuid.UUID
+	if err := s.db.WithContext(ctx).
+		Model(&models.Tenant{}).
+		Where("status = ?", "active").
+		Pluck("id", &tenantIDs).Error; err != nil {
+		staffSyncRuns.WithLabelValues("error").Inc()
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	result := s.newResult(dryRun)
+	for _, tenantID := range tenantIDs {
+		if ctx.Err() != nil {
+			result.Errors = append(result.Errors, ctx.Err().Error())
+			break
+		}
+		tenantResult, err := s.reconcileTenant(ctx, tenantID, dryRun)
+		if err != nil {
+			log.Printf("[StaffMembershipSync] Failed to reconcile tenant %s: %v", tenantID, err)
+			result.Errors = append(result.Errors, fmt.Sprintf("tenant %s: %v", tenantID, err))
+			continue
+		}
+		result.add(tenantResult)
+	}
+	result.Duration = time.Since(result.StartedAt).String()
+
+	// Drift gauges describe the whole fleet, so only full runs publish them
+	for kind, count := range result.Drift {
+		staffSyncDrift.WithLabelValues(kind).Set(float64(count))
+	}
+	staffSyncLastRun.SetToCurrentTime()
+	if len(result.Errors) > 0 {
+		staffSyncRuns.WithLabelValues("partial").Inc()
+	} else {
+		staffSyncRuns.WithLabelValues("success").Inc()
+	}
+
+	log.Printf("[StaffMembershipSync] Completed: tenants=%d, staff=%d, created=%d, drift=%v, errors=%d",
+		result.TenantsChecked, result.StaffChecked, result.MembershipsCreated, result.Drift, len(result.Errors))
+
+	return result, nil
+}
+
+// ReconcileTenant reconciles a single tenant on demand
+func (s *StaffMembershipSyncService) ReconcileTenant(ctx context.Context, tenantID uuid.UUID, dryRun bool) (*StaffSyncResult, error) {
+	if !s.mu.TryLock() {
+		return nil, ErrStaffSyncInProgress
+	}
+	defer s.mu.Unlock()
+
+	result := s.newResult(dryRun)
+	tenantResult, err := s.reconcileTenant(ctx, tenantID, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	result.add(tenantResult)
+	result.Duration = time.Since(result.StartedAt).String()
+
+	return result, nil
+}
+
+// ListConflicts returns recorded conflicts matching the list query, optionally including resolved ones
+func (s *StaffMembershipSyncService) ListConflicts(ctx context.Context, q *listquery.Query, includeResolved bool) ([]models.MembershipSyncConflict, int64, string, error) {
+	query := s.db.WithContext(ctx).Model(&models.MembershipSyncConflict{}).Scopes(q.FilterScope())
+	if !includeResolved {
+		query = query.Where("resolved_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to count sync conflicts: %w", err)
+	}
+
+	var conflicts []models.MembershipSyncConflict
+	if err := query.Scopes(q.PageScope()).Find(&conflicts).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to list sync conflicts: %w", err)
+	}
+
+	conflicts, next, err := listquery.NextPage(q, conflicts)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return conflicts, total, next, nil
+}
+
+func (s *StaffMembershipSyncService) newResult(dryRun bool) *StaffSyncResult {
+	return &StaffSyncResult{
+		StartedAt:     time.Now(),
+		DryRun:        dryRun,
+		CreateMissing: s.createMissing && !dryRun,
+		Drift: map[string]int{
+			driftMissingMembership:                0,
+			driftMembershipNoStaff:                0,
+			models.SyncConflictRoleMismatch:       0,
+			models.SyncConflictMembershipInactive: 0,
+			models.SyncConflictStaffInactive:      0,
+			models.SyncConflictUnlinkedStaff:      0,
+			models.SyncConflictCreateFailed:       0,
+		},
+	}
+}
+
+func (r *StaffSyncResult) add(t *StaffSyncTenantResult) {
+	r.TenantsChecked++
+	r.StaffChecked += t.StaffChecked
+	r.MembershipsCreated += t.MembershipsCreated
+	r.Drift[driftMissingMembership] += t.MissingMemberships
+	r.Drift[driftMembershipNoStaff] += t.MembershipsWithoutStaff
+	for _, c := range t.Conflicts {
+		r.Drift[c.Kind]++
+	}
+	if t.MissingMemberships > 0 || t.MembershipsWithoutStaff > 0 || len(t.Conflicts) > 0 {
+		r.Tenants = append(r.Tenants, t)
+	}
+}
+
+// reconcileTenant compares a tenant's staff with its memberships
+func (s *StaffMembershipSyncService) reconcileTenant(ctx context.Context, tenantID uuid.UUID, dryRun bool) (*StaffSyncTenantResult, error) {
+	staff, err := s.staff.ListTenantStaff(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staff: %w", err)
+	}
+
+	var memberships []models.UserTenantMembership
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Find(&memberships).Error; err != nil {
+		return nil, fmt.Errorf("failed to load memberships: %w", err)
+	}
+	byUser := make(map[uuid.UUID]*models.UserTenantMembership, len(memberships))
+	for i := range memberships {
+		byUser[memberships[i].UserID] = &memberships[i]
+	}
+
+	userIDs, err := s.resolveUserIDs(ctx, staff)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StaffSyncTenantResult{
+		TenantID:     tenantID,
+		StaffChecked: len(staff),
+		Conflicts:    []models.MembershipSyncConflict{},
+	}
+	create := s.createMissing && !dryRun
+	matched := make(map[uuid.UUID]bool, len(staff))
+
+	for _, member := range staff {
+		expectedRole := membershipRoleForStaff(member.Role)
+		conflict := models.MembershipSyncConflict{
+			TenantID:       tenantID,
+			StaffID:        member.ID,
+			Email:          member.Email,
+			StaffRole:      member.Role,
+			MembershipRole: expectedRole,
+		}
+
+		userID, linked := userIDs[member.ID]
+		if !linked {
+			if member.IsActive {
+				conflict.Kind = models.SyncConflictUnlinkedStaff
+				conflict.Details = "staff member has no Keycloak user; membership cannot be created until they sign in"
+				result.Conflicts = append(result.Conflicts, conflict)
+			}
+			continue
+		}
+		conflict.UserID = &userID
+		matched[userID] = true
+
+		membership, exists := byUser[userID]
+		switch {
+		case !exists && member.IsActive:
+			result.MissingMemberships++
+			if !create {
+				continue
+			}
+			if err := s.createMembership(ctx, tenantID, userID, expectedRole); err != nil {
+				log.Printf("[StaffMembershipSync] Failed to create membership for staff %s in tenant %s: %v", member.ID, tenantID, err)
+				conflict.Kind = models.SyncConflictCreateFailed
+				conflict.Details = err.Error()
+				result.Conflicts = append(result.Conflicts, conflict)
+				continue
+			}
+			result.MembershipsCreated++
+			staffSyncMembershipsCreated.Inc()
+			log.Printf("[StaffMembershipSync] Created %s membership for staff %s (user %s) in tenant %s", expectedRole, member.ID, userID, tenantID)
+
+		case !exists:
+			// Inactive staff without a membership is already consistent
+
+		case member.IsActive && !membership.IsActive:
+			// The membership may have been removed on purpose; do not reactivate it automatically
+			conflict.Kind = models.SyncConflictMembershipInactive
+			conflict.MembershipRole = membership.Role
+			conflict.Details = "staff member is active but their membership is deactivated"
+			result.Conflicts = append(result.Conflicts, conflict)
+
+		case !member.IsActive && membership.IsActive:
+			conflict.Kind = models.SyncConflictStaffInactive
+			conflict.MembershipRole = membership.Role
+			conflict.Details = fmt.Sprintf("staff member is %s but their membership is still active", accountStatusOrInactive(member.AccountStatus))
+			result.Conflicts = append(result.Conflicts, conflict)
+
+		case member.IsActive && membership.Role != expectedRole:
+			conflict.Kind = models.SyncConflictRoleMismatch
+			conflict.MembershipRole = membership.Role
+			conflict.Details = fmt.Sprintf("staff role %q maps to %q but membership role is %q", member.Role, expectedRole, membership.Role)
+			result.Conflicts = append(result.Conflicts, conflict)
+		}
+	}
+
+	for _, m := range memberships {
+		if m.IsActive && !matched[m.UserID] && m.InvitationToken == "" {
+			result.MembershipsWithoutStaff++
+		}
+	}
+
+	if !dryRun {
+		if err := s.recordConflicts(ctx, tenantID, result.Conflicts); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// resolveUserIDs maps staff IDs to local user IDs via their Keycloak user ID
+// Staff without a tenant_users row keep their Keycloak ID, mirroring MembershipService.ResolveUserID.
+func (s *StaffMembershipSyncService) resolveUserIDs(ctx context.Context, staff []clients.StaffMemberInfo) (map[uuid.UUID]uuid.UUID, error) {
+	keycloakIDs := make(map[uuid.UUID]uuid.UUID, len(staff))
+	var lookup []uuid.UUID
+	for _, member := range staff {
+		keycloakID, err := uuid.Parse(member.KeycloakUserID)
+		if err != nil {
+			continue
+		}
+		keycloakIDs[member.ID] = keycloakID
+		lookup = append(lookup, keycloakID)
+	}
+
+	localIDs := make(map[uuid.UUID]uuid.UUID, len(lookup))
+	if len(lookup) > 0 {
+		var users []models.User
+		if err := s.db.WithContext(ctx).
+			Select("id", "keycloak_id").
+			Where("keycloak_id IN ?", lookup).
+			Find(&users).Error; err != nil {
+			return nil, fmt.Errorf("failed to resolve staff users: %w", err)
+		}
+		for _, u := range users {
+			if u.KeycloakID != nil {
+				localIDs[*u.KeycloakID] = u.ID
+			}
+		}
+	}
+
+	resolved := make(map[uuid.UUID]uuid.UUID, len(keycloakIDs))
+	for staffID, keycloakID := range keycloakIDs {
+		if localID, ok := localIDs[keycloakID]; ok {
+			resolved[staffID] = localID
+		} else {
+			resolved[staffID] = keycloakID
+		}
+	}
+	return resolved, nil
+}
+
+func (s *StaffMembershipSyncService) createMembership(ctx context.Context, tenantID, userID uuid.UUID, role string) error {
+	now := time.Now()
+	membership := &models.UserTenantMembership{
+		UserID:     userID,
+		TenantID:   tenantID,
+		Role:       role,
+		IsActive:   true,
+		AcceptedAt: &now, // Staff were onboarded in staff-service; no invitation to accept
+	}
+	if err := s.db.WithContext(ctx).Create(membership).Error; err != nil {
+		return fmt.Errorf("failed to create membership: %w", err)
+	}
+	return nil
+}
+
+// recordConflicts upserts the conflicts found for a tenant and resolves those no longer present
+func (s *StaffMembershipSyncService) recordConflicts(ctx context.Context, tenantID uuid.UUID, conflicts []models.MembershipSyncConflict) error {
+	// Postgres keeps microseconds; truncate so rows seen in this run compare equal to now
+	now := time.Now().Truncate(time.Microsecond)
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range conflicts {
+			c := conflicts[i]
+			c.FirstSeenAt = now
+			c.LastSeenAt = now
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "tenant_id"}, {Name: "staff_id"}, {Name: "kind"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"user_id":         c.UserID,
+					"email":           c.Email,
+					"staff_role":      c.StaffRole,
+					"membership_role": c.MembershipRole,
+					"details":         c.Details,
+					"last_seen_at":    now,
+					"resolved_at":     nil,
+					"occurrences":     gorm.Expr("membership_sync_conflicts.occurrences + 1"),
+					// Restart the clock when a previously resolved conflict comes back
+					"first_seen_at": gorm.Expr("CASE WHEN membership_sync_conflicts.resolved_at IS NULL THEN membership_sync_conflicts.first_seen_at ELSE ? END", now),
+				}),
+			}).Create(&c).Error; err != nil {
+				return fmt.Errorf("failed to record sync conflict: %w", err)
+			}
+		}
+
+		// Anything not seen in this run has been fixed
+		if err := tx.Model(&models.MembershipSyncConflict{}).
+			Where("tenant_id = ? AND resolved_at IS NULL AND last_seen_at < ?", tenantID, now).
+			Update("resolved_at", now).Error; err != nil {
+			return fmt.Errorf("failed to resolve sync conflicts: %w", err)
+		}
+		return nil
+	})
+}
+
+// membershipRoleForStaff maps a staff-service role to a membership role
+func membershipRoleForStaff(staffRole string) string {
+	switch strings.ToLower(strings.TrimSpace(staffRole)) {
+	case "owner", "store_owner":
+		return models.MembershipRoleOwner
+	case "admin", "store_admin", "tenant_admin", "super_admin":
+		return models.MembershipRoleAdmin
+	case "manager", "store_manager":
+		return models.MembershipRoleManager
+	case "viewer", "read_only", "readonly":
+		return models.MembershipRoleViewer
+	default:
+		return models.MembershipRoleMember
+	}
+}
+
+func accountStatusOrInactive(status string) string {
+	if status == "" {
+		return "inactive"
+	}
+	return status
+}
@@ -0,0 +1,587 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/nats"
+	"tenant-service/internal/repository"
+)
+
+const (
+	// MaxTrialDays bounds how long a trial granted through the internal API can run
+	MaxTrialDays = 365
+	// subscriptionChangeFeaturesOverridden is published when only feature overrides changed
+	subscriptionChangeFeaturesOverridden = "features_overridden"
+)
+
+var (
+	// ErrPlanNotFound is returned for unknown or retired plan codes
+	ErrPlanNotFound = errors.New("plan not found")
+	// ErrPlanNotSelfService is returned when an owner picks a plan only sales or billing can assign
+	ErrPlanNotSelfService = errors.New("this plan cannot be selected self-service")
+	// ErrPlanUnchanged is returned when the tenant is already on the requested plan and billing cycle
+	ErrPlanUnchanged = errors.New("tenant is already on this plan")
+	// ErrPlanMemberLimit is returned when a downgrade would leave more members than the plan allows
+	ErrPlanMemberLimit = errors.New("tenant has more members than the plan allows")
+	// ErrSubscriptionAccessForbidden is returned when the user may not view or change the plan
+	ErrSubscriptionAccessForbidden = errors.New("only tenant owners can change the plan and owners or admins view it")
+)
+
+// PlanEventPublisher publishes plan change events (satisfied by *nats.Client)
+type PlanEventPublisher interface {
+	PublishTenantPlanChanged(ctx context.Context, event *nats.TenantPlanChangedEvent) error
+}
+
+// TenantCacheInvalidator drops cached tenant lookups after a write (satisfied by *MembershipService)
+type TenantCacheInvalidator interface {
+	InvalidateTenantCache(ctx context.Context, tenantID uuid.UUID)
+}
+
+// ChangePlanRequest assigns, upgrades or downgrades a tenant's plan
+type ChangePlanRequest struct {
+	PlanCode     string `json:"plan_code" binding:"required"`
+	BillingCycle string `json:"billing_cycle"` // monthly (default) or yearly
+	TrialDays    int    `json:"trial_days"`    // Internal API only: trial the plan, then return to the default plan
+}
+
+// UpdateFeatureOverridesRequest grants (true) or revokes (false) single features for a tenant;
+// null removes the override so the plan decides again
+type UpdateFeatureOverridesRequest struct {
+	Overrides map[string]*bool `json:"overrides" binding:"required"`
+}
+
+// TenantSubscriptionInfo is a tenant's effective plan and feature flags
+type TenantSubscriptionInfo struct {
+	TenantID         uuid.UUID          `json:"tenant_id"`
+	Plan             *models.TenantPlan `json:"plan"`
+	Status           string             `json:"status"`
+	BillingCycle     string             `json:"billing_cycle"`
+	TrialEndsAt      *time.Time         `json:"trial_ends_at,omitempty"`
+	StartedAt        *time.Time         `json:"started_at,omitempty"`
+	FeatureOverrides map[string]bool    `json:"feature_overrides"`
+	Features         map[string]bool    `json:"features"`
+	BillingStatus    string             `json:"billing_status,omitempty"` // Cached from the billing provider; suspended revokes every feature
+}
+
+// FeatureEvaluation answers which features a tenant has, for other services
+type FeatureEvaluation struct {
+	TenantID      uuid.UUID       `json:"tenant_id"`
+	PlanCode      string          `json:"plan_code"`
+	PricingTier   string          `json:"pricing_tier"`
+	Status        string          `json:"status"`
+	BillingStatus string          `json:"billing_status,omitempty"`
+	Features      map[string]bool `json:"features"`
+	EvaluatedAt   time.Time       `json:"evaluated_at"`
+}
+
+// SubscriptionService manages tenant plans, trials and plan feature flags.
+// The plan's pricing tier is mirrored on the tenant so quotas follow plan changes.
+type SubscriptionService struct {
+	db             *gorm.DB
+	membershipRepo *repository.MembershipRepository
+	publisher      PlanEventPublisher
+	cache          TenantCacheInvalidator
+	config         config.SubscriptionConfig
+}
+
+// NewSubscriptionService creates a new subscription service
+func NewSubscriptionService(db *gorm.DB, cfg config.SubscriptionConfig) *SubscriptionService {
+	return &SubscriptionService{
+		db:             db,
+		membershipRepo: repository.NewMembershipRepository(db),
+		config:         cfg,
+	}
+}
+
+// SetEventPublisher enables tenant.plan.changed events
+func (s *SubscriptionService) SetEventPublisher(publisher PlanEventPublisher) {
+	s.publisher = publisher
+}
+
+// SetCacheInvalidator drops cached tenant lookups when the pricing tier changes
+func (s *SubscriptionService) SetCacheInvalidator(cache TenantCacheInvalidator) {
+	s.cache = cache
+}
+
+// TrialCheckInterval returns the interval of the trial expiry job
+func (s *SubscriptionService) TrialCheckInterval() time.Duration {
+	if s.config.TrialCheckIntervalMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(s.config.TrialCheckIntervalMinutes) * time.Minute
+}
+
+// AuthorizeView checks the user is an owner or admin of the tenant
+func (s *SubscriptionService) AuthorizeView(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrSubscriptionAccessForbidden
+	}
+	return nil
+}
+
+// AuthorizeManage checks the user owns the tenant
+func (s *SubscriptionService) AuthorizeManage(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || role != models.MembershipRoleOwner {
+		return ErrSubscriptionAccessForbidden
+	}
+	return nil
+}
+
+// ListPlans returns the active plans, cheapest first
+func (s *SubscriptionService) ListPlans(ctx context.Context) ([]models.TenantPlan, error) {
+	var plans []models.TenantPlan
+	if err := s.db.WithContext(ctx).Where("is_active = ?", true).Order("rank ASC, code ASC").Find(&plans).Error; err != nil {
+		return nil, fmt.Errorf("failed to list plans: %w", err)
+	}
+	return plans, nil
+}
+
+// GetSubscription returns the tenant's effective plan. An expired trial the background job
+// has not processed yet already evaluates as the default plan, and a tenant billing suspended
+// has every feature disabled.
+func (s *SubscriptionService) GetSubscription(ctx context.Context, tenantID uuid.UUID) (*TenantSubscriptionInfo, error) {
+	db := s.db.WithContext(ctx)
+	subscription, plan, err := s.currentPlan(db, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &TenantSubscriptionInfo{
+		TenantID:         tenantID,
+		Plan:             plan,
+		Status:           models.SubscriptionStatusActive,
+		BillingCycle:     models.BillingCycleMonthly,
+		FeatureOverrides: map[string]bool{},
+	}
+	if subscription != nil {
+		info.BillingCycle = subscription.BillingCycle
+		info.StartedAt = &subscription.StartedAt
+		info.FeatureOverrides = subscription.Overrides()
+		if subscription.IsTrialing(time.Now()) {
+			info.Status = models.SubscriptionStatusTrialing
+			info.TrialEndsAt = subscription.TrialEndsAt
+		}
+	}
+	info.Features = mergeFeatures(plan, info.FeatureOverrides)
+
+	var tenant models.Tenant
+	if err := db.Select("id", "billing_status").First(&tenant, "id = ?", tenantID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tenant billing status: %w", err)
+	}
+	info.BillingStatus = tenant.BillingStatus
+	if tenant.IsBillingSuspended() {
+		for key := range info.Features {
+			info.Features[key] = false
+		}
+	}
+	return info, nil
+}
+
+// EvaluateFeatures returns the tenant's effective feature flags
+func (s *SubscriptionService) EvaluateFeatures(ctx context.Context, tenantID uuid.UUID) (*FeatureEvaluation, error) {
+	info, err := s.GetSubscription(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &FeatureEvaluation{
+		TenantID:      tenantID,
+		PlanCode:      info.Plan.Code,
+		PricingTier:   info.Plan.PricingTier,
+		Status:        info.Status,
+		BillingStatus: info.BillingStatus,
+		Features:      info.Features,
+		EvaluatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// ChangePlan moves the tenant to another plan. selfService requests (owners) may only pick
+// self-service plans and cannot start trials; the internal API can assign any active plan.
+func (s *SubscriptionService) ChangePlan(ctx context.Context, tenantID uuid.UUID, req ChangePlanRequest, changedBy *uuid.UUID, selfService bool) (*TenantSubscriptionInfo, error) {
+	req.PlanCode = strings.ToLower(strings.TrimSpace(req.PlanCode))
+	if req.BillingCycle == "" {
+		req.BillingCycle = models.BillingCycleMonthly
+	}
+	if req.BillingCycle != models.BillingCycleMonthly && req.BillingCycle != models.BillingCycleYearly {
+		return nil, NewValidationError("billing_cycle", "billing_cycle must be monthly or yearly", nil)
+	}
+	if req.TrialDays < 0 || req.TrialDays > MaxTrialDays {
+		return nil, NewValidationError("trial_days", fmt.Sprintf("trial_days must be between 0 and %d", MaxTrialDays), nil)
+	}
+	if selfService && req.TrialDays > 0 {
+		return nil, NewValidationError("trial_days", "trials cannot be started self-service", nil)
+	}
+
+	plan, err := s.planByCode(s.db.WithContext(ctx), req.PlanCode)
+	if err != nil {
+		return nil, err
+	}
+	if selfService && !plan.SelfService {
+		return nil, ErrPlanNotSelfService
+	}
+
+	if err := s.applyPlan(ctx, tenantID, plan, req.BillingCycle, req.TrialDays, changedBy, "", true); err != nil {
+		return nil, err
+	}
+	return s.GetSubscription(ctx, tenantID)
+}
+
+// StartTenantSubscription puts a newly created tenant on its first plan: a trial of the trial
+// plan when one is configured and the onboarding template grants trial days, otherwise the
+// default plan
+func (s *SubscriptionService) StartTenantSubscription(ctx context.Context, tenantID uuid.UUID, trialDays int) error {
+	code := s.defaultPlanCode()
+	if s.config.TrialPlan != "" && trialDays > 0 {
+		code = s.config.TrialPlan
+	} else {
+		trialDays = 0
+	}
+	if trialDays > MaxTrialDays {
+		trialDays = MaxTrialDays
+	}
+
+	plan, err := s.planByCode(s.db.WithContext(ctx), code)
+	if err != nil {
+		return fmt.Errorf("failed to load plan %q: %w", code, err)
+	}
+	err = s.applyPlan(ctx, tenantID, plan, models.BillingCycleMonthly, trialDays, nil, models.SubscriptionChangeAssigned, false)
+	if errors.Is(err, ErrPlanUnchanged) {
+		return nil
+	}
+	return err
+}
+
+// ExpireTrials moves tenants whose trial ended to the default plan
+func (s *SubscriptionService) ExpireTrials(ctx context.Context) (int, error) {
+	var expired []models.TenantSubscription
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND trial_ends_at <= ?", models.SubscriptionStatusTrialing, time.Now()).
+		Limit(500).
+		Find(&expired).Error; err != nil {
+		return 0, fmt.Errorf("failed to find expired trials: %w", err)
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	plan, err := s.planByCode(s.db.WithContext(ctx), s.defaultPlanCode())
+	if err != nil {
+		return 0, fmt.Errorf("failed to load default plan: %w", err)
+	}
+
+	moved := 0
+	for _, subscription := range expired {
+		// Members over the default plan's quota are reported by the usage job rather than blocking expiry
+		err := s.applyPlan(ctx, subscription.TenantID, plan, subscription.BillingCycle, 0, nil, models.SubscriptionChangeTrialExpired, false)
+		if err != nil && !errors.Is(err, ErrPlanUnchanged) {
+			log.Printf("[SubscriptionService] Failed to expire trial of tenant %s: %v", subscription.TenantID, err)
+			continue
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// UpdateFeatureOverrides grants or revokes single features for a tenant on top of its plan
+func (s *SubscriptionService) UpdateFeatureOverrides(ctx context.Context, tenantID uuid.UUID, req UpdateFeatureOverridesRequest) (*TenantSubscriptionInfo, error) {
+	for key := range req.Overrides {
+		if !models.IsKnownFeature(key) {
+			return nil, NewValidationError("overrides", fmt.Sprintf("unknown feature %q", key), nil)
+		}
+	}
+
+	var plan *models.TenantPlan
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.lockTenant(tx, tenantID); err != nil {
+			return err
+		}
+		subscription, current, err := s.currentPlan(tx, tenantID)
+		if err != nil {
+			return err
+		}
+		plan = current
+
+		if subscription == nil {
+			// Tenants predating plans get a subscription on the plan of their pricing tier
+			subscription = &models.TenantSubscription{
+				TenantID:     tenantID,
+				PlanID:       current.ID,
+				Status:       models.SubscriptionStatusActive,
+				BillingCycle: models.BillingCycleMonthly,
+				StartedAt:    time.Now(),
+			}
+		}
+
+		overrides := subscription.Overrides()
+		for key, value := range req.Overrides {
+			if value == nil {
+				delete(overrides, key)
+			} else {
+				overrides[key] = *value
+			}
+		}
+		encoded, err := json.Marshal(overrides)
+		if err != nil {
+			return fmt.Errorf("failed to encode feature overrides: %w", err)
+		}
+		subscription.FeatureOverrides = models.JSONB(encoded)
+		subscription.Plan = nil
+
+		if err := tx.Save(subscription).Error; err != nil {
+			return fmt.Errorf("failed to save feature overrides: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[SubscriptionService] Updated feature overrides of tenant %s", tenantID)
+	info, err := s.GetSubscription(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	s.publishPlanChanged(ctx, tenantID, plan, plan.Code, info.Status, subscriptionChangeFeaturesOverridden, info.TrialEndsAt)
+	return info, nil
+}
+
+// applyPlan writes the tenant's subscription and mirrors the plan's pricing tier on the tenant.
+// change is derived from plan ranks when empty.
+func (s *SubscriptionService) applyPlan(ctx context.Context, tenantID uuid.UUID, plan *models.TenantPlan, billingCycle string, trialDays int, changedBy *uuid.UUID, change string, checkMembers bool) error {
+	now := time.Now()
+	var previousCode string
+	var trialEndsAt *time.Time
+	status := models.SubscriptionStatusActive
+	if trialDays > 0 {
+		ends := now.AddDate(0, 0, trialDays)
+		trialEndsAt = &ends
+		status = models.SubscriptionStatusTrialing
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.lockTenant(tx, tenantID); err != nil {
+			return err
+		}
+		subscription, current, err := s.currentPlan(tx, tenantID)
+		if err != nil {
+			return err
+		}
+		previousCode = current.Code
+
+		if subscription != nil && current.ID == plan.ID && subscription.BillingCycle == billingCycle &&
+			trialDays == 0 && subscription.Status == models.SubscriptionStatusActive {
+			return ErrPlanUnchanged
+		}
+
+		if checkMembers && plan.Rank < current.Rank {
+			if err := s.checkMemberLimit(tx, tenantID, plan); err != nil {
+				return err
+			}
+		}
+
+		if change == "" {
+			switch {
+			case trialDays > 0:
+				change = models.SubscriptionChangeTrialStarted
+			case plan.Rank > current.Rank:
+				change = models.SubscriptionChangeUpgrade
+			case plan.Rank < current.Rank:
+				change = models.SubscriptionChangeDowngrade
+			default:
+				change = models.SubscriptionChangeAssigned
+			}
+		}
+
+		if subscription == nil {
+			subscription = &models.TenantSubscription{TenantID: tenantID}
+		}
+		subscription.PlanID = plan.ID
+		subscription.Plan = nil
+		subscription.Status = status
+		subscription.BillingCycle = billingCycle
+		subscription.TrialEndsAt = trialEndsAt
+		subscription.StartedAt = now
+		subscription.ChangedBy = changedBy
+		if err := tx.Save(subscription).Error; err != nil {
+			return fmt.Errorf("failed to save subscription: %w", err)
+		}
+
+		if err := tx.Model(&models.Tenant{}).Where("id = ?", tenantID).Updates(map[string]interface{}{
+			"pricing_tier":            plan.PricingTier,
+			"pricing_tier_updated_at": now,
+			"trial_ends_at":           trialEndsAt,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update tenant pricing tier: %w", err)
+		}
+
+		details, _ := models.NewJSONB(map[string]interface{}{
+			"change":        change,
+			"plan":          plan.Code,
+			"previous_plan": previousCode,
+			"billing_cycle": billingCycle,
+			"trial_ends_at": trialEndsAt,
+		})
+		actor := uuid.Nil
+		if changedBy != nil {
+			actor = *changedBy
+		}
+		if err := tx.Create(&models.TenantActivityLog{
+			TenantID:     tenantID,
+			UserID:       actor,
+			Action:       models.ActivitySubscriptionChanged,
+			ResourceType: "subscription",
+			ResourceID:   &subscription.ID,
+			Details:      details,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to log plan change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[SubscriptionService] Tenant %s plan %s: %s -> %s (status: %s)", tenantID, change, previousCode, plan.Code, status)
+	if s.cache != nil {
+		s.cache.InvalidateTenantCache(ctx, tenantID)
+	}
+	s.publishPlanChanged(ctx, tenantID, plan, previousCode, status, change, trialEndsAt)
+	return nil
+}
+
+// currentPlan loads the tenant's subscription (nil for tenants predating plans) and its
+// effective plan: the default plan once a trial ended, the pricing tier's plan without a subscription
+func (s *SubscriptionService) currentPlan(db *gorm.DB, tenantID uuid.UUID) (*models.TenantSubscription, *models.TenantPlan, error) {
+	var subscription models.TenantSubscription
+	err := db.Preload("Plan").Where("tenant_id = ?", tenantID).First(&subscription).Error
+	switch {
+	case err == nil:
+		if subscription.Status == models.SubscriptionStatusTrialing && !subscription.IsTrialing(time.Now()) {
+			plan, err := s.planByCode(db, s.defaultPlanCode())
+			return &subscription, plan, err
+		}
+		if subscription.Plan == nil {
+			return nil, nil, fmt.Errorf("plan %s of tenant %s not found", subscription.PlanID, tenantID)
+		}
+		return &subscription, subscription.Plan, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	var tenant models.Tenant
+	if err := db.Select("id", "pricing_tier").First(&tenant, "id = ?", tenantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("tenant not found")
+		}
+		return nil, nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	var plan models.TenantPlan
+	err = db.Where("pricing_tier = ? AND is_active = ?", tenant.PricingTier, true).Order("rank ASC").First(&plan).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		defaultPlan, err := s.planByCode(db, s.defaultPlanCode())
+		return nil, defaultPlan, err
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+	return nil, &plan, nil
+}
+
+// checkMemberLimit rejects a downgrade to a plan with fewer seats than active members
+func (s *SubscriptionService) checkMemberLimit(tx *gorm.DB, tenantID uuid.UUID, plan *models.TenantPlan) error {
+	limit := quotaLimit(plan.PricingTier, models.UsageMetricMembers)
+	if limit < 0 {
+		return nil
+	}
+
+	var members int64
+	if err := tx.Model(&models.UserTenantMembership{}).
+		Where("tenant_id = ? AND is_active = ? AND role <> ?", tenantID, true, "customer").
+		Count(&members).Error; err != nil {
+		return fmt.Errorf("failed to count members: %w", err)
+	}
+	if members > limit {
+		return fmt.Errorf("%w: %d members, the %s plan allows %d", ErrPlanMemberLimit, members, plan.Code, limit)
+	}
+	return nil
+}
+
+// lockTenant serializes plan changes of a tenant
+func (s *SubscriptionService) lockTenant(tx *gorm.DB, tenantID uuid.UUID) error {
+	var tenant models.Tenant
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&tenant, "id = ?", tenantID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("tenant not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock tenant: %w", err)
+	}
+	return nil
+}
+
+// planByCode loads an active plan
+func (s *SubscriptionService) planByCode(db *gorm.DB, code string) (*models.TenantPlan, error) {
+	var plan models.TenantPlan
+	err := db.Where("code = ? AND is_active = ?", code, true).First(&plan).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPlanNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+	return &plan, nil
+}
+
+// defaultPlanCode returns the plan of new tenants and expired trials
+func (s *SubscriptionService) defaultPlanCode() string {
+	if s.config.DefaultPlan == "" {
+		return models.PricingTierFree
+	}
+	return s.config.DefaultPlan
+}
+
+// publishPlanChanged publishes tenant.plan.changed; failures are logged, the change is already saved
+func (s *SubscriptionService) publishPlanChanged(ctx context.Context, tenantID uuid.UUID, plan *models.TenantPlan, previousCode, status, change string, trialEndsAt *time.Time) {
+	if s.publisher == nil {
+		return
+	}
+	if err := s.publisher.PublishTenantPlanChanged(ctx, &nats.TenantPlanChangedEvent{
+		TenantID:         tenantID.String(),
+		PlanCode:         plan.Code,
+		PreviousPlanCode: previousCode,
+		PricingTier:      plan.PricingTier,
+		Status:           status,
+		Change:           change,
+		TrialEndsAt:      trialEndsAt,
+	}); err != nil {
+		log.Printf("[SubscriptionService] Failed to publish plan change for tenant %s: %v", tenantID, err)
+	}
+}
+
+// mergeFeatures applies a tenant's overrides to its plan's feature flags
+func mergeFeatures(plan *models.TenantPlan, overrides map[string]bool) map[string]bool {
+	features := map[string]bool{}
+	for key := range models.DefaultPlanFeatures()[models.PricingTierEnterprise] {
+		features[key] = false
+	}
+	for key, enabled := range plan.FeatureFlags() {
+		features[key] = enabled
+	}
+	for key, enabled := range overrides {
+		features[key] = enabled
+	}
+	return features
+}
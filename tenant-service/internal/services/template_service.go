@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"tenant-service/internal/listquery"
 	"tenant-service/internal/models"
 	"tenant-service/internal/repository"
 )
@@ -30,6 +31,9 @@ func (s *TemplateService) CreateTemplate(ctx context.Context, template *models.O
 	if template.ApplicationType == "" {
 		return nil, fmt.Errorf("application type is required")
 	}
+	if err := validateTemplateCustomFields(template); err != nil {
+		return nil, err
+	}
 
 	return s.templateRepo.CreateTemplate(ctx, template)
 }
@@ -55,22 +59,52 @@ func (s *TemplateService) GetActiveTemplates(ctx context.Context) ([]models.Onbo
 }
 
 // UpdateTemplate updates an existing template
+// Settings (application type, active, default) are updated in place. Content changes are
+// published as a new version so sessions already in flight keep the version they started with.
 func (s *TemplateService) UpdateTemplate(ctx context.Context, template *models.OnboardingTemplate) (*models.OnboardingTemplate, error) {
 	// Validate template exists
-	existing, err := s.templateRepo.GetTemplateByID(ctx, template.ID)
+	existing, err := s.loadVersionedTemplate(ctx, template.ID)
 	if err != nil {
 		return nil, fmt.Errorf("template not found: %w", err)
 	}
+	if err := validateTemplateCustomFields(template); err != nil {
+		return nil, err
+	}
+
+	contentChanged := existing.Name != template.Name ||
+		existing.Description != template.Description ||
+		!templateContentEqual(existing.TemplateConfig, template.TemplateConfig) ||
+		!templateContentEqual(existing.CustomFields, template.CustomFields)
+	if contentChanged {
+		draft, err := s.templateRepo.GetDraftVersion(ctx, existing.ID)
+		if err != nil {
+			return nil, err
+		}
+		if draft != nil {
+			return nil, ErrTemplateDraftPending
+		}
+	}
 
-	// Update fields
-	existing.Name = template.Name
-	existing.Description = template.Description
+	// Update settings
 	existing.ApplicationType = template.ApplicationType
-	existing.TemplateConfig = template.TemplateConfig
 	existing.IsActive = template.IsActive
 	existing.IsDefault = template.IsDefault
 
-	return s.templateRepo.UpdateTemplate(ctx, existing)
+	updated, err := s.templateRepo.UpdateTemplate(ctx, existing)
+	if err != nil || !contentChanged {
+		return updated, err
+	}
+
+	version := models.NewTemplateVersion(updated, 0, models.TemplateVersionStatusDraft)
+	version.Name = template.Name
+	version.Description = template.Description
+	version.TemplateConfig = template.TemplateConfig
+	version.CustomFields = template.CustomFields
+	version.ChangeNotes = "Updated directly on the template"
+	if _, err := s.templateRepo.CreateDraftVersion(ctx, version); err != nil {
+		return nil, err
+	}
+	return s.templateRepo.PublishTemplateVersion(ctx, updated, version)
 }
 
 // SetDefaultTemplate sets a template as the default for its application type
@@ -83,16 +117,18 @@ func (s *TemplateService) DeleteTemplate(ctx context.Context, id uuid.UUID) erro
 	return s.templateRepo.DeleteTemplate(ctx, id)
 }
 
-// ListTemplates lists templates with pagination and filtering
-func (s *TemplateService) ListTemplates(ctx context.Context, page, pageSize int, filters map[string]interface{}) ([]models.OnboardingTemplate, int64, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
-	}
+// ListTemplates lists templates with pagination, sorting and filtering
+func (s *TemplateService) ListTemplates(ctx context.Context, q *listquery.Query) ([]models.OnboardingTemplate, int64, string, error) {
+	return s.templateRepo.ListTemplates(ctx, q)
+}
 
-	return s.templateRepo.ListTemplates(ctx, page, pageSize, filters)
+// validateTemplateCustomFields checks the custom field definitions on a template
+func validateTemplateCustomFields(template *models.OnboardingTemplate) error {
+	defs, err := template.CustomFieldDefinitions()
+	if err != nil {
+		return NewValidationError("custom_fields", err.Error(), nil)
+	}
+	return ValidateCustomFieldDefinitions(defs)
 }
 
 // ValidateTemplateConfiguration validates template configuration
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+// Template versioning errors
+var (
+	// ErrTemplateNotFound is returned when the onboarding template does not exist
+	ErrTemplateNotFound = repository.ErrTemplateNotFound
+	// ErrTemplateVersionNotFound is returned when a template version does not exist
+	ErrTemplateVersionNotFound = errors.New("template version not found")
+	// ErrTemplateDraftNotFound is returned when publishing or discarding without a draft
+	ErrTemplateDraftNotFound = errors.New("template has no draft version")
+	// ErrTemplateDraftPending is returned when a template with an open draft is edited in place
+	ErrTemplateDraftPending = errors.New("template has an unpublished draft; update the draft and publish it instead")
+)
+
+// TemplateDraftRequest updates the draft version of a template
+// Omitted fields keep their current draft value, or the published value when the draft is created.
+type TemplateDraftRequest struct {
+	Name           *string      `json:"name,omitempty"`
+	Description    *string      `json:"description,omitempty"`
+	TemplateConfig models.JSONB `json:"template_config,omitempty"`
+	Steps          models.JSONB `json:"steps,omitempty"`
+	CustomFields   models.JSONB `json:"custom_fields,omitempty"`
+	ChangeNotes    *string      `json:"change_notes,omitempty"`
+}
+
+// TemplateFieldChange is a changed scalar or config value between two template versions
+type TemplateFieldChange struct {
+	Field string      `json:"field"` // name, description or template_config.<key>
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// TemplateItemDiff lists added, removed and changed items by their key
+type TemplateItemDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// TemplateVersionDiff describes what changed from one template version to another
+type TemplateVersionDiff struct {
+	TemplateID   uuid.UUID             `json:"template_id"`
+	FromVersion  int                   `json:"from_version"`
+	ToVersion    int                   `json:"to_version"`
+	Changed      bool                  `json:"changed"`
+	Fields       []TemplateFieldChange `json:"fields"`
+	Steps        TemplateItemDiff      `json:"steps"`         // Keyed by step id
+	CustomFields TemplateItemDiff      `json:"custom_fields"` // Keyed by custom field key
+}
+
+// ListVersions returns all versions of a template, newest first
+func (s *TemplateService) ListVersions(ctx context.Context, templateID uuid.UUID) ([]models.OnboardingTemplateVersion, error) {
+	if _, err := s.loadVersionedTemplate(ctx, templateID); err != nil {
+		return nil, err
+	}
+	return s.templateRepo.ListTemplateVersions(ctx, templateID)
+}
+
+// GetVersion returns one version of a template
+func (s *TemplateService) GetVersion(ctx context.Context, templateID uuid.UUID, version int) (*models.OnboardingTemplateVersion, error) {
+	if _, err := s.loadVersionedTemplate(ctx, templateID); err != nil {
+		return nil, err
+	}
+	v, err := s.templateRepo.GetTemplateVersion(ctx, templateID, version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, ErrTemplateVersionNotFound
+	}
+	return v, nil
+}
+
+// SaveDraft creates or updates the draft version of a template
+// A new draft starts from the published content; the live template is not changed until it is published.
+func (s *TemplateService) SaveDraft(ctx context.Context, templateID uuid.UUID, req *TemplateDraftRequest) (*models.OnboardingTemplateVersion, error) {
+	template, err := s.loadVersionedTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := s.templateRepo.GetDraftVersion(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	isNew := draft == nil
+	if isNew {
+		draft = models.NewTemplateVersion(template, 0, models.TemplateVersionStatusDraft)
+	}
+
+	if req.Name != nil {
+		draft.Name = *req.Name
+	}
+	if req.Description != nil {
+		draft.Description = *req.Description
+	}
+	if req.TemplateConfig != nil {
+		draft.TemplateConfig = req.TemplateConfig
+	}
+	if req.Steps != nil {
+		draft.Steps = req.Steps
+	}
+	if req.CustomFields != nil {
+		draft.CustomFields = req.CustomFields
+	}
+	if req.ChangeNotes != nil {
+		draft.ChangeNotes = *req.ChangeNotes
+	}
+
+	if err := validateTemplateVersion(template, draft); err != nil {
+		return nil, err
+	}
+
+	if isNew {
+		return s.templateRepo.CreateDraftVersion(ctx, draft)
+	}
+	return s.templateRepo.UpdateTemplateVersion(ctx, draft)
+}
+
+// DiscardDraft deletes the draft version of a template
+func (s *TemplateService) DiscardDraft(ctx context.Context, templateID uuid.UUID) error {
+	if _, err := s.loadVersionedTemplate(ctx, templateID); err != nil {
+		return err
+	}
+	draft, err := s.templateRepo.GetDraftVersion(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	if draft == nil {
+		return ErrTemplateDraftNotFound
+	}
+	return s.templateRepo.DeleteTemplateVersion(ctx, draft.ID)
+}
+
+// PublishDraft publishes the draft version of a template
+// New sessions start on the published version; sessions already in flight stay on the version they started with.
+func (s *TemplateService) PublishDraft(ctx context.Context, templateID uuid.UUID, changeNotes string) (*models.OnboardingTemplate, error) {
+	template, err := s.loadVersionedTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	draft, err := s.templateRepo.GetDraftVersion(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if draft == nil {
+		return nil, ErrTemplateDraftNotFound
+	}
+	if err := validateTemplateVersion(template, draft); err != nil {
+		return nil, err
+	}
+	if changeNotes != "" {
+		draft.ChangeNotes = changeNotes
+	}
+
+	return s.templateRepo.PublishTemplateVersion(ctx, template, draft)
+}
+
+// DiffVersions compares two versions of a template
+func (s *TemplateService) DiffVersions(ctx context.Context, templateID uuid.UUID, fromVersion, toVersion int) (*TemplateVersionDiff, error) {
+	from, err := s.GetVersion(ctx, templateID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.GetVersion(ctx, templateID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return DiffTemplateVersions(from, to)
+}
+
+// loadVersionedTemplate loads a template and makes sure its published content is recorded as a version
+func (s *TemplateService) loadVersionedTemplate(ctx context.Context, templateID uuid.UUID) (*models.OnboardingTemplate, error) {
+	template, err := s.templateRepo.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.EnsureBaselineVersion(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// validateTemplateVersion checks a version's content before it is saved or published
+func validateTemplateVersion(template *models.OnboardingTemplate, version *models.OnboardingTemplateVersion) error {
+	if version.Name == "" {
+		return NewValidationError("name", "template name is required", nil)
+	}
+	if _, err := decodeTemplateSteps(version.Steps); err != nil {
+		return NewValidationError("steps", err.Error(), nil)
+	}
+	return validateTemplateCustomFields(version.Apply(template))
+}
+
+// DiffTemplateVersions compares the content of two template versions
+func DiffTemplateVersions(from, to *models.OnboardingTemplateVersion) (*TemplateVersionDiff, error) {
+	diff := &TemplateVersionDiff{
+		TemplateID:  to.TemplateID,
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Fields:      []TemplateFieldChange{},
+	}
+
+	if from.Name != to.Name {
+		diff.Fields = append(diff.Fields, TemplateFieldChange{Field: "name", From: from.Name, To: to.Name})
+	}
+	if from.Description != to.Description {
+		diff.Fields = append(diff.Fields, TemplateFieldChange{Field: "description", From: from.Description, To: to.Description})
+	}
+
+	fromConfig, err := decodeTemplateConfig(from.TemplateConfig)
+	if err != nil {
+		return nil, fmt.Errorf("version %d: %w", from.Version, err)
+	}
+	toConfig, err := decodeTemplateConfig(to.TemplateConfig)
+	if err != nil {
+		return nil, fmt.Errorf("version %d: %w", to.Version, err)
+	}
+	for _, key := range unionKeys(fromConfig, toConfig) {
+		if !reflect.DeepEqual(fromConfig[key], toConfig[key]) {
+			diff.Fields = append(diff.Fields, TemplateFieldChange{Field: "template_config." + key, From: fromConfig[key], To: toConfig[key]})
+		}
+	}
+
+	fromSteps, err := decodeTemplateSteps(from.Steps)
+	if err != nil {
+		return nil, fmt.Errorf("version %d: %w", from.Version, err)
+	}
+	toSteps, err := decodeTemplateSteps(to.Steps)
+	if err != nil {
+		return nil, fmt.Errorf("version %d: %w", to.Version, err)
+	}
+	diff.Steps = diffKeyedItems(fromSteps, toSteps)
+
+	fromFields, err := decodeTemplateCustomFields(from.CustomFields)
+	if err != nil {
+		return nil, fmt.Errorf("version %d: %w", from.Version, err)
+	}
+	toFields, err := decodeTemplateCustomFields(to.CustomFields)
+	if err != nil {
+		return nil, fmt.Errorf("version %d: %w", to.Version, err)
+	}
+	diff.CustomFields = diffKeyedItems(fromFields, toFields)
+
+	diff.Changed = len(diff.Fields) > 0 ||
+		len(diff.Steps.Added)+len(diff.Steps.Removed)+len(diff.Steps.Changed) > 0 ||
+		len(diff.CustomFields.Added)+len(diff.CustomFields.Removed)+len(diff.CustomFields.Changed) > 0
+	return diff, nil
+}
+
+// decodeTemplateConfig decodes a template_config object
+func decodeTemplateConfig(data models.JSONB) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	if isEmptyJSON(data) {
+		return config, nil
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("template_config must be an object: %w", err)
+	}
+	return config, nil
+}
+
+// decodeTemplateSteps decodes the steps array keyed by step id (falling back to the step position)
+func decodeTemplateSteps(data models.JSONB) (map[string]interface{}, error) {
+	steps := map[string]interface{}{}
+	if isEmptyJSON(data) {
+		return steps, nil
+	}
+	var list []map[string]interface{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("steps must be an array of objects: %w", err)
+	}
+	for i, step := range list {
+		key, _ := step["id"].(string)
+		if key == "" {
+			key = fmt.Sprintf("#%d", i+1)
+		}
+		if _, exists := steps[key]; exists {
+			return nil, fmt.Errorf("duplicate step id %q", key)
+		}
+		steps[key] = step
+	}
+	return steps, nil
+}
+
+// decodeTemplateCustomFields decodes the custom field definitions keyed by field key
+func decodeTemplateCustomFields(data models.JSONB) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if isEmptyJSON(data) {
+		return fields, nil
+	}
+	var list []map[string]interface{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("custom_fields must be an array of objects: %w", err)
+	}
+	for i, field := range list {
+		key, _ := field["key"].(string)
+		if key == "" {
+			key = fmt.Sprintf("#%d", i+1)
+		}
+		fields[key] = field
+	}
+	return fields, nil
+}
+
+// diffKeyedItems compares two sets of items by key
+func diffKeyedItems(from, to map[string]interface{}) TemplateItemDiff {
+	diff := TemplateItemDiff{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	for _, key := range unionKeys(from, to) {
+		before, inFrom := from[key]
+		after, inTo := to[key]
+		switch {
+		case !inFrom:
+			diff.Added = append(diff.Added, key)
+		case !inTo:
+			diff.Removed = append(diff.Removed, key)
+		case !reflect.DeepEqual(before, after):
+			diff.Changed = append(diff.Changed, key)
+		}
+	}
+	return diff
+}
+
+// unionKeys returns the sorted keys present in either map
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// templateContentEqual reports whether two JSONB values hold the same JSON
+func templateContentEqual(a, b models.JSONB) bool {
+	if isEmptyJSON(a) || isEmptyJSON(b) {
+		return isEmptyJSON(a) && isEmptyJSON(b)
+	}
+	var av, bv interface{}
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+func isEmptyJSON(data models.JSONB) bool {
+	return len(data) == 0 || string(data) == "null"
+}
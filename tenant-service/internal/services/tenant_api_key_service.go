@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+const (
+	// APIKeyPrefix marks tenant API keys so they are recognisable in logs and secret scanners
+	APIKeyPrefix = "tnk_"
+	// MaxActiveAPIKeysPerTenant bounds how many unrevoked keys a tenant can hold
+	MaxActiveAPIKeysPerTenant = 25
+	// MaxAPIKeyRotationGrace bounds how long a rotated key keeps working alongside its replacement
+	MaxAPIKeyRotationGrace = 7 * 24 * time.Hour
+
+	apiKeyDisplayPrefixLength = 12
+	// apiKeyLastUsedResolution avoids a write on every validation of a busy key
+	apiKeyLastUsedResolution = time.Minute
+)
+
+var (
+	// ErrAPIKeyAccessForbidden is returned when the user is not the tenant owner
+	ErrAPIKeyAccessForbidden = errors.New("only the tenant owner can manage API keys")
+	// ErrAPIKeyNotFound is returned when the key does not exist for the tenant
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrAPIKeyRevoked is returned when rotating or revoking a key that is no longer active
+	ErrAPIKeyRevoked = errors.New("API key is revoked or expired")
+	// ErrAPIKeyRotated is returned when rotating a key that already has a replacement
+	ErrAPIKeyRotated = errors.New("API key has already been rotated")
+	// ErrAPIKeyInvalid is returned by Validate for unknown, revoked or expired keys
+	ErrAPIKeyInvalid = errors.New("invalid API key")
+	// ErrAPIKeyInsufficientScope is returned by Validate when the key's scope is too narrow
+	ErrAPIKeyInsufficientScope = errors.New("API key scope does not allow this operation")
+)
+
+// CreateAPIKeyRequest mints a new tenant API key
+type CreateAPIKeyRequest struct {
+	Name          string `json:"name" binding:"required,max=100"`
+	Scope         string `json:"scope" binding:"required"`
+	ExpiresInDays int    `json:"expires_in_days"` // 0 = never expires
+}
+
+// RotateAPIKeyRequest replaces a key, optionally keeping the old one valid for a grace period
+type RotateAPIKeyRequest struct {
+	GracePeriodMinutes int `json:"grace_period_minutes"`
+}
+
+// CreatedAPIKey is returned once when a key is minted or rotated; Key is never retrievable again
+type CreatedAPIKey struct {
+	*models.TenantAPIKey
+	Key string `json:"key"`
+}
+
+// APIKeyValidation describes a valid key to the calling service
+type APIKeyValidation struct {
+	Valid     bool       `json:"valid"`
+	KeyID     uuid.UUID  `json:"key_id"`
+	TenantID  uuid.UUID  `json:"tenant_id"`
+	Scope     string     `json:"scope"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// TenantAPIKeyService manages tenant API keys and validates them for other services
+type TenantAPIKeyService struct {
+	db             *gorm.DB
+	membershipRepo *repository.MembershipRepository
+	usage          *UsageService
+}
+
+// NewTenantAPIKeyService creates a new tenant API key service
+func NewTenantAPIKeyService(db *gorm.DB) *TenantAPIKeyService {
+	return &TenantAPIKeyService{
+		db:             db,
+		membershipRepo: repository.NewMembershipRepository(db),
+	}
+}
+
+// SetUsageService meters validated keys against the tenant's monthly API call quota
+func (s *TenantAPIKeyService) SetUsageService(usage *UsageService) {
+	s.usage = usage
+}
+
+// AuthorizeManage checks the user owns the tenant
+func (s *TenantAPIKeyService) AuthorizeManage(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || role != models.MembershipRoleOwner {
+		return ErrAPIKeyAccessForbidden
+	}
+	return nil
+}
+
+// Create mints a new key for the tenant
+func (s *TenantAPIKeyService) Create(ctx context.Context, tenantID, userID uuid.UUID, req CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, NewValidationError("name", "name is required", nil)
+	}
+	if !models.IsValidAPIKeyScope(req.Scope) {
+		return nil, NewValidationError("scope", "scope must be one of read_only, member_management, full", nil)
+	}
+	if req.ExpiresInDays < 0 {
+		return nil, NewValidationError("expires_in_days", "expires_in_days must be positive", nil)
+	}
+
+	var active int64
+	if err := s.db.WithContext(ctx).
+		Model(&models.TenantAPIKey{}).
+		Where("tenant_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", tenantID, time.Now()).
+		Count(&active).Error; err != nil {
+		return nil, fmt.Errorf("failed to count API keys: %w", err)
+	}
+	if active >= MaxActiveAPIKeysPerTenant {
+		return nil, NewValidationError("name", fmt.Sprintf("a tenant can have at most %d active API keys", MaxActiveAPIKeysPerTenant), nil)
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	created, err := s.mint(s.db.WithContext(ctx), &models.TenantAPIKey{
+		TenantID:  tenantID,
+		Name:      name,
+		Scope:     req.Scope,
+		CreatedBy: userID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APIKeys] Created %s key %s for tenant %s", created.Scope, created.KeyPrefix, tenantID)
+	return created, nil
+}
+
+// List returns the tenant's keys, newest first
+func (s *TenantAPIKeyService) List(ctx context.Context, tenantID uuid.UUID) ([]models.TenantAPIKey, error) {
+	var keys []models.TenantAPIKey
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at DESC").
+		Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Rotate issues a replacement with the same name and scope. The old key is revoked at once,
+// or expires after the requested grace period so integrations can switch over. The old key is
+// locked for the rotation, so concurrent rotations issue one replacement; a key can only be
+// rotated once. A grace period keeps both keys active, so it counts against
+// MaxActiveAPIKeysPerTenant like a new key.
+func (s *TenantAPIKeyService) Rotate(ctx context.Context, tenantID, keyID, userID uuid.UUID, req RotateAPIKeyRequest) (*CreatedAPIKey, error) {
+	grace := time.Duration(req.GracePeriodMinutes) * time.Minute
+	if grace < 0 || grace > MaxAPIKeyRotationGrace {
+		return nil, NewValidationError("grace_period_minutes", "grace_period_minutes must be between 0 and 10080", nil)
+	}
+
+	var created *CreatedAPIKey
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		old, err := s.activeKey(tx.Clauses(clause.Locking{Strength: "UPDATE"}), tenantID, keyID)
+		if err != nil {
+			return err
+		}
+
+		var replacements int64
+		if err := tx.Model(&models.TenantAPIKey{}).Where("rotated_from_id = ?", old.ID).Count(&replacements).Error; err != nil {
+			return fmt.Errorf("failed to check API key rotation: %w", err)
+		}
+		if replacements > 0 {
+			return ErrAPIKeyRotated
+		}
+
+		if grace > 0 {
+			var active int64
+			if err := tx.Model(&models.TenantAPIKey{}).
+				Where("tenant_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", tenantID, time.Now()).
+				Count(&active).Error; err != nil {
+				return fmt.Errorf("failed to count API keys: %w", err)
+			}
+			if active >= MaxActiveAPIKeysPerTenant {
+				return NewValidationError("grace_period_minutes", fmt.Sprintf("a tenant can have at most %d active API keys; rotate without a grace period or revoke a key first", MaxActiveAPIKeysPerTenant), nil)
+			}
+		}
+
+		// Copied before the grace update, which can overwrite old.ExpiresAt in place.
+		// The replacement keeps the original expiry, not the grace deadline
+		var expiresAt *time.Time
+		if old.ExpiresAt != nil {
+			t := *old.ExpiresAt
+			expiresAt = &t
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{}
+		if grace == 0 {
+			updates["revoked_at"] = now
+			updates["revoked_by"] = userID
+		} else if old.ExpiresAt == nil || old.ExpiresAt.After(now.Add(grace)) {
+			updates["expires_at"] = now.Add(grace)
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(old).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to retire API key: %w", err)
+			}
+		}
+
+		created, err = s.mint(tx, &models.TenantAPIKey{
+			TenantID:      tenantID,
+			Name:          old.Name,
+			Scope:         old.Scope,
+			CreatedBy:     userID,
+			RotatedFromID: &old.ID,
+			ExpiresAt:     expiresAt,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APIKeys] Rotated key %s for tenant %s (grace %v)", keyID, tenantID, grace)
+	return created, nil
+}
+
+// Revoke disables a key immediately
+func (s *TenantAPIKeyService) Revoke(ctx context.Context, tenantID, keyID, userID uuid.UUID) error {
+	result := s.db.WithContext(ctx).
+		Model(&models.TenantAPIKey{}).
+		Where("id = ? AND tenant_id = ? AND revoked_at IS NULL", keyID, tenantID).
+		Updates(map[string]interface{}{
+			"revoked_at": time.Now(),
+			"revoked_by": userID,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke API key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		s.db.WithContext(ctx).Model(&models.TenantAPIKey{}).Where("id = ? AND tenant_id = ?", keyID, tenantID).Count(&count)
+		if count == 0 {
+			return ErrAPIKeyNotFound
+		}
+		return ErrAPIKeyRevoked
+	}
+
+	log.Printf("[APIKeys] Revoked key %s for tenant %s", keyID, tenantID)
+	return nil
+}
+
+// Validate resolves a presented key for another service. requiredScope is optional; when set
+// the key must include it. Keys of tenants that are not active (e.g. pending deletion) are rejected,
+// and so are keys of tenants over their API call quota when quotas are enforced.
+func (s *TenantAPIKeyService) Validate(ctx context.Context, key, requiredScope string) (*APIKeyValidation, error) {
+	key = strings.TrimSpace(key)
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if requiredScope != "" && !models.IsValidAPIKeyScope(requiredScope) {
+		return nil, NewValidationError("required_scope", "required_scope must be one of read_only, member_management, full", nil)
+	}
+
+	var apiKey models.TenantAPIKey
+	err := s.db.WithContext(ctx).Where("key_hash = ?", hashAPIKey(key)).First(&apiKey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up API key: %w", err)
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	var tenant models.Tenant
+	if err := s.db.WithContext(ctx).Select("id", "status", "pricing_tier").First(&tenant, "id = ?", apiKey.TenantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyInvalid
+		}
+		return nil, fmt.Errorf("failed to look up API key tenant: %w", err)
+	}
+	if tenant.Status != "active" {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	if requiredScope != "" && !models.APIKeyScopeAllows(apiKey.Scope, requiredScope) {
+		return nil, ErrAPIKeyInsufficientScope
+	}
+
+	if s.usage != nil {
+		if err := s.usage.MeterAPICall(ctx, apiKey.TenantID, tenant.PricingTier); err != nil {
+			return nil, err
+		}
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedResolution {
+		if err := s.db.WithContext(ctx).Model(&apiKey).UpdateColumn("last_used_at", now).Error; err != nil {
+			log.Printf("[APIKeys] Failed to record use of key %s: %v", apiKey.ID, err)
+		}
+	}
+
+	return &APIKeyValidation{
+		Valid:     true,
+		KeyID:     apiKey.ID,
+		TenantID:  apiKey.TenantID,
+		Scope:     apiKey.Scope,
+		ExpiresAt: apiKey.ExpiresAt,
+	}, nil
+}
+
+// activeKey loads an unrevoked, unexpired key of the tenant
+func (s *TenantAPIKeyService) activeKey(db *gorm.DB, tenantID, keyID uuid.UUID) (*models.TenantAPIKey, error) {
+	var key models.TenantAPIKey
+	err := db.Where("id = ? AND tenant_id = ?", keyID, tenantID).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if !key.IsActive(time.Now()) {
+		return nil, ErrAPIKeyRevoked
+	}
+	return &key, nil
+}
+
+// mint generates the secret for key, stores its hash and returns the plaintext once
+func (s *TenantAPIKeyService) mint(db *gorm.DB, key *models.TenantAPIKey) (*CreatedAPIKey, error) {
+	plaintext, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key.KeyPrefix = plaintext[:apiKeyDisplayPrefixLength]
+	key.KeyHash = hashAPIKey(plaintext)
+
+	if err := db.Create(key).Error; err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+	return &CreatedAPIKey{TenantAPIKey: key, Key: plaintext}, nil
+}
+
+func generateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tesseract-Nexus/go-shared/auth"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tenant-service/internal/clients"
+	"tenant-service/internal/models"
+)
+
+var (
+	// ErrEmailChangeNoMembership is returned when the user is not an active member of the tenant
+	ErrEmailChangeNoMembership = errors.New("user is not an active member of this tenant")
+	// ErrEmailChangeInvalidToken is returned for unknown, expired, cancelled or already used links
+	ErrEmailChangeInvalidToken = errors.New("invalid or expired email change link")
+)
+
+// RequestEmailChangeInput represents a user's request to change their login email
+type RequestEmailChangeInput struct {
+	UserID          uuid.UUID // JWT subject (Keycloak or local user ID)
+	TenantID        uuid.UUID
+	NewEmail        string
+	CurrentPassword string
+	IPAddress       string
+	UserAgent       string
+}
+
+// EmailChangeRequestResult describes a pending email change
+type EmailChangeRequestResult struct {
+	NewEmail  string    `json:"new_email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ConfirmEmailChangeInput represents the confirmation of an email change via the emailed link
+type ConfirmEmailChangeInput struct {
+	Token     string
+	IPAddress string
+	UserAgent string
+}
+
+// EmailChangeConfirmResult describes a completed email change
+type EmailChangeConfirmResult struct {
+	Email              string `json:"email"`
+	MembershipsUpdated int64  `json:"memberships_updated"`
+}
+
+// NormalizeNewEmail trims and lowercases the requested address and checks it differs from the current one
+func NormalizeNewEmail(currentEmail, requested string) (string, error) {
+	email := strings.ToLower(strings.TrimSpace(requested))
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return "", NewValidationError("new_email", "Enter a valid email address", nil)
+	}
+	if strings.EqualFold(email, strings.TrimSpace(currentEmail)) {
+		return "", NewValidationError("new_email", "The new email is the same as the current one", nil)
+	}
+	return email, nil
+}
+
+// RequestEmailChange verifies the user's password and sends a confirmation link to the new address.
+// Nothing changes until the link is followed, so the current address keeps working; a new request
+// cancels any pending one.
+func (s *TenantAuthService) RequestEmailChange(ctx context.Context, input *RequestEmailChangeInput) (*EmailChangeRequestResult, error) {
+	user, err := s.GetUserByKeycloakOrLocalID(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	membership, err := s.membershipRepo.GetMembership(ctx, user.ID, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil || !membership.IsActive {
+		return nil, ErrEmailChangeNoMembership
+	}
+
+	newEmail, err := NormalizeNewEmail(user.Email, input.NewEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.KeycloakID == nil {
+		return nil, fmt.Errorf("user does not have a Keycloak account")
+	}
+	if s.keycloakClient == nil || s.keycloakConfig == nil {
+		return nil, fmt.Errorf("authentication service not properly configured")
+	}
+
+	// Re-authenticate: a hijacked session alone must not be enough to take over the account
+	if _, err := s.keycloakClient.GetTokenWithPassword(ctx, s.keycloakConfig.ClientID, s.keycloakConfig.ClientSecret, user.Email, input.CurrentPassword); err != nil {
+		s.logEmailChangeEvent(ctx, input.TenantID, user.ID, models.AuthEventEmailChangeRequested, models.AuthEventStatusFailed, input.IPAddress, input.UserAgent, map[string]interface{}{
+			"new_email": newEmail,
+			"reason":    "invalid_password",
+		})
+		return nil, NewValidationError("current_password", "Current password is incorrect", nil)
+	}
+
+	if err := s.checkEmailAvailable(ctx, s.db, newEmail, user); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.membershipRepo.GetTenantByID(ctx, input.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	rawToken, hashedToken, err := generateSecureToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	record := &models.EmailChangeToken{
+		Token:          hashedToken,
+		UserID:         user.ID,
+		TenantID:       input.TenantID,
+		OldEmail:       user.Email,
+		NewEmail:       newEmail,
+		ExpiresAt:      time.Now().Add(models.DefaultEmailChangeTokenExpiry),
+		RequestedIP:    input.IPAddress,
+		RequestedAgent: input.UserAgent,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.EmailChangeToken{}).
+			Where("user_id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL", user.ID).
+			Update("cancelled_at", time.Now()).Error; err != nil {
+			return fmt.Errorf("failed to cancel pending email changes: %w", err)
+		}
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email change request: %w", err)
+	}
+
+	if s.notificationClient != nil {
+		storeName := tenant.Name
+		if storeName == "" {
+			storeName = tenant.Slug
+		}
+		if err := s.notificationClient.SendEmailChangeVerificationEmail(ctx, &clients.EmailChangeVerificationEmailData{
+			Email:       newEmail,
+			FirstName:   user.FirstName,
+			StoreName:   storeName,
+			ConfirmLink: emailChangeConfirmLink(tenant.Slug, membership.Role, rawToken),
+			ExpiresAt:   record.ExpiresAt,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to send confirmation email: %w", err)
+		}
+	}
+
+	s.logEmailChangeEvent(ctx, input.TenantID, user.ID, models.AuthEventEmailChangeRequested, models.AuthEventStatusSuccess, input.IPAddress, input.UserAgent, map[string]interface{}{
+		"new_email": newEmail,
+	})
+	log.Printf("[TenantAuthService] Email change requested for user %s", user.ID)
+
+	return &EmailChangeRequestResult{NewEmail: newEmail, ExpiresAt: record.ExpiresAt}, nil
+}
+
+// ConfirmEmailChange applies a pending email change. The user, all their memberships and the
+// audit entries are updated in one transaction, and Keycloak is updated before it commits; if
+// the commit fails afterwards, Keycloak is reverted to the previous address.
+func (s *TenantAuthService) ConfirmEmailChange(ctx context.Context, input *ConfirmEmailChangeInput) (*EmailChangeConfirmResult, error) {
+	if s.keycloakClient == nil {
+		return nil, fmt.Errorf("authentication service not properly configured")
+	}
+
+	var (
+		record     models.EmailChangeToken
+		user       models.User
+		updated    int64
+		previousKC *auth.UserRepresentation
+	)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token = ?", hashToken(input.Token)).
+			First(&record).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEmailChangeInvalidToken
+			}
+			return fmt.Errorf("failed to lookup email change: %w", err)
+		}
+		if !record.IsPending(time.Now()) {
+			return ErrEmailChangeInvalidToken
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", record.UserID).Error; err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		// The email changed some other way since the request
+		if !strings.EqualFold(user.Email, record.OldEmail) {
+			return ErrEmailChangeInvalidToken
+		}
+		if user.KeycloakID == nil {
+			return fmt.Errorf("user does not have a Keycloak account")
+		}
+		if err := s.checkEmailAvailable(ctx, tx, record.NewEmail, &user); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&user).Update("email", record.NewEmail).Error; err != nil {
+			return fmt.Errorf("failed to update user email: %w", err)
+		}
+		result := tx.Model(&models.UserTenantMembership{}).
+			Where("user_id = ? AND invited_email <> ''", user.ID).
+			Update("invited_email", record.NewEmail)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update memberships: %w", result.Error)
+		}
+		updated = result.RowsAffected
+
+		now := time.Now()
+		if err := tx.Model(&record).Updates(map[string]interface{}{
+			"confirmed_at":    now,
+			"confirmed_ip":    input.IPAddress,
+			"confirmed_agent": input.UserAgent,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to mark email change confirmed: %w", err)
+		}
+
+		// Every tenant the user belongs to gets the change in its auth audit trail
+		var tenantIDs []uuid.UUID
+		if err := tx.Model(&models.UserTenantMembership{}).
+			Where("user_id = ?", user.ID).
+			Distinct().Pluck("tenant_id", &tenantIDs).Error; err != nil {
+			return fmt.Errorf("failed to list user tenants: %w", err)
+		}
+		if !containsUUID(tenantIDs, record.TenantID) {
+			tenantIDs = append(tenantIDs, record.TenantID)
+		}
+		for _, tenantID := range tenantIDs {
+			auditLog := &models.TenantAuthAuditLog{
+				TenantID:    tenantID,
+				UserID:      &user.ID,
+				EventType:   models.AuthEventEmailChanged,
+				EventStatus: models.AuthEventStatusSuccess,
+				IPAddress:   input.IPAddress,
+				UserAgent:   input.UserAgent,
+				Details: models.MustNewJSONB(map[string]interface{}{
+					"old_email":           record.OldEmail,
+					"new_email":           record.NewEmail,
+					"requested_in_tenant": record.TenantID,
+				}),
+			}
+			if err := tx.Create(auditLog).Error; err != nil {
+				return fmt.Errorf("failed to log email change: %w", err)
+			}
+		}
+
+		// Keycloak last, so any failure above leaves it untouched
+		kcUser, err := s.keycloakClient.GetUserByID(ctx, user.KeycloakID.String())
+		if err != nil {
+			return fmt.Errorf("failed to get Keycloak user: %w", err)
+		}
+		if kcUser == nil {
+			return fmt.Errorf("keycloak user %s not found", user.KeycloakID)
+		}
+		previous := *kcUser
+		changed := *kcUser
+		changed.Email = record.NewEmail
+		changed.EmailVerified = true
+		// Usernames are the signup email; keep them in step so the old address can be reused
+		if strings.EqualFold(changed.Username, record.OldEmail) {
+			changed.Username = record.NewEmail
+		}
+		if err := s.keycloakClient.UpdateUser(ctx, user.KeycloakID.String(), changed); err != nil {
+			return fmt.Errorf("failed to update email in Keycloak: %w", err)
+		}
+		previousKC = &previous
+		return nil
+	})
+	if err != nil {
+		if previousKC != nil {
+			// The commit failed after Keycloak was updated
+			if revertErr := s.keycloakClient.UpdateUser(ctx, user.KeycloakID.String(), *previousKC); revertErr != nil {
+				log.Printf("[TenantAuthService] CRITICAL: Failed to revert Keycloak email of user %s after a failed commit: %v", user.ID, revertErr)
+			}
+		}
+		return nil, err
+	}
+
+	s.membershipRepo.InvalidateUserMembershipsCache(ctx, user.ID)
+
+	if s.notificationClient != nil {
+		storeName := ""
+		if tenant, err := s.membershipRepo.GetTenantByID(ctx, record.TenantID); err == nil {
+			storeName = tenant.Name
+		}
+		if err := s.notificationClient.SendEmailChangedNoticeEmail(ctx, &clients.EmailChangedNoticeEmailData{
+			Email:     record.OldEmail,
+			NewEmail:  record.NewEmail,
+			FirstName: user.FirstName,
+			StoreName: storeName,
+		}); err != nil {
+			log.Printf("[TenantAuthService] Warning: Failed to send email changed notice: %v", err)
+		}
+	}
+
+	log.Printf("[TenantAuthService] Email changed for user %s (%d memberships updated)", user.ID, updated)
+	return &EmailChangeConfirmResult{Email: record.NewEmail, MembershipsUpdated: updated}, nil
+}
+
+// checkEmailAvailable rejects addresses already used by another local or Keycloak user
+func (s *TenantAuthService) checkEmailAvailable(ctx context.Context, db *gorm.DB, email string, user *models.User) error {
+	var count int64
+	if err := db.WithContext(ctx).Model(&models.User{}).
+		Where("LOWER(email) = ? AND id <> ?", email, user.ID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+	if count > 0 {
+		return NewConflictError("email", "this email is already used by another account")
+	}
+
+	if s.keycloakClient != nil {
+		kcUser, err := s.keycloakClient.GetUserByEmail(ctx, email)
+		if err != nil {
+			return fmt.Errorf("failed to check email in Keycloak: %w", err)
+		}
+		if kcUser != nil && (user.KeycloakID == nil || kcUser.ID != user.KeycloakID.String()) {
+			return NewConflictError("email", "this email is already used by another account")
+		}
+	}
+	return nil
+}
+
+func (s *TenantAuthService) logEmailChangeEvent(ctx context.Context, tenantID, userID uuid.UUID, eventType, status, ipAddress, userAgent string, details map[string]interface{}) {
+	auditLog := &models.TenantAuthAuditLog{
+		TenantID:    tenantID,
+		UserID:      &userID,
+		EventType:   eventType,
+		EventStatus: status,
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+		Details:     models.MustNewJSONB(details),
+	}
+	if err := s.credentialRepo.LogAuthEvent(ctx, auditLog); err != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to log email change event: %v", err)
+	}
+}
+
+// emailChangeConfirmLink points customers at the storefront and everyone else at the admin portal
+func emailChangeConfirmLink(tenantSlug, role, rawToken string) string {
+	baseDomain := os.Getenv("BASE_DOMAIN")
+	if baseDomain == "" {
+		baseDomain = "tesserix.app"
+	}
+	if role == "customer" {
+		return fmt.Sprintf("https://%s-store.%s/account/confirm-email?token=%s", tenantSlug, baseDomain, rawToken)
+	}
+	return fmt.Sprintf("https://%s-admin.%s/account/confirm-email?token=%s", tenantSlug, baseDomain, rawToken)
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
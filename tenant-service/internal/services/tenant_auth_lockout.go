@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+	natsClient "tenant-service/internal/nats"
+)
+
+// AccountStatus is the lockout state shown to a user before they enter their password
+type AccountStatus struct {
+	AccountExists     bool       `json:"account_exists"`
+	AccountLocked     bool       `json:"account_locked"`
+	LockedUntil       *time.Time `json:"locked_until,omitempty"`
+	RemainingAttempts int        `json:"remaining_attempts"`
+}
+
+// SetLoginAttemptGuard replaces the default in-memory login attempt guard (e.g. to share state through Redis)
+func (s *TenantAuthService) SetLoginAttemptGuard(guard *LoginAttemptGuard) {
+	s.attemptGuard = guard
+}
+
+// GetAccountStatus reports whether the email is locked out of the tenant from ipAddress, combining
+// the tenant+email+IP guard with the account's own lockout. It never counts as a login attempt.
+func (s *TenantAuthService) GetAccountStatus(ctx context.Context, email, tenantSlug, ipAddress string) (*AccountStatus, error) {
+	tenant, err := s.membershipRepo.GetTenantBySlug(ctx, tenantSlug)
+	if err != nil {
+		return &AccountStatus{}, nil
+	}
+
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth policy: %w", err)
+	}
+	attempt := s.attemptGuard.Status(ctx, tenant.ID, email, ipAddress, policy.LockoutSettings())
+	status := &AccountStatus{
+		AccountLocked:     attempt.Locked,
+		LockedUntil:       attempt.LockedUntil,
+		RemainingAttempts: attempt.RemainingAttempts,
+	}
+
+	var user models.User
+	if err := s.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return status, nil
+	}
+	membership, err := s.membershipRepo.GetMembership(ctx, user.ID, tenant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check membership: %w", err)
+	}
+	if membership == nil || !membership.IsActive {
+		return status, nil
+	}
+	status.AccountExists = true
+
+	isLocked, lockedUntil, remainingAttempts, err := s.credentialRepo.CheckAccountLockout(ctx, user.ID, tenant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check lockout: %w", err)
+	}
+	if isLocked {
+		status.AccountLocked = true
+		if lockedUntil == nil || status.LockedUntil == nil || lockedUntil.After(*status.LockedUntil) {
+			status.LockedUntil = lockedUntil
+		}
+	}
+	if status.AccountLocked {
+		status.RemainingAttempts = 0
+	} else if remainingAttempts < status.RemainingAttempts {
+		status.RemainingAttempts = remainingAttempts
+	}
+	return status, nil
+}
+
+// recordFailedAttempt counts a failed login against the tenant+email+IP guard and reflects the
+// result in resp, keeping the lower of the account's and the guard's remaining attempts
+func (s *TenantAuthService) recordFailedAttempt(ctx context.Context, tenantID uuid.UUID, req *ValidateCredentialsRequest, lockout models.LockoutSettings, resp *ValidateCredentialsResponse) *ValidateCredentialsResponse {
+	attempt := s.attemptGuard.RecordFailure(ctx, tenantID, req.Email, req.IPAddress, lockout)
+	if attempt.Locked {
+		resp.AccountLocked = true
+		resp.LockedUntil = attempt.LockedUntil
+		resp.RemainingAttempts = 0
+	} else if attempt.RemainingAttempts < resp.RemainingAttempts {
+		resp.RemainingAttempts = attempt.RemainingAttempts
+	}
+
+	publishSecurityEvent(s.securityEvents, loginSecurityEvent(natsClient.EventSecurityLoginFailed, tenantID, req, resp.ErrorCode))
+	if attempt.Locked {
+		// Locked clients are turned away before reaching here, so this is the attempt that locked it
+		event := loginSecurityEvent(natsClient.EventSecurityAccountLocked, tenantID, req, "TOO_MANY_ATTEMPTS")
+		event.Metadata["tier"] = attempt.Tier
+		if attempt.LockedUntil != nil {
+			event.Metadata["locked_until"] = attempt.LockedUntil.UTC().Format(time.RFC3339)
+		}
+		publishSecurityEvent(s.securityEvents, event)
+	}
+	return resp
+}
+
+// loginSecurityEvent builds a failed login or lockout security event for a credentials request
+func loginSecurityEvent(eventType string, tenantID uuid.UUID, req *ValidateCredentialsRequest, reason string) *natsClient.SecurityEvent {
+	return &natsClient.SecurityEvent{
+		EventType:  eventType,
+		TenantID:   tenantID.String(),
+		Outcome:    natsClient.SecurityOutcomeFailure,
+		ActorEmail: req.Email,
+		IPAddress:  req.IPAddress,
+		UserAgent:  req.UserAgent,
+		Reason:     reason,
+		Metadata:   map[string]interface{}{"auth_context": req.AuthContext},
+	}
+}
@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+)
+
+const (
+	// mfaRecoveryCodeCount is the number of single-use recovery codes issued per enrollment
+	mfaRecoveryCodeCount = 10
+	// mfaRecoveryCodeHalf is the number of characters on each side of the recovery code dash
+	mfaRecoveryCodeHalf = 5
+	// mfaRecoveryAlphabet avoids characters that are easily confused when typed (0/o, 1/l/i)
+	mfaRecoveryAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+)
+
+var (
+	// ErrMFAAlreadyEnabled is returned when enrolling a user whose MFA is already active
+	ErrMFAAlreadyEnabled = errors.New("MFA is already enabled; disable it before enrolling again")
+	// ErrMFANotEnrolled is returned when confirming MFA before an enrollment was started
+	ErrMFANotEnrolled = errors.New("no MFA enrollment in progress")
+	// ErrMFANotEnabled is returned when an operation requires active MFA
+	ErrMFANotEnabled = errors.New("MFA is not enabled")
+	// ErrInvalidMFACode is returned when a TOTP or recovery code does not verify
+	ErrInvalidMFACode = errors.New("invalid MFA code")
+	// ErrMFARequiredByPolicy is returned when disabling MFA the tenant's auth policy requires
+	ErrMFARequiredByPolicy = errors.New("MFA is required by the tenant's security policy and cannot be disabled")
+	// ErrMFANoMembership is returned when the user is not an active member of the tenant
+	ErrMFANoMembership = errors.New("user does not have access to this tenant")
+)
+
+// MFAStatus describes a user's MFA state for a tenant
+type MFAStatus struct {
+	Enabled                bool       `json:"enabled"`
+	Type                   string     `json:"type,omitempty"`
+	EnrollmentPending      bool       `json:"enrollment_pending"`
+	RequiredByPolicy       bool       `json:"required_by_policy"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+	LastUsedAt             *time.Time `json:"last_used_at,omitempty"`
+}
+
+// MFAEnrollment is returned when TOTP enrollment starts; the secret is shown only once
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+	Issuer     string `json:"issuer"`
+	Account    string `json:"account"`
+	Digits     int    `json:"digits"`
+	Period     int    `json:"period"`
+}
+
+// MFAVerifyResult is returned by VerifyMFA; recovery codes are only present when enrollment completes
+type MFAVerifyResult struct {
+	Verified      bool     `json:"verified"`
+	Enabled       bool     `json:"enabled"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// GetMFAStatus returns the user's MFA state for a tenant
+func (s *TenantAuthService) GetMFAStatus(ctx context.Context, userID, tenantID uuid.UUID) (*MFAStatus, error) {
+	user, credential, err := s.loadMFACredential(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MFAStatus{}
+	if credential != nil {
+		status.Enabled = credential.MFAEnabled
+		status.Type = credential.MFAType
+		status.EnrollmentPending = !credential.MFAEnabled && credential.MFASecret != ""
+		status.RecoveryCodesRemaining = len(decodeRecoveryCodeHashes(credential.MFABackupCodes))
+		status.LastUsedAt = credential.MFALastUsedAt
+	}
+
+	required, err := s.mfaRequiredForUser(ctx, user.ID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	status.RequiredByPolicy = required
+	return status, nil
+}
+
+// EnrollTOTP starts TOTP enrollment by storing a new encrypted secret; MFA is enabled once VerifyMFA confirms a code
+func (s *TenantAuthService) EnrollTOTP(ctx context.Context, userID, tenantID uuid.UUID) (*MFAEnrollment, error) {
+	if s.keyService == nil {
+		return nil, ErrMasterKeyNotConfigured
+	}
+
+	user, credential, err := s.loadMFACredential(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if credential != nil && credential.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if credential == nil {
+		if _, err := s.credentialRepo.CreateCredentialWithoutPassword(ctx, user.ID, tenantID, &user.ID); err != nil {
+			return nil, fmt.Errorf("failed to create credential record: %w", err)
+		}
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, keyVersion, err := s.keyService.Encrypt(ctx, tenantID, secret, user.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt MFA secret: %w", err)
+	}
+	if err := s.credentialRepo.SavePendingMFASecret(ctx, user.ID, tenantID, models.MFATypeTOTP, encrypted, keyVersion); err != nil {
+		return nil, err
+	}
+
+	issuer := "Tesseract Hub"
+	if tenant, err := s.membershipRepo.GetTenantByID(ctx, tenantID); err == nil && tenant != nil && tenant.Name != "" {
+		issuer = tenant.Name
+	}
+
+	return &MFAEnrollment{
+		Secret:     secret,
+		OTPAuthURL: TOTPProvisioningURI(issuer, user.Email, secret),
+		Issuer:     issuer,
+		Account:    user.Email,
+		Digits:     TOTPDigits,
+		Period:     int(TOTPPeriod / time.Second),
+	}, nil
+}
+
+// VerifyMFA confirms a pending enrollment (returning recovery codes) or verifies a code for an enrolled user
+func (s *TenantAuthService) VerifyMFA(ctx context.Context, userID, tenantID uuid.UUID, code string) (*MFAVerifyResult, error) {
+	user, credential, err := s.loadMFACredential(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil || credential.MFASecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	if credential.MFAEnabled {
+		if err := s.verifySecondFactor(ctx, credential, code); err != nil {
+			return nil, err
+		}
+		return &MFAVerifyResult{Verified: true, Enabled: true}, nil
+	}
+
+	// Pending enrollment: only a TOTP code proves the authenticator was set up
+	secret, err := s.decryptMFASecret(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		s.logMFAEvent(ctx, tenantID, user.ID, models.AuthEventMFAFailed, models.AuthEventStatusFailed, "enrollment")
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	activated, err := s.credentialRepo.ActivateMFA(ctx, user.ID, tenantID, hashes, TOTPStepStart(step))
+	if err != nil {
+		return nil, err
+	}
+	if !activated {
+		return nil, ErrMFANotEnrolled
+	}
+
+	s.logMFAEvent(ctx, tenantID, user.ID, models.AuthEventMFAEnabled, models.AuthEventStatusSuccess, models.MFATypeTOTP)
+	return &MFAVerifyResult{Verified: true, Enabled: true, RecoveryCodes: codes}, nil
+}
+
+// DisableMFAWithCode turns MFA off after verifying a TOTP or recovery code, unless the tenant policy requires it
+func (s *TenantAuthService) DisableMFAWithCode(ctx context.Context, userID, tenantID uuid.UUID, code string) error {
+	user, credential, err := s.loadMFACredential(ctx, userID, tenantID)
+	if err != nil {
+		return err
+	}
+	if credential == nil || !credential.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+
+	required, err := s.mfaRequiredForUser(ctx, user.ID, tenantID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMFARequiredByPolicy
+	}
+
+	if err := s.verifySecondFactor(ctx, credential, code); err != nil {
+		return err
+	}
+	if err := s.credentialRepo.DisableMFA(ctx, user.ID, tenantID); err != nil {
+		return err
+	}
+
+	s.logMFAEvent(ctx, tenantID, user.ID, models.AuthEventMFADisabled, models.AuthEventStatusSuccess, credential.MFAType)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after verifying a TOTP code
+func (s *TenantAuthService) RegenerateRecoveryCodes(ctx context.Context, userID, tenantID uuid.UUID, code string) ([]string, error) {
+	_, credential, err := s.loadMFACredential(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil || !credential.MFAEnabled {
+		return nil, ErrMFANotEnabled
+	}
+	if isRecoveryCodeFormat(strings.TrimSpace(code)) {
+		return nil, NewValidationError("code", "a code from the authenticator app is required to regenerate recovery codes", nil)
+	}
+	if err := s.verifySecondFactor(ctx, credential, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.credentialRepo.SetMFABackupCodes(ctx, credential.UserID, tenantID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// verifySecondFactor checks a TOTP code (rejecting replays) or consumes a recovery code
+func (s *TenantAuthService) verifySecondFactor(ctx context.Context, credential *models.TenantCredential, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ErrInvalidMFACode
+	}
+
+	verified := false
+	method := models.MFATypeTOTP
+	if isRecoveryCodeFormat(code) {
+		method = "recovery_code"
+		consumed, err := s.credentialRepo.ConsumeMFABackupCode(ctx, credential.UserID, credential.TenantID, hashRecoveryCode(code))
+		if err != nil {
+			return err
+		}
+		verified = consumed
+	} else {
+		secret, err := s.decryptMFASecret(ctx, credential)
+		if err != nil {
+			return err
+		}
+		if step, ok := ValidateTOTP(secret, code, time.Now()); ok {
+			claimed, err := s.credentialRepo.ClaimMFATimeStep(ctx, credential.UserID, credential.TenantID, TOTPStepStart(step))
+			if err != nil {
+				return err
+			}
+			verified = claimed
+		}
+	}
+
+	if !verified {
+		s.logMFAEvent(ctx, credential.TenantID, credential.UserID, models.AuthEventMFAFailed, models.AuthEventStatusFailed, method)
+		return ErrInvalidMFACode
+	}
+	s.logMFAEvent(ctx, credential.TenantID, credential.UserID, models.AuthEventMFAVerified, models.AuthEventStatusSuccess, method)
+	return nil
+}
+
+// mfaRequiredForUser reports whether the tenant's auth policy requires MFA for the user's role
+func (s *TenantAuthService) mfaRequiredForUser(ctx context.Context, userID, tenantID uuid.UUID) (bool, error) {
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return false, err
+	}
+	if policy == nil {
+		return false, nil
+	}
+	role := ""
+	if membership, err := s.membershipRepo.GetMembership(ctx, userID, tenantID); err == nil && membership != nil {
+		role = membership.Role
+	}
+	return MFARequiredByPolicy(policy, role), nil
+}
+
+// MFARequiredByPolicy reports whether the policy requires MFA for every user or for the given role
+func MFARequiredByPolicy(policy *models.TenantAuthPolicy, role string) bool {
+	if policy == nil {
+		return false
+	}
+	if policy.MFARequired {
+		return true
+	}
+	if role == "" || len(policy.MFARequiredForRoles) == 0 {
+		return false
+	}
+	var roles []string
+	if err := json.Unmarshal(policy.MFARequiredForRoles, &roles); err != nil {
+		return false
+	}
+	for _, r := range roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadMFACredential resolves the local user (the JWT subject may be a Keycloak ID), checks their
+// membership of the tenant and returns their tenant credential
+func (s *TenantAuthService) loadMFACredential(ctx context.Context, userID, tenantID uuid.UUID) (*models.User, *models.TenantCredential, error) {
+	user, err := s.GetUserByKeycloakOrLocalID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	membership, err := s.membershipRepo.GetMembership(ctx, user.ID, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if membership == nil || !membership.IsActive {
+		return nil, nil, ErrMFANoMembership
+	}
+	credential, err := s.credentialRepo.GetCredential(ctx, user.ID, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, credential, nil
+}
+
+func (s *TenantAuthService) decryptMFASecret(ctx context.Context, credential *models.TenantCredential) (string, error) {
+	if credential.KeyVersion == 0 {
+		// Legacy plaintext row, encrypted by the next re-encryption pass
+		return credential.MFASecret, nil
+	}
+	if s.keyService == nil {
+		return "", ErrMasterKeyNotConfigured
+	}
+	return s.keyService.Decrypt(ctx, credential.TenantID, credential.KeyVersion, credential.MFASecret, credential.UserID.String())
+}
+
+func (s *TenantAuthService) logMFAEvent(ctx context.Context, tenantID, userID uuid.UUID, eventType, status, method string) {
+	auditLog := &models.TenantAuthAuditLog{
+		TenantID:    tenantID,
+		UserID:      &userID,
+		EventType:   eventType,
+		EventStatus: status,
+		Details:     models.MustNewJSONB(map[string]interface{}{"method": method}),
+	}
+	if err := s.credentialRepo.LogAuthEvent(ctx, auditLog); err != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to log MFA event: %v", err)
+	}
+}
+
+// generateRecoveryCodes returns plaintext codes for the user and their SHA-256 hashes for storage
+func generateRecoveryCodes() ([]string, models.JSONB, error) {
+	// Bytes at or above the largest multiple of the alphabet size are skipped to avoid modulo bias
+	limit := byte(256 - 256%len(mfaRecoveryAlphabet))
+	codes := make([]string, mfaRecoveryCodeCount)
+	hashes := make([]string, mfaRecoveryCodeCount)
+	buf := make([]byte, 1)
+	for i := range codes {
+		var b strings.Builder
+		for b.Len() < 2*mfaRecoveryCodeHalf+1 {
+			if b.Len() == mfaRecoveryCodeHalf {
+				b.WriteByte('-')
+				continue
+			}
+			if _, err := rand.Read(buf); err != nil {
+				return nil, nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+			}
+			if buf[0] < limit {
+				b.WriteByte(mfaRecoveryAlphabet[int(buf[0])%len(mfaRecoveryAlphabet)])
+			}
+		}
+		codes[i] = b.String()
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	encoded, err := models.NewJSONB(hashes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode recovery codes: %w", err)
+	}
+	return codes, encoded, nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+func isRecoveryCodeFormat(code string) bool {
+	return len(code) == 2*mfaRecoveryCodeHalf+1 && code[mfaRecoveryCodeHalf] == '-'
+}
+
+func decodeRecoveryCodeHashes(data models.JSONB) []string {
+	var hashes []string
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &hashes)
+	}
+	return hashes
+}
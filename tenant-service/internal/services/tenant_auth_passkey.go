@@ -0,0 +1,859 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tenant-service/internal/models"
+)
+
+const (
+	// passkeyNameMaxLength is the longest label a user can give a passkey
+	passkeyNameMaxLength = 100
+	// passkeyPolicyMaxRoles is the most roles a passkey policy can name
+	passkeyPolicyMaxRoles = 20
+)
+
+var (
+	// ErrPasskeysNotConfigured is returned when the service has no WebAuthn relying party configuration
+	ErrPasskeysNotConfigured = errors.New("passkeys are not configured")
+	// ErrPasskeyNotFound is returned when the passkey does not exist or belongs to another user
+	ErrPasskeyNotFound = errors.New("passkey not found")
+	// ErrPasskeyLimitReached is returned when the user already registered the maximum number of passkeys
+	ErrPasskeyLimitReached = errors.New("maximum number of passkeys reached; remove one before adding another")
+	// ErrPasskeyRequiredByPolicy is returned when removing the last passkey the tenant's auth policy requires
+	ErrPasskeyRequiredByPolicy = errors.New("a passkey is required by the tenant's security policy and the last one cannot be removed")
+	// ErrPasskeyChallengeInvalid is returned when a ceremony response refers to an unknown, used or expired challenge
+	ErrPasskeyChallengeInvalid = errors.New("passkey challenge is invalid or expired; start again")
+	// ErrPasskeyVerificationFailed is returned when the authenticator response does not verify
+	ErrPasskeyVerificationFailed = errors.New("passkey verification failed")
+	// ErrPasskeyPolicyForbidden is returned when the user may not manage the tenant's passkey policy
+	ErrPasskeyPolicyForbidden = errors.New("only tenant owners and admins can manage the passkey policy")
+)
+
+// PasskeyCeremony is returned when a registration or login starts. Options is passed to
+// navigator.credentials.create() or navigator.credentials.get(); ChallengeID is sent back with the result.
+type PasskeyCeremony struct {
+	ChallengeID uuid.UUID   `json:"challenge_id"`
+	Options     interface{} `json:"options"`
+	ExpiresAt   time.Time   `json:"expires_at"`
+}
+
+// FinishPasskeyRegistrationInput completes a registration with the authenticator's attestation
+type FinishPasskeyRegistrationInput struct {
+	UserID      uuid.UUID
+	TenantID    uuid.UUID
+	ChallengeID uuid.UUID
+	Name        string
+	Credential  []byte // PublicKeyCredential JSON returned by navigator.credentials.create()
+	IPAddress   string
+	UserAgent   string
+}
+
+// BeginPasskeyLoginInput starts a passkey login. Without an email (or when the email has no passkeys)
+// the browser offers every passkey it holds for the tenant.
+type BeginPasskeyLoginInput struct {
+	TenantID   uuid.UUID
+	TenantSlug string
+	Email      string
+}
+
+// FinishPasskeyLoginInput completes a login with the authenticator's assertion
+type FinishPasskeyLoginInput struct {
+	ChallengeID uuid.UUID
+	Credential  []byte // PublicKeyCredential JSON returned by navigator.credentials.get()
+	IPAddress   string
+	UserAgent   string
+	RememberMe  bool
+}
+
+// PasskeyPolicy is the passkey section of a tenant's auth policy
+type PasskeyPolicy struct {
+	Policy           string     `json:"policy"`             // off, prefer or require
+	RequiredForRoles []string   `json:"required_for_roles"` // Roles prefer/require applies to
+	IsDefault        bool       `json:"is_default"`         // True when the tenant has no stored auth policy
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdatePasskeyPolicyRequest changes the passkey policy; omitted fields keep their current value
+type UpdatePasskeyPolicyRequest struct {
+	Policy           *string  `json:"policy"`
+	RequiredForRoles []string `json:"required_for_roles"`
+}
+
+// passkeyUser adapts a tenant user and their passkeys for the tenant to webauthn.User
+type passkeyUser struct {
+	user        *models.User
+	records     []models.WebAuthnCredential
+	credentials []webauthn.Credential
+}
+
+// WebAuthnID is the user handle stored in discoverable passkeys: the local user ID
+func (u *passkeyUser) WebAuthnID() []byte {
+	id := u.user.ID
+	return id[:]
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	if name := strings.TrimSpace(u.user.FirstName + " " + u.user.LastName); name != "" {
+		return name
+	}
+	return u.user.Email
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// record returns the stored passkey the credential was loaded from
+func (u *passkeyUser) record(credentialID []byte) *models.WebAuthnCredential {
+	encoded := base64.RawURLEncoding.EncodeToString(credentialID)
+	for i := range u.records {
+		if u.records[i].CredentialID == encoded {
+			return &u.records[i]
+		}
+	}
+	return nil
+}
+
+// ListPasskeys returns the user's passkeys for a tenant
+func (s *TenantAuthService) ListPasskeys(ctx context.Context, userID, tenantID uuid.UUID) ([]models.WebAuthnCredential, error) {
+	user, err := s.loadPasskeyMember(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.listPasskeyRecords(ctx, user.ID, tenantID)
+}
+
+// BeginPasskeyRegistration starts registering a passkey for the signed-in user
+func (s *TenantAuthService) BeginPasskeyRegistration(ctx context.Context, userID, tenantID uuid.UUID) (*PasskeyCeremony, error) {
+	user, err := s.loadPasskeyMember(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := s.membershipRepo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	wa, err := s.webAuthnForTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+	pu, err := s.newPasskeyUser(ctx, user, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pu.records) >= s.passkeyConfig.MaxCredentialsPerUser {
+		return nil, ErrPasskeyLimitReached
+	}
+
+	creation, session, err := wa.BeginRegistration(pu,
+		webauthn.WithExclusions(webauthn.Credentials(pu.credentials).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithConveyancePreference(protocol.ConveyancePreference(s.passkeyConfig.Attestation)),
+		webauthn.WithExtensions(map[string]any{"credProps": true}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start passkey registration: %w", err)
+	}
+	return s.saveWebAuthnChallenge(ctx, tenantID, &user.ID, models.WebAuthnCeremonyRegistration, session, creation)
+}
+
+// FinishPasskeyRegistration validates the attestation and stores the new passkey
+func (s *TenantAuthService) FinishPasskeyRegistration(ctx context.Context, input *FinishPasskeyRegistrationInput) (*models.WebAuthnCredential, error) {
+	name := strings.TrimSpace(input.Name)
+	if len(name) > passkeyNameMaxLength {
+		return nil, NewValidationError("name", fmt.Sprintf("name must be at most %d characters", passkeyNameMaxLength), nil)
+	}
+
+	user, err := s.loadPasskeyMember(ctx, input.UserID, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := s.membershipRepo.GetTenantByID(ctx, input.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	wa, err := s.webAuthnForTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, session, err := s.consumeWebAuthnChallenge(ctx, input.ChallengeID, input.TenantID, models.WebAuthnCeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.UserID == nil || *challenge.UserID != user.ID {
+		return nil, ErrPasskeyChallengeInvalid
+	}
+
+	pu, err := s.newPasskeyUser(ctx, user, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pu.records) >= s.passkeyConfig.MaxCredentialsPerUser {
+		return nil, ErrPasskeyLimitReached
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(input.Credential)
+	if err != nil {
+		s.logPasskeyEvent(ctx, input.TenantID, &user.ID, models.AuthEventPasskeyFailed, models.AuthEventStatusFailed, input.IPAddress, input.UserAgent, map[string]interface{}{"ceremony": models.WebAuthnCeremonyRegistration, "reason": "MALFORMED_RESPONSE"})
+		return nil, fmt.Errorf("%w: %s", ErrPasskeyVerificationFailed, protocolErrorDetails(err))
+	}
+	credential, err := wa.CreateCredential(pu, *session, parsed)
+	if err != nil {
+		s.logPasskeyEvent(ctx, input.TenantID, &user.ID, models.AuthEventPasskeyFailed, models.AuthEventStatusFailed, input.IPAddress, input.UserAgent, map[string]interface{}{"ceremony": models.WebAuthnCeremonyRegistration, "reason": "INVALID_ATTESTATION"})
+		return nil, fmt.Errorf("%w: %s", ErrPasskeyVerificationFailed, protocolErrorDetails(err))
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("Passkey %d", len(pu.records)+1)
+	}
+	record := newWebAuthnCredentialRecord(user.ID, input.TenantID, name, credential)
+	if err := s.db.WithContext(ctx).Create(record).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+			return nil, NewConflictError("passkey", "This passkey is already registered")
+		}
+		return nil, fmt.Errorf("failed to save passkey: %w", err)
+	}
+
+	s.logPasskeyEvent(ctx, input.TenantID, &user.ID, models.AuthEventPasskeyRegistered, models.AuthEventStatusSuccess, input.IPAddress, input.UserAgent, map[string]interface{}{"passkey_id": record.ID, "attestation_type": record.AttestationType})
+	log.Printf("[TenantAuthService] Passkey %s registered for user %s in tenant %s", record.ID, user.ID, input.TenantID)
+	return record, nil
+}
+
+// DeletePasskey removes one of the user's passkeys; the last one stays while the tenant requires passkeys for the user's role
+func (s *TenantAuthService) DeletePasskey(ctx context.Context, userID, tenantID, passkeyID uuid.UUID, ipAddress, userAgent string) error {
+	user, err := s.loadPasskeyMember(ctx, userID, tenantID)
+	if err != nil {
+		return err
+	}
+	records, err := s.listPasskeyRecords(ctx, user.ID, tenantID)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, record := range records {
+		if record.ID == passkeyID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrPasskeyNotFound
+	}
+
+	if len(records) == 1 {
+		policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		role, _ := s.membershipRepo.GetUserRole(ctx, user.ID, tenantID)
+		if PasskeyPolicyFor(policy, role) == models.PasskeyPolicyRequire {
+			return ErrPasskeyRequiredByPolicy
+		}
+	}
+
+	result := s.db.WithContext(ctx).
+		Where("id = ? AND user_id = ? AND tenant_id = ?", passkeyID, user.ID, tenantID).
+		Delete(&models.WebAuthnCredential{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete passkey: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrPasskeyNotFound
+	}
+
+	s.logPasskeyEvent(ctx, tenantID, &user.ID, models.AuthEventPasskeyRemoved, models.AuthEventStatusSuccess, ipAddress, userAgent, map[string]interface{}{"passkey_id": passkeyID})
+	return nil
+}
+
+// BeginPasskeyLogin starts a passkey login for a tenant
+func (s *TenantAuthService) BeginPasskeyLogin(ctx context.Context, input *BeginPasskeyLoginInput) (*PasskeyCeremony, error) {
+	var tenant *models.Tenant
+	var err error
+	if input.TenantID != uuid.Nil {
+		tenant, err = s.membershipRepo.GetTenantByID(ctx, input.TenantID)
+	} else if input.TenantSlug != "" {
+		tenant, err = s.membershipRepo.GetTenantBySlug(ctx, input.TenantSlug)
+	} else {
+		return nil, NewValidationError("tenant_id", "either tenant_id or tenant_slug is required", nil)
+	}
+	if err != nil || tenant == nil {
+		return nil, NewValidationError("tenant_id", "The specified organization was not found", nil)
+	}
+	wa, err := s.webAuthnForTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	// Passkeys always verify the user (PIN or biometrics), so a passkey login counts as multi-factor
+	var assertion *protocol.CredentialAssertion
+	var session *webauthn.SessionData
+	var userID *uuid.UUID
+	if email := strings.TrimSpace(input.Email); email != "" {
+		var user models.User
+		if err := s.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err == nil {
+			pu, err := s.newPasskeyUser(ctx, &user, tenant.ID)
+			if err != nil {
+				return nil, err
+			}
+			if len(pu.credentials) > 0 {
+				assertion, session, err = wa.BeginLogin(pu, webauthn.WithUserVerification(protocol.VerificationRequired))
+				if err != nil {
+					return nil, fmt.Errorf("failed to start passkey login: %w", err)
+				}
+				userID = &user.ID
+			}
+		}
+	}
+	// Unknown emails get the same discoverable ceremony so the response does not reveal who has passkeys
+	if assertion == nil {
+		assertion, session, err = wa.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+		if err != nil {
+			return nil, fmt.Errorf("failed to start passkey login: %w", err)
+		}
+	}
+	return s.saveWebAuthnChallenge(ctx, tenant.ID, userID, models.WebAuthnCeremonyLogin, session, assertion)
+}
+
+// FinishPasskeyLogin verifies the assertion and signs the user in, issuing Keycloak tokens through token exchange
+func (s *TenantAuthService) FinishPasskeyLogin(ctx context.Context, input *FinishPasskeyLoginInput) (*ValidateCredentialsResponse, error) {
+	var stored models.WebAuthnChallenge
+	if err := s.db.WithContext(ctx).Select("tenant_id").Where("id = ?", input.ChallengeID).First(&stored).Error; err != nil {
+		return nil, ErrPasskeyChallengeInvalid
+	}
+	challenge, session, err := s.consumeWebAuthnChallenge(ctx, input.ChallengeID, stored.TenantID, models.WebAuthnCeremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := s.membershipRepo.GetTenantByID(ctx, challenge.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	wa, err := s.webAuthnForTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := func(userID *uuid.UUID, reason string) *ValidateCredentialsResponse {
+		s.logPasskeyEvent(ctx, tenant.ID, userID, models.AuthEventPasskeyFailed, models.AuthEventStatusFailed, input.IPAddress, input.UserAgent, map[string]interface{}{"ceremony": models.WebAuthnCeremonyLogin, "reason": reason})
+		return &ValidateCredentialsResponse{
+			Valid:        false,
+			UserID:       userID,
+			TenantID:     tenant.ID,
+			TenantSlug:   tenant.Slug,
+			ErrorCode:    "INVALID_PASSKEY",
+			ErrorMessage: "The passkey could not be verified",
+		}
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(input.Credential)
+	if err != nil {
+		return failed(challenge.UserID, "MALFORMED_RESPONSE"), nil
+	}
+
+	var pu *passkeyUser
+	var credential *webauthn.Credential
+	if challenge.UserID != nil {
+		if pu, err = s.loadPasskeyUser(ctx, *challenge.UserID, tenant.ID); err != nil {
+			return nil, err
+		}
+		credential, err = wa.ValidateLogin(pu, *session, parsed)
+	} else {
+		credential, err = wa.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+			userID, err := uuid.FromBytes(userHandle)
+			if err != nil {
+				return nil, fmt.Errorf("invalid user handle: %w", err)
+			}
+			if pu, err = s.loadPasskeyUser(ctx, userID, tenant.ID); err != nil {
+				return nil, err
+			}
+			return pu, nil
+		}, *session, parsed)
+	}
+	if err != nil || pu == nil {
+		var userID *uuid.UUID
+		if pu != nil {
+			userID = &pu.user.ID
+		}
+		log.Printf("[TenantAuthService] Passkey login failed for tenant %s: %s", tenant.ID, protocolErrorDetails(err))
+		return failed(userID, "INVALID_ASSERTION"), nil
+	}
+	user := pu.user
+
+	membership, err := s.membershipRepo.GetMembership(ctx, user.ID, tenant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check membership: %w", err)
+	}
+	if membership == nil || !membership.IsActive {
+		s.logFailedAuthEvent(ctx, tenant.ID, &user.ID, user.Email, input.IPAddress, input.UserAgent, "NO_MEMBERSHIP")
+		return &ValidateCredentialsResponse{
+			Valid:        false,
+			TenantID:     tenant.ID,
+			TenantSlug:   tenant.Slug,
+			ErrorCode:    "NO_ACCESS",
+			ErrorMessage: "You do not have access to this organization",
+		}, nil
+	}
+
+	isLocked, lockedUntil, _, err := s.credentialRepo.CheckAccountLockout(ctx, user.ID, tenant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check lockout: %w", err)
+	}
+	if isLocked {
+		s.logFailedAuthEvent(ctx, tenant.ID, &user.ID, user.Email, input.IPAddress, input.UserAgent, "ACCOUNT_LOCKED")
+		errorMessage := "Account is permanently locked. Please contact support."
+		if lockedUntil != nil {
+			errorMessage = fmt.Sprintf("Account is locked until %s", lockedUntil.Format(time.RFC3339))
+		}
+		return &ValidateCredentialsResponse{
+			Valid:         false,
+			UserID:        &user.ID,
+			TenantID:      tenant.ID,
+			TenantSlug:    tenant.Slug,
+			AccountLocked: true,
+			LockedUntil:   lockedUntil,
+			ErrorCode:     "ACCOUNT_LOCKED",
+			ErrorMessage:  errorMessage,
+		}, nil
+	}
+
+	record := pu.record(credential.ID)
+	if record != nil {
+		s.updatePasskeyUsage(ctx, record, credential)
+		if credential.Authenticator.CloneWarning {
+			// The signature counter went backwards: two copies of the private key may exist
+			s.logPasskeyEvent(ctx, tenant.ID, &user.ID, models.AuthEventSuspiciousActivity, models.AuthEventStatusSuccess, input.IPAddress, input.UserAgent, map[string]interface{}{"passkey_id": record.ID, "reason": "PASSKEY_CLONE_WARNING"})
+		}
+	}
+
+	keycloakUserID := ""
+	if user.KeycloakID != nil {
+		keycloakUserID = user.KeycloakID.String()
+	}
+	if s.keycloakClient == nil || s.keycloakConfig == nil || keycloakUserID == "" {
+		log.Printf("[TenantAuthService] ERROR: No Keycloak config available to issue tokens for passkey login")
+		return nil, fmt.Errorf("authentication service not properly configured")
+	}
+	tokens, err := s.keycloakClient.GetTokensForValidatedUser(ctx, keycloakUserID, s.keycloakConfig.ClientID, s.keycloakConfig.ClientSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue tokens: %w", err)
+	}
+
+	if err := s.credentialRepo.RecordLoginAttempt(ctx, user.ID, tenant.ID, true, input.IPAddress, input.UserAgent); err != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to record successful login: %v", err)
+	}
+	s.attemptGuard.Reset(ctx, tenant.ID, user.Email, input.IPAddress)
+	s.logSuccessAuthEvent(ctx, tenant.ID, &user.ID, input.IPAddress, input.UserAgent)
+	if record != nil {
+		s.logPasskeyEvent(ctx, tenant.ID, &user.ID, models.AuthEventPasskeyLogin, models.AuthEventStatusSuccess, input.IPAddress, input.UserAgent, map[string]interface{}{"passkey_id": record.ID})
+	}
+
+	orgID := ""
+	if tenant.KeycloakOrgID != nil {
+		orgID = tenant.KeycloakOrgID.String()
+	}
+	credentialRecord, _ := s.credentialRepo.GetCredential(ctx, user.ID, tenant.ID)
+	sessionID := s.startSession(ctx, tenant.ID, &user.ID, keycloakUserID, AuthContextStaff, input.IPAddress, input.UserAgent, input.RememberMe, tokens)
+
+	return &ValidateCredentialsResponse{
+		Valid:            true,
+		UserID:           &user.ID,
+		KeycloakUserID:   keycloakUserID,
+		TenantID:         tenant.ID,
+		TenantSlug:       tenant.Slug,
+		OrgID:            orgID,
+		Email:            user.Email,
+		FirstName:        user.FirstName,
+		LastName:         user.LastName,
+		Role:             membership.Role,
+		MFAEnabled:       credentialRecord != nil && credentialRecord.MFAEnabled,
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		IDToken:          tokens.IDToken,
+		ExpiresIn:        tokens.ExpiresIn,
+		RefreshExpiresIn: tokens.RefreshExpiresIn,
+		SessionID:        sessionID,
+	}, nil
+}
+
+// AuthorizePasskeyPolicy checks the user is an owner or admin of the tenant
+func (s *TenantAuthService) AuthorizePasskeyPolicy(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrPasskeyPolicyForbidden
+	}
+	return nil
+}
+
+// GetPasskeyPolicy returns the tenant's passkey policy, or the defaults when none is stored
+func (s *TenantAuthService) GetPasskeyPolicy(ctx context.Context, tenantID uuid.UUID) (*PasskeyPolicy, error) {
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return toPasskeyPolicy(policy), nil
+}
+
+// UpdatePasskeyPolicy applies changes to the tenant's passkey policy, creating the auth policy if needed
+func (s *TenantAuthService) UpdatePasskeyPolicy(ctx context.Context, tenantID, userID uuid.UUID, req UpdatePasskeyPolicyRequest) (*PasskeyPolicy, error) {
+	if req.Policy != nil && !IsValidPasskeyPolicy(*req.Policy) {
+		return nil, NewValidationError("policy", "policy must be one of off, prefer, require", []string{models.PasskeyPolicyOff, models.PasskeyPolicyPrefer, models.PasskeyPolicyRequire})
+	}
+	var roles []string
+	if req.RequiredForRoles != nil {
+		if len(req.RequiredForRoles) > passkeyPolicyMaxRoles {
+			return nil, NewValidationError("required_for_roles", fmt.Sprintf("at most %d roles can be listed", passkeyPolicyMaxRoles), nil)
+		}
+		roles = make([]string, 0, len(req.RequiredForRoles))
+		for _, role := range req.RequiredForRoles {
+			if role = strings.ToLower(strings.TrimSpace(role)); role == "" {
+				return nil, NewValidationError("required_for_roles", "roles must not be empty", nil)
+			}
+			roles = append(roles, role)
+		}
+	}
+
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		if policy, err = s.credentialRepo.CreateAuthPolicy(ctx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Policy != nil {
+		policy.PasskeyPolicy = *req.Policy
+	}
+	if roles != nil {
+		encoded, err := models.NewJSONB(roles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode roles: %w", err)
+		}
+		policy.PasskeyRequiredForRoles = encoded
+	}
+	policy.UpdatedBy = &userID
+
+	if err := s.credentialRepo.UpdateAuthPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	log.Printf("[TenantAuthService] Passkey policy for tenant %s set to %s by %s", tenantID, policy.PasskeyPolicy, userID)
+	return toPasskeyPolicy(policy), nil
+}
+
+func toPasskeyPolicy(policy *models.TenantAuthPolicy) *PasskeyPolicy {
+	result := &PasskeyPolicy{
+		Policy:           models.PasskeyPolicyOff,
+		RequiredForRoles: []string{models.MembershipRoleOwner, models.MembershipRoleAdmin},
+		IsDefault:        policy == nil,
+	}
+	if policy != nil {
+		if IsValidPasskeyPolicy(policy.PasskeyPolicy) {
+			result.Policy = policy.PasskeyPolicy
+		}
+		if len(policy.PasskeyRequiredForRoles) > 0 {
+			var roles []string
+			if err := json.Unmarshal(policy.PasskeyRequiredForRoles, &roles); err == nil {
+				result.RequiredForRoles = roles
+			}
+		}
+		updatedAt := policy.UpdatedAt
+		result.UpdatedAt = &updatedAt
+	}
+	return result
+}
+
+// IsValidPasskeyPolicy reports whether value is a known passkey policy
+func IsValidPasskeyPolicy(value string) bool {
+	switch value {
+	case models.PasskeyPolicyOff, models.PasskeyPolicyPrefer, models.PasskeyPolicyRequire:
+		return true
+	}
+	return false
+}
+
+// PasskeyPolicyFor returns the passkey policy that applies to a member with the given role:
+// the tenant's prefer/require setting for roles it names, off for everyone else
+func PasskeyPolicyFor(policy *models.TenantAuthPolicy, role string) string {
+	if policy == nil || role == "" || !IsValidPasskeyPolicy(policy.PasskeyPolicy) || policy.PasskeyPolicy == models.PasskeyPolicyOff {
+		return models.PasskeyPolicyOff
+	}
+	var roles []string
+	if err := json.Unmarshal(policy.PasskeyRequiredForRoles, &roles); err != nil {
+		return models.PasskeyPolicyOff
+	}
+	for _, r := range roles {
+		if strings.EqualFold(r, role) {
+			return policy.PasskeyPolicy
+		}
+	}
+	return models.PasskeyPolicyOff
+}
+
+// PasskeyOrigins returns the origins passkey ceremonies of a tenant may run on: its admin
+// subdomain plus any configured extra origins
+func PasskeyOrigins(slug, baseDomain string, extra []string) []string {
+	origins := make([]string, 0, len(extra)+1)
+	if slug != "" && baseDomain != "" {
+		origins = append(origins, fmt.Sprintf("https://%s-admin.%s", slug, baseDomain))
+	}
+	for _, origin := range extra {
+		if origin = strings.TrimSuffix(strings.TrimSpace(origin), "/"); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// passkeyPolicyForUser returns the passkey policy for the user's role and whether they registered a passkey
+func (s *TenantAuthService) passkeyPolicyForUser(ctx context.Context, policy *models.TenantAuthPolicy, userID, tenantID uuid.UUID, role string) (string, bool, error) {
+	passkeyPolicy := PasskeyPolicyFor(policy, role)
+	if passkeyPolicy == models.PasskeyPolicyOff {
+		return passkeyPolicy, false, nil
+	}
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.WebAuthnCredential{}).
+		Where("user_id = ? AND tenant_id = ?", userID, tenantID).
+		Count(&count).Error; err != nil {
+		return "", false, fmt.Errorf("failed to count passkeys: %w", err)
+	}
+	return passkeyPolicy, count > 0, nil
+}
+
+// webAuthnForTenant builds the relying party for a tenant's admin origin
+func (s *TenantAuthService) webAuthnForTenant(tenant *models.Tenant) (*webauthn.WebAuthn, error) {
+	if s.passkeyConfig == nil || s.passkeyConfig.RPID == "" {
+		return nil, ErrPasskeysNotConfigured
+	}
+	ttl := time.Duration(s.passkeyConfig.ChallengeTTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = models.DefaultWebAuthnChallengeExpiry
+	}
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          s.passkeyConfig.RPID,
+		RPDisplayName: s.passkeyConfig.RPDisplayName,
+		RPOrigins:     PasskeyOrigins(tenant.Slug, s.baseDomain, s.passkeyConfig.ExtraOrigins),
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: ttl, TimeoutUVD: ttl},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: ttl, TimeoutUVD: ttl},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid passkey configuration: %w", err)
+	}
+	return wa, nil
+}
+
+// loadPasskeyMember resolves the local user (the JWT subject may be a Keycloak ID) and checks
+// they are an active member of the tenant
+func (s *TenantAuthService) loadPasskeyMember(ctx context.Context, userID, tenantID uuid.UUID) (*models.User, error) {
+	user, err := s.GetUserByKeycloakOrLocalID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	membership, err := s.membershipRepo.GetMembership(ctx, user.ID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil || !membership.IsActive {
+		return nil, ErrMFANoMembership
+	}
+	return user, nil
+}
+
+// loadPasskeyUser loads a user by local ID with their passkeys for the tenant
+func (s *TenantAuthService) loadPasskeyUser(ctx context.Context, userID, tenantID uuid.UUID) (*passkeyUser, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return s.newPasskeyUser(ctx, &user, tenantID)
+}
+
+func (s *TenantAuthService) newPasskeyUser(ctx context.Context, user *models.User, tenantID uuid.UUID) (*passkeyUser, error) {
+	records, err := s.listPasskeyRecords(ctx, user.ID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	pu := &passkeyUser{user: user, records: records}
+	for _, record := range records {
+		pu.credentials = append(pu.credentials, webAuthnCredentialFromRecord(record))
+	}
+	return pu, nil
+}
+
+func (s *TenantAuthService) listPasskeyRecords(ctx context.Context, userID, tenantID uuid.UUID) ([]models.WebAuthnCredential, error) {
+	var records []models.WebAuthnCredential
+	if err := s.db.WithContext(ctx).
+		Where("user_id = ? AND tenant_id = ?", userID, tenantID).
+		Order("created_at ASC").
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	return records, nil
+}
+
+// updatePasskeyUsage stores the signature counter and backup state reported by a login
+func (s *TenantAuthService) updatePasskeyUsage(ctx context.Context, record *models.WebAuthnCredential, credential *webauthn.Credential) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"sign_count":    int64(credential.Authenticator.SignCount),
+		"backup_state":  credential.Flags.BackupState,
+		"clone_warning": record.CloneWarning || credential.Authenticator.CloneWarning,
+		"last_used_at":  now,
+		"updated_at":    now,
+	}
+	if err := s.db.WithContext(ctx).Model(&models.WebAuthnCredential{}).Where("id = ?", record.ID).Updates(updates).Error; err != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to update passkey usage: %v", err)
+	}
+}
+
+// saveWebAuthnChallenge stores the ceremony session so the finish step can verify the response
+func (s *TenantAuthService) saveWebAuthnChallenge(ctx context.Context, tenantID uuid.UUID, userID *uuid.UUID, ceremony string, session *webauthn.SessionData, options interface{}) (*PasskeyCeremony, error) {
+	sessionData, err := models.NewJSONB(session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode passkey challenge: %w", err)
+	}
+	ttl := time.Duration(s.passkeyConfig.ChallengeTTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = models.DefaultWebAuthnChallengeExpiry
+	}
+	challenge := &models.WebAuthnChallenge{
+		TenantID:    tenantID,
+		UserID:      userID,
+		Ceremony:    ceremony,
+		SessionData: sessionData,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+	if err := s.db.WithContext(ctx).Create(challenge).Error; err != nil {
+		return nil, fmt.Errorf("failed to save passkey challenge: %w", err)
+	}
+	// Abandoned ceremonies are cleared opportunistically
+	s.db.WithContext(ctx).Where("expires_at < ?", time.Now().Add(-time.Hour)).Delete(&models.WebAuthnChallenge{})
+
+	return &PasskeyCeremony{ChallengeID: challenge.ID, Options: options, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// consumeWebAuthnChallenge deletes and returns a challenge so each one verifies at most one response
+func (s *TenantAuthService) consumeWebAuthnChallenge(ctx context.Context, challengeID, tenantID uuid.UUID, ceremony string) (*models.WebAuthnChallenge, *webauthn.SessionData, error) {
+	var challenges []models.WebAuthnChallenge
+	result := s.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND tenant_id = ?", challengeID, tenantID).
+		Delete(&challenges)
+	if result.Error != nil {
+		return nil, nil, fmt.Errorf("failed to load passkey challenge: %w", result.Error)
+	}
+	if len(challenges) == 0 || !challenges[0].IsUsable(ceremony, time.Now()) {
+		return nil, nil, ErrPasskeyChallengeInvalid
+	}
+	var session webauthn.SessionData
+	if err := json.Unmarshal(challenges[0].SessionData, &session); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode passkey challenge: %w", err)
+	}
+	return &challenges[0], &session, nil
+}
+
+func (s *TenantAuthService) logPasskeyEvent(ctx context.Context, tenantID uuid.UUID, userID *uuid.UUID, eventType, status, ipAddress, userAgent string, details map[string]interface{}) {
+	auditLog := &models.TenantAuthAuditLog{
+		TenantID:    tenantID,
+		UserID:      userID,
+		EventType:   eventType,
+		EventStatus: status,
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+		Details:     models.MustNewJSONB(details),
+	}
+	if err := s.credentialRepo.LogAuthEvent(ctx, auditLog); err != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to log passkey event: %v", err)
+	}
+}
+
+// newWebAuthnCredentialRecord converts a verified registration into the stored passkey
+func newWebAuthnCredentialRecord(userID, tenantID uuid.UUID, name string, credential *webauthn.Credential) *models.WebAuthnCredential {
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+	aaguid := ""
+	if id, err := uuid.FromBytes(credential.Authenticator.AAGUID); err == nil {
+		aaguid = id.String()
+	}
+	return &models.WebAuthnCredential{
+		UserID:          userID,
+		TenantID:        tenantID,
+		CredentialID:    base64.RawURLEncoding.EncodeToString(credential.ID),
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          aaguid,
+		Transports:      models.MustNewJSONB(transports),
+		Flags:           int(credential.Flags.ProtocolValue()),
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		SignCount:       int64(credential.Authenticator.SignCount),
+		Name:            name,
+	}
+}
+
+// webAuthnCredentialFromRecord restores the credential record verified against at login
+func webAuthnCredentialFromRecord(record models.WebAuthnCredential) webauthn.Credential {
+	credentialID, err := base64.RawURLEncoding.DecodeString(record.CredentialID)
+	if err != nil {
+		log.Printf("[TenantAuthService] Warning: Passkey %s has an invalid credential ID: %v", record.ID, err)
+	}
+	var transports []string
+	if len(record.Transports) > 0 {
+		_ = json.Unmarshal(record.Transports, &transports)
+	}
+	credential := webauthn.Credential{
+		ID:              credentialID,
+		PublicKey:       record.PublicKey,
+		AttestationType: record.AttestationType,
+		Flags:           webauthn.NewCredentialFlags(protocol.AuthenticatorFlags(record.Flags)),
+		Authenticator: webauthn.Authenticator{
+			SignCount:    uint32(record.SignCount),
+			CloneWarning: record.CloneWarning,
+		},
+	}
+	if aaguid, err := uuid.Parse(record.AAGUID); err == nil {
+		credential.Authenticator.AAGUID = aaguid[:]
+	}
+	for _, transport := range transports {
+		credential.Transport = append(credential.Transport, protocol.AuthenticatorTransport(transport))
+	}
+	return credential
+}
+
+// protocolErrorDetails returns the diagnostic details of a WebAuthn protocol error
+func protocolErrorDetails(err error) string {
+	if err == nil {
+		return "unknown user"
+	}
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.Details != "" {
+		return protocolErr.Details
+	}
+	return err.Error()
+}
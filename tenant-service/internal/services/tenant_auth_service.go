@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -13,7 +14,9 @@ import (
 	"github.com/Tesseract-Nexus/go-shared/auth"
 	"github.com/Tesseract-Nexus/go-shared/security"
 	"tenant-service/internal/clients"
+	"tenant-service/internal/config"
 	"tenant-service/internal/models"
+	natsClient "tenant-service/internal/nats"
 	"tenant-service/internal/repository"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -25,13 +28,21 @@ import (
 type TenantAuthService struct {
 	credentialRepo     *repository.CredentialRepository
 	membershipRepo     *repository.MembershipRepository
-	keycloakClient     *auth.KeycloakAdminClient
+	keycloakClient     *timedKeycloakClient
 	keycloakConfig     *KeycloakAuthConfig
 	db                 *gorm.DB
 	staffClient        StaffClientInterface          // For staff member credential validation
 	notificationClient *clients.NotificationClient   // For sending emails
 	verificationClient *clients.VerificationClient   // For email verification
 	natsClient         NATSClientInterface           // For publishing customer events
+	securityEvents     SecurityEventPublisher        // For security.* events consumed by audit alerting
+	keyService         *TenantKeyService             // For per-tenant credential encryption
+	passwordPolicy     *PasswordPolicyService        // For password policy enforcement
+	sessions           *SessionRegistry              // For listing and revoking active sessions
+	attemptGuard       *LoginAttemptGuard            // For tenant+email+IP brute-force lockouts
+	ssoService         *TenantSSOService             // For routing SSO-enforced staff logins to the tenant IdP
+	passkeyConfig      *config.PasskeyConfig         // For WebAuthn registration and passkey logins
+	baseDomain         string                        // For the admin origin passkey ceremonies run on
 }
 
 // NATSClientInterface defines the interface for NATS event publishing
@@ -56,12 +67,20 @@ func NewTenantAuthService(db *gorm.DB, keycloakClient *auth.KeycloakAdminClient,
 	return &TenantAuthService{
 		credentialRepo: repository.NewCredentialRepository(db),
 		membershipRepo: repository.NewMembershipRepository(db),
-		keycloakClient: keycloakClient,
+		keycloakClient: newTimedKeycloakClient(keycloakClient),
 		keycloakConfig: keycloakConfig,
 		db:             db,
+		passwordPolicy: NewPasswordPolicyService(db, nil),
+		sessions:       NewSessionRegistry(db, nil),
+		attemptGuard:   NewLoginAttemptGuard(nil),
 	}
 }
 
+// SetPasswordPolicyService replaces the default password policy service (e.g. to enable breach checks)
+func (s *TenantAuthService) SetPasswordPolicyService(passwordPolicy *PasswordPolicyService) {
+	s.passwordPolicy = passwordPolicy
+}
+
 // SetStaffClient sets the staff service client for staff credential validation
 func (s *TenantAuthService) SetStaffClient(client StaffClientInterface) {
 	s.staffClient = client
@@ -82,6 +101,27 @@ func (s *TenantAuthService) SetNATSClient(client NATSClientInterface) {
 	s.natsClient = client
 }
 
+// SetSecurityEventPublisher enables security events for failed logins, lockouts and password changes
+func (s *TenantAuthService) SetSecurityEventPublisher(publisher SecurityEventPublisher) {
+	s.securityEvents = publisher
+}
+
+// SetKeyService sets the tenant key service used to encrypt credential data
+func (s *TenantAuthService) SetKeyService(keyService *TenantKeyService) {
+	s.keyService = keyService
+}
+
+// SetSSOService sets the tenant SSO service used to route staff logins to enterprise IdPs
+func (s *TenantAuthService) SetSSOService(ssoService *TenantSSOService) {
+	s.ssoService = ssoService
+}
+
+// SetPasskeyConfig enables passkey registration and login; baseDomain builds each tenant's admin origin
+func (s *TenantAuthService) SetPasskeyConfig(cfg config.PasskeyConfig, baseDomain string) {
+	s.passkeyConfig = &cfg
+	s.baseDomain = baseDomain
+}
+
 // GetUserByKeycloakOrLocalID resolves a user by either Keycloak ID or local ID
 // This handles the case where JWT tokens contain Keycloak subject (sub) but
 // existing users may have a different local ID in tenant_users table
@@ -119,6 +159,8 @@ type ValidateCredentialsRequest struct {
 	IPAddress              string    `json:"ip_address,omitempty"`
 	UserAgent              string    `json:"user_agent,omitempty"`
 	SkipPasswordValidation bool      `json:"skip_password_validation,omitempty"` // Skip password check, only check account status
+	MFACode                string    `json:"mfa_code,omitempty"`                 // TOTP or recovery code for users with MFA enabled
+	RememberMe             bool      `json:"remember_me,omitempty"`              // Use the tenant's remember-me session lifetime, when allowed
 }
 
 // ValidateCredentialsResponse represents the response from credential validation
@@ -135,17 +177,25 @@ type ValidateCredentialsResponse struct {
 	Role           string     `json:"role,omitempty"`            // User's role in the tenant
 	MFARequired    bool       `json:"mfa_required"`              // Whether MFA is required
 	MFAEnabled     bool       `json:"mfa_enabled"`               // Whether user has MFA enabled
+	MFAEnrollmentRequired bool `json:"mfa_enrollment_required,omitempty"` // Policy requires MFA but the user has not enrolled yet
 	AccountLocked  bool       `json:"account_locked"`            // Whether account is locked
 	LockedUntil    *time.Time `json:"locked_until,omitempty"`    // When lockout expires
 	RemainingAttempts int     `json:"remaining_attempts,omitempty"` // Remaining login attempts
+	PasswordChangeRequired bool `json:"password_change_required,omitempty"` // Password is past the tenant's rotation interval
 	ErrorCode      string     `json:"error_code,omitempty"`
 	ErrorMessage   string     `json:"error_message,omitempty"`
+	SSO            *SSOLoginRoute `json:"sso,omitempty"` // Set with SSO_REQUIRED: log in through this IdP instead
+	PasskeyRequired            bool `json:"passkey_required,omitempty"`             // Set with PASSKEY_REQUIRED: log in with a passkey instead
+	PasskeyEnrollmentRequired  bool `json:"passkey_enrollment_required,omitempty"`  // Policy requires a passkey but the user has not registered one yet
+	PasskeyEnrollmentSuggested bool `json:"passkey_enrollment_suggested,omitempty"` // Policy prefers passkeys and the user has not registered one yet
 
 	// Keycloak tokens (only populated if IssueTokens is true in request)
-	AccessToken  string `json:"access_token,omitempty"`
-	RefreshToken string `json:"refresh_token,omitempty"`
-	IDToken      string `json:"id_token,omitempty"`
-	ExpiresIn    int    `json:"expires_in,omitempty"`
+	AccessToken      string `json:"access_token,omitempty"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	IDToken          string `json:"id_token,omitempty"`
+	ExpiresIn        int    `json:"expires_in,omitempty"`
+	RefreshExpiresIn int    `json:"refresh_expires_in,omitempty"` // Capped to the tenant's session policy
+	SessionID        string `json:"session_id,omitempty"`         // Registry ID of the session the tokens belong to
 }
 
 // ValidateCredentials validates tenant-specific credentials for a user
@@ -171,6 +221,45 @@ func (s *TenantAuthService) ValidateCredentials(ctx context.Context, req *Valida
 		}, nil
 	}
 
+	// Staff of tenants enforcing SSO must sign in through the tenant's IdP, not with a password
+	if req.AuthContext != AuthContextCustomer && s.ssoService != nil {
+		route, err := s.ssoService.RouteLogin(ctx, tenant.ID, req.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check SSO routing: %w", err)
+		}
+		if route != nil {
+			return &ValidateCredentialsResponse{
+				Valid:        false,
+				TenantID:     tenant.ID,
+				TenantSlug:   tenant.Slug,
+				SSO:          route,
+				ErrorCode:    "SSO_REQUIRED",
+				ErrorMessage: "This organization requires single sign-on for your email domain",
+			}, nil
+		}
+	}
+
+	// Get auth policy for lockout thresholds and MFA requirements
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth policy: %w", err)
+	}
+	lockout := policy.LockoutSettings()
+
+	// Check the tenant+email+IP guard first so a locked-out client learns nothing about the account
+	if attempt := s.attemptGuard.Status(ctx, tenant.ID, req.Email, req.IPAddress, lockout); attempt.Locked {
+		s.logFailedAuthEvent(ctx, tenant.ID, nil, req.Email, req.IPAddress, req.UserAgent, "TOO_MANY_ATTEMPTS")
+		return &ValidateCredentialsResponse{
+			Valid:         false,
+			TenantID:      tenant.ID,
+			TenantSlug:    tenant.Slug,
+			AccountLocked: true,
+			LockedUntil:   attempt.LockedUntil,
+			ErrorCode:     "ACCOUNT_LOCKED",
+			ErrorMessage:  fmt.Sprintf("Too many failed login attempts. Try again after %s", attempt.LockedUntil.Format(time.RFC3339)),
+		}, nil
+	}
+
 	// Get user by email
 	var user models.User
 	if err := s.db.WithContext(ctx).Where("email = ?", req.Email).First(&user).Error; err != nil {
@@ -180,7 +269,7 @@ func (s *TenantAuthService) ValidateCredentials(ctx context.Context, req *Valida
 			// This prevents store owners from logging into their own storefront using admin credentials
 			if req.AuthContext != AuthContextCustomer && s.staffClient != nil && s.keycloakClient != nil && s.keycloakConfig != nil {
 				log.Printf("[TenantAuthService] User not in tenant_users, trying staff fallback for %s (auth_context=%s)", security.MaskEmail(req.Email), req.AuthContext)
-				return s.validateStaffCredentials(ctx, tenant, req)
+				return s.validateStaffCredentials(ctx, tenant, req, lockout)
 			}
 			// For customer context, don't fall back to staff - this is the security fix
 			if req.AuthContext == AuthContextCustomer {
@@ -188,13 +277,14 @@ func (s *TenantAuthService) ValidateCredentials(ctx context.Context, req *Valida
 			}
 			// Don't reveal whether user exists
 			s.logFailedAuthEvent(ctx, tenant.ID, nil, req.Email, req.IPAddress, req.UserAgent, "USER_NOT_FOUND")
-			return &ValidateCredentialsResponse{
-				Valid:        false,
-				TenantID:     tenant.ID,
-				TenantSlug:   tenant.Slug,
-				ErrorCode:    "INVALID_CREDENTIALS",
-				ErrorMessage: "Invalid email or password",
-			}, nil
+			return s.recordFailedAttempt(ctx, tenant.ID, req, lockout, &ValidateCredentialsResponse{
+				Valid:             false,
+				TenantID:          tenant.ID,
+				TenantSlug:        tenant.Slug,
+				RemainingAttempts: lockout.MaxAttempts,
+				ErrorCode:         "INVALID_CREDENTIALS",
+				ErrorMessage:      "Invalid email or password",
+			}), nil
 		}
 		return nil, fmt.Errorf("failed to lookup user: %w", err)
 	}
@@ -306,7 +396,7 @@ func (s *TenantAuthService) ValidateCredentials(ctx context.Context, req *Valida
 		_, _, remainingAttempts, _ = s.credentialRepo.CheckAccountLockout(ctx, user.ID, tenant.ID)
 
 		s.logFailedAuthEvent(ctx, tenant.ID, &user.ID, req.Email, req.IPAddress, req.UserAgent, "INVALID_PASSWORD")
-		return &ValidateCredentialsResponse{
+		return s.recordFailedAttempt(ctx, tenant.ID, req, lockout, &ValidateCredentialsResponse{
 			Valid:             false,
 			UserID:            &user.ID,
 			TenantID:          tenant.ID,
@@ -314,27 +404,70 @@ func (s *TenantAuthService) ValidateCredentials(ctx context.Context, req *Valida
 			RemainingAttempts: remainingAttempts,
 			ErrorCode:         "INVALID_CREDENTIALS",
 			ErrorMessage:      "Invalid email or password",
-		}, nil
+		}), nil
 	}
 
-	// Record successful login
-	if err := s.credentialRepo.RecordLoginAttempt(ctx, user.ID, tenant.ID, true, req.IPAddress, req.UserAgent); err != nil {
-		log.Printf("[TenantAuthService] Warning: Failed to record successful login: %v", err)
+	// Under a require policy, members who registered a passkey must sign in with it instead of a password
+	passkeyPolicy, hasPasskey, err := s.passkeyPolicyForUser(ctx, policy, user.ID, tenant.ID, membership.Role)
+	if err != nil {
+		return nil, err
 	}
-
-	// Get auth policy for MFA requirements
-	mfaRequired := false
-	policy, _ := s.credentialRepo.GetAuthPolicy(ctx, tenant.ID)
-	if policy != nil {
-		mfaRequired = policy.MFARequired
-		// TODO: Check MFARequiredForRoles based on user's role
+	if passkeyPolicy == models.PasskeyPolicyRequire && hasPasskey {
+		s.logFailedAuthEvent(ctx, tenant.ID, &user.ID, req.Email, req.IPAddress, req.UserAgent, "PASSKEY_REQUIRED")
+		return &ValidateCredentialsResponse{
+			Valid:           false,
+			UserID:          &user.ID,
+			TenantID:        tenant.ID,
+			TenantSlug:      tenant.Slug,
+			PasskeyRequired: true,
+			ErrorCode:       "PASSKEY_REQUIRED",
+			ErrorMessage:    "This organization requires you to sign in with your passkey",
+		}, nil
 	}
 
-	// Check if user has MFA enabled
 	mfaEnabled := credential != nil && credential.MFAEnabled
+	mfaPolicyRequired := MFARequiredByPolicy(policy, membership.Role)
+
+	// Verify the second factor when one was supplied; without it the login stays pending
+	mfaVerified := false
+	if mfaEnabled && req.MFACode != "" {
+		if err := s.verifySecondFactor(ctx, credential, req.MFACode); err != nil {
+			if !errors.Is(err, ErrInvalidMFACode) {
+				return nil, fmt.Errorf("failed to verify MFA code: %w", err)
+			}
+			if err := s.credentialRepo.RecordLoginAttempt(ctx, user.ID, tenant.ID, false, req.IPAddress, req.UserAgent); err != nil {
+				log.Printf("[TenantAuthService] Warning: Failed to record login attempt: %v", err)
+			}
+			_, _, remainingAttempts, _ = s.credentialRepo.CheckAccountLockout(ctx, user.ID, tenant.ID)
+
+			s.logFailedAuthEvent(ctx, tenant.ID, &user.ID, req.Email, req.IPAddress, req.UserAgent, "INVALID_MFA_CODE")
+			return s.recordFailedAttempt(ctx, tenant.ID, req, lockout, &ValidateCredentialsResponse{
+				Valid:             false,
+				UserID:            &user.ID,
+				TenantID:          tenant.ID,
+				TenantSlug:        tenant.Slug,
+				MFARequired:       true,
+				MFAEnabled:        true,
+				RemainingAttempts: remainingAttempts,
+				ErrorCode:         "INVALID_MFA_CODE",
+				ErrorMessage:      "Invalid verification code",
+			}), nil
+		}
+		mfaVerified = true
+	}
+	mfaRequired := mfaEnabled && !mfaVerified
+
+	// Record successful login only once the user is fully authenticated, so a password-only
+	// attempt cannot reset the failed-attempt counter used against MFA code guessing
+	if !mfaRequired {
+		if err := s.credentialRepo.RecordLoginAttempt(ctx, user.ID, tenant.ID, true, req.IPAddress, req.UserAgent); err != nil {
+			log.Printf("[TenantAuthService] Warning: Failed to record successful login: %v", err)
+		}
+		s.attemptGuard.Reset(ctx, tenant.ID, req.Email, req.IPAddress)
 
-	// Log successful auth event
-	s.logSuccessAuthEvent(ctx, tenant.ID, &user.ID, req.IPAddress, req.UserAgent)
+		// Log successful auth event
+		s.logSuccessAuthEvent(ctx, tenant.ID, &user.ID, req.IPAddress, req.UserAgent)
+	}
 
 	response := &ValidateCredentialsResponse{
 		Valid:          true,
@@ -349,16 +482,22 @@ func (s *TenantAuthService) ValidateCredentials(ctx context.Context, req *Valida
 		Role:           membership.Role,
 		MFARequired:    mfaRequired,
 		MFAEnabled:     mfaEnabled,
+		MFAEnrollmentRequired: mfaPolicyRequired && !mfaEnabled,
+		PasswordChangeRequired: PasswordChangeRequired(policy, credential, time.Now()),
+		PasskeyEnrollmentRequired:  passkeyPolicy == models.PasskeyPolicyRequire && !hasPasskey,
+		PasskeyEnrollmentSuggested: passkeyPolicy == models.PasskeyPolicyPrefer && !hasPasskey,
 	}
 
 	// Attach tokens from Keycloak validation (already obtained during password validation)
 	// Skip if MFA is required - tokens will be issued after MFA verification
 	if keycloakTokens != nil && !mfaRequired {
 		log.Printf("[TenantAuthService] Attaching tokens from Keycloak validation")
+		response.SessionID = s.startSession(ctx, tenant.ID, &user.ID, keycloakUserID, req.AuthContext, req.IPAddress, req.UserAgent, req.RememberMe, keycloakTokens)
 		response.AccessToken = keycloakTokens.AccessToken
 		response.RefreshToken = keycloakTokens.RefreshToken
 		response.IDToken = keycloakTokens.IDToken
 		response.ExpiresIn = keycloakTokens.ExpiresIn
+		response.RefreshExpiresIn = keycloakTokens.RefreshExpiresIn
 	}
 
 	return response, nil
@@ -366,7 +505,7 @@ func (s *TenantAuthService) ValidateCredentials(ctx context.Context, req *Valida
 
 // validateStaffCredentials validates credentials for a staff member via Keycloak
 // This is called as a fallback when user is not found in tenant_users
-func (s *TenantAuthService) validateStaffCredentials(ctx context.Context, tenant *models.Tenant, req *ValidateCredentialsRequest) (*ValidateCredentialsResponse, error) {
+func (s *TenantAuthService) validateStaffCredentials(ctx context.Context, tenant *models.Tenant, req *ValidateCredentialsRequest, lockout models.LockoutSettings) (*ValidateCredentialsResponse, error) {
 	// Get staff info from staff-service
 	staffInfo, err := s.staffClient.GetStaffByEmailForTenant(ctx, req.Email, tenant.ID)
 	if err != nil {
@@ -383,13 +522,14 @@ func (s *TenantAuthService) validateStaffCredentials(ctx context.Context, tenant
 	if staffInfo == nil {
 		// Staff not found
 		s.logFailedAuthEvent(ctx, tenant.ID, nil, req.Email, req.IPAddress, req.UserAgent, "STAFF_NOT_FOUND")
-		return &ValidateCredentialsResponse{
-			Valid:        false,
-			TenantID:     tenant.ID,
-			TenantSlug:   tenant.Slug,
-			ErrorCode:    "INVALID_CREDENTIALS",
-			ErrorMessage: "Invalid email or password",
-		}, nil
+		return s.recordFailedAttempt(ctx, tenant.ID, req, lockout, &ValidateCredentialsResponse{
+			Valid:             false,
+			TenantID:          tenant.ID,
+			TenantSlug:        tenant.Slug,
+			RemainingAttempts: lockout.MaxAttempts,
+			ErrorCode:         "INVALID_CREDENTIALS",
+			ErrorMessage:      "Invalid email or password",
+		}), nil
 	}
 
 	// Check staff is active
@@ -429,16 +569,18 @@ func (s *TenantAuthService) validateStaffCredentials(ctx context.Context, tenant
 	if kcErr != nil {
 		log.Printf("[TenantAuthService] Keycloak staff password validation failed: %v", kcErr)
 		s.logFailedAuthEvent(ctx, tenant.ID, &staffInfo.ID, req.Email, req.IPAddress, req.UserAgent, "INVALID_PASSWORD")
-		return &ValidateCredentialsResponse{
-			Valid:        false,
-			TenantID:     tenant.ID,
-			TenantSlug:   tenant.Slug,
-			ErrorCode:    "INVALID_CREDENTIALS",
-			ErrorMessage: "Invalid email or password",
-		}, nil
+		return s.recordFailedAttempt(ctx, tenant.ID, req, lockout, &ValidateCredentialsResponse{
+			Valid:             false,
+			TenantID:          tenant.ID,
+			TenantSlug:        tenant.Slug,
+			RemainingAttempts: lockout.MaxAttempts,
+			ErrorCode:         "INVALID_CREDENTIALS",
+			ErrorMessage:      "Invalid email or password",
+		}), nil
 	}
 
 	log.Printf("[TenantAuthService] Staff password validation succeeded for %s", security.MaskEmail(req.Email))
+	s.attemptGuard.Reset(ctx, tenant.ID, req.Email, req.IPAddress)
 
 	// Extract actual Keycloak user ID from access token and sync if different
 	if tokens != nil && tokens.AccessToken != "" {
@@ -492,10 +634,12 @@ func (s *TenantAuthService) validateStaffCredentials(ctx context.Context, tenant
 
 	// Attach tokens
 	if tokens != nil {
+		response.SessionID = s.startSession(ctx, tenant.ID, &staffInfo.ID, staffInfo.KeycloakUserID, req.AuthContext, req.IPAddress, req.UserAgent, req.RememberMe, tokens)
 		response.AccessToken = tokens.AccessToken
 		response.RefreshToken = tokens.RefreshToken
 		response.IDToken = tokens.IDToken
 		response.ExpiresIn = tokens.ExpiresIn
+		response.RefreshExpiresIn = tokens.RefreshExpiresIn
 	}
 
 	return response, nil
@@ -538,11 +682,8 @@ func (s *TenantAuthService) ChangePassword(ctx context.Context, userID, tenantID
 	}
 
 	// Validate new password against tenant's policy
-	policy, _ := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
-	if policy != nil {
-		if err := s.validatePasswordPolicy(newPassword, policy); err != nil {
-			return err
-		}
+	if err := s.passwordPolicy.ValidatePassword(ctx, tenantID, newPassword); err != nil {
+		return err
 	}
 
 	// Update password in Keycloak
@@ -550,6 +691,11 @@ func (s *TenantAuthService) ChangePassword(ctx context.Context, userID, tenantID
 		return fmt.Errorf("failed to update password in Keycloak: %w", err)
 	}
 
+	// Restart the rotation clock
+	if err := s.passwordPolicy.RecordPasswordChange(ctx, user.ID, tenantID); err != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to record password change: %v", err)
+	}
+
 	// Log password change event (use user.ID for consistent audit trail)
 	localUserID := user.ID
 	auditLog := &models.TenantAuthAuditLog{
@@ -581,11 +727,8 @@ func (s *TenantAuthService) SetPassword(ctx context.Context, userID, tenantID uu
 	}
 
 	// Validate password against tenant's policy
-	policy, _ := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
-	if policy != nil {
-		if err := s.validatePasswordPolicy(password, policy); err != nil {
-			return err
-		}
+	if err := s.passwordPolicy.ValidatePassword(ctx, tenantID, password); err != nil {
+		return err
 	}
 
 	// Update password in Keycloak (single source of truth)
@@ -613,78 +756,22 @@ func (s *TenantAuthService) SetPassword(ctx context.Context, userID, tenantID uu
 		}
 	}
 
-	return nil
-}
-
-// validatePasswordPolicy validates a password against the tenant's policy
-func (s *TenantAuthService) validatePasswordPolicy(password string, policy *models.TenantAuthPolicy) error {
-	if len(password) < policy.PasswordMinLength {
-		return fmt.Errorf("password must be at least %d characters", policy.PasswordMinLength)
-	}
-	if len(password) > policy.PasswordMaxLength {
-		return fmt.Errorf("password must be at most %d characters", policy.PasswordMaxLength)
+	// Restart the rotation clock
+	if err := s.passwordPolicy.RecordPasswordChange(ctx, user.ID, tenantID); err != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to record password change: %v", err)
 	}
 
-	if policy.PasswordRequireUppercase {
-		hasUpper := false
-		for _, c := range password {
-			if c >= 'A' && c <= 'Z' {
-				hasUpper = true
-				break
-			}
-		}
-		if !hasUpper {
-			return fmt.Errorf("password must contain at least one uppercase letter")
-		}
+	event := &natsClient.SecurityEvent{
+		EventType:    natsClient.EventSecurityPasswordReset,
+		TenantID:     tenantID.String(),
+		TargetUserID: user.ID.String(),
+		TargetEmail:  user.Email,
+		Reason:       "password_set",
 	}
-
-	if policy.PasswordRequireLowercase {
-		hasLower := false
-		for _, c := range password {
-			if c >= 'a' && c <= 'z' {
-				hasLower = true
-				break
-			}
-		}
-		if !hasLower {
-			return fmt.Errorf("password must contain at least one lowercase letter")
-		}
-	}
-
-	if policy.PasswordRequireNumbers {
-		hasNumber := false
-		for _, c := range password {
-			if c >= '0' && c <= '9' {
-				hasNumber = true
-				break
-			}
-		}
-		if !hasNumber {
-			return fmt.Errorf("password must contain at least one number")
-		}
-	}
-
-	if policy.PasswordRequireSpecialChars {
-		hasSpecial := false
-		specialChars := policy.PasswordSpecialChars
-		if specialChars == "" {
-			specialChars = "!@#$%^&*()_+-=[]{}|;:,.<>?"
-		}
-		for _, c := range password {
-			for _, s := range specialChars {
-				if c == s {
-					hasSpecial = true
-					break
-				}
-			}
-			if hasSpecial {
-				break
-			}
-		}
-		if !hasSpecial {
-			return fmt.Errorf("password must contain at least one special character")
-		}
+	if setBy != nil {
+		event.ActorID = setBy.String()
 	}
+	publishSecurityEvent(s.securityEvents, event)
 
 	return nil
 }
@@ -792,6 +879,12 @@ func (s *TenantAuthService) UnlockAccount(ctx context.Context, userID, tenantID
 		log.Printf("[TenantAuthService] Warning: Failed to log unlock event: %v", auditErr)
 	}
 
+	// Also lift the tenant+email+IP lockouts of the account
+	var user models.User
+	if err := s.db.WithContext(ctx).Select("email").Where("id = ?", userID).First(&user).Error; err == nil {
+		s.attemptGuard.ClearForEmail(ctx, tenantID, user.Email)
+	}
+
 	return nil
 }
 
@@ -852,10 +945,12 @@ type RegisterCustomerResponse struct {
 	ErrorMessage   string     `json:"error_message,omitempty"`
 
 	// Keycloak tokens for immediate login after registration
-	AccessToken  string `json:"access_token,omitempty"`
-	RefreshToken string `json:"refresh_token,omitempty"`
-	IDToken      string `json:"id_token,omitempty"`
-	ExpiresIn    int    `json:"expires_in,omitempty"`
+	AccessToken      string `json:"access_token,omitempty"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	IDToken          string `json:"id_token,omitempty"`
+	ExpiresIn        int    `json:"expires_in,omitempty"`
+	RefreshExpiresIn int    `json:"refresh_expires_in,omitempty"`
+	SessionID        string `json:"session_id,omitempty"`
 }
 
 // RegisterCustomer registers a new customer for the storefront
@@ -920,17 +1015,18 @@ func (s *TenantAuthService) RegisterCustomer(ctx context.Context, req *RegisterC
 	}
 
 	// Validate password against tenant's policy
-	policy, _ := s.credentialRepo.GetAuthPolicy(ctx, tenant.ID)
-	if policy != nil {
-		if err := s.validatePasswordPolicy(req.Password, policy); err != nil {
-			return &RegisterCustomerResponse{
-				Success:      false,
-				TenantID:     tenant.ID,
-				TenantSlug:   tenant.Slug,
-				ErrorCode:    "INVALID_PASSWORD",
-				ErrorMessage: err.Error(),
-			}, nil
+	if err := s.passwordPolicy.ValidatePassword(ctx, tenant.ID, req.Password); err != nil {
+		validationErr, ok := IsValidationError(err)
+		if !ok {
+			return nil, fmt.Errorf("failed to validate password: %w", err)
 		}
+		return &RegisterCustomerResponse{
+			Success:      false,
+			TenantID:     tenant.ID,
+			TenantSlug:   tenant.Slug,
+			ErrorCode:    "INVALID_PASSWORD",
+			ErrorMessage: validationErr.Message,
+		}, nil
 	}
 
 	// Check if user already exists in Keycloak (may have registered on another storefront)
@@ -1069,6 +1165,7 @@ func (s *TenantAuthService) RegisterCustomer(ctx context.Context, req *RegisterC
 			"INSERT INTO user_tenant_memberships (user_id, tenant_id, role, is_active) VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING",
 			membership.UserID, membership.TenantID, membership.Role, membership.IsActive,
 		)
+		s.membershipRepo.InvalidateUserMembershipsCache(ctx, user.ID)
 	}
 
 	// Add user to tenant's Keycloak Organization for identity isolation
@@ -1162,10 +1259,12 @@ func (s *TenantAuthService) RegisterCustomer(ctx context.Context, req *RegisterC
 
 	// Attach tokens if available
 	if tokens != nil {
+		response.SessionID = s.startSession(ctx, tenant.ID, &user.ID, keycloakUserID, "customer", req.IPAddress, req.UserAgent, false, tokens)
 		response.AccessToken = tokens.AccessToken
 		response.RefreshToken = tokens.RefreshToken
 		response.IDToken = tokens.IDToken
 		response.ExpiresIn = tokens.ExpiresIn
+		response.RefreshExpiresIn = tokens.RefreshExpiresIn
 	}
 
 	return response, nil
@@ -1244,6 +1343,10 @@ type SecurityPolicyResponse struct {
 	SessionTimeoutMinutes  int `json:"session_timeout_minutes"`
 	MaxConcurrentSessions  int `json:"max_concurrent_sessions"`
 
+	// Session lifetime policy
+	SessionIdleTimeoutMinutes int `json:"session_idle_timeout_minutes"`
+	SessionRememberMeDays     int `json:"session_remember_me_days"`
+
 	// Progressive lockout policy (strict 2-tier)
 	// Tier 1 (5 attempts): 30 min temporary lockout
 	// Tier 2 (7 attempts): Permanent lockout - requires admin unlock or password reset
@@ -1298,6 +1401,8 @@ func (s *TenantAuthService) GetSecurityPolicy(ctx context.Context, tenantID uuid
 		LockoutDurationMinutes:      policy.LockoutDurationMinutes,
 		SessionTimeoutMinutes:       policy.SessionTimeoutMinutes,
 		MaxConcurrentSessions:       policy.MaxConcurrentSessions,
+		SessionIdleTimeoutMinutes:   policy.SessionIdleTimeoutMinutes,
+		SessionRememberMeDays:       policy.SessionRememberMeDays,
 		EnableProgressiveLockout:    policy.EnableProgressiveLockout,
 		Tier1LockoutMinutes:         policy.Tier1LockoutMinutes,
 		PermanentLockoutThreshold:   policy.PermanentLockoutThreshold,
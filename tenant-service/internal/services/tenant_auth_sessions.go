@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Tesseract-Nexus/go-shared/auth"
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+)
+
+// SessionStatus tells other services whether a session may still be used
+type SessionStatus struct {
+	SessionID     string     `json:"session_id"`
+	Active        bool       `json:"active"`
+	TenantID      uuid.UUID  `json:"tenant_id"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	IdleExpiresAt *time.Time `json:"idle_expires_at,omitempty"` // Set when the session has an idle timeout
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason string     `json:"revoked_reason,omitempty"`
+}
+
+// SetSessionRegistry replaces the default database-only session registry (e.g. to add the Redis cache)
+func (s *TenantAuthService) SetSessionRegistry(sessions *SessionRegistry) {
+	s.sessions = sessions
+}
+
+// ListSessions returns the user's active sessions for a tenant, marking currentSessionID as current.
+// subject is the JWT subject (Keycloak user ID).
+func (s *TenantAuthService) ListSessions(ctx context.Context, subject string, tenantID uuid.UUID, currentSessionID string) ([]models.TenantAuthSession, error) {
+	sessions, err := s.sessions.List(ctx, tenantID.String(), subject)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].CurrentSession = currentSessionID != "" && sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends one of the user's sessions for a tenant and records it in the auth audit log
+func (s *TenantAuthService) RevokeSession(ctx context.Context, subject string, tenantID uuid.UUID, sessionID, ipAddress, userAgent string) error {
+	session, err := s.sessions.Revoke(ctx, tenantID.String(), subject, sessionID, subject, SessionRevokedByUser)
+	if err != nil {
+		return err
+	}
+
+	auditLog := &models.TenantAuthAuditLog{
+		TenantID:    tenantID,
+		UserID:      session.UserID,
+		EventType:   models.AuthEventSessionRevoked,
+		EventStatus: models.AuthEventStatusSuccess,
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+		SessionID:   session.ID,
+		Details: models.MustNewJSONB(map[string]interface{}{
+			"revoked_by":         subject,
+			"reason":             session.RevokedReason,
+			"session_ip_address": session.IPAddress,
+			"session_created_at": session.CreatedAt,
+		}),
+	}
+	if auditErr := s.credentialRepo.LogAuthEvent(ctx, auditLog); auditErr != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to log session revoke event: %v", auditErr)
+	}
+	return nil
+}
+
+// GetSessionStatus reports whether a session is still active. Status checks count as use of
+// the session: they keep it from idling out, and a session found idle is revoked.
+func (s *TenantAuthService) GetSessionStatus(ctx context.Context, sessionID string) (*SessionStatus, error) {
+	session, err := s.sessions.Get(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if session.RevokedAt == nil && now.Before(session.ExpiresAt) {
+		if session.IsIdle(now) {
+			s.expireIdleSession(ctx, session)
+		} else if err := s.sessions.Touch(ctx, session); err != nil {
+			log.Printf("[TenantAuthService] Warning: Failed to record session activity: %v", err)
+		}
+	}
+
+	status := &SessionStatus{
+		SessionID:     session.ID,
+		Active:        session.IsActive(now),
+		TenantID:      session.TenantID,
+		ExpiresAt:     session.ExpiresAt,
+		RevokedAt:     session.RevokedAt,
+		RevokedReason: session.RevokedReason,
+	}
+	if session.IdleTimeoutMinutes > 0 && session.RevokedAt == nil {
+		idleExpiresAt := session.IdleExpiresAt()
+		status.IdleExpiresAt = &idleExpiresAt
+	}
+	return status, nil
+}
+
+// expireIdleSession revokes a session that outlived its idle timeout and records it in the
+// auth audit log. session is updated in place; failures leave it inactive anyway.
+func (s *TenantAuthService) expireIdleSession(ctx context.Context, session *models.TenantAuthSession) {
+	revoked, err := s.sessions.Revoke(ctx, session.TenantID.String(), session.Subject, session.ID, "system", SessionRevokedIdleTimeout)
+	if err != nil {
+		if !errors.Is(err, ErrSessionNotFound) {
+			log.Printf("[TenantAuthService] Warning: Failed to revoke idle session %s: %v", session.ID, err)
+		}
+		return
+	}
+	*session = *revoked
+
+	auditLog := &models.TenantAuthAuditLog{
+		TenantID:    session.TenantID,
+		UserID:      session.UserID,
+		EventType:   models.AuthEventSessionRevoked,
+		EventStatus: models.AuthEventStatusSuccess,
+		IPAddress:   session.IPAddress,
+		UserAgent:   session.UserAgent,
+		SessionID:   session.ID,
+		Details: models.MustNewJSONB(map[string]interface{}{
+			"revoked_by":           "system",
+			"reason":               SessionRevokedIdleTimeout,
+			"idle_timeout_minutes": session.IdleTimeoutMinutes,
+			"last_seen_at":         session.LastSeenAt,
+		}),
+	}
+	if auditErr := s.credentialRepo.LogAuthEvent(ctx, auditLog); auditErr != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to log idle session revoke event: %v", auditErr)
+	}
+}
+
+// startSession registers the session behind freshly issued tokens and returns its ID.
+// The tenant's session policy sets the session lifetime and caps the token lifetimes in
+// tokens, so callers must copy the tokens after starting the session.
+// Failures are logged rather than failing the login.
+func (s *TenantAuthService) startSession(ctx context.Context, tenantID uuid.UUID, userID *uuid.UUID, keycloakUserID, authContext, ipAddress, userAgent string, rememberMe bool, tokens *auth.TokenResponse) string {
+	if tokens == nil || tokens.AccessToken == "" {
+		return ""
+	}
+
+	policy, err := s.credentialRepo.GetAuthPolicy(ctx, tenantID)
+	if err != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to load session policy, using defaults: %v", err)
+	}
+	settings := policy.SessionSettings()
+	if rememberMe && settings.RememberMeDays == 0 {
+		rememberMe = false
+	}
+	lifetime := settings.Lifetime(rememberMe)
+	ApplySessionPolicy(tokens, settings, rememberMe)
+
+	claims := tokenClaims(tokens.AccessToken)
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		subject = keycloakUserID
+	}
+	if subject == "" && userID != nil {
+		subject = userID.String()
+	}
+	sessionID, _ := claims["sid"].(string)
+	if sessionID == "" {
+		sessionID, _ = claims["session_state"].(string)
+	}
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+
+	session := &models.TenantAuthSession{
+		ID:                 sessionID,
+		TenantID:           tenantID,
+		Subject:            subject,
+		UserID:             userID,
+		AuthContext:        authContext,
+		IPAddress:          ipAddress,
+		UserAgent:          userAgent,
+		ExpiresAt:          time.Now().Add(lifetime),
+		IdleTimeoutMinutes: settings.IdleMinutes,
+		RememberMe:         rememberMe,
+	}
+	if err := s.sessions.Register(ctx, session); err != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to register session: %v", err)
+		return ""
+	}
+
+	auditLog := &models.TenantAuthAuditLog{
+		TenantID:    tenantID,
+		UserID:      userID,
+		EventType:   models.AuthEventSessionCreated,
+		EventStatus: models.AuthEventStatusSuccess,
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+		SessionID:   sessionID,
+	}
+	if err := s.credentialRepo.LogAuthEvent(ctx, auditLog); err != nil {
+		log.Printf("[TenantAuthService] Warning: Failed to log session created event: %v", err)
+	}
+	return sessionID
+}
+
+// ApplySessionPolicy caps the lifetimes Keycloak reported for freshly issued tokens to the
+// tenant's session policy. Keycloak's token endpoint takes no per-request lifetimes, so the
+// realm settings stay the upper bound and the capped values tell clients when to stop using
+// the tokens: the refresh token expires with the session or after one idle period, whichever
+// is sooner, and the access token never outlives the refresh token.
+func ApplySessionPolicy(tokens *auth.TokenResponse, settings models.SessionSettings, rememberMe bool) {
+	limit := int(settings.Lifetime(rememberMe) / time.Second)
+	if idle := int(settings.IdleTimeout() / time.Second); idle > 0 && idle < limit {
+		limit = idle
+	}
+	if tokens.RefreshExpiresIn <= 0 || tokens.RefreshExpiresIn > limit {
+		tokens.RefreshExpiresIn = limit
+	}
+	if tokens.ExpiresIn > tokens.RefreshExpiresIn {
+		tokens.ExpiresIn = tokens.RefreshExpiresIn
+	}
+}
+
+// tokenClaims decodes a JWT payload without verifying it (the token was just issued by Keycloak)
+func tokenClaims(token string) map[string]interface{} {
+	parts := strings.Split(token, ".")
+	if len(parts) < 2 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil
+	}
+	var claims map[string]interface{}
+	if json.Unmarshal(payload, &claims) != nil {
+		return nil
+	}
+	return claims
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"tenant-service/internal/models"
+	"tenant-service/internal/redis"
+)
+
+const (
+	// MaxBulkTenantLookup is the maximum number of IDs and slugs accepted by one bulk lookup
+	MaxBulkTenantLookup = 500
+
+	// tenantSummaryCacheTTL bounds how stale a cached summary can be, since tenant
+	// updates happen across several services and are not individually invalidated
+	tenantSummaryCacheTTL = 5 * time.Minute
+
+	tenantSummarySlugKeyPrefix = "slug:"
+)
+
+// BulkTenantLookupRequest lists tenants to resolve by ID and/or slug
+type BulkTenantLookupRequest struct {
+	IDs   []string `json:"ids"`
+	Slugs []string `json:"slugs"`
+}
+
+// BulkTenantLookupResult maps each requested ID or slug to its outcome
+// Lookups succeed or fail per tenant: a bad ID or a failed query does not fail the whole request
+type BulkTenantLookupResult struct {
+	Tenants  map[string]*TenantBasicInfo `json:"tenants"`          // Keyed by the ID or slug as requested
+	NotFound []string                    `json:"notFound"`         // Requested IDs/slugs with no matching tenant
+	Errors   map[string]string           `json:"errors,omitempty"` // Requested IDs/slugs that could not be looked up
+	Cached   int                         `json:"cached"`           // Number of tenants served from cache
+}
+
+// SetCache enables Redis caching of tenant summaries for bulk lookups
+func (s *TenantService) SetCache(redisClient *redis.Client) {
+	s.redisClient = redisClient
+}
+
+// BulkGetTenants resolves up to MaxBulkTenantLookup tenants by ID or slug in two queries,
+// serving cached summaries first. Unlike GetTenantBySlug it does not fall back to storefront slugs.
+func (s *TenantService) BulkGetTenants(ctx context.Context, req *BulkTenantLookupRequest) (*BulkTenantLookupResult, error) {
+	ids := dedupeRefs(req.IDs, false)
+	slugs := dedupeRefs(req.Slugs, true)
+	if len(ids)+len(slugs) == 0 {
+		return nil, NewValidationError("ids", "at least one tenant ID or slug is required", nil)
+	}
+	if len(ids)+len(slugs) > MaxBulkTenantLookup {
+		return nil, NewValidationError("ids", fmt.Sprintf("at most %d tenant IDs and slugs may be requested", MaxBulkTenantLookup), nil)
+	}
+
+	result := &BulkTenantLookupResult{
+		Tenants:  make(map[string]*TenantBasicInfo, len(ids)+len(slugs)),
+		NotFound: []string{},
+		Errors:   make(map[string]string),
+	}
+
+	// Requested ref -> cache key ("<id>" or "slug:<slug>")
+	cacheKeys := make(map[string]string, len(ids)+len(slugs))
+	parsedIDs := make(map[string]uuid.UUID, len(ids))
+	for _, id := range ids {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			result.Errors[id] = "invalid tenant ID format"
+			continue
+		}
+		parsedIDs[id] = parsed
+		cacheKeys[id] = parsed.String()
+	}
+	for _, slug := range slugs {
+		cacheKeys[slug] = tenantSummarySlugKeyPrefix + slug
+	}
+
+	s.loadCachedSummaries(ctx, cacheKeys, result)
+
+	// Query what the cache did not have
+	var missingIDs []uuid.UUID
+	for ref, id := range parsedIDs {
+		if _, ok := result.Tenants[ref]; !ok {
+			missingIDs = append(missingIDs, id)
+		}
+	}
+	var missingSlugs []string
+	for _, slug := range slugs {
+		if _, ok := result.Tenants[slug]; !ok {
+			missingSlugs = append(missingSlugs, slug)
+		}
+	}
+
+	toCache := make(map[string]*TenantBasicInfo)
+
+	if len(missingIDs) > 0 {
+		var tenants []models.Tenant
+		if err := s.db.WithContext(ctx).Where("id IN ?", missingIDs).Find(&tenants).Error; err != nil {
+			log.Printf("[TenantService] Bulk lookup by ID failed: %v", err)
+			for ref := range parsedIDs {
+				if _, ok := result.Tenants[ref]; !ok {
+					result.Errors[ref] = "lookup failed"
+				}
+			}
+		} else {
+			byID := make(map[uuid.UUID]*TenantBasicInfo, len(tenants))
+			for i := range tenants {
+				byID[tenants[i].ID] = toTenantBasicInfo(&tenants[i])
+			}
+			for ref, id := range parsedIDs {
+				if _, ok := result.Tenants[ref]; ok {
+					continue
+				}
+				if info, ok := byID[id]; ok {
+					result.Tenants[ref] = info
+					toCache[cacheKeys[ref]] = info
+				} else {
+					result.NotFound = append(result.NotFound, ref)
+				}
+			}
+		}
+	}
+
+	if len(missingSlugs) > 0 {
+		var tenants []models.Tenant
+		if err := s.db.WithContext(ctx).Where("slug IN ?", missingSlugs).Find(&tenants).Error; err != nil {
+			log.Printf("[TenantService] Bulk lookup by slug failed: %v", err)
+			for _, slug := range missingSlugs {
+				result.Errors[slug] = "lookup failed"
+			}
+		} else {
+			bySlug := make(map[string]*TenantBasicInfo, len(tenants))
+			for i := range tenants {
+				bySlug[tenants[i].Slug] = toTenantBasicInfo(&tenants[i])
+			}
+			for _, slug := range missingSlugs {
+				if info, ok := bySlug[slug]; ok {
+					result.Tenants[slug] = info
+					toCache[cacheKeys[slug]] = info
+				} else {
+					result.NotFound = append(result.NotFound, slug)
+				}
+			}
+		}
+	}
+
+	s.saveCachedSummaries(ctx, toCache)
+
+	return result, nil
+}
+
+// loadCachedSummaries fills result with cached summaries; cache errors are logged and treated as misses
+func (s *TenantService) loadCachedSummaries(ctx context.Context, cacheKeys map[string]string, result *BulkTenantLookupResult) {
+	if s.redisClient == nil || len(cacheKeys) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(cacheKeys))
+	for _, key := range cacheKeys {
+		keys = append(keys, key)
+	}
+
+	cached, err := s.redisClient.GetTenantSummaries(ctx, keys)
+	if err != nil {
+		log.Printf("[TenantService] WARN: tenant summary cache read failed: %v", err)
+		return
+	}
+
+	for ref, key := range cacheKeys {
+		data, ok := cached[key]
+		if !ok {
+			continue
+		}
+		var info TenantBasicInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			continue
+		}
+		result.Tenants[ref] = &info
+		result.Cached++
+	}
+}
+
+// saveCachedSummaries caches found tenants; failures only cost a future cache miss
+func (s *TenantService) saveCachedSummaries(ctx context.Context, summaries map[string]*TenantBasicInfo) {
+	if s.redisClient == nil || len(summaries) == 0 {
+		return
+	}
+
+	data := make(map[string][]byte, len(summaries))
+	for key, info := range summaries {
+		encoded, err := json.Marshal(info)
+		if err != nil {
+			continue
+		}
+		data[key] = encoded
+	}
+
+	if err := s.redisClient.SaveTenantSummaries(ctx, data, tenantSummaryCacheTTL); err != nil {
+		log.Printf("[TenantService] WARN: tenant summary cache write failed: %v", err)
+	}
+}
+
+// toTenantBasicInfo converts a tenant to the summary returned to internal callers
+func toTenantBasicInfo(tenant *models.Tenant) *TenantBasicInfo {
+	return &TenantBasicInfo{
+		ID:              tenant.ID.String(),
+		Slug:            tenant.Slug,
+		Name:            tenant.Name,
+		DisplayName:     tenant.DisplayName,
+		Subdomain:       tenant.Subdomain,
+		BillingEmail:    tenant.BillingEmail,
+		Status:          tenant.Status,
+		PricingTier:     tenant.PricingTier,
+		BillingStatus:   tenant.BillingStatus,
+		StorefrontURL:   tenant.StorefrontURL,
+		AdminURL:        tenant.AdminURL,
+		APIURL:          tenant.APIURL,
+		CustomDomain:    tenant.CustomDomain,
+		UseCustomDomain: tenant.UseCustomDomain,
+	}
+}
+
+// dedupeRefs trims and de-duplicates IDs or slugs, dropping empty values
+func dedupeRefs(refs []string, lowercase bool) []string {
+	seen := make(map[string]bool, len(refs))
+	out := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if lowercase {
+			ref = strings.ToLower(ref)
+		}
+		if ref == "" || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		out = append(out, ref)
+	}
+	return out
+}
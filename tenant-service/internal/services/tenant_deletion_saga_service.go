@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	natsClient "tenant-service/internal/nats"
+)
+
+// ErrDeletionNotFound is returned when no deletion saga exists for a tenant
+var ErrDeletionNotFound = errors.New("tenant deletion not found")
+
+// TenantDeletionSagaService coordinates tenant data purges across services
+// After the tenant is removed locally, each participating service is asked to purge its data
+// via tenant.deletion.requested and must reply with tenant.deletion.acknowledged.
+// Services that do not acknowledge are re-requested until MaxAttempts is reached.
+type TenantDeletionSagaService struct {
+	db            *gorm.DB
+	natsClient    *natsClient.Client
+	participants  []string
+	retryInterval time.Duration
+	maxAttempts   int
+}
+
+// NewTenantDeletionSagaService creates a new tenant deletion saga coordinator
+func NewTenantDeletionSagaService(db *gorm.DB, nc *natsClient.Client, cfg config.DeletionConfig) *TenantDeletionSagaService {
+	retryInterval := time.Duration(cfg.RetryIntervalMinutes) * time.Minute
+	if retryInterval <= 0 {
+		retryInterval = 15 * time.Minute
+	}
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+
+	return &TenantDeletionSagaService{
+		db:            db,
+		natsClient:    nc,
+		participants:  cfg.Participants,
+		retryInterval: retryInterval,
+		maxAttempts:   maxAttempts,
+	}
+}
+
+// RetryInterval returns how long a service has to acknowledge before it is re-requested
+func (s *TenantDeletionSagaService) RetryInterval() time.Duration {
+	return s.retryInterval
+}
+
+// TenantDeletionServiceStatus is the per-service view returned by the status endpoint
+type TenantDeletionServiceStatus struct {
+	Service         string     `json:"service"`
+	Status          string     `json:"status"`
+	Attempts        int        `json:"attempts"`
+	ItemsPurged     int64      `json:"items_purged"`
+	LastError       string     `json:"last_error,omitempty"`
+	LastRequestedAt *time.Time `json:"last_requested_at,omitempty"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+// TenantDeletionStatusResponse summarises the saga progress for a tenant
+type TenantDeletionStatusResponse struct {
+	TenantID       string                        `json:"tenant_id"`
+	Slug           string                        `json:"slug"`
+	DeletedAt      time.Time                     `json:"deleted_at"`
+	Completed      bool                          `json:"completed"`
+	CompletedAt    *time.Time                    `json:"completed_at,omitempty"`
+	PurgedCount    int                           `json:"purged_count"`
+	PendingCount   int                           `json:"pending_count"`
+	FailedCount    int                           `json:"failed_count"`
+	ExhaustedCount int                           `json:"exhausted_count"`
+	Services       []TenantDeletionServiceStatus `json:"services"`
+}
+
+// Start records a pending status for every participant and publishes the first deletion request
+// Called after the tenant has been archived and deleted locally; failures are logged and left
+// for RetryLaggards so the user-facing deletion is never blocked on other services
+func (s *TenantDeletionSagaService) Start(ctx context.Context, deleted *models.DeletedTenant) error {
+	if len(s.participants) == 0 {
+		log.Printf("[DeletionSaga] No participants configured, skipping saga for tenant %s", deleted.OriginalTenantID)
+		return nil
+	}
+
+	statuses := make([]models.TenantDeletionStatus, 0, len(s.participants))
+	for _, service := range s.participants {
+		statuses = append(statuses, models.TenantDeletionStatus{
+			TenantID:        deleted.OriginalTenantID,
+			DeletedTenantID: deleted.ID,
+			Slug:            deleted.Slug,
+			ServiceName:     service,
+			Status:          models.DeletionStatusPending,
+		})
+	}
+
+	if err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&statuses).Error; err != nil {
+		return fmt.Errorf("failed to create deletion statuses: %w", err)
+	}
+
+	log.Printf("[DeletionSaga] Started deletion saga for tenant %s (%s) with %d participants", deleted.Slug, deleted.OriginalTenantID, len(statuses))
+
+	return s.request(ctx, deleted.OriginalTenantID, deleted.Slug, statuses)
+}
+
+// HandleAcknowledgment applies a participant's purge acknowledgment
+// Unknown tenants or services are ignored so stray acknowledgments are not redelivered forever
+func (s *TenantDeletionSagaService) HandleAcknowledgment(ctx context.Context, event *natsClient.TenantDeletionAcknowledgedEvent) error {
+	tenantID, err := uuid.Parse(event.TenantID)
+	if err != nil {
+		log.Printf("[DeletionSaga] Ignoring acknowledgment with invalid tenant ID %q from %s", event.TenantID, event.Service)
+		return nil
+	}
+
+	var status models.TenantDeletionStatus
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND service_name = ?", tenantID, event.Service).
+		First(&status).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[DeletionSaga] Ignoring acknowledgment from %s for unknown deletion of tenant %s", event.Service, tenantID)
+			return nil
+		}
+		return fmt.Errorf("failed to load deletion status: %w", err)
+	}
+
+	if !status.Acknowledge(event.Status, event.ItemsPurged, event.Error, time.Now()) {
+		// Duplicate acknowledgment (e.g. after a re-request) - nothing to do
+		return nil
+	}
+	if status.Status == models.DeletionStatusPurged {
+		log.Printf("[DeletionSaga] %s purged tenant %s (%d items)", event.Service, tenantID, event.ItemsPurged)
+	} else {
+		log.Printf("[DeletionSaga] %s failed to purge tenant %s: %s", event.Service, tenantID, event.Error)
+	}
+
+	if err := s.db.WithContext(ctx).
+		Model(&models.TenantDeletionStatus{}).
+		Where("id = ?", status.ID).
+		Updates(map[string]interface{}{
+			"status":          status.Status,
+			"acknowledged_at": status.AcknowledgedAt,
+			"items_purged":    status.ItemsPurged,
+			"last_error":      status.LastError,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update deletion status: %w", err)
+	}
+
+	return s.completeIfDone(ctx, tenantID, status.DeletedTenantID)
+}
+
+// RetryLaggards re-requests the purge from services that have not acknowledged within the retry interval
+// Returns the number of services re-requested
+func (s *TenantDeletionSagaService) RetryLaggards(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-s.retryInterval)
+
+	var laggards []models.TenantDeletionStatus
+	if err := s.db.WithContext(ctx).
+		Where("status IN ?", []string{models.DeletionStatusPending, models.DeletionStatusFailed}).
+		Where("last_requested_at IS NULL OR last_requested_at < ?", cutoff).
+		Order("tenant_id, service_name").
+		Find(&laggards).Error; err != nil {
+		return 0, fmt.Errorf("failed to load pending deletions: %w", err)
+	}
+
+	byTenant := make(map[uuid.UUID][]models.TenantDeletionStatus)
+	var order []uuid.UUID
+	for _, status := range laggards {
+		if !status.NeedsRetry(cutoff) {
+			continue
+		}
+		if status.Exhaust(s.maxAttempts) {
+			s.exhaust(ctx, status)
+			continue
+		}
+		if _, ok := byTenant[status.TenantID]; !ok {
+			order = append(order, status.TenantID)
+		}
+		byTenant[status.TenantID] = append(byTenant[status.TenantID], status)
+	}
+
+	retried := 0
+	for _, tenantID := range order {
+		statuses := byTenant[tenantID]
+		if err := s.request(ctx, tenantID, statuses[0].Slug, statuses); err != nil {
+			log.Printf("[DeletionSaga] Failed to re-request purge for tenant %s: %v", tenantID, err)
+			continue
+		}
+		retried += len(statuses)
+	}
+
+	return retried, nil
+}
+
+// GetStatus returns the saga progress for a deleted tenant
+func (s *TenantDeletionSagaService) GetStatus(ctx context.Context, tenantID uuid.UUID) (*TenantDeletionStatusResponse, *models.DeletedTenant, error) {
+	var deleted models.DeletedTenant
+	if err := s.db.WithContext(ctx).
+		Where("original_tenant_id = ?", tenantID).
+		Order("deleted_at DESC").
+		First(&deleted).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrDeletionNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get deleted tenant: %w", err)
+	}
+
+	var statuses []models.TenantDeletionStatus
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("service_name").
+		Find(&statuses).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to get deletion statuses: %w", err)
+	}
+
+	response := &TenantDeletionStatusResponse{
+		TenantID:    tenantID.String(),
+		Slug:        deleted.Slug,
+		DeletedAt:   deleted.DeletedAt,
+		CompletedAt: deleted.CleanupCompletedAt,
+		Services:    make([]TenantDeletionServiceStatus, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		switch status.Status {
+		case models.DeletionStatusPurged:
+			response.PurgedCount++
+		case models.DeletionStatusFailed:
+			response.FailedCount++
+		case models.DeletionStatusExhausted:
+			response.ExhaustedCount++
+		default:
+			response.PendingCount++
+		}
+		response.Services = append(response.Services, TenantDeletionServiceStatus{
+			Service:         status.ServiceName,
+			Status:          status.Status,
+			Attempts:        status.Attempts,
+			ItemsPurged:     status.ItemsPurged,
+			LastError:       status.LastError,
+			LastRequestedAt: status.LastRequestedAt,
+			AcknowledgedAt:  status.AcknowledgedAt,
+		})
+	}
+	response.Completed = models.AllPurged(statuses)
+
+	return response, &deleted, nil
+}
+
+// request publishes a deletion request for the given services and bumps their attempt counters
+func (s *TenantDeletionSagaService) request(ctx context.Context, tenantID uuid.UUID, slug string, statuses []models.TenantDeletionStatus) error {
+	services := make([]string, 0, len(statuses))
+	ids := make([]uuid.UUID, 0, len(statuses))
+	attempt := 0
+	for _, status := range statuses {
+		services = append(services, status.ServiceName)
+		ids = append(ids, status.ID)
+		if status.Attempts+1 > attempt {
+			attempt = status.Attempts + 1
+		}
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := s.natsClient.PublishTenantDeletionRequested(publishCtx, &natsClient.TenantDeletionRequestedEvent{
+		TenantID:    tenantID.String(),
+		Slug:        slug,
+		Services:    services,
+		Attempt:     attempt,
+		RequestedAt: time.Now().UTC(),
+	})
+
+	// Count the attempt even if publishing failed so unreachable NATS cannot retry forever
+	updates := map[string]interface{}{
+		"attempts":          gorm.Expr("attempts + 1"),
+		"last_requested_at": time.Now(),
+	}
+	if err != nil {
+		updates["last_error"] = fmt.Sprintf("failed to publish deletion request: %v", err)
+	}
+	if updateErr := s.db.WithContext(ctx).
+		Model(&models.TenantDeletionStatus{}).
+		Where("id IN ?", ids).
+		Updates(updates).Error; updateErr != nil {
+		log.Printf("[DeletionSaga] Failed to record deletion request for tenant %s: %v", tenantID, updateErr)
+	}
+
+	return err
+}
+
+// exhaust persists a service that never confirmed the purge
+func (s *TenantDeletionSagaService) exhaust(ctx context.Context, status models.TenantDeletionStatus) {
+	if err := s.db.WithContext(ctx).
+		Model(&models.TenantDeletionStatus{}).
+		Where("id = ?", status.ID).
+		Updates(map[string]interface{}{
+			"status":     status.Status,
+			"last_error": status.LastError,
+		}).Error; err != nil {
+		log.Printf("[DeletionSaga] Failed to mark %s exhausted for tenant %s: %v", status.ServiceName, status.TenantID, err)
+		return
+	}
+
+	log.Printf("[DeletionSaga] WARNING: %s did not purge tenant %s after %d attempts - manual cleanup required", status.ServiceName, status.TenantID, status.Attempts)
+}
+
+// completeIfDone stamps the archived tenant record once every participant has purged
+func (s *TenantDeletionSagaService) completeIfDone(ctx context.Context, tenantID, deletedTenantID uuid.UUID) error {
+	var statuses []models.TenantDeletionStatus
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Find(&statuses).Error; err != nil {
+		return fmt.Errorf("failed to load deletion statuses: %w", err)
+	}
+
+	if !models.AllPurged(statuses) {
+		return nil
+	}
+
+	cleaned := make(map[string]interface{}, len(statuses))
+	for _, status := range statuses {
+		cleaned[status.ServiceName] = map[string]interface{}{
+			"items_purged":    status.ItemsPurged,
+			"acknowledged_at": status.AcknowledgedAt,
+		}
+	}
+
+	cleanedJSON, err := json.Marshal(cleaned)
+	if err != nil {
+		return fmt.Errorf("failed to serialize cleaned resources: %w", err)
+	}
+
+	now := time.Now()
+	if err := s.db.WithContext(ctx).
+		Model(&models.DeletedTenant{}).
+		Where("id = ?", deletedTenantID).
+		Updates(map[string]interface{}{
+			"resources_cleaned":    models.JSONB(cleanedJSON),
+			"cleanup_completed_at": now,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to mark tenant cleanup complete: %w", err)
+	}
+
+	log.Printf("[DeletionSaga] All %d services purged tenant %s - deletion saga complete", len(statuses), tenantID)
+	return nil
+}
@@ -0,0 +1,553 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/clients"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+const (
+	// tenantExportFormatVersion is bumped whenever the archive layout changes
+	tenantExportFormatVersion = 1
+	// tenantExportBatchSize bounds how many log rows are loaded at once
+	tenantExportBatchSize = 1000
+	// tenantExportTimeout bounds the generation of one archive
+	tenantExportTimeout = 30 * time.Minute
+	// tenantExportStaleAfter is how long an export may stay pending or running before it is restarted
+	tenantExportStaleAfter = time.Hour
+)
+
+var (
+	// ErrTenantExportForbidden is returned when the user is not the tenant owner
+	ErrTenantExportForbidden = errors.New("only the tenant owner can export tenant data")
+	// ErrTenantExportNotFound is returned when the export does not exist for the tenant
+	ErrTenantExportNotFound = errors.New("tenant export not found")
+	// ErrTenantExportNotReady is returned when the archive is not (or no longer) downloadable
+	ErrTenantExportNotReady = errors.New("tenant export is not ready for download")
+)
+
+// TenantExportService generates ZIP archives of everything tenant-service holds for a tenant
+// (profile, memberships, onboarding data, activity and auth audit logs) for data portability
+// requests. Archives are built in the background and the requester is emailed a download link.
+type TenantExportService struct {
+	db                 *gorm.DB
+	membershipRepo     *repository.MembershipRepository
+	notificationClient *clients.NotificationClient
+	config             config.ExportConfig
+}
+
+// NewTenantExportService creates a new tenant export service; notificationClient may be nil
+func NewTenantExportService(db *gorm.DB, notificationClient *clients.NotificationClient, cfg config.ExportConfig) *TenantExportService {
+	if cfg.RetentionHours <= 0 {
+		cfg.RetentionHours = 168
+	}
+	return &TenantExportService{
+		db:                 db,
+		membershipRepo:     repository.NewMembershipRepository(db),
+		notificationClient: notificationClient,
+		config:             cfg,
+	}
+}
+
+// AuthorizeExport checks the user owns the tenant
+func (s *TenantExportService) AuthorizeExport(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || role != models.MembershipRoleOwner {
+		return ErrTenantExportForbidden
+	}
+	return nil
+}
+
+// RequestExport queues a new export and starts generating it in the background.
+// An export that is already pending or running for the tenant is returned instead.
+func (s *TenantExportService) RequestExport(ctx context.Context, tenantID, userID uuid.UUID) (*models.TenantExport, bool, error) {
+	var inFlight models.TenantExport
+	err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND status IN ?", tenantID, []string{models.TenantExportStatusPending, models.TenantExportStatusRunning}).
+		Order("created_at DESC").
+		First(&inFlight).Error
+	if err == nil {
+		return &inFlight, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, fmt.Errorf("failed to check in-flight exports: %w", err)
+	}
+
+	export := &models.TenantExport{
+		TenantID:    tenantID,
+		RequestedBy: userID,
+		Status:      models.TenantExportStatusPending,
+	}
+	if err := s.db.WithContext(ctx).Create(export).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to create tenant export: %w", err)
+	}
+
+	go s.Generate(context.Background(), export.ID)
+	return export, true, nil
+}
+
+// ListExports returns the tenant's exports, newest first
+func (s *TenantExportService) ListExports(ctx context.Context, tenantID uuid.UUID) ([]models.TenantExport, error) {
+	var exports []models.TenantExport
+	if err := s.db.WithContext(ctx).
+		Omit("archive").
+		Where("tenant_id = ?", tenantID).
+		Order("created_at DESC").
+		Limit(50).
+		Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tenant exports: %w", err)
+	}
+	return exports, nil
+}
+
+// GetExport returns one of the tenant's exports without its archive
+func (s *TenantExportService) GetExport(ctx context.Context, tenantID, exportID uuid.UUID) (*models.TenantExport, error) {
+	var export models.TenantExport
+	err := s.db.WithContext(ctx).
+		Omit("archive").
+		Where("id = ? AND tenant_id = ?", exportID, tenantID).
+		First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTenantExportNotFound
+		}
+		return nil, fmt.Errorf("failed to get tenant export: %w", err)
+	}
+	return &export, nil
+}
+
+// OpenArchive returns a downloadable export with its archive; a nil exportID selects the latest one
+func (s *TenantExportService) OpenArchive(ctx context.Context, tenantID uuid.UUID, exportID *uuid.UUID) (*models.TenantExport, error) {
+	query := s.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+	if exportID != nil {
+		query = query.Where("id = ?", *exportID)
+	} else {
+		query = query.Where("status = ?", models.TenantExportStatusCompleted)
+	}
+
+	var export models.TenantExport
+	if err := query.Order("created_at DESC").First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTenantExportNotFound
+		}
+		return nil, fmt.Errorf("failed to load tenant export: %w", err)
+	}
+	return s.downloadable(ctx, &export)
+}
+
+// OpenArchiveByToken returns a downloadable export for the emailed download link
+func (s *TenantExportService) OpenArchiveByToken(ctx context.Context, exportID uuid.UUID, token string) (*models.TenantExport, error) {
+	var export models.TenantExport
+	if err := s.db.WithContext(ctx).Where("id = ?", exportID).First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTenantExportNotFound
+		}
+		return nil, fmt.Errorf("failed to load tenant export: %w", err)
+	}
+
+	hash := hashExportToken(token)
+	if token == "" || export.DownloadTokenHash == "" ||
+		subtle.ConstantTimeCompare([]byte(hash), []byte(export.DownloadTokenHash)) != 1 {
+		return nil, ErrTenantExportNotFound
+	}
+	return s.downloadable(ctx, &export)
+}
+
+// Generate builds the archive for a pending export, stores it and notifies the requester
+func (s *TenantExportService) Generate(ctx context.Context, exportID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(ctx, tenantExportTimeout)
+	defer cancel()
+
+	now := time.Now()
+	result := s.db.WithContext(ctx).Model(&models.TenantExport{}).
+		Where("id = ? AND status = ?", exportID, models.TenantExportStatusPending).
+		Updates(map[string]interface{}{
+			"status":     models.TenantExportStatusRunning,
+			"started_at": now,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		log.Printf("[TenantExport] Failed to start export %s: %v", exportID, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		return // Started by another replica
+	}
+
+	var export models.TenantExport
+	if err := s.db.WithContext(ctx).Omit("archive").First(&export, "id = ?", exportID).Error; err != nil {
+		log.Printf("[TenantExport] Failed to load export %s: %v", exportID, err)
+		return
+	}
+
+	archive, counts, err := s.buildArchive(ctx, export.TenantID)
+	if err != nil {
+		log.Printf("[TenantExport] Export %s for tenant %s failed: %v", exportID, export.TenantID, err)
+		s.db.Model(&models.TenantExport{}).Where("id = ?", exportID).Updates(map[string]interface{}{
+			"status":     models.TenantExportStatusFailed,
+			"error":      err.Error(),
+			"updated_at": time.Now(),
+		})
+		return
+	}
+
+	token, err := generateExportToken()
+	if err != nil {
+		log.Printf("[TenantExport] Failed to generate download token for export %s: %v", exportID, err)
+		return
+	}
+	countsJSON, _ := json.Marshal(counts)
+	checksum := sha256.Sum256(archive)
+	completedAt := time.Now()
+	expiresAt := completedAt.Add(time.Duration(s.config.RetentionHours) * time.Hour)
+
+	if err := s.db.WithContext(ctx).Model(&models.TenantExport{}).Where("id = ?", exportID).Updates(map[string]interface{}{
+		"status":              models.TenantExportStatusCompleted,
+		"archive":             archive,
+		"size_bytes":          int64(len(archive)),
+		"checksum":            hex.EncodeToString(checksum[:]),
+		"record_counts":       models.JSONB(countsJSON),
+		"download_token_hash": hashExportToken(token),
+		"error":               "",
+		"completed_at":        completedAt,
+		"expires_at":          expiresAt,
+		"updated_at":          completedAt,
+	}).Error; err != nil {
+		log.Printf("[TenantExport] Failed to store archive for export %s: %v", exportID, err)
+		return
+	}
+
+	log.Printf("[TenantExport] Export %s for tenant %s completed (%d bytes)", exportID, export.TenantID, len(archive))
+	s.notifyReady(ctx, &export, token, expiresAt)
+}
+
+// RunMaintenance drops expired archives and restarts exports interrupted by a restart
+func (s *TenantExportService) RunMaintenance(ctx context.Context) error {
+	now := time.Now()
+	if err := s.db.WithContext(ctx).Model(&models.TenantExport{}).
+		Where("status = ? AND expires_at < ?", models.TenantExportStatusCompleted, now).
+		Updates(map[string]interface{}{
+			"status":              models.TenantExportStatusExpired,
+			"archive":             nil,
+			"download_token_hash": "",
+			"updated_at":          now,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to expire tenant exports: %w", err)
+	}
+
+	var stale []models.TenantExport
+	if err := s.db.WithContext(ctx).
+		Omit("archive").
+		Where("status IN ? AND updated_at < ?",
+			[]string{models.TenantExportStatusPending, models.TenantExportStatusRunning}, now.Add(-tenantExportStaleAfter)).
+		Find(&stale).Error; err != nil {
+		return fmt.Errorf("failed to load stale tenant exports: %w", err)
+	}
+	for _, export := range stale {
+		result := s.db.WithContext(ctx).Model(&models.TenantExport{}).
+			Where("id = ? AND status = ? AND updated_at = ?", export.ID, export.Status, export.UpdatedAt).
+			Updates(map[string]interface{}{"status": models.TenantExportStatusPending, "updated_at": now})
+		if result.Error == nil && result.RowsAffected > 0 {
+			log.Printf("[TenantExport] Restarting stale export %s", export.ID)
+			s.Generate(ctx, export.ID)
+		}
+	}
+	return nil
+}
+
+// downloadable checks the export's archive can be served and marks it downloaded
+func (s *TenantExportService) downloadable(ctx context.Context, export *models.TenantExport) (*models.TenantExport, error) {
+	if export.Status != models.TenantExportStatusCompleted || len(export.Archive) == 0 ||
+		(export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt)) {
+		return nil, ErrTenantExportNotReady
+	}
+
+	now := time.Now()
+	s.db.WithContext(ctx).Model(&models.TenantExport{}).Where("id = ?", export.ID).Update("downloaded_at", now)
+	export.DownloadedAt = &now
+	return export, nil
+}
+
+// exportMember is a membership joined with its user, without invitation tokens
+type exportMember struct {
+	UserID              uuid.UUID    `json:"user_id"`
+	Email               string       `json:"email"`
+	FirstName           string       `json:"first_name"`
+	LastName            string       `json:"last_name"`
+	Phone               string       `json:"phone,omitempty"`
+	Role                string       `json:"role"`
+	Permissions         models.JSONB `json:"permissions"`
+	IsActive            bool         `json:"is_active"`
+	InvitedBy           *uuid.UUID   `json:"invited_by,omitempty"`
+	InvitedAt           *time.Time   `json:"invited_at,omitempty"`
+	InvitationExpiresAt *time.Time   `json:"invitation_expires_at,omitempty"`
+	AcceptedAt          *time.Time   `json:"accepted_at,omitempty"`
+	LastAccessedAt      *time.Time   `json:"last_accessed_at,omitempty"`
+	CreatedAt           time.Time    `json:"created_at"`
+}
+
+// buildArchive writes every tenant dataset as a JSON file into a ZIP archive
+func (s *TenantExportService) buildArchive(ctx context.Context, tenantID uuid.UUID) ([]byte, map[string]int, error) {
+	db := s.db.WithContext(ctx)
+
+	var tenant models.Tenant
+	if err := db.First(&tenant, "id = ?", tenantID).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load tenant: %w", err)
+	}
+
+	var members []exportMember
+	if err := db.Table("user_tenant_memberships m").
+		Select(`m.user_id, u.email, u.first_name, u.last_name, u.phone, m.role, m.permissions, m.is_active,
+			m.invited_by, m.invited_at, m.invitation_expires_at, m.accepted_at, m.last_accessed_at, m.created_at`).
+		Joins("LEFT JOIN tenant_users u ON u.id = m.user_id").
+		Where("m.tenant_id = ?", tenantID).
+		Order("m.created_at ASC").
+		Scan(&members).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load memberships: %w", err)
+	}
+
+	onboarding, err := s.loadOnboarding(ctx, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	counts := map[string]int{
+		"tenant":      1,
+		"memberships": len(members),
+		"onboarding":  len(onboarding["sessions"].([]models.OnboardingSession)),
+	}
+
+	if err := writeExportJSON(zw, "tenant.json", tenant); err != nil {
+		return nil, nil, err
+	}
+	if err := writeExportJSON(zw, "memberships.json", members); err != nil {
+		return nil, nil, err
+	}
+	if err := writeExportJSON(zw, "onboarding.json", onboarding); err != nil {
+		return nil, nil, err
+	}
+
+	activity, err := writeExportLogs(zw, "activity_logs.json",
+		db.Model(&models.TenantActivityLog{}).Where("tenant_id = ?", tenantID).Order("created_at ASC, id ASC"),
+		func() interface{} { return &[]models.TenantActivityLog{} })
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to export activity logs: %w", err)
+	}
+	counts["activity_logs"] = activity
+
+	audit, err := writeExportLogs(zw, "auth_audit_logs.json",
+		db.Model(&models.TenantAuthAuditLog{}).Where("tenant_id = ?", tenantID).Order("created_at ASC, id ASC"),
+		func() interface{} { return &[]models.TenantAuthAuditLog{} })
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to export auth audit logs: %w", err)
+	}
+	counts["auth_audit_logs"] = audit
+
+	manifest := map[string]interface{}{
+		"format_version": tenantExportFormatVersion,
+		"tenant_id":      tenantID,
+		"tenant_slug":    tenant.Slug,
+		"generated_at":   time.Now().UTC(),
+		"source":         "tenant-service",
+		"record_counts":  counts,
+	}
+	if err := writeExportJSON(zw, "manifest.json", manifest); err != nil {
+		return nil, nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return buf.Bytes(), counts, nil
+}
+
+// loadOnboarding collects the onboarding sessions that created the tenant and their step data
+func (s *TenantExportService) loadOnboarding(ctx context.Context, tenantID uuid.UUID) (map[string]interface{}, error) {
+	db := s.db.WithContext(ctx)
+
+	var sessions []models.OnboardingSession
+	if err := db.Where("tenant_id = ?", tenantID).Order("created_at ASC").Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load onboarding sessions: %w", err)
+	}
+	sessionIDs := make([]uuid.UUID, len(sessions))
+	for i, session := range sessions {
+		sessionIDs[i] = session.ID
+	}
+
+	var (
+		business     []models.BusinessInformation
+		contacts     []models.ContactInformation
+		addresses    []models.BusinessAddress
+		payments     []models.PaymentInformation
+		applications []models.ApplicationConfiguration
+	)
+	if len(sessionIDs) > 0 {
+		for _, dataset := range []struct {
+			name string
+			dest interface{}
+		}{
+			{"business information", &business},
+			{"contact information", &contacts},
+			{"business addresses", &addresses},
+			{"payment information", &payments},
+			{"application configuration", &applications},
+		} {
+			if err := db.Where("onboarding_session_id IN ?", sessionIDs).Find(dataset.dest).Error; err != nil {
+				return nil, fmt.Errorf("failed to load %s: %w", dataset.name, err)
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"sessions":                   sessions,
+		"business_information":       business,
+		"contact_information":        contacts,
+		"business_addresses":         addresses,
+		"payment_information":        payments,
+		"application_configurations": applications,
+	}, nil
+}
+
+// notifyReady emails the requester a download link
+func (s *TenantExportService) notifyReady(ctx context.Context, export *models.TenantExport, token string, expiresAt time.Time) {
+	if s.notificationClient == nil {
+		return
+	}
+
+	var tenant models.Tenant
+	if err := s.db.WithContext(ctx).Select("id", "name", "display_name", "admin_url", "billing_email").
+		First(&tenant, "id = ?", export.TenantID).Error; err != nil {
+		log.Printf("[TenantExport] Failed to load tenant for export notification: %v", err)
+		return
+	}
+
+	var user models.User
+	email := tenant.BillingEmail
+	if err := s.db.WithContext(ctx).
+		Where("id = ? OR keycloak_id = ?", export.RequestedBy, export.RequestedBy).
+		First(&user).Error; err == nil {
+		email = user.Email
+	}
+	if email == "" {
+		log.Printf("[TenantExport] No recipient for export %s notification", export.ID)
+		return
+	}
+
+	baseURL := s.config.PublicBaseURL
+	if baseURL == "" {
+		baseURL = tenant.AdminURL
+	}
+	storeName := tenant.DisplayName
+	if storeName == "" {
+		storeName = tenant.Name
+	}
+
+	err := s.notificationClient.SendDataExportReadyEmail(ctx, &clients.DataExportReadyEmailData{
+		Email:        email,
+		FirstName:    user.FirstName,
+		StoreName:    storeName,
+		DownloadLink: TenantExportDownloadURL(baseURL, export.ID, token),
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		log.Printf("[TenantExport] Failed to send export notification for %s: %v", export.ID, err)
+	}
+}
+
+// TenantExportDownloadURL builds the tokenized public download link of an export
+func TenantExportDownloadURL(baseURL string, exportID uuid.UUID, token string) string {
+	return fmt.Sprintf("%s/api/v1/tenant-exports/%s/download?token=%s", strings.TrimRight(baseURL, "/"), exportID, token)
+}
+
+func writeExportJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeExportLogs streams a log table into a JSON array in batches so large histories
+// are never loaded at once; newBatch returns a pointer to an empty slice of the row type
+func writeExportLogs(zw *zip.Writer, name string, query *gorm.DB, newBatch func() interface{}) (int, error) {
+	w, err := zw.Create(name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := w.Write([]byte("[")); err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for offset := 0; ; offset += tenantExportBatchSize {
+		batch := newBatch()
+		if err := query.Session(&gorm.Session{}).Offset(offset).Limit(tenantExportBatchSize).Find(batch).Error; err != nil {
+			return total, err
+		}
+
+		n := reflect.ValueOf(batch).Elem().Len()
+		if n == 0 {
+			break
+		}
+
+		encoded, err := json.Marshal(batch)
+		if err != nil {
+			return total, err
+		}
+		if total > 0 {
+			if _, err := w.Write([]byte(",")); err != nil {
+				return total, err
+			}
+		}
+		if _, err := w.Write(encoded[1 : len(encoded)-1]); err != nil {
+			return total, err
+		}
+
+		total += n
+		if n < tenantExportBatchSize {
+			break
+		}
+	}
+
+	_, err = w.Write([]byte("]\n"))
+	return total, err
+}
+
+func generateExportToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashExportToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,486 @@
+package services
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+// reencryptBatchSize is the number of credential rows re-encrypted per transaction
+const reencryptBatchSize = 100
+
+// ErrKeyManagementForbidden is returned when a non owner/admin manages tenant keys
+var ErrKeyManagementForbidden = errors.New("only tenant owners and admins can manage encryption keys")
+
+// TenantKeyService manages per-tenant data-encryption keys for credential data
+// Each tenant has one active DEK; rotation creates a new version and re-encrypts
+// existing rows in the background. Rows record the key version that protects them.
+type TenantKeyService struct {
+	db             *gorm.DB
+	wrapper        KeyWrapper
+	membershipRepo *repository.MembershipRepository
+	config         config.EncryptionConfig
+
+	// Unwrapped DEKs keyed by tenant and version, so KMS is not called per operation
+	cacheMu sync.RWMutex
+	cache   map[string]cipher.AEAD
+
+	// Prevents concurrent re-encryption runs for the same tenant
+	reencryptMu  sync.Mutex
+	reencrypting map[uuid.UUID]bool
+}
+
+// NewTenantKeyService creates a new tenant key service
+func NewTenantKeyService(db *gorm.DB, wrapper KeyWrapper, cfg config.EncryptionConfig) *TenantKeyService {
+	return &TenantKeyService{
+		db:             db,
+		wrapper:        wrapper,
+		membershipRepo: repository.NewMembershipRepository(db),
+		config:         cfg,
+		cache:          make(map[string]cipher.AEAD),
+		reencrypting:   make(map[uuid.UUID]bool),
+	}
+}
+
+// ReencryptInterval returns how often the background re-encryption job should run
+func (s *TenantKeyService) ReencryptInterval() time.Duration {
+	if s.config.ReencryptIntervalMinutes <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(s.config.ReencryptIntervalMinutes) * time.Minute
+}
+
+// AuthorizeKeyManagement verifies the user is an owner or admin of the tenant
+func (s *TenantKeyService) AuthorizeKeyManagement(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil {
+		return ErrKeyManagementForbidden
+	}
+	if role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin {
+		return ErrKeyManagementForbidden
+	}
+	return nil
+}
+
+// TenantKeyStatus summarises a tenant's key versions and re-encryption progress
+type TenantKeyStatus struct {
+	TenantID             string                       `json:"tenant_id"`
+	ActiveVersion        int                          `json:"active_version"`
+	MasterKeyID          string                       `json:"master_key_id"`
+	Keys                 []models.TenantEncryptionKey `json:"keys"`
+	RowsOnActiveKey      int64                        `json:"rows_on_active_key"`
+	RowsPendingReencrypt int64                        `json:"rows_pending_reencrypt"`
+	ReencryptRunning     bool                         `json:"reencrypt_running"`
+}
+
+// Encrypt encrypts credential data with the tenant's active key
+// Returns the ciphertext and the key version that must be stored alongside it
+func (s *TenantKeyService) Encrypt(ctx context.Context, tenantID uuid.UUID, plaintext, additionalData string) (string, int, error) {
+	key, err := s.activeKey(ctx, tenantID, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	aead, err := s.aead(ctx, key)
+	if err != nil {
+		return "", 0, err
+	}
+	ciphertext, err := sealBase64(aead, []byte(plaintext), credentialAAD(tenantID, key.Version, additionalData))
+	if err != nil {
+		return "", 0, err
+	}
+	return ciphertext, key.Version, nil
+}
+
+// Decrypt decrypts credential data protected by the given key version
+// Version 0 denotes legacy plaintext written before per-tenant encryption was enabled
+func (s *TenantKeyService) Decrypt(ctx context.Context, tenantID uuid.UUID, version int, ciphertext, additionalData string) (string, error) {
+	if version == 0 || ciphertext == "" {
+		return ciphertext, nil
+	}
+
+	var key models.TenantEncryptionKey
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND version = ?", tenantID, version).
+		First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("encryption key version %d not found for tenant", version)
+		}
+		return "", fmt.Errorf("failed to load encryption key: %w", err)
+	}
+	aead, err := s.aead(ctx, &key)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := openBase64(aead, ciphertext, credentialAAD(tenantID, version, additionalData))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// RotateKey creates a new active key version for the tenant and starts re-encryption
+// The previous active key becomes decrypt-only until no rows reference it
+func (s *TenantKeyService) RotateKey(ctx context.Context, tenantID uuid.UUID, rotatedBy *uuid.UUID) (*models.TenantEncryptionKey, error) {
+	var newKey *models.TenantEncryptionKey
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.TenantEncryptionKey
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND status = ?", tenantID, models.EncryptionKeyStatusActive).
+			First(&current).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to load active key: %w", err)
+		}
+
+		nextVersion := 1
+		if err == nil {
+			now := time.Now()
+			if err := tx.Model(&current).Updates(map[string]interface{}{
+				"status":     models.EncryptionKeyStatusDecryptOnly,
+				"rotated_at": now,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to demote active key: %w", err)
+			}
+			nextVersion = current.Version + 1
+		}
+
+		created, err := s.createKey(ctx, tx, tenantID, nextVersion, rotatedBy)
+		if err != nil {
+			return err
+		}
+		newKey = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[TenantKeyService] Rotated encryption key for tenant %s to version %d", tenantID, newKey.Version)
+
+	// Re-encrypt in the background; the runner job picks up anything this run misses
+	go func() {
+		reencryptCtx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+		if _, err := s.ReencryptTenant(reencryptCtx, tenantID); err != nil {
+			log.Printf("[TenantKeyService] Re-encryption after rotation failed for tenant %s: %v", tenantID, err)
+		}
+	}()
+
+	return newKey, nil
+}
+
+// ReencryptTenant moves all of a tenant's credential rows onto the active key
+// Legacy plaintext rows (key version 0) are encrypted for the first time. Returns rows updated.
+func (s *TenantKeyService) ReencryptTenant(ctx context.Context, tenantID uuid.UUID) (int, error) {
+	if !s.startReencrypt(tenantID) {
+		return 0, nil
+	}
+	defer s.finishReencrypt(tenantID)
+
+	active, err := s.activeKey(ctx, tenantID, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for {
+		var rows []models.TenantCredential
+		if err := s.db.WithContext(ctx).
+			Select("id, user_id, tenant_id, mfa_secret, key_version").
+			Where("tenant_id = ? AND key_version <> ? AND mfa_secret IS NOT NULL AND mfa_secret <> ''", tenantID, active.Version).
+			Limit(reencryptBatchSize).
+			Find(&rows).Error; err != nil {
+			return total, fmt.Errorf("failed to load credentials for re-encryption: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		updated := 0
+		for _, row := range rows {
+			plaintext, err := s.Decrypt(ctx, tenantID, row.KeyVersion, row.MFASecret, row.UserID.String())
+			if err != nil {
+				return total, fmt.Errorf("failed to decrypt credential %s: %w", row.ID, err)
+			}
+			ciphertext, version, err := s.Encrypt(ctx, tenantID, plaintext, row.UserID.String())
+			if err != nil {
+				return total, fmt.Errorf("failed to encrypt credential %s: %w", row.ID, err)
+			}
+
+			// Guard on the old version so a concurrent write is never overwritten
+			result := s.db.WithContext(ctx).
+				Model(&models.TenantCredential{}).
+				Where("id = ? AND key_version = ?", row.ID, row.KeyVersion).
+				Updates(map[string]interface{}{
+					"mfa_secret":  ciphertext,
+					"key_version": version,
+				})
+			if result.Error != nil {
+				return total, fmt.Errorf("failed to store re-encrypted credential %s: %w", row.ID, result.Error)
+			}
+			updated += int(result.RowsAffected)
+		}
+		total += updated
+
+		if updated == 0 {
+			// Every row in the batch changed underneath us; reload on the next pass
+			select {
+			case <-ctx.Done():
+				return total, ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+	}
+
+	if err := s.retireUnusedKeys(ctx, tenantID); err != nil {
+		return total, err
+	}
+
+	if total > 0 {
+		log.Printf("[TenantKeyService] Re-encrypted %d credentials for tenant %s onto key version %d", total, tenantID, active.Version)
+	}
+	return total, nil
+}
+
+// ReencryptPending re-encrypts every tenant that still has decrypt-only keys or legacy
+// plaintext credentials (key version 0)
+// Used by the background runner to resume interrupted re-encryption and encrypt legacy rows
+func (s *TenantKeyService) ReencryptPending(ctx context.Context) (int, error) {
+	var rotatedTenantIDs []uuid.UUID
+	if err := s.db.WithContext(ctx).
+		Model(&models.TenantEncryptionKey{}).
+		Where("status = ?", models.EncryptionKeyStatusDecryptOnly).
+		Distinct("tenant_id").
+		Pluck("tenant_id", &rotatedTenantIDs).Error; err != nil {
+		return 0, fmt.Errorf("failed to find tenants pending re-encryption: %w", err)
+	}
+
+	var legacyTenantIDs []uuid.UUID
+	if err := s.db.WithContext(ctx).
+		Model(&models.TenantCredential{}).
+		Where("key_version = 0 AND mfa_secret IS NOT NULL AND mfa_secret <> ''").
+		Distinct("tenant_id").
+		Pluck("tenant_id", &legacyTenantIDs).Error; err != nil {
+		return 0, fmt.Errorf("failed to find tenants with unencrypted credentials: %w", err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(rotatedTenantIDs)+len(legacyTenantIDs))
+	tenantIDs := make([]uuid.UUID, 0, len(rotatedTenantIDs)+len(legacyTenantIDs))
+	for _, tenantID := range append(rotatedTenantIDs, legacyTenantIDs...) {
+		if !seen[tenantID] {
+			seen[tenantID] = true
+			tenantIDs = append(tenantIDs, tenantID)
+		}
+	}
+
+	total := 0
+	for _, tenantID := range tenantIDs {
+		count, err := s.ReencryptTenant(ctx, tenantID)
+		if err != nil {
+			log.Printf("[TenantKeyService] Re-encryption failed for tenant %s: %v", tenantID, err)
+			continue
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// GetKeyStatus returns the tenant's key versions and re-encryption progress
+func (s *TenantKeyService) GetKeyStatus(ctx context.Context, tenantID uuid.UUID) (*TenantKeyStatus, error) {
+	var keys []models.TenantEncryptionKey
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("version DESC").
+		Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list encryption keys: %w", err)
+	}
+
+	status := &TenantKeyStatus{
+		TenantID:         tenantID.String(),
+		MasterKeyID:      s.wrapper.KeyID(),
+		Keys:             keys,
+		ReencryptRunning: s.isReencrypting(tenantID),
+	}
+	for _, key := range keys {
+		if key.Status == models.EncryptionKeyStatusActive {
+			status.ActiveVersion = key.Version
+		}
+	}
+
+	base := s.db.WithContext(ctx).
+		Model(&models.TenantCredential{}).
+		Where("tenant_id = ? AND mfa_secret IS NOT NULL AND mfa_secret <> ''", tenantID)
+	if err := base.Session(&gorm.Session{}).
+		Where("key_version = ?", status.ActiveVersion).
+		Count(&status.RowsOnActiveKey).Error; err != nil {
+		return nil, fmt.Errorf("failed to count credentials: %w", err)
+	}
+	if err := base.Session(&gorm.Session{}).
+		Where("key_version <> ?", status.ActiveVersion).
+		Count(&status.RowsPendingReencrypt).Error; err != nil {
+		return nil, fmt.Errorf("failed to count credentials: %w", err)
+	}
+
+	return status, nil
+}
+
+// activeKey returns the tenant's active key, creating version 1 on first use
+func (s *TenantKeyService) activeKey(ctx context.Context, tenantID uuid.UUID, createdBy *uuid.UUID) (*models.TenantEncryptionKey, error) {
+	var key models.TenantEncryptionKey
+	err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND status = ?", tenantID, models.EncryptionKeyStatusActive).
+		First(&key).Error
+	if err == nil {
+		return &key, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load active key: %w", err)
+	}
+
+	created, createErr := s.createKey(ctx, s.db.WithContext(ctx), tenantID, 1, createdBy)
+	if createErr == nil {
+		log.Printf("[TenantKeyService] Created initial encryption key for tenant %s", tenantID)
+		return created, nil
+	}
+
+	// Another request may have created the first key concurrently (unique tenant/version)
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND status = ?", tenantID, models.EncryptionKeyStatusActive).
+		First(&key).Error; err != nil {
+		return nil, fmt.Errorf("failed to create initial key: %w", createErr)
+	}
+	return &key, nil
+}
+
+// createKey generates, wraps and stores a new active DEK
+func (s *TenantKeyService) createKey(ctx context.Context, tx *gorm.DB, tenantID uuid.UUID, version int, createdBy *uuid.UUID) (*models.TenantEncryptionKey, error) {
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	wrapped, err := s.wrapper.Wrap(ctx, dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	key := &models.TenantEncryptionKey{
+		TenantID:    tenantID,
+		Version:     version,
+		Status:      models.EncryptionKeyStatusActive,
+		Algorithm:   "AES-256-GCM",
+		WrappedKey:  wrapped,
+		MasterKeyID: s.wrapper.KeyID(),
+		CreatedBy:   createdBy,
+	}
+	if err := tx.Create(key).Error; err != nil {
+		return nil, fmt.Errorf("failed to store data key: %w", err)
+	}
+	return key, nil
+}
+
+// aead returns the cipher for a key, unwrapping it through KMS on first use
+func (s *TenantKeyService) aead(ctx context.Context, key *models.TenantEncryptionKey) (cipher.AEAD, error) {
+	cacheKey := fmt.Sprintf("%s:%d", key.TenantID, key.Version)
+
+	s.cacheMu.RLock()
+	aead, ok := s.cache[cacheKey]
+	s.cacheMu.RUnlock()
+	if ok {
+		return aead, nil
+	}
+
+	dek, err := s.wrapper.Unwrap(ctx, key.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	aead, err = newAESGCM(dek)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+
+	s.cacheMu.Lock()
+	s.cache[cacheKey] = aead
+	s.cacheMu.Unlock()
+	return aead, nil
+}
+
+// retireUnusedKeys retires decrypt-only keys that no longer protect any rows
+func (s *TenantKeyService) retireUnusedKeys(ctx context.Context, tenantID uuid.UUID) error {
+	var keys []models.TenantEncryptionKey
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND status = ?", tenantID, models.EncryptionKeyStatusDecryptOnly).
+		Find(&keys).Error; err != nil {
+		return fmt.Errorf("failed to load rotated keys: %w", err)
+	}
+
+	for _, key := range keys {
+		var inUse int64
+		if err := s.db.WithContext(ctx).
+			Model(&models.TenantCredential{}).
+			Where("tenant_id = ? AND key_version = ?", tenantID, key.Version).
+			Count(&inUse).Error; err != nil {
+			return fmt.Errorf("failed to check key usage: %w", err)
+		}
+		if inUse > 0 {
+			continue
+		}
+
+		now := time.Now()
+		if err := s.db.WithContext(ctx).
+			Model(&key).
+			Updates(map[string]interface{}{
+				"status":     models.EncryptionKeyStatusRetired,
+				"retired_at": now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to retire key version %d: %w", key.Version, err)
+		}
+
+		s.cacheMu.Lock()
+		delete(s.cache, fmt.Sprintf("%s:%d", tenantID, key.Version))
+		s.cacheMu.Unlock()
+
+		log.Printf("[TenantKeyService] Retired encryption key version %d for tenant %s", key.Version, tenantID)
+	}
+	return nil
+}
+
+func (s *TenantKeyService) startReencrypt(tenantID uuid.UUID) bool {
+	s.reencryptMu.Lock()
+	defer s.reencryptMu.Unlock()
+	if s.reencrypting[tenantID] {
+		return false
+	}
+	s.reencrypting[tenantID] = true
+	return true
+}
+
+func (s *TenantKeyService) finishReencrypt(tenantID uuid.UUID) {
+	s.reencryptMu.Lock()
+	defer s.reencryptMu.Unlock()
+	delete(s.reencrypting, tenantID)
+}
+
+func (s *TenantKeyService) isReencrypting(tenantID uuid.UUID) bool {
+	s.reencryptMu.Lock()
+	defer s.reencryptMu.Unlock()
+	return s.reencrypting[tenantID]
+}
+
+// credentialAAD binds ciphertext to its tenant, key version and owning record
+func credentialAAD(tenantID uuid.UUID, version int, additionalData string) []byte {
+	return []byte(fmt.Sprintf("%s:%d:%s", tenantID, version, additionalData))
+}
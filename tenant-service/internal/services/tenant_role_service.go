@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/models"
+	natsClient "tenant-service/internal/nats"
+	"tenant-service/internal/repository"
+)
+
+var (
+	// ErrRoleForbidden is returned when the user may not manage the tenant's roles
+	ErrRoleForbidden = errors.New("only tenant owners and admins can manage roles")
+	// ErrRoleNotFound is returned when the tenant has no custom role with the given ID or name
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrRoleNameTaken is returned when the tenant already has a role with the name
+	ErrRoleNameTaken = errors.New("a role with this name already exists")
+	// ErrRoleInUse is returned when deleting a role that is still assigned to members
+	ErrRoleInUse = errors.New("role is assigned to members; assign them another role first")
+)
+
+// MaxRolePermissions caps the permission grants of a custom role
+const MaxRolePermissions = 200
+
+var (
+	roleNamePattern   = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+	permissionPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*(:[a-z][a-z0-9_-]*)*:([a-z][a-z0-9_-]*|\*)$`)
+)
+
+// SaveRoleRequest creates or replaces a custom role
+type SaveRoleRequest struct {
+	Name        string   `json:"name"` // Lower-case identifier stored on memberships, e.g. "support-agent"
+	DisplayName string   `json:"display_name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"` // resource:action grants, e.g. "orders:view" or "catalog:*"
+}
+
+// BuiltInRole describes one of the fixed membership roles
+type BuiltInRole struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// TenantRolesResponse lists the roles members of a tenant can hold
+type TenantRolesResponse struct {
+	BuiltIn []BuiltInRole       `json:"built_in"`
+	Custom  []models.TenantRole `json:"custom"`
+}
+
+// PermissionCheckRequest asks whether a user holds a permission in a tenant
+type PermissionCheckRequest struct {
+	UserID     string `json:"user_id" binding:"required"`
+	Permission string `json:"permission" binding:"required"`
+}
+
+// PermissionCheckResult is the outcome of a permission check
+type PermissionCheckResult struct {
+	Allowed     bool     `json:"allowed"`
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions"` // The member's effective grants
+}
+
+// TenantRoleService manages tenant-defined roles and answers permission checks for other services
+type TenantRoleService struct {
+	roleRepo       *repository.RoleRepository
+	membershipRepo *repository.MembershipRepository
+	securityEvents SecurityEventPublisher
+}
+
+// NewTenantRoleService creates a new tenant role service
+func NewTenantRoleService(db *gorm.DB, membershipRepo *repository.MembershipRepository) *TenantRoleService {
+	return &TenantRoleService{
+		roleRepo:       repository.NewRoleRepository(db),
+		membershipRepo: membershipRepo,
+	}
+}
+
+// SetSecurityEventPublisher enables security.role_escalation events for role edits that widen access
+func (s *TenantRoleService) SetSecurityEventPublisher(publisher SecurityEventPublisher) {
+	s.securityEvents = publisher
+}
+
+// AuthorizeManage checks the user is an owner or admin of the tenant
+func (s *TenantRoleService) AuthorizeManage(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrRoleForbidden
+	}
+	return nil
+}
+
+// ListRoles returns the built-in roles and the tenant's custom roles
+func (s *TenantRoleService) ListRoles(ctx context.Context, tenantID uuid.UUID) (*TenantRolesResponse, error) {
+	custom, err := s.roleRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	builtIn := models.BuiltInRolePermissions()
+	response := &TenantRolesResponse{Custom: custom}
+	for _, name := range []string{models.MembershipRoleOwner, models.MembershipRoleAdmin, models.MembershipRoleManager, models.MembershipRoleMember, models.MembershipRoleViewer} {
+		response.BuiltIn = append(response.BuiltIn, BuiltInRole{Name: name, Permissions: builtIn[name]})
+	}
+	return response, nil
+}
+
+// GetRole returns one of the tenant's custom roles
+func (s *TenantRoleService) GetRole(ctx context.Context, tenantID, roleID uuid.UUID) (*models.TenantRole, error) {
+	role, err := s.roleRepo.GetByID(ctx, tenantID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+	return role, nil
+}
+
+// CreateRole defines a new custom role for the tenant
+func (s *TenantRoleService) CreateRole(ctx context.Context, tenantID, userID uuid.UUID, req SaveRoleRequest) (*models.TenantRole, error) {
+	role := &models.TenantRole{TenantID: tenantID, CreatedBy: userID}
+	if err := s.applyRequest(ctx, role, req); err != nil {
+		return nil, err
+	}
+	role.UpdatedBy = userID
+
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+	log.Printf("[TenantRoleService] Role %s created for tenant %s by %s", role.Name, tenantID, userID)
+	return role, nil
+}
+
+// UpdateRole replaces a custom role; members holding it get the new name and permissions
+func (s *TenantRoleService) UpdateRole(ctx context.Context, tenantID, roleID, userID uuid.UUID, req SaveRoleRequest) (*models.TenantRole, error) {
+	role, err := s.GetRole(ctx, tenantID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	previousName := role.Name
+	previousPermissions := role.PermissionList()
+	if err := s.applyRequest(ctx, role, req); err != nil {
+		return nil, err
+	}
+	role.UpdatedBy = userID
+
+	memberIDs, err := s.roleRepo.SaveAndSyncMembers(ctx, role, previousName)
+	if err != nil {
+		return nil, err
+	}
+	s.membershipRepo.InvalidateUserMembershipsCache(ctx, memberIDs...)
+
+	// Widening a role escalates every member holding it
+	if escalated := models.EscalatedPermissions(previousPermissions, role.PermissionList()); len(escalated) > 0 && len(memberIDs) > 0 {
+		publishSecurityEvent(s.securityEvents, &natsClient.SecurityEvent{
+			EventType: natsClient.EventSecurityRoleEscalation,
+			TenantID:  tenantID.String(),
+			ActorID:   userID.String(),
+			Reason:    "role_permissions_changed",
+			Metadata: map[string]interface{}{
+				"role":             role.Name,
+				"escalated_grants": escalated,
+				"members_affected": len(memberIDs),
+			},
+		})
+	}
+	log.Printf("[TenantRoleService] Role %s updated for tenant %s by %s (%d members)", role.Name, tenantID, userID, len(memberIDs))
+	return role, nil
+}
+
+// DeleteRole removes a custom role that no member holds
+func (s *TenantRoleService) DeleteRole(ctx context.Context, tenantID, roleID, userID uuid.UUID) error {
+	role, err := s.GetRole(ctx, tenantID, roleID)
+	if err != nil {
+		return err
+	}
+	members, err := s.roleRepo.CountMembers(ctx, tenantID, role.Name)
+	if err != nil {
+		return err
+	}
+	if members > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := s.roleRepo.Delete(ctx, tenantID, roleID); err != nil {
+		return err
+	}
+	log.Printf("[TenantRoleService] Role %s deleted for tenant %s by %s", role.Name, tenantID, userID)
+	return nil
+}
+
+// ResolveMemberRole returns the permissions to store on a membership assigned role: none for
+// built-in roles, whose permissions are fixed, and the custom role's grants otherwise
+func (s *TenantRoleService) ResolveMemberRole(ctx context.Context, tenantID uuid.UUID, role string) (models.JSONB, error) {
+	if models.IsBuiltInRole(role) {
+		return models.JSONB(`[]`), nil
+	}
+	custom, err := s.roleRepo.GetByName(ctx, tenantID, role)
+	if err != nil {
+		return nil, err
+	}
+	if custom == nil {
+		return nil, ErrRoleNotFound
+	}
+	return custom.Permissions, nil
+}
+
+// CheckPermission reports whether the user's active membership in the tenant grants the permission
+func (s *TenantRoleService) CheckPermission(ctx context.Context, tenantID uuid.UUID, req PermissionCheckRequest) (*PermissionCheckResult, error) {
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, NewValidationError("user_id", "user_id must be a UUID", nil)
+	}
+	permission := strings.ToLower(strings.TrimSpace(req.Permission))
+	if !permissionPattern.MatchString(permission) {
+		return nil, NewValidationError("permission", "permission must look like resource:action", nil)
+	}
+
+	membership, err := s.membershipRepo.GetActiveMembership(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil {
+		return &PermissionCheckResult{Allowed: false, Permissions: []string{}}, nil
+	}
+
+	permissions := membership.PermissionList()
+	return &PermissionCheckResult{
+		Allowed:     models.PermissionGranted(permissions, permission),
+		Role:        membership.Role,
+		Permissions: permissions,
+	}, nil
+}
+
+// applyRequest validates the request and copies it onto role
+func (s *TenantRoleService) applyRequest(ctx context.Context, role *models.TenantRole, req SaveRoleRequest) error {
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if !roleNamePattern.MatchString(name) {
+		return NewValidationError("name", "name must be 2-50 lower-case letters, digits, '-' or '_' and start with a letter", nil)
+	}
+	if models.IsBuiltInRole(name) {
+		return NewValidationError("name", fmt.Sprintf("%s is a built-in role", name), nil)
+	}
+	if name != role.Name {
+		existing, err := s.roleRepo.GetByName(ctx, role.TenantID, name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrRoleNameTaken
+		}
+	}
+
+	permissions, err := NormalizePermissions(req.Permissions)
+	if err != nil {
+		return err
+	}
+
+	role.Name = name
+	role.DisplayName = strings.TrimSpace(req.DisplayName)
+	if role.DisplayName == "" {
+		role.DisplayName = name
+	}
+	role.Description = strings.TrimSpace(req.Description)
+	role.Permissions, err = toJSONB(permissions)
+	return err
+}
+
+// NormalizePermissions validates resource:action grants and returns them lower-cased, sorted
+// and without duplicates. The global "*" grant is reserved for owners and admins.
+func NormalizePermissions(permissions []string) ([]string, error) {
+	if len(permissions) == 0 {
+		return nil, NewValidationError("permissions", "at least one permission is required", nil)
+	}
+	if len(permissions) > MaxRolePermissions {
+		return nil, NewValidationError("permissions", fmt.Sprintf("a role can have at most %d permissions", MaxRolePermissions), nil)
+	}
+
+	seen := make(map[string]bool, len(permissions))
+	normalized := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.ToLower(strings.TrimSpace(permission))
+		if !permissionPattern.MatchString(permission) {
+			return nil, NewValidationError("permissions", fmt.Sprintf("%q must look like resource:action or resource:*", permission), nil)
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			normalized = append(normalized, permission)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
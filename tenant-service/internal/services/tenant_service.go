@@ -14,6 +14,8 @@ import (
 	"tenant-service/internal/integrations"
 	"tenant-service/internal/models"
 	natsClient "tenant-service/internal/nats"
+	"tenant-service/internal/redis"
+	"tenant-service/internal/repository"
 	"gorm.io/gorm"
 )
 
@@ -23,6 +25,7 @@ type TenantService struct {
 	membershipSvc *MembershipService
 	vendorClient  *clients.VendorClient
 	natsClient    *natsClient.Client
+	redisClient   *redis.Client // Optional: caches bulk lookup summaries
 }
 
 // NewTenantService creates a new tenant service
@@ -376,6 +379,10 @@ type TenantBasicInfo struct {
 	BillingEmail string `json:"billingEmail,omitempty"`
 	Status       string `json:"status"` // Required for middleware tenant validation
 
+	// Subscription state cached from billing
+	PricingTier   string `json:"pricingTier,omitempty"`
+	BillingStatus string `json:"billingStatus,omitempty"`
+
 	// URL fields for custom domain support
 	StorefrontURL   string `json:"storefront_url,omitempty"`
 	AdminURL        string `json:"admin_url,omitempty"`
@@ -399,6 +406,8 @@ func (s *TenantService) GetTenantByID(ctx context.Context, tenantID uuid.UUID) (
 		Subdomain:       tenant.Subdomain,
 		BillingEmail:    tenant.BillingEmail,
 		Status:          tenant.Status,
+		PricingTier:     tenant.PricingTier,
+		BillingStatus:   tenant.BillingStatus,
 		StorefrontURL:   tenant.StorefrontURL,
 		AdminURL:        tenant.AdminURL,
 		APIURL:          tenant.APIURL,
@@ -408,7 +417,7 @@ func (s *TenantService) GetTenantByID(ctx context.Context, tenantID uuid.UUID) (
 }
 
 // GetTenantBySlug retrieves basic tenant information by slug (for internal service calls)
-// Falls back to storefront slug lookup if tenant slug not found (matches MembershipRepository behavior)
+// Falls back to slug redirects, then storefront slug lookup if tenant slug not found (matches MembershipRepository behavior)
 func (s *TenantService) GetTenantBySlug(ctx context.Context, slug string) (*TenantBasicInfo, error) {
 	log.Printf("[TenantService] GetTenantBySlug called for slug: %s", slug)
 
@@ -417,6 +426,26 @@ func (s *TenantService) GetTenantBySlug(ctx context.Context, slug string) (*Tena
 		log.Printf("[TenantService] Tenant not found by slug %s, error: %v", slug, err)
 
 		if err == gorm.ErrRecordNotFound {
+			// A renamed tenant is still found by its former slug until the redirect expires
+			redirected, redirectErr := repository.NewMembershipRepository(s.db).GetTenantBySlugRedirect(ctx, slug)
+			if redirectErr != nil {
+				return nil, redirectErr
+			}
+			if redirected != nil {
+				log.Printf("[TenantService] Resolved former slug %s to tenant %s (now %s)", slug, redirected.ID, redirected.Slug)
+				return &TenantBasicInfo{
+					ID:            redirected.ID.String(),
+					Slug:          redirected.Slug,
+					Name:          redirected.Name,
+					DisplayName:   redirected.DisplayName,
+					Subdomain:     redirected.Subdomain,
+					BillingEmail:  redirected.BillingEmail,
+					Status:        redirected.Status,
+					PricingTier:   redirected.PricingTier,
+					BillingStatus: redirected.BillingStatus,
+				}, nil
+			}
+
 			// Fallback: Try storefront slug lookup
 			// This handles cases where storefront slug differs from tenant slug
 			if s.vendorClient == nil {
@@ -457,26 +486,30 @@ func (s *TenantService) GetTenantBySlug(ctx context.Context, slug string) (*Tena
 
 			log.Printf("[TenantService] Successfully resolved slug %s to tenant %s via storefront fallback", slug, tenant.ID.String())
 			return &TenantBasicInfo{
-				ID:           tenant.ID.String(),
-				Slug:         tenant.Slug,
-				Name:         tenant.Name,
-				DisplayName:  tenant.DisplayName,
-				Subdomain:    tenant.Subdomain,
-				BillingEmail: tenant.BillingEmail,
-				Status:       tenant.Status,
+				ID:            tenant.ID.String(),
+				Slug:          tenant.Slug,
+				Name:          tenant.Name,
+				DisplayName:   tenant.DisplayName,
+				Subdomain:     tenant.Subdomain,
+				BillingEmail:  tenant.BillingEmail,
+				Status:        tenant.Status,
+				PricingTier:   tenant.PricingTier,
+				BillingStatus: tenant.BillingStatus,
 			}, nil
 		}
 		return nil, err
 	}
 
 	return &TenantBasicInfo{
-		ID:           tenant.ID.String(),
-		Slug:         tenant.Slug,
-		Name:         tenant.Name,
-		DisplayName:  tenant.DisplayName,
-		Subdomain:    tenant.Subdomain,
-		BillingEmail: tenant.BillingEmail,
-		Status:       tenant.Status,
+		ID:            tenant.ID.String(),
+		Slug:          tenant.Slug,
+		Name:          tenant.Name,
+		DisplayName:   tenant.DisplayName,
+		Subdomain:     tenant.Subdomain,
+		BillingEmail:  tenant.BillingEmail,
+		Status:        tenant.Status,
+		PricingTier:   tenant.PricingTier,
+		BillingStatus: tenant.BillingStatus,
 	}, nil
 }
 
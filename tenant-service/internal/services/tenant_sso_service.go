@@ -0,0 +1,748 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Tesseract-Nexus/go-shared/auth"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+var (
+	// ErrSSOConfigForbidden is returned when the user may not manage the tenant's SSO configuration
+	ErrSSOConfigForbidden = errors.New("only tenant owners and admins can manage single sign-on")
+	// ErrSSOConfigNotFound is returned when the tenant has no SSO configuration
+	ErrSSOConfigNotFound = errors.New("single sign-on is not configured for this tenant")
+	// ErrSSOUnavailable is returned when identity providers cannot be managed because the
+	// Keycloak admin client is not configured
+	ErrSSOUnavailable = errors.New("single sign-on is unavailable: identity provider management is not configured")
+	// ErrSSOProvisioningFailed is returned when Keycloak rejected the identity provider
+	ErrSSOProvisioningFailed = errors.New("failed to provision the identity provider")
+	// ErrSSOProvisionMismatch is returned when a broker login does not belong to the tenant's IdP
+	ErrSSOProvisionMismatch = errors.New("login does not match the tenant's single sign-on configuration")
+
+	// errSSOFetchBlocked is returned by the dialer when an IdP document URL resolves to an
+	// address inside the platform
+	errSSOFetchBlocked = errors.New("identity provider address is not public")
+)
+
+// ssoRoleRank orders the roles SSO may grant; owner is never granted through group mappings
+var ssoRoleRank = map[string]int{
+	models.MembershipRoleViewer:  1,
+	models.MembershipRoleMember:  2,
+	models.MembershipRoleManager: 3,
+	models.MembershipRoleAdmin:   4,
+}
+
+var ssoDomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// SaveSSOConfigRequest replaces a tenant's SSO configuration
+type SaveSSOConfigRequest struct {
+	Protocol          string            `json:"protocol"` // oidc or saml
+	DisplayName       string            `json:"display_name"`
+	Enabled           bool              `json:"enabled"`
+	Enforced          bool              `json:"enforced"` // Refuse password login for the domains
+	Domains           []string          `json:"domains"`
+	GroupsClaim       string            `json:"groups_claim"`
+	GroupRoleMappings map[string]string `json:"group_role_mappings"`
+	DefaultRole       string            `json:"default_role"`
+	// OIDC: the endpoints are read from discovery_url when it is set; explicit values take precedence
+	DiscoveryURL     string `json:"discovery_url"`
+	Issuer           string `json:"issuer"`
+	AuthorizationURL string `json:"authorization_url"`
+	TokenURL         string `json:"token_url"`
+	JWKSURL          string `json:"jwks_url"`
+	ClientID         string `json:"client_id"`
+	ClientSecret     string `json:"client_secret"` // Omit to keep the current secret
+	// SAML: either the metadata document or a URL it can be fetched from
+	MetadataXML string `json:"metadata_xml"`
+	MetadataURL string `json:"metadata_url"`
+}
+
+// ValidateSAMLMetadataRequest checks a SAML metadata document without saving it
+type ValidateSAMLMetadataRequest struct {
+	MetadataXML string `json:"metadata_xml"`
+	MetadataURL string `json:"metadata_url"`
+}
+
+// SSOConfigResponse is a tenant's SSO configuration with the values the IdP admin needs
+type SSOConfigResponse struct {
+	*models.TenantSSOConfig
+	RedirectURI   string `json:"redirect_uri"`              // OIDC redirect URI / SAML ACS URL to register at the IdP
+	SPMetadataURL string `json:"sp_metadata_url,omitempty"` // SAML service provider metadata
+}
+
+// SSOLoginRoute tells the login flow to send the user to the tenant's identity provider
+type SSOLoginRoute struct {
+	IdPHint     string `json:"idp_hint"` // Keycloak kc_idp_hint
+	Protocol    string `json:"protocol"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// ProvisionSSOMemberRequest is sent after a broker login to create or update the user's membership
+type ProvisionSSOMemberRequest struct {
+	TenantID   uuid.UUID `json:"tenant_id" binding:"required"`
+	IdPAlias   string    `json:"idp_alias" binding:"required"`
+	KeycloakID string    `json:"-"` // Taken from the authenticated token, never the body
+	Email      string    `json:"email" binding:"required,email"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	Groups     []string  `json:"groups"`
+}
+
+// ProvisionSSOMemberResult is the membership an SSO login resolved to
+type ProvisionSSOMemberResult struct {
+	UserID   uuid.UUID `json:"user_id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	Role     string    `json:"role"`
+	Created  bool      `json:"created"` // True when the membership was created by this login
+}
+
+// TenantSSOService manages enterprise tenants' single sign-on: the Keycloak identity provider,
+// the email domains routed to it and the roles granted to users who log in through it
+type TenantSSOService struct {
+	ssoRepo        *repository.SSOConfigRepository
+	membershipRepo *repository.MembershipRepository
+	keycloak       *timedKeycloakClient
+	cfg            config.SSOConfig
+	httpClient     *http.Client
+}
+
+// NewTenantSSOService creates a new tenant SSO service; keycloak may be nil, in which case
+// configurations can be read but not provisioned
+func NewTenantSSOService(db *gorm.DB, keycloak *auth.KeycloakAdminClient, cfg config.SSOConfig) *TenantSSOService {
+	return &TenantSSOService{
+		ssoRepo:        repository.NewSSOConfigRepository(db),
+		membershipRepo: repository.NewMembershipRepository(db),
+		keycloak:       newTimedKeycloakClient(keycloak),
+		cfg:            cfg,
+		httpClient:     newIdPDocumentClient(cfg),
+	}
+}
+
+// newIdPDocumentClient returns the client fetching IdP documents from tenant-supplied URLs.
+// Unless cfg allows private hosts, it refuses to connect to addresses inside the platform and
+// does not follow redirects.
+func newIdPDocumentClient(cfg config.SSOConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !cfg.AllowPrivateMetadataHosts {
+		// Checked on the resolved address so DNS can't point a metadata URL at the cluster
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateIdPAddress(ip) {
+				return errSSOFetchBlocked
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: time.Duration(cfg.MetadataFetchTimeout) * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// Documents are fetched from the URL the admin entered; a redirect could also lead past
+		// the address check
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isPrivateIdPAddress reports whether ip is internal to the platform
+func isPrivateIdPAddress(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast()
+}
+
+// AuthorizeManage checks the user is an owner or admin of the tenant
+func (s *TenantSSOService) AuthorizeManage(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrSSOConfigForbidden
+	}
+	return nil
+}
+
+// GetConfig returns the tenant's SSO configuration
+func (s *TenantSSOService) GetConfig(ctx context.Context, tenantID uuid.UUID) (*SSOConfigResponse, error) {
+	cfg, err := s.ssoRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, ErrSSOConfigNotFound
+	}
+	return s.toResponse(cfg), nil
+}
+
+// SaveConfig validates the configuration and creates or updates the tenant's Keycloak identity
+// provider. The configuration is stored even when Keycloak rejects it, with the failure recorded,
+// so the admin can see what went wrong; ErrSSOProvisioningFailed is returned in that case.
+func (s *TenantSSOService) SaveConfig(ctx context.Context, tenantID, userID uuid.UUID, req SaveSSOConfigRequest) (*SSOConfigResponse, error) {
+	if s.keycloak == nil {
+		return nil, ErrSSOUnavailable
+	}
+
+	domains, err := NormalizeSSODomains(req.Domains)
+	if err != nil {
+		return nil, err
+	}
+	mappings, defaultRole, err := validateSSORoles(req.GroupRoleMappings, req.DefaultRole)
+	if err != nil {
+		return nil, err
+	}
+	if req.Enforced && !req.Enabled {
+		return nil, NewValidationError("enforced", "single sign-on must be enabled to be enforced", nil)
+	}
+	for _, domain := range domains {
+		owner, err := s.ssoRepo.FindDomainOwner(ctx, domain, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if owner != uuid.Nil {
+			return nil, NewValidationError("domains", fmt.Sprintf("%s is already used for single sign-on by another tenant", domain), nil)
+		}
+	}
+
+	tenant, err := s.membershipRepo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.ssoRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	sso := existing
+	if sso == nil {
+		sso = &models.TenantSSOConfig{
+			TenantID:  tenantID,
+			Alias:     SSOAliasForTenant(tenant.Slug),
+			CreatedBy: userID,
+		}
+	}
+	previousProtocol := sso.Protocol
+
+	sso.Protocol = strings.ToLower(strings.TrimSpace(req.Protocol))
+	sso.DisplayName = strings.TrimSpace(req.DisplayName)
+	if sso.DisplayName == "" {
+		sso.DisplayName = tenant.Name
+	}
+	sso.Enabled = req.Enabled
+	sso.Enforced = req.Enforced
+	sso.GroupsClaim = strings.TrimSpace(req.GroupsClaim)
+	if sso.GroupsClaim == "" {
+		sso.GroupsClaim = "groups"
+	}
+	sso.DefaultRole = defaultRole
+	sso.UpdatedBy = userID
+	if sso.Domains, err = toJSONB(domains); err != nil {
+		return nil, err
+	}
+	if sso.GroupRoleMappings, err = toJSONB(mappings); err != nil {
+		return nil, err
+	}
+
+	switch sso.Protocol {
+	case models.SSOProtocolOIDC:
+		if err := s.applyOIDC(ctx, sso, req, existing == nil || previousProtocol != models.SSOProtocolOIDC); err != nil {
+			return nil, err
+		}
+	case models.SSOProtocolSAML:
+		if err := s.applySAML(ctx, sso, req); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, NewValidationError("protocol", "protocol must be oidc or saml", nil)
+	}
+
+	idp := s.buildIdentityProvider(sso, req.ClientSecret)
+	provisionErr := s.provisionIdentityProvider(ctx, sso, previousProtocol, idp)
+	if provisionErr != nil {
+		sso.ProvisioningStatus = models.SSOProvisioningFailed
+		sso.ProvisioningError = provisionErr.Error()
+	} else {
+		now := time.Now()
+		sso.ProvisioningStatus = models.SSOProvisioningProvisioned
+		sso.ProvisioningError = ""
+		sso.ProvisionedAt = &now
+	}
+
+	if err := s.ssoRepo.Save(ctx, sso); err != nil {
+		return nil, err
+	}
+	if provisionErr != nil {
+		log.Printf("[TenantSSOService] Failed to provision identity provider %s for tenant %s: %v", sso.Alias, tenantID, provisionErr)
+		return s.toResponse(sso), fmt.Errorf("%w: %v", ErrSSOProvisioningFailed, provisionErr)
+	}
+	log.Printf("[TenantSSOService] %s identity provider %s for tenant %s saved by %s", sso.Protocol, sso.Alias, tenantID, userID)
+	return s.toResponse(sso), nil
+}
+
+// DeleteConfig removes the tenant's identity provider and SSO configuration. Users who signed in
+// through SSO keep their memberships but have to use password login or an invitation.
+func (s *TenantSSOService) DeleteConfig(ctx context.Context, tenantID, userID uuid.UUID) error {
+	sso, err := s.ssoRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if sso == nil {
+		return ErrSSOConfigNotFound
+	}
+	if s.keycloak == nil {
+		return ErrSSOUnavailable
+	}
+	if err := s.keycloak.DeleteIdentityProvider(ctx, sso.Alias); err != nil {
+		return fmt.Errorf("%w: %v", ErrSSOProvisioningFailed, err)
+	}
+	if err := s.ssoRepo.Delete(ctx, tenantID); err != nil {
+		return err
+	}
+	log.Printf("[TenantSSOService] Identity provider %s for tenant %s deleted by %s", sso.Alias, tenantID, userID)
+	return nil
+}
+
+// ValidateMetadata parses SAML metadata without saving it, so admins can check a document
+// before switching the tenant over
+func (s *TenantSSOService) ValidateMetadata(ctx context.Context, req ValidateSAMLMetadataRequest) (*SAMLMetadata, error) {
+	data, err := s.loadMetadata(ctx, req.MetadataXML, req.MetadataURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSAMLMetadata(data, time.Now(), s.certificateMinValidity())
+}
+
+// TestConnection checks Keycloak can reach the tenant's identity provider
+func (s *TenantSSOService) TestConnection(ctx context.Context, tenantID uuid.UUID) (*auth.IdPTestResult, error) {
+	sso, err := s.ssoRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if sso == nil {
+		return nil, ErrSSOConfigNotFound
+	}
+	if s.keycloak == nil {
+		return nil, ErrSSOUnavailable
+	}
+	return s.keycloak.TestIdentityProvider(ctx, sso.Alias)
+}
+
+// RouteLogin returns where a password login for the email must be sent instead, or nil when
+// the tenant does not enforce SSO for the email's domain
+func (s *TenantSSOService) RouteLogin(ctx context.Context, tenantID uuid.UUID, email string) (*SSOLoginRoute, error) {
+	sso, err := s.ssoRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if sso == nil || !sso.RoutesLogin(email) {
+		return nil, nil
+	}
+	return &SSOLoginRoute{
+		IdPHint:     sso.Alias,
+		Protocol:    sso.Protocol,
+		DisplayName: sso.DisplayName,
+	}, nil
+}
+
+// ProvisionMember creates or updates the tenant user and membership after a login through the
+// tenant's identity provider. The role is re-derived from the IdP groups on every login, so
+// group changes at the IdP take effect on the next sign-in; owners are never changed.
+func (s *TenantSSOService) ProvisionMember(ctx context.Context, req ProvisionSSOMemberRequest) (*ProvisionSSOMemberResult, error) {
+	sso, err := s.ssoRepo.GetByTenantID(ctx, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if sso == nil || !sso.Enabled || sso.Alias != req.IdPAlias || !sso.MatchesEmail(req.Email) {
+		return nil, ErrSSOProvisionMismatch
+	}
+	keycloakID, err := uuid.Parse(req.KeycloakID)
+	if err != nil {
+		return nil, NewValidationError("user_id", "authenticated user ID must be a UUID", nil)
+	}
+	role := ResolveSSORole(sso.RoleMappings(), sso.DefaultRole, req.Groups)
+
+	user, err := findOrCreateMemberUser(ctx, s.membershipRepo, keycloakID, req.Email, req.FirstName, req.LastName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProvisionSSOMemberResult{UserID: user.ID, TenantID: req.TenantID, Role: role}
+	membership, err := s.membershipRepo.GetMembership(ctx, user.ID, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if membership == nil {
+		membership = &models.UserTenantMembership{
+			UserID:     user.ID,
+			TenantID:   req.TenantID,
+			Role:       role,
+			IsActive:   true,
+			AcceptedAt: &now,
+		}
+		if err := s.membershipRepo.CreateMembership(ctx, membership); err != nil {
+			return nil, err
+		}
+		result.Created = true
+		log.Printf("[TenantSSOService] Provisioned %s as %s of tenant %s via %s", user.ID, role, req.TenantID, sso.Alias)
+		return result, nil
+	}
+
+	if membership.Role == models.MembershipRoleOwner {
+		result.Role = membership.Role
+		return result, nil
+	}
+	if membership.Role != role || !membership.IsActive {
+		log.Printf("[TenantSSOService] Role of %s in tenant %s changed from %s to %s by IdP groups", user.ID, req.TenantID, membership.Role, role)
+		membership.Role = role
+		membership.IsActive = true
+		membership.Tenant = nil
+		if err := s.membershipRepo.UpdateMembership(ctx, membership); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// ResolveSSORole maps a user's IdP groups to a membership role. The highest-privileged mapped
+// role wins; users in no mapped group get the default role. Groups may be given as Keycloak
+// group paths ("/engineering/admins") or plain names.
+func ResolveSSORole(mappings map[string]string, defaultRole string, groups []string) string {
+	resolved := ""
+	for _, group := range groups {
+		role, ok := mappings[group]
+		if !ok {
+			role, ok = mappings[strings.TrimPrefix(group, "/")]
+		}
+		if !ok {
+			continue
+		}
+		if ssoRoleRank[role] > ssoRoleRank[resolved] {
+			resolved = role
+		}
+	}
+	if resolved == "" {
+		if _, ok := ssoRoleRank[defaultRole]; ok {
+			return defaultRole
+		}
+		return models.MembershipRoleMember
+	}
+	return resolved
+}
+
+// NormalizeSSODomains lower-cases, de-duplicates and validates the email domains routed to an
+// identity provider
+func NormalizeSSODomains(domains []string) ([]string, error) {
+	seen := map[string]bool{}
+	normalized := []string{}
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@")
+		if domain == "" || seen[domain] {
+			continue
+		}
+		if !ssoDomainPattern.MatchString(domain) {
+			return nil, NewValidationError("domains", fmt.Sprintf("%s is not a valid email domain", domain), nil)
+		}
+		seen[domain] = true
+		normalized = append(normalized, domain)
+	}
+	if len(normalized) == 0 {
+		return nil, NewValidationError("domains", "at least one email domain is required", nil)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// SSOAliasForTenant is the Keycloak identity provider alias of a tenant
+func SSOAliasForTenant(slug string) string {
+	return "tenant-" + slug + "-sso"
+}
+
+func validateSSORoles(mappings map[string]string, defaultRole string) (map[string]string, string, error) {
+	validated := make(map[string]string, len(mappings))
+	for group, role := range mappings {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			return nil, "", NewValidationError("group_role_mappings", "group names must not be empty", nil)
+		}
+		if _, ok := ssoRoleRank[role]; !ok {
+			return nil, "", NewValidationError("group_role_mappings", fmt.Sprintf("group %s maps to %q; roles must be admin, manager, member or viewer", group, role), nil)
+		}
+		validated[group] = role
+	}
+	if defaultRole == "" {
+		defaultRole = models.MembershipRoleMember
+	}
+	if _, ok := ssoRoleRank[defaultRole]; !ok {
+		return nil, "", NewValidationError("default_role", "default_role must be admin, manager, member or viewer", nil)
+	}
+	return validated, defaultRole, nil
+}
+
+func (s *TenantSSOService) applyOIDC(ctx context.Context, sso *models.TenantSSOConfig, req SaveSSOConfigRequest, requireSecret bool) error {
+	discoveryURL := ""
+	if strings.TrimSpace(req.DiscoveryURL) != "" {
+		discoveryURL = OIDCDiscoveryURL(req.DiscoveryURL)
+		discovery, err := s.discoverOIDC(ctx, discoveryURL)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.Issuer) == "" {
+			req.Issuer = discovery.Issuer
+		}
+		if strings.TrimSpace(req.AuthorizationURL) == "" {
+			req.AuthorizationURL = discovery.AuthorizationEndpoint
+		}
+		if strings.TrimSpace(req.TokenURL) == "" {
+			req.TokenURL = discovery.TokenEndpoint
+		}
+		if strings.TrimSpace(req.JWKSURL) == "" {
+			req.JWKSURL = discovery.JWKSURI
+		}
+	}
+
+	fields := []struct {
+		name, value string
+	}{
+		{"issuer", req.Issuer},
+		{"authorization_url", req.AuthorizationURL},
+		{"token_url", req.TokenURL},
+		{"jwks_url", req.JWKSURL},
+	}
+	for _, field := range fields {
+		if err := validateHTTPSURL(field.name, field.value); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(req.ClientID) == "" {
+		return NewValidationError("client_id", "client_id is required", nil)
+	}
+	if requireSecret && req.ClientSecret == "" {
+		return NewValidationError("client_secret", "client_secret is required", nil)
+	}
+
+	sso.DiscoveryURL = discoveryURL
+	sso.Issuer = strings.TrimSpace(req.Issuer)
+	sso.AuthorizationURL = strings.TrimSpace(req.AuthorizationURL)
+	sso.TokenURL = strings.TrimSpace(req.TokenURL)
+	sso.JWKSURL = strings.TrimSpace(req.JWKSURL)
+	sso.ClientID = strings.TrimSpace(req.ClientID)
+	sso.MetadataURL, sso.IdPEntityID, sso.SSOURL, sso.SLOURL, sso.SigningCertificate = "", "", "", "", ""
+	sso.CertificateExpiresAt = nil
+	return nil
+}
+
+func (s *TenantSSOService) applySAML(ctx context.Context, sso *models.TenantSSOConfig, req SaveSSOConfigRequest) error {
+	data, err := s.loadMetadata(ctx, req.MetadataXML, req.MetadataURL)
+	if err != nil {
+		return err
+	}
+	metadata, err := ParseSAMLMetadata(data, time.Now(), s.certificateMinValidity())
+	if err != nil {
+		return err
+	}
+
+	sso.MetadataURL = strings.TrimSpace(req.MetadataURL)
+	sso.IdPEntityID = metadata.EntityID
+	sso.SSOURL = metadata.SSOURL
+	sso.SLOURL = metadata.SLOURL
+	sso.SigningCertificate = metadata.SigningCertificate
+	expiresAt := metadata.CertificateExpiresAt
+	sso.CertificateExpiresAt = &expiresAt
+	sso.DiscoveryURL, sso.Issuer, sso.AuthorizationURL, sso.TokenURL, sso.JWKSURL, sso.ClientID = "", "", "", "", "", ""
+	return nil
+}
+
+// discoverOIDC fetches and validates the identity provider's OpenID configuration
+func (s *TenantSSOService) discoverOIDC(ctx context.Context, discoveryURL string) (*OIDCDiscovery, error) {
+	data, err := s.fetchDocument(ctx, "discovery_url", "discovery document", discoveryURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseOIDCDiscovery(data, discoveryURL)
+}
+
+// loadMetadata returns the uploaded metadata document, or fetches it from metadataURL
+func (s *TenantSSOService) loadMetadata(ctx context.Context, metadataXML, metadataURL string) ([]byte, error) {
+	maxBytes := s.cfg.MetadataMaxBytes
+	if strings.TrimSpace(metadataXML) != "" {
+		if len(metadataXML) > maxBytes {
+			return nil, NewValidationError("metadata_xml", fmt.Sprintf("metadata must be at most %d bytes", maxBytes), nil)
+		}
+		return []byte(metadataXML), nil
+	}
+	if strings.TrimSpace(metadataURL) == "" {
+		return nil, NewValidationError("metadata_xml", "metadata_xml or metadata_url is required for SAML", nil)
+	}
+	return s.fetchDocument(ctx, "metadata_url", "metadata", metadataURL)
+}
+
+// fetchDocument downloads an IdP document (SAML metadata, OpenID configuration) over https,
+// reporting failures as validation errors on field. The URL is tenant-supplied, so the errors
+// never include what the server returned; the details are only logged.
+func (s *TenantSSOService) fetchDocument(ctx context.Context, field, document, rawURL string) ([]byte, error) {
+	maxBytes := s.cfg.MetadataMaxBytes
+	if err := validateHTTPSURL(field, rawURL); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSpace(rawURL), nil)
+	if err != nil {
+		return nil, NewValidationError(field, fmt.Sprintf("%s is not a valid URL", field), nil)
+	}
+	fetchFailed := NewValidationError(field, fmt.Sprintf("failed to fetch %s from %s", document, field), nil)
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		log.Printf("[TenantSSOService] Failed to fetch %s from %s: %v", document, httpReq.URL.Redacted(), err)
+		return nil, fetchFailed
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("[TenantSSOService] Failed to fetch %s from %s: status %d", document, httpReq.URL.Redacted(), resp.StatusCode)
+		return nil, fetchFailed
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxBytes)+1))
+	if err != nil {
+		log.Printf("[TenantSSOService] Failed to read %s from %s: %v", document, httpReq.URL.Redacted(), err)
+		return nil, fetchFailed
+	}
+	if len(data) > maxBytes {
+		return nil, NewValidationError(field, fmt.Sprintf("%s must be at most %d bytes", document, maxBytes), nil)
+	}
+	return data, nil
+}
+
+func (s *TenantSSOService) certificateMinValidity() time.Duration {
+	return time.Duration(s.cfg.CertificateMinDays) * 24 * time.Hour
+}
+
+// buildIdentityProvider translates the configuration into a Keycloak identity provider; an
+// empty clientSecret leaves Keycloak's stored secret unchanged
+func (s *TenantSSOService) buildIdentityProvider(sso *models.TenantSSOConfig, clientSecret string) auth.IdentityProviderConfig {
+	idp := auth.IdentityProviderConfig{
+		Alias:       sso.Alias,
+		DisplayName: sso.DisplayName,
+		ProviderID:  sso.Protocol,
+		Enabled:     sso.Enabled,
+		TrustEmail:  true,
+		StoreToken:  false,
+		Config:      map[string]string{"syncMode": "FORCE"},
+	}
+
+	switch sso.Protocol {
+	case models.SSOProtocolOIDC:
+		idp.Config["issuer"] = sso.Issuer
+		idp.Config["authorizationUrl"] = sso.AuthorizationURL
+		idp.Config["tokenUrl"] = sso.TokenURL
+		idp.Config["jwksUrl"] = sso.JWKSURL
+		idp.Config["useJwksUrl"] = "true"
+		idp.Config["validateSignature"] = "true"
+		idp.Config["clientId"] = sso.ClientID
+		idp.Config["clientAuthMethod"] = "client_secret_post"
+		idp.Config["defaultScope"] = "openid email profile"
+		if clientSecret != "" {
+			idp.Config["clientSecret"] = clientSecret
+		}
+	case models.SSOProtocolSAML:
+		idp.Config["idpEntityId"] = sso.IdPEntityID
+		idp.Config["entityId"] = s.serviceProviderEntityID()
+		idp.Config["singleSignOnServiceUrl"] = sso.SSOURL
+		idp.Config["singleLogoutServiceUrl"] = sso.SLOURL
+		idp.Config["signingCertificate"] = sso.SigningCertificate
+		idp.Config["validateSignature"] = "true"
+		idp.Config["wantAssertionsSigned"] = "true"
+		idp.Config["postBindingResponse"] = "true"
+		idp.Config["postBindingAuthnRequest"] = "true"
+		idp.Config["nameIDPolicyFormat"] = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+		idp.Config["principalType"] = "SUBJECT"
+	}
+	return idp
+}
+
+// provisionIdentityProvider creates or updates the Keycloak identity provider. Keycloak cannot
+// change an identity provider's protocol, so a protocol switch recreates it.
+func (s *TenantSSOService) provisionIdentityProvider(ctx context.Context, sso *models.TenantSSOConfig, previousProtocol string, idp auth.IdentityProviderConfig) error {
+	current, err := s.keycloak.GetIdentityProvider(ctx, sso.Alias)
+	if err != nil {
+		return err
+	}
+	if current != nil && previousProtocol != "" && previousProtocol != sso.Protocol {
+		if err := s.keycloak.DeleteIdentityProvider(ctx, sso.Alias); err != nil {
+			return err
+		}
+		current = nil
+	}
+	if current == nil {
+		if sso.Protocol == models.SSOProtocolOIDC && idp.Config["clientSecret"] == "" {
+			return errors.New("client_secret is required to create the identity provider")
+		}
+		return s.keycloak.CreateIdentityProvider(ctx, idp)
+	}
+	if _, ok := idp.Config["clientSecret"]; !ok && current.Config != nil {
+		// Keycloak returns the stored secret masked; sending it back keeps it
+		if secret, ok := current.Config["clientSecret"]; ok {
+			idp.Config["clientSecret"] = secret
+		}
+	}
+	return s.keycloak.UpdateIdentityProvider(ctx, sso.Alias, idp)
+}
+
+func (s *TenantSSOService) realmURL() string {
+	return strings.TrimSuffix(s.cfg.KeycloakBaseURL, "/") + "/realms/" + s.cfg.KeycloakRealm
+}
+
+func (s *TenantSSOService) serviceProviderEntityID() string {
+	return s.realmURL()
+}
+
+func (s *TenantSSOService) toResponse(sso *models.TenantSSOConfig) *SSOConfigResponse {
+	response := &SSOConfigResponse{
+		TenantSSOConfig: sso,
+		RedirectURI:     s.realmURL() + "/broker/" + sso.Alias + "/endpoint",
+	}
+	if sso.Protocol == models.SSOProtocolSAML {
+		response.SPMetadataURL = response.RedirectURI + "/descriptor"
+	}
+	return response
+}
+
+func validateHTTPSURL(field, value string) error {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || parsed.Host == "" {
+		return NewValidationError(field, fmt.Sprintf("%s must be a valid URL", field), nil)
+	}
+	if parsed.Scheme != "https" {
+		return NewValidationError(field, fmt.Sprintf("%s must use https", field), nil)
+	}
+	return nil
+}
+
+func toJSONB(value interface{}) (models.JSONB, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return models.JSONB(data), nil
+}
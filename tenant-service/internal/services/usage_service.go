@@ -0,0 +1,453 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/nats"
+	"tenant-service/internal/repository"
+)
+
+var (
+	// ErrQuotaExceeded is returned when quota enforcement is enabled and the plan quota is used up
+	ErrQuotaExceeded = errors.New("plan quota exceeded")
+	// ErrUsageAccessForbidden is returned when the user is not a tenant owner or admin
+	ErrUsageAccessForbidden = errors.New("only tenant owners and admins can view usage")
+)
+
+// QuotaEventPublisher publishes quota breach events (satisfied by *nats.Client)
+type QuotaEventPublisher interface {
+	PublishTenantQuotaExceeded(ctx context.Context, event *nats.TenantQuotaExceededEvent) error
+}
+
+// UsageMetric is a tenant's usage of one metric against its plan quota
+type UsageMetric struct {
+	Metric   string `json:"metric"`
+	Unit     string `json:"unit"`             // "count" or "bytes"
+	Period   string `json:"period,omitempty"` // YYYY-MM for monthly metrics
+	Used     int64  `json:"used"`
+	Limit    int64  `json:"limit"` // -1 for unlimited
+	Exceeded bool   `json:"exceeded"`
+}
+
+// TenantUsage is the usage report returned by GET /tenants/:id/usage
+type TenantUsage struct {
+	TenantID       uuid.UUID     `json:"tenant_id"`
+	PricingTier    string        `json:"pricing_tier"`
+	QuotasEnforced bool          `json:"quotas_enforced"`
+	Metrics        []UsageMetric `json:"metrics"`
+}
+
+// UsageService meters per-tenant usage and checks it against plan quotas.
+// Members are counted live; API calls are metered on API key validation; storage and emails
+// are metered from document-service and notification-service events.
+type UsageService struct {
+	db             *gorm.DB
+	membershipRepo *repository.MembershipRepository
+	publisher      QuotaEventPublisher
+	config         config.UsageConfig
+
+	// notified caches quota notices already recorded, sparing the database on every call over quota
+	notified sync.Map
+}
+
+// NewUsageService creates a new usage service
+func NewUsageService(db *gorm.DB, cfg config.UsageConfig) *UsageService {
+	return &UsageService{
+		db:             db,
+		membershipRepo: repository.NewMembershipRepository(db),
+		config:         cfg,
+	}
+}
+
+// SetEventPublisher enables tenant.quota.exceeded events
+func (s *UsageService) SetEventPublisher(publisher QuotaEventPublisher) {
+	s.publisher = publisher
+}
+
+// CheckInterval returns the interval of the member quota check and event key pruning job
+func (s *UsageService) CheckInterval() time.Duration {
+	if s.config.CheckIntervalMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(s.config.CheckIntervalMinutes) * time.Minute
+}
+
+// AuthorizeView checks the user is an owner or admin of the tenant
+func (s *UsageService) AuthorizeView(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil || (role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin) {
+		return ErrUsageAccessForbidden
+	}
+	return nil
+}
+
+// GetUsage returns the tenant's current usage of every metered quota
+func (s *UsageService) GetUsage(ctx context.Context, tenantID uuid.UUID) (*TenantUsage, error) {
+	tier, err := s.tenantTier(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.countMembers(ctx, tenantID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	period := currentUsagePeriod()
+	var counters []models.TenantUsageCounter
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND period IN ?", tenantID, []string{"", period}).
+		Find(&counters).Error; err != nil {
+		return nil, fmt.Errorf("failed to load usage counters: %w", err)
+	}
+	values := make(map[string]int64, len(counters))
+	for _, counter := range counters {
+		values[counter.Metric] = counter.Value
+	}
+
+	metric := func(name, unit, period string, used int64) UsageMetric {
+		limit := quotaLimit(tier, name)
+		return UsageMetric{
+			Metric:   name,
+			Unit:     unit,
+			Period:   period,
+			Used:     used,
+			Limit:    limit,
+			Exceeded: limit >= 0 && used > limit,
+		}
+	}
+
+	return &TenantUsage{
+		TenantID:       tenantID,
+		PricingTier:    tier,
+		QuotasEnforced: s.config.EnforceQuotas,
+		Metrics: []UsageMetric{
+			metric(models.UsageMetricMembers, "count", "", members),
+			metric(models.UsageMetricAPICalls, "count", period, values[models.UsageMetricAPICalls]),
+			metric(models.UsageMetricStorage, "bytes", "", values[models.UsageMetricStorage]),
+			metric(models.UsageMetricEmails, "count", period, values[models.UsageMetricEmails]),
+		},
+	}, nil
+}
+
+// CheckMemberQuota returns ErrQuotaExceeded when enforcement is enabled and adding another
+// member would exceed the plan quota. Pending invitations count as reserved seats.
+func (s *UsageService) CheckMemberQuota(ctx context.Context, tenantID uuid.UUID) error {
+	if !s.config.EnforceQuotas {
+		return nil
+	}
+
+	tier, err := s.tenantTier(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	limit := quotaLimit(tier, models.UsageMetricMembers)
+	if limit < 0 {
+		return nil
+	}
+
+	seats, err := s.countMembers(ctx, tenantID, true)
+	if err != nil {
+		return err
+	}
+	if seats >= limit {
+		return fmt.Errorf("%w: the %s plan allows %d members", ErrQuotaExceeded, tier, limit)
+	}
+	return nil
+}
+
+// MeterAPICall counts a validated API key use. It returns ErrQuotaExceeded only when
+// enforcement is enabled and the monthly quota is used up; metering failures are logged
+// rather than failing the call.
+func (s *UsageService) MeterAPICall(ctx context.Context, tenantID uuid.UUID, tier string) error {
+	period := currentUsagePeriod()
+	used, err := s.increment(s.db.WithContext(ctx), tenantID, models.UsageMetricAPICalls, period, 1)
+	if err != nil {
+		log.Printf("[UsageService] Failed to meter API call for tenant %s: %v", tenantID, err)
+		return nil
+	}
+
+	limit := quotaLimit(tier, models.UsageMetricAPICalls)
+	if limit < 0 || used <= limit {
+		return nil
+	}
+	s.notifyExceeded(ctx, tenantID, tier, models.UsageMetricAPICalls, period, used, limit)
+	if s.config.EnforceQuotas {
+		return fmt.Errorf("%w: the %s plan allows %d API calls per month", ErrQuotaExceeded, tier, limit)
+	}
+	return nil
+}
+
+// RecordDocumentEvent meters storage from a document-service upload or deletion event
+func (s *UsageService) RecordDocumentEvent(ctx context.Context, event *nats.DocumentUsageEvent) error {
+	var delta int64
+	switch event.EventType {
+	case nats.SubjectDocumentUploaded:
+		delta = event.FileSize
+	case nats.SubjectDocumentDeleted:
+		delta = -event.FileSize
+	default:
+		return nil
+	}
+	if delta == 0 || event.DocumentID == "" {
+		return nil
+	}
+
+	return s.recordEvent(ctx, event.TenantID, event.EventType+":"+event.DocumentID, models.UsageMetricStorage, "", delta)
+}
+
+// RecordNotificationSent meters emails from a notification-service send event
+func (s *UsageService) RecordNotificationSent(ctx context.Context, event *nats.NotificationSentEvent) error {
+	if !strings.EqualFold(event.Channel, "email") || event.NotificationID == "" {
+		return nil
+	}
+
+	return s.recordEvent(ctx, event.TenantID, nats.SubjectNotificationSent+":"+event.NotificationID, models.UsageMetricEmails, currentUsagePeriod(), 1)
+}
+
+// recordEvent applies an event's delta once, however often the event is delivered
+func (s *UsageService) recordEvent(ctx context.Context, tenantIDStr, eventKey, metric, period string, delta int64) error {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		// Events for non-tenant scopes (e.g. onboarding uploads) are not metered
+		return nil
+	}
+
+	var used int64
+	counted := false
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(
+			"INSERT INTO tenant_usage_events (event_key, tenant_id, created_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING",
+			eventKey, tenantID, time.Now(),
+		)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record usage event: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil // Redelivery of an event already metered
+		}
+
+		used, err = s.increment(tx, tenantID, metric, period, delta)
+		counted = err == nil
+		return err
+	})
+	if err != nil || !counted {
+		return err
+	}
+
+	tier, err := s.tenantTier(ctx, tenantID)
+	if err != nil {
+		log.Printf("[UsageService] Failed to check %s quota for tenant %s: %v", metric, tenantID, err)
+		return nil
+	}
+	limit := quotaLimit(tier, metric)
+	switch {
+	case limit >= 0 && used > limit:
+		s.notifyExceeded(ctx, tenantID, tier, metric, period, used, limit)
+	case period == "":
+		// Running totals can drop back under quota; a later breach is reported again
+		s.clearNotice(ctx, tenantID, metric, period)
+	}
+	return nil
+}
+
+// CheckMemberQuotas reports tenants whose active members exceed their plan quota, e.g. after
+// joins through SSO, domain auto-join or staff sync, which are never blocked
+func (s *UsageService) CheckMemberQuotas(ctx context.Context) (int, error) {
+	var rows []struct {
+		TenantID    uuid.UUID
+		PricingTier string
+		Members     int64
+	}
+	if err := s.db.WithContext(ctx).
+		Table("user_tenant_memberships AS m").
+		Select("m.tenant_id, t.pricing_tier, COUNT(*) AS members").
+		Joins("JOIN tenants t ON t.id = m.tenant_id").
+		Where("m.is_active = ? AND m.role <> ? AND t.status = ?", true, "customer", "active").
+		Group("m.tenant_id, t.pricing_tier").
+		Scan(&rows).Error; err != nil {
+		return 0, fmt.Errorf("failed to count members: %w", err)
+	}
+
+	exceeded := 0
+	overQuota := make([]uuid.UUID, 0)
+	for _, row := range rows {
+		limit := quotaLimit(row.PricingTier, models.UsageMetricMembers)
+		if limit < 0 || row.Members <= limit {
+			continue
+		}
+		exceeded++
+		overQuota = append(overQuota, row.TenantID)
+		s.notifyExceeded(ctx, row.TenantID, row.PricingTier, models.UsageMetricMembers, "", row.Members, limit)
+	}
+
+	// Tenants back under quota (members removed or plan upgraded) are reported again on a later breach
+	query := s.db.WithContext(ctx).Where("metric = ? AND period = ?", models.UsageMetricMembers, "")
+	if len(overQuota) > 0 {
+		query = query.Where("tenant_id NOT IN ?", overQuota)
+	}
+	var cleared []models.TenantQuotaNotice
+	if err := query.Find(&cleared).Error; err != nil {
+		return exceeded, fmt.Errorf("failed to load member quota notices: %w", err)
+	}
+	for _, notice := range cleared {
+		s.clearNotice(ctx, notice.TenantID, models.UsageMetricMembers, "")
+	}
+
+	return exceeded, nil
+}
+
+// PruneEvents deletes metered event keys older than the retention window; redeliveries
+// arrive within minutes, so old keys only take space
+func (s *UsageService) PruneEvents(ctx context.Context) (int64, error) {
+	days := s.config.EventRetentionDays
+	if days <= 0 {
+		days = 30
+	}
+	result := s.db.WithContext(ctx).
+		Where("created_at < ?", time.Now().AddDate(0, 0, -days)).
+		Delete(&models.TenantUsageEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to prune usage events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// increment adds delta to a counter, never going below zero, and returns the new value
+func (s *UsageService) increment(db *gorm.DB, tenantID uuid.UUID, metric, period string, delta int64) (int64, error) {
+	var value int64
+	if err := db.Raw(`
+		INSERT INTO tenant_usage_counters (tenant_id, metric, period, value, updated_at)
+		VALUES (?, ?, ?, GREATEST(?::bigint, 0), NOW())
+		ON CONFLICT (tenant_id, metric, period)
+		DO UPDATE SET value = GREATEST(tenant_usage_counters.value + ?::bigint, 0), updated_at = NOW()
+		RETURNING value`,
+		tenantID, metric, period, delta, delta,
+	).Scan(&value).Error; err != nil {
+		return 0, fmt.Errorf("failed to update %s usage: %w", metric, err)
+	}
+	return value, nil
+}
+
+// notifyExceeded publishes tenant.quota.exceeded once per tenant, metric and period
+func (s *UsageService) notifyExceeded(ctx context.Context, tenantID uuid.UUID, tier, metric, period string, used, limit int64) {
+	key := tenantID.String() + ":" + metric + ":" + period
+	if _, ok := s.notified.Load(key); ok {
+		return
+	}
+
+	result := s.db.WithContext(ctx).Exec(
+		"INSERT INTO tenant_quota_notices (tenant_id, metric, period, notified_at) VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING",
+		tenantID, metric, period, time.Now(),
+	)
+	if result.Error != nil {
+		log.Printf("[UsageService] Failed to record %s quota notice for tenant %s: %v", metric, tenantID, result.Error)
+		return
+	}
+	s.notified.Store(key, struct{}{})
+	if result.RowsAffected == 0 {
+		return // Already reported, possibly by another instance
+	}
+
+	log.Printf("[UsageService] Tenant %s exceeded its %s quota (%d/%d, tier: %s)", tenantID, metric, used, limit, tier)
+	if s.publisher == nil {
+		return
+	}
+	if err := s.publisher.PublishTenantQuotaExceeded(ctx, &nats.TenantQuotaExceededEvent{
+		TenantID:    tenantID.String(),
+		Metric:      metric,
+		Period:      period,
+		Used:        used,
+		Limit:       limit,
+		PricingTier: tier,
+		Enforced:    s.config.EnforceQuotas && metric != models.UsageMetricStorage && metric != models.UsageMetricEmails,
+	}); err != nil {
+		// Release the notice so the breach is reported on the next check
+		log.Printf("[UsageService] Failed to publish %s quota exceeded for tenant %s: %v", metric, tenantID, err)
+		s.clearNotice(ctx, tenantID, metric, period)
+	}
+}
+
+// clearNotice forgets a reported breach so the next one is reported again
+func (s *UsageService) clearNotice(ctx context.Context, tenantID uuid.UUID, metric, period string) {
+	s.notified.Delete(tenantID.String() + ":" + metric + ":" + period)
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND metric = ? AND period = ?", tenantID, metric, period).
+		Delete(&models.TenantQuotaNotice{}).Error; err != nil {
+		log.Printf("[UsageService] Failed to clear %s quota notice for tenant %s: %v", metric, tenantID, err)
+	}
+}
+
+// countMembers counts the tenant's active members, plus pending invitations when reserving seats
+func (s *UsageService) countMembers(ctx context.Context, tenantID uuid.UUID, includeInvitations bool) (int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.UserTenantMembership{}).Where("tenant_id = ? AND role <> ?", tenantID, "customer")
+	if includeInvitations {
+		query = query.Where(`is_active = ? OR (invitation_token <> '' AND accepted_at IS NULL
+			AND invitation_revoked_at IS NULL AND invitation_expires_at > ?)`, true, time.Now())
+	} else {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count members: %w", err)
+	}
+	return count, nil
+}
+
+// tenantTier returns the tenant's pricing tier
+func (s *UsageService) tenantTier(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	var tenant models.Tenant
+	if err := s.db.WithContext(ctx).Select("id", "pricing_tier").First(&tenant, "id = ?", tenantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("tenant not found")
+		}
+		return "", fmt.Errorf("failed to get tenant: %w", err)
+	}
+	return tenant.PricingTier, nil
+}
+
+// quotaLimit returns the plan quota of a metric, -1 for unlimited. Unknown tiers get the free quotas.
+func quotaLimit(tier, metric string) int64 {
+	tiers := models.GetPricingTiers()
+	plan, ok := tiers[tier]
+	if !ok {
+		plan = tiers[models.PricingTierFree]
+	}
+
+	var limit int
+	switch metric {
+	case models.UsageMetricMembers:
+		limit = plan.MaxUsers
+	case models.UsageMetricAPICalls:
+		limit = plan.MaxAPICallsPerMonth
+	case models.UsageMetricStorage:
+		if plan.MaxStorageMB < 0 {
+			return -1
+		}
+		return int64(plan.MaxStorageMB) * 1024 * 1024
+	case models.UsageMetricEmails:
+		limit = plan.MaxEmailsPerMonth
+	default:
+		return -1
+	}
+	if limit < 0 {
+		return -1
+	}
+	return int64(limit)
+}
+
+// currentUsagePeriod returns the current monthly usage period (UTC)
+func currentUsagePeriod() string {
+	return time.Now().UTC().Format(models.UsagePeriodFormat)
+}
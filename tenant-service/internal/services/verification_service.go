@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"tenant-service/internal/clients"
 	"tenant-service/internal/config"
+	"tenant-service/internal/metrics"
 	"tenant-service/internal/models"
 	tsnats "tenant-service/internal/nats"
 	"tenant-service/internal/redis"
@@ -91,7 +92,9 @@ func (s *VerificationService) GetDNSConfig(ctx context.Context, sessionID uuid.U
 }
 
 // StartEmailVerification initiates email verification process
-func (s *VerificationService) StartEmailVerification(ctx context.Context, sessionID uuid.UUID, email string) (*models.VerificationRecord, error) {
+func (s *VerificationService) StartEmailVerification(ctx context.Context, sessionID uuid.UUID, email string) (_ *models.VerificationRecord, err error) {
+	defer metrics.ObserveOnboardingStep(ctx, metrics.StepEmailVerification, time.Now(), &err)
+
 	method := s.GetVerificationMethod()
 
 	if method == VerificationMethodLink {
@@ -102,7 +105,9 @@ func (s *VerificationService) StartEmailVerification(ctx context.Context, sessio
 }
 
 // StartEmailVerificationWithBusinessName initiates email verification with business name for personalized emails
-func (s *VerificationService) StartEmailVerificationWithBusinessName(ctx context.Context, sessionID uuid.UUID, email, businessName string) (*models.VerificationRecord, error) {
+func (s *VerificationService) StartEmailVerificationWithBusinessName(ctx context.Context, sessionID uuid.UUID, email, businessName string) (_ *models.VerificationRecord, err error) {
+	defer metrics.ObserveOnboardingStep(ctx, metrics.StepEmailVerification, time.Now(), &err)
+
 	method := s.GetVerificationMethod()
 
 	if method == VerificationMethodLink {
@@ -124,6 +129,7 @@ func (s *VerificationService) startEmailVerificationWithOTP(ctx context.Context,
 	if err != nil {
 		return nil, fmt.Errorf("failed to send verification code: %w", err)
 	}
+	metrics.RecordVerificationCodeGenerated(ctx, "email")
 
 	// Map response to VerificationRecord for backwards compatibility
 	record := &models.VerificationRecord{
@@ -210,6 +216,7 @@ func (s *VerificationService) startEmailVerificationWithLinkAndBusinessName(ctx
 		_ = s.redisClient.DeleteVerificationToken(ctx, token)
 		return nil, fmt.Errorf("failed to send verification email: %w", err)
 	}
+	metrics.RecordVerificationCodeGenerated(ctx, "email_link")
 
 	if dnsConfig != nil && dnsConfig.IsCustomDomain {
 		log.Printf("[VerificationService] Verification email sent with DNS instructions for custom domain %s", dnsConfig.CustomDomain)
@@ -429,10 +436,12 @@ func (s *VerificationService) VerifyByToken(ctx context.Context, token string) (
 	// Get token data from Redis
 	tokenData, err := s.redisClient.GetVerificationToken(ctx, token)
 	if err != nil {
+		metrics.RecordVerificationAttempt(ctx, "email_link", metrics.VerificationError)
 		return nil, fmt.Errorf("failed to retrieve verification token: %w", err)
 	}
 
 	if tokenData == nil {
+		metrics.RecordVerificationAttempt(ctx, "email_link", metrics.VerificationExpired)
 		return nil, fmt.Errorf("verification token not found or expired")
 	}
 
@@ -454,6 +463,7 @@ func (s *VerificationService) VerifyByToken(ctx context.Context, token string) (
 			if currentEmail != "" && strings.ToLower(currentEmail) != strings.ToLower(tokenData.Email) {
 				log.Printf("[VerificationService] SECURITY: Token email mismatch! Token has %s but session contact is %s",
 					tokenData.Email, currentEmail)
+				metrics.RecordVerificationAttempt(ctx, "email_link", metrics.VerificationFailed)
 				return nil, fmt.Errorf("verification token is invalid: email does not match current session contact")
 			}
 			log.Printf("[VerificationService] Token email validated against session contact: %s", tokenData.Email)
@@ -462,8 +472,10 @@ func (s *VerificationService) VerifyByToken(ctx context.Context, token string) (
 
 	// Mark email as verified in Redis
 	if err := s.redisClient.SaveEmailVerificationStatus(ctx, tokenData.Email, tokenData.SessionID, true, 7*24*time.Hour); err != nil {
+		metrics.RecordVerificationAttempt(ctx, "email_link", metrics.VerificationError)
 		return nil, fmt.Errorf("failed to save verification status: %w", err)
 	}
+	metrics.RecordVerificationAttempt(ctx, "email_link", metrics.VerificationSuccess)
 
 	// Delete the used token
 	if err := s.redisClient.DeleteVerificationToken(ctx, token); err != nil {
@@ -510,6 +522,7 @@ func (s *VerificationService) StartPhoneVerification(ctx context.Context, sessio
 	if err != nil {
 		return nil, fmt.Errorf("failed to send verification code: %w", err)
 	}
+	metrics.RecordVerificationCodeGenerated(ctx, "phone")
 
 	// Map response to VerificationRecord
 	record := &models.VerificationRecord{
@@ -540,6 +553,12 @@ func (s *VerificationService) VerifyCode(ctx context.Context, sessionID uuid.UUI
 
 // VerifyCodeWithRecipient verifies a verification code with recipient information
 func (s *VerificationService) VerifyCodeWithRecipient(ctx context.Context, sessionID uuid.UUID, recipient, code, purpose string) (*models.VerificationRecord, error) {
+	// Map purpose to verification type
+	verificationType := "email"
+	if purpose == "phone_verification" {
+		verificationType = "phone"
+	}
+
 	// Call verification service to verify code
 	resp, err := s.verificationClient.VerifyCode(ctx, &clients.VerifyCodeRequest{
 		Recipient: recipient,
@@ -547,12 +566,15 @@ func (s *VerificationService) VerifyCodeWithRecipient(ctx context.Context, sessi
 		Purpose:   purpose,
 	})
 	if err != nil {
+		metrics.RecordVerificationAttempt(ctx, verificationType, metrics.VerificationError)
 		return nil, fmt.Errorf("failed to verify code: %w", err)
 	}
 
 	if !resp.Verified {
+		metrics.RecordVerificationAttempt(ctx, verificationType, metrics.VerificationFailed)
 		return nil, fmt.Errorf("%s", resp.Message)
 	}
+	metrics.RecordVerificationAttempt(ctx, verificationType, metrics.VerificationSuccess)
 
 	// Store verification status in Redis so IsEmailVerifiedByRecipient can find it later
 	// This is critical for OTP-based verification where the verification-service may not
@@ -568,11 +590,6 @@ func (s *VerificationService) VerifyCodeWithRecipient(ctx context.Context, sessi
 	}
 
 	// Map response to VerificationRecord
-	verificationType := "email"
-	if purpose == "phone_verification" {
-		verificationType = "phone"
-	}
-
 	record := &models.VerificationRecord{
 		OnboardingSessionID: sessionID,
 		VerificationType:    verificationType,
@@ -613,6 +630,7 @@ func (s *VerificationService) ResendVerificationCode(ctx context.Context, sessio
 	if err != nil {
 		return nil, fmt.Errorf("failed to resend verification code: %w", err)
 	}
+	metrics.RecordVerificationCodeGenerated(ctx, verificationType)
 
 	// Map response to VerificationRecord
 	record := &models.VerificationRecord{
@@ -0,0 +1,491 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tenant-service/internal/config"
+	"tenant-service/internal/listquery"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+const (
+	// MaxWebhookBulkReplay caps how many dead-lettered events one bulk replay may resend
+	MaxWebhookBulkReplay = 100
+
+	// webhookBaseRetryDelay is the delay before the first retry; each retry waits 4x longer
+	webhookBaseRetryDelay = 30 * time.Second
+	// webhookMaxRetryDelay caps the exponential retry delay
+	webhookMaxRetryDelay = 6 * time.Hour
+	// webhookResponseBodyLimit bounds the response body stored per attempt
+	webhookResponseBodyLimit = 4096
+	// webhookDueBatchSize bounds how many events one delivery run sends
+	webhookDueBatchSize = 100
+	// webhookStatsDefaultWindow is the stats window when no start time is given
+	webhookStatsDefaultWindow = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrWebhookAccessForbidden is returned when the user may not manage the tenant's webhooks
+	ErrWebhookAccessForbidden = errors.New("only tenant owners and admins can manage webhook deliveries")
+	// ErrWebhookEventNotFound is returned when the event does not exist for the tenant
+	ErrWebhookEventNotFound = errors.New("webhook event not found")
+	// ErrWebhookEventNotReplayable is returned when an event is still queued for automatic delivery
+	ErrWebhookEventNotReplayable = errors.New("webhook event is still pending automatic delivery")
+)
+
+// WebhookDeliveryService delivers webhook events, records every attempt and
+// exposes dead letters, manual replay and per-endpoint failure statistics
+type WebhookDeliveryService struct {
+	db             *gorm.DB
+	membershipRepo *repository.MembershipRepository
+	httpClient     *http.Client
+	interval       time.Duration
+}
+
+// WebhookReplayResult is the outcome of replaying one webhook event
+type WebhookReplayResult struct {
+	EventID    uuid.UUID `json:"event_id"`
+	Success    bool      `json:"success"`
+	Status     string    `json:"status,omitempty"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// WebhookBulkReplayRequest selects dead-lettered events to replay, either by ID or by filter
+type WebhookBulkReplayRequest struct {
+	EventIDs   []uuid.UUID `json:"event_ids"`
+	EventType  string      `json:"event_type"`
+	WebhookURL string      `json:"webhook_url"`
+	Limit      int         `json:"limit"`
+}
+
+// WebhookBulkReplayResult summarises a bulk replay
+type WebhookBulkReplayResult struct {
+	Requested int                   `json:"requested"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []WebhookReplayResult `json:"results"`
+}
+
+// WebhookEndpointStats aggregates delivery attempts for one endpoint URL
+type WebhookEndpointStats struct {
+	WebhookURL         string     `json:"webhook_url"`
+	TotalAttempts      int64      `json:"total_attempts"`
+	SuccessfulAttempts int64      `json:"successful_attempts"`
+	FailedAttempts     int64      `json:"failed_attempts"`
+	FailureRate        float64    `json:"failure_rate"`
+	AvgDurationMs      float64    `json:"avg_duration_ms"`
+	DeadLetters        int64      `json:"dead_letters"`
+	LastSuccessAt      *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt      *time.Time `json:"last_failure_at,omitempty"`
+	LastError          string     `json:"last_error,omitempty"`
+	LastStatusCode     int        `json:"last_status_code,omitempty"`
+}
+
+// NewWebhookDeliveryService creates a new webhook delivery service
+func NewWebhookDeliveryService(db *gorm.DB, cfg config.WebhookConfig) *WebhookDeliveryService {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	interval := time.Duration(cfg.DeliveryIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &WebhookDeliveryService{
+		db:             db,
+		membershipRepo: repository.NewMembershipRepository(db),
+		httpClient:     &http.Client{Timeout: timeout},
+		interval:       interval,
+	}
+}
+
+// Interval returns how often the background job should deliver due events
+func (s *WebhookDeliveryService) Interval() time.Duration {
+	return s.interval
+}
+
+// AuthorizeWebhookAccess checks the user is an owner or admin of the tenant
+func (s *WebhookDeliveryService) AuthorizeWebhookAccess(ctx context.Context, tenantID, userID uuid.UUID) error {
+	role, err := s.membershipRepo.GetUserRole(ctx, userID, tenantID)
+	if err != nil {
+		return ErrWebhookAccessForbidden
+	}
+	if role != models.MembershipRoleOwner && role != models.MembershipRoleAdmin {
+		return ErrWebhookAccessForbidden
+	}
+	return nil
+}
+
+// WebhookRetryDelay returns the backoff before the next automatic attempt after
+// the given number of failed attempts (30s, 2m, 8m, ... capped at 6h)
+func WebhookRetryDelay(failedAttempts int) time.Duration {
+	if failedAttempts < 1 {
+		failedAttempts = 1
+	}
+	delay := webhookBaseRetryDelay
+	for i := 1; i < failedAttempts; i++ {
+		delay *= 4
+		if delay >= webhookMaxRetryDelay {
+			return webhookMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+// DeliverDue sends pending events whose retry time has come and returns how many were attempted
+// Each event is claimed with a conditional update so concurrent replicas never send it twice
+func (s *WebhookDeliveryService) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	var events []models.WebhookEvent
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND webhook_url <> '' AND (next_retry_at IS NULL OR next_retry_at <= ?)", models.WebhookStatusPending, now).
+		Order("created_at ASC").
+		Limit(webhookDueBatchSize).
+		Find(&events).Error; err != nil {
+		return 0, fmt.Errorf("failed to load due webhook events: %w", err)
+	}
+
+	delivered := 0
+	for i := range events {
+		event := &events[i]
+
+		// Lease the event past the HTTP timeout so a crashed run is retried later
+		lease := now.Add(2 * s.httpClient.Timeout)
+		result := s.db.WithContext(ctx).Model(&models.WebhookEvent{}).
+			Where("id = ? AND status = ? AND attempts = ?", event.ID, models.WebhookStatusPending, event.Attempts).
+			Update("next_retry_at", lease)
+		if result.Error != nil {
+			return delivered, fmt.Errorf("failed to claim webhook event: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue // Claimed by another replica
+		}
+
+		if _, err := s.deliver(ctx, event, models.WebhookTriggerAutomatic); err != nil {
+			log.Printf("[WebhookDelivery] Failed to record delivery of event %s: %v", event.ID, err)
+			continue
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// ListDeadLetters returns the tenant's dead-lettered events
+func (s *WebhookDeliveryService) ListDeadLetters(ctx context.Context, tenantID uuid.UUID, q *listquery.Query) ([]models.WebhookEvent, int64, string, error) {
+	query := s.db.WithContext(ctx).Model(&models.WebhookEvent{}).
+		Where("tenant_id = ? AND status = ?", tenantID, models.WebhookStatusFailed).
+		Scopes(q.FilterScope())
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to count dead-lettered webhook events: %w", err)
+	}
+
+	var events []models.WebhookEvent
+	if err := query.Scopes(q.PageScope()).Find(&events).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to list dead-lettered webhook events: %w", err)
+	}
+
+	events, next, err := listquery.NextPage(q, events)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return events, total, next, nil
+}
+
+// ListAttempts returns the delivery attempt history of a tenant's event, oldest first
+func (s *WebhookDeliveryService) ListAttempts(ctx context.Context, tenantID, eventID uuid.UUID) (*models.WebhookEvent, []models.WebhookDeliveryAttempt, error) {
+	event, err := s.getEvent(ctx, tenantID, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var attempts []models.WebhookDeliveryAttempt
+	if err := s.db.WithContext(ctx).
+		Where("webhook_event_id = ?", eventID).
+		Order("created_at ASC").
+		Find(&attempts).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to list webhook delivery attempts: %w", err)
+	}
+	return event, attempts, nil
+}
+
+// Replay immediately resends one of the tenant's events
+// Dead-lettered and already delivered events can be replayed; pending events are left to the retry job
+func (s *WebhookDeliveryService) Replay(ctx context.Context, tenantID, eventID uuid.UUID) (*WebhookReplayResult, error) {
+	event, err := s.getEvent(ctx, tenantID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == models.WebhookStatusPending {
+		return nil, ErrWebhookEventNotReplayable
+	}
+	return s.replay(ctx, event)
+}
+
+// BulkReplay resends dead-lettered events selected by ID or by event type and endpoint
+func (s *WebhookDeliveryService) BulkReplay(ctx context.Context, tenantID uuid.UUID, req WebhookBulkReplayRequest) (*WebhookBulkReplayResult, error) {
+	if len(req.EventIDs) > MaxWebhookBulkReplay {
+		return nil, NewValidationError("event_ids", fmt.Sprintf("at most %d events can be replayed at once", MaxWebhookBulkReplay), nil)
+	}
+	if req.Limit < 0 || req.Limit > MaxWebhookBulkReplay {
+		return nil, NewValidationError("limit", fmt.Sprintf("limit must be between 1 and %d", MaxWebhookBulkReplay), nil)
+	}
+	if len(req.EventIDs) == 0 && req.EventType == "" && req.WebhookURL == "" {
+		return nil, NewValidationError("event_ids", "event_ids or an event_type/webhook_url filter is required", nil)
+	}
+
+	query := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND status = ?", tenantID, models.WebhookStatusFailed)
+	if len(req.EventIDs) > 0 {
+		query = query.Where("id IN ?", req.EventIDs)
+	}
+	if req.EventType != "" {
+		query = query.Where("event_type = ?", req.EventType)
+	}
+	if req.WebhookURL != "" {
+		query = query.Where("webhook_url = ?", req.WebhookURL)
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = MaxWebhookBulkReplay
+	}
+
+	var events []models.WebhookEvent
+	if err := query.Order("created_at ASC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to load dead-lettered webhook events: %w", err)
+	}
+
+	result := &WebhookBulkReplayResult{Results: make([]WebhookReplayResult, 0, len(events))}
+
+	// Report requested IDs that are not dead letters of this tenant
+	if len(req.EventIDs) > 0 {
+		found := make(map[uuid.UUID]bool, len(events))
+		for _, event := range events {
+			found[event.ID] = true
+		}
+		for _, id := range req.EventIDs {
+			if !found[id] {
+				result.Results = append(result.Results, WebhookReplayResult{EventID: id, Error: "event is not dead-lettered"})
+				result.Failed++
+			}
+		}
+	}
+
+	for i := range events {
+		replayed, err := s.replay(ctx, &events[i])
+		if err != nil {
+			replayed = &WebhookReplayResult{EventID: events[i].ID, Error: err.Error()}
+		}
+		if replayed.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+		result.Results = append(result.Results, *replayed)
+	}
+	result.Requested = len(result.Results)
+	return result, nil
+}
+
+// EndpointStats aggregates the tenant's delivery attempts per endpoint since the given time
+func (s *WebhookDeliveryService) EndpointStats(ctx context.Context, tenantID uuid.UUID, since time.Time) ([]WebhookEndpointStats, error) {
+	if since.IsZero() {
+		since = time.Now().Add(-webhookStatsDefaultWindow)
+	}
+
+	var stats []WebhookEndpointStats
+	if err := s.db.WithContext(ctx).Model(&models.WebhookDeliveryAttempt{}).
+		Select(`webhook_url,
+			COUNT(*) AS total_attempts,
+			COUNT(*) FILTER (WHERE success) AS successful_attempts,
+			COUNT(*) FILTER (WHERE NOT success) AS failed_attempts,
+			COALESCE(AVG(duration_ms), 0) AS avg_duration_ms,
+			MAX(created_at) FILTER (WHERE success) AS last_success_at,
+			MAX(created_at) FILTER (WHERE NOT success) AS last_failure_at`).
+		Where("tenant_id = ? AND created_at >= ?", tenantID, since).
+		Group("webhook_url").
+		Order("failed_attempts DESC").
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to aggregate webhook delivery attempts: %w", err)
+	}
+
+	var deadLetters []struct {
+		WebhookURL string
+		Count      int64
+	}
+	if err := s.db.WithContext(ctx).Model(&models.WebhookEvent{}).
+		Select("webhook_url, COUNT(*) AS count").
+		Where("tenant_id = ? AND status = ?", tenantID, models.WebhookStatusFailed).
+		Group("webhook_url").
+		Scan(&deadLetters).Error; err != nil {
+		return nil, fmt.Errorf("failed to count dead-lettered webhook events: %w", err)
+	}
+	deadLettersByURL := make(map[string]int64, len(deadLetters))
+	for _, d := range deadLetters {
+		deadLettersByURL[d.WebhookURL] = d.Count
+	}
+
+	for i := range stats {
+		st := &stats[i]
+		st.DeadLetters = deadLettersByURL[st.WebhookURL]
+		if st.TotalAttempts > 0 {
+			st.FailureRate = float64(st.FailedAttempts) / float64(st.TotalAttempts)
+		}
+		if st.LastFailureAt == nil {
+			continue
+		}
+
+		var lastFailure models.WebhookDeliveryAttempt
+		err := s.db.WithContext(ctx).
+			Where("tenant_id = ? AND webhook_url = ? AND success = ?", tenantID, st.WebhookURL, false).
+			Order("created_at DESC").
+			First(&lastFailure).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to load last webhook failure: %w", err)
+		}
+		st.LastError = lastFailure.Error
+		st.LastStatusCode = lastFailure.StatusCode
+	}
+	return stats, nil
+}
+
+func (s *WebhookDeliveryService) getEvent(ctx context.Context, tenantID, eventID uuid.UUID) (*models.WebhookEvent, error) {
+	var event models.WebhookEvent
+	err := s.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", eventID, tenantID).
+		First(&event).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookEventNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook event: %w", err)
+	}
+	return &event, nil
+}
+
+func (s *WebhookDeliveryService) replay(ctx context.Context, event *models.WebhookEvent) (*WebhookReplayResult, error) {
+	event.ReplayCount++
+	attempt, err := s.deliver(ctx, event, models.WebhookTriggerManualReplay)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookReplayResult{
+		EventID:    event.ID,
+		Success:    attempt.Success,
+		Status:     event.Status,
+		StatusCode: attempt.StatusCode,
+		Error:      attempt.Error,
+	}, nil
+}
+
+// deliver sends the event once, records the attempt and updates the event's delivery state
+// Automatic failures are retried with backoff until MaxAttempts, then dead-lettered;
+// a failed manual replay leaves the event dead-lettered
+func (s *WebhookDeliveryService) deliver(ctx context.Context, event *models.WebhookEvent, trigger string) (*models.WebhookDeliveryAttempt, error) {
+	attempt := s.send(ctx, event, event.Attempts+1)
+	attempt.Trigger = trigger
+
+	now := time.Now()
+	event.Attempts++
+	event.ResponseStatus = attempt.StatusCode
+	event.ResponseBody = attempt.ResponseBody
+	event.LastError = attempt.Error
+
+	switch {
+	case attempt.Success:
+		event.Status = models.WebhookStatusSent
+		event.DeliveredAt = &now
+		event.DeadLetteredAt = nil
+		event.NextRetryAt = nil
+	case trigger == models.WebhookTriggerAutomatic && event.Attempts < event.MaxAttempts:
+		next := now.Add(WebhookRetryDelay(event.Attempts))
+		event.NextRetryAt = &next
+	default:
+		event.Status = models.WebhookStatusFailed
+		event.NextRetryAt = nil
+		if event.DeadLetteredAt == nil {
+			event.DeadLetteredAt = &now
+		}
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.WebhookEvent{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+			"status":           event.Status,
+			"attempts":         event.Attempts,
+			"next_retry_at":    event.NextRetryAt,
+			"response_status":  event.ResponseStatus,
+			"response_body":    event.ResponseBody,
+			"last_error":       event.LastError,
+			"delivered_at":     event.DeliveredAt,
+			"dead_lettered_at": event.DeadLetteredAt,
+			"replay_count":     event.ReplayCount,
+			"updated_at":       now,
+		}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+	return attempt, nil
+}
+
+// send performs the HTTP POST for one attempt
+func (s *WebhookDeliveryService) send(ctx context.Context, event *models.WebhookEvent, attemptNumber int) *models.WebhookDeliveryAttempt {
+	attempt := &models.WebhookDeliveryAttempt{
+		WebhookEventID: event.ID,
+		TenantID:       event.TenantID,
+		WebhookURL:     event.WebhookURL,
+		AttemptNumber:  attemptNumber,
+	}
+
+	payload := []byte(event.Payload)
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, event.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		attempt.Error = fmt.Sprintf("invalid webhook request: %v", err)
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tesseract-tenant-webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", event.ID.String())
+	req.Header.Set("X-Webhook-Event", event.EventType)
+	req.Header.Set("X-Webhook-Attempt", strconv.Itoa(attemptNumber))
+
+	start := time.Now()
+	resp, err := s.httpClient.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	attempt.StatusCode = resp.StatusCode
+	attempt.ResponseBody = string(body)
+	attempt.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !attempt.Success {
+		attempt.Error = fmt.Sprintf("endpoint responded with HTTP %d", resp.StatusCode)
+	}
+	return attempt
+}
@@ -14,6 +14,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"tenant-service/docs"
 	"tenant-service/internal/background"
 	"tenant-service/internal/clients"
 	"tenant-service/internal/config"
@@ -31,6 +34,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // vendorClientAdapter adapts clients.VendorClient to repository.VendorClientInterface
@@ -43,6 +47,18 @@ func (a *vendorClientAdapter) GetStorefrontBySlug(ctx context.Context, slug stri
 	return a.client.GetStorefrontBySlug(ctx, slug)
 }
 
+// @title Tenant Service API
+// @version 1.0.0
+// @description Tenant onboarding, membership, tenant-aware authentication and tenant administration for Tesseract Hub
+
+// @host localhost:8086
+// @BasePath /
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and JWT token.
+
 func main() {
 	// Load configuration
 	cfg := config.New()
@@ -58,6 +74,14 @@ func main() {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Apply the seed profile of default templates and reserved slugs (SEED_PROFILE, else APP_ENV)
+	seedProfileSvc := services.NewSeedProfileService(db, cfg.Seed)
+	if plan, err := seedProfileSvc.Apply(context.Background(), ""); err != nil {
+		log.Printf("Warning: Failed to apply seed profile: %v", err)
+	} else {
+		log.Printf("Applied seed profile %s (checksum %.12s): %d changes, %d unchanged", plan.Profile, plan.Checksum, len(plan.Changes), plan.Unchanged)
+	}
+
 	// Initialize Redis connection
 	var redisClient *redis.Client
 	redisClient, err = redis.NewClient(cfg.Redis)
@@ -104,6 +128,20 @@ func main() {
 	membershipRepo.SetTenantRouterClient(tenantRouterClient)
 	log.Printf("Initialized tenant-router-service client: %s", tenantRouterServiceURL)
 
+	// Cache tenant-by-slug and memberships-by-user lookups; invalidations fan out over NATS
+	if redisClient != nil {
+		membershipRepo.SetCache(redisClient)
+		if nc != nil {
+			membershipRepo.SetCachePublisher(nc)
+			if err := nc.SubscribeMembershipCacheInvalidated(func(event *natsClient.MembershipCacheInvalidatedEvent) {
+				membershipRepo.EvictCachedLookups(event.Keys)
+			}); err != nil {
+				log.Printf("Warning: Failed to subscribe to membership cache invalidations: %v", err)
+			}
+		}
+		log.Println("Membership repository: tenant and membership lookup cache enabled")
+	}
+
 	// Initialize clients
 	verificationServiceURL := getEnv("VERIFICATION_SERVICE_URL", "http://localhost:8088")
 	// Load verification API key from GCP Secret Manager (production) or env var (dev)
@@ -129,6 +167,28 @@ func main() {
 	templateSvc := services.NewTemplateService(templateRepo)
 	notificationSvc := services.NewNotificationService()
 	membershipSvc := services.NewMembershipService(membershipRepo)
+	membershipSvc.SetInvitationConfig(cfg.Invitation)
+	if nc != nil {
+		membershipSvc.SetEventPublisher(nc)
+	}
+
+	// Initialize usage metering (members, API calls, storage, emails) against plan quotas
+	usageSvc := services.NewUsageService(db, cfg.Usage)
+	membershipSvc.SetMemberQuotaChecker(usageSvc)
+	if nc != nil {
+		usageSvc.SetEventPublisher(nc)
+		if err := nc.SubscribeDocumentUsage(func(event *natsClient.DocumentUsageEvent) error {
+			return usageSvc.RecordDocumentEvent(context.Background(), event)
+		}); err != nil {
+			log.Printf("Warning: Failed to subscribe to document usage events: %v", err)
+		}
+		if err := nc.SubscribeNotificationSent(func(event *natsClient.NotificationSentEvent) error {
+			return usageSvc.RecordNotificationSent(context.Background(), event)
+		}); err != nil {
+			log.Printf("Warning: Failed to subscribe to notification usage events: %v", err)
+		}
+	}
+	log.Printf("UsageService initialized (quota enforcement: %v)", cfg.Usage.EnforceQuotas)
 	onboardingSvc := services.NewOnboardingService(
 		onboardingRepo,
 		taskRepo,
@@ -139,6 +199,30 @@ func main() {
 		db,
 	)
 
+	// Initialize plans and subscriptions (trials, plan feature flags)
+	subscriptionSvc := services.NewSubscriptionService(db, cfg.Subscription)
+	subscriptionSvc.SetCacheInvalidator(membershipSvc)
+	if nc != nil {
+		subscriptionSvc.SetEventPublisher(nc)
+	}
+	onboardingSvc.SetSubscriptionStarter(subscriptionSvc)
+	log.Printf("SubscriptionService initialized (default plan: %s, trial plan: %q)", cfg.Subscription.DefaultPlan, cfg.Subscription.TrialPlan)
+
+	// Initialize billing provider integration (webhook events, payment failure suspension)
+	billingSvc := services.NewBillingService(db, subscriptionSvc, cfg.Billing)
+	if cfg.Billing.WebhookSecret == "" {
+		log.Println("Warning: BILLING_WEBHOOK_SECRET not set - billing webhooks are rejected")
+	}
+
+	// Initialize tenant slug renames (old slugs redirect until TENANT_SLUG_REDIRECT_DAYS)
+	slugChangeSvc := services.NewSlugChangeService(db, membershipRepo, cfg.SlugChange, cfg.URL.BaseDomain)
+	if nc != nil {
+		slugChangeSvc.SetEventPublisher(nc)
+	}
+
+	// Initialize business contact changes (verified through verification-service before they apply)
+	contactChangeSvc := services.NewContactChangeService(db, membershipRepo, verificationClient)
+
 	// Initialize draft service (with optional Redis)
 	var draftSvc *services.DraftService
 	if redisClient != nil {
@@ -157,6 +241,9 @@ func main() {
 
 	// Initialize tenant service (for quick tenant creation)
 	tenantSvc := services.NewTenantService(db, membershipSvc, vendorClient, nc)
+	if redisClient != nil {
+		tenantSvc.SetCache(redisClient)
+	}
 
 	// Initialize Keycloak admin client for offboarding cleanup
 	var keycloakClient *auth.KeycloakAdminClient
@@ -180,6 +267,19 @@ func main() {
 	// Initialize offboarding service (for tenant deletion)
 	offboardingSvc := services.NewOffboardingService(db, membershipSvc, nc, keycloakClient)
 
+	// Initialize tenant deletion saga (cross-service purge coordination)
+	deletionSagaSvc := services.NewTenantDeletionSagaService(db, nc, cfg.Deletion)
+	offboardingSvc.SetDeletionSaga(deletionSagaSvc)
+	offboardingSvc.SetDeletionConfig(cfg.Deletion)
+	if nc != nil {
+		if err := nc.SubscribeTenantDeletionAcknowledged(func(event *natsClient.TenantDeletionAcknowledgedEvent) error {
+			return deletionSagaSvc.HandleAcknowledgment(context.Background(), event)
+		}); err != nil {
+			log.Printf("Warning: Failed to subscribe to tenant deletion acknowledgments: %v", err)
+		}
+	}
+	log.Printf("TenantDeletionSagaService initialized (participants: %v)", cfg.Deletion.Participants)
+
 	// Initialize tenant auth service for multi-tenant credential isolation
 	// This enables the same email to have different passwords per tenant
 	var tenantAuthSvc *services.TenantAuthService
@@ -205,6 +305,17 @@ func main() {
 		log.Println("TenantAuthService initialized (without Keycloak token issuance)")
 	}
 
+	// Initialize tenant SSO; staff of tenants enforcing SSO are routed to their IdP on login
+	tenantSSOSvc := services.NewTenantSSOService(db, keycloakClient, cfg.SSO)
+	tenantAuthSvc.SetSSOService(tenantSSOSvc)
+
+	// Initialize tenant-defined roles; assigning one copies its permissions onto the membership
+	tenantRoleSvc := services.NewTenantRoleService(db, membershipRepo)
+	membershipSvc.SetRoleResolver(tenantRoleSvc)
+
+	// Initialize email domain auto-join; domains are verified by TXT record (custom-domain-service) or emailed code
+	joinDomainSvc := services.NewJoinDomainService(db, clients.NewCustomDomainClient(cfg.Integration.CustomDomainServiceURL), notificationClient, cfg.JoinDomain)
+
 	// Initialize staff client for staff tenant lookup during login
 	staffServiceURL := os.Getenv("STAFF_SERVICE_URL")
 	if staffServiceURL == "" {
@@ -230,6 +341,13 @@ func main() {
 		log.Println("NATS client wired to TenantAuthService for customer registration events")
 	}
 
+	// Track login sessions in Redis (when available) backed by PostgreSQL
+	tenantAuthSvc.SetSessionRegistry(services.NewSessionRegistry(db, redisClient))
+	tenantAuthSvc.SetLoginAttemptGuard(services.NewLoginAttemptGuard(redisClient))
+
+	// Passkeys (WebAuthn) are bound to the base domain so one registration covers every admin subdomain
+	tenantAuthSvc.SetPasskeyConfig(cfg.Passkey, cfg.URL.BaseDomain)
+
 	// Initialize customer deactivation service for self-service account deactivation
 	var customerDeactivationSvc *services.CustomerDeactivationService
 	if keycloakClient != nil {
@@ -249,22 +367,110 @@ func main() {
 		log.Println("CustomerDeactivationService initialized (without Keycloak password verification)")
 	}
 
+	// Wire NATS client to deactivation service for publishing customer.purged events
+	if nc != nil {
+		customerDeactivationSvc.SetEventPublisher(nc)
+	}
+
+	// Initialize password policy enforcement (complexity, rotation, breached-password checks)
+	passwordPolicySvc := services.NewPasswordPolicyService(db, services.NewHIBPChecker(
+		cfg.Password.BreachCheckURL,
+		time.Duration(cfg.Password.BreachCheckTimeoutSeconds)*time.Second,
+	))
+	tenantAuthSvc.SetPasswordPolicyService(passwordPolicySvc)
+
 	// Initialize password reset service for self-service password recovery
 	var passwordResetSvc *services.PasswordResetService
 	if keycloakClient != nil {
 		passwordResetSvc = services.NewPasswordResetService(db, keycloakClient, notificationClient)
+		passwordResetSvc.SetPasswordPolicyService(passwordPolicySvc)
 		log.Println("PasswordResetService initialized for self-service password recovery")
 	} else {
 		log.Println("Warning: PasswordResetService not initialized (Keycloak client not available)")
 	}
 
+	// Initialize per-tenant credential encryption (data keys wrapped by the KMS master key)
+	var tenantKeySvc *services.TenantKeyService
+	keyWrapper, err := services.NewLocalKeyWrapper(cfg.Encryption.MasterKeyID, cfg.Encryption.MasterKey)
+	if err != nil {
+		log.Printf("Warning: Tenant encryption keys disabled: %v", err)
+	} else {
+		tenantKeySvc = services.NewTenantKeyService(db, keyWrapper, cfg.Encryption)
+		tenantAuthSvc.SetKeyService(tenantKeySvc)
+		log.Printf("TenantKeyService initialized (master key: %s)", keyWrapper.KeyID())
+	}
+
+	// Initialize staff membership reconciliation (staff-service records vs memberships)
+	staffSyncSvc := services.NewStaffMembershipSyncService(db, staffClient, cfg.StaffSync)
+	log.Printf("StaffMembershipSyncService initialized (create missing: %v)", cfg.StaffSync.CreateMissing)
+
+	// Initialize webhook delivery (attempt history, dead letters, replay)
+	webhookDeliverySvc := services.NewWebhookDeliveryService(db, cfg.Webhook)
+
+	// Security events (failed logins, lockouts, password resets, role escalations) for audit alerting
+	var securityEventPublisher services.SecurityEventPublisher
+	if nc != nil {
+		securityEventPublisher = nc
+		tenantAuthSvc.SetSecurityEventPublisher(nc)
+		membershipSvc.SetSecurityEventPublisher(nc)
+		tenantRoleSvc.SetSecurityEventPublisher(nc)
+		if passwordResetSvc != nil {
+			passwordResetSvc.SetSecurityEventPublisher(nc)
+		}
+		log.Println("Security events enabled on the SECURITY_EVENTS stream")
+	}
+	securityEventSvc := services.NewSecurityEventService(securityEventPublisher)
+
+	// Initialize read-only maintenance flags (cached in Redis when available)
+	maintenanceSvc := services.NewMaintenanceService(db, redisClient)
+
+	// Initialize tenant data exports (data portability archives, emailed download links)
+	tenantExportSvc := services.NewTenantExportService(db, notificationClient, cfg.Export)
+
+	// Initialize onboarding funnel analytics (summary table refreshed by a background job)
+	onboardingAnalyticsSvc := services.NewOnboardingAnalyticsService(db, cfg.Funnel)
+
+	// Initialize onboarding session cleanup (support listing, bulk actions and stale session purge)
+	sessionAdminSvc := services.NewOnboardingSessionAdminService(db, redisClient, cfg.SessionPurge)
+
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandlerWithNATS(db, nc)
+	if redisClient != nil {
+		healthHandler.SetRedis(redisClient)
+	}
 	onboardingHandler := handlers.NewOnboardingHandler(onboardingSvc, templateSvc)
 	templateHandler := handlers.NewTemplateHandler(templateSvc)
 	verificationHandler := handlers.NewVerificationHandler(verificationSvc, onboardingSvc)
 	membershipHandler := handlers.NewMembershipHandlerWithStaff(membershipSvc, staffClient, tenantSvc)
 	tenantHandler := handlers.NewTenantHandler(tenantSvc, offboardingSvc)
+	tenantHandler.SetDeletionSagaService(deletionSagaSvc)
+	encryptionKeyHandler := handlers.NewEncryptionKeyHandler(tenantKeySvc)
+	staffSyncHandler := handlers.NewStaffSyncHandler(staffSyncSvc)
+	webhookHandler := handlers.NewWebhookHandler(webhookDeliverySvc)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceSvc)
+	securityEventHandler := handlers.NewSecurityEventHandler(securityEventSvc)
+	tenantExportHandler := handlers.NewTenantExportHandler(tenantExportSvc)
+	onboardingAnalyticsHandler := handlers.NewOnboardingAnalyticsHandler(onboardingAnalyticsSvc)
+	sessionAdminHandler := handlers.NewOnboardingSessionAdminHandler(sessionAdminSvc)
+	apiKeySvc := services.NewTenantAPIKeyService(db)
+	apiKeySvc.SetUsageService(usageSvc)
+	apiKeyHandler := handlers.NewTenantAPIKeyHandler(apiKeySvc)
+	usageHandler := handlers.NewTenantUsageHandler(usageSvc)
+	subscriptionHandler := handlers.NewTenantSubscriptionHandler(subscriptionSvc)
+	billingHandler := handlers.NewBillingHandler(billingSvc)
+	slugHandler := handlers.NewTenantSlugHandler(slugChangeSvc)
+	contactHandler := handlers.NewTenantContactHandler(contactChangeSvc)
+	seedProfileHandler := handlers.NewSeedProfileHandler(seedProfileSvc)
+	passwordPolicyHandler := handlers.NewPasswordPolicyHandler(passwordPolicySvc)
+	lockoutPolicyHandler := handlers.NewLockoutPolicyHandler(services.NewLockoutPolicyService(db))
+	sessionPolicyHandler := handlers.NewSessionPolicyHandler(services.NewSessionPolicyService(db))
+	ssoHandler := handlers.NewTenantSSOHandler(tenantSSOSvc)
+	roleHandler := handlers.NewTenantRoleHandler(tenantRoleSvc)
+	joinDomainHandler := handlers.NewJoinDomainHandler(joinDomainSvc)
+	mfaHandler := handlers.NewMFAHandler(tenantAuthSvc)
+	sessionHandler := handlers.NewSessionHandler(tenantAuthSvc)
+	emailChangeHandler := handlers.NewEmailChangeHandler(tenantAuthSvc)
+	passkeyHandler := handlers.NewPasskeyHandler(tenantAuthSvc)
 	authHandler := handlers.NewAuthHandler(tenantAuthSvc, staffClient)
 	authHandler.SetDeactivationService(customerDeactivationSvc)
 	log.Println("CustomerDeactivationService wired to AuthHandler for account deactivation endpoints")
@@ -300,6 +506,38 @@ func main() {
 		// Wire reconciliation service for stuck tenant recovery
 		bgRunner.SetReconciliationService(reconciliationSvc)
 		log.Println("TenantReconciliationService wired to background runner for stuck tenant recovery")
+		// Wire deletion saga for re-requesting unacknowledged tenant purges
+		bgRunner.SetDeletionSagaService(deletionSagaSvc)
+		// Wire key service for resuming credential re-encryption after rotation
+		if tenantKeySvc != nil {
+			bgRunner.SetKeyService(tenantKeySvc)
+		}
+		// Wire staff sync for creating memberships missing for staff-service records
+		if cfg.StaffSync.Enabled {
+			bgRunner.SetStaffSyncService(staffSyncSvc)
+		}
+		// Wire webhook delivery for sending and retrying webhook events
+		if cfg.Webhook.DeliveryEnabled {
+			bgRunner.SetWebhookDeliveryService(webhookDeliverySvc)
+		}
+		// Wire tenant exports for archive retention and resuming interrupted exports
+		bgRunner.SetExportService(tenantExportSvc)
+		// Wire offboarding for purging tenants once their deletion grace period elapses
+		bgRunner.SetOffboardingService(offboardingSvc)
+		// Wire membership service for purging expired and revoked invitations
+		bgRunner.SetMembershipService(membershipSvc)
+		// Wire onboarding analytics for refreshing the funnel summary
+		bgRunner.SetOnboardingAnalyticsService(onboardingAnalyticsSvc)
+		// Wire usage metering for member quota checks and pruning metered event keys
+		bgRunner.SetUsageService(usageSvc)
+		// Wire subscriptions for moving tenants whose trial ended to the default plan
+		bgRunner.SetSubscriptionService(subscriptionSvc)
+		// Wire billing for suspending tenants whose payment stayed failed past the grace period
+		bgRunner.SetBillingService(billingSvc)
+		// Wire slug renames for releasing former slugs whose redirect expired
+		bgRunner.SetSlugChangeService(slugChangeSvc)
+		// Wire onboarding session cleanup for purging sessions stuck before completion
+		bgRunner.SetSessionAdminService(sessionAdminSvc)
 		bgRunner.Start()
 	}
 
@@ -314,10 +552,36 @@ func main() {
 	router := setupRouter(
 		healthHandler,
 		onboardingHandler,
+		onboardingAnalyticsHandler,
+		sessionAdminHandler,
 		templateHandler,
 		verificationHandler,
 		membershipHandler,
 		tenantHandler,
+		encryptionKeyHandler,
+		tenantExportHandler,
+		apiKeyHandler,
+		usageHandler,
+		subscriptionHandler,
+		billingHandler,
+		slugHandler,
+		contactHandler,
+		passwordPolicyHandler,
+		lockoutPolicyHandler,
+		sessionPolicyHandler,
+		ssoHandler,
+		roleHandler,
+		joinDomainHandler,
+		mfaHandler,
+		sessionHandler,
+		emailChangeHandler,
+		passkeyHandler,
+		webhookHandler,
+		staffSyncHandler,
+		maintenanceHandler,
+		maintenanceSvc,
+		securityEventHandler,
+		seedProfileHandler,
 		authHandler,
 		draftHandler,
 		testHandler,
@@ -371,10 +635,36 @@ func main() {
 func setupRouter(
 	healthHandler *handlers.HealthHandler,
 	onboardingHandler *handlers.OnboardingHandler,
+	onboardingAnalyticsHandler *handlers.OnboardingAnalyticsHandler,
+	sessionAdminHandler *handlers.OnboardingSessionAdminHandler,
 	templateHandler *handlers.TemplateHandler,
 	verificationHandler *handlers.VerificationHandler,
 	membershipHandler *handlers.MembershipHandler,
 	tenantHandler *handlers.TenantHandler,
+	encryptionKeyHandler *handlers.EncryptionKeyHandler,
+	tenantExportHandler *handlers.TenantExportHandler,
+	apiKeyHandler *handlers.TenantAPIKeyHandler,
+	usageHandler *handlers.TenantUsageHandler,
+	subscriptionHandler *handlers.TenantSubscriptionHandler,
+	billingHandler *handlers.BillingHandler,
+	slugHandler *handlers.TenantSlugHandler,
+	contactHandler *handlers.TenantContactHandler,
+	passwordPolicyHandler *handlers.PasswordPolicyHandler,
+	lockoutPolicyHandler *handlers.LockoutPolicyHandler,
+	sessionPolicyHandler *handlers.SessionPolicyHandler,
+	ssoHandler *handlers.TenantSSOHandler,
+	roleHandler *handlers.TenantRoleHandler,
+	joinDomainHandler *handlers.JoinDomainHandler,
+	mfaHandler *handlers.MFAHandler,
+	sessionHandler *handlers.SessionHandler,
+	emailChangeHandler *handlers.EmailChangeHandler,
+	passkeyHandler *handlers.PasskeyHandler,
+	webhookHandler *handlers.WebhookHandler,
+	staffSyncHandler *handlers.StaffSyncHandler,
+	maintenanceHandler *handlers.MaintenanceHandler,
+	maintenanceChecker middleware.MaintenanceChecker,
+	securityEventHandler *handlers.SecurityEventHandler,
+	seedProfileHandler *handlers.SeedProfileHandler,
 	authHandler *handlers.AuthHandler,
 	draftHandler *handlers.DraftHandler,
 	testHandler *handlers.TestHandler,
@@ -399,26 +689,46 @@ func setupRouter(
 		"https://onboarding.tesserix.app",     // Onboarding app (prod)
 	}
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "X-Tenant-ID", "X-User-ID"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "X-Tenant-ID", "X-User-ID", "X-Session-ID"}
 	config.AllowCredentials = true
 
 	// Global middleware
 	router.Use(cors.New(config))              // CORS
 	router.Use(gin.Recovery())                // Panic recovery
 	router.Use(middleware.RequestID())        // Correlation IDs
+	router.Use(middleware.TraceContext())     // Trace IDs for metric exemplars
 	router.Use(middleware.StructuredLogger()) // Structured logging
 	router.Use(metricsCollector.Middleware()) // Prometheus metrics
 	router.Use(middleware.TenantExtraction()) // Tenant context
 
-	// Metrics endpoint (Prometheus scraping)
-	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	// Metrics endpoint (Prometheus scraping). OpenMetrics is offered so exemplars
+	// (trace IDs on business metrics) are exposed to scrapers that request it.
+	router.GET("/metrics", gin.WrapH(promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	)))
 
 	// Health endpoints
 	router.GET("/health", healthHandler.Health)
 	router.GET("/ready", healthHandler.Ready)
 
+	// API documentation: Swagger UI and the raw spec for client codegen (generated by `make swagger`)
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(docs.SwaggerInfo.ReadDoc()))
+	})
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
+	// Read-only maintenance mode: writes return 503 while the platform or tenant is flagged.
+	// Login and lookup endpoints that only read despite using POST stay available.
+	v1.Use(middleware.ReadOnlyMode(maintenanceChecker,
+		"/api/v1/auth/validate",
+		"/api/v1/auth/tenants",
+		"/api/v1/auth/account-status",
+		"/api/v1/auth/check-deactivated",
+		"/api/v1/verify/token-info",
+	))
 	{
 		// Onboarding templates
 		templates := v1.Group("/onboarding/templates")
@@ -433,6 +743,15 @@ func setupRouter(
 			templates.GET("/default/:applicationType", templateHandler.GetDefaultTemplate)
 			templates.GET("/active", templateHandler.GetActiveTemplates)
 			templates.POST("/validate-config", templateHandler.ValidateTemplateConfiguration)
+			templates.GET("/:templateId/custom-fields/query", onboardingHandler.QueryCustomFields)
+
+			// Template versioning: edit a draft, publish it as a new version, compare versions
+			templates.GET("/:templateId/versions", templateHandler.ListTemplateVersions)
+			templates.GET("/:templateId/versions/:version", templateHandler.GetTemplateVersion)
+			templates.PUT("/:templateId/draft", templateHandler.SaveTemplateDraft)
+			templates.DELETE("/:templateId/draft", templateHandler.DiscardTemplateDraft)
+			templates.POST("/:templateId/publish", templateHandler.PublishTemplate)
+			templates.GET("/:templateId/diff", templateHandler.DiffTemplateVersions)
 		}
 
 		// SSE handler for real-time session events
@@ -453,6 +772,7 @@ func setupRouter(
 			// Business information
 			sessions.POST("/:sessionId/business-information", onboardingHandler.UpdateBusinessInformation)
 			sessions.PUT("/:sessionId/business-information", onboardingHandler.UpdateBusinessInformation)
+			sessions.GET("/:sessionId/custom-fields", onboardingHandler.GetSessionCustomFields)
 
 			// Contact information
 			sessions.POST("/:sessionId/contact-information", onboardingHandler.UpdateContactInformation)
@@ -497,6 +817,12 @@ func setupRouter(
 			verify.POST("/resend-by-email", verificationHandler.ResendVerificationByEmail)
 		}
 
+		// Subscription plans (public pricing)
+		v1.GET("/plans", subscriptionHandler.ListPlans)
+
+		// Billing provider webhooks (no auth - verified by HMAC signature)
+		v1.POST("/webhooks/billing", billingHandler.HandleWebhook)
+
 		// Public invitation acceptance (no auth)
 		publicInvitations := v1.Group("/invitations")
 		{
@@ -542,13 +868,119 @@ func setupRouter(
 			tenants.GET("/:id/growthbook/sdk-key", tenantHandler.GetTenantGrowthBookSDKKey)
 
 			// Member management (uses tenant ID)
+			tenants.GET("/:id/members", membershipHandler.ListMembers)
 			tenants.POST("/:id/members/invite", membershipHandler.InviteMember)
+			tenants.POST("/:id/members/import", membershipHandler.ImportMembers)
+			tenants.GET("/:id/members/invitations", membershipHandler.ListInvitations)
+			tenants.POST("/:id/members/invitations/:invId/resend", membershipHandler.ResendInvitation)
+			tenants.DELETE("/:id/members/invitations/:invId", membershipHandler.RevokeInvitation)
 			tenants.DELETE("/:id/members/:memberId", membershipHandler.RemoveMember)
 			tenants.PUT("/:id/members/:memberId/role", membershipHandler.UpdateMemberRole)
+			tenants.GET("/:id/activity", membershipHandler.ListActivity)
 
 			// Tenant deletion (offboarding) - owner only
 			tenants.GET("/:id/deletion", tenantHandler.GetTenantDeletionInfo)
+			tenants.GET("/:id/deletion/status", tenantHandler.GetTenantDeletionStatus)
 			tenants.DELETE("/:id", tenantHandler.DeleteTenant)
+			tenants.POST("/:id/restore", tenantHandler.RestoreTenant)
+
+			// Per-tenant data-encryption keys - owner/admin only
+			tenants.GET("/:id/encryption-keys", encryptionKeyHandler.GetEncryptionKeys)
+			tenants.POST("/:id/encryption-keys/rotate", encryptionKeyHandler.RotateEncryptionKey)
+
+			// Tenant data export (data portability) - owner only
+			tenants.POST("/:id/export", tenantExportHandler.RequestExport)
+			tenants.GET("/:id/export", tenantExportHandler.DownloadExport)
+			tenants.GET("/:id/exports", tenantExportHandler.ListExports)
+			tenants.GET("/:id/exports/:exportId", tenantExportHandler.GetExport)
+			// Tenant API keys for integrations (owner only)
+			tenants.GET("/:id/api-keys", apiKeyHandler.ListAPIKeys)
+			tenants.POST("/:id/api-keys", apiKeyHandler.CreateAPIKey)
+			tenants.POST("/:id/api-keys/:keyId/rotate", apiKeyHandler.RotateAPIKey)
+			tenants.DELETE("/:id/api-keys/:keyId", apiKeyHandler.RevokeAPIKey)
+
+			// Plan usage and quotas (owner or admin)
+			tenants.GET("/:id/usage", usageHandler.GetTenantUsage)
+
+			// Slug rename; the old slug redirects for a while (owner only)
+			tenants.PUT("/:id/slug", slugHandler.ChangeSlug)
+
+			// Business contact email/phone; changes apply once the new value is verified (owner or admin)
+			tenants.GET("/:id/contact", contactHandler.GetContact)
+			tenants.POST("/:id/contact/changes", contactHandler.RequestChange)
+			tenants.POST("/:id/contact/changes/:changeId/verify", contactHandler.VerifyChange)
+			tenants.POST("/:id/contact/changes/:changeId/resend", contactHandler.ResendCode)
+			tenants.DELETE("/:id/contact/changes/:changeId", contactHandler.CancelChange)
+
+			// Plan and feature flags (owners or admins view, owners change)
+			tenants.GET("/:id/subscription", subscriptionHandler.GetSubscription)
+			tenants.PUT("/:id/subscription", subscriptionHandler.ChangePlan)
+
+			// Password policy (complexity, rotation, breached-password checks) - owner/admin only
+			tenants.GET("/:id/password-policy", passwordPolicyHandler.GetPasswordPolicy)
+			tenants.PUT("/:id/password-policy", passwordPolicyHandler.UpdatePasswordPolicy)
+			tenants.DELETE("/:id/password-policy", passwordPolicyHandler.ResetPasswordPolicy)
+
+			// Login lockout thresholds (tiered, per tenant+email+IP) - owner/admin only
+			tenants.GET("/:id/lockout-policy", lockoutPolicyHandler.GetLockoutPolicy)
+			tenants.PUT("/:id/lockout-policy", lockoutPolicyHandler.UpdateLockoutPolicy)
+			tenants.GET("/:id/session-policy", sessionPolicyHandler.GetSessionPolicy)
+			tenants.PUT("/:id/session-policy", sessionPolicyHandler.UpdateSessionPolicy)
+
+			// Passkey policy (off, prefer or require per role) - owner/admin only
+			tenants.GET("/:id/passkey-policy", passkeyHandler.GetPasskeyPolicy)
+			tenants.PUT("/:id/passkey-policy", passkeyHandler.UpdatePasskeyPolicy)
+
+			// Single sign-on (owner/admin)
+			tenants.GET("/:id/sso", ssoHandler.GetSSOConfig)
+			tenants.PUT("/:id/sso", ssoHandler.SaveSSOConfig)
+			tenants.DELETE("/:id/sso", ssoHandler.DeleteSSOConfig)
+			tenants.POST("/:id/sso/metadata/validate", ssoHandler.ValidateSAMLMetadata)
+			tenants.POST("/:id/sso/test", ssoHandler.TestSSOConnection)
+
+			// Custom roles and their permission sets (owner/admin)
+			tenants.GET("/:id/roles", roleHandler.ListRoles)
+			tenants.POST("/:id/roles", roleHandler.CreateRole)
+			tenants.GET("/:id/roles/:roleId", roleHandler.GetRole)
+			tenants.PUT("/:id/roles/:roleId", roleHandler.UpdateRole)
+			tenants.DELETE("/:id/roles/:roleId", roleHandler.DeleteRole)
+
+			// Email domain auto-join and its approval queue (owner/admin)
+			tenants.GET("/:id/join-domains", joinDomainHandler.ListJoinDomains)
+			tenants.POST("/:id/join-domains", joinDomainHandler.AddJoinDomain)
+			tenants.POST("/:id/join-domains/:domainId/verify", joinDomainHandler.VerifyJoinDomain)
+			tenants.POST("/:id/join-domains/:domainId/resend-code", joinDomainHandler.ResendJoinDomainCode)
+			tenants.PATCH("/:id/join-domains/:domainId", joinDomainHandler.UpdateJoinDomain)
+			tenants.DELETE("/:id/join-domains/:domainId", joinDomainHandler.DeleteJoinDomain)
+			tenants.GET("/:id/join-requests", joinDomainHandler.ListJoinRequests)
+			tenants.POST("/:id/join-requests/:requestId/approve", joinDomainHandler.ApproveJoinRequest)
+			tenants.POST("/:id/join-requests/:requestId/reject", joinDomainHandler.RejectJoinRequest)
+
+			// Webhook delivery history, dead letters and replay - owner/admin only
+			tenants.GET("/:id/webhooks/events/:eventId/attempts", webhookHandler.ListEventAttempts)
+			tenants.POST("/:id/webhooks/events/:eventId/replay", webhookHandler.ReplayEvent)
+			tenants.GET("/:id/webhooks/dead-letters", webhookHandler.ListDeadLetters)
+			tenants.POST("/:id/webhooks/dead-letters/replay", webhookHandler.ReplayDeadLetters)
+			tenants.GET("/:id/webhooks/stats", webhookHandler.GetEndpointStats)
+		}
+
+		// Tenant export download via the emailed link (authorized by the link token)
+		v1.GET("/tenant-exports/:exportId/download", tenantExportHandler.DownloadExportByToken)
+
+		// Onboarding funnel analytics (requires auth, platform owners only)
+		onboardingAnalytics := v1.Group("/onboarding/analytics")
+		onboardingAnalytics.Use(istioAuth)
+		{
+			onboardingAnalytics.GET("/funnel", onboardingAnalyticsHandler.GetFunnel)
+		}
+
+		// Onboarding session support tooling (requires auth, platform owners only)
+		adminSessions := v1.Group("/admin/onboarding/sessions")
+		adminSessions.Use(istioAuth)
+		{
+			adminSessions.GET("", sessionAdminHandler.ListSessions)
+			adminSessions.POST("/abandon", sessionAdminHandler.AbandonSessions)
+			adminSessions.POST("/delete", sessionAdminHandler.DeleteSessions)
 		}
 
 		// Invitation endpoints (requires auth)
@@ -574,6 +1006,13 @@ func setupRouter(
 			authRoutes.POST("/request-password-reset", authHandler.RequestPasswordReset) // Request password reset email
 			authRoutes.POST("/validate-reset-token", authHandler.ValidateResetToken)     // Validate reset token
 			authRoutes.POST("/reset-password", authHandler.ResetPassword)                // Reset password with token
+
+			// Email change confirmation (public - authorized by the token emailed to the new address)
+			authRoutes.POST("/email-change/confirm", emailChangeHandler.ConfirmEmailChange)
+
+			// Passkey login (public - the signed assertion authenticates the user)
+			authRoutes.POST("/passkeys/login/begin", passkeyHandler.BeginPasskeyLogin)
+			authRoutes.POST("/passkeys/login/finish", passkeyHandler.FinishPasskeyLogin)
 		}
 
 		// Protected auth endpoints (require Istio JWT auth)
@@ -585,6 +1024,32 @@ func setupRouter(
 			protectedAuth.POST("/set-password", authHandler.SetPassword)              // Set password (after verification)
 			protectedAuth.POST("/unlock-account", authHandler.UnlockAccount)          // Admin: unlock locked account
 			protectedAuth.POST("/deactivate-account", authHandler.DeactivateAccount)  // Customer self-service deactivation
+
+			// TOTP multi-factor authentication for the current user
+			protectedAuth.GET("/mfa", mfaHandler.GetMFAStatus)
+			protectedAuth.POST("/mfa/enroll", mfaHandler.EnrollTOTP)
+			protectedAuth.POST("/mfa/verify", mfaHandler.VerifyMFA)
+			protectedAuth.POST("/mfa/disable", mfaHandler.DisableMFA)
+			protectedAuth.POST("/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+
+			// Passkeys (WebAuthn) of the current user
+			protectedAuth.GET("/passkeys", passkeyHandler.ListPasskeys)
+			protectedAuth.POST("/passkeys/register/begin", passkeyHandler.BeginPasskeyRegistration)
+			protectedAuth.POST("/passkeys/register/finish", passkeyHandler.FinishPasskeyRegistration)
+			protectedAuth.DELETE("/passkeys/:passkeyId", passkeyHandler.DeletePasskey)
+
+			// Active sessions per tenant
+			protectedAuth.GET("/sessions", sessionHandler.ListSessions)
+			protectedAuth.DELETE("/sessions/:sessionId", sessionHandler.RevokeSession)
+
+			// Login email change (re-authenticates, then verifies the new address)
+			protectedAuth.POST("/email-change", emailChangeHandler.RequestEmailChange)
+
+			// Membership provisioning after a login brokered through a tenant IdP (called by auth-bff)
+			protectedAuth.POST("/sso/provision", ssoHandler.ProvisionSSOMember)
+
+			// Membership through a verified email domain on login (called by auth-bff)
+			protectedAuth.POST("/domain-join", joinDomainHandler.JoinByEmailDomain)
 		}
 
 		// Internal service-to-service endpoints (requires X-Internal-Service header)
@@ -592,8 +1057,36 @@ func setupRouter(
 		{
 			internal.GET("/tenants/:id", tenantHandler.GetTenantInfo)
 			internal.GET("/tenants/by-slug/:slug", tenantHandler.GetTenantBySlug)
+			internal.POST("/tenants/bulk", tenantHandler.BulkGetTenants)
 			// Sync existing customers to customer.registered events (one-time migration)
 			internal.POST("/sync-customers", authHandler.SyncCustomersToEvents)
+			// Staff-service membership reconciliation (on demand; also runs on a schedule)
+			internal.POST("/staff-sync/reconcile", staffSyncHandler.Reconcile)
+			internal.GET("/staff-sync/conflicts", staffSyncHandler.ListConflicts)
+			// Read-only maintenance mode (platform-wide or per tenant)
+			internal.GET("/maintenance", maintenanceHandler.ListMaintenance)
+			internal.PUT("/maintenance/platform", maintenanceHandler.EnablePlatformMaintenance)
+			internal.DELETE("/maintenance/platform", maintenanceHandler.DisablePlatformMaintenance)
+			internal.GET("/maintenance/tenants/:id", maintenanceHandler.GetTenantMaintenance)
+			internal.PUT("/maintenance/tenants/:id", maintenanceHandler.EnableTenantMaintenance)
+			internal.DELETE("/maintenance/tenants/:id", maintenanceHandler.DisableTenantMaintenance)
+			// Synthetic security events for testing audit alerting
+			internal.POST("/security-events/synthetic", securityEventHandler.InjectSyntheticEvent)
+			// Tenant API key validation for storefront integrations calling other services
+			internal.POST("/api-keys/validate", apiKeyHandler.ValidateAPIKey)
+			// Session revocation check for services that hold a session_id
+			internal.GET("/auth/sessions/:sessionId", sessionHandler.GetSessionStatus)
+			// Plan assignment (including trials) and feature flag evaluation for other services
+			internal.PUT("/tenants/:id/subscription", subscriptionHandler.AssignPlan)
+			internal.GET("/tenants/:id/features", subscriptionHandler.GetFeatures)
+			internal.PUT("/tenants/:id/features", subscriptionHandler.UpdateFeatureOverrides)
+			// Member permission checks for staff-service and settings-service
+			internal.POST("/tenants/:id/permissions/check", roleHandler.CheckPermission)
+			// Seed profiles of default templates and reserved slugs (dry-run diff, apply on demand)
+			internal.GET("/seeds/diff", seedProfileHandler.DiffProfile)
+			internal.POST("/seeds/apply", seedProfileHandler.ApplyProfile)
+			// Purge of onboarding sessions stuck before completion (on demand; also runs on a schedule)
+			internal.POST("/onboarding/sessions/purge", sessionAdminHandler.PurgeStaleSessions)
 		}
 
 		// Draft persistence endpoints (optional - only if draftHandler is available)
@@ -646,12 +1139,16 @@ func autoMigrate(db *gorm.DB) error {
 	modelsToMigrate := []interface{}{
 		&models.Tenant{},
 		&models.User{},
-		&models.UserTenantMembership{},  // Multi-tenant membership support
-		&models.TenantActivityLog{},     // Audit trail for tenant activities
-		&models.ReservedSlug{},          // Reserved slugs for validation (cached in memory)
-		&models.TenantSlugReservation{}, // Tracks claimed slugs during onboarding
-		&models.DeletedTenant{},         // Audit table for deleted tenants
+		&models.UserTenantMembership{},   // Multi-tenant membership support
+		&models.TenantActivityLog{},      // Audit trail for tenant activities
+		&models.ReservedSlug{},           // Reserved slugs for validation (cached in memory)
+		&models.TenantSlugReservation{},  // Tracks claimed slugs during onboarding
+		&models.DeletedTenant{},          // Audit table for deleted tenants
+		&models.TenantDeletionStatus{},   // Per-service purge acknowledgments for deleted tenants
+		&models.TenantEncryptionKey{},    // Wrapped per-tenant data-encryption keys
+		&models.MembershipSyncConflict{}, // Staff-service vs membership conflicts awaiting resolution
 		&models.OnboardingTemplate{},
+		&models.OnboardingTemplateVersion{}, // Draft, published and archived template versions
 		&models.OnboardingSession{},
 		&models.BusinessInformation{},
 		&models.ContactInformation{},
@@ -663,15 +1160,38 @@ func autoMigrate(db *gorm.DB) error {
 		&models.TaskExecutionLog{},
 		&models.DomainReservation{},
 		&models.OnboardingNotification{},
+		&models.OnboardingFunnelDaily{}, // Daily onboarding funnel summary for analytics
 		&models.WebhookEvent{},
+		&models.WebhookDeliveryAttempt{}, // Per-attempt webhook delivery history
+		&models.MaintenanceFlag{},        // Platform/tenant read-only maintenance flags
+		&models.TenantExport{},           // Tenant data export archives
+		&models.TenantAPIKey{},           // Tenant-scoped API keys
+		&models.TenantUsageCounter{},     // Metered usage against plan quotas
+		&models.TenantUsageEvent{},       // Metered events, for de-duplicating redeliveries
+		&models.TenantQuotaNotice{},      // Published quota breaches
+		&models.TenantPlan{},             // Subscription plans and their feature flags
+		&models.TenantSubscription{},     // Each tenant's plan, trial and feature overrides
+		&models.BillingEvent{},           // Processed billing provider webhooks
+		&models.TenantSlugRedirect{},     // Former slugs of renamed tenants
+		&models.TenantContactChange{},    // Business contact changes awaiting verification
+		&models.SeedRecord{},             // Checksums of applied seed profile entries
 		// Multi-tenant credential isolation models
 		&models.TenantCredential{},   // Per-tenant passwords for enterprise credential isolation
 		&models.TenantAuthPolicy{},   // Per-tenant authentication policies
 		&models.TenantAuthAuditLog{}, // Authentication audit trail per tenant
+		&models.TenantAuthSession{},  // Active login sessions per tenant user
+		&models.TenantSSOConfig{},    // Enterprise SSO identity providers per tenant
+		&models.TenantJoinDomain{},   // Email domains whose users may join a tenant
+		&models.TenantJoinRequest{},  // Approval queue for email domain joins
+		&models.TenantRole{},         // Tenant-defined roles and their permission sets
 		// Customer account deactivation
 		&models.DeactivatedMembership{}, // Archive of deactivated customer accounts
 		// Password reset tokens
 		&models.PasswordResetToken{}, // Secure tokens for password reset flow
+		&models.EmailChangeToken{},   // Pending login email changes awaiting confirmation
+		// Passkeys
+		&models.WebAuthnCredential{}, // Passkeys registered per user per tenant
+		&models.WebAuthnChallenge{},  // Single-use registration and login challenges
 	}
 
 	for _, model := range modelsToMigrate {
@@ -682,14 +1202,9 @@ func autoMigrate(db *gorm.DB) error {
 
 	log.Println("Database migration completed successfully")
 
-	// Seed default templates if they don't exist
-	if err := seedDefaultTemplates(db); err != nil {
-		log.Printf("Warning: Failed to seed default templates: %v", err)
-	}
-
-	// Seed reserved slugs for URL protection
-	if err := seedReservedSlugs(db); err != nil {
-		log.Printf("Warning: Failed to seed reserved slugs: %v", err)
+	// Seed a plan per pricing tier
+	if err := seedTenantPlans(db); err != nil {
+		log.Printf("Warning: Failed to seed tenant plans: %v", err)
 	}
 
 	// Populate URLs for existing tenants that don't have them set
@@ -700,193 +1215,6 @@ func autoMigrate(db *gorm.DB) error {
 	return nil
 }
 
-// seedDefaultTemplates creates default onboarding templates if they don't exist
-func seedDefaultTemplates(db *gorm.DB) error {
-	// Check if ACTIVE ecommerce template already exists
-	// IMPORTANT: Must check is_active = true to match GetDefaultTemplate query
-	var activeCount int64
-	if err := db.Model(&models.OnboardingTemplate{}).Where("application_type = ? AND is_default = ? AND is_active = ?", "ecommerce", true, true).Count(&activeCount).Error; err != nil {
-		return fmt.Errorf("failed to check existing templates: %w", err)
-	}
-
-	if activeCount > 0 {
-		log.Println("Default active templates already exist, skipping seed")
-		return nil
-	}
-
-	// Check if inactive template exists and reactivate it
-	var inactiveCount int64
-	if err := db.Model(&models.OnboardingTemplate{}).Where("application_type = ? AND is_default = ? AND is_active = ?", "ecommerce", true, false).Count(&inactiveCount).Error; err != nil {
-		log.Printf("Warning: Failed to check inactive templates: %v", err)
-	}
-
-	if inactiveCount > 0 {
-		log.Println("Found inactive default template, reactivating...")
-		if err := db.Model(&models.OnboardingTemplate{}).
-			Where("application_type = ? AND is_default = ?", "ecommerce", true).
-			Update("is_active", true).Error; err != nil {
-			return fmt.Errorf("failed to reactivate template: %w", err)
-		}
-		log.Println("Default ecommerce template reactivated successfully")
-		return nil
-	}
-
-	log.Println("Seeding default onboarding templates...")
-
-	// E-commerce template steps
-	ecommerceSteps := []map[string]interface{}{
-		{
-			"id":          "business-registration",
-			"name":        "Business Registration",
-			"description": "Register your business details",
-			"order":       1,
-			"required":    true,
-			"type":        "form",
-		},
-		{
-			"id":          "contact-details",
-			"name":        "Contact Details",
-			"description": "Provide contact information",
-			"order":       2,
-			"required":    true,
-			"type":        "form",
-		},
-		{
-			"id":          "business-address",
-			"name":        "Business Address",
-			"description": "Enter your business address",
-			"order":       3,
-			"required":    true,
-			"type":        "form",
-		},
-		{
-			"id":          "store-setup",
-			"name":        "Store Setup",
-			"description": "Configure your store settings",
-			"order":       4,
-			"required":    true,
-			"type":        "form",
-		},
-	}
-
-	stepsJSON, _ := models.NewJSONB(ecommerceSteps)
-	configJSON, _ := models.NewJSONB(map[string]interface{}{
-		"requires_payment":  true,
-		"requires_domain":   true,
-		"trial_period_days": 14,
-	})
-	metadataJSON, _ := models.NewJSONB(map[string]interface{}{})
-
-	ecommerceTemplate := &models.OnboardingTemplate{
-		Name:            "E-commerce Store Setup",
-		Description:     "Complete setup flow for online stores and e-commerce platforms",
-		ApplicationType: "ecommerce",
-		Version:         1,
-		IsActive:        true,
-		IsDefault:       true,
-		TemplateConfig:  configJSON,
-		Steps:           stepsJSON,
-		Metadata:        metadataJSON,
-	}
-
-	if err := db.Create(ecommerceTemplate).Error; err != nil {
-		return fmt.Errorf("failed to create ecommerce template: %w", err)
-	}
-
-	log.Printf("Created default ecommerce template with ID: %s", ecommerceTemplate.ID)
-	return nil
-}
-
-// seedReservedSlugs creates default reserved slugs for URL protection
-func seedReservedSlugs(db *gorm.DB) error {
-	// Check if reserved slugs already exist
-	var count int64
-	if err := db.Model(&models.ReservedSlug{}).Where("is_active = ?", true).Count(&count).Error; err != nil {
-		return fmt.Errorf("failed to check existing reserved slugs: %w", err)
-	}
-
-	if count > 0 {
-		log.Printf("Reserved slugs already exist (%d active), skipping seed", count)
-		return nil
-	}
-
-	log.Println("Seeding reserved slugs for URL protection...")
-
-	// Define reserved slugs by category
-	reservedSlugs := []models.ReservedSlug{
-		// System slugs - critical paths
-		{Slug: "admin", Reason: "System administration", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "api", Reason: "API endpoints", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "app", Reason: "Application path", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "auth", Reason: "Authentication", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "login", Reason: "Login page", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "logout", Reason: "Logout page", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "register", Reason: "Registration page", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "signup", Reason: "Signup page", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "signin", Reason: "Signin page", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "dashboard", Reason: "Dashboard path", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "settings", Reason: "Settings path", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "account", Reason: "Account path", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "profile", Reason: "Profile path", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "help", Reason: "Help page", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "support", Reason: "Support page", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "status", Reason: "Status page", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "health", Reason: "Health check", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "metrics", Reason: "Metrics endpoint", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "docs", Reason: "Documentation", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "api-docs", Reason: "API documentation", Category: "system", IsActive: true, CreatedBy: "system"},
-		// Infrastructure slugs
-		{Slug: "www", Reason: "Web subdomain", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		{Slug: "cdn", Reason: "CDN subdomain", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		{Slug: "mail", Reason: "Mail subdomain", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		{Slug: "smtp", Reason: "SMTP subdomain", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		{Slug: "ftp", Reason: "FTP subdomain", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		{Slug: "static", Reason: "Static assets", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		{Slug: "assets", Reason: "Assets path", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		{Slug: "images", Reason: "Images path", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		{Slug: "files", Reason: "Files path", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		{Slug: "uploads", Reason: "Uploads path", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		{Slug: "media", Reason: "Media path", Category: "infrastructure", IsActive: true, CreatedBy: "system"},
-		// Brand slugs
-		{Slug: "tesseract", Reason: "Brand protection", Category: "brand", IsActive: true, CreatedBy: "system"},
-		{Slug: "tesserix", Reason: "Brand protection", Category: "brand", IsActive: true, CreatedBy: "system"},
-		{Slug: "tesseract-hub", Reason: "Brand protection", Category: "brand", IsActive: true, CreatedBy: "system"},
-		{Slug: "marketplace", Reason: "Platform name", Category: "brand", IsActive: true, CreatedBy: "system"},
-		// Common reserved terms
-		{Slug: "test", Reason: "Reserved for testing", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "demo", Reason: "Reserved for demos", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "staging", Reason: "Reserved for staging", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "dev", Reason: "Reserved for development", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "prod", Reason: "Reserved for production", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "production", Reason: "Reserved for production", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "internal", Reason: "Internal use", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "private", Reason: "Private use", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "public", Reason: "Public use", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "root", Reason: "Root access", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "system", Reason: "System use", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "null", Reason: "Reserved keyword", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "undefined", Reason: "Reserved keyword", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "true", Reason: "Reserved keyword", Category: "system", IsActive: true, CreatedBy: "system"},
-		{Slug: "false", Reason: "Reserved keyword", Category: "system", IsActive: true, CreatedBy: "system"},
-	}
-
-	// Insert reserved slugs, ignoring duplicates
-	var inserted int
-	for _, slug := range reservedSlugs {
-		result := db.Where("slug = ?", slug.Slug).FirstOrCreate(&slug)
-		if result.Error != nil {
-			log.Printf("Warning: Failed to create reserved slug '%s': %v", slug.Slug, result.Error)
-			continue
-		}
-		if result.RowsAffected > 0 {
-			inserted++
-		}
-	}
-
-	log.Printf("Seeded %d reserved slugs successfully", inserted)
-	return nil
-}
-
 func initDatabase() (*gorm.DB, error) {
 	// Get database configuration from environment
 	host := getEnv("DB_HOST", "localhost")
@@ -935,26 +1263,8 @@ func initMetrics(db *gorm.DB) *metrics.Metrics {
 		Subsystem:   "tenant",
 	})
 
-	// Register business-specific metrics
-
-	// Onboarding session metrics
-	onboardingSessionsTotal := m.RegisterCounter(
-		"tesseract_tenant_onboarding_sessions_total",
-		"Total number of onboarding sessions created",
-		[]string{"application_type", "status"},
-	)
-
-	verificationAttemptsTotal := m.RegisterCounter(
-		"tesseract_tenant_verification_attempts_total",
-		"Total number of verification attempts",
-		[]string{"type", "status"},
-	)
-
-	verificationCodesGenerated := m.RegisterCounter(
-		"tesseract_tenant_verification_codes_generated_total",
-		"Total number of verification codes generated",
-		[]string{"type"},
-	)
+	// Business counters and histograms (onboarding sessions and steps, verification,
+	// Keycloak latency) are registered by internal/metrics where they are recorded
 
 	// Active sessions gauge
 	activeSessions := m.RegisterGauge(
@@ -1037,16 +1347,13 @@ func initMetrics(db *gorm.DB) *metrics.Metrics {
 	// Log registered metrics for debugging
 	log.Printf("Registered business metrics:")
 	log.Printf("  - onboarding_sessions_total")
+	log.Printf("  - onboarding_step_duration_seconds")
 	log.Printf("  - verification_attempts_total")
 	log.Printf("  - verification_codes_generated_total")
+	log.Printf("  - keycloak_request_duration_seconds")
 	log.Printf("  - active_sessions")
 	log.Printf("  - db_connection metrics")
 
-	// Store metrics in closure variables to avoid unused variable warnings
-	_ = onboardingSessionsTotal
-	_ = verificationAttemptsTotal
-	_ = verificationCodesGenerated
-
 	return m
 }
 
@@ -1221,6 +1528,53 @@ func preMigrateUserModel(db *gorm.DB) error {
 	return nil
 }
 
+// seedTenantPlans creates a plan for each pricing tier that has none yet.
+// Existing plans are left alone so prices and features edited in the database survive restarts.
+func seedTenantPlans(db *gorm.DB) error {
+	tiers := models.GetPricingTiers()
+	features := models.DefaultPlanFeatures()
+	order := []string{
+		models.PricingTierFree,
+		models.PricingTierStarter,
+		models.PricingTierProfessional,
+		models.PricingTierEnterprise,
+	}
+
+	created := 0
+	for rank, code := range order {
+		tier, ok := tiers[code]
+		if !ok {
+			continue
+		}
+		featuresJSON, err := models.NewJSONB(features[code])
+		if err != nil {
+			return fmt.Errorf("failed to encode features of plan %s: %w", code, err)
+		}
+		plan := &models.TenantPlan{
+			Code:         code,
+			Name:         tier.DisplayName,
+			Description:  tier.Description,
+			PricingTier:  code,
+			MonthlyPrice: tier.MonthlyPrice,
+			YearlyPrice:  tier.YearlyPrice,
+			Features:     featuresJSON,
+			Rank:         rank,
+			SelfService:  tier.IsEnabled,
+			IsActive:     true,
+		}
+		result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, DoNothing: true}).Create(plan)
+		if result.Error != nil {
+			return fmt.Errorf("failed to seed plan %s: %w", code, result.Error)
+		}
+		created += int(result.RowsAffected)
+	}
+
+	if created > 0 {
+		log.Printf("Seeded %d tenant plans", created)
+	}
+	return nil
+}
+
 // populateTenantURLs sets default URLs for existing tenants that don't have them
 // This ensures all tenants (both custom domain and standard) have admin_url, storefront_url, api_url set
 func populateTenantURLs(db *gorm.DB) error {
@@ -0,0 +1,25 @@
+-- Migration: Add tenant_deletion_statuses table for the cross-service deletion saga
+-- One row per (deleted tenant, participating service) tracking purge acknowledgments
+
+CREATE TABLE IF NOT EXISTS tenant_deletion_statuses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    deleted_tenant_id UUID NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    service_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER DEFAULT 0,
+    items_purged BIGINT DEFAULT 0,
+    last_error TEXT,
+    last_requested_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_deletion_service ON tenant_deletion_statuses(tenant_id, service_name);
+CREATE INDEX IF NOT EXISTS idx_tenant_deletion_statuses_deleted_tenant ON tenant_deletion_statuses(deleted_tenant_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_deletion_statuses_status ON tenant_deletion_statuses(status);
+
+COMMENT ON TABLE tenant_deletion_statuses IS 'Per-service purge acknowledgments for deleted tenants (deletion saga)';
+COMMENT ON COLUMN tenant_deletion_statuses.status IS 'pending, purged, failed (re-requested) or exhausted (manual cleanup required)';
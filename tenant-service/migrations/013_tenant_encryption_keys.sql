@@ -0,0 +1,26 @@
+-- Migration: Add per-tenant data-encryption keys for credential data
+-- Keys are stored wrapped by the KMS master key; credential rows record the key version protecting them
+
+CREATE TABLE IF NOT EXISTS tenant_encryption_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    algorithm VARCHAR(30) NOT NULL DEFAULT 'AES-256-GCM',
+    wrapped_key TEXT NOT NULL,
+    master_key_id VARCHAR(255) NOT NULL,
+    created_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    retired_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_key_version ON tenant_encryption_keys(tenant_id, version);
+CREATE INDEX IF NOT EXISTS idx_tenant_encryption_keys_status ON tenant_encryption_keys(status);
+
+ALTER TABLE tenant_credentials ADD COLUMN IF NOT EXISTS key_version INTEGER DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_tenant_credentials_key_version ON tenant_credentials(key_version);
+
+COMMENT ON TABLE tenant_encryption_keys IS 'Per-tenant data-encryption keys, wrapped by the KMS master key';
+COMMENT ON COLUMN tenant_encryption_keys.status IS 'active (encrypts new data), decrypt_only (awaiting re-encryption) or retired';
+COMMENT ON COLUMN tenant_credentials.key_version IS 'Tenant key version protecting mfa_secret (0 = legacy plaintext)';
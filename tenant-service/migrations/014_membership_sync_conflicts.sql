@@ -0,0 +1,24 @@
+-- Migration: Track conflicts found by the staff-service membership reconciliation job
+-- Missing memberships are created automatically; anything needing a human decision is recorded here
+
+CREATE TABLE IF NOT EXISTS membership_sync_conflicts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    staff_id UUID NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    user_id UUID,
+    email VARCHAR(255),
+    staff_role VARCHAR(50),
+    membership_role VARCHAR(50),
+    details TEXT,
+    occurrences INTEGER DEFAULT 1,
+    first_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_membership_sync_conflict ON membership_sync_conflicts(tenant_id, staff_id, kind);
+CREATE INDEX IF NOT EXISTS idx_membership_sync_conflicts_resolved_at ON membership_sync_conflicts(resolved_at);
+
+COMMENT ON TABLE membership_sync_conflicts IS 'Staff-service vs user_tenant_memberships disagreements awaiting manual resolution';
+COMMENT ON COLUMN membership_sync_conflicts.kind IS 'role_mismatch, membership_inactive, staff_inactive, unlinked_staff or create_failed';
@@ -0,0 +1,30 @@
+-- Migration: 015_onboarding_custom_fields.sql
+-- Description: Adds per-template custom onboarding fields and structured storage for their answers
+-- Templates define extra questions (license numbers, cuisine type, ...) bound to an onboarding step
+
+-- ============================================================================
+-- STEP 1: Custom field definitions on templates
+-- ============================================================================
+-- JSON array of {key, label, type, required, step, options, pattern, min/max, ...}
+
+ALTER TABLE onboarding_templates
+ADD COLUMN IF NOT EXISTS custom_fields JSONB DEFAULT '[]';
+
+-- ============================================================================
+-- STEP 2: Custom field answers on business information
+-- ============================================================================
+-- JSON object keyed by field key; values are normalized to the field type
+
+ALTER TABLE business_information
+ADD COLUMN IF NOT EXISTS custom_fields JSONB DEFAULT '{}';
+
+-- GIN index for typed custom field queries (containment lookups)
+CREATE INDEX IF NOT EXISTS idx_business_info_custom_fields
+ON business_information USING GIN (custom_fields jsonb_path_ops);
+
+-- ============================================================================
+-- STEP 3: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON COLUMN onboarding_templates.custom_fields IS 'Custom onboarding field definitions (type, validation, required, step binding)';
+COMMENT ON COLUMN business_information.custom_fields IS 'Answers to the template custom fields bound to the business information step';
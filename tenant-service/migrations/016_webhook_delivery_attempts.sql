@@ -0,0 +1,62 @@
+-- Migration: 016_webhook_delivery_attempts.sql
+-- Description: Adds per-attempt delivery history and dead-letter tracking for webhook events
+-- Lets integrators inspect failed deliveries, replay them and see per-endpoint failure rates
+
+-- ============================================================================
+-- STEP 1: Tenant scoping and dead-letter columns on webhook events
+-- ============================================================================
+
+ALTER TABLE webhook_events
+ADD COLUMN IF NOT EXISTS tenant_id UUID,
+ADD COLUMN IF NOT EXISTS last_error TEXT,
+ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS dead_lettered_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS replay_count INTEGER DEFAULT 0;
+
+-- Backfill the tenant from the onboarding session that produced the event
+UPDATE webhook_events we
+SET tenant_id = os.tenant_id
+FROM onboarding_sessions os
+WHERE we.onboarding_session_id = os.id
+  AND we.tenant_id IS NULL
+  AND os.tenant_id IS NOT NULL;
+
+-- Events that already exhausted their retries are dead letters
+UPDATE webhook_events
+SET dead_lettered_at = updated_at
+WHERE status = 'failed' AND dead_lettered_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_tenant_id ON webhook_events(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_events_dead_lettered_at ON webhook_events(dead_lettered_at);
+
+-- ============================================================================
+-- STEP 2: Delivery attempt history
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    webhook_event_id UUID NOT NULL REFERENCES webhook_events(id) ON DELETE CASCADE,
+    tenant_id UUID,
+    webhook_url VARCHAR(2048),
+    attempt_number INTEGER NOT NULL,
+    trigger VARCHAR(20) NOT NULL DEFAULT 'automatic',
+    success BOOLEAN NOT NULL DEFAULT FALSE,
+    status_code INTEGER,
+    response_body TEXT,
+    error TEXT,
+    duration_ms BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_event ON webhook_delivery_attempts(webhook_event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_tenant ON webhook_delivery_attempts(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_url ON webhook_delivery_attempts(webhook_url);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_created_at ON webhook_delivery_attempts(created_at);
+
+-- ============================================================================
+-- STEP 3: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON COLUMN webhook_events.dead_lettered_at IS 'When the event exhausted its retries; cleared when it is replayed';
+COMMENT ON COLUMN webhook_events.replay_count IS 'Number of manual replays requested for the event';
+COMMENT ON TABLE webhook_delivery_attempts IS 'One row per HTTP delivery attempt of a webhook event (automatic or manual replay)';
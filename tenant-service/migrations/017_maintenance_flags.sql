@@ -0,0 +1,32 @@
+-- Migration: 017_maintenance_flags.sql
+-- Description: Adds platform and per-tenant read-only maintenance flags
+-- While a flag is active, write endpoints return 503 so data migrations can run safely
+
+-- ============================================================================
+-- STEP 1: Maintenance flags
+-- ============================================================================
+-- scope_key is "platform" or "tenant:<tenant_id>"; one active flag per scope
+
+CREATE TABLE IF NOT EXISTS maintenance_flags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    scope_key VARCHAR(64) NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    tenant_id UUID,
+    reason VARCHAR(255),
+    message TEXT,
+    enabled_by VARCHAR(255),
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_maintenance_flags_scope_key ON maintenance_flags(scope_key);
+CREATE INDEX IF NOT EXISTS idx_maintenance_flags_tenant_id ON maintenance_flags(tenant_id);
+
+-- ============================================================================
+-- STEP 2: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON TABLE maintenance_flags IS 'Platform and per-tenant read-only maintenance flags (cached in Redis)';
+COMMENT ON COLUMN maintenance_flags.expires_at IS 'Flag lifts automatically after this time; NULL = until disabled';
@@ -0,0 +1,37 @@
+-- Migration: 018_tenant_exports.sql
+-- Description: Adds asynchronous tenant data exports (GDPR data portability)
+-- Archives are generated in the background, downloadable until expires_at, then purged
+
+-- ============================================================================
+-- STEP 1: Tenant exports
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_exports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    requested_by UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    archive BYTEA,
+    size_bytes BIGINT DEFAULT 0,
+    checksum VARCHAR(64),
+    record_counts JSONB,
+    download_token_hash VARCHAR(64),
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    downloaded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_exports_tenant_id ON tenant_exports(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_exports_status ON tenant_exports(status);
+CREATE INDEX IF NOT EXISTS idx_tenant_exports_expires_at ON tenant_exports(expires_at);
+
+-- ============================================================================
+-- STEP 2: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON TABLE tenant_exports IS 'Asynchronously generated ZIP archives of tenant data for data portability requests';
+COMMENT ON COLUMN tenant_exports.download_token_hash IS 'SHA-256 of the one-off token embedded in the emailed download link';
@@ -0,0 +1,22 @@
+-- Migration: 019_tenant_pending_deletion.sql
+-- Description: Adds a grace period to tenant deletion
+-- Deleted tenants enter pending_deletion and can be restored by the owner until
+-- deletion_scheduled_for, after which the background job purges them
+
+-- ============================================================================
+-- STEP 1: Deletion schedule columns
+-- ============================================================================
+
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS deletion_requested_by UUID;
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS deletion_scheduled_for TIMESTAMP WITH TIME ZONE;
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS deletion_reason TEXT;
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS status_before_deletion VARCHAR(50);
+
+-- ============================================================================
+-- STEP 2: Index for the purge job
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_tenants_deletion_scheduled_for
+    ON tenants (deletion_scheduled_for)
+    WHERE status = 'pending_deletion';
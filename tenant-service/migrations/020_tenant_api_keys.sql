@@ -0,0 +1,35 @@
+-- Migration: 020_tenant_api_keys.sql
+-- Description: Adds tenant-scoped API keys for service access
+-- Only a SHA-256 of each key is stored; the plaintext is shown once on creation/rotation
+
+-- ============================================================================
+-- STEP 1: Tenant API keys
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    scope VARCHAR(30) NOT NULL,
+    key_prefix VARCHAR(20) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    created_by UUID NOT NULL,
+    rotated_from_id UUID,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_api_keys_key_hash ON tenant_api_keys(key_hash);
+CREATE INDEX IF NOT EXISTS idx_tenant_api_keys_tenant_id ON tenant_api_keys(tenant_id);
+
+-- ============================================================================
+-- STEP 2: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON TABLE tenant_api_keys IS 'Tenant-scoped API keys validated by other services via /internal/api-keys/validate';
+COMMENT ON COLUMN tenant_api_keys.scope IS 'read_only, member_management or full';
+COMMENT ON COLUMN tenant_api_keys.key_hash IS 'SHA-256 of the full key; the plaintext is never stored';
@@ -0,0 +1,9 @@
+-- Migration: 021_tenant_password_policy.sql
+-- Description: Adds breached-password checking to tenant auth policies
+-- Rotation uses the existing password_expiry_days column
+
+-- ============================================================================
+-- STEP 1: Breached-password check flag
+-- ============================================================================
+
+ALTER TABLE tenant_auth_policies ADD COLUMN IF NOT EXISTS password_check_breached BOOLEAN DEFAULT false;
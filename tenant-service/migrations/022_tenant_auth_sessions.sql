@@ -0,0 +1,34 @@
+-- Migration: 022_tenant_auth_sessions.sql
+-- Description: Adds a registry of login sessions so users can list and revoke them per tenant
+-- Session IDs are the Keycloak "sid" of the issued tokens when available
+
+-- ============================================================================
+-- STEP 1: Session registry
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_auth_sessions (
+    id VARCHAR(255) PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_id UUID,
+    auth_context VARCHAR(20),
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_by VARCHAR(255),
+    revoked_reason VARCHAR(50)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_auth_sessions_owner ON tenant_auth_sessions(tenant_id, subject);
+CREATE INDEX IF NOT EXISTS idx_tenant_auth_sessions_expires_at ON tenant_auth_sessions(expires_at);
+
+-- ============================================================================
+-- STEP 2: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON TABLE tenant_auth_sessions IS 'Login sessions per tenant user; Redis caches active sessions for listing';
+COMMENT ON COLUMN tenant_auth_sessions.subject IS 'Keycloak user ID (JWT sub) that owns the session';
+COMMENT ON COLUMN tenant_auth_sessions.revoked_reason IS 'Why the session was ended, e.g. user_revoked';
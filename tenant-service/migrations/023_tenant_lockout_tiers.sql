@@ -0,0 +1,19 @@
+-- Migration: 023_tenant_lockout_tiers.sql
+-- Description: Makes every progressive lockout tier configurable per tenant
+-- Tier n locks after n x max_login_attempts failures; previously only tier 1 was configurable
+
+-- ============================================================================
+-- STEP 1: Tier durations
+-- ============================================================================
+
+ALTER TABLE tenant_auth_policies ADD COLUMN IF NOT EXISTS tier2_lockout_minutes INTEGER DEFAULT 60;
+ALTER TABLE tenant_auth_policies ADD COLUMN IF NOT EXISTS tier3_lockout_minutes INTEGER DEFAULT 360;
+ALTER TABLE tenant_auth_policies ADD COLUMN IF NOT EXISTS tier4_lockout_minutes INTEGER DEFAULT 1440;
+
+-- ============================================================================
+-- STEP 2: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON COLUMN tenant_auth_policies.tier2_lockout_minutes IS 'Lockout length after 2 x max_login_attempts failures';
+COMMENT ON COLUMN tenant_auth_policies.tier3_lockout_minutes IS 'Lockout length after 3 x max_login_attempts failures';
+COMMENT ON COLUMN tenant_auth_policies.tier4_lockout_minutes IS 'Maximum lockout length (4 x max_login_attempts failures or more); lockouts always expire';
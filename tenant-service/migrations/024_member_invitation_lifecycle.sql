@@ -0,0 +1,38 @@
+-- Migration: 024_member_invitation_lifecycle.sql
+-- Description: Tracks invitation resends and revocation so admins can list, resend and revoke
+-- pending invitations; expired and revoked invitations are purged by the background runner
+
+-- ============================================================================
+-- STEP 1: Lifecycle columns (invited_email was added in 006)
+-- ============================================================================
+
+ALTER TABLE user_tenant_memberships ADD COLUMN IF NOT EXISTS invitation_sent_count INTEGER DEFAULT 0;
+ALTER TABLE user_tenant_memberships ADD COLUMN IF NOT EXISTS invitation_last_sent_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE user_tenant_memberships ADD COLUMN IF NOT EXISTS invitation_revoked_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE user_tenant_memberships ADD COLUMN IF NOT EXISTS invitation_revoked_by UUID;
+
+UPDATE user_tenant_memberships
+SET invitation_sent_count = 1, invitation_last_sent_at = invited_at
+WHERE invited_at IS NOT NULL AND COALESCE(invitation_sent_count, 0) = 0;
+
+-- ============================================================================
+-- STEP 2: Allow several pending invitations per tenant
+-- ============================================================================
+-- Invitations use the nil UUID as user_id until accepted, so the user/tenant
+-- uniqueness only applies to real users
+
+ALTER TABLE user_tenant_memberships DROP CONSTRAINT IF EXISTS uk_user_tenant;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_user_tenant
+    ON user_tenant_memberships(user_id, tenant_id)
+    WHERE user_id <> '00000000-0000-0000-0000-000000000000';
+
+CREATE INDEX IF NOT EXISTS idx_utm_pending_invitations
+    ON user_tenant_memberships(tenant_id, invited_at)
+    WHERE user_id = '00000000-0000-0000-0000-000000000000' AND accepted_at IS NULL;
+
+-- ============================================================================
+-- STEP 3: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON COLUMN user_tenant_memberships.invitation_sent_count IS 'Times the invitation was sent, including resends';
+COMMENT ON COLUMN user_tenant_memberships.invitation_revoked_at IS 'When an admin revoked the invitation; the token is cleared at the same time';
@@ -0,0 +1,39 @@
+-- Migration: 025_onboarding_funnel_summary.sql
+-- Description: Daily onboarding funnel summary backing GET /api/v1/onboarding/analytics/funnel;
+-- rebuilt for the lookback window by the background runner so funnel queries never scan sessions
+
+-- ============================================================================
+-- STEP 1: Summary table
+-- ============================================================================
+-- One row per session start day, application type and stage. Template steps are
+-- keyed 'step:<task_id>' and ordered by the task order_index.
+
+CREATE TABLE IF NOT EXISTS onboarding_funnel_daily (
+    day DATE NOT NULL,
+    application_type VARCHAR(50) NOT NULL,
+    stage_key VARCHAR(150) NOT NULL,
+    stage_name VARCHAR(255),
+    stage_order INTEGER NOT NULL DEFAULT 0,
+    sessions BIGINT NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (day, application_type, stage_key)
+);
+
+-- ============================================================================
+-- STEP 2: Indexes for the refresh job
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_onboarding_sessions_started_at
+    ON onboarding_sessions(started_at);
+
+CREATE INDEX IF NOT EXISTS idx_verification_records_session_verified
+    ON verification_records(onboarding_session_id)
+    WHERE verification_type = 'email' AND status = 'verified';
+
+-- ============================================================================
+-- STEP 3: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON TABLE onboarding_funnel_daily IS 'Onboarding sessions started per day that reached each funnel stage; rebuilt by the funnel refresh job';
+COMMENT ON COLUMN onboarding_funnel_daily.stage_key IS 'started, step:<task_id>, email_verified or completed';
+COMMENT ON COLUMN onboarding_funnel_daily.sessions IS 'Sessions started on day that reached the stage (completed or skipped for template steps)';
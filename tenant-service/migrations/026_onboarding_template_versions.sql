@@ -0,0 +1,69 @@
+-- Migration: 026_onboarding_template_versions.sql
+-- Description: Versioned onboarding templates with a draft -> published -> archived workflow;
+-- sessions are pinned to the template version they started with
+
+-- ============================================================================
+-- STEP 1: Template versions
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS onboarding_template_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    template_id UUID NOT NULL REFERENCES onboarding_templates(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    template_config JSONB DEFAULT '{}',
+    steps JSONB DEFAULT '[]',
+    custom_fields JSONB DEFAULT '[]',
+    change_notes TEXT,
+    published_at TIMESTAMP WITH TIME ZONE,
+    archived_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_template_version_status CHECK (status IN ('draft', 'published', 'archived'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_template_version
+    ON onboarding_template_versions(template_id, version);
+CREATE INDEX IF NOT EXISTS idx_onboarding_template_versions_status
+    ON onboarding_template_versions(status);
+
+-- At most one draft and one published version per template
+CREATE UNIQUE INDEX IF NOT EXISTS idx_template_versions_one_draft
+    ON onboarding_template_versions(template_id) WHERE status = 'draft';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_template_versions_one_published
+    ON onboarding_template_versions(template_id) WHERE status = 'published';
+
+-- ============================================================================
+-- STEP 2: Record the current content of existing templates as their published version
+-- ============================================================================
+
+INSERT INTO onboarding_template_versions (template_id, version, status, name, description, template_config, steps, custom_fields, published_at)
+SELECT t.id, GREATEST(COALESCE(t.version, 1), 1), 'published', t.name, t.description,
+       t.template_config, t.steps, t.custom_fields, t.updated_at
+FROM onboarding_templates t
+WHERE NOT EXISTS (
+    SELECT 1 FROM onboarding_template_versions v WHERE v.template_id = t.id
+);
+
+-- ============================================================================
+-- STEP 3: Pin sessions to a template version
+-- ============================================================================
+-- Existing sessions are pinned to the version that is live now, which is the
+-- content they have been using so far
+
+ALTER TABLE onboarding_sessions ADD COLUMN IF NOT EXISTS template_version INTEGER DEFAULT 0;
+
+UPDATE onboarding_sessions s
+SET template_version = GREATEST(COALESCE(t.version, 1), 1)
+FROM onboarding_templates t
+WHERE s.template_id = t.id AND COALESCE(s.template_version, 0) = 0;
+
+-- ============================================================================
+-- STEP 4: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON TABLE onboarding_template_versions IS 'Snapshots of onboarding template content; drafts are edited, published versions are immutable';
+COMMENT ON COLUMN onboarding_template_versions.status IS 'draft, published (live for new sessions) or archived (superseded, still used by pinned sessions)';
+COMMENT ON COLUMN onboarding_sessions.template_version IS 'Template version the session started with; 0 = started before versioning';
@@ -0,0 +1,59 @@
+-- Migration: 027_tenant_sso_configs.sql
+-- Description: Per-tenant enterprise SSO (OIDC/SAML) backed by a Keycloak identity provider,
+-- with email-domain login routing and IdP group to membership role mappings
+
+-- ============================================================================
+-- STEP 1: SSO configurations
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_sso_configs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    protocol VARCHAR(10) NOT NULL,
+    alias VARCHAR(100) NOT NULL,
+    display_name VARCHAR(255),
+    enabled BOOLEAN DEFAULT FALSE,
+    enforced BOOLEAN DEFAULT FALSE,
+    domains JSONB DEFAULT '[]',
+    groups_claim VARCHAR(100) DEFAULT 'groups',
+    group_role_mappings JSONB DEFAULT '{}',
+    default_role VARCHAR(50) DEFAULT 'member',
+    -- OIDC
+    issuer VARCHAR(500),
+    authorization_url VARCHAR(500),
+    token_url VARCHAR(500),
+    jwks_url VARCHAR(500),
+    client_id VARCHAR(255),
+    -- SAML
+    metadata_url VARCHAR(500),
+    idp_entity_id VARCHAR(500),
+    sso_url VARCHAR(500),
+    slo_url VARCHAR(500),
+    signing_certificate TEXT,
+    certificate_expires_at TIMESTAMP WITH TIME ZONE,
+    -- Provisioning
+    provisioning_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    provisioning_error TEXT,
+    provisioned_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID,
+    updated_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_tenant_sso_protocol CHECK (protocol IN ('oidc', 'saml')),
+    CONSTRAINT chk_tenant_sso_provisioning_status CHECK (provisioning_status IN ('pending', 'provisioned', 'failed')),
+    CONSTRAINT chk_tenant_sso_default_role CHECK (default_role IN ('admin', 'manager', 'member', 'viewer'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_sso_configs_tenant_id ON tenant_sso_configs(tenant_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_sso_configs_alias ON tenant_sso_configs(alias);
+
+-- Email domain lookups (domains @> '["example.com"]')
+CREATE INDEX IF NOT EXISTS idx_tenant_sso_configs_domains ON tenant_sso_configs USING GIN (domains);
+
+-- ============================================================================
+-- STEP 2: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON TABLE tenant_sso_configs IS 'Enterprise tenant SSO; the identity provider lives in Keycloak under alias, OIDC client secrets are stored only there';
+COMMENT ON COLUMN tenant_sso_configs.enforced IS 'Password login is refused for staff whose email domain is in domains; /auth/validate returns SSO_REQUIRED';
+COMMENT ON COLUMN tenant_sso_configs.group_role_mappings IS 'IdP group -> membership role (admin, manager, member, viewer); the highest mapped role wins';
@@ -0,0 +1,72 @@
+-- Migration: 028_tenant_join_domains.sql
+-- Description: Email domain auto-join - domains a tenant proved it controls (TXT record or
+-- emailed code) and the approval queue for users who sign in with an address at them
+
+-- ============================================================================
+-- STEP 1: Join domains
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_join_domains (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    domain VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    verification_method VARCHAR(20) NOT NULL,
+    verification_token VARCHAR(64) NOT NULL,
+    verification_email VARCHAR(255),
+    email_code_hash VARCHAR(64),
+    email_code_expires_at TIMESTAMP WITH TIME ZONE,
+    email_code_attempts INTEGER DEFAULT 0,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    verified_by UUID,
+    join_mode VARCHAR(20) NOT NULL DEFAULT 'approval',
+    default_role VARCHAR(50) NOT NULL DEFAULT 'member',
+    enabled BOOLEAN DEFAULT TRUE,
+    created_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_tenant_join_domain_status CHECK (status IN ('pending', 'verified')),
+    CONSTRAINT chk_tenant_join_domain_method CHECK (verification_method IN ('dns_txt', 'email')),
+    CONSTRAINT chk_tenant_join_domain_mode CHECK (join_mode IN ('auto', 'approval')),
+    CONSTRAINT chk_tenant_join_domain_role CHECK (default_role IN ('manager', 'member', 'viewer'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_join_domain ON tenant_join_domains(tenant_id, domain);
+CREATE INDEX IF NOT EXISTS idx_tenant_join_domains_domain ON tenant_join_domains(domain);
+
+-- A domain can be verified by one tenant only
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_join_domains_verified
+    ON tenant_join_domains(domain) WHERE status = 'verified';
+
+-- ============================================================================
+-- STEP 2: Join requests (approval mode)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_join_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    domain_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    role VARCHAR(50) NOT NULL,
+    reviewed_by UUID,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_tenant_join_request_status CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_join_requests_tenant_id ON tenant_join_requests(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_join_requests_domain_id ON tenant_join_requests(domain_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_join_requests_user_id ON tenant_join_requests(user_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_join_requests_status ON tenant_join_requests(status);
+
+-- ============================================================================
+-- STEP 3: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON TABLE tenant_join_domains IS 'Email domains whose users may join the tenant without an invitation once verified';
+COMMENT ON COLUMN tenant_join_domains.verification_token IS 'Published as TXT _tesserix-join.<domain> = tesserix-join=<token>';
+COMMENT ON COLUMN tenant_join_domains.email_code_hash IS 'SHA-256 of the code emailed to verification_email; cleared once verified';
+COMMENT ON TABLE tenant_join_requests IS 'Users waiting for an owner or admin to approve a join through a domain in approval mode';
@@ -0,0 +1,49 @@
+-- Migration: 029_tenant_usage_metering.sql
+-- Description: Per-tenant usage metering (API calls, storage, emails) against plan quotas,
+-- with de-duplication of metered events and a record of published quota breaches
+
+-- ============================================================================
+-- STEP 1: Usage counters
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_usage_counters (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    metric VARCHAR(30) NOT NULL,
+    period VARCHAR(7) NOT NULL DEFAULT '',
+    value BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, metric, period)
+);
+
+-- ============================================================================
+-- STEP 2: Metered events (idempotency for at-least-once delivery)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_usage_events (
+    event_key VARCHAR(150) PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_usage_events_tenant_id ON tenant_usage_events(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_usage_events_created_at ON tenant_usage_events(created_at);
+
+-- ============================================================================
+-- STEP 3: Quota breach notices
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_quota_notices (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    metric VARCHAR(30) NOT NULL,
+    period VARCHAR(7) NOT NULL DEFAULT '',
+    notified_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, metric, period)
+);
+
+-- ============================================================================
+-- STEP 4: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON TABLE tenant_usage_counters IS 'Metered usage per tenant; monthly metrics keyed by YYYY-MM period, running totals (storage) by an empty period';
+COMMENT ON TABLE tenant_usage_events IS 'Keys of document and notification events already metered; pruned after the event retention window';
+COMMENT ON TABLE tenant_quota_notices IS 'tenant.quota.exceeded already published for this metric and period; cleared when a running total drops back under quota';
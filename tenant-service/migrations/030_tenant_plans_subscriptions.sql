@@ -0,0 +1,57 @@
+-- Migration: 030_tenant_plans_subscriptions.sql
+-- Description: Subscription plans with feature flags, and each tenant's plan, trial and
+-- feature overrides. Plans are seeded per pricing tier on startup.
+
+-- ============================================================================
+-- STEP 1: Plans
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    pricing_tier VARCHAR(50) NOT NULL,
+    monthly_price NUMERIC NOT NULL DEFAULT 0,
+    yearly_price NUMERIC NOT NULL DEFAULT 0,
+    features JSONB DEFAULT '{}',
+    rank INTEGER NOT NULL DEFAULT 0,
+    self_service BOOLEAN DEFAULT FALSE,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_plans_code ON tenant_plans(code);
+
+-- ============================================================================
+-- STEP 2: Tenant subscriptions
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_subscriptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    plan_id UUID NOT NULL REFERENCES tenant_plans(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    billing_cycle VARCHAR(20) NOT NULL DEFAULT 'monthly',
+    trial_ends_at TIMESTAMP WITH TIME ZONE,
+    feature_overrides JSONB DEFAULT '{}',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    changed_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_subscriptions_tenant_id ON tenant_subscriptions(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_subscriptions_plan_id ON tenant_subscriptions(plan_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_subscriptions_status ON tenant_subscriptions(status);
+CREATE INDEX IF NOT EXISTS idx_tenant_subscriptions_trial_ends_at ON tenant_subscriptions(trial_ends_at);
+
+-- ============================================================================
+-- STEP 3: Add comments for documentation
+-- ============================================================================
+
+COMMENT ON TABLE tenant_plans IS 'Subscription plans; pricing_tier selects the quotas, features holds the feature flags the plan grants';
+COMMENT ON TABLE tenant_subscriptions IS 'Each tenant''s plan; tenants without a row are on the plan of their pricing tier';
+COMMENT ON COLUMN tenant_subscriptions.trial_ends_at IS 'End of a trial; the trial expiry job then moves the tenant to the default plan';
+COMMENT ON COLUMN tenant_subscriptions.feature_overrides IS 'Per-tenant feature grants (true) or revocations (false) on top of the plan';
@@ -0,0 +1,6 @@
+-- Migration: 031_tenant_sso_oidc_discovery.sql
+-- Description: OIDC identity providers can be configured from their discovery document;
+-- remember the URL the endpoints were read from
+
+ALTER TABLE tenant_sso_configs
+    ADD COLUMN IF NOT EXISTS discovery_url VARCHAR(500);
@@ -0,0 +1,35 @@
+-- Migration: 033_tenant_billing_state.sql
+-- Description: Billing provider subscription state cached on tenants, and processed billing
+-- webhooks so redeliveries are not applied twice.
+
+-- ============================================================================
+-- STEP 1: Billing state on tenants
+-- ============================================================================
+
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS billing_status VARCHAR(20);
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS billing_customer_id VARCHAR(255);
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS billing_subscription_id VARCHAR(255);
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS billing_period_ends_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS payment_failed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS billing_updated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS status_before_suspension VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_tenants_billing_status ON tenants(billing_status);
+CREATE INDEX IF NOT EXISTS idx_tenants_billing_customer_id ON tenants(billing_customer_id) WHERE billing_customer_id IS NOT NULL;
+
+-- ============================================================================
+-- STEP 2: Processed billing webhooks
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_billing_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider_id VARCHAR(255) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    tenant_id UUID,
+    payload JSONB,
+    result VARCHAR(255),
+    processed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_billing_events_provider_id ON tenant_billing_events(provider_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_billing_events_tenant_id ON tenant_billing_events(tenant_id);
@@ -0,0 +1,27 @@
+-- Migration: 034_tenant_slug_redirects.sql
+-- Description: Tenant slug renames. The former slug keeps resolving to the tenant (and keeps
+-- its reservation) until the redirect expires.
+
+-- ============================================================================
+-- STEP 1: Last rename on tenants (rename cooldown)
+-- ============================================================================
+
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS slug_changed_at TIMESTAMP WITH TIME ZONE;
+
+-- ============================================================================
+-- STEP 2: Redirects from former slugs
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_slug_redirects (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    old_slug VARCHAR(50) NOT NULL,
+    new_slug VARCHAR(50) NOT NULL,
+    changed_by UUID,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_slug_redirects_old_slug ON tenant_slug_redirects(old_slug);
+CREATE INDEX IF NOT EXISTS idx_tenant_slug_redirects_tenant_id ON tenant_slug_redirects(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_slug_redirects_expires_at ON tenant_slug_redirects(expires_at);
@@ -0,0 +1,17 @@
+-- Migration: 035_seed_records.sql
+-- Description: Checksums of applied seed profile entries (default onboarding templates and
+-- reserved slugs). Entries whose checksum is unchanged are skipped on the next apply.
+
+CREATE TABLE IF NOT EXISTS seed_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR(30) NOT NULL,
+    key VARCHAR(100) NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    profile VARCHAR(50) NOT NULL,
+    target_id UUID,
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seed_record_key ON seed_records(kind, key);
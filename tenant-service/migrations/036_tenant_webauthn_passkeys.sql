@@ -0,0 +1,57 @@
+-- Migration: 036_tenant_webauthn_passkeys.sql
+-- Description: WebAuthn passkeys for tenant logins. Passkeys are stored per user per tenant,
+-- ceremonies use single-use challenges, and the auth policy can prefer or require passkeys
+-- for selected roles.
+
+-- ============================================================================
+-- STEP 1: Passkey policy on tenant auth policies
+-- ============================================================================
+
+ALTER TABLE tenant_auth_policies ADD COLUMN IF NOT EXISTS passkey_policy VARCHAR(20) DEFAULT 'off'; -- off, prefer, require
+ALTER TABLE tenant_auth_policies ADD COLUMN IF NOT EXISTS passkey_required_for_roles JSONB DEFAULT '["owner", "admin"]';
+
+-- ============================================================================
+-- STEP 2: Registered passkeys
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_webauthn_credentials (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES tenant_users(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    credential_id VARCHAR(1024) NOT NULL, -- base64url
+    public_key BYTEA NOT NULL,            -- COSE-encoded
+    attestation_type VARCHAR(50),
+    aaguid VARCHAR(36),
+    transports JSONB DEFAULT '[]',
+    flags INTEGER DEFAULT 0,
+    backup_eligible BOOLEAN DEFAULT FALSE,
+    backup_state BOOLEAN DEFAULT FALSE,
+    sign_count BIGINT DEFAULT 0,
+    clone_warning BOOLEAN DEFAULT FALSE,
+    name VARCHAR(100),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webauthn_credentials_tenant_credential ON tenant_webauthn_credentials(tenant_id, credential_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_webauthn_credentials_user_id ON tenant_webauthn_credentials(user_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_webauthn_credentials_tenant_id ON tenant_webauthn_credentials(tenant_id);
+
+-- ============================================================================
+-- STEP 3: Registration and login challenges (single use)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_webauthn_challenges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID, -- NULL for username-less logins
+    ceremony VARCHAR(20) NOT NULL, -- registration, login
+    session_data JSONB NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_webauthn_challenges_tenant_id ON tenant_webauthn_challenges(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_webauthn_challenges_user_id ON tenant_webauthn_challenges(user_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_webauthn_challenges_expires_at ON tenant_webauthn_challenges(expires_at);
@@ -0,0 +1,25 @@
+-- Migration: 037_tenant_contact_changes.sql
+-- Description: Verified changes of the tenant's business contact email and phone. A change is
+-- pending until the code sent to the new value is verified; the old value stays active until then.
+
+-- ============================================================================
+-- STEP 1: Pending and completed contact changes
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_contact_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    field VARCHAR(20) NOT NULL, -- email, phone
+    old_value VARCHAR(255),
+    new_value VARCHAR(255) NOT NULL,
+    phone_country_code VARCHAR(10),
+    requested_by UUID NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_contact_changes_tenant_id ON tenant_contact_changes(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_tenant_contact_changes_expires_at ON tenant_contact_changes(expires_at);
@@ -0,0 +1,20 @@
+-- Migration: 038_tenant_session_policy.sql
+-- Description: Per-tenant session lifetime controls. session_timeout_minutes remains the absolute
+-- lifetime; tenants can add an idle timeout and allow longer remember-me logins. Sessions keep the
+-- idle timeout in force when they started, so policy changes apply to new logins.
+
+-- ============================================================================
+-- STEP 1: Session policy on tenant auth policies
+-- ============================================================================
+
+ALTER TABLE tenant_auth_policies ADD COLUMN IF NOT EXISTS session_idle_timeout_minutes INTEGER DEFAULT 0; -- 0 = no idle timeout
+ALTER TABLE tenant_auth_policies ADD COLUMN IF NOT EXISTS session_remember_me_days INTEGER DEFAULT 0;     -- 0 = remember-me disabled
+
+-- ============================================================================
+-- STEP 2: Idle timeout and remember-me on sessions
+-- ============================================================================
+
+ALTER TABLE tenant_auth_sessions ADD COLUMN IF NOT EXISTS idle_timeout_minutes INTEGER DEFAULT 0;
+ALTER TABLE tenant_auth_sessions ADD COLUMN IF NOT EXISTS remember_me BOOLEAN DEFAULT FALSE;
+
+COMMENT ON COLUMN tenant_auth_sessions.idle_timeout_minutes IS 'Inactivity after which the session is revoked (idle_timeout); 0 = none';
@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+// slugClaimContenders is how many onboarding sessions race for the same slug
+const slugClaimContenders = 16
+
+// openSlugClaimDB connects to the Postgres database named by TEST_DATABASE_URL, skipping the
+// test when it is not set. The advisory and row locks under test only exist in Postgres, so
+// these tests cannot run against sqlmock.
+func openSlugClaimDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping Postgres integration test")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err, "Failed to connect to test database")
+	require.NoError(t, db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error)
+	require.NoError(t, db.AutoMigrate(&models.TenantSlugReservation{}), "Failed to migrate test database")
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(slugClaimContenders + 2)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+// uniqueSlug returns a slug no other test run has used, so runs don't see each other's rows
+func uniqueSlug(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+
+	slug := fmt.Sprintf("race-%s", uuid.New().String()[:8])
+	t.Cleanup(func() {
+		db.Where("slug = ?", slug).Delete(&models.TenantSlugReservation{})
+	})
+	return slug
+}
+
+func TestReserveSlug_ConcurrentSessionsOneWinner(t *testing.T) {
+	db := openSlugClaimDB(t)
+	repo := repository.NewMembershipRepository(db)
+	slug := uniqueSlug(t, db)
+	ctx := context.Background()
+
+	sessions := make([]uuid.UUID, slugClaimContenders)
+	errs := make([]error, slugClaimContenders)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range sessions {
+		sessions[i] = uuid.New()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = repo.ReserveSlug(ctx, slug, sessions[i], "race@example.com")
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	winner := -1
+	for i, err := range errs {
+		if err == nil {
+			require.Equal(t, -1, winner, "sessions %d and %d both reserved the slug", winner, i)
+			winner = i
+			continue
+		}
+		assert.ErrorIs(t, err, repository.ErrSlugTaken)
+	}
+	require.NotEqual(t, -1, winner, "no session reserved the slug")
+
+	// Reserving again from the winning session only extends its hold
+	reservation, err := repo.ReserveSlug(ctx, slug, sessions[winner], "race@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, reservation.SessionID)
+	assert.Equal(t, sessions[winner], *reservation.SessionID)
+	assert.Equal(t, models.SlugReservationPending, reservation.Status)
+
+	var rows int64
+	require.NoError(t, db.Model(&models.TenantSlugReservation{}).Where("slug = ?", slug).Count(&rows).Error)
+	assert.Equal(t, int64(1), rows)
+}
+
+func TestClaimSlug_ConcurrentSessionsOneWinner(t *testing.T) {
+	db := openSlugClaimDB(t)
+	repo := repository.NewMembershipRepository(db)
+	slug := uniqueSlug(t, db)
+	ctx := context.Background()
+
+	sessions := make([]uuid.UUID, slugClaimContenders)
+	tenants := make([]uuid.UUID, slugClaimContenders)
+	errs := make([]error, slugClaimContenders)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range sessions {
+		sessions[i], tenants[i] = uuid.New(), uuid.New()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			// Each session reserves the slug and, if that works, creates its tenant with it
+			if _, err := repo.ReserveSlug(ctx, slug, sessions[i], "race@example.com"); err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = repo.ActivateSlugReservationForSession(ctx, slug, sessions[i], tenants[i])
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	winner := -1
+	for i, err := range errs {
+		if err == nil {
+			require.Equal(t, -1, winner, "sessions %d and %d both claimed the slug", winner, i)
+			winner = i
+			continue
+		}
+		assert.ErrorIs(t, err, repository.ErrSlugTaken, "session %d", i)
+	}
+	require.NotEqual(t, -1, winner, "no session claimed the slug")
+
+	// A retried onboarding step from the winning session changes nothing
+	_, err := repo.ReserveSlug(ctx, slug, sessions[winner], "race@example.com")
+	require.NoError(t, err)
+	require.NoError(t, repo.ActivateSlugReservationForSession(ctx, slug, sessions[winner], tenants[winner]))
+
+	// Every other session is still refused
+	loser := (winner + 1) % slugClaimContenders
+	assert.ErrorIs(t, repo.ActivateSlugReservationForSession(ctx, slug, sessions[loser], tenants[loser]), repository.ErrSlugTaken)
+
+	var reservation models.TenantSlugReservation
+	require.NoError(t, db.Where("slug = ?", slug).First(&reservation).Error)
+	assert.Equal(t, models.SlugReservationActive, reservation.Status)
+	require.NotNil(t, reservation.TenantID)
+	assert.Equal(t, tenants[winner], *reservation.TenantID)
+	require.NotNil(t, reservation.SessionID)
+	assert.Equal(t, sessions[winner], *reservation.SessionID)
+	assert.Nil(t, reservation.ExpiresAt)
+}
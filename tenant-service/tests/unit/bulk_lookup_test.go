@@ -0,0 +1,38 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/services"
+)
+
+func TestBulkGetTenants_RejectsEmptyRequest(t *testing.T) {
+	svc := services.NewTenantService(nil, nil, nil, nil)
+
+	_, err := svc.BulkGetTenants(context.Background(), &services.BulkTenantLookupRequest{
+		IDs:   []string{"", "  "},
+		Slugs: []string{""},
+	})
+
+	validationErr, ok := services.IsValidationError(err)
+	require.True(t, ok, "expected validation error, got %v", err)
+	assert.Equal(t, "ids", validationErr.Field)
+}
+
+func TestBulkGetTenants_RejectsOversizedRequest(t *testing.T) {
+	svc := services.NewTenantService(nil, nil, nil, nil)
+
+	slugs := make([]string, services.MaxBulkTenantLookup+1)
+	for i := range slugs {
+		slugs[i] = fmt.Sprintf("store-%d", i)
+	}
+
+	_, err := svc.BulkGetTenants(context.Background(), &services.BulkTenantLookupRequest{Slugs: slugs})
+
+	_, ok := services.IsValidationError(err)
+	assert.True(t, ok, "expected validation error, got %v", err)
+}
@@ -0,0 +1,88 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/metrics"
+)
+
+func TestTraceIDFromHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"w3c traceparent", map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}, "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"b3 single header", map[string]string{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1"}, "80f198ee56343ba864fe8b2a57d3eff7"},
+		{"b3 multi header", map[string]string{"X-B3-TraceId": "463ac35c9f6413ad"}, "463ac35c9f6413ad"},
+		{"traceparent wins over b3", map[string]string{
+			"traceparent":  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"X-B3-TraceId": "463ac35c9f6413ad",
+		}, "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"all-zero trace id", map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"}, ""},
+		{"malformed traceparent", map[string]string{"traceparent": "not-a-trace"}, ""},
+		{"no headers", map[string]string{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for key, value := range tt.headers {
+				header.Set(key, value)
+			}
+			assert.Equal(t, tt.want, metrics.TraceIDFromHeaders(header))
+		})
+	}
+}
+
+func TestTraceIDContextRoundTrip(t *testing.T) {
+	ctx := metrics.WithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", metrics.TraceIDFromContext(ctx))
+
+	assert.Empty(t, metrics.TraceIDFromContext(context.Background()))
+	assert.Equal(t, context.Background(), metrics.WithTraceID(context.Background(), ""))
+}
+
+func TestObserveKeycloakCall_AttachesTraceExemplar(t *testing.T) {
+	traceID := "0af7651916cd43dd8448eb211c80319c"
+	ctx := metrics.WithTraceID(context.Background(), traceID)
+
+	metrics.ObserveKeycloakCall(ctx, "unit_test_operation", time.Now(), errors.New("unavailable"))
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	found := false
+	for _, family := range families {
+		if family.GetName() != "tesseract_tenant_keycloak_request_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["operation"] != "unit_test_operation" {
+				continue
+			}
+			assert.Equal(t, metrics.ResultError, labels["result"])
+			for _, bucket := range metric.GetHistogram().GetBucket() {
+				if exemplar := bucket.GetExemplar(); exemplar != nil {
+					for _, label := range exemplar.GetLabel() {
+						if label.GetName() == "trace_id" && label.GetValue() == traceID {
+							found = true
+						}
+					}
+				}
+			}
+		}
+	}
+	assert.True(t, found, "expected an exemplar with the trace ID on the keycloak latency histogram")
+}
@@ -0,0 +1,160 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+func TestValidateCustomFieldDefinitions(t *testing.T) {
+	valid := models.CustomFieldDefinition{Key: "liquor_license", Label: "Liquor license", Type: models.CustomFieldTypeText}
+
+	tests := []struct {
+		name    string
+		defs    []models.CustomFieldDefinition
+		wantErr string
+	}{
+		{"valid", []models.CustomFieldDefinition{valid, {Key: "seats", Label: "Seats", Type: models.CustomFieldTypeNumber}}, ""},
+		{"uppercase key", []models.CustomFieldDefinition{{Key: "License", Label: "License", Type: models.CustomFieldTypeText}}, "must be lowercase"},
+		{"duplicate key", []models.CustomFieldDefinition{valid, valid}, "duplicate field key"},
+		{"missing label", []models.CustomFieldDefinition{{Key: "vat", Type: models.CustomFieldTypeText}}, "label is required"},
+		{"unknown type", []models.CustomFieldDefinition{{Key: "vat", Label: "VAT", Type: "color"}}, "unsupported type"},
+		{"select without options", []models.CustomFieldDefinition{{Key: "tier", Label: "Tier", Type: models.CustomFieldTypeSelect}}, "require options"},
+		{"invalid pattern", []models.CustomFieldDefinition{{Key: "vat", Label: "VAT", Type: models.CustomFieldTypeText, Pattern: "("}}, "invalid pattern"},
+		{"inverted length", []models.CustomFieldDefinition{{Key: "vat", Label: "VAT", Type: models.CustomFieldTypeText, MinLength: intPtr(5), MaxLength: intPtr(2)}}, "min_length exceeds max_length"},
+		{"inverted range", []models.CustomFieldDefinition{{Key: "seats", Label: "Seats", Type: models.CustomFieldTypeNumber, Min: floatPtr(10), Max: floatPtr(1)}}, "min exceeds max"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := services.ValidateCustomFieldDefinitions(tt.defs)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateCustomFieldValues_Normalizes(t *testing.T) {
+	defs := []models.CustomFieldDefinition{
+		{Key: "seats", Label: "Seats", Type: models.CustomFieldTypeNumber, Min: floatPtr(1)},
+		{Key: "delivery", Label: "Delivery", Type: models.CustomFieldTypeBoolean},
+		{Key: "opened_on", Label: "Opened on", Type: models.CustomFieldTypeDate},
+		{Key: "cuisines", Label: "Cuisines", Type: models.CustomFieldTypeMultiSelect, Options: []string{"thai", "italian"}},
+		{Key: "license", Label: "License", Type: models.CustomFieldTypeText, Pattern: `^[A-Z]{2}-\d+$`},
+		{Key: "notes", Label: "Notes", Type: models.CustomFieldTypeText},
+	}
+
+	values, err := services.ValidateCustomFieldValues(defs, map[string]interface{}{
+		"seats":     "40",
+		"delivery":  "true",
+		"opened_on": "2024-03-01T10:00:00Z",
+		"cuisines":  []interface{}{"thai", "thai", "italian"},
+		"license":   "  CA-1234 ",
+		"notes":     "",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"seats":     float64(40),
+		"delivery":  true,
+		"opened_on": "2024-03-01",
+		"cuisines":  []string{"thai", "italian"},
+		"license":   "CA-1234",
+	}, values, "empty optional fields are dropped")
+}
+
+func TestValidateCustomFieldValues_CollectsErrors(t *testing.T) {
+	defs := []models.CustomFieldDefinition{
+		{Key: "license", Label: "Liquor license", Type: models.CustomFieldTypeText, Required: true},
+		{Key: "contact", Label: "Contact", Type: models.CustomFieldTypeEmail},
+		{Key: "site", Label: "Site", Type: models.CustomFieldTypeURL},
+		{Key: "tier", Label: "Tier", Type: models.CustomFieldTypeSelect, Options: []string{"gold", "silver"}},
+		{Key: "seats", Label: "Seats", Type: models.CustomFieldTypeNumber, Max: floatPtr(100)},
+	}
+
+	_, err := services.ValidateCustomFieldValues(defs, map[string]interface{}{
+		"license": "  ",
+		"contact": "Jane <jane@example.com>",
+		"site":    "example.com",
+		"tier":    "bronze",
+		"seats":   float64(250),
+		"extra":   "x",
+	})
+	fieldErr, ok := services.IsCustomFieldValidationError(err)
+	require.True(t, ok)
+
+	messages := make(map[string]string, len(fieldErr.Errors))
+	for _, fe := range fieldErr.Errors {
+		messages[fe.Key] = fe.Message
+	}
+	assert.Equal(t, map[string]string{
+		"license": "Liquor license is required",
+		"contact": "must be a valid email address",
+		"site":    "must be a valid URL",
+		"tier":    "must be one of: gold, silver",
+		"seats":   "must be at most 100",
+		"extra":   "unknown field for this onboarding step",
+	}, messages)
+}
+
+func TestParseCustomFieldQueryValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     models.CustomFieldDefinition
+		raw     string
+		want    interface{}
+		wantErr bool
+	}{
+		{"number", models.CustomFieldDefinition{Type: models.CustomFieldTypeNumber, Min: floatPtr(10)}, "5", float64(5), false},
+		{"invalid number", models.CustomFieldDefinition{Type: models.CustomFieldTypeNumber}, "five", nil, true},
+		{"date", models.CustomFieldDefinition{Type: models.CustomFieldTypeDate}, "2024-03-01", "2024-03-01", false},
+		{"text ignores pattern", models.CustomFieldDefinition{Type: models.CustomFieldTypeText, Pattern: `^\d+$`, MinLength: intPtr(5)}, "ab", "ab", false},
+		{"multiselect matches one option", models.CustomFieldDefinition{Type: models.CustomFieldTypeMultiSelect, Options: []string{"thai", "italian"}}, "thai", "thai", false},
+		{"unknown option", models.CustomFieldDefinition{Type: models.CustomFieldTypeSelect, Options: []string{"gold"}}, "bronze", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := services.ParseCustomFieldQueryValue(tt.def, tt.raw)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got, "query values skip min/max, length and pattern rules")
+		})
+	}
+}
+
+func TestTemplateCustomFieldsForStep(t *testing.T) {
+	template := &models.OnboardingTemplate{
+		CustomFields: models.JSONB(`[
+			{"key": "vat_number", "label": "VAT", "type": "text"},
+			{"key": "pickup_hours", "label": "Pickup hours", "type": "text", "step": "store_setup"}
+		]`),
+	}
+
+	bound, err := template.CustomFieldsForStep(models.CustomFieldStepBusinessInformation)
+	require.NoError(t, err)
+	require.Len(t, bound, 1, "fields without a step are collected on business information")
+	assert.Equal(t, "vat_number", bound[0].Key)
+
+	bound, err = template.CustomFieldsForStep("store_setup")
+	require.NoError(t, err)
+	require.Len(t, bound, 1)
+	assert.Equal(t, "pickup_hours", bound[0].Key)
+
+	template.CustomFields = models.JSONB(`{`)
+	_, err = template.CustomFieldDefinitions()
+	assert.Error(t, err)
+}
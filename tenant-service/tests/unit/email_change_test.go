@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestNormalizeNewEmail(t *testing.T) {
+	email, err := services.NormalizeNewEmail("old@example.com", "  New.Person@Example.COM ")
+	require.NoError(t, err)
+	assert.Equal(t, "new.person@example.com", email)
+
+	tests := []struct {
+		name      string
+		requested string
+	}{
+		{"same address", "old@example.com"},
+		{"same address in another case", " OLD@example.com"},
+		{"not an address", "not-an-email"},
+		{"display name", "Jane <jane@example.com>"},
+		{"empty", "   "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := services.NormalizeNewEmail("old@example.com", tt.requested)
+			validationErr, ok := services.IsValidationError(err)
+			require.True(t, ok, "expected a validation error, got %v", err)
+			assert.Equal(t, "new_email", validationErr.Field)
+		})
+	}
+}
+
+func TestEmailChangeToken_IsPending(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Minute)
+
+	assert.True(t, (&models.EmailChangeToken{ExpiresAt: now.Add(time.Hour)}).IsPending(now))
+	assert.False(t, (&models.EmailChangeToken{ExpiresAt: now}).IsPending(now), "expired")
+	assert.False(t, (&models.EmailChangeToken{ExpiresAt: now.Add(time.Hour), ConfirmedAt: &earlier}).IsPending(now), "already confirmed")
+	assert.False(t, (&models.EmailChangeToken{ExpiresAt: now.Add(time.Hour), CancelledAt: &earlier}).IsPending(now), "superseded by a newer request")
+}
@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"tenant-service/internal/handlers"
+	"tenant-service/internal/services"
+)
+
+func TestEmailDomain(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"jane@Acme.COM", "acme.com"},
+		{"jane+ops@eu.acme.com", "eu.acme.com"},
+		{`"a@b"@acme.com`, "acme.com"},
+		{"no-at-sign", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			assert.Equal(t, tt.want, services.EmailDomain(tt.email))
+		})
+	}
+}
+
+func TestIsPublicEmailDomain(t *testing.T) {
+	assert.True(t, services.IsPublicEmailDomain("gmail.com"))
+	assert.True(t, services.IsPublicEmailDomain("Outlook.com"))
+	assert.False(t, services.IsPublicEmailDomain("acme.com"))
+	assert.False(t, services.IsPublicEmailDomain("gmail.com.acme.io"))
+}
+
+func TestJoinByEmailDomain_RequiresVerifiedTokenEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// The service is never reached: every request below must be refused first
+	router.POST("/api/v1/auth/domain-join", handlers.NewJoinDomainHandler(nil).JoinByEmailDomain)
+	userID := uuid.New().String()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"legacy headers only", map[string]string{"X-User-ID": userID, "X-User-Email": "ceo@acme.com"}, http.StatusUnauthorized},
+		{"no email claim", map[string]string{"x-jwt-claim-sub": userID, "X-User-Email": "ceo@acme.com", "x-jwt-claim-email-verified": "true"}, http.StatusUnauthorized},
+		{"email not verified", map[string]string{"x-jwt-claim-sub": userID, "x-jwt-claim-email": "ceo@acme.com", "x-jwt-claim-email-verified": "false"}, http.StatusForbidden},
+		{"no email_verified claim", map[string]string{"x-jwt-claim-sub": userID, "x-jwt-claim-email": "ceo@acme.com"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/domain-join", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
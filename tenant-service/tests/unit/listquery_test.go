@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"tenant-service/internal/listquery"
+)
+
+type listQueryRow struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+}
+
+var testListQuery = listquery.Config{
+	Sorts:       map[string]string{"created_at": "created_at", "name": "name"},
+	DefaultSort: "-created_at",
+	Filters: map[string]listquery.Field{
+		"status":     {Column: "status", Ops: []string{listquery.OpEq, listquery.OpIn}},
+		"is_active":  {Column: "is_active", Type: listquery.TypeBool},
+		"created_at": {Column: "created_at", Type: listquery.TypeTime},
+	},
+}
+
+func dryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	return db
+}
+
+func TestListQuery_OffsetPagination(t *testing.T) {
+	q, err := listquery.Parse(url.Values{"page": {"3"}, "page_size": {"500"}}, testListQuery)
+	require.NoError(t, err)
+
+	assert.Equal(t, listquery.ModeOffset, q.Mode)
+	assert.Equal(t, 100, q.Limit, "limit is capped at the max")
+	assert.Equal(t, 200, q.Offset)
+	assert.Equal(t, []listquery.Sort{{Key: "created_at", Column: "created_at", Desc: true}}, q.Sorts)
+
+	meta := q.Meta(250, "")
+	assert.Equal(t, int64(3), meta.TotalPages)
+	assert.False(t, meta.HasMore)
+}
+
+func TestListQuery_FiltersAndSorts(t *testing.T) {
+	q, err := listquery.Parse(url.Values{
+		"status[in]":      {"active,pending"},
+		"is_active":       {"true"},
+		"created_at[gte]": {"2025-01-01"},
+		"sort":            {"name,-created_at"},
+		"unrelated":       {"ignored"},
+	}, testListQuery)
+	require.NoError(t, err)
+	assert.Len(t, q.Filters, 3)
+	assert.Len(t, q.Sorts, 2)
+
+	stmt := dryRunDB(t).Model(&listQueryRow{}).Scopes(q.FilterScope(), q.PageScope()).Find(&[]listQueryRow{}).Statement
+	sql := stmt.SQL.String()
+	assert.Contains(t, sql, "status IN ($")
+	assert.Contains(t, sql, "is_active = $")
+	assert.Contains(t, sql, "created_at >= $")
+	assert.Contains(t, sql, "ORDER BY name ASC,created_at DESC,id ASC")
+}
+
+func TestListQuery_RejectsInvalidParameters(t *testing.T) {
+	cases := []url.Values{
+		{"sort": {"password"}},
+		{"status[gt]": {"a"}},
+		{"secret[eq]": {"x"}},
+		{"is_active": {"maybe"}},
+		{"page": {"0"}},
+		{"cursor": {"not-a-cursor"}},
+	}
+	for _, values := range cases {
+		_, err := listquery.Parse(values, testListQuery)
+		_, ok := listquery.IsError(err)
+		assert.True(t, ok, "expected a query error for %v", values)
+	}
+}
+
+func TestListQuery_CursorPagination(t *testing.T) {
+	q, err := listquery.Parse(url.Values{"cursor": {""}, "limit": {"2"}}, testListQuery)
+	require.NoError(t, err)
+	assert.Equal(t, listquery.ModeCursor, q.Mode)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	rows := []listQueryRow{
+		{ID: uuid.New(), CreatedAt: now},
+		{ID: uuid.New(), CreatedAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), CreatedAt: now.Add(-2 * time.Minute)},
+	}
+	page, next, err := listquery.NextPage(q, rows)
+	require.NoError(t, err)
+	assert.Len(t, page, 2)
+	require.NotEmpty(t, next)
+	assert.True(t, q.Meta(3, next).HasMore)
+
+	q2, err := listquery.Parse(url.Values{"cursor": {next}, "limit": {"2"}}, testListQuery)
+	require.NoError(t, err)
+	stmt := dryRunDB(t).Model(&listQueryRow{}).Scopes(q2.PageScope()).Find(&[]listQueryRow{}).Statement
+	assert.Contains(t, stmt.SQL.String(), "(created_at < $1 OR (created_at = $2 AND id < $3))")
+	assert.Contains(t, stmt.SQL.String(), "LIMIT $4")
+	assert.Equal(t, rows[1].ID.String(), stmt.Vars[2])
+
+	// A cursor is only valid for the sort order it was issued for
+	_, err = listquery.Parse(url.Values{"cursor": {next}, "sort": {"name"}}, testListQuery)
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestLockoutSettings_Defaults(t *testing.T) {
+	var policy *models.TenantAuthPolicy
+	settings := policy.LockoutSettings()
+
+	assert.Equal(t, models.DefaultLockoutSettings(), settings)
+	assert.Equal(t, 5, settings.MaxAttempts)
+	assert.Equal(t, 48*time.Hour, settings.ResetAfter())
+}
+
+func TestLockoutSettings_FromPolicy(t *testing.T) {
+	policy := &models.TenantAuthPolicy{
+		MaxLoginAttempts:         3,
+		EnableProgressiveLockout: true,
+		Tier1LockoutMinutes:      15,
+		Tier2LockoutMinutes:      0, // unset keeps the default
+		Tier3LockoutMinutes:      120,
+		Tier4LockoutMinutes:      600,
+		LockoutResetHours:        12,
+	}
+	settings := policy.LockoutSettings()
+
+	assert.Equal(t, 3, settings.MaxAttempts)
+	assert.Equal(t, [models.LockoutTierCount]int{15, 60, 120, 600}, settings.TierMinutes)
+	assert.Equal(t, 12*time.Hour, settings.ResetAfter())
+}
+
+func TestLockoutSettings_LockoutFor(t *testing.T) {
+	settings := models.DefaultLockoutSettings()
+
+	tests := []struct {
+		totalFailed int
+		wantTier    int
+		wantLength  time.Duration
+	}{
+		{5, 1, 10 * time.Minute},
+		{9, 1, 10 * time.Minute},
+		{10, 2, time.Hour},
+		{15, 3, 6 * time.Hour},
+		{20, 4, 24 * time.Hour},
+		{100, 4, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		tier, length := settings.LockoutFor(tt.totalFailed)
+		assert.Equal(t, tt.wantTier, tier, "tier after %d failures", tt.totalFailed)
+		assert.Equal(t, tt.wantLength, length, "lockout after %d failures", tt.totalFailed)
+	}
+
+	settings.Progressive = false
+	tier, length := settings.LockoutFor(20)
+	assert.Equal(t, 1, tier)
+	assert.Equal(t, 10*time.Minute, length)
+}
+
+func TestLoginAttemptGuard_LocksPerEmailAndIP(t *testing.T) {
+	ctx := context.Background()
+	guard := services.NewLoginAttemptGuard(nil)
+	tenantID := uuid.New()
+	settings := models.LockoutSettings{MaxAttempts: 3, Progressive: true, TierMinutes: [models.LockoutTierCount]int{10, 60, 360, 1440}, ResetHours: 24}
+
+	status := guard.RecordFailure(ctx, tenantID, "user@example.com", "10.0.0.1", settings)
+	assert.False(t, status.Locked)
+	assert.Equal(t, 2, status.RemainingAttempts)
+
+	guard.RecordFailure(ctx, tenantID, "user@example.com", "10.0.0.1", settings)
+	status = guard.RecordFailure(ctx, tenantID, "User@Example.com", "10.0.0.1", settings)
+	require.True(t, status.Locked)
+	assert.Equal(t, 1, status.Tier)
+	require.NotNil(t, status.LockedUntil)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), *status.LockedUntil, time.Minute)
+
+	assert.True(t, guard.Status(ctx, tenantID, "user@example.com", "10.0.0.1", settings).Locked)
+	// Another IP, email or tenant is not affected
+	assert.False(t, guard.Status(ctx, tenantID, "user@example.com", "10.0.0.2", settings).Locked)
+	assert.False(t, guard.Status(ctx, tenantID, "other@example.com", "10.0.0.1", settings).Locked)
+	assert.False(t, guard.Status(ctx, uuid.New(), "user@example.com", "10.0.0.1", settings).Locked)
+}
+
+func TestLoginAttemptGuard_ResetAndClear(t *testing.T) {
+	ctx := context.Background()
+	guard := services.NewLoginAttemptGuard(nil)
+	tenantID := uuid.New()
+	settings := models.LockoutSettings{MaxAttempts: 2, Progressive: true, TierMinutes: [models.LockoutTierCount]int{10, 60, 360, 1440}, ResetHours: 24}
+
+	guard.RecordFailure(ctx, tenantID, "user@example.com", "10.0.0.1", settings)
+	guard.Reset(ctx, tenantID, "user@example.com", "10.0.0.1")
+	assert.Equal(t, 2, guard.Status(ctx, tenantID, "user@example.com", "10.0.0.1", settings).RemainingAttempts)
+
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		guard.RecordFailure(ctx, tenantID, "user@example.com", ip, settings)
+		require.True(t, guard.RecordFailure(ctx, tenantID, "user@example.com", ip, settings).Locked)
+	}
+	guard.ClearForEmail(ctx, tenantID, "USER@example.com")
+	assert.False(t, guard.Status(ctx, tenantID, "user@example.com", "10.0.0.1", settings).Locked)
+	assert.False(t, guard.Status(ctx, tenantID, "user@example.com", "10.0.0.2", settings).Locked)
+}
@@ -0,0 +1,101 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/middleware"
+	"tenant-service/internal/models"
+)
+
+type fakeMaintenanceChecker struct {
+	flags map[string]*models.MaintenanceFlag // keyed by tenant ID ("" = platform)
+	err   error
+}
+
+func (f *fakeMaintenanceChecker) ActiveMaintenance(ctx context.Context, tenantID string) (*models.MaintenanceFlag, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if flag := f.flags[""]; flag != nil {
+		return flag, nil
+	}
+	return f.flags[tenantID], nil
+}
+
+func newMaintenanceRouter(checker middleware.MaintenanceChecker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	group := router.Group("/api/v1")
+	group.Use(middleware.ReadOnlyMode(checker, "/api/v1/auth/validate"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	group.GET("/tenants/:id", ok)
+	group.POST("/tenants/:id/members/invite", ok)
+	group.POST("/auth/validate", ok)
+	return router
+}
+
+func TestReadOnlyMode_BlocksWritesForFlaggedTenant(t *testing.T) {
+	tenantID := uuid.New()
+	expiresAt := time.Now().Add(10 * time.Minute)
+	checker := &fakeMaintenanceChecker{flags: map[string]*models.MaintenanceFlag{
+		tenantID.String(): {Scope: models.MaintenanceScopeTenant, TenantID: &tenantID, Reason: "backfill", ExpiresAt: &expiresAt},
+	}}
+	router := newMaintenanceRouter(checker)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/tenants/"+tenantID.String()+"/members/invite", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, middleware.MaintenanceErrorCode, body["code"])
+	maintenance, ok := body["maintenance"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "tenant", maintenance["scope"])
+	assert.Equal(t, "backfill", maintenance["reason"])
+
+	// Other tenants keep writing
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/tenants/"+uuid.New().String()+"/members/invite", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReadOnlyMode_AllowsReadsAndExemptPaths(t *testing.T) {
+	checker := &fakeMaintenanceChecker{flags: map[string]*models.MaintenanceFlag{
+		"": {Scope: models.MaintenanceScopePlatform},
+	}}
+	router := newMaintenanceRouter(checker)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/tenants/"+uuid.New().String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/validate", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/tenants/"+uuid.New().String()+"/members/invite", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "300", w.Header().Get("Retry-After"))
+}
+
+func TestReadOnlyMode_FailsOpenOnLookupError(t *testing.T) {
+	router := newMaintenanceRouter(&fakeMaintenanceChecker{err: errors.New("redis down")})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/tenants/"+uuid.New().String()+"/members/invite", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,91 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/services"
+)
+
+func TestParseMemberImportCSV_ReadsColumnsByHeader(t *testing.T) {
+	csv := "\ufeffRole,Email,First Name,Last Name\n" +
+		"admin, Jane@Example.com ,Jane,Doe\n" +
+		"\n" +
+		",bob@example.com,Bob,\n"
+
+	rows, err := services.ParseMemberImportCSV(strings.NewReader(csv))
+
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, services.MemberImportRow{Email: "Jane@Example.com", Role: "admin", Name: "Jane Doe"}, rows[0])
+	assert.Equal(t, services.MemberImportRow{Email: "bob@example.com", Name: "Bob"}, rows[1])
+}
+
+func TestParseMemberImportCSV_RequiresEmailColumn(t *testing.T) {
+	_, err := services.ParseMemberImportCSV(strings.NewReader("name,role\nJane,admin\n"))
+
+	validationErr, ok := services.IsValidationError(err)
+	require.True(t, ok, "expected validation error, got %v", err)
+	assert.Equal(t, "file", validationErr.Field)
+}
+
+func TestParseMemberImportCSV_RejectsTooManyRows(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("email\n")
+	for i := 0; i <= services.MaxMemberImportRows; i++ {
+		fmt.Fprintf(&b, "staff%d@example.com\n", i)
+	}
+
+	_, err := services.ParseMemberImportCSV(strings.NewReader(b.String()))
+
+	validationErr, ok := services.IsValidationError(err)
+	require.True(t, ok, "expected validation error, got %v", err)
+	assert.Equal(t, "members", validationErr.Field)
+}
+
+func TestNormalizeMemberImportRow(t *testing.T) {
+	tests := []struct {
+		name      string
+		row       services.MemberImportRow
+		wantEmail string
+		wantRole  string
+		wantErr   bool
+	}{
+		{"defaults role to member", services.MemberImportRow{Email: " Ann@Example.com "}, "ann@example.com", "member", false},
+		{"normalizes role case", services.MemberImportRow{Email: "a@example.com", Role: "Manager"}, "a@example.com", "manager", false},
+		{"rejects owner role", services.MemberImportRow{Email: "a@example.com", Role: "owner"}, "a@example.com", "owner", true},
+		{"rejects missing email", services.MemberImportRow{Role: "admin"}, "", "", true},
+		{"rejects display-name address", services.MemberImportRow{Email: "Ann <ann@example.com>"}, "ann <ann@example.com>", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email, role, err := services.NormalizeMemberImportRow(tt.row)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantEmail, email)
+			assert.Equal(t, tt.wantRole, role)
+		})
+	}
+}
+
+func TestImportMembers_RejectsEmptyAndOversizedBatches(t *testing.T) {
+	svc := services.NewMembershipService(nil)
+
+	_, err := svc.ImportMembers(context.Background(), uuid.New(), uuid.New(), nil)
+	_, ok := services.IsValidationError(err)
+	assert.True(t, ok, "expected validation error, got %v", err)
+
+	rows := make([]services.MemberImportRow, services.MaxMemberImportRows+1)
+	_, err = svc.ImportMembers(context.Background(), uuid.New(), uuid.New(), rows)
+	_, ok = services.IsValidationError(err)
+	assert.True(t, ok, "expected validation error, got %v", err)
+}
@@ -0,0 +1,45 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestInvitationStatus(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		membership models.UserTenantMembership
+		want       string
+	}{
+		{"pending", models.UserTenantMembership{InvitationExpiresAt: &future}, models.InvitationStatusPending},
+		{"no expiry is pending", models.UserTenantMembership{}, models.InvitationStatusPending},
+		{"expired", models.UserTenantMembership{InvitationExpiresAt: &past}, models.InvitationStatusExpired},
+		{"expires exactly now", models.UserTenantMembership{InvitationExpiresAt: &now}, models.InvitationStatusExpired},
+		{"revoked before expiry", models.UserTenantMembership{InvitationExpiresAt: &future, InvitationRevokedAt: &past}, models.InvitationStatusRevoked},
+		{"revoked wins over expired", models.UserTenantMembership{InvitationExpiresAt: &past, InvitationRevokedAt: &past}, models.InvitationStatusRevoked},
+		{"accepted", models.UserTenantMembership{InvitationExpiresAt: &past, AcceptedAt: &past}, models.InvitationStatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.membership.InvitationStatus(now))
+		})
+	}
+}
+
+func TestInvitationCleanupInterval(t *testing.T) {
+	svc := services.NewMembershipService(nil)
+	assert.Equal(t, time.Hour, svc.InvitationCleanupInterval())
+
+	svc.SetInvitationConfig(config.InvitationConfig{CleanupIntervalMinutes: 15})
+	assert.Equal(t, 15*time.Minute, svc.InvitationCleanupInterval())
+}
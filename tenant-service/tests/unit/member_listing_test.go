@@ -0,0 +1,132 @@
+package unit
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/listquery"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+	"tenant-service/internal/services"
+)
+
+// expectUserRole expects the role lookup of GetUserRole; an empty role means no membership
+func expectUserRole(mock sqlmock.Sqlmock, userID, tenantID uuid.UUID, role string) {
+	rows := sqlmock.NewRows([]string{"role"})
+	if role != "" {
+		rows.AddRow(role)
+	}
+	mock.ExpectQuery(`SELECT "role" FROM "user_tenant_memberships" WHERE user_id = \$1 AND tenant_id = \$2 AND is_active = \$3`).
+		WithArgs(userID, tenantID, true, 1).
+		WillReturnRows(rows)
+	if role == "" {
+		mock.ExpectQuery(`SELECT \* FROM "tenant_users" WHERE keycloak_id = \$1`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	}
+}
+
+func newListQuery(t *testing.T, raw string, cfg listquery.Config) *listquery.Query {
+	t.Helper()
+	values, err := url.ParseQuery(raw)
+	require.NoError(t, err)
+	q, err := listquery.Parse(values, cfg)
+	require.NoError(t, err)
+	return q
+}
+
+func TestListMembers_RequiresMembership(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID, userID := uuid.New(), uuid.New()
+	expectUserRole(mock, userID, tenantID, "")
+
+	svc := services.NewMembershipService(repository.NewMembershipRepository(db))
+	_, _, _, err := svc.ListMembers(context.Background(), tenantID, userID, newListQuery(t, "", listquery.Config{}))
+	assert.ErrorIs(t, err, services.ErrNotTenantMember)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListMembers_EnrichesFromUsers(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID, userID := uuid.New(), uuid.New()
+	memberID := uuid.New()
+	expectUserRole(mock, userID, tenantID, models.MembershipRoleMember)
+
+	cfg := listquery.Config{
+		Sorts:   map[string]string{"created_at": "created_at"},
+		Filters: map[string]listquery.Field{"role": {Column: "role", Ops: []string{listquery.OpEq, listquery.OpIn}}},
+	}
+	q := newListQuery(t, "role[in]=admin,member&limit=10&sort=-created_at", cfg)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "user_tenant_memberships" WHERE \(tenant_id = \$1 AND user_id <> \$2\) AND role IN \(\$3,\$4\)`).
+		WithArgs(tenantID, uuid.Nil, "admin", "member").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT \* FROM "user_tenant_memberships" WHERE \(tenant_id = \$1 AND user_id <> \$2\) AND role IN \(\$3,\$4\) ORDER BY created_at DESC,id ASC LIMIT \$5`).
+		WithArgs(tenantID, uuid.Nil, "admin", "member", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "tenant_id", "role", "is_active"}).
+			AddRow(uuid.New(), memberID, tenantID, models.MembershipRoleAdmin, true))
+	mock.ExpectQuery(`SELECT \* FROM "tenant_users" WHERE id IN \(\$1\)`).
+		WithArgs(memberID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "first_name", "last_name"}).
+			AddRow(memberID, "ada@example.com", "Ada", "Lovelace"))
+
+	svc := services.NewMembershipService(repository.NewMembershipRepository(db))
+	members, total, next, err := svc.ListMembers(context.Background(), tenantID, userID, q)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, int64(1), total)
+	assert.Empty(t, next)
+	require.Len(t, members, 1)
+	assert.Equal(t, memberID, members[0].UserID)
+	assert.Equal(t, models.MembershipRoleAdmin, members[0].Role)
+	assert.Equal(t, "ada@example.com", members[0].Email)
+	assert.Equal(t, "Lovelace", members[0].LastName)
+}
+
+func TestListActivity_OwnersAndAdminsOnly(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID, userID := uuid.New(), uuid.New()
+	expectUserRole(mock, userID, tenantID, models.MembershipRoleMember)
+
+	svc := services.NewMembershipService(repository.NewMembershipRepository(db))
+	_, _, _, err := svc.ListActivity(context.Background(), tenantID, userID, newListQuery(t, "", listquery.Config{}))
+	assert.ErrorIs(t, err, services.ErrActivityLogForbidden)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListInvitations_FiltersStatusInQuery(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID, userID := uuid.New(), uuid.New()
+	expectUserRole(mock, userID, tenantID, models.MembershipRoleOwner)
+
+	// Paging happens in SQL, so the status condition must be part of the query
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "user_tenant_memberships" WHERE \(tenant_id = \$1 AND user_id = \$2 AND accepted_at IS NULL\) AND \(invitation_revoked_at IS NULL AND invitation_expires_at <= \$3\)`).
+		WithArgs(tenantID, uuid.Nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT \* FROM "user_tenant_memberships" WHERE .*invitation_expires_at <= \$3.* LIMIT \$4 OFFSET \$5`).
+		WithArgs(tenantID, uuid.Nil, sqlmock.AnyArg(), 5, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	svc := services.NewMembershipService(repository.NewMembershipRepository(db))
+	invitations, total, _, err := svc.ListInvitations(context.Background(), tenantID, userID, models.InvitationStatusExpired, newListQuery(t, "page=2&limit=5", listquery.Config{}))
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Empty(t, invitations)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestListInvitations_RejectsUnknownStatus(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID, userID := uuid.New(), uuid.New()
+	expectUserRole(mock, userID, tenantID, models.MembershipRoleAdmin)
+
+	svc := services.NewMembershipService(repository.NewMembershipRepository(db))
+	_, _, _, err := svc.ListInvitations(context.Background(), tenantID, userID, "accepted", newListQuery(t, "", listquery.Config{}))
+	_, ok := services.IsValidationError(err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,125 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+type fakeMembershipCacheStore struct {
+	entries map[string][]byte
+	reads   int
+}
+
+func (f *fakeMembershipCacheStore) GetMembershipCacheEntry(ctx context.Context, key string) ([]byte, error) {
+	f.reads++
+	return f.entries[key], nil
+}
+
+func (f *fakeMembershipCacheStore) SaveMembershipCacheEntry(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	f.entries[key] = data
+	return nil
+}
+
+func (f *fakeMembershipCacheStore) DeleteMembershipCacheEntries(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		delete(f.entries, key)
+	}
+	return nil
+}
+
+type fakeMembershipCachePublisher struct {
+	published [][]string
+}
+
+func (f *fakeMembershipCachePublisher) PublishMembershipCacheInvalidated(ctx context.Context, keys []string) error {
+	f.published = append(f.published, keys)
+	return nil
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+// The repository has no database here, so every lookup must be answered from the cache
+func newCachedMembershipRepo(t *testing.T, userID uuid.UUID, tenant *models.Tenant) (*repository.MembershipRepository, *fakeMembershipCacheStore) {
+	store := &fakeMembershipCacheStore{entries: map[string][]byte{
+		"slug:" + tenant.Slug:     mustJSON(t, tenant),
+		"user:" + userID.String(): mustJSON(t, []models.UserTenantMembership{{UserID: userID, TenantID: tenant.ID, Role: models.MembershipRoleOwner, IsActive: true, Tenant: tenant}}),
+	}}
+	repo := repository.NewMembershipRepository(nil)
+	repo.SetCache(store)
+	return repo, store
+}
+
+func TestMembershipCache_AccessAnsweredFromCache(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	tenant := &models.Tenant{ID: uuid.New(), Slug: "acme", DisplayName: "Acme", Status: "active"}
+	repo, _ := newCachedMembershipRepo(t, userID, tenant)
+
+	hasAccess, found, err := repo.HasAccessBySlug(ctx, userID, "acme")
+	require.NoError(t, err)
+	assert.True(t, hasAccess)
+	assert.Equal(t, tenant.ID, found.ID)
+
+	membership, err := repo.GetMembership(ctx, userID, tenant.ID)
+	require.NoError(t, err)
+	require.NotNil(t, membership)
+	assert.Equal(t, models.MembershipRoleOwner, membership.Role)
+	require.NotNil(t, membership.Tenant)
+	assert.Equal(t, "Acme", membership.Tenant.DisplayName)
+}
+
+func TestMembershipCache_LocalLayerUntilEvicted(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	tenant := &models.Tenant{ID: uuid.New(), Slug: "acme", DisplayName: "Acme", Status: "active"}
+	repo, store := newCachedMembershipRepo(t, userID, tenant)
+
+	_, err := repo.GetTenantBySlug(ctx, "acme")
+	require.NoError(t, err)
+
+	// Another instance renamed the tenant and refreshed Redis
+	store.entries["slug:acme"] = mustJSON(t, &models.Tenant{ID: tenant.ID, Slug: "acme", DisplayName: "Acme Ltd", Status: "active"})
+
+	cached, err := repo.GetTenantBySlug(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", cached.DisplayName, "served from memory")
+	assert.Equal(t, 1, store.reads)
+
+	repo.EvictCachedLookups([]string{"slug:acme"})
+
+	refreshed, err := repo.GetTenantBySlug(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Ltd", refreshed.DisplayName)
+}
+
+func TestMembershipCache_InvalidationClearsRedisAndPublishes(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	tenant := &models.Tenant{ID: uuid.New(), Slug: "acme", Status: "active"}
+	repo, store := newCachedMembershipRepo(t, userID, tenant)
+	publisher := &fakeMembershipCachePublisher{}
+	repo.SetCachePublisher(publisher)
+
+	repo.InvalidateUserMembershipsCache(ctx, userID, uuid.Nil)
+	assert.NotContains(t, store.entries, "user:"+userID.String())
+	assert.Contains(t, store.entries, "slug:acme")
+
+	repo.InvalidateDeletedTenantCache(ctx, "acme", []uuid.UUID{userID})
+	assert.NotContains(t, store.entries, "slug:acme")
+
+	require.Len(t, publisher.published, 2)
+	assert.Equal(t, []string{"user:" + userID.String()}, publisher.published[0])
+	assert.Equal(t, []string{"slug:acme", "user:" + userID.String()}, publisher.published[1])
+}
@@ -0,0 +1,101 @@
+package unit
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+// rfc6238Secret is the RFC 6238 SHA-1 test key "12345678901234567890" in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_MatchesRFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; the 6-digit code is the last six digits
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, v := range vectors {
+		code, err := services.TOTPCode(rfc6238Secret, time.Unix(v.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, v.code, code, "t=%d", v.unix)
+	}
+}
+
+func TestValidateTOTP_AcceptsAdjacentStepsOnly(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / 30
+
+	for _, offset := range []int64{-1, 0, 1} {
+		code, err := services.TOTPCode(rfc6238Secret, now.Add(time.Duration(offset)*services.TOTPPeriod))
+		require.NoError(t, err)
+		step, ok := services.ValidateTOTP(rfc6238Secret, code, now)
+		assert.True(t, ok, "offset %d", offset)
+		assert.Equal(t, current+offset, step)
+	}
+
+	old, err := services.TOTPCode(rfc6238Secret, now.Add(-3*services.TOTPPeriod))
+	require.NoError(t, err)
+	_, ok := services.ValidateTOTP(rfc6238Secret, old, now)
+	assert.False(t, ok)
+}
+
+func TestValidateTOTP_RejectsMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870821", "abcdef"} {
+		_, ok := services.ValidateTOTP(rfc6238Secret, code, now)
+		assert.False(t, ok, "code %q", code)
+	}
+	_, ok := services.ValidateTOTP("not base32!", "287082", now)
+	assert.False(t, ok)
+
+	_, ok = services.ValidateTOTP(rfc6238Secret, " 287082 ", now)
+	assert.True(t, ok)
+}
+
+func TestGenerateTOTPSecret_RoundTrips(t *testing.T) {
+	secret, err := services.GenerateTOTPSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	now := time.Now()
+	code, err := services.TOTPCode(secret, now)
+	require.NoError(t, err)
+	_, ok := services.ValidateTOTP(secret, code, now)
+	assert.True(t, ok)
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := services.TOTPProvisioningURI("Acme Store", "owner@acme.test", rfc6238Secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Acme%20Store:owner@acme.test?"))
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	q := parsed.Query()
+	assert.Equal(t, rfc6238Secret, q.Get("secret"))
+	assert.Equal(t, "Acme Store", q.Get("issuer"))
+	assert.Equal(t, "6", q.Get("digits"))
+	assert.Equal(t, "30", q.Get("period"))
+}
+
+func TestMFARequiredByPolicy(t *testing.T) {
+	roles := models.MustNewJSONB([]string{"owner", "admin"})
+
+	assert.False(t, services.MFARequiredByPolicy(nil, "owner"))
+	assert.True(t, services.MFARequiredByPolicy(&models.TenantAuthPolicy{MFARequired: true}, "customer"))
+	assert.True(t, services.MFARequiredByPolicy(&models.TenantAuthPolicy{MFARequiredForRoles: roles}, "Admin"))
+	assert.False(t, services.MFARequiredByPolicy(&models.TenantAuthPolicy{MFARequiredForRoles: roles}, "member"))
+	assert.False(t, services.MFARequiredByPolicy(&models.TenantAuthPolicy{MFARequiredForRoles: roles}, ""))
+}
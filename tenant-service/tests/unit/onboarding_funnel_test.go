@@ -0,0 +1,91 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestBuildOnboardingFunnel_OrdersStagesAndComputesDropOff(t *testing.T) {
+	rows := []models.OnboardingFunnelDaily{
+		{StageKey: models.FunnelStageCompleted, StageName: "Onboarding completed", StageOrder: models.FunnelStageCompletedOrder, Sessions: 20},
+		{StageKey: "step:contact_info", StageName: "Contact Information", StageOrder: 2, Sessions: 60},
+		{StageKey: models.FunnelStageStarted, StageName: "Session started", StageOrder: models.FunnelStageStartedOrder, Sessions: 100},
+		{StageKey: models.FunnelStageEmailVerified, StageName: "Email verified", StageOrder: models.FunnelStageEmailVerifiedOrder, Sessions: 40},
+		{StageKey: "step:business_info", StageName: "Business Information", StageOrder: 1, Sessions: 80},
+	}
+
+	funnel := services.BuildOnboardingFunnel(rows)
+
+	require.Len(t, funnel.Stages, 5)
+	keys := make([]string, 0, len(funnel.Stages))
+	for _, stage := range funnel.Stages {
+		keys = append(keys, stage.Key)
+	}
+	assert.Equal(t, []string{"started", "step:business_info", "step:contact_info", "email_verified", "completed"}, keys)
+
+	assert.Equal(t, int64(100), funnel.SessionsStarted)
+	assert.Equal(t, int64(20), funnel.SessionsCompleted)
+	assert.Equal(t, 0.2, funnel.CompletionRate)
+
+	assert.Equal(t, int64(0), funnel.Stages[0].DropOff)
+	assert.Equal(t, 1.0, funnel.Stages[0].ConversionRate)
+	assert.Equal(t, int64(20), funnel.Stages[1].DropOff)
+	assert.Equal(t, 0.2, funnel.Stages[1].DropOffRate)
+	assert.Equal(t, int64(20), funnel.Stages[2].DropOff)
+	assert.Equal(t, 0.25, funnel.Stages[2].DropOffRate)
+	assert.Equal(t, 0.6, funnel.Stages[2].ConversionRate)
+	assert.Equal(t, int64(20), funnel.Stages[4].DropOff)
+	assert.Equal(t, 0.5, funnel.Stages[4].DropOffRate)
+}
+
+func TestBuildOnboardingFunnel_NoDropOffWhenStageGrows(t *testing.T) {
+	rows := []models.OnboardingFunnelDaily{
+		{StageKey: models.FunnelStageStarted, StageOrder: models.FunnelStageStartedOrder, Sessions: 10},
+		{StageKey: "step:business_info", StageOrder: 1, Sessions: 3},
+		{StageKey: models.FunnelStageEmailVerified, StageOrder: models.FunnelStageEmailVerifiedOrder, Sessions: 5},
+	}
+
+	funnel := services.BuildOnboardingFunnel(rows)
+
+	require.Len(t, funnel.Stages, 3)
+	assert.Equal(t, int64(0), funnel.Stages[2].DropOff)
+	assert.Equal(t, 0.0, funnel.Stages[2].DropOffRate)
+}
+
+func TestBuildOnboardingFunnel_Empty(t *testing.T) {
+	funnel := services.BuildOnboardingFunnel(nil)
+
+	assert.NotNil(t, funnel.Stages)
+	assert.Empty(t, funnel.Stages)
+	assert.Equal(t, 0.0, funnel.CompletionRate)
+}
+
+func TestGetFunnel_ValidatesQuery(t *testing.T) {
+	svc := services.NewOnboardingAnalyticsService(nil, config.FunnelConfig{MaxRangeDays: 90})
+	day := time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name  string
+		query services.OnboardingFunnelQuery
+	}{
+		{"from after to", services.OnboardingFunnelQuery{From: day.AddDate(0, 0, 1), To: day}},
+		{"range too long", services.OnboardingFunnelQuery{From: day.AddDate(0, 0, -90), To: day}},
+		{"unknown application type", services.OnboardingFunnelQuery{From: day, To: day, ApplicationType: "crm"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.GetFunnel(context.Background(), tc.query)
+			require.Error(t, err)
+			_, ok := services.IsValidationError(err)
+			assert.True(t, ok, "expected a validation error, got %v", err)
+		})
+	}
+}
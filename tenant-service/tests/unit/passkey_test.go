@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestPasskeyPolicyFor(t *testing.T) {
+	roles, err := models.NewJSONB([]string{"owner", "admin"})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.PasskeyPolicyOff, services.PasskeyPolicyFor(nil, "owner"))
+	assert.Equal(t, models.PasskeyPolicyOff, services.PasskeyPolicyFor(&models.TenantAuthPolicy{PasskeyPolicy: models.PasskeyPolicyOff, PasskeyRequiredForRoles: roles}, "owner"))
+
+	required := &models.TenantAuthPolicy{PasskeyPolicy: models.PasskeyPolicyRequire, PasskeyRequiredForRoles: roles}
+	assert.Equal(t, models.PasskeyPolicyRequire, services.PasskeyPolicyFor(required, "Admin"))
+	assert.Equal(t, models.PasskeyPolicyOff, services.PasskeyPolicyFor(required, "member"))
+	assert.Equal(t, models.PasskeyPolicyOff, services.PasskeyPolicyFor(required, ""))
+
+	prefer := &models.TenantAuthPolicy{PasskeyPolicy: models.PasskeyPolicyPrefer, PasskeyRequiredForRoles: roles}
+	assert.Equal(t, models.PasskeyPolicyPrefer, services.PasskeyPolicyFor(prefer, "owner"))
+
+	// Unknown policy values and unreadable roles never enforce anything
+	assert.Equal(t, models.PasskeyPolicyOff, services.PasskeyPolicyFor(&models.TenantAuthPolicy{PasskeyPolicy: "always", PasskeyRequiredForRoles: roles}, "owner"))
+	assert.Equal(t, models.PasskeyPolicyOff, services.PasskeyPolicyFor(&models.TenantAuthPolicy{PasskeyPolicy: models.PasskeyPolicyRequire, PasskeyRequiredForRoles: models.JSONB(`{}`)}, "owner"))
+}
+
+func TestIsValidPasskeyPolicy(t *testing.T) {
+	assert.True(t, services.IsValidPasskeyPolicy("off"))
+	assert.True(t, services.IsValidPasskeyPolicy("prefer"))
+	assert.True(t, services.IsValidPasskeyPolicy("require"))
+	assert.False(t, services.IsValidPasskeyPolicy(""))
+	assert.False(t, services.IsValidPasskeyPolicy("Require"))
+}
+
+func TestPasskeyOrigins(t *testing.T) {
+	assert.Equal(t, []string{"https://acme-admin.tesserix.app"}, services.PasskeyOrigins("acme", "tesserix.app", nil))
+	assert.Equal(t,
+		[]string{"https://acme-admin.tesserix.app", "http://localhost:3000"},
+		services.PasskeyOrigins("acme", "tesserix.app", []string{" http://localhost:3000/ ", ""}))
+	assert.Empty(t, services.PasskeyOrigins("", "tesserix.app", nil))
+}
+
+func TestWebAuthnChallengeIsUsable(t *testing.T) {
+	now := time.Now()
+	challenge := &models.WebAuthnChallenge{Ceremony: models.WebAuthnCeremonyLogin, ExpiresAt: now.Add(time.Minute)}
+
+	assert.True(t, challenge.IsUsable(models.WebAuthnCeremonyLogin, now))
+	assert.False(t, challenge.IsUsable(models.WebAuthnCeremonyRegistration, now))
+	assert.False(t, challenge.IsUsable(models.WebAuthnCeremonyLogin, now.Add(2*time.Minute)))
+}
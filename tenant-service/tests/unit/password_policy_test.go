@@ -0,0 +1,155 @@
+package unit
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+type stubBreachChecker struct {
+	breached bool
+	err      error
+}
+
+func (s stubBreachChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	return s.breached, s.err
+}
+
+func strictPasswordPolicy() *models.TenantAuthPolicy {
+	return &models.TenantAuthPolicy{
+		PasswordMinLength:           10,
+		PasswordMaxLength:           64,
+		PasswordRequireUppercase:    true,
+		PasswordRequireLowercase:    true,
+		PasswordRequireNumbers:      true,
+		PasswordRequireSpecialChars: true,
+		PasswordSpecialChars:        "!@#",
+	}
+}
+
+func TestPasswordPolicyCheckPassword_EnforcesComplexity(t *testing.T) {
+	svc := services.NewPasswordPolicyService(nil, nil)
+	policy := strictPasswordPolicy()
+
+	tests := []struct {
+		name     string
+		password string
+		wantMsg  string
+	}{
+		{"too short", "Ab1!", "at least 10 characters"},
+		{"too long", "Ab1!" + strings.Repeat("x", 61), "at most 64 characters"},
+		{"no uppercase", "abcdefgh1!", "uppercase"},
+		{"no lowercase", "ABCDEFGH1!", "lowercase"},
+		{"no number", "Abcdefghi!", "number"},
+		{"special char not in policy set", "Abcdefgh1$", "special character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.CheckPassword(context.Background(), policy, tt.password)
+			validationErr, ok := services.IsValidationError(err)
+			require.True(t, ok, "expected validation error, got %v", err)
+			assert.Equal(t, "password", validationErr.Field)
+			assert.Contains(t, validationErr.Message, tt.wantMsg)
+		})
+	}
+
+	assert.NoError(t, svc.CheckPassword(context.Background(), policy, "Abcdefgh1#"))
+}
+
+func TestPasswordPolicyCheckPassword_BreachCheck(t *testing.T) {
+	policy := strictPasswordPolicy()
+	policy.PasswordCheckBreached = true
+
+	breached := services.NewPasswordPolicyService(nil, stubBreachChecker{breached: true})
+	_, ok := services.IsValidationError(breached.CheckPassword(context.Background(), policy, "Abcdefgh1#"))
+	assert.True(t, ok, "breached password should be rejected")
+
+	// Lookup failures must not block password changes
+	unavailable := services.NewPasswordPolicyService(nil, stubBreachChecker{err: errors.New("timeout")})
+	assert.NoError(t, unavailable.CheckPassword(context.Background(), policy, "Abcdefgh1#"))
+
+	policy.PasswordCheckBreached = false
+	assert.NoError(t, breached.CheckPassword(context.Background(), policy, "Abcdefgh1#"))
+}
+
+func TestHIBPChecker_UsesKAnonymityRange(t *testing.T) {
+	sum := sha1.Sum([]byte("password123"))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	var requestedPath, padding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		padding = r.Header.Get("Add-Padding")
+		fmt.Fprintf(w, "0000000000000000000000000000000000A:0\r\n%s:42\r\n", hash[5:])
+	}))
+	defer server.Close()
+
+	checker := services.NewHIBPChecker(server.URL+"/range", time.Second)
+	breached, err := checker.IsBreached(context.Background(), "password123")
+	require.NoError(t, err)
+	assert.True(t, breached)
+	assert.Equal(t, "/range/"+hash[:5], requestedPath, "only the hash prefix may be sent")
+	assert.Equal(t, "true", padding)
+
+	breached, err = checker.IsBreached(context.Background(), "a-password-not-in-the-range")
+	require.NoError(t, err)
+	assert.False(t, breached)
+}
+
+func TestPasswordPolicyUpdate_ValidatesRequest(t *testing.T) {
+	svc := services.NewPasswordPolicyService(nil, nil)
+	intPtr := func(v int) *int { return &v }
+	strPtr := func(v string) *string { return &v }
+
+	tests := []struct {
+		name      string
+		req       services.UpdatePasswordPolicyRequest
+		wantField string
+	}{
+		{"min length below floor", services.UpdatePasswordPolicyRequest{MinLength: intPtr(6)}, "min_length"},
+		{"max length above ceiling", services.UpdatePasswordPolicyRequest{MaxLength: intPtr(1000)}, "max_length"},
+		{"max below min", services.UpdatePasswordPolicyRequest{MinLength: intPtr(20), MaxLength: intPtr(12)}, "max_length"},
+		{"negative rotation", services.UpdatePasswordPolicyRequest{RotationDays: intPtr(-1)}, "rotation_days"},
+		{"rotation too long", services.UpdatePasswordPolicyRequest{RotationDays: intPtr(1000)}, "rotation_days"},
+		{"empty special chars", services.UpdatePasswordPolicyRequest{SpecialChars: strPtr("")}, "special_chars"},
+		{"letters as special chars", services.UpdatePasswordPolicyRequest{SpecialChars: strPtr("!a")}, "special_chars"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.UpdatePolicy(context.Background(), uuid.New(), uuid.New(), tt.req)
+			validationErr, ok := services.IsValidationError(err)
+			require.True(t, ok, "expected validation error, got %v", err)
+			assert.Equal(t, tt.wantField, validationErr.Field)
+		})
+	}
+}
+
+func TestPasswordChangeRequired(t *testing.T) {
+	now := time.Now()
+	days := 90
+	policy := &models.TenantAuthPolicy{PasswordExpiryDays: &days}
+	recent := now.AddDate(0, 0, -10)
+	stale := now.AddDate(0, 0, -91)
+
+	assert.False(t, services.PasswordChangeRequired(policy, nil, now))
+	assert.False(t, services.PasswordChangeRequired(nil, &models.TenantCredential{PasswordSetAt: stale}, now))
+	assert.False(t, services.PasswordChangeRequired(policy, &models.TenantCredential{PasswordSetAt: recent}, now))
+	assert.True(t, services.PasswordChangeRequired(policy, &models.TenantCredential{PasswordSetAt: stale}, now))
+	// The last change wins over the original set time
+	assert.False(t, services.PasswordChangeRequired(policy, &models.TenantCredential{PasswordSetAt: stale, LastPasswordChangeAt: &recent}, now))
+	// An explicit rotation flag applies even without an interval
+	assert.True(t, services.PasswordChangeRequired(nil, &models.TenantCredential{PasswordSetAt: recent, PasswordRotationRequired: true}, now))
+}
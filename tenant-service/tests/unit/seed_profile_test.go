@@ -0,0 +1,167 @@
+package unit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/seeds"
+	"tenant-service/internal/services"
+)
+
+func writeSeedProfile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".json"), []byte(content), 0o644))
+}
+
+func TestSeedProfile_BuiltInBase(t *testing.T) {
+	profile, err := seeds.NewLoader("").Load(seeds.BaseProfile)
+	require.NoError(t, err)
+
+	assert.Equal(t, seeds.BaseProfile, profile.Name)
+	require.Len(t, profile.Templates, 1)
+	assert.Equal(t, "ecommerce", profile.Templates[0].ApplicationType)
+	assert.True(t, profile.Templates[0].IsDefault)
+	assert.True(t, profile.Templates[0].IsActive())
+
+	slugs := make(map[string]bool)
+	for _, s := range profile.ReservedSlugs {
+		slugs[s.Slug] = s.IsActive()
+	}
+	assert.True(t, slugs["admin"])
+	assert.True(t, slugs["www"])
+	assert.True(t, slugs["tesserix"])
+}
+
+func TestSeedProfile_ExtendsAndOverrides(t *testing.T) {
+	dir := t.TempDir()
+	writeSeedProfile(t, dir, "development", `{
+		"extends": "base",
+		"templates": [
+			{"key": "saas-default", "name": "SaaS Setup", "application_type": "saas", "is_default": true, "steps": []}
+		],
+		"reserved_slugs": [
+			{"slug": "demo", "reason": "Demo stores are allowed in development", "category": "system", "active": false},
+			{"slug": "sandbox", "reason": "Sandbox environment", "category": "infrastructure"}
+		]
+	}`)
+
+	base, err := seeds.NewLoader("").Load(seeds.BaseProfile)
+	require.NoError(t, err)
+	profile, err := seeds.NewLoader(dir).Load("development")
+	require.NoError(t, err)
+
+	assert.Equal(t, "development", profile.Name)
+	require.Len(t, profile.Templates, 2)
+	assert.Equal(t, "ecommerce-default", profile.Templates[0].Key)
+	assert.Equal(t, "saas-default", profile.Templates[1].Key)
+
+	// Overrides keep the parent's position; additions come last
+	assert.Len(t, profile.ReservedSlugs, len(base.ReservedSlugs)+1)
+	for i, s := range base.ReservedSlugs {
+		assert.Equal(t, s.Slug, profile.ReservedSlugs[i].Slug)
+		if s.Slug == "demo" {
+			assert.False(t, profile.ReservedSlugs[i].IsActive())
+		}
+	}
+	assert.Equal(t, "sandbox", profile.ReservedSlugs[len(profile.ReservedSlugs)-1].Slug)
+	assert.NotEqual(t, base.Checksum(), profile.Checksum())
+}
+
+func TestSeedProfile_DirectoryOverridesBuiltIn(t *testing.T) {
+	dir := t.TempDir()
+	writeSeedProfile(t, dir, "base", `{"reserved_slugs": [{"slug": "admin", "reason": "Admin", "category": "system"}]}`)
+
+	profile, err := seeds.NewLoader(dir).Load(seeds.BaseProfile)
+	require.NoError(t, err)
+	assert.Empty(t, profile.Templates)
+	assert.Len(t, profile.ReservedSlugs, 1)
+}
+
+func TestSeedProfile_LoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeSeedProfile(t, dir, "loop-a", `{"extends": "loop-b"}`)
+	writeSeedProfile(t, dir, "loop-b", `{"extends": "loop-a"}`)
+	writeSeedProfile(t, dir, "broken", `{"templates": [`)
+	writeSeedProfile(t, dir, "two-defaults", `{"templates": [
+		{"key": "a", "name": "A", "application_type": "ecommerce", "is_default": true},
+		{"key": "b", "name": "B", "application_type": "ecommerce", "is_default": true}
+	]}`)
+	writeSeedProfile(t, dir, "retired-default", `{"templates": [
+		{"key": "a", "name": "A", "application_type": "ecommerce", "is_default": true},
+		{"key": "b", "name": "B", "application_type": "ecommerce", "is_default": true, "active": false}
+	]}`)
+	writeSeedProfile(t, dir, "bad-slug", `{"reserved_slugs": [{"slug": "Not A Slug", "reason": "x", "category": "system"}]}`)
+	writeSeedProfile(t, dir, "bad-steps", `{"templates": [{"key": "a", "name": "A", "application_type": "saas", "steps": {}}]}`)
+
+	loader := seeds.NewLoader(dir)
+
+	_, err := loader.Load("production-eu")
+	assert.ErrorIs(t, err, seeds.ErrProfileNotFound)
+	_, err = loader.Load("../base")
+	assert.ErrorIs(t, err, seeds.ErrProfileNotFound)
+
+	for _, name := range []string{"loop-a", "broken", "two-defaults", "bad-slug", "bad-steps"} {
+		_, err := loader.Load(name)
+		assert.ErrorIs(t, err, seeds.ErrInvalidProfile, name)
+	}
+
+	// Only active templates compete for the default
+	_, err = loader.Load("retired-default")
+	assert.NoError(t, err)
+}
+
+func TestSeedProfile_ChecksumIgnoresFormatting(t *testing.T) {
+	a := seeds.TemplateSeed{Key: "k", Name: "N", ApplicationType: "saas",
+		TemplateConfig: json.RawMessage(`{"a": 1, "b": [1, 2]}`)}
+	b := seeds.TemplateSeed{Key: "k", Name: "N", ApplicationType: "saas",
+		TemplateConfig: json.RawMessage(`{"b":[1,2],"a":1}`)}
+	assert.Equal(t, a.Checksum(), b.Checksum())
+
+	b.TemplateConfig = json.RawMessage(`{"b":[2,1],"a":1}`)
+	assert.NotEqual(t, a.Checksum(), b.Checksum())
+
+	active := false
+	slug := seeds.ReservedSlugSeed{Slug: "demo", Reason: "Demo", Category: "system"}
+	retired := slug
+	retired.Active = &active
+	assert.NotEqual(t, slug.Checksum(), retired.Checksum())
+}
+
+func TestTemplateSeedChanges(t *testing.T) {
+	seed := seeds.TemplateSeed{
+		Key:             "ecommerce-default",
+		Name:            "E-commerce Store Setup",
+		ApplicationType: "ecommerce",
+		IsDefault:       true,
+		TemplateConfig:  json.RawMessage(`{"trial_period_days": 14}`),
+		Steps:           json.RawMessage(`[{"id": "store-setup"}]`),
+	}
+
+	template := seed.Model()
+	assert.Empty(t, services.TemplateSeedChanges(seed, template))
+
+	// Missing custom fields match the empty default however they are stored
+	template.CustomFields = nil
+	assert.Empty(t, services.TemplateSeedChanges(seed, template))
+
+	template.Steps = models.JSONB(`[{"id": "store-setup"}, {"id": "payments"}]`)
+	template.IsActive = false
+	template.Description = "Edited by an admin"
+	assert.Equal(t, []string{"description", "steps", "is_active"}, services.TemplateSeedChanges(seed, template))
+}
+
+func TestReservedSlugSeedChanges(t *testing.T) {
+	active := false
+	seed := seeds.ReservedSlugSeed{Slug: "demo", Reason: "Reserved for demos", Category: "system", Active: &active}
+
+	reserved := &models.ReservedSlug{Slug: "demo", Reason: "Reserved for demos", Category: "system", IsActive: true}
+	assert.Equal(t, []string{"is_active"}, services.ReservedSlugSeedChanges(seed, reserved))
+
+	reserved.IsActive = false
+	assert.Empty(t, services.ReservedSlugSeedChanges(seed, reserved))
+}
@@ -0,0 +1,101 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tesseract-Nexus/go-shared/auth"
+	"github.com/stretchr/testify/assert"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestSessionSettings_Defaults(t *testing.T) {
+	var policy *models.TenantAuthPolicy
+	settings := policy.SessionSettings()
+
+	assert.Equal(t, models.DefaultSessionSettings(), settings)
+	assert.Equal(t, 8*time.Hour, settings.Lifetime(false))
+	assert.Equal(t, 8*time.Hour, settings.Lifetime(true), "remember-me is disabled by default")
+	assert.Zero(t, settings.IdleTimeout())
+}
+
+func TestSessionSettings_FromPolicy(t *testing.T) {
+	policy := &models.TenantAuthPolicy{
+		SessionTimeoutMinutes:     60,
+		SessionIdleTimeoutMinutes: 15,
+		SessionRememberMeDays:     14,
+	}
+	settings := policy.SessionSettings()
+
+	assert.Equal(t, time.Hour, settings.Lifetime(false))
+	assert.Equal(t, 14*24*time.Hour, settings.Lifetime(true))
+	assert.Equal(t, 15*time.Minute, settings.IdleTimeout())
+}
+
+func TestApplySessionPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    models.SessionSettings
+		rememberMe  bool
+		tokens      auth.TokenResponse
+		wantAccess  int
+		wantRefresh int
+	}{
+		{
+			name:        "idle timeout caps refresh token",
+			settings:    models.SessionSettings{AbsoluteMinutes: 480, IdleMinutes: 15},
+			tokens:      auth.TokenResponse{ExpiresIn: 300, RefreshExpiresIn: 1800},
+			wantAccess:  300,
+			wantRefresh: 900,
+		},
+		{
+			name:        "absolute lifetime caps refresh and access tokens",
+			settings:    models.SessionSettings{AbsoluteMinutes: 5},
+			tokens:      auth.TokenResponse{ExpiresIn: 600, RefreshExpiresIn: 1800},
+			wantAccess:  300,
+			wantRefresh: 300,
+		},
+		{
+			name:        "shorter realm lifetime is kept",
+			settings:    models.SessionSettings{AbsoluteMinutes: 480},
+			tokens:      auth.TokenResponse{ExpiresIn: 300, RefreshExpiresIn: 1800},
+			wantAccess:  300,
+			wantRefresh: 1800,
+		},
+		{
+			name:        "missing refresh lifetime uses the session lifetime",
+			settings:    models.SessionSettings{AbsoluteMinutes: 60, RememberMeDays: 7},
+			rememberMe:  true,
+			tokens:      auth.TokenResponse{ExpiresIn: 300},
+			wantAccess:  300,
+			wantRefresh: 7 * 24 * 3600,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := tt.tokens
+			services.ApplySessionPolicy(&tokens, tt.settings, tt.rememberMe)
+			assert.Equal(t, tt.wantAccess, tokens.ExpiresIn)
+			assert.Equal(t, tt.wantRefresh, tokens.RefreshExpiresIn)
+		})
+	}
+}
+
+func TestTenantAuthSession_IdleTimeout(t *testing.T) {
+	now := time.Now()
+	session := models.TenantAuthSession{
+		LastSeenAt:         now.Add(-10 * time.Minute),
+		ExpiresAt:          now.Add(time.Hour),
+		IdleTimeoutMinutes: 15,
+	}
+
+	assert.True(t, session.IsActive(now))
+	assert.Equal(t, now.Add(5*time.Minute), session.IdleExpiresAt())
+	assert.False(t, session.IsActive(now.Add(5*time.Minute)), "idle for the full timeout")
+	assert.True(t, session.IsIdle(now.Add(6*time.Minute)))
+
+	session.IdleTimeoutMinutes = 0
+	assert.True(t, session.IsActive(now.Add(30*time.Minute)), "no idle timeout")
+	assert.True(t, session.IdleExpiresAt().IsZero())
+}
@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+)
+
+func TestTenantAuthSession_IsActive(t *testing.T) {
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		session models.TenantAuthSession
+		want    bool
+	}{
+		{"active", models.TenantAuthSession{ExpiresAt: now.Add(time.Hour)}, true},
+		{"expired", models.TenantAuthSession{ExpiresAt: now.Add(-time.Second)}, false},
+		{"expires now", models.TenantAuthSession{ExpiresAt: now}, false},
+		{"revoked", models.TenantAuthSession{ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.session.IsActive(now))
+		})
+	}
+}
+
+func TestTenantAuthSession_JSONOmitsOwnerSubject(t *testing.T) {
+	session := models.TenantAuthSession{
+		ID:             "kc-session-1",
+		TenantID:       uuid.New(),
+		Subject:        "keycloak-user-1",
+		IPAddress:      "203.0.113.7",
+		ExpiresAt:      time.Now().Add(time.Hour),
+		CurrentSession: true,
+	}
+
+	data, err := json.Marshal(session)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.NotContains(t, fields, "subject")
+	assert.Equal(t, "kc-session-1", fields["id"])
+	assert.Equal(t, true, fields["current"])
+}
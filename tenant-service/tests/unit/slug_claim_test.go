@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"tenant-service/internal/models"
+	"tenant-service/internal/repository"
+)
+
+func pendingReservation(sessionID uuid.UUID, expiresAt time.Time) *models.TenantSlugReservation {
+	return &models.TenantSlugReservation{
+		Slug:      "acme",
+		Status:    models.SlugReservationPending,
+		SessionID: &sessionID,
+		ExpiresAt: &expiresAt,
+	}
+}
+
+func TestEvaluateSlugReservation(t *testing.T) {
+	now := time.Now()
+	mine, other := uuid.New(), uuid.New()
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name     string
+		existing *models.TenantSlugReservation
+		want     repository.SlugClaimAction
+	}{
+		{"never reserved", nil, repository.SlugClaimCreate},
+		{"released", &models.TenantSlugReservation{Status: models.SlugReservationReleased, SessionID: &other}, repository.SlugClaimTakeover},
+		{"own pending hold", pendingReservation(mine, now.Add(time.Minute)), repository.SlugClaimRenew},
+		{"own expired hold", pendingReservation(mine, now.Add(-time.Minute)), repository.SlugClaimRenew},
+		{"other pending hold", pendingReservation(other, now.Add(time.Minute)), repository.SlugClaimReject},
+		{"other expired hold", pendingReservation(other, now.Add(-time.Minute)), repository.SlugClaimTakeover},
+		{"own active", &models.TenantSlugReservation{Status: models.SlugReservationActive, SessionID: &mine, TenantID: &tenantID}, repository.SlugClaimNoop},
+		{"other active", &models.TenantSlugReservation{Status: models.SlugReservationActive, SessionID: &other, TenantID: &tenantID}, repository.SlugClaimReject},
+		{"active without session", &models.TenantSlugReservation{Status: models.SlugReservationActive, TenantID: &tenantID}, repository.SlugClaimReject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, repository.EvaluateSlugReservation(tt.existing, mine, now))
+		})
+	}
+}
+
+func TestEvaluateSlugActivation(t *testing.T) {
+	now := time.Now()
+	mine, other := uuid.New(), uuid.New()
+	tenantID, otherTenant := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name      string
+		existing  *models.TenantSlugReservation
+		sessionID *uuid.UUID
+		want      repository.SlugClaimAction
+	}{
+		{"never reserved", nil, &mine, repository.SlugClaimCreate},
+		{"own pending hold", pendingReservation(mine, now.Add(time.Minute)), &mine, repository.SlugClaimTakeover},
+		{"other pending hold", pendingReservation(other, now.Add(time.Minute)), &mine, repository.SlugClaimReject},
+		{"other pending hold without session", pendingReservation(other, now.Add(time.Minute)), nil, repository.SlugClaimReject},
+		{"other expired hold", pendingReservation(other, now.Add(-time.Minute)), &mine, repository.SlugClaimTakeover},
+		{"already active for tenant", &models.TenantSlugReservation{Status: models.SlugReservationActive, TenantID: &tenantID}, &mine, repository.SlugClaimNoop},
+		{"active for another tenant", &models.TenantSlugReservation{Status: models.SlugReservationActive, TenantID: &otherTenant}, nil, repository.SlugClaimReject},
+		{"released", &models.TenantSlugReservation{Status: models.SlugReservationReleased}, nil, repository.SlugClaimTakeover},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, repository.EvaluateSlugActivation(tt.existing, tt.sessionID, tenantID, now))
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package unit
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/clients"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+// staticStaffDirectory returns a fixed staff list for every tenant
+type staticStaffDirectory []clients.StaffMemberInfo
+
+func (d staticStaffDirectory) ListTenantStaff(ctx context.Context, tenantID uuid.UUID) ([]clients.StaffMemberInfo, error) {
+	return d, nil
+}
+
+var membershipColumns = []string{"id", "user_id", "tenant_id", "role", "is_active", "invitation_token"}
+
+// expectStaffUsers expects the Keycloak ID lookup and maps keycloak IDs to local user IDs
+func expectStaffUsers(mock sqlmock.Sqlmock, users map[uuid.UUID]uuid.UUID) {
+	rows := sqlmock.NewRows([]string{"id", "keycloak_id"})
+	for keycloakID, userID := range users {
+		rows.AddRow(userID, keycloakID)
+	}
+	mock.ExpectQuery(`SELECT "id","keycloak_id" FROM "tenant_users" WHERE keycloak_id IN`).
+		WillReturnRows(rows)
+}
+
+// notNullArg matches any non-NULL argument
+type notNullArg struct{}
+
+func (notNullArg) Match(v driver.Value) bool {
+	return v != nil
+}
+
+func conflictKinds(conflicts []models.MembershipSyncConflict) map[uuid.UUID]string {
+	kinds := make(map[uuid.UUID]string, len(conflicts))
+	for _, c := range conflicts {
+		kinds[c.StaffID] = c.Kind
+	}
+	return kinds
+}
+
+func TestStaffSync_DryRunReportsDrift(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID := uuid.New()
+
+	missingStaff, missingKC, missingUser := uuid.New(), uuid.New(), uuid.New()
+	mismatchStaff, mismatchKC := uuid.New(), uuid.New()
+	deactivatedStaff, deactivatedKC, deactivatedUser := uuid.New(), uuid.New(), uuid.New()
+	leftStaff, leftKC, leftUser := uuid.New(), uuid.New(), uuid.New()
+	unlinkedStaff := uuid.New()
+	orphanUser := uuid.New()
+
+	staff := staticStaffDirectory{
+		{ID: missingStaff, KeycloakUserID: missingKC.String(), IsActive: true, Role: "staff"},
+		// No tenant_users row: the Keycloak ID is the membership user ID
+		{ID: mismatchStaff, KeycloakUserID: mismatchKC.String(), IsActive: true, Role: "store_admin"},
+		{ID: deactivatedStaff, KeycloakUserID: deactivatedKC.String(), IsActive: true, Role: "staff"},
+		{ID: leftStaff, KeycloakUserID: leftKC.String(), IsActive: false, AccountStatus: "suspended", Role: "staff"},
+		{ID: unlinkedStaff, Email: "new@example.com", IsActive: true, Role: "staff"},
+		{ID: uuid.New(), IsActive: false, Role: "staff"},
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "user_tenant_memberships" WHERE tenant_id = \$1`).
+		WithArgs(tenantID).
+		WillReturnRows(sqlmock.NewRows(membershipColumns).
+			AddRow(uuid.New(), mismatchKC, tenantID, models.MembershipRoleMember, true, "").
+			AddRow(uuid.New(), deactivatedUser, tenantID, models.MembershipRoleMember, false, "").
+			AddRow(uuid.New(), leftUser, tenantID, models.MembershipRoleMember, true, "").
+			AddRow(uuid.New(), orphanUser, tenantID, models.MembershipRoleViewer, true, "").
+			AddRow(uuid.New(), uuid.Nil, tenantID, models.MembershipRoleMember, true, "pending-invite"))
+	expectStaffUsers(mock, map[uuid.UUID]uuid.UUID{
+		missingKC:     missingUser,
+		deactivatedKC: deactivatedUser,
+		leftKC:        leftUser,
+	})
+
+	svc := services.NewStaffMembershipSyncService(db, staff, config.StaffSyncConfig{CreateMissing: true})
+	result, err := svc.ReconcileTenant(context.Background(), tenantID, true)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet(), "a dry run writes nothing")
+
+	assert.True(t, result.DryRun)
+	assert.False(t, result.CreateMissing, "dry runs never create memberships")
+	assert.Equal(t, 6, result.StaffChecked)
+	assert.Equal(t, 0, result.MembershipsCreated)
+	assert.Equal(t, 1, result.Drift["missing_membership"])
+	assert.Equal(t, 1, result.Drift["membership_without_staff"], "invitations are not drift")
+
+	require.Len(t, result.Tenants, 1)
+	assert.Equal(t, map[uuid.UUID]string{
+		mismatchStaff:    models.SyncConflictRoleMismatch,
+		deactivatedStaff: models.SyncConflictMembershipInactive,
+		leftStaff:        models.SyncConflictStaffInactive,
+		unlinkedStaff:    models.SyncConflictUnlinkedStaff,
+	}, conflictKinds(result.Tenants[0].Conflicts))
+
+	for _, c := range result.Tenants[0].Conflicts {
+		switch c.Kind {
+		case models.SyncConflictRoleMismatch:
+			assert.Equal(t, models.MembershipRoleMember, c.MembershipRole)
+			assert.Equal(t, `staff role "store_admin" maps to "admin" but membership role is "member"`, c.Details)
+		case models.SyncConflictStaffInactive:
+			assert.Equal(t, "staff member is suspended but their membership is still active", c.Details)
+		case models.SyncConflictUnlinkedStaff:
+			assert.Nil(t, c.UserID)
+		}
+	}
+}
+
+func TestStaffSync_CreatesMissingMembership(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID := uuid.New()
+	staffID, keycloakID, userID := uuid.New(), uuid.New(), uuid.New()
+
+	staff := staticStaffDirectory{
+		{ID: staffID, KeycloakUserID: keycloakID.String(), IsActive: true, Role: "store_manager"},
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "user_tenant_memberships" WHERE tenant_id = \$1`).
+		WithArgs(tenantID).
+		WillReturnRows(sqlmock.NewRows(membershipColumns))
+	expectStaffUsers(mock, map[uuid.UUID]uuid.UUID{keycloakID: userID})
+
+	// Created for the local user with the mapped role, already accepted
+	mock.ExpectQuery(`INSERT INTO "user_tenant_memberships" \("user_id","tenant_id","role","is_default","is_active",`).
+		WithArgs(userID, tenantID, models.MembershipRoleManager, false, true,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), notNullArg{},
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+
+	// No conflicts: the run only resolves conflicts from earlier runs
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "membership_sync_conflicts" SET "resolved_at"=\$1 WHERE tenant_id = \$2 AND resolved_at IS NULL AND last_seen_at < \$3`).
+		WithArgs(sqlmock.AnyArg(), tenantID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	svc := services.NewStaffMembershipSyncService(db, staff, config.StaffSyncConfig{CreateMissing: true})
+	result, err := svc.ReconcileTenant(context.Background(), tenantID, false)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.True(t, result.CreateMissing)
+	assert.Equal(t, 1, result.MembershipsCreated)
+	assert.Equal(t, 1, result.Drift["missing_membership"])
+	require.Len(t, result.Tenants, 1)
+	assert.Empty(t, result.Tenants[0].Conflicts)
+}
+
+func TestStaffSync_ReportOnlyLeavesMissingMembership(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID := uuid.New()
+	keycloakID := uuid.New()
+
+	staff := staticStaffDirectory{
+		{ID: uuid.New(), KeycloakUserID: keycloakID.String(), IsActive: true, Role: "staff"},
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "user_tenant_memberships" WHERE tenant_id = \$1`).
+		WithArgs(tenantID).
+		WillReturnRows(sqlmock.NewRows(membershipColumns))
+	expectStaffUsers(mock, nil)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "membership_sync_conflicts" SET "resolved_at"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	svc := services.NewStaffMembershipSyncService(db, staff, config.StaffSyncConfig{CreateMissing: false})
+	result, err := svc.ReconcileTenant(context.Background(), tenantID, false)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet(), "no membership is inserted when only reporting drift")
+
+	assert.False(t, result.CreateMissing)
+	assert.Equal(t, 0, result.MembershipsCreated)
+	assert.Equal(t, 1, result.Drift["missing_membership"])
+}
@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestDiffTemplateVersions_ReportsChanges(t *testing.T) {
+	templateID := uuid.New()
+	from := &models.OnboardingTemplateVersion{
+		TemplateID:     templateID,
+		Version:        1,
+		Name:           "E-commerce Store Setup",
+		Description:    "Setup flow",
+		TemplateConfig: models.JSONB(`{"requires_payment": true, "trial_period_days": 14}`),
+		Steps:          models.JSONB(`[{"id": "business-registration", "order": 1}, {"id": "store-setup", "order": 2}]`),
+		CustomFields:   models.JSONB(`[{"key": "vat_number", "label": "VAT", "type": "text"}, {"key": "license", "label": "License", "type": "text"}]`),
+	}
+	to := &models.OnboardingTemplateVersion{
+		TemplateID:     templateID,
+		Version:        2,
+		Name:           "E-commerce Store Setup",
+		Description:    "Faster setup flow",
+		TemplateConfig: models.JSONB(`{"requires_payment": true, "trial_period_days": 30, "requires_domain": false}`),
+		Steps:          models.JSONB(`[{"id": "business-registration", "order": 1}, {"id": "store-setup", "order": 3}, {"id": "payments", "order": 2}]`),
+		CustomFields:   models.JSONB(`[{"key": "vat_number", "label": "VAT", "type": "text", "required": true}]`),
+	}
+
+	diff, err := services.DiffTemplateVersions(from, to)
+	require.NoError(t, err)
+
+	assert.True(t, diff.Changed)
+	assert.Equal(t, 1, diff.FromVersion)
+	assert.Equal(t, 2, diff.ToVersion)
+
+	fields := map[string]services.TemplateFieldChange{}
+	for _, change := range diff.Fields {
+		fields[change.Field] = change
+	}
+	assert.Len(t, fields, 3)
+	assert.Equal(t, "Faster setup flow", fields["description"].To)
+	assert.Equal(t, float64(30), fields["template_config.trial_period_days"].To)
+	assert.Nil(t, fields["template_config.requires_domain"].From)
+	assert.NotContains(t, fields, "name")
+
+	assert.Equal(t, []string{"payments"}, diff.Steps.Added)
+	assert.Empty(t, diff.Steps.Removed)
+	assert.Equal(t, []string{"store-setup"}, diff.Steps.Changed)
+
+	assert.Empty(t, diff.CustomFields.Added)
+	assert.Equal(t, []string{"license"}, diff.CustomFields.Removed)
+	assert.Equal(t, []string{"vat_number"}, diff.CustomFields.Changed)
+}
+
+func TestDiffTemplateVersions_Identical(t *testing.T) {
+	version := &models.OnboardingTemplateVersion{
+		Version:        1,
+		Name:           "SaaS",
+		TemplateConfig: models.JSONB(`{"a": 1}`),
+		Steps:          models.JSONB(`[{"id": "one"}]`),
+	}
+	same := *version
+	same.Version = 2
+	same.TemplateConfig = models.JSONB(`{ "a" : 1 }`)
+
+	diff, err := services.DiffTemplateVersions(version, &same)
+	require.NoError(t, err)
+	assert.False(t, diff.Changed)
+	assert.Empty(t, diff.Fields)
+}
+
+func TestDiffTemplateVersions_RejectsMalformedSteps(t *testing.T) {
+	from := &models.OnboardingTemplateVersion{Version: 1, Steps: models.JSONB(`{"id": "not-an-array"}`)}
+	to := &models.OnboardingTemplateVersion{Version: 2}
+
+	_, err := services.DiffTemplateVersions(from, to)
+	assert.Error(t, err)
+}
+
+func TestTemplateVersion_ApplyPinsContent(t *testing.T) {
+	template := &models.OnboardingTemplate{
+		ID:           uuid.New(),
+		Name:         "Live",
+		Version:      3,
+		IsDefault:    true,
+		CustomFields: models.JSONB(`[]`),
+	}
+	pinned := &models.OnboardingTemplateVersion{
+		TemplateID:   template.ID,
+		Version:      2,
+		Name:         "Previous",
+		CustomFields: models.JSONB(`[{"key": "license", "label": "License", "type": "text", "required": true}]`),
+	}
+
+	applied := pinned.Apply(template)
+
+	assert.Equal(t, 2, applied.Version)
+	assert.Equal(t, "Previous", applied.Name)
+	assert.True(t, applied.IsDefault)
+	assert.Equal(t, "Live", template.Name, "the live template must not be modified")
+	defs, err := applied.CustomFieldDefinitions()
+	require.NoError(t, err)
+	require.Len(t, defs, 1)
+	assert.Equal(t, "license", defs[0].Key)
+}
@@ -0,0 +1,190 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestAPIKeyScopeAllows_IsHierarchical(t *testing.T) {
+	assert.True(t, models.APIKeyScopeAllows(models.APIKeyScopeFull, models.APIKeyScopeReadOnly))
+	assert.True(t, models.APIKeyScopeAllows(models.APIKeyScopeFull, models.APIKeyScopeMemberManagement))
+	assert.True(t, models.APIKeyScopeAllows(models.APIKeyScopeMemberManagement, models.APIKeyScopeReadOnly))
+	assert.True(t, models.APIKeyScopeAllows(models.APIKeyScopeReadOnly, models.APIKeyScopeReadOnly))
+
+	assert.False(t, models.APIKeyScopeAllows(models.APIKeyScopeReadOnly, models.APIKeyScopeMemberManagement))
+	assert.False(t, models.APIKeyScopeAllows(models.APIKeyScopeMemberManagement, models.APIKeyScopeFull))
+	assert.False(t, models.APIKeyScopeAllows("admin", models.APIKeyScopeReadOnly))
+	assert.False(t, models.APIKeyScopeAllows(models.APIKeyScopeFull, "admin"))
+}
+
+func TestAPIKeyCreate_ValidatesRequest(t *testing.T) {
+	svc := services.NewTenantAPIKeyService(nil)
+
+	tests := []struct {
+		name      string
+		req       services.CreateAPIKeyRequest
+		wantField string
+	}{
+		{"blank name", services.CreateAPIKeyRequest{Name: "  ", Scope: models.APIKeyScopeReadOnly}, "name"},
+		{"unknown scope", services.CreateAPIKeyRequest{Name: "storefront", Scope: "admin"}, "scope"},
+		{"negative expiry", services.CreateAPIKeyRequest{Name: "storefront", Scope: models.APIKeyScopeFull, ExpiresInDays: -1}, "expires_in_days"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Create(context.Background(), uuid.New(), uuid.New(), tt.req)
+			validationErr, ok := services.IsValidationError(err)
+			require.True(t, ok, "expected validation error, got %v", err)
+			assert.Equal(t, tt.wantField, validationErr.Field)
+		})
+	}
+}
+
+func TestAPIKeyValidate_RejectsMalformedKeysWithoutLookup(t *testing.T) {
+	svc := services.NewTenantAPIKeyService(nil)
+
+	_, err := svc.Validate(context.Background(), "not-a-tenant-key", "")
+	assert.ErrorIs(t, err, services.ErrAPIKeyInvalid)
+
+	_, err = svc.Validate(context.Background(), services.APIKeyPrefix+"abc", "admin")
+	_, ok := services.IsValidationError(err)
+	assert.True(t, ok)
+}
+
+func TestAPIKeyRotate_RejectsExcessiveGracePeriod(t *testing.T) {
+	svc := services.NewTenantAPIKeyService(nil)
+
+	_, err := svc.Rotate(context.Background(), uuid.New(), uuid.New(), uuid.New(), services.RotateAPIKeyRequest{GracePeriodMinutes: 7*24*60 + 1})
+	validationErr, ok := services.IsValidationError(err)
+	require.True(t, ok)
+	assert.Equal(t, "grace_period_minutes", validationErr.Field)
+}
+
+func TestAPIKeyRotate_KeepsOriginalExpiry(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID, keyID, userID := uuid.New(), uuid.New(), uuid.New()
+	expiresAt := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	mock.ExpectBegin()
+	expectLockedAPIKey(mock, tenantID, keyID, expiresAt)
+	expectAPIKeyReplacements(mock, keyID, 0)
+	expectActiveAPIKeys(mock, tenantID, 3)
+	// The old key stays valid for the grace period only
+	mock.ExpectExec(`UPDATE "tenant_api_keys" SET "expires_at"=\$1,"updated_at"=\$2 WHERE "id" = \$3`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), keyID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO "tenant_api_keys"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	svc := services.NewTenantAPIKeyService(db)
+	created, err := svc.Rotate(context.Background(), tenantID, keyID, userID, services.RotateAPIKeyRequest{GracePeriodMinutes: 60})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.NotNil(t, created.ExpiresAt, "rotation does not extend a key's lifetime")
+	assert.True(t, expiresAt.Equal(*created.ExpiresAt))
+	assert.Equal(t, keyID, *created.RotatedFromID)
+	assert.Equal(t, models.APIKeyScopeReadOnly, created.Scope)
+}
+
+func TestAPIKeyRotate_RejectsAlreadyRotatedKey(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID, keyID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	// Still active during its grace period, but a replacement exists
+	expectLockedAPIKey(mock, tenantID, keyID, time.Now().Add(time.Hour))
+	expectAPIKeyReplacements(mock, keyID, 1)
+	mock.ExpectRollback()
+
+	svc := services.NewTenantAPIKeyService(db)
+	_, err := svc.Rotate(context.Background(), tenantID, keyID, uuid.New(), services.RotateAPIKeyRequest{GracePeriodMinutes: 60})
+	assert.ErrorIs(t, err, services.ErrAPIKeyRotated)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRotate_RejectsRevokedKey(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID, keyID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "tenant_api_keys" WHERE id = \$1 AND tenant_id = \$2 .* FOR UPDATE`).
+		WithArgs(keyID, tenantID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name", "scope", "revoked_at"}).
+			AddRow(keyID, tenantID, "storefront", models.APIKeyScopeReadOnly, time.Now().Add(-time.Minute)))
+	mock.ExpectRollback()
+
+	svc := services.NewTenantAPIKeyService(db)
+	_, err := svc.Rotate(context.Background(), tenantID, keyID, uuid.New(), services.RotateAPIKeyRequest{})
+	assert.ErrorIs(t, err, services.ErrAPIKeyRevoked)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRotate_GracePeriodCountsAgainstKeyLimit(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID, keyID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectLockedAPIKey(mock, tenantID, keyID, time.Now().Add(24*time.Hour))
+	expectAPIKeyReplacements(mock, keyID, 0)
+	expectActiveAPIKeys(mock, tenantID, services.MaxActiveAPIKeysPerTenant)
+	mock.ExpectRollback()
+
+	svc := services.NewTenantAPIKeyService(db)
+	_, err := svc.Rotate(context.Background(), tenantID, keyID, uuid.New(), services.RotateAPIKeyRequest{GracePeriodMinutes: 60})
+	validationErr, ok := services.IsValidationError(err)
+	require.True(t, ok, "expected validation error, got %v", err)
+	assert.Equal(t, "grace_period_minutes", validationErr.Field)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRotate_WithoutGracePeriodAtKeyLimit(t *testing.T) {
+	db, mock := newMockDB(t)
+	tenantID, keyID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectLockedAPIKey(mock, tenantID, keyID, time.Now().Add(24*time.Hour))
+	expectAPIKeyReplacements(mock, keyID, 0)
+	// The old key is revoked in the same transaction, so the active count does not grow
+	mock.ExpectExec(`UPDATE "tenant_api_keys" SET "revoked_at"=\$1,"revoked_by"=\$2,"updated_at"=\$3 WHERE "id" = \$4`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), keyID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO "tenant_api_keys"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	svc := services.NewTenantAPIKeyService(db)
+	_, err := svc.Rotate(context.Background(), tenantID, keyID, uuid.New(), services.RotateAPIKeyRequest{})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectLockedAPIKey expects Rotate to load the old key with a row lock
+func expectLockedAPIKey(mock sqlmock.Sqlmock, tenantID, keyID uuid.UUID, expiresAt time.Time) {
+	mock.ExpectQuery(`SELECT \* FROM "tenant_api_keys" WHERE id = \$1 AND tenant_id = \$2 .* FOR UPDATE`).
+		WithArgs(keyID, tenantID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name", "scope", "expires_at"}).
+			AddRow(keyID, tenantID, "storefront", models.APIKeyScopeReadOnly, expiresAt))
+}
+
+// expectAPIKeyReplacements expects Rotate to count the keys already rotated from keyID
+func expectAPIKeyReplacements(mock sqlmock.Sqlmock, keyID uuid.UUID, count int64) {
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "tenant_api_keys" WHERE rotated_from_id = \$1`).
+		WithArgs(keyID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+// expectActiveAPIKeys expects Rotate to count the tenant's active keys
+func expectActiveAPIKeys(mock sqlmock.Sqlmock, tenantID uuid.UUID, count int64) {
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "tenant_api_keys" WHERE tenant_id = \$1 AND revoked_at IS NULL`).
+		WithArgs(tenantID, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
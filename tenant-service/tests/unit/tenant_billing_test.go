@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestVerifyBillingSignature(t *testing.T) {
+	secret := "whsec_test"
+	payload := []byte(`{"id":"evt_1","type":"payment_failed"}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := services.SignBillingPayload(secret, timestamp, payload)
+
+	assert.NoError(t, services.VerifyBillingSignature(secret, payload, timestamp, signature, 5*time.Minute, now))
+	assert.NoError(t, services.VerifyBillingSignature(secret, payload, timestamp, "sha256="+signature, 5*time.Minute, now))
+
+	tests := []struct {
+		name      string
+		secret    string
+		payload   []byte
+		timestamp string
+		signature string
+		now       time.Time
+		want      error
+	}{
+		{"no secret configured", "", payload, timestamp, signature, now, services.ErrBillingWebhookDisabled},
+		{"wrong secret", "other", payload, timestamp, signature, now, services.ErrBillingSignatureInvalid},
+		{"tampered body", secret, []byte(`{"id":"evt_1","type":"payment_succeeded"}`), timestamp, signature, now, services.ErrBillingSignatureInvalid},
+		{"missing signature", secret, payload, timestamp, "", now, services.ErrBillingSignatureInvalid},
+		{"missing timestamp", secret, payload, "", signature, now, services.ErrBillingSignatureInvalid},
+		{"stale", secret, payload, timestamp, signature, now.Add(10 * time.Minute), services.ErrBillingSignatureInvalid},
+		{"from the future", secret, payload, timestamp, signature, now.Add(-10 * time.Minute), services.ErrBillingSignatureInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := services.VerifyBillingSignature(tt.secret, tt.payload, tt.timestamp, tt.signature, 5*time.Minute, tt.now)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestBillingService_HandleWebhookRejectsBeforeApplying(t *testing.T) {
+	// A nil database proves nothing is read or written for rejected webhooks
+	svc := services.NewBillingService(nil, nil, config.BillingConfig{WebhookSecret: "whsec_test"})
+	ctx := context.Background()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	_, err := svc.HandleWebhook(ctx, []byte(`{}`), timestamp, "bad")
+	assert.ErrorIs(t, err, services.ErrBillingSignatureInvalid)
+
+	disabled := services.NewBillingService(nil, nil, config.BillingConfig{})
+	_, err = disabled.HandleWebhook(ctx, []byte(`{}`), timestamp, "bad")
+	assert.ErrorIs(t, err, services.ErrBillingWebhookDisabled)
+
+	for _, payload := range []string{`not json`, `{"type":"payment_failed"}`, `{"id":"evt_1"}`} {
+		signature := services.SignBillingPayload("whsec_test", timestamp, []byte(payload))
+		_, err := svc.HandleWebhook(ctx, []byte(payload), timestamp, signature)
+		_, ok := services.IsValidationError(err)
+		assert.True(t, ok, "expected validation error for %s, got %v", payload, err)
+	}
+}
+
+func TestBillingService_SuspensionCheckInterval(t *testing.T) {
+	assert.Equal(t, time.Hour, services.NewBillingService(nil, nil, config.BillingConfig{}).SuspensionCheckInterval())
+	assert.Equal(t, 10*time.Minute, services.NewBillingService(nil, nil, config.BillingConfig{SuspensionCheckIntervalMinutes: 10}).SuspensionCheckInterval())
+}
+
+func TestTenant_IsBillingSuspended(t *testing.T) {
+	require.True(t, (&models.Tenant{BillingStatus: models.BillingStatusSuspended}).IsBillingSuspended())
+	for _, status := range []string{"", models.BillingStatusTrialing, models.BillingStatusActive, models.BillingStatusPastDue, models.BillingStatusCancelled} {
+		assert.False(t, (&models.Tenant{BillingStatus: status}).IsBillingSuspended(), status)
+	}
+}
@@ -0,0 +1,64 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestNormalizeContactValue_Email(t *testing.T) {
+	email, err := services.NormalizeContactValue(models.ContactFieldEmail, "old@example.com", "  Billing@Example.COM ")
+	require.NoError(t, err)
+	assert.Equal(t, "billing@example.com", email)
+
+	for _, requested := range []string{"OLD@example.com", "not-an-email", "Jane <jane@example.com>", " "} {
+		_, err := services.NormalizeContactValue(models.ContactFieldEmail, "old@example.com", requested)
+		_, ok := services.IsValidationError(err)
+		assert.True(t, ok, "expected a validation error for %q, got %v", requested, err)
+	}
+}
+
+func TestNormalizeContactValue_Phone(t *testing.T) {
+	phone, err := services.NormalizeContactValue(models.ContactFieldPhone, "+61400000000", "+61 (412) 345-678")
+	require.NoError(t, err)
+	assert.Equal(t, "+61412345678", phone)
+
+	tests := []struct {
+		name      string
+		requested string
+	}{
+		{"same number with separators", "+61 400 000 000"},
+		{"no country code", "0412345678"},
+		{"too short", "+6141"},
+		{"letters", "+61412ABC678"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := services.NormalizeContactValue(models.ContactFieldPhone, "+61400000000", tt.requested)
+			validationErr, ok := services.IsValidationError(err)
+			require.True(t, ok, "expected a validation error, got %v", err)
+			assert.Equal(t, "value", validationErr.Field)
+		})
+	}
+}
+
+func TestNormalizeContactValue_UnknownField(t *testing.T) {
+	_, err := services.NormalizeContactValue("fax", "", "+61412345678")
+	validationErr, ok := services.IsValidationError(err)
+	require.True(t, ok)
+	assert.Equal(t, "field", validationErr.Field)
+}
+
+func TestTenantContactChange_IsPending(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Minute)
+
+	assert.True(t, (&models.TenantContactChange{ExpiresAt: now.Add(time.Hour)}).IsPending(now))
+	assert.False(t, (&models.TenantContactChange{ExpiresAt: now}).IsPending(now), "expired")
+	assert.False(t, (&models.TenantContactChange{ExpiresAt: now.Add(time.Hour), VerifiedAt: &earlier}).IsPending(now), "already verified")
+	assert.False(t, (&models.TenantContactChange{ExpiresAt: now.Add(time.Hour), CancelledAt: &earlier}).IsPending(now), "cancelled or superseded")
+}
@@ -0,0 +1,27 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"tenant-service/internal/config"
+)
+
+func TestDeletionGracePeriod_UsesTierOverride(t *testing.T) {
+	t.Setenv("TENANT_DELETION_GRACE_DAYS", "30")
+	t.Setenv("TENANT_DELETION_GRACE_DAYS_BY_TIER", "free:7, enterprise:90,invalid,starter:x")
+
+	cfg := config.New().Deletion
+
+	assert.Equal(t, 7, cfg.GracePeriodDays("free"))
+	assert.Equal(t, 90, cfg.GracePeriodDays("enterprise"))
+	assert.Equal(t, 30, cfg.GracePeriodDays("professional"))
+	assert.Equal(t, 30, cfg.GracePeriodDays("starter"))
+}
+
+func TestDeletionGracePeriod_ZeroPurgesImmediately(t *testing.T) {
+	t.Setenv("TENANT_DELETION_GRACE_DAYS", "0")
+	t.Setenv("TENANT_DELETION_GRACE_DAYS_BY_TIER", "")
+
+	assert.Equal(t, 0, config.New().Deletion.GracePeriodDays("free"))
+}
@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestDeletionSaga_DefaultParticipants(t *testing.T) {
+	t.Setenv("TENANT_DELETION_PARTICIPANTS", "")
+
+	cfg := config.New().Deletion
+	assert.Equal(t, []string{"document-service", "settings-service", "notification-service", "audit-service"}, cfg.Participants)
+}
+
+func TestDeletionSaga_ParticipantsOverride(t *testing.T) {
+	t.Setenv("TENANT_DELETION_PARTICIPANTS", "document-service, audit-service")
+
+	cfg := config.New().Deletion
+	assert.Equal(t, []string{"document-service", "audit-service"}, cfg.Participants)
+}
+
+func TestDeletionSaga_NoSagaWithoutParticipants(t *testing.T) {
+	saga := services.NewTenantDeletionSagaService(nil, nil, config.DeletionConfig{})
+	err := saga.Start(context.Background(), &models.DeletedTenant{OriginalTenantID: uuid.New(), Slug: "acme"})
+	assert.NoError(t, err, "no saga is started without participants")
+}
+
+func TestDeletionStatus_AcknowledgePurged(t *testing.T) {
+	now := time.Now()
+	status := models.TenantDeletionStatus{Status: models.DeletionStatusFailed, LastError: "timeout"}
+
+	require.True(t, status.Acknowledge(models.DeletionStatusPurged, 42, "", now))
+	assert.Equal(t, models.DeletionStatusPurged, status.Status)
+	assert.Equal(t, int64(42), status.ItemsPurged)
+	assert.Empty(t, status.LastError)
+	assert.Equal(t, now, *status.AcknowledgedAt)
+	assert.True(t, status.IsTerminal())
+}
+
+func TestDeletionStatus_AcknowledgeFailed(t *testing.T) {
+	status := models.TenantDeletionStatus{Status: models.DeletionStatusPending}
+
+	require.True(t, status.Acknowledge(models.DeletionStatusFailed, 3, "bucket locked", time.Now()))
+	assert.Equal(t, models.DeletionStatusFailed, status.Status)
+	assert.Equal(t, "bucket locked", status.LastError)
+	assert.False(t, status.IsTerminal(), "failed purges are re-requested")
+}
+
+func TestDeletionStatus_DuplicateAcknowledgmentIgnored(t *testing.T) {
+	acknowledgedAt := time.Now().Add(-time.Hour)
+	status := models.TenantDeletionStatus{Status: models.DeletionStatusPurged, ItemsPurged: 42, AcknowledgedAt: &acknowledgedAt}
+
+	assert.False(t, status.Acknowledge(models.DeletionStatusFailed, 0, "late failure", time.Now()))
+	assert.Equal(t, models.DeletionStatusPurged, status.Status)
+	assert.Equal(t, int64(42), status.ItemsPurged)
+	assert.Equal(t, acknowledgedAt, *status.AcknowledgedAt)
+}
+
+func TestDeletionStatus_NeedsRetry(t *testing.T) {
+	cutoff := time.Now().Add(-15 * time.Minute)
+	before := cutoff.Add(-time.Minute)
+	after := cutoff.Add(time.Minute)
+
+	tests := []struct {
+		name   string
+		status models.TenantDeletionStatus
+		want   bool
+	}{
+		{"never requested", models.TenantDeletionStatus{Status: models.DeletionStatusPending}, true},
+		{"pending past interval", models.TenantDeletionStatus{Status: models.DeletionStatusPending, LastRequestedAt: &before}, true},
+		{"pending within interval", models.TenantDeletionStatus{Status: models.DeletionStatusPending, LastRequestedAt: &after}, false},
+		{"failed past interval", models.TenantDeletionStatus{Status: models.DeletionStatusFailed, LastRequestedAt: &before}, true},
+		{"purged", models.TenantDeletionStatus{Status: models.DeletionStatusPurged, LastRequestedAt: &before}, false},
+		{"exhausted", models.TenantDeletionStatus{Status: models.DeletionStatusExhausted, LastRequestedAt: &before}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.status.NeedsRetry(cutoff))
+		})
+	}
+}
+
+func TestDeletionStatus_ExhaustAfterMaxAttempts(t *testing.T) {
+	status := models.TenantDeletionStatus{Status: models.DeletionStatusPending, Attempts: 9}
+	assert.False(t, status.Exhaust(10), "attempts remain")
+	assert.Equal(t, models.DeletionStatusPending, status.Status)
+
+	status.Attempts = 10
+	require.True(t, status.Exhaust(10))
+	assert.Equal(t, models.DeletionStatusExhausted, status.Status)
+	assert.Equal(t, "no purge acknowledgment after 10 attempts", status.LastError)
+	assert.True(t, status.IsTerminal())
+	assert.False(t, status.Exhaust(10), "already exhausted")
+}
+
+func TestDeletionStatus_ExhaustKeepsLastError(t *testing.T) {
+	status := models.TenantDeletionStatus{Status: models.DeletionStatusFailed, Attempts: 10, LastError: "bucket locked"}
+
+	require.True(t, status.Exhaust(10))
+	assert.Equal(t, "bucket locked", status.LastError)
+}
+
+func TestDeletionStatus_PurgedNeverExhausted(t *testing.T) {
+	status := models.TenantDeletionStatus{Status: models.DeletionStatusPurged, Attempts: 10}
+
+	assert.False(t, status.Exhaust(10))
+	assert.Equal(t, models.DeletionStatusPurged, status.Status)
+}
+
+func TestDeletionStatus_AllPurged(t *testing.T) {
+	purged := models.TenantDeletionStatus{Status: models.DeletionStatusPurged}
+	pending := models.TenantDeletionStatus{Status: models.DeletionStatusPending}
+	exhausted := models.TenantDeletionStatus{Status: models.DeletionStatusExhausted}
+
+	assert.True(t, models.AllPurged([]models.TenantDeletionStatus{purged, purged}))
+	assert.False(t, models.AllPurged([]models.TenantDeletionStatus{purged, pending}))
+	assert.False(t, models.AllPurged([]models.TenantDeletionStatus{purged, exhausted}), "exhausted services need manual cleanup")
+	assert.False(t, models.AllPurged(nil))
+}
@@ -0,0 +1,147 @@
+package unit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+var exportColumns = []string{"id", "tenant_id", "requested_by", "status", "archive", "download_token_hash", "expires_at", "created_at", "updated_at"}
+
+func exportTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newExportService(t *testing.T) (*services.TenantExportService, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock := newMockDB(t)
+	return services.NewTenantExportService(db, nil, config.ExportConfig{}), mock
+}
+
+func expectExportByID(mock sqlmock.Sqlmock, exportID uuid.UUID, status string, archive []byte, tokenHash string, expiresAt time.Time) {
+	mock.ExpectQuery(`SELECT \* FROM "tenant_exports" WHERE id = \$1`).
+		WithArgs(exportID, 1).
+		WillReturnRows(sqlmock.NewRows(exportColumns).
+			AddRow(exportID, uuid.New(), uuid.New(), status, archive, tokenHash, expiresAt, time.Now(), time.Now()))
+}
+
+func TestTenantExport_OwnerOnly(t *testing.T) {
+	svc, mock := newExportService(t)
+	tenantID, userID := uuid.New(), uuid.New()
+
+	expectUserRole(mock, userID, tenantID, models.MembershipRoleAdmin)
+	assert.ErrorIs(t, svc.AuthorizeExport(context.Background(), tenantID, userID), services.ErrTenantExportForbidden)
+
+	expectUserRole(mock, userID, tenantID, models.MembershipRoleOwner)
+	assert.NoError(t, svc.AuthorizeExport(context.Background(), tenantID, userID))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantExport_RequestReturnsInFlightExport(t *testing.T) {
+	svc, mock := newExportService(t)
+	tenantID, userID := uuid.New(), uuid.New()
+	inFlightID := uuid.New()
+
+	mock.ExpectQuery(`SELECT \* FROM "tenant_exports" WHERE tenant_id = \$1 AND status IN \(\$2,\$3\) ORDER BY created_at DESC`).
+		WithArgs(tenantID, models.TenantExportStatusPending, models.TenantExportStatusRunning, 1).
+		WillReturnRows(sqlmock.NewRows(exportColumns).
+			AddRow(inFlightID, tenantID, uuid.New(), models.TenantExportStatusRunning, nil, "", nil, time.Now(), time.Now()))
+
+	export, created, err := svc.RequestExport(context.Background(), tenantID, userID)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet(), "no second export is created")
+	assert.False(t, created)
+	assert.Equal(t, inFlightID, export.ID)
+}
+
+func TestTenantExport_DownloadByToken(t *testing.T) {
+	svc, mock := newExportService(t)
+	exportID := uuid.New()
+	token := "download-token"
+	archive := []byte("PK\x03\x04")
+
+	expectExportByID(mock, exportID, models.TenantExportStatusCompleted, archive, exportTokenHash(token), time.Now().Add(time.Hour))
+	mock.ExpectExec(`UPDATE "tenant_exports" SET "downloaded_at"=\$1,"updated_at"=\$2 WHERE id = \$3`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), exportID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	export, err := svc.OpenArchiveByToken(context.Background(), exportID, token)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, archive, export.Archive)
+	assert.NotNil(t, export.DownloadedAt)
+}
+
+func TestTenantExport_DownloadRejectsWrongToken(t *testing.T) {
+	svc, mock := newExportService(t)
+	exportID := uuid.New()
+
+	expectExportByID(mock, exportID, models.TenantExportStatusCompleted, []byte("PK"), exportTokenHash("download-token"), time.Now().Add(time.Hour))
+	_, err := svc.OpenArchiveByToken(context.Background(), exportID, "guessed-token")
+	assert.ErrorIs(t, err, services.ErrTenantExportNotFound, "a wrong token does not reveal the export exists")
+
+	// Expired exports have their token hash cleared
+	expectExportByID(mock, exportID, models.TenantExportStatusExpired, nil, "", time.Now().Add(-time.Hour))
+	_, err = svc.OpenArchiveByToken(context.Background(), exportID, "")
+	assert.ErrorIs(t, err, services.ErrTenantExportNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantExport_DownloadRequiresLiveArchive(t *testing.T) {
+	token := "download-token"
+
+	tests := []struct {
+		name      string
+		status    string
+		archive   []byte
+		expiresAt time.Time
+	}{
+		{"still running", models.TenantExportStatusRunning, nil, time.Now().Add(time.Hour)},
+		{"past expiry", models.TenantExportStatusCompleted, []byte("PK"), time.Now().Add(-time.Minute)},
+		{"archive dropped", models.TenantExportStatusCompleted, nil, time.Now().Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock := newExportService(t)
+			exportID := uuid.New()
+			expectExportByID(mock, exportID, tt.status, tt.archive, exportTokenHash(token), tt.expiresAt)
+
+			_, err := svc.OpenArchiveByToken(context.Background(), exportID, token)
+			assert.ErrorIs(t, err, services.ErrTenantExportNotReady)
+			require.NoError(t, mock.ExpectationsWereMet(), "undownloadable exports are not marked downloaded")
+		})
+	}
+}
+
+func TestTenantExport_MaintenanceExpiresArchives(t *testing.T) {
+	svc, mock := newExportService(t)
+
+	mock.ExpectExec(`UPDATE "tenant_exports" SET "archive"=\$1,"download_token_hash"=\$2,"status"=\$3,"updated_at"=\$4 WHERE status = \$5 AND expires_at < \$6`).
+		WithArgs(nil, "", models.TenantExportStatusExpired, sqlmock.AnyArg(), models.TenantExportStatusCompleted, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectQuery(`SELECT .* FROM "tenant_exports" WHERE status IN \(\$1,\$2\) AND updated_at < \$3`).
+		WithArgs(models.TenantExportStatusPending, models.TenantExportStatusRunning, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(exportColumns))
+
+	require.NoError(t, svc.RunMaintenance(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantExportDownloadURL(t *testing.T) {
+	exportID := uuid.MustParse("6f1c2b7e-8a4d-4c1e-9f5a-2d3b4c5d6e7f")
+	assert.Equal(t,
+		"https://admin.example.com/api/v1/tenant-exports/6f1c2b7e-8a4d-4c1e-9f5a-2d3b4c5d6e7f/download?token=abc",
+		services.TenantExportDownloadURL("https://admin.example.com/", exportID, "abc"))
+}
@@ -0,0 +1,310 @@
+package unit
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+var keyColumns = []string{"id", "tenant_id", "version", "status", "algorithm", "wrapped_key", "master_key_id"}
+
+func newMasterKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func newKeyWrapper(t *testing.T) *services.LocalKeyWrapper {
+	t.Helper()
+	wrapper, err := services.NewLocalKeyWrapper("local-v1", newMasterKey(t))
+	require.NoError(t, err)
+	return wrapper
+}
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db, mock
+}
+
+// newWrappedKey returns a fresh DEK wrapped by wrapper, as stored in tenant_encryption_keys
+func newWrappedKey(t *testing.T, wrapper services.KeyWrapper) string {
+	t.Helper()
+	dek := make([]byte, 32)
+	_, err := rand.Read(dek)
+	require.NoError(t, err)
+	wrapped, err := wrapper.Wrap(context.Background(), dek)
+	require.NoError(t, err)
+	return wrapped
+}
+
+func keyRow(tenantID uuid.UUID, version int, status, wrapped string) *sqlmock.Rows {
+	return sqlmock.NewRows(keyColumns).
+		AddRow(uuid.New(), tenantID, version, status, "AES-256-GCM", wrapped, "local-v1")
+}
+
+func expectActiveKey(mock sqlmock.Sqlmock, tenantID uuid.UUID, version int, wrapped string) {
+	mock.ExpectQuery(`SELECT \* FROM "tenant_encryption_keys" WHERE tenant_id = \$1 AND status = \$2`).
+		WithArgs(tenantID, models.EncryptionKeyStatusActive, 1).
+		WillReturnRows(keyRow(tenantID, version, models.EncryptionKeyStatusActive, wrapped))
+}
+
+func expectKeyVersion(mock sqlmock.Sqlmock, tenantID uuid.UUID, version int, status, wrapped string) {
+	mock.ExpectQuery(`SELECT \* FROM "tenant_encryption_keys" WHERE tenant_id = \$1 AND version = \$2`).
+		WithArgs(tenantID, version, 1).
+		WillReturnRows(keyRow(tenantID, version, status, wrapped))
+}
+
+// captureArg matches any value and records it, to read back what was written
+type captureArg struct {
+	value *string
+}
+
+func (c captureArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	if ok {
+		*c.value = s
+	}
+	return ok
+}
+
+func TestLocalKeyWrapper_WrapUnwrap(t *testing.T) {
+	ctx := context.Background()
+	wrapper := newKeyWrapper(t)
+	dek := []byte("0123456789abcdef0123456789abcdef")
+
+	wrapped, err := wrapper.Wrap(ctx, dek)
+	require.NoError(t, err)
+	assert.NotContains(t, wrapped, string(dek))
+
+	unwrapped, err := wrapper.Unwrap(ctx, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dek, unwrapped)
+
+	again, err := wrapper.Wrap(ctx, dek)
+	require.NoError(t, err)
+	assert.NotEqual(t, wrapped, again, "each wrap uses a fresh nonce")
+}
+
+func TestLocalKeyWrapper_RejectsOtherMasterKey(t *testing.T) {
+	ctx := context.Background()
+	masterKey := newMasterKey(t)
+	wrapper, err := services.NewLocalKeyWrapper("local-v1", masterKey)
+	require.NoError(t, err)
+
+	wrapped, err := wrapper.Wrap(ctx, make([]byte, 32))
+	require.NoError(t, err)
+
+	_, err = newKeyWrapper(t).Unwrap(ctx, wrapped)
+	assert.Error(t, err, "different master key")
+
+	relabelled, err := services.NewLocalKeyWrapper("local-v2", masterKey)
+	require.NoError(t, err)
+	_, err = relabelled.Unwrap(ctx, wrapped)
+	assert.Error(t, err, "wrapped keys are bound to the master key ID")
+}
+
+func TestLocalKeyWrapper_InvalidMasterKey(t *testing.T) {
+	_, err := services.NewLocalKeyWrapper("local-v1", "")
+	assert.ErrorIs(t, err, services.ErrMasterKeyNotConfigured)
+
+	_, err = services.NewLocalKeyWrapper("local-v1", "not base64!")
+	assert.Error(t, err)
+
+	_, err = services.NewLocalKeyWrapper("local-v1", base64.StdEncoding.EncodeToString(make([]byte, 16)))
+	assert.Error(t, err, "master key must be 32 bytes")
+}
+
+func TestTenantKeyService_EncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	db, mock := newMockDB(t)
+	wrapper := newKeyWrapper(t)
+	tenantID := uuid.New()
+	wrapped := newWrappedKey(t, wrapper)
+	expectActiveKey(mock, tenantID, 1, wrapped)
+
+	keys := services.NewTenantKeyService(db, wrapper, config.EncryptionConfig{})
+	ciphertext, version, err := keys.Encrypt(ctx, tenantID, "JBSWY3DPEHPK3PXP", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+	assert.NotContains(t, ciphertext, "JBSWY3DPEHPK3PXP")
+
+	expectKeyVersion(mock, tenantID, 1, models.EncryptionKeyStatusActive, wrapped)
+	expectKeyVersion(mock, tenantID, 1, models.EncryptionKeyStatusActive, wrapped)
+
+	plaintext, err := keys.Decrypt(ctx, tenantID, version, ciphertext, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", plaintext)
+
+	_, err = keys.Decrypt(ctx, tenantID, version, ciphertext, "user-2")
+	assert.Error(t, err, "ciphertext is bound to its owning record")
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantKeyService_DecryptLegacyPlaintext(t *testing.T) {
+	db, mock := newMockDB(t)
+	keys := services.NewTenantKeyService(db, newKeyWrapper(t), config.EncryptionConfig{})
+
+	plaintext, err := keys.Decrypt(context.Background(), uuid.New(), 0, "JBSWY3DPEHPK3PXP", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", plaintext)
+	require.NoError(t, mock.ExpectationsWereMet(), "version 0 needs no key")
+}
+
+func TestTenantKeyService_RotateKey(t *testing.T) {
+	db, mock := newMockDB(t)
+	wrapper := newKeyWrapper(t)
+	tenantID := uuid.New()
+	rotatedBy := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "tenant_encryption_keys" WHERE tenant_id = \$1 AND status = \$2 .* FOR UPDATE`).
+		WithArgs(tenantID, models.EncryptionKeyStatusActive, 1).
+		WillReturnRows(keyRow(tenantID, 1, models.EncryptionKeyStatusActive, newWrappedKey(t, wrapper)))
+	mock.ExpectExec(`UPDATE "tenant_encryption_keys" SET "rotated_at"=\$1,"status"=\$2 WHERE "id" = \$3`).
+		WithArgs(sqlmock.AnyArg(), models.EncryptionKeyStatusDecryptOnly, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO "tenant_encryption_keys"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+	// Background re-encryption after the rotation; stopped here and covered separately
+	mock.ExpectQuery(`SELECT \* FROM "tenant_encryption_keys"`).
+		WillReturnError(errors.New("stop"))
+
+	keys := services.NewTenantKeyService(db, wrapper, config.EncryptionConfig{})
+	newKey, err := keys.RotateKey(context.Background(), tenantID, &rotatedBy)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, newKey.Version)
+	assert.Equal(t, models.EncryptionKeyStatusActive, newKey.Status)
+	assert.Equal(t, &rotatedBy, newKey.CreatedBy)
+	assert.Equal(t, "local-v1", newKey.MasterKeyID)
+	dek, err := wrapper.Unwrap(context.Background(), newKey.WrappedKey)
+	require.NoError(t, err)
+	assert.Len(t, dek, 32)
+
+	assert.Eventually(t, func() bool { return mock.ExpectationsWereMet() == nil }, time.Second, 10*time.Millisecond)
+}
+
+func TestTenantKeyService_ReencryptRotatedRows(t *testing.T) {
+	ctx := context.Background()
+	wrapper := newKeyWrapper(t)
+	tenantID := uuid.New()
+	userID := uuid.New()
+	credentialID := uuid.New()
+	v1, v2 := newWrappedKey(t, wrapper), newWrappedKey(t, wrapper)
+
+	// A secret written before the rotation, under version 1
+	oldDB, oldMock := newMockDB(t)
+	expectActiveKey(oldMock, tenantID, 1, v1)
+	oldCiphertext, _, err := services.NewTenantKeyService(oldDB, wrapper, config.EncryptionConfig{}).
+		Encrypt(ctx, tenantID, "JBSWY3DPEHPK3PXP", userID.String())
+	require.NoError(t, err)
+
+	db, mock := newMockDB(t)
+	var newCiphertext string
+	expectActiveKey(mock, tenantID, 2, v2)
+	mock.ExpectQuery(`SELECT id, user_id, tenant_id, mfa_secret, key_version FROM "tenant_credentials" WHERE tenant_id = \$1 AND key_version <> \$2`).
+		WithArgs(tenantID, 2, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "tenant_id", "mfa_secret", "key_version"}).
+			AddRow(credentialID, userID, tenantID, oldCiphertext, 1))
+	expectKeyVersion(mock, tenantID, 1, models.EncryptionKeyStatusDecryptOnly, v1)
+	expectActiveKey(mock, tenantID, 2, v2)
+	mock.ExpectExec(`UPDATE "tenant_credentials" SET "key_version"=\$1,"mfa_secret"=\$2,"updated_at"=\$3 WHERE id = \$4 AND key_version = \$5`).
+		WithArgs(2, captureArg{&newCiphertext}, sqlmock.AnyArg(), credentialID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id, user_id, tenant_id, mfa_secret, key_version FROM "tenant_credentials"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	// Version 1 no longer protects any row and is retired
+	mock.ExpectQuery(`SELECT \* FROM "tenant_encryption_keys" WHERE tenant_id = \$1 AND status = \$2`).
+		WithArgs(tenantID, models.EncryptionKeyStatusDecryptOnly).
+		WillReturnRows(keyRow(tenantID, 1, models.EncryptionKeyStatusDecryptOnly, v1))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "tenant_credentials" WHERE tenant_id = \$1 AND key_version = \$2`).
+		WithArgs(tenantID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`UPDATE "tenant_encryption_keys" SET "retired_at"=\$1,"status"=\$2 WHERE "id" = \$3`).
+		WithArgs(sqlmock.AnyArg(), models.EncryptionKeyStatusRetired, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	keys := services.NewTenantKeyService(db, wrapper, config.EncryptionConfig{})
+	count, err := keys.ReencryptTenant(ctx, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	expectKeyVersion(mock, tenantID, 2, models.EncryptionKeyStatusActive, v2)
+	plaintext, err := keys.Decrypt(ctx, tenantID, 2, newCiphertext, userID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", plaintext)
+}
+
+func TestTenantKeyService_ReencryptPendingEncryptsLegacyPlaintext(t *testing.T) {
+	ctx := context.Background()
+	db, mock := newMockDB(t)
+	wrapper := newKeyWrapper(t)
+	tenantID := uuid.New()
+	userID := uuid.New()
+	credentialID := uuid.New()
+	v1 := newWrappedKey(t, wrapper)
+
+	// No tenant has rotated keys, but one still has a plaintext MFA secret
+	mock.ExpectQuery(`SELECT DISTINCT "tenant_id" FROM "tenant_encryption_keys" WHERE status = \$1`).
+		WithArgs(models.EncryptionKeyStatusDecryptOnly).
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id"}))
+	mock.ExpectQuery(`SELECT DISTINCT "tenant_id" FROM "tenant_credentials" WHERE key_version = 0`).
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id"}).AddRow(tenantID))
+
+	var ciphertext string
+	expectActiveKey(mock, tenantID, 1, v1)
+	mock.ExpectQuery(`SELECT id, user_id, tenant_id, mfa_secret, key_version FROM "tenant_credentials" WHERE tenant_id = \$1 AND key_version <> \$2`).
+		WithArgs(tenantID, 1, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "tenant_id", "mfa_secret", "key_version"}).
+			AddRow(credentialID, userID, tenantID, "JBSWY3DPEHPK3PXP", 0))
+	expectActiveKey(mock, tenantID, 1, v1)
+	mock.ExpectExec(`UPDATE "tenant_credentials" SET "key_version"=\$1,"mfa_secret"=\$2,"updated_at"=\$3 WHERE id = \$4 AND key_version = \$5`).
+		WithArgs(1, captureArg{&ciphertext}, sqlmock.AnyArg(), credentialID, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id, user_id, tenant_id, mfa_secret, key_version FROM "tenant_credentials"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT \* FROM "tenant_encryption_keys" WHERE tenant_id = \$1 AND status = \$2`).
+		WithArgs(tenantID, models.EncryptionKeyStatusDecryptOnly).
+		WillReturnRows(sqlmock.NewRows(keyColumns))
+
+	keys := services.NewTenantKeyService(db, wrapper, config.EncryptionConfig{})
+	count, err := keys.ReencryptPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.NotContains(t, ciphertext, "JBSWY3DPEHPK3PXP")
+	expectKeyVersion(mock, tenantID, 1, models.EncryptionKeyStatusActive, v1)
+	plaintext, err := keys.Decrypt(ctx, tenantID, 1, ciphertext, userID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", plaintext)
+}
@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestPermissionGranted(t *testing.T) {
+	tests := []struct {
+		name       string
+		grants     []string
+		permission string
+		want       bool
+	}{
+		{"exact grant", []string{"orders:view"}, "orders:view", true},
+		{"other action", []string{"orders:view"}, "orders:edit", false},
+		{"resource wildcard", []string{"catalog:*"}, "catalog:products:view", true},
+		{"nested wildcard", []string{"catalog:products:*"}, "catalog:products:edit", true},
+		{"nested wildcard does not cover siblings", []string{"catalog:products:*"}, "catalog:categories:view", false},
+		{"wildcard needs the separator", []string{"order:*"}, "orders:view", false},
+		{"global wildcard", []string{models.PermissionWildcard}, "settings:edit", true},
+		{"no grants", nil, "orders:view", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.PermissionGranted(tt.grants, tt.permission))
+		})
+	}
+}
+
+func TestEscalatedPermissions(t *testing.T) {
+	builtIn := models.BuiltInRolePermissions()
+
+	tests := []struct {
+		name     string
+		previous []string
+		next     []string
+		want     []string
+	}{
+		{"viewer to admin", builtIn[models.MembershipRoleViewer], builtIn[models.MembershipRoleAdmin], []string{models.PermissionWildcard}},
+		{"admin to viewer", builtIn[models.MembershipRoleAdmin], builtIn[models.MembershipRoleViewer], nil},
+		{"covered by wildcard", []string{"catalog:*"}, []string{"catalog:products:edit"}, nil},
+		{"new resource", []string{"orders:view"}, []string{"orders:view", "customers:edit"}, []string{"customers:edit"}},
+		{"from nothing", nil, []string{"orders:view"}, []string{"orders:view"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.EscalatedPermissions(tt.previous, tt.next))
+		})
+	}
+}
+
+func TestNormalizePermissions(t *testing.T) {
+	permissions, err := services.NormalizePermissions([]string{" Orders:View ", "catalog:*", "orders:view", "customers:view"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"catalog:*", "customers:view", "orders:view"}, permissions)
+
+	invalid := [][]string{
+		nil,
+		{"*"},
+		{"orders"},
+		{"orders:"},
+		{"orders:*:view"},
+		{"orders view"},
+	}
+	for _, perms := range invalid {
+		_, err := services.NormalizePermissions(perms)
+		_, isValidation := services.IsValidationError(err)
+		assert.True(t, isValidation, "expected %v to be rejected", perms)
+	}
+
+	tooMany := make([]string, services.MaxRolePermissions+1)
+	for i := range tooMany {
+		tooMany[i] = "orders:view"
+	}
+	_, err = services.NormalizePermissions(tooMany)
+	assert.Error(t, err)
+}
+
+func TestMembershipPermissionList(t *testing.T) {
+	owner := &models.UserTenantMembership{Role: models.MembershipRoleOwner}
+	assert.True(t, models.PermissionGranted(owner.PermissionList(), "settings:edit"))
+
+	// Built-in roles ignore permissions stored on the membership
+	viewer := &models.UserTenantMembership{Role: models.MembershipRoleViewer, Permissions: models.JSONB(`["orders:*"]`)}
+	assert.True(t, models.PermissionGranted(viewer.PermissionList(), "orders:view"))
+	assert.False(t, models.PermissionGranted(viewer.PermissionList(), "orders:edit"))
+
+	custom := &models.UserTenantMembership{Role: "support-agent", Permissions: models.JSONB(`["orders:view","customers:*"]`)}
+	assert.True(t, models.PermissionGranted(custom.PermissionList(), "customers:edit"))
+	assert.False(t, models.PermissionGranted(custom.PermissionList(), "orders:edit"))
+
+	// The legacy '{}' default grants nothing
+	legacy := &models.UserTenantMembership{Role: "support-agent", Permissions: models.JSONB(`{}`)}
+	assert.Empty(t, legacy.PermissionList())
+}
+
+func TestIsBuiltInRole(t *testing.T) {
+	for _, role := range []string{models.MembershipRoleOwner, models.MembershipRoleAdmin, models.MembershipRoleManager, models.MembershipRoleMember, models.MembershipRoleViewer} {
+		assert.True(t, models.IsBuiltInRole(role), role)
+	}
+	assert.False(t, models.IsBuiltInRole("support-agent"))
+}
@@ -0,0 +1,58 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestCheckSlugChange(t *testing.T) {
+	now := time.Now()
+	recently := now.Add(-24 * time.Hour)
+	longAgo := now.Add(-60 * 24 * time.Hour)
+	cooldown := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		tenant models.Tenant
+		want   error
+	}{
+		{"never renamed", models.Tenant{Status: "active"}, nil},
+		{"renamed before the cooldown", models.Tenant{Status: "active", SlugChangedAt: &longAgo}, nil},
+		{"renamed within the cooldown", models.Tenant{Status: "active", SlugChangedAt: &recently}, services.ErrSlugChangeCooldown},
+		{"custom domain", models.Tenant{Status: "active", UseCustomDomain: true}, services.ErrSlugChangeCustomDomain},
+		{"still creating", models.Tenant{Status: "creating"}, services.ErrSlugChangeNotActive},
+		{"suspended", models.Tenant{Status: models.TenantStatusSuspended}, services.ErrSlugChangeNotActive},
+		{"pending deletion", models.Tenant{Status: models.TenantStatusPendingDeletion}, services.ErrSlugChangeNotActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := services.CheckSlugChange(&tt.tenant, cooldown, now)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	// Without a cooldown a tenant can be renamed again right away
+	assert.NoError(t, services.CheckSlugChange(&models.Tenant{Status: "active", SlugChangedAt: &recently}, 0, now))
+}
+
+func TestTenantHosts(t *testing.T) {
+	admin, storefront, api := services.TenantHosts("new-store", "tesserix.app")
+	assert.Equal(t, "new-store-admin.tesserix.app", admin)
+	assert.Equal(t, "new-store.tesserix.app", storefront)
+	assert.Equal(t, "new-store-api.tesserix.app", api)
+}
+
+func TestSlugChangeService_RedirectCheckInterval(t *testing.T) {
+	assert.Equal(t, time.Hour, services.NewSlugChangeService(nil, nil, config.SlugChangeConfig{}, "").RedirectCheckInterval())
+	assert.Equal(t, 5*time.Minute, services.NewSlugChangeService(nil, nil, config.SlugChangeConfig{CheckIntervalMinutes: 5}, "").RedirectCheckInterval())
+}
@@ -0,0 +1,229 @@
+package unit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestResolveSSORole(t *testing.T) {
+	mappings := map[string]string{
+		"store-admins":  models.MembershipRoleAdmin,
+		"/ops/managers": models.MembershipRoleManager,
+		"read-only":     models.MembershipRoleViewer,
+		"contractors":   models.MembershipRoleMember,
+	}
+
+	tests := []struct {
+		name   string
+		groups []string
+		want   string
+	}{
+		{"highest mapped role wins", []string{"read-only", "store-admins"}, models.MembershipRoleAdmin},
+		{"keycloak group path", []string{"/store-admins"}, models.MembershipRoleAdmin},
+		{"mapping given as path", []string{"/ops/managers", "read-only"}, models.MembershipRoleManager},
+		{"no mapped group uses default", []string{"everyone"}, models.MembershipRoleViewer},
+		{"no groups uses default", nil, models.MembershipRoleViewer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, services.ResolveSSORole(mappings, models.MembershipRoleViewer, tt.groups))
+		})
+	}
+}
+
+func TestResolveSSORole_NeverGrantsOwner(t *testing.T) {
+	mappings := map[string]string{"founders": models.MembershipRoleOwner}
+	assert.Equal(t, models.MembershipRoleMember, services.ResolveSSORole(mappings, "", []string{"founders"}))
+}
+
+func TestNormalizeSSODomains(t *testing.T) {
+	domains, err := services.NormalizeSSODomains([]string{" Acme.COM ", "@eu.acme.com", "acme.com"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme.com", "eu.acme.com"}, domains)
+
+	_, err = services.NormalizeSSODomains([]string{"not a domain"})
+	_, isValidation := services.IsValidationError(err)
+	assert.True(t, isValidation)
+
+	_, err = services.NormalizeSSODomains(nil)
+	assert.Error(t, err)
+}
+
+func TestTenantSSOConfig_RoutesLogin(t *testing.T) {
+	sso := &models.TenantSSOConfig{
+		Enabled:            true,
+		Enforced:           true,
+		ProvisioningStatus: models.SSOProvisioningProvisioned,
+		Domains:            models.JSONB(`["acme.com"]`),
+	}
+
+	assert.True(t, sso.RoutesLogin("jane@ACME.com"))
+	assert.False(t, sso.RoutesLogin("jane@acme.com.evil.io"))
+	assert.False(t, sso.RoutesLogin("jane@gmail.com"))
+
+	sso.Enforced = false
+	assert.False(t, sso.RoutesLogin("jane@acme.com"), "password login stays available until SSO is enforced")
+
+	sso.Enforced = true
+	sso.ProvisioningStatus = models.SSOProvisioningFailed
+	assert.False(t, sso.RoutesLogin("jane@acme.com"), "logins are not routed to an IdP that failed to provision")
+}
+
+func TestParseSAMLMetadata(t *testing.T) {
+	now := time.Now()
+	cert := selfSignedCertificate(t, now.Add(-time.Hour), now.Add(365*24*time.Hour))
+
+	metadata, err := services.ParseSAMLMetadata([]byte(samlMetadata(cert, "https://idp.acme.com/sso")), now, 7*24*time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://idp.acme.com/entity", metadata.EntityID)
+	assert.Equal(t, "https://idp.acme.com/sso", metadata.SSOURL)
+	assert.Equal(t, "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST", metadata.SSOBinding)
+	assert.Equal(t, "https://idp.acme.com/slo", metadata.SLOURL)
+	assert.Equal(t, cert, metadata.SigningCertificate)
+	assert.Contains(t, metadata.CertificateSubject, "idp.acme.com")
+}
+
+func TestParseSAMLMetadata_Rejects(t *testing.T) {
+	now := time.Now()
+	valid := selfSignedCertificate(t, now.Add(-time.Hour), now.Add(365*24*time.Hour))
+	expiring := selfSignedCertificate(t, now.Add(-time.Hour), now.Add(48*time.Hour))
+
+	tests := []struct {
+		name     string
+		metadata string
+	}{
+		{"not xml", "metadata"},
+		{"no identity provider", `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://sp"></EntityDescriptor>`},
+		{"plain http sso url", samlMetadata(valid, "http://idp.acme.com/sso")},
+		{"certificate expires too soon", samlMetadata(expiring, "https://idp.acme.com/sso")},
+		{"certificate not decodable", samlMetadata("bm90IGEgY2VydA==", "https://idp.acme.com/sso")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := services.ParseSAMLMetadata([]byte(tt.metadata), now, 7*24*time.Hour)
+			require.Error(t, err)
+			_, isValidation := services.IsValidationError(err)
+			assert.True(t, isValidation)
+		})
+	}
+}
+
+func samlMetadata(cert, ssoURL string) string {
+	return fmt.Sprintf(`<?xml version="1.0"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="https://idp.acme.com/entity">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>
+        %s
+      </ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.acme.com/slo"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="%s/redirect"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="%s"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`, cert, ssoURL, ssoURL)
+}
+
+func selfSignedCertificate(t *testing.T, notBefore, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.acme.com"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+func TestOIDCDiscoveryURL(t *testing.T) {
+	assert.Equal(t, "https://login.acme.com/realms/staff/.well-known/openid-configuration", services.OIDCDiscoveryURL("https://login.acme.com/realms/staff"))
+	assert.Equal(t, "https://login.acme.com/realms/staff/.well-known/openid-configuration", services.OIDCDiscoveryURL("https://login.acme.com/realms/staff/"))
+	assert.Equal(t, "https://login.acme.com/.well-known/openid-configuration", services.OIDCDiscoveryURL(" https://login.acme.com/.well-known/openid-configuration "))
+}
+
+func TestParseOIDCDiscovery(t *testing.T) {
+	discoveryURL := "https://login.acme.com/.well-known/openid-configuration"
+
+	discovery, err := services.ParseOIDCDiscovery([]byte(oidcDiscovery("https://login.acme.com", "https://login.acme.com/oauth2/token")), discoveryURL)
+	require.NoError(t, err)
+	assert.Equal(t, "https://login.acme.com", discovery.Issuer)
+	assert.Equal(t, "https://login.acme.com/oauth2/authorize", discovery.AuthorizationEndpoint)
+	assert.Equal(t, "https://login.acme.com/oauth2/token", discovery.TokenEndpoint)
+	assert.Equal(t, "https://login.acme.com/oauth2/keys", discovery.JWKSURI)
+}
+
+func TestParseOIDCDiscovery_Rejects(t *testing.T) {
+	discoveryURL := "https://login.acme.com/.well-known/openid-configuration"
+
+	tests := []struct {
+		name     string
+		document string
+	}{
+		{"not json", "<html></html>"},
+		{"missing endpoints", `{"issuer": "https://login.acme.com"}`},
+		{"plain http endpoint", oidcDiscovery("https://login.acme.com", "http://login.acme.com/oauth2/token")},
+		{"issuer mismatch", oidcDiscovery("https://evil.example.com", "https://login.acme.com/oauth2/token")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := services.ParseOIDCDiscovery([]byte(tt.document), discoveryURL)
+			require.Error(t, err)
+			validationErr, isValidation := services.IsValidationError(err)
+			require.True(t, isValidation)
+			assert.Equal(t, "discovery_url", validationErr.Field)
+			assert.NotContains(t, validationErr.Message, "evil.example.com")
+		})
+	}
+}
+
+func TestTenantSSOService_FetchRefusesPrivateAddresses(t *testing.T) {
+	var requests int32
+	idp := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "internal admin console", http.StatusForbidden)
+	}))
+	defer idp.Close()
+
+	svc := services.NewTenantSSOService(nil, nil, config.SSOConfig{MetadataFetchTimeout: 5, MetadataMaxBytes: 1 << 20})
+	_, err := svc.ValidateMetadata(context.Background(), services.ValidateSAMLMetadataRequest{MetadataURL: idp.URL + "/metadata"})
+	require.Error(t, err)
+
+	validationErr, isValidation := services.IsValidationError(err)
+	require.True(t, isValidation)
+	assert.Equal(t, "metadata_url", validationErr.Field)
+	assert.Equal(t, "failed to fetch metadata from metadata_url", validationErr.Message)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests), "the loopback server must not be contacted")
+}
+
+func oidcDiscovery(issuer, tokenEndpoint string) string {
+	return fmt.Sprintf(`{
+  "issuer": %q,
+  "authorization_endpoint": "https://login.acme.com/oauth2/authorize",
+  "token_endpoint": %q,
+  "jwks_uri": "https://login.acme.com/oauth2/keys",
+  "response_types_supported": ["code"]
+}`, issuer, tokenEndpoint)
+}
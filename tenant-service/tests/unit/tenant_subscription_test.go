@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/services"
+)
+
+func TestDefaultPlanFeatures_GrowWithTier(t *testing.T) {
+	features := models.DefaultPlanFeatures()
+	order := []string{models.PricingTierFree, models.PricingTierStarter, models.PricingTierProfessional, models.PricingTierEnterprise}
+
+	for _, tier := range order {
+		require.Contains(t, features, tier)
+		for key := range features[tier] {
+			assert.True(t, models.IsKnownFeature(key), "%s grants unknown feature %s", tier, key)
+		}
+	}
+
+	// Every feature of a tier is also granted by the tiers above it
+	for i := 1; i < len(order); i++ {
+		for key, enabled := range features[order[i-1]] {
+			assert.Equal(t, enabled, features[order[i]][key], "%s should keep %s", order[i], key)
+		}
+	}
+
+	assert.Empty(t, features[models.PricingTierFree])
+	assert.True(t, features[models.PricingTierEnterprise][models.FeatureSSO])
+	assert.False(t, features[models.PricingTierProfessional][models.FeatureSSO])
+}
+
+func TestTenantPlan_FeatureFlagsIgnoreNonBooleans(t *testing.T) {
+	plan := models.TenantPlan{Features: models.JSONB(`{"sso": true, "white_label": false, "api_access": "yes"}`)}
+
+	assert.Equal(t, map[string]bool{"sso": true, "white_label": false}, plan.FeatureFlags())
+
+	empty := models.TenantPlan{}
+	assert.Empty(t, empty.FeatureFlags())
+
+	invalid := models.TenantPlan{Features: models.JSONB(`not json`)}
+	assert.Empty(t, invalid.FeatureFlags())
+}
+
+func TestTenantSubscription_IsTrialing(t *testing.T) {
+	now := time.Now()
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		subscription models.TenantSubscription
+		want         bool
+	}{
+		{"trial running", models.TenantSubscription{Status: models.SubscriptionStatusTrialing, TrialEndsAt: &future}, true},
+		{"trial ended", models.TenantSubscription{Status: models.SubscriptionStatusTrialing, TrialEndsAt: &past}, false},
+		{"trial without end", models.TenantSubscription{Status: models.SubscriptionStatusTrialing}, false},
+		{"active", models.TenantSubscription{Status: models.SubscriptionStatusActive, TrialEndsAt: &future}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.subscription.IsTrialing(now))
+		})
+	}
+}
+
+func TestSubscriptionService_ChangePlanValidation(t *testing.T) {
+	svc := services.NewSubscriptionService(nil, config.SubscriptionConfig{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		req         services.ChangePlanRequest
+		selfService bool
+		field       string
+	}{
+		{"unknown billing cycle", services.ChangePlanRequest{PlanCode: "starter", BillingCycle: "weekly"}, true, "billing_cycle"},
+		{"negative trial", services.ChangePlanRequest{PlanCode: "starter", TrialDays: -1}, false, "trial_days"},
+		{"trial too long", services.ChangePlanRequest{PlanCode: "starter", TrialDays: services.MaxTrialDays + 1}, false, "trial_days"},
+		{"self-service trial", services.ChangePlanRequest{PlanCode: "starter", TrialDays: 14}, true, "trial_days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ChangePlan(ctx, uuid.New(), tt.req, nil, tt.selfService)
+			validationErr, ok := services.IsValidationError(err)
+			require.True(t, ok, "expected validation error, got %v", err)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
+
+func TestSubscriptionService_RejectsUnknownFeatureOverrides(t *testing.T) {
+	svc := services.NewSubscriptionService(nil, config.SubscriptionConfig{})
+	enabled := true
+
+	_, err := svc.UpdateFeatureOverrides(context.Background(), uuid.New(), services.UpdateFeatureOverridesRequest{
+		Overrides: map[string]*bool{models.FeatureSSO: &enabled, "teleportation": &enabled},
+	})
+
+	_, ok := services.IsValidationError(err)
+	assert.True(t, ok, "expected validation error, got %v", err)
+}
+
+func TestSubscriptionService_TrialCheckInterval(t *testing.T) {
+	assert.Equal(t, 15*time.Minute, services.NewSubscriptionService(nil, config.SubscriptionConfig{}).TrialCheckInterval())
+	assert.Equal(t, 5*time.Minute, services.NewSubscriptionService(nil, config.SubscriptionConfig{TrialCheckIntervalMinutes: 5}).TrialCheckInterval())
+}
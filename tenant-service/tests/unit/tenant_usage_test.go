@@ -0,0 +1,57 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"tenant-service/internal/config"
+	"tenant-service/internal/models"
+	"tenant-service/internal/nats"
+	"tenant-service/internal/services"
+)
+
+func TestPricingTiers_QuotasGrowWithTier(t *testing.T) {
+	tiers := models.GetPricingTiers()
+	order := []string{models.PricingTierFree, models.PricingTierStarter, models.PricingTierProfessional}
+
+	for i := 1; i < len(order); i++ {
+		lower, higher := tiers[order[i-1]], tiers[order[i]]
+		assert.Greater(t, higher.MaxAPICallsPerMonth, lower.MaxAPICallsPerMonth, order[i])
+		assert.Greater(t, higher.MaxEmailsPerMonth, lower.MaxEmailsPerMonth, order[i])
+	}
+
+	enterprise := tiers[models.PricingTierEnterprise]
+	assert.Equal(t, -1, enterprise.MaxAPICallsPerMonth)
+	assert.Equal(t, -1, enterprise.MaxEmailsPerMonth)
+}
+
+func TestUsageService_MemberQuotaNotCheckedWhenEnforcementDisabled(t *testing.T) {
+	svc := services.NewUsageService(nil, config.UsageConfig{EnforceQuotas: false})
+
+	assert.NoError(t, svc.CheckMemberQuota(context.Background(), uuid.New()))
+}
+
+func TestUsageService_IgnoresEventsThatAreNotMetered(t *testing.T) {
+	svc := services.NewUsageService(nil, config.UsageConfig{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		event *nats.DocumentUsageEvent
+	}{
+		{"other document event", &nats.DocumentUsageEvent{EventType: "document.updated", TenantID: uuid.NewString(), DocumentID: "doc-1", FileSize: 10}},
+		{"empty file", &nats.DocumentUsageEvent{EventType: nats.SubjectDocumentUploaded, TenantID: uuid.NewString(), DocumentID: "doc-1"}},
+		{"non-tenant scope", &nats.DocumentUsageEvent{EventType: nats.SubjectDocumentUploaded, TenantID: "onboarding", DocumentID: "doc-1", FileSize: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, svc.RecordDocumentEvent(ctx, tt.event))
+		})
+	}
+
+	assert.NoError(t, svc.RecordNotificationSent(ctx, &nats.NotificationSentEvent{
+		TenantID: uuid.NewString(), NotificationID: "n-1", Channel: "SMS", Units: 1,
+	}))
+}
@@ -0,0 +1,54 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tenant-service/internal/config"
+	"tenant-service/internal/services"
+)
+
+func TestWebhookRetryDelay_BacksOffExponentially(t *testing.T) {
+	assert.Equal(t, 30*time.Second, services.WebhookRetryDelay(0))
+	assert.Equal(t, 30*time.Second, services.WebhookRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, services.WebhookRetryDelay(2))
+	assert.Equal(t, 8*time.Minute, services.WebhookRetryDelay(3))
+	assert.Equal(t, 6*time.Hour, services.WebhookRetryDelay(20))
+}
+
+func TestWebhookBulkReplay_ValidatesSelection(t *testing.T) {
+	svc := services.NewWebhookDeliveryService(nil, config.WebhookConfig{})
+
+	tests := []struct {
+		name      string
+		req       services.WebhookBulkReplayRequest
+		wantField string
+	}{
+		{"requires ids or filter", services.WebhookBulkReplayRequest{}, "event_ids"},
+		{"caps event ids", services.WebhookBulkReplayRequest{EventIDs: make([]uuid.UUID, services.MaxWebhookBulkReplay+1)}, "event_ids"},
+		{"caps limit", services.WebhookBulkReplayRequest{EventType: "onboarding.completed", Limit: services.MaxWebhookBulkReplay + 1}, "limit"},
+		{"rejects negative limit", services.WebhookBulkReplayRequest{EventType: "onboarding.completed", Limit: -1}, "limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.BulkReplay(context.Background(), uuid.New(), tt.req)
+
+			validationErr, ok := services.IsValidationError(err)
+			require.True(t, ok, "expected validation error, got %v", err)
+			assert.Equal(t, tt.wantField, validationErr.Field)
+		})
+	}
+}
+
+func TestNewWebhookDeliveryService_DefaultsInterval(t *testing.T) {
+	svc := services.NewWebhookDeliveryService(nil, config.WebhookConfig{})
+	assert.Equal(t, time.Minute, svc.Interval())
+
+	svc = services.NewWebhookDeliveryService(nil, config.WebhookConfig{DeliveryIntervalSeconds: 15})
+	assert.Equal(t, 15*time.Second, svc.Interval())
+}
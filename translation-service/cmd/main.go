@@ -22,6 +22,7 @@ import (
 	"translation-service/internal/middleware"
 	"translation-service/internal/models"
 	"translation-service/internal/repository"
+	"translation-service/internal/warming"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/Tesseract-Nexus/go-shared/tracing"
@@ -105,7 +106,8 @@ func main() {
 	}
 
 	// Initialize translation providers
-	// Provider priority: LibreTranslate (1) -> Bergamot (2) -> Hugging Face (3) -> Google (4)
+	// Provider priority: LibreTranslate (1) -> Bergamot (2) -> Hugging Face (3) -> Google (4) -> DeepL (5) -> LLM (6)
+	// Requests with formality or tone hints prefer the providers that can apply them (DeepL, LLM)
 	var providers []clients.TranslationProvider
 
 	// 1. LibreTranslate (primary provider - open source, self-hosted)
@@ -162,15 +164,46 @@ func main() {
 		log.Warn("Google Translate API key not configured - some languages may not be available")
 	}
 
+	// 5. DeepL (paid - preferred for requests with a formality hint)
+	if cfg.Translation.DeepLKey != "" {
+		deepL := clients.NewDeepLClient(
+			cfg.Translation.DeepLKey,
+			cfg.Translation.DeepLURL,
+			log,
+		)
+		providers = append(providers, deepL)
+		log.Info("DeepL enabled for formality-controlled translations (priority 5)")
+	} else {
+		log.Info("DeepL not configured - formality hints fall back to the LLM provider if available")
+	}
+
+	// 6. LLM (OpenAI-compatible - preferred for requests with a tone hint)
+	if cfg.Translation.LLMURL != "" {
+		llm := clients.NewLLMClient(
+			cfg.Translation.LLMKey,
+			cfg.Translation.LLMURL,
+			cfg.Translation.LLMModel,
+			log,
+		)
+		providers = append(providers, llm)
+		log.Info("LLM translation enabled for tone-controlled translations (priority 6)")
+	} else {
+		log.Info("LLM translation not configured - tone hints will be ignored")
+	}
+
 	// Create the orchestrator with provider chain
 	orchestrator := clients.NewTranslationOrchestrator(providers, log)
 
+	// Cache warmer refreshes hot keys before expiry and runs precompute jobs
+	warmer := warming.NewWarmer(repo, redisCache, orchestrator, &cfg.Translation, log)
+
 	// Initialize handler with orchestrator
 	handler := handlers.NewTranslationHandler(
 		repo,
 		redisCache,
 		orchestrator,
 		libreTranslate, // Keep reference for language detection
+		warmer,
 		&cfg.Translation,
 		log,
 	)
@@ -224,12 +257,15 @@ func main() {
 		v1.POST("/translate/batch", rateLimiter.Middleware(), handler.TranslateBatch)
 		v1.POST("/detect", rateLimiter.Middleware(), handler.DetectLanguage)
 		v1.GET("/languages", handler.GetLanguages)
+		v1.POST("/localize/format", rateLimiter.Middleware(), handler.FormatLocalized)
 
 		// Tenant-specific endpoints
 		v1.GET("/stats", middleware.RequireTenantID(), handler.GetStats)
 		v1.GET("/preferences", middleware.RequireTenantID(), handler.GetPreference)
 		v1.PUT("/preferences", middleware.RequireTenantID(), handler.UpdatePreference)
 		v1.DELETE("/cache", middleware.RequireTenantID(), handler.InvalidateCache)
+		v1.POST("/cache/precompute", middleware.RequireTenantID(), handler.PrecomputeTranslations)
+		v1.GET("/cache/precompute/:id", middleware.RequireTenantID(), handler.GetPrecomputeJob)
 
 		// User-specific language preference endpoints
 		// These persist user's preferred language in the database
@@ -245,6 +281,10 @@ func main() {
 	// Start background cleanup task
 	go startCleanupTask(repo, log)
 
+	// Start cache warmer
+	warmerCtx, stopWarmer := context.WithCancel(context.Background())
+	go warmer.Start(warmerCtx)
+
 	// Start server
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	srv := &http.Server{
@@ -278,6 +318,8 @@ func main() {
 		log.WithError(err).Error("Server forced to shutdown")
 	}
 
+	stopWarmer()
+
 	// Close Redis connection
 	if redisCache != nil {
 		if err := redisCache.Close(); err != nil {
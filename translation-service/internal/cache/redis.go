@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -11,6 +12,10 @@ import (
 	"translation-service/internal/models"
 )
 
+// hotKeysKey is a sorted set of translation cache keys scored by recent hits
+// Kept outside the trans:<tenant>:* namespace so tenant invalidation patterns never match it
+const hotKeysKey = "trans_hot_keys"
+
 // TranslationCache provides Redis-based caching for translations
 type TranslationCache struct {
 	client *redis.Client
@@ -25,6 +30,17 @@ type CachedTranslation struct {
 	TargetLang     string    `json:"target_lang"`
 	Provider       string    `json:"provider"`
 	CachedAt       time.Time `json:"cached_at"`
+
+	// Request fields needed to re-translate the entry when warming (absent on older entries)
+	TenantID   string `json:"tenant_id,omitempty"`
+	SourceText string `json:"source_text,omitempty"`
+	Context    string `json:"context,omitempty"`
+}
+
+// HotKey is a frequently read cache key and its decayed hit count
+type HotKey struct {
+	Key  string
+	Hits float64
 }
 
 // NewTranslationCache creates a new Redis cache instance
@@ -81,6 +97,11 @@ func (c *TranslationCache) Get(ctx context.Context, tenantID, sourceLang, target
 		return nil, nil
 	}
 
+	// Track popularity so the warming worker can refresh the entry before it expires
+	if err := c.client.ZIncrBy(ctx, hotKeysKey, 1, key).Err(); err != nil {
+		c.logger.WithError(err).Debug("Failed to record cache hit")
+	}
+
 	return &cached, nil
 }
 
@@ -95,6 +116,9 @@ func (c *TranslationCache) Set(ctx context.Context, tenantID, sourceLang, target
 		TargetLang:     targetLang,
 		Provider:       provider,
 		CachedAt:       time.Now(),
+		TenantID:       tenantID,
+		SourceText:     sourceText,
+		Context:        translationContext,
 	}
 
 	val, err := json.Marshal(cached)
@@ -160,6 +184,68 @@ func (c *TranslationCache) deleteByPattern(ctx context.Context, pattern string)
 	return nil
 }
 
+// HotKeys returns up to limit cache keys with at least minHits recent hits, most popular first
+func (c *TranslationCache) HotKeys(ctx context.Context, limit int, minHits float64) ([]HotKey, error) {
+	entries, err := c.client.ZRevRangeByScoreWithScores(ctx, hotKeysKey, &redis.ZRangeBy{
+		Min:   strconv.FormatFloat(minHits, 'f', -1, 64),
+		Max:   "+inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hot keys: %w", err)
+	}
+
+	hotKeys := make([]HotKey, 0, len(entries))
+	for _, entry := range entries {
+		if key, ok := entry.Member.(string); ok {
+			hotKeys = append(hotKeys, HotKey{Key: key, Hits: entry.Score})
+		}
+	}
+	return hotKeys, nil
+}
+
+// GetByKey returns a cached entry and its remaining TTL by raw cache key
+// Returns nil when the key no longer exists
+func (c *TranslationCache) GetByKey(ctx context.Context, key string) (*CachedTranslation, time.Duration, error) {
+	pipe := c.client.Pipeline()
+	getCmd := pipe.Get(ctx, key)
+	ttlCmd := pipe.TTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, 0, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+
+	val, err := getCmd.Result()
+	if err == redis.Nil {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var cached CachedTranslation
+	if err := json.Unmarshal([]byte(val), &cached); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal cached translation: %w", err)
+	}
+	return &cached, ttlCmd.Val(), nil
+}
+
+// ForgetHotKey stops tracking a key (e.g. after it expired or was invalidated)
+func (c *TranslationCache) ForgetHotKey(ctx context.Context, key string) error {
+	return c.client.ZRem(ctx, hotKeysKey, key).Err()
+}
+
+// DecayHotKeys halves all hit counts and drops keys that fell below one hit
+// Called once per warming cycle so popularity reflects recent traffic
+func (c *TranslationCache) DecayHotKeys(ctx context.Context) error {
+	if err := c.client.ZUnionStore(ctx, hotKeysKey, &redis.ZStore{
+		Keys:    []string{hotKeysKey},
+		Weights: []float64{0.5},
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to decay hot keys: %w", err)
+	}
+	return c.client.ZRemRangeByScore(ctx, hotKeysKey, "-inf", "(1").Err()
+}
+
 // GetStats returns cache statistics
 func (c *TranslationCache) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	info, err := c.client.Info(ctx, "stats", "memory").Result()
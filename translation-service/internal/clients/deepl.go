@@ -0,0 +1,282 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DeepLClient handles communication with the DeepL API
+// Used for styled translations since DeepL can switch between formal and informal register
+// Priority: 4 (paid, after Google for unstyled translations)
+type DeepLClient struct {
+	apiKey     string
+	httpClient *http.Client
+	logger     *logrus.Entry
+	baseURL    string
+	priority   int
+
+	// Health tracking
+	healthy      bool
+	lastHealthy  time.Time
+	failureCount int
+	healthMu     sync.RWMutex
+}
+
+// DeepLTranslateRequest represents a translation request to the DeepL API
+type DeepLTranslateRequest struct {
+	Text       []string `json:"text"`
+	SourceLang string   `json:"source_lang,omitempty"`
+	TargetLang string   `json:"target_lang"`
+	Formality  string   `json:"formality,omitempty"`
+}
+
+// DeepLTranslateResponse represents a translation response from the DeepL API
+type DeepLTranslateResponse struct {
+	Translations []struct {
+		DetectedSourceLanguage string `json:"detected_source_language"`
+		Text                   string `json:"text"`
+	} `json:"translations"`
+	Message string `json:"message,omitempty"`
+}
+
+// deepLLanguages are the languages DeepL translates, keyed by our language code
+var deepLLanguages = map[string]bool{
+	"ar": true, "bg": true, "cs": true, "da": true, "de": true, "el": true,
+	"en": true, "es": true, "et": true, "fi": true, "fr": true, "hu": true,
+	"id": true, "it": true, "ja": true, "ko": true, "lt": true, "lv": true,
+	"nb": true, "nl": true, "pl": true, "pt": true, "ro": true, "ru": true,
+	"sk": true, "sl": true, "sv": true, "tr": true, "uk": true, "zh": true,
+}
+
+// deepLFormalityLanguages are the target languages DeepL applies formality to
+var deepLFormalityLanguages = map[string]bool{
+	"de": true, "es": true, "fr": true, "it": true, "ja": true,
+	"nl": true, "pl": true, "pt": true, "ru": true,
+}
+
+// NewDeepLClient creates a new DeepL client
+// Free-plan keys (suffix ":fx") use the free API host unless baseURL is set
+func NewDeepLClient(apiKey string, baseURL string, logger *logrus.Entry) *DeepLClient {
+	if baseURL == "" {
+		baseURL = "https://api.deepl.com"
+		if strings.HasSuffix(apiKey, ":fx") {
+			baseURL = "https://api-free.deepl.com"
+		}
+	}
+
+	return &DeepLClient{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger:   logger,
+		priority: 4, // After Google; preferred only when formality is requested
+		healthy:  true,
+	}
+}
+
+// Name returns the provider name
+func (c *DeepLClient) Name() ProviderName {
+	return ProviderDeepL
+}
+
+// Priority returns the provider priority
+func (c *DeepLClient) Priority() int {
+	return c.priority
+}
+
+// IsConfigured returns true if the DeepL client is properly configured
+func (c *DeepLClient) IsConfigured() bool {
+	return c.apiKey != ""
+}
+
+// IsHealthy checks if the provider is currently healthy
+func (c *DeepLClient) IsHealthy(ctx context.Context) bool {
+	c.healthMu.RLock()
+	healthy := c.healthy
+	lastHealthy := c.lastHealthy
+	failureCount := c.failureCount
+	c.healthMu.RUnlock()
+
+	// If unhealthy, check if enough time has passed for retry
+	if !healthy && failureCount > 0 {
+		backoffDuration := time.Duration(failureCount) * 30 * time.Second
+		if backoffDuration > 5*time.Minute {
+			backoffDuration = 5 * time.Minute
+		}
+		if time.Since(lastHealthy) < backoffDuration {
+			return false
+		}
+	}
+
+	return healthy
+}
+
+// SupportsLanguagePair checks if DeepL supports the language pair
+func (c *DeepLClient) SupportsLanguagePair(sourceLang, targetLang string) bool {
+	if sourceLang != "" && sourceLang != "auto" && !deepLLanguages[baseLanguage(sourceLang)] {
+		return false
+	}
+	return deepLLanguages[baseLanguage(targetLang)]
+}
+
+// StyleCapabilities reports formality support for the target language; DeepL has no tone control
+func (c *DeepLClient) StyleCapabilities(targetLang string) StyleCapabilities {
+	return StyleCapabilities{Formality: deepLFormalityLanguages[baseLanguage(targetLang)]}
+}
+
+// markHealthy marks the provider as healthy
+func (c *DeepLClient) markHealthy() {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.healthy = true
+	c.lastHealthy = time.Now()
+	c.failureCount = 0
+}
+
+// markUnhealthy marks the provider as unhealthy
+func (c *DeepLClient) markUnhealthy(reason string) {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.healthy = false
+	c.failureCount++
+	c.logger.WithFields(logrus.Fields{
+		"reason":        reason,
+		"failure_count": c.failureCount,
+	}).Warn("DeepL marked unhealthy")
+}
+
+// Translate translates text from source to target language (implements TranslationProvider)
+func (c *DeepLClient) Translate(ctx context.Context, text, sourceLang, targetLang string) (*TranslationResult, error) {
+	return c.TranslateStyled(ctx, text, sourceLang, targetLang, TranslationStyle{})
+}
+
+// TranslateStyled translates text applying the formality hint (implements StyledTranslator)
+func (c *DeepLClient) TranslateStyled(ctx context.Context, text, sourceLang, targetLang string, style TranslationStyle) (*TranslationResult, error) {
+	results, err := c.translate(ctx, []string{text}, sourceLang, targetLang, style)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no translation returned")
+	}
+	return &results[0], nil
+}
+
+// TranslateBatch translates multiple texts in a batch (implements TranslationProvider)
+func (c *DeepLClient) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string) ([]TranslationResult, error) {
+	return c.translate(ctx, texts, sourceLang, targetLang, TranslationStyle{})
+}
+
+// translate calls the DeepL API, which translates batches natively
+func (c *DeepLClient) translate(ctx context.Context, texts []string, sourceLang, targetLang string, style TranslationStyle) ([]TranslationResult, error) {
+	start := time.Now()
+
+	if !c.IsConfigured() {
+		return nil, fmt.Errorf("DeepL API key not configured")
+	}
+
+	req := DeepLTranslateRequest{
+		Text:       texts,
+		TargetLang: deepLTargetCode(targetLang),
+	}
+	if sourceLang != "" && sourceLang != "auto" {
+		req.SourceLang = strings.ToUpper(baseLanguage(sourceLang))
+	}
+	// The prefer_* variants fall back to the default register instead of failing
+	// for target languages without formality support
+	switch style.Formality {
+	case FormalityFormal:
+		req.Formality = "prefer_more"
+	case FormalityInformal:
+		req.Formality = "prefer_less"
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v2/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "DeepL-Auth-Key "+c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.markUnhealthy(err.Error())
+		return nil, fmt.Errorf("translation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	var result DeepLTranslateResponse
+	if resp.StatusCode != http.StatusOK {
+		_ = json.Unmarshal(bodyBytes, &result)
+		errMsg := fmt.Sprintf("DeepL API error %d: %s", resp.StatusCode, result.Message)
+		// Quota exhaustion and server errors affect every request, bad input does not
+		if resp.StatusCode == 456 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			c.markUnhealthy(errMsg)
+		}
+		return nil, fmt.Errorf("%s", errMsg)
+	}
+
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	c.markHealthy()
+	latency := time.Since(start)
+
+	results := make([]TranslationResult, len(result.Translations))
+	for i, t := range result.Translations {
+		detectedSource := sourceLang
+		if sourceLang == "" || sourceLang == "auto" {
+			detectedSource = strings.ToLower(t.DetectedSourceLanguage)
+		}
+		results[i] = TranslationResult{
+			TranslatedText: t.Text,
+			SourceLang:     detectedSource,
+			TargetLang:     targetLang,
+			Provider:       ProviderDeepL,
+			Latency:        latency,
+		}
+	}
+
+	return results, nil
+}
+
+// deepLTargetCode maps a language code to the DeepL target code, which requires
+// a regional variant for English and Portuguese
+func deepLTargetCode(lang string) string {
+	switch strings.ToLower(lang) {
+	case "en":
+		return "EN-US"
+	case "pt":
+		return "PT-PT"
+	case "zh", "zh-hans", "zh-cn":
+		return "ZH-HANS"
+	}
+	return strings.ToUpper(lang)
+}
+
+// baseLanguage strips any region or script from a language code ("pt-BR" -> "pt")
+func baseLanguage(lang string) string {
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	return strings.ToLower(lang)
+}
@@ -0,0 +1,275 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LLMClient translates through an OpenAI-compatible chat completions API
+// Formality and tone are applied through the system prompt, so both work for any target language
+// Priority: 5 (slowest and most expensive; preferred only when tone is requested)
+type LLMClient struct {
+	apiKey     string
+	model      string
+	httpClient *http.Client
+	logger     *logrus.Entry
+	baseURL    string
+	priority   int
+
+	// Health tracking
+	healthy      bool
+	lastHealthy  time.Time
+	failureCount int
+	healthMu     sync.RWMutex
+}
+
+// LLMChatMessage is a single message of a chat completion request
+type LLMChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// LLMChatRequest represents a chat completion request
+type LLMChatRequest struct {
+	Model       string           `json:"model"`
+	Messages    []LLMChatMessage `json:"messages"`
+	Temperature float64          `json:"temperature"`
+}
+
+// LLMChatResponse represents a chat completion response
+type LLMChatResponse struct {
+	Choices []struct {
+		Message LLMChatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+// llmFormalityInstructions describes each formality level to the model
+var llmFormalityInstructions = map[string]string{
+	FormalityFormal:   "Use the formal register of the target language (for example Sie in German, vous in French, usted in Spanish, keigo in Japanese).",
+	FormalityInformal: "Use the informal register of the target language (for example du in German, tu in French, tú in Spanish, plain form in Japanese).",
+}
+
+// llmToneInstructions describes each tone to the model
+var llmToneInstructions = map[string]string{
+	ToneFriendly:     "Write in a warm, friendly tone.",
+	ToneProfessional: "Write in a clear, professional tone.",
+	TonePlayful:      "Write in a light, playful tone.",
+	ToneLuxury:       "Write in a refined, premium tone suited to a luxury brand.",
+}
+
+// NewLLMClient creates a new LLM translation client
+// baseURL is the API root that serves /chat/completions (e.g. https://api.openai.com/v1)
+func NewLLMClient(apiKey, baseURL, model string, logger *logrus.Entry) *LLMClient {
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+
+	return &LLMClient{
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		logger:   logger,
+		priority: 5, // Last in the chain for unstyled translations
+		healthy:  true,
+	}
+}
+
+// Name returns the provider name
+func (c *LLMClient) Name() ProviderName {
+	return ProviderLLM
+}
+
+// Priority returns the provider priority
+func (c *LLMClient) Priority() int {
+	return c.priority
+}
+
+// IsConfigured returns true if the client has an endpoint to call
+// Self-hosted endpoints may not need an API key
+func (c *LLMClient) IsConfigured() bool {
+	return c.baseURL != ""
+}
+
+// IsHealthy checks if the provider is currently healthy
+func (c *LLMClient) IsHealthy(ctx context.Context) bool {
+	c.healthMu.RLock()
+	healthy := c.healthy
+	lastHealthy := c.lastHealthy
+	failureCount := c.failureCount
+	c.healthMu.RUnlock()
+
+	// If unhealthy, check if enough time has passed for retry
+	if !healthy && failureCount > 0 {
+		backoffDuration := time.Duration(failureCount) * 30 * time.Second
+		if backoffDuration > 5*time.Minute {
+			backoffDuration = 5 * time.Minute
+		}
+		if time.Since(lastHealthy) < backoffDuration {
+			return false
+		}
+	}
+
+	return healthy
+}
+
+// SupportsLanguagePair returns true; the model is asked to translate any pair
+func (c *LLMClient) SupportsLanguagePair(sourceLang, targetLang string) bool {
+	return targetLang != ""
+}
+
+// StyleCapabilities reports that both formality and tone are applied for every language
+func (c *LLMClient) StyleCapabilities(targetLang string) StyleCapabilities {
+	return StyleCapabilities{Formality: true, Tone: true}
+}
+
+// markHealthy marks the provider as healthy
+func (c *LLMClient) markHealthy() {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.healthy = true
+	c.lastHealthy = time.Now()
+	c.failureCount = 0
+}
+
+// markUnhealthy marks the provider as unhealthy
+func (c *LLMClient) markUnhealthy(reason string) {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.healthy = false
+	c.failureCount++
+	c.logger.WithFields(logrus.Fields{
+		"reason":        reason,
+		"failure_count": c.failureCount,
+	}).Warn("LLM translator marked unhealthy")
+}
+
+// Translate translates text from source to target language (implements TranslationProvider)
+func (c *LLMClient) Translate(ctx context.Context, text, sourceLang, targetLang string) (*TranslationResult, error) {
+	return c.TranslateStyled(ctx, text, sourceLang, targetLang, TranslationStyle{})
+}
+
+// TranslateStyled translates text with formality and tone applied through the prompt (implements StyledTranslator)
+func (c *LLMClient) TranslateStyled(ctx context.Context, text, sourceLang, targetLang string, style TranslationStyle) (*TranslationResult, error) {
+	start := time.Now()
+
+	if !c.IsConfigured() {
+		return nil, fmt.Errorf("LLM translation endpoint not configured")
+	}
+
+	req := LLMChatRequest{
+		Model: c.model,
+		Messages: []LLMChatMessage{
+			{Role: "system", Content: buildLLMTranslationPrompt(sourceLang, targetLang, style)},
+			{Role: "user", Content: text},
+		},
+		Temperature: 0.2,
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.markUnhealthy(err.Error())
+		return nil, fmt.Errorf("translation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	var result LLMChatResponse
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			c.markUnhealthy(fmt.Sprintf("status %d", resp.StatusCode))
+			return nil, fmt.Errorf("LLM API error %d", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if result.Error != nil || resp.StatusCode != http.StatusOK {
+		errMsg := fmt.Sprintf("LLM API error %d", resp.StatusCode)
+		if result.Error != nil {
+			errMsg = fmt.Sprintf("%s: %s", errMsg, result.Error.Message)
+		}
+		c.markUnhealthy(errMsg)
+		return nil, fmt.Errorf("%s", errMsg)
+	}
+
+	if len(result.Choices) == 0 || strings.TrimSpace(result.Choices[0].Message.Content) == "" {
+		return nil, fmt.Errorf("no translation returned")
+	}
+
+	c.markHealthy()
+
+	return &TranslationResult{
+		TranslatedText: strings.TrimSpace(result.Choices[0].Message.Content),
+		SourceLang:     sourceLang,
+		TargetLang:     targetLang,
+		Provider:       ProviderLLM,
+		Latency:        time.Since(start),
+	}, nil
+}
+
+// TranslateBatch translates multiple texts one at a time (implements TranslationProvider)
+func (c *LLMClient) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string) ([]TranslationResult, error) {
+	results := make([]TranslationResult, len(texts))
+	for i, text := range texts {
+		result, err := c.Translate(ctx, text, sourceLang, targetLang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate item %d: %w", i, err)
+		}
+		results[i] = *result
+	}
+	return results, nil
+}
+
+// buildLLMTranslationPrompt builds the system prompt for a translation with the given style
+func buildLLMTranslationPrompt(sourceLang, targetLang string, style TranslationStyle) string {
+	var b strings.Builder
+
+	if sourceLang == "" || sourceLang == "auto" {
+		fmt.Fprintf(&b, "Translate the user's text into the language with code %q.", targetLang)
+	} else {
+		fmt.Fprintf(&b, "Translate the user's text from the language with code %q into the language with code %q.", sourceLang, targetLang)
+	}
+	b.WriteString(" Reply with the translation only, without quotes, notes or explanations.")
+	b.WriteString(" Keep placeholders such as {name}, {{count}} or %s, HTML tags, URLs and line breaks exactly as they are.")
+
+	if instruction, ok := llmFormalityInstructions[style.Formality]; ok {
+		b.WriteString(" ")
+		b.WriteString(instruction)
+	}
+	if instruction, ok := llmToneInstructions[style.Tone]; ok {
+		b.WriteString(" ")
+		b.WriteString(instruction)
+	}
+
+	return b.String()
+}
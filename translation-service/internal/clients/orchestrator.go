@@ -14,7 +14,9 @@ import (
 // Provider Order (by priority):
 //   1. LibreTranslate (open-source, self-hosted, free)
 //   2. Hugging Face (open-source models, free API with limits)
-//   3. Google Translate (paid, last resort)
+//   3. Google Translate (paid)
+//   4. DeepL (paid, honors formality)
+//   5. LLM (OpenAI-compatible, honors formality and tone)
 //
 // The orchestrator:
 //   - Tries providers in priority order
@@ -153,6 +155,114 @@ func (o *TranslationOrchestrator) Translate(ctx context.Context, text, sourceLan
 	return nil, fmt.Errorf("no suitable provider found for %s->%s (tried: %v)", sourceLang, targetLang, attemptedProviders)
 }
 
+// TranslateStyled attempts translation honoring formality and tone hints.
+// Providers that can apply more of the style are tried first, priority breaking ties;
+// providers that can apply none of it remain as fallbacks and translate unstyled.
+// The hints actually applied are reported in the result's Style.
+func (o *TranslationOrchestrator) TranslateStyled(ctx context.Context, text, sourceLang, targetLang string, style TranslationStyle) (*TranslationResult, error) {
+	if style.IsZero() {
+		return o.Translate(ctx, text, sourceLang, targetLang)
+	}
+	if len(o.providers) == 0 {
+		return nil, fmt.Errorf("no translation providers configured")
+	}
+
+	var lastErr error
+	attemptedProviders := make([]string, 0)
+
+	for _, provider := range o.styledCandidates(ctx, sourceLang, targetLang, style) {
+		providerName := provider.Name()
+		applied := providerStyle(provider, targetLang, style)
+
+		attemptedProviders = append(attemptedProviders, string(providerName))
+
+		// Attempt translation
+		start := time.Now()
+		var result *TranslationResult
+		var err error
+		if styled, ok := provider.(StyledTranslator); ok && !applied.IsZero() {
+			result, err = styled.TranslateStyled(ctx, text, sourceLang, targetLang, applied)
+		} else {
+			result, err = provider.Translate(ctx, text, sourceLang, targetLang)
+		}
+		latency := time.Since(start)
+
+		if err != nil {
+			lastErr = err
+			o.recordFailure(providerName, err.Error(), latency)
+			o.logger.WithFields(logrus.Fields{
+				"provider":  providerName,
+				"error":     err.Error(),
+				"latency":   latency.String(),
+				"formality": style.Formality,
+				"tone":      style.Tone,
+			}).Warn("Styled translation failed, trying next provider")
+			continue
+		}
+
+		o.recordSuccess(providerName, int64(len(text)), latency)
+		result.Style = applied
+		if applied != style {
+			o.logger.WithFields(logrus.Fields{
+				"provider":        providerName,
+				"target_lang":     targetLang,
+				"requested_style": style,
+				"applied_style":   applied,
+			}).Debug("Provider could not apply all requested style hints")
+		}
+
+		return result, nil
+	}
+
+	// All providers failed
+	if lastErr != nil {
+		return nil, fmt.Errorf("all providers failed (tried: %v): %w", attemptedProviders, lastErr)
+	}
+
+	return nil, fmt.Errorf("no suitable provider found for %s->%s (tried: %v)", sourceLang, targetLang, attemptedProviders)
+}
+
+// ApplicableStyle returns the part of style the best available provider would apply to a
+// sourceLang->targetLang translation. Callers key caches on it so a hint no provider can
+// honor does not split the cache.
+func (o *TranslationOrchestrator) ApplicableStyle(ctx context.Context, sourceLang, targetLang string, style TranslationStyle) TranslationStyle {
+	if style.IsZero() {
+		return style
+	}
+	candidates := o.styledCandidates(ctx, sourceLang, targetLang, style)
+	if len(candidates) == 0 {
+		return TranslationStyle{}
+	}
+	return providerStyle(candidates[0], targetLang, style)
+}
+
+// styledCandidates returns the healthy providers supporting the pair, ordered by how much
+// of style they can apply and then by priority
+func (o *TranslationOrchestrator) styledCandidates(ctx context.Context, sourceLang, targetLang string, style TranslationStyle) []TranslationProvider {
+	candidates := make([]TranslationProvider, 0, len(o.providers))
+	for _, provider := range o.providers {
+		if !provider.IsHealthy(ctx) || !provider.SupportsLanguagePair(sourceLang, targetLang) {
+			continue
+		}
+		candidates = append(candidates, provider)
+	}
+
+	// Providers are already in priority order, so a stable sort keeps it for ties
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return providerStyle(candidates[i], targetLang, style).hints() > providerStyle(candidates[j], targetLang, style).hints()
+	})
+	return candidates
+}
+
+// providerStyle returns the part of style the provider can apply when translating into targetLang
+func providerStyle(provider TranslationProvider, targetLang string, style TranslationStyle) TranslationStyle {
+	styled, ok := provider.(StyledTranslator)
+	if !ok {
+		return TranslationStyle{}
+	}
+	return styled.StyleCapabilities(targetLang).Apply(style)
+}
+
 // TranslateBatch attempts batch translation using the provider chain
 func (o *TranslationOrchestrator) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string) ([]TranslationResult, error) {
 	if len(o.providers) == 0 {
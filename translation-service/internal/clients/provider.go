@@ -13,8 +13,80 @@ const (
 	ProviderBergamot       ProviderName = "bergamot"
 	ProviderHuggingFace    ProviderName = "huggingface"
 	ProviderGoogle         ProviderName = "google"
+	ProviderDeepL          ProviderName = "deepl"
+	ProviderLLM            ProviderName = "llm"
 )
 
+// Formality levels a translation can ask for; empty leaves the register to the provider
+const (
+	FormalityFormal   = "formal"
+	FormalityInformal = "informal"
+)
+
+// Tones a translation can ask for; empty means neutral
+const (
+	ToneFriendly     = "friendly"
+	ToneProfessional = "professional"
+	TonePlayful      = "playful"
+	ToneLuxury       = "luxury"
+)
+
+// TranslationStyle carries the formality and tone hints of a translation
+type TranslationStyle struct {
+	Formality string `json:"formality,omitempty"`
+	Tone      string `json:"tone,omitempty"`
+}
+
+// IsZero reports whether no style hints are set
+func (s TranslationStyle) IsZero() bool {
+	return s.Formality == "" && s.Tone == ""
+}
+
+// hints returns how many style hints are set
+func (s TranslationStyle) hints() int {
+	n := 0
+	if s.Formality != "" {
+		n++
+	}
+	if s.Tone != "" {
+		n++
+	}
+	return n
+}
+
+// IsValidFormality reports whether f is empty or a known formality level
+func IsValidFormality(f string) bool {
+	return f == "" || f == FormalityFormal || f == FormalityInformal
+}
+
+// StyleCapabilities lists the style hints a provider honors for a target language
+type StyleCapabilities struct {
+	Formality bool
+	Tone      bool
+}
+
+// Apply returns the part of style the provider can honor
+func (c StyleCapabilities) Apply(style TranslationStyle) TranslationStyle {
+	applied := TranslationStyle{}
+	if c.Formality {
+		applied.Formality = style.Formality
+	}
+	if c.Tone {
+		applied.Tone = style.Tone
+	}
+	return applied
+}
+
+// StyledTranslator is implemented by providers that can honor formality or tone hints.
+// Providers without it still serve styled requests, just without the hints.
+type StyledTranslator interface {
+	// StyleCapabilities returns the hints the provider honors when translating into targetLang
+	StyleCapabilities(targetLang string) StyleCapabilities
+
+	// TranslateStyled translates text applying the given style hints
+	TranslateStyled(ctx context.Context, text, sourceLang, targetLang string, style TranslationStyle) (*TranslationResult, error)
+}
+
 // TranslationProvider defines the interface that all translation providers must implement
 type TranslationProvider interface {
 	// Name returns the provider's identifier
@@ -47,6 +119,7 @@ type TranslationResult struct {
 	Provider       ProviderName `json:"provider"`
 	Latency        time.Duration `json:"latency"`
 	FromCache      bool         `json:"from_cache"`
+	Style          TranslationStyle `json:"style"` // Hints the provider applied
 }
 
 // ProviderHealth tracks the health status of a provider
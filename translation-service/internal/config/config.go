@@ -7,7 +7,8 @@ import (
 
 	"github.com/joho/godotenv"
 
-	"github.com/Tesseract-Nexus/go-shared/secrets")
+	"github.com/Tesseract-Nexus/go-shared/secrets"
+)
 
 type Config struct {
 	Server      ServerConfig
@@ -59,17 +60,38 @@ type TranslationConfig struct {
 	// Google Cloud Translation configuration (final fallback - paid, most languages)
 	GoogleTranslateKey string
 
+	// DeepL configuration (paid, used for formality-controlled translations)
+	DeepLKey string
+	DeepLURL string // Optional; derived from the key type when empty
+
+	// OpenAI-compatible LLM configuration (used for tone-controlled translations)
+	LLMURL   string
+	LLMKey   string
+	LLMModel string
+
 	// Cache settings
-	CacheTTL         time.Duration
-	CacheEnabled     bool
+	CacheTTL     time.Duration
+	CacheEnabled bool
+
+	// Cache warming: refresh hot keys before they expire
+	CacheWarmingEnabled    bool
+	CacheWarmInterval      time.Duration
+	CacheWarmRefreshBefore time.Duration // Refresh hot keys whose remaining TTL is below this
+	CacheWarmMinHits       int
+	CacheWarmMaxKeys       int
+
+	// Precompute jobs run between these UTC hours when scheduled off-peak
+	OffPeakStartHour   int
+	OffPeakEndHour     int
+	MaxPrecomputeItems int
 
 	// Rate limiting
-	RateLimit        int
-	RateLimitWindow  time.Duration
+	RateLimit       int
+	RateLimitWindow time.Duration
 
 	// Batch settings
-	MaxBatchSize     int
-	BatchTimeout     time.Duration
+	MaxBatchSize int
+	BatchTimeout time.Duration
 
 	// Supported languages
 	DefaultSourceLang string
@@ -104,20 +126,33 @@ func Load() (*Config, error) {
 			LogLevel:    getEnv("LOG_LEVEL", "info"),
 		},
 		Translation: TranslationConfig{
-			LibreTranslateURL:  getEnv("LIBRETRANSLATE_URL", "http://libretranslate:5000"),
-			LibreTranslateKey:  getEnv("LIBRETRANSLATE_API_KEY", ""),
-			BergamotURL:        getEnv("BERGAMOT_URL", "http://bergamot-service:8080"),
-			HuggingFaceURL:     getEnv("HUGGINGFACE_URL", "http://huggingface-mt-service:8080"),
-			HuggingFaceKey:     getEnv("HUGGINGFACE_API_KEY", ""),
-			GoogleTranslateKey: secrets.GetSecretOrEnv("GOOGLE_TRANSLATE_API_KEY_SECRET_NAME", "GOOGLE_TRANSLATE_API_KEY", ""),
-			CacheTTL:          getEnvAsDuration("CACHE_TTL", 24*time.Hour),
-			CacheEnabled:      getEnvAsBool("CACHE_ENABLED", true),
-			RateLimit:         getEnvAsInt("RATE_LIMIT", 100),
-			RateLimitWindow:   getEnvAsDuration("RATE_LIMIT_WINDOW", time.Minute),
-			MaxBatchSize:      getEnvAsInt("MAX_BATCH_SIZE", 50),
-			BatchTimeout:      getEnvAsDuration("BATCH_TIMEOUT", 30*time.Second),
-			DefaultSourceLang: getEnv("DEFAULT_SOURCE_LANG", "en"),
-			DefaultTargetLang: getEnv("DEFAULT_TARGET_LANG", "hi"),
+			LibreTranslateURL:      getEnv("LIBRETRANSLATE_URL", "http://libretranslate:5000"),
+			LibreTranslateKey:      getEnv("LIBRETRANSLATE_API_KEY", ""),
+			BergamotURL:            getEnv("BERGAMOT_URL", "http://bergamot-service:8080"),
+			HuggingFaceURL:         getEnv("HUGGINGFACE_URL", "http://huggingface-mt-service:8080"),
+			HuggingFaceKey:         getEnv("HUGGINGFACE_API_KEY", ""),
+			GoogleTranslateKey:     secrets.GetSecretOrEnv("GOOGLE_TRANSLATE_API_KEY_SECRET_NAME", "GOOGLE_TRANSLATE_API_KEY", ""),
+			DeepLKey:               secrets.GetSecretOrEnv("DEEPL_API_KEY_SECRET_NAME", "DEEPL_API_KEY", ""),
+			DeepLURL:               getEnv("DEEPL_URL", ""),
+			LLMURL:                 getEnv("LLM_TRANSLATE_URL", ""),
+			LLMKey:                 secrets.GetSecretOrEnv("LLM_TRANSLATE_API_KEY_SECRET_NAME", "LLM_TRANSLATE_API_KEY", ""),
+			LLMModel:               getEnv("LLM_TRANSLATE_MODEL", "gpt-4o-mini"),
+			CacheTTL:               getEnvAsDuration("CACHE_TTL", 24*time.Hour),
+			CacheEnabled:           getEnvAsBool("CACHE_ENABLED", true),
+			CacheWarmingEnabled:    getEnvAsBool("CACHE_WARMING_ENABLED", true),
+			CacheWarmInterval:      getEnvAsDuration("CACHE_WARM_INTERVAL", 10*time.Minute),
+			CacheWarmRefreshBefore: getEnvAsDuration("CACHE_WARM_REFRESH_BEFORE", 2*time.Hour),
+			CacheWarmMinHits:       getEnvAsInt("CACHE_WARM_MIN_HITS", 5),
+			CacheWarmMaxKeys:       getEnvAsInt("CACHE_WARM_MAX_KEYS", 500),
+			OffPeakStartHour:       getEnvAsInt("OFF_PEAK_START_HOUR", 1),
+			OffPeakEndHour:         getEnvAsInt("OFF_PEAK_END_HOUR", 5),
+			MaxPrecomputeItems:     getEnvAsInt("MAX_PRECOMPUTE_ITEMS", 1000),
+			RateLimit:              getEnvAsInt("RATE_LIMIT", 100),
+			RateLimitWindow:        getEnvAsDuration("RATE_LIMIT_WINDOW", time.Minute),
+			MaxBatchSize:           getEnvAsInt("MAX_BATCH_SIZE", 50),
+			BatchTimeout:           getEnvAsDuration("BATCH_TIMEOUT", 30*time.Second),
+			DefaultSourceLang:      getEnv("DEFAULT_SOURCE_LANG", "en"),
+			DefaultTargetLang:      getEnv("DEFAULT_TARGET_LANG", "hi"),
 		},
 	}, nil
 }
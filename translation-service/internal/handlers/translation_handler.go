@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,9 +13,11 @@ import (
 	"translation-service/internal/cache"
 	"translation-service/internal/clients"
 	"translation-service/internal/config"
+	"translation-service/internal/localize"
 	"translation-service/internal/middleware"
 	"translation-service/internal/models"
 	"translation-service/internal/repository"
+	"translation-service/internal/warming"
 )
 
 // TranslationHandler handles translation API requests
@@ -23,8 +27,22 @@ type TranslationHandler struct {
 	orchestrator *clients.TranslationOrchestrator
 	// Keep legacy references for health checks and language detection
 	libreTranslate *clients.LibreTranslateClient
+	warmer         *warming.Warmer
 	config         *config.TranslationConfig
 	logger         *logrus.Entry
+
+	// Tenant preferences read on the translate path, cached briefly to spare the database
+	prefCache   map[string]cachedPreference
+	prefCacheMu sync.RWMutex
+}
+
+// preferenceCacheTTL bounds how long a replica serves a tenant's old style defaults after an update
+const preferenceCacheTTL = time.Minute
+
+// cachedPreference is a tenant preference held in the handler's in-memory cache
+type cachedPreference struct {
+	pref      *models.TenantLanguagePreference
+	expiresAt time.Time
 }
 
 // normalizeLanguageCode converts common language code variants to LibreTranslate compatible codes
@@ -50,6 +68,7 @@ func NewTranslationHandler(
 	cache *cache.TranslationCache,
 	orchestrator *clients.TranslationOrchestrator,
 	libreTranslate *clients.LibreTranslateClient,
+	warmer *warming.Warmer,
 	cfg *config.TranslationConfig,
 	logger *logrus.Entry,
 ) *TranslationHandler {
@@ -58,9 +77,54 @@ func NewTranslationHandler(
 		cache:          cache,
 		orchestrator:   orchestrator,
 		libreTranslate: libreTranslate,
+		warmer:         warmer,
 		config:         cfg,
 		logger:         logger,
+		prefCache:      make(map[string]cachedPreference),
+	}
+}
+
+// tenantPreference returns the tenant's language preference from the in-memory cache or the database
+func (h *TranslationHandler) tenantPreference(ctx context.Context, tenantID string) (*models.TenantLanguagePreference, error) {
+	h.prefCacheMu.RLock()
+	cached, ok := h.prefCache[tenantID]
+	h.prefCacheMu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.pref, nil
 	}
+
+	pref, err := h.repo.GetPreference(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	h.prefCacheMu.Lock()
+	h.prefCache[tenantID] = cachedPreference{pref: pref, expiresAt: time.Now().Add(preferenceCacheTTL)}
+	h.prefCacheMu.Unlock()
+	return pref, nil
+}
+
+// resolveStyle returns the style for a translation: hints on the request win, the tenant's
+// defaults fill in the rest. Failing to load the defaults only costs the defaults.
+func (h *TranslationHandler) resolveStyle(ctx context.Context, tenantID, targetLang, formality, tone string) clients.TranslationStyle {
+	style := clients.TranslationStyle{Formality: formality, Tone: tone}
+	if style.Formality != "" && style.Tone != "" {
+		return style
+	}
+
+	pref, err := h.tenantPreference(ctx, tenantID)
+	if err != nil {
+		h.logger.WithError(err).WithField("tenant_id", tenantID).Warn("Failed to load tenant style defaults")
+		return style
+	}
+	defaultFormality, defaultTone := pref.StyleFor(targetLang)
+	if style.Formality == "" {
+		style.Formality = defaultFormality
+	}
+	if style.Tone == "" {
+		style.Tone = defaultTone
+	}
+	return style
 }
 
 // Translate handles single translation requests
@@ -93,9 +157,15 @@ func (h *TranslationHandler) Translate(c *gin.Context) {
 	sourceLang = normalizeLanguageCode(sourceLang)
 	targetLang := normalizeLanguageCode(req.TargetLang)
 
+	// Cache on the part of the style the providers can apply, so a hint no provider
+	// supports for this language does not split the cache
+	style := h.resolveStyle(ctx, tenantID, targetLang, req.Formality, req.Tone)
+	lookupStyle := h.orchestrator.ApplicableStyle(ctx, sourceLang, targetLang, style)
+	cacheContext := models.StyledContext(req.Context, lookupStyle.Formality, lookupStyle.Tone)
+
 	// Check Redis cache first
 	if h.cache != nil && h.config.CacheEnabled {
-		cached, err := h.cache.Get(ctx, tenantID, sourceLang, targetLang, req.Text, req.Context)
+		cached, err := h.cache.Get(ctx, tenantID, sourceLang, targetLang, req.Text, cacheContext)
 		if err == nil && cached != nil {
 			h.logger.WithFields(logrus.Fields{
 				"tenant_id":   tenantID,
@@ -113,13 +183,15 @@ func (h *TranslationHandler) Translate(c *gin.Context) {
 				TargetLang:     targetLang,
 				Cached:         true,
 				Provider:       cached.Provider,
+				Formality:      lookupStyle.Formality,
+				Tone:           lookupStyle.Tone,
 			})
 			return
 		}
 	}
 
 	// Check database cache
-	sourceHash := models.GenerateSourceHash(sourceLang, targetLang, req.Text, req.Context)
+	sourceHash := models.GenerateSourceHash(sourceLang, targetLang, req.Text, cacheContext)
 	dbCached, err := h.repo.GetCachedTranslation(ctx, tenantID, sourceLang, targetLang, sourceHash)
 	if err == nil && dbCached != nil {
 		h.logger.WithFields(logrus.Fields{
@@ -130,7 +202,7 @@ func (h *TranslationHandler) Translate(c *gin.Context) {
 
 		// Update Redis cache
 		if h.cache != nil && h.config.CacheEnabled {
-			go h.cache.Set(context.Background(), tenantID, sourceLang, targetLang, req.Text, dbCached.TranslatedText, req.Context, dbCached.Provider)
+			go h.cache.Set(context.Background(), tenantID, sourceLang, targetLang, req.Text, dbCached.TranslatedText, cacheContext, dbCached.Provider)
 		}
 
 		// Update hit count
@@ -144,12 +216,14 @@ func (h *TranslationHandler) Translate(c *gin.Context) {
 			TargetLang:     targetLang,
 			Cached:         true,
 			Provider:       dbCached.Provider,
+			Formality:      lookupStyle.Formality,
+			Tone:           lookupStyle.Tone,
 		})
 		return
 	}
 
-	// Translate using the orchestrator (tries providers in priority order)
-	result, err := h.orchestrator.Translate(ctx, req.Text, sourceLang, targetLang)
+	// Translate using the orchestrator (tries providers in priority order, style-capable ones first)
+	result, err := h.orchestrator.TranslateStyled(ctx, req.Text, sourceLang, targetLang, style)
 	if err != nil {
 		h.logger.WithFields(logrus.Fields{
 			"error":       err.Error(),
@@ -166,6 +240,12 @@ func (h *TranslationHandler) Translate(c *gin.Context) {
 	translated := result.TranslatedText
 	provider := string(result.Provider)
 
+	// A fallback provider may have applied less of the style than expected; cache under what it applied
+	if result.Style != lookupStyle {
+		cacheContext = models.StyledContext(req.Context, result.Style.Formality, result.Style.Tone)
+		sourceHash = models.GenerateSourceHash(sourceLang, targetLang, req.Text, cacheContext)
+	}
+
 	// Cache the result
 	cacheEntry := &models.TranslationCache{
 		TenantID:       tenantID,
@@ -174,7 +254,7 @@ func (h *TranslationHandler) Translate(c *gin.Context) {
 		SourceHash:     sourceHash,
 		SourceText:     req.Text,
 		TranslatedText: translated,
-		Context:        req.Context,
+		Context:        cacheContext,
 		Provider:       provider,
 		ExpiresAt:      time.Now().Add(h.config.CacheTTL),
 	}
@@ -188,7 +268,7 @@ func (h *TranslationHandler) Translate(c *gin.Context) {
 
 	// Save to Redis cache
 	if h.cache != nil && h.config.CacheEnabled {
-		go h.cache.Set(context.Background(), tenantID, sourceLang, targetLang, req.Text, translated, req.Context, provider)
+		go h.cache.Set(context.Background(), tenantID, sourceLang, targetLang, req.Text, translated, cacheContext, provider)
 	}
 
 	// Update stats
@@ -201,6 +281,8 @@ func (h *TranslationHandler) Translate(c *gin.Context) {
 		TargetLang:     targetLang,
 		Cached:         false,
 		Provider:       provider,
+		Formality:      result.Style.Formality,
+		Tone:           result.Style.Tone,
 	})
 }
 
@@ -236,6 +318,9 @@ func (h *TranslationHandler) TranslateBatch(c *gin.Context) {
 	// Normalize target language code for provider compatibility
 	targetLang := normalizeLanguageCode(req.TargetLang)
 
+	// One style applies to the whole batch
+	style := h.resolveStyle(ctx, tenantID, targetLang, req.Formality, req.Tone)
+
 	response := models.BatchTranslationResponse{
 		Items:      make([]models.BatchTranslationItem, len(req.Items)),
 		TotalCount: len(req.Items),
@@ -259,12 +344,17 @@ func (h *TranslationHandler) TranslateBatch(c *gin.Context) {
 			SourceLang:   sourceLang,
 		}
 
+		lookupStyle := h.orchestrator.ApplicableStyle(ctx, sourceLang, targetLang, style)
+		cacheContext := models.StyledContext(item.Context, lookupStyle.Formality, lookupStyle.Tone)
+
 		// Check Redis cache first
 		if h.cache != nil && h.config.CacheEnabled {
-			cached, err := h.cache.Get(ctx, tenantID, sourceLang, targetLang, item.Text, item.Context)
+			cached, err := h.cache.Get(ctx, tenantID, sourceLang, targetLang, item.Text, cacheContext)
 			if err == nil && cached != nil {
 				responseItem.TranslatedText = cached.TranslatedText
 				responseItem.Cached = true
+				responseItem.Formality = lookupStyle.Formality
+				responseItem.Tone = lookupStyle.Tone
 				response.CachedCount++
 				response.Items[i] = responseItem
 				continue
@@ -272,28 +362,37 @@ func (h *TranslationHandler) TranslateBatch(c *gin.Context) {
 		}
 
 		// Check database cache
-		sourceHash := models.GenerateSourceHash(sourceLang, targetLang, item.Text, item.Context)
+		sourceHash := models.GenerateSourceHash(sourceLang, targetLang, item.Text, cacheContext)
 		dbCached, err := h.repo.GetCachedTranslation(ctx, tenantID, sourceLang, targetLang, sourceHash)
 		if err == nil && dbCached != nil {
 			responseItem.TranslatedText = dbCached.TranslatedText
 			responseItem.Cached = true
+			responseItem.Formality = lookupStyle.Formality
+			responseItem.Tone = lookupStyle.Tone
 			response.CachedCount++
 			response.Items[i] = responseItem
 
 			// Update Redis cache
 			if h.cache != nil && h.config.CacheEnabled {
-				go h.cache.Set(context.Background(), tenantID, sourceLang, targetLang, item.Text, dbCached.TranslatedText, item.Context, dbCached.Provider)
+				go h.cache.Set(context.Background(), tenantID, sourceLang, targetLang, item.Text, dbCached.TranslatedText, cacheContext, dbCached.Provider)
 			}
 			continue
 		}
 
 		// Translate using orchestrator
-		result, err := h.orchestrator.Translate(ctx, item.Text, sourceLang, targetLang)
+		result, err := h.orchestrator.TranslateStyled(ctx, item.Text, sourceLang, targetLang, style)
 		if err != nil {
 			responseItem.Error = err.Error()
 			responseItem.TranslatedText = item.Text // Return original on error
 		} else {
 			responseItem.TranslatedText = result.TranslatedText
+			responseItem.Formality = result.Style.Formality
+			responseItem.Tone = result.Style.Tone
+
+			if result.Style != lookupStyle {
+				cacheContext = models.StyledContext(item.Context, result.Style.Formality, result.Style.Tone)
+				sourceHash = models.GenerateSourceHash(sourceLang, targetLang, item.Text, cacheContext)
+			}
 
 			// Cache the result
 			cacheEntry := &models.TranslationCache{
@@ -303,7 +402,7 @@ func (h *TranslationHandler) TranslateBatch(c *gin.Context) {
 				SourceHash:     sourceHash,
 				SourceText:     item.Text,
 				TranslatedText: result.TranslatedText,
-				Context:        item.Context,
+				Context:        cacheContext,
 				Provider:       string(result.Provider),
 				ExpiresAt:      time.Now().Add(h.config.CacheTTL),
 			}
@@ -315,7 +414,7 @@ func (h *TranslationHandler) TranslateBatch(c *gin.Context) {
 			}(cacheEntry)
 
 			if h.cache != nil && h.config.CacheEnabled {
-				go h.cache.Set(context.Background(), tenantID, sourceLang, targetLang, item.Text, result.TranslatedText, item.Context, string(result.Provider))
+				go h.cache.Set(context.Background(), tenantID, sourceLang, targetLang, item.Text, result.TranslatedText, cacheContext, string(result.Provider))
 			}
 		}
 
@@ -332,6 +431,39 @@ func (h *TranslationHandler) TranslateBatch(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// FormatLocalized formats currency amounts, numbers and dates using the locale's CLDR rules
+// POST /api/v1/localize/format
+func (h *TranslationHandler) FormatLocalized(c *gin.Context) {
+	var req localize.FormatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if len(req.Items) > h.config.MaxBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":    "BATCH_TOO_LARGE",
+			"message":  "Too many items in format request",
+			"max_size": h.config.MaxBatchSize,
+		})
+		return
+	}
+
+	formatter := localize.NewFormatter(req.Locale)
+	response := localize.FormatResponse{
+		Locale:  formatter.Locale(),
+		Results: make([]localize.Result, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		response.Results[i] = formatter.Format(item)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // DetectLanguage handles language detection requests
 // POST /api/v1/detect
 func (h *TranslationHandler) DetectLanguage(c *gin.Context) {
@@ -452,12 +584,20 @@ func (h *TranslationHandler) GetPreference(c *gin.Context) {
 		json.Unmarshal(pref.EnabledLanguages, &enabledLanguages)
 	}
 
+	languageFormality := map[string]string{}
+	if len(pref.LanguageFormality) > 0 {
+		json.Unmarshal(pref.LanguageFormality, &languageFormality)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"tenant_id":           pref.TenantID,
 		"default_source_lang": pref.DefaultSourceLang,
 		"default_target_lang": pref.DefaultTargetLang,
 		"enabled_languages":   enabledLanguages,
 		"auto_detect":         pref.AutoDetect,
+		"default_formality":   pref.DefaultFormality,
+		"default_tone":        pref.DefaultTone,
+		"language_formality":  languageFormality,
 	})
 }
 
@@ -478,6 +618,10 @@ func (h *TranslationHandler) UpdatePreference(c *gin.Context) {
 		DefaultTargetLang string   `json:"default_target_lang"`
 		EnabledLanguages  []string `json:"enabled_languages"`
 		AutoDetect        bool     `json:"auto_detect"`
+		DefaultFormality  string   `json:"default_formality" binding:"omitempty,oneof=formal informal"`
+		DefaultTone       string   `json:"default_tone" binding:"omitempty,oneof=friendly professional playful luxury"`
+		// Per target language formality, e.g. {"de": "formal", "es": "informal"}
+		LanguageFormality map[string]string `json:"language_formality"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -488,17 +632,37 @@ func (h *TranslationHandler) UpdatePreference(c *gin.Context) {
 		return
 	}
 
+	for lang, formality := range req.LanguageFormality {
+		if formality == "" || !clients.IsValidFormality(formality) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "INVALID_REQUEST",
+				"message": "language_formality values must be formal or informal (language " + lang + ")",
+			})
+			return
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	enabledLanguagesJSON, _ := json.Marshal(req.EnabledLanguages)
 
+	// Keys are normalized like the target languages they are matched against
+	languageFormality := make(map[string]string, len(req.LanguageFormality))
+	for lang, formality := range req.LanguageFormality {
+		languageFormality[normalizeLanguageCode(lang)] = formality
+	}
+	languageFormalityJSON, _ := json.Marshal(languageFormality)
+
 	pref := &models.TenantLanguagePreference{
 		TenantID:          tenantID,
 		DefaultSourceLang: req.DefaultSourceLang,
 		DefaultTargetLang: req.DefaultTargetLang,
 		EnabledLanguages:  enabledLanguagesJSON,
 		AutoDetect:        req.AutoDetect,
+		DefaultFormality:  req.DefaultFormality,
+		DefaultTone:       req.DefaultTone,
+		LanguageFormality: languageFormalityJSON,
 	}
 
 	if err := h.repo.SavePreference(ctx, pref); err != nil {
@@ -510,6 +674,10 @@ func (h *TranslationHandler) UpdatePreference(c *gin.Context) {
 		return
 	}
 
+	h.prefCacheMu.Lock()
+	delete(h.prefCache, tenantID)
+	h.prefCacheMu.Unlock()
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Preferences updated successfully",
 	})
@@ -552,6 +720,80 @@ func (h *TranslationHandler) InvalidateCache(c *gin.Context) {
 	})
 }
 
+// PrecomputeTranslations schedules translation of known strings into the tenant's enabled languages
+// POST /api/v1/cache/precompute
+func (h *TranslationHandler) PrecomputeTranslations(c *gin.Context) {
+	tenantID, ok := middleware.GetTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "MISSING_TENANT_ID",
+			"message": "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	var req warming.PrecomputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+	if req.SourceLang != "" {
+		req.SourceLang = normalizeLanguageCode(req.SourceLang)
+	}
+	for i, lang := range req.TargetLangs {
+		req.TargetLangs[i] = normalizeLanguageCode(lang)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	job, err := h.warmer.SchedulePrecompute(ctx, tenantID, req)
+	if err != nil {
+		if errors.Is(err, warming.ErrTooManyItems) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "TOO_MANY_ITEMS",
+				"message": err.Error(),
+			})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to schedule precompute job")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "PRECOMPUTE_FAILED",
+			"message": "Failed to schedule precompute job",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetPrecomputeJob returns the progress of a precompute job
+// GET /api/v1/cache/precompute/:id
+func (h *TranslationHandler) GetPrecomputeJob(c *gin.Context) {
+	tenantID, ok := middleware.GetTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "MISSING_TENANT_ID",
+			"message": "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	job, found := h.warmer.GetJob(tenantID, c.Param("id"))
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "JOB_NOT_FOUND",
+			"message": "Precompute job not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
 // Health returns service health status
 // GET /health
 func (h *TranslationHandler) Health(c *gin.Context) {
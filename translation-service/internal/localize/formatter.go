@@ -0,0 +1,390 @@
+package localize
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/ar"
+	"github.com/go-playground/locales/ar_AE"
+	"github.com/go-playground/locales/bn"
+	"github.com/go-playground/locales/bn_IN"
+	"github.com/go-playground/locales/currency"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/en_AU"
+	"github.com/go-playground/locales/en_CA"
+	"github.com/go-playground/locales/en_GB"
+	"github.com/go-playground/locales/en_IN"
+	"github.com/go-playground/locales/en_SG"
+	"github.com/go-playground/locales/en_US"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/es_MX"
+	"github.com/go-playground/locales/fa"
+	"github.com/go-playground/locales/fil"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/gu"
+	"github.com/go-playground/locales/he"
+	"github.com/go-playground/locales/hi"
+	"github.com/go-playground/locales/hi_IN"
+	"github.com/go-playground/locales/id"
+	"github.com/go-playground/locales/it"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/kn"
+	"github.com/go-playground/locales/ko"
+	"github.com/go-playground/locales/ml"
+	"github.com/go-playground/locales/mr"
+	"github.com/go-playground/locales/ms"
+	"github.com/go-playground/locales/nl"
+	"github.com/go-playground/locales/or"
+	"github.com/go-playground/locales/pa"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/pt_BR"
+	"github.com/go-playground/locales/ru"
+	"github.com/go-playground/locales/ta"
+	"github.com/go-playground/locales/ta_IN"
+	"github.com/go-playground/locales/te"
+	"github.com/go-playground/locales/th"
+	"github.com/go-playground/locales/tr"
+	"github.com/go-playground/locales/vi"
+	"github.com/go-playground/locales/zh"
+)
+
+// DefaultLocale is used when the requested locale is not supported
+const DefaultLocale = "en"
+
+// Format types
+const (
+	TypeCurrency = "currency"
+	TypeNumber   = "number"
+	TypePercent  = "percent"
+	TypeDate     = "date"
+	TypeTime     = "time"
+	TypeDateTime = "datetime"
+)
+
+// Date/time styles (CLDR lengths)
+const (
+	StyleShort  = "short"
+	StyleMedium = "medium"
+	StyleLong   = "long"
+	StyleFull   = "full"
+)
+
+// Errors returned when an item cannot be formatted
+var (
+	ErrUnsupportedType     = errors.New("unsupported format type")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrInvalidValue        = errors.New("invalid value")
+)
+
+// translators maps CLDR locale identifiers to their rule sets
+// Covers the languages offered by the translation service plus common regional variants
+var translators = map[string]func() locales.Translator{
+	"ar": ar.New, "ar_AE": ar_AE.New,
+	"bn": bn.New, "bn_IN": bn_IN.New,
+	"de": de.New,
+	"en": en.New, "en_AU": en_AU.New, "en_CA": en_CA.New, "en_GB": en_GB.New,
+	"en_IN": en_IN.New, "en_SG": en_SG.New, "en_US": en_US.New,
+	"es": es.New, "es_MX": es_MX.New,
+	"fa":  fa.New,
+	"fil": fil.New, "tl": fil.New,
+	"fr": fr.New,
+	"gu": gu.New,
+	"he": he.New,
+	"hi": hi.New, "hi_IN": hi_IN.New,
+	"id": id.New,
+	"it": it.New,
+	"ja": ja.New,
+	"kn": kn.New,
+	"ko": ko.New,
+	"ml": ml.New,
+	"mr": mr.New,
+	"ms": ms.New,
+	"nl": nl.New,
+	"or": or.New,
+	"pa": pa.New,
+	"pt": pt.New, "pt_BR": pt_BR.New,
+	"ru": ru.New,
+	"ta": ta.New, "ta_IN": ta_IN.New,
+	"te": te.New,
+	"th": th.New,
+	"tr": tr.New,
+	"vi": vi.New,
+	"zh": zh.New,
+}
+
+// currencies maps supported ISO 4217 codes to their CLDR type and minor unit digits
+var currencies = map[string]struct {
+	code   currency.Type
+	digits uint64
+}{
+	"AED": {currency.AED, 2},
+	"AUD": {currency.AUD, 2},
+	"BDT": {currency.BDT, 2},
+	"BHD": {currency.BHD, 3},
+	"BRL": {currency.BRL, 2},
+	"CAD": {currency.CAD, 2},
+	"CHF": {currency.CHF, 2},
+	"CNY": {currency.CNY, 2},
+	"EUR": {currency.EUR, 2},
+	"GBP": {currency.GBP, 2},
+	"IDR": {currency.IDR, 2},
+	"ILS": {currency.ILS, 2},
+	"INR": {currency.INR, 2},
+	"JPY": {currency.JPY, 0},
+	"KRW": {currency.KRW, 0},
+	"KWD": {currency.KWD, 3},
+	"LKR": {currency.LKR, 2},
+	"MXN": {currency.MXN, 2},
+	"MYR": {currency.MYR, 2},
+	"NPR": {currency.NPR, 2},
+	"NZD": {currency.NZD, 2},
+	"PHP": {currency.PHP, 2},
+	"RUB": {currency.RUB, 2},
+	"SAR": {currency.SAR, 2},
+	"SGD": {currency.SGD, 2},
+	"THB": {currency.THB, 2},
+	"TRY": {currency.TRY, 2},
+	"USD": {currency.USD, 2},
+	"VND": {currency.VND, 0},
+	"ZAR": {currency.ZAR, 2},
+}
+
+// Item is a single value to format
+type Item struct {
+	Key      string          `json:"key,omitempty"` // Caller reference echoed back in the result
+	Type     string          `json:"type" binding:"required,oneof=currency number percent date time datetime"`
+	Value    json.RawMessage `json:"value" binding:"required"` // Number for numeric types; RFC3339/YYYY-MM-DD string or unix seconds for dates
+	Currency string          `json:"currency,omitempty"`       // ISO 4217 code, required for currency
+	Decimals *int            `json:"decimals,omitempty"`       // Fraction digits; defaults to the currency's minor unit or the value's precision
+	Style    string          `json:"style,omitempty" binding:"omitempty,oneof=short medium long full"`
+	Timezone string          `json:"timezone,omitempty"` // IANA zone for dates, defaults to UTC
+}
+
+// Result is the formatted output for one item
+type Result struct {
+	Key       string `json:"key,omitempty"`
+	Type      string `json:"type"`
+	Formatted string `json:"formatted,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// FormatRequest is a batch of values to format for one locale
+type FormatRequest struct {
+	Locale string `json:"locale" binding:"required"` // BCP 47 or CLDR identifier, e.g. "hi-IN"
+	Items  []Item `json:"items" binding:"required,min=1,dive"`
+}
+
+// FormatResponse holds formatted values in request order
+type FormatResponse struct {
+	Locale  string   `json:"locale"` // Resolved CLDR locale used for formatting
+	Results []Result `json:"results"`
+}
+
+// Formatter formats values using the CLDR rules of a single locale
+type Formatter struct {
+	translator locales.Translator
+}
+
+// NewFormatter returns a formatter for the closest supported match of locale
+// Accepts BCP 47 ("en-IN") or CLDR ("en_IN") identifiers, falling back to the base language, then DefaultLocale
+func NewFormatter(locale string) *Formatter {
+	return &Formatter{translator: translators[ResolveLocale(locale)]()}
+}
+
+// ResolveLocale returns the supported CLDR locale identifier used for locale
+func ResolveLocale(locale string) string {
+	tag := strings.ReplaceAll(strings.TrimSpace(locale), "-", "_")
+	parts := strings.Split(tag, "_")
+	if len(parts) > 1 {
+		parts[len(parts)-1] = strings.ToUpper(parts[len(parts)-1])
+	}
+	parts[0] = strings.ToLower(parts[0])
+
+	candidate := strings.Join(parts, "_")
+	if _, ok := translators[candidate]; ok {
+		return candidate
+	}
+	if _, ok := translators[parts[0]]; ok {
+		return parts[0]
+	}
+	return DefaultLocale
+}
+
+// Locale returns the resolved CLDR locale identifier
+func (f *Formatter) Locale() string {
+	return f.translator.Locale()
+}
+
+// Format formats a single item, returning the error in the result rather than failing the batch
+func (f *Formatter) Format(item Item) Result {
+	result := Result{Key: item.Key, Type: item.Type}
+
+	formatted, err := f.format(item)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Formatted = formatted
+	return result
+}
+
+func (f *Formatter) format(item Item) (string, error) {
+	switch item.Type {
+	case TypeCurrency:
+		amount, err := parseNumber(item.Value)
+		if err != nil {
+			return "", err
+		}
+		cur, ok := currencies[strings.ToUpper(item.Currency)]
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrUnsupportedCurrency, item.Currency)
+		}
+		digits := cur.digits
+		if item.Decimals != nil {
+			digits = clampDecimals(*item.Decimals)
+		}
+		formatted := f.translator.FmtCurrency(amount, digits, cur.code)
+		if digits == 0 {
+			// CLDR currency patterns pad to two fraction digits; zero-decimal currencies (JPY, KRW) drop them
+			formatted = strings.Replace(formatted, f.decimalSeparator()+"00", "", 1)
+		}
+		return formatted, nil
+
+	case TypeNumber, TypePercent:
+		num, err := parseNumber(item.Value)
+		if err != nil {
+			return "", err
+		}
+		if item.Type == TypePercent {
+			// Percent values are fractions (0.25 = 25%); round away float noise from the scaling
+			num = math.Round(num*100*1e6) / 1e6
+		}
+		digits := precision(num)
+		if item.Decimals != nil {
+			digits = clampDecimals(*item.Decimals)
+		}
+		if item.Type == TypePercent {
+			return f.translator.FmtPercent(num, digits), nil
+		}
+		return f.translator.FmtNumber(num, digits), nil
+
+	case TypeDate, TypeTime, TypeDateTime:
+		t, err := parseTime(item.Value, item.Timezone)
+		if err != nil {
+			return "", err
+		}
+		switch item.Type {
+		case TypeDate:
+			return f.date(t, item.Style), nil
+		case TypeTime:
+			return f.time(t, item.Style), nil
+		default:
+			return f.date(t, item.Style) + " " + f.time(t, item.Style), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedType, item.Type)
+}
+
+// decimalSeparator returns the locale's decimal symbol (not exposed by the Translator interface)
+func (f *Formatter) decimalSeparator() string {
+	s := f.translator.FmtNumber(0.5, 1)
+	return strings.TrimSuffix(strings.TrimPrefix(s, "0"), "5")
+}
+
+func (f *Formatter) date(t time.Time, style string) string {
+	switch style {
+	case StyleShort:
+		return f.translator.FmtDateShort(t)
+	case StyleLong:
+		return f.translator.FmtDateLong(t)
+	case StyleFull:
+		return f.translator.FmtDateFull(t)
+	default:
+		return f.translator.FmtDateMedium(t)
+	}
+}
+
+func (f *Formatter) time(t time.Time, style string) string {
+	switch style {
+	case StyleMedium:
+		return f.translator.FmtTimeMedium(t)
+	case StyleLong:
+		return f.translator.FmtTimeLong(t)
+	case StyleFull:
+		return f.translator.FmtTimeFull(t)
+	default:
+		return f.translator.FmtTimeShort(t)
+	}
+}
+
+// parseNumber accepts a JSON number or numeric string
+func parseNumber(raw json.RawMessage) (float64, error) {
+	var num float64
+	if err := json.Unmarshal(raw, &num); err == nil {
+		return num, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if num, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return num, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: expected a number", ErrInvalidValue)
+}
+
+// parseTime accepts RFC3339, YYYY-MM-DD or unix seconds and converts to the requested timezone
+func parseTime(raw json.RawMessage, timezone string) (time.Time, error) {
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return time.Time{}, fmt.Errorf("%w: unknown timezone %q", ErrInvalidValue, timezone)
+		}
+	}
+
+	var unix int64
+	if err := json.Unmarshal(raw, &unix); err == nil {
+		return time.Unix(unix, 0).In(loc), nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return time.Time{}, fmt.Errorf("%w: expected a date string or unix timestamp", ErrInvalidValue)
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(loc), nil
+	}
+	// Date-only values are calendar dates and are not shifted across zones
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: %q is not RFC3339 or YYYY-MM-DD", ErrInvalidValue, s)
+}
+
+// precision returns the number of fraction digits needed to represent num (capped at 6)
+func precision(num float64) uint64 {
+	s := strconv.FormatFloat(math.Abs(num), 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return clampDecimals(len(s) - i - 1)
+	}
+	return 0
+}
+
+func clampDecimals(d int) uint64 {
+	if d < 0 {
+		return 0
+	}
+	if d > 6 {
+		return 6
+	}
+	return uint64(d)
+}
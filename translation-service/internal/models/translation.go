@@ -3,6 +3,8 @@ package models
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,7 +32,7 @@ type TranslationCache struct {
 	SourceHash   string    `json:"source_hash" gorm:"type:varchar(64);not null;uniqueIndex:idx_translation_cache_unique"`
 	SourceText   string    `json:"source_text" gorm:"type:text;not null"`
 	TranslatedText string  `json:"translated_text" gorm:"type:text;not null"`
-	Context      string    `json:"context" gorm:"type:varchar(100)"` // e.g., "product_name", "category", "ui_label"
+	Context      string    `json:"context" gorm:"type:varchar(150)"` // e.g., "product_name", "category", "ui_label"; see StyledContext
 	HitCount     int       `json:"hit_count" gorm:"default:0"`
 	Provider     string    `json:"provider" gorm:"type:varchar(50)"` // libretranslate, huggingface, etc.
 	CreatedAt    time.Time `json:"created_at"`
@@ -45,12 +47,40 @@ func GenerateSourceHash(sourceLang, targetLang, sourceText, context string) stri
 	return hex.EncodeToString(hash[:])
 }
 
+// styleSeparator separates the caller's context from the style suffix in a styled context
+const styleSeparator = "#style="
+
+// StyledContext folds formality and tone into a translation context, so cache keys and
+// hashes distinguish translations of the same text in different styles. An unstyled
+// context is returned unchanged, keeping existing cache entries valid.
+func StyledContext(context, formality, tone string) string {
+	if formality == "" && tone == "" {
+		return context
+	}
+	return context + styleSeparator + formality + "/" + tone
+}
+
+// SplitStyledContext reverses StyledContext
+func SplitStyledContext(styled string) (context, formality, tone string) {
+	i := strings.LastIndex(styled, styleSeparator)
+	if i < 0 {
+		return styled, "", ""
+	}
+	context = styled[:i]
+	formality, tone, _ = strings.Cut(styled[i+len(styleSeparator):], "/")
+	return context, formality, tone
+}
+
 // TranslationRequest represents a translation request from the API
 type TranslationRequest struct {
 	Text       string `json:"text" binding:"required"`
 	SourceLang string `json:"source_lang"` // Optional, auto-detect if empty
 	TargetLang string `json:"target_lang" binding:"required"`
 	Context    string `json:"context"` // Optional context for better translation
+
+	// Optional style hints; empty values fall back to the tenant's defaults
+	Formality string `json:"formality" binding:"omitempty,oneof=formal informal"`
+	Tone      string `json:"tone" binding:"omitempty,oneof=friendly professional playful luxury"`
 }
 
 // TranslationResponse represents the response for a single translation
@@ -61,6 +91,8 @@ type TranslationResponse struct {
 	TargetLang     string `json:"target_lang"`
 	Cached         bool   `json:"cached"`
 	Provider       string `json:"provider,omitempty"`
+	Formality      string `json:"formality,omitempty"` // Formality the translation was produced with
+	Tone           string `json:"tone,omitempty"`      // Tone the translation was produced with
 }
 
 // BatchTranslationRequest represents a batch translation request
@@ -68,6 +100,8 @@ type BatchTranslationRequest struct {
 	Items      []TranslationItem `json:"items" binding:"required,min=1,max=50"`
 	SourceLang string            `json:"source_lang"` // Default source lang for all items
 	TargetLang string            `json:"target_lang" binding:"required"`
+	Formality  string            `json:"formality" binding:"omitempty,oneof=formal informal"`
+	Tone       string            `json:"tone" binding:"omitempty,oneof=friendly professional playful luxury"`
 }
 
 // TranslationItem represents a single item in a batch request
@@ -93,6 +127,8 @@ type BatchTranslationItem struct {
 	TranslatedText string `json:"translated_text"`
 	SourceLang     string `json:"source_lang"`
 	Cached         bool   `json:"cached"`
+	Formality      string `json:"formality,omitempty"`
+	Tone           string `json:"tone,omitempty"`
 	Error          string `json:"error,omitempty"`
 }
 
@@ -128,10 +164,30 @@ type TenantLanguagePreference struct {
 	DefaultTargetLang string    `json:"default_target_lang" gorm:"type:varchar(10);default:'hi'"`
 	EnabledLanguages  []byte    `json:"enabled_languages" gorm:"type:jsonb"` // JSON array of enabled language codes
 	AutoDetect        bool      `json:"auto_detect" gorm:"default:true"`
+	DefaultFormality  string    `json:"default_formality" gorm:"type:varchar(20)"` // formal, informal or empty for provider default
+	DefaultTone       string    `json:"default_tone" gorm:"type:varchar(20)"`      // friendly, professional, playful, luxury or empty
+	LanguageFormality []byte    `json:"language_formality" gorm:"type:jsonb"`      // JSON object of target language -> formality overrides
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 }
 
+// StyleFor returns the tenant's default formality and tone for a target language.
+// A per-language formality override wins over the default formality.
+func (p *TenantLanguagePreference) StyleFor(targetLang string) (formality, tone string) {
+	formality, tone = p.DefaultFormality, p.DefaultTone
+	if len(p.LanguageFormality) == 0 {
+		return formality, tone
+	}
+	var overrides map[string]string
+	if err := json.Unmarshal(p.LanguageFormality, &overrides); err != nil {
+		return formality, tone
+	}
+	if f, ok := overrides[targetLang]; ok {
+		formality = f
+	}
+	return formality, tone
+}
+
 // UserLanguagePreference stores language preferences per user within a tenant
 // This is the main table for user-specific language settings
 type UserLanguagePreference struct {
@@ -229,7 +229,7 @@ func (r *translationRepository) SavePreference(ctx context.Context, pref *models
 	return r.db.WithContext(ctx).
 		Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "tenant_id"}},
-			DoUpdates: clause.AssignmentColumns([]string{"default_source_lang", "default_target_lang", "enabled_languages", "auto_detect", "updated_at"}),
+			DoUpdates: clause.AssignmentColumns([]string{"default_source_lang", "default_target_lang", "enabled_languages", "auto_detect", "default_formality", "default_tone", "language_formality", "updated_at"}),
 		}).
 		Create(pref).Error
 }
@@ -0,0 +1,429 @@
+package warming
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"translation-service/internal/cache"
+	"translation-service/internal/clients"
+	"translation-service/internal/config"
+	"translation-service/internal/models"
+	"translation-service/internal/repository"
+)
+
+// Precompute schedules
+const (
+	ScheduleNow     = "now"
+	ScheduleOffPeak = "off_peak"
+)
+
+// Precompute job statuses
+const (
+	JobStatusScheduled = "scheduled"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+// jobRetention is how long finished jobs stay queryable
+const jobRetention = 24 * time.Hour
+
+// ErrTooManyItems is returned when a precompute request exceeds MaxPrecomputeItems
+var ErrTooManyItems = errors.New("too many items for precompute")
+
+// PrecomputeItem is a known source string to translate ahead of time
+type PrecomputeItem struct {
+	Text    string `json:"text" binding:"required"`
+	Context string `json:"context,omitempty"`
+}
+
+// PrecomputeRequest asks for items to be translated into all tenant-enabled languages
+type PrecomputeRequest struct {
+	Items       []PrecomputeItem `json:"items" binding:"required,min=1"`
+	SourceLang  string           `json:"source_lang,omitempty"`
+	TargetLangs []string         `json:"target_langs,omitempty"` // Defaults to the tenant's enabled languages
+	Schedule    string           `json:"schedule,omitempty" binding:"omitempty,oneof=now off_peak"`
+	Formality   string           `json:"formality,omitempty" binding:"omitempty,oneof=formal informal"`                 // Defaults to the tenant's style
+	Tone        string           `json:"tone,omitempty" binding:"omitempty,oneof=friendly professional playful luxury"` // Defaults to the tenant's style
+}
+
+// PrecomputeJob tracks the progress of a precompute request
+type PrecomputeJob struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenant_id"`
+	Status      string     `json:"status"`
+	Schedule    string     `json:"schedule"`
+	SourceLang  string     `json:"source_lang"`
+	TargetLangs []string   `json:"target_langs"`
+	Total       int        `json:"total"`
+	Translated  int        `json:"translated"`
+	Skipped     int        `json:"skipped"` // Already cached
+	Failed      int        `json:"failed"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	items  []PrecomputeItem
+	styles map[string]clients.TranslationStyle // Style per target language
+}
+
+// Warmer keeps popular translations in cache and precomputes known strings
+type Warmer struct {
+	repo         repository.TranslationRepository
+	cache        *cache.TranslationCache
+	orchestrator *clients.TranslationOrchestrator
+	config       *config.TranslationConfig
+	logger       *logrus.Entry
+
+	mu   sync.Mutex
+	jobs map[string]*PrecomputeJob
+}
+
+// NewWarmer creates a new cache warmer
+func NewWarmer(
+	repo repository.TranslationRepository,
+	translationCache *cache.TranslationCache,
+	orchestrator *clients.TranslationOrchestrator,
+	cfg *config.TranslationConfig,
+	logger *logrus.Entry,
+) *Warmer {
+	return &Warmer{
+		repo:         repo,
+		cache:        translationCache,
+		orchestrator: orchestrator,
+		config:       cfg,
+		logger:       logger.WithField("component", "cache_warmer"),
+		jobs:         make(map[string]*PrecomputeJob),
+	}
+}
+
+// Start runs the warming loop until ctx is cancelled
+// Each cycle refreshes hot keys close to expiry (when Redis caching and warming are enabled)
+// and runs any off-peak precompute jobs that are due
+func (w *Warmer) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.config.CacheWarmInterval)
+	defer ticker.Stop()
+
+	w.logger.WithFields(logrus.Fields{
+		"interval":       w.config.CacheWarmInterval,
+		"refresh_before": w.config.CacheWarmRefreshBefore,
+		"min_hits":       w.config.CacheWarmMinHits,
+	}).Info("Cache warmer started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Cache warmer stopped")
+			return
+		case <-ticker.C:
+			w.refreshHotKeys(ctx)
+			w.runDueJobs(ctx)
+		}
+	}
+}
+
+// refreshHotKeys re-translates popular entries whose TTL is about to run out
+func (w *Warmer) refreshHotKeys(ctx context.Context) {
+	if w.cache == nil || !w.config.CacheWarmingEnabled {
+		return
+	}
+
+	hotKeys, err := w.cache.HotKeys(ctx, w.config.CacheWarmMaxKeys, float64(w.config.CacheWarmMinHits))
+	if err != nil {
+		w.logger.WithError(err).Warn("Failed to load hot keys")
+		return
+	}
+
+	refreshed := 0
+	for _, hotKey := range hotKeys {
+		if ctx.Err() != nil {
+			return
+		}
+
+		entry, ttl, err := w.cache.GetByKey(ctx, hotKey.Key)
+		if err != nil {
+			w.logger.WithError(err).WithField("key", hotKey.Key).Debug("Failed to read hot key")
+			continue
+		}
+		// Expired entries are repopulated on the next request; entries cached before
+		// warming was added lack the source text and cannot be re-translated
+		if entry == nil || entry.SourceText == "" || entry.TenantID == "" {
+			_ = w.cache.ForgetHotKey(ctx, hotKey.Key)
+			continue
+		}
+		if ttl > w.config.CacheWarmRefreshBefore {
+			continue
+		}
+
+		// Styled entries carry their style in the context; refresh them in the same style
+		translationContext, formality, tone := models.SplitStyledContext(entry.Context)
+		style := clients.TranslationStyle{Formality: formality, Tone: tone}
+		if err := w.translateAndStore(ctx, entry.TenantID, entry.SourceLang, entry.TargetLang, entry.SourceText, translationContext, style); err != nil {
+			w.logger.WithError(err).WithField("key", hotKey.Key).Warn("Failed to refresh hot key")
+			continue
+		}
+		refreshed++
+	}
+
+	if err := w.cache.DecayHotKeys(ctx); err != nil {
+		w.logger.WithError(err).Warn("Failed to decay hot keys")
+	}
+
+	if refreshed > 0 {
+		w.logger.WithFields(logrus.Fields{
+			"refreshed": refreshed,
+			"hot_keys":  len(hotKeys),
+		}).Info("Refreshed hot translation cache entries")
+	}
+}
+
+// translateAndStore translates text and writes it to both cache layers with a fresh expiry,
+// keyed on the style the provider applied
+func (w *Warmer) translateAndStore(ctx context.Context, tenantID, sourceLang, targetLang, text, translationContext string, style clients.TranslationStyle) error {
+	result, err := w.orchestrator.TranslateStyled(ctx, text, sourceLang, targetLang, style)
+	if err != nil {
+		return err
+	}
+	provider := string(result.Provider)
+	translationContext = models.StyledContext(translationContext, result.Style.Formality, result.Style.Tone)
+
+	if err := w.repo.SaveTranslation(ctx, &models.TranslationCache{
+		TenantID:       tenantID,
+		SourceLang:     sourceLang,
+		TargetLang:     targetLang,
+		SourceHash:     models.GenerateSourceHash(sourceLang, targetLang, text, translationContext),
+		SourceText:     text,
+		TranslatedText: result.TranslatedText,
+		Context:        translationContext,
+		Provider:       provider,
+		ExpiresAt:      time.Now().Add(w.config.CacheTTL),
+	}); err != nil {
+		w.logger.WithError(err).Warn("Failed to save warmed translation to database cache")
+	}
+
+	if w.cache != nil {
+		return w.cache.Set(ctx, tenantID, sourceLang, targetLang, text, result.TranslatedText, translationContext, provider)
+	}
+	return nil
+}
+
+// SchedulePrecompute validates a request and queues a precompute job
+// Jobs scheduled "now" start immediately; off-peak jobs wait for the configured window
+func (w *Warmer) SchedulePrecompute(ctx context.Context, tenantID string, req PrecomputeRequest) (*PrecomputeJob, error) {
+	if len(req.Items) > w.config.MaxPrecomputeItems {
+		return nil, fmt.Errorf("%w: %d exceeds limit of %d", ErrTooManyItems, len(req.Items), w.config.MaxPrecomputeItems)
+	}
+
+	// The preference also supplies the tenant's style defaults, so it is always loaded
+	pref, err := w.repo.GetPreference(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tenant language preferences: %w", err)
+	}
+
+	sourceLang := req.SourceLang
+	targetLangs := req.TargetLangs
+	if sourceLang == "" {
+		sourceLang = pref.DefaultSourceLang
+	}
+	if len(targetLangs) == 0 {
+		if err := json.Unmarshal(pref.EnabledLanguages, &targetLangs); err != nil {
+			return nil, fmt.Errorf("failed to parse enabled languages: %w", err)
+		}
+	}
+	if sourceLang == "" {
+		sourceLang = w.config.DefaultSourceLang
+	}
+
+	// Warm entries in the style translate requests will look up
+	langs := make([]string, 0, len(targetLangs))
+	styles := make(map[string]clients.TranslationStyle, len(targetLangs))
+	for _, lang := range targetLangs {
+		if lang == sourceLang {
+			continue
+		}
+		langs = append(langs, lang)
+		formality, tone := pref.StyleFor(lang)
+		if req.Formality != "" {
+			formality = req.Formality
+		}
+		if req.Tone != "" {
+			tone = req.Tone
+		}
+		styles[lang] = clients.TranslationStyle{Formality: formality, Tone: tone}
+	}
+
+	schedule := req.Schedule
+	if schedule == "" {
+		schedule = ScheduleOffPeak
+	}
+
+	job := &PrecomputeJob{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		Status:      JobStatusScheduled,
+		Schedule:    schedule,
+		SourceLang:  sourceLang,
+		TargetLangs: langs,
+		Total:       len(req.Items) * len(langs),
+		CreatedAt:   time.Now(),
+		items:       req.Items,
+		styles:      styles,
+	}
+
+	w.mu.Lock()
+	w.pruneJobsLocked()
+	w.jobs[job.ID] = job
+	snapshot := job.snapshot()
+	w.mu.Unlock()
+
+	if schedule == ScheduleNow {
+		go w.runJob(context.Background(), job)
+	}
+
+	return snapshot, nil
+}
+
+// GetJob returns a copy of a precompute job owned by the tenant
+func (w *Warmer) GetJob(tenantID, jobID string) (*PrecomputeJob, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	job, ok := w.jobs[jobID]
+	if !ok || job.TenantID != tenantID {
+		return nil, false
+	}
+	return job.snapshot(), true
+}
+
+// runDueJobs starts scheduled off-peak jobs when inside the off-peak window
+func (w *Warmer) runDueJobs(ctx context.Context) {
+	if !w.inOffPeak(time.Now().UTC()) {
+		return
+	}
+
+	w.mu.Lock()
+	var due []*PrecomputeJob
+	for _, job := range w.jobs {
+		if job.Status == JobStatusScheduled && job.Schedule == ScheduleOffPeak {
+			job.Status = JobStatusRunning
+			due = append(due, job)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, job := range due {
+		w.runJob(ctx, job)
+	}
+}
+
+// runJob translates every item into every target language, skipping entries already cached
+func (w *Warmer) runJob(ctx context.Context, job *PrecomputeJob) {
+	w.mu.Lock()
+	now := time.Now()
+	job.Status = JobStatusRunning
+	job.StartedAt = &now
+	w.mu.Unlock()
+
+	log := w.logger.WithFields(logrus.Fields{
+		"job_id":    job.ID,
+		"tenant_id": job.TenantID,
+		"total":     job.Total,
+	})
+	log.Info("Precompute job started")
+
+	for _, item := range job.items {
+		for _, targetLang := range job.TargetLangs {
+			if ctx.Err() != nil {
+				w.finishJob(job, ctx.Err())
+				return
+			}
+
+			var err error
+			style := job.styles[targetLang]
+			skipped := w.isCached(ctx, job.TenantID, job.SourceLang, targetLang, item, style)
+			if !skipped {
+				err = w.translateAndStore(ctx, job.TenantID, job.SourceLang, targetLang, item.Text, item.Context, style)
+			}
+
+			w.mu.Lock()
+			switch {
+			case skipped:
+				job.Skipped++
+			case err != nil:
+				job.Failed++
+			default:
+				job.Translated++
+			}
+			w.mu.Unlock()
+
+			if err != nil {
+				log.WithError(err).WithField("target_lang", targetLang).Debug("Precompute translation failed")
+			}
+		}
+	}
+
+	w.finishJob(job, nil)
+	log.Info("Precompute job completed")
+}
+
+// isCached reports whether a fresh translation in the given style already exists in the database cache
+func (w *Warmer) isCached(ctx context.Context, tenantID, sourceLang, targetLang string, item PrecomputeItem, style clients.TranslationStyle) bool {
+	applied := w.orchestrator.ApplicableStyle(ctx, sourceLang, targetLang, style)
+	translationContext := models.StyledContext(item.Context, applied.Formality, applied.Tone)
+	sourceHash := models.GenerateSourceHash(sourceLang, targetLang, item.Text, translationContext)
+	cached, err := w.repo.GetCachedTranslation(ctx, tenantID, sourceLang, targetLang, sourceHash)
+	return err == nil && cached != nil && time.Until(cached.ExpiresAt) > w.config.CacheWarmRefreshBefore
+}
+
+func (w *Warmer) finishJob(job *PrecomputeJob, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	job.CompletedAt = &now
+	job.items = nil
+	if err != nil {
+		job.Status = JobStatusFailed
+		job.Error = err.Error()
+		return
+	}
+	job.Status = JobStatusCompleted
+}
+
+// inOffPeak reports whether t (UTC) falls in the configured off-peak window, which may wrap midnight
+func (w *Warmer) inOffPeak(t time.Time) bool {
+	start, end, hour := w.config.OffPeakStartHour, w.config.OffPeakEndHour, t.Hour()
+	if start == end {
+		return true
+	}
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// pruneJobsLocked drops finished jobs past their retention; caller holds w.mu
+func (w *Warmer) pruneJobsLocked() {
+	for id, job := range w.jobs {
+		if job.CompletedAt != nil && time.Since(*job.CompletedAt) > jobRetention {
+			delete(w.jobs, id)
+		}
+	}
+}
+
+// snapshot returns a copy safe to hand out while the job keeps running; caller holds w.mu
+func (j *PrecomputeJob) snapshot() *PrecomputeJob {
+	c := *j
+	c.items = nil
+	c.styles = nil
+	c.TargetLangs = append([]string(nil), j.TargetLangs...)
+	return &c
+}
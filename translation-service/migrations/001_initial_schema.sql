@@ -22,7 +22,7 @@ CREATE TABLE IF NOT EXISTS translation_caches (
     source_hash VARCHAR(64) NOT NULL,
     source_text TEXT NOT NULL,
     translated_text TEXT NOT NULL,
-    context VARCHAR(100),
+    context VARCHAR(150),
     hit_count INTEGER DEFAULT 0,
     provider VARCHAR(50),
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
@@ -57,6 +57,9 @@ CREATE TABLE IF NOT EXISTS tenant_language_preferences (
     default_target_lang VARCHAR(10) DEFAULT 'hi',
     enabled_languages JSONB,
     auto_detect BOOLEAN DEFAULT TRUE,
+    default_formality VARCHAR(20),
+    default_tone VARCHAR(20),
+    language_formality JSONB,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
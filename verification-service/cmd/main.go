@@ -19,6 +19,7 @@ import (
 	"verification-service/internal/config"
 	"verification-service/internal/events"
 	"verification-service/internal/handlers"
+	verificationmetrics "verification-service/internal/metrics"
 	"verification-service/internal/middleware"
 	"verification-service/internal/models"
 	"verification-service/internal/providers"
@@ -55,6 +56,8 @@ func main() {
 	// Initialize repositories
 	verificationRepo := repository.NewVerificationRepository(db)
 	rateLimitRepo := repository.NewRateLimitRepository(db)
+	disposableDomainRepo := repository.NewDisposableDomainRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
 
 	// Initialize email provider
 	emailProvider, err := providers.EmailProviderFactory(
@@ -78,9 +81,20 @@ func main() {
 		log.Fatalf("Failed to initialize verification service: %v", err)
 	}
 
+	// Disposable email detection; the worker loads the blocklist and keeps the feed fresh
+	emailReputationService := services.NewEmailReputationService(cfg.Reputation, disposableDomainRepo)
+	verificationService.SetEmailReputationService(emailReputationService)
+	reputationStop := make(chan struct{})
+	go emailReputationService.RunRefreshWorker(reputationStop)
+
+	analyticsService := services.NewAnalyticsService(analyticsRepo)
+
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(db)
 	verificationHandler := handlers.NewVerificationHandler(verificationService)
+	statsHandler := handlers.NewStatsHandler(db, cfg.Alerting)
+	emailReputationHandler := handlers.NewEmailReputationHandler(emailReputationService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
 
 	// Initialize NATS events publisher (non-blocking)
 	eventLogger := logrus.New()
@@ -98,7 +112,7 @@ func main() {
 	metricsCollector := initMetrics(db)
 
 	// Setup router
-	router := setupRouter(cfg, healthHandler, verificationHandler, metricsCollector)
+	router := setupRouter(cfg, healthHandler, verificationHandler, statsHandler, emailReputationHandler, analyticsHandler, metricsCollector)
 
 	// Setup server
 	server := &http.Server{
@@ -120,6 +134,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	close(reputationStop)
 
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -132,7 +147,7 @@ func main() {
 	log.Println("Server exited")
 }
 
-func setupRouter(cfg *config.Config, healthHandler *handlers.HealthHandler, verificationHandler *handlers.VerificationHandler, metricsCollector *metrics.Metrics) *gin.Engine {
+func setupRouter(cfg *config.Config, healthHandler *handlers.HealthHandler, verificationHandler *handlers.VerificationHandler, statsHandler *handlers.StatsHandler, emailReputationHandler *handlers.EmailReputationHandler, analyticsHandler *handlers.AnalyticsHandler, metricsCollector *metrics.Metrics) *gin.Engine {
 	// Set Gin mode
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -150,9 +165,29 @@ func setupRouter(cfg *config.Config, healthHandler *handlers.HealthHandler, veri
 	router.GET("/ready", healthHandler.Ready)
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// Internal diagnostics (API key required)
+	internal := router.Group("/internal")
+	internal.Use(middleware.APIKeyAuth(cfg.Security.APIKey, cfg.Security.CallerAPIKeys))
+	{
+		internal.GET("/stats", statsHandler.Stats)
+
+		// Disposable domain blocklist management
+		internal.GET("/disposable-domains/status", emailReputationHandler.Status)
+		internal.POST("/disposable-domains", emailReputationHandler.AddDomains)
+		internal.POST("/disposable-domains/refresh", emailReputationHandler.Refresh)
+		internal.DELETE("/disposable-domains/:domain", emailReputationHandler.RemoveDomain)
+
+		// Verification analytics dashboard
+		internal.GET("/analytics/summary", analyticsHandler.Summary)
+		internal.GET("/analytics/timeseries", analyticsHandler.Timeseries)
+		internal.GET("/analytics/templates", analyticsHandler.Templates)
+		internal.GET("/analytics/callers", analyticsHandler.Callers)
+		internal.GET("/analytics/breakdown", analyticsHandler.Breakdown)
+	}
+
 	// API v1 routes (with API key authentication)
 	v1 := router.Group("/api/v1")
-	v1.Use(middleware.APIKeyAuth(cfg.Security.APIKey))
+	v1.Use(middleware.APIKeyAuth(cfg.Security.APIKey, cfg.Security.CallerAPIKeys))
 	{
 		// Verification endpoints
 		v1.POST("/verify/send", verificationHandler.SendCode)
@@ -162,6 +197,7 @@ func setupRouter(cfg *config.Config, healthHandler *handlers.HealthHandler, veri
 
 		// Email endpoints
 		v1.POST("/email/send", verificationHandler.SendEmail)
+		v1.GET("/email/reputation", emailReputationHandler.GetReputation)
 	}
 
 	return router
@@ -180,6 +216,7 @@ func autoMigrate(db *gorm.DB) error {
 		&models.VerificationCode{},
 		&models.VerificationAttempt{},
 		&models.RateLimit{},
+		&models.DisposableDomain{},
 	}
 
 	for _, model := range modelsToMigrate {
@@ -221,45 +258,8 @@ func initMetrics(db *gorm.DB) *metrics.Metrics {
 		Subsystem:   "verification",
 	})
 
-	// Register verification-specific business metrics
-	codesGenerated := promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Namespace: "tesseract",
-			Subsystem: "verification",
-			Name:      "codes_generated_total",
-			Help:      "Total number of verification codes generated",
-		},
-		[]string{"type"}, // email, phone
-	)
-
-	attemptsTotal := promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Namespace: "tesseract",
-			Subsystem: "verification",
-			Name:      "attempts_total",
-			Help:      "Total number of verification attempts",
-		},
-		[]string{"type", "result"}, // type: email/phone, result: success/failure
-	)
-
-	rateLimitsHit := promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Namespace: "tesseract",
-			Subsystem: "verification",
-			Name:      "rate_limits_hit_total",
-			Help:      "Total number of rate limit violations",
-		},
-		[]string{"type"}, // send, verify
-	)
-
-	activeVerifications := promauto.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: "tesseract",
-			Subsystem: "verification",
-			Name:      "active_verifications",
-			Help:      "Number of currently active (pending) verifications",
-		},
-	)
+	// Verification business metrics (codes, attempts, rate limits, provider sends)
+	// are registered in internal/metrics and recorded by the service layer
 
 	// Database connection pool metrics
 	dbConnectionsOpen := promauto.NewGauge(
@@ -312,19 +312,14 @@ func initMetrics(db *gorm.DB) *metrics.Metrics {
 			db.Model(&models.VerificationCode{}).
 				Where("is_used = ? AND verified_at IS NULL AND expires_at > ?", false, time.Now()).
 				Count(&count)
-			activeVerifications.Set(float64(count))
+			verificationmetrics.SetActiveVerifications(count)
 		}
 	}()
 
 	// Log metrics initialization
 	log.Println("Metrics initialized successfully")
-	log.Printf("Registered metrics: codes_generated_total, attempts_total, rate_limits_hit_total, active_verifications")
+	log.Printf("Registered metrics: codes_generated_total, attempts_total, rate_limits_hit_total, active_verifications, provider_send_duration_seconds, provider_send_failures_total, disposable_rejections_total, disposable_domains")
 	log.Printf("Database metrics: db_connections_open, db_connections_in_use, db_connections_idle")
 
-	// Suppress unused variable warnings (these will be used by handlers in the future)
-	_ = codesGenerated
-	_ = attemptsTotal
-	_ = rateLimitsHit
-
 	return m
 }
@@ -4,17 +4,20 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets")
 
 // Config holds all configuration for the verification service
 type Config struct {
-	Server    ServerConfig
-	Database  DatabaseConfig
-	Email     EmailConfig
-	Security  SecurityConfig
-	RateLimit RateLimitConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Email      EmailConfig
+	Security   SecurityConfig
+	RateLimit  RateLimitConfig
+	Alerting   AlertingConfig
+	Reputation ReputationConfig
 }
 
 // ServerConfig holds server configuration
@@ -48,6 +51,9 @@ type SecurityConfig struct {
 	EncryptionKey    string // 32 bytes for AES-256
 	OTPLength        int
 	OTPExpiryMinutes int
+
+	// CallerAPIKeys are additional API keys by caller name, reported in analytics (APIKey is "default")
+	CallerAPIKeys map[string]string
 }
 
 // RateLimitConfig holds rate limiting settings
@@ -57,6 +63,19 @@ type RateLimitConfig struct {
 	CooldownMinutes int
 }
 
+// AlertingConfig holds the thresholds flagged by the /internal/stats summary
+type AlertingConfig struct {
+	ProviderFailureRate float64 // Failure ratio (0-1) above which a provider is flagged
+	ProviderLatencyMs   int     // Average send latency above which a provider is flagged
+	ProviderMinSends    int64   // Sends required before a provider is evaluated
+}
+
+// ReputationConfig holds disposable email blocklist settings
+type ReputationConfig struct {
+	BlocklistURL         string // Plain-text feed of disposable domains, one per line (empty = builtin and manual entries only)
+	RefreshIntervalHours int    // How often the feed is re-imported (0 = never)
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	config := &Config{
@@ -81,6 +100,7 @@ func Load() (*Config, error) {
 		},
 		Security: SecurityConfig{
 			APIKey:           secrets.GetAPIKey(),
+			CallerAPIKeys:    getEnvAsKeyMap("CALLER_API_KEYS"),
 			EncryptionKey:    secrets.GetEncryptionKey(),
 			OTPLength:        getEnvAsInt("OTP_LENGTH", 6),
 			OTPExpiryMinutes: getEnvAsInt("OTP_EXPIRY_MINUTES", 10),
@@ -90,6 +110,15 @@ func Load() (*Config, error) {
 			MaxCodesPerHour: getEnvAsInt("MAX_CODES_PER_HOUR", 5),
 			CooldownMinutes: getEnvAsInt("COOLDOWN_MINUTES", 60),
 		},
+		Alerting: AlertingConfig{
+			ProviderFailureRate: getEnvAsFloat("ALERT_PROVIDER_FAILURE_RATE", 0.1),
+			ProviderLatencyMs:   getEnvAsInt("ALERT_PROVIDER_LATENCY_MS", 3000),
+			ProviderMinSends:    int64(getEnvAsInt("ALERT_PROVIDER_MIN_SENDS", 20)),
+		},
+		Reputation: ReputationConfig{
+			BlocklistURL:         getEnv("DISPOSABLE_BLOCKLIST_URL", "https://raw.githubusercontent.com/disposable-email-domains/disposable-email-domains/main/disposable_email_blocklist.conf"),
+			RefreshIntervalHours: getEnvAsInt("DISPOSABLE_BLOCKLIST_REFRESH_HOURS", 24),
+		},
 	}
 
 	// Validate required fields
@@ -109,6 +138,15 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("API_KEY is required for inter-service authentication")
 	}
 
+	for name, key := range c.Security.CallerAPIKeys {
+		if name == "" || key == "" {
+			return fmt.Errorf("CALLER_API_KEYS entries must be name=key pairs")
+		}
+		if key == c.Security.APIKey {
+			return fmt.Errorf("CALLER_API_KEYS entry %q reuses API_KEY", name)
+		}
+	}
+
 	if c.Security.EncryptionKey == "" {
 		return fmt.Errorf("ENCRYPTION_KEY is required")
 	}
@@ -161,3 +199,29 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return value
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsKeyMap parses "name=value,name2=value2" into a map
+func getEnvAsKeyMap(key string) map[string]string {
+	result := map[string]string{}
+	for _, pair := range strings.Split(getEnv(key, ""), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		result[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return result
+}
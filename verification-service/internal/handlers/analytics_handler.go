@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"verification-service/internal/models"
+	"verification-service/internal/services"
+)
+
+// AnalyticsHandler serves the verification analytics dashboard
+type AnalyticsHandler struct {
+	analyticsService *services.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// Summary returns send volume, verify success rate, time-to-verify and resend rate,
+// compared with the previous period of equal length
+func (h *AnalyticsHandler) Summary(c *gin.Context) {
+	filter, ok := bindAnalyticsFilter(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.analyticsService.Summary(c.Request.Context(), filter)
+	if err != nil {
+		respondAnalyticsError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Verification analytics retrieved successfully", summary)
+}
+
+// Timeseries returns the funnel per hour or day
+func (h *AnalyticsHandler) Timeseries(c *gin.Context) {
+	filter, ok := bindAnalyticsFilter(c)
+	if !ok {
+		return
+	}
+
+	timeseries, err := h.analyticsService.Timeseries(c.Request.Context(), filter, c.Query("interval"))
+	if err != nil {
+		respondAnalyticsError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Verification analytics retrieved successfully", timeseries)
+}
+
+// Templates returns the funnel per email template
+func (h *AnalyticsHandler) Templates(c *gin.Context) {
+	h.breakdown(c, models.AnalyticsGroupTemplate)
+}
+
+// Callers returns the funnel per calling service API key
+func (h *AnalyticsHandler) Callers(c *gin.Context) {
+	h.breakdown(c, models.AnalyticsGroupCaller)
+}
+
+// Breakdown returns the funnel grouped by the group_by query parameter
+func (h *AnalyticsHandler) Breakdown(c *gin.Context) {
+	h.breakdown(c, c.Query("group_by"))
+}
+
+func (h *AnalyticsHandler) breakdown(c *gin.Context, groupBy string) {
+	filter, ok := bindAnalyticsFilter(c)
+	if !ok {
+		return
+	}
+
+	breakdown, err := h.analyticsService.Breakdown(c.Request.Context(), filter, groupBy)
+	if err != nil {
+		respondAnalyticsError(c, err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Verification analytics retrieved successfully", breakdown)
+}
+
+// bindAnalyticsFilter parses the from, to, tenant_id, purpose, channel, template and caller
+// query parameters, writing a 400 if any is malformed
+func bindAnalyticsFilter(c *gin.Context) (models.AnalyticsFilter, bool) {
+	filter := models.AnalyticsFilter{
+		Purpose:  c.Query("purpose"),
+		Channel:  c.Query("channel"),
+		Template: c.Query("template"),
+		Caller:   c.Query("caller"),
+	}
+
+	var err error
+	if filter.From, err = parseAnalyticsTime(c.Query("from")); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid from parameter", err)
+		return filter, false
+	}
+	if filter.To, err = parseAnalyticsTime(c.Query("to")); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid to parameter", err)
+		return filter, false
+	}
+	if tenantID := c.Query("tenant_id"); tenantID != "" {
+		id, err := uuid.Parse(tenantID)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid tenant_id parameter", err)
+			return filter, false
+		}
+		filter.TenantID = &id
+	}
+	return filter, true
+}
+
+// parseAnalyticsTime accepts RFC 3339 timestamps and YYYY-MM-DD dates (midnight UTC)
+func parseAnalyticsTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("expected RFC 3339 timestamp or YYYY-MM-DD date, got %q", value)
+}
+
+// respondAnalyticsError maps analytics service errors to HTTP responses
+func respondAnalyticsError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidAnalyticsQuery) {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid analytics query", err)
+		return
+	}
+	ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve verification analytics", err)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"verification-service/internal/models"
+	"verification-service/internal/services"
+)
+
+// EmailReputationHandler handles email reputation lookups and disposable blocklist management
+type EmailReputationHandler struct {
+	reputationService *services.EmailReputationService
+}
+
+// NewEmailReputationHandler creates a new email reputation handler
+func NewEmailReputationHandler(reputationService *services.EmailReputationService) *EmailReputationHandler {
+	return &EmailReputationHandler{
+		reputationService: reputationService,
+	}
+}
+
+// GetReputation classifies an email address as disposable, role, free provider or business
+func (h *EmailReputationHandler) GetReputation(c *gin.Context) {
+	email := c.Query("email")
+	if email == "" {
+		ErrorResponse(c, http.StatusBadRequest, "email query parameter is required", nil)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Email reputation retrieved successfully", h.reputationService.Classify(email))
+}
+
+// AddDomains adds domains to the blocklist manually
+func (h *EmailReputationHandler) AddDomains(c *gin.Context) {
+	var req models.DisposableDomainsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	domains, err := h.reputationService.AddDomains(c.Request.Context(), req.Domains)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Failed to add disposable domains", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Disposable domains added successfully", gin.H{"domains": domains})
+}
+
+// RemoveDomain removes a domain from the blocklist
+func (h *EmailReputationHandler) RemoveDomain(c *gin.Context) {
+	removed, err := h.reputationService.RemoveDomain(c.Request.Context(), c.Param("domain"))
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to remove disposable domain", err)
+		return
+	}
+	if !removed {
+		ErrorResponse(c, http.StatusNotFound, "Disposable domain not found", nil)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Disposable domain removed successfully", nil)
+}
+
+// Refresh re-imports the upstream blocklist feed immediately
+func (h *EmailReputationHandler) Refresh(c *gin.Context) {
+	imported, err := h.reputationService.Refresh(c.Request.Context())
+	if err != nil {
+		ErrorResponse(c, http.StatusBadGateway, "Failed to refresh disposable blocklist", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Disposable blocklist refreshed successfully", gin.H{"imported": imported})
+}
+
+// Status summarises the blocklist
+func (h *EmailReputationHandler) Status(c *gin.Context) {
+	status, err := h.reputationService.Status(c.Request.Context())
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to get disposable blocklist status", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, "Disposable blocklist status retrieved successfully", status)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"verification-service/internal/config"
+	"verification-service/internal/metrics"
+)
+
+// StatsHandler serves a JSON summary of the service metrics for quick diagnosis
+type StatsHandler struct {
+	db     *gorm.DB
+	alerts config.AlertingConfig
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(db *gorm.DB, alerts config.AlertingConfig) *StatsHandler {
+	return &StatsHandler{db: db, alerts: alerts}
+}
+
+// Stats returns the in-process metric summary, database pool stats and any breached thresholds
+func (h *StatsHandler) Stats(c *gin.Context) {
+	snapshot := metrics.GetSnapshot()
+
+	database := gin.H{"status": "unavailable"}
+	if sqlDB, err := h.db.DB(); err == nil {
+		dbStats := sqlDB.Stats()
+		database = gin.H{
+			"status":           "ok",
+			"open":             dbStats.OpenConnections,
+			"in_use":           dbStats.InUse,
+			"idle":             dbStats.Idle,
+			"wait_count":       dbStats.WaitCount,
+			"wait_duration_ms": dbStats.WaitDuration.Milliseconds(),
+		}
+	}
+
+	alerts := h.evaluate(snapshot)
+	status := "ok"
+	if len(alerts) > 0 {
+		status = "degraded"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":   status,
+		"alerts":   alerts,
+		"metrics":  snapshot,
+		"database": database,
+		"thresholds": gin.H{
+			"provider_failure_rate": h.alerts.ProviderFailureRate,
+			"provider_latency_ms":   h.alerts.ProviderLatencyMs,
+			"provider_min_sends":    h.alerts.ProviderMinSends,
+		},
+	})
+}
+
+// evaluate lists the providers breaching the configured alerting thresholds
+func (h *StatsHandler) evaluate(snapshot metrics.Snapshot) []string {
+	alerts := []string{}
+	for _, p := range snapshot.Providers {
+		if p.Sends < h.alerts.ProviderMinSends {
+			continue
+		}
+		if h.alerts.ProviderFailureRate > 0 && p.FailureRate > h.alerts.ProviderFailureRate {
+			alerts = append(alerts, fmt.Sprintf("%s/%s failure rate %.1f%% exceeds %.1f%%",
+				p.Provider, p.Operation, p.FailureRate*100, h.alerts.ProviderFailureRate*100))
+		}
+		if h.alerts.ProviderLatencyMs > 0 && p.AvgLatencyMs > float64(h.alerts.ProviderLatencyMs) {
+			alerts = append(alerts, fmt.Sprintf("%s/%s average latency %.0fms exceeds %dms",
+				p.Provider, p.Operation, p.AvgLatencyMs, h.alerts.ProviderLatencyMs))
+		}
+	}
+	return alerts
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"verification-service/internal/middleware"
 	"verification-service/internal/models"
 	"verification-service/internal/services"
 )
@@ -27,9 +29,14 @@ func (h *VerificationHandler) SendCode(c *gin.Context) {
 		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
 		return
 	}
+	req.Caller = c.GetString(middleware.CallerContextKey)
 
 	response, err := h.verificationService.SendVerificationCode(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, services.ErrDisposableEmail) {
+			ErrorResponse(c, http.StatusUnprocessableEntity, err.Error(), nil)
+			return
+		}
 		// Check for rate limit errors
 		errMsg := err.Error()
 		if errMsg == "rate limit exceeded: too many verification codes sent" {
@@ -76,6 +83,7 @@ func (h *VerificationHandler) ResendCode(c *gin.Context) {
 		ErrorResponse(c, http.StatusBadRequest, "Invalid request payload", err)
 		return
 	}
+	req.Caller = c.GetString(middleware.CallerContextKey)
 
 	response, err := h.verificationService.ResendCode(c.Request.Context(), &req)
 	if err != nil {
@@ -126,6 +134,10 @@ func (h *VerificationHandler) SendEmail(c *gin.Context) {
 
 	err := h.verificationService.SendCustomEmail(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, services.ErrDisposableEmail) {
+			ErrorResponse(c, http.StatusUnprocessableEntity, err.Error(), nil)
+			return
+		}
 		ErrorResponse(c, http.StatusInternalServerError, "Failed to send email", err)
 		return
 	}
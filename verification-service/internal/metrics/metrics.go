@@ -0,0 +1,320 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	namespace = "tesseract"
+	subsystem = "verification"
+)
+
+// Verification attempt results
+const (
+	ResultSuccess     = "success"
+	ResultInvalid     = "invalid"
+	ResultExpired     = "expired"
+	ResultUsed        = "already_used"
+	ResultMaxAttempts = "max_attempts"
+)
+
+// Rate limit types
+const (
+	RateLimitSend   = "send"
+	RateLimitVerify = "verify"
+)
+
+// Strict-mode operations that can reject disposable recipients
+const (
+	OperationVerifySend = "verify_send"
+	OperationEmailSend  = "email_send"
+)
+
+// Verification-specific business metrics
+var (
+	codesGenerated = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "codes_generated_total",
+			Help:      "Total number of verification codes generated",
+		},
+		[]string{"type"}, // email, sms
+	)
+
+	attemptsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "attempts_total",
+			Help:      "Total number of verification attempts",
+		},
+		[]string{"type", "result"}, // type: email/sms, result: success/invalid/expired/already_used/max_attempts
+	)
+
+	rateLimitsHit = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rate_limits_hit_total",
+			Help:      "Total number of rate limit violations",
+		},
+		[]string{"type"}, // send, verify
+	)
+
+	activeVerifications = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "active_verifications",
+			Help:      "Number of currently active (pending) verifications",
+		},
+	)
+
+	providerSendDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "provider_send_duration_seconds",
+			Help:      "Latency of delivery provider send calls",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30},
+		},
+		[]string{"provider", "operation"},
+	)
+
+	disposableRejections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "disposable_rejections_total",
+			Help:      "Total number of strict-mode sends rejected for a disposable email domain",
+		},
+		[]string{"operation"}, // verify_send, email_send
+	)
+
+	disposableDomains = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "disposable_domains",
+			Help:      "Number of domains on the disposable email blocklist",
+		},
+	)
+
+	providerSendFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "provider_send_failures_total",
+			Help:      "Total number of failed delivery provider send calls",
+		},
+		[]string{"provider", "operation"},
+	)
+)
+
+// stats mirrors the Prometheus metrics in memory so they can be summarised as JSON
+var stats = newCollector()
+
+// RecordCodeGenerated records a verification code sent over the given channel
+func RecordCodeGenerated(channel string) {
+	codesGenerated.WithLabelValues(channel).Inc()
+	stats.incr(stats.codes, channel)
+}
+
+// RecordAttempt records a verification attempt and its result
+func RecordAttempt(channel, result string) {
+	if channel == "" {
+		channel = "unknown"
+	}
+	attemptsTotal.WithLabelValues(channel, result).Inc()
+	stats.incr(stats.attempts, channel+":"+result)
+}
+
+// RecordRateLimitHit records a rejected send or verify operation
+func RecordRateLimitHit(limitType string) {
+	rateLimitsHit.WithLabelValues(limitType).Inc()
+	stats.incr(stats.rateLimits, limitType)
+}
+
+// SetActiveVerifications records the number of pending verification codes
+func SetActiveVerifications(count int64) {
+	activeVerifications.Set(float64(count))
+	stats.mu.Lock()
+	stats.active = count
+	stats.mu.Unlock()
+}
+
+// RecordDisposableRejection records a strict-mode send rejected for a disposable domain
+func RecordDisposableRejection(operation string) {
+	disposableRejections.WithLabelValues(operation).Inc()
+}
+
+// SetDisposableDomains records the size of the disposable domain blocklist
+func SetDisposableDomains(count int) {
+	disposableDomains.Set(float64(count))
+}
+
+// ObserveProviderSend records the latency and outcome of a provider send call
+func ObserveProviderSend(provider, operation string, duration time.Duration, err error) {
+	providerSendDuration.WithLabelValues(provider, operation).Observe(duration.Seconds())
+	if err != nil {
+		providerSendFailures.WithLabelValues(provider, operation).Inc()
+	}
+	stats.observeProvider(provider, operation, duration, err)
+}
+
+// ProviderStats summarises the send calls of one provider operation
+type ProviderStats struct {
+	Provider      string     `json:"provider"`
+	Operation     string     `json:"operation"`
+	Sends         int64      `json:"sends"`
+	Failures      int64      `json:"failures"`
+	FailureRate   float64    `json:"failure_rate"`
+	AvgLatencyMs  float64    `json:"avg_latency_ms"`
+	MaxLatencyMs  float64    `json:"max_latency_ms"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// Snapshot is a point-in-time summary of the business metrics since process start
+type Snapshot struct {
+	StartedAt           time.Time        `json:"started_at"`
+	UptimeSeconds       int64            `json:"uptime_seconds"`
+	CodesGenerated      map[string]int64 `json:"codes_generated"`
+	Attempts            map[string]int64 `json:"attempts"` // keyed by "type:result"
+	RateLimitsHit       map[string]int64 `json:"rate_limits_hit"`
+	ActiveVerifications int64            `json:"active_verifications"`
+	Providers           []ProviderStats  `json:"providers"`
+}
+
+// GetSnapshot returns the current in-memory metric summary
+func GetSnapshot() Snapshot {
+	return stats.snapshot()
+}
+
+type providerKey struct {
+	provider  string
+	operation string
+}
+
+type providerCounters struct {
+	sends         int64
+	failures      int64
+	totalLatency  time.Duration
+	maxLatency    time.Duration
+	lastSuccessAt time.Time
+	lastErrorAt   time.Time
+	lastError     string
+}
+
+type collector struct {
+	mu         sync.Mutex
+	startedAt  time.Time
+	codes      map[string]int64
+	attempts   map[string]int64
+	rateLimits map[string]int64
+	active     int64
+	providers  map[providerKey]*providerCounters
+}
+
+func newCollector() *collector {
+	return &collector{
+		startedAt:  time.Now(),
+		codes:      make(map[string]int64),
+		attempts:   make(map[string]int64),
+		rateLimits: make(map[string]int64),
+		providers:  make(map[providerKey]*providerCounters),
+	}
+}
+
+func (c *collector) incr(counts map[string]int64, key string) {
+	c.mu.Lock()
+	counts[key]++
+	c.mu.Unlock()
+}
+
+func (c *collector) observeProvider(provider, operation string, duration time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := providerKey{provider: provider, operation: operation}
+	counters, ok := c.providers[key]
+	if !ok {
+		counters = &providerCounters{}
+		c.providers[key] = counters
+	}
+
+	counters.sends++
+	counters.totalLatency += duration
+	if duration > counters.maxLatency {
+		counters.maxLatency = duration
+	}
+	if err != nil {
+		counters.failures++
+		counters.lastErrorAt = time.Now()
+		counters.lastError = err.Error()
+	} else {
+		counters.lastSuccessAt = time.Now()
+	}
+}
+
+func (c *collector) snapshot() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snap := Snapshot{
+		StartedAt:           c.startedAt,
+		UptimeSeconds:       int64(time.Since(c.startedAt).Seconds()),
+		CodesGenerated:      copyCounts(c.codes),
+		Attempts:            copyCounts(c.attempts),
+		RateLimitsHit:       copyCounts(c.rateLimits),
+		ActiveVerifications: c.active,
+		Providers:           make([]ProviderStats, 0, len(c.providers)),
+	}
+
+	for key, counters := range c.providers {
+		ps := ProviderStats{
+			Provider:     key.provider,
+			Operation:    key.operation,
+			Sends:        counters.sends,
+			Failures:     counters.failures,
+			MaxLatencyMs: float64(counters.maxLatency.Microseconds()) / 1000,
+			LastError:    counters.lastError,
+		}
+		if counters.sends > 0 {
+			ps.FailureRate = float64(counters.failures) / float64(counters.sends)
+			ps.AvgLatencyMs = float64(counters.totalLatency.Microseconds()) / 1000 / float64(counters.sends)
+		}
+		if !counters.lastSuccessAt.IsZero() {
+			t := counters.lastSuccessAt
+			ps.LastSuccessAt = &t
+		}
+		if !counters.lastErrorAt.IsZero() {
+			t := counters.lastErrorAt
+			ps.LastErrorAt = &t
+		}
+		snap.Providers = append(snap.Providers, ps)
+	}
+	sort.Slice(snap.Providers, func(i, j int) bool {
+		if snap.Providers[i].Provider != snap.Providers[j].Provider {
+			return snap.Providers[i].Provider < snap.Providers[j].Provider
+		}
+		return snap.Providers[i].Operation < snap.Providers[j].Operation
+	})
+
+	return snap
+}
+
+func copyCounts(counts map[string]int64) map[string]int64 {
+	out := make(map[string]int64, len(counts))
+	for k, v := range counts {
+		out[k] = v
+	}
+	return out
+}
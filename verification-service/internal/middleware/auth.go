@@ -1,14 +1,23 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// APIKeyAuth middleware validates API key for inter-service authentication
-func APIKeyAuth(validAPIKey string) gin.HandlerFunc {
+// CallerContextKey is the gin context key holding the name of the API key a request used
+const CallerContextKey = "api_caller"
+
+// DefaultCaller names the primary API key
+const DefaultCaller = "default"
+
+// APIKeyAuth middleware validates API key for inter-service authentication.
+// callerKeys are accepted alongside validAPIKey; the matching caller name is stored
+// under CallerContextKey so sends can be attributed per caller.
+func APIKeyAuth(validAPIKey string, callerKeys map[string]string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
 
@@ -33,7 +42,8 @@ func APIKeyAuth(validAPIKey string) gin.HandlerFunc {
 			return
 		}
 
-		if apiKey != validAPIKey {
+		caller := matchAPIKey(apiKey, validAPIKey, callerKeys)
+		if caller == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
 				"message": "Invalid API key",
@@ -46,6 +56,20 @@ func APIKeyAuth(validAPIKey string) gin.HandlerFunc {
 			return
 		}
 
+		c.Set(CallerContextKey, caller)
 		c.Next()
 	}
 }
+
+// matchAPIKey returns the caller name of a valid API key, or "" if the key is unknown
+func matchAPIKey(apiKey, validAPIKey string, callerKeys map[string]string) string {
+	if subtle.ConstantTimeCompare([]byte(apiKey), []byte(validAPIKey)) == 1 {
+		return DefaultCaller
+	}
+	for name, key := range callerKeys {
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(key)) == 1 {
+			return name
+		}
+	}
+	return ""
+}
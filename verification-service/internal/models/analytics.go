@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Analytics breakdown dimensions
+const (
+	AnalyticsGroupTemplate = "template"
+	AnalyticsGroupCaller   = "caller"
+	AnalyticsGroupPurpose  = "purpose"
+	AnalyticsGroupChannel  = "channel"
+)
+
+// Timeseries bucket sizes
+const (
+	AnalyticsIntervalHour = "hour"
+	AnalyticsIntervalDay  = "day"
+)
+
+// AnalyticsFilter narrows verification analytics to codes created in [From, To)
+type AnalyticsFilter struct {
+	From     time.Time  `json:"from"`
+	To       time.Time  `json:"to"`
+	TenantID *uuid.UUID `json:"tenant_id,omitempty"`
+	Purpose  string     `json:"purpose,omitempty"`
+	Channel  string     `json:"channel,omitempty"`
+	Template string     `json:"template,omitempty"`
+	Caller   string     `json:"caller,omitempty"`
+}
+
+// VerificationAggregate holds the raw counts of a group of verification codes
+type VerificationAggregate struct {
+	Key           string
+	Bucket        time.Time
+	Sends         int64
+	SendFailures  int64
+	Resends       int64
+	Verified      int64
+	Attempts      int64
+	MedianSeconds *float64
+	P90Seconds    *float64
+}
+
+// VerificationFunnelStats summarises sends and verifications of a group of codes
+type VerificationFunnelStats struct {
+	Sends                     int64    `json:"sends"`
+	SendFailures              int64    `json:"send_failures"`
+	Delivered                 int64    `json:"delivered"`
+	Resends                   int64    `json:"resends"`
+	Verified                  int64    `json:"verified"`
+	VerifyAttempts            int64    `json:"verify_attempts"`
+	SendFailureRate           float64  `json:"send_failure_rate"`
+	VerifySuccessRate         float64  `json:"verify_success_rate"` // verified / delivered
+	ResendRate                float64  `json:"resend_rate"`         // resends / sends
+	MedianTimeToVerifySeconds *float64 `json:"median_time_to_verify_seconds"`
+	P90TimeToVerifySeconds    *float64 `json:"p90_time_to_verify_seconds"`
+}
+
+// AnalyticsSummaryResponse compares a period with the period of equal length before it
+type AnalyticsSummaryResponse struct {
+	Filter   AnalyticsFilter         `json:"filter"`
+	Current  VerificationFunnelStats `json:"current"`
+	Previous VerificationFunnelStats `json:"previous"`
+	// VerifySuccessRateChange is the current minus the previous verify success rate
+	VerifySuccessRateChange float64 `json:"verify_success_rate_change"`
+}
+
+// AnalyticsTimeseriesPoint is the funnel of the codes created in one bucket
+type AnalyticsTimeseriesPoint struct {
+	Bucket time.Time `json:"bucket"`
+	VerificationFunnelStats
+}
+
+// AnalyticsTimeseriesResponse lists funnel stats per bucket, including empty buckets
+type AnalyticsTimeseriesResponse struct {
+	Filter   AnalyticsFilter            `json:"filter"`
+	Interval string                     `json:"interval"`
+	Points   []AnalyticsTimeseriesPoint `json:"points"`
+}
+
+// AnalyticsBreakdownEntry is the funnel of one template, caller, purpose or channel
+type AnalyticsBreakdownEntry struct {
+	Key string `json:"key"`
+	VerificationFunnelStats
+}
+
+// AnalyticsBreakdownResponse lists funnel stats per group, by send volume
+type AnalyticsBreakdownResponse struct {
+	Filter  AnalyticsFilter           `json:"filter"`
+	GroupBy string                    `json:"group_by"`
+	Entries []AnalyticsBreakdownEntry `json:"entries"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Disposable domain sources
+const (
+	DisposableSourceFeed   = "feed"   // Imported from the upstream blocklist by the refresh worker
+	DisposableSourceManual = "manual" // Added by an operator via /internal/disposable-domains
+)
+
+// DisposableDomain is a blocklisted temporary/disposable email domain
+type DisposableDomain struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Domain    string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"domain"`
+	Source    string    `gorm:"type:varchar(20);not null;index" json:"source"` // feed, manual
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (DisposableDomain) TableName() string {
+	return "disposable_domains"
+}
+
+// BeforeCreate hook to generate UUID
+func (d *DisposableDomain) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
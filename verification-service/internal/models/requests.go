@@ -10,6 +10,8 @@ type SendVerificationRequest struct {
 	SessionID *uuid.UUID             `json:"session_id,omitempty"`
 	TenantID  *uuid.UUID             `json:"tenant_id,omitempty"`
 	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Strict    bool                   `json:"strict,omitempty"` // reject disposable email domains
+	Caller    string                 `json:"-"`                // API key caller, set from the authenticated request
 }
 
 // VerifyCodeRequest represents a request to verify a code
@@ -25,6 +27,7 @@ type ResendCodeRequest struct {
 	Channel   string     `json:"channel" binding:"required,oneof=email sms"`
 	Purpose   string     `json:"purpose" binding:"required"`
 	SessionID *uuid.UUID `json:"session_id,omitempty"`
+	Caller    string     `json:"-"` // API key caller, set from the authenticated request
 }
 
 // CheckStatusRequest represents a request to check verification status
@@ -46,4 +49,10 @@ type SendEmailRequest struct {
 	DashboardURL     string                 `json:"dashboard_url,omitempty"`
 	VerificationLink string                 `json:"verification_link,omitempty"`
 	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	Strict           bool                   `json:"strict,omitempty"` // reject disposable email domains
+}
+
+// DisposableDomainsRequest adds domains to the manual disposable blocklist
+type DisposableDomainsRequest struct {
+	Domains []string `json:"domains" binding:"required,min=1,max=1000"`
 }
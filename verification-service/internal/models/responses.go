@@ -53,3 +53,31 @@ type VerificationStatusResponse struct {
 	CanResend    bool       `json:"can_resend"`
 	AttemptsLeft int        `json:"attempts_left"`
 }
+
+// Email reputation classifications, from most to least restrictive
+const (
+	EmailClassInvalid    = "invalid"
+	EmailClassDisposable = "disposable"
+	EmailClassRole       = "role"
+	EmailClassFree       = "free_provider"
+	EmailClassBusiness   = "business"
+)
+
+// EmailReputationResponse classifies an email address
+type EmailReputationResponse struct {
+	Email          string `json:"email"`
+	Domain         string `json:"domain"`
+	ValidSyntax    bool   `json:"valid_syntax"`
+	Disposable     bool   `json:"disposable"`
+	RoleAccount    bool   `json:"role_account"`
+	FreeProvider   bool   `json:"free_provider"`
+	Classification string `json:"classification"`
+}
+
+// DisposableBlocklistStatus summarises the disposable domain blocklist
+type DisposableBlocklistStatus struct {
+	Domains         int        `json:"domains"`
+	FeedDomains     int64      `json:"feed_domains"`
+	ManualDomains   int64      `json:"manual_domains"`
+	LastRefreshedAt *time.Time `json:"last_refreshed_at,omitempty"`
+}
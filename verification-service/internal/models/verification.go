@@ -23,7 +23,11 @@ type VerificationCode struct {
 	MaxAttempts  int            `gorm:"default:3" json:"max_attempts"`
 	IsUsed       bool           `gorm:"default:false;index" json:"is_used"`
 	Metadata     []byte         `gorm:"type:jsonb" json:"metadata,omitempty"`
-	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	Template     string         `gorm:"type:varchar(50);index" json:"template,omitempty"` // template the code was sent with
+	Caller       string         `gorm:"type:varchar(100);index" json:"caller,omitempty"`  // API key caller that requested the code
+	IsResend     bool           `gorm:"default:false" json:"is_resend"`
+	SendFailed   bool           `gorm:"default:false" json:"send_failed"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime;index" json:"created_at"`
 	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
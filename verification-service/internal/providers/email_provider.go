@@ -38,7 +38,7 @@ func EmailProviderFactory(providerName, apiKey, fromEmail, fromName string) (Ema
 	}
 
 	baseURL := getEnvOrDefault("NOTIFICATION_SERVICE_URL", "http://notification-service.devtest.svc.cluster.local:8090")
-	return NewNotificationServiceProvider(baseURL, apiKey, fromEmail, fromName), nil
+	return NewInstrumentedEmailProvider(NewNotificationServiceProvider(baseURL, apiKey, fromEmail, fromName)), nil
 }
 
 // getEnvOrDefault returns the environment variable value or a default
@@ -49,6 +49,22 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// VerificationTemplate names the template a verification code is sent with, matching the
+// purposes handled by FormatVerificationEmailWithBranding; used to compare template performance
+func VerificationTemplate(channel, purpose string) string {
+	if channel != "email" {
+		return channel
+	}
+	switch purpose {
+	case "customer_email_verification":
+		return "customer_otp"
+	case "email_verification", "staff_mfa_verification", "password_reset", "welcome", "account_created":
+		return purpose
+	default:
+		return "generic"
+	}
+}
+
 // FormatVerificationEmail formats the verification email content
 func FormatVerificationEmail(code, purpose string) (string, string) {
 	return FormatVerificationEmailWithBranding(code, purpose, "", 10)
@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"time"
+
+	"verification-service/internal/metrics"
+)
+
+// Provider operations recorded in send metrics
+const (
+	OperationVerificationEmail = "verification_email"
+	OperationEmail             = "email"
+)
+
+// instrumentedEmailProvider records latency and failures of every send call
+type instrumentedEmailProvider struct {
+	next EmailProvider
+}
+
+// NewInstrumentedEmailProvider wraps an email provider with send metrics
+func NewInstrumentedEmailProvider(next EmailProvider) EmailProvider {
+	return &instrumentedEmailProvider{next: next}
+}
+
+// SendVerificationEmail sends a verification email and records the call
+func (p *instrumentedEmailProvider) SendVerificationEmail(recipient, code, purpose string) error {
+	start := time.Now()
+	err := p.next.SendVerificationEmail(recipient, code, purpose)
+	metrics.ObserveProviderSend(p.next.GetName(), OperationVerificationEmail, time.Since(start), err)
+	return err
+}
+
+// SendEmail sends a custom email and records the call
+func (p *instrumentedEmailProvider) SendEmail(recipient, subject, htmlBody string) error {
+	start := time.Now()
+	err := p.next.SendEmail(recipient, subject, htmlBody)
+	metrics.ObserveProviderSend(p.next.GetName(), OperationEmail, time.Since(start), err)
+	return err
+}
+
+// GetName returns the name of the wrapped provider
+func (p *instrumentedEmailProvider) GetName() string {
+	return p.next.GetName()
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"verification-service/internal/models"
+)
+
+// aggregateColumns computes the funnel counts of a group of verification codes
+const aggregateColumns = `COUNT(*) AS sends,
+	COUNT(*) FILTER (WHERE send_failed) AS send_failures,
+	COUNT(*) FILTER (WHERE is_resend) AS resends,
+	COUNT(*) FILTER (WHERE verified_at IS NOT NULL) AS verified,
+	COALESCE(SUM(attempt_count), 0) AS attempts,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM verified_at - created_at)) FILTER (WHERE verified_at IS NOT NULL) AS median_seconds,
+	percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM verified_at - created_at)) FILTER (WHERE verified_at IS NOT NULL) AS p90_seconds`
+
+// groupColumns maps breakdown dimensions to their columns; codes sent before a column
+// was recorded are reported as "unknown"
+var groupColumns = map[string]string{
+	models.AnalyticsGroupTemplate: "COALESCE(NULLIF(template, ''), 'unknown')",
+	models.AnalyticsGroupCaller:   "COALESCE(NULLIF(caller, ''), 'unknown')",
+	models.AnalyticsGroupPurpose:  "purpose",
+	models.AnalyticsGroupChannel:  "channel",
+}
+
+// AnalyticsRepository aggregates verification codes for the analytics endpoints
+type AnalyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository creates a new analytics repository
+func NewAnalyticsRepository(db *gorm.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// Aggregate returns the funnel counts of all codes matching the filter
+func (r *AnalyticsRepository) Aggregate(ctx context.Context, filter models.AnalyticsFilter) (*models.VerificationAggregate, error) {
+	var aggregate models.VerificationAggregate
+	err := r.codes(ctx, filter).
+		Select(aggregateColumns).
+		Scan(&aggregate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &aggregate, nil
+}
+
+// AggregateBy returns the funnel counts per template, caller, purpose or channel, by send volume
+func (r *AnalyticsRepository) AggregateBy(ctx context.Context, filter models.AnalyticsFilter, groupBy string) ([]models.VerificationAggregate, error) {
+	column, ok := groupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported analytics grouping: %s", groupBy)
+	}
+
+	var aggregates []models.VerificationAggregate
+	err := r.codes(ctx, filter).
+		Select(column + " AS key, " + aggregateColumns).
+		Group("key").
+		Order("sends DESC, key").
+		Scan(&aggregates).Error
+	return aggregates, err
+}
+
+// AggregateByInterval returns the funnel counts per UTC hour or day bucket; empty buckets are omitted
+func (r *AnalyticsRepository) AggregateByInterval(ctx context.Context, filter models.AnalyticsFilter, interval string) ([]models.VerificationAggregate, error) {
+	if interval != models.AnalyticsIntervalHour && interval != models.AnalyticsIntervalDay {
+		return nil, fmt.Errorf("unsupported analytics interval: %s", interval)
+	}
+
+	var aggregates []models.VerificationAggregate
+	err := r.codes(ctx, filter).
+		Select("date_trunc('" + interval + "', created_at AT TIME ZONE 'UTC') AS bucket, " + aggregateColumns).
+		Group("bucket").
+		Order("bucket").
+		Scan(&aggregates).Error
+	return aggregates, err
+}
+
+// codes selects the verification codes matching the filter, including soft-deleted ones
+// so the cleanup of expired codes does not skew the funnel
+func (r *AnalyticsRepository) codes(ctx context.Context, filter models.AnalyticsFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.VerificationCode{}).
+		Where("created_at >= ? AND created_at < ?", filter.From, filter.To)
+
+	if filter.TenantID != nil {
+		query = query.Where("tenant_id = ?", *filter.TenantID)
+	}
+	if filter.Purpose != "" {
+		query = query.Where("purpose = ?", filter.Purpose)
+	}
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+	if filter.Template != "" {
+		query = query.Where("template = ?", filter.Template)
+	}
+	if filter.Caller != "" {
+		query = query.Where("caller = ?", filter.Caller)
+	}
+	return query
+}
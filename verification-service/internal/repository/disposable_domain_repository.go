@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"verification-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DisposableDomainRepository handles database operations for the disposable domain blocklist
+type DisposableDomainRepository struct {
+	db *gorm.DB
+}
+
+// NewDisposableDomainRepository creates a new disposable domain repository
+func NewDisposableDomainRepository(db *gorm.DB) *DisposableDomainRepository {
+	return &DisposableDomainRepository{db: db}
+}
+
+// ListDomains returns every blocklisted domain
+func (r *DisposableDomainRepository) ListDomains(ctx context.Context) ([]string, error) {
+	var domains []string
+	err := r.db.WithContext(ctx).
+		Model(&models.DisposableDomain{}).
+		Pluck("domain", &domains).Error
+	return domains, err
+}
+
+// CountBySource returns how many domains came from a source
+func (r *DisposableDomainRepository) CountBySource(ctx context.Context, source string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.DisposableDomain{}).
+		Where("source = ?", source).
+		Count(&count).Error
+	return count, err
+}
+
+// Upsert adds domains for a source; domains already present keep their original source
+// so manual entries are never downgraded by a feed import
+func (r *DisposableDomainRepository) Upsert(ctx context.Context, source string, domains []string) error {
+	if len(domains) == 0 {
+		return nil
+	}
+	rows := make([]models.DisposableDomain, 0, len(domains))
+	for _, domain := range domains {
+		rows = append(rows, models.DisposableDomain{Domain: domain, Source: source})
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "domain"}}, DoNothing: true}).
+		CreateInBatches(rows, 500).Error
+}
+
+// ReplaceSource makes the domains of a source exactly the given list. Rows of the source that
+// are still listed are touched with seenAt and the rest are swept, which avoids a huge NOT IN list.
+func (r *DisposableDomainRepository) ReplaceSource(ctx context.Context, source string, domains []string, seenAt time.Time) error {
+	rows := make([]models.DisposableDomain, 0, len(domains))
+	for _, domain := range domains {
+		rows = append(rows, models.DisposableDomain{Domain: domain, Source: source, CreatedAt: seenAt, UpdatedAt: seenAt})
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(rows) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "domain"}},
+				DoUpdates: clause.AssignmentColumns([]string{"updated_at"}),
+				Where: clause.Where{Exprs: []clause.Expression{
+					clause.Expr{SQL: "disposable_domains.source = excluded.source"},
+				}},
+			}).CreateInBatches(rows, 500).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("source = ? AND updated_at < ?", source, seenAt).
+			Delete(&models.DisposableDomain{}).Error
+	})
+}
+
+// Delete removes a domain from the blocklist, returning false when it was not listed
+func (r *DisposableDomainRepository) Delete(ctx context.Context, domain string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("domain = ?", domain).
+		Delete(&models.DisposableDomain{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -80,6 +80,13 @@ func (r *VerificationRepository) MarkAsUsed(ctx context.Context, id uuid.UUID) e
 		}).Error
 }
 
+// MarkSendFailed records that the provider failed to deliver a verification code
+func (r *VerificationRepository) MarkSendFailed(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.VerificationCode{}).
+		Where("id = ?", id).
+		UpdateColumn("send_failed", true).Error
+}
+
 // IncrementAttempts increments the attempt count
 func (r *VerificationRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Model(&models.VerificationCode{}).
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"verification-service/internal/models"
+	"verification-service/internal/repository"
+)
+
+// Analytics date range limits
+const (
+	defaultAnalyticsRange = 7 * 24 * time.Hour
+	maxAnalyticsRange     = 90 * 24 * time.Hour
+	maxHourlyRange        = 7 * 24 * time.Hour
+)
+
+// ErrInvalidAnalyticsQuery is returned for malformed analytics filters
+var ErrInvalidAnalyticsQuery = errors.New("invalid analytics query")
+
+// AnalyticsService reports send volume, verify success, time-to-verify and resend rates
+// so deliverability problems show up before they hurt onboarding conversion
+type AnalyticsService struct {
+	analyticsRepo *repository.AnalyticsRepository
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(analyticsRepo *repository.AnalyticsRepository) *AnalyticsService {
+	return &AnalyticsService{analyticsRepo: analyticsRepo}
+}
+
+// NormalizeFilter applies the default range (the last 7 days) and validates the range
+func (s *AnalyticsService) NormalizeFilter(filter *models.AnalyticsFilter) error {
+	if filter.To.IsZero() {
+		filter.To = time.Now().UTC()
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.Add(-defaultAnalyticsRange)
+	}
+	if !filter.From.Before(filter.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidAnalyticsQuery)
+	}
+	if filter.To.Sub(filter.From) > maxAnalyticsRange {
+		return fmt.Errorf("%w: date range must not exceed 90 days", ErrInvalidAnalyticsQuery)
+	}
+	return nil
+}
+
+// Summary returns the funnel of the filtered period and of the period of equal length before it
+func (s *AnalyticsService) Summary(ctx context.Context, filter models.AnalyticsFilter) (*models.AnalyticsSummaryResponse, error) {
+	if err := s.NormalizeFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	current, err := s.analyticsRepo.Aggregate(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate verification codes: %w", err)
+	}
+
+	previousFilter := filter
+	previousFilter.From = filter.From.Add(-filter.To.Sub(filter.From))
+	previousFilter.To = filter.From
+	previous, err := s.analyticsRepo.Aggregate(ctx, previousFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate verification codes: %w", err)
+	}
+
+	response := &models.AnalyticsSummaryResponse{
+		Filter:   filter,
+		Current:  funnelStats(current),
+		Previous: funnelStats(previous),
+	}
+	response.VerifySuccessRateChange = response.Current.VerifySuccessRate - response.Previous.VerifySuccessRate
+	return response, nil
+}
+
+// Timeseries returns the funnel per hour or day bucket, filling empty buckets with zeros
+func (s *AnalyticsService) Timeseries(ctx context.Context, filter models.AnalyticsFilter, interval string) (*models.AnalyticsTimeseriesResponse, error) {
+	if err := s.NormalizeFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	var step time.Duration
+	switch interval {
+	case "", models.AnalyticsIntervalDay:
+		interval, step = models.AnalyticsIntervalDay, 24*time.Hour
+	case models.AnalyticsIntervalHour:
+		if filter.To.Sub(filter.From) > maxHourlyRange {
+			return nil, fmt.Errorf("%w: hourly buckets are limited to 7 days", ErrInvalidAnalyticsQuery)
+		}
+		step = time.Hour
+	default:
+		return nil, fmt.Errorf("%w: interval must be hour or day", ErrInvalidAnalyticsQuery)
+	}
+
+	aggregates, err := s.analyticsRepo.AggregateByInterval(ctx, filter, interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate verification codes: %w", err)
+	}
+	byBucket := make(map[time.Time]*models.VerificationAggregate, len(aggregates))
+	for i := range aggregates {
+		byBucket[aggregates[i].Bucket.UTC()] = &aggregates[i]
+	}
+
+	points := []models.AnalyticsTimeseriesPoint{}
+	for bucket := filter.From.UTC().Truncate(step); bucket.Before(filter.To); bucket = bucket.Add(step) {
+		aggregate, ok := byBucket[bucket]
+		if !ok {
+			aggregate = &models.VerificationAggregate{}
+		}
+		points = append(points, models.AnalyticsTimeseriesPoint{
+			Bucket:                  bucket,
+			VerificationFunnelStats: funnelStats(aggregate),
+		})
+	}
+
+	return &models.AnalyticsTimeseriesResponse{
+		Filter:   filter,
+		Interval: interval,
+		Points:   points,
+	}, nil
+}
+
+// Breakdown returns the funnel per template, caller, purpose or channel
+func (s *AnalyticsService) Breakdown(ctx context.Context, filter models.AnalyticsFilter, groupBy string) (*models.AnalyticsBreakdownResponse, error) {
+	if err := s.NormalizeFilter(&filter); err != nil {
+		return nil, err
+	}
+	switch groupBy {
+	case models.AnalyticsGroupTemplate, models.AnalyticsGroupCaller, models.AnalyticsGroupPurpose, models.AnalyticsGroupChannel:
+	default:
+		return nil, fmt.Errorf("%w: group_by must be template, caller, purpose or channel", ErrInvalidAnalyticsQuery)
+	}
+
+	aggregates, err := s.analyticsRepo.AggregateBy(ctx, filter, groupBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate verification codes: %w", err)
+	}
+
+	entries := make([]models.AnalyticsBreakdownEntry, 0, len(aggregates))
+	for i := range aggregates {
+		entries = append(entries, models.AnalyticsBreakdownEntry{
+			Key:                     aggregates[i].Key,
+			VerificationFunnelStats: funnelStats(&aggregates[i]),
+		})
+	}
+
+	return &models.AnalyticsBreakdownResponse{
+		Filter:  filter,
+		GroupBy: groupBy,
+		Entries: entries,
+	}, nil
+}
+
+// funnelStats derives the rates of an aggregate; rates of empty groups are zero
+func funnelStats(aggregate *models.VerificationAggregate) models.VerificationFunnelStats {
+	delivered := aggregate.Sends - aggregate.SendFailures
+	return models.VerificationFunnelStats{
+		Sends:                     aggregate.Sends,
+		SendFailures:              aggregate.SendFailures,
+		Delivered:                 delivered,
+		Resends:                   aggregate.Resends,
+		Verified:                  aggregate.Verified,
+		VerifyAttempts:            aggregate.Attempts,
+		SendFailureRate:           ratio(aggregate.SendFailures, aggregate.Sends),
+		VerifySuccessRate:         ratio(aggregate.Verified, delivered),
+		ResendRate:                ratio(aggregate.Resends, aggregate.Sends),
+		MedianTimeToVerifySeconds: aggregate.MedianSeconds,
+		P90TimeToVerifySeconds:    aggregate.P90Seconds,
+	}
+}
+
+// ratio returns part/total, or 0 when total is 0
+func ratio(part, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
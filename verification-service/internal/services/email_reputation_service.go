@@ -0,0 +1,339 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"verification-service/internal/config"
+	"verification-service/internal/metrics"
+	"verification-service/internal/models"
+	"verification-service/internal/repository"
+)
+
+const (
+	// maxBlocklistBytes caps the size of a downloaded blocklist feed
+	maxBlocklistBytes = 10 << 20
+	// minFeedDomains guards against replacing the blocklist with an empty or truncated feed
+	minFeedDomains = 100
+)
+
+// ErrDisposableEmail is returned in strict mode when the recipient uses a disposable domain
+var ErrDisposableEmail = errors.New("disposable email addresses are not accepted")
+
+// builtinDisposableDomains are always blocked, even before the first feed import
+var builtinDisposableDomains = []string{
+	"10minutemail.com", "20minutemail.com", "33mail.com", "dispostable.com", "emailondeck.com",
+	"fakeinbox.com", "getairmail.com", "getnada.com", "guerrillamail.com", "guerrillamail.net",
+	"guerrillamailblock.com", "mailcatch.com", "maildrop.cc", "mailinator.com", "mailnesia.com",
+	"mintemail.com", "mohmal.com", "sharklasers.com", "spamgourmet.com", "temp-mail.org",
+	"tempail.com", "tempmail.com", "tempmailo.com", "throwawaymail.com", "trashmail.com",
+	"yopmail.com",
+}
+
+// roleLocalParts are mailbox names that usually reach a team rather than a person
+var roleLocalParts = map[string]bool{
+	"abuse": true, "accounts": true, "admin": true, "administrator": true, "billing": true,
+	"careers": true, "contact": true, "support": true, "help": true, "hostmaster": true,
+	"hr": true, "info": true, "jobs": true, "marketing": true, "no-reply": true,
+	"noc": true, "noreply": true, "office": true, "postmaster": true, "root": true,
+	"sales": true, "security": true, "team": true, "webmaster": true,
+}
+
+// freeProviderDomains are consumer mailbox providers
+var freeProviderDomains = map[string]bool{
+	"126.com": true, "163.com": true, "aol.com": true, "fastmail.com": true, "gmail.com": true,
+	"gmx.com": true, "gmx.de": true, "googlemail.com": true, "hey.com": true, "hotmail.com": true,
+	"icloud.com": true, "live.com": true, "mac.com": true, "mail.com": true, "me.com": true,
+	"msn.com": true, "outlook.com": true, "proton.me": true, "protonmail.com": true, "qq.com": true,
+	"rediffmail.com": true, "tutanota.com": true, "web.de": true, "yahoo.co.uk": true, "yahoo.com": true,
+	"yandex.com": true, "yandex.ru": true, "zoho.com": true,
+}
+
+// EmailReputationService classifies email addresses and maintains the disposable domain blocklist.
+// The blocklist is held in memory and reloaded from the database after every change.
+type EmailReputationService struct {
+	config     config.ReputationConfig
+	repo       *repository.DisposableDomainRepository
+	httpClient *http.Client
+
+	mu              sync.RWMutex
+	disposable      map[string]bool
+	lastRefreshedAt *time.Time
+}
+
+// NewEmailReputationService creates a new email reputation service seeded with the builtin blocklist
+func NewEmailReputationService(cfg config.ReputationConfig, repo *repository.DisposableDomainRepository) *EmailReputationService {
+	s := &EmailReputationService{
+		config:     cfg,
+		repo:       repo,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	s.setDomains(nil)
+	return s
+}
+
+// Classify returns the reputation of an email address
+func (s *EmailReputationService) Classify(email string) *models.EmailReputationResponse {
+	email = strings.TrimSpace(email)
+	result := &models.EmailReputationResponse{
+		Email:          email,
+		Classification: models.EmailClassInvalid,
+	}
+
+	localPart, domain, ok := splitEmail(email)
+	if !ok {
+		return result
+	}
+	result.Domain = domain
+	result.ValidSyntax = true
+	result.Disposable = s.isDisposableDomain(domain)
+	result.RoleAccount = roleLocalParts[strings.SplitN(localPart, "+", 2)[0]]
+	result.FreeProvider = freeProviderDomains[domain]
+
+	switch {
+	case result.Disposable:
+		result.Classification = models.EmailClassDisposable
+	case result.RoleAccount:
+		result.Classification = models.EmailClassRole
+	case result.FreeProvider:
+		result.Classification = models.EmailClassFree
+	default:
+		result.Classification = models.EmailClassBusiness
+	}
+	return result
+}
+
+// IsDisposable reports whether the email address uses a blocklisted domain
+func (s *EmailReputationService) IsDisposable(email string) bool {
+	_, domain, ok := splitEmail(email)
+	return ok && s.isDisposableDomain(domain)
+}
+
+// Status summarises the blocklist
+func (s *EmailReputationService) Status(ctx context.Context) (*models.DisposableBlocklistStatus, error) {
+	feed, err := s.repo.CountBySource(ctx, models.DisposableSourceFeed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count feed domains: %w", err)
+	}
+	manual, err := s.repo.CountBySource(ctx, models.DisposableSourceManual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count manual domains: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &models.DisposableBlocklistStatus{
+		Domains:         len(s.disposable),
+		FeedDomains:     feed,
+		ManualDomains:   manual,
+		LastRefreshedAt: s.lastRefreshedAt,
+	}, nil
+}
+
+// Reload rebuilds the in-memory blocklist from the database
+func (s *EmailReputationService) Reload(ctx context.Context) error {
+	domains, err := s.repo.ListDomains(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load disposable domains: %w", err)
+	}
+	s.setDomains(domains)
+	return nil
+}
+
+// Refresh re-imports the upstream feed and reloads the blocklist
+func (s *EmailReputationService) Refresh(ctx context.Context) (int, error) {
+	if s.config.BlocklistURL == "" {
+		return 0, fmt.Errorf("no disposable blocklist URL configured")
+	}
+
+	domains, err := s.fetchFeed(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(domains) < minFeedDomains {
+		return 0, fmt.Errorf("disposable blocklist feed returned only %d domains, keeping current list", len(domains))
+	}
+
+	if err := s.repo.ReplaceSource(ctx, models.DisposableSourceFeed, domains, time.Now()); err != nil {
+		return 0, fmt.Errorf("failed to store disposable domains: %w", err)
+	}
+	if err := s.Reload(ctx); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.lastRefreshedAt = &now
+	s.mu.Unlock()
+
+	log.Printf("[EmailReputation] Imported %d disposable domains from feed", len(domains))
+	return len(domains), nil
+}
+
+// AddDomains blocklists domains manually
+func (s *EmailReputationService) AddDomains(ctx context.Context, domains []string) ([]string, error) {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = normalizeDomain(domain)
+		if !isPlausibleDomain(domain) {
+			return nil, fmt.Errorf("invalid domain: %q", domain)
+		}
+		normalized = append(normalized, domain)
+	}
+
+	if err := s.repo.Upsert(ctx, models.DisposableSourceManual, normalized); err != nil {
+		return nil, fmt.Errorf("failed to store disposable domains: %w", err)
+	}
+	return normalized, s.Reload(ctx)
+}
+
+// RemoveDomain takes a domain off the blocklist; builtin domains cannot be removed
+func (s *EmailReputationService) RemoveDomain(ctx context.Context, domain string) (bool, error) {
+	removed, err := s.repo.Delete(ctx, normalizeDomain(domain))
+	if err != nil {
+		return false, fmt.Errorf("failed to remove disposable domain: %w", err)
+	}
+	return removed, s.Reload(ctx)
+}
+
+// RunRefreshWorker loads the blocklist and re-imports the feed on the configured interval until stop is closed
+func (s *EmailReputationService) RunRefreshWorker(stop <-chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	if err := s.Reload(ctx); err != nil {
+		log.Printf("[EmailReputation] Warning: %v (using builtin blocklist)", err)
+	}
+	cancel()
+
+	if s.config.BlocklistURL == "" || s.config.RefreshIntervalHours <= 0 {
+		log.Println("[EmailReputation] Disposable blocklist feed refresh disabled")
+		return
+	}
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		if _, err := s.Refresh(ctx); err != nil {
+			log.Printf("[EmailReputation] Failed to refresh disposable blocklist: %v", err)
+		}
+	}
+	refresh()
+
+	ticker := time.NewTicker(time.Duration(s.config.RefreshIntervalHours) * time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+func (s *EmailReputationService) fetchFeed(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.config.BlocklistURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid disposable blocklist URL: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download disposable blocklist: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("disposable blocklist download returned status %d", resp.StatusCode)
+	}
+
+	seen := make(map[string]bool)
+	var domains []string
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxBlocklistBytes))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domain := normalizeDomain(line)
+		if isPlausibleDomain(domain) && !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read disposable blocklist: %w", err)
+	}
+	return domains, nil
+}
+
+func (s *EmailReputationService) setDomains(domains []string) {
+	set := make(map[string]bool, len(builtinDisposableDomains)+len(domains))
+	for _, domain := range builtinDisposableDomains {
+		set[domain] = true
+	}
+	for _, domain := range domains {
+		set[domain] = true
+	}
+
+	s.mu.Lock()
+	s.disposable = set
+	s.mu.Unlock()
+	metrics.SetDisposableDomains(len(set))
+}
+
+// isDisposableDomain matches the domain and each parent domain, so subdomains of a
+// blocklisted domain are blocked too
+func (s *EmailReputationService) isDisposableDomain(domain string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for {
+		if s.disposable[domain] {
+			return true
+		}
+		dot := strings.IndexByte(domain, '.')
+		if dot < 0 || !strings.Contains(domain[dot+1:], ".") {
+			return false
+		}
+		domain = domain[dot+1:]
+	}
+}
+
+// splitEmail validates a bare email address and returns its lowercased local part and domain
+func splitEmail(email string) (string, string, bool) {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return "", "", false
+	}
+	at := strings.LastIndexByte(addr.Address, '@')
+	if at <= 0 {
+		return "", "", false
+	}
+	domain := normalizeDomain(addr.Address[at+1:])
+	if !isPlausibleDomain(domain) {
+		return "", "", false
+	}
+	return strings.ToLower(addr.Address[:at]), domain, true
+}
+
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+func isPlausibleDomain(domain string) bool {
+	if len(domain) < 3 || len(domain) > 253 || !strings.Contains(domain, ".") {
+		return false
+	}
+	for _, r := range domain {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return !strings.HasPrefix(domain, ".") && !strings.Contains(domain, "..")
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"verification-service/internal/config"
 	"verification-service/internal/events"
+	"verification-service/internal/metrics"
 	"verification-service/internal/models"
 	"verification-service/internal/providers"
 	"verification-service/internal/repository"
@@ -25,6 +26,7 @@ type VerificationService struct {
 	emailProvider    providers.EmailProvider
 	encryptor        *crypto.Encryptor
 	otpGenerator     *otp.Generator
+	emailReputation  *EmailReputationService
 }
 
 // NewVerificationService creates a new verification service
@@ -51,8 +53,23 @@ func NewVerificationService(
 	}, nil
 }
 
+// SetEmailReputationService enables strict-mode rejection of disposable email recipients
+func (s *VerificationService) SetEmailReputationService(emailReputation *EmailReputationService) {
+	s.emailReputation = emailReputation
+}
+
 // SendVerificationCode sends a verification code to the recipient
 func (s *VerificationService) SendVerificationCode(ctx context.Context, req *models.SendVerificationRequest) (*models.SendVerificationResponse, error) {
+	return s.sendVerificationCode(ctx, req, false)
+}
+
+// sendVerificationCode sends a verification code, recording whether it replaces an earlier code for analytics
+func (s *VerificationService) sendVerificationCode(ctx context.Context, req *models.SendVerificationRequest, resend bool) (*models.SendVerificationResponse, error) {
+	if req.Strict && req.Channel == "email" && s.isDisposable(req.Recipient) {
+		metrics.RecordDisposableRejection(metrics.OperationVerifySend)
+		return nil, ErrDisposableEmail
+	}
+
 	// Check rate limit for sending codes
 	exceeded, _, err := s.rateLimitRepo.CheckLimit(
 		ctx,
@@ -65,6 +82,7 @@ func (s *VerificationService) SendVerificationCode(ctx context.Context, req *mod
 		return nil, fmt.Errorf("failed to check rate limit: %w", err)
 	}
 	if exceeded {
+		metrics.RecordRateLimitHit(metrics.RateLimitSend)
 		return nil, fmt.Errorf("rate limit exceeded: too many verification codes sent")
 	}
 
@@ -115,6 +133,9 @@ func (s *VerificationService) SendVerificationCode(ctx context.Context, req *mod
 		TenantID:    req.TenantID,
 		ExpiresAt:   expiresAt,
 		MaxAttempts: s.config.RateLimit.MaxAttempts,
+		Template:    providers.VerificationTemplate(req.Channel, req.Purpose),
+		Caller:      req.Caller,
+		IsResend:    resend,
 	}
 
 	// Save to database
@@ -124,8 +145,12 @@ func (s *VerificationService) SendVerificationCode(ctx context.Context, req *mod
 
 	// Send email/SMS based on channel
 	if err := s.sendCode(req.Channel, req.Recipient, code, req.Purpose); err != nil {
+		if markErr := s.verificationRepo.MarkSendFailed(ctx, verificationCode.ID); markErr != nil {
+			log.Printf("[VerificationService] Warning: Failed to record send failure: %v", markErr)
+		}
 		return nil, fmt.Errorf("failed to send verification code: %w", err)
 	}
+	metrics.RecordCodeGenerated(req.Channel)
 
 	// Increment rate limit counter
 	if err := s.rateLimitRepo.Increment(ctx, req.Recipient, "send"); err != nil {
@@ -155,6 +180,7 @@ func (s *VerificationService) VerifyCode(ctx context.Context, req *models.Verify
 	verificationCode, err := s.verificationRepo.GetByCodeHash(ctx, codeHash)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
+			metrics.RecordAttempt("", metrics.ResultInvalid)
 			return &models.VerifyCodeResponse{
 				Success:  false,
 				Verified: false,
@@ -166,6 +192,7 @@ func (s *VerificationService) VerifyCode(ctx context.Context, req *models.Verify
 
 	// Check if recipient matches
 	if verificationCode.Recipient != req.Recipient {
+		metrics.RecordAttempt(verificationCode.Channel, metrics.ResultInvalid)
 		return &models.VerifyCodeResponse{
 			Success:  false,
 			Verified: false,
@@ -175,6 +202,7 @@ func (s *VerificationService) VerifyCode(ctx context.Context, req *models.Verify
 
 	// Check if purpose matches
 	if verificationCode.Purpose != req.Purpose {
+		metrics.RecordAttempt(verificationCode.Channel, metrics.ResultInvalid)
 		return &models.VerifyCodeResponse{
 			Success:  false,
 			Verified: false,
@@ -195,6 +223,7 @@ func (s *VerificationService) VerifyCode(ctx context.Context, req *models.Verify
 
 	// Check if code has expired
 	if verificationCode.IsExpired() {
+		metrics.RecordAttempt(verificationCode.Channel, metrics.ResultExpired)
 		return &models.VerifyCodeResponse{
 			Success:  false,
 			Verified: false,
@@ -204,6 +233,7 @@ func (s *VerificationService) VerifyCode(ctx context.Context, req *models.Verify
 
 	// Check if code has been used
 	if verificationCode.IsUsed {
+		metrics.RecordAttempt(verificationCode.Channel, metrics.ResultUsed)
 		return &models.VerifyCodeResponse{
 			Success:  false,
 			Verified: false,
@@ -213,6 +243,8 @@ func (s *VerificationService) VerifyCode(ctx context.Context, req *models.Verify
 
 	// Check if max attempts reached
 	if verificationCode.AttemptCount > verificationCode.MaxAttempts {
+		metrics.RecordAttempt(verificationCode.Channel, metrics.ResultMaxAttempts)
+		metrics.RecordRateLimitHit(metrics.RateLimitVerify)
 		return &models.VerifyCodeResponse{
 			Success:  false,
 			Verified: false,
@@ -227,6 +259,7 @@ func (s *VerificationService) VerifyCode(ctx context.Context, req *models.Verify
 	}
 
 	if decryptedCode != normalizedCode {
+		metrics.RecordAttempt(verificationCode.Channel, metrics.ResultInvalid)
 		return &models.VerifyCodeResponse{
 			Success:  false,
 			Verified: false,
@@ -240,6 +273,7 @@ func (s *VerificationService) VerifyCode(ctx context.Context, req *models.Verify
 	}
 
 	// Log successful attempt
+	metrics.RecordAttempt(verificationCode.Channel, metrics.ResultSuccess)
 	_ = s.verificationRepo.LogAttempt(ctx, &models.VerificationAttempt{
 		VerificationCodeID: verificationCode.ID,
 		Success:            true,
@@ -291,6 +325,7 @@ func (s *VerificationService) ResendCode(ctx context.Context, req *models.Resend
 				Channel:   req.Channel,
 				Purpose:   req.Purpose,
 				SessionID: req.SessionID,
+				Caller:    req.Caller,
 			})
 		}
 		return nil, fmt.Errorf("failed to get latest code: %w", err)
@@ -302,12 +337,13 @@ func (s *VerificationService) ResendCode(ctx context.Context, req *models.Resend
 	}
 
 	// Send a new code
-	return s.SendVerificationCode(ctx, &models.SendVerificationRequest{
+	return s.sendVerificationCode(ctx, &models.SendVerificationRequest{
 		Recipient: req.Recipient,
 		Channel:   req.Channel,
 		Purpose:   req.Purpose,
 		SessionID: req.SessionID,
-	})
+		Caller:    req.Caller,
+	}, true)
 }
 
 // GetVerificationStatus checks the verification status for a recipient
@@ -375,6 +411,11 @@ func (s *VerificationService) sendCode(channel, recipient, code, purpose string)
 
 // SendCustomEmail sends a custom email (welcome, account created, verification link, etc.)
 func (s *VerificationService) SendCustomEmail(ctx context.Context, req *models.SendEmailRequest) error {
+	if req.Strict && s.isDisposable(req.Recipient) {
+		metrics.RecordDisposableRejection(metrics.OperationEmailSend)
+		return ErrDisposableEmail
+	}
+
 	var subject, htmlBody string
 
 	switch req.EmailType {
@@ -437,3 +478,9 @@ func (s *VerificationService) SendCustomEmail(ctx context.Context, req *models.S
 	// Send the email
 	return s.emailProvider.SendEmail(req.Recipient, subject, htmlBody)
 }
+
+// isDisposable reports whether the recipient uses a disposable domain; without a
+// reputation service every recipient is accepted
+func (s *VerificationService) isDisposable(recipient string) bool {
+	return s.emailReputation != nil && s.emailReputation.IsDisposable(recipient)
+}